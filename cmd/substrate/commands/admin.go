@@ -0,0 +1,339 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// adminCmd is the parent command for maintenance operations.
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Maintenance commands for the mail store",
+}
+
+// adminGCCmd enforces topic retention.
+var adminGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Delete messages that have outlived their topic's retention",
+	Long: `Enforce per-topic retention immediately instead of waiting for the
+daemon's background compactor.
+
+Messages older than their topic's retention_seconds are deleted along with
+their recipient rows and search index entries, and consumer offsets left
+behind by agents that unsubscribed are dropped. Starred, unread and snoozed
+messages, messages still awaiting an ack by their deadline, messages under a
+legal hold, messages in threads with an open review, plan review or pending
+question, and queue messages still awaiting a consumer are always kept.
+
+Use --dry-run to see what would be deleted without deleting anything.`,
+	RunE: runAdminGC,
+}
+
+// adminHoldCmd places or lifts a legal hold.
+var adminHoldCmd = &cobra.Command{
+	Use:   "hold [message_id]",
+	Short: "Exempt a message or thread from retention",
+	Long: `Place a legal hold on a message, or on every message in a thread
+with --thread. Held messages are never deleted by retention until the hold is
+lifted with --release.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAdminHold,
+}
+
+// adminRetentionCmd sets a topic's retention.
+var adminRetentionCmd = &cobra.Command{
+	Use:   "retention <topic> <duration>",
+	Short: "Set how long a topic's messages are kept",
+	Long: `Set how long messages on a topic are kept before retention deletes
+them, as a Go duration such as 12h, a number of days such as 30d, or 0 to
+keep them forever. An agent's direct messages are on its agent/<name>/inbox
+topic. Topics start out with a retention of seven days.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAdminRetention,
+}
+
+// adminMergeThreadsCmd merges two threads.
+var adminMergeThreadsCmd = &cobra.Command{
+	Use:   "merge-threads <from-thread-id> <into-thread-id>",
+	Short: "Move every message of one thread into another",
+	Long: `Merge a thread that agents accidentally forked back into the
+conversation it belongs to. Every message of the first thread moves into the
+second, along with the questions asked in it, the messages scheduled into it
+and agents' mute and follow settings for it. Threads tracked by a review or
+plan review can't be merged.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAdminMergeThreads,
+}
+
+// adminSplitThreadCmd splits a thread.
+var adminSplitThreadCmd = &cobra.Command{
+	Use:   "split-thread <message-id>",
+	Short: "Move a message and its replies into a new thread",
+	Long: `Split a conversation that drifted onto another topic. The message
+and every later message in its thread move into a new thread, along with the
+questions they ask; agents keep their mute and follow settings for it. The
+first message of a thread, and threads tracked by a review or plan review,
+can't be split off.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAdminSplitThread,
+}
+
+// Admin command flags.
+var (
+	adminGCDryRun     bool
+	adminHoldThreadID string
+	adminHoldRelease  bool
+)
+
+func init() {
+	adminGCCmd.Flags().BoolVar(
+		&adminGCDryRun, "dry-run", false,
+		"Report what would be deleted without deleting anything",
+	)
+	adminHoldCmd.Flags().StringVar(
+		&adminHoldThreadID, "thread", "",
+		"Hold every message in this thread",
+	)
+	adminHoldCmd.Flags().BoolVar(
+		&adminHoldRelease, "release", false,
+		"Lift the hold instead of placing it",
+	)
+
+	adminCmd.AddCommand(adminGCCmd)
+	adminCmd.AddCommand(adminHoldCmd)
+	adminCmd.AddCommand(adminRetentionCmd)
+	adminCmd.AddCommand(adminMergeThreadsCmd)
+	adminCmd.AddCommand(adminSplitThreadCmd)
+}
+
+// runAdminGC handles the `substrate admin gc` command.
+func runAdminGC(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	report, err := client.CollectGarbage(ctx, adminGCDryRun)
+	if err != nil {
+		return fmt.Errorf("failed to collect garbage: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(report)
+	default:
+		verb := "Deleted"
+		if report.DryRun {
+			verb = "Would delete"
+		}
+
+		if len(report.Topics) == 0 {
+			fmt.Println("No messages have outlived their retention.")
+		} else {
+			fmt.Printf("Expired messages by topic (%d):\n\n",
+				len(report.Topics))
+		}
+		for _, t := range report.Topics {
+			retention := time.Duration(t.RetentionSeconds) * time.Second
+			fmt.Printf("  %s\n", t.TopicName)
+			fmt.Printf("    Retention: %s | Expired: %d | "+
+				"Deletable: %d | Kept: %d\n",
+				formatDuration(retention), t.ExpiredCount,
+				t.DeletableCount, t.ExpiredCount-t.DeletableCount)
+		}
+
+		fmt.Printf("\n%s %d messages, %d recipient entries and %d "+
+			"stale consumer offsets.\n", verb, report.MessagesDeleted,
+			report.RecipientsDeleted, report.ConsumerOffsetsDeleted)
+	}
+
+	return nil
+}
+
+// runAdminHold handles the `substrate admin hold` command.
+func runAdminHold(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	var msgID int64
+	switch {
+	case len(args) == 1 && adminHoldThreadID != "":
+		return fmt.Errorf("specify either a message ID or --thread, " +
+			"not both")
+
+	case len(args) == 1:
+		var err error
+		msgID, err = strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid message ID: %w", err)
+		}
+
+	case adminHoldThreadID == "":
+		return fmt.Errorf("a message ID or --thread is required")
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	hold := !adminHoldRelease
+	updated, err := client.SetLegalHold(
+		ctx, msgID, adminHoldThreadID, hold,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set legal hold: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"message_id":       msgID,
+			"thread_id":        adminHoldThreadID,
+			"hold":             hold,
+			"messages_updated": updated,
+		})
+	default:
+		action := "Placed"
+		if !hold {
+			action = "Lifted"
+		}
+		fmt.Printf("%s legal hold on %d message(s).\n", action, updated)
+	}
+
+	return nil
+}
+
+// runAdminRetention handles the `substrate admin retention` command.
+func runAdminRetention(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	retention, err := parseRetention(args[1])
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	topic, err := client.SetTopicRetention(ctx, args[0], retention)
+	if err != nil {
+		return fmt.Errorf("failed to set topic retention: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(topic)
+	default:
+		if topic.RetentionSeconds == 0 {
+			fmt.Printf("Messages on %s are now kept forever.\n",
+				topic.Name)
+			return nil
+		}
+
+		fmt.Printf("Messages on %s are now kept for %s.\n", topic.Name,
+			formatDuration(retention))
+	}
+
+	return nil
+}
+
+// parseRetention parses a topic retention: a Go duration, a number of days
+// ending in d, or 0 to keep messages forever.
+func parseRetention(s string) (time.Duration, error) {
+	var (
+		retention time.Duration
+		err       error
+	)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int64
+		n, err = strconv.ParseInt(days, 10, 64)
+		retention = time.Duration(n) * 24 * time.Hour
+	} else {
+		retention, err = time.ParseDuration(s)
+	}
+	if err != nil || retention < 0 ||
+		(retention > 0 && retention < time.Second) {
+
+		return 0, fmt.Errorf("invalid retention %q: expected a duration "+
+			"such as 12h or 30d, or 0", s)
+	}
+
+	return retention, nil
+}
+
+// runAdminMergeThreads handles the `substrate admin merge-threads` command.
+func runAdminMergeThreads(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	moved, err := client.MergeThreads(ctx, args[0], args[1])
+	if err != nil {
+		return fmt.Errorf("failed to merge threads: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"from_thread_id": args[0],
+			"into_thread_id": args[1],
+			"messages_moved": moved,
+		})
+	default:
+		fmt.Printf("Moved %d message(s) into thread %s.\n", moved,
+			args[1])
+	}
+
+	return nil
+}
+
+// runAdminSplitThread handles the `substrate admin split-thread` command.
+func runAdminSplitThread(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	msgID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid message ID: %w", err)
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	resp, err := client.SplitThread(ctx, msgID)
+	if err != nil {
+		return fmt.Errorf("failed to split thread: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"message_id":     msgID,
+			"thread_id":      resp.ThreadID,
+			"messages_moved": resp.MessagesMoved,
+		})
+	default:
+		fmt.Printf("Moved %d message(s) into new thread %s.\n",
+			resp.MessagesMoved, resp.ThreadID)
+	}
+
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/roasbeef/subtrate/internal/auth"
+	"github.com/roasbeef/subtrate/internal/mail"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +26,33 @@ var agentRegisterCmd = &cobra.Command{
 	RunE:  runAgentRegister,
 }
 
+var agentUpdateCmd = &cobra.Command{
+	Use:   "update [name]",
+	Short: "Update an agent's capabilities",
+	Long: `Replace the capabilities an agent declares for cap: routing.
+Defaults to the current agent.
+
+Each --cap is kind:name, where kind is language, repo, tool or skill, or a
+bare name taken as a skill:
+
+  substrate agent update --cap language:go,repo:roasbeef/substrate \
+    --cap can-review-security --max-concurrency 2
+
+Mail and tasks addressed to cap:<name> then go to the least loaded online
+agent declaring it. Pass no --cap to clear the capabilities.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAgentUpdate,
+}
+
+var agentRoutesCmd = &cobra.Command{
+	Use:   "routes",
+	Short: "Show which agents cap: addresses were routed to",
+	Long: `List the agents the router picked for mail and tasks addressed to
+a capability, newest first, with how many agents it chose between and
+the picked agent's load at the time.`,
+	RunE: runAgentRoutes,
+}
+
 var agentListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all agents",
@@ -54,16 +83,28 @@ working directory, git branch, purpose, hostname, and unread message counts.
 
 Use --status to filter by agent status (active, busy, idle, offline).
 Use --project to filter by project key prefix.
-Use --name to filter by agent name substring.`,
+Use --name to filter by agent name substring.
+Use --cap to filter by declared capability, either a name such as go-review
+or kind:name such as language:go.`,
 	RunE: runAgentDiscover,
 }
 
 var (
 	registerProject string
+	registerMint    bool
 	forceDelete     bool
 	discoverStatus  string
 	discoverProject string
 	discoverName    string
+	discoverCap     string
+
+	// agentCaps and agentMaxConcurrency are the capabilities declared by
+	// agent register and agent update.
+	agentCaps           []string
+	agentMaxConcurrency int64
+
+	routesCap   string
+	routesLimit int
 )
 
 func init() {
@@ -72,9 +113,23 @@ func init() {
 	agentCmd.AddCommand(agentWhoamiCmd)
 	agentCmd.AddCommand(agentDeleteCmd)
 	agentCmd.AddCommand(agentDiscoverCmd)
+	agentCmd.AddCommand(agentUpdateCmd)
+	agentCmd.AddCommand(agentRoutesCmd)
 
 	agentRegisterCmd.Flags().StringVar(&registerProject, "project", "",
 		"Project key to associate with the agent")
+	agentRegisterCmd.Flags().BoolVar(&registerMint, "mint-token", false,
+		"Also mint a bearer token for the agent")
+
+	for _, cmd := range []*cobra.Command{agentRegisterCmd, agentUpdateCmd} {
+		cmd.Flags().StringSliceVar(&agentCaps, "cap", nil,
+			"Capability to declare (kind:name or a skill, "+
+				"repeatable)")
+		cmd.Flags().Int64Var(
+			&agentMaxConcurrency, "max-concurrency", 0,
+			"Most tasks to take on at once (0 for no limit)",
+		)
+	}
 
 	agentDeleteCmd.Flags().BoolVarP(&forceDelete, "force", "f", false,
 		"Skip confirmation prompt")
@@ -91,6 +146,40 @@ func init() {
 		&discoverName, "name", "",
 		"Filter by agent name substring",
 	)
+	agentDiscoverCmd.Flags().StringVar(
+		&discoverCap, "cap", "",
+		"Filter by capability (name or kind:name)",
+	)
+
+	agentRoutesCmd.Flags().StringVar(
+		&routesCap, "cap", "",
+		"Only show routes for this capability",
+	)
+	agentRoutesCmd.Flags().IntVarP(
+		&routesLimit, "limit", "n", 50,
+		"Maximum number of routes to show",
+	)
+}
+
+// parseAgentCapabilities parses the --cap and --max-concurrency flags.
+func parseAgentCapabilities() (mail.AgentCapabilities, error) {
+	caps, err := mail.ParseAgentCapabilities(agentCaps)
+	if err != nil {
+		return mail.AgentCapabilities{}, &CLIError{
+			Code:    ExitValidation,
+			Message: err.Error(),
+		}
+	}
+	caps.MaxConcurrency = agentMaxConcurrency
+
+	if err := caps.Validate(); err != nil {
+		return mail.AgentCapabilities{}, &CLIError{
+			Code:    ExitValidation,
+			Message: err.Error(),
+		}
+	}
+
+	return caps, nil
 }
 
 func runAgentRegister(cmd *cobra.Command, args []string) error {
@@ -107,6 +196,13 @@ func runAgentRegister(cmd *cobra.Command, args []string) error {
 		name = args[0]
 	}
 
+	// Check the capabilities first so bad ones don't leave the agent
+	// registered without them.
+	caps, err := parseAgentCapabilities()
+	if err != nil {
+		return err
+	}
+
 	// RegisterAgent handles name generation if empty.
 	gitBranch := getGitBranch()
 	agentID, agentNameResult, err := client.RegisterAgent(
@@ -116,22 +212,177 @@ func runAgentRegister(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to register agent: %w", err)
 	}
 
+	if !caps.IsEmpty() {
+		caps, err = client.SetAgentCapabilities(ctx, agentID, caps)
+		if err != nil {
+			return fmt.Errorf("failed to declare capabilities: %w",
+				err)
+		}
+	}
+
+	var token string
+	if registerMint {
+		token, _, err = client.MintCredential(ctx, auth.MintRequest{
+			AgentID: agentID,
+			Name:    "register",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to mint token: %w", err)
+		}
+	}
+
 	topicName := fmt.Sprintf("agent/%s/inbox", agentNameResult)
 
 	switch outputFormat {
 	case "json":
-		return outputJSON(map[string]any{
-			"id":      agentID,
-			"name":    agentNameResult,
-			"project": registerProject,
-			"topic":   topicName,
-		})
+		out := map[string]any{
+			"id":           agentID,
+			"name":         agentNameResult,
+			"project":      registerProject,
+			"topic":        topicName,
+			"capabilities": caps,
+		}
+		if token != "" {
+			out["token"] = token
+		}
+		return outputJSON(out)
 	default:
 		fmt.Printf("Agent registered: %s (ID: %d)\n", agentNameResult, agentID)
 		fmt.Printf("  Inbox topic: %s\n", topicName)
 		if registerProject != "" {
 			fmt.Printf("  Project: %s\n", registerProject)
 		}
+		printCapabilities("  ", caps)
+		if token != "" {
+			fmt.Printf("  Token: %s\n", token)
+		}
+	}
+
+	return nil
+}
+
+// runAgentUpdate replaces the capabilities the named or current agent
+// declares.
+func runAgentUpdate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	caps, err := parseAgentCapabilities()
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var (
+		agentID   int64
+		agentName string
+	)
+	if len(args) > 0 {
+		agentRow, err := client.GetAgentByName(ctx, args[0])
+		if err != nil {
+			return &CLIError{
+				Code: ExitNotFound,
+				Message: fmt.Sprintf(
+					"agent not found: %s", args[0],
+				),
+			}
+		}
+		agentID, agentName = agentRow.ID, agentRow.Name
+	} else {
+		agentID, agentName, err = getCurrentAgentWithClient(ctx, client)
+		if err != nil {
+			return err
+		}
+	}
+
+	caps, err = client.SetAgentCapabilities(ctx, agentID, caps)
+	if err != nil {
+		return fmt.Errorf("failed to update agent: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"id":           agentID,
+			"name":         agentName,
+			"capabilities": caps,
+		})
+	default:
+		fmt.Printf("Updated %s (ID: %d)\n", agentName, agentID)
+		if caps.IsEmpty() {
+			fmt.Println("  No capabilities declared")
+		}
+		printCapabilities("  ", caps)
+	}
+
+	return nil
+}
+
+// printCapabilities prints an agent's declared capabilities, one kind per
+// line, skipping kinds it declares none of.
+func printCapabilities(indent string, caps mail.AgentCapabilities) {
+	kinds := []struct {
+		label string
+		names []string
+	}{
+		{"Langs:", caps.Languages},
+		{"Repos:", caps.Repos},
+		{"Tools:", caps.Tools},
+		{"Skills:", caps.Skills},
+	}
+	for _, k := range kinds {
+		if len(k.names) > 0 {
+			fmt.Printf("%s%-10s%s\n", indent, k.label,
+				strings.Join(k.names, ", "))
+		}
+	}
+	if caps.MaxConcurrency > 0 {
+		fmt.Printf("%s%-10s%d tasks\n", indent, "Max:",
+			caps.MaxConcurrency)
+	}
+}
+
+// runAgentRoutes lists the agents cap: addresses were routed to.
+func runAgentRoutes(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	routes, err := client.ListCapabilityRoutes(ctx, routesCap, routesLimit)
+	if err != nil {
+		return fmt.Errorf("failed to list routes: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(routes)
+	default:
+		if len(routes) == 0 {
+			fmt.Println("No capability routes.")
+			return nil
+		}
+
+		for _, r := range routes {
+			target := fmt.Sprintf("message #%d", r.MessageID)
+			if r.TaskID != 0 {
+				target = fmt.Sprintf("task #%d", r.TaskID)
+			}
+			fmt.Printf(
+				"%s  cap:%s -> %s (%s, %d candidates, %d "+
+					"unread, %d in progress)\n",
+				r.CreatedAt.Local().Format(time.DateTime),
+				r.Capability, r.AgentName, target,
+				r.Candidates, r.UnreadCount, r.InProgressCount,
+			)
+		}
 	}
 
 	return nil
@@ -225,7 +476,10 @@ func runAgentDiscover(cmd *cobra.Command, args []string) error {
 	}
 
 	// Apply client-side filters.
-	agents = filterDiscoveredAgents(agents)
+	agents, err = filterDiscoveredAgents(agents)
+	if err != nil {
+		return err
+	}
 
 	switch outputFormat {
 	case "json":
@@ -283,20 +537,33 @@ func runAgentDiscover(cmd *cobra.Command, args []string) error {
 				)
 			}
 			fmt.Printf("    Unread:   %d messages\n", a.UnreadCount)
+			printCapabilities("    ", a.Capabilities)
 		}
 	}
 
 	return nil
 }
 
-// filterDiscoveredAgents applies the --status, --project, and --name
+// filterDiscoveredAgents applies the --status, --project, --name and --cap
 // flags to filter the discovered agents list.
 func filterDiscoveredAgents(
 	agents []DiscoveredAgentInfo,
-) []DiscoveredAgentInfo {
+) ([]DiscoveredAgentInfo, error) {
 	if discoverStatus == "" && discoverProject == "" &&
-		discoverName == "" {
-		return agents
+		discoverName == "" && discoverCap == "" {
+		return agents, nil
+	}
+
+	var capKind, capName string
+	if discoverCap != "" {
+		var err error
+		capKind, capName, err = mail.ParseCapability(discoverCap)
+		if err != nil {
+			return nil, &CLIError{
+				Code:    ExitValidation,
+				Message: err.Error(),
+			}
+		}
 	}
 
 	// Parse status filter into a set.
@@ -323,9 +590,12 @@ func filterDiscoveredAgents(
 			) {
 			continue
 		}
+		if capName != "" && !a.Capabilities.Has(capKind, capName) {
+			continue
+		}
 		filtered = append(filtered, a)
 	}
-	return filtered
+	return filtered, nil
 }
 
 func runAgentDelete(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,345 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// askWaitPollInterval is how often `substrate ask --wait` checks for an
+// answer.
+const askWaitPollInterval = 5 * time.Second
+
+// askCmd asks a structured question.
+var askCmd = &cobra.Command{
+	Use:   "ask <question>",
+	Short: "Ask a question and optionally wait for the answer",
+	Long: `Ask agents or the user a question through mail.
+
+The question is sent as a message listing the options. The first reply in
+its thread from one of the agents asked, or from --fallback once it was
+escalated, is recorded as the answer, whether it was sent with 'substrate
+answer', a reply from the CLI, the web UI or MCP. A
+reply naming an option by its number or text is recorded as that option;
+anything else is recorded as a free-form answer.
+
+With --timeout, an unanswered question is escalated to --fallback, and if
+it still isn't answered after another timeout it takes the --default
+answer, or expires if there is none.
+
+With --wait the command blocks until the question is answered, like
+'substrate plan wait'. Use --format json for structured output, or
+--format hook to inject the answer into the session from a hook.
+
+Example:
+  substrate ask "Which database should the cache use?" --to User \
+    --option SQLite --option Postgres --default SQLite \
+    --timeout 30m --fallback Lead --wait`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAsk,
+}
+
+// askWaitCmd waits for the answer to a question asked earlier.
+var askWaitCmd = &cobra.Command{
+	Use:   "wait <question-id>",
+	Short: "Wait for the answer to a question",
+	Long: `Block until a question is answered, defaulted or expired, or
+until --wait-timeout passes. Use it to keep waiting after
+'substrate ask --wait' timed out.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAskWait,
+}
+
+// answerCmd answers a question.
+var answerCmd = &cobra.Command{
+	Use:   "answer <question-id> <answer>",
+	Short: "Answer a question",
+	Long: `Answer a question by replying in its thread. The answer may be
+an option's number or text, or free text.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAnswer,
+}
+
+var (
+	askTo          string
+	askOptions     []string
+	askDefault     string
+	askDetails     string
+	askDetailsFile string
+	askTimeout     time.Duration
+	askFallback    string
+	askPriority    string
+	askWait        bool
+	askWaitTimeout time.Duration
+)
+
+func init() {
+	askCmd.Flags().StringVar(&askTo, "to", "",
+		"Comma-separated recipients or @groups (required)")
+	askCmd.Flags().StringArrayVar(&askOptions, "option", nil,
+		"A suggested answer (repeatable)")
+	askCmd.Flags().StringVar(&askDefault, "default", "",
+		"Answer used if nobody answers in time")
+	askCmd.Flags().StringVar(&askDetails, "details", "",
+		"Additional context in markdown")
+	askCmd.Flags().StringVar(&askDetailsFile, "details-file", "",
+		"Read additional context from file (overrides --details)")
+	askCmd.Flags().DurationVar(&askTimeout, "timeout", 0,
+		"How long to wait for an answer before escalating or "+
+			"defaulting (0 for never)")
+	askCmd.Flags().StringVar(&askFallback, "fallback", "",
+		"Agent to escalate to if nobody answers within --timeout")
+	askCmd.Flags().StringVar(&askPriority, "priority", "normal",
+		"Priority: urgent, normal, low")
+	askCmd.Flags().BoolVar(&askWait, "wait", false,
+		"Block until the question is answered")
+	askCmd.MarkFlagRequired("to")
+
+	for _, cmd := range []*cobra.Command{askCmd, askWaitCmd} {
+		cmd.Flags().DurationVar(&askWaitTimeout, "wait-timeout",
+			9*time.Minute, "Maximum time to wait for an answer")
+	}
+
+	askCmd.AddCommand(askWaitCmd)
+}
+
+// runAsk handles the `substrate ask` command.
+func runAsk(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if err := validateEnum(
+		askPriority, "priority", []string{"urgent", "normal", "low"},
+	); err != nil {
+		return err
+	}
+
+	details := askDetails
+	if askDetailsFile != "" {
+		data, err := os.ReadFile(askDetailsFile)
+		if err != nil {
+			return fmt.Errorf("failed to read details file: %w",
+				err)
+		}
+		details = strings.TrimSpace(string(data))
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	question, err := client.AskQuestion(ctx, mail.AskQuestionRequest{
+		SenderID:       agentID,
+		RecipientNames: splitAgentList(askTo),
+		Question:       args[0],
+		Details:        details,
+		Options:        askOptions,
+		DefaultAnswer:  askDefault,
+		Timeout:        askTimeout,
+		Fallback:       askFallback,
+		Priority:       mail.Priority(askPriority),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ask question: %w", err)
+	}
+
+	if askWait {
+		return waitForAnswer(ctx, client, question)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(questionOutput(ctx, client, question))
+	default:
+		fmt.Printf("Asked question %s.\n", question.QuestionID)
+		fmt.Printf("Wait for the answer with: substrate ask wait %s\n",
+			question.QuestionID)
+	}
+
+	return nil
+}
+
+// runAskWait handles the `substrate ask wait` command.
+func runAskWait(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	question, err := client.GetQuestion(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get question: %w", err)
+	}
+
+	return waitForAnswer(ctx, client, question)
+}
+
+// waitForAnswer polls a question until it is resolved or the wait times out,
+// then outputs it.
+func waitForAnswer(ctx context.Context, client *Client,
+	question store.Question,
+) error {
+	deadline := time.Now().Add(askWaitTimeout)
+
+	for !mail.QuestionState(question.State).Resolved() {
+		if time.Now().After(deadline) {
+			return outputQuestionTimeout(question)
+		}
+
+		time.Sleep(askWaitPollInterval)
+
+		var err error
+		question, err = client.GetQuestion(ctx, question.QuestionID)
+		if err != nil {
+			return fmt.Errorf("failed to get question: %w", err)
+		}
+	}
+
+	return outputQuestionAnswer(ctx, client, question)
+}
+
+// runAnswer handles the `substrate answer` command.
+func runAnswer(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	question, err := client.AnswerQuestion(ctx, args[0], agentID, args[1])
+	if err != nil {
+		return fmt.Errorf("failed to answer question: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(questionOutput(ctx, client, question))
+	default:
+		fmt.Printf("Answered %q: %s\n", question.Question,
+			question.Answer)
+	}
+
+	return nil
+}
+
+// questionOutput builds the structured form of a question for JSON output.
+func questionOutput(ctx context.Context, client *Client,
+	question store.Question,
+) map[string]any {
+	output := map[string]any{
+		"question_id":    question.QuestionID,
+		"thread_id":      question.ThreadID,
+		"question":       question.Question,
+		"options":        question.Options,
+		"default_answer": question.DefaultAnswer,
+		"state":          question.State,
+		"answer":         question.Answer,
+		"option_index":   mail.QuestionOptionIndex(question),
+		"escalated":      question.EscalatedAt != nil,
+		"answered_at":    question.AnsweredAt,
+	}
+	if question.AnsweredBy != 0 {
+		output["answered_by"] = answererName(ctx, client, question)
+	}
+
+	return output
+}
+
+// answererName returns the name of the agent that answered a question, or
+// its ID if the agent can't be looked up.
+func answererName(ctx context.Context, client *Client,
+	question store.Question,
+) string {
+	agent, err := client.GetAgent(ctx, question.AnsweredBy)
+	if err != nil {
+		return fmt.Sprintf("agent %d", question.AnsweredBy)
+	}
+
+	return agent.Name
+}
+
+// outputQuestionAnswer outputs a resolved question in the appropriate
+// format.
+func outputQuestionAnswer(ctx context.Context, client *Client,
+	question store.Question,
+) error {
+	var summary string
+	switch mail.QuestionState(question.State) {
+	case mail.QuestionAnswered:
+		summary = fmt.Sprintf("%s answered: %s",
+			answererName(ctx, client, question), question.Answer)
+
+	case mail.QuestionDefaulted:
+		summary = fmt.Sprintf("Nobody answered in time, so the "+
+			"default applies: %s", question.Answer)
+
+	default:
+		summary = "Nobody answered in time and there is no default."
+	}
+
+	switch outputFormat {
+	case "hook":
+		// Blocking hands the answer back to the session as the
+		// reason, so the agent picks up where it left off.
+		return outputHookDecisionBlock(fmt.Sprintf(
+			"Answer to your question %q (%s): %s",
+			question.Question, question.QuestionID, summary,
+		))
+
+	case "json":
+		return outputJSON(questionOutput(ctx, client, question))
+
+	default:
+		fmt.Printf("%s\n%s\n", question.Question, summary)
+	}
+
+	return nil
+}
+
+// outputQuestionTimeout outputs a question that is still pending after the
+// wait timed out.
+func outputQuestionTimeout(question store.Question) error {
+	switch outputFormat {
+	case "hook":
+		return outputHookDecisionBlock(fmt.Sprintf(
+			"Your question %q is still waiting for an answer. "+
+				"Run `substrate ask wait %s` to keep waiting.",
+			question.Question, question.QuestionID,
+		))
+
+	case "json":
+		return outputJSON(map[string]any{
+			"question_id": question.QuestionID,
+			"state":       "timeout",
+			"message":     "Timed out waiting for an answer",
+		})
+
+	default:
+		fmt.Printf("Timed out waiting for an answer to question %s\n",
+			question.QuestionID)
+	}
+
+	return nil
+}
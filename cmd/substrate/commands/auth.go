@@ -0,0 +1,396 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	subtraterpc "github.com/roasbeef/subtrate/internal/api/grpc"
+	"github.com/roasbeef/subtrate/internal/auth"
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// substrateTokenEnv is the environment variable holding the bearer token
+// sent to the daemon when --token isn't given.
+const substrateTokenEnv = "SUBSTRATE_TOKEN"
+
+// authCmd is the parent command for credential management.
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Mint, list and revoke credentials",
+	Long: `Manage the bearer credentials the daemon requires when it runs
+with -auth.
+
+An agent credential can only act as its agent: the daemon derives the
+sender of everything sent with it from the credential. An admin credential
+may act as any agent and manage credentials. The daemon keeps an admin
+token in ~/.subtrate/admin.token, which the CLI uses when neither --token
+nor $SUBSTRATE_TOKEN is set.`,
+}
+
+var authMintCmd = &cobra.Command{
+	Use:   "mint [agent]",
+	Short: "Mint a credential",
+	Long: `Mint a bearer token for an agent, the current one by default, or
+an admin token with --admin. The token is only shown once.
+
+  substrate auth mint AliceAgent --expires 720h --rpc SendMail,FetchInbox
+  SUBSTRATE_TOKEN=$(substrate auth mint AliceAgent --format json | jq -r .token)
+
+Without a running daemon the token is minted straight into the database,
+which is how the first tokens are made.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAuthMint,
+}
+
+var authListCmd = &cobra.Command{
+	Use:   "list [agent]",
+	Short: "List credentials",
+	Long:  `List credentials, newest first, for one agent or for everyone.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runAuthList,
+}
+
+var authRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Revoke a credential",
+	Long:  `Revoke a credential by ID. Its token stops working at once.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAuthRevoke,
+}
+
+var (
+	// authToken is the bearer token sent to the daemon.
+	authToken string
+
+	mintAdmin   bool
+	mintName    string
+	mintMethods []string
+	mintExpires time.Duration
+
+	listAllCredentials bool
+)
+
+func init() {
+	authCmd.AddCommand(authMintCmd)
+	authCmd.AddCommand(authListCmd)
+	authCmd.AddCommand(authRevokeCmd)
+
+	authMintCmd.Flags().BoolVar(&mintAdmin, "admin", false,
+		"Mint an admin credential instead of an agent one")
+	authMintCmd.Flags().StringVar(&mintName, "name", "",
+		"Label for the credential, e.g. where it is used")
+	authMintCmd.Flags().StringSliceVar(&mintMethods, "rpc", nil,
+		"Restrict the credential to these RPCs, e.g. SendMail "+
+			"(repeatable)")
+	authMintCmd.Flags().DurationVar(&mintExpires, "expires", 0,
+		"How long the credential is valid for (0 for no expiry)")
+
+	authListCmd.Flags().BoolVar(&listAllCredentials, "all", false,
+		"Include revoked credentials")
+}
+
+// resolveToken returns the bearer token to send to the daemon: --token,
+// then $SUBSTRATE_TOKEN, then the daemon's admin token file if it is
+// readable.
+func resolveToken() string {
+	if authToken != "" {
+		return authToken
+	}
+	if env := os.Getenv(substrateTokenEnv); env != "" {
+		return env
+	}
+
+	tokenPath, err := auth.DefaultAdminTokenFile()
+	if err != nil {
+		return ""
+	}
+	token, err := auth.ReadTokenFile(tokenPath)
+	if err != nil {
+		return ""
+	}
+
+	return token
+}
+
+// bearerCredentials sends a bearer token with every RPC.
+type bearerCredentials struct {
+	token string
+}
+
+// GetRequestMetadata returns the authorization header for an RPC.
+func (b bearerCredentials) GetRequestMetadata(ctx context.Context,
+	uri ...string,
+) (map[string]string, error) {
+	return map[string]string{
+		"authorization": "Bearer " + b.token,
+	}, nil
+}
+
+// RequireTransportSecurity reports that the token may be sent over a
+// plaintext connection, as the daemon listens on localhost or a unix socket
+// by default.
+func (b bearerCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// grpcDialOptions returns the options for dialing the daemon at addr,
+// sending the resolved token with every RPC.
+func grpcDialOptions(addr string) ([]grpc.DialOption, error) {
+	creds, err := transportCredentials(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentialDialOptions(creds), nil
+}
+
+// credentialDialOptions returns the options for dialing the daemon with the
+// given transport credentials, along with our bearer credential if any.
+func credentialDialOptions(
+	creds credentials.TransportCredentials,
+) []grpc.DialOption {
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if token := resolveToken(); token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(
+			bearerCredentials{token: token},
+		))
+	}
+
+	return opts
+}
+
+// verifyDaemon makes a lightweight call to check that the daemon is up and
+// accepts our credential. A credential restricted to other RPCs proves both
+// just as well.
+func verifyDaemon(ctx context.Context,
+	agentClient subtraterpc.AgentClient,
+) error {
+	_, err := agentClient.ListAgents(ctx, &subtraterpc.ListAgentsRequest{})
+	switch status.Code(err) {
+	case codes.OK, codes.PermissionDenied:
+		return nil
+
+	case codes.Unauthenticated:
+		return &CLIError{
+			Code: ExitAuth,
+			Message: fmt.Sprintf("daemon refused credential: %s "+
+				"(pass --token or set $%s)",
+				status.Convert(err).Message(),
+				substrateTokenEnv),
+			Err: err,
+		}
+	}
+
+	if tlsErr := tlsHandshakeError(err); tlsErr != nil {
+		return tlsErr
+	}
+
+	return err
+}
+
+// isAuthError reports whether an error is an authentication failure, either
+// of our credential or of the daemon's TLS certificate.
+func isAuthError(err error) bool {
+	var cliErr *CLIError
+	return errors.As(err, &cliErr) && cliErr.Code == ExitAuth
+}
+
+func runAuthMint(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if mintAdmin && len(args) > 0 {
+		return NewValidationError(
+			"an admin credential isn't tied to an agent", nil,
+		)
+	}
+	if mintExpires < 0 {
+		return NewValidationError("--expires must not be negative", nil)
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	req := auth.MintRequest{
+		Name:           mintName,
+		AllowedMethods: mintMethods,
+		TTL:            mintExpires,
+	}
+	if !mintAdmin {
+		req.AgentID, _, err = resolveAgentArg(ctx, client, args)
+		if err != nil {
+			return err
+		}
+	}
+
+	token, cred, err := client.MintCredential(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to mint credential: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"token":      token,
+			"credential": cred,
+		})
+	default:
+		fmt.Printf("Minted credential %d for %s\n", cred.ID,
+			credentialOwner(cred))
+		fmt.Printf("  Token: %s\n", token)
+		fmt.Println("  The token is not shown again.")
+	}
+
+	return nil
+}
+
+func runAuthList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var agentID int64
+	if len(args) > 0 {
+		agentID, _, err = resolveAgentArg(ctx, client, args)
+		if err != nil {
+			return err
+		}
+	}
+
+	creds, err := client.ListCredentials(ctx, agentID, listAllCredentials)
+	if err != nil {
+		return fmt.Errorf("failed to list credentials: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(creds)
+	default:
+		if len(creds) == 0 {
+			fmt.Println("No credentials.")
+			return nil
+		}
+
+		for _, cred := range creds {
+			fmt.Println(formatCredential(cred))
+		}
+	}
+
+	return nil
+}
+
+func runAuthRevoke(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || id <= 0 {
+		return NewValidationError(
+			fmt.Sprintf("invalid credential ID: %s", args[0]), err,
+		)
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	err = client.RevokeCredential(ctx, id)
+	if errors.Is(err, auth.ErrCredentialNotFound) ||
+		status.Code(err) == codes.NotFound {
+
+		return NewNotFoundError(
+			fmt.Sprintf("credential not found: %d", id), err,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke credential: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"id":      id,
+			"revoked": true,
+		})
+	default:
+		fmt.Printf("Revoked credential %d\n", id)
+	}
+
+	return nil
+}
+
+// resolveAgentArg resolves the agent named by a command's argument, or the
+// current agent if there is none.
+func resolveAgentArg(ctx context.Context, client *Client,
+	args []string,
+) (int64, string, error) {
+	if len(args) == 0 {
+		return getCurrentAgentWithClient(ctx, client)
+	}
+
+	agentRow, err := client.GetAgentByName(ctx, args[0])
+	if err != nil {
+		return 0, "", NewNotFoundError(
+			fmt.Sprintf("agent not found: %s", args[0]), err,
+		)
+	}
+
+	return agentRow.ID, agentRow.Name, nil
+}
+
+// credentialOwner describes who a credential acts as.
+func credentialOwner(cred store.Credential) string {
+	if cred.IsAdmin() {
+		return "admin"
+	}
+
+	return cred.AgentName
+}
+
+// formatCredential formats a credential as one line of text.
+func formatCredential(cred store.Credential) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#%d  %-16s", cred.ID, credentialOwner(cred))
+	if cred.Name != "" {
+		fmt.Fprintf(&b, "  %q", cred.Name)
+	}
+	fmt.Fprintf(&b, "  created %s",
+		cred.CreatedAt.Local().Format(time.DateTime))
+
+	switch {
+	case cred.RevokedAt != nil:
+		fmt.Fprintf(&b, "  revoked %s",
+			cred.RevokedAt.Local().Format(time.DateTime))
+
+	case cred.ExpiresAt != nil:
+		fmt.Fprintf(&b, "  expires %s",
+			cred.ExpiresAt.Local().Format(time.DateTime))
+	}
+	if cred.LastUsedAt != nil {
+		fmt.Fprintf(&b, "  last used %s",
+			cred.LastUsedAt.Local().Format(time.DateTime))
+	}
+	if len(cred.AllowedMethods) > 0 {
+		fmt.Fprintf(&b, "  rpcs: %s",
+			strings.Join(cred.AllowedMethods, ","))
+	}
+
+	return b.String()
+}
@@ -2,21 +2,27 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/roasbeef/subtrate/internal/agent"
 	subtraterpc "github.com/roasbeef/subtrate/internal/api/grpc"
+	"github.com/roasbeef/subtrate/internal/auth"
 	"github.com/roasbeef/subtrate/internal/db"
 	"github.com/roasbeef/subtrate/internal/db/sqlc"
 	"github.com/roasbeef/subtrate/internal/mail"
 	"github.com/roasbeef/subtrate/internal/queue"
+	"github.com/roasbeef/subtrate/internal/ratelimit"
+	"github.com/roasbeef/subtrate/internal/search"
 	"github.com/roasbeef/subtrate/internal/store"
 )
 
@@ -36,7 +42,9 @@ const (
 
 // resolveGRPCAddr returns the gRPC address to use for connecting to the
 // substrated daemon. It checks, in order: the --grpc-addr flag, the
-// SUBSTRATE_GRPC_ADDR environment variable, and the hardcoded default.
+// SUBSTRATE_GRPC_ADDR environment variable, the default unix socket
+// (~/.subtrate/substrated.sock) if a daemon is serving it, and the
+// hardcoded default. Addresses of the form unix:///path name a unix socket.
 func resolveGRPCAddr() string {
 	if grpcAddr != "" {
 		return grpcAddr
@@ -44,6 +52,9 @@ func resolveGRPCAddr() string {
 	if env := os.Getenv(substrateGRPCAddrEnv); env != "" {
 		return env
 	}
+	if target, ok := discoverUnixSocket(); ok {
+		return target
+	}
 
 	return defaultGRPCAddr
 }
@@ -58,6 +69,7 @@ type Client struct {
 	reviewClient     subtraterpc.ReviewServiceClient
 	taskClient       subtraterpc.TaskServiceClient
 	planReviewClient subtraterpc.PlanReviewServiceClient
+	inboxRuleClient  subtraterpc.InboxRuleServiceClient
 
 	// When using direct DB mode.
 	store       *db.Store
@@ -129,6 +141,14 @@ func getClient() (*Client, error) {
 		return client, nil
 	}
 
+	// A daemon that refused our credential or presented the wrong
+	// certificate is running, so falling back to the database would only
+	// go around it. Invalid TLS flags are reported rather than ignored.
+	var cliErr *CLIError
+	if errors.As(err, &cliErr) {
+		return nil, err
+	}
+
 	// If gRPC failed, fall back to direct database access.
 	if verbose {
 		fmt.Fprintf(os.Stderr,
@@ -299,6 +319,11 @@ func deliverOperation(
 		if p.DeadlineAt != nil {
 			req.Deadline = p.DeadlineAt
 		}
+		if p.Partial {
+			req.DeliveryMode = mail.DeliveryModePartial
+		}
+		req.SendAt = p.SendAt
+		req.Metadata = p.Metadata
 
 		_, _, err = client.SendMail(ctx, req)
 		return err
@@ -311,7 +336,7 @@ func deliverOperation(
 			)
 		}
 
-		_, _, err = client.Publish(
+		_, _, _, err = client.Publish(
 			ctx, sender.ID, p.TopicName, p.Subject, p.Body,
 			mail.Priority(p.Priority),
 		)
@@ -346,6 +371,41 @@ func deliverOperation(
 		_, _, err = client.SendMail(ctx, req)
 		return err
 
+	case *queue.ReplyPayload:
+		sender, err := client.GetAgentByName(ctx, p.SenderName)
+		if err != nil {
+			return fmt.Errorf("resolve sender %q: %w",
+				p.SenderName, err,
+			)
+		}
+
+		_, err = client.Reply(ctx, mail.ReplyRequest{
+			SenderID:       sender.ID,
+			MessageID:      p.MessageID,
+			Body:           p.Body,
+			SenderOnly:     p.SenderOnly,
+			Quote:          p.Quote,
+			Priority:       mail.Priority(p.Priority),
+			IdempotencyKey: op.IdempotencyKey,
+		})
+		return err
+
+	case *queue.ThreadStatePayload:
+		ag, err := client.GetAgentByName(ctx, p.AgentName)
+		if err != nil {
+			return fmt.Errorf("resolve agent %q: %w",
+				p.AgentName, err,
+			)
+		}
+
+		apply, err := threadStateFunc(p.State)
+		if err != nil {
+			return err
+		}
+
+		_, err = apply(client, ctx, ag.ID, p.ThreadID)
+		return err
+
 	default:
 		return fmt.Errorf("unknown payload type: %T", payload)
 	}
@@ -364,12 +424,65 @@ func newIdempotencyKey() string {
 
 // tryGRPCConnection attempts to connect to the daemon via gRPC.
 func tryGRPCConnection(addr string) (*Client, error) {
+	conn, err := dialDaemon(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		conn:             conn,
+		mailClient:       subtraterpc.NewMailClient(conn),
+		agentClient:      subtraterpc.NewAgentClient(conn),
+		reviewClient:     subtraterpc.NewReviewServiceClient(conn),
+		taskClient:       subtraterpc.NewTaskServiceClient(conn),
+		planReviewClient: subtraterpc.NewPlanReviewServiceClient(conn),
+		inboxRuleClient:  subtraterpc.NewInboxRuleServiceClient(conn),
+		mode:             ModeGRPC,
+		grpcAddr:         addr,
+	}, nil
+}
+
+// dialDaemon connects to the daemon at addr and checks that it responds. If
+// a plaintext connection fails because the daemon serves TLS, it retries
+// pinned to the certificate generated in ~/.subtrate/tls, or fails if there
+// is none. Everything that talks to the daemon over gRPC dials through it.
+func dialDaemon(addr string) (*grpc.ClientConn, error) {
+	opts, err := grpcDialOptions(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := connectDaemon(addr, opts)
+	if err == nil || isAuthError(err) {
+		return conn, err
+	}
+
+	// A daemon serving TLS drops a plaintext client as if it weren't
+	// running, so check for one before falling back around it.
+	pin, tlsErr := detectDaemonTLS(addr)
+	if tlsErr != nil {
+		return nil, tlsErr
+	}
+	if pin == "" {
+		return nil, err
+	}
+
+	creds, err := pinnedCredentials(pin)
+	if err != nil {
+		return nil, err
+	}
+
+	return connectDaemon(addr, credentialDialOptions(creds))
+}
+
+// connectDaemon connects to the daemon at addr with the given dial options
+// and checks that it responds.
+func connectDaemon(addr string, opts []grpc.DialOption) (*grpc.ClientConn,
+	error,
+) {
 	// Use grpc.NewClient (non-blocking) and verify connectivity manually.
 	// This replaces deprecated grpc.DialContext with grpc.WithBlock.
-	conn, err := grpc.NewClient(
-		addr,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	conn, err := grpc.NewClient(addr, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
 	}
@@ -379,25 +492,17 @@ func tryGRPCConnection(addr string) (*Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), grpcConnectTimeout)
 	defer cancel()
 
-	agentClient := subtraterpc.NewAgentClient(conn)
-
 	// Make a lightweight call to verify connectivity.
-	_, err = agentClient.ListAgents(ctx, &subtraterpc.ListAgentsRequest{})
+	err = verifyDaemon(ctx, subtraterpc.NewAgentClient(conn))
 	if err != nil {
 		conn.Close()
+		if isAuthError(err) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("daemon not responding: %w", err)
 	}
 
-	return &Client{
-		conn:             conn,
-		mailClient:       subtraterpc.NewMailClient(conn),
-		agentClient:      agentClient,
-		reviewClient:     subtraterpc.NewReviewServiceClient(conn),
-		taskClient:       subtraterpc.NewTaskServiceClient(conn),
-		planReviewClient: subtraterpc.NewPlanReviewServiceClient(conn),
-		mode:             ModeGRPC,
-		grpcAddr:         addr,
-	}, nil
+	return conn, nil
 }
 
 // getDirectClient creates a client that directly accesses the database.
@@ -414,9 +519,17 @@ func getDirectClient() (*Client, error) {
 		return nil, fmt.Errorf("failed to create identity manager: %w", err)
 	}
 
+	// Without the daemon to limit RPCs as they arrive, the mail service
+	// holds agents to their rate limits itself.
+	storage := store.FromDB(dbStore.DB())
+	mailService := mail.NewService(mail.ServiceConfig{
+		Store:       storage,
+		RateLimiter: ratelimit.NewLimiter(storage),
+	})
+
 	return &Client{
 		store:       dbStore,
-		mailService: mail.NewServiceWithStore(store.FromDB(dbStore.DB())),
+		mailService: mailService,
 		registry:    registry,
 		identityMgr: identityMgr,
 		mode:        ModeDirect,
@@ -498,10 +611,12 @@ func (c *Client) DeleteAgent(ctx context.Context, id int64) error {
 func (c *Client) FetchInbox(ctx context.Context, req mail.FetchInboxRequest) ([]mail.InboxMessage, error) {
 	if c.mode == ModeGRPC {
 		grpcReq := &subtraterpc.FetchInboxRequest{
-			AgentId:    req.AgentID,
-			Limit:      int32(req.Limit),
-			UnreadOnly: req.UnreadOnly,
-			Offset:     int32(req.Offset),
+			AgentId:     req.AgentID,
+			Limit:       int32(req.Limit),
+			UnreadOnly:  req.UnreadOnly,
+			Offset:      int32(req.Offset),
+			OverdueOnly: req.OverdueOnly,
+			Label:       req.Label,
 		}
 
 		resp, err := c.mailClient.FetchInbox(ctx, grpcReq)
@@ -569,35 +684,26 @@ func (c *Client) ReadThread(ctx context.Context, agentID int64, threadID string)
 		return convertProtoMessagesToMail(resp.Messages), nil
 	}
 
-	// Direct mode: use database query directly.
-	messages, err := c.store.Queries().GetMessagesByThread(ctx, threadID)
-	if err != nil {
-		return nil, err
-	}
-
-	result := make([]mail.InboxMessage, 0, len(messages))
-	for _, m := range messages {
-		msg := mail.InboxMessage{
-			ID:        m.ID,
-			ThreadID:  m.ThreadID,
-			TopicID:   m.TopicID,
-			SenderID:  m.SenderID,
-			Subject:   m.Subject,
-			Body:      m.BodyMd,
-			Priority:  mail.Priority(m.Priority),
-			CreatedAt: time.Unix(m.CreatedAt, 0),
-		}
-		if m.DeadlineAt.Valid {
-			t := time.Unix(m.DeadlineAt.Int64, 0)
-			msg.Deadline = &t
-		}
-		result = append(result, msg)
-	}
-	return result, nil
+	// Direct mode: read through the mail service, which fills in each
+	// message's sender.
+	return c.mailService.ReadThread(ctx, agentID, threadID)
 }
 
 // SendMail sends a new message.
 func (c *Client) SendMail(ctx context.Context, req mail.SendMailRequest) (int64, string, error) {
+	resp, err := c.SendMailDetailed(ctx, req)
+	if err != nil {
+		return 0, "", err
+	}
+	return resp.MessageID, resp.ThreadID, nil
+}
+
+// SendMailDetailed sends a message and returns the full send response,
+// including how group and alias recipients were expanded and the delivery
+// outcome for each recipient.
+func (c *Client) SendMailDetailed(ctx context.Context,
+	req mail.SendMailRequest,
+) (mail.SendMailResponse, error) {
 	if c.mode == ModeGRPC {
 		grpcReq := &subtraterpc.SendMailRequest{
 			SenderId:       req.SenderID,
@@ -608,28 +714,65 @@ func (c *Client) SendMail(ctx context.Context, req mail.SendMailRequest) (int64,
 			Body:           req.Body,
 			Priority:       convertPriorityToProto(req.Priority),
 			IdempotencyKey: req.IdempotencyKey,
+			Metadata:       req.Metadata,
+		}
+		if req.DeliveryMode == mail.DeliveryModePartial {
+			grpcReq.DeliveryMode =
+				subtraterpc.DeliveryMode_DELIVERY_MODE_PARTIAL
 		}
 		if req.Deadline != nil {
 			grpcReq.DeadlineAt = timestamppb.New(*req.Deadline)
 		}
+		if req.SendAt != nil {
+			grpcReq.SendAt = timestamppb.New(*req.SendAt)
+		}
 
 		resp, err := c.mailClient.SendMail(ctx, grpcReq)
 		if err != nil {
-			return 0, "", err
+			return mail.SendMailResponse{}, err
+		}
+
+		expansions := make(
+			[]mail.RecipientExpansion, len(resp.Expansions),
+		)
+		for i, e := range resp.Expansions {
+			expansions[i] = mail.RecipientExpansion{
+				Address:    e.Address,
+				AgentNames: e.AgentNames,
+			}
+		}
+
+		deliveries := make(
+			[]mail.RecipientDelivery, len(resp.Deliveries),
+		)
+		for i, d := range resp.Deliveries {
+			deliveries[i] = mail.RecipientDelivery{
+				Recipient: d.Recipient,
+				AgentID:   d.AgentId,
+				Status:    convertRecipientStatusFromProto(d.Status),
+				Reason:    d.Reason,
+			}
 		}
-		return resp.MessageId, resp.ThreadId, nil
+
+		return mail.SendMailResponse{
+			MessageID:   resp.MessageId,
+			ThreadID:    resp.ThreadId,
+			Expansions:  expansions,
+			Deliveries:  deliveries,
+			ScheduledID: resp.ScheduledId,
+		}, nil
 	}
 
 	result := c.mailService.Receive(ctx, req)
 	val, err := result.Unpack()
 	if err != nil {
-		return 0, "", err
+		return mail.SendMailResponse{}, err
 	}
 	resp := val.(mail.SendMailResponse)
 	if resp.Error != nil {
-		return 0, "", resp.Error
+		return mail.SendMailResponse{}, resp.Error
 	}
-	return resp.MessageID, resp.ThreadID, nil
+	return resp, nil
 }
 
 // UpdateState changes the state of a message.
@@ -776,50 +919,187 @@ func (c *Client) PollChanges(ctx context.Context, agentID int64, sinceOffsets ma
 	return resp.NewMessages, resp.NewOffsets, nil
 }
 
-// Search performs full-text search across messages.
-func (c *Client) Search(ctx context.Context, agentID int64, query string, topicID int64, limit int) ([]mail.InboxMessage, error) {
+// Search runs a search query, or one of the agent's saved searches, over its
+// messages, returning the page of hits after the request's cursor.
+func (c *Client) Search(ctx context.Context,
+	req mail.SearchRequest,
+) (mail.SearchResponse, error) {
 	if c.mode == ModeGRPC {
 		resp, err := c.mailClient.Search(ctx, &subtraterpc.SearchRequest{
-			AgentId: agentID,
-			Query:   query,
-			TopicId: topicID,
-			Limit:   int32(limit),
+			AgentId:     req.AgentID,
+			Query:       req.Query,
+			Limit:       req.Limit,
+			Label:       req.Label,
+			Cursor:      req.Cursor,
+			SavedSearch: req.SavedSearch,
+			MessageIds:  req.MessageIDs,
 		})
 		if err != nil {
-			return nil, err
+			return mail.SearchResponse{}, err
+		}
+
+		out := mail.SearchResponse{
+			Hits:       make([]mail.SearchHit, len(resp.Results)),
+			Total:      resp.Total,
+			NextCursor: resp.NextCursor,
+		}
+		for i, m := range resp.Results {
+			out.Hits[i].InboxMessage = convertProtoMessageToMail(m)
+			if i < len(resp.Hits) {
+				out.Hits[i].Snippet = resp.Hits[i].Snippet
+				out.Hits[i].SubjectHighlight =
+					resp.Hits[i].SubjectHighlight
+				out.Hits[i].Rank = resp.Hits[i].Rank
+			}
 		}
-		return convertProtoMessagesToMail(resp.Results), nil
+
+		return out, nil
 	}
 
-	// Direct mode: use database search directly.
-	if topicID > 0 {
-		// Search within topic not implemented in direct mode yet.
-		return nil, fmt.Errorf("topic-scoped search requires daemon connection")
+	return c.mailService.Search(ctx, req)
+}
+
+// SearchAll runs a search query over messages, review issues, plan reviews,
+// tasks and annotations, or just the given kinds, returning their hits
+// merged by rank.
+func (c *Client) SearchAll(ctx context.Context, agentID int64, query string,
+	kinds []search.Kind, limit int,
+) (mail.SearchAllResponse, error) {
+	if c.mode == ModeGRPC {
+		protoKinds := make([]subtraterpc.SearchKind, len(kinds))
+		for i, k := range kinds {
+			protoKinds[i] = convertSearchKindToProto(k)
+		}
+
+		resp, err := c.mailClient.SearchAll(
+			ctx, &subtraterpc.SearchAllRequest{
+				AgentId: agentID,
+				Query:   query,
+				Kinds:   protoKinds,
+				Limit:   int32(limit),
+			},
+		)
+		if err != nil {
+			return mail.SearchAllResponse{}, err
+		}
+
+		out := mail.SearchAllResponse{
+			Hits:  make([]store.SearchAllHit, len(resp.Hits)),
+			Total: resp.Total,
+		}
+		for i, h := range resp.Hits {
+			out.Hits[i] = store.SearchAllHit{
+				Kind:      convertProtoToSearchKind(h.Kind),
+				ID:        h.Id,
+				Ref:       h.Ref,
+				ThreadID:  h.ThreadId,
+				Title:     h.Title,
+				Snippet:   h.Snippet,
+				Rank:      h.Rank,
+				CreatedAt: h.CreatedAt.AsTime(),
+			}
+		}
+
+		return out, nil
 	}
 
-	results, err := c.store.SearchMessagesForAgent(ctx, query, agentID, limit)
-	if err != nil {
-		return nil, err
+	return c.mailService.SearchAll(ctx, mail.SearchAllRequest{
+		AgentID: agentID,
+		Query:   query,
+		Kinds:   kinds,
+		Limit:   int32(limit),
+	})
+}
+
+// SemanticSearch finds an agent's messages closest in meaning to a query,
+// merged with the query's BM25 matches if hybrid is set. In direct mode,
+// messages not yet embedded are embedded first.
+func (c *Client) SemanticSearch(ctx context.Context, agentID int64,
+	query string, hybrid bool, limit int,
+) (mail.SemanticSearchResponse, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.SemanticSearch(
+			ctx, &subtraterpc.SemanticSearchRequest{
+				AgentId: agentID,
+				Query:   query,
+				Limit:   int32(limit),
+				Hybrid:  hybrid,
+			},
+		)
+		if err != nil {
+			return mail.SemanticSearchResponse{}, err
+		}
+
+		return convertProtoSemanticResponse(resp), nil
 	}
 
-	messages := make([]mail.InboxMessage, 0, len(results))
-	for _, r := range results {
-		messages = append(messages, mail.InboxMessage{
-			ID:        r.ID,
-			ThreadID:  r.ThreadID,
-			TopicID:   r.TopicID,
-			SenderID:  r.SenderID,
-			Subject:   r.Subject,
-			Body:      r.BodyMd,
-			Priority:  mail.Priority(r.Priority),
-			CreatedAt: time.Unix(r.CreatedAt, 0),
-		})
+	if _, err := c.mailService.IndexEmbeddings(ctx); err != nil {
+		return mail.SemanticSearchResponse{}, err
+	}
+
+	return c.mailService.SemanticSearch(ctx, mail.SemanticSearchRequest{
+		AgentID: agentID,
+		Query:   query,
+		Limit:   int32(limit),
+		Hybrid:  hybrid,
+	})
+}
+
+// SimilarMessages finds the agent's messages at least minScore similar to a
+// text, best first.
+func (c *Client) SimilarMessages(ctx context.Context, agentID int64,
+	text string, minScore float64, limit int,
+) (mail.SemanticSearchResponse, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.SimilarMessages(
+			ctx, &subtraterpc.SimilarMessagesRequest{
+				AgentId:  agentID,
+				Text:     text,
+				Limit:    int32(limit),
+				MinScore: minScore,
+			},
+		)
+		if err != nil {
+			return mail.SemanticSearchResponse{}, err
+		}
+
+		return convertProtoSemanticResponse(resp), nil
+	}
+
+	if _, err := c.mailService.IndexEmbeddings(ctx); err != nil {
+		return mail.SemanticSearchResponse{}, err
+	}
+
+	return c.mailService.SimilarMessages(ctx, mail.SimilarMessagesRequest{
+		AgentID:  agentID,
+		Text:     text,
+		Limit:    int32(limit),
+		MinScore: minScore,
+	})
+}
+
+// convertProtoSemanticResponse converts the hits of a semantic search.
+func convertProtoSemanticResponse(
+	resp *subtraterpc.SemanticSearchResponse,
+) mail.SemanticSearchResponse {
+	out := mail.SemanticSearchResponse{
+		Hits: make([]mail.SemanticHit, len(resp.Results)),
+	}
+	for i, m := range resp.Results {
+		out.Hits[i].InboxMessage = convertProtoMessageToMail(m)
+		if i < len(resp.Hits) {
+			out.Hits[i].Snippet = resp.Hits[i].Snippet
+			out.Hits[i].Score = resp.Hits[i].Score
+			out.Hits[i].Similarity = resp.Hits[i].Similarity
+			out.Hits[i].TextScore = resp.Hits[i].TextScore
+		}
 	}
-	return messages, nil
+
+	return out
 }
 
 // Publish sends a message to a topic.
-func (c *Client) Publish(ctx context.Context, senderID int64, topicName, subject, body string, priority mail.Priority) (int64, int, error) {
+func (c *Client) Publish(ctx context.Context, senderID int64, topicName, subject, body string, priority mail.Priority) (int64, int, bool, error) {
 	if c.mode == ModeGRPC {
 		resp, err := c.mailClient.Publish(ctx, &subtraterpc.PublishRequest{
 			SenderId:  senderID,
@@ -829,9 +1109,9 @@ func (c *Client) Publish(ctx context.Context, senderID int64, topicName, subject
 			Priority:  convertPriorityToProto(priority),
 		})
 		if err != nil {
-			return 0, 0, err
+			return 0, 0, false, err
 		}
-		return resp.MessageId, int(resp.RecipientsCount), nil
+		return resp.MessageId, int(resp.RecipientsCount), resp.Queued, nil
 	}
 
 	result := c.mailService.Receive(ctx, mail.PublishRequest{
@@ -843,13 +1123,13 @@ func (c *Client) Publish(ctx context.Context, senderID int64, topicName, subject
 	})
 	val, err := result.Unpack()
 	if err != nil {
-		return 0, 0, err
+		return 0, 0, false, err
 	}
 	resp := val.(mail.PublishResponse)
 	if resp.Error != nil {
-		return 0, 0, resp.Error
+		return 0, 0, false, resp.Error
 	}
-	return resp.MessageID, resp.RecipientsCount, nil
+	return resp.MessageID, resp.RecipientsCount, resp.Queued, nil
 }
 
 // RegisterAgent creates a new agent.
@@ -987,15 +1267,24 @@ func (c *Client) ListConsumerOffsets(ctx context.Context, agentID int64) (map[st
 }
 
 // Subscribe subscribes an agent to a topic.
-func (c *Client) Subscribe(ctx context.Context, agentID int64, topicName string) error {
+func (c *Client) Subscribe(ctx context.Context, agentID int64, topicName, topicType string) error {
 	if c.mode == ModeGRPC {
 		_, err := c.mailClient.Subscribe(ctx, &subtraterpc.SubscribeRequest{
 			AgentId:   agentID,
 			TopicName: topicName,
+			TopicType: topicType,
 		})
 		return err
 	}
 
+	// An explicit type creates the topic if it doesn't exist yet.
+	if topicType != "" {
+		_, err := c.mailService.SubscribeTopic(
+			ctx, agentID, topicName, topicType,
+		)
+		return err
+	}
+
 	// Get the topic.
 	topic, err := c.store.Queries().GetTopicByName(ctx, topicName)
 	if err != nil {
@@ -1020,73 +1309,1355 @@ func (c *Client) Subscribe(ctx context.Context, agentID int64, topicName string)
 	})
 }
 
-// Unsubscribe removes an agent's subscription to a topic.
-func (c *Client) Unsubscribe(ctx context.Context, agentID int64, topicName string) error {
+// ClaimQueueMessage leases the next available message on a queue topic. A
+// nil claim is returned when the queue is empty.
+func (c *Client) ClaimQueueMessage(ctx context.Context, agentID int64,
+	topicName string, visibility time.Duration,
+) (*mail.QueueClaim, error) {
 	if c.mode == ModeGRPC {
-		_, err := c.mailClient.Unsubscribe(ctx, &subtraterpc.UnsubscribeRequest{
-			AgentId:   agentID,
-			TopicName: topicName,
-		})
+		resp, err := c.mailClient.ClaimQueueMessage(
+			ctx, &subtraterpc.ClaimQueueMessageRequest{
+				AgentId:   agentID,
+				TopicName: topicName,
+				VisibilityTimeoutSeconds: int64(
+					visibility.Seconds(),
+				),
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !resp.Claimed {
+			return nil, nil
+		}
+
+		return &mail.QueueClaim{
+			Message:        convertProtoMessageToMail(resp.Message),
+			LeaseExpiresAt: resp.LeaseExpiresAt.AsTime(),
+			DeliveryCount:  int(resp.DeliveryCount),
+		}, nil
+	}
+
+	return c.mailService.ClaimQueueMessage(
+		ctx, agentID, topicName, visibility,
+	)
+}
+
+// AckQueueMessage completes the agent's lease on a queue message.
+func (c *Client) AckQueueMessage(ctx context.Context, agentID,
+	messageID int64,
+) error {
+	if c.mode == ModeGRPC {
+		_, err := c.mailClient.AckQueueMessage(
+			ctx, &subtraterpc.AckQueueMessageRequest{
+				AgentId:   agentID,
+				MessageId: messageID,
+			},
+		)
 		return err
 	}
 
-	// Get the topic.
-	topic, err := c.store.Queries().GetTopicByName(ctx, topicName)
-	if err != nil {
-		return fmt.Errorf("topic %q not found", topicName)
+	return c.mailService.AckQueueMessage(ctx, agentID, messageID)
+}
+
+// ReleaseQueueMessage hands the agent's lease on a queue message back to the
+// queue.
+func (c *Client) ReleaseQueueMessage(ctx context.Context, agentID,
+	messageID int64, reason string,
+) error {
+	if c.mode == ModeGRPC {
+		_, err := c.mailClient.ReleaseQueueMessage(
+			ctx, &subtraterpc.ReleaseQueueMessageRequest{
+				AgentId:   agentID,
+				MessageId: messageID,
+				Reason:    reason,
+			},
+		)
+		return err
 	}
 
-	// Delete subscription.
-	return c.store.Queries().DeleteSubscription(ctx, sqlc.DeleteSubscriptionParams{
-		AgentID: agentID,
-		TopicID: topic.ID,
-	})
+	return c.mailService.ReleaseQueueMessage(
+		ctx, agentID, messageID, reason,
+	)
 }
 
-// TopicInfo represents information about a topic.
-type TopicInfo struct {
-	ID               int64
-	Name             string
-	Type             string
-	RetentionSeconds int64
-	SubscriberCount  int64
+// ExtendQueueLease pushes out the agent's lease on a queue message and
+// returns the new expiry.
+func (c *Client) ExtendQueueLease(ctx context.Context, agentID,
+	messageID int64, visibility time.Duration,
+) (time.Time, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.ExtendQueueLease(
+			ctx, &subtraterpc.ExtendQueueLeaseRequest{
+				AgentId:   agentID,
+				MessageId: messageID,
+				VisibilityTimeoutSeconds: int64(
+					visibility.Seconds(),
+				),
+			},
+		)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		return resp.LeaseExpiresAt.AsTime(), nil
+	}
+
+	return c.mailService.ExtendQueueLease(
+		ctx, agentID, messageID, visibility,
+	)
 }
 
-// ListTopics returns all topics.
-func (c *Client) ListTopics(ctx context.Context) ([]TopicInfo, error) {
+// CollectGarbage enforces topic retention, or reports what would be deleted
+// when dryRun is set.
+func (c *Client) CollectGarbage(ctx context.Context,
+	dryRun bool,
+) (mail.RetentionReport, error) {
 	if c.mode == ModeGRPC {
-		resp, err := c.mailClient.ListTopics(ctx, &subtraterpc.ListTopicsRequest{})
+		resp, err := c.mailClient.CollectGarbage(
+			ctx, &subtraterpc.CollectGarbageRequest{DryRun: dryRun},
+		)
 		if err != nil {
-			return nil, err
+			return mail.RetentionReport{}, err
 		}
-		topics := make([]TopicInfo, len(resp.Topics))
+
+		topics := make([]store.ExpiredTopicSummary, len(resp.Topics))
 		for i, t := range resp.Topics {
-			topics[i] = TopicInfo{
-				ID:   t.Id,
-				Name: t.Name,
-				Type: t.TopicType,
-				// RetentionSeconds and SubscriberCount not in proto.
+			topics[i] = store.ExpiredTopicSummary{
+				TopicID:          t.TopicId,
+				TopicName:        t.TopicName,
+				RetentionSeconds: t.RetentionSeconds,
+				ExpiredCount:     t.ExpiredCount,
+				DeletableCount:   t.DeletableCount,
+				RecipientCount:   t.RecipientCount,
 			}
 		}
-		return topics, nil
-	}
 
-	dbTopics, err := c.store.Queries().ListTopics(ctx)
-	if err != nil {
-		return nil, err
+		return mail.RetentionReport{
+			DryRun:                 resp.DryRun,
+			Topics:                 topics,
+			MessagesDeleted:        resp.MessagesDeleted,
+			RecipientsDeleted:      resp.RecipientsDeleted,
+			ConsumerOffsetsDeleted: resp.ConsumerOffsetsDeleted,
+		}, nil
 	}
 
-	topics := make([]TopicInfo, len(dbTopics))
-	for i, t := range dbTopics {
-		var retention int64
-		if t.RetentionSeconds.Valid {
-			retention = t.RetentionSeconds.Int64
+	compactor := mail.NewRetentionCompactor(mail.RetentionCompactorConfig{
+		Store: store.FromDB(c.store.DB()),
+	})
+
+	return compactor.Compact(ctx, time.Now(), dryRun)
+}
+
+// SetLegalHold places or lifts a legal hold on a message, or on every
+// message in a thread when threadID is set. The number of messages updated
+// is returned.
+func (c *Client) SetLegalHold(ctx context.Context, messageID int64,
+	threadID string, hold bool,
+) (int64, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.SetLegalHold(
+			ctx, &subtraterpc.SetLegalHoldRequest{
+				MessageId: messageID,
+				ThreadId:  threadID,
+				Hold:      hold,
+			},
+		)
+		if err != nil {
+			return 0, err
 		}
 
-		count, _ := c.store.Queries().CountSubscribersByTopic(ctx, t.ID)
+		return resp.MessagesUpdated, nil
+	}
 
-		topics[i] = TopicInfo{
-			ID:               t.ID,
+	s := store.FromDB(c.store.DB())
+	if threadID != "" {
+		return s.SetThreadLegalHold(ctx, threadID, hold)
+	}
+
+	return s.SetMessageLegalHold(ctx, messageID, hold)
+}
+
+// SetTopicRetention sets how long a topic's messages are kept. A retention
+// of 0 keeps them forever.
+func (c *Client) SetTopicRetention(ctx context.Context, topicName string,
+	retention time.Duration,
+) (store.Topic, error) {
+	seconds := int64(retention / time.Second)
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.SetTopicRetention(
+			ctx, &subtraterpc.SetTopicRetentionRequest{
+				TopicName:        topicName,
+				RetentionSeconds: seconds,
+			},
+		)
+		if err != nil {
+			return store.Topic{}, err
+		}
+
+		return store.Topic{
+			ID:               resp.Topic.Id,
+			Name:             resp.Topic.Name,
+			TopicType:        resp.Topic.TopicType,
+			RetentionSeconds: resp.Topic.RetentionSeconds,
+			CreatedAt:        resp.Topic.CreatedAt.AsTime(),
+		}, nil
+	}
+
+	s := store.FromDB(c.store.DB())
+	topic, err := s.GetTopicByName(ctx, topicName)
+	if err != nil {
+		return store.Topic{}, err
+	}
+	if err := s.UpdateTopicRetention(ctx, topic.ID, seconds); err != nil {
+		return store.Topic{}, err
+	}
+	topic.RetentionSeconds = seconds
+
+	return topic, nil
+}
+
+// recipientGroupFromProto converts a proto recipient group to its mail
+// service form.
+func recipientGroupFromProto(g *subtraterpc.RecipientGroup) mail.RecipientGroup {
+	group := mail.RecipientGroup{
+		RecipientGroup: store.RecipientGroup{
+			ID:          g.Id,
+			Name:        g.Name,
+			Description: g.Description,
+			MemberCount: g.MemberCount,
+		},
+		Members: g.Members,
+	}
+	if g.CreatedAt != nil {
+		group.CreatedAt = g.CreatedAt.AsTime()
+	}
+
+	return group
+}
+
+// CreateRecipientGroup creates a named recipient group with the given
+// members.
+func (c *Client) CreateRecipientGroup(ctx context.Context, name,
+	description string, createdBy int64, members []string,
+) (mail.RecipientGroup, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.CreateRecipientGroup(
+			ctx, &subtraterpc.CreateRecipientGroupRequest{
+				Name:        name,
+				Description: description,
+				CreatedBy:   createdBy,
+				Members:     members,
+			},
+		)
+		if err != nil {
+			return mail.RecipientGroup{}, err
+		}
+
+		return recipientGroupFromProto(resp.Group), nil
+	}
+
+	return c.mailService.CreateRecipientGroup(
+		ctx, name, description, createdBy, members,
+	)
+}
+
+// GetRecipientGroup returns a recipient group and its members.
+func (c *Client) GetRecipientGroup(ctx context.Context,
+	name string,
+) (mail.RecipientGroup, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.GetRecipientGroup(
+			ctx, &subtraterpc.GetRecipientGroupRequest{Name: name},
+		)
+		if err != nil {
+			return mail.RecipientGroup{}, err
+		}
+
+		return recipientGroupFromProto(resp.Group), nil
+	}
+
+	return c.mailService.GetRecipientGroup(ctx, name)
+}
+
+// ListRecipientGroups lists all recipient groups.
+func (c *Client) ListRecipientGroups(
+	ctx context.Context,
+) ([]store.RecipientGroup, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.ListRecipientGroups(
+			ctx, &subtraterpc.ListRecipientGroupsRequest{},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		groups := make([]store.RecipientGroup, len(resp.Groups))
+		for i, g := range resp.Groups {
+			groups[i] = recipientGroupFromProto(g).RecipientGroup
+		}
+
+		return groups, nil
+	}
+
+	return c.mailService.ListRecipientGroups(ctx)
+}
+
+// DeleteRecipientGroup deletes a recipient group.
+func (c *Client) DeleteRecipientGroup(ctx context.Context, name string) error {
+	if c.mode == ModeGRPC {
+		_, err := c.mailClient.DeleteRecipientGroup(
+			ctx, &subtraterpc.DeleteRecipientGroupRequest{Name: name},
+		)
+		return err
+	}
+
+	return c.mailService.DeleteRecipientGroup(ctx, name)
+}
+
+// AddRecipientGroupMembers adds agents to a recipient group.
+func (c *Client) AddRecipientGroupMembers(ctx context.Context, name string,
+	members []string,
+) (mail.RecipientGroup, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.AddRecipientGroupMembers(
+			ctx, &subtraterpc.RecipientGroupMembersRequest{
+				Name:    name,
+				Members: members,
+			},
+		)
+		if err != nil {
+			return mail.RecipientGroup{}, err
+		}
+
+		return recipientGroupFromProto(resp.Group), nil
+	}
+
+	return c.mailService.AddRecipientGroupMembers(ctx, name, members)
+}
+
+// RemoveRecipientGroupMembers removes agents from a recipient group.
+func (c *Client) RemoveRecipientGroupMembers(ctx context.Context,
+	name string, members []string,
+) (mail.RecipientGroup, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.RemoveRecipientGroupMembers(
+			ctx, &subtraterpc.RecipientGroupMembersRequest{
+				Name:    name,
+				Members: members,
+			},
+		)
+		if err != nil {
+			return mail.RecipientGroup{}, err
+		}
+
+		return recipientGroupFromProto(resp.Group), nil
+	}
+
+	return c.mailService.RemoveRecipientGroupMembers(ctx, name, members)
+}
+
+// ListDeadLetters lists the sender's undeliverable recipients, newest first.
+func (c *Client) ListDeadLetters(ctx context.Context, agentID int64,
+	limit int,
+) ([]store.DeadLetter, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.ListDeadLetters(
+			ctx, &subtraterpc.ListDeadLettersRequest{
+				AgentId: agentID,
+				Limit:   int32(limit),
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		letters := make([]store.DeadLetter, len(resp.DeadLetters))
+		for i, l := range resp.DeadLetters {
+			status := convertRecipientStatusFromProto(l.Status)
+			letters[i] = store.DeadLetter{
+				ID:        l.Id,
+				MessageID: l.MessageId,
+				ThreadID:  l.ThreadId,
+				Subject:   l.Subject,
+				SenderID:  agentID,
+				Recipient: l.Recipient,
+				Status:    string(status),
+				Reason:    l.Reason,
+				CreatedAt: l.CreatedAt.AsTime(),
+			}
+		}
+
+		return letters, nil
+	}
+
+	return c.mailService.ListDeadLetters(ctx, agentID, limit)
+}
+
+// DeleteDeadLetter removes an entry from the sender's dead-letter mailbox.
+func (c *Client) DeleteDeadLetter(ctx context.Context, agentID,
+	id int64,
+) error {
+	if c.mode == ModeGRPC {
+		_, err := c.mailClient.DeleteDeadLetter(
+			ctx, &subtraterpc.DeleteDeadLetterRequest{
+				AgentId: agentID,
+				Id:      id,
+			},
+		)
+		return err
+	}
+
+	return c.mailService.DeleteDeadLetter(ctx, agentID, id)
+}
+
+// ListScheduledMessages lists the sender's scheduled messages that have not
+// been sent or canceled, soonest first.
+func (c *Client) ListScheduledMessages(ctx context.Context, agentID int64,
+	limit int,
+) ([]store.ScheduledMessage, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.ListScheduledMessages(
+			ctx, &subtraterpc.ListScheduledMessagesRequest{
+				AgentId: agentID,
+				Limit:   int32(limit),
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs := make([]store.ScheduledMessage, len(resp.Messages))
+		for i, m := range resp.Messages {
+			msgs[i] = store.ScheduledMessage{
+				ID:             m.Id,
+				SenderID:       agentID,
+				RecipientNames: m.RecipientNames,
+				TopicName:      m.TopicName,
+				ThreadID:       m.ThreadId,
+				Subject:        m.Subject,
+				Body:           m.Body,
+				Priority: string(
+					convertProtoToPriority(m.Priority),
+				),
+				SendAt:    m.SendAt.AsTime(),
+				State:     m.State,
+				LastError: m.LastError,
+				CreatedAt: m.CreatedAt.AsTime(),
+			}
+		}
+
+		return msgs, nil
+	}
+
+	return c.mailService.ListScheduledMessages(ctx, agentID, limit)
+}
+
+// CancelScheduledMessage cancels a scheduled message before it is sent.
+func (c *Client) CancelScheduledMessage(ctx context.Context, agentID,
+	id int64,
+) error {
+	if c.mode == ModeGRPC {
+		_, err := c.mailClient.CancelScheduledMessage(
+			ctx, &subtraterpc.CancelScheduledMessageRequest{
+				AgentId: agentID,
+				Id:      id,
+			},
+		)
+		return err
+	}
+
+	return c.mailService.CancelScheduledMessage(ctx, agentID, id)
+}
+
+// CreateSchedule creates a recurring message sent on a cron schedule.
+func (c *Client) CreateSchedule(ctx context.Context,
+	req mail.CreateScheduleRequest,
+) (store.Schedule, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.CreateSchedule(
+			ctx, &subtraterpc.CreateScheduleRequest{
+				AgentId:        req.SenderID,
+				Name:           req.Name,
+				RecipientNames: req.RecipientNames,
+				TopicName:      req.TopicName,
+				Subject:        req.Subject,
+				Body:           req.Body,
+				Priority: convertPriorityToProto(
+					req.Priority,
+				),
+				CronExpr: req.CronExpr,
+				Timezone: req.Timezone,
+				CatchUp:  string(req.CatchUp),
+			},
+		)
+		if err != nil {
+			return store.Schedule{}, err
+		}
+
+		return convertProtoSchedule(req.SenderID, resp.Schedule), nil
+	}
+
+	return c.mailService.CreateSchedule(ctx, req)
+}
+
+// ListSchedules lists the agent's recurring schedules by name.
+func (c *Client) ListSchedules(ctx context.Context,
+	agentID int64,
+) ([]store.Schedule, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.ListSchedules(
+			ctx, &subtraterpc.ListSchedulesRequest{
+				AgentId: agentID,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		schedules := make([]store.Schedule, len(resp.Schedules))
+		for i, sched := range resp.Schedules {
+			schedules[i] = convertProtoSchedule(agentID, sched)
+		}
+
+		return schedules, nil
+	}
+
+	return c.mailService.ListSchedules(ctx, agentID)
+}
+
+// DeleteSchedule deletes one of the agent's recurring schedules.
+func (c *Client) DeleteSchedule(ctx context.Context, agentID int64,
+	name string,
+) error {
+	if c.mode == ModeGRPC {
+		_, err := c.mailClient.DeleteSchedule(
+			ctx, &subtraterpc.DeleteScheduleRequest{
+				AgentId: agentID,
+				Name:    name,
+			},
+		)
+		return err
+	}
+
+	return c.mailService.DeleteSchedule(ctx, agentID, name)
+}
+
+// SetSchedulePaused pauses or resumes one of the agent's recurring
+// schedules.
+func (c *Client) SetSchedulePaused(ctx context.Context, agentID int64,
+	name string, paused bool,
+) (store.Schedule, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.SetSchedulePaused(
+			ctx, &subtraterpc.SetSchedulePausedRequest{
+				AgentId: agentID,
+				Name:    name,
+				Paused:  paused,
+			},
+		)
+		if err != nil {
+			return store.Schedule{}, err
+		}
+
+		return convertProtoSchedule(agentID, resp.Schedule), nil
+	}
+
+	if paused {
+		return c.mailService.PauseSchedule(ctx, agentID, name)
+	}
+	return c.mailService.ResumeSchedule(ctx, agentID, name)
+}
+
+// AskQuestion sends a structured question.
+func (c *Client) AskQuestion(ctx context.Context,
+	req mail.AskQuestionRequest,
+) (store.Question, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.AskQuestion(
+			ctx, &subtraterpc.AskQuestionRequest{
+				AgentId:        req.SenderID,
+				RecipientNames: req.RecipientNames,
+				Question:       req.Question,
+				Details:        req.Details,
+				Options:        req.Options,
+				DefaultAnswer:  req.DefaultAnswer,
+				TimeoutSeconds: int64(
+					req.Timeout / time.Second,
+				),
+				Fallback: req.Fallback,
+				Priority: convertPriorityToProto(
+					req.Priority,
+				),
+			},
+		)
+		if err != nil {
+			return store.Question{}, err
+		}
+
+		return convertProtoQuestion(resp.Question), nil
+	}
+
+	return c.mailService.AskQuestion(ctx, req)
+}
+
+// GetQuestion returns a question and, once resolved, its answer.
+func (c *Client) GetQuestion(ctx context.Context,
+	questionID string,
+) (store.Question, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.GetQuestion(
+			ctx, &subtraterpc.GetQuestionRequest{
+				QuestionId: questionID,
+			},
+		)
+		if err != nil {
+			return store.Question{}, err
+		}
+
+		return convertProtoQuestion(resp.Question), nil
+	}
+
+	return c.mailService.GetQuestion(ctx, questionID)
+}
+
+// AnswerQuestion answers a question by replying in its thread.
+func (c *Client) AnswerQuestion(ctx context.Context, questionID string,
+	agentID int64, answer string,
+) (store.Question, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.AnswerQuestion(
+			ctx, &subtraterpc.AnswerQuestionRequest{
+				QuestionId: questionID,
+				AgentId:    agentID,
+				Answer:     answer,
+			},
+		)
+		if err != nil {
+			return store.Question{}, err
+		}
+
+		return convertProtoQuestion(resp.Question), nil
+	}
+
+	return c.mailService.AnswerQuestion(ctx, questionID, agentID, answer)
+}
+
+// EditMessage edits one of the agent's sent messages, returning the revision
+// number of the edited version.
+func (c *Client) EditMessage(ctx context.Context,
+	req mail.EditMessageRequest,
+) (int, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.EditMessage(
+			ctx, &subtraterpc.EditMessageRequest{
+				AgentId:   req.SenderID,
+				MessageId: req.MessageID,
+				Subject:   req.Subject,
+				Body:      req.Body,
+			},
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		return int(resp.Revision), nil
+	}
+
+	resp, err := c.mailService.EditMessage(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.Revision, nil
+}
+
+// RecallMessage recalls one of the agent's sent messages that no recipient
+// has read yet.
+func (c *Client) RecallMessage(ctx context.Context, agentID,
+	messageID int64,
+) error {
+	if c.mode == ModeGRPC {
+		_, err := c.mailClient.RecallMessage(
+			ctx, &subtraterpc.RecallMessageRequest{
+				AgentId:   agentID,
+				MessageId: messageID,
+			},
+		)
+		return err
+	}
+
+	return c.mailService.RecallMessage(ctx, agentID, messageID)
+}
+
+// GetMessageHistory returns every version of a message, oldest first, ending
+// with its current version.
+func (c *Client) GetMessageHistory(ctx context.Context,
+	agentID, messageID int64,
+) ([]store.MessageRevision, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.GetMessageHistory(
+			ctx, &subtraterpc.GetMessageHistoryRequest{
+				AgentId:   agentID,
+				MessageId: messageID,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		versions := make([]store.MessageRevision, len(resp.Revisions))
+		for i, rev := range resp.Revisions {
+			versions[i] = store.MessageRevision{
+				MessageID:  rev.MessageId,
+				Revision:   int(rev.Revision),
+				Subject:    rev.Subject,
+				Body:       rev.Body,
+				CreatedAt:  timestampToTime(rev.CreatedAt),
+				ReplacedAt: timestampToTime(rev.ReplacedAt),
+			}
+		}
+
+		return versions, nil
+	}
+
+	history, err := c.mailService.GetMessageHistory(
+		ctx, agentID, messageID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return history.Versions(), nil
+}
+
+// CreateInboxRule adds a rule after the agent's existing inbox rules.
+func (c *Client) CreateInboxRule(ctx context.Context,
+	req mail.CreateInboxRuleRequest,
+) (store.InboxRule, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.inboxRuleClient.CreateInboxRule(
+			ctx, &subtraterpc.CreateInboxRuleRequest{
+				AgentId:        req.AgentID,
+				Name:           req.Name,
+				StopProcessing: req.StopProcessing,
+				Conditions: convertInboxRuleConditionsToProto(
+					req.Conditions,
+				),
+				Actions: &subtraterpc.InboxRuleActions{
+					Archive:   req.Actions.Archive,
+					Star:      req.Actions.Star,
+					Snooze:    req.Actions.Snooze,
+					Category:  string(req.Actions.Category),
+					ForwardTo: req.Actions.ForwardTo,
+					Label:     req.Actions.Label,
+				},
+			},
+		)
+		if err != nil {
+			return store.InboxRule{}, err
+		}
+
+		return convertProtoInboxRule(resp), nil
+	}
+
+	return c.mailService.CreateInboxRule(ctx, req)
+}
+
+// ListInboxRules lists the agent's inbox rules in the order they are
+// applied.
+func (c *Client) ListInboxRules(ctx context.Context,
+	agentID int64,
+) ([]store.InboxRule, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.inboxRuleClient.ListInboxRules(
+			ctx, &subtraterpc.ListInboxRulesRequest{
+				AgentId: agentID,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		rules := make([]store.InboxRule, len(resp.Rules))
+		for i, rule := range resp.Rules {
+			rules[i] = convertProtoInboxRule(rule)
+		}
+
+		return rules, nil
+	}
+
+	return c.mailService.ListInboxRules(ctx, agentID)
+}
+
+// DeleteInboxRule deletes one of the agent's inbox rules.
+func (c *Client) DeleteInboxRule(ctx context.Context, agentID int64,
+	name string,
+) error {
+	if c.mode == ModeGRPC {
+		_, err := c.inboxRuleClient.DeleteInboxRule(
+			ctx, &subtraterpc.DeleteInboxRuleRequest{
+				AgentId: agentID,
+				Name:    name,
+			},
+		)
+		return err
+	}
+
+	return c.mailService.DeleteInboxRule(ctx, agentID, name)
+}
+
+// SetInboxRuleEnabled enables or disables one of the agent's inbox rules.
+func (c *Client) SetInboxRuleEnabled(ctx context.Context, agentID int64,
+	name string, enabled bool,
+) (store.InboxRule, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.inboxRuleClient.SetInboxRuleEnabled(
+			ctx, &subtraterpc.SetInboxRuleEnabledRequest{
+				AgentId: agentID,
+				Name:    name,
+				Enabled: enabled,
+			},
+		)
+		if err != nil {
+			return store.InboxRule{}, err
+		}
+
+		return convertProtoInboxRule(resp), nil
+	}
+
+	return c.mailService.SetInboxRuleEnabled(ctx, agentID, name, enabled)
+}
+
+// TestInboxRule reports which of the agent's recently received messages an
+// inbox rule would match, without changing them.
+func (c *Client) TestInboxRule(ctx context.Context,
+	req mail.TestInboxRuleRequest,
+) (mail.TestInboxRuleResponse, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.inboxRuleClient.TestInboxRule(
+			ctx, &subtraterpc.TestInboxRuleRequest{
+				AgentId: req.AgentID,
+				Name:    req.Name,
+				Conditions: convertInboxRuleConditionsToProto(
+					req.Conditions,
+				),
+				Limit: int32(req.Limit),
+			},
+		)
+		if err != nil {
+			return mail.TestInboxRuleResponse{}, err
+		}
+
+		return mail.TestInboxRuleResponse{
+			Scanned: int(resp.Scanned),
+			Matches: convertProtoMessagesToMail(resp.Matches),
+		}, nil
+	}
+
+	return c.mailService.TestInboxRule(ctx, req)
+}
+
+// CreateLabel creates a label the agent can file its messages under.
+func (c *Client) CreateLabel(ctx context.Context, agentID int64,
+	name string,
+) (store.Label, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.CreateLabel(
+			ctx, &subtraterpc.CreateLabelRequest{
+				AgentId: agentID,
+				Name:    name,
+			},
+		)
+		if err != nil {
+			return store.Label{}, err
+		}
+
+		return convertProtoLabel(resp), nil
+	}
+
+	return c.mailService.CreateLabel(ctx, agentID, name)
+}
+
+// ListLabels lists the agent's labels by name, with their counts.
+func (c *Client) ListLabels(ctx context.Context,
+	agentID int64,
+) ([]store.Label, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.ListLabels(
+			ctx, &subtraterpc.ListLabelsRequest{AgentId: agentID},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		labels := make([]store.Label, len(resp.Labels))
+		for i, label := range resp.Labels {
+			labels[i] = convertProtoLabel(label)
+		}
+
+		return labels, nil
+	}
+
+	return c.mailService.ListLabels(ctx, agentID)
+}
+
+// DeleteLabel deletes one of the agent's labels.
+func (c *Client) DeleteLabel(ctx context.Context, agentID int64,
+	name string,
+) error {
+	if c.mode == ModeGRPC {
+		_, err := c.mailClient.DeleteLabel(
+			ctx, &subtraterpc.DeleteLabelRequest{
+				AgentId: agentID,
+				Name:    name,
+			},
+		)
+		return err
+	}
+
+	return c.mailService.DeleteLabel(ctx, agentID, name)
+}
+
+// SaveSearch saves a search query under a name for the agent.
+func (c *Client) SaveSearch(ctx context.Context, agentID int64, name,
+	query string,
+) (store.SavedSearch, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.SaveSearch(
+			ctx, &subtraterpc.SaveSearchRequest{
+				AgentId: agentID,
+				Name:    name,
+				Query:   query,
+			},
+		)
+		if err != nil {
+			return store.SavedSearch{}, err
+		}
+
+		return convertProtoSavedSearch(resp), nil
+	}
+
+	return c.mailService.SaveSearch(ctx, agentID, name, query)
+}
+
+// ListSavedSearches lists the agent's saved searches by name.
+func (c *Client) ListSavedSearches(ctx context.Context,
+	agentID int64,
+) ([]store.SavedSearch, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.ListSavedSearches(
+			ctx, &subtraterpc.ListSavedSearchesRequest{
+				AgentId: agentID,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		searches := make([]store.SavedSearch, len(resp.Searches))
+		for i, saved := range resp.Searches {
+			searches[i] = convertProtoSavedSearch(saved)
+		}
+
+		return searches, nil
+	}
+
+	return c.mailService.ListSavedSearches(ctx, agentID)
+}
+
+// DeleteSavedSearch deletes one of the agent's saved searches.
+func (c *Client) DeleteSavedSearch(ctx context.Context, agentID int64,
+	name string,
+) error {
+	if c.mode == ModeGRPC {
+		_, err := c.mailClient.DeleteSavedSearch(
+			ctx, &subtraterpc.DeleteSavedSearchRequest{
+				AgentId: agentID,
+				Name:    name,
+			},
+		)
+		return err
+	}
+
+	return c.mailService.DeleteSavedSearch(ctx, agentID, name)
+}
+
+// LabelMessage applies and removes labels on the agent's copy of a message,
+// returning the labels it carries afterwards.
+func (c *Client) LabelMessage(ctx context.Context,
+	req mail.LabelMessageRequest,
+) ([]string, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.LabelMessage(
+			ctx, &subtraterpc.LabelMessageRequest{
+				AgentId:   req.AgentID,
+				MessageId: req.MessageID,
+				Add:       req.Add,
+				Remove:    req.Remove,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return resp.Labels, nil
+	}
+
+	return c.mailService.LabelMessage(ctx, req)
+}
+
+// SetThreadMuted mutes or unmutes a thread for an agent.
+func (c *Client) SetThreadMuted(ctx context.Context, agentID int64,
+	threadID string, muted bool,
+) (store.ThreadPreference, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.SetThreadMuted(
+			ctx, &subtraterpc.SetThreadMutedRequest{
+				AgentId:  agentID,
+				ThreadId: threadID,
+				Muted:    muted,
+			},
+		)
+		if err != nil {
+			return store.ThreadPreference{}, err
+		}
+
+		return convertProtoThreadPreference(resp), nil
+	}
+
+	return c.mailService.SetThreadMuted(ctx, agentID, threadID, muted)
+}
+
+// SetThreadFollowing follows or unfollows a thread for an agent.
+func (c *Client) SetThreadFollowing(ctx context.Context, agentID int64,
+	threadID string, following bool,
+) (store.ThreadPreference, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.SetThreadFollowing(
+			ctx, &subtraterpc.SetThreadFollowingRequest{
+				AgentId:   agentID,
+				ThreadId:  threadID,
+				Following: following,
+			},
+		)
+		if err != nil {
+			return store.ThreadPreference{}, err
+		}
+
+		return convertProtoThreadPreference(resp), nil
+	}
+
+	return c.mailService.SetThreadFollowing(
+		ctx, agentID, threadID, following,
+	)
+}
+
+// ListThreadPreferences lists the threads an agent muted or follows.
+func (c *Client) ListThreadPreferences(ctx context.Context,
+	agentID int64,
+) ([]store.ThreadPreference, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.ListThreadPreferences(
+			ctx, &subtraterpc.ListThreadPreferencesRequest{
+				AgentId: agentID,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		prefs := make([]store.ThreadPreference, len(resp.Threads))
+		for i, pref := range resp.Threads {
+			prefs[i] = convertProtoThreadPreference(pref)
+		}
+
+		return prefs, nil
+	}
+
+	return c.mailService.ListThreadPreferences(ctx, agentID)
+}
+
+// MergeThreads moves every message of one thread into another.
+func (c *Client) MergeThreads(ctx context.Context, fromThreadID,
+	intoThreadID string,
+) (int64, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.MergeThreads(
+			ctx, &subtraterpc.MergeThreadsRequest{
+				FromThreadId: fromThreadID,
+				IntoThreadId: intoThreadID,
+			},
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		return resp.MessagesMoved, nil
+	}
+
+	return c.mailService.MergeThreads(ctx, fromThreadID, intoThreadID)
+}
+
+// SplitThread moves a message and every later message in its thread into a
+// new thread.
+func (c *Client) SplitThread(ctx context.Context,
+	messageID int64,
+) (mail.SplitThreadResponse, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.SplitThread(
+			ctx, &subtraterpc.SplitThreadRequest{
+				MessageId: messageID,
+			},
+		)
+		if err != nil {
+			return mail.SplitThreadResponse{}, err
+		}
+
+		return mail.SplitThreadResponse{
+			ThreadID:      resp.ThreadId,
+			MessagesMoved: resp.MessagesMoved,
+		}, nil
+	}
+
+	return c.mailService.SplitThread(ctx, messageID)
+}
+
+// ReplyResult is the result of replying to a message.
+type ReplyResult struct {
+	MessageID      int64
+	ThreadID       string
+	RecipientNames []string
+}
+
+// Reply replies to a message, by default to its sender and every other
+// recipient of it.
+func (c *Client) Reply(ctx context.Context,
+	req mail.ReplyRequest,
+) (*ReplyResult, error) {
+	if c.mode == ModeGRPC {
+		priority := convertPriorityToProto(req.Priority)
+		resp, err := c.mailClient.ReplyToThread(
+			ctx, &subtraterpc.ReplyToThreadRequest{
+				SenderId:       req.SenderID,
+				MessageId:      req.MessageID,
+				Body:           req.Body,
+				SenderOnly:     req.SenderOnly,
+				Quote:          req.Quote,
+				Priority:       priority,
+				IdempotencyKey: req.IdempotencyKey,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ReplyResult{
+			MessageID:      resp.MessageId,
+			ThreadID:       resp.ThreadId,
+			RecipientNames: resp.RecipientNames,
+		}, nil
+	}
+
+	sendReq, err := c.mailService.PrepareReply(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.SendMailDetailed(ctx, sendReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReplyResult{
+		MessageID:      resp.MessageID,
+		ThreadID:       resp.ThreadID,
+		RecipientNames: sendReq.RecipientNames,
+	}, nil
+}
+
+// ArchiveThread archives the agent's messages in a thread, returning how
+// many were archived.
+func (c *Client) ArchiveThread(ctx context.Context, agentID int64,
+	threadID string,
+) (int, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.ArchiveThread(
+			ctx, &subtraterpc.ArchiveThreadRequest{
+				AgentId:  agentID,
+				ThreadId: threadID,
+			},
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		return int(resp.MessagesArchived), nil
+	}
+
+	return c.setThreadState(
+		ctx, agentID, threadID, mail.StateArchivedStr.String(),
+	)
+}
+
+// DeleteThread moves the agent's messages in a thread to trash, returning
+// how many were moved.
+func (c *Client) DeleteThread(ctx context.Context, agentID int64,
+	threadID string,
+) (int, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.DeleteThread(
+			ctx, &subtraterpc.DeleteThreadRequest{
+				AgentId:  agentID,
+				ThreadId: threadID,
+			},
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		return int(resp.MessagesDeleted), nil
+	}
+
+	return c.setThreadState(
+		ctx, agentID, threadID, mail.StateTrashStr.String(),
+	)
+}
+
+// MarkThreadUnread marks the agent's messages in a thread unread, returning
+// how many were marked.
+func (c *Client) MarkThreadUnread(ctx context.Context, agentID int64,
+	threadID string,
+) (int, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.MarkThreadUnread(
+			ctx, &subtraterpc.MarkThreadUnreadRequest{
+				AgentId:  agentID,
+				ThreadId: threadID,
+			},
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		return int(resp.MessagesMarked), nil
+	}
+
+	return c.setThreadState(
+		ctx, agentID, threadID, mail.StateUnreadStr.String(),
+	)
+}
+
+// setThreadState moves each message the agent received in a thread to a new
+// state through the mail service, the way the thread RPCs do, returning how
+// many moved.
+func (c *Client) setThreadState(ctx context.Context, agentID int64,
+	threadID, state string,
+) (int, error) {
+	msgIDs, err := c.mailService.ReceivedThreadMessages(
+		ctx, agentID, threadID,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, msgID := range msgIDs {
+		err := c.UpdateState(ctx, agentID, msgID, state, nil)
+		if err != nil {
+			continue
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// convertProtoThreadPreference converts a proto thread preference to its
+// store form.
+func convertProtoThreadPreference(
+	pref *subtraterpc.ThreadPreferenceProto,
+) store.ThreadPreference {
+	result := store.ThreadPreference{
+		ThreadID:  pref.ThreadId,
+		AgentID:   pref.AgentId,
+		Muted:     pref.Muted,
+		Following: pref.Following,
+	}
+	if pref.UpdatedAt != nil {
+		result.UpdatedAt = pref.UpdatedAt.AsTime()
+	}
+
+	return result
+}
+
+// Unsubscribe removes an agent's subscription to a topic.
+func (c *Client) Unsubscribe(ctx context.Context, agentID int64, topicName string) error {
+	if c.mode == ModeGRPC {
+		_, err := c.mailClient.Unsubscribe(ctx, &subtraterpc.UnsubscribeRequest{
+			AgentId:   agentID,
+			TopicName: topicName,
+		})
+		return err
+	}
+
+	// Get the topic.
+	topic, err := c.store.Queries().GetTopicByName(ctx, topicName)
+	if err != nil {
+		return fmt.Errorf("topic %q not found", topicName)
+	}
+
+	// Delete subscription.
+	return c.store.Queries().DeleteSubscription(ctx, sqlc.DeleteSubscriptionParams{
+		AgentID: agentID,
+		TopicID: topic.ID,
+	})
+}
+
+// TopicInfo represents information about a topic.
+type TopicInfo struct {
+	ID               int64
+	Name             string
+	Type             string
+	RetentionSeconds int64
+	SubscriberCount  int64
+}
+
+// ListTopics returns all topics.
+func (c *Client) ListTopics(ctx context.Context) ([]TopicInfo, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.mailClient.ListTopics(ctx, &subtraterpc.ListTopicsRequest{})
+		if err != nil {
+			return nil, err
+		}
+		topics := make([]TopicInfo, len(resp.Topics))
+		for i, t := range resp.Topics {
+			topics[i] = TopicInfo{
+				ID:   t.Id,
+				Name: t.Name,
+				Type: t.TopicType,
+				// RetentionSeconds and SubscriberCount not in proto.
+			}
+		}
+		return topics, nil
+	}
+
+	dbTopics, err := c.store.Queries().ListTopics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	topics := make([]TopicInfo, len(dbTopics))
+	for i, t := range dbTopics {
+		var retention int64
+		if t.RetentionSeconds.Valid {
+			retention = t.RetentionSeconds.Int64
+		}
+
+		count, _ := c.store.Queries().CountSubscribersByTopic(ctx, t.ID)
+
+		topics[i] = TopicInfo{
+			ID:               t.ID,
 			Name:             t.Name,
 			Type:             t.TopicType,
 			RetentionSeconds: retention,
@@ -1169,6 +2740,7 @@ func convertProtoMessageToMail(m *subtraterpc.InboxMessage) mail.InboxMessage {
 		Priority:   convertProtoToPriority(m.Priority),
 		State:      convertProtoToState(m.State),
 		CreatedAt:  timestampToTime(m.CreatedAt),
+		Labels:     m.Labels,
 	}
 
 	if m.DeadlineAt != nil && m.DeadlineAt.IsValid() {
@@ -1187,6 +2759,10 @@ func convertProtoMessageToMail(m *subtraterpc.InboxMessage) mail.InboxMessage {
 		t := m.AcknowledgedAt.AsTime()
 		msg.AckedAt = &t
 	}
+	if m.EditedAt != nil && m.EditedAt.IsValid() {
+		t := m.EditedAt.AsTime()
+		msg.EditedAt = &t
+	}
 
 	return msg
 }
@@ -1226,6 +2802,156 @@ func convertProtoToPriority(p subtraterpc.Priority) mail.Priority {
 	}
 }
 
+// convertProtoSchedule converts a proto schedule owned by the given agent to
+// the store type.
+func convertProtoSchedule(agentID int64,
+	sched *subtraterpc.Schedule,
+) store.Schedule {
+	result := store.Schedule{
+		ID:             sched.Id,
+		Name:           sched.Name,
+		SenderID:       agentID,
+		RecipientNames: sched.RecipientNames,
+		TopicName:      sched.TopicName,
+		Subject:        sched.Subject,
+		Body:           sched.Body,
+		Priority:       string(convertProtoToPriority(sched.Priority)),
+		CronExpr:       sched.CronExpr,
+		Timezone:       sched.Timezone,
+		CatchUp:        sched.CatchUp,
+		Paused:         sched.Paused,
+		NextRunAt:      sched.NextRunAt.AsTime(),
+		LastMessageID:  sched.LastMessageId,
+		LastError:      sched.LastError,
+		RunCount:       sched.RunCount,
+		CreatedAt:      sched.CreatedAt.AsTime(),
+	}
+	if sched.LastRunAt != nil {
+		lastRun := sched.LastRunAt.AsTime()
+		result.LastRunAt = &lastRun
+	}
+
+	return result
+}
+
+// convertInboxRuleConditionsToProto converts inbox rule conditions to their
+// proto form.
+func convertInboxRuleConditionsToProto(
+	cond store.InboxRuleConditions,
+) *subtraterpc.InboxRuleConditions {
+	return &subtraterpc.InboxRuleConditions{
+		SenderName:     cond.SenderName,
+		SenderPrefix:   cond.SenderPrefix,
+		SubjectPattern: cond.SubjectPattern,
+		TopicName:      cond.TopicName,
+		Priority:       cond.Priority,
+		MetadataKey:    cond.MetadataKey,
+		MetadataValue:  cond.MetadataValue,
+	}
+}
+
+// convertProtoInboxRule converts a proto inbox rule to the store type.
+func convertProtoInboxRule(rule *subtraterpc.InboxRuleProto) store.InboxRule {
+	result := store.InboxRule{
+		ID:             rule.Id,
+		AgentID:        rule.AgentId,
+		Name:           rule.Name,
+		Enabled:        rule.Enabled,
+		StopProcessing: rule.StopProcessing,
+		MatchCount:     rule.MatchCount,
+		CreatedAt:      timestampToTime(rule.CreatedAt),
+		UpdatedAt:      timestampToTime(rule.UpdatedAt),
+	}
+	if c := rule.Conditions; c != nil {
+		result.InboxRuleConditions = store.InboxRuleConditions{
+			SenderName:     c.SenderName,
+			SenderPrefix:   c.SenderPrefix,
+			SubjectPattern: c.SubjectPattern,
+			TopicName:      c.TopicName,
+			Priority:       c.Priority,
+			MetadataKey:    c.MetadataKey,
+			MetadataValue:  c.MetadataValue,
+		}
+	}
+	if a := rule.Actions; a != nil {
+		result.InboxRuleActions = store.InboxRuleActions{
+			Archive:   a.Archive,
+			Star:      a.Star,
+			Snooze:    a.Snooze,
+			Category:  store.InboxCategory(a.Category),
+			ForwardTo: a.ForwardTo,
+			Label:     a.Label,
+		}
+	}
+	if rule.LastMatchedAt != nil {
+		lastMatched := rule.LastMatchedAt.AsTime()
+		result.LastMatchedAt = &lastMatched
+	}
+
+	return result
+}
+
+// convertProtoLabel converts a proto label to the store type.
+func convertProtoLabel(label *subtraterpc.LabelProto) store.Label {
+	return store.Label{
+		ID:           label.Id,
+		AgentID:      label.AgentId,
+		Name:         label.Name,
+		MessageCount: label.MessageCount,
+		UnreadCount:  label.UnreadCount,
+		CreatedAt:    timestampToTime(label.CreatedAt),
+	}
+}
+
+// convertProtoSavedSearch converts a proto saved search to the store type.
+func convertProtoSavedSearch(
+	saved *subtraterpc.SavedSearchProto,
+) store.SavedSearch {
+	return store.SavedSearch{
+		ID:        saved.Id,
+		AgentID:   saved.AgentId,
+		Name:      saved.Name,
+		Query:     saved.Query,
+		CreatedAt: timestampToTime(saved.CreatedAt),
+		UpdatedAt: timestampToTime(saved.UpdatedAt),
+	}
+}
+
+// convertProtoQuestion converts a proto question to the store type.
+func convertProtoQuestion(question *subtraterpc.Question) store.Question {
+	result := store.Question{
+		QuestionID:    question.QuestionId,
+		MessageID:     question.MessageId,
+		ThreadID:      question.ThreadId,
+		AskerID:       question.AskerId,
+		Question:      question.Question,
+		Options:       question.Options,
+		DefaultAnswer: question.DefaultAnswer,
+		Timeout: time.Duration(question.TimeoutSeconds) *
+			time.Second,
+		Fallback:        question.Fallback,
+		State:           question.State,
+		Answer:          question.Answer,
+		AnsweredBy:      question.AnsweredBy,
+		AnswerMessageID: question.AnswerMessageId,
+		CreatedAt:       question.CreatedAt.AsTime(),
+	}
+	if question.TimeoutAt != nil {
+		timeoutAt := question.TimeoutAt.AsTime()
+		result.TimeoutAt = &timeoutAt
+	}
+	if question.EscalatedAt != nil {
+		escalatedAt := question.EscalatedAt.AsTime()
+		result.EscalatedAt = &escalatedAt
+	}
+	if question.AnsweredAt != nil {
+		answeredAt := question.AnsweredAt.AsTime()
+		result.AnsweredAt = &answeredAt
+	}
+
+	return result
+}
+
 func convertStateToProto(s string) subtraterpc.MessageState {
 	switch s {
 	case "unread":
@@ -1264,6 +2990,36 @@ func convertProtoToState(s subtraterpc.MessageState) string {
 	}
 }
 
+// convertSearchKindToProto maps a search kind to its proto enum value, whose
+// name is the kind's upper-cased and prefixed.
+func convertSearchKindToProto(k search.Kind) subtraterpc.SearchKind {
+	name := "SEARCH_KIND_" + strings.ToUpper(string(k))
+	return subtraterpc.SearchKind(subtraterpc.SearchKind_value[name])
+}
+
+// convertProtoToSearchKind maps a proto search kind back to a search kind.
+func convertProtoToSearchKind(k subtraterpc.SearchKind) search.Kind {
+	name := strings.TrimPrefix(k.String(), "SEARCH_KIND_")
+	return search.Kind(strings.ToLower(name))
+}
+
+func convertRecipientStatusFromProto(
+	s subtraterpc.RecipientStatus,
+) mail.RecipientStatus {
+	switch s {
+	case subtraterpc.RecipientStatus_RECIPIENT_STATUS_DELIVERED:
+		return mail.RecipientDelivered
+	case subtraterpc.RecipientStatus_RECIPIENT_STATUS_QUEUED:
+		return mail.RecipientQueued
+	case subtraterpc.RecipientStatus_RECIPIENT_STATUS_UNKNOWN:
+		return mail.RecipientUnknown
+	case subtraterpc.RecipientStatus_RECIPIENT_STATUS_REJECTED:
+		return mail.RecipientRejected
+	default:
+		return ""
+	}
+}
+
 // =============================================================================
 // Review client methods (gRPC only - requires daemon)
 // =============================================================================
@@ -1524,18 +3280,18 @@ type DiscoveredAgentInfo struct {
 	WorkingDir   string
 	Hostname     string
 	UnreadCount  int64
+	Capabilities mail.AgentCapabilities
 }
 
-// DiscoverAgents returns all agents with discovery metadata. In direct
-// mode, status is computed from last_active_at timestamps. The gRPC
-// path will be wired once proto is regenerated.
+// DiscoverAgents returns all agents with discovery metadata and declared
+// capabilities. In direct mode, status is computed from last_active_at
+// timestamps.
 func (c *Client) DiscoverAgents(
 	ctx context.Context,
 ) ([]DiscoveredAgentInfo, error) {
 	if c.mode == ModeGRPC {
-		// Fallback: use GetAgentsStatus and enrich with available data.
-		resp, err := c.agentClient.GetAgentsStatus(
-			ctx, &subtraterpc.GetAgentsStatusRequest{},
+		resp, err := c.agentClient.DiscoverAgents(
+			ctx, &subtraterpc.DiscoverAgentsRequest{},
 		)
 		if err != nil {
 			return nil, err
@@ -1552,9 +3308,17 @@ func (c *Client) DiscoverAgents(
 				Name:         a.Name,
 				ProjectKey:   a.ProjectKey,
 				GitBranch:    a.GitBranch,
-				Status:       a.Status.String(),
+				Status:       discoveredStatus(a.Status),
 				LastActive:   lastActive,
 				SecondsSince: a.SecondsSinceHeartbeat,
+				SessionID:    a.SessionId,
+				Purpose:      a.Purpose,
+				WorkingDir:   a.WorkingDir,
+				Hostname:     a.Hostname,
+				UnreadCount:  a.UnreadCount,
+				Capabilities: convertProtoCapabilities(
+					a.Capabilities,
+				),
 			}
 		}
 		return agents, nil
@@ -1566,6 +3330,11 @@ func (c *Client) DiscoverAgents(
 		return nil, err
 	}
 
+	allCaps, err := c.mailService.ListAgentCapabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	agents := make([]DiscoveredAgentInfo, len(rows))
 	for i := range rows {
 		row := &rows[i]
@@ -1589,12 +3358,105 @@ func (c *Client) DiscoverAgents(
 			WorkingDir:   row.WorkingDir.String,
 			Hostname:     row.Hostname.String,
 			UnreadCount:  row.UnreadCount,
+			Capabilities: allCaps[row.ID],
 		}
 	}
 
 	return agents, nil
 }
 
+// discoveredStatus converts a proto agent status to the lowercase form
+// direct mode reports.
+func discoveredStatus(st subtraterpc.AgentStatus) string {
+	return strings.ToLower(
+		strings.TrimPrefix(st.String(), "AGENT_STATUS_"),
+	)
+}
+
+// SetAgentCapabilities replaces the capabilities and concurrency limit an
+// agent declares, returning them as stored.
+func (c *Client) SetAgentCapabilities(ctx context.Context, agentID int64,
+	caps mail.AgentCapabilities,
+) (mail.AgentCapabilities, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.agentClient.UpdateAgent(
+			ctx, &subtraterpc.UpdateAgentRequest{
+				Id: agentID,
+				Capabilities: &subtraterpc.AgentCapabilities{
+					Languages:      caps.Languages,
+					Repos:          caps.Repos,
+					Tools:          caps.Tools,
+					Skills:         caps.Skills,
+					MaxConcurrency: caps.MaxConcurrency,
+				},
+			},
+		)
+		if err != nil {
+			return mail.AgentCapabilities{}, err
+		}
+
+		return convertProtoCapabilities(resp.Agent.Capabilities), nil
+	}
+
+	return c.mailService.SetAgentCapabilities(ctx, agentID, caps)
+}
+
+// ListCapabilityRoutes lists the agents the router picked for mail and
+// tasks addressed to a capability, newest first. An empty capability lists
+// every route.
+func (c *Client) ListCapabilityRoutes(ctx context.Context,
+	capability string, limit int,
+) ([]mail.CapabilityRoute, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.agentClient.ListCapabilityRoutes(
+			ctx, &subtraterpc.ListCapabilityRoutesRequest{
+				Capability: capability,
+				Limit:      int32(limit),
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		routes := make([]mail.CapabilityRoute, len(resp.Routes))
+		for i, r := range resp.Routes {
+			routes[i] = mail.CapabilityRoute{
+				Capability:      r.Capability,
+				AgentID:         r.AgentId,
+				AgentName:       r.AgentName,
+				MessageID:       r.MessageId,
+				TaskID:          r.TaskId,
+				Candidates:      r.Candidates,
+				UnreadCount:     r.UnreadCount,
+				InProgressCount: r.InProgressCount,
+				CreatedAt:       r.CreatedAt.AsTime(),
+			}
+		}
+
+		return routes, nil
+	}
+
+	return c.mailService.ListCapabilityRoutes(ctx, capability, limit)
+}
+
+// convertProtoCapabilities converts proto capabilities, treating nil as
+// none.
+func convertProtoCapabilities(
+	caps *subtraterpc.AgentCapabilities,
+) mail.AgentCapabilities {
+	if caps == nil {
+		return mail.AgentCapabilities{}
+	}
+
+	return mail.AgentCapabilities{
+		Languages:      caps.Languages,
+		Repos:          caps.Repos,
+		Tools:          caps.Tools,
+		Skills:         caps.Skills,
+		MaxConcurrency: caps.MaxConcurrency,
+	}
+}
+
 // computeAgentStatus derives an agent's status from the elapsed time since
 // its last heartbeat and whether it has an active session.
 func computeAgentStatus(elapsed time.Duration, hasSession bool) string {
@@ -1614,3 +3476,254 @@ func computeAgentStatus(elapsed time.Duration, hasSession bool) string {
 		return "offline"
 	}
 }
+
+// MintCredential mints a credential and returns its token. In direct mode
+// this needs no credential, which is how the first tokens are minted.
+func (c *Client) MintCredential(ctx context.Context,
+	req auth.MintRequest,
+) (string, store.Credential, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.agentClient.MintCredential(
+			ctx, &subtraterpc.MintCredentialRequest{
+				AgentId:        req.AgentID,
+				Admin:          req.AgentID == 0,
+				Name:           req.Name,
+				AllowedMethods: req.AllowedMethods,
+				TtlSeconds:     int64(req.TTL.Seconds()),
+			},
+		)
+		if err != nil {
+			return "", store.Credential{}, err
+		}
+
+		return resp.Token, convertProtoCredential(resp.Credential), nil
+	}
+
+	token, cred, err := c.credentials().Mint(ctx, req)
+	cred.TokenHash = ""
+
+	return token, cred, err
+}
+
+// ListCredentials lists credentials, newest first, for one agent or for
+// everyone if agentID is zero.
+func (c *Client) ListCredentials(ctx context.Context, agentID int64,
+	includeRevoked bool,
+) ([]store.Credential, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.agentClient.ListCredentials(
+			ctx, &subtraterpc.ListCredentialsRequest{
+				AgentId:        agentID,
+				IncludeRevoked: includeRevoked,
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		creds := make([]store.Credential, len(resp.Credentials))
+		for i, cred := range resp.Credentials {
+			creds[i] = convertProtoCredential(cred)
+		}
+
+		return creds, nil
+	}
+
+	creds, err := c.credentials().List(ctx, agentID, includeRevoked)
+	if err != nil {
+		return nil, err
+	}
+
+	// Token hashes stay in the database, as they do over gRPC.
+	for i := range creds {
+		creds[i].TokenHash = ""
+	}
+
+	return creds, nil
+}
+
+// RevokeCredential revokes a credential so its token stops working.
+func (c *Client) RevokeCredential(ctx context.Context, id int64) error {
+	if c.mode == ModeGRPC {
+		_, err := c.agentClient.RevokeCredential(
+			ctx, &subtraterpc.RevokeCredentialRequest{Id: id},
+		)
+		return err
+	}
+
+	return c.credentials().Revoke(ctx, id)
+}
+
+// SetLimit sets an agent's rate limit or storage quota for a class, or the
+// default for every agent if agentID is zero.
+func (c *Client) SetLimit(ctx context.Context, agentID int64,
+	class ratelimit.Class, amount int64, interval time.Duration,
+	burst int64,
+) (store.AgentLimit, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.agentClient.SetLimit(
+			ctx, &subtraterpc.SetLimitRequest{
+				AgentId:         agentID,
+				Class:           string(class),
+				Amount:          amount,
+				IntervalSeconds: int64(interval / time.Second),
+				Burst:           burst,
+			},
+		)
+		if err != nil {
+			return store.AgentLimit{}, err
+		}
+
+		return convertProtoAgentLimit(resp.Limit), nil
+	}
+
+	limit, err := c.limiter().Set(
+		ctx, agentID, class, amount, interval, burst,
+	)
+	if err != nil {
+		return store.AgentLimit{}, err
+	}
+	if agentID != 0 {
+		ag, err := c.store.Queries().GetAgent(ctx, agentID)
+		if err == nil {
+			limit.AgentName = ag.Name
+		}
+	}
+
+	return limit, nil
+}
+
+// ClearLimit removes an agent's limit for a class, or the default if
+// agentID is zero. It returns false if none was set.
+func (c *Client) ClearLimit(ctx context.Context, agentID int64,
+	class ratelimit.Class,
+) (bool, error) {
+	if c.mode == ModeGRPC {
+		_, err := c.agentClient.ClearLimit(
+			ctx, &subtraterpc.ClearLimitRequest{
+				AgentId: agentID,
+				Class:   string(class),
+			},
+		)
+		if status.Code(err) == codes.NotFound {
+			return false, nil
+		}
+
+		return err == nil, err
+	}
+
+	return c.limiter().Clear(ctx, agentID, class)
+}
+
+// ListLimits lists the default limits followed by the agents' own, or only
+// those that apply to an agent if agentID is non-zero.
+func (c *Client) ListLimits(ctx context.Context,
+	agentID int64,
+) ([]store.AgentLimit, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.agentClient.ListLimits(
+			ctx, &subtraterpc.ListLimitsRequest{AgentId: agentID},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		limits := make([]store.AgentLimit, len(resp.Limits))
+		for i, limit := range resp.Limits {
+			limits[i] = convertProtoAgentLimit(limit)
+		}
+
+		return limits, nil
+	}
+
+	return c.limiter().List(ctx, agentID)
+}
+
+// GetUsage returns an agent's standing against its limits.
+func (c *Client) GetUsage(ctx context.Context,
+	agentID int64,
+) ([]ratelimit.Usage, error) {
+	if c.mode == ModeGRPC {
+		resp, err := c.agentClient.GetUsage(
+			ctx, &subtraterpc.GetUsageRequest{AgentId: agentID},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		usage := make([]ratelimit.Usage, len(resp.Usage))
+		for i, u := range resp.Usage {
+			usage[i] = ratelimit.Usage{
+				Class:     ratelimit.Class(u.Class),
+				Tokens:    u.Tokens,
+				Throttled: u.Throttled,
+				UsedBytes: u.UsedBytes,
+			}
+			if u.Limit != nil {
+				limit := convertProtoAgentLimit(u.Limit)
+				usage[i].Limit = &limit
+			}
+			if u.LastThrottledAt != nil {
+				t := u.LastThrottledAt.AsTime()
+				usage[i].LastThrottledAt = &t
+			}
+		}
+
+		return usage, nil
+	}
+
+	return c.limiter().Usage(ctx, agentID)
+}
+
+// limiter returns a rate limiter over the client's database, for direct
+// mode.
+func (c *Client) limiter() *ratelimit.Limiter {
+	return ratelimit.NewLimiter(store.FromDB(c.store.DB()))
+}
+
+// convertProtoAgentLimit converts a proto limit.
+func convertProtoAgentLimit(limit *subtraterpc.AgentLimit) store.AgentLimit {
+	return store.AgentLimit{
+		ID:        limit.Id,
+		AgentID:   limit.AgentId,
+		AgentName: limit.AgentName,
+		Class:     limit.Class,
+		Amount:    limit.Amount,
+		Interval:  time.Duration(limit.IntervalSeconds) * time.Second,
+		Burst:     limit.Burst,
+		UpdatedAt: limit.UpdatedAt.AsTime(),
+	}
+}
+
+// credentials returns a credential service over the client's database, for
+// direct mode.
+func (c *Client) credentials() *auth.Service {
+	return auth.NewService(store.FromDB(c.store.DB()))
+}
+
+// convertProtoCredential converts a proto credential.
+func convertProtoCredential(cred *subtraterpc.Credential) store.Credential {
+	c := store.Credential{
+		ID:             cred.Id,
+		AgentID:        cred.AgentId,
+		AgentName:      cred.AgentName,
+		Name:           cred.Name,
+		AllowedMethods: cred.AllowedMethods,
+		CreatedAt:      cred.CreatedAt.AsTime(),
+	}
+	for _, ts := range []struct {
+		from *timestamppb.Timestamp
+		to   **time.Time
+	}{
+		{cred.ExpiresAt, &c.ExpiresAt},
+		{cred.LastUsedAt, &c.LastUsedAt},
+		{cred.RevokedAt, &c.RevokedAt},
+	} {
+		if ts.from != nil {
+			t := ts.from.AsTime()
+			*ts.to = &t
+		}
+	}
+
+	return c
+}
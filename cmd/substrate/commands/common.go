@@ -175,6 +175,11 @@ func formatMessage(msg mail.InboxMessage) string {
 			msg.Deadline.Format(time.RFC3339))
 	}
 
+	if len(msg.Labels) > 0 {
+		fmt.Fprintf(&sb, "  Labels: %s\n",
+			strings.Join(msg.Labels, ", "))
+	}
+
 	return sb.String()
 }
 
@@ -193,6 +198,9 @@ func formatMessageFull(msg *mail.InboxMessage) string {
 	fmt.Fprintf(&sb, "Thread: %s\n", msg.ThreadID)
 	fmt.Fprintf(&sb, "Priority: %s\n", msg.Priority)
 	fmt.Fprintf(&sb, "State: %s\n", msg.State)
+	if len(msg.Labels) > 0 {
+		fmt.Fprintf(&sb, "Labels: %s\n", strings.Join(msg.Labels, ", "))
+	}
 	fmt.Fprintf(&sb, "Created: %s\n",
 		msg.CreatedAt.Format(time.RFC3339))
 
@@ -211,6 +219,11 @@ func formatMessageFull(msg *mail.InboxMessage) string {
 			msg.AckedAt.Format(time.RFC3339))
 	}
 
+	if msg.EditedAt != nil {
+		fmt.Fprintf(&sb, "Edited: %s (see 'substrate history %d')\n",
+			msg.EditedAt.Format(time.RFC3339), msg.ID)
+	}
+
 	sb.WriteString(strings.Repeat("-", 60) + "\n")
 	sb.WriteString(msg.Body + "\n")
 
@@ -326,6 +339,10 @@ const (
 
 	// ExitConflict indicates a conflict (e.g., already exists).
 	ExitConflict = 5
+
+	// ExitRateLimited indicates a call was refused by a rate limit or
+	// storage quota.
+	ExitRateLimited = 6
 )
 
 // CLIError wraps an error with a semantic exit code for structured output.
@@ -363,6 +380,8 @@ func NewNotFoundError(msg string, err error) *CLIError {
 // OutputError writes a structured error to stderr when format is JSON,
 // or a plain text error otherwise.
 func OutputError(err error) int {
+	err = limitCLIError(err)
+
 	if outputFormat == "json" {
 		code := ExitError
 		if cliErr, ok := err.(*CLIError); ok {
@@ -470,6 +489,7 @@ func pickFields(
 // paginationEnvelope wraps list results with pagination metadata.
 type paginationEnvelope struct {
 	Items         interface{} `json:"items"`
+	Total         int64       `json:"total,omitempty"`
 	NextPageToken string      `json:"next_page_token,omitempty"`
 }
 
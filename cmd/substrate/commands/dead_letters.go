@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// deadLettersCmd lists the current agent's dead letters.
+var deadLettersCmd = &cobra.Command{
+	Use:   "dead-letters",
+	Short: "List recipients your messages could not be delivered to",
+	Long: `List your dead-letter mailbox.
+
+When a message is sent with --partial, every recipient that could not be
+reached is filed here: names that matched no agent, group or alias
+("unknown"), and recipients refused by a delivery policy ("rejected").
+Entries stay until they are deleted or their message is removed by
+retention.`,
+	Args: cobra.NoArgs,
+	RunE: runDeadLetters,
+}
+
+// deadLettersDeleteCmd deletes a dead letter.
+var deadLettersDeleteCmd = &cobra.Command{
+	Use:   "delete <id>",
+	Short: "Remove an entry from your dead-letter mailbox",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDeadLettersDelete,
+}
+
+// deadLettersLimit bounds the number of dead letters listed.
+var deadLettersLimit int
+
+func init() {
+	deadLettersCmd.Flags().IntVar(
+		&deadLettersLimit, "limit", 50,
+		"Maximum number of dead letters to list",
+	)
+
+	deadLettersCmd.AddCommand(deadLettersDeleteCmd)
+}
+
+// runDeadLetters handles the `substrate dead-letters` command.
+func runDeadLetters(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, agentName, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	letters, err := client.ListDeadLetters(ctx, agentID, deadLettersLimit)
+	if err != nil {
+		return fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(letters)
+	default:
+		if len(letters) == 0 {
+			fmt.Printf("%s has no dead letters.\n", agentName)
+			return nil
+		}
+
+		fmt.Printf("Dead letters for %s (%d):\n\n", agentName,
+			len(letters))
+		for _, l := range letters {
+			fmt.Printf("  [%d] %s (%s)\n", l.ID, l.Recipient,
+				l.Status)
+			fmt.Printf("    Message %d: %s\n", l.MessageID, l.Subject)
+			if l.Reason != "" {
+				fmt.Printf("    Reason: %s\n", l.Reason)
+			}
+			fmt.Printf("    %s\n", l.CreatedAt.Format(
+				"2006-01-02 15:04:05",
+			))
+		}
+	}
+
+	return nil
+}
+
+// runDeadLettersDelete handles the `substrate dead-letters delete` command.
+func runDeadLettersDelete(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid dead letter ID: %w", err)
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if err := client.DeleteDeadLetter(ctx, agentID, id); err != nil {
+		return fmt.Errorf("failed to delete dead letter: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"id":      id,
+			"deleted": true,
+		})
+	default:
+		fmt.Printf("Deleted dead letter %d.\n", id)
+	}
+
+	return nil
+}
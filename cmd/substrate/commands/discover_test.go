@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/roasbeef/subtrate/internal/mail"
 	"github.com/stretchr/testify/require"
 )
 
@@ -103,14 +104,19 @@ func TestComputeAgentStatus(t *testing.T) {
 }
 
 // TestFilterDiscoveredAgents verifies client-side filtering by status,
-// project prefix, and agent name substring. Subtests run sequentially
-// because filterDiscoveredAgents reads package-level flag vars.
+// project prefix, agent name substring and capability. Subtests run
+// sequentially because filterDiscoveredAgents reads package-level flag
+// vars.
 func TestFilterDiscoveredAgents(t *testing.T) {
 	agents := []DiscoveredAgentInfo{
 		{
 			Name:       "AlphaAgent",
 			Status:     "active",
 			ProjectKey: "subtrate",
+			Capabilities: mail.AgentCapabilities{
+				Languages: []string{"go"},
+				Skills:    []string{"go-review"},
+			},
 		},
 		{
 			Name:       "BetaAgent",
@@ -121,6 +127,9 @@ func TestFilterDiscoveredAgents(t *testing.T) {
 			Name:       "GammaAgent",
 			Status:     "idle",
 			ProjectKey: "lnd",
+			Capabilities: mail.AgentCapabilities{
+				Skills: []string{"go-review"},
+			},
 		},
 		{
 			Name:       "DeltaAgent",
@@ -139,7 +148,9 @@ func TestFilterDiscoveredAgents(t *testing.T) {
 		status  string
 		project string
 		agName  string
+		cap     string
 		want    []string
+		wantErr bool
 	}{
 		{
 			name: "no filters returns all",
@@ -212,6 +223,26 @@ func TestFilterDiscoveredAgents(t *testing.T) {
 			status: "active",
 			want:   []string{"AlphaAgent", "UpperAgent"},
 		},
+		{
+			name: "filter by capability of any kind",
+			cap:  "go-review",
+			want: []string{"AlphaAgent", "GammaAgent"},
+		},
+		{
+			name: "filter by capability kind",
+			cap:  "cap:language:go",
+			want: []string{"AlphaAgent"},
+		},
+		{
+			name: "filter by capability under another kind",
+			cap:  "tool:go-review",
+			want: []string{},
+		},
+		{
+			name:    "invalid capability kind",
+			cap:     "team:core",
+			wantErr: true,
+		},
 	}
 
 	for _, tc := range tests {
@@ -221,12 +252,18 @@ func TestFilterDiscoveredAgents(t *testing.T) {
 			discoverStatus = tc.status
 			discoverProject = tc.project
 			discoverName = tc.agName
+			discoverCap = tc.cap
 
 			// Copy input to avoid mutation.
 			input := make([]DiscoveredAgentInfo, len(agents))
 			copy(input, agents)
 
-			got := filterDiscoveredAgents(input)
+			got, err := filterDiscoveredAgents(input)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
 
 			names := make([]string, len(got))
 			for i, a := range got {
@@ -0,0 +1,216 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/spf13/cobra"
+)
+
+// editCmd edits a sent message.
+var editCmd = &cobra.Command{
+	Use:   "edit <message_id>",
+	Short: "Edit a message you sent",
+	Long: `Replace the subject or body of a message you already sent.
+
+The replaced version is kept in the message's revision history, which
+'substrate history' shows, and recipients are notified of the edit.
+Messages under a legal hold can't be edited.
+
+Example:
+  substrate edit 42 --body "Deploy to staging-east first, not staging-west."`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEdit,
+}
+
+// recallCmd recalls a sent message.
+var recallCmd = &cobra.Command{
+	Use:   "recall <message_id>",
+	Short: "Recall a message you sent before anyone reads it",
+	Long: `Delete a message you sent, as long as every recipient still has
+it unread. Once any recipient has read, starred, archived or otherwise
+acted on the message it can no longer be recalled; edit it instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRecall,
+}
+
+// historyCmd shows a message's revision history.
+var historyCmd = &cobra.Command{
+	Use:   "history <message_id>",
+	Short: "Show the revision history of a message",
+	Long: `Show every version of a message, oldest first, ending with its
+current version. A message that was never edited has a single version.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistory,
+}
+
+var (
+	editSubject  string
+	editBody     string
+	editBodyFile string
+)
+
+func init() {
+	editCmd.Flags().StringVar(&editSubject, "subject", "",
+		"New subject (keeps the current one if empty)")
+	editCmd.Flags().StringVar(&editBody, "body", "",
+		"New body in markdown (keeps the current one if empty)")
+	editCmd.Flags().StringVar(&editBodyFile, "body-file", "",
+		"Read the new body from file (overrides --body)")
+}
+
+// parseMessageID parses a message ID argument.
+func parseMessageID(arg string) (int64, error) {
+	msgID, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid message ID: %w", err)
+	}
+
+	return msgID, nil
+}
+
+// runEdit handles the `substrate edit` command.
+func runEdit(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	msgID, err := parseMessageID(args[0])
+	if err != nil {
+		return err
+	}
+
+	body := editBody
+	if editBodyFile != "" {
+		data, err := os.ReadFile(editBodyFile)
+		if err != nil {
+			return fmt.Errorf("failed to read body file: %w", err)
+		}
+		body = strings.TrimSpace(string(data))
+	}
+	if editSubject == "" && body == "" {
+		return fmt.Errorf("--subject, --body or --body-file is " +
+			"required")
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	revision, err := client.EditMessage(ctx, mail.EditMessageRequest{
+		SenderID:  agentID,
+		MessageID: msgID,
+		Subject:   editSubject,
+		Body:      body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to edit message: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"message_id": msgID,
+			"revision":   revision,
+		})
+	default:
+		fmt.Printf("Edited message #%d (revision %d).\n", msgID,
+			revision)
+	}
+
+	return nil
+}
+
+// runRecall handles the `substrate recall` command.
+func runRecall(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	msgID, err := parseMessageID(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if err := client.RecallMessage(ctx, agentID, msgID); err != nil {
+		return fmt.Errorf("failed to recall message: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"message_id": msgID,
+			"recalled":   true,
+		})
+	default:
+		fmt.Printf("Recalled message #%d.\n", msgID)
+	}
+
+	return nil
+}
+
+// runHistory handles the `substrate history` command.
+func runHistory(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	msgID, err := parseMessageID(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	versions, err := client.GetMessageHistory(ctx, agentID, msgID)
+	if err != nil {
+		return fmt.Errorf("failed to get message history: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(versions)
+	default:
+		fmt.Printf("Message #%d has %d version(s):\n", msgID,
+			len(versions))
+		for i, v := range versions {
+			label := fmt.Sprintf("Revision %d", v.Revision)
+			if i == len(versions)-1 {
+				label += " (current)"
+			}
+
+			fmt.Printf("\n%s, %s\n", label,
+				v.CreatedAt.Format(time.RFC3339))
+			fmt.Println(strings.Repeat("-", 60))
+			fmt.Printf("Subject: %s\n\n%s\n", v.Subject, v.Body)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,288 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/spf13/cobra"
+)
+
+// groupsCmd is the parent command for recipient group management.
+var groupsCmd = &cobra.Command{
+	Use:   "groups",
+	Short: "Manage recipient groups",
+	Long: `Manage named recipient groups.
+
+A message addressed to @<group> is delivered to every member of the group.
+Besides stored groups, the following aliases are resolved when a message is
+sent:
+
+  @project:<name>  agents whose project directory is named <name>
+  @branch:<name>   agents working on git branch <name>
+  @online          agents with a recent heartbeat
+  @reviewers       reviewer agents
+
+The sender is never included in an expansion, and the agents each address
+expanded to are recorded on the message.`,
+	RunE: runGroupsList,
+}
+
+// groupsListCmd lists all recipient groups.
+var groupsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recipient groups",
+	Args:  cobra.NoArgs,
+	RunE:  runGroupsList,
+}
+
+// groupsShowCmd shows a single recipient group.
+var groupsShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a recipient group and its members",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runGroupsShow,
+}
+
+// groupsCreateCmd creates a recipient group.
+var groupsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a recipient group",
+	Long: `Create a recipient group. Group names are made of lowercase
+letters, digits, dots, dashes and underscores, and may not shadow a built-in
+alias.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGroupsCreate,
+}
+
+// groupsDeleteCmd deletes a recipient group.
+var groupsDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a recipient group",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runGroupsDelete,
+}
+
+// groupsAddCmd adds members to a recipient group.
+var groupsAddCmd = &cobra.Command{
+	Use:   "add <name> <agent>...",
+	Short: "Add agents to a recipient group",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runGroupsAdd,
+}
+
+// groupsRemoveCmd removes members from a recipient group.
+var groupsRemoveCmd = &cobra.Command{
+	Use:   "remove <name> <agent>...",
+	Short: "Remove agents from a recipient group",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runGroupsRemove,
+}
+
+// Groups command flags.
+var (
+	groupsDescription string
+	groupsMembers     string
+)
+
+func init() {
+	groupsCreateCmd.Flags().StringVar(
+		&groupsDescription, "description", "",
+		"Description of the group",
+	)
+	groupsCreateCmd.Flags().StringVar(
+		&groupsMembers, "members", "",
+		"Comma-separated agent names to add to the group",
+	)
+
+	groupsCmd.AddCommand(groupsListCmd)
+	groupsCmd.AddCommand(groupsShowCmd)
+	groupsCmd.AddCommand(groupsCreateCmd)
+	groupsCmd.AddCommand(groupsDeleteCmd)
+	groupsCmd.AddCommand(groupsAddCmd)
+	groupsCmd.AddCommand(groupsRemoveCmd)
+}
+
+// runGroupsList handles the `substrate groups list` command.
+func runGroupsList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	groups, err := client.ListRecipientGroups(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list groups: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(groups)
+	default:
+		if len(groups) == 0 {
+			fmt.Println("No recipient groups exist.")
+			return nil
+		}
+
+		fmt.Printf("Recipient groups (%d):\n\n", len(groups))
+		for _, g := range groups {
+			fmt.Printf("  @%s (%d members)\n", g.Name,
+				g.MemberCount)
+			if g.Description != "" {
+				fmt.Printf("    %s\n", g.Description)
+			}
+		}
+	}
+
+	return nil
+}
+
+// runGroupsShow handles the `substrate groups show` command.
+func runGroupsShow(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	group, err := client.GetRecipientGroup(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get group: %w", err)
+	}
+
+	return printRecipientGroup(group)
+}
+
+// runGroupsCreate handles the `substrate groups create` command.
+func runGroupsCreate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// The creator is informational only, so a missing identity isn't an
+	// error.
+	createdBy, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		createdBy = 0
+	}
+
+	group, err := client.CreateRecipientGroup(
+		ctx, args[0], groupsDescription, createdBy,
+		splitAgentList(groupsMembers),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create group: %w", err)
+	}
+
+	return printRecipientGroup(group)
+}
+
+// runGroupsDelete handles the `substrate groups delete` command.
+func runGroupsDelete(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.DeleteRecipientGroup(ctx, args[0]); err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+
+	name := strings.TrimPrefix(args[0], mail.RecipientAliasPrefix)
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"name":    name,
+			"deleted": true,
+		})
+	default:
+		fmt.Printf("Deleted group @%s.\n", name)
+	}
+
+	return nil
+}
+
+// runGroupsAdd handles the `substrate groups add` command.
+func runGroupsAdd(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	group, err := client.AddRecipientGroupMembers(ctx, args[0], args[1:])
+	if err != nil {
+		return fmt.Errorf("failed to add members: %w", err)
+	}
+
+	return printRecipientGroup(group)
+}
+
+// runGroupsRemove handles the `substrate groups remove` command.
+func runGroupsRemove(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	group, err := client.RemoveRecipientGroupMembers(ctx, args[0], args[1:])
+	if err != nil {
+		return fmt.Errorf("failed to remove members: %w", err)
+	}
+
+	return printRecipientGroup(group)
+}
+
+// printRecipientGroup prints a recipient group in the selected output format.
+func printRecipientGroup(group mail.RecipientGroup) error {
+	switch outputFormat {
+	case "json":
+		return outputJSON(group)
+	default:
+		fmt.Printf("@%s\n", group.Name)
+		if group.Description != "" {
+			fmt.Printf("  %s\n", group.Description)
+		}
+		if len(group.Members) == 0 {
+			fmt.Println("  No members.")
+			return nil
+		}
+
+		fmt.Printf("  Members (%d): %s\n", len(group.Members),
+			strings.Join(group.Members, ", "))
+	}
+
+	return nil
+}
+
+// splitAgentList splits a comma-separated list of agent names, dropping
+// empty entries.
+func splitAgentList(list string) []string {
+	var names []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
@@ -6,11 +6,16 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/roasbeef/subtrate/internal/mail"
 	"github.com/roasbeef/subtrate/internal/queue"
 	"github.com/spf13/cobra"
 )
 
-var heartbeatSessionStart bool
+var (
+	heartbeatSessionStart   bool
+	heartbeatCaps           []string
+	heartbeatMaxConcurrency int64
+)
 
 var heartbeatCmd = &cobra.Command{
 	Use:   "heartbeat",
@@ -18,13 +23,23 @@ var heartbeatCmd = &cobra.Command{
 	Long: `Send a heartbeat to update the agent's last active timestamp.
 
 This is useful for Claude Code hooks to signal agent presence.
-The heartbeat updates the agent's status to active/busy.`,
+The heartbeat updates the agent's status to active/busy.
+
+With --session-start, --cap and --max-concurrency declare the agent's
+capabilities for cap: routing, replacing any declared before.`,
 	RunE: runHeartbeat,
 }
 
 func init() {
 	heartbeatCmd.Flags().BoolVar(&heartbeatSessionStart, "session-start", false,
 		"Start a new session (marks agent as busy)")
+	heartbeatCmd.Flags().StringSliceVar(&heartbeatCaps, "cap", nil,
+		"Capabilities to declare on session start (kind:name or "+
+			"a skill)")
+	heartbeatCmd.Flags().Int64Var(
+		&heartbeatMaxConcurrency, "max-concurrency", 0,
+		"Most tasks to take on at once when declaring capabilities",
+	)
 
 	rootCmd.AddCommand(heartbeatCmd)
 }
@@ -68,6 +83,23 @@ func runHeartbeat(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to record heartbeat: %w", err)
 	}
 
+	// A session start re-declares the agent's capabilities, so they
+	// follow whatever the hook environment says this session can do.
+	declareCaps := len(heartbeatCaps) > 0 || heartbeatMaxConcurrency > 0
+	if heartbeatSessionStart && declareCaps {
+		caps, err := mail.ParseAgentCapabilities(heartbeatCaps)
+		if err != nil {
+			return err
+		}
+		caps.MaxConcurrency = heartbeatMaxConcurrency
+
+		_, err = client.SetAgentCapabilities(ctx, agentID, caps)
+		if err != nil {
+			return fmt.Errorf("failed to declare capabilities: %w",
+				err)
+		}
+	}
+
 	// If session start is requested, track the session via identity.
 	var sessInfo *SessionInfo
 	if heartbeatSessionStart && sessionID != "" {
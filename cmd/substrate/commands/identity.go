@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/roasbeef/subtrate/internal/agent"
+	"github.com/roasbeef/subtrate/internal/auth"
 	"github.com/spf13/cobra"
 )
 
@@ -60,6 +62,9 @@ var identitySetDefaultCmd = &cobra.Command{
 
 var setDefaultAgentName string
 
+// ensureMint mints a bearer token for the ensured identity.
+var ensureMint bool
+
 func init() {
 	identityCmd.AddCommand(identityEnsureCmd)
 	identityCmd.AddCommand(identityRestoreCmd)
@@ -68,6 +73,9 @@ func init() {
 	identityCmd.AddCommand(identityListCmd)
 	identityCmd.AddCommand(identitySetDefaultCmd)
 
+	identityEnsureCmd.Flags().BoolVar(&ensureMint, "mint-token", false,
+		"Also mint a bearer token for the agent")
+
 	identitySetDefaultCmd.Flags().StringVar(&setDefaultAgentName, "agent", "",
 		"Agent name to set as default (required)")
 	identitySetDefaultCmd.MarkFlagRequired("agent")
@@ -103,8 +111,25 @@ func runIdentityEnsure(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to ensure identity: %w", err)
 	}
 
+	var token string
+	if ensureMint {
+		token, _, err = client.MintCredential(ctx, auth.MintRequest{
+			AgentID: identity.AgentID,
+			Name:    "session " + sid,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to mint token: %w", err)
+		}
+	}
+
 	switch outputFormat {
 	case "json":
+		if token != "" {
+			return outputJSON(struct {
+				*agent.IdentityFile
+				Token string `json:"token"`
+			}{identity, token})
+		}
 		return outputJSON(identity)
 	case "context":
 		fmt.Printf("[Subtrate] Agent: %s\n", identity.AgentName)
@@ -116,6 +141,9 @@ func runIdentityEnsure(cmd *cobra.Command, args []string) error {
 		if identity.ProjectKey != "" {
 			fmt.Printf("  Project: %s\n", identity.ProjectKey)
 		}
+		if token != "" {
+			fmt.Printf("  Token: %s\n", token)
+		}
 	}
 
 	return nil
@@ -10,15 +10,21 @@ import (
 )
 
 var (
-	inboxLimit int
-	inboxAll   bool
+	inboxLimit   int
+	inboxAll     bool
+	inboxOverdue bool
+	inboxLabel   string
 )
 
 var inboxCmd = &cobra.Command{
 	Use:   "inbox",
 	Short: "View your inbox",
-	Long:  `Display messages in your inbox. By default shows only unread messages.`,
-	RunE:  runInbox,
+	Long: `Display messages in your inbox. By default shows only unread messages.
+
+Use --overdue to list unacknowledged messages whose deadline has passed,
+most overdue first, and --label to list the messages filed under a label,
+archived ones included.`,
+	RunE: runInbox,
 }
 
 func init() {
@@ -26,6 +32,10 @@ func init() {
 		"Maximum number of messages to display")
 	inboxCmd.Flags().BoolVarP(&inboxAll, "all", "a", false,
 		"Show all messages (default: only unread)")
+	inboxCmd.Flags().BoolVar(&inboxOverdue, "overdue", false,
+		"Show only unacknowledged messages past their deadline")
+	inboxCmd.Flags().StringVar(&inboxLabel, "label", "",
+		"Show only messages filed under this label")
 }
 
 func runInbox(cmd *cobra.Command, args []string) error {
@@ -59,10 +69,12 @@ func runInbox(cmd *cobra.Command, args []string) error {
 	}
 
 	req := mail.FetchInboxRequest{
-		AgentID:    agentID,
-		Limit:      inboxLimit,
-		Offset:     offset,
-		UnreadOnly: !inboxAll,
+		AgentID:     agentID,
+		Limit:       inboxLimit,
+		Offset:      offset,
+		UnreadOnly:  !inboxAll && !inboxOverdue && inboxLabel == "",
+		OverdueOnly: inboxOverdue,
+		Label:       inboxLabel,
 	}
 
 	messages, err := client.FetchInbox(ctx, req)
@@ -87,6 +99,15 @@ func runInbox(cmd *cobra.Command, args []string) error {
 		return nil
 
 	default:
+		if len(messages) == 0 && inboxOverdue {
+			fmt.Printf("No overdue messages for %s.\n", agentNameStr)
+			return nil
+		}
+		if len(messages) == 0 && inboxLabel != "" {
+			fmt.Printf("No messages labeled %q for %s.\n",
+				inboxLabel, agentNameStr)
+			return nil
+		}
 		if len(messages) == 0 {
 			fmt.Printf("Inbox for %s is empty.\n", agentNameStr)
 			return nil
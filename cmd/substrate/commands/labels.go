@@ -0,0 +1,233 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/spf13/cobra"
+)
+
+// labelsCmd is the parent command for message labels.
+var labelsCmd = &cobra.Command{
+	Use:   "labels",
+	Short: "Manage labels you file messages under",
+	Long: `Manage labels, your own inbox categories on top of primary and
+notifications.
+
+A message can carry several labels, and labels are yours alone: other
+recipients of the same message don't see them. Label messages by hand with
+'substrate labels add', or as they arrive with 'substrate rules add
+--label'. Use 'substrate inbox --label' and 'substrate search --label' to
+find labeled messages. Slashes nest labels, as in "projects/api".`,
+}
+
+// labelsListCmd lists the current agent's labels.
+var labelsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your labels with their message counts",
+	Args:  cobra.NoArgs,
+	RunE:  runLabelsList,
+}
+
+// labelsCreateCmd creates a label.
+var labelsCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a label",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runLabelsCreate,
+}
+
+// labelsDeleteCmd deletes a label.
+var labelsDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a label and remove it from its messages",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runLabelsDelete,
+}
+
+// labelsAddCmd applies labels to a message.
+var labelsAddCmd = &cobra.Command{
+	Use:   "add <message-id> <label>...",
+	Short: "Label a message, creating labels as needed",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLabelMessage(args, true)
+	},
+}
+
+// labelsRemoveCmd removes labels from a message.
+var labelsRemoveCmd = &cobra.Command{
+	Use:   "remove <message-id> <label>...",
+	Short: "Remove labels from a message",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLabelMessage(args, false)
+	},
+}
+
+func init() {
+	labelsCmd.AddCommand(labelsListCmd)
+	labelsCmd.AddCommand(labelsCreateCmd)
+	labelsCmd.AddCommand(labelsDeleteCmd)
+	labelsCmd.AddCommand(labelsAddCmd)
+	labelsCmd.AddCommand(labelsRemoveCmd)
+}
+
+// runLabelsList handles the `substrate labels list` command.
+func runLabelsList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, agentName, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	labels, err := client.ListLabels(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to list labels: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(labels)
+	default:
+		if len(labels) == 0 {
+			fmt.Printf("%s has no labels.\n", agentName)
+			return nil
+		}
+
+		fmt.Printf("Labels for %s (%d):\n\n", agentName, len(labels))
+		for _, label := range labels {
+			fmt.Printf("  %-24s %d messages, %d unread\n",
+				label.Name, label.MessageCount,
+				label.UnreadCount)
+		}
+	}
+
+	return nil
+}
+
+// runLabelsCreate handles the `substrate labels create` command.
+func runLabelsCreate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	label, err := client.CreateLabel(ctx, agentID, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to create label: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(label)
+	default:
+		fmt.Printf("Created label %s.\n", label.Name)
+	}
+
+	return nil
+}
+
+// runLabelsDelete handles the `substrate labels delete` command.
+func runLabelsDelete(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if err := client.DeleteLabel(ctx, agentID, args[0]); err != nil {
+		return fmt.Errorf("failed to delete label: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"name":    args[0],
+			"deleted": true,
+		})
+	default:
+		fmt.Printf("Deleted label %s.\n", args[0])
+	}
+
+	return nil
+}
+
+// runLabelMessage handles the `substrate labels add` and
+// `substrate labels remove` commands.
+func runLabelMessage(args []string, add bool) error {
+	ctx := context.Background()
+
+	msgID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid message ID: %w", err)
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	req := mail.LabelMessageRequest{
+		AgentID:   agentID,
+		MessageID: msgID,
+	}
+	if add {
+		req.Add = args[1:]
+	} else {
+		req.Remove = args[1:]
+	}
+
+	labels, err := client.LabelMessage(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to label message: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"message_id": msgID,
+			"labels":     labels,
+		})
+	default:
+		if len(labels) == 0 {
+			fmt.Printf("Message #%d has no labels.\n", msgID)
+			return nil
+		}
+		fmt.Printf("Message #%d labels: %s\n", msgID,
+			strings.Join(labels, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,353 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/ratelimit"
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// limitsCmd is the parent command for rate limit and quota management.
+var limitsCmd = &cobra.Command{
+	Use:   "limits",
+	Short: "Set, list and clear rate limits and quotas",
+	Long: `Manage the rate limits and storage quotas agents are held to.
+
+Rate limits are token buckets per agent and class of RPC:
+
+  send        SendMail, ReplyToThread and AskQuestion
+  publish     Publish
+  search      Search, SearchAll, SemanticSearch and SimilarMessages
+  review      CreateReview and CreatePlanReview
+
+The body_bytes quota caps the bytes of message body an agent may send each
+UTC day. A limit set without --agent is the default for every agent, and an
+agent's own limit overrides it. A refused call fails with ResourceExhausted
+(HTTP 429) and says when to retry; the CLI exits with code 6.`,
+}
+
+var limitsListCmd = &cobra.Command{
+	Use:   "list [agent]",
+	Short: "List limits",
+	Long: `List the default limits followed by the agents' own, or only the
+limits that apply to one agent.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLimitsList,
+}
+
+var limitsSetCmd = &cobra.Command{
+	Use:   "set <class> <rate|size>",
+	Short: "Set a limit",
+	Long: `Set a rate limit as amount/interval, or the body_bytes quota as a
+size per day, for one agent or for everyone.
+
+  substrate limits set send 30/1m
+  substrate limits set search 5/s --agent AliceAgent --burst 20
+  substrate limits set body_bytes 10MB --agent AliceAgent
+
+Intervals are durations such as 1m or 90s, or days such as 1d. Sizes take
+KB, MB and GB (powers of 1000) or KiB, MiB and GiB (powers of 1024).`,
+	Args: cobra.ExactArgs(2),
+	RunE: runLimitsSet,
+}
+
+var limitsClearCmd = &cobra.Command{
+	Use:   "clear <class>",
+	Short: "Clear a limit",
+	Long: `Remove an agent's limit for a class, so the default applies
+again, or the default itself without --agent.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLimitsClear,
+}
+
+var (
+	limitAgent string
+	limitBurst int64
+)
+
+func init() {
+	limitsCmd.AddCommand(limitsListCmd)
+	limitsCmd.AddCommand(limitsSetCmd)
+	limitsCmd.AddCommand(limitsClearCmd)
+
+	for _, cmd := range []*cobra.Command{limitsSetCmd, limitsClearCmd} {
+		cmd.Flags().StringVar(&limitAgent, "agent", "",
+			"Agent the limit is for (default: every agent)")
+	}
+	limitsSetCmd.Flags().Int64Var(&limitBurst, "burst", 0,
+		"Calls allowed at once (default: the rate's amount)")
+}
+
+func runLimitsList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var agentID int64
+	if len(args) > 0 {
+		agentID, _, err = resolveAgentArg(ctx, client, args)
+		if err != nil {
+			return err
+		}
+	}
+
+	limits, err := client.ListLimits(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to list limits: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(limits)
+	default:
+		if len(limits) == 0 {
+			fmt.Println("No limits.")
+			return nil
+		}
+
+		for _, limit := range limits {
+			fmt.Println(formatLimit(limit))
+		}
+	}
+
+	return nil
+}
+
+func runLimitsSet(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	class := ratelimit.Class(args[0])
+	if err := ratelimit.ValidateClass(class); err != nil {
+		return NewValidationError(err.Error(), err)
+	}
+	if limitBurst < 0 {
+		return NewValidationError("--burst must not be negative", nil)
+	}
+
+	var (
+		amount   int64
+		interval time.Duration
+		err      error
+	)
+	if class == ratelimit.ClassBodyBytes {
+		amount, err = ratelimit.ParseBytes(args[1])
+	} else {
+		amount, interval, err = ratelimit.ParseRate(args[1])
+	}
+	if err != nil {
+		return NewValidationError(err.Error(), err)
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, err := resolveLimitAgent(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	limit, err := client.SetLimit(
+		ctx, agentID, class, amount, interval, limitBurst,
+	)
+	if errors.Is(err, ratelimit.ErrInvalidLimit) ||
+		status.Code(err) == codes.InvalidArgument {
+
+		return NewValidationError(err.Error(), err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set limit: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(limit)
+	default:
+		fmt.Printf("Set %s\n", formatLimit(limit))
+	}
+
+	return nil
+}
+
+func runLimitsClear(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	class := ratelimit.Class(args[0])
+	if err := ratelimit.ValidateClass(class); err != nil {
+		return NewValidationError(err.Error(), err)
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, err := resolveLimitAgent(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	cleared, err := client.ClearLimit(ctx, agentID, class)
+	if err != nil {
+		return fmt.Errorf("failed to clear limit: %w", err)
+	}
+	if !cleared {
+		return NewNotFoundError(
+			fmt.Sprintf("no %s limit set for %s", class,
+				limitOwner(limitAgent)), nil,
+		)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"agent":   limitAgent,
+			"class":   class,
+			"cleared": true,
+		})
+	default:
+		fmt.Printf("Cleared the %s limit for %s\n", class,
+			limitOwner(limitAgent))
+	}
+
+	return nil
+}
+
+// resolveLimitAgent resolves --agent, or returns zero for the default.
+func resolveLimitAgent(ctx context.Context, client *Client) (int64, error) {
+	if limitAgent == "" {
+		return 0, nil
+	}
+
+	agentID, _, err := resolveAgentArg(ctx, client, []string{limitAgent})
+	return agentID, err
+}
+
+// limitOwner describes who a limit applies to.
+func limitOwner(agentName string) string {
+	if agentName == "" {
+		return "every agent"
+	}
+
+	return agentName
+}
+
+// formatInterval formats an interval in the largest unit that divides it,
+// as ParseRate accepts it back.
+func formatInterval(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+
+	return fmt.Sprintf("%ds", d/time.Second)
+}
+
+// formatLimitValue formats what a limit allows.
+func formatLimitValue(limit store.AgentLimit) string {
+	if ratelimit.Class(limit.Class) == ratelimit.ClassBodyBytes {
+		return fmt.Sprintf("%d bytes/day", limit.Amount)
+	}
+
+	return fmt.Sprintf("%d/%s burst %d", limit.Amount,
+		formatInterval(limit.Interval), limit.Burst)
+}
+
+// formatLimit formats a limit as one line of text.
+func formatLimit(limit store.AgentLimit) string {
+	owner := "default"
+	if limit.AgentID != 0 {
+		owner = limit.AgentName
+	}
+
+	return fmt.Sprintf("%-16s  %-10s  %s", owner, limit.Class,
+		formatLimitValue(limit))
+}
+
+// limitedUsage returns the usage of the classes that are limited or were
+// throttled.
+func limitedUsage(usage []ratelimit.Usage) []ratelimit.Usage {
+	var limited []ratelimit.Usage
+	for _, u := range usage {
+		if u.Limit != nil || u.Throttled > 0 {
+			limited = append(limited, u)
+		}
+	}
+
+	return limited
+}
+
+// formatUsage formats an agent's standing against its limits, leaving out
+// classes that are unlimited and were never throttled.
+func formatUsage(usage []ratelimit.Usage) string {
+	var sb strings.Builder
+	for _, u := range limitedUsage(usage) {
+		fmt.Fprintf(&sb, "  %-10s  ", u.Class)
+		switch {
+		case u.Limit == nil:
+			sb.WriteString("unlimited")
+
+		case u.Class == ratelimit.ClassBodyBytes:
+			fmt.Fprintf(&sb, "%d of %d bytes used today",
+				u.UsedBytes, u.Limit.Amount)
+
+		default:
+			fmt.Fprintf(&sb, "%d of %d left (%s)", int64(u.Tokens),
+				u.Limit.Burst, formatLimitValue(*u.Limit))
+		}
+		if u.Throttled > 0 {
+			fmt.Fprintf(&sb, ", throttled %d times", u.Throttled)
+		}
+		if u.LastThrottledAt != nil {
+			fmt.Fprintf(&sb, ", last %s",
+				u.LastThrottledAt.Local().Format(time.DateTime))
+		}
+		sb.WriteString("\n")
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+
+	return "Limits:\n" + sb.String()
+}
+
+// limitCLIError returns a CLIError with ExitRateLimited if err refused a
+// call over a rate limit or quota, and err unchanged otherwise.
+func limitCLIError(err error) error {
+	if _, ok := err.(*CLIError); ok {
+		return err
+	}
+
+	if errors.Is(err, ratelimit.ErrRateLimited) ||
+		errors.Is(err, ratelimit.ErrQuotaExceeded) ||
+		status.Code(err) == codes.ResourceExhausted {
+
+		return &CLIError{
+			Code:    ExitRateLimited,
+			Message: err.Error(),
+			Err:     err,
+		}
+	}
+
+	return err
+}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,10 +13,7 @@ import (
 
 	sdkmcp "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
-	subtraterpc "github.com/roasbeef/subtrate/internal/api/grpc"
 	"github.com/roasbeef/subtrate/internal/mcp"
 )
 
@@ -86,34 +84,19 @@ func runMCPServe(cmd *cobra.Command, args []string) error {
 	// Determine gRPC address.
 	addr := resolveGRPCAddr()
 
-	// Connect to the daemon via gRPC.
-	conn, err := grpc.NewClient(
-		addr,
-		grpc.WithTransportCredentials(
-			insecure.NewCredentials(),
-		),
-	)
+	// Connect to the daemon via gRPC, detecting one that serves TLS the
+	// same way the other commands do.
+	conn, err := dialDaemon(addr)
+	var cliErr *CLIError
+	if errors.As(err, &cliErr) {
+		return err
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create gRPC client: %w", err)
+		return fmt.Errorf("no daemon at %s: %w (start with: make run)",
+			addr, err)
 	}
 	defer conn.Close()
 
-	// Verify connectivity with a quick health check.
-	verifyCtx, verifyCancel := context.WithTimeout(
-		context.Background(), grpcConnectTimeout,
-	)
-	defer verifyCancel()
-
-	agentClient := subtraterpc.NewAgentClient(conn)
-	if _, err := agentClient.ListAgents(
-		verifyCtx, &subtraterpc.ListAgentsRequest{},
-	); err != nil {
-		return fmt.Errorf(
-			"daemon not responding at %s: %w "+
-				"(start with: make run)", addr, err,
-		)
-	}
-
 	// Create MCP server with gRPC backend.
 	backend := mcp.NewGRPCBackend(conn)
 	mcpServer := mcp.NewServerWithBackend(backend)
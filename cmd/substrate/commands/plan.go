@@ -15,6 +15,7 @@ import (
 	claudeagent "github.com/roasbeef/claude-agent-sdk-go"
 	subtraterpc "github.com/roasbeef/subtrate/internal/api/grpc"
 	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/ratelimit"
 	"github.com/roasbeef/subtrate/internal/store"
 	"github.com/spf13/cobra"
 )
@@ -1021,6 +1022,13 @@ func (c *Client) CreatePlanReview(
 		s := store.FromDB(c.store.DB())
 		defer s.Close()
 
+		err := ratelimit.NewLimiter(s).Allow(
+			ctx, params.RequesterID, ratelimit.ClassReview,
+		)
+		if err != nil {
+			return store.PlanReview{}, err
+		}
+
 		return s.CreatePlanReview(ctx, params)
 	}
 
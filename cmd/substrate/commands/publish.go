@@ -20,9 +20,12 @@ var (
 var publishCmd = &cobra.Command{
 	Use:   "publish <topic>",
 	Short: "Publish a message to a topic",
-	Long:  `Publish a message to all subscribers of a topic.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runPublish,
+	Long: `Publish a message to all subscribers of a topic.
+
+Messages published to a queue topic are not fanned out. Each one waits to be
+claimed by exactly one subscriber with 'substrate work claim'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPublish,
 }
 
 func init() {
@@ -68,7 +71,7 @@ func runPublish(cmd *cobra.Command, args []string) error {
 		)
 	}
 
-	msgID, recipientsCount, err := client.Publish(
+	msgID, recipientsCount, queued, err := client.Publish(
 		ctx, agentID, topicName, publishSubject, publishBody, priority,
 	)
 	if err != nil {
@@ -81,8 +84,14 @@ func runPublish(cmd *cobra.Command, args []string) error {
 			"message_id":       msgID,
 			"topic":            topicName,
 			"recipients_count": recipientsCount,
+			"queue":            queued,
 		})
 	default:
+		if queued {
+			fmt.Printf("Published to queue %s! Message ID: %d, "+
+				"waiting to be claimed\n", topicName, msgID)
+			return nil
+		}
 		fmt.Printf("Published to %s! Message ID: %d, Recipients: %d\n",
 			topicName, msgID, recipientsCount)
 	}
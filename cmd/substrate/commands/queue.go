@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -137,10 +138,16 @@ func runQueueDrain(cmd *cobra.Command, args []string) error {
 		)
 	}
 
-	// Get a connected client (not queued).
+	// Get a connected client (not queued). As with any other command, a
+	// daemon that refused our credential or TLS is reported rather than
+	// gone around.
 	addr := resolveGRPCAddr()
 
 	client, err := tryGRPCConnection(addr)
+	var cliErr *CLIError
+	if errors.As(err, &cliErr) {
+		return err
+	}
 	if err != nil {
 		client, err = getDirectClient()
 		if err != nil {
@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/queue"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replyBody       string
+	replyBodyFile   string
+	replySenderOnly bool
+	replyQuote      bool
+	replyPriority   string
+)
+
+// replyCmd replies to a message.
+var replyCmd = &cobra.Command{
+	Use:   "reply <message_id>",
+	Short: "Reply to a message",
+	Long: `Reply to a message in its thread.
+
+The reply goes to the message's sender and everyone else it was sent to,
+leaving you out. Use --sender-only to reply to the sender alone. Replies to
+your own messages go to their recipients. The subject is the message's,
+prefixed with "Re: ", and --quote appends the message to the reply as a
+quoted block.
+
+The body can be given with --body or read from a file with --body-file,
+which takes precedence. When neither the daemon nor the database is
+reachable, the reply is queued and sent once connectivity returns.
+
+Example:
+  substrate reply 42 --body "Staging looks good, shipping now." --quote`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReply,
+}
+
+func init() {
+	replyCmd.Flags().StringVar(&replyBody, "body", "",
+		"Reply body in markdown")
+	replyCmd.Flags().StringVar(&replyBodyFile, "body-file", "",
+		"Read reply body from file (overrides --body)")
+	replyCmd.Flags().BoolVar(&replySenderOnly, "sender-only", false,
+		"Reply to the message's sender only")
+	replyCmd.Flags().BoolVar(&replyQuote, "quote", false,
+		"Quote the message in the reply")
+	replyCmd.Flags().StringVar(&replyPriority, "priority", "normal",
+		"Priority: urgent, normal, low")
+}
+
+// runReply handles the `substrate reply` command.
+func runReply(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	msgID, err := parseMessageID(args[0])
+	if err != nil {
+		return err
+	}
+
+	body := replyBody
+	if replyBodyFile != "" {
+		data, err := os.ReadFile(replyBodyFile)
+		if err != nil {
+			return fmt.Errorf("failed to read body file: %w", err)
+		}
+		body = strings.TrimSpace(string(data))
+	}
+	if body == "" {
+		return fmt.Errorf("--body or --body-file is required")
+	}
+
+	if err := validateEnum(
+		replyPriority, "priority",
+		[]string{"urgent", "normal", "low"},
+	); err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, agentNameStr, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	// In queue mode, the message can't be read, so only the reply itself
+	// is queued and its recipients are resolved on delivery.
+	if client.Mode() == ModeQueued {
+		return enqueueReply(ctx, client, agentNameStr, msgID, body)
+	}
+
+	result, err := client.Reply(ctx, mail.ReplyRequest{
+		SenderID:   agentID,
+		MessageID:  msgID,
+		Body:       body,
+		SenderOnly: replySenderOnly,
+		Quote:      replyQuote,
+		Priority:   mail.Priority(replyPriority),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reply: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"message_id":      result.MessageID,
+			"thread_id":       result.ThreadID,
+			"recipient_names": result.RecipientNames,
+		})
+	default:
+		fmt.Printf("Reply sent! ID: %d, Thread: %s\n",
+			result.MessageID, result.ThreadID)
+		fmt.Printf("  To: %s\n", strings.Join(
+			result.RecipientNames, ", ",
+		))
+	}
+
+	return nil
+}
+
+// enqueueReply stores a reply in the local queue for later delivery.
+func enqueueReply(ctx context.Context, client *Client, senderName string,
+	messageID int64, body string,
+) error {
+	key := newIdempotencyKey()
+	payload := queue.ReplyPayload{
+		SenderName: senderName,
+		MessageID:  messageID,
+		Body:       body,
+		Priority:   replyPriority,
+		SenderOnly: replySenderOnly,
+		Quote:      replyQuote,
+	}
+
+	payloadJSON, err := queue.MarshalPayload(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	now := time.Now()
+	op := queue.PendingOperation{
+		IdempotencyKey: key,
+		OperationType:  queue.OpReply,
+		PayloadJSON:    payloadJSON,
+		AgentName:      senderName,
+		SessionID:      sessionID,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(client.queueCfg.DefaultTTL),
+	}
+
+	if err := client.queueStore.Enqueue(ctx, op); err != nil {
+		return fmt.Errorf("enqueue reply: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"queued":          true,
+			"idempotency_key": key,
+		})
+	default:
+		fmt.Printf("Reply to message #%d queued (offline)\n",
+			messageID)
+	}
+
+	return nil
+}
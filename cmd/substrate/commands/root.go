@@ -84,7 +84,35 @@ func init() {
 	)
 	rootCmd.PersistentFlags().StringVar(
 		&grpcAddr, "grpc-addr", "",
-		"Address of substrated daemon (default: localhost:10009)",
+		"Address of substrated daemon, or unix:///path for a unix "+
+			"socket (default: ~/.subtrate/substrated.sock if "+
+			"served, else localhost:10009)",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&tlsCertPath, "tls-cert", "",
+		"Daemon TLS certificate to pin (default: $SUBSTRATE_TLS_CERT, "+
+			"then ~/.subtrate/tls/tls.cert with --tls)",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&tlsFingerprint, "tls-fingerprint", "",
+		"SHA-256 fingerprint of the daemon TLS certificate to pin "+
+			"(default: $SUBSTRATE_TLS_FINGERPRINT)",
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&useTLS, "tls", false,
+		"Connect to the daemon over TLS, pinning "+
+			"~/.subtrate/tls/tls.cert unless another certificate "+
+			"is given (default: $SUBSTRATE_TLS)",
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&noTLS, "no-tls", false,
+		"Connect to the daemon over TCP without TLS even if a "+
+			"certificate is given",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&authToken, "token", "",
+		"Bearer token for the daemon (default: $SUBSTRATE_TOKEN, then "+
+			"~/.subtrate/admin.token)",
 	)
 	rootCmd.PersistentFlags().StringVar(
 		&agentName, "agent", "",
@@ -134,7 +162,11 @@ func init() {
 	// Add subcommands.
 	rootCmd.AddCommand(inboxCmd)
 	rootCmd.AddCommand(sendCmd)
+	rootCmd.AddCommand(replyCmd)
 	rootCmd.AddCommand(readCmd)
+	rootCmd.AddCommand(editCmd)
+	rootCmd.AddCommand(recallCmd)
+	rootCmd.AddCommand(historyCmd)
 	rootCmd.AddCommand(ackCmd)
 	rootCmd.AddCommand(starCmd)
 	rootCmd.AddCommand(snoozeCmd)
@@ -153,6 +185,20 @@ func init() {
 	rootCmd.AddCommand(reviewCmd)
 	rootCmd.AddCommand(sendDiffCmd)
 	rootCmd.AddCommand(queueCmd)
+	rootCmd.AddCommand(workCmd)
+	rootCmd.AddCommand(adminCmd)
+	rootCmd.AddCommand(authCmd)
+	rootCmd.AddCommand(limitsCmd)
+	rootCmd.AddCommand(groupsCmd)
+	rootCmd.AddCommand(deadLettersCmd)
+	rootCmd.AddCommand(scheduledCmd)
+	rootCmd.AddCommand(scheduleCmd)
+	rootCmd.AddCommand(rulesCmd)
+	rootCmd.AddCommand(labelsCmd)
+	rootCmd.AddCommand(searchesCmd)
+	rootCmd.AddCommand(threadCmd)
+	rootCmd.AddCommand(askCmd)
+	rootCmd.AddCommand(answerCmd)
 	rootCmd.AddCommand(tasksCmd)
 	rootCmd.AddCommand(planCmd)
 	rootCmd.AddCommand(versionCmd)
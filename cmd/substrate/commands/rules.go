@@ -0,0 +1,458 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// rulesCmd is the parent command for inbox rules.
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Manage rules that file incoming messages",
+	Long: `Manage inbox rules, which file messages as they are delivered to
+you.
+
+A rule has conditions and actions. A message matches when it meets every
+condition the rule sets: sender name or name prefix, a subject regular
+expression, the topic it was sent to, its priority, or a metadata key and
+optionally its value. Matching messages can be archived, starred or
+snoozed, filed under the primary or notifications category, labeled, and
+forwarded to another agent, whose own rules then apply to it.
+
+Rules run in the order they were added, and every matching rule applies
+unless one with --stop matches first. Use 'substrate rules test' to see
+what a rule would match among your recent messages before adding it.`,
+}
+
+// rulesAddCmd creates an inbox rule.
+var rulesAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add an inbox rule",
+	Long: `Add an inbox rule after your existing rules.
+
+--snooze takes a duration such as "2h", or a time of day such as "09:00"
+for its next occurrence. --notification is shorthand for
+--category notifications.
+
+Example:
+  substrate rules add reviewers --sender-prefix reviewer- \
+    --subject '^\[Status\]' --archive --stop`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRulesAdd,
+}
+
+// rulesListCmd lists the current agent's inbox rules.
+var rulesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your inbox rules in the order they run",
+	Args:  cobra.NoArgs,
+	RunE:  runRulesList,
+}
+
+// rulesDeleteCmd deletes an inbox rule.
+var rulesDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete an inbox rule",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRulesDelete,
+}
+
+// rulesEnableCmd enables an inbox rule.
+var rulesEnableCmd = &cobra.Command{
+	Use:   "enable <name>",
+	Short: "Enable a disabled inbox rule",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRulesEnable(args[0], true)
+	},
+}
+
+// rulesDisableCmd disables an inbox rule.
+var rulesDisableCmd = &cobra.Command{
+	Use:   "disable <name>",
+	Short: "Stop an inbox rule from running without deleting it",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRulesEnable(args[0], false)
+	},
+}
+
+// rulesTestCmd dry-runs an inbox rule.
+var rulesTestCmd = &cobra.Command{
+	Use:   "test [name]",
+	Short: "Show which recent messages a rule would match",
+	Long: `Try a rule against the messages you received most recently,
+without changing them. Give the name of a saved rule, or the conditions to
+try as flags.
+
+Example:
+  substrate rules test --sender-prefix reviewer- --limit 100`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRulesTest,
+}
+
+var (
+	ruleSenderName     string
+	ruleSenderPrefix   string
+	ruleSubject        string
+	ruleTopic          string
+	rulePriority       string
+	ruleMetaKey        string
+	ruleMetaValue      string
+	ruleArchive        bool
+	ruleStar           bool
+	ruleSnooze         string
+	ruleCategory       string
+	ruleNotification   bool
+	ruleForwardTo      string
+	ruleLabel          string
+	ruleStopProcessing bool
+	ruleTestLimit      int
+)
+
+// addRuleConditionFlags registers the flags for an inbox rule's conditions.
+func addRuleConditionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&ruleSenderName, "sender", "",
+		"Match messages from this agent")
+	cmd.Flags().StringVar(&ruleSenderPrefix, "sender-prefix", "",
+		"Match senders whose name starts with this prefix")
+	cmd.Flags().StringVar(&ruleSubject, "subject", "",
+		"Match subjects against this regular expression")
+	cmd.Flags().StringVar(&ruleTopic, "topic", "",
+		"Match messages sent to this topic")
+	cmd.Flags().StringVar(&rulePriority, "priority", "",
+		"Match this priority: urgent, normal, low")
+	cmd.Flags().StringVar(&ruleMetaKey, "meta-key", "",
+		"Match messages with this metadata key")
+	cmd.Flags().StringVar(&ruleMetaValue, "meta-value", "",
+		"Match this value of --meta-key (default: any)")
+}
+
+func init() {
+	addRuleConditionFlags(rulesAddCmd)
+	rulesAddCmd.Flags().BoolVar(&ruleArchive, "archive", false,
+		"Archive matching messages")
+	rulesAddCmd.Flags().BoolVar(&ruleStar, "star", false,
+		"Star matching messages")
+	rulesAddCmd.Flags().StringVar(&ruleSnooze, "snooze", "",
+		"Snooze matching messages (e.g., '2h', '09:00')")
+	rulesAddCmd.Flags().StringVar(&ruleCategory, "category", "",
+		"File matching messages under: primary, notifications")
+	rulesAddCmd.Flags().BoolVar(&ruleNotification, "notification", false,
+		"File matching messages as notifications")
+	rulesAddCmd.Flags().StringVar(&ruleForwardTo, "forward-to", "",
+		"Also deliver matching messages to this agent")
+	rulesAddCmd.Flags().StringVar(&ruleLabel, "label", "",
+		"Label matching messages, creating the label if needed")
+	rulesAddCmd.Flags().BoolVar(&ruleStopProcessing, "stop", false,
+		"Skip later rules when this rule matches")
+	rulesAddCmd.MarkFlagsMutuallyExclusive("archive", "star", "snooze")
+	rulesAddCmd.MarkFlagsMutuallyExclusive("category", "notification")
+
+	addRuleConditionFlags(rulesTestCmd)
+	rulesTestCmd.Flags().IntVar(&ruleTestLimit, "limit",
+		mail.DefaultRuleTestLimit, "Number of recent messages to try")
+
+	rulesCmd.AddCommand(rulesAddCmd)
+	rulesCmd.AddCommand(rulesListCmd)
+	rulesCmd.AddCommand(rulesDeleteCmd)
+	rulesCmd.AddCommand(rulesEnableCmd)
+	rulesCmd.AddCommand(rulesDisableCmd)
+	rulesCmd.AddCommand(rulesTestCmd)
+}
+
+// ruleConditionsFromFlags builds inbox rule conditions from the flags.
+func ruleConditionsFromFlags() store.InboxRuleConditions {
+	return store.InboxRuleConditions{
+		SenderName:     ruleSenderName,
+		SenderPrefix:   ruleSenderPrefix,
+		SubjectPattern: ruleSubject,
+		TopicName:      ruleTopic,
+		Priority:       rulePriority,
+		MetadataKey:    ruleMetaKey,
+		MetadataValue:  ruleMetaValue,
+	}
+}
+
+// runRulesAdd handles the `substrate rules add` command.
+func runRulesAdd(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	category := store.InboxCategory(ruleCategory)
+	if ruleNotification {
+		category = store.InboxCategoryNotifications
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	rule, err := client.CreateInboxRule(ctx, mail.CreateInboxRuleRequest{
+		AgentID:        agentID,
+		Name:           args[0],
+		StopProcessing: ruleStopProcessing,
+		Conditions:     ruleConditionsFromFlags(),
+		Actions: store.InboxRuleActions{
+			Archive:   ruleArchive,
+			Star:      ruleStar,
+			Snooze:    ruleSnooze,
+			Category:  category,
+			ForwardTo: ruleForwardTo,
+			Label:     ruleLabel,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add rule: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(rule)
+	default:
+		fmt.Printf("Added rule %s: %s -> %s\n", rule.Name,
+			describeRuleConditions(rule.InboxRuleConditions),
+			describeRuleActions(rule))
+	}
+
+	return nil
+}
+
+// runRulesList handles the `substrate rules list` command.
+func runRulesList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, agentName, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	rules, err := client.ListInboxRules(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to list rules: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(rules)
+	default:
+		if len(rules) == 0 {
+			fmt.Printf("%s has no inbox rules.\n", agentName)
+			return nil
+		}
+
+		fmt.Printf("Inbox rules for %s (%d):\n\n", agentName,
+			len(rules))
+		for i, rule := range rules {
+			state := ""
+			if !rule.Enabled {
+				state = " [disabled]"
+			}
+
+			cond := describeRuleConditions(
+				rule.InboxRuleConditions,
+			)
+			fmt.Printf("  %d. %s%s: %s -> %s\n", i+1, rule.Name,
+				state, cond, describeRuleActions(rule))
+
+			matched := "never matched"
+			if rule.LastMatchedAt != nil {
+				matched = fmt.Sprintf("%d matches, last %s",
+					rule.MatchCount,
+					rule.LastMatchedAt.Local().Format(
+						"2006-01-02 15:04:05",
+					))
+			}
+			fmt.Printf("     %s\n", matched)
+		}
+	}
+
+	return nil
+}
+
+// runRulesDelete handles the `substrate rules delete` command.
+func runRulesDelete(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if err := client.DeleteInboxRule(ctx, agentID, args[0]); err != nil {
+		return fmt.Errorf("failed to delete rule: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"name":    args[0],
+			"deleted": true,
+		})
+	default:
+		fmt.Printf("Deleted rule %s.\n", args[0])
+	}
+
+	return nil
+}
+
+// runRulesEnable handles the `substrate rules enable` and
+// `substrate rules disable` commands.
+func runRulesEnable(name string, enabled bool) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	rule, err := client.SetInboxRuleEnabled(ctx, agentID, name, enabled)
+	if err != nil {
+		return fmt.Errorf("failed to update rule: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(rule)
+	default:
+		if enabled {
+			fmt.Printf("Enabled rule %s.\n", rule.Name)
+		} else {
+			fmt.Printf("Disabled rule %s.\n", rule.Name)
+		}
+	}
+
+	return nil
+}
+
+// runRulesTest handles the `substrate rules test` command.
+func runRulesTest(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	req := mail.TestInboxRuleRequest{
+		Conditions: ruleConditionsFromFlags(),
+		Limit:      ruleTestLimit,
+	}
+	if len(args) == 1 {
+		req.Name = args[0]
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	req.AgentID, _, err = getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.TestInboxRule(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to test rule: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(resp)
+	default:
+		fmt.Printf("Matched %d of your last %d messages.\n",
+			len(resp.Matches), resp.Scanned)
+		for _, msg := range resp.Matches {
+			created := msg.CreatedAt.Local().Format(
+				"2006-01-02 15:04",
+			)
+			fmt.Printf("  #%d %s  %-20s %s\n", msg.ID, created,
+				msg.SenderName, msg.Subject)
+		}
+	}
+
+	return nil
+}
+
+// describeRuleConditions summarizes an inbox rule's conditions.
+func describeRuleConditions(cond store.InboxRuleConditions) string {
+	var parts []string
+	if cond.SenderName != "" {
+		parts = append(parts, "from "+cond.SenderName)
+	}
+	if cond.SenderPrefix != "" {
+		parts = append(parts, "from "+cond.SenderPrefix+"*")
+	}
+	if cond.SubjectPattern != "" {
+		parts = append(parts, fmt.Sprintf("subject /%s/",
+			cond.SubjectPattern))
+	}
+	if cond.TopicName != "" {
+		parts = append(parts, "topic "+cond.TopicName)
+	}
+	if cond.Priority != "" {
+		parts = append(parts, cond.Priority+" priority")
+	}
+	if cond.MetadataKey != "" {
+		meta := "meta " + cond.MetadataKey
+		if cond.MetadataValue != "" {
+			meta += "=" + cond.MetadataValue
+		}
+		parts = append(parts, meta)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// describeRuleActions summarizes an inbox rule's actions.
+func describeRuleActions(rule store.InboxRule) string {
+	var parts []string
+	switch {
+	case rule.Archive:
+		parts = append(parts, "archive")
+	case rule.Star:
+		parts = append(parts, "star")
+	case rule.Snooze != "":
+		parts = append(parts, "snooze "+rule.Snooze)
+	}
+	if rule.Category != store.InboxCategoryAll {
+		parts = append(parts, "file as "+string(rule.Category))
+	}
+	if rule.ForwardTo != "" {
+		parts = append(parts, "forward to "+rule.ForwardTo)
+	}
+	if rule.Label != "" {
+		parts = append(parts, "label "+rule.Label)
+	}
+	if rule.StopProcessing {
+		parts = append(parts, "stop")
+	}
+
+	return strings.Join(parts, ", ")
+}
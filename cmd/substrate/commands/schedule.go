@@ -0,0 +1,343 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// scheduleCmd is the parent command for recurring schedules.
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Manage recurring messages sent on a cron schedule",
+	Long: `Manage recurring messages that the daemon sends on a schedule.
+
+A schedule sends the same message to agents or a topic every time its cron
+expression fires, for example every weekday at 09:00. Schedules are owned
+by the agent that creates them and sent from it. Combined with
+'substrate watch', this turns a long-lived agent into a periodic worker.
+
+Each run is recorded in the activity feed. If the daemon was down when a
+schedule should have fired, the schedule's catch-up policy decides what
+happens once it is back: "once" (the default) sends a single message for
+all the missed runs, and "skip" drops them and waits for the next one.`,
+}
+
+// scheduleAddCmd creates a recurring schedule.
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Create a recurring message",
+	Long: `Create a recurring message.
+
+The cron expression has five fields: minute, hour, day of month, month and
+day of week. Fields accept '*', values, ranges ('1-5'), steps ('*/15') and
+lists ('1,15'), and month and weekday names such as 'jan' and 'mon'. The
+macros @hourly, @daily, @weekly, @monthly and @yearly are also accepted.
+The expression is evaluated in --tz, the daemon's local time zone by
+default.
+
+Example:
+  substrate schedule add flaky-sweep --cron "0 9 * * mon-fri" \
+    --tz America/New_York --to SilverWolf \
+    --subject "Run the flaky test sweep"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScheduleAdd,
+}
+
+// scheduleListCmd lists the current agent's schedules.
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your recurring messages",
+	Args:  cobra.NoArgs,
+	RunE:  runScheduleList,
+}
+
+// scheduleRmCmd deletes a schedule.
+var scheduleRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Delete a recurring message",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runScheduleRm,
+}
+
+// schedulePauseCmd pauses a schedule.
+var schedulePauseCmd = &cobra.Command{
+	Use:   "pause <name>",
+	Short: "Stop a recurring message until it is resumed",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSchedulePause(args[0], true)
+	},
+}
+
+// scheduleResumeCmd resumes a paused schedule.
+var scheduleResumeCmd = &cobra.Command{
+	Use:   "resume <name>",
+	Short: "Resume a paused recurring message",
+	Long: `Resume a paused recurring message.
+
+Runs that fell while the schedule was paused are not caught up; it next
+runs at its first occurrence from now.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSchedulePause(args[0], false)
+	},
+}
+
+var (
+	scheduleCron     string
+	scheduleTo       string
+	scheduleTopic    string
+	scheduleSubject  string
+	scheduleBody     string
+	scheduleBodyFile string
+	schedulePriority string
+	scheduleTZ       string
+	scheduleCatchUp  string
+)
+
+func init() {
+	scheduleAddCmd.Flags().StringVar(&scheduleCron, "cron", "",
+		"Cron expression, e.g. '0 9 * * mon-fri' (required)")
+	scheduleAddCmd.Flags().StringVar(&scheduleTo, "to", "",
+		"Comma-separated recipients or @groups")
+	scheduleAddCmd.Flags().StringVar(&scheduleTopic, "topic", "",
+		"Topic to publish to instead of recipients")
+	scheduleAddCmd.Flags().StringVar(&scheduleSubject, "subject", "",
+		"Message subject (required)")
+	scheduleAddCmd.Flags().StringVar(&scheduleBody, "body", "",
+		"Message body in markdown")
+	scheduleAddCmd.Flags().StringVar(&scheduleBodyFile, "body-file", "",
+		"Read message body from file (overrides --body)")
+	scheduleAddCmd.Flags().StringVar(&schedulePriority, "priority",
+		"normal", "Priority: urgent, normal, low")
+	scheduleAddCmd.Flags().StringVar(&scheduleTZ, "tz", "",
+		"IANA time zone, e.g. 'Europe/Berlin' (default: daemon's)")
+	scheduleAddCmd.Flags().StringVar(&scheduleCatchUp, "catch-up", "once",
+		"Missed runs after downtime: once, skip")
+	scheduleAddCmd.MarkFlagsMutuallyExclusive("to", "topic")
+	scheduleAddCmd.MarkFlagsOneRequired("to", "topic")
+
+	scheduleAddCmd.MarkFlagRequired("cron")
+	scheduleAddCmd.MarkFlagRequired("subject")
+
+	scheduleCmd.AddCommand(scheduleAddCmd)
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleRmCmd)
+	scheduleCmd.AddCommand(schedulePauseCmd)
+	scheduleCmd.AddCommand(scheduleResumeCmd)
+}
+
+// runScheduleAdd handles the `substrate schedule add` command.
+func runScheduleAdd(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if err := validateEnum(
+		schedulePriority, "priority",
+		[]string{"urgent", "normal", "low"},
+	); err != nil {
+		return err
+	}
+	if err := validateEnum(
+		scheduleCatchUp, "catch-up", []string{"once", "skip"},
+	); err != nil {
+		return err
+	}
+
+	body := scheduleBody
+	if scheduleBodyFile != "" {
+		data, err := os.ReadFile(scheduleBodyFile)
+		if err != nil {
+			return fmt.Errorf("failed to read body file: %w", err)
+		}
+		body = strings.TrimSpace(string(data))
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	sched, err := client.CreateSchedule(ctx, mail.CreateScheduleRequest{
+		Name:           args[0],
+		SenderID:       agentID,
+		RecipientNames: splitAgentList(scheduleTo),
+		TopicName:      scheduleTopic,
+		Subject:        scheduleSubject,
+		Body:           body,
+		Priority:       mail.Priority(schedulePriority),
+		CronExpr:       scheduleCron,
+		Timezone:       scheduleTZ,
+		CatchUp:        mail.CatchUpPolicy(scheduleCatchUp),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create schedule: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(sched)
+	default:
+		fmt.Printf("Created schedule %s.\n", sched.Name)
+		fmt.Printf("Next run: %s\n", formatScheduleTime(sched))
+	}
+
+	return nil
+}
+
+// runScheduleList handles the `substrate schedule list` command.
+func runScheduleList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, agentName, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	schedules, err := client.ListSchedules(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to list schedules: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(schedules)
+	default:
+		if len(schedules) == 0 {
+			fmt.Printf("%s has no schedules.\n", agentName)
+			return nil
+		}
+
+		fmt.Printf("Schedules for %s (%d):\n\n", agentName,
+			len(schedules))
+		for _, sched := range schedules {
+			to := strings.Join(sched.RecipientNames, ", ")
+			if sched.TopicName != "" {
+				to = "topic " + sched.TopicName
+			}
+
+			state := ""
+			if sched.Paused {
+				state = " [paused]"
+			}
+
+			fmt.Printf("  %s%s: %s -> %s\n", sched.Name, state,
+				sched.Subject, to)
+			fmt.Printf("    %s (%s, catch-up %s)\n", sched.CronExpr,
+				sched.Timezone, sched.CatchUp)
+			if !sched.Paused {
+				fmt.Printf("    Next run: %s\n",
+					formatScheduleTime(sched))
+			}
+			if sched.LastRunAt != nil {
+				fmt.Printf("    Last run: %s (%d runs)\n",
+					sched.LastRunAt.Local().Format(
+						"2006-01-02 15:04:05",
+					), sched.RunCount)
+			}
+			if sched.LastError != "" {
+				fmt.Printf("    Last error: %s\n",
+					sched.LastError)
+			}
+		}
+	}
+
+	return nil
+}
+
+// runScheduleRm handles the `substrate schedule rm` command.
+func runScheduleRm(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if err := client.DeleteSchedule(ctx, agentID, args[0]); err != nil {
+		return fmt.Errorf("failed to delete schedule: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"name":    args[0],
+			"deleted": true,
+		})
+	default:
+		fmt.Printf("Deleted schedule %s.\n", args[0])
+	}
+
+	return nil
+}
+
+// runSchedulePause handles the `substrate schedule pause` and
+// `substrate schedule resume` commands.
+func runSchedulePause(name string, paused bool) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	sched, err := client.SetSchedulePaused(ctx, agentID, name, paused)
+	if err != nil {
+		return fmt.Errorf("failed to update schedule: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(sched)
+	default:
+		if paused {
+			fmt.Printf("Paused schedule %s.\n", sched.Name)
+			return nil
+		}
+
+		fmt.Printf("Resumed schedule %s.\n", sched.Name)
+		fmt.Printf("Next run: %s\n", formatScheduleTime(sched))
+	}
+
+	return nil
+}
+
+// formatScheduleTime formats a schedule's next run in its own time zone.
+func formatScheduleTime(sched store.Schedule) string {
+	loc, err := mail.LoadScheduleLocation(sched.Timezone)
+	if err != nil {
+		loc = time.Local
+	}
+
+	return sched.NextRunAt.In(loc).Format("2006-01-02 15:04 MST")
+}
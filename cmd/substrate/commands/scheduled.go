@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// scheduledCmd lists the current agent's scheduled messages.
+var scheduledCmd = &cobra.Command{
+	Use:   "scheduled",
+	Short: "List messages scheduled to be sent later",
+	Long: `List your scheduled messages.
+
+Messages sent with 'substrate send --at' or '--in' are held by the daemon
+until their send time and then delivered. Scheduled messages that have not
+gone out yet are listed here, soonest first, along with any whose delivery
+failed and the reason it did.`,
+	Args: cobra.NoArgs,
+	RunE: runScheduled,
+}
+
+// scheduledCancelCmd cancels a scheduled message.
+var scheduledCancelCmd = &cobra.Command{
+	Use:   "cancel <id>",
+	Short: "Cancel a scheduled message before it is sent",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runScheduledCancel,
+}
+
+// scheduledLimit bounds the number of scheduled messages listed.
+var scheduledLimit int
+
+func init() {
+	scheduledCmd.Flags().IntVar(
+		&scheduledLimit, "limit", 50,
+		"Maximum number of scheduled messages to list",
+	)
+
+	scheduledCmd.AddCommand(scheduledCancelCmd)
+}
+
+// runScheduled handles the `substrate scheduled` command.
+func runScheduled(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, agentName, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	msgs, err := client.ListScheduledMessages(ctx, agentID, scheduledLimit)
+	if err != nil {
+		return fmt.Errorf("failed to list scheduled messages: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(msgs)
+	default:
+		if len(msgs) == 0 {
+			fmt.Printf("%s has no scheduled messages.\n", agentName)
+			return nil
+		}
+
+		fmt.Printf("Scheduled messages for %s (%d):\n\n", agentName,
+			len(msgs))
+		for _, m := range msgs {
+			to := strings.Join(m.RecipientNames, ", ")
+			if to == "" {
+				to = m.TopicName
+			}
+
+			fmt.Printf("  [%d] %s -> %s\n", m.ID, m.Subject, to)
+			fmt.Printf("    Sends at %s\n", m.SendAt.Local().Format(
+				"2006-01-02 15:04:05",
+			))
+			if m.State == "failed" {
+				fmt.Printf("    Failed: %s\n", m.LastError)
+			}
+		}
+	}
+
+	return nil
+}
+
+// runScheduledCancel handles the `substrate scheduled cancel` command.
+func runScheduledCancel(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid scheduled message ID: %w", err)
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	err = client.CancelScheduledMessage(ctx, agentID, id)
+	if err != nil {
+		return fmt.Errorf("failed to cancel scheduled message: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"id":       id,
+			"canceled": true,
+		})
+	default:
+		fmt.Printf("Canceled scheduled message %d.\n", id)
+	}
+
+	return nil
+}
@@ -3,34 +3,106 @@ package commands
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/search"
 	"github.com/spf13/cobra"
 )
 
 var (
 	searchIn    string
+	searchLabel string
+	searchKind  string
 	searchLimit int
+	searchSaved string
+
+	searchSemantic bool
+	searchHybrid   bool
 )
 
 // searchCmd searches for messages.
 var searchCmd = &cobra.Command{
-	Use:   "search <query>",
+	Use:   "search [query]",
 	Short: "Search messages",
-	Long:  `Search messages using full-text search.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runSearch,
+	Long: `Search the messages you sent or received.
+
+Words and "quoted phrases" must all match. OR matches either of the terms
+around it, a trailing * matches words by prefix, and a leading - or NOT
+excludes a term. These operators filter the results:
+
+  from:<agent>  to:<agent>      Sender or recipient, "me" for yourself
+  topic:<name>  thread:<id>     Topic or thread
+  label:<name>                  Messages filed under one of your labels
+  priority:<p>                  urgent, normal or low
+  state:<s>  is:<s>             unread, read, starred, snoozed, archived,
+                                or trash
+  before:<t>  after:<t>         A date (2026-01-02), an RFC 3339 time, or
+                                an age such as 12h, 7d or 2w
+  has:diff  has:attachment      Messages carrying a diff or attachments
+
+Values with spaces may be quoted, as in from:"Code Reviewer".
+
+Results are ranked best match first, with subject matches counting for more
+than body matches, and show a snippet of the body around the matched terms.
+Filter-only queries list the newest messages first. When there are more
+results than --limit, pass the printed page token to --page-token to see
+the next page. Use 'substrate read <id>' for a full message.
+
+--kind searches other kinds of entities instead of, or along with,
+messages: review_issue, plan_review, task, plan_annotation and
+diff_annotation ("annotation" for both), as a comma-separated list or
+"all". Their hits are merged by rank and labeled with their kind. Only
+terms and before: and after: apply to them.
+
+--semantic finds messages by meaning instead: the terms are embedded and
+compared with each message's embedding, so a message can match without
+sharing a word with the query, while the operators still filter. --hybrid
+merges those results with the messages the terms match, weighing the
+similarity against the BM25 rank. Semantic results aren't paged.
+
+--saved runs one of the searches you saved with 'substrate searches save'.
+A query given along with it narrows the saved search's results.
+
+Examples:
+  substrate search "deploy OR release is:unread"
+  substrate search 'from:Reviewer has:diff after:7d -nit'
+  substrate search --kind all '"htlc timeout" bug'
+  substrate search --semantic 'why does the payments test fail'
+  substrate search --saved triage is:unread`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSearch,
 }
 
 func init() {
 	searchCmd.Flags().StringVar(&searchIn, "in", "",
-		"Limit search to a specific topic")
+		"Limit search to a specific topic (like topic:)")
+	searchCmd.Flags().StringVar(&searchLabel, "label", "",
+		"Limit search to messages filed under a label (like label:)")
+	searchCmd.Flags().StringVar(&searchKind, "kind", "message",
+		"Kinds to search: message, review_issue, plan_review, task, "+
+			"plan_annotation, diff_annotation, annotation or all")
 	searchCmd.Flags().IntVarP(&searchLimit, "limit", "n", 20,
-		"Maximum number of results")
+		"Maximum number of results per page")
+	searchCmd.Flags().BoolVar(&searchSemantic, "semantic", false,
+		"Find messages by meaning rather than by matching words")
+	searchCmd.Flags().BoolVar(&searchHybrid, "hybrid", false,
+		"Merge semantic results with word matches (implies --semantic)")
+	searchCmd.Flags().StringVar(&searchSaved, "saved", "",
+		"Run one of your saved searches, narrowed by the query "+
+			"if given")
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
-	query := args[0]
+
+	var query string
+	if len(args) > 0 {
+		query = args[0]
+	}
+	if query == "" && searchSaved == "" {
+		return fmt.Errorf("a query or --saved is required")
+	}
 
 	client, err := getClient()
 	if err != nil {
@@ -43,33 +115,211 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Get topic ID if searching within a specific topic.
-	var topicID int64
-	// Note: Topic lookup would need to be added to the client if needed.
-	// For now, we pass 0 to search all topics.
+	if searchIn != "" {
+		query += " " + search.QuoteFilter("topic", searchIn)
+	}
+
+	kinds, err := search.ParseKinds(searchKind)
+	if err != nil {
+		return err
+	}
+	messagesOnly := len(kinds) == 1 && kinds[0] == search.KindMessage
+	switch {
+	case searchSaved != "" &&
+		(searchSemantic || searchHybrid || !messagesOnly):
+
+		return fmt.Errorf("--saved only applies to keyword message " +
+			"searches")
+
+	case (searchSemantic || searchHybrid) && !messagesOnly:
+		return fmt.Errorf("--semantic only applies to message searches")
+
+	case searchSemantic || searchHybrid:
+		return runSemanticSearch(ctx, client, agentID, query)
+
+	case !messagesOnly:
+		return runSearchAll(ctx, client, agentID, query, kinds)
+	}
+
+	resp, err := client.Search(ctx, mail.SearchRequest{
+		AgentID:     agentID,
+		Query:       query,
+		Limit:       int32(searchLimit),
+		Label:       searchLabel,
+		Cursor:      pageToken,
+		SavedSearch: searchSaved,
+	})
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	desc := fmt.Sprintf("%q", query)
+	if searchSaved != "" {
+		desc = fmt.Sprintf("saved search %q", searchSaved)
+		if query != "" {
+			desc += fmt.Sprintf(" narrowed by %q", query)
+		}
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(paginationEnvelope{
+			Items:         resp.Hits,
+			Total:         resp.Total,
+			NextPageToken: resp.NextCursor,
+		})
+	default:
+		if len(resp.Hits) == 0 {
+			fmt.Printf("No results for %s.\n", desc)
+			return nil
+		}
+
+		fmt.Printf("Search results for %s (%d of %d):\n\n", desc,
+			len(resp.Hits), resp.Total)
+		for _, r := range resp.Hits {
+			fmt.Printf("  #%d: %s\n", r.ID, r.SubjectHighlight)
+			fmt.Printf("    From: %s | Thread: %s | Priority: %s\n",
+				r.SenderName, r.ThreadID, r.Priority)
+			if len(r.Labels) > 0 {
+				fmt.Printf("    Labels: %s\n",
+					strings.Join(r.Labels, ", "))
+			}
+			if r.Snippet != "" {
+				fmt.Printf("    %s\n", r.Snippet)
+			}
+			fmt.Println()
+		}
+		if resp.NextCursor != "" {
+			fmt.Printf("More results: --page-token %s\n",
+				resp.NextCursor)
+		}
+	}
+
+	return nil
+}
+
+// runSearchAll searches the given kinds of entities, or every kind if none
+// are given, and prints their hits.
+func runSearchAll(ctx context.Context, client *Client, agentID int64,
+	query string, kinds []search.Kind,
+) error {
+	switch {
+	case searchLabel != "":
+		return fmt.Errorf("--label only applies to message searches")
+
+	case pageToken != "":
+		return fmt.Errorf("--page-token only applies to message " +
+			"searches")
+	}
+
+	resp, err := client.SearchAll(ctx, agentID, query, kinds, searchLimit)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(paginationEnvelope{
+			Items: resp.Hits,
+			Total: resp.Total,
+		})
+	default:
+		if len(resp.Hits) == 0 {
+			fmt.Printf("No results for %q.\n", query)
+			return nil
+		}
+
+		fmt.Printf("Search results for %q (%d of %d):\n\n", query,
+			len(resp.Hits), resp.Total)
+		for _, h := range resp.Hits {
+			fmt.Printf("  [%s] #%d: %s\n", h.Kind, h.ID, h.Title)
+
+			var refs []string
+			if h.Ref != "" {
+				refs = append(refs, searchRefLabel(h.Kind)+": "+
+					h.Ref)
+			}
+			if h.ThreadID != "" {
+				refs = append(refs, "Thread: "+h.ThreadID)
+			}
+			if len(refs) > 0 {
+				fmt.Printf("    %s\n",
+					strings.Join(refs, " | "))
+			}
+			if h.Snippet != "" {
+				fmt.Printf("    %s\n", h.Snippet)
+			}
+			fmt.Println()
+		}
+	}
+
+	return nil
+}
 
-	results, err := client.Search(ctx, agentID, query, topicID, searchLimit)
+// runSemanticSearch finds the agent's messages closest in meaning to the
+// query and prints them with their scores.
+func runSemanticSearch(ctx context.Context, client *Client, agentID int64,
+	query string,
+) error {
+	if pageToken != "" {
+		return fmt.Errorf("--page-token doesn't apply to semantic " +
+			"searches")
+	}
+	if searchLabel != "" {
+		query += " " + search.QuoteFilter("label", searchLabel)
+	}
+
+	resp, err := client.SemanticSearch(
+		ctx, agentID, query, searchHybrid, searchLimit,
+	)
 	if err != nil {
 		return fmt.Errorf("search failed: %w", err)
 	}
 
 	switch outputFormat {
 	case "json":
-		return outputJSON(results)
+		return outputJSON(paginationEnvelope{
+			Items: resp.Hits,
+			Total: int64(len(resp.Hits)),
+		})
 	default:
-		if len(results) == 0 {
+		if len(resp.Hits) == 0 {
 			fmt.Printf("No results for %q.\n", query)
 			return nil
 		}
 
-		fmt.Printf("Search results for %q (%d):\n\n", query, len(results))
-		for _, r := range results {
-			fmt.Printf("  #%d: %s\n", r.ID, r.Subject)
-			fmt.Printf("    Thread: %s | Priority: %s\n",
-				r.ThreadID, r.Priority)
+		fmt.Printf("Semantic results for %q:\n\n", query)
+		for _, h := range resp.Hits {
+			fmt.Printf("  #%d: %s (score %.2f)\n", h.ID, h.Subject,
+				h.Score)
+			fmt.Printf("    From: %s | Thread: %s | Priority: %s\n",
+				h.SenderName, h.ThreadID, h.Priority)
+			if h.Snippet != "" {
+				fmt.Printf("    %s\n", h.Snippet)
+			}
 			fmt.Println()
 		}
 	}
 
 	return nil
 }
+
+// searchRefLabel names what a hit's Ref refers to for its kind.
+func searchRefLabel(kind search.Kind) string {
+	switch kind {
+	case search.KindReviewIssue:
+		return "Review"
+
+	case search.KindPlanReview, search.KindPlanAnnotation:
+		return "Plan review"
+
+	case search.KindTask:
+		return "Task list"
+
+	case search.KindDiffAnnotation:
+		return "Message"
+
+	default:
+		return "Ref"
+	}
+}
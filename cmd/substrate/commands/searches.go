@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// searchesCmd is the parent command for saved searches.
+var searchesCmd = &cobra.Command{
+	Use:   "searches",
+	Short: "Manage your saved searches",
+	Long: `Manage saved searches, the queries you rerun when triaging.
+
+A saved search is a search query stored under a name. Run it with
+'substrate search --saved <name>', optionally narrowed by a further query,
+or wake only for mail matching it with 'substrate watch --saved <name>'.
+Queries are stored as written, so ages such as after:1d are measured from
+each run. Saving under a name you already use replaces its query.`,
+	Example: `  substrate searches save triage 'priority:urgent after:1d'
+  substrate searches save unacked-user 'to:User is:unread'
+  substrate search --saved triage
+  substrate watch --session-id "$CLAUDE_SESSION_ID" --saved triage`,
+}
+
+// searchesListCmd lists the current agent's saved searches.
+var searchesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your saved searches",
+	Args:  cobra.NoArgs,
+	RunE:  runSearchesList,
+}
+
+// searchesSaveCmd saves a search.
+var searchesSaveCmd = &cobra.Command{
+	Use:   "save <name> <query>",
+	Short: "Save a search query under a name",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runSearchesSave,
+}
+
+// searchesDeleteCmd deletes a saved search.
+var searchesDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a saved search",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSearchesDelete,
+}
+
+func init() {
+	searchesCmd.AddCommand(searchesListCmd)
+	searchesCmd.AddCommand(searchesSaveCmd)
+	searchesCmd.AddCommand(searchesDeleteCmd)
+}
+
+// runSearchesList handles the `substrate searches list` command.
+func runSearchesList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, agentName, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	searches, err := client.ListSavedSearches(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to list saved searches: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(searches)
+	default:
+		if len(searches) == 0 {
+			fmt.Printf("%s has no saved searches.\n", agentName)
+			return nil
+		}
+
+		fmt.Printf("Saved searches for %s (%d):\n\n", agentName,
+			len(searches))
+		for _, saved := range searches {
+			fmt.Printf("  %-24s %s\n", saved.Name, saved.Query)
+		}
+	}
+
+	return nil
+}
+
+// runSearchesSave handles the `substrate searches save` command.
+func runSearchesSave(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	saved, err := client.SaveSearch(ctx, agentID, args[0], args[1])
+	if err != nil {
+		return fmt.Errorf("failed to save search: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(saved)
+	default:
+		fmt.Printf("Saved search %s: %s\n", saved.Name, saved.Query)
+	}
+
+	return nil
+}
+
+// runSearchesDelete handles the `substrate searches delete` command.
+func runSearchesDelete(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	err = client.DeleteSavedSearch(ctx, agentID, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"name":    args[0],
+			"deleted": true,
+		})
+	default:
+		fmt.Printf("Deleted saved search %s.\n", args[0])
+	}
+
+	return nil
+}
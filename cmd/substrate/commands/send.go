@@ -20,6 +20,10 @@ var (
 	sendPriority string
 	sendDeadline string
 	sendThreadID string
+	sendPartial  bool
+	sendAt       string
+	sendIn       string
+	sendMeta     []string
 )
 
 var sendCmd = &cobra.Command{
@@ -30,13 +34,29 @@ var sendCmd = &cobra.Command{
 The message body can be specified inline with --body, or read from a
 file with --body-file. When both are provided, --body-file takes
 precedence. Use --body-file for long multi-line markdown content to
-avoid shell quoting issues.`,
+avoid shell quoting issues.
+
+Several recipients can be given as a comma-separated list. By default the
+send fails if any recipient is unknown or rejected. With --partial, the
+message is delivered to every recipient that can receive it, the outcome
+for each recipient is printed, and the rest are filed in your dead-letter
+mailbox (see 'substrate dead-letters').
+
+Use --at or --in to send the message later. --at takes an RFC3339
+timestamp, a local "2006-01-02 15:04" time, or a local "15:04" time of day
+(the next occurrence of it), and --in takes a duration such as "2h". The
+daemon delivers the message once it is due, and recipients are resolved at
+that point. Scheduled messages can be listed and canceled with
+'substrate scheduled'.
+
+Use --meta key=value, repeatable, to attach metadata that recipients' inbox
+rules can match on (see 'substrate rules').`,
 	RunE: runSend,
 }
 
 func init() {
 	sendCmd.Flags().StringVar(&sendTo, "to", "",
-		"Recipient agent name or topic (required)")
+		"Comma-separated recipients or @groups (required)")
 	sendCmd.Flags().StringVar(&sendSubject, "subject", "",
 		"Message subject (required)")
 	sendCmd.Flags().StringVar(&sendBody, "body", "",
@@ -49,6 +69,15 @@ func init() {
 		"Acknowledgment deadline (e.g., '2h', '2026-01-29T10:00:00')")
 	sendCmd.Flags().StringVar(&sendThreadID, "thread", "",
 		"Thread ID for replies")
+	sendCmd.Flags().BoolVar(&sendPartial, "partial", false,
+		"Deliver to every reachable recipient instead of failing")
+	sendCmd.Flags().StringVar(&sendAt, "at", "",
+		"Send at a later time (e.g., '09:00', '2026-01-29 09:00')")
+	sendCmd.Flags().StringVar(&sendIn, "in", "",
+		"Send after a delay (e.g., '2h', '30m')")
+	sendCmd.Flags().StringArrayVar(&sendMeta, "meta", nil,
+		"Metadata as key=value for inbox rules (repeatable)")
+	sendCmd.MarkFlagsMutuallyExclusive("at", "in")
 
 	sendCmd.MarkFlagRequired("to")
 	sendCmd.MarkFlagRequired("subject")
@@ -99,43 +128,235 @@ func runSend(cmd *cobra.Command, args []string) error {
 		deadline = &d
 	}
 
+	sendAtTime, err := parseSendTime(sendAt, sendIn, time.Now())
+	if err != nil {
+		return err
+	}
+
+	metadata, err := parseMetadata(sendMeta)
+	if err != nil {
+		return err
+	}
+
 	// In queue mode, enqueue the operation for later delivery.
 	if client.Mode() == ModeQueued {
 		return enqueueSend(
 			ctx, client, agentNameStr, body,
-			string(priority), deadline,
+			string(priority), deadline, sendAtTime, metadata,
 		)
 	}
 
 	req := mail.SendMailRequest{
 		SenderID:       agentID,
-		RecipientNames: []string{sendTo},
+		RecipientNames: splitAgentList(sendTo),
 		Subject:        sendSubject,
 		Body:           body,
 		Priority:       priority,
 		Deadline:       deadline,
 		ThreadID:       sendThreadID,
+		SendAt:         sendAtTime,
+		Metadata:       metadata,
+	}
+	if sendPartial {
+		req.DeliveryMode = mail.DeliveryModePartial
 	}
 
-	msgID, threadID, err := client.SendMail(ctx, req)
+	warnDuplicate(ctx, client, agentID, sendSubject, body)
+
+	resp, err := client.SendMailDetailed(ctx, req)
 	if err != nil {
 		return err
 	}
 
+	if resp.ScheduledID != 0 {
+		return printScheduledSend(resp, *sendAtTime)
+	}
+
 	switch outputFormat {
 	case "json":
 		return outputJSON(map[string]any{
-			"message_id": msgID,
-			"thread_id":  threadID,
+			"message_id": resp.MessageID,
+			"thread_id":  resp.ThreadID,
+			"expansions": resp.Expansions,
+			"deliveries": resp.Deliveries,
 		})
 	default:
-		fmt.Printf("Message sent! ID: %d, Thread: %s\n",
-			msgID, threadID)
+		if delivered(resp.Deliveries) {
+			fmt.Printf("Message sent! ID: %d, Thread: %s\n",
+				resp.MessageID, resp.ThreadID)
+		} else {
+			fmt.Printf("Message not delivered to any recipient. "+
+				"ID: %d, Thread: %s\n", resp.MessageID,
+				resp.ThreadID)
+		}
+		for _, e := range resp.Expansions {
+			fmt.Printf("  %s -> %s\n", e.Address,
+				strings.Join(e.AgentNames, ", "))
+		}
+		printDeliveries(resp.Deliveries)
 	}
 
 	return nil
 }
 
+// warnDuplicate warns if a message about to be sent reads like one the agent
+// already sent or received. The check is best effort: if it fails, the
+// message is sent without it.
+func warnDuplicate(ctx context.Context, client *Client, agentID int64,
+	subject, body string,
+) {
+	resp, err := client.SimilarMessages(
+		ctx, agentID, mail.EmbedText(subject, body),
+		mail.DefaultDuplicateThreshold, 1,
+	)
+	if err != nil || len(resp.Hits) == 0 {
+		return
+	}
+
+	dup := resp.Hits[0]
+	fmt.Fprintf(os.Stderr, "Warning: this looks like a duplicate of #%d "+
+		"(%q, %.0f%% similar)\n", dup.ID, dup.Subject, 100*dup.Score)
+}
+
+// delivered reports whether a message reached at least one recipient. A
+// send without per-recipient results, such as a topic publish, counts as
+// delivered.
+func delivered(deliveries []mail.RecipientDelivery) bool {
+	for _, d := range deliveries {
+		if !d.Status.Undeliverable() {
+			return true
+		}
+	}
+
+	return len(deliveries) == 0
+}
+
+// printDeliveries prints the delivery outcome for each recipient of a
+// message. Nothing is printed when every recipient was reached directly.
+func printDeliveries(deliveries []mail.RecipientDelivery) {
+	allDelivered := true
+	for _, d := range deliveries {
+		if d.Status != mail.RecipientDelivered {
+			allDelivered = false
+		}
+	}
+	if allDelivered {
+		return
+	}
+
+	fmt.Println("Recipients:")
+	for _, d := range deliveries {
+		if d.Reason != "" {
+			fmt.Printf("  %-20s %-10s %s\n", d.Recipient, d.Status,
+				d.Reason)
+			continue
+		}
+		fmt.Printf("  %-20s %s\n", d.Recipient, d.Status)
+	}
+	for _, d := range deliveries {
+		if d.Status.Undeliverable() {
+			fmt.Println("Undeliverable recipients were filed in " +
+				"your dead-letter mailbox.")
+			break
+		}
+	}
+}
+
+// printScheduledSend prints the result of a send that was deferred to a
+// later time.
+func printScheduledSend(resp mail.SendMailResponse, sendAt time.Time) error {
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"scheduled_id": resp.ScheduledID,
+			"thread_id":    resp.ThreadID,
+			"send_at":      sendAt,
+		})
+	default:
+		fmt.Printf("Message scheduled! ID: %d, Thread: %s\n",
+			resp.ScheduledID, resp.ThreadID)
+		fmt.Printf("  Sends at %s\n", sendAt.Local().Format(
+			"2006-01-02 15:04:05",
+		))
+	}
+
+	return nil
+}
+
+// parseSendTime parses the --at and --in flags into the time a message
+// should be sent at. It returns nil if neither flag is set.
+func parseSendTime(at, in string, now time.Time) (*time.Time, error) {
+	switch {
+	case in != "":
+		d, err := time.ParseDuration(in)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --in: %w", err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("--in must be positive")
+		}
+		t := now.Add(d)
+
+		return &t, nil
+
+	case at != "":
+		if t, err := time.Parse(time.RFC3339, at); err == nil {
+			return &t, nil
+		}
+
+		for _, layout := range []string{
+			"2006-01-02 15:04", "2006-01-02T15:04",
+		} {
+			t, err := time.ParseInLocation(
+				layout, at, now.Location(),
+			)
+			if err == nil {
+				return &t, nil
+			}
+		}
+
+		// A bare time of day means its next occurrence.
+		clock, err := time.ParseInLocation("15:04", at, now.Location())
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse --at %q as a "+
+				"timestamp or time of day", at)
+		}
+		t := time.Date(
+			now.Year(), now.Month(), now.Day(), clock.Hour(),
+			clock.Minute(), 0, 0, now.Location(),
+		)
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+
+		return &t, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// parseMetadata parses --meta key=value pairs into a metadata map. It
+// returns nil if no pairs are given.
+func parseMetadata(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	metadata := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --meta %q, expected "+
+				"key=value", pair)
+		}
+		metadata[key] = value
+	}
+
+	return metadata, nil
+}
+
 // parseDuration parses a duration string or RFC3339 timestamp.
 func parseDuration(s string) (time.Time, error) {
 	// Try parsing as duration (e.g., "2h", "30m").
@@ -164,18 +385,22 @@ func parseDuration(s string) (time.Time, error) {
 // enqueueSend stores a send operation in the local queue for later delivery.
 func enqueueSend(
 	ctx context.Context, client *Client, senderName, body,
-	priority string, deadline *time.Time,
+	priority string, deadline, sendAt *time.Time,
+	metadata map[string]string,
 ) error {
 	key := newIdempotencyKey()
 	payload := queue.SendPayload{
 		SenderName:     senderName,
-		RecipientNames: []string{sendTo},
+		RecipientNames: splitAgentList(sendTo),
 		Subject:        sendSubject,
 		Body:           body,
 		Priority:       priority,
 		TopicName:      "",
 		ThreadID:       sendThreadID,
 		DeadlineAt:     deadline,
+		Partial:        sendPartial,
+		SendAt:         sendAt,
+		Metadata:       metadata,
 	}
 
 	payloadJSON, err := queue.MarshalPayload(payload)
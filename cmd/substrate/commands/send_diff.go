@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/search"
 	"github.com/spf13/cobra"
 )
 
@@ -147,7 +148,7 @@ func runSendDiff(cmd *cobra.Command, args []string) error {
 	)
 
 	// Diff marker — the frontend detects this and renders with DiffViewer.
-	body.WriteString("<!-- substrate:diff -->\n")
+	body.WriteString(search.DiffMarker + "\n")
 	body.WriteString(patch)
 
 	// Build a content-based idempotency key from the diff patch.
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseSendTime verifies that the --at and --in flags resolve to the
+// expected send times.
+func TestParseSendTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 29, 14, 30, 0, 0, time.UTC)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		at   string
+		in   string
+		want time.Time
+	}{
+		{
+			name: "delay",
+			in:   "2h",
+			want: now.Add(2 * time.Hour),
+		},
+		{
+			name: "rfc3339",
+			at:   "2026-01-30T09:00:00Z",
+			want: at(30, 9, 0),
+		},
+		{
+			name: "local date and time",
+			at:   "2026-01-30 09:15",
+			want: at(30, 9, 15),
+		},
+		{
+			name: "time of day later today",
+			at:   "17:00",
+			want: at(29, 17, 0),
+		},
+		{
+			name: "time of day tomorrow",
+			at:   "09:00",
+			want: at(30, 9, 0),
+		},
+	}
+	for _, tc := range tests {
+		got, err := parseSendTime(tc.at, tc.in, now)
+		require.NoError(t, err, tc.name)
+		require.NotNil(t, got, tc.name)
+		require.True(t, tc.want.Equal(*got), "%s: got %v", tc.name, got)
+	}
+
+	got, err := parseSendTime("", "", now)
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	for _, in := range []string{"soon", "-1h", "0s"} {
+		_, err := parseSendTime("", in, now)
+		require.Error(t, err, in)
+	}
+
+	_, err = parseSendTime("tomorrow", "", now)
+	require.Error(t, err)
+}
+
+// TestParseMetadata verifies that --meta flags parse into a metadata map.
+func TestParseMetadata(t *testing.T) {
+	t.Parallel()
+
+	metadata, err := parseMetadata(nil)
+	require.NoError(t, err)
+	require.Nil(t, metadata)
+
+	metadata, err = parseMetadata([]string{"ci=failed", "url=a=b", "x="})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"ci":  "failed",
+		"url": "a=b",
+		"x":   "",
+	}, metadata)
+
+	_, err = parseMetadata([]string{"novalue"})
+	require.Error(t, err)
+
+	_, err = parseMetadata([]string{"=value"})
+	require.Error(t, err)
+}
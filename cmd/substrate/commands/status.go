@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/ratelimit"
 	"github.com/spf13/cobra"
 )
 
@@ -36,9 +38,16 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Limits are only shown once some apply. A daemon that predates them,
+	// or a credential without access to them, just leaves them out.
+	usage, _ := client.GetUsage(ctx, agentID)
+
 	switch outputFormat {
 	case "json":
-		return outputJSON(status)
+		return outputJSON(struct {
+			mail.AgentStatus
+			Limits []ratelimit.Usage `json:",omitempty"`
+		}{*status, limitedUsage(usage)})
 	case "context":
 		if status.UrgentCount > 0 {
 			fmt.Printf("[Subtrate] %d urgent, %d unread messages\n",
@@ -50,6 +59,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return nil
 	default:
 		fmt.Print(formatStatus(*status))
+		fmt.Print(formatUsage(usage))
 	}
 
 	return nil
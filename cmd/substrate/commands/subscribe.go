@@ -11,11 +11,18 @@ import (
 var subscribeCmd = &cobra.Command{
 	Use:   "subscribe <topic>",
 	Short: "Subscribe to a topic",
-	Long:  `Subscribe the current agent to a topic for receiving messages.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runSubscribe,
+	Long: `Subscribe the current agent to a topic for receiving messages.
+
+Use --type queue to create a work queue: each message published to it is
+delivered to exactly one subscriber, which claims it with 'substrate work
+claim'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSubscribe,
 }
 
+// subscribeType is the type given to the topic if it doesn't exist yet.
+var subscribeType string
+
 // unsubscribeCmd unsubscribes from a topic.
 var unsubscribeCmd = &cobra.Command{
 	Use:   "unsubscribe <topic>",
@@ -26,6 +33,9 @@ var unsubscribeCmd = &cobra.Command{
 }
 
 func init() {
+	subscribeCmd.Flags().StringVar(&subscribeType, "type", "",
+		"Create the topic with this type if missing: broadcast, queue")
+
 	rootCmd.AddCommand(subscribeCmd)
 	rootCmd.AddCommand(unsubscribeCmd)
 }
@@ -45,7 +55,17 @@ func runSubscribe(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if err := client.Subscribe(ctx, agentID, topicName); err != nil {
+	if subscribeType != "" {
+		err := validateEnum(
+			subscribeType, "type", []string{"broadcast", "queue"},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Subscribe(ctx, agentID, topicName, subscribeType)
+	if err != nil {
 		return fmt.Errorf("failed to subscribe: %w", err)
 	}
 
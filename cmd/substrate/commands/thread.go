@@ -0,0 +1,436 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/queue"
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// threadCmd is the parent command for thread operations.
+var threadCmd = &cobra.Command{
+	Use:   "thread",
+	Short: "Show, file, mute and follow conversation threads",
+	Long: `Read and manage whole conversations at once.
+
+'thread show' prints every message in a thread, oldest first. 'archive',
+'unread' and 'delete' file every message of the thread you received; while
+offline they're queued like replies and applied on the next connection.
+'show' and the mute and follow commands need the daemon or the database.
+
+Muting a thread delivers its new replies already read, so they don't wake
+'substrate watch' or show up as unread. Following a thread delivers its
+replies to you even when you aren't addressed. A thread can't be muted and
+followed at once: doing one undoes the other. Thread IDs are shown by
+'substrate read'.`,
+}
+
+// threadShowCmd prints every message in a thread.
+var threadShowCmd = &cobra.Command{
+	Use:   "show <thread-id>",
+	Short: "Show every message in a thread",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runThreadShow,
+}
+
+// threadArchiveCmd archives a thread.
+var threadArchiveCmd = &cobra.Command{
+	Use:   "archive <thread-id>",
+	Short: "Archive every message of a thread you received",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runThreadState(
+			args[0], mail.StateArchivedStr.String(), "archived",
+		)
+	},
+}
+
+// threadUnreadCmd marks a thread unread.
+var threadUnreadCmd = &cobra.Command{
+	Use:   "unread <thread-id>",
+	Short: "Mark every message of a thread you received unread",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runThreadState(
+			args[0], mail.StateUnreadStr.String(), "marked unread",
+		)
+	},
+}
+
+// threadDeleteCmd moves a thread to trash.
+var threadDeleteCmd = &cobra.Command{
+	Use:   "delete <thread-id>",
+	Short: "Move every message of a thread you received to trash",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runThreadState(
+			args[0], mail.StateTrashStr.String(), "moved to trash",
+		)
+	},
+}
+
+// threadMuteCmd mutes a thread.
+var threadMuteCmd = &cobra.Command{
+	Use:   "mute <thread-id>",
+	Short: "Receive a thread's new replies already read",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runThreadPreference(args[0], func(ctx context.Context,
+			client *Client, agentID int64,
+		) (store.ThreadPreference, error) {
+			return client.SetThreadMuted(
+				ctx, agentID, args[0], true,
+			)
+		})
+	},
+}
+
+// threadUnmuteCmd unmutes a thread.
+var threadUnmuteCmd = &cobra.Command{
+	Use:   "unmute <thread-id>",
+	Short: "Receive a thread's new replies unread again",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runThreadPreference(args[0], func(ctx context.Context,
+			client *Client, agentID int64,
+		) (store.ThreadPreference, error) {
+			return client.SetThreadMuted(
+				ctx, agentID, args[0], false,
+			)
+		})
+	},
+}
+
+// threadFollowCmd follows a thread.
+var threadFollowCmd = &cobra.Command{
+	Use:   "follow <thread-id>",
+	Short: "Receive a thread's replies even when not addressed",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runThreadPreference(args[0], func(ctx context.Context,
+			client *Client, agentID int64,
+		) (store.ThreadPreference, error) {
+			return client.SetThreadFollowing(
+				ctx, agentID, args[0], true,
+			)
+		})
+	},
+}
+
+// threadUnfollowCmd unfollows a thread.
+var threadUnfollowCmd = &cobra.Command{
+	Use:   "unfollow <thread-id>",
+	Short: "Stop receiving a thread's replies you aren't addressed in",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runThreadPreference(args[0], func(ctx context.Context,
+			client *Client, agentID int64,
+		) (store.ThreadPreference, error) {
+			return client.SetThreadFollowing(
+				ctx, agentID, args[0], false,
+			)
+		})
+	},
+}
+
+// threadListCmd lists the threads the current agent muted or follows.
+var threadListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the threads you muted or follow",
+	Args:  cobra.NoArgs,
+	RunE:  runThreadList,
+}
+
+func init() {
+	threadCmd.AddCommand(threadShowCmd)
+	threadCmd.AddCommand(threadArchiveCmd)
+	threadCmd.AddCommand(threadUnreadCmd)
+	threadCmd.AddCommand(threadDeleteCmd)
+	threadCmd.AddCommand(threadMuteCmd)
+	threadCmd.AddCommand(threadUnmuteCmd)
+	threadCmd.AddCommand(threadFollowCmd)
+	threadCmd.AddCommand(threadUnfollowCmd)
+	threadCmd.AddCommand(threadListCmd)
+}
+
+// errThreadOffline is returned by thread commands that can't run from the
+// offline queue: showing a thread needs its messages, and mute and follow
+// print the resulting preference.
+var errThreadOffline = errors.New("this thread command needs the " +
+	"substrated daemon or the database; only archive, unread and delete " +
+	"are queued while offline")
+
+// threadStateFunc returns the client method that moves a thread's messages
+// to the given mail state.
+func threadStateFunc(state string) (func(*Client, context.Context, int64,
+	string) (int, error), error,
+) {
+	switch mail.RecipientState(state) {
+	case mail.StateArchivedStr:
+		return (*Client).ArchiveThread, nil
+	case mail.StateUnreadStr:
+		return (*Client).MarkThreadUnread, nil
+	case mail.StateTrashStr:
+		return (*Client).DeleteThread, nil
+	default:
+		return nil, fmt.Errorf("unknown thread state %q", state)
+	}
+}
+
+// runThreadShow handles the `substrate thread show` command.
+func runThreadShow(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// The thread's messages can't be read while offline, so unlike
+	// archive, unread and delete, show isn't queued.
+	if client.Mode() == ModeQueued {
+		return errThreadOffline
+	}
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	msgs, err := client.ReadThread(ctx, agentID, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read thread: %w", err)
+	}
+	if len(msgs) == 0 {
+		return fmt.Errorf("thread %s not found", args[0])
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(msgs)
+	default:
+		fmt.Print(formatThread(args[0], msgs))
+	}
+
+	return nil
+}
+
+// runThreadState handles the `substrate thread archive`, `unread` and
+// `delete` commands, moving the thread's messages to state.
+func runThreadState(threadID, state, verb string) error {
+	ctx := context.Background()
+
+	apply, err := threadStateFunc(state)
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, agentName, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	// In queue mode, the thread's messages can't be looked up, so the
+	// change is queued and applied to them on delivery.
+	if client.Mode() == ModeQueued {
+		return enqueueThreadState(
+			ctx, client, agentName, threadID, state,
+		)
+	}
+
+	count, err := apply(client, ctx, agentID, threadID)
+	if err != nil {
+		return fmt.Errorf("failed to update thread: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"thread_id": threadID,
+			"messages":  count,
+		})
+	default:
+		fmt.Printf("%d message(s) in thread %s %s.\n", count, threadID,
+			verb)
+	}
+
+	return nil
+}
+
+// enqueueThreadState stores a thread state change in the local queue for
+// later delivery.
+func enqueueThreadState(ctx context.Context, client *Client, agentName,
+	threadID, state string,
+) error {
+	key := newIdempotencyKey()
+	payload := queue.ThreadStatePayload{
+		AgentName: agentName,
+		ThreadID:  threadID,
+		State:     state,
+	}
+
+	payloadJSON, err := queue.MarshalPayload(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
+	now := time.Now()
+	op := queue.PendingOperation{
+		IdempotencyKey: key,
+		OperationType:  queue.OpThreadState,
+		PayloadJSON:    payloadJSON,
+		AgentName:      agentName,
+		SessionID:      sessionID,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(client.queueCfg.DefaultTTL),
+	}
+
+	if err := client.queueStore.Enqueue(ctx, op); err != nil {
+		return fmt.Errorf("enqueue thread change: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"queued":          true,
+			"idempotency_key": key,
+		})
+	default:
+		fmt.Printf("Change to thread %s queued (offline)\n", threadID)
+	}
+
+	return nil
+}
+
+// formatThread formats every message of a thread for display, oldest first.
+func formatThread(threadID string, msgs []mail.InboxMessage) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Thread %s (%d messages)\n", threadID, len(msgs))
+	for _, msg := range msgs {
+		sender := msg.SenderName
+		if sender == "" {
+			sender = fmt.Sprintf("Agent#%d", msg.SenderID)
+		}
+
+		sb.WriteString(strings.Repeat("=", 60) + "\n")
+		fmt.Fprintf(&sb, "#%d  %s  %s\n", msg.ID, sender,
+			msg.CreatedAt.Format(time.RFC3339))
+		fmt.Fprintf(&sb, "Subject: %s\n", msg.Subject)
+		if msg.EditedAt != nil {
+			fmt.Fprintf(&sb, "Edited: %s\n",
+				msg.EditedAt.Format(time.RFC3339))
+		}
+		sb.WriteString(strings.Repeat("-", 60) + "\n")
+		sb.WriteString(msg.Body + "\n")
+	}
+
+	return sb.String()
+}
+
+// runThreadPreference handles the `substrate thread mute`, `unmute`,
+// `follow` and `unfollow` commands.
+func runThreadPreference(threadID string, set func(context.Context,
+	*Client, int64) (store.ThreadPreference, error),
+) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if client.Mode() == ModeQueued {
+		return errThreadOffline
+	}
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	pref, err := set(ctx, client, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to update thread: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(pref)
+	default:
+		fmt.Printf("Thread %s is now %s.\n", threadID,
+			describeThreadPreference(pref))
+	}
+
+	return nil
+}
+
+// runThreadList handles the `substrate thread list` command.
+func runThreadList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if client.Mode() == ModeQueued {
+		return errThreadOffline
+	}
+
+	agentID, agentName, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	prefs, err := client.ListThreadPreferences(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to list threads: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(prefs)
+	default:
+		if len(prefs) == 0 {
+			fmt.Printf("%s hasn't muted or followed any threads.\n",
+				agentName)
+			return nil
+		}
+
+		fmt.Printf("Threads for %s (%d):\n\n", agentName, len(prefs))
+		for _, pref := range prefs {
+			fmt.Printf("  %-36s  %s\n", pref.ThreadID,
+				describeThreadPreference(pref))
+		}
+	}
+
+	return nil
+}
+
+// describeThreadPreference summarizes an agent's preference for a thread.
+func describeThreadPreference(pref store.ThreadPreference) string {
+	switch {
+	case pref.Muted:
+		return "muted"
+	case pref.Following:
+		return "followed"
+	default:
+		return "neither muted nor followed"
+	}
+}
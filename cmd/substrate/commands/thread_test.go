@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFormatThread verifies that a thread prints its messages oldest first,
+// naming senders that weren't resolved by ID.
+func TestFormatThread(t *testing.T) {
+	t.Parallel()
+
+	sent := time.Date(2026, 1, 29, 14, 30, 0, 0, time.UTC)
+	out := formatThread("thread-1", []mail.InboxMessage{{
+		ID:         1,
+		SenderName: "Alice",
+		Subject:    "Release plan",
+		Body:       "Shipping on Friday.",
+		CreatedAt:  sent,
+	}, {
+		ID:        2,
+		SenderID:  7,
+		Subject:   "Re: Release plan",
+		Body:      "None from me.",
+		CreatedAt: sent.Add(time.Hour),
+	}})
+
+	require.True(t, strings.HasPrefix(out,
+		"Thread thread-1 (2 messages)\n"))
+	require.Contains(t, out, "#1  Alice  2026-01-29T14:30:00Z\n")
+	require.Contains(t, out, "#2  Agent#7  2026-01-29T15:30:00Z\n")
+	require.Less(t, strings.Index(out, "Shipping on Friday."),
+		strings.Index(out, "None from me."))
+}
+
+// TestThreadStateFunc verifies that queued thread states map back to the
+// client method that applies them, and unknown states are rejected.
+func TestThreadStateFunc(t *testing.T) {
+	t.Parallel()
+
+	for _, state := range []mail.RecipientState{
+		mail.StateArchivedStr, mail.StateUnreadStr, mail.StateTrashStr,
+	} {
+		apply, err := threadStateFunc(state.String())
+		require.NoError(t, err)
+		require.NotNil(t, apply)
+	}
+
+	_, err := threadStateFunc(mail.StateReadStr.String())
+	require.Error(t, err)
+}
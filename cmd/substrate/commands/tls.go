@@ -0,0 +1,278 @@
+package commands
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	subtraterpc "github.com/roasbeef/subtrate/internal/api/grpc"
+	"github.com/roasbeef/subtrate/internal/tlscert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// substrateTLSCertEnv is the environment variable naming the daemon
+	// certificate to pin when --tls-cert isn't given.
+	substrateTLSCertEnv = "SUBSTRATE_TLS_CERT"
+
+	// substrateTLSFingerprintEnv is the environment variable holding the
+	// certificate fingerprint to pin when --tls-fingerprint isn't given.
+	substrateTLSFingerprintEnv = "SUBSTRATE_TLS_FINGERPRINT"
+
+	// substrateTLSEnv is the environment variable that requests TLS, like
+	// --tls, when set to a true value.
+	substrateTLSEnv = "SUBSTRATE_TLS"
+
+	// tlsHandshakeFailed is how gRPC reports a connection that failed
+	// during the TLS handshake.
+	tlsHandshakeFailed = "authentication handshake failed"
+
+	// unixProbeTimeout bounds the check for a daemon serving the default
+	// unix socket.
+	unixProbeTimeout = 200 * time.Millisecond
+
+	// tlsProbeTimeout bounds the check for a daemon serving TLS after a
+	// plaintext connection to it failed.
+	tlsProbeTimeout = time.Second
+)
+
+var (
+	// tlsCertPath is the daemon certificate to pin.
+	tlsCertPath string
+
+	// tlsFingerprint is the daemon certificate fingerprint to pin.
+	tlsFingerprint string
+
+	// useTLS requests TLS pinned to the daemon's generated certificate.
+	useTLS bool
+
+	// noTLS forces a plaintext connection to the daemon.
+	noTLS bool
+)
+
+// discoverUnixSocket returns the dial target of the default unix socket if
+// a daemon is serving it.
+func discoverUnixSocket() (string, bool) {
+	socketPath, err := subtraterpc.DefaultUnixSocketPath()
+	if err != nil {
+		return "", false
+	}
+
+	conn, err := net.DialTimeout("unix", socketPath, unixProbeTimeout)
+	if err != nil {
+		return "", false
+	}
+	conn.Close()
+
+	return subtraterpc.UnixTarget(socketPath), true
+}
+
+// isUnixTarget reports whether a dial target is a unix socket.
+func isUnixTarget(addr string) bool {
+	return strings.HasPrefix(addr, "unix:")
+}
+
+// resolveTLSPin returns the fingerprint of the certificate the daemon at
+// addr must present, or an empty string to connect without TLS. It checks,
+// in order: --tls-fingerprint, $SUBSTRATE_TLS_FINGERPRINT, --tls-cert,
+// $SUBSTRATE_TLS_CERT, and, only when --tls or $SUBSTRATE_TLS requests it,
+// the certificate the daemon generates in ~/.subtrate/tls. Unix sockets and
+// --no-tls never use TLS.
+func resolveTLSPin(addr string) (string, error) {
+	if noTLS && useTLS {
+		return "", NewValidationError(
+			"--tls and --no-tls can't be used together", nil,
+		)
+	}
+	if noTLS || isUnixTarget(addr) {
+		return "", nil
+	}
+
+	fingerprint := tlsFingerprint
+	if fingerprint == "" {
+		fingerprint = os.Getenv(substrateTLSFingerprintEnv)
+	}
+	if fingerprint != "" {
+		pin, err := tlscert.ParseFingerprint(fingerprint)
+		if err != nil {
+			return "", NewValidationError(err.Error(), err)
+		}
+
+		return pin, nil
+	}
+
+	certPath := tlsCertPath
+	if certPath == "" {
+		certPath = os.Getenv(substrateTLSCertEnv)
+	}
+	if certPath != "" {
+		pin, err := tlscert.FingerprintFile(certPath)
+		if err != nil {
+			return "", NewValidationError(
+				fmt.Sprintf("invalid TLS certificate: %v", err),
+				err,
+			)
+		}
+
+		return pin, nil
+	}
+
+	// The generated certificate outlives a daemon restarted without
+	// -tls, so it's only pinned when TLS is asked for.
+	if !tlsRequested() {
+		return "", nil
+	}
+
+	tlsDir, err := tlscert.DefaultDir()
+	if err != nil {
+		return "", NewValidationError(err.Error(), err)
+	}
+	pin, err := tlscert.FingerprintFile(
+		filepath.Join(tlsDir, tlscert.CertFilename),
+	)
+	if err != nil {
+		return "", NewValidationError(
+			fmt.Sprintf("no daemon TLS certificate to pin (pass "+
+				"--tls-cert or --tls-fingerprint): %v", err),
+			err,
+		)
+	}
+
+	return pin, nil
+}
+
+// tlsRequested reports whether --tls or $SUBSTRATE_TLS asks for TLS.
+func tlsRequested() bool {
+	if useTLS {
+		return true
+	}
+
+	requested, err := strconv.ParseBool(os.Getenv(substrateTLSEnv))
+	return err == nil && requested
+}
+
+// transportCredentials returns the credentials for dialing the daemon at
+// addr: TLS pinned to the daemon's certificate if one was resolved,
+// plaintext otherwise.
+func transportCredentials(addr string) (credentials.TransportCredentials,
+	error,
+) {
+	pin, err := resolveTLSPin(addr)
+	if err != nil {
+		return nil, err
+	}
+	if pin == "" {
+		return insecure.NewCredentials(), nil
+	}
+
+	return pinnedCredentials(pin)
+}
+
+// pinnedCredentials returns TLS credentials that only accept a daemon
+// presenting the certificate with the given fingerprint.
+func pinnedCredentials(pin string) (credentials.TransportCredentials,
+	error,
+) {
+	tlsCfg, err := tlscert.PinnedConfig(pin)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(tlsCfg), nil
+}
+
+// detectDaemonTLS checks, after a plaintext connection to the daemon at addr
+// failed, whether the daemon serves TLS instead, since it drops a plaintext
+// client just as if it weren't running. If it does, it returns the
+// fingerprint of the certificate generated in ~/.subtrate/tls to retry with,
+// or an error if there's none to pin. It returns an empty pin and no error
+// if the connection wasn't plaintext or the daemon doesn't serve TLS.
+func detectDaemonTLS(addr string) (string, error) {
+	pin, err := resolveTLSPin(addr)
+	if err != nil || pin != "" || noTLS || isUnixTarget(addr) {
+		return "", nil
+	}
+	if !daemonServesTLS(addr) {
+		return "", nil
+	}
+
+	tlsDir, err := tlscert.DefaultDir()
+	if err == nil {
+		pin, err = tlscert.FingerprintFile(
+			filepath.Join(tlsDir, tlscert.CertFilename),
+		)
+	}
+	if err != nil {
+		return "", &CLIError{
+			Code: ExitAuth,
+			Message: fmt.Sprintf("daemon at %s serves TLS, but "+
+				"there's no certificate to pin (pass "+
+				"--tls-cert or --tls-fingerprint): %v", addr,
+				err),
+			Err: err,
+		}
+	}
+
+	return pin, nil
+}
+
+// daemonServesTLS reports whether the daemon at addr completes a TLS
+// handshake.
+func daemonServesTLS(addr string) bool {
+	dialer := &net.Dialer{Timeout: tlsProbeTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{
+		MinVersion: tls.VersionTLS12,
+
+		// Only whether the daemon speaks TLS matters here. Its
+		// certificate is checked against the pin when connecting
+		// for real.
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return false
+	}
+	conn.Close()
+
+	return true
+}
+
+// tlsHandshakeError returns an error if a failed RPC never got past the TLS
+// handshake, because the daemon presented a certificate other than the
+// pinned one or doesn't serve TLS at all. Either way a daemon is listening,
+// so the failure is reported rather than going around it.
+func tlsHandshakeError(err error) error {
+	if status.Code(err) != codes.Unavailable {
+		return nil
+	}
+
+	msg := status.Convert(err).Message()
+	switch {
+	case strings.Contains(msg, tlscert.ErrFingerprintMismatch.Error()):
+		return &CLIError{
+			Code: ExitAuth,
+			Message: fmt.Sprintf("daemon presented an unexpected "+
+				"TLS certificate (pin it with --tls-cert or "+
+				"--tls-fingerprint): %s", msg),
+			Err: err,
+		}
+
+	case strings.Contains(msg, tlsHandshakeFailed):
+		return &CLIError{
+			Code: ExitAuth,
+			Message: fmt.Sprintf("TLS handshake with the daemon "+
+				"failed (pass --no-tls if it doesn't serve "+
+				"TLS): %s", msg),
+			Err: err,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+
+	subtraterpc "github.com/roasbeef/subtrate/internal/api/grpc"
+	"github.com/roasbeef/subtrate/internal/tlscert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// TestResolveTLSPinDefaultCert tests that the certificate generated in
+// ~/.subtrate/tls is only pinned when TLS is requested, and that requesting
+// TLS without a certificate to pin is an error.
+func TestResolveTLSPinDefaultCert(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(substrateTLSEnv, "")
+	t.Setenv(substrateTLSCertEnv, "")
+	t.Setenv(substrateTLSFingerprintEnv, "")
+	t.Cleanup(func() { useTLS, noTLS = false, false })
+
+	const addr = "localhost:10009"
+
+	// With nothing to pin, requesting TLS fails rather than falling
+	// back to plaintext.
+	useTLS = true
+	_, err := resolveTLSPin(addr)
+	var cliErr *CLIError
+	require.ErrorAs(t, err, &cliErr)
+	require.Equal(t, ExitValidation, cliErr.Code)
+
+	tlsDir := filepath.Join(home, ".subtrate", "tls")
+	cert, err := tlscert.Ensure(
+		filepath.Join(tlsDir, tlscert.CertFilename),
+		filepath.Join(tlsDir, tlscert.KeyFilename), nil,
+	)
+	require.NoError(t, err)
+	fingerprint := tlscert.Fingerprint(cert.Certificate[0])
+
+	// A certificate left behind isn't pinned unless TLS is requested,
+	// by flag or environment.
+	useTLS = false
+	pin, err := resolveTLSPin(addr)
+	require.NoError(t, err)
+	require.Empty(t, pin)
+
+	t.Setenv(substrateTLSEnv, "true")
+	pin, err = resolveTLSPin(addr)
+	require.NoError(t, err)
+	require.Equal(t, fingerprint, pin)
+
+	// Unix sockets never use TLS, and --no-tls can't be combined with
+	// --tls.
+	pin, err = resolveTLSPin("unix:///tmp/substrated.sock")
+	require.NoError(t, err)
+	require.Empty(t, pin)
+
+	useTLS, noTLS = true, true
+	_, err = resolveTLSPin(addr)
+	require.Error(t, err)
+}
+
+// TestTLSHandshakeError tests that failed TLS handshakes are reported as
+// auth errors, while a daemon that isn't running is not.
+func TestTLSHandshakeError(t *testing.T) {
+	t.Parallel()
+
+	mismatch := status.Error(codes.Unavailable, "connection error: "+
+		"transport: authentication handshake failed: "+
+		tlscert.ErrFingerprintMismatch.Error())
+	plaintext := status.Error(codes.Unavailable, "connection error: "+
+		"transport: authentication handshake failed: tls: first "+
+		"record does not look like a TLS handshake")
+	refused := status.Error(codes.Unavailable, "connection error: "+
+		"transport: Error while dialing: dial tcp: connect: "+
+		"connection refused")
+
+	for _, err := range []error{mismatch, plaintext} {
+		var cliErr *CLIError
+		require.ErrorAs(t, tlsHandshakeError(err), &cliErr)
+		require.Equal(t, ExitAuth, cliErr.Code)
+		require.True(t, errors.Is(cliErr.Err, err))
+	}
+
+	require.NoError(t, tlsHandshakeError(refused))
+	require.NoError(t, tlsHandshakeError(
+		status.Error(codes.Internal, "authentication handshake failed"),
+	))
+}
+
+// serveTLSHandshakes accepts connections on lis and completes their TLS
+// handshakes until it's closed.
+func serveTLSHandshakes(lis net.Listener) {
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		if tlsConn, ok := conn.(*tls.Conn); ok {
+			_ = tlsConn.Handshake()
+		}
+		conn.Close()
+	}
+}
+
+// TestDetectDaemonTLS tests that a daemon found serving TLS after a
+// plaintext connection failed is retried pinned to the generated
+// certificate, or reported if there's none to pin.
+func TestDetectDaemonTLS(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(substrateTLSEnv, "")
+	t.Setenv(substrateTLSCertEnv, "")
+	t.Setenv(substrateTLSFingerprintEnv, "")
+
+	certDir := t.TempDir()
+	cert, err := tlscert.Ensure(
+		filepath.Join(certDir, tlscert.CertFilename),
+		filepath.Join(certDir, tlscert.KeyFilename), nil,
+	)
+	require.NoError(t, err)
+
+	tlsLis, err := tls.Listen(
+		"tcp", "127.0.0.1:0", tlscert.ServerConfig(cert),
+	)
+	require.NoError(t, err)
+	defer tlsLis.Close()
+	go serveTLSHandshakes(tlsLis)
+
+	plainLis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer plainLis.Close()
+	go serveTLSHandshakes(plainLis)
+
+	// A daemon that doesn't serve TLS leaves the failure as it was.
+	pin, err := detectDaemonTLS(plainLis.Addr().String())
+	require.NoError(t, err)
+	require.Empty(t, pin)
+
+	// One that does is an auth failure while there's nothing to pin.
+	tlsAddr := tlsLis.Addr().String()
+	_, err = detectDaemonTLS(tlsAddr)
+	var cliErr *CLIError
+	require.ErrorAs(t, err, &cliErr)
+	require.Equal(t, ExitAuth, cliErr.Code)
+
+	// With the generated certificate in place, it's pinned.
+	tlsDir := filepath.Join(home, ".subtrate", "tls")
+	generated, err := tlscert.Ensure(
+		filepath.Join(tlsDir, tlscert.CertFilename),
+		filepath.Join(tlsDir, tlscert.KeyFilename), nil,
+	)
+	require.NoError(t, err)
+	pin, err = detectDaemonTLS(tlsAddr)
+	require.NoError(t, err)
+	require.Equal(t, tlscert.Fingerprint(generated.Certificate[0]), pin)
+}
+
+// listAgentsServer is an agent service that only answers the call used to
+// check that the daemon is up.
+type listAgentsServer struct {
+	subtraterpc.UnimplementedAgentServer
+}
+
+// ListAgents returns no agents.
+func (listAgentsServer) ListAgents(context.Context,
+	*subtraterpc.ListAgentsRequest,
+) (*subtraterpc.ListAgentsResponse, error) {
+	return &subtraterpc.ListAgentsResponse{}, nil
+}
+
+// TestDialDaemonTLS tests that dialing a daemon that only serves TLS retries
+// pinned to the generated certificate once the plaintext attempt fails.
+func TestDialDaemonTLS(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(substrateTLSEnv, "")
+	t.Setenv(substrateTLSCertEnv, "")
+	t.Setenv(substrateTLSFingerprintEnv, "")
+	t.Setenv(substrateTokenEnv, "")
+
+	tlsDir := filepath.Join(home, ".subtrate", "tls")
+	cert, err := tlscert.Ensure(
+		filepath.Join(tlsDir, tlscert.CertFilename),
+		filepath.Join(tlsDir, tlscert.KeyFilename), nil,
+	)
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.Creds(
+		credentials.NewTLS(tlscert.ServerConfig(cert)),
+	))
+	subtraterpc.RegisterAgentServer(server, listAgentsServer{})
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	conn, err := dialDaemon(lis.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = subtraterpc.NewAgentClient(conn).ListAgents(
+		context.Background(), &subtraterpc.ListAgentsRequest{},
+	)
+	require.NoError(t, err)
+}
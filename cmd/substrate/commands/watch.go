@@ -12,8 +12,11 @@ import (
 	"syscall"
 	"time"
 
+	subtraterpc "github.com/roasbeef/subtrate/internal/api/grpc"
 	"github.com/roasbeef/subtrate/internal/mail"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -22,10 +25,17 @@ var (
 	watchCheck     bool
 	watchEvents    string
 	watchMaxMsgs   int
+	watchSaved     string
+	watchPriority  []string
+	watchFrom      []string
+	watchTopics    []string
+	watchThreads   []string
+	watchTakeover  bool
 )
 
 // watchPollInterval is how often the watcher checks for new messages
-// between heartbeats. Kept as a variable so tests can shorten it.
+// between heartbeats when it can't stream them from the daemon. Kept as
+// a variable so tests can shorten it.
 var watchPollInterval = 5 * time.Second
 
 // watchRetryInterval is how long the watcher sleeps after a failed poll
@@ -46,6 +56,24 @@ var ErrWatcherArmed = &CLIError{
 	Message: "another watcher is already armed for this agent",
 }
 
+// errWatchLeaseLost is returned when another watcher took the lease
+// over while this one was parked. The new watcher wakes the agent from
+// then on, so this one must not re-arm.
+var errWatchLeaseLost = &CLIError{
+	Code:    ExitConflict,
+	Message: "watcher lease taken over by another watcher; do not re-arm",
+}
+
+// The events of a watch stream the watcher acts on.
+const (
+	watchWake      = subtraterpc.WatchEventType_WATCH_EVENT_TYPE_WAKE
+	watchLeaseLost = subtraterpc.WatchEventType_WATCH_EVENT_TYPE_LEASE_LOST
+)
+
+// errWatchTimeout is returned by awaitWatchWake when the watch timeout
+// expires before any mail wakes it.
+var errWatchTimeout = errors.New("watch timed out")
+
 // ExitInterrupted is the exit code when the watcher is killed by a
 // signal. It follows the 128+SIGINT convention so a deliberate kill is
 // distinguishable from a wake (0) and from fatal errors (1) — the
@@ -73,24 +101,40 @@ exits. The watcher's stdout carries the new messages plus a re-arm
 instruction.
 
 Behaviors:
+  - Event-driven: connected to the daemon, the watcher streams new
+    mail and wakes as soon as matching mail arrives. With direct
+    database access it polls every few seconds instead.
   - Self-draining: if unread mail already exists on startup, the
     watcher exits immediately with the backlog.
-  - Lease: only one watcher per agent. A second invocation exits with
-    code 5 (conflict) without disturbing the first.
+  - Lease: only one watcher per agent. The daemon holds the lease, so
+    this holds across hosts; with direct database access it's a lock
+    file on this host. A second invocation exits with code 5
+    (conflict) without disturbing the first, unless it passes
+    --takeover, in which case the first exits with code 5 instead.
   - Heartbeats: sends liveness heartbeats while parked, so agent
     status stays accurate without any hook churn.
   - Server-down: retries with backoff internally rather than exiting.
 
 Exit codes:
   0    woke with a digest on stdout (or --timeout expired)
-  1    fatal error (identity resolution, lease I/O)
-  5    another watcher is already armed for this agent
+  1    fatal error (identity resolution, lease I/O, unknown filter)
+  5    another watcher is armed for this agent, or took over the lease
   130  interrupted by signal (not a wake; do not re-arm)
 
+Use --priority, --from, --topic and --thread to wake only for some mail,
+and --saved to wake only for mail matching one of your saved searches
+(see 'substrate searches'). Each flag can be repeated, or take a comma
+separated list, to match any of its values; mail must pass every flag
+given. Other new mail is left for a later watcher.
+
 Use --check to test the lease without arming: exit 0 if a live watcher
 is armed, exit 1 otherwise (for hook scripts).`,
 	Example: `  substrate watch --session-id "$CLAUDE_SESSION_ID"
   substrate watch --session-id "$CLAUDE_SESSION_ID" --timeout 4h
+  substrate watch --session-id "$CLAUDE_SESSION_ID" --saved triage
+  substrate watch --session-id "$CLAUDE_SESSION_ID" --priority urgent \
+    --from Bob --topic deploys
+  substrate watch --session-id "$CLAUDE_SESSION_ID" --takeover
   substrate watch --session-id "$CLAUDE_SESSION_ID" --check`,
 	// Errors are semantic (lease conflict, not-armed); usage spam would
 	// only pollute the agent's context.
@@ -109,6 +153,18 @@ func init() {
 		"Comma-separated event types to wake on (v1: mail only)")
 	watchCmd.Flags().IntVar(&watchMaxMsgs, "max-messages", 10,
 		"Maximum messages included in the wake digest")
+	watchCmd.Flags().StringVar(&watchSaved, "saved", "",
+		"Only wake for mail matching this saved search")
+	watchCmd.Flags().StringSliceVar(&watchPriority, "priority", nil,
+		"Only wake for mail of these priorities (urgent, normal, low)")
+	watchCmd.Flags().StringSliceVar(&watchFrom, "from", nil,
+		"Only wake for mail from these senders")
+	watchCmd.Flags().StringSliceVar(&watchTopics, "topic", nil,
+		"Only wake for mail published to these topics")
+	watchCmd.Flags().StringSliceVar(&watchThreads, "thread", nil,
+		"Only wake for mail in these threads")
+	watchCmd.Flags().BoolVar(&watchTakeover, "takeover", false,
+		"Take the lease over from an armed watcher")
 }
 
 // watchLockDir returns the directory holding watcher lease files,
@@ -259,16 +315,57 @@ func filterFreshMessages(
 	return fresh, maxID
 }
 
+// matchSavedSearch returns the messages one of the agent's saved searches
+// matches, in their original order. A watcher filtering by the saved search
+// only wakes for, and only digests, the matches.
+func matchSavedSearch(ctx context.Context, client *Client, agentID int64,
+	name string, msgs []mail.InboxMessage,
+) ([]mail.InboxMessage, error) {
+	if len(msgs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int64, len(msgs))
+	for i, msg := range msgs {
+		ids[i] = msg.ID
+	}
+
+	resp, err := client.Search(ctx, mail.SearchRequest{
+		AgentID:     agentID,
+		SavedSearch: name,
+		MessageIDs:  ids,
+		Limit:       int32(len(ids)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make(map[int64]bool, len(resp.Hits))
+	for _, hit := range resp.Hits {
+		matched[hit.ID] = true
+	}
+
+	var kept []mail.InboxMessage
+	for _, msg := range msgs {
+		if matched[msg.ID] {
+			kept = append(kept, msg)
+		}
+	}
+
+	return kept, nil
+}
+
 // acquireWatchLease claims the watcher lease for an agent by writing
-// this process's PID. Stale leases (dead PID) are reclaimed. Returns a
-// release function, or ErrWatcherArmed if a live watcher exists.
-func acquireWatchLease(agentID int64) (func(), error) {
+// this process's PID. Stale leases (dead PID) are reclaimed, and with
+// takeover so are live ones. Returns a release function, or
+// ErrWatcherArmed if a live watcher exists.
+func acquireWatchLease(agentID int64, takeover bool) (func(), error) {
 	path, err := watchLockPath(agentID)
 	if err != nil {
 		return nil, err
 	}
 
-	if pidAlive(readLeasePID(path)) {
+	if !takeover && pidAlive(readLeasePID(path)) {
 		return nil, ErrWatcherArmed
 	}
 
@@ -289,6 +386,86 @@ func acquireWatchLease(agentID int64) (func(), error) {
 	return release, nil
 }
 
+// holdsWatchLease reports whether this process still holds the watcher
+// lease for an agent, i.e. no other watcher took it over.
+func holdsWatchLease(agentID int64) bool {
+	path, err := watchLockPath(agentID)
+	if err != nil {
+		return false
+	}
+
+	return readLeasePID(path) == os.Getpid()
+}
+
+// watchHolder identifies this watcher to the daemon, by host and PID.
+func watchHolder() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// resolveWatchFilter builds the wake filter from the filter flags,
+// resolving sender and topic names. The daemon resolves them itself, so
+// only the polling watcher needs this.
+func resolveWatchFilter(ctx context.Context,
+	client *Client,
+) (mail.WatchFilter, error) {
+	filter := mail.WatchFilter{ThreadIDs: watchThreads}
+	for _, p := range watchPriority {
+		filter.Priorities = append(filter.Priorities, mail.Priority(p))
+	}
+
+	for _, name := range watchFrom {
+		a, err := client.GetAgentByName(ctx, name)
+		if err != nil {
+			return filter, fmt.Errorf("unknown sender %q: %w", name,
+				err)
+		}
+		filter.SenderIDs = append(filter.SenderIDs, a.ID)
+	}
+
+	if len(watchTopics) > 0 {
+		topics, err := client.ListTopics(ctx)
+		if err != nil {
+			return filter, fmt.Errorf("failed to list topics: %w",
+				err)
+		}
+		ids := make(map[string]int64, len(topics))
+		for _, t := range topics {
+			ids[t.Name] = t.ID
+		}
+
+		for _, name := range watchTopics {
+			id, ok := ids[name]
+			if !ok {
+				return filter, fmt.Errorf("unknown topic %q",
+					name)
+			}
+			filter.TopicIDs = append(filter.TopicIDs, id)
+		}
+	}
+
+	return filter, nil
+}
+
+// filterWatchMessages returns the messages that pass the wake filter,
+// in their original order.
+func filterWatchMessages(msgs []mail.InboxMessage,
+	filter mail.WatchFilter,
+) []mail.InboxMessage {
+	var kept []mail.InboxMessage
+	for _, msg := range msgs {
+		if filter.Matches(msg) {
+			kept = append(kept, msg)
+		}
+	}
+
+	return kept
+}
+
 // runWatch implements the watch command. It arms the lease, parks until
 // a mail event (or timeout), prints a wake digest, and exits.
 func runWatch(cmd *cobra.Command, args []string) error {
@@ -313,37 +490,254 @@ func runWatch(cmd *cobra.Command, args []string) error {
 	// --check: report lease state without arming. Exit 0 if armed,
 	// exit 1 (via error) otherwise, so hook scripts can branch on it.
 	if watchCheck {
-		armed, err := watcherArmed(agentID)
+		return checkWatcher(ctx, client, agentID, agentNameStr)
+	}
+
+	for _, p := range watchPriority {
+		err := validateEnum(
+			p, "priority", []string{"urgent", "normal", "low"},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// A saved search filter that doesn't run now never will, so fail
+	// fast rather than park forever.
+	if watchSaved != "" {
+		_, err := client.Search(ctx, mail.SearchRequest{
+			AgentID:     agentID,
+			SavedSearch: watchSaved,
+			Limit:       1,
+		})
+		if err != nil {
+			return fmt.Errorf("invalid --saved filter: %w", err)
+		}
+	}
+
+	var deadline time.Time
+	if watchTimeout > 0 {
+		deadline = time.Now().Add(watchTimeout)
+	}
+
+	// Connected to the daemon, it holds the lease and streams the mail
+	// that wakes us. Otherwise fall back to polling the database.
+	if client.Mode() == ModeGRPC {
+		return runWatchStream(ctx, client, agentID, agentNameStr,
+			deadline)
+	}
+
+	return runWatchPoll(ctx, client, agentID, agentNameStr, deadline)
+}
+
+// checkWatcher reports whether a watcher is armed for the agent, asking
+// the daemon if connected to one and checking the lock file otherwise.
+func checkWatcher(ctx context.Context, client *Client, agentID int64,
+	agentName string,
+) error {
+	if client.Mode() == ModeGRPC {
+		holder, _, armed, err := client.GetWatcher(ctx, agentID)
 		if err != nil {
 			return err
 		}
 
 		if armed {
-			fmt.Printf("armed (agent %s)\n", agentNameStr)
+			fmt.Printf("armed (agent %s, held by %s)\n", agentName,
+				holder)
 			return nil
 		}
 
-		return fmt.Errorf("not armed (agent %s)", agentNameStr)
+		return fmt.Errorf("not armed (agent %s)", agentName)
 	}
 
-	release, err := acquireWatchLease(agentID)
+	armed, err := watcherArmed(agentID)
 	if err != nil {
 		return err
 	}
-	defer release()
+
+	if armed {
+		fmt.Printf("armed (agent %s)\n", agentName)
+		return nil
+	}
+
+	return fmt.Errorf("not armed (agent %s)", agentName)
+}
+
+// runWatchStream parks on the daemon's WatchInbox stream until mail
+// passing the filters arrives, then prints the wake digest. The daemon
+// holds the agent's watcher lease for as long as the stream is open.
+func runWatchStream(ctx context.Context, client *Client, agentID int64,
+	agentName string, deadline time.Time,
+) error {
+	req := WatchRequest{
+		AgentID:     agentID,
+		Holder:      watchHolder(),
+		SenderNames: watchFrom,
+		TopicNames:  watchTopics,
+		ThreadIDs:   watchThreads,
+		SavedSearch: watchSaved,
+		Takeover:    watchTakeover,
+	}
+	for _, p := range watchPriority {
+		req.Priorities = append(req.Priorities, mail.Priority(p))
+	}
 
 	// Initial heartbeat marks the agent active immediately.
 	_ = client.UpdateHeartbeat(ctx, agentID)
 
-	var deadline time.Time
-	if watchTimeout > 0 {
-		deadline = time.Now().Add(watchTimeout)
+	for {
+		// Mail at or below the watermark was already delivered in a
+		// prior wake digest, so it doesn't wake the agent again.
+		req.AfterMessageID = readWatchWatermark(agentID)
+
+		msgs, err := awaitWatchWake(ctx, client, req, deadline)
+		switch {
+		case err == nil:
+			// A repushed message, such as one whose snooze
+			// ended, may be older than the watermark, which
+			// mustn't move back.
+			_, maxID := filterFreshMessages(msgs, 0)
+			writeWatchWatermark(
+				agentID, max(req.AfterMessageID, maxID),
+			)
+			fmt.Print(formatWatchDigest(
+				msgs, agentName, watchMaxMsgs,
+			))
+			return nil
+
+		case errors.Is(err, errWatchTimeout):
+			fmt.Print(formatWatchTimeout(agentName, watchTimeout))
+			return nil
+
+		case ctx.Err() != nil:
+			return errWatchInterrupted
+
+		case errors.Is(err, errWatchLeaseLost):
+			return errWatchLeaseLost
+		}
+
+		switch status.Code(err) {
+		case codes.AlreadyExists:
+			return ErrWatcherArmed
+
+		case codes.InvalidArgument, codes.NotFound:
+			return fmt.Errorf("invalid watch filter: %w", err)
+		}
+
+		// Transient failure (daemon restarting): park and retry. A
+		// re-arm only takes back our own lease, not one another
+		// watcher has armed since.
+		req.Takeover = false
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			fmt.Print(formatWatchTimeout(agentName, watchTimeout))
+			return nil
+		}
+		if !sleepCtx(ctx, watchRetryInterval) {
+			return errWatchInterrupted
+		}
 	}
+}
+
+// awaitWatchWake arms a watcher over the daemon's WatchInbox stream and
+// waits for it to wake, returning the mail that woke it. It heartbeats
+// while parked, and returns errWatchTimeout once the deadline passes or
+// errWatchLeaseLost if another watcher takes the lease over.
+func awaitWatchWake(ctx context.Context, client *Client, req WatchRequest,
+	deadline time.Time,
+) ([]mail.InboxMessage, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.WatchInbox(streamCtx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan WatchEvent)
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case events <- event:
+			case <-streamCtx.Done():
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(max(watchHeartbeat, time.Second))
+	defer heartbeat.Stop()
+
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case <-timeout:
+			return nil, errWatchTimeout
+
+		case <-heartbeat.C:
+			_ = client.UpdateHeartbeat(ctx, req.AgentID)
+
+		case err := <-errs:
+			return nil, err
+
+		case event := <-events:
+			switch event.Type {
+			case watchWake:
+				return event.Messages, nil
+
+			case watchLeaseLost:
+				return nil, errWatchLeaseLost
+			}
+		}
+	}
+}
+
+// runWatchPoll parks by polling the inbox until mail passing the filters
+// arrives, then prints the wake digest. It's the fallback for direct
+// database access, where there's no daemon to stream from, and holds
+// the lease as a lock file on this host.
+func runWatchPoll(ctx context.Context, client *Client, agentID int64,
+	agentName string, deadline time.Time,
+) error {
+	filter, err := resolveWatchFilter(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	release, err := acquireWatchLease(agentID, watchTakeover)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// Initial heartbeat marks the agent active immediately.
+	_ = client.UpdateHeartbeat(ctx, agentID)
 
 	lastHeartbeat := time.Now()
 	watermark := readWatchWatermark(agentID)
 
 	for {
+		// A watcher that took the lease over wakes the agent from
+		// now on.
+		if !holdsWatchLease(agentID) {
+			return errWatchLeaseLost
+		}
+
 		// Self-draining check: unread mail newer than the digest
 		// watermark ends the park immediately, including backlog
 		// that arrived while no watcher was armed. Messages at or
@@ -353,19 +747,23 @@ func runWatch(cmd *cobra.Command, args []string) error {
 		// the mail read would re-arm into an instant, unbounded
 		// wake loop on the same backlog.
 		msgs, _, err := client.PollChanges(ctx, agentID, nil)
+		if err == nil {
+			msgs, _ = filterFreshMessages(msgs, watermark)
+			msgs = filterWatchMessages(msgs, filter)
+		}
+		if err == nil && watchSaved != "" {
+			msgs, err = matchSavedSearch(
+				ctx, client, agentID, watchSaved, msgs,
+			)
+		}
 		switch {
 		case err == nil && len(msgs) > 0:
-			fresh, maxID := filterFreshMessages(
-				msgs, watermark,
-			)
-			if len(fresh) > 0 {
-				writeWatchWatermark(agentID, maxID)
-				fmt.Print(formatWatchDigest(
-					fresh, agentNameStr, watchMaxMsgs,
-				))
-				return nil
-			}
-			// Only already-digested backlog: keep parking.
+			_, maxID := filterFreshMessages(msgs, watermark)
+			writeWatchWatermark(agentID, maxID)
+			fmt.Print(formatWatchDigest(
+				msgs, agentName, watchMaxMsgs,
+			))
+			return nil
 
 		case err != nil:
 			// Transient failure (server down, DB busy): park
@@ -382,7 +780,7 @@ func runWatch(cmd *cobra.Command, args []string) error {
 		// the lease on its own schedule.
 		if !deadline.IsZero() && time.Now().After(deadline) {
 			fmt.Print(formatWatchTimeout(
-				agentNameStr, watchTimeout,
+				agentName, watchTimeout,
 			))
 			return nil
 		}
@@ -499,3 +897,129 @@ func watchRearmFooter() string {
    via the Bash tool with run_in_background: true, then end your turn.
 `, sidArg, sidArg, sidArg)
 }
+
+// =============================================================================
+// Client methods for watching
+// =============================================================================
+
+// WatchRequest arms a watcher on an agent's inbox over the daemon's
+// WatchInbox stream.
+type WatchRequest struct {
+	// AgentID is the agent whose inbox is watched.
+	AgentID int64
+
+	// Holder identifies the watcher to the daemon, such as its host and
+	// PID.
+	Holder string
+
+	// AfterMessageID is the newest message the agent was already woken
+	// for. Only newer mail wakes the watcher.
+	AfterMessageID int64
+
+	// Priorities, SenderNames, TopicNames and ThreadIDs are the wake
+	// filters. Each empty one matches any message.
+	Priorities  []mail.Priority
+	SenderNames []string
+	TopicNames  []string
+	ThreadIDs   []string
+
+	// SavedSearch names a saved search the mail must also match.
+	SavedSearch string
+
+	// LeaseTTL is how long the lease lasts unrenewed, or the daemon's
+	// default if zero.
+	LeaseTTL time.Duration
+
+	// Takeover takes the lease from another armed watcher.
+	Takeover bool
+}
+
+// WatchEvent is an event on a watch stream.
+type WatchEvent struct {
+	// Type is the kind of event.
+	Type subtraterpc.WatchEventType
+
+	// Messages is the mail that woke the watcher, for wake events.
+	Messages []mail.InboxMessage
+
+	// LeaseExpiresAt is when the lease lapses unless renewed, for armed
+	// events.
+	LeaseExpiresAt time.Time
+}
+
+// WatchStream is an armed watcher's stream of events.
+type WatchStream struct {
+	stream subtraterpc.Mail_WatchInboxClient
+}
+
+// Recv waits for the next event on the stream.
+func (w *WatchStream) Recv() (WatchEvent, error) {
+	event, err := w.stream.Recv()
+	if err != nil {
+		return WatchEvent{}, err
+	}
+
+	return WatchEvent{
+		Type:           event.Type,
+		Messages:       convertProtoMessagesToMail(event.Messages),
+		LeaseExpiresAt: timestampToTime(event.LeaseExpiresAt),
+	}, nil
+}
+
+// WatchInbox arms a watcher on an agent's inbox, streaming the events of
+// the watch until the context is cancelled. It needs a connection to the
+// daemon, which holds the watcher lease.
+func (c *Client) WatchInbox(ctx context.Context,
+	req WatchRequest,
+) (*WatchStream, error) {
+	if c.mode != ModeGRPC {
+		return nil, fmt.Errorf("watch streams need a connection " +
+			"to the daemon")
+	}
+
+	priorities := make([]subtraterpc.Priority, len(req.Priorities))
+	for i, p := range req.Priorities {
+		priorities[i] = convertPriorityToProto(p)
+	}
+
+	stream, err := c.mailClient.WatchInbox(
+		ctx, &subtraterpc.WatchInboxRequest{
+			AgentId:         req.AgentID,
+			Holder:          req.Holder,
+			AfterMessageId:  req.AfterMessageID,
+			Priorities:      priorities,
+			SenderNames:     req.SenderNames,
+			TopicNames:      req.TopicNames,
+			ThreadIds:       req.ThreadIDs,
+			SavedSearch:     req.SavedSearch,
+			LeaseTtlSeconds: int32(req.LeaseTTL / time.Second),
+			Takeover:        req.Takeover,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WatchStream{stream: stream}, nil
+}
+
+// GetWatcher returns the watcher holding the daemon's watcher lease on an
+// agent's inbox and when it expires, or false if none is armed.
+func (c *Client) GetWatcher(ctx context.Context,
+	agentID int64,
+) (string, time.Time, bool, error) {
+	if c.mode != ModeGRPC {
+		return "", time.Time{}, false, fmt.Errorf("watcher leases " +
+			"are held by the daemon")
+	}
+
+	resp, err := c.mailClient.GetWatcher(
+		ctx, &subtraterpc.GetWatcherRequest{AgentId: agentID},
+	)
+	if err != nil {
+		return "", time.Time{}, false, err
+	}
+
+	return resp.Holder, timestampToTime(resp.LeaseExpiresAt),
+		resp.Armed, nil
+}
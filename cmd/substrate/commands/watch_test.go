@@ -29,7 +29,7 @@ func withTempHome(t *testing.T) string {
 func TestWatchLeaseAcquireRelease(t *testing.T) {
 	withTempHome(t)
 
-	release, err := acquireWatchLease(42)
+	release, err := acquireWatchLease(42, false)
 	require.NoError(t, err)
 
 	path, err := watchLockPath(42)
@@ -71,7 +71,7 @@ func TestWatchLeaseConflict(t *testing.T) {
 	)
 	require.NoError(t, err)
 
-	_, err = acquireWatchLease(7)
+	_, err = acquireWatchLease(7, false)
 	require.ErrorIs(t, err, ErrWatcherArmed)
 
 	cliErr, ok := err.(*CLIError)
@@ -98,7 +98,7 @@ func TestWatchLeaseStaleReclaim(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, armed, "dead PID should not count as armed")
 
-	release, err := acquireWatchLease(9)
+	release, err := acquireWatchLease(9, false)
 	require.NoError(t, err)
 	defer release()
 
@@ -111,7 +111,7 @@ func TestWatchLeaseStaleReclaim(t *testing.T) {
 func TestWatchLeaseReleaseRespectsSuccessor(t *testing.T) {
 	withTempHome(t)
 
-	release, err := acquireWatchLease(11)
+	release, err := acquireWatchLease(11, false)
 	require.NoError(t, err)
 
 	// Simulate a successor overwriting the lease with another PID.
@@ -126,6 +126,41 @@ func TestWatchLeaseReleaseRespectsSuccessor(t *testing.T) {
 	require.Equal(t, 999999, readLeasePID(path))
 }
 
+// TestWatchLeaseTakeover verifies that --takeover claims a live lease,
+// and that the watcher it was taken from sees it no longer holds it.
+func TestWatchLeaseTakeover(t *testing.T) {
+	withTempHome(t)
+
+	cmd := exec.Command("sleep", "60")
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_, _ = cmd.Process.Wait()
+	})
+
+	path, err := watchLockPath(13)
+	require.NoError(t, err)
+	err = os.WriteFile(
+		path, []byte(strconv.Itoa(cmd.Process.Pid)), 0o644,
+	)
+	require.NoError(t, err)
+	require.False(t, holdsWatchLease(13))
+
+	release, err := acquireWatchLease(13, true)
+	require.NoError(t, err)
+	require.True(t, holdsWatchLease(13))
+
+	// Taken back over by another watcher.
+	err = os.WriteFile(
+		path, []byte(strconv.Itoa(cmd.Process.Pid)), 0o644,
+	)
+	require.NoError(t, err)
+	require.False(t, holdsWatchLease(13))
+
+	release()
+	require.Equal(t, cmd.Process.Pid, readLeasePID(path))
+}
+
 // TestReadLeasePIDMalformed verifies malformed or missing lease files
 // read as PID 0 (not armed).
 func TestReadLeasePIDMalformed(t *testing.T) {
@@ -197,6 +232,27 @@ func TestFilterFreshMessages(t *testing.T) {
 	require.Equal(t, int64(0), maxID)
 }
 
+// TestFilterWatchMessages verifies the polling watcher only wakes for
+// mail passing the filter flags, keeping its order.
+func TestFilterWatchMessages(t *testing.T) {
+	msgs := []mail.InboxMessage{
+		{ID: 1, SenderID: 2, Priority: mail.PriorityUrgent},
+		{ID: 2, SenderID: 3, Priority: mail.PriorityUrgent},
+		{ID: 3, SenderID: 2, Priority: mail.PriorityLow},
+		{ID: 4, SenderID: 2, Priority: mail.PriorityUrgent},
+	}
+
+	require.Equal(t, msgs, filterWatchMessages(msgs, mail.WatchFilter{}))
+
+	kept := filterWatchMessages(msgs, mail.WatchFilter{
+		Priorities: []mail.Priority{mail.PriorityUrgent},
+		SenderIDs:  []int64{2},
+	})
+	require.Len(t, kept, 2)
+	require.Equal(t, int64(1), kept[0].ID)
+	require.Equal(t, int64(4), kept[1].ID)
+}
+
 // TestErrWatchInterruptedExitCode verifies the signal-interrupt error
 // carries the 128+SIGINT exit code so a kill is distinguishable from a
 // wake (0) and a fatal error (1).
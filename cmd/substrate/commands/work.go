@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// workCmd is the parent command for consuming queue topics.
+var workCmd = &cobra.Command{
+	Use:   "work",
+	Short: "Claim and complete messages from queue topics",
+	Long: `Consume work from queue topics with competing-consumer semantics.
+
+Each message published to a queue topic is delivered to exactly one
+subscriber. Claiming a message leases it to the current agent and hides it
+from other consumers until the visibility timeout passes. Ack the message
+once it has been handled, or release it to hand it back to the queue. Leases
+that expire without an ack are redelivered, and a message that fails too
+many deliveries is moved to the <topic>/dead-letter topic.`,
+}
+
+// workClaimCmd claims the next message from a queue topic.
+var workClaimCmd = &cobra.Command{
+	Use:   "claim <topic>",
+	Short: "Claim the next message from a queue topic",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWorkClaim,
+}
+
+// workAckCmd acks a claimed queue message.
+var workAckCmd = &cobra.Command{
+	Use:   "ack <message_id>",
+	Short: "Complete a claimed queue message",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWorkAck,
+}
+
+// workReleaseCmd releases a claimed queue message.
+var workReleaseCmd = &cobra.Command{
+	Use:   "release <message_id>",
+	Short: "Hand a claimed queue message back to the queue",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWorkRelease,
+}
+
+// workExtendCmd extends the lease on a claimed queue message.
+var workExtendCmd = &cobra.Command{
+	Use:   "extend <message_id>",
+	Short: "Extend the lease on a claimed queue message",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWorkExtend,
+}
+
+// Work command flags.
+var (
+	workVisibility    time.Duration
+	workReleaseReason string
+)
+
+func init() {
+	workClaimCmd.Flags().DurationVar(
+		&workVisibility, "visibility", 0,
+		"How long the message stays leased (default: server setting)",
+	)
+	workExtendCmd.Flags().DurationVar(
+		&workVisibility, "visibility", 0,
+		"New lease duration from now (default: server setting)",
+	)
+	workReleaseCmd.Flags().StringVar(
+		&workReleaseReason, "reason", "",
+		"Why the message couldn't be handled",
+	)
+
+	workCmd.AddCommand(workClaimCmd)
+	workCmd.AddCommand(workAckCmd)
+	workCmd.AddCommand(workReleaseCmd)
+	workCmd.AddCommand(workExtendCmd)
+}
+
+// runWorkClaim handles the `substrate work claim` command.
+func runWorkClaim(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	topicName := args[0]
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	claim, err := client.ClaimQueueMessage(
+		ctx, agentID, topicName, workVisibility,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to claim: %w", err)
+	}
+
+	switch outputFormat {
+	case "json":
+		if claim == nil {
+			return outputJSON(map[string]any{
+				"topic":   topicName,
+				"claimed": false,
+			})
+		}
+		return outputJSON(map[string]any{
+			"topic":            topicName,
+			"claimed":          true,
+			"message":          claim.Message,
+			"lease_expires_at": claim.LeaseExpiresAt,
+			"delivery_count":   claim.DeliveryCount,
+		})
+	default:
+		if claim == nil {
+			fmt.Printf("No messages waiting on %s.\n", topicName)
+			return nil
+		}
+
+		fmt.Printf("Claimed message %d from %s (delivery %d, lease "+
+			"expires in %s)\n\n", claim.Message.ID, topicName,
+			claim.DeliveryCount,
+			formatDuration(time.Until(claim.LeaseExpiresAt)))
+		fmt.Print(formatMessageFull(&claim.Message))
+	}
+
+	return nil
+}
+
+// leaseOp runs an operation on the current agent's lease on a queue message
+// and returns a human readable summary of the result.
+type leaseOp func(ctx context.Context, client *Client, agentID,
+	msgID int64) (string, error)
+
+// runWorkAck handles the `substrate work ack` command.
+func runWorkAck(cmd *cobra.Command, args []string) error {
+	return runWorkLeaseOp(args, "acked", func(ctx context.Context,
+		client *Client, agentID, msgID int64) (string, error) {
+
+		err := client.AckQueueMessage(ctx, agentID, msgID)
+		return fmt.Sprintf("Message %d acked.", msgID), err
+	})
+}
+
+// runWorkRelease handles the `substrate work release` command.
+func runWorkRelease(cmd *cobra.Command, args []string) error {
+	return runWorkLeaseOp(args, "released", func(ctx context.Context,
+		client *Client, agentID, msgID int64) (string, error) {
+
+		err := client.ReleaseQueueMessage(
+			ctx, agentID, msgID, workReleaseReason,
+		)
+		return fmt.Sprintf("Message %d released.", msgID), err
+	})
+}
+
+// runWorkExtend handles the `substrate work extend` command.
+func runWorkExtend(cmd *cobra.Command, args []string) error {
+	return runWorkLeaseOp(args, "extended", func(ctx context.Context,
+		client *Client, agentID, msgID int64) (string, error) {
+
+		expiresAt, err := client.ExtendQueueLease(
+			ctx, agentID, msgID, workVisibility,
+		)
+		return fmt.Sprintf("Lease on message %d now expires in %s.",
+			msgID, formatDuration(time.Until(expiresAt))), err
+	})
+}
+
+// runWorkLeaseOp parses the message ID argument, runs op against the
+// current agent's lease and reports the result.
+func runWorkLeaseOp(args []string, status string, op leaseOp) error {
+	ctx := context.Background()
+
+	msgID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid message ID: %w", err)
+	}
+
+	client, err := getClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	agentID, _, err := getCurrentAgentWithClient(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	summary, err := op(ctx, client, agentID, msgID)
+	if err != nil {
+		return err
+	}
+
+	switch outputFormat {
+	case "json":
+		return outputJSON(map[string]any{
+			"message_id": msgID,
+			"status":     status,
+		})
+	default:
+		fmt.Println(summary)
+	}
+
+	return nil
+}
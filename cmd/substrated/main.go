@@ -2,13 +2,17 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"flag"
+	"fmt"
 	"io"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
@@ -17,15 +21,21 @@ import (
 	"github.com/roasbeef/subtrate/internal/activity"
 	"github.com/roasbeef/subtrate/internal/agent"
 	subtraterpc "github.com/roasbeef/subtrate/internal/api/grpc"
+	"github.com/roasbeef/subtrate/internal/auth"
 	"github.com/roasbeef/subtrate/internal/baselib/actor"
 	"github.com/roasbeef/subtrate/internal/build"
 	"github.com/roasbeef/subtrate/internal/db"
+	"github.com/roasbeef/subtrate/internal/embed"
 	"github.com/roasbeef/subtrate/internal/mail"
 	"github.com/roasbeef/subtrate/internal/mcp"
+	"github.com/roasbeef/subtrate/internal/ratelimit"
 	"github.com/roasbeef/subtrate/internal/review"
+	"github.com/roasbeef/subtrate/internal/search"
 	"github.com/roasbeef/subtrate/internal/store"
 	"github.com/roasbeef/subtrate/internal/summary"
+	"github.com/roasbeef/subtrate/internal/tlscert"
 	"github.com/roasbeef/subtrate/internal/web"
+	"google.golang.org/grpc/credentials"
 )
 
 func main() {
@@ -37,9 +47,65 @@ func main() {
 		logDir         = flag.String("log-dir", "~/.subtrate/logs", "Directory for log files (empty to disable file logging)")
 		maxLogFiles    = flag.Int("max-log-files", build.DefaultMaxLogFiles, "Maximum number of rotated log files to keep")
 		maxLogFileSize = flag.Int("max-log-file-size", build.DefaultMaxLogFileSize, "Maximum log file size in MB before rotation")
+		deadlineCheck  = flag.Duration("deadline-check-interval", mail.DefaultDeadlineCheckInterval, "How often to check for overdue messages (0 to disable escalation)")
+		escalationSpec = flag.String("escalation-policy", "", "Deadline escalation chain, e.g. \"0s:renotify+activity,30m:priority,2h:fallback\" (empty for the default chain)")
+		escalationTo   = flag.String("escalation-fallback", mail.DefaultEscalationFallbackAgent, "Agent copied on overdue messages by the fallback escalation action")
+		queueLeaseTime = flag.Duration("queue-visibility-timeout", mail.DefaultQueueVisibilityTimeout, "Default lease duration for claimed queue messages")
+		queueMaxTries  = flag.Int("queue-max-deliveries", mail.DefaultQueueMaxDeliveries, "Deliveries a queue message gets before moving to its dead-letter topic")
+		queueReapEvery = flag.Duration("queue-reap-interval", mail.DefaultQueueReapInterval, "How often expired queue leases are redelivered or dead-lettered")
+		isolateProject = flag.Bool("isolate-projects", false, "Only deliver mail between agents of the same project; agents with no project, like the User, can mail and be mailed by anyone")
+		retentionTick  = flag.Duration("retention-interval", 0, "How often expired messages are deleted per topic retention (0 disables the compactor)")
+		searchWeight   = flag.Float64("search-subject-weight", search.DefaultSubjectWeight, "How much more a search term matched in a message's subject counts towards its rank than one matched in its body")
+		scheduleTick   = flag.Duration("schedule-interval", mail.DefaultScheduledSendInterval, "How often due scheduled messages and recurring schedules are sent and question timeouts are handled")
+		embedderKind   = flag.String("embedder", "hash", "Embedding provider for semantic search: \"hash\" (local hashed n-grams) or \"http\" (an OpenAI-compatible endpoint, key from $SUBSTRATE_EMBEDDER_API_KEY)")
+		embedderURL    = flag.String("embedder-url", "", "Embeddings endpoint for the http embedder, e.g. https://api.openai.com/v1/embeddings")
+		embedderModel  = flag.String("embedder-model", "", "Model the http embedder embeds with")
+		embedTick      = flag.Duration("embedding-interval", mail.DefaultEmbeddingInterval, "How often messages missed by the embedding indexer are embedded")
+		hybridWeight   = flag.Float64("semantic-hybrid-weight", mail.DefaultSemanticHybridWeight, "How much similarity counts against BM25 rank in hybrid search, from 0 to 1")
+		requireAuth    = flag.Bool("auth", false, "Require a bearer credential on every gRPC, REST and WebSocket request (mint them with \"substrate auth mint\")")
+		adminTokenFile = flag.String("admin-token-file", "~/.subtrate/admin.token", "File holding the admin credential, created when -auth is set and it is missing")
+		grpcUnix       = flag.String("grpc-unix", "", "Unix socket to also serve gRPC on, only accessible to this user, e.g. ~/.subtrate/substrated.sock, where the CLI looks for it (empty to disable)")
+		enableTLS      = flag.Bool("tls", false, "Serve gRPC and the web UI over TLS, with the certificate from -tls-cert or one generated in -tls-dir")
+		tlsDir         = flag.String("tls-dir", "~/.subtrate/tls", "Directory holding the generated tls.cert and tls.key, created on first start with -tls")
+		tlsCertPath    = flag.String("tls-cert", "", "TLS certificate to serve instead of a generated one (requires -tls-key)")
+		tlsKeyPath     = flag.String("tls-key", "", "TLS private key for -tls-cert")
+		tlsExtraHosts  = flag.String("tls-extra-hosts", "", "Comma-separated extra DNS names and IPs a generated certificate covers, besides localhost and the hostname")
 	)
 	flag.Parse()
 
+	escalationPolicy := mail.DefaultEscalationPolicy()
+	escalationPolicy.FallbackAgent = *escalationTo
+	if *escalationSpec != "" {
+		var err error
+		escalationPolicy, err = mail.ParseEscalationPolicy(
+			*escalationSpec, *escalationTo,
+		)
+		if err != nil {
+			log.Fatalf("Invalid escalation policy: %v", err)
+		}
+	}
+
+	var embedder embed.Embedder
+	switch *embedderKind {
+	case "hash":
+		embedder = embed.NewHashEmbedder(embed.DefaultDimensions)
+
+	case "http":
+		httpEmbedder, err := embed.NewHTTPEmbedder(embed.HTTPConfig{
+			URL:    *embedderURL,
+			Model:  *embedderModel,
+			APIKey: os.Getenv("SUBSTRATE_EMBEDDER_API_KEY"),
+		})
+		if err != nil {
+			log.Fatalf("Invalid embedder: %v", err)
+		}
+		embedder = httpEmbedder
+
+	default:
+		log.Fatalf("Unknown embedder %q (want hash or http)",
+			*embedderKind)
+	}
+
 	// Expand home directory in paths.
 	expandHome := func(path string) string {
 		expanded := os.ExpandEnv(path)
@@ -192,11 +258,22 @@ func main() {
 	)
 	log.Println("NotificationHub actor started")
 
+	var recipientPolicy mail.RecipientPolicy
+	if *isolateProject {
+		recipientPolicy = mail.ProjectIsolationPolicy
+	}
+
 	// Create the mail service with the notification hub reference.
 	// This enables real-time notifications when messages are sent.
 	mailSvc := mail.NewService(mail.ServiceConfig{
-		Store:           storage,
-		NotificationHub: notificationHub,
+		Store:                  storage,
+		NotificationHub:        notificationHub,
+		QueueVisibilityTimeout: *queueLeaseTime,
+		QueueMaxDeliveries:     *queueMaxTries,
+		RecipientPolicy:        recipientPolicy,
+		SearchSubjectWeight:    *searchWeight,
+		Embedder:               embedder,
+		SemanticHybridWeight:   *hybridWeight,
 	})
 
 	// Register the mail service as an actor.
@@ -208,6 +285,17 @@ func main() {
 	)
 	log.Println("Mail actor started with NotificationHub integration")
 
+	// Re-arm wake timers for snoozed messages (restart recovery). Any
+	// snooze that expired while the daemon was down wakes immediately.
+	numSnoozed, err := mailSvc.RecoverSnoozedMessages(context.Background())
+	if err != nil {
+		log.Printf(
+			"Warning: failed to recover snoozed messages: %v", err,
+		)
+	}
+	log.Printf("Snooze wake scheduler started (%d wakes recovered)",
+		numSnoozed)
+
 	// Create and register the activity actor.
 	activitySvc := activity.NewService(activity.ServiceConfig{
 		Store: storage,
@@ -227,6 +315,7 @@ func main() {
 	reviewSvc := review.NewService(review.ServiceConfig{
 		Store:       storage,
 		ActorSystem: actorSystem,
+		RateLimiter: ratelimit.NewLimiter(storage),
 	})
 	reviewRef := actor.RegisterWithSystem(
 		actorSystem,
@@ -288,14 +377,62 @@ func main() {
 		os.Exit(1)
 	}()
 
+	// With auth enabled, make sure there is an admin credential for the
+	// CLI and web UI to use.
+	var authSvc *auth.Service
+	if *requireAuth {
+		authSvc = auth.NewService(storage)
+
+		tokenPath := expandHome(*adminTokenFile)
+		_, err := authSvc.EnsureAdminToken(ctx, tokenPath)
+		if err != nil {
+			log.Fatalf("Failed to set up admin token: %v", err)
+		}
+		log.Printf("Authentication enabled, admin token in %s",
+			tokenPath)
+	}
+
+	// With TLS enabled, load the user's certificate or generate our own,
+	// and pin it for the gateway's connection to the gRPC server.
+	var (
+		serverTLS  *tls.Config
+		gatewayTLS credentials.TransportCredentials
+	)
+	if *enableTLS {
+		cert, err := loadTLSCert(
+			expandHome(*tlsDir), expandHome(*tlsCertPath),
+			expandHome(*tlsKeyPath), *tlsExtraHosts,
+		)
+		if err != nil {
+			log.Fatalf("Failed to set up TLS: %v", err)
+		}
+		serverTLS = tlscert.ServerConfig(cert)
+
+		fingerprint := tlscert.Fingerprint(cert.Certificate[0])
+		pinned, err := tlscert.PinnedConfig(fingerprint)
+		if err != nil {
+			log.Fatalf("Failed to pin TLS certificate: %v", err)
+		}
+		gatewayTLS = credentials.NewTLS(pinned)
+
+		log.Printf("TLS enabled, certificate fingerprint sha256:%s",
+			fingerprint)
+	}
+
 	// Start gRPC server if enabled.
-	var grpcServer *subtraterpc.Server
-	if *grpcAddr != "" {
+	var (
+		grpcServer   *subtraterpc.Server
+		grpcUnixPath = expandHome(*grpcUnix)
+	)
+	if *grpcAddr != "" || grpcUnixPath != "" {
 		grpcCfg := subtraterpc.DefaultServerConfig()
 		grpcCfg.ListenAddr = *grpcAddr
+		grpcCfg.UnixSocketPath = grpcUnixPath
+		grpcCfg.TLSConfig = serverTLS
 		grpcCfg.MailRef = mailRef
 		grpcCfg.ActivityRef = activityRef
 		grpcCfg.ReviewRef = reviewRef
+		grpcCfg.Auth = authSvc
 
 		// Pass the notification hub actor for gRPC streaming RPCs.
 		grpcServer = subtraterpc.NewServer(
@@ -306,7 +443,13 @@ func main() {
 			log.Fatalf("Failed to start gRPC server: %v", err)
 		}
 		defer grpcServer.Stop()
-		log.Printf("gRPC server listening on %s", *grpcAddr)
+		if *grpcAddr != "" {
+			log.Printf("gRPC server listening on %s", *grpcAddr)
+		}
+		if grpcUnixPath != "" {
+			log.Printf("gRPC server listening on unix socket %s",
+				grpcUnixPath)
+		}
 	}
 
 	// Start the web server if enabled.
@@ -317,13 +460,23 @@ func main() {
 		webCfg.ActivityRef = activityRef
 		webCfg.NotificationHubRef = web.NewActorNotificationHubRef(notificationHub)
 
-		// Enable grpc-gateway REST proxy if gRPC server is running.
-		if *grpcAddr != "" {
+		// Enable grpc-gateway REST proxy if gRPC server is running,
+		// preferring the unix socket, which needs no TLS.
+		switch {
+		case grpcUnixPath != "":
+			webCfg.GRPCEndpoint = subtraterpc.UnixTarget(
+				grpcUnixPath,
+			)
+
+		case *grpcAddr != "":
 			webCfg.GRPCEndpoint = *grpcAddr
+			webCfg.GRPCCredentials = gatewayTLS
 		}
+		webCfg.TLSConfig = serverTLS
 
 		// Wire summary service into the web server.
 		webCfg.SummarySvc = summarySvc
+		webCfg.Auth = authSvc
 
 		webServer, err := web.NewServer(webCfg, storage, agentReg)
 		if err != nil {
@@ -354,6 +507,14 @@ func main() {
 			log.Println("Task change notifications wired: gRPC → WebSocket")
 		}
 
+		// Wire review state changes from the review service to the
+		// WebSocket hub's reviews channel.
+		if webServer.GetHub() != nil {
+			reviewSvc.SetStateNotifier(
+				web.NewHubReviewNotifier(webServer.GetHub()),
+			)
+		}
+
 		go func() {
 			log.Printf("Starting web server on %s", *webAddr)
 			if err := webServer.Start(); err != nil && err != http.ErrServerClosed {
@@ -372,6 +533,51 @@ func main() {
 	go summarySvc.RunBackgroundRefresh(ctx)
 	log.Println("Summary background refresh started")
 
+	// Start the deadline monitor, which escalates unacked messages once
+	// their deadline passes.
+	if *deadlineCheck > 0 {
+		deadlineMonitor := mail.NewDeadlineMonitor(mail.DeadlineMonitorConfig{
+			Store:           storage,
+			NotificationHub: notificationHub,
+			Policy:          escalationPolicy,
+			CheckInterval:   *deadlineCheck,
+			Log:             slog.Default(),
+		})
+		go deadlineMonitor.Run(ctx)
+		log.Printf("Deadline monitor started (%d escalation levels)",
+			len(escalationPolicy.Levels))
+	}
+
+	// Start the queue reaper, which redelivers expired leases and moves
+	// exhausted queue messages to their dead-letter topics.
+	go mailSvc.RunQueueReaper(ctx, *queueReapEvery, slog.Default())
+	log.Println("Queue lease reaper started")
+
+	// Start the message scheduler, which sends scheduled messages once
+	// their send time arrives.
+	go mailSvc.RunMessageScheduler(ctx, *scheduleTick, slog.Default())
+	log.Println("Message scheduler started")
+
+	// Start the embedding indexer, which embeds new messages for semantic
+	// search.
+	go mailSvc.RunEmbeddingIndexer(ctx, *embedTick, slog.Default())
+	log.Printf("Embedding indexer started (model %s)", embedder.Model())
+
+	// Start the retention compactor, which deletes messages that have
+	// outlived their topic's retention window.
+	if *retentionTick > 0 {
+		compactor := mail.NewRetentionCompactor(
+			mail.RetentionCompactorConfig{
+				Store:    storage,
+				Interval: *retentionTick,
+				Log:      slog.Default(),
+			},
+		)
+		go compactor.Run(ctx)
+		log.Printf("Retention compactor started (every %v)",
+			*retentionTick)
+	}
+
 	// Run the MCP server on stdio transport if enabled, otherwise
 	// block until signal.
 	if *enableMCP {
@@ -386,6 +592,31 @@ func main() {
 	}
 }
 
+// loadTLSCert loads the user's certificate if one is given, and otherwise
+// the one generated in tlsDir, generating it first if needed.
+func loadTLSCert(tlsDir, certPath, keyPath,
+	extraHosts string,
+) (tls.Certificate, error) {
+	if certPath != "" || keyPath != "" {
+		if certPath == "" || keyPath == "" {
+			return tls.Certificate{}, fmt.Errorf("-tls-cert and " +
+				"-tls-key must be given together")
+		}
+
+		return tlscert.Load(certPath, keyPath)
+	}
+
+	var hosts []string
+	if extraHosts != "" {
+		hosts = strings.Split(extraHosts, ",")
+	}
+
+	return tlscert.Ensure(
+		filepath.Join(tlsDir, tlscert.CertFilename),
+		filepath.Join(tlsDir, tlscert.KeyFilename), hosts,
+	)
+}
+
 // commitInfo returns the best available commit identifier. It prefers the
 // Commit string set via ldflags (which includes tag info), falling back to
 // the VCS commit hash from runtime/debug.
@@ -253,6 +253,8 @@ func (h *HeartbeatManager) DiscoverAgents(
 		agent := &sqlc.Agent{
 			ID:               row.ID,
 			Name:             row.Name,
+			ProjectKey:       row.ProjectKey,
+			GitBranch:        row.GitBranch,
 			CurrentSessionID: row.CurrentSessionID,
 			LastActiveAt:     row.LastActiveAt,
 		}
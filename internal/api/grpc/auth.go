@@ -0,0 +1,298 @@
+package subtraterpc
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/roasbeef/subtrate/internal/auth"
+	"github.com/roasbeef/subtrate/internal/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// publicMethods can be called without a credential.
+var publicMethods = map[string]bool{
+	"HealthCheck": true,
+}
+
+// adminMethods can only be called with an admin credential. Recipient
+// groups are shared by every agent, and plan reviews and annotations are
+// the human reviewer's, so agents can't delete or annotate them, or change
+// a group's members.
+var adminMethods = map[string]bool{
+	"DeleteAgent":                 true,
+	"CollectGarbage":              true,
+	"SetLegalHold":                true,
+	"SetTopicRetention":           true,
+	"MergeThreads":                true,
+	"SplitThread":                 true,
+	"PruneOldTasks":               true,
+	"MintCredential":              true,
+	"ListCredentials":             true,
+	"RevokeCredential":            true,
+	"SetLimit":                    true,
+	"ClearLimit":                  true,
+	"ListLimits":                  true,
+	"DeleteRecipientGroup":        true,
+	"AddRecipientGroupMembers":    true,
+	"RemoveRecipientGroupMembers": true,
+	"DeletePlanReview":            true,
+	"CreatePlanAnnotation":        true,
+	"UpdatePlanAnnotation":        true,
+	"DeletePlanAnnotation":        true,
+	"CreateDiffAnnotation":        true,
+	"UpdateDiffAnnotation":        true,
+	"DeleteDiffAnnotation":        true,
+}
+
+// lookupMethods take an agent ID or name as a filter or lookup key rather
+// than as the caller's identity, so an agent credential doesn't bind it.
+var lookupMethods = map[string]bool{
+	"GetAgent":        true,
+	"ListTopics":      true,
+	"ListActivities":  true,
+	"ListTaskLists":   true,
+	"ListTasks":       true,
+	"GetTaskStats":    true,
+	"ListReviews":     true,
+	"ListPlanReviews": true,
+}
+
+// identityFields are the request fields that name the calling agent. Task
+// owners are agent names, so an agent credential may only take tasks for
+// its own agent.
+var identityFields = []protoreflect.Name{
+	"sender_id", "agent_id", "requester_id", "agent_name", "created_by",
+	"recipient_id", "reviewed_by", "owner",
+}
+
+// methodIdentityFields overrides identityFields for requests that name the
+// calling agent differently.
+var methodIdentityFields = map[string][]protoreflect.Name{
+	"UpdateAgent": {"id"},
+
+	// The recipient is the agent being asked about, and the answer only
+	// covers status the caller sent.
+	"HasUnackedStatusTo": {"sender_id"},
+}
+
+// authenticate checks the bearer credential in an RPC's metadata and that
+// it may call the method, returning a context carrying the credential.
+func (s *Server) authenticate(ctx context.Context,
+	fullMethod string,
+) (context.Context, error) {
+	method := path.Base(fullMethod)
+	if publicMethods[method] {
+		return ctx, nil
+	}
+
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+	}
+
+	token, err := auth.ParseBearer(header)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	cred, err := s.authSvc.Authenticate(ctx, token)
+	switch {
+	case errors.Is(err, auth.ErrMissingCredential),
+		errors.Is(err, auth.ErrInvalidCredential),
+		errors.Is(err, auth.ErrCredentialExpired),
+		errors.Is(err, auth.ErrCredentialRevoked):
+
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to "+
+			"authenticate: %v", err)
+	}
+
+	if !auth.MethodAllowed(cred, fullMethod) {
+		return nil, status.Errorf(codes.PermissionDenied, "%v: %s",
+			auth.ErrMethodNotAllowed, method)
+	}
+	if adminMethods[method] && !cred.IsAdmin() {
+		return nil, status.Errorf(codes.PermissionDenied, "%v: %s",
+			auth.ErrAdminRequired, method)
+	}
+
+	return auth.NewContext(ctx, cred), nil
+}
+
+// bindIdentity makes a request act as the agent an agent credential belongs
+// to: identity fields left unset are filled with the agent, and ones naming
+// a different agent are refused. Admin credentials may act as anyone.
+func bindIdentity(ctx context.Context, fullMethod string, req any) error {
+	cred, ok := auth.FromContext(ctx)
+	if !ok || cred.IsAdmin() {
+		return nil
+	}
+
+	method := path.Base(fullMethod)
+	if lookupMethods[method] {
+		return nil
+	}
+
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	fields := identityFields
+	if override, ok := methodIdentityFields[method]; ok {
+		fields = override
+	}
+
+	return bindIdentityFields(msg.ProtoReflect(), fields, cred)
+}
+
+// bindIdentityFields binds the given identity fields of a message to an
+// agent credential.
+func bindIdentityFields(m protoreflect.Message, fields []protoreflect.Name,
+	cred store.Credential,
+) error {
+	for _, name := range fields {
+		fd := m.Descriptor().Fields().ByName(name)
+		if fd == nil || fd.IsList() || fd.IsMap() {
+			continue
+		}
+
+		switch fd.Kind() {
+		case protoreflect.Int64Kind:
+			id := m.Get(fd).Int()
+			switch {
+			case id == 0:
+				m.Set(fd, protoreflect.ValueOfInt64(
+					cred.AgentID,
+				))
+
+			case id != cred.AgentID:
+				return status.Errorf(codes.PermissionDenied,
+					"credential for %s can't act as agent "+
+						"%d", cred.AgentName, id)
+			}
+
+		case protoreflect.StringKind:
+			name := m.Get(fd).String()
+			if name != "" && !strings.EqualFold(
+				name, cred.AgentName,
+			) {
+
+				return status.Errorf(codes.PermissionDenied,
+					"credential for %s can't act as %s",
+					cred.AgentName, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// authUnaryInterceptor authenticates unary RPCs and binds their requests to
+// the caller's agent.
+func (s *Server) authUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	ctx, err := s.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bindIdentity(ctx, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// authStreamInterceptor authenticates streaming RPCs and binds every
+// message received on them to the caller's agent.
+func (s *Server) authStreamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, err := s.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &authServerStream{
+		ServerStream: ss,
+		ctx:          ctx,
+		fullMethod:   info.FullMethod,
+	})
+}
+
+// authServerStream is a server stream carrying the caller's credential,
+// whose received messages are bound to the caller's agent.
+type authServerStream struct {
+	grpc.ServerStream
+
+	ctx        context.Context
+	fullMethod string
+}
+
+// Context returns the stream's context, which carries the credential.
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// RecvMsg receives a message and binds it to the caller's agent.
+func (s *authServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	return bindIdentity(s.ctx, s.fullMethod, m)
+}
+
+// requireAdmin returns an error unless authentication is disabled or the
+// caller holds an admin credential.
+func (s *Server) requireAdmin(ctx context.Context) error {
+	if s.authSvc == nil {
+		return nil
+	}
+
+	cred, ok := auth.FromContext(ctx)
+	if ok && cred.IsAdmin() {
+		return nil
+	}
+
+	return status.Error(codes.PermissionDenied,
+		auth.ErrAdminRequired.Error())
+}
+
+// requireOwner returns an error unless authentication is disabled, the
+// caller holds an admin credential, or its agent is the owner of the
+// resource, for requests that name a resource rather than an agent.
+func (s *Server) requireOwner(ctx context.Context, ownerID int64,
+	resource string,
+) error {
+	if s.authSvc == nil {
+		return nil
+	}
+
+	cred, ok := auth.FromContext(ctx)
+	if ok && (cred.IsAdmin() || cred.AgentID == ownerID) {
+		return nil
+	}
+
+	return status.Errorf(codes.PermissionDenied,
+		"%s belongs to another agent", resource)
+}
@@ -0,0 +1,157 @@
+package subtraterpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/auth"
+	"github.com/roasbeef/subtrate/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// credentialStatusError maps credential errors onto gRPC status codes.
+func credentialStatusError(operation string, err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, sql.ErrNoRows),
+		errors.Is(err, auth.ErrCredentialNotFound):
+
+		code = codes.NotFound
+	}
+
+	return status.Errorf(code, "failed to %s: %v", operation, err)
+}
+
+// MintCredential mints a bearer token for an agent, or an admin token.
+func (s *Server) MintCredential(ctx context.Context,
+	req *MintCredentialRequest,
+) (*MintCredentialResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case req.Admin == (req.AgentId != 0):
+		return nil, status.Error(codes.InvalidArgument,
+			"exactly one of agent_id and admin is required")
+
+	case req.TtlSeconds < 0:
+		return nil, status.Error(codes.InvalidArgument,
+			"ttl_seconds must not be negative")
+	}
+
+	if req.AgentId != 0 {
+		_, err := s.store.Queries().GetAgent(ctx, req.AgentId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "agent %d "+
+				"not found", req.AgentId)
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to "+
+				"get agent: %v", err)
+		}
+	}
+
+	token, cred, err := s.credentials.Mint(ctx, auth.MintRequest{
+		AgentID:        req.AgentId,
+		Name:           req.Name,
+		AllowedMethods: req.AllowedMethods,
+		TTL:            time.Duration(req.TtlSeconds) * time.Second,
+	})
+	if err != nil {
+		return nil, credentialStatusError("mint credential", err)
+	}
+
+	return &MintCredentialResponse{
+		Token:      token,
+		Credential: convertCredential(cred),
+	}, nil
+}
+
+// ListCredentials lists credentials, newest first.
+func (s *Server) ListCredentials(ctx context.Context,
+	req *ListCredentialsRequest,
+) (*ListCredentialsResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	creds, err := s.credentials.List(ctx, req.AgentId, req.IncludeRevoked)
+	if err != nil {
+		return nil, credentialStatusError("list credentials", err)
+	}
+
+	resp := &ListCredentialsResponse{
+		Credentials: make([]*Credential, len(creds)),
+	}
+	for i, cred := range creds {
+		resp.Credentials[i] = convertCredential(cred)
+	}
+
+	return resp, nil
+}
+
+// RevokeCredential revokes a credential so its token stops working.
+func (s *Server) RevokeCredential(ctx context.Context,
+	req *RevokeCredentialRequest,
+) (*RevokeCredentialResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.Id == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "id is required",
+		)
+	}
+
+	if err := s.credentials.Revoke(ctx, req.Id); err != nil {
+		return nil, credentialStatusError("revoke credential", err)
+	}
+
+	return &RevokeCredentialResponse{}, nil
+}
+
+// mintAgentToken mints a token for an agent that was just registered or
+// whose identity was just ensured.
+func (s *Server) mintAgentToken(ctx context.Context, agentID int64,
+	name string,
+) (string, error) {
+	token, _, err := s.credentials.Mint(ctx, auth.MintRequest{
+		AgentID: agentID,
+		Name:    name,
+	})
+	if err != nil {
+		return "", credentialStatusError("mint token", err)
+	}
+
+	return token, nil
+}
+
+// convertCredential converts a credential to its proto form.
+func convertCredential(cred store.Credential) *Credential {
+	c := &Credential{
+		Id:             cred.ID,
+		AgentId:        cred.AgentID,
+		AgentName:      cred.AgentName,
+		Admin:          cred.IsAdmin(),
+		Name:           cred.Name,
+		AllowedMethods: cred.AllowedMethods,
+		CreatedAt:      timestamppb.New(cred.CreatedAt),
+	}
+	if cred.ExpiresAt != nil {
+		c.ExpiresAt = timestamppb.New(*cred.ExpiresAt)
+	}
+	if cred.LastUsedAt != nil {
+		c.LastUsedAt = timestamppb.New(*cred.LastUsedAt)
+	}
+	if cred.RevokedAt != nil {
+		c.RevokedAt = timestamppb.New(*cred.RevokedAt)
+	}
+
+	return c
+}
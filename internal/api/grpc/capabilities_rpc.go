@@ -0,0 +1,272 @@
+package subtraterpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+	"github.com/roasbeef/subtrate/internal/mail"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// capabilityStatusError maps capability errors from the mail service onto
+// gRPC status codes.
+func capabilityStatusError(operation string, err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		code = codes.NotFound
+
+	case errors.Is(err, mail.ErrInvalidCapability):
+		code = codes.InvalidArgument
+
+	case errors.Is(err, mail.ErrNoCapableAgent):
+		code = codes.FailedPrecondition
+	}
+
+	return status.Errorf(code, "failed to %s: %v", operation, err)
+}
+
+// UpdateAgent updates an agent's project binding, git branch and declared
+// capabilities. Empty project and branch fields are left as they are, and
+// the capabilities are only replaced when given.
+func (s *Server) UpdateAgent(ctx context.Context,
+	req *UpdateAgentRequest,
+) (*UpdateAgentResponse, error) {
+	if req.Id == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "id is required",
+		)
+	}
+
+	a, err := s.store.Queries().GetAgent(ctx, req.Id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "agent %d not found",
+			req.Id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get "+
+			"agent: %v", err)
+	}
+
+	caps := capabilitiesFromProto(req.Capabilities)
+	if req.Capabilities != nil {
+		if err := caps.Validate(); err != nil {
+			return nil, capabilityStatusError("update agent", err)
+		}
+	}
+
+	if req.ProjectKey != "" || req.GitBranch != "" {
+		params := sqlc.UpdateAgentProjectParams{
+			ProjectKey: a.ProjectKey,
+			GitBranch:  a.GitBranch,
+			ID:         a.ID,
+		}
+		if req.ProjectKey != "" {
+			params.ProjectKey = sql.NullString{
+				String: req.ProjectKey, Valid: true,
+			}
+		}
+		if req.GitBranch != "" {
+			params.GitBranch = sql.NullString{
+				String: req.GitBranch, Valid: true,
+			}
+		}
+
+		err := s.store.Queries().UpdateAgentProject(ctx, params)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to "+
+				"update agent: %v", err)
+		}
+	}
+
+	if req.Capabilities != nil {
+		_, err := s.mailSvc.SetAgentCapabilities(ctx, a.ID, caps)
+		if err != nil {
+			return nil, capabilityStatusError("update agent", err)
+		}
+	}
+
+	agentResp, err := s.GetAgent(ctx, &GetAgentRequest{AgentId: a.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateAgentResponse{Agent: agentResp}, nil
+}
+
+// DiscoverAgents returns every agent with its status, discovery metadata,
+// unread count and declared capabilities, narrowed by the request's
+// filters. The status counts cover every agent.
+func (s *Server) DiscoverAgents(ctx context.Context,
+	req *DiscoverAgentsRequest,
+) (*DiscoverAgentsResponse, error) {
+	var capKind, capName string
+	if req.CapabilityFilter != "" {
+		var err error
+		capKind, capName, err = mail.ParseCapability(
+			req.CapabilityFilter,
+		)
+		if err != nil {
+			return nil, capabilityStatusError(
+				"discover agents", err,
+			)
+		}
+	}
+
+	discovered, counts, err := s.heartbeatMgr.DiscoverAgents(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to discover "+
+			"agents: %v", err)
+	}
+
+	allCaps, err := s.mailSvc.ListAgentCapabilities(ctx)
+	if err != nil {
+		return nil, capabilityStatusError("discover agents", err)
+	}
+
+	statuses := make(map[AgentStatus]bool, len(req.StatusFilter))
+	for _, st := range req.StatusFilter {
+		statuses[st] = true
+	}
+
+	var agents []*DiscoveredAgent
+	for _, d := range discovered {
+		a := d.Agent
+		agentStatus := agentStatusToProto(d.Status)
+		caps := allCaps[a.ID]
+
+		switch {
+		case len(statuses) > 0 && !statuses[agentStatus]:
+			continue
+
+		case req.ProjectFilter != "" && !strings.HasPrefix(
+			a.ProjectKey.String, req.ProjectFilter,
+		):
+			continue
+
+		case req.NameFilter != "" && !strings.Contains(
+			strings.ToLower(a.Name),
+			strings.ToLower(req.NameFilter),
+		):
+			continue
+
+		case capName != "" && !caps.Has(capKind, capName):
+			continue
+		}
+
+		agents = append(agents, &DiscoveredAgent{
+			Id:           a.ID,
+			Name:         a.Name,
+			ProjectKey:   a.ProjectKey.String,
+			GitBranch:    a.GitBranch.String,
+			Status:       agentStatus,
+			LastActiveAt: timestamppb.New(d.LastActive),
+			SecondsSinceHeartbeat: int64(
+				time.Since(d.LastActive).Seconds(),
+			),
+			SessionId:    a.CurrentSessionID.String,
+			Purpose:      d.Purpose,
+			WorkingDir:   d.WorkingDir,
+			Hostname:     d.Hostname,
+			UnreadCount:  d.UnreadCount,
+			Capabilities: capabilitiesToProto(caps),
+		})
+	}
+
+	return &DiscoverAgentsResponse{
+		Agents: agents,
+		Counts: &AgentStatusCounts{
+			Active:  int32(counts.Active),
+			Busy:    int32(counts.Busy),
+			Idle:    int32(counts.Idle),
+			Offline: int32(counts.Offline),
+		},
+	}, nil
+}
+
+// ListCapabilityRoutes lists the agents the router picked for mail and
+// tasks addressed to a capability, newest first.
+func (s *Server) ListCapabilityRoutes(ctx context.Context,
+	req *ListCapabilityRoutesRequest,
+) (*ListCapabilityRoutesResponse, error) {
+	routes, err := s.mailSvc.ListCapabilityRoutes(
+		ctx, req.Capability, int(req.Limit),
+	)
+	if err != nil {
+		return nil, capabilityStatusError("list routes", err)
+	}
+
+	resp := &ListCapabilityRoutesResponse{
+		Routes: make([]*CapabilityRoute, len(routes)),
+	}
+	for i, r := range routes {
+		resp.Routes[i] = convertCapabilityRoute(r)
+	}
+
+	return resp, nil
+}
+
+// agentCapabilities returns the capabilities an agent declares in proto
+// form, or nil if it declares none.
+func (s *Server) agentCapabilities(ctx context.Context,
+	agentID int64,
+) (*AgentCapabilities, error) {
+	caps, err := s.mailSvc.GetAgentCapabilities(ctx, agentID)
+	if err != nil {
+		return nil, capabilityStatusError("get capabilities", err)
+	}
+
+	return capabilitiesToProto(caps), nil
+}
+
+// capabilitiesToProto converts an agent's capabilities to their proto
+// form, or nil if it declares none.
+func capabilitiesToProto(caps mail.AgentCapabilities) *AgentCapabilities {
+	if caps.IsEmpty() {
+		return nil
+	}
+
+	return &AgentCapabilities{
+		Languages:      caps.Languages,
+		Repos:          caps.Repos,
+		Tools:          caps.Tools,
+		Skills:         caps.Skills,
+		MaxConcurrency: caps.MaxConcurrency,
+	}
+}
+
+// capabilitiesFromProto converts proto capabilities, treating nil as none.
+func capabilitiesFromProto(caps *AgentCapabilities) mail.AgentCapabilities {
+	if caps == nil {
+		return mail.AgentCapabilities{}
+	}
+
+	return mail.AgentCapabilities{
+		Languages:      caps.Languages,
+		Repos:          caps.Repos,
+		Tools:          caps.Tools,
+		Skills:         caps.Skills,
+		MaxConcurrency: caps.MaxConcurrency,
+	}
+}
+
+// convertCapabilityRoute converts a capability route to its proto form.
+func convertCapabilityRoute(r mail.CapabilityRoute) *CapabilityRoute {
+	return &CapabilityRoute{
+		Capability:      r.Capability,
+		AgentId:         r.AgentID,
+		AgentName:       r.AgentName,
+		MessageId:       r.MessageID,
+		TaskId:          r.TaskID,
+		Candidates:      r.Candidates,
+		UnreadCount:     r.UnreadCount,
+		InProgressCount: r.InProgressCount,
+		CreatedAt:       timestamppb.New(r.CreatedAt),
+	}
+}
@@ -0,0 +1,124 @@
+package subtraterpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// convertDeliveryMode converts a proto delivery mode to its mail service
+// form.
+func convertDeliveryMode(mode DeliveryMode) mail.DeliveryMode {
+	if mode == DeliveryMode_DELIVERY_MODE_PARTIAL {
+		return mail.DeliveryModePartial
+	}
+
+	return mail.DeliveryModeAtomic
+}
+
+// convertRecipientStatus converts a mail service recipient status to its
+// proto form.
+func convertRecipientStatus(s mail.RecipientStatus) RecipientStatus {
+	switch s {
+	case mail.RecipientDelivered:
+		return RecipientStatus_RECIPIENT_STATUS_DELIVERED
+	case mail.RecipientQueued:
+		return RecipientStatus_RECIPIENT_STATUS_QUEUED
+	case mail.RecipientUnknown:
+		return RecipientStatus_RECIPIENT_STATUS_UNKNOWN
+	case mail.RecipientRejected:
+		return RecipientStatus_RECIPIENT_STATUS_REJECTED
+	default:
+		return RecipientStatus_RECIPIENT_STATUS_UNSPECIFIED
+	}
+}
+
+// convertRecipientDeliveries converts per-recipient delivery results to
+// their proto form.
+func convertRecipientDeliveries(
+	deliveries []mail.RecipientDelivery,
+) []*RecipientDelivery {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	result := make([]*RecipientDelivery, len(deliveries))
+	for i, d := range deliveries {
+		result[i] = &RecipientDelivery{
+			Recipient: d.Recipient,
+			AgentId:   d.AgentID,
+			Status:    convertRecipientStatus(d.Status),
+			Reason:    d.Reason,
+		}
+	}
+
+	return result
+}
+
+// ListDeadLetters lists the sender's undeliverable recipients, newest first.
+func (s *Server) ListDeadLetters(ctx context.Context,
+	req *ListDeadLettersRequest,
+) (*ListDeadLettersResponse, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id is required",
+		)
+	}
+
+	letters, err := s.mailSvc.ListDeadLetters(
+		ctx, req.AgentId, int(req.Limit),
+	)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.Internal, "failed to list dead letters: %v", err,
+		)
+	}
+
+	resp := &ListDeadLettersResponse{
+		DeadLetters: make([]*DeadLetter, len(letters)),
+	}
+	for i, l := range letters {
+		resp.DeadLetters[i] = &DeadLetter{
+			Id:        l.ID,
+			MessageId: l.MessageID,
+			ThreadId:  l.ThreadID,
+			Subject:   l.Subject,
+			Recipient: l.Recipient,
+			Status: convertRecipientStatus(
+				mail.RecipientStatus(l.Status),
+			),
+			Reason:    l.Reason,
+			CreatedAt: timestamppb.New(l.CreatedAt),
+		}
+	}
+
+	return resp, nil
+}
+
+// DeleteDeadLetter removes an entry from the sender's dead-letter mailbox.
+func (s *Server) DeleteDeadLetter(ctx context.Context,
+	req *DeleteDeadLetterRequest,
+) (*DeleteDeadLetterResponse, error) {
+	if req.AgentId == 0 || req.Id == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id and id are required",
+		)
+	}
+
+	err := s.mailSvc.DeleteDeadLetter(ctx, req.AgentId, req.Id)
+	switch {
+	case errors.Is(err, mail.ErrDeadLetterNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+
+	case err != nil:
+		return nil, status.Errorf(
+			codes.Internal, "failed to delete dead letter: %v", err,
+		)
+	}
+
+	return &DeleteDeadLetterResponse{Success: true}, nil
+}
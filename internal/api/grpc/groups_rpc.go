@@ -0,0 +1,211 @@
+package subtraterpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// recipientStatusError maps recipient group, alias, capability and delivery
+// errors from the mail service onto gRPC status codes.
+func recipientStatusError(operation string, err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, mail.ErrRecipientGroupNotFound),
+		errors.Is(err, mail.ErrAgentNotFound):
+
+		code = codes.NotFound
+
+	case errors.Is(err, mail.ErrRecipientGroupExists):
+		code = codes.AlreadyExists
+
+	case errors.Is(err, mail.ErrRecipientRejected):
+		code = codes.PermissionDenied
+
+	case errors.Is(err, mail.ErrNoCapableAgent):
+		code = codes.FailedPrecondition
+
+	case errors.Is(err, mail.ErrInvalidGroupName),
+		errors.Is(err, mail.ErrUnknownRecipientAlias),
+		errors.Is(err, mail.ErrEmptyRecipientAlias),
+		errors.Is(err, mail.ErrInvalidCapability):
+
+		code = codes.InvalidArgument
+	}
+
+	return status.Errorf(code, "failed to %s: %v", operation, err)
+}
+
+// convertRecipientGroup converts a store recipient group to its proto form.
+func convertRecipientGroup(g store.RecipientGroup,
+	members []string,
+) *RecipientGroup {
+	return &RecipientGroup{
+		Id:          g.ID,
+		Name:        g.Name,
+		Description: g.Description,
+		MemberCount: g.MemberCount,
+		Members:     members,
+		CreatedAt:   timestamppb.New(g.CreatedAt),
+	}
+}
+
+// recipientGroupResponse wraps a recipient group and its members in a
+// response.
+func recipientGroupResponse(
+	group mail.RecipientGroup,
+) *RecipientGroupResponse {
+	return &RecipientGroupResponse{
+		Group: convertRecipientGroup(group.RecipientGroup, group.Members),
+	}
+}
+
+// convertRecipientExpansions converts recipient expansions to their proto
+// form.
+func convertRecipientExpansions(
+	expansions []mail.RecipientExpansion,
+) []*RecipientExpansion {
+	if len(expansions) == 0 {
+		return nil
+	}
+
+	result := make([]*RecipientExpansion, len(expansions))
+	for i, e := range expansions {
+		result[i] = &RecipientExpansion{
+			Address:    e.Address,
+			AgentNames: e.AgentNames,
+		}
+	}
+
+	return result
+}
+
+// CreateRecipientGroup creates a recipient group with its initial members.
+func (s *Server) CreateRecipientGroup(ctx context.Context,
+	req *CreateRecipientGroupRequest,
+) (*RecipientGroupResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "name is required",
+		)
+	}
+
+	group, err := s.mailSvc.CreateRecipientGroup(
+		ctx, req.Name, req.Description, req.CreatedBy, req.Members,
+	)
+	if err != nil {
+		return nil, recipientStatusError("create group", err)
+	}
+
+	return recipientGroupResponse(group), nil
+}
+
+// GetRecipientGroup returns a recipient group and its members.
+func (s *Server) GetRecipientGroup(ctx context.Context,
+	req *GetRecipientGroupRequest,
+) (*RecipientGroupResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "name is required",
+		)
+	}
+
+	group, err := s.mailSvc.GetRecipientGroup(ctx, req.Name)
+	if err != nil {
+		return nil, recipientStatusError("get group", err)
+	}
+
+	return recipientGroupResponse(group), nil
+}
+
+// ListRecipientGroups lists all recipient groups.
+func (s *Server) ListRecipientGroups(ctx context.Context,
+	req *ListRecipientGroupsRequest,
+) (*ListRecipientGroupsResponse, error) {
+	groups, err := s.mailSvc.ListRecipientGroups(ctx)
+	if err != nil {
+		return nil, recipientStatusError("list groups", err)
+	}
+
+	resp := &ListRecipientGroupsResponse{
+		Groups: make([]*RecipientGroup, len(groups)),
+	}
+	for i, g := range groups {
+		resp.Groups[i] = convertRecipientGroup(g, nil)
+	}
+
+	return resp, nil
+}
+
+// DeleteRecipientGroup deletes a recipient group.
+func (s *Server) DeleteRecipientGroup(ctx context.Context,
+	req *DeleteRecipientGroupRequest,
+) (*DeleteRecipientGroupResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "name is required",
+		)
+	}
+
+	err := s.mailSvc.DeleteRecipientGroup(ctx, req.Name)
+	if err != nil {
+		return nil, recipientStatusError("delete group", err)
+	}
+
+	return &DeleteRecipientGroupResponse{Success: true}, nil
+}
+
+// AddRecipientGroupMembers adds agents to a recipient group.
+func (s *Server) AddRecipientGroupMembers(ctx context.Context,
+	req *RecipientGroupMembersRequest,
+) (*RecipientGroupResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "name is required",
+		)
+	}
+	if len(req.Members) == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "members is required",
+		)
+	}
+
+	group, err := s.mailSvc.AddRecipientGroupMembers(
+		ctx, req.Name, req.Members,
+	)
+	if err != nil {
+		return nil, recipientStatusError("add members", err)
+	}
+
+	return recipientGroupResponse(group), nil
+}
+
+// RemoveRecipientGroupMembers removes agents from a recipient group.
+func (s *Server) RemoveRecipientGroupMembers(ctx context.Context,
+	req *RecipientGroupMembersRequest,
+) (*RecipientGroupResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "name is required",
+		)
+	}
+	if len(req.Members) == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "members is required",
+		)
+	}
+
+	group, err := s.mailSvc.RemoveRecipientGroupMembers(
+		ctx, req.Name, req.Members,
+	)
+	if err != nil {
+		return nil, recipientStatusError("remove members", err)
+	}
+
+	return recipientGroupResponse(group), nil
+}
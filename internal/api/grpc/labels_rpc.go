@@ -0,0 +1,137 @@
+package subtraterpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CreateLabel creates a label the agent can file its messages under.
+func (s *Server) CreateLabel(ctx context.Context,
+	req *CreateLabelRequest,
+) (*LabelProto, error) {
+	if req.AgentId == 0 || req.Name == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id and name are required",
+		)
+	}
+
+	label, err := s.mailSvc.CreateLabel(ctx, req.AgentId, req.Name)
+	if err != nil {
+		return nil, labelStatusError("create label", err)
+	}
+
+	return convertLabel(label), nil
+}
+
+// ListLabels lists the agent's labels by name, with their counts.
+func (s *Server) ListLabels(ctx context.Context,
+	req *ListLabelsRequest,
+) (*ListLabelsResponse, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id is required",
+		)
+	}
+
+	labels, err := s.mailSvc.ListLabels(ctx, req.AgentId)
+	if err != nil {
+		return nil, labelStatusError("list labels", err)
+	}
+
+	return &ListLabelsResponse{Labels: convertLabels(labels)}, nil
+}
+
+// DeleteLabel deletes one of the agent's labels.
+func (s *Server) DeleteLabel(ctx context.Context,
+	req *DeleteLabelRequest,
+) (*DeleteLabelResponse, error) {
+	if req.AgentId == 0 || req.Name == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id and name are required",
+		)
+	}
+
+	err := s.mailSvc.DeleteLabel(ctx, req.AgentId, req.Name)
+	if err != nil {
+		return nil, labelStatusError("delete label", err)
+	}
+
+	return &DeleteLabelResponse{}, nil
+}
+
+// LabelMessage applies and removes labels on the agent's copy of a message.
+func (s *Server) LabelMessage(ctx context.Context,
+	req *LabelMessageRequest,
+) (*LabelMessageResponse, error) {
+	if req.AgentId == 0 || req.MessageId == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"agent_id and message_id are required",
+		)
+	}
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "add or remove is required",
+		)
+	}
+
+	labels, err := s.mailSvc.LabelMessage(ctx, mail.LabelMessageRequest{
+		AgentID:   req.AgentId,
+		MessageID: req.MessageId,
+		Add:       req.Add,
+		Remove:    req.Remove,
+	})
+	if err != nil {
+		return nil, labelStatusError("label message", err)
+	}
+
+	return &LabelMessageResponse{Labels: labels}, nil
+}
+
+// labelStatusError maps label errors from the mail service onto gRPC status
+// codes.
+func labelStatusError(operation string, err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, mail.ErrLabelNotFound),
+		errors.Is(err, mail.ErrMessageNotFound):
+
+		code = codes.NotFound
+
+	case errors.Is(err, mail.ErrLabelExists):
+		code = codes.AlreadyExists
+
+	case errors.Is(err, mail.ErrInvalidLabel):
+		code = codes.InvalidArgument
+	}
+
+	return status.Errorf(code, "failed to %s: %v", operation, err)
+}
+
+// convertLabel converts a label to its proto form.
+func convertLabel(label store.Label) *LabelProto {
+	return &LabelProto{
+		Id:           label.ID,
+		AgentId:      label.AgentID,
+		Name:         label.Name,
+		MessageCount: label.MessageCount,
+		UnreadCount:  label.UnreadCount,
+		CreatedAt:    timestamppb.New(label.CreatedAt),
+	}
+}
+
+// convertLabels converts labels to their proto form.
+func convertLabels(labels []store.Label) []*LabelProto {
+	result := make([]*LabelProto, len(labels))
+	for i, label := range labels {
+		result[i] = convertLabel(label)
+	}
+
+	return result
+}
@@ -0,0 +1,178 @@
+package subtraterpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/ratelimit"
+	"github.com/roasbeef/subtrate/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// limitConfigStatusError maps errors from configuring limits onto gRPC
+// status codes.
+func limitConfigStatusError(operation string, err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, ratelimit.ErrInvalidLimit):
+		code = codes.InvalidArgument
+
+	case errors.Is(err, sql.ErrNoRows):
+		code = codes.NotFound
+	}
+
+	return status.Errorf(code, "failed to %s: %v", operation, err)
+}
+
+// requireAgent returns a NotFound status if an agent doesn't exist.
+func (s *Server) requireAgent(ctx context.Context, agentID int64) error {
+	_, err := s.store.Queries().GetAgent(ctx, agentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Errorf(codes.NotFound, "agent %d not found",
+			agentID)
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get agent: %v",
+			err)
+	}
+
+	return nil
+}
+
+// SetLimit sets an agent's rate limit or storage quota for a class, or the
+// default for every agent.
+func (s *Server) SetLimit(ctx context.Context,
+	req *SetLimitRequest,
+) (*SetLimitResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.AgentId != 0 {
+		if err := s.requireAgent(ctx, req.AgentId); err != nil {
+			return nil, err
+		}
+	}
+
+	limit, err := s.limiter.Set(
+		ctx, req.AgentId, ratelimit.Class(req.Class), req.Amount,
+		time.Duration(req.IntervalSeconds)*time.Second, req.Burst,
+	)
+	if err != nil {
+		return nil, limitConfigStatusError("set limit", err)
+	}
+
+	// The stored limit doesn't carry the agent's name, so list it back.
+	limits, err := s.limiter.List(ctx, req.AgentId)
+	if err != nil {
+		return nil, limitConfigStatusError("set limit", err)
+	}
+	for _, l := range limits {
+		if l.ID == limit.ID {
+			limit = l
+		}
+	}
+
+	return &SetLimitResponse{Limit: convertAgentLimit(limit)}, nil
+}
+
+// ClearLimit removes an agent's limit for a class, or the default.
+func (s *Server) ClearLimit(ctx context.Context,
+	req *ClearLimitRequest,
+) (*ClearLimitResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	cleared, err := s.limiter.Clear(
+		ctx, req.AgentId, ratelimit.Class(req.Class),
+	)
+	if err != nil {
+		return nil, limitConfigStatusError("clear limit", err)
+	}
+	if !cleared {
+		return nil, status.Errorf(codes.NotFound, "no %s limit set",
+			req.Class)
+	}
+
+	return &ClearLimitResponse{}, nil
+}
+
+// ListLimits lists the default limits followed by the agents' own.
+func (s *Server) ListLimits(ctx context.Context,
+	req *ListLimitsRequest,
+) (*ListLimitsResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	limits, err := s.limiter.List(ctx, req.AgentId)
+	if err != nil {
+		return nil, limitConfigStatusError("list limits", err)
+	}
+
+	resp := &ListLimitsResponse{
+		Limits: make([]*AgentLimit, len(limits)),
+	}
+	for i, limit := range limits {
+		resp.Limits[i] = convertAgentLimit(limit)
+	}
+
+	return resp, nil
+}
+
+// GetUsage reports an agent's standing against its limits.
+func (s *Server) GetUsage(ctx context.Context,
+	req *GetUsageRequest,
+) (*GetUsageResponse, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id is required",
+		)
+	}
+
+	usage, err := s.limiter.Usage(ctx, req.AgentId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get "+
+			"usage: %v", err)
+	}
+
+	resp := &GetUsageResponse{
+		Usage: make([]*LimitUsage, len(usage)),
+	}
+	for i, u := range usage {
+		lu := &LimitUsage{
+			Class:     string(u.Class),
+			Tokens:    u.Tokens,
+			Throttled: u.Throttled,
+			UsedBytes: u.UsedBytes,
+		}
+		if u.Limit != nil {
+			lu.Limit = convertAgentLimit(*u.Limit)
+		}
+		if u.LastThrottledAt != nil {
+			lu.LastThrottledAt = timestamppb.New(*u.LastThrottledAt)
+		}
+		resp.Usage[i] = lu
+	}
+
+	return resp, nil
+}
+
+// convertAgentLimit converts a store limit to its proto form.
+func convertAgentLimit(limit store.AgentLimit) *AgentLimit {
+	return &AgentLimit{
+		Id:              limit.ID,
+		AgentId:         limit.AgentID,
+		AgentName:       limit.AgentName,
+		Class:           limit.Class,
+		Amount:          limit.Amount,
+		IntervalSeconds: int64(limit.Interval / time.Second),
+		Burst:           limit.Burst,
+		UpdatedAt:       timestamppb.New(limit.UpdatedAt),
+	}
+}
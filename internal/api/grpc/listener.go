@@ -0,0 +1,71 @@
+package subtraterpc
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// unixDialTimeout bounds the check for a daemon already serving a socket.
+const unixDialTimeout = time.Second
+
+// DefaultUnixSocketPath returns the conventional unix socket path,
+// ~/.subtrate/substrated.sock, which clients look for before falling back
+// to TCP.
+func DefaultUnixSocketPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".subtrate", "substrated.sock"), nil
+}
+
+// UnixTarget returns the gRPC dial target for a unix socket path.
+func UnixTarget(path string) string {
+	if filepath.IsAbs(path) {
+		return "unix://" + path
+	}
+
+	return "unix:" + path
+}
+
+// listenUnix listens on a unix socket only its owner can connect to. A
+// socket left behind by a daemon that didn't shut down cleanly is replaced,
+// but one a running daemon still serves is not.
+func listenUnix(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create socket dir: %w", err)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		conn, err := net.DialTimeout("unix", path, unixDialTimeout)
+		if err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("unix socket %s is already in "+
+				"use", path)
+		}
+
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale unix "+
+				"socket: %w", err)
+		}
+	}
+
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+
+	// Connecting needs write access to the socket, so it's limited to
+	// the daemon's user straight away, before the server accepts on it.
+	if err := os.Chmod(path, 0600); err != nil {
+		lis.Close()
+		return nil, fmt.Errorf("failed to restrict unix socket: %w",
+			err)
+	}
+
+	return lis, nil
+}
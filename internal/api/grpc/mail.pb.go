@@ -137,6 +137,230 @@ func (MessageState) EnumDescriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{1}
 }
 
+// DeliveryMode controls how a send handles recipients that can't be
+// delivered to.
+type DeliveryMode int32
+
+const (
+	// Fail the whole send if any recipient can't be delivered to.
+	DeliveryMode_DELIVERY_MODE_ATOMIC DeliveryMode = 0
+	// Deliver to every resolvable recipient and dead-letter the rest.
+	DeliveryMode_DELIVERY_MODE_PARTIAL DeliveryMode = 1
+)
+
+// Enum value maps for DeliveryMode.
+var (
+	DeliveryMode_name = map[int32]string{
+		0: "DELIVERY_MODE_ATOMIC",
+		1: "DELIVERY_MODE_PARTIAL",
+	}
+	DeliveryMode_value = map[string]int32{
+		"DELIVERY_MODE_ATOMIC":  0,
+		"DELIVERY_MODE_PARTIAL": 1,
+	}
+)
+
+func (x DeliveryMode) Enum() *DeliveryMode {
+	p := new(DeliveryMode)
+	*p = x
+	return p
+}
+
+func (x DeliveryMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeliveryMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_mail_proto_enumTypes[2].Descriptor()
+}
+
+func (DeliveryMode) Type() protoreflect.EnumType {
+	return &file_mail_proto_enumTypes[2]
+}
+
+func (x DeliveryMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeliveryMode.Descriptor instead.
+func (DeliveryMode) EnumDescriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{2}
+}
+
+// RecipientStatus is the outcome of delivering a message to one recipient.
+type RecipientStatus int32
+
+const (
+	RecipientStatus_RECIPIENT_STATUS_UNSPECIFIED RecipientStatus = 0
+	RecipientStatus_RECIPIENT_STATUS_DELIVERED   RecipientStatus = 1
+	// Delivered to an agent that is currently offline.
+	RecipientStatus_RECIPIENT_STATUS_QUEUED RecipientStatus = 2
+	// No agent, group or alias matched the recipient.
+	RecipientStatus_RECIPIENT_STATUS_UNKNOWN RecipientStatus = 3
+	// A delivery policy refused the recipient.
+	RecipientStatus_RECIPIENT_STATUS_REJECTED RecipientStatus = 4
+)
+
+// Enum value maps for RecipientStatus.
+var (
+	RecipientStatus_name = map[int32]string{
+		0: "RECIPIENT_STATUS_UNSPECIFIED",
+		1: "RECIPIENT_STATUS_DELIVERED",
+		2: "RECIPIENT_STATUS_QUEUED",
+		3: "RECIPIENT_STATUS_UNKNOWN",
+		4: "RECIPIENT_STATUS_REJECTED",
+	}
+	RecipientStatus_value = map[string]int32{
+		"RECIPIENT_STATUS_UNSPECIFIED": 0,
+		"RECIPIENT_STATUS_DELIVERED":   1,
+		"RECIPIENT_STATUS_QUEUED":      2,
+		"RECIPIENT_STATUS_UNKNOWN":     3,
+		"RECIPIENT_STATUS_REJECTED":    4,
+	}
+)
+
+func (x RecipientStatus) Enum() *RecipientStatus {
+	p := new(RecipientStatus)
+	*p = x
+	return p
+}
+
+func (x RecipientStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RecipientStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_mail_proto_enumTypes[3].Descriptor()
+}
+
+func (RecipientStatus) Type() protoreflect.EnumType {
+	return &file_mail_proto_enumTypes[3]
+}
+
+func (x RecipientStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RecipientStatus.Descriptor instead.
+func (RecipientStatus) EnumDescriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{3}
+}
+
+// WatchEventType is the kind of a WatchInboxEvent.
+type WatchEventType int32
+
+const (
+	WatchEventType_WATCH_EVENT_TYPE_UNSPECIFIED WatchEventType = 0
+	WatchEventType_WATCH_EVENT_TYPE_ARMED       WatchEventType = 1 // The lease was granted
+	WatchEventType_WATCH_EVENT_TYPE_WAKE        WatchEventType = 2 // Matching mail arrived
+	WatchEventType_WATCH_EVENT_TYPE_LEASE_LOST  WatchEventType = 3 // Another watcher took the lease
+)
+
+// Enum value maps for WatchEventType.
+var (
+	WatchEventType_name = map[int32]string{
+		0: "WATCH_EVENT_TYPE_UNSPECIFIED",
+		1: "WATCH_EVENT_TYPE_ARMED",
+		2: "WATCH_EVENT_TYPE_WAKE",
+		3: "WATCH_EVENT_TYPE_LEASE_LOST",
+	}
+	WatchEventType_value = map[string]int32{
+		"WATCH_EVENT_TYPE_UNSPECIFIED": 0,
+		"WATCH_EVENT_TYPE_ARMED":       1,
+		"WATCH_EVENT_TYPE_WAKE":        2,
+		"WATCH_EVENT_TYPE_LEASE_LOST":  3,
+	}
+)
+
+func (x WatchEventType) Enum() *WatchEventType {
+	p := new(WatchEventType)
+	*p = x
+	return p
+}
+
+func (x WatchEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WatchEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_mail_proto_enumTypes[4].Descriptor()
+}
+
+func (WatchEventType) Type() protoreflect.EnumType {
+	return &file_mail_proto_enumTypes[4]
+}
+
+func (x WatchEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WatchEventType.Descriptor instead.
+func (WatchEventType) EnumDescriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{4}
+}
+
+// SearchKind is a type of entity SearchAll can return.
+type SearchKind int32
+
+const (
+	SearchKind_SEARCH_KIND_UNSPECIFIED     SearchKind = 0
+	SearchKind_SEARCH_KIND_MESSAGE         SearchKind = 1
+	SearchKind_SEARCH_KIND_REVIEW_ISSUE    SearchKind = 2
+	SearchKind_SEARCH_KIND_PLAN_REVIEW     SearchKind = 3
+	SearchKind_SEARCH_KIND_TASK            SearchKind = 4
+	SearchKind_SEARCH_KIND_PLAN_ANNOTATION SearchKind = 5
+	SearchKind_SEARCH_KIND_DIFF_ANNOTATION SearchKind = 6
+)
+
+// Enum value maps for SearchKind.
+var (
+	SearchKind_name = map[int32]string{
+		0: "SEARCH_KIND_UNSPECIFIED",
+		1: "SEARCH_KIND_MESSAGE",
+		2: "SEARCH_KIND_REVIEW_ISSUE",
+		3: "SEARCH_KIND_PLAN_REVIEW",
+		4: "SEARCH_KIND_TASK",
+		5: "SEARCH_KIND_PLAN_ANNOTATION",
+		6: "SEARCH_KIND_DIFF_ANNOTATION",
+	}
+	SearchKind_value = map[string]int32{
+		"SEARCH_KIND_UNSPECIFIED":     0,
+		"SEARCH_KIND_MESSAGE":         1,
+		"SEARCH_KIND_REVIEW_ISSUE":    2,
+		"SEARCH_KIND_PLAN_REVIEW":     3,
+		"SEARCH_KIND_TASK":            4,
+		"SEARCH_KIND_PLAN_ANNOTATION": 5,
+		"SEARCH_KIND_DIFF_ANNOTATION": 6,
+	}
+)
+
+func (x SearchKind) Enum() *SearchKind {
+	p := new(SearchKind)
+	*p = x
+	return p
+}
+
+func (x SearchKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SearchKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_mail_proto_enumTypes[5].Descriptor()
+}
+
+func (SearchKind) Type() protoreflect.EnumType {
+	return &file_mail_proto_enumTypes[5]
+}
+
+func (x SearchKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SearchKind.Descriptor instead.
+func (SearchKind) EnumDescriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{5}
+}
+
 // AgentStatus represents an agent's current status.
 type AgentStatus int32
 
@@ -177,11 +401,11 @@ func (x AgentStatus) String() string {
 }
 
 func (AgentStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_mail_proto_enumTypes[2].Descriptor()
+	return file_mail_proto_enumTypes[6].Descriptor()
 }
 
 func (AgentStatus) Type() protoreflect.EnumType {
-	return &file_mail_proto_enumTypes[2]
+	return &file_mail_proto_enumTypes[6]
 }
 
 func (x AgentStatus) Number() protoreflect.EnumNumber {
@@ -190,7 +414,7 @@ func (x AgentStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use AgentStatus.Descriptor instead.
 func (AgentStatus) EnumDescriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{2}
+	return file_mail_proto_rawDescGZIP(), []int{6}
 }
 
 // SessionStatus represents the status of a session.
@@ -230,11 +454,11 @@ func (x SessionStatus) String() string {
 }
 
 func (SessionStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_mail_proto_enumTypes[3].Descriptor()
+	return file_mail_proto_enumTypes[7].Descriptor()
 }
 
 func (SessionStatus) Type() protoreflect.EnumType {
-	return &file_mail_proto_enumTypes[3]
+	return &file_mail_proto_enumTypes[7]
 }
 
 func (x SessionStatus) Number() protoreflect.EnumNumber {
@@ -243,7 +467,7 @@ func (x SessionStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use SessionStatus.Descriptor instead.
 func (SessionStatus) EnumDescriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{3}
+	return file_mail_proto_rawDescGZIP(), []int{7}
 }
 
 // ActivityType represents the type of an activity.
@@ -257,6 +481,7 @@ const (
 	ActivityType_ACTIVITY_TYPE_SESSION_COMPLETED ActivityType = 4
 	ActivityType_ACTIVITY_TYPE_AGENT_REGISTERED  ActivityType = 5
 	ActivityType_ACTIVITY_TYPE_HEARTBEAT         ActivityType = 6
+	ActivityType_ACTIVITY_TYPE_SCHEDULE_RUN      ActivityType = 7
 )
 
 // Enum value maps for ActivityType.
@@ -269,6 +494,7 @@ var (
 		4: "ACTIVITY_TYPE_SESSION_COMPLETED",
 		5: "ACTIVITY_TYPE_AGENT_REGISTERED",
 		6: "ACTIVITY_TYPE_HEARTBEAT",
+		7: "ACTIVITY_TYPE_SCHEDULE_RUN",
 	}
 	ActivityType_value = map[string]int32{
 		"ACTIVITY_TYPE_UNSPECIFIED":       0,
@@ -278,6 +504,7 @@ var (
 		"ACTIVITY_TYPE_SESSION_COMPLETED": 4,
 		"ACTIVITY_TYPE_AGENT_REGISTERED":  5,
 		"ACTIVITY_TYPE_HEARTBEAT":         6,
+		"ACTIVITY_TYPE_SCHEDULE_RUN":      7,
 	}
 )
 
@@ -292,11 +519,11 @@ func (x ActivityType) String() string {
 }
 
 func (ActivityType) Descriptor() protoreflect.EnumDescriptor {
-	return file_mail_proto_enumTypes[4].Descriptor()
+	return file_mail_proto_enumTypes[8].Descriptor()
 }
 
 func (ActivityType) Type() protoreflect.EnumType {
-	return &file_mail_proto_enumTypes[4]
+	return &file_mail_proto_enumTypes[8]
 }
 
 func (x ActivityType) Number() protoreflect.EnumNumber {
@@ -305,7 +532,7 @@ func (x ActivityType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ActivityType.Descriptor instead.
 func (ActivityType) EnumDescriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{4}
+	return file_mail_proto_rawDescGZIP(), []int{8}
 }
 
 // TaskStatus represents the status of a task.
@@ -348,11 +575,11 @@ func (x TaskStatus) String() string {
 }
 
 func (TaskStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_mail_proto_enumTypes[5].Descriptor()
+	return file_mail_proto_enumTypes[9].Descriptor()
 }
 
 func (TaskStatus) Type() protoreflect.EnumType {
-	return &file_mail_proto_enumTypes[5]
+	return &file_mail_proto_enumTypes[9]
 }
 
 func (x TaskStatus) Number() protoreflect.EnumNumber {
@@ -361,7 +588,7 @@ func (x TaskStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use TaskStatus.Descriptor instead.
 func (TaskStatus) EnumDescriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{5}
+	return file_mail_proto_rawDescGZIP(), []int{9}
 }
 
 // PlanReviewState represents the state of a plan review.
@@ -404,11 +631,11 @@ func (x PlanReviewState) String() string {
 }
 
 func (PlanReviewState) Descriptor() protoreflect.EnumDescriptor {
-	return file_mail_proto_enumTypes[6].Descriptor()
+	return file_mail_proto_enumTypes[10].Descriptor()
 }
 
 func (PlanReviewState) Type() protoreflect.EnumType {
-	return &file_mail_proto_enumTypes[6]
+	return &file_mail_proto_enumTypes[10]
 }
 
 func (x PlanReviewState) Number() protoreflect.EnumNumber {
@@ -417,7 +644,7 @@ func (x PlanReviewState) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PlanReviewState.Descriptor instead.
 func (PlanReviewState) EnumDescriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{6}
+	return file_mail_proto_rawDescGZIP(), []int{10}
 }
 
 // InboxMessage represents a message in an agent's inbox.
@@ -442,8 +669,12 @@ type InboxMessage struct {
 	ReadAt           *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=read_at,json=readAt,proto3" json:"read_at,omitempty"`                         // null if unread
 	AcknowledgedAt   *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=acknowledged_at,json=acknowledgedAt,proto3" json:"acknowledged_at,omitempty"` // null if not acked
 	RecipientNames   []string               `protobuf:"bytes,17,rep,name=recipient_names,json=recipientNames,proto3" json:"recipient_names,omitempty"` // Names of all recipients.
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	EditedAt         *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=edited_at,json=editedAt,proto3" json:"edited_at,omitempty"`                   // null if never edited
+	// Labels the agent filed the message under. Only set for a single
+	// agent's view of its inbox.
+	Labels        []string `protobuf:"bytes,19,rep,name=labels,proto3" json:"labels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *InboxMessage) Reset() {
@@ -595,6 +826,20 @@ func (x *InboxMessage) GetRecipientNames() []string {
 	return nil
 }
 
+func (x *InboxMessage) GetEditedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EditedAt
+	}
+	return nil
+}
+
+func (x *InboxMessage) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
 // SendMailRequest is the request for SendMail.
 type SendMailRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
@@ -608,6 +853,9 @@ type SendMailRequest struct {
 	DeadlineAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=deadline_at,json=deadlineAt,proto3" json:"deadline_at,omitempty"`                // Optional deadline
 	AttachmentsJson string                 `protobuf:"bytes,9,opt,name=attachments_json,json=attachmentsJson,proto3" json:"attachments_json,omitempty"` // JSON string of attachments
 	IdempotencyKey  string                 `protobuf:"bytes,10,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`   // Optional key for deduplication
+	DeliveryMode    DeliveryMode           `protobuf:"varint,11,opt,name=delivery_mode,json=deliveryMode,proto3,enum=subtraterpc.DeliveryMode" json:"delivery_mode,omitempty"`
+	SendAt          *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"`                                                                 // Optional, schedules the send
+	Metadata        map[string]string      `protobuf:"bytes,13,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Optional, matched by inbox rules
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -712,11 +960,39 @@ func (x *SendMailRequest) GetIdempotencyKey() string {
 	return ""
 }
 
+func (x *SendMailRequest) GetDeliveryMode() DeliveryMode {
+	if x != nil {
+		return x.DeliveryMode
+	}
+	return DeliveryMode_DELIVERY_MODE_ATOMIC
+}
+
+func (x *SendMailRequest) GetSendAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SendAt
+	}
+	return nil
+}
+
+func (x *SendMailRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 // SendMailResponse is the response for SendMail.
 type SendMailResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MessageId     int64                  `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	MessageId int64                  `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ThreadId  string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	// expansions lists the agents each recipient group or alias resolved to.
+	Expansions []*RecipientExpansion `protobuf:"bytes,3,rep,name=expansions,proto3" json:"expansions,omitempty"`
+	// deliveries reports the outcome for each recipient, in addressed order.
+	Deliveries []*RecipientDelivery `protobuf:"bytes,4,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	// scheduled_id is set instead of message_id when the send was deferred
+	// to send_at.
+	ScheduledId   int64 `protobuf:"varint,5,opt,name=scheduled_id,json=scheduledId,proto3" json:"scheduled_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -765,41 +1041,52 @@ func (x *SendMailResponse) GetThreadId() string {
 	return ""
 }
 
-// FetchInboxRequest is the request for FetchInbox.
-type FetchInboxRequest struct {
-	state       protoimpl.MessageState `protogen:"open.v1"`
-	AgentId     int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	Limit       int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                                                              // Maximum messages to return
-	UnreadOnly  bool                   `protobuf:"varint,3,opt,name=unread_only,json=unreadOnly,proto3" json:"unread_only,omitempty"`                                  // Only return unread messages
-	StateFilter MessageState           `protobuf:"varint,4,opt,name=state_filter,json=stateFilter,proto3,enum=subtraterpc.MessageState" json:"state_filter,omitempty"` // Filter by state
-	SentOnly    bool                   `protobuf:"varint,5,opt,name=sent_only,json=sentOnly,proto3" json:"sent_only,omitempty"`                                        // Only return messages sent by the agent
-	// sender_name_prefix filters to messages from agents whose name starts with
-	// this prefix (e.g., "reviewer-" for CodeReviewer aggregate).
-	SenderNamePrefix string `protobuf:"bytes,6,opt,name=sender_name_prefix,json=senderNamePrefix,proto3" json:"sender_name_prefix,omitempty"`
-	Offset           int32  `protobuf:"varint,7,opt,name=offset,proto3" json:"offset,omitempty"` // Number of messages to skip for pagination
-	// category filters by inbox category. Recognized values: "primary"
-	// (everything that isn't hook-generated chatter) and
-	// "notifications" (hook-generated Permission/Idle/Status/
-	// Notification messages). Empty means no category filter.
-	Category      string `protobuf:"bytes,8,opt,name=category,proto3" json:"category,omitempty"`
+func (x *SendMailResponse) GetExpansions() []*RecipientExpansion {
+	if x != nil {
+		return x.Expansions
+	}
+	return nil
+}
+
+func (x *SendMailResponse) GetDeliveries() []*RecipientDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+func (x *SendMailResponse) GetScheduledId() int64 {
+	if x != nil {
+		return x.ScheduledId
+	}
+	return 0
+}
+
+// RecipientDelivery is the delivery outcome for one recipient of a message.
+type RecipientDelivery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Recipient     string                 `protobuf:"bytes,1,opt,name=recipient,proto3" json:"recipient,omitempty"`             // Agent name, or the address if unresolved.
+	AgentId       int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Zero for unknown recipients.
+	Status        RecipientStatus        `protobuf:"varint,3,opt,name=status,proto3,enum=subtraterpc.RecipientStatus" json:"status,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FetchInboxRequest) Reset() {
-	*x = FetchInboxRequest{}
+func (x *RecipientDelivery) Reset() {
+	*x = RecipientDelivery{}
 	mi := &file_mail_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *FetchInboxRequest) String() string {
+func (x *RecipientDelivery) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FetchInboxRequest) ProtoMessage() {}
+func (*RecipientDelivery) ProtoMessage() {}
 
-func (x *FetchInboxRequest) ProtoReflect() protoreflect.Message {
+func (x *RecipientDelivery) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -811,65 +1098,218 @@ func (x *FetchInboxRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FetchInboxRequest.ProtoReflect.Descriptor instead.
-func (*FetchInboxRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RecipientDelivery.ProtoReflect.Descriptor instead.
+func (*RecipientDelivery) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *FetchInboxRequest) GetAgentId() int64 {
+func (x *RecipientDelivery) GetRecipient() string {
 	if x != nil {
-		return x.AgentId
+		return x.Recipient
 	}
-	return 0
+	return ""
 }
 
-func (x *FetchInboxRequest) GetLimit() int32 {
+func (x *RecipientDelivery) GetAgentId() int64 {
 	if x != nil {
-		return x.Limit
+		return x.AgentId
 	}
 	return 0
 }
 
-func (x *FetchInboxRequest) GetUnreadOnly() bool {
+func (x *RecipientDelivery) GetStatus() RecipientStatus {
 	if x != nil {
-		return x.UnreadOnly
+		return x.Status
 	}
-	return false
+	return RecipientStatus_RECIPIENT_STATUS_UNSPECIFIED
 }
 
-func (x *FetchInboxRequest) GetStateFilter() MessageState {
+func (x *RecipientDelivery) GetReason() string {
 	if x != nil {
-		return x.StateFilter
+		return x.Reason
 	}
-	return MessageState_STATE_UNSPECIFIED
+	return ""
 }
 
-func (x *FetchInboxRequest) GetSentOnly() bool {
-	if x != nil {
-		return x.SentOnly
-	}
-	return false
+// RecipientExpansion records the agents a recipient group or dynamic alias
+// such as "@backend-team" or "@branch:main" resolved to at send time.
+type RecipientExpansion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	AgentNames    []string               `protobuf:"bytes,2,rep,name=agent_names,json=agentNames,proto3" json:"agent_names,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *FetchInboxRequest) GetSenderNamePrefix() string {
+func (x *RecipientExpansion) Reset() {
+	*x = RecipientExpansion{}
+	mi := &file_mail_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecipientExpansion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecipientExpansion) ProtoMessage() {}
+
+func (x *RecipientExpansion) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[4]
 	if x != nil {
-		return x.SenderNamePrefix
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *FetchInboxRequest) GetOffset() int32 {
+// Deprecated: Use RecipientExpansion.ProtoReflect.Descriptor instead.
+func (*RecipientExpansion) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RecipientExpansion) GetAddress() string {
 	if x != nil {
-		return x.Offset
+		return x.Address
 	}
-	return 0
+	return ""
 }
 
-func (x *FetchInboxRequest) GetCategory() string {
+func (x *RecipientExpansion) GetAgentNames() []string {
 	if x != nil {
-		return x.Category
+		return x.AgentNames
 	}
-	return ""
+	return nil
+}
+
+// FetchInboxRequest is the request for FetchInbox.
+type FetchInboxRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	AgentId     int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Limit       int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                                                              // Maximum messages to return
+	UnreadOnly  bool                   `protobuf:"varint,3,opt,name=unread_only,json=unreadOnly,proto3" json:"unread_only,omitempty"`                                  // Only return unread messages
+	StateFilter MessageState           `protobuf:"varint,4,opt,name=state_filter,json=stateFilter,proto3,enum=subtraterpc.MessageState" json:"state_filter,omitempty"` // Filter by state
+	SentOnly    bool                   `protobuf:"varint,5,opt,name=sent_only,json=sentOnly,proto3" json:"sent_only,omitempty"`                                        // Only return messages sent by the agent
+	// sender_name_prefix filters to messages from agents whose name starts with
+	// this prefix (e.g., "reviewer-" for CodeReviewer aggregate).
+	SenderNamePrefix string `protobuf:"bytes,6,opt,name=sender_name_prefix,json=senderNamePrefix,proto3" json:"sender_name_prefix,omitempty"`
+	Offset           int32  `protobuf:"varint,7,opt,name=offset,proto3" json:"offset,omitempty"` // Number of messages to skip for pagination
+	// category filters by inbox category. Recognized values: "primary"
+	// (everything that isn't hook-generated chatter) and
+	// "notifications" (hook-generated Permission/Idle/Status/
+	// Notification messages). Empty means no category filter.
+	Category string `protobuf:"bytes,8,opt,name=category,proto3" json:"category,omitempty"`
+	// overdue_only restricts the results to unacked messages whose deadline
+	// has passed, most overdue first.
+	OverdueOnly bool `protobuf:"varint,9,opt,name=overdue_only,json=overdueOnly,proto3" json:"overdue_only,omitempty"`
+	// label restricts the results to messages the agent filed under the
+	// label, in any state but trash.
+	Label         string `protobuf:"bytes,10,opt,name=label,proto3" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FetchInboxRequest) Reset() {
+	*x = FetchInboxRequest{}
+	mi := &file_mail_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchInboxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchInboxRequest) ProtoMessage() {}
+
+func (x *FetchInboxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchInboxRequest.ProtoReflect.Descriptor instead.
+func (*FetchInboxRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FetchInboxRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *FetchInboxRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *FetchInboxRequest) GetUnreadOnly() bool {
+	if x != nil {
+		return x.UnreadOnly
+	}
+	return false
+}
+
+func (x *FetchInboxRequest) GetStateFilter() MessageState {
+	if x != nil {
+		return x.StateFilter
+	}
+	return MessageState_STATE_UNSPECIFIED
+}
+
+func (x *FetchInboxRequest) GetSentOnly() bool {
+	if x != nil {
+		return x.SentOnly
+	}
+	return false
+}
+
+func (x *FetchInboxRequest) GetSenderNamePrefix() string {
+	if x != nil {
+		return x.SenderNamePrefix
+	}
+	return ""
+}
+
+func (x *FetchInboxRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *FetchInboxRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *FetchInboxRequest) GetOverdueOnly() bool {
+	if x != nil {
+		return x.OverdueOnly
+	}
+	return false
+}
+
+func (x *FetchInboxRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
 }
 
 // FetchInboxResponse is the response for FetchInbox.
@@ -886,7 +1326,7 @@ type FetchInboxResponse struct {
 
 func (x *FetchInboxResponse) Reset() {
 	*x = FetchInboxResponse{}
-	mi := &file_mail_proto_msgTypes[4]
+	mi := &file_mail_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -898,7 +1338,7 @@ func (x *FetchInboxResponse) String() string {
 func (*FetchInboxResponse) ProtoMessage() {}
 
 func (x *FetchInboxResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[4]
+	mi := &file_mail_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -911,7 +1351,7 @@ func (x *FetchInboxResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FetchInboxResponse.ProtoReflect.Descriptor instead.
 func (*FetchInboxResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{4}
+	return file_mail_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *FetchInboxResponse) GetMessages() []*InboxMessage {
@@ -938,14 +1378,16 @@ type InboxCategoryCounts struct {
 	// spam doesn't dominate the unread badge).
 	Unread int64 `protobuf:"varint,4,opt,name=unread,proto3" json:"unread,omitempty"`
 	// Starred count across all categories.
-	Starred       int64 `protobuf:"varint,5,opt,name=starred,proto3" json:"starred,omitempty"`
+	Starred int64 `protobuf:"varint,5,opt,name=starred,proto3" json:"starred,omitempty"`
+	// Counts of each of the agent's labels, by name.
+	Labels        []*LabelProto `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *InboxCategoryCounts) Reset() {
 	*x = InboxCategoryCounts{}
-	mi := &file_mail_proto_msgTypes[5]
+	mi := &file_mail_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -957,7 +1399,7 @@ func (x *InboxCategoryCounts) String() string {
 func (*InboxCategoryCounts) ProtoMessage() {}
 
 func (x *InboxCategoryCounts) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[5]
+	mi := &file_mail_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -970,7 +1412,7 @@ func (x *InboxCategoryCounts) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use InboxCategoryCounts.ProtoReflect.Descriptor instead.
 func (*InboxCategoryCounts) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{5}
+	return file_mail_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *InboxCategoryCounts) GetPrimary() int64 {
@@ -1001,6 +1443,13 @@ func (x *InboxCategoryCounts) GetStarred() int64 {
 	return 0
 }
 
+func (x *InboxCategoryCounts) GetLabels() []*LabelProto {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
 // ReadMessageRequest is the request for ReadMessage.
 type ReadMessageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1012,7 +1461,7 @@ type ReadMessageRequest struct {
 
 func (x *ReadMessageRequest) Reset() {
 	*x = ReadMessageRequest{}
-	mi := &file_mail_proto_msgTypes[6]
+	mi := &file_mail_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1024,7 +1473,7 @@ func (x *ReadMessageRequest) String() string {
 func (*ReadMessageRequest) ProtoMessage() {}
 
 func (x *ReadMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[6]
+	mi := &file_mail_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1037,7 +1486,7 @@ func (x *ReadMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadMessageRequest.ProtoReflect.Descriptor instead.
 func (*ReadMessageRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{6}
+	return file_mail_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ReadMessageRequest) GetAgentId() int64 {
@@ -1056,15 +1505,21 @@ func (x *ReadMessageRequest) GetMessageId() int64 {
 
 // ReadMessageResponse is the response for ReadMessage.
 type ReadMessageResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       *InboxMessage          `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message *InboxMessage          `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// expansions lists the agents each recipient group or alias resolved to
+	// when the message was sent.
+	Expansions []*RecipientExpansion `protobuf:"bytes,2,rep,name=expansions,proto3" json:"expansions,omitempty"`
+	// revisions lists the versions of the message that edits replaced,
+	// oldest first.
+	Revisions     []*MessageRevision `protobuf:"bytes,3,rep,name=revisions,proto3" json:"revisions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ReadMessageResponse) Reset() {
 	*x = ReadMessageResponse{}
-	mi := &file_mail_proto_msgTypes[7]
+	mi := &file_mail_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1076,7 +1531,7 @@ func (x *ReadMessageResponse) String() string {
 func (*ReadMessageResponse) ProtoMessage() {}
 
 func (x *ReadMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[7]
+	mi := &file_mail_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1089,7 +1544,7 @@ func (x *ReadMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadMessageResponse.ProtoReflect.Descriptor instead.
 func (*ReadMessageResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{7}
+	return file_mail_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ReadMessageResponse) GetMessage() *InboxMessage {
@@ -1099,6 +1554,20 @@ func (x *ReadMessageResponse) GetMessage() *InboxMessage {
 	return nil
 }
 
+func (x *ReadMessageResponse) GetExpansions() []*RecipientExpansion {
+	if x != nil {
+		return x.Expansions
+	}
+	return nil
+}
+
+func (x *ReadMessageResponse) GetRevisions() []*MessageRevision {
+	if x != nil {
+		return x.Revisions
+	}
+	return nil
+}
+
 // ReadThreadRequest is the request for ReadThread.
 type ReadThreadRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1110,7 +1579,7 @@ type ReadThreadRequest struct {
 
 func (x *ReadThreadRequest) Reset() {
 	*x = ReadThreadRequest{}
-	mi := &file_mail_proto_msgTypes[8]
+	mi := &file_mail_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1122,7 +1591,7 @@ func (x *ReadThreadRequest) String() string {
 func (*ReadThreadRequest) ProtoMessage() {}
 
 func (x *ReadThreadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[8]
+	mi := &file_mail_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1135,7 +1604,7 @@ func (x *ReadThreadRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadThreadRequest.ProtoReflect.Descriptor instead.
 func (*ReadThreadRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{8}
+	return file_mail_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ReadThreadRequest) GetAgentId() int64 {
@@ -1154,15 +1623,18 @@ func (x *ReadThreadRequest) GetThreadId() string {
 
 // ReadThreadResponse is the response for ReadThread.
 type ReadThreadResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Messages      []*InboxMessage        `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Messages []*InboxMessage        `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	// revisions lists the versions that edits replaced for every edited
+	// message in the thread, grouped by message and oldest first.
+	Revisions     []*MessageRevision `protobuf:"bytes,2,rep,name=revisions,proto3" json:"revisions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ReadThreadResponse) Reset() {
 	*x = ReadThreadResponse{}
-	mi := &file_mail_proto_msgTypes[9]
+	mi := &file_mail_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1174,7 +1646,7 @@ func (x *ReadThreadResponse) String() string {
 func (*ReadThreadResponse) ProtoMessage() {}
 
 func (x *ReadThreadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[9]
+	mi := &file_mail_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1187,7 +1659,7 @@ func (x *ReadThreadResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadThreadResponse.ProtoReflect.Descriptor instead.
 func (*ReadThreadResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{9}
+	return file_mail_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ReadThreadResponse) GetMessages() []*InboxMessage {
@@ -1197,6 +1669,13 @@ func (x *ReadThreadResponse) GetMessages() []*InboxMessage {
 	return nil
 }
 
+func (x *ReadThreadResponse) GetRevisions() []*MessageRevision {
+	if x != nil {
+		return x.Revisions
+	}
+	return nil
+}
+
 // UpdateStateRequest is the request for UpdateState.
 type UpdateStateRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1210,7 +1689,7 @@ type UpdateStateRequest struct {
 
 func (x *UpdateStateRequest) Reset() {
 	*x = UpdateStateRequest{}
-	mi := &file_mail_proto_msgTypes[10]
+	mi := &file_mail_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1222,7 +1701,7 @@ func (x *UpdateStateRequest) String() string {
 func (*UpdateStateRequest) ProtoMessage() {}
 
 func (x *UpdateStateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[10]
+	mi := &file_mail_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1235,7 +1714,7 @@ func (x *UpdateStateRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateStateRequest.ProtoReflect.Descriptor instead.
 func (*UpdateStateRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{10}
+	return file_mail_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *UpdateStateRequest) GetAgentId() int64 {
@@ -1276,7 +1755,7 @@ type UpdateStateResponse struct {
 
 func (x *UpdateStateResponse) Reset() {
 	*x = UpdateStateResponse{}
-	mi := &file_mail_proto_msgTypes[11]
+	mi := &file_mail_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1288,7 +1767,7 @@ func (x *UpdateStateResponse) String() string {
 func (*UpdateStateResponse) ProtoMessage() {}
 
 func (x *UpdateStateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[11]
+	mi := &file_mail_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1301,7 +1780,7 @@ func (x *UpdateStateResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateStateResponse.ProtoReflect.Descriptor instead.
 func (*UpdateStateResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{11}
+	return file_mail_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *UpdateStateResponse) GetSuccess() bool {
@@ -1322,7 +1801,7 @@ type AckMessageRequest struct {
 
 func (x *AckMessageRequest) Reset() {
 	*x = AckMessageRequest{}
-	mi := &file_mail_proto_msgTypes[12]
+	mi := &file_mail_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1334,7 +1813,7 @@ func (x *AckMessageRequest) String() string {
 func (*AckMessageRequest) ProtoMessage() {}
 
 func (x *AckMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[12]
+	mi := &file_mail_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1347,7 +1826,7 @@ func (x *AckMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AckMessageRequest.ProtoReflect.Descriptor instead.
 func (*AckMessageRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{12}
+	return file_mail_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *AckMessageRequest) GetAgentId() int64 {
@@ -1374,7 +1853,7 @@ type AckMessageResponse struct {
 
 func (x *AckMessageResponse) Reset() {
 	*x = AckMessageResponse{}
-	mi := &file_mail_proto_msgTypes[13]
+	mi := &file_mail_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1386,7 +1865,7 @@ func (x *AckMessageResponse) String() string {
 func (*AckMessageResponse) ProtoMessage() {}
 
 func (x *AckMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[13]
+	mi := &file_mail_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1399,7 +1878,7 @@ func (x *AckMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AckMessageResponse.ProtoReflect.Descriptor instead.
 func (*AckMessageResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{13}
+	return file_mail_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *AckMessageResponse) GetSuccess() bool {
@@ -1419,7 +1898,7 @@ type GetStatusRequest struct {
 
 func (x *GetStatusRequest) Reset() {
 	*x = GetStatusRequest{}
-	mi := &file_mail_proto_msgTypes[14]
+	mi := &file_mail_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1431,7 +1910,7 @@ func (x *GetStatusRequest) String() string {
 func (*GetStatusRequest) ProtoMessage() {}
 
 func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[14]
+	mi := &file_mail_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1444,7 +1923,7 @@ func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
 func (*GetStatusRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{14}
+	return file_mail_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GetStatusRequest) GetAgentId() int64 {
@@ -1469,7 +1948,7 @@ type GetStatusResponse struct {
 
 func (x *GetStatusResponse) Reset() {
 	*x = GetStatusResponse{}
-	mi := &file_mail_proto_msgTypes[15]
+	mi := &file_mail_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1481,7 +1960,7 @@ func (x *GetStatusResponse) String() string {
 func (*GetStatusResponse) ProtoMessage() {}
 
 func (x *GetStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[15]
+	mi := &file_mail_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1494,7 +1973,7 @@ func (x *GetStatusResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetStatusResponse.ProtoReflect.Descriptor instead.
 func (*GetStatusResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{15}
+	return file_mail_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetStatusResponse) GetAgentId() int64 {
@@ -1550,7 +2029,7 @@ type PollChangesRequest struct {
 
 func (x *PollChangesRequest) Reset() {
 	*x = PollChangesRequest{}
-	mi := &file_mail_proto_msgTypes[16]
+	mi := &file_mail_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1562,7 +2041,7 @@ func (x *PollChangesRequest) String() string {
 func (*PollChangesRequest) ProtoMessage() {}
 
 func (x *PollChangesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[16]
+	mi := &file_mail_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1575,7 +2054,7 @@ func (x *PollChangesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PollChangesRequest.ProtoReflect.Descriptor instead.
 func (*PollChangesRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{16}
+	return file_mail_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *PollChangesRequest) GetAgentId() int64 {
@@ -1603,7 +2082,7 @@ type PollChangesResponse struct {
 
 func (x *PollChangesResponse) Reset() {
 	*x = PollChangesResponse{}
-	mi := &file_mail_proto_msgTypes[17]
+	mi := &file_mail_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1615,7 +2094,7 @@ func (x *PollChangesResponse) String() string {
 func (*PollChangesResponse) ProtoMessage() {}
 
 func (x *PollChangesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[17]
+	mi := &file_mail_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1628,7 +2107,7 @@ func (x *PollChangesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PollChangesResponse.ProtoReflect.Descriptor instead.
 func (*PollChangesResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{17}
+	return file_mail_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *PollChangesResponse) GetNewMessages() []*InboxMessage {
@@ -1655,7 +2134,7 @@ type SubscribeInboxRequest struct {
 
 func (x *SubscribeInboxRequest) Reset() {
 	*x = SubscribeInboxRequest{}
-	mi := &file_mail_proto_msgTypes[18]
+	mi := &file_mail_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1667,7 +2146,7 @@ func (x *SubscribeInboxRequest) String() string {
 func (*SubscribeInboxRequest) ProtoMessage() {}
 
 func (x *SubscribeInboxRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[18]
+	mi := &file_mail_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1680,7 +2159,7 @@ func (x *SubscribeInboxRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubscribeInboxRequest.ProtoReflect.Descriptor instead.
 func (*SubscribeInboxRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{18}
+	return file_mail_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *SubscribeInboxRequest) GetAgentId() int64 {
@@ -1690,34 +2169,39 @@ func (x *SubscribeInboxRequest) GetAgentId() int64 {
 	return 0
 }
 
-// PublishRequest is the request for Publish.
-type PublishRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	SenderId       int64                  `protobuf:"varint,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
-	TopicName      string                 `protobuf:"bytes,2,opt,name=topic_name,json=topicName,proto3" json:"topic_name,omitempty"`
-	Subject        string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
-	Body           string                 `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
-	Priority       Priority               `protobuf:"varint,5,opt,name=priority,proto3,enum=subtraterpc.Priority" json:"priority,omitempty"`
-	IdempotencyKey string                 `protobuf:"bytes,6,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // Optional key for deduplication
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+// WatchInboxRequest is the request for WatchInbox. The wake filters narrow
+// the mail that wakes the watcher; each empty one matches any message.
+type WatchInboxRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AgentId         int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Holder          string                 `protobuf:"bytes,2,opt,name=holder,proto3" json:"holder,omitempty"`                                          // Identifies the watcher, e.g. host:pid
+	AfterMessageId  int64                  `protobuf:"varint,3,opt,name=after_message_id,json=afterMessageId,proto3" json:"after_message_id,omitempty"` // Only wake for newer mail
+	Priorities      []Priority             `protobuf:"varint,4,rep,packed,name=priorities,proto3,enum=subtraterpc.Priority" json:"priorities,omitempty"`
+	SenderNames     []string               `protobuf:"bytes,5,rep,name=sender_names,json=senderNames,proto3" json:"sender_names,omitempty"`
+	TopicNames      []string               `protobuf:"bytes,6,rep,name=topic_names,json=topicNames,proto3" json:"topic_names,omitempty"`
+	ThreadIds       []string               `protobuf:"bytes,7,rep,name=thread_ids,json=threadIds,proto3" json:"thread_ids,omitempty"`
+	SavedSearch     string                 `protobuf:"bytes,8,opt,name=saved_search,json=savedSearch,proto3" json:"saved_search,omitempty"`                // Only wake for its matches
+	LeaseTtlSeconds int32                  `protobuf:"varint,9,opt,name=lease_ttl_seconds,json=leaseTtlSeconds,proto3" json:"lease_ttl_seconds,omitempty"` // 0 = server default
+	Takeover        bool                   `protobuf:"varint,10,opt,name=takeover,proto3" json:"takeover,omitempty"`                                       // Take the lease from a live watcher
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *PublishRequest) Reset() {
-	*x = PublishRequest{}
-	mi := &file_mail_proto_msgTypes[19]
+func (x *WatchInboxRequest) Reset() {
+	*x = WatchInboxRequest{}
+	mi := &file_mail_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PublishRequest) String() string {
+func (x *WatchInboxRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PublishRequest) ProtoMessage() {}
+func (*WatchInboxRequest) ProtoMessage() {}
 
-func (x *PublishRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[19]
+func (x *WatchInboxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1728,9 +2212,290 @@ func (x *PublishRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PublishRequest.ProtoReflect.Descriptor instead.
-func (*PublishRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use WatchInboxRequest.ProtoReflect.Descriptor instead.
+func (*WatchInboxRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *WatchInboxRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *WatchInboxRequest) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+func (x *WatchInboxRequest) GetAfterMessageId() int64 {
+	if x != nil {
+		return x.AfterMessageId
+	}
+	return 0
+}
+
+func (x *WatchInboxRequest) GetPriorities() []Priority {
+	if x != nil {
+		return x.Priorities
+	}
+	return nil
+}
+
+func (x *WatchInboxRequest) GetSenderNames() []string {
+	if x != nil {
+		return x.SenderNames
+	}
+	return nil
+}
+
+func (x *WatchInboxRequest) GetTopicNames() []string {
+	if x != nil {
+		return x.TopicNames
+	}
+	return nil
+}
+
+func (x *WatchInboxRequest) GetThreadIds() []string {
+	if x != nil {
+		return x.ThreadIds
+	}
+	return nil
+}
+
+func (x *WatchInboxRequest) GetSavedSearch() string {
+	if x != nil {
+		return x.SavedSearch
+	}
+	return ""
+}
+
+func (x *WatchInboxRequest) GetLeaseTtlSeconds() int32 {
+	if x != nil {
+		return x.LeaseTtlSeconds
+	}
+	return 0
+}
+
+func (x *WatchInboxRequest) GetTakeover() bool {
+	if x != nil {
+		return x.Takeover
+	}
+	return false
+}
+
+// WatchInboxEvent is an event on a WatchInbox stream.
+type WatchInboxEvent struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Type           WatchEventType         `protobuf:"varint,1,opt,name=type,proto3,enum=subtraterpc.WatchEventType" json:"type,omitempty"`
+	Messages       []*InboxMessage        `protobuf:"bytes,2,rep,name=messages,proto3" json:"messages,omitempty"` // Set for wakes
+	LeaseExpiresAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=lease_expires_at,json=leaseExpiresAt,proto3" json:"lease_expires_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WatchInboxEvent) Reset() {
+	*x = WatchInboxEvent{}
+	mi := &file_mail_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchInboxEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchInboxEvent) ProtoMessage() {}
+
+func (x *WatchInboxEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchInboxEvent.ProtoReflect.Descriptor instead.
+func (*WatchInboxEvent) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *WatchInboxEvent) GetType() WatchEventType {
+	if x != nil {
+		return x.Type
+	}
+	return WatchEventType_WATCH_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *WatchInboxEvent) GetMessages() []*InboxMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *WatchInboxEvent) GetLeaseExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LeaseExpiresAt
+	}
+	return nil
+}
+
+// GetWatcherRequest is the request for GetWatcher.
+type GetWatcherRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWatcherRequest) Reset() {
+	*x = GetWatcherRequest{}
+	mi := &file_mail_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWatcherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWatcherRequest) ProtoMessage() {}
+
+func (x *GetWatcherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWatcherRequest.ProtoReflect.Descriptor instead.
+func (*GetWatcherRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetWatcherRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+// GetWatcherResponse is the response for GetWatcher.
+type GetWatcherResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Armed          bool                   `protobuf:"varint,1,opt,name=armed,proto3" json:"armed,omitempty"`
+	Holder         string                 `protobuf:"bytes,2,opt,name=holder,proto3" json:"holder,omitempty"`
+	LeaseExpiresAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=lease_expires_at,json=leaseExpiresAt,proto3" json:"lease_expires_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetWatcherResponse) Reset() {
+	*x = GetWatcherResponse{}
+	mi := &file_mail_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWatcherResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWatcherResponse) ProtoMessage() {}
+
+func (x *GetWatcherResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWatcherResponse.ProtoReflect.Descriptor instead.
+func (*GetWatcherResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetWatcherResponse) GetArmed() bool {
+	if x != nil {
+		return x.Armed
+	}
+	return false
+}
+
+func (x *GetWatcherResponse) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+func (x *GetWatcherResponse) GetLeaseExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LeaseExpiresAt
+	}
+	return nil
+}
+
+// PublishRequest is the request for Publish.
+type PublishRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SenderId       int64                  `protobuf:"varint,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	TopicName      string                 `protobuf:"bytes,2,opt,name=topic_name,json=topicName,proto3" json:"topic_name,omitempty"`
+	Subject        string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	Body           string                 `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	Priority       Priority               `protobuf:"varint,5,opt,name=priority,proto3,enum=subtraterpc.Priority" json:"priority,omitempty"`
+	IdempotencyKey string                 `protobuf:"bytes,6,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`                                         // Optional key for deduplication
+	Metadata       map[string]string      `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Optional, matched by inbox rules
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PublishRequest) Reset() {
+	*x = PublishRequest{}
+	mi := &file_mail_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishRequest) ProtoMessage() {}
+
+func (x *PublishRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishRequest.ProtoReflect.Descriptor instead.
+func (*PublishRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *PublishRequest) GetSenderId() int64 {
@@ -1775,18 +2540,26 @@ func (x *PublishRequest) GetIdempotencyKey() string {
 	return ""
 }
 
+func (x *PublishRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 // PublishResponse is the response for Publish.
 type PublishResponse struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	MessageId       int64                  `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
 	RecipientsCount int32                  `protobuf:"varint,2,opt,name=recipients_count,json=recipientsCount,proto3" json:"recipients_count,omitempty"`
+	Queued          bool                   `protobuf:"varint,3,opt,name=queued,proto3" json:"queued,omitempty"` // Queue topic: waits to be claimed
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
 func (x *PublishResponse) Reset() {
 	*x = PublishResponse{}
-	mi := &file_mail_proto_msgTypes[20]
+	mi := &file_mail_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1798,7 +2571,7 @@ func (x *PublishResponse) String() string {
 func (*PublishResponse) ProtoMessage() {}
 
 func (x *PublishResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[20]
+	mi := &file_mail_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1811,7 +2584,7 @@ func (x *PublishResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PublishResponse.ProtoReflect.Descriptor instead.
 func (*PublishResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{20}
+	return file_mail_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *PublishResponse) GetMessageId() int64 {
@@ -1828,18 +2601,26 @@ func (x *PublishResponse) GetRecipientsCount() int32 {
 	return 0
 }
 
+func (x *PublishResponse) GetQueued() bool {
+	if x != nil {
+		return x.Queued
+	}
+	return false
+}
+
 // SubscribeRequest is the request for Subscribe.
 type SubscribeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 	TopicName     string                 `protobuf:"bytes,2,opt,name=topic_name,json=topicName,proto3" json:"topic_name,omitempty"`
+	TopicType     string                 `protobuf:"bytes,3,opt,name=topic_type,json=topicType,proto3" json:"topic_type,omitempty"` // Type for new topics (default: broadcast)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SubscribeRequest) Reset() {
 	*x = SubscribeRequest{}
-	mi := &file_mail_proto_msgTypes[21]
+	mi := &file_mail_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1851,7 +2632,7 @@ func (x *SubscribeRequest) String() string {
 func (*SubscribeRequest) ProtoMessage() {}
 
 func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[21]
+	mi := &file_mail_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1864,7 +2645,7 @@ func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
 func (*SubscribeRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{21}
+	return file_mail_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *SubscribeRequest) GetAgentId() int64 {
@@ -1881,6 +2662,13 @@ func (x *SubscribeRequest) GetTopicName() string {
 	return ""
 }
 
+func (x *SubscribeRequest) GetTopicType() string {
+	if x != nil {
+		return x.TopicType
+	}
+	return ""
+}
+
 // SubscribeResponse is the response for Subscribe.
 type SubscribeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1892,7 +2680,7 @@ type SubscribeResponse struct {
 
 func (x *SubscribeResponse) Reset() {
 	*x = SubscribeResponse{}
-	mi := &file_mail_proto_msgTypes[22]
+	mi := &file_mail_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1904,7 +2692,7 @@ func (x *SubscribeResponse) String() string {
 func (*SubscribeResponse) ProtoMessage() {}
 
 func (x *SubscribeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[22]
+	mi := &file_mail_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1917,7 +2705,7 @@ func (x *SubscribeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubscribeResponse.ProtoReflect.Descriptor instead.
 func (*SubscribeResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{22}
+	return file_mail_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *SubscribeResponse) GetSuccess() bool {
@@ -1945,7 +2733,7 @@ type UnsubscribeRequest struct {
 
 func (x *UnsubscribeRequest) Reset() {
 	*x = UnsubscribeRequest{}
-	mi := &file_mail_proto_msgTypes[23]
+	mi := &file_mail_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1957,7 +2745,7 @@ func (x *UnsubscribeRequest) String() string {
 func (*UnsubscribeRequest) ProtoMessage() {}
 
 func (x *UnsubscribeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[23]
+	mi := &file_mail_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1970,7 +2758,7 @@ func (x *UnsubscribeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UnsubscribeRequest.ProtoReflect.Descriptor instead.
 func (*UnsubscribeRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{23}
+	return file_mail_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *UnsubscribeRequest) GetAgentId() int64 {
@@ -1997,7 +2785,7 @@ type UnsubscribeResponse struct {
 
 func (x *UnsubscribeResponse) Reset() {
 	*x = UnsubscribeResponse{}
-	mi := &file_mail_proto_msgTypes[24]
+	mi := &file_mail_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2009,7 +2797,7 @@ func (x *UnsubscribeResponse) String() string {
 func (*UnsubscribeResponse) ProtoMessage() {}
 
 func (x *UnsubscribeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[24]
+	mi := &file_mail_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2022,7 +2810,7 @@ func (x *UnsubscribeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UnsubscribeResponse.ProtoReflect.Descriptor instead.
 func (*UnsubscribeResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{24}
+	return file_mail_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *UnsubscribeResponse) GetSuccess() bool {
@@ -2032,33 +2820,31 @@ func (x *UnsubscribeResponse) GetSuccess() bool {
 	return false
 }
 
-// Topic represents a pub/sub topic.
-type Topic struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	TopicType     string                 `protobuf:"bytes,3,opt,name=topic_type,json=topicType,proto3" json:"topic_type,omitempty"` // "direct", "broadcast", "queue"
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	MessageCount  int64                  `protobuf:"varint,5,opt,name=message_count,json=messageCount,proto3" json:"message_count,omitempty"` // Number of messages in this topic
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// ClaimQueueMessageRequest is the request for ClaimQueueMessage.
+type ClaimQueueMessageRequest struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	AgentId                  int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	TopicName                string                 `protobuf:"bytes,2,opt,name=topic_name,json=topicName,proto3" json:"topic_name,omitempty"`
+	VisibilityTimeoutSeconds int64                  `protobuf:"varint,3,opt,name=visibility_timeout_seconds,json=visibilityTimeoutSeconds,proto3" json:"visibility_timeout_seconds,omitempty"` // 0 for the server default
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
 }
 
-func (x *Topic) Reset() {
-	*x = Topic{}
-	mi := &file_mail_proto_msgTypes[25]
+func (x *ClaimQueueMessageRequest) Reset() {
+	*x = ClaimQueueMessageRequest{}
+	mi := &file_mail_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Topic) String() string {
+func (x *ClaimQueueMessageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Topic) ProtoMessage() {}
+func (*ClaimQueueMessageRequest) ProtoMessage() {}
 
-func (x *Topic) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[25]
+func (x *ClaimQueueMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2069,70 +2855,58 @@ func (x *Topic) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Topic.ProtoReflect.Descriptor instead.
-func (*Topic) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use ClaimQueueMessageRequest.ProtoReflect.Descriptor instead.
+func (*ClaimQueueMessageRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *Topic) GetId() int64 {
+func (x *ClaimQueueMessageRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Id
+		return x.AgentId
 	}
 	return 0
 }
 
-func (x *Topic) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *Topic) GetTopicType() string {
+func (x *ClaimQueueMessageRequest) GetTopicName() string {
 	if x != nil {
-		return x.TopicType
+		return x.TopicName
 	}
 	return ""
 }
 
-func (x *Topic) GetCreatedAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.CreatedAt
-	}
-	return nil
-}
-
-func (x *Topic) GetMessageCount() int64 {
+func (x *ClaimQueueMessageRequest) GetVisibilityTimeoutSeconds() int64 {
 	if x != nil {
-		return x.MessageCount
+		return x.VisibilityTimeoutSeconds
 	}
 	return 0
 }
 
-// ListTopicsRequest is the request for ListTopics.
-type ListTopicsRequest struct {
+// ClaimQueueMessageResponse is the response for ClaimQueueMessage.
+type ClaimQueueMessageResponse struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
-	AgentId        int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // If set, only show subscribed topics
-	SubscribedOnly bool                   `protobuf:"varint,2,opt,name=subscribed_only,json=subscribedOnly,proto3" json:"subscribed_only,omitempty"`
+	Claimed        bool                   `protobuf:"varint,1,opt,name=claimed,proto3" json:"claimed,omitempty"` // false if the queue is empty
+	Message        *InboxMessage          `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	LeaseExpiresAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=lease_expires_at,json=leaseExpiresAt,proto3" json:"lease_expires_at,omitempty"`
+	DeliveryCount  int32                  `protobuf:"varint,4,opt,name=delivery_count,json=deliveryCount,proto3" json:"delivery_count,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
-func (x *ListTopicsRequest) Reset() {
-	*x = ListTopicsRequest{}
-	mi := &file_mail_proto_msgTypes[26]
+func (x *ClaimQueueMessageResponse) Reset() {
+	*x = ClaimQueueMessageResponse{}
+	mi := &file_mail_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListTopicsRequest) String() string {
+func (x *ClaimQueueMessageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListTopicsRequest) ProtoMessage() {}
+func (*ClaimQueueMessageResponse) ProtoMessage() {}
 
-func (x *ListTopicsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[26]
+func (x *ClaimQueueMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2143,96 +2917,63 @@ func (x *ListTopicsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListTopicsRequest.ProtoReflect.Descriptor instead.
-func (*ListTopicsRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use ClaimQueueMessageResponse.ProtoReflect.Descriptor instead.
+func (*ClaimQueueMessageResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *ListTopicsRequest) GetAgentId() int64 {
+func (x *ClaimQueueMessageResponse) GetClaimed() bool {
 	if x != nil {
-		return x.AgentId
+		return x.Claimed
 	}
-	return 0
+	return false
 }
 
-func (x *ListTopicsRequest) GetSubscribedOnly() bool {
+func (x *ClaimQueueMessageResponse) GetMessage() *InboxMessage {
 	if x != nil {
-		return x.SubscribedOnly
+		return x.Message
 	}
-	return false
-}
-
-// ListTopicsResponse is the response for ListTopics.
-type ListTopicsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Topics        []*Topic               `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *ListTopicsResponse) Reset() {
-	*x = ListTopicsResponse{}
-	mi := &file_mail_proto_msgTypes[27]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *ListTopicsResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+	return nil
 }
 
-func (*ListTopicsResponse) ProtoMessage() {}
-
-func (x *ListTopicsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[27]
+func (x *ClaimQueueMessageResponse) GetLeaseExpiresAt() *timestamppb.Timestamp {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.LeaseExpiresAt
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use ListTopicsResponse.ProtoReflect.Descriptor instead.
-func (*ListTopicsResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{27}
+	return nil
 }
 
-func (x *ListTopicsResponse) GetTopics() []*Topic {
+func (x *ClaimQueueMessageResponse) GetDeliveryCount() int32 {
 	if x != nil {
-		return x.Topics
+		return x.DeliveryCount
 	}
-	return nil
+	return 0
 }
 
-// SearchRequest is the request for Search.
-type SearchRequest struct {
+// AckQueueMessageRequest is the request for AckQueueMessage.
+type AckQueueMessageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	Query         string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
-	TopicId       int64                  `protobuf:"varint,3,opt,name=topic_id,json=topicId,proto3" json:"topic_id,omitempty"` // Optional, filter by topic
-	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	MessageId     int64                  `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SearchRequest) Reset() {
-	*x = SearchRequest{}
-	mi := &file_mail_proto_msgTypes[28]
+func (x *AckQueueMessageRequest) Reset() {
+	*x = AckQueueMessageRequest{}
+	mi := &file_mail_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SearchRequest) String() string {
+func (x *AckQueueMessageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SearchRequest) ProtoMessage() {}
+func (*AckQueueMessageRequest) ProtoMessage() {}
 
-func (x *SearchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[28]
+func (x *AckQueueMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2243,62 +2984,48 @@ func (x *SearchRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
-func (*SearchRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use AckQueueMessageRequest.ProtoReflect.Descriptor instead.
+func (*AckQueueMessageRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *SearchRequest) GetAgentId() int64 {
+func (x *AckQueueMessageRequest) GetAgentId() int64 {
 	if x != nil {
 		return x.AgentId
 	}
 	return 0
 }
 
-func (x *SearchRequest) GetQuery() string {
-	if x != nil {
-		return x.Query
-	}
-	return ""
-}
-
-func (x *SearchRequest) GetTopicId() int64 {
-	if x != nil {
-		return x.TopicId
-	}
-	return 0
-}
-
-func (x *SearchRequest) GetLimit() int32 {
+func (x *AckQueueMessageRequest) GetMessageId() int64 {
 	if x != nil {
-		return x.Limit
+		return x.MessageId
 	}
 	return 0
 }
 
-// SearchResponse is the response for Search.
-type SearchResponse struct {
+// AckQueueMessageResponse is the response for AckQueueMessage.
+type AckQueueMessageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Results       []*InboxMessage        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SearchResponse) Reset() {
-	*x = SearchResponse{}
-	mi := &file_mail_proto_msgTypes[29]
+func (x *AckQueueMessageResponse) Reset() {
+	*x = AckQueueMessageResponse{}
+	mi := &file_mail_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SearchResponse) String() string {
+func (x *AckQueueMessageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SearchResponse) ProtoMessage() {}
+func (*AckQueueMessageResponse) ProtoMessage() {}
 
-func (x *SearchResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[29]
+func (x *AckQueueMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2309,42 +3036,43 @@ func (x *SearchResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
-func (*SearchResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use AckQueueMessageResponse.ProtoReflect.Descriptor instead.
+func (*AckQueueMessageResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *SearchResponse) GetResults() []*InboxMessage {
+func (x *AckQueueMessageResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Results
+		return x.Success
 	}
-	return nil
+	return false
 }
 
-// HasUnackedStatusToRequest is the request for HasUnackedStatusTo.
-type HasUnackedStatusToRequest struct {
+// ReleaseQueueMessageRequest is the request for ReleaseQueueMessage.
+type ReleaseQueueMessageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	SenderId      int64                  `protobuf:"varint,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
-	RecipientId   int64                  `protobuf:"varint,2,opt,name=recipient_id,json=recipientId,proto3" json:"recipient_id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	MessageId     int64                  `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // Recorded as the delivery's last error
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HasUnackedStatusToRequest) Reset() {
-	*x = HasUnackedStatusToRequest{}
-	mi := &file_mail_proto_msgTypes[30]
+func (x *ReleaseQueueMessageRequest) Reset() {
+	*x = ReleaseQueueMessageRequest{}
+	mi := &file_mail_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HasUnackedStatusToRequest) String() string {
+func (x *ReleaseQueueMessageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HasUnackedStatusToRequest) ProtoMessage() {}
+func (*ReleaseQueueMessageRequest) ProtoMessage() {}
 
-func (x *HasUnackedStatusToRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[30]
+func (x *ReleaseQueueMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2355,48 +3083,55 @@ func (x *HasUnackedStatusToRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HasUnackedStatusToRequest.ProtoReflect.Descriptor instead.
-func (*HasUnackedStatusToRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use ReleaseQueueMessageRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseQueueMessageRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *HasUnackedStatusToRequest) GetSenderId() int64 {
+func (x *ReleaseQueueMessageRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.SenderId
+		return x.AgentId
 	}
 	return 0
 }
 
-func (x *HasUnackedStatusToRequest) GetRecipientId() int64 {
+func (x *ReleaseQueueMessageRequest) GetMessageId() int64 {
 	if x != nil {
-		return x.RecipientId
+		return x.MessageId
 	}
 	return 0
 }
 
-// HasUnackedStatusToResponse is the response for HasUnackedStatusTo.
-type HasUnackedStatusToResponse struct {
+func (x *ReleaseQueueMessageRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// ReleaseQueueMessageResponse is the response for ReleaseQueueMessage.
+type ReleaseQueueMessageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	HasPending    bool                   `protobuf:"varint,1,opt,name=has_pending,json=hasPending,proto3" json:"has_pending,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HasUnackedStatusToResponse) Reset() {
-	*x = HasUnackedStatusToResponse{}
-	mi := &file_mail_proto_msgTypes[31]
+func (x *ReleaseQueueMessageResponse) Reset() {
+	*x = ReleaseQueueMessageResponse{}
+	mi := &file_mail_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HasUnackedStatusToResponse) String() string {
+func (x *ReleaseQueueMessageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HasUnackedStatusToResponse) ProtoMessage() {}
+func (*ReleaseQueueMessageResponse) ProtoMessage() {}
 
-func (x *HasUnackedStatusToResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[31]
+func (x *ReleaseQueueMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2407,43 +3142,43 @@ func (x *HasUnackedStatusToResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HasUnackedStatusToResponse.ProtoReflect.Descriptor instead.
-func (*HasUnackedStatusToResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{31}
+// Deprecated: Use ReleaseQueueMessageResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseQueueMessageResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *HasUnackedStatusToResponse) GetHasPending() bool {
+func (x *ReleaseQueueMessageResponse) GetSuccess() bool {
 	if x != nil {
-		return x.HasPending
+		return x.Success
 	}
 	return false
 }
 
-// RegisterAgentRequest is the request for RegisterAgent.
-type RegisterAgentRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	ProjectKey    string                 `protobuf:"bytes,2,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"` // Optional project binding
-	GitBranch     string                 `protobuf:"bytes,3,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`    // Optional git branch name
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// ExtendQueueLeaseRequest is the request for ExtendQueueLease.
+type ExtendQueueLeaseRequest struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	AgentId                  int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	MessageId                int64                  `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	VisibilityTimeoutSeconds int64                  `protobuf:"varint,3,opt,name=visibility_timeout_seconds,json=visibilityTimeoutSeconds,proto3" json:"visibility_timeout_seconds,omitempty"` // 0 for the server default
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
 }
 
-func (x *RegisterAgentRequest) Reset() {
-	*x = RegisterAgentRequest{}
-	mi := &file_mail_proto_msgTypes[32]
+func (x *ExtendQueueLeaseRequest) Reset() {
+	*x = ExtendQueueLeaseRequest{}
+	mi := &file_mail_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterAgentRequest) String() string {
+func (x *ExtendQueueLeaseRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterAgentRequest) ProtoMessage() {}
+func (*ExtendQueueLeaseRequest) ProtoMessage() {}
 
-func (x *RegisterAgentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[32]
+func (x *ExtendQueueLeaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2454,125 +3189,55 @@ func (x *RegisterAgentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterAgentRequest.ProtoReflect.Descriptor instead.
-func (*RegisterAgentRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{32}
+// Deprecated: Use ExtendQueueLeaseRequest.ProtoReflect.Descriptor instead.
+func (*ExtendQueueLeaseRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *RegisterAgentRequest) GetName() string {
+func (x *ExtendQueueLeaseRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Name
+		return x.AgentId
 	}
-	return ""
+	return 0
 }
 
-func (x *RegisterAgentRequest) GetProjectKey() string {
+func (x *ExtendQueueLeaseRequest) GetMessageId() int64 {
 	if x != nil {
-		return x.ProjectKey
+		return x.MessageId
 	}
-	return ""
+	return 0
 }
 
-func (x *RegisterAgentRequest) GetGitBranch() string {
+func (x *ExtendQueueLeaseRequest) GetVisibilityTimeoutSeconds() int64 {
 	if x != nil {
-		return x.GitBranch
+		return x.VisibilityTimeoutSeconds
 	}
-	return ""
+	return 0
 }
 
-// RegisterAgentResponse is the response for RegisterAgent.
-type RegisterAgentResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	ProjectKey    string                 `protobuf:"bytes,3,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
-	GitBranch     string                 `protobuf:"bytes,4,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *RegisterAgentResponse) Reset() {
-	*x = RegisterAgentResponse{}
-	mi := &file_mail_proto_msgTypes[33]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *RegisterAgentResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*RegisterAgentResponse) ProtoMessage() {}
-
-func (x *RegisterAgentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[33]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use RegisterAgentResponse.ProtoReflect.Descriptor instead.
-func (*RegisterAgentResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{33}
-}
-
-func (x *RegisterAgentResponse) GetAgentId() int64 {
-	if x != nil {
-		return x.AgentId
-	}
-	return 0
-}
-
-func (x *RegisterAgentResponse) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *RegisterAgentResponse) GetProjectKey() string {
-	if x != nil {
-		return x.ProjectKey
-	}
-	return ""
-}
-
-func (x *RegisterAgentResponse) GetGitBranch() string {
-	if x != nil {
-		return x.GitBranch
-	}
-	return ""
-}
-
-// GetAgentRequest is the request for GetAgent.
-type GetAgentRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Use ID or name
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// ExtendQueueLeaseResponse is the response for ExtendQueueLease.
+type ExtendQueueLeaseResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	LeaseExpiresAt *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=lease_expires_at,json=leaseExpiresAt,proto3" json:"lease_expires_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *GetAgentRequest) Reset() {
-	*x = GetAgentRequest{}
-	mi := &file_mail_proto_msgTypes[34]
+func (x *ExtendQueueLeaseResponse) Reset() {
+	*x = ExtendQueueLeaseResponse{}
+	mi := &file_mail_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAgentRequest) String() string {
+func (x *ExtendQueueLeaseResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAgentRequest) ProtoMessage() {}
+func (*ExtendQueueLeaseResponse) ProtoMessage() {}
 
-func (x *GetAgentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[34]
+func (x *ExtendQueueLeaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2583,55 +3248,46 @@ func (x *GetAgentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAgentRequest.ProtoReflect.Descriptor instead.
-func (*GetAgentRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{34}
-}
-
-func (x *GetAgentRequest) GetAgentId() int64 {
-	if x != nil {
-		return x.AgentId
-	}
-	return 0
+// Deprecated: Use ExtendQueueLeaseResponse.ProtoReflect.Descriptor instead.
+func (*ExtendQueueLeaseResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *GetAgentRequest) GetName() string {
+func (x *ExtendQueueLeaseResponse) GetLeaseExpiresAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Name
+		return x.LeaseExpiresAt
 	}
-	return ""
+	return nil
 }
 
-// GetAgentResponse is the response for GetAgent.
-// Note: int64 fields serialize as strings in JSON (protobuf standard).
-type GetAgentResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	ProjectKey    string                 `protobuf:"bytes,3,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
-	SessionId     string                 `protobuf:"bytes,4,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Current session ID (renamed from current_session_id)
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	LastActiveAt  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_active_at,json=lastActiveAt,proto3" json:"last_active_at,omitempty"`
-	GitBranch     string                 `protobuf:"bytes,7,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Topic represents a pub/sub topic.
+type Topic struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name             string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	TopicType        string                 `protobuf:"bytes,3,opt,name=topic_type,json=topicType,proto3" json:"topic_type,omitempty"` // "direct", "broadcast", "queue"
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	MessageCount     int64                  `protobuf:"varint,5,opt,name=message_count,json=messageCount,proto3" json:"message_count,omitempty"`             // Number of messages in this topic
+	RetentionSeconds int64                  `protobuf:"varint,6,opt,name=retention_seconds,json=retentionSeconds,proto3" json:"retention_seconds,omitempty"` // Seconds messages are kept, 0 for forever
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *GetAgentResponse) Reset() {
-	*x = GetAgentResponse{}
-	mi := &file_mail_proto_msgTypes[35]
+func (x *Topic) Reset() {
+	*x = Topic{}
+	mi := &file_mail_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAgentResponse) String() string {
+func (x *Topic) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAgentResponse) ProtoMessage() {}
+func (*Topic) ProtoMessage() {}
 
-func (x *GetAgentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[35]
+func (x *Topic) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2642,83 +3298,77 @@ func (x *GetAgentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAgentResponse.ProtoReflect.Descriptor instead.
-func (*GetAgentResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{35}
+// Deprecated: Use Topic.ProtoReflect.Descriptor instead.
+func (*Topic) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *GetAgentResponse) GetId() int64 {
+func (x *Topic) GetId() int64 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *GetAgentResponse) GetName() string {
+func (x *Topic) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-func (x *GetAgentResponse) GetProjectKey() string {
-	if x != nil {
-		return x.ProjectKey
-	}
-	return ""
-}
-
-func (x *GetAgentResponse) GetSessionId() string {
+func (x *Topic) GetTopicType() string {
 	if x != nil {
-		return x.SessionId
+		return x.TopicType
 	}
 	return ""
 }
 
-func (x *GetAgentResponse) GetCreatedAt() *timestamppb.Timestamp {
+func (x *Topic) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
 		return x.CreatedAt
 	}
 	return nil
 }
 
-func (x *GetAgentResponse) GetLastActiveAt() *timestamppb.Timestamp {
+func (x *Topic) GetMessageCount() int64 {
 	if x != nil {
-		return x.LastActiveAt
+		return x.MessageCount
 	}
-	return nil
+	return 0
 }
 
-func (x *GetAgentResponse) GetGitBranch() string {
+func (x *Topic) GetRetentionSeconds() int64 {
 	if x != nil {
-		return x.GitBranch
+		return x.RetentionSeconds
 	}
-	return ""
+	return 0
 }
 
-// ListAgentsRequest is the request for ListAgents.
-type ListAgentsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// ListTopicsRequest is the request for ListTopics.
+type ListTopicsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AgentId        int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // If set, only show subscribed topics
+	SubscribedOnly bool                   `protobuf:"varint,2,opt,name=subscribed_only,json=subscribedOnly,proto3" json:"subscribed_only,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *ListAgentsRequest) Reset() {
-	*x = ListAgentsRequest{}
-	mi := &file_mail_proto_msgTypes[36]
+func (x *ListTopicsRequest) Reset() {
+	*x = ListTopicsRequest{}
+	mi := &file_mail_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAgentsRequest) String() string {
+func (x *ListTopicsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAgentsRequest) ProtoMessage() {}
+func (*ListTopicsRequest) ProtoMessage() {}
 
-func (x *ListAgentsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[36]
+func (x *ListTopicsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2729,41 +3379,48 @@ func (x *ListAgentsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAgentsRequest.ProtoReflect.Descriptor instead.
-func (*ListAgentsRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{36}
+// Deprecated: Use ListTopicsRequest.ProtoReflect.Descriptor instead.
+func (*ListTopicsRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *ListAgentsRequest) GetLimit() int32 {
+func (x *ListTopicsRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Limit
+		return x.AgentId
 	}
 	return 0
 }
 
-// ListAgentsResponse is the response for ListAgents.
-type ListAgentsResponse struct {
+func (x *ListTopicsRequest) GetSubscribedOnly() bool {
+	if x != nil {
+		return x.SubscribedOnly
+	}
+	return false
+}
+
+// ListTopicsResponse is the response for ListTopics.
+type ListTopicsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Agents        []*GetAgentResponse    `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	Topics        []*Topic               `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAgentsResponse) Reset() {
-	*x = ListAgentsResponse{}
-	mi := &file_mail_proto_msgTypes[37]
+func (x *ListTopicsResponse) Reset() {
+	*x = ListTopicsResponse{}
+	mi := &file_mail_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAgentsResponse) String() string {
+func (x *ListTopicsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAgentsResponse) ProtoMessage() {}
+func (*ListTopicsResponse) ProtoMessage() {}
 
-func (x *ListAgentsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[37]
+func (x *ListTopicsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2774,43 +3431,52 @@ func (x *ListAgentsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAgentsResponse.ProtoReflect.Descriptor instead.
-func (*ListAgentsResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{37}
+// Deprecated: Use ListTopicsResponse.ProtoReflect.Descriptor instead.
+func (*ListTopicsResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *ListAgentsResponse) GetAgents() []*GetAgentResponse {
+func (x *ListTopicsResponse) GetTopics() []*Topic {
 	if x != nil {
-		return x.Agents
+		return x.Topics
 	}
 	return nil
 }
 
-// EnsureIdentityRequest is the request for EnsureIdentity.
-type EnsureIdentityRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	ProjectDir    string                 `protobuf:"bytes,2,opt,name=project_dir,json=projectDir,proto3" json:"project_dir,omitempty"`
-	GitBranch     string                 `protobuf:"bytes,3,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`
+// SearchRequest is the request for Search.
+type SearchRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	AgentId     int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Query       string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`                     // Terms and filters such as from: and is:unread
+	TopicId     int64                  `protobuf:"varint,3,opt,name=topic_id,json=topicId,proto3" json:"topic_id,omitempty"` // Optional, filter by topic
+	Limit       int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Label       string                 `protobuf:"bytes,5,opt,name=label,proto3" json:"label,omitempty"`                                 // Optional, filter by the agent's label
+	Cursor      string                 `protobuf:"bytes,6,opt,name=cursor,proto3" json:"cursor,omitempty"`                               // next_cursor of the previous page
+	IncludeBody bool                   `protobuf:"varint,7,opt,name=include_body,json=includeBody,proto3" json:"include_body,omitempty"` // Return full bodies, not just snippets
+	// Name of one of the agent's saved searches to run. query, if also
+	// set, narrows its results.
+	SavedSearch string `protobuf:"bytes,8,opt,name=saved_search,json=savedSearch,proto3" json:"saved_search,omitempty"`
+	// Optional, restricts the search to these messages.
+	MessageIds    []int64 `protobuf:"varint,9,rep,packed,name=message_ids,json=messageIds,proto3" json:"message_ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *EnsureIdentityRequest) Reset() {
-	*x = EnsureIdentityRequest{}
-	mi := &file_mail_proto_msgTypes[38]
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_mail_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *EnsureIdentityRequest) String() string {
+func (x *SearchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*EnsureIdentityRequest) ProtoMessage() {}
+func (*SearchRequest) ProtoMessage() {}
 
-func (x *EnsureIdentityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[38]
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2821,118 +3487,100 @@ func (x *EnsureIdentityRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use EnsureIdentityRequest.ProtoReflect.Descriptor instead.
-func (*EnsureIdentityRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{38}
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *EnsureIdentityRequest) GetSessionId() string {
+func (x *SearchRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.SessionId
+		return x.AgentId
 	}
-	return ""
+	return 0
 }
 
-func (x *EnsureIdentityRequest) GetProjectDir() string {
+func (x *SearchRequest) GetQuery() string {
 	if x != nil {
-		return x.ProjectDir
+		return x.Query
 	}
 	return ""
 }
 
-func (x *EnsureIdentityRequest) GetGitBranch() string {
+func (x *SearchRequest) GetTopicId() int64 {
 	if x != nil {
-		return x.GitBranch
+		return x.TopicId
 	}
-	return ""
-}
-
-// EnsureIdentityResponse is the response for EnsureIdentity.
-type EnsureIdentityResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	AgentName     string                 `protobuf:"bytes,2,opt,name=agent_name,json=agentName,proto3" json:"agent_name,omitempty"`
-	Created       bool                   `protobuf:"varint,3,opt,name=created,proto3" json:"created,omitempty"` // True if a new agent was created
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *EnsureIdentityResponse) Reset() {
-	*x = EnsureIdentityResponse{}
-	mi := &file_mail_proto_msgTypes[39]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	return 0
 }
 
-func (x *EnsureIdentityResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *SearchRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
 }
 
-func (*EnsureIdentityResponse) ProtoMessage() {}
-
-func (x *EnsureIdentityResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[39]
+func (x *SearchRequest) GetLabel() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Label
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use EnsureIdentityResponse.ProtoReflect.Descriptor instead.
-func (*EnsureIdentityResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{39}
+func (x *SearchRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
 }
 
-func (x *EnsureIdentityResponse) GetAgentId() int64 {
+func (x *SearchRequest) GetIncludeBody() bool {
 	if x != nil {
-		return x.AgentId
+		return x.IncludeBody
 	}
-	return 0
+	return false
 }
 
-func (x *EnsureIdentityResponse) GetAgentName() string {
+func (x *SearchRequest) GetSavedSearch() string {
 	if x != nil {
-		return x.AgentName
+		return x.SavedSearch
 	}
 	return ""
 }
 
-func (x *EnsureIdentityResponse) GetCreated() bool {
+func (x *SearchRequest) GetMessageIds() []int64 {
 	if x != nil {
-		return x.Created
+		return x.MessageIds
 	}
-	return false
+	return nil
 }
 
-// SaveIdentityRequest is the request for SaveIdentity.
-type SaveIdentityRequest struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	SessionId       string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	AgentId         int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	ConsumerOffsets map[int64]int64        `protobuf:"bytes,3,rep,name=consumer_offsets,json=consumerOffsets,proto3" json:"consumer_offsets,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // topic_id -> offset
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+// SearchHit carries the ranking details of one search result.
+type SearchHit struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	MessageId        int64                  `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Snippet          string                 `protobuf:"bytes,2,opt,name=snippet,proto3" json:"snippet,omitempty"` // Body excerpt, matched terms in **bold**
+	SubjectHighlight string                 `protobuf:"bytes,3,opt,name=subject_highlight,json=subjectHighlight,proto3" json:"subject_highlight,omitempty"`
+	Rank             float64                `protobuf:"fixed64,4,opt,name=rank,proto3" json:"rank,omitempty"` // BM25 rank, lower is better
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *SaveIdentityRequest) Reset() {
-	*x = SaveIdentityRequest{}
-	mi := &file_mail_proto_msgTypes[40]
+func (x *SearchHit) Reset() {
+	*x = SearchHit{}
+	mi := &file_mail_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SaveIdentityRequest) String() string {
+func (x *SearchHit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SaveIdentityRequest) ProtoMessage() {}
+func (*SearchHit) ProtoMessage() {}
 
-func (x *SaveIdentityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[40]
+func (x *SearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2943,55 +3591,67 @@ func (x *SaveIdentityRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SaveIdentityRequest.ProtoReflect.Descriptor instead.
-func (*SaveIdentityRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{40}
+// Deprecated: Use SearchHit.ProtoReflect.Descriptor instead.
+func (*SearchHit) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *SaveIdentityRequest) GetSessionId() string {
+func (x *SearchHit) GetMessageId() int64 {
 	if x != nil {
-		return x.SessionId
+		return x.MessageId
+	}
+	return 0
+}
+
+func (x *SearchHit) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
 	}
 	return ""
 }
 
-func (x *SaveIdentityRequest) GetAgentId() int64 {
+func (x *SearchHit) GetSubjectHighlight() string {
 	if x != nil {
-		return x.AgentId
+		return x.SubjectHighlight
 	}
-	return 0
+	return ""
 }
 
-func (x *SaveIdentityRequest) GetConsumerOffsets() map[int64]int64 {
+func (x *SearchHit) GetRank() float64 {
 	if x != nil {
-		return x.ConsumerOffsets
+		return x.Rank
 	}
-	return nil
+	return 0
 }
 
-// SaveIdentityResponse is the response for SaveIdentity.
-type SaveIdentityResponse struct {
+// SearchResponse is the response for Search. Results are best matches first,
+// or newest first for filter-only queries, and their bodies are empty unless
+// include_body was set. hits[i] describes results[i].
+type SearchResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Results       []*InboxMessage        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Hits          []*SearchHit           `protobuf:"bytes,2,rep,name=hits,proto3" json:"hits,omitempty"`
+	Total         int64                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`                            // Matches across every page
+	NextCursor    string                 `protobuf:"bytes,4,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // Empty on the last page
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SaveIdentityResponse) Reset() {
-	*x = SaveIdentityResponse{}
-	mi := &file_mail_proto_msgTypes[41]
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_mail_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SaveIdentityResponse) String() string {
+func (x *SearchResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SaveIdentityResponse) ProtoMessage() {}
+func (*SearchResponse) ProtoMessage() {}
 
-func (x *SaveIdentityResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[41]
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3002,41 +3662,65 @@ func (x *SaveIdentityResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SaveIdentityResponse.ProtoReflect.Descriptor instead.
-func (*SaveIdentityResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{41}
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{44}
 }
 
-func (x *SaveIdentityResponse) GetSuccess() bool {
+func (x *SearchResponse) GetResults() []*InboxMessage {
 	if x != nil {
-		return x.Success
+		return x.Results
 	}
-	return false
+	return nil
 }
 
-// DeleteAgentRequest is the request for DeleteAgent.
-type DeleteAgentRequest struct {
+func (x *SearchResponse) GetHits() []*SearchHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SearchResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// SearchAllRequest is the request for SearchAll.
+type SearchAllRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`                 // Narrows messages to the agent's, 0 for all
+	Query         string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`                                     // Terms, plus optional before: and after:
+	Kinds         []SearchKind           `protobuf:"varint,3,rep,packed,name=kinds,proto3,enum=subtraterpc.SearchKind" json:"kinds,omitempty"` // Empty searches every kind
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteAgentRequest) Reset() {
-	*x = DeleteAgentRequest{}
-	mi := &file_mail_proto_msgTypes[42]
+func (x *SearchAllRequest) Reset() {
+	*x = SearchAllRequest{}
+	mi := &file_mail_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteAgentRequest) String() string {
+func (x *SearchAllRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteAgentRequest) ProtoMessage() {}
+func (*SearchAllRequest) ProtoMessage() {}
 
-func (x *DeleteAgentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[42]
+func (x *SearchAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3047,88 +3731,70 @@ func (x *DeleteAgentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteAgentRequest.ProtoReflect.Descriptor instead.
-func (*DeleteAgentRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{42}
+// Deprecated: Use SearchAllRequest.ProtoReflect.Descriptor instead.
+func (*SearchAllRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *DeleteAgentRequest) GetId() int64 {
+func (x *SearchAllRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Id
+		return x.AgentId
 	}
 	return 0
 }
 
-// DeleteAgentResponse is the response for DeleteAgent.
-type DeleteAgentResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *DeleteAgentResponse) Reset() {
-	*x = DeleteAgentResponse{}
-	mi := &file_mail_proto_msgTypes[43]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *DeleteAgentResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*DeleteAgentResponse) ProtoMessage() {}
-
-func (x *DeleteAgentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[43]
+func (x *SearchAllRequest) GetQuery() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Query
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use DeleteAgentResponse.ProtoReflect.Descriptor instead.
-func (*DeleteAgentResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{43}
+func (x *SearchAllRequest) GetKinds() []SearchKind {
+	if x != nil {
+		return x.Kinds
+	}
+	return nil
 }
 
-func (x *DeleteAgentResponse) GetSuccess() bool {
+func (x *SearchAllRequest) GetLimit() int32 {
 	if x != nil {
-		return x.Success
+		return x.Limit
 	}
-	return false
+	return 0
 }
 
-// ReplyToThreadRequest is the request for ReplyToThread.
-type ReplyToThreadRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	SenderId      int64                  `protobuf:"varint,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
-	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
-	Body          string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+// SearchAllHit is an entity matched by SearchAll.
+type SearchAllHit struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Kind  SearchKind             `protobuf:"varint,1,opt,name=kind,proto3,enum=subtraterpc.SearchKind" json:"kind,omitempty"`
+	Id    int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`  // Message, issue, plan review, task or annotation ID
+	Ref   string                 `protobuf:"bytes,3,opt,name=ref,proto3" json:"ref,omitempty"` // Review, plan review or task list ID, or the
+	// message ID of a diff annotation
+	ThreadId      string                 `protobuf:"bytes,4,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"` // Thread the entity is discussed in, if any
+	Title         string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`
+	Snippet       string                 `protobuf:"bytes,6,opt,name=snippet,proto3" json:"snippet,omitempty"` // Text excerpt, matched terms in **bold**
+	Rank          float64                `protobuf:"fixed64,7,opt,name=rank,proto3" json:"rank,omitempty"`     // BM25 rank, lower is better
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReplyToThreadRequest) Reset() {
-	*x = ReplyToThreadRequest{}
-	mi := &file_mail_proto_msgTypes[44]
+func (x *SearchAllHit) Reset() {
+	*x = SearchAllHit{}
+	mi := &file_mail_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReplyToThreadRequest) String() string {
+func (x *SearchAllHit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReplyToThreadRequest) ProtoMessage() {}
+func (*SearchAllHit) ProtoMessage() {}
 
-func (x *ReplyToThreadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[44]
+func (x *SearchAllHit) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3139,153 +3805,90 @@ func (x *ReplyToThreadRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReplyToThreadRequest.ProtoReflect.Descriptor instead.
-func (*ReplyToThreadRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{44}
+// Deprecated: Use SearchAllHit.ProtoReflect.Descriptor instead.
+func (*SearchAllHit) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *ReplyToThreadRequest) GetSenderId() int64 {
+func (x *SearchAllHit) GetKind() SearchKind {
 	if x != nil {
-		return x.SenderId
+		return x.Kind
 	}
-	return 0
+	return SearchKind_SEARCH_KIND_UNSPECIFIED
 }
 
-func (x *ReplyToThreadRequest) GetThreadId() string {
+func (x *SearchAllHit) GetId() int64 {
 	if x != nil {
-		return x.ThreadId
+		return x.Id
 	}
-	return ""
+	return 0
 }
 
-func (x *ReplyToThreadRequest) GetBody() string {
+func (x *SearchAllHit) GetRef() string {
 	if x != nil {
-		return x.Body
+		return x.Ref
 	}
 	return ""
 }
 
-// ReplyToThreadResponse is the response for ReplyToThread.
-type ReplyToThreadResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MessageId     int64                  `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *ReplyToThreadResponse) Reset() {
-	*x = ReplyToThreadResponse{}
-	mi := &file_mail_proto_msgTypes[45]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *ReplyToThreadResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*ReplyToThreadResponse) ProtoMessage() {}
-
-func (x *ReplyToThreadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[45]
+func (x *SearchAllHit) GetThreadId() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.ThreadId
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use ReplyToThreadResponse.ProtoReflect.Descriptor instead.
-func (*ReplyToThreadResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{45}
+	return ""
 }
 
-func (x *ReplyToThreadResponse) GetMessageId() int64 {
+func (x *SearchAllHit) GetTitle() string {
 	if x != nil {
-		return x.MessageId
+		return x.Title
 	}
-	return 0
-}
-
-// ArchiveThreadRequest is the request for ArchiveThread.
-type ArchiveThreadRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *ArchiveThreadRequest) Reset() {
-	*x = ArchiveThreadRequest{}
-	mi := &file_mail_proto_msgTypes[46]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *ArchiveThreadRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+	return ""
 }
 
-func (*ArchiveThreadRequest) ProtoMessage() {}
-
-func (x *ArchiveThreadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[46]
+func (x *SearchAllHit) GetSnippet() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Snippet
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use ArchiveThreadRequest.ProtoReflect.Descriptor instead.
-func (*ArchiveThreadRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{46}
+	return ""
 }
 
-func (x *ArchiveThreadRequest) GetAgentId() int64 {
+func (x *SearchAllHit) GetRank() float64 {
 	if x != nil {
-		return x.AgentId
+		return x.Rank
 	}
 	return 0
 }
 
-func (x *ArchiveThreadRequest) GetThreadId() string {
+func (x *SearchAllHit) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.ThreadId
+		return x.CreatedAt
 	}
-	return ""
+	return nil
 }
 
-// ArchiveThreadResponse is the response for ArchiveThread.
-type ArchiveThreadResponse struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Success          bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	MessagesArchived int32                  `protobuf:"varint,2,opt,name=messages_archived,json=messagesArchived,proto3" json:"messages_archived,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+// SearchAllResponse is the response for SearchAll, best matches first.
+type SearchAllResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hits          []*SearchAllHit        `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"` // Matches beyond the limit included
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ArchiveThreadResponse) Reset() {
-	*x = ArchiveThreadResponse{}
+func (x *SearchAllResponse) Reset() {
+	*x = SearchAllResponse{}
 	mi := &file_mail_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ArchiveThreadResponse) String() string {
+func (x *SearchAllResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ArchiveThreadResponse) ProtoMessage() {}
+func (*SearchAllResponse) ProtoMessage() {}
 
-func (x *ArchiveThreadResponse) ProtoReflect() protoreflect.Message {
+func (x *SearchAllResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3297,48 +3900,52 @@ func (x *ArchiveThreadResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ArchiveThreadResponse.ProtoReflect.Descriptor instead.
-func (*ArchiveThreadResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SearchAllResponse.ProtoReflect.Descriptor instead.
+func (*SearchAllResponse) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *ArchiveThreadResponse) GetSuccess() bool {
+func (x *SearchAllResponse) GetHits() []*SearchAllHit {
 	if x != nil {
-		return x.Success
+		return x.Hits
 	}
-	return false
+	return nil
 }
 
-func (x *ArchiveThreadResponse) GetMessagesArchived() int32 {
+func (x *SearchAllResponse) GetTotal() int64 {
 	if x != nil {
-		return x.MessagesArchived
+		return x.Total
 	}
 	return 0
 }
 
-// DeleteThreadRequest is the request for DeleteThread.
-type DeleteThreadRequest struct {
+// SavedSearchProto is a search query an agent saved under a name.
+type SavedSearchProto struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Query         string                 `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteThreadRequest) Reset() {
-	*x = DeleteThreadRequest{}
+func (x *SavedSearchProto) Reset() {
+	*x = SavedSearchProto{}
 	mi := &file_mail_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteThreadRequest) String() string {
+func (x *SavedSearchProto) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteThreadRequest) ProtoMessage() {}
+func (*SavedSearchProto) ProtoMessage() {}
 
-func (x *DeleteThreadRequest) ProtoReflect() protoreflect.Message {
+func (x *SavedSearchProto) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3350,48 +3957,77 @@ func (x *DeleteThreadRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteThreadRequest.ProtoReflect.Descriptor instead.
-func (*DeleteThreadRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SavedSearchProto.ProtoReflect.Descriptor instead.
+func (*SavedSearchProto) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *DeleteThreadRequest) GetAgentId() int64 {
+func (x *SavedSearchProto) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SavedSearchProto) GetAgentId() int64 {
 	if x != nil {
 		return x.AgentId
 	}
 	return 0
 }
 
-func (x *DeleteThreadRequest) GetThreadId() string {
+func (x *SavedSearchProto) GetName() string {
 	if x != nil {
-		return x.ThreadId
+		return x.Name
 	}
 	return ""
 }
 
-// DeleteThreadResponse is the response for DeleteThread.
-type DeleteThreadResponse struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	MessagesDeleted int32                  `protobuf:"varint,2,opt,name=messages_deleted,json=messagesDeleted,proto3" json:"messages_deleted,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+func (x *SavedSearchProto) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
 }
 
-func (x *DeleteThreadResponse) Reset() {
-	*x = DeleteThreadResponse{}
+func (x *SavedSearchProto) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *SavedSearchProto) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// SaveSearchRequest is the request for SaveSearch.
+type SaveSearchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Query         string                 `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveSearchRequest) Reset() {
+	*x = SaveSearchRequest{}
 	mi := &file_mail_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteThreadResponse) String() string {
+func (x *SaveSearchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteThreadResponse) ProtoMessage() {}
+func (*SaveSearchRequest) ProtoMessage() {}
 
-func (x *DeleteThreadResponse) ProtoReflect() protoreflect.Message {
+func (x *SaveSearchRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3403,48 +4039,54 @@ func (x *DeleteThreadResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteThreadResponse.ProtoReflect.Descriptor instead.
-func (*DeleteThreadResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SaveSearchRequest.ProtoReflect.Descriptor instead.
+func (*SaveSearchRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *DeleteThreadResponse) GetSuccess() bool {
+func (x *SaveSearchRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Success
+		return x.AgentId
 	}
-	return false
+	return 0
 }
 
-func (x *DeleteThreadResponse) GetMessagesDeleted() int32 {
+func (x *SaveSearchRequest) GetName() string {
 	if x != nil {
-		return x.MessagesDeleted
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-// MarkThreadUnreadRequest is the request for MarkThreadUnread.
-type MarkThreadUnreadRequest struct {
+func (x *SaveSearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+// ListSavedSearchesRequest is the request for ListSavedSearches.
+type ListSavedSearchesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MarkThreadUnreadRequest) Reset() {
-	*x = MarkThreadUnreadRequest{}
+func (x *ListSavedSearchesRequest) Reset() {
+	*x = ListSavedSearchesRequest{}
 	mi := &file_mail_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MarkThreadUnreadRequest) String() string {
+func (x *ListSavedSearchesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MarkThreadUnreadRequest) ProtoMessage() {}
+func (*ListSavedSearchesRequest) ProtoMessage() {}
 
-func (x *MarkThreadUnreadRequest) ProtoReflect() protoreflect.Message {
+func (x *ListSavedSearchesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3456,47 +4098,40 @@ func (x *MarkThreadUnreadRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MarkThreadUnreadRequest.ProtoReflect.Descriptor instead.
-func (*MarkThreadUnreadRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListSavedSearchesRequest.ProtoReflect.Descriptor instead.
+func (*ListSavedSearchesRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{50}
 }
 
-func (x *MarkThreadUnreadRequest) GetAgentId() int64 {
+func (x *ListSavedSearchesRequest) GetAgentId() int64 {
 	if x != nil {
 		return x.AgentId
 	}
 	return 0
 }
 
-func (x *MarkThreadUnreadRequest) GetThreadId() string {
-	if x != nil {
-		return x.ThreadId
-	}
-	return ""
-}
-
-// MarkThreadUnreadResponse is the response for MarkThreadUnread.
-type MarkThreadUnreadResponse struct {
+// ListSavedSearchesResponse is the response for ListSavedSearches.
+type ListSavedSearchesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Searches      []*SavedSearchProto    `protobuf:"bytes,1,rep,name=searches,proto3" json:"searches,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MarkThreadUnreadResponse) Reset() {
-	*x = MarkThreadUnreadResponse{}
+func (x *ListSavedSearchesResponse) Reset() {
+	*x = ListSavedSearchesResponse{}
 	mi := &file_mail_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MarkThreadUnreadResponse) String() string {
+func (x *ListSavedSearchesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MarkThreadUnreadResponse) ProtoMessage() {}
+func (*ListSavedSearchesResponse) ProtoMessage() {}
 
-func (x *MarkThreadUnreadResponse) ProtoReflect() protoreflect.Message {
+func (x *ListSavedSearchesResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3508,40 +4143,41 @@ func (x *MarkThreadUnreadResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MarkThreadUnreadResponse.ProtoReflect.Descriptor instead.
-func (*MarkThreadUnreadResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListSavedSearchesResponse.ProtoReflect.Descriptor instead.
+func (*ListSavedSearchesResponse) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{51}
 }
 
-func (x *MarkThreadUnreadResponse) GetSuccess() bool {
+func (x *ListSavedSearchesResponse) GetSearches() []*SavedSearchProto {
 	if x != nil {
-		return x.Success
+		return x.Searches
 	}
-	return false
+	return nil
 }
 
-// GetTopicRequest is the request for GetTopic.
-type GetTopicRequest struct {
+// DeleteSavedSearchRequest is the request for DeleteSavedSearch.
+type DeleteSavedSearchRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TopicId       int64                  `protobuf:"varint,1,opt,name=topic_id,json=topicId,proto3" json:"topic_id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTopicRequest) Reset() {
-	*x = GetTopicRequest{}
+func (x *DeleteSavedSearchRequest) Reset() {
+	*x = DeleteSavedSearchRequest{}
 	mi := &file_mail_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTopicRequest) String() string {
+func (x *DeleteSavedSearchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTopicRequest) ProtoMessage() {}
+func (*DeleteSavedSearchRequest) ProtoMessage() {}
 
-func (x *GetTopicRequest) ProtoReflect() protoreflect.Message {
+func (x *DeleteSavedSearchRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3553,40 +4189,46 @@ func (x *GetTopicRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTopicRequest.ProtoReflect.Descriptor instead.
-func (*GetTopicRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteSavedSearchRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSavedSearchRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{52}
 }
 
-func (x *GetTopicRequest) GetTopicId() int64 {
+func (x *DeleteSavedSearchRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.TopicId
+		return x.AgentId
 	}
 	return 0
 }
 
-// GetTopicResponse is the response for GetTopic.
-type GetTopicResponse struct {
+func (x *DeleteSavedSearchRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// DeleteSavedSearchResponse is the response for DeleteSavedSearch.
+type DeleteSavedSearchResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Topic         *Topic                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTopicResponse) Reset() {
-	*x = GetTopicResponse{}
+func (x *DeleteSavedSearchResponse) Reset() {
+	*x = DeleteSavedSearchResponse{}
 	mi := &file_mail_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTopicResponse) String() string {
+func (x *DeleteSavedSearchResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTopicResponse) ProtoMessage() {}
+func (*DeleteSavedSearchResponse) ProtoMessage() {}
 
-func (x *GetTopicResponse) ProtoReflect() protoreflect.Message {
+func (x *DeleteSavedSearchResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3598,41 +4240,38 @@ func (x *GetTopicResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTopicResponse.ProtoReflect.Descriptor instead.
-func (*GetTopicResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteSavedSearchResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSavedSearchResponse) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *GetTopicResponse) GetTopic() *Topic {
-	if x != nil {
-		return x.Topic
-	}
-	return nil
-}
-
-// AutocompleteRecipientsRequest is the request for AutocompleteRecipients.
-type AutocompleteRecipientsRequest struct {
+// SemanticSearchRequest is the request for SemanticSearch. The query's terms
+// are matched by meaning, and its filters apply as they do in Search.
+type SemanticSearchRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // 0 searches every message
+	Query         string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Hybrid        bool                   `protobuf:"varint,4,opt,name=hybrid,proto3" json:"hybrid,omitempty"`                      // Merge in the BM25 ranks of the terms
+	MinScore      float64                `protobuf:"fixed64,5,opt,name=min_score,json=minScore,proto3" json:"min_score,omitempty"` // Drop hits scoring less
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AutocompleteRecipientsRequest) Reset() {
-	*x = AutocompleteRecipientsRequest{}
+func (x *SemanticSearchRequest) Reset() {
+	*x = SemanticSearchRequest{}
 	mi := &file_mail_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AutocompleteRecipientsRequest) String() string {
+func (x *SemanticSearchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AutocompleteRecipientsRequest) ProtoMessage() {}
+func (*SemanticSearchRequest) ProtoMessage() {}
 
-func (x *AutocompleteRecipientsRequest) ProtoReflect() protoreflect.Message {
+func (x *SemanticSearchRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3644,51 +4283,73 @@ func (x *AutocompleteRecipientsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AutocompleteRecipientsRequest.ProtoReflect.Descriptor instead.
-func (*AutocompleteRecipientsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SemanticSearchRequest.ProtoReflect.Descriptor instead.
+func (*SemanticSearchRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{54}
 }
 
-func (x *AutocompleteRecipientsRequest) GetQuery() string {
+func (x *SemanticSearchRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *SemanticSearchRequest) GetQuery() string {
 	if x != nil {
 		return x.Query
 	}
 	return ""
 }
 
-func (x *AutocompleteRecipientsRequest) GetLimit() int32 {
+func (x *SemanticSearchRequest) GetLimit() int32 {
 	if x != nil {
 		return x.Limit
 	}
 	return 0
 }
 
-// AutocompleteRecipient represents a recipient suggestion.
-type AutocompleteRecipient struct {
+func (x *SemanticSearchRequest) GetHybrid() bool {
+	if x != nil {
+		return x.Hybrid
+	}
+	return false
+}
+
+func (x *SemanticSearchRequest) GetMinScore() float64 {
+	if x != nil {
+		return x.MinScore
+	}
+	return 0
+}
+
+// SimilarMessagesRequest is the request for SimilarMessages. Either a message
+// or a text is compared.
+type SimilarMessagesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	ProjectKey    string                 `protobuf:"bytes,3,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
-	GitBranch     string                 `protobuf:"bytes,4,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`
-	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"` // "active", "busy", "idle", "offline"
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`       // 0 compares every message
+	MessageId     int64                  `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // Left out of the hits
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`                             // Compared if message_id is 0
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	MinScore      float64                `protobuf:"fixed64,5,opt,name=min_score,json=minScore,proto3" json:"min_score,omitempty"` // Drop hits less similar
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AutocompleteRecipient) Reset() {
-	*x = AutocompleteRecipient{}
+func (x *SimilarMessagesRequest) Reset() {
+	*x = SimilarMessagesRequest{}
 	mi := &file_mail_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AutocompleteRecipient) String() string {
+func (x *SimilarMessagesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AutocompleteRecipient) ProtoMessage() {}
+func (*SimilarMessagesRequest) ProtoMessage() {}
 
-func (x *AutocompleteRecipient) ProtoReflect() protoreflect.Message {
+func (x *SimilarMessagesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3700,68 +4361,72 @@ func (x *AutocompleteRecipient) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AutocompleteRecipient.ProtoReflect.Descriptor instead.
-func (*AutocompleteRecipient) Descriptor() ([]byte, []int) {
+// Deprecated: Use SimilarMessagesRequest.ProtoReflect.Descriptor instead.
+func (*SimilarMessagesRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{55}
 }
 
-func (x *AutocompleteRecipient) GetId() int64 {
+func (x *SimilarMessagesRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Id
+		return x.AgentId
 	}
 	return 0
 }
 
-func (x *AutocompleteRecipient) GetName() string {
+func (x *SimilarMessagesRequest) GetMessageId() int64 {
 	if x != nil {
-		return x.Name
+		return x.MessageId
 	}
-	return ""
+	return 0
 }
 
-func (x *AutocompleteRecipient) GetProjectKey() string {
+func (x *SimilarMessagesRequest) GetText() string {
 	if x != nil {
-		return x.ProjectKey
+		return x.Text
 	}
 	return ""
 }
 
-func (x *AutocompleteRecipient) GetGitBranch() string {
+func (x *SimilarMessagesRequest) GetLimit() int32 {
 	if x != nil {
-		return x.GitBranch
+		return x.Limit
 	}
-	return ""
+	return 0
 }
 
-func (x *AutocompleteRecipient) GetStatus() string {
+func (x *SimilarMessagesRequest) GetMinScore() float64 {
 	if x != nil {
-		return x.Status
+		return x.MinScore
 	}
-	return ""
+	return 0
 }
 
-// AutocompleteRecipientsResponse is the response for AutocompleteRecipients.
-type AutocompleteRecipientsResponse struct {
-	state         protoimpl.MessageState   `protogen:"open.v1"`
-	Recipients    []*AutocompleteRecipient `protobuf:"bytes,1,rep,name=recipients,proto3" json:"recipients,omitempty"`
+// SemanticHit scores a message found by its meaning.
+type SemanticHit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     int64                  `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Snippet       string                 `protobuf:"bytes,2,opt,name=snippet,proto3" json:"snippet,omitempty"`                        // Start of the body
+	Score         float64                `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty"`                          // What hits are ordered by, higher is better
+	Similarity    float64                `protobuf:"fixed64,4,opt,name=similarity,proto3" json:"similarity,omitempty"`                // Cosine similarity, -1 to 1
+	TextScore     float64                `protobuf:"fixed64,5,opt,name=text_score,json=textScore,proto3" json:"text_score,omitempty"` // Relative BM25 rank, 0 to 1, if hybrid
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AutocompleteRecipientsResponse) Reset() {
-	*x = AutocompleteRecipientsResponse{}
+func (x *SemanticHit) Reset() {
+	*x = SemanticHit{}
 	mi := &file_mail_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AutocompleteRecipientsResponse) String() string {
+func (x *SemanticHit) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AutocompleteRecipientsResponse) ProtoMessage() {}
+func (*SemanticHit) ProtoMessage() {}
 
-func (x *AutocompleteRecipientsResponse) ProtoReflect() protoreflect.Message {
+func (x *SemanticHit) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3773,44 +4438,71 @@ func (x *AutocompleteRecipientsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AutocompleteRecipientsResponse.ProtoReflect.Descriptor instead.
-func (*AutocompleteRecipientsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SemanticHit.ProtoReflect.Descriptor instead.
+func (*SemanticHit) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *AutocompleteRecipientsResponse) GetRecipients() []*AutocompleteRecipient {
+func (x *SemanticHit) GetMessageId() int64 {
 	if x != nil {
-		return x.Recipients
+		return x.MessageId
 	}
-	return nil
+	return 0
 }
 
-// DeleteMessageRequest is the request for DeleteMessage.
-type DeleteMessageRequest struct {
-	state     protoimpl.MessageState `protogen:"open.v1"`
-	AgentId   int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	MessageId int64                  `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	// mark_sender_deleted marks the message as deleted from sender's perspective.
-	// Used when deleting from aggregate views like CodeReviewer.
-	MarkSenderDeleted bool `protobuf:"varint,3,opt,name=mark_sender_deleted,json=markSenderDeleted,proto3" json:"mark_sender_deleted,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+func (x *SemanticHit) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
 }
 
-func (x *DeleteMessageRequest) Reset() {
-	*x = DeleteMessageRequest{}
+func (x *SemanticHit) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *SemanticHit) GetSimilarity() float64 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+func (x *SemanticHit) GetTextScore() float64 {
+	if x != nil {
+		return x.TextScore
+	}
+	return 0
+}
+
+// SemanticSearchResponse is the response for SemanticSearch and
+// SimilarMessages. Results are best first, with empty bodies, and hits[i]
+// describes results[i].
+type SemanticSearchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*InboxMessage        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Hits          []*SemanticHit         `protobuf:"bytes,2,rep,name=hits,proto3" json:"hits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SemanticSearchResponse) Reset() {
+	*x = SemanticSearchResponse{}
 	mi := &file_mail_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteMessageRequest) String() string {
+func (x *SemanticSearchResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteMessageRequest) ProtoMessage() {}
+func (*SemanticSearchResponse) ProtoMessage() {}
 
-func (x *DeleteMessageRequest) ProtoReflect() protoreflect.Message {
+func (x *SemanticSearchResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3822,54 +4514,48 @@ func (x *DeleteMessageRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteMessageRequest.ProtoReflect.Descriptor instead.
-func (*DeleteMessageRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SemanticSearchResponse.ProtoReflect.Descriptor instead.
+func (*SemanticSearchResponse) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{57}
 }
 
-func (x *DeleteMessageRequest) GetAgentId() int64 {
-	if x != nil {
-		return x.AgentId
-	}
-	return 0
-}
-
-func (x *DeleteMessageRequest) GetMessageId() int64 {
+func (x *SemanticSearchResponse) GetResults() []*InboxMessage {
 	if x != nil {
-		return x.MessageId
+		return x.Results
 	}
-	return 0
+	return nil
 }
 
-func (x *DeleteMessageRequest) GetMarkSenderDeleted() bool {
+func (x *SemanticSearchResponse) GetHits() []*SemanticHit {
 	if x != nil {
-		return x.MarkSenderDeleted
+		return x.Hits
 	}
-	return false
+	return nil
 }
 
-// DeleteMessageResponse is the response for DeleteMessage.
-type DeleteMessageResponse struct {
+// HasUnackedStatusToRequest is the request for HasUnackedStatusTo.
+type HasUnackedStatusToRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	SenderId      int64                  `protobuf:"varint,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	RecipientId   int64                  `protobuf:"varint,2,opt,name=recipient_id,json=recipientId,proto3" json:"recipient_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteMessageResponse) Reset() {
-	*x = DeleteMessageResponse{}
+func (x *HasUnackedStatusToRequest) Reset() {
+	*x = HasUnackedStatusToRequest{}
 	mi := &file_mail_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteMessageResponse) String() string {
+func (x *HasUnackedStatusToRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteMessageResponse) ProtoMessage() {}
+func (*HasUnackedStatusToRequest) ProtoMessage() {}
 
-func (x *DeleteMessageResponse) ProtoReflect() protoreflect.Message {
+func (x *HasUnackedStatusToRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3881,42 +4567,47 @@ func (x *DeleteMessageResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteMessageResponse.ProtoReflect.Descriptor instead.
-func (*DeleteMessageResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use HasUnackedStatusToRequest.ProtoReflect.Descriptor instead.
+func (*HasUnackedStatusToRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{58}
 }
 
-func (x *DeleteMessageResponse) GetSuccess() bool {
+func (x *HasUnackedStatusToRequest) GetSenderId() int64 {
 	if x != nil {
-		return x.Success
+		return x.SenderId
 	}
-	return false
+	return 0
 }
 
-// UpdateAgentRequest is the request for UpdateAgent.
-type UpdateAgentRequest struct {
+func (x *HasUnackedStatusToRequest) GetRecipientId() int64 {
+	if x != nil {
+		return x.RecipientId
+	}
+	return 0
+}
+
+// HasUnackedStatusToResponse is the response for HasUnackedStatusTo.
+type HasUnackedStatusToResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	ProjectKey    string                 `protobuf:"bytes,2,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
-	GitBranch     string                 `protobuf:"bytes,3,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`
+	HasPending    bool                   `protobuf:"varint,1,opt,name=has_pending,json=hasPending,proto3" json:"has_pending,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateAgentRequest) Reset() {
-	*x = UpdateAgentRequest{}
+func (x *HasUnackedStatusToResponse) Reset() {
+	*x = HasUnackedStatusToResponse{}
 	mi := &file_mail_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateAgentRequest) String() string {
+func (x *HasUnackedStatusToResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateAgentRequest) ProtoMessage() {}
+func (*HasUnackedStatusToResponse) ProtoMessage() {}
 
-func (x *UpdateAgentRequest) ProtoReflect() protoreflect.Message {
+func (x *HasUnackedStatusToResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3928,54 +4619,45 @@ func (x *UpdateAgentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateAgentRequest.ProtoReflect.Descriptor instead.
-func (*UpdateAgentRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use HasUnackedStatusToResponse.ProtoReflect.Descriptor instead.
+func (*HasUnackedStatusToResponse) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{59}
 }
 
-func (x *UpdateAgentRequest) GetId() int64 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-func (x *UpdateAgentRequest) GetProjectKey() string {
-	if x != nil {
-		return x.ProjectKey
-	}
-	return ""
-}
-
-func (x *UpdateAgentRequest) GetGitBranch() string {
+func (x *HasUnackedStatusToResponse) GetHasPending() bool {
 	if x != nil {
-		return x.GitBranch
+		return x.HasPending
 	}
-	return ""
+	return false
 }
 
-// UpdateAgentResponse is the response for UpdateAgent.
-type UpdateAgentResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Agent         *GetAgentResponse      `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+// RegisterAgentRequest is the request for RegisterAgent.
+type RegisterAgentRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Name         string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ProjectKey   string                 `protobuf:"bytes,2,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"` // Optional project binding
+	GitBranch    string                 `protobuf:"bytes,3,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`    // Optional git branch name
+	Capabilities *AgentCapabilities     `protobuf:"bytes,4,opt,name=capabilities,proto3" json:"capabilities,omitempty"`               // Optional declared capabilities
+	// mint_token also mints a bearer token for the new agent.
+	MintToken     bool `protobuf:"varint,5,opt,name=mint_token,json=mintToken,proto3" json:"mint_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateAgentResponse) Reset() {
-	*x = UpdateAgentResponse{}
+func (x *RegisterAgentRequest) Reset() {
+	*x = RegisterAgentRequest{}
 	mi := &file_mail_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateAgentResponse) String() string {
+func (x *RegisterAgentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateAgentResponse) ProtoMessage() {}
+func (*RegisterAgentRequest) ProtoMessage() {}
 
-func (x *UpdateAgentResponse) ProtoReflect() protoreflect.Message {
+func (x *RegisterAgentRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3987,47 +4669,73 @@ func (x *UpdateAgentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateAgentResponse.ProtoReflect.Descriptor instead.
-func (*UpdateAgentResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RegisterAgentRequest.ProtoReflect.Descriptor instead.
+func (*RegisterAgentRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *UpdateAgentResponse) GetAgent() *GetAgentResponse {
+func (x *RegisterAgentRequest) GetName() string {
 	if x != nil {
-		return x.Agent
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-// AgentWithStatus represents an agent with its current status.
-type AgentWithStatus struct {
-	state                 protoimpl.MessageState `protogen:"open.v1"`
-	Id                    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name                  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	ProjectKey            string                 `protobuf:"bytes,3,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
-	GitBranch             string                 `protobuf:"bytes,4,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`
-	Status                AgentStatus            `protobuf:"varint,5,opt,name=status,proto3,enum=subtraterpc.AgentStatus" json:"status,omitempty"`
-	LastActiveAt          *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_active_at,json=lastActiveAt,proto3" json:"last_active_at,omitempty"`
-	SessionId             int64                  `protobuf:"varint,7,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	SecondsSinceHeartbeat int64                  `protobuf:"varint,8,opt,name=seconds_since_heartbeat,json=secondsSinceHeartbeat,proto3" json:"seconds_since_heartbeat,omitempty"`
-	unknownFields         protoimpl.UnknownFields
-	sizeCache             protoimpl.SizeCache
-}
+func (x *RegisterAgentRequest) GetProjectKey() string {
+	if x != nil {
+		return x.ProjectKey
+	}
+	return ""
+}
 
-func (x *AgentWithStatus) Reset() {
-	*x = AgentWithStatus{}
+func (x *RegisterAgentRequest) GetGitBranch() string {
+	if x != nil {
+		return x.GitBranch
+	}
+	return ""
+}
+
+func (x *RegisterAgentRequest) GetCapabilities() *AgentCapabilities {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *RegisterAgentRequest) GetMintToken() bool {
+	if x != nil {
+		return x.MintToken
+	}
+	return false
+}
+
+// RegisterAgentResponse is the response for RegisterAgent.
+type RegisterAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ProjectKey    string                 `protobuf:"bytes,3,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
+	GitBranch     string                 `protobuf:"bytes,4,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`
+	Capabilities  *AgentCapabilities     `protobuf:"bytes,5,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	Token         string                 `protobuf:"bytes,6,opt,name=token,proto3" json:"token,omitempty"` // Set when mint_token was given
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterAgentResponse) Reset() {
+	*x = RegisterAgentResponse{}
 	mi := &file_mail_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AgentWithStatus) String() string {
+func (x *RegisterAgentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AgentWithStatus) ProtoMessage() {}
+func (*RegisterAgentResponse) ProtoMessage() {}
 
-func (x *AgentWithStatus) ProtoReflect() protoreflect.Message {
+func (x *RegisterAgentResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4039,92 +4747,83 @@ func (x *AgentWithStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AgentWithStatus.ProtoReflect.Descriptor instead.
-func (*AgentWithStatus) Descriptor() ([]byte, []int) {
+// Deprecated: Use RegisterAgentResponse.ProtoReflect.Descriptor instead.
+func (*RegisterAgentResponse) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{61}
 }
 
-func (x *AgentWithStatus) GetId() int64 {
+func (x *RegisterAgentResponse) GetAgentId() int64 {
 	if x != nil {
-		return x.Id
+		return x.AgentId
 	}
 	return 0
 }
 
-func (x *AgentWithStatus) GetName() string {
+func (x *RegisterAgentResponse) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-func (x *AgentWithStatus) GetProjectKey() string {
+func (x *RegisterAgentResponse) GetProjectKey() string {
 	if x != nil {
 		return x.ProjectKey
 	}
 	return ""
 }
 
-func (x *AgentWithStatus) GetGitBranch() string {
+func (x *RegisterAgentResponse) GetGitBranch() string {
 	if x != nil {
 		return x.GitBranch
 	}
 	return ""
 }
 
-func (x *AgentWithStatus) GetStatus() AgentStatus {
-	if x != nil {
-		return x.Status
-	}
-	return AgentStatus_AGENT_STATUS_UNSPECIFIED
-}
-
-func (x *AgentWithStatus) GetLastActiveAt() *timestamppb.Timestamp {
+func (x *RegisterAgentResponse) GetCapabilities() *AgentCapabilities {
 	if x != nil {
-		return x.LastActiveAt
+		return x.Capabilities
 	}
 	return nil
 }
 
-func (x *AgentWithStatus) GetSessionId() int64 {
-	if x != nil {
-		return x.SessionId
-	}
-	return 0
-}
-
-func (x *AgentWithStatus) GetSecondsSinceHeartbeat() int64 {
+func (x *RegisterAgentResponse) GetToken() string {
 	if x != nil {
-		return x.SecondsSinceHeartbeat
+		return x.Token
 	}
-	return 0
+	return ""
 }
 
-// AgentStatusCounts represents counts of agents by status.
-type AgentStatusCounts struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Active        int32                  `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
-	Busy          int32                  `protobuf:"varint,2,opt,name=busy,proto3" json:"busy,omitempty"`
-	Idle          int32                  `protobuf:"varint,3,opt,name=idle,proto3" json:"idle,omitempty"`
-	Offline       int32                  `protobuf:"varint,4,opt,name=offline,proto3" json:"offline,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// AgentCapabilities are the capabilities an agent declares. Mail and tasks
+// addressed to cap:<name> are routed to an online agent declaring <name>,
+// and cap:<kind>:<name> restricts the match to one kind.
+type AgentCapabilities struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Languages []string               `protobuf:"bytes,1,rep,name=languages,proto3" json:"languages,omitempty"` // e.g. go, rust
+	Repos     []string               `protobuf:"bytes,2,rep,name=repos,proto3" json:"repos,omitempty"`         // e.g. roasbeef/substrate
+	Tools     []string               `protobuf:"bytes,3,rep,name=tools,proto3" json:"tools,omitempty"`         // e.g. gopls
+	Skills    []string               `protobuf:"bytes,4,rep,name=skills,proto3" json:"skills,omitempty"`       // e.g. go-review, can-review-security
+	// max_concurrency is the most tasks the agent takes on at once. 0
+	// means no limit.
+	MaxConcurrency int64 `protobuf:"varint,5,opt,name=max_concurrency,json=maxConcurrency,proto3" json:"max_concurrency,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *AgentStatusCounts) Reset() {
-	*x = AgentStatusCounts{}
+func (x *AgentCapabilities) Reset() {
+	*x = AgentCapabilities{}
 	mi := &file_mail_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AgentStatusCounts) String() string {
+func (x *AgentCapabilities) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AgentStatusCounts) ProtoMessage() {}
+func (*AgentCapabilities) ProtoMessage() {}
 
-func (x *AgentStatusCounts) ProtoReflect() protoreflect.Message {
+func (x *AgentCapabilities) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4136,60 +4835,69 @@ func (x *AgentStatusCounts) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AgentStatusCounts.ProtoReflect.Descriptor instead.
-func (*AgentStatusCounts) Descriptor() ([]byte, []int) {
+// Deprecated: Use AgentCapabilities.ProtoReflect.Descriptor instead.
+func (*AgentCapabilities) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *AgentStatusCounts) GetActive() int32 {
+func (x *AgentCapabilities) GetLanguages() []string {
 	if x != nil {
-		return x.Active
+		return x.Languages
 	}
-	return 0
+	return nil
 }
 
-func (x *AgentStatusCounts) GetBusy() int32 {
+func (x *AgentCapabilities) GetRepos() []string {
 	if x != nil {
-		return x.Busy
+		return x.Repos
 	}
-	return 0
+	return nil
 }
 
-func (x *AgentStatusCounts) GetIdle() int32 {
+func (x *AgentCapabilities) GetTools() []string {
 	if x != nil {
-		return x.Idle
+		return x.Tools
 	}
-	return 0
+	return nil
 }
 
-func (x *AgentStatusCounts) GetOffline() int32 {
+func (x *AgentCapabilities) GetSkills() []string {
 	if x != nil {
-		return x.Offline
+		return x.Skills
+	}
+	return nil
+}
+
+func (x *AgentCapabilities) GetMaxConcurrency() int64 {
+	if x != nil {
+		return x.MaxConcurrency
 	}
 	return 0
 }
 
-// GetAgentsStatusRequest is the request for GetAgentsStatus.
-type GetAgentsStatusRequest struct {
+// GetAgentRequest is the request for GetAgent.
+type GetAgentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Use ID or name
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAgentsStatusRequest) Reset() {
-	*x = GetAgentsStatusRequest{}
+func (x *GetAgentRequest) Reset() {
+	*x = GetAgentRequest{}
 	mi := &file_mail_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAgentsStatusRequest) String() string {
+func (x *GetAgentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAgentsStatusRequest) ProtoMessage() {}
+func (*GetAgentRequest) ProtoMessage() {}
 
-func (x *GetAgentsStatusRequest) ProtoReflect() protoreflect.Message {
+func (x *GetAgentRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4201,34 +4909,55 @@ func (x *GetAgentsStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAgentsStatusRequest.ProtoReflect.Descriptor instead.
-func (*GetAgentsStatusRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetAgentRequest.ProtoReflect.Descriptor instead.
+func (*GetAgentRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{63}
 }
 
-// GetAgentsStatusResponse is the response for GetAgentsStatus.
-type GetAgentsStatusResponse struct {
+func (x *GetAgentRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *GetAgentRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// GetAgentResponse is the response for GetAgent.
+// Note: int64 fields serialize as strings in JSON (protobuf standard).
+type GetAgentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Agents        []*AgentWithStatus     `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
-	Counts        *AgentStatusCounts     `protobuf:"bytes,2,opt,name=counts,proto3" json:"counts,omitempty"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ProjectKey    string                 `protobuf:"bytes,3,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
+	SessionId     string                 `protobuf:"bytes,4,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Current session ID (renamed from current_session_id)
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastActiveAt  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_active_at,json=lastActiveAt,proto3" json:"last_active_at,omitempty"`
+	GitBranch     string                 `protobuf:"bytes,7,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`
+	Capabilities  *AgentCapabilities     `protobuf:"bytes,8,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAgentsStatusResponse) Reset() {
-	*x = GetAgentsStatusResponse{}
+func (x *GetAgentResponse) Reset() {
+	*x = GetAgentResponse{}
 	mi := &file_mail_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAgentsStatusResponse) String() string {
+func (x *GetAgentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAgentsStatusResponse) ProtoMessage() {}
+func (*GetAgentResponse) ProtoMessage() {}
 
-func (x *GetAgentsStatusResponse) ProtoReflect() protoreflect.Message {
+func (x *GetAgentResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4240,52 +4969,89 @@ func (x *GetAgentsStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAgentsStatusResponse.ProtoReflect.Descriptor instead.
-func (*GetAgentsStatusResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetAgentResponse.ProtoReflect.Descriptor instead.
+func (*GetAgentResponse) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{64}
 }
 
-func (x *GetAgentsStatusResponse) GetAgents() []*AgentWithStatus {
+func (x *GetAgentResponse) GetId() int64 {
 	if x != nil {
-		return x.Agents
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetAgentResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetAgentResponse) GetProjectKey() string {
+	if x != nil {
+		return x.ProjectKey
+	}
+	return ""
+}
+
+func (x *GetAgentResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *GetAgentResponse) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
 	}
 	return nil
 }
 
-func (x *GetAgentsStatusResponse) GetCounts() *AgentStatusCounts {
+func (x *GetAgentResponse) GetLastActiveAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Counts
+		return x.LastActiveAt
 	}
 	return nil
 }
 
-// DiscoverAgentsRequest is the request for DiscoverAgents.
-type DiscoverAgentsRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// status_filter filters by agent status (match any). Empty means all.
-	StatusFilter []AgentStatus `protobuf:"varint,1,rep,packed,name=status_filter,json=statusFilter,proto3,enum=subtraterpc.AgentStatus" json:"status_filter,omitempty"`
-	// project_filter filters by project key prefix.
-	ProjectFilter string `protobuf:"bytes,2,opt,name=project_filter,json=projectFilter,proto3" json:"project_filter,omitempty"`
-	// name_filter filters by agent name substring.
-	NameFilter    string `protobuf:"bytes,3,opt,name=name_filter,json=nameFilter,proto3" json:"name_filter,omitempty"`
+func (x *GetAgentResponse) GetGitBranch() string {
+	if x != nil {
+		return x.GitBranch
+	}
+	return ""
+}
+
+func (x *GetAgentResponse) GetCapabilities() *AgentCapabilities {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+// ListAgentsRequest is the request for ListAgents.
+type ListAgentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DiscoverAgentsRequest) Reset() {
-	*x = DiscoverAgentsRequest{}
+func (x *ListAgentsRequest) Reset() {
+	*x = ListAgentsRequest{}
 	mi := &file_mail_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DiscoverAgentsRequest) String() string {
+func (x *ListAgentsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DiscoverAgentsRequest) ProtoMessage() {}
+func (*ListAgentsRequest) ProtoMessage() {}
 
-func (x *DiscoverAgentsRequest) ProtoReflect() protoreflect.Message {
+func (x *ListAgentsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4297,65 +5063,40 @@ func (x *DiscoverAgentsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DiscoverAgentsRequest.ProtoReflect.Descriptor instead.
-func (*DiscoverAgentsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListAgentsRequest.ProtoReflect.Descriptor instead.
+func (*ListAgentsRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *DiscoverAgentsRequest) GetStatusFilter() []AgentStatus {
+func (x *ListAgentsRequest) GetLimit() int32 {
 	if x != nil {
-		return x.StatusFilter
+		return x.Limit
 	}
-	return nil
-}
-
-func (x *DiscoverAgentsRequest) GetProjectFilter() string {
-	if x != nil {
-		return x.ProjectFilter
-	}
-	return ""
-}
-
-func (x *DiscoverAgentsRequest) GetNameFilter() string {
-	if x != nil {
-		return x.NameFilter
-	}
-	return ""
+	return 0
 }
 
-// DiscoveredAgent represents an agent with full discovery metadata.
-type DiscoveredAgent struct {
-	state                 protoimpl.MessageState `protogen:"open.v1"`
-	Id                    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name                  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	ProjectKey            string                 `protobuf:"bytes,3,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
-	GitBranch             string                 `protobuf:"bytes,4,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`
-	Status                AgentStatus            `protobuf:"varint,5,opt,name=status,proto3,enum=subtraterpc.AgentStatus" json:"status,omitempty"`
-	LastActiveAt          *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_active_at,json=lastActiveAt,proto3" json:"last_active_at,omitempty"`
-	SecondsSinceHeartbeat int64                  `protobuf:"varint,7,opt,name=seconds_since_heartbeat,json=secondsSinceHeartbeat,proto3" json:"seconds_since_heartbeat,omitempty"`
-	SessionId             string                 `protobuf:"bytes,8,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	Purpose               string                 `protobuf:"bytes,9,opt,name=purpose,proto3" json:"purpose,omitempty"`
-	WorkingDir            string                 `protobuf:"bytes,10,opt,name=working_dir,json=workingDir,proto3" json:"working_dir,omitempty"`
-	Hostname              string                 `protobuf:"bytes,11,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	UnreadCount           int64                  `protobuf:"varint,12,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
-	unknownFields         protoimpl.UnknownFields
-	sizeCache             protoimpl.SizeCache
+// ListAgentsResponse is the response for ListAgents.
+type ListAgentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agents        []*GetAgentResponse    `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DiscoveredAgent) Reset() {
-	*x = DiscoveredAgent{}
+func (x *ListAgentsResponse) Reset() {
+	*x = ListAgentsResponse{}
 	mi := &file_mail_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DiscoveredAgent) String() string {
+func (x *ListAgentsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DiscoveredAgent) ProtoMessage() {}
+func (*ListAgentsResponse) ProtoMessage() {}
 
-func (x *DiscoveredAgent) ProtoReflect() protoreflect.Message {
+func (x *ListAgentsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4367,119 +5108,114 @@ func (x *DiscoveredAgent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DiscoveredAgent.ProtoReflect.Descriptor instead.
-func (*DiscoveredAgent) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListAgentsResponse.ProtoReflect.Descriptor instead.
+func (*ListAgentsResponse) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{66}
 }
 
-func (x *DiscoveredAgent) GetId() int64 {
+func (x *ListAgentsResponse) GetAgents() []*GetAgentResponse {
 	if x != nil {
-		return x.Id
+		return x.Agents
 	}
-	return 0
+	return nil
 }
 
-func (x *DiscoveredAgent) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
+// EnsureIdentityRequest is the request for EnsureIdentity.
+type EnsureIdentityRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	SessionId  string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	ProjectDir string                 `protobuf:"bytes,2,opt,name=project_dir,json=projectDir,proto3" json:"project_dir,omitempty"`
+	GitBranch  string                 `protobuf:"bytes,3,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`
+	// mint_token also mints a bearer token for the agent.
+	MintToken     bool `protobuf:"varint,4,opt,name=mint_token,json=mintToken,proto3" json:"mint_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DiscoveredAgent) GetProjectKey() string {
-	if x != nil {
-		return x.ProjectKey
-	}
-	return ""
+func (x *EnsureIdentityRequest) Reset() {
+	*x = EnsureIdentityRequest{}
+	mi := &file_mail_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *DiscoveredAgent) GetGitBranch() string {
-	if x != nil {
-		return x.GitBranch
-	}
-	return ""
+func (x *EnsureIdentityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *DiscoveredAgent) GetStatus() AgentStatus {
-	if x != nil {
-		return x.Status
-	}
-	return AgentStatus_AGENT_STATUS_UNSPECIFIED
-}
+func (*EnsureIdentityRequest) ProtoMessage() {}
 
-func (x *DiscoveredAgent) GetLastActiveAt() *timestamppb.Timestamp {
+func (x *EnsureIdentityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[67]
 	if x != nil {
-		return x.LastActiveAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *DiscoveredAgent) GetSecondsSinceHeartbeat() int64 {
-	if x != nil {
-		return x.SecondsSinceHeartbeat
-	}
-	return 0
+// Deprecated: Use EnsureIdentityRequest.ProtoReflect.Descriptor instead.
+func (*EnsureIdentityRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{67}
 }
 
-func (x *DiscoveredAgent) GetSessionId() string {
+func (x *EnsureIdentityRequest) GetSessionId() string {
 	if x != nil {
 		return x.SessionId
 	}
 	return ""
 }
 
-func (x *DiscoveredAgent) GetPurpose() string {
-	if x != nil {
-		return x.Purpose
-	}
-	return ""
-}
-
-func (x *DiscoveredAgent) GetWorkingDir() string {
+func (x *EnsureIdentityRequest) GetProjectDir() string {
 	if x != nil {
-		return x.WorkingDir
+		return x.ProjectDir
 	}
 	return ""
 }
 
-func (x *DiscoveredAgent) GetHostname() string {
+func (x *EnsureIdentityRequest) GetGitBranch() string {
 	if x != nil {
-		return x.Hostname
+		return x.GitBranch
 	}
 	return ""
 }
 
-func (x *DiscoveredAgent) GetUnreadCount() int64 {
+func (x *EnsureIdentityRequest) GetMintToken() bool {
 	if x != nil {
-		return x.UnreadCount
+		return x.MintToken
 	}
-	return 0
+	return false
 }
 
-// DiscoverAgentsResponse is the response for DiscoverAgents.
-type DiscoverAgentsResponse struct {
+// EnsureIdentityResponse is the response for EnsureIdentity.
+type EnsureIdentityResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Agents        []*DiscoveredAgent     `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
-	Counts        *AgentStatusCounts     `protobuf:"bytes,2,opt,name=counts,proto3" json:"counts,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	AgentName     string                 `protobuf:"bytes,2,opt,name=agent_name,json=agentName,proto3" json:"agent_name,omitempty"`
+	Created       bool                   `protobuf:"varint,3,opt,name=created,proto3" json:"created,omitempty"` // True if a new agent was created
+	Token         string                 `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`      // Set when mint_token was given
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DiscoverAgentsResponse) Reset() {
-	*x = DiscoverAgentsResponse{}
-	mi := &file_mail_proto_msgTypes[67]
+func (x *EnsureIdentityResponse) Reset() {
+	*x = EnsureIdentityResponse{}
+	mi := &file_mail_proto_msgTypes[68]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DiscoverAgentsResponse) String() string {
+func (x *EnsureIdentityResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DiscoverAgentsResponse) ProtoMessage() {}
+func (*EnsureIdentityResponse) ProtoMessage() {}
 
-func (x *DiscoverAgentsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[67]
+func (x *EnsureIdentityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[68]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4490,52 +5226,64 @@ func (x *DiscoverAgentsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DiscoverAgentsResponse.ProtoReflect.Descriptor instead.
-func (*DiscoverAgentsResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{67}
+// Deprecated: Use EnsureIdentityResponse.ProtoReflect.Descriptor instead.
+func (*EnsureIdentityResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{68}
 }
 
-func (x *DiscoverAgentsResponse) GetAgents() []*DiscoveredAgent {
+func (x *EnsureIdentityResponse) GetAgentId() int64 {
 	if x != nil {
-		return x.Agents
+		return x.AgentId
 	}
-	return nil
+	return 0
 }
 
-func (x *DiscoverAgentsResponse) GetCounts() *AgentStatusCounts {
+func (x *EnsureIdentityResponse) GetAgentName() string {
 	if x != nil {
-		return x.Counts
+		return x.AgentName
 	}
-	return nil
+	return ""
 }
 
-// HeartbeatRequest is the request for Heartbeat.
-// Either agent_id or agent_name must be provided. If both are given,
-// agent_id takes precedence.
-type HeartbeatRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,proto3" json:"agent_id,omitempty"`
-	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,proto3" json:"session_id,omitempty"`
-	AgentName     string                 `protobuf:"bytes,3,opt,name=agent_name,proto3" json:"agent_name,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *EnsureIdentityResponse) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
 }
 
-func (x *HeartbeatRequest) Reset() {
-	*x = HeartbeatRequest{}
-	mi := &file_mail_proto_msgTypes[68]
+func (x *EnsureIdentityResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+// SaveIdentityRequest is the request for SaveIdentity.
+type SaveIdentityRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	SessionId       string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	AgentId         int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	ConsumerOffsets map[int64]int64        `protobuf:"bytes,3,rep,name=consumer_offsets,json=consumerOffsets,proto3" json:"consumer_offsets,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // topic_id -> offset
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SaveIdentityRequest) Reset() {
+	*x = SaveIdentityRequest{}
+	mi := &file_mail_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HeartbeatRequest) String() string {
+func (x *SaveIdentityRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HeartbeatRequest) ProtoMessage() {}
+func (*SaveIdentityRequest) ProtoMessage() {}
 
-func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[68]
+func (x *SaveIdentityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4546,55 +5294,55 @@ func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
-func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{68}
+// Deprecated: Use SaveIdentityRequest.ProtoReflect.Descriptor instead.
+func (*SaveIdentityRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{69}
 }
 
-func (x *HeartbeatRequest) GetAgentId() int64 {
+func (x *SaveIdentityRequest) GetSessionId() string {
 	if x != nil {
-		return x.AgentId
+		return x.SessionId
 	}
-	return 0
+	return ""
 }
 
-func (x *HeartbeatRequest) GetSessionId() string {
+func (x *SaveIdentityRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.SessionId
+		return x.AgentId
 	}
-	return ""
+	return 0
 }
 
-func (x *HeartbeatRequest) GetAgentName() string {
+func (x *SaveIdentityRequest) GetConsumerOffsets() map[int64]int64 {
 	if x != nil {
-		return x.AgentName
+		return x.ConsumerOffsets
 	}
-	return ""
+	return nil
 }
 
-// HeartbeatResponse is the response for Heartbeat.
-type HeartbeatResponse struct {
+// SaveIdentityResponse is the response for SaveIdentity.
+type SaveIdentityResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HeartbeatResponse) Reset() {
-	*x = HeartbeatResponse{}
-	mi := &file_mail_proto_msgTypes[69]
+func (x *SaveIdentityResponse) Reset() {
+	*x = SaveIdentityResponse{}
+	mi := &file_mail_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HeartbeatResponse) String() string {
+func (x *SaveIdentityResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HeartbeatResponse) ProtoMessage() {}
+func (*SaveIdentityResponse) ProtoMessage() {}
 
-func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[69]
+func (x *SaveIdentityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4605,48 +5353,41 @@ func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
-func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{69}
+// Deprecated: Use SaveIdentityResponse.ProtoReflect.Descriptor instead.
+func (*SaveIdentityResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{70}
 }
 
-func (x *HeartbeatResponse) GetSuccess() bool {
+func (x *SaveIdentityResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-// SessionInfo represents a session.
-type SessionInfo struct {
+// DeleteAgentRequest is the request for DeleteAgent.
+type DeleteAgentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	AgentId       int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	AgentName     string                 `protobuf:"bytes,3,opt,name=agent_name,json=agentName,proto3" json:"agent_name,omitempty"`
-	Project       string                 `protobuf:"bytes,4,opt,name=project,proto3" json:"project,omitempty"`
-	Branch        string                 `protobuf:"bytes,5,opt,name=branch,proto3" json:"branch,omitempty"`
-	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
-	EndedAt       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=ended_at,json=endedAt,proto3" json:"ended_at,omitempty"`
-	Status        SessionStatus          `protobuf:"varint,8,opt,name=status,proto3,enum=subtraterpc.SessionStatus" json:"status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SessionInfo) Reset() {
-	*x = SessionInfo{}
-	mi := &file_mail_proto_msgTypes[70]
+func (x *DeleteAgentRequest) Reset() {
+	*x = DeleteAgentRequest{}
+	mi := &file_mail_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SessionInfo) String() string {
+func (x *DeleteAgentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SessionInfo) ProtoMessage() {}
+func (*DeleteAgentRequest) ProtoMessage() {}
 
-func (x *SessionInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[70]
+func (x *DeleteAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4657,91 +5398,41 @@ func (x *SessionInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SessionInfo.ProtoReflect.Descriptor instead.
-func (*SessionInfo) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{70}
+// Deprecated: Use DeleteAgentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAgentRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{71}
 }
 
-func (x *SessionInfo) GetId() int64 {
+func (x *DeleteAgentRequest) GetId() int64 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *SessionInfo) GetAgentId() int64 {
-	if x != nil {
-		return x.AgentId
-	}
-	return 0
-}
-
-func (x *SessionInfo) GetAgentName() string {
-	if x != nil {
-		return x.AgentName
-	}
-	return ""
-}
-
-func (x *SessionInfo) GetProject() string {
-	if x != nil {
-		return x.Project
-	}
-	return ""
-}
-
-func (x *SessionInfo) GetBranch() string {
-	if x != nil {
-		return x.Branch
-	}
-	return ""
-}
-
-func (x *SessionInfo) GetStartedAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.StartedAt
-	}
-	return nil
-}
-
-func (x *SessionInfo) GetEndedAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.EndedAt
-	}
-	return nil
-}
-
-func (x *SessionInfo) GetStatus() SessionStatus {
-	if x != nil {
-		return x.Status
-	}
-	return SessionStatus_SESSION_STATUS_UNSPECIFIED
-}
-
-// ListSessionsRequest is the request for ListSessions.
-type ListSessionsRequest struct {
+// DeleteAgentResponse is the response for DeleteAgent.
+type DeleteAgentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ActiveOnly    bool                   `protobuf:"varint,1,opt,name=active_only,json=activeOnly,proto3" json:"active_only,omitempty"`
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListSessionsRequest) Reset() {
-	*x = ListSessionsRequest{}
-	mi := &file_mail_proto_msgTypes[71]
+func (x *DeleteAgentResponse) Reset() {
+	*x = DeleteAgentResponse{}
+	mi := &file_mail_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListSessionsRequest) String() string {
+func (x *DeleteAgentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListSessionsRequest) ProtoMessage() {}
+func (*DeleteAgentResponse) ProtoMessage() {}
 
-func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[71]
+func (x *DeleteAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4752,48 +5443,57 @@ func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
-func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{71}
+// Deprecated: Use DeleteAgentResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAgentResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{72}
 }
 
-func (x *ListSessionsRequest) GetActiveOnly() bool {
+func (x *DeleteAgentResponse) GetSuccess() bool {
 	if x != nil {
-		return x.ActiveOnly
+		return x.Success
 	}
 	return false
 }
 
-func (x *ListSessionsRequest) GetLimit() int32 {
-	if x != nil {
-		return x.Limit
-	}
-	return 0
-}
-
-// ListSessionsResponse is the response for ListSessions.
-type ListSessionsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Sessions      []*SessionInfo         `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// ReplyToThreadRequest is the request for ReplyToThread.
+type ReplyToThreadRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	SenderId int64                  `protobuf:"varint,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	ThreadId string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	Body     string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	// message_id replies to a specific message instead of the thread.
+	// The reply goes to the message's sender and its other recipients,
+	// and thread_id may be left empty.
+	MessageId int64 `protobuf:"varint,4,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// sender_only replies to the message's sender alone. Only used with
+	// message_id.
+	SenderOnly bool `protobuf:"varint,5,opt,name=sender_only,json=senderOnly,proto3" json:"sender_only,omitempty"`
+	// quote appends the replied-to message to the reply, quoted. Only
+	// used with message_id.
+	Quote bool `protobuf:"varint,6,opt,name=quote,proto3" json:"quote,omitempty"`
+	// priority is the reply's priority, normal if unspecified.
+	Priority Priority `protobuf:"varint,7,opt,name=priority,proto3,enum=subtraterpc.Priority" json:"priority,omitempty"`
+	// idempotency_key deduplicates retried replies.
+	IdempotencyKey string `protobuf:"bytes,8,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *ListSessionsResponse) Reset() {
-	*x = ListSessionsResponse{}
-	mi := &file_mail_proto_msgTypes[72]
+func (x *ReplyToThreadRequest) Reset() {
+	*x = ReplyToThreadRequest{}
+	mi := &file_mail_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListSessionsResponse) String() string {
+func (x *ReplyToThreadRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListSessionsResponse) ProtoMessage() {}
+func (*ReplyToThreadRequest) ProtoMessage() {}
 
-func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[72]
+func (x *ReplyToThreadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4804,85 +5504,93 @@ func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
-func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{72}
+// Deprecated: Use ReplyToThreadRequest.ProtoReflect.Descriptor instead.
+func (*ReplyToThreadRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{73}
 }
 
-func (x *ListSessionsResponse) GetSessions() []*SessionInfo {
+func (x *ReplyToThreadRequest) GetSenderId() int64 {
 	if x != nil {
-		return x.Sessions
+		return x.SenderId
 	}
-	return nil
+	return 0
 }
 
-// GetSessionRequest is the request for GetSession.
-type GetSessionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	SessionId     int64                  `protobuf:"varint,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ReplyToThreadRequest) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
 }
 
-func (x *GetSessionRequest) Reset() {
-	*x = GetSessionRequest{}
-	mi := &file_mail_proto_msgTypes[73]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ReplyToThreadRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
 }
 
-func (x *GetSessionRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ReplyToThreadRequest) GetMessageId() int64 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
 }
 
-func (*GetSessionRequest) ProtoMessage() {}
+func (x *ReplyToThreadRequest) GetSenderOnly() bool {
+	if x != nil {
+		return x.SenderOnly
+	}
+	return false
+}
 
-func (x *GetSessionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[73]
+func (x *ReplyToThreadRequest) GetQuote() bool {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Quote
 	}
-	return mi.MessageOf(x)
+	return false
 }
 
-// Deprecated: Use GetSessionRequest.ProtoReflect.Descriptor instead.
-func (*GetSessionRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{73}
+func (x *ReplyToThreadRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
 }
 
-func (x *GetSessionRequest) GetSessionId() int64 {
+func (x *ReplyToThreadRequest) GetIdempotencyKey() string {
 	if x != nil {
-		return x.SessionId
+		return x.IdempotencyKey
 	}
-	return 0
+	return ""
 }
 
-// GetSessionResponse is the response for GetSession.
-type GetSessionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Session       *SessionInfo           `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// ReplyToThreadResponse is the response for ReplyToThread.
+type ReplyToThreadResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	MessageId int64                  `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// thread_id is the thread the reply joined.
+	ThreadId string `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	// recipient_names are the agents the reply was sent to.
+	RecipientNames []string `protobuf:"bytes,3,rep,name=recipient_names,json=recipientNames,proto3" json:"recipient_names,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *GetSessionResponse) Reset() {
-	*x = GetSessionResponse{}
+func (x *ReplyToThreadResponse) Reset() {
+	*x = ReplyToThreadResponse{}
 	mi := &file_mail_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSessionResponse) String() string {
+func (x *ReplyToThreadResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSessionResponse) ProtoMessage() {}
+func (*ReplyToThreadResponse) ProtoMessage() {}
 
-func (x *GetSessionResponse) ProtoReflect() protoreflect.Message {
+func (x *ReplyToThreadResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4894,42 +5602,55 @@ func (x *GetSessionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSessionResponse.ProtoReflect.Descriptor instead.
-func (*GetSessionResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReplyToThreadResponse.ProtoReflect.Descriptor instead.
+func (*ReplyToThreadResponse) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{74}
 }
 
-func (x *GetSessionResponse) GetSession() *SessionInfo {
+func (x *ReplyToThreadResponse) GetMessageId() int64 {
 	if x != nil {
-		return x.Session
+		return x.MessageId
 	}
-	return nil
+	return 0
 }
 
-// StartSessionRequest is the request for StartSession.
-type StartSessionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+func (x *ReplyToThreadResponse) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *ReplyToThreadResponse) GetRecipientNames() []string {
+	if x != nil {
+		return x.RecipientNames
+	}
+	return nil
+}
+
+// ArchiveThreadRequest is the request for ArchiveThread.
+type ArchiveThreadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	Project       string                 `protobuf:"bytes,2,opt,name=project,proto3" json:"project,omitempty"`
-	Branch        string                 `protobuf:"bytes,3,opt,name=branch,proto3" json:"branch,omitempty"`
+	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StartSessionRequest) Reset() {
-	*x = StartSessionRequest{}
+func (x *ArchiveThreadRequest) Reset() {
+	*x = ArchiveThreadRequest{}
 	mi := &file_mail_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StartSessionRequest) String() string {
+func (x *ArchiveThreadRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StartSessionRequest) ProtoMessage() {}
+func (*ArchiveThreadRequest) ProtoMessage() {}
 
-func (x *StartSessionRequest) ProtoReflect() protoreflect.Message {
+func (x *ArchiveThreadRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4941,54 +5662,48 @@ func (x *StartSessionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StartSessionRequest.ProtoReflect.Descriptor instead.
-func (*StartSessionRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ArchiveThreadRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveThreadRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{75}
 }
 
-func (x *StartSessionRequest) GetAgentId() int64 {
+func (x *ArchiveThreadRequest) GetAgentId() int64 {
 	if x != nil {
 		return x.AgentId
 	}
 	return 0
 }
 
-func (x *StartSessionRequest) GetProject() string {
-	if x != nil {
-		return x.Project
-	}
-	return ""
-}
-
-func (x *StartSessionRequest) GetBranch() string {
+func (x *ArchiveThreadRequest) GetThreadId() string {
 	if x != nil {
-		return x.Branch
+		return x.ThreadId
 	}
 	return ""
 }
 
-// StartSessionResponse is the response for StartSession.
-type StartSessionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Session       *SessionInfo           `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// ArchiveThreadResponse is the response for ArchiveThread.
+type ArchiveThreadResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Success          bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	MessagesArchived int32                  `protobuf:"varint,2,opt,name=messages_archived,json=messagesArchived,proto3" json:"messages_archived,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *StartSessionResponse) Reset() {
-	*x = StartSessionResponse{}
+func (x *ArchiveThreadResponse) Reset() {
+	*x = ArchiveThreadResponse{}
 	mi := &file_mail_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StartSessionResponse) String() string {
+func (x *ArchiveThreadResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StartSessionResponse) ProtoMessage() {}
+func (*ArchiveThreadResponse) ProtoMessage() {}
 
-func (x *StartSessionResponse) ProtoReflect() protoreflect.Message {
+func (x *ArchiveThreadResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -5000,40 +5715,48 @@ func (x *StartSessionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StartSessionResponse.ProtoReflect.Descriptor instead.
-func (*StartSessionResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ArchiveThreadResponse.ProtoReflect.Descriptor instead.
+func (*ArchiveThreadResponse) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{76}
 }
 
-func (x *StartSessionResponse) GetSession() *SessionInfo {
+func (x *ArchiveThreadResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Session
+		return x.Success
 	}
-	return nil
+	return false
 }
 
-// CompleteSessionRequest is the request for CompleteSession.
-type CompleteSessionRequest struct {
+func (x *ArchiveThreadResponse) GetMessagesArchived() int32 {
+	if x != nil {
+		return x.MessagesArchived
+	}
+	return 0
+}
+
+// DeleteThreadRequest is the request for DeleteThread.
+type DeleteThreadRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	SessionId     int64                  `protobuf:"varint,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CompleteSessionRequest) Reset() {
-	*x = CompleteSessionRequest{}
+func (x *DeleteThreadRequest) Reset() {
+	*x = DeleteThreadRequest{}
 	mi := &file_mail_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CompleteSessionRequest) String() string {
+func (x *DeleteThreadRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CompleteSessionRequest) ProtoMessage() {}
+func (*DeleteThreadRequest) ProtoMessage() {}
 
-func (x *CompleteSessionRequest) ProtoReflect() protoreflect.Message {
+func (x *DeleteThreadRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -5045,40 +5768,48 @@ func (x *CompleteSessionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CompleteSessionRequest.ProtoReflect.Descriptor instead.
-func (*CompleteSessionRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteThreadRequest.ProtoReflect.Descriptor instead.
+func (*DeleteThreadRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{77}
 }
 
-func (x *CompleteSessionRequest) GetSessionId() int64 {
+func (x *DeleteThreadRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.SessionId
+		return x.AgentId
 	}
 	return 0
 }
 
-// CompleteSessionResponse is the response for CompleteSession.
-type CompleteSessionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *DeleteThreadRequest) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
 }
 
-func (x *CompleteSessionResponse) Reset() {
-	*x = CompleteSessionResponse{}
+// DeleteThreadResponse is the response for DeleteThread.
+type DeleteThreadResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	MessagesDeleted int32                  `protobuf:"varint,2,opt,name=messages_deleted,json=messagesDeleted,proto3" json:"messages_deleted,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeleteThreadResponse) Reset() {
+	*x = DeleteThreadResponse{}
 	mi := &file_mail_proto_msgTypes[78]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CompleteSessionResponse) String() string {
+func (x *DeleteThreadResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CompleteSessionResponse) ProtoMessage() {}
+func (*DeleteThreadResponse) ProtoMessage() {}
 
-func (x *CompleteSessionResponse) ProtoReflect() protoreflect.Message {
+func (x *DeleteThreadResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[78]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -5090,46 +5821,48 @@ func (x *CompleteSessionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CompleteSessionResponse.ProtoReflect.Descriptor instead.
-func (*CompleteSessionResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteThreadResponse.ProtoReflect.Descriptor instead.
+func (*DeleteThreadResponse) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{78}
 }
 
-func (x *CompleteSessionResponse) GetSuccess() bool {
+func (x *DeleteThreadResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-// ActivityInfo represents an activity entry.
-type ActivityInfo struct {
+func (x *DeleteThreadResponse) GetMessagesDeleted() int32 {
+	if x != nil {
+		return x.MessagesDeleted
+	}
+	return 0
+}
+
+// MarkThreadUnreadRequest is the request for MarkThreadUnread.
+type MarkThreadUnreadRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	AgentId       int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	AgentName     string                 `protobuf:"bytes,3,opt,name=agent_name,json=agentName,proto3" json:"agent_name,omitempty"`
-	Type          ActivityType           `protobuf:"varint,4,opt,name=type,proto3,enum=subtraterpc.ActivityType" json:"type,omitempty"`
-	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	MetadataJson  string                 `protobuf:"bytes,7,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"` // JSON-encoded metadata
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ActivityInfo) Reset() {
-	*x = ActivityInfo{}
+func (x *MarkThreadUnreadRequest) Reset() {
+	*x = MarkThreadUnreadRequest{}
 	mi := &file_mail_proto_msgTypes[79]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ActivityInfo) String() string {
+func (x *MarkThreadUnreadRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ActivityInfo) ProtoMessage() {}
+func (*MarkThreadUnreadRequest) ProtoMessage() {}
 
-func (x *ActivityInfo) ProtoReflect() protoreflect.Message {
+func (x *MarkThreadUnreadRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[79]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -5141,85 +5874,50 @@ func (x *ActivityInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ActivityInfo.ProtoReflect.Descriptor instead.
-func (*ActivityInfo) Descriptor() ([]byte, []int) {
+// Deprecated: Use MarkThreadUnreadRequest.ProtoReflect.Descriptor instead.
+func (*MarkThreadUnreadRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{79}
 }
 
-func (x *ActivityInfo) GetId() int64 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-func (x *ActivityInfo) GetAgentId() int64 {
+func (x *MarkThreadUnreadRequest) GetAgentId() int64 {
 	if x != nil {
 		return x.AgentId
 	}
 	return 0
 }
 
-func (x *ActivityInfo) GetAgentName() string {
-	if x != nil {
-		return x.AgentName
-	}
-	return ""
-}
-
-func (x *ActivityInfo) GetType() ActivityType {
-	if x != nil {
-		return x.Type
-	}
-	return ActivityType_ACTIVITY_TYPE_UNSPECIFIED
-}
-
-func (x *ActivityInfo) GetDescription() string {
-	if x != nil {
-		return x.Description
-	}
-	return ""
-}
-
-func (x *ActivityInfo) GetCreatedAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.CreatedAt
-	}
-	return nil
-}
-
-func (x *ActivityInfo) GetMetadataJson() string {
+func (x *MarkThreadUnreadRequest) GetThreadId() string {
 	if x != nil {
-		return x.MetadataJson
+		return x.ThreadId
 	}
 	return ""
 }
 
-// ListActivitiesRequest is the request for ListActivities.
-type ListActivitiesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`          // Filter by agent (0 = all)
-	Type          ActivityType           `protobuf:"varint,2,opt,name=type,proto3,enum=subtraterpc.ActivityType" json:"type,omitempty"` // Filter by type (UNSPECIFIED = all)
-	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// MarkThreadUnreadResponse is the response for MarkThreadUnread.
+type MarkThreadUnreadResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// messages_marked is how many of the agent's messages in the thread
+	// were marked unread.
+	MessagesMarked int32 `protobuf:"varint,2,opt,name=messages_marked,json=messagesMarked,proto3" json:"messages_marked,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *ListActivitiesRequest) Reset() {
-	*x = ListActivitiesRequest{}
+func (x *MarkThreadUnreadResponse) Reset() {
+	*x = MarkThreadUnreadResponse{}
 	mi := &file_mail_proto_msgTypes[80]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListActivitiesRequest) String() string {
+func (x *MarkThreadUnreadResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListActivitiesRequest) ProtoMessage() {}
+func (*MarkThreadUnreadResponse) ProtoMessage() {}
 
-func (x *ListActivitiesRequest) ProtoReflect() protoreflect.Message {
+func (x *MarkThreadUnreadResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[80]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -5231,64 +5929,47 @@ func (x *ListActivitiesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListActivitiesRequest.ProtoReflect.Descriptor instead.
-func (*ListActivitiesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use MarkThreadUnreadResponse.ProtoReflect.Descriptor instead.
+func (*MarkThreadUnreadResponse) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{80}
 }
 
-func (x *ListActivitiesRequest) GetAgentId() int64 {
+func (x *MarkThreadUnreadResponse) GetSuccess() bool {
 	if x != nil {
-		return x.AgentId
+		return x.Success
 	}
-	return 0
+	return false
 }
 
-func (x *ListActivitiesRequest) GetType() ActivityType {
+func (x *MarkThreadUnreadResponse) GetMessagesMarked() int32 {
 	if x != nil {
-		return x.Type
-	}
-	return ActivityType_ACTIVITY_TYPE_UNSPECIFIED
-}
-
-func (x *ListActivitiesRequest) GetPage() int32 {
-	if x != nil {
-		return x.Page
-	}
-	return 0
-}
-
-func (x *ListActivitiesRequest) GetPageSize() int32 {
-	if x != nil {
-		return x.PageSize
+		return x.MessagesMarked
 	}
 	return 0
 }
 
-// ListActivitiesResponse is the response for ListActivities.
-type ListActivitiesResponse struct {
+// GetTopicRequest is the request for GetTopic.
+type GetTopicRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Activities    []*ActivityInfo        `protobuf:"bytes,1,rep,name=activities,proto3" json:"activities,omitempty"`
-	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
-	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	TopicId       int64                  `protobuf:"varint,1,opt,name=topic_id,json=topicId,proto3" json:"topic_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListActivitiesResponse) Reset() {
-	*x = ListActivitiesResponse{}
+func (x *GetTopicRequest) Reset() {
+	*x = GetTopicRequest{}
 	mi := &file_mail_proto_msgTypes[81]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListActivitiesResponse) String() string {
+func (x *GetTopicRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListActivitiesResponse) ProtoMessage() {}
+func (*GetTopicRequest) ProtoMessage() {}
 
-func (x *ListActivitiesResponse) ProtoReflect() protoreflect.Message {
+func (x *GetTopicRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[81]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -5300,64 +5981,40 @@ func (x *ListActivitiesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListActivitiesResponse.ProtoReflect.Descriptor instead.
-func (*ListActivitiesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetTopicRequest.ProtoReflect.Descriptor instead.
+func (*GetTopicRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{81}
 }
 
-func (x *ListActivitiesResponse) GetActivities() []*ActivityInfo {
-	if x != nil {
-		return x.Activities
-	}
-	return nil
-}
-
-func (x *ListActivitiesResponse) GetTotal() int64 {
-	if x != nil {
-		return x.Total
-	}
-	return 0
-}
-
-func (x *ListActivitiesResponse) GetPage() int32 {
-	if x != nil {
-		return x.Page
-	}
-	return 0
-}
-
-func (x *ListActivitiesResponse) GetPageSize() int32 {
+func (x *GetTopicRequest) GetTopicId() int64 {
 	if x != nil {
-		return x.PageSize
+		return x.TopicId
 	}
 	return 0
 }
 
-// DashboardStats represents dashboard statistics.
-type DashboardStats struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	ActiveAgents    int32                  `protobuf:"varint,1,opt,name=active_agents,json=activeAgents,proto3" json:"active_agents,omitempty"`
-	RunningSessions int32                  `protobuf:"varint,2,opt,name=running_sessions,json=runningSessions,proto3" json:"running_sessions,omitempty"`
-	PendingMessages int32                  `protobuf:"varint,3,opt,name=pending_messages,json=pendingMessages,proto3" json:"pending_messages,omitempty"`
-	CompletedToday  int32                  `protobuf:"varint,4,opt,name=completed_today,json=completedToday,proto3" json:"completed_today,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+// GetTopicResponse is the response for GetTopic.
+type GetTopicResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Topic         *Topic                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DashboardStats) Reset() {
-	*x = DashboardStats{}
+func (x *GetTopicResponse) Reset() {
+	*x = GetTopicResponse{}
 	mi := &file_mail_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DashboardStats) String() string {
+func (x *GetTopicResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DashboardStats) ProtoMessage() {}
+func (*GetTopicResponse) ProtoMessage() {}
 
-func (x *DashboardStats) ProtoReflect() protoreflect.Message {
+func (x *GetTopicResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -5369,60 +6026,41 @@ func (x *DashboardStats) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DashboardStats.ProtoReflect.Descriptor instead.
-func (*DashboardStats) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetTopicResponse.ProtoReflect.Descriptor instead.
+func (*GetTopicResponse) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{82}
 }
 
-func (x *DashboardStats) GetActiveAgents() int32 {
-	if x != nil {
-		return x.ActiveAgents
-	}
-	return 0
-}
-
-func (x *DashboardStats) GetRunningSessions() int32 {
-	if x != nil {
-		return x.RunningSessions
-	}
-	return 0
-}
-
-func (x *DashboardStats) GetPendingMessages() int32 {
-	if x != nil {
-		return x.PendingMessages
-	}
-	return 0
-}
-
-func (x *DashboardStats) GetCompletedToday() int32 {
+func (x *GetTopicResponse) GetTopic() *Topic {
 	if x != nil {
-		return x.CompletedToday
+		return x.Topic
 	}
-	return 0
+	return nil
 }
 
-// GetDashboardStatsRequest is the request for GetDashboardStats.
-type GetDashboardStatsRequest struct {
+// AutocompleteRecipientsRequest is the request for AutocompleteRecipients.
+type AutocompleteRecipientsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDashboardStatsRequest) Reset() {
-	*x = GetDashboardStatsRequest{}
+func (x *AutocompleteRecipientsRequest) Reset() {
+	*x = AutocompleteRecipientsRequest{}
 	mi := &file_mail_proto_msgTypes[83]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDashboardStatsRequest) String() string {
+func (x *AutocompleteRecipientsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDashboardStatsRequest) ProtoMessage() {}
+func (*AutocompleteRecipientsRequest) ProtoMessage() {}
 
-func (x *GetDashboardStatsRequest) ProtoReflect() protoreflect.Message {
+func (x *AutocompleteRecipientsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[83]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -5434,33 +6072,51 @@ func (x *GetDashboardStatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDashboardStatsRequest.ProtoReflect.Descriptor instead.
-func (*GetDashboardStatsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use AutocompleteRecipientsRequest.ProtoReflect.Descriptor instead.
+func (*AutocompleteRecipientsRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{83}
 }
 
-// GetDashboardStatsResponse is the response for GetDashboardStats.
-type GetDashboardStatsResponse struct {
+func (x *AutocompleteRecipientsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *AutocompleteRecipientsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// AutocompleteRecipient represents a recipient suggestion.
+type AutocompleteRecipient struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Stats         *DashboardStats        `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ProjectKey    string                 `protobuf:"bytes,3,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
+	GitBranch     string                 `protobuf:"bytes,4,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"` // "active", "busy", "idle", "offline"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDashboardStatsResponse) Reset() {
-	*x = GetDashboardStatsResponse{}
+func (x *AutocompleteRecipient) Reset() {
+	*x = AutocompleteRecipient{}
 	mi := &file_mail_proto_msgTypes[84]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDashboardStatsResponse) String() string {
+func (x *AutocompleteRecipient) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDashboardStatsResponse) ProtoMessage() {}
+func (*AutocompleteRecipient) ProtoMessage() {}
 
-func (x *GetDashboardStatsResponse) ProtoReflect() protoreflect.Message {
+func (x *AutocompleteRecipient) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[84]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -5472,79 +6128,69 @@ func (x *GetDashboardStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDashboardStatsResponse.ProtoReflect.Descriptor instead.
-func (*GetDashboardStatsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use AutocompleteRecipient.ProtoReflect.Descriptor instead.
+func (*AutocompleteRecipient) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{84}
 }
 
-func (x *GetDashboardStatsResponse) GetStats() *DashboardStats {
+func (x *AutocompleteRecipient) GetId() int64 {
 	if x != nil {
-		return x.Stats
+		return x.Id
 	}
-	return nil
-}
-
-// HealthCheckRequest is the request for HealthCheck.
-type HealthCheckRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	return 0
 }
 
-func (x *HealthCheckRequest) Reset() {
-	*x = HealthCheckRequest{}
-	mi := &file_mail_proto_msgTypes[85]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *AutocompleteRecipient) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
 }
 
-func (x *HealthCheckRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *AutocompleteRecipient) GetProjectKey() string {
+	if x != nil {
+		return x.ProjectKey
+	}
+	return ""
 }
 
-func (*HealthCheckRequest) ProtoMessage() {}
-
-func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[85]
+func (x *AutocompleteRecipient) GetGitBranch() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.GitBranch
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use HealthCheckRequest.ProtoReflect.Descriptor instead.
-func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{85}
+func (x *AutocompleteRecipient) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
 }
 
-// HealthCheckResponse is the response for HealthCheck.
-type HealthCheckResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // "ok" or "error"
-	Time          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+// AutocompleteRecipientsResponse is the response for AutocompleteRecipients.
+type AutocompleteRecipientsResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Recipients    []*AutocompleteRecipient `protobuf:"bytes,1,rep,name=recipients,proto3" json:"recipients,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HealthCheckResponse) Reset() {
-	*x = HealthCheckResponse{}
-	mi := &file_mail_proto_msgTypes[86]
+func (x *AutocompleteRecipientsResponse) Reset() {
+	*x = AutocompleteRecipientsResponse{}
+	mi := &file_mail_proto_msgTypes[85]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HealthCheckResponse) String() string {
+func (x *AutocompleteRecipientsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthCheckResponse) ProtoMessage() {}
+func (*AutocompleteRecipientsResponse) ProtoMessage() {}
 
-func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[86]
+func (x *AutocompleteRecipientsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[85]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -5555,50 +6201,103 @@ func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthCheckResponse.ProtoReflect.Descriptor instead.
-func (*HealthCheckResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{86}
+// Deprecated: Use AutocompleteRecipientsResponse.ProtoReflect.Descriptor instead.
+func (*AutocompleteRecipientsResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{85}
 }
 
-func (x *HealthCheckResponse) GetStatus() string {
+func (x *AutocompleteRecipientsResponse) GetRecipients() []*AutocompleteRecipient {
 	if x != nil {
-		return x.Status
+		return x.Recipients
 	}
-	return ""
+	return nil
 }
 
-func (x *HealthCheckResponse) GetTime() *timestamppb.Timestamp {
+// DeleteMessageRequest is the request for DeleteMessage.
+type DeleteMessageRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AgentId   int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	MessageId int64                  `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// mark_sender_deleted marks the message as deleted from sender's perspective.
+	// Used when deleting from aggregate views like CodeReviewer.
+	MarkSenderDeleted bool `protobuf:"varint,3,opt,name=mark_sender_deleted,json=markSenderDeleted,proto3" json:"mark_sender_deleted,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *DeleteMessageRequest) Reset() {
+	*x = DeleteMessageRequest{}
+	mi := &file_mail_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMessageRequest) ProtoMessage() {}
+
+func (x *DeleteMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[86]
 	if x != nil {
-		return x.Time
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-// BranchTarget specifies a full branch diff review.
-type BranchTarget struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// branch is the feature branch to review.
-	Branch string `protobuf:"bytes,1,opt,name=branch,proto3" json:"branch,omitempty"`
-	// base_branch is the branch to diff against (e.g., "main").
-	BaseBranch    string `protobuf:"bytes,2,opt,name=base_branch,json=baseBranch,proto3" json:"base_branch,omitempty"`
+// Deprecated: Use DeleteMessageRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMessageRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *DeleteMessageRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *DeleteMessageRequest) GetMessageId() int64 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
+}
+
+func (x *DeleteMessageRequest) GetMarkSenderDeleted() bool {
+	if x != nil {
+		return x.MarkSenderDeleted
+	}
+	return false
+}
+
+// DeleteMessageResponse is the response for DeleteMessage.
+type DeleteMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BranchTarget) Reset() {
-	*x = BranchTarget{}
+func (x *DeleteMessageResponse) Reset() {
+	*x = DeleteMessageResponse{}
 	mi := &file_mail_proto_msgTypes[87]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BranchTarget) String() string {
+func (x *DeleteMessageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BranchTarget) ProtoMessage() {}
+func (*DeleteMessageResponse) ProtoMessage() {}
 
-func (x *BranchTarget) ProtoReflect() protoreflect.Message {
+func (x *DeleteMessageResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[87]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -5610,50 +6309,41 @@ func (x *BranchTarget) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BranchTarget.ProtoReflect.Descriptor instead.
-func (*BranchTarget) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteMessageResponse.ProtoReflect.Descriptor instead.
+func (*DeleteMessageResponse) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{87}
 }
 
-func (x *BranchTarget) GetBranch() string {
-	if x != nil {
-		return x.Branch
-	}
-	return ""
-}
-
-func (x *BranchTarget) GetBaseBranch() string {
+func (x *DeleteMessageResponse) GetSuccess() bool {
 	if x != nil {
-		return x.BaseBranch
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-// CommitTarget specifies a single commit review.
-type CommitTarget struct {
+// CollectGarbageRequest is the request for CollectGarbage.
+type CollectGarbageRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// sha is the commit SHA to review.
-	Sha string `protobuf:"bytes,1,opt,name=sha,proto3" json:"sha,omitempty"`
-	// branch is the branch name for display/naming purposes only.
-	Branch        string `protobuf:"bytes,2,opt,name=branch,proto3" json:"branch,omitempty"`
+	// dry_run reports what would be deleted without deleting anything.
+	DryRun        bool `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CommitTarget) Reset() {
-	*x = CommitTarget{}
+func (x *CollectGarbageRequest) Reset() {
+	*x = CollectGarbageRequest{}
 	mi := &file_mail_proto_msgTypes[88]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CommitTarget) String() string {
+func (x *CollectGarbageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CommitTarget) ProtoMessage() {}
+func (*CollectGarbageRequest) ProtoMessage() {}
 
-func (x *CommitTarget) ProtoReflect() protoreflect.Message {
+func (x *CollectGarbageRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[88]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -5665,52 +6355,47 @@ func (x *CommitTarget) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CommitTarget.ProtoReflect.Descriptor instead.
-func (*CommitTarget) Descriptor() ([]byte, []int) {
+// Deprecated: Use CollectGarbageRequest.ProtoReflect.Descriptor instead.
+func (*CollectGarbageRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{88}
 }
 
-func (x *CommitTarget) GetSha() string {
-	if x != nil {
-		return x.Sha
-	}
-	return ""
-}
-
-func (x *CommitTarget) GetBranch() string {
+func (x *CollectGarbageRequest) GetDryRun() bool {
 	if x != nil {
-		return x.Branch
+		return x.DryRun
 	}
-	return ""
+	return false
 }
 
-// CommitRangeTarget specifies a range of commits to review.
-type CommitRangeTarget struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// start_sha is the first commit in the range (exclusive).
-	StartSha string `protobuf:"bytes,1,opt,name=start_sha,json=startSha,proto3" json:"start_sha,omitempty"`
-	// end_sha is the last commit in the range (inclusive).
-	EndSha string `protobuf:"bytes,2,opt,name=end_sha,json=endSha,proto3" json:"end_sha,omitempty"`
-	// branch is the branch name for display/naming purposes only.
-	Branch        string `protobuf:"bytes,3,opt,name=branch,proto3" json:"branch,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// TopicRetentionReport summarizes the expired messages of a topic.
+type TopicRetentionReport struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	TopicId          int64                  `protobuf:"varint,1,opt,name=topic_id,json=topicId,proto3" json:"topic_id,omitempty"`
+	TopicName        string                 `protobuf:"bytes,2,opt,name=topic_name,json=topicName,proto3" json:"topic_name,omitempty"`
+	RetentionSeconds int64                  `protobuf:"varint,3,opt,name=retention_seconds,json=retentionSeconds,proto3" json:"retention_seconds,omitempty"`
+	// expired_count includes messages that are kept, such as unread,
+	// starred or held ones.
+	ExpiredCount   int64 `protobuf:"varint,4,opt,name=expired_count,json=expiredCount,proto3" json:"expired_count,omitempty"`
+	DeletableCount int64 `protobuf:"varint,5,opt,name=deletable_count,json=deletableCount,proto3" json:"deletable_count,omitempty"`
+	RecipientCount int64 `protobuf:"varint,6,opt,name=recipient_count,json=recipientCount,proto3" json:"recipient_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *CommitRangeTarget) Reset() {
-	*x = CommitRangeTarget{}
+func (x *TopicRetentionReport) Reset() {
+	*x = TopicRetentionReport{}
 	mi := &file_mail_proto_msgTypes[89]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CommitRangeTarget) String() string {
+func (x *TopicRetentionReport) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CommitRangeTarget) ProtoMessage() {}
+func (*TopicRetentionReport) ProtoMessage() {}
 
-func (x *CommitRangeTarget) ProtoReflect() protoreflect.Message {
+func (x *TopicRetentionReport) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[89]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -5722,59 +6407,79 @@ func (x *CommitRangeTarget) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CommitRangeTarget.ProtoReflect.Descriptor instead.
-func (*CommitRangeTarget) Descriptor() ([]byte, []int) {
+// Deprecated: Use TopicRetentionReport.ProtoReflect.Descriptor instead.
+func (*TopicRetentionReport) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{89}
 }
 
-func (x *CommitRangeTarget) GetStartSha() string {
+func (x *TopicRetentionReport) GetTopicId() int64 {
 	if x != nil {
-		return x.StartSha
+		return x.TopicId
 	}
-	return ""
+	return 0
 }
 
-func (x *CommitRangeTarget) GetEndSha() string {
+func (x *TopicRetentionReport) GetTopicName() string {
 	if x != nil {
-		return x.EndSha
+		return x.TopicName
 	}
 	return ""
 }
 
-func (x *CommitRangeTarget) GetBranch() string {
+func (x *TopicRetentionReport) GetRetentionSeconds() int64 {
 	if x != nil {
-		return x.Branch
+		return x.RetentionSeconds
 	}
-	return ""
+	return 0
 }
 
-// PRTarget specifies a pull request review.
-type PRTarget struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// number is the PR number.
-	Number int32 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
-	// branch is the PR's head branch.
-	Branch string `protobuf:"bytes,2,opt,name=branch,proto3" json:"branch,omitempty"`
-	// base_branch is the PR's base branch.
-	BaseBranch    string `protobuf:"bytes,3,opt,name=base_branch,json=baseBranch,proto3" json:"base_branch,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *TopicRetentionReport) GetExpiredCount() int64 {
+	if x != nil {
+		return x.ExpiredCount
+	}
+	return 0
 }
 
-func (x *PRTarget) Reset() {
-	*x = PRTarget{}
+func (x *TopicRetentionReport) GetDeletableCount() int64 {
+	if x != nil {
+		return x.DeletableCount
+	}
+	return 0
+}
+
+func (x *TopicRetentionReport) GetRecipientCount() int64 {
+	if x != nil {
+		return x.RecipientCount
+	}
+	return 0
+}
+
+// CollectGarbageResponse is the response for CollectGarbage.
+type CollectGarbageResponse struct {
+	state                  protoimpl.MessageState  `protogen:"open.v1"`
+	DryRun                 bool                    `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	Topics                 []*TopicRetentionReport `protobuf:"bytes,2,rep,name=topics,proto3" json:"topics,omitempty"`
+	MessagesDeleted        int64                   `protobuf:"varint,3,opt,name=messages_deleted,json=messagesDeleted,proto3" json:"messages_deleted,omitempty"`
+	RecipientsDeleted      int64                   `protobuf:"varint,4,opt,name=recipients_deleted,json=recipientsDeleted,proto3" json:"recipients_deleted,omitempty"`
+	ConsumerOffsetsDeleted int64                   `protobuf:"varint,5,opt,name=consumer_offsets_deleted,json=consumerOffsetsDeleted,proto3" json:"consumer_offsets_deleted,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *CollectGarbageResponse) Reset() {
+	*x = CollectGarbageResponse{}
 	mi := &file_mail_proto_msgTypes[90]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PRTarget) String() string {
+func (x *CollectGarbageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PRTarget) ProtoMessage() {}
+func (*CollectGarbageResponse) ProtoMessage() {}
 
-func (x *PRTarget) ProtoReflect() protoreflect.Message {
+func (x *CollectGarbageResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[90]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -5786,87 +6491,72 @@ func (x *PRTarget) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PRTarget.ProtoReflect.Descriptor instead.
-func (*PRTarget) Descriptor() ([]byte, []int) {
+// Deprecated: Use CollectGarbageResponse.ProtoReflect.Descriptor instead.
+func (*CollectGarbageResponse) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{90}
 }
 
-func (x *PRTarget) GetNumber() int32 {
+func (x *CollectGarbageResponse) GetDryRun() bool {
 	if x != nil {
-		return x.Number
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *CollectGarbageResponse) GetTopics() []*TopicRetentionReport {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+func (x *CollectGarbageResponse) GetMessagesDeleted() int64 {
+	if x != nil {
+		return x.MessagesDeleted
 	}
 	return 0
 }
 
-func (x *PRTarget) GetBranch() string {
+func (x *CollectGarbageResponse) GetRecipientsDeleted() int64 {
 	if x != nil {
-		return x.Branch
+		return x.RecipientsDeleted
 	}
-	return ""
+	return 0
 }
 
-func (x *PRTarget) GetBaseBranch() string {
+func (x *CollectGarbageResponse) GetConsumerOffsetsDeleted() int64 {
 	if x != nil {
-		return x.BaseBranch
+		return x.ConsumerOffsetsDeleted
 	}
-	return ""
+	return 0
 }
 
-// CreateReviewRequest is the request for CreateReview.
-type CreateReviewRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// repo_path is the local path to the repository.
-	RepoPath string `protobuf:"bytes,4,opt,name=repo_path,json=repoPath,proto3" json:"repo_path,omitempty"`
-	// remote_url is the git remote URL.
-	RemoteUrl string `protobuf:"bytes,11,opt,name=remote_url,json=remoteUrl,proto3" json:"remote_url,omitempty"`
-	// review_type is the type of review: full, security, performance, architecture.
-	ReviewType string `protobuf:"bytes,6,opt,name=review_type,json=reviewType,proto3" json:"review_type,omitempty"`
-	// priority is the review priority: urgent, normal, low.
-	Priority string `protobuf:"bytes,7,opt,name=priority,proto3" json:"priority,omitempty"`
-	// reviewers is optional list of specific reviewers to assign.
-	Reviewers []string `protobuf:"bytes,8,rep,name=reviewers,proto3" json:"reviewers,omitempty"`
-	// description is an optional description of what to review.
-	Description string `protobuf:"bytes,9,opt,name=description,proto3" json:"description,omitempty"`
-	// requester_id is the agent ID requesting the review.
-	RequesterId int64 `protobuf:"varint,10,opt,name=requester_id,json=requesterId,proto3" json:"requester_id,omitempty"`
-	// target specifies what to review. Exactly one must be set.
-	//
-	// Types that are valid to be assigned to Target:
-	//
-	//	*CreateReviewRequest_BranchTarget
-	//	*CreateReviewRequest_CommitTarget
-	//	*CreateReviewRequest_CommitRangeTarget
-	//	*CreateReviewRequest_PrTarget
-	Target isCreateReviewRequest_Target `protobuf_oneof:"target"`
-	// Deprecated fields kept for backward compatibility.
-	// Use target oneof instead.
-	//
-	// Deprecated: Marked as deprecated in mail.proto.
-	Branch string `protobuf:"bytes,1,opt,name=branch,proto3" json:"branch,omitempty"`
-	// Deprecated: Marked as deprecated in mail.proto.
-	BaseBranch string `protobuf:"bytes,2,opt,name=base_branch,json=baseBranch,proto3" json:"base_branch,omitempty"`
-	// Deprecated: Marked as deprecated in mail.proto.
-	CommitSha string `protobuf:"bytes,3,opt,name=commit_sha,json=commitSha,proto3" json:"commit_sha,omitempty"`
-	// Deprecated: Marked as deprecated in mail.proto.
-	PrNumber      int32 `protobuf:"varint,5,opt,name=pr_number,json=prNumber,proto3" json:"pr_number,omitempty"`
+// SetLegalHoldRequest is the request for SetLegalHold. Exactly one of
+// message_id and thread_id must be set.
+type SetLegalHoldRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	MessageId int64                  `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ThreadId  string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	// hold places the hold when true and lifts it when false.
+	Hold          bool `protobuf:"varint,3,opt,name=hold,proto3" json:"hold,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateReviewRequest) Reset() {
-	*x = CreateReviewRequest{}
+func (x *SetLegalHoldRequest) Reset() {
+	*x = SetLegalHoldRequest{}
 	mi := &file_mail_proto_msgTypes[91]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateReviewRequest) String() string {
+func (x *SetLegalHoldRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateReviewRequest) ProtoMessage() {}
+func (*SetLegalHoldRequest) ProtoMessage() {}
 
-func (x *CreateReviewRequest) ProtoReflect() protoreflect.Message {
+func (x *SetLegalHoldRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mail_proto_msgTypes[91]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -5878,193 +6568,206 @@ func (x *CreateReviewRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateReviewRequest.ProtoReflect.Descriptor instead.
-func (*CreateReviewRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetLegalHoldRequest.ProtoReflect.Descriptor instead.
+func (*SetLegalHoldRequest) Descriptor() ([]byte, []int) {
 	return file_mail_proto_rawDescGZIP(), []int{91}
 }
 
-func (x *CreateReviewRequest) GetRepoPath() string {
+func (x *SetLegalHoldRequest) GetMessageId() int64 {
 	if x != nil {
-		return x.RepoPath
+		return x.MessageId
 	}
-	return ""
+	return 0
 }
 
-func (x *CreateReviewRequest) GetRemoteUrl() string {
+func (x *SetLegalHoldRequest) GetThreadId() string {
 	if x != nil {
-		return x.RemoteUrl
+		return x.ThreadId
 	}
 	return ""
 }
 
-func (x *CreateReviewRequest) GetReviewType() string {
+func (x *SetLegalHoldRequest) GetHold() bool {
 	if x != nil {
-		return x.ReviewType
+		return x.Hold
 	}
-	return ""
+	return false
 }
 
-func (x *CreateReviewRequest) GetPriority() string {
-	if x != nil {
-		return x.Priority
-	}
-	return ""
+// SetLegalHoldResponse is the response for SetLegalHold.
+type SetLegalHoldResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MessagesUpdated int64                  `protobuf:"varint,1,opt,name=messages_updated,json=messagesUpdated,proto3" json:"messages_updated,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *CreateReviewRequest) GetReviewers() []string {
-	if x != nil {
-		return x.Reviewers
-	}
-	return nil
+func (x *SetLegalHoldResponse) Reset() {
+	*x = SetLegalHoldResponse{}
+	mi := &file_mail_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateReviewRequest) GetDescription() string {
-	if x != nil {
-		return x.Description
-	}
-	return ""
+func (x *SetLegalHoldResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *CreateReviewRequest) GetRequesterId() int64 {
+func (*SetLegalHoldResponse) ProtoMessage() {}
+
+func (x *SetLegalHoldResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[92]
 	if x != nil {
-		return x.RequesterId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *CreateReviewRequest) GetTarget() isCreateReviewRequest_Target {
-	if x != nil {
-		return x.Target
-	}
-	return nil
+// Deprecated: Use SetLegalHoldResponse.ProtoReflect.Descriptor instead.
+func (*SetLegalHoldResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{92}
 }
 
-func (x *CreateReviewRequest) GetBranchTarget() *BranchTarget {
+func (x *SetLegalHoldResponse) GetMessagesUpdated() int64 {
 	if x != nil {
-		if x, ok := x.Target.(*CreateReviewRequest_BranchTarget); ok {
-			return x.BranchTarget
-		}
+		return x.MessagesUpdated
 	}
-	return nil
+	return 0
 }
 
-func (x *CreateReviewRequest) GetCommitTarget() *CommitTarget {
-	if x != nil {
-		if x, ok := x.Target.(*CreateReviewRequest_CommitTarget); ok {
-			return x.CommitTarget
-		}
-	}
-	return nil
+// SetTopicRetentionRequest is the request for SetTopicRetention.
+type SetTopicRetentionRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	TopicName string                 `protobuf:"bytes,1,opt,name=topic_name,json=topicName,proto3" json:"topic_name,omitempty"`
+	// retention_seconds is how long the topic's messages are kept, or 0 to
+	// keep them forever.
+	RetentionSeconds int64 `protobuf:"varint,2,opt,name=retention_seconds,json=retentionSeconds,proto3" json:"retention_seconds,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *CreateReviewRequest) GetCommitRangeTarget() *CommitRangeTarget {
-	if x != nil {
-		if x, ok := x.Target.(*CreateReviewRequest_CommitRangeTarget); ok {
-			return x.CommitRangeTarget
-		}
-	}
-	return nil
+func (x *SetTopicRetentionRequest) Reset() {
+	*x = SetTopicRetentionRequest{}
+	mi := &file_mail_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateReviewRequest) GetPrTarget() *PRTarget {
-	if x != nil {
-		if x, ok := x.Target.(*CreateReviewRequest_PrTarget); ok {
-			return x.PrTarget
-		}
-	}
-	return nil
+func (x *SetTopicRetentionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-// Deprecated: Marked as deprecated in mail.proto.
-func (x *CreateReviewRequest) GetBranch() string {
+func (*SetTopicRetentionRequest) ProtoMessage() {}
+
+func (x *SetTopicRetentionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[93]
 	if x != nil {
-		return x.Branch
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-// Deprecated: Marked as deprecated in mail.proto.
-func (x *CreateReviewRequest) GetBaseBranch() string {
-	if x != nil {
-		return x.BaseBranch
-	}
-	return ""
+// Deprecated: Use SetTopicRetentionRequest.ProtoReflect.Descriptor instead.
+func (*SetTopicRetentionRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{93}
 }
 
-// Deprecated: Marked as deprecated in mail.proto.
-func (x *CreateReviewRequest) GetCommitSha() string {
+func (x *SetTopicRetentionRequest) GetTopicName() string {
 	if x != nil {
-		return x.CommitSha
+		return x.TopicName
 	}
 	return ""
 }
 
-// Deprecated: Marked as deprecated in mail.proto.
-func (x *CreateReviewRequest) GetPrNumber() int32 {
+func (x *SetTopicRetentionRequest) GetRetentionSeconds() int64 {
 	if x != nil {
-		return x.PrNumber
+		return x.RetentionSeconds
 	}
 	return 0
 }
 
-type isCreateReviewRequest_Target interface {
-	isCreateReviewRequest_Target()
+// SetTopicRetentionResponse is the response for SetTopicRetention.
+type SetTopicRetentionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Topic         *Topic                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-type CreateReviewRequest_BranchTarget struct {
-	// branch_target reviews a full branch diff against base.
-	BranchTarget *BranchTarget `protobuf:"bytes,20,opt,name=branch_target,json=branchTarget,proto3,oneof"`
+func (x *SetTopicRetentionResponse) Reset() {
+	*x = SetTopicRetentionResponse{}
+	mi := &file_mail_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-type CreateReviewRequest_CommitTarget struct {
-	// commit_target reviews a single commit.
-	CommitTarget *CommitTarget `protobuf:"bytes,21,opt,name=commit_target,json=commitTarget,proto3,oneof"`
+func (x *SetTopicRetentionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-type CreateReviewRequest_CommitRangeTarget struct {
-	// commit_range_target reviews a range of commits.
-	CommitRangeTarget *CommitRangeTarget `protobuf:"bytes,22,opt,name=commit_range_target,json=commitRangeTarget,proto3,oneof"`
-}
+func (*SetTopicRetentionResponse) ProtoMessage() {}
 
-type CreateReviewRequest_PrTarget struct {
-	// pr_target reviews a pull request.
-	PrTarget *PRTarget `protobuf:"bytes,23,opt,name=pr_target,json=prTarget,proto3,oneof"`
+func (x *SetTopicRetentionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (*CreateReviewRequest_BranchTarget) isCreateReviewRequest_Target() {}
-
-func (*CreateReviewRequest_CommitTarget) isCreateReviewRequest_Target() {}
-
-func (*CreateReviewRequest_CommitRangeTarget) isCreateReviewRequest_Target() {}
+// Deprecated: Use SetTopicRetentionResponse.ProtoReflect.Descriptor instead.
+func (*SetTopicRetentionResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{94}
+}
 
-func (*CreateReviewRequest_PrTarget) isCreateReviewRequest_Target() {}
+func (x *SetTopicRetentionResponse) GetTopic() *Topic {
+	if x != nil {
+		return x.Topic
+	}
+	return nil
+}
 
-// CreateReviewResponse is the response for CreateReview and ResubmitReview.
-type CreateReviewResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
-	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
-	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
-	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+// RecipientGroup is a named set of agents addressable as "@<name>".
+type RecipientGroup struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	MemberCount int64                  `protobuf:"varint,4,opt,name=member_count,json=memberCount,proto3" json:"member_count,omitempty"`
+	// members is only populated for single-group responses.
+	Members       []string               `protobuf:"bytes,5,rep,name=members,proto3" json:"members,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateReviewResponse) Reset() {
-	*x = CreateReviewResponse{}
-	mi := &file_mail_proto_msgTypes[92]
+func (x *RecipientGroup) Reset() {
+	*x = RecipientGroup{}
+	mi := &file_mail_proto_msgTypes[95]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateReviewResponse) String() string {
+func (x *RecipientGroup) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateReviewResponse) ProtoMessage() {}
+func (*RecipientGroup) ProtoMessage() {}
 
-func (x *CreateReviewResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[92]
+func (x *RecipientGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[95]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6075,65 +6778,79 @@ func (x *CreateReviewResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateReviewResponse.ProtoReflect.Descriptor instead.
-func (*CreateReviewResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{92}
+// Deprecated: Use RecipientGroup.ProtoReflect.Descriptor instead.
+func (*RecipientGroup) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{95}
 }
 
-func (x *CreateReviewResponse) GetReviewId() string {
+func (x *RecipientGroup) GetId() int64 {
 	if x != nil {
-		return x.ReviewId
+		return x.Id
 	}
-	return ""
+	return 0
 }
 
-func (x *CreateReviewResponse) GetThreadId() string {
+func (x *RecipientGroup) GetName() string {
 	if x != nil {
-		return x.ThreadId
+		return x.Name
 	}
 	return ""
 }
 
-func (x *CreateReviewResponse) GetState() string {
+func (x *RecipientGroup) GetDescription() string {
 	if x != nil {
-		return x.State
+		return x.Description
 	}
 	return ""
 }
 
-func (x *CreateReviewResponse) GetError() string {
+func (x *RecipientGroup) GetMemberCount() int64 {
 	if x != nil {
-		return x.Error
+		return x.MemberCount
 	}
-	return ""
+	return 0
 }
 
-// ListReviewsProtoRequest is the request for ListReviews.
-type ListReviewsProtoRequest struct {
+func (x *RecipientGroup) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *RecipientGroup) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// CreateRecipientGroupRequest is the request for CreateRecipientGroup.
+type CreateRecipientGroupRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`                                 // Optional state filter
-	RequesterId   int64                  `protobuf:"varint,2,opt,name=requester_id,json=requesterId,proto3" json:"requester_id,omitempty"` // Optional requester filter
-	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedBy     int64                  `protobuf:"varint,3,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	Members       []string               `protobuf:"bytes,4,rep,name=members,proto3" json:"members,omitempty"` // Agent names.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListReviewsProtoRequest) Reset() {
-	*x = ListReviewsProtoRequest{}
-	mi := &file_mail_proto_msgTypes[93]
+func (x *CreateRecipientGroupRequest) Reset() {
+	*x = CreateRecipientGroupRequest{}
+	mi := &file_mail_proto_msgTypes[96]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListReviewsProtoRequest) String() string {
+func (x *CreateRecipientGroupRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListReviewsProtoRequest) ProtoMessage() {}
+func (*CreateRecipientGroupRequest) ProtoMessage() {}
 
-func (x *ListReviewsProtoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[93]
+func (x *CreateRecipientGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[96]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6144,62 +6861,62 @@ func (x *ListReviewsProtoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListReviewsProtoRequest.ProtoReflect.Descriptor instead.
-func (*ListReviewsProtoRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{93}
+// Deprecated: Use CreateRecipientGroupRequest.ProtoReflect.Descriptor instead.
+func (*CreateRecipientGroupRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{96}
 }
 
-func (x *ListReviewsProtoRequest) GetState() string {
+func (x *CreateRecipientGroupRequest) GetName() string {
 	if x != nil {
-		return x.State
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ListReviewsProtoRequest) GetRequesterId() int64 {
+func (x *CreateRecipientGroupRequest) GetDescription() string {
 	if x != nil {
-		return x.RequesterId
+		return x.Description
 	}
-	return 0
+	return ""
 }
 
-func (x *ListReviewsProtoRequest) GetLimit() int32 {
+func (x *CreateRecipientGroupRequest) GetCreatedBy() int64 {
 	if x != nil {
-		return x.Limit
+		return x.CreatedBy
 	}
 	return 0
 }
 
-func (x *ListReviewsProtoRequest) GetOffset() int32 {
+func (x *CreateRecipientGroupRequest) GetMembers() []string {
 	if x != nil {
-		return x.Offset
+		return x.Members
 	}
-	return 0
+	return nil
 }
 
-// ListReviewsProtoResponse is the response for ListReviews.
-type ListReviewsProtoResponse struct {
+// GetRecipientGroupRequest is the request for GetRecipientGroup.
+type GetRecipientGroupRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Reviews       []*ReviewSummaryProto  `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListReviewsProtoResponse) Reset() {
-	*x = ListReviewsProtoResponse{}
-	mi := &file_mail_proto_msgTypes[94]
+func (x *GetRecipientGroupRequest) Reset() {
+	*x = GetRecipientGroupRequest{}
+	mi := &file_mail_proto_msgTypes[97]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListReviewsProtoResponse) String() string {
+func (x *GetRecipientGroupRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListReviewsProtoResponse) ProtoMessage() {}
+func (*GetRecipientGroupRequest) ProtoMessage() {}
 
-func (x *ListReviewsProtoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[94]
+func (x *GetRecipientGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[97]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6210,47 +6927,41 @@ func (x *ListReviewsProtoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListReviewsProtoResponse.ProtoReflect.Descriptor instead.
-func (*ListReviewsProtoResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{94}
+// Deprecated: Use GetRecipientGroupRequest.ProtoReflect.Descriptor instead.
+func (*GetRecipientGroupRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{97}
 }
 
-func (x *ListReviewsProtoResponse) GetReviews() []*ReviewSummaryProto {
+func (x *GetRecipientGroupRequest) GetName() string {
 	if x != nil {
-		return x.Reviews
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-// ReviewSummaryProto represents a review summary in proto format.
-type ReviewSummaryProto struct {
+// RecipientGroupResponse returns a single recipient group with its members.
+type RecipientGroupResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
-	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
-	RequesterId   int64                  `protobuf:"varint,3,opt,name=requester_id,json=requesterId,proto3" json:"requester_id,omitempty"`
-	Branch        string                 `protobuf:"bytes,4,opt,name=branch,proto3" json:"branch,omitempty"`
-	State         string                 `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
-	ReviewType    string                 `protobuf:"bytes,6,opt,name=review_type,json=reviewType,proto3" json:"review_type,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Group         *RecipientGroup        `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReviewSummaryProto) Reset() {
-	*x = ReviewSummaryProto{}
-	mi := &file_mail_proto_msgTypes[95]
+func (x *RecipientGroupResponse) Reset() {
+	*x = RecipientGroupResponse{}
+	mi := &file_mail_proto_msgTypes[98]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReviewSummaryProto) String() string {
+func (x *RecipientGroupResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReviewSummaryProto) ProtoMessage() {}
+func (*RecipientGroupResponse) ProtoMessage() {}
 
-func (x *ReviewSummaryProto) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[95]
+func (x *RecipientGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[98]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6261,83 +6972,78 @@ func (x *ReviewSummaryProto) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReviewSummaryProto.ProtoReflect.Descriptor instead.
-func (*ReviewSummaryProto) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{95}
+// Deprecated: Use RecipientGroupResponse.ProtoReflect.Descriptor instead.
+func (*RecipientGroupResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{98}
 }
 
-func (x *ReviewSummaryProto) GetReviewId() string {
+func (x *RecipientGroupResponse) GetGroup() *RecipientGroup {
 	if x != nil {
-		return x.ReviewId
+		return x.Group
 	}
-	return ""
+	return nil
 }
 
-func (x *ReviewSummaryProto) GetThreadId() string {
-	if x != nil {
-		return x.ThreadId
-	}
-	return ""
+// ListRecipientGroupsRequest is the request for ListRecipientGroups.
+type ListRecipientGroupsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReviewSummaryProto) GetRequesterId() int64 {
-	if x != nil {
-		return x.RequesterId
-	}
-	return 0
+func (x *ListRecipientGroupsRequest) Reset() {
+	*x = ListRecipientGroupsRequest{}
+	mi := &file_mail_proto_msgTypes[99]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReviewSummaryProto) GetBranch() string {
-	if x != nil {
-		return x.Branch
-	}
-	return ""
+func (x *ListRecipientGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *ReviewSummaryProto) GetState() string {
-	if x != nil {
-		return x.State
-	}
-	return ""
-}
+func (*ListRecipientGroupsRequest) ProtoMessage() {}
 
-func (x *ReviewSummaryProto) GetReviewType() string {
+func (x *ListRecipientGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[99]
 	if x != nil {
-		return x.ReviewType
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *ReviewSummaryProto) GetCreatedAt() int64 {
-	if x != nil {
-		return x.CreatedAt
-	}
-	return 0
+// Deprecated: Use ListRecipientGroupsRequest.ProtoReflect.Descriptor instead.
+func (*ListRecipientGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{99}
 }
 
-// GetReviewProtoRequest is the request for GetReview.
-type GetReviewProtoRequest struct {
+// ListRecipientGroupsResponse is the response for ListRecipientGroups.
+type ListRecipientGroupsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	Groups        []*RecipientGroup      `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetReviewProtoRequest) Reset() {
-	*x = GetReviewProtoRequest{}
-	mi := &file_mail_proto_msgTypes[96]
+func (x *ListRecipientGroupsResponse) Reset() {
+	*x = ListRecipientGroupsResponse{}
+	mi := &file_mail_proto_msgTypes[100]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetReviewProtoRequest) String() string {
+func (x *ListRecipientGroupsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetReviewProtoRequest) ProtoMessage() {}
+func (*ListRecipientGroupsResponse) ProtoMessage() {}
 
-func (x *GetReviewProtoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[96]
+func (x *ListRecipientGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[100]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6348,51 +7054,41 @@ func (x *GetReviewProtoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetReviewProtoRequest.ProtoReflect.Descriptor instead.
-func (*GetReviewProtoRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{96}
+// Deprecated: Use ListRecipientGroupsResponse.ProtoReflect.Descriptor instead.
+func (*ListRecipientGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{100}
 }
 
-func (x *GetReviewProtoRequest) GetReviewId() string {
+func (x *ListRecipientGroupsResponse) GetGroups() []*RecipientGroup {
 	if x != nil {
-		return x.ReviewId
+		return x.Groups
 	}
-	return ""
+	return nil
 }
 
-// ReviewDetailResponse is the response for GetReview.
-type ReviewDetailResponse struct {
-	state      protoimpl.MessageState `protogen:"open.v1"`
-	ReviewId   string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
-	ThreadId   string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
-	State      string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
-	Branch     string                 `protobuf:"bytes,4,opt,name=branch,proto3" json:"branch,omitempty"`
-	BaseBranch string                 `protobuf:"bytes,5,opt,name=base_branch,json=baseBranch,proto3" json:"base_branch,omitempty"`
-	ReviewType string                 `protobuf:"bytes,6,opt,name=review_type,json=reviewType,proto3" json:"review_type,omitempty"`
-	Iterations int32                  `protobuf:"varint,7,opt,name=iterations,proto3" json:"iterations,omitempty"`
-	OpenIssues int64                  `protobuf:"varint,8,opt,name=open_issues,json=openIssues,proto3" json:"open_issues,omitempty"`
-	Error      string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
-	// Iteration details for displaying review history.
-	IterationDetails []*ReviewIterationProto `protobuf:"bytes,10,rep,name=iteration_details,json=iterationDetails,proto3" json:"iteration_details,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+// DeleteRecipientGroupRequest is the request for DeleteRecipientGroup.
+type DeleteRecipientGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReviewDetailResponse) Reset() {
-	*x = ReviewDetailResponse{}
-	mi := &file_mail_proto_msgTypes[97]
+func (x *DeleteRecipientGroupRequest) Reset() {
+	*x = DeleteRecipientGroupRequest{}
+	mi := &file_mail_proto_msgTypes[101]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReviewDetailResponse) String() string {
+func (x *DeleteRecipientGroupRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReviewDetailResponse) ProtoMessage() {}
+func (*DeleteRecipientGroupRequest) ProtoMessage() {}
 
-func (x *ReviewDetailResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[97]
+func (x *DeleteRecipientGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[101]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6403,113 +7099,146 @@ func (x *ReviewDetailResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReviewDetailResponse.ProtoReflect.Descriptor instead.
-func (*ReviewDetailResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{97}
+// Deprecated: Use DeleteRecipientGroupRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRecipientGroupRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{101}
 }
 
-func (x *ReviewDetailResponse) GetReviewId() string {
+func (x *DeleteRecipientGroupRequest) GetName() string {
 	if x != nil {
-		return x.ReviewId
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ReviewDetailResponse) GetThreadId() string {
-	if x != nil {
-		return x.ThreadId
-	}
-	return ""
+// DeleteRecipientGroupResponse is the response for DeleteRecipientGroup.
+type DeleteRecipientGroupResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReviewDetailResponse) GetState() string {
-	if x != nil {
-		return x.State
-	}
-	return ""
+func (x *DeleteRecipientGroupResponse) Reset() {
+	*x = DeleteRecipientGroupResponse{}
+	mi := &file_mail_proto_msgTypes[102]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReviewDetailResponse) GetBranch() string {
-	if x != nil {
-		return x.Branch
-	}
-	return ""
+func (x *DeleteRecipientGroupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *ReviewDetailResponse) GetBaseBranch() string {
+func (*DeleteRecipientGroupResponse) ProtoMessage() {}
+
+func (x *DeleteRecipientGroupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[102]
 	if x != nil {
-		return x.BaseBranch
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *ReviewDetailResponse) GetReviewType() string {
-	if x != nil {
-		return x.ReviewType
-	}
-	return ""
+// Deprecated: Use DeleteRecipientGroupResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRecipientGroupResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{102}
 }
 
-func (x *ReviewDetailResponse) GetIterations() int32 {
+func (x *DeleteRecipientGroupResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Iterations
+		return x.Success
 	}
-	return 0
+	return false
 }
 
-func (x *ReviewDetailResponse) GetOpenIssues() int64 {
+// RecipientGroupMembersRequest adds or removes members of a recipient group.
+type RecipientGroupMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Members       []string               `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"` // Agent names.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecipientGroupMembersRequest) Reset() {
+	*x = RecipientGroupMembersRequest{}
+	mi := &file_mail_proto_msgTypes[103]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecipientGroupMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecipientGroupMembersRequest) ProtoMessage() {}
+
+func (x *RecipientGroupMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[103]
 	if x != nil {
-		return x.OpenIssues
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *ReviewDetailResponse) GetError() string {
+// Deprecated: Use RecipientGroupMembersRequest.ProtoReflect.Descriptor instead.
+func (*RecipientGroupMembersRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{103}
+}
+
+func (x *RecipientGroupMembersRequest) GetName() string {
 	if x != nil {
-		return x.Error
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ReviewDetailResponse) GetIterationDetails() []*ReviewIterationProto {
+func (x *RecipientGroupMembersRequest) GetMembers() []string {
 	if x != nil {
-		return x.IterationDetails
+		return x.Members
 	}
 	return nil
 }
 
-// ReviewIterationProto represents a single review iteration with results.
-type ReviewIterationProto struct {
+// DeadLetter is a recipient a message could not be delivered to.
+type DeadLetter struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	IterationNum  int32                  `protobuf:"varint,1,opt,name=iteration_num,json=iterationNum,proto3" json:"iteration_num,omitempty"`
-	ReviewerId    string                 `protobuf:"bytes,2,opt,name=reviewer_id,json=reviewerId,proto3" json:"reviewer_id,omitempty"`
-	Decision      string                 `protobuf:"bytes,3,opt,name=decision,proto3" json:"decision,omitempty"`
-	Summary       string                 `protobuf:"bytes,4,opt,name=summary,proto3" json:"summary,omitempty"`
-	FilesReviewed int32                  `protobuf:"varint,5,opt,name=files_reviewed,json=filesReviewed,proto3" json:"files_reviewed,omitempty"`
-	LinesAnalyzed int32                  `protobuf:"varint,6,opt,name=lines_analyzed,json=linesAnalyzed,proto3" json:"lines_analyzed,omitempty"`
-	DurationMs    int64                  `protobuf:"varint,7,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
-	CostUsd       float64                `protobuf:"fixed64,8,opt,name=cost_usd,json=costUsd,proto3" json:"cost_usd,omitempty"`
-	StartedAt     int64                  `protobuf:"varint,9,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
-	CompletedAt   int64                  `protobuf:"varint,10,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	MessageId     int64                  `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ThreadId      string                 `protobuf:"bytes,3,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	Subject       string                 `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
+	Recipient     string                 `protobuf:"bytes,5,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Status        RecipientStatus        `protobuf:"varint,6,opt,name=status,proto3,enum=subtraterpc.RecipientStatus" json:"status,omitempty"`
+	Reason        string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReviewIterationProto) Reset() {
-	*x = ReviewIterationProto{}
-	mi := &file_mail_proto_msgTypes[98]
+func (x *DeadLetter) Reset() {
+	*x = DeadLetter{}
+	mi := &file_mail_proto_msgTypes[104]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReviewIterationProto) String() string {
+func (x *DeadLetter) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReviewIterationProto) ProtoMessage() {}
+func (*DeadLetter) ProtoMessage() {}
 
-func (x *ReviewIterationProto) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[98]
+func (x *DeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[104]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6520,105 +7249,91 @@ func (x *ReviewIterationProto) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReviewIterationProto.ProtoReflect.Descriptor instead.
-func (*ReviewIterationProto) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{98}
+// Deprecated: Use DeadLetter.ProtoReflect.Descriptor instead.
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{104}
 }
 
-func (x *ReviewIterationProto) GetIterationNum() int32 {
+func (x *DeadLetter) GetId() int64 {
 	if x != nil {
-		return x.IterationNum
+		return x.Id
 	}
 	return 0
 }
 
-func (x *ReviewIterationProto) GetReviewerId() string {
+func (x *DeadLetter) GetMessageId() int64 {
 	if x != nil {
-		return x.ReviewerId
+		return x.MessageId
 	}
-	return ""
+	return 0
 }
 
-func (x *ReviewIterationProto) GetDecision() string {
+func (x *DeadLetter) GetThreadId() string {
 	if x != nil {
-		return x.Decision
+		return x.ThreadId
 	}
 	return ""
 }
 
-func (x *ReviewIterationProto) GetSummary() string {
+func (x *DeadLetter) GetSubject() string {
 	if x != nil {
-		return x.Summary
+		return x.Subject
 	}
 	return ""
 }
 
-func (x *ReviewIterationProto) GetFilesReviewed() int32 {
-	if x != nil {
-		return x.FilesReviewed
-	}
-	return 0
-}
-
-func (x *ReviewIterationProto) GetLinesAnalyzed() int32 {
-	if x != nil {
-		return x.LinesAnalyzed
-	}
-	return 0
-}
-
-func (x *ReviewIterationProto) GetDurationMs() int64 {
+func (x *DeadLetter) GetRecipient() string {
 	if x != nil {
-		return x.DurationMs
+		return x.Recipient
 	}
-	return 0
+	return ""
 }
 
-func (x *ReviewIterationProto) GetCostUsd() float64 {
+func (x *DeadLetter) GetStatus() RecipientStatus {
 	if x != nil {
-		return x.CostUsd
+		return x.Status
 	}
-	return 0
+	return RecipientStatus_RECIPIENT_STATUS_UNSPECIFIED
 }
 
-func (x *ReviewIterationProto) GetStartedAt() int64 {
+func (x *DeadLetter) GetReason() string {
 	if x != nil {
-		return x.StartedAt
+		return x.Reason
 	}
-	return 0
+	return ""
 }
 
-func (x *ReviewIterationProto) GetCompletedAt() int64 {
+func (x *DeadLetter) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.CompletedAt
+		return x.CreatedAt
 	}
-	return 0
+	return nil
 }
 
-// ResubmitReviewRequest is the request for ResubmitReview.
-type ResubmitReviewRequest struct {
+// ListDeadLettersRequest is the request for ListDeadLetters.
+type ListDeadLettersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
-	CommitSha     string                 `protobuf:"bytes,2,opt,name=commit_sha,json=commitSha,proto3" json:"commit_sha,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // The sender whose dead letters to list.
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ResubmitReviewRequest) Reset() {
-	*x = ResubmitReviewRequest{}
-	mi := &file_mail_proto_msgTypes[99]
+func (x *ListDeadLettersRequest) Reset() {
+	*x = ListDeadLettersRequest{}
+	mi := &file_mail_proto_msgTypes[105]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ResubmitReviewRequest) String() string {
+func (x *ListDeadLettersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResubmitReviewRequest) ProtoMessage() {}
+func (*ListDeadLettersRequest) ProtoMessage() {}
 
-func (x *ResubmitReviewRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[99]
+func (x *ListDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[105]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6629,49 +7344,48 @@ func (x *ResubmitReviewRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResubmitReviewRequest.ProtoReflect.Descriptor instead.
-func (*ResubmitReviewRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{99}
+// Deprecated: Use ListDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{105}
 }
 
-func (x *ResubmitReviewRequest) GetReviewId() string {
+func (x *ListDeadLettersRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.ReviewId
+		return x.AgentId
 	}
-	return ""
+	return 0
 }
 
-func (x *ResubmitReviewRequest) GetCommitSha() string {
+func (x *ListDeadLettersRequest) GetLimit() int32 {
 	if x != nil {
-		return x.CommitSha
+		return x.Limit
 	}
-	return ""
+	return 0
 }
 
-// CancelReviewProtoRequest is the request for CancelReview.
-type CancelReviewProtoRequest struct {
+// ListDeadLettersResponse is the response for ListDeadLetters.
+type ListDeadLettersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
-	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	DeadLetters   []*DeadLetter          `protobuf:"bytes,1,rep,name=dead_letters,json=deadLetters,proto3" json:"dead_letters,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CancelReviewProtoRequest) Reset() {
-	*x = CancelReviewProtoRequest{}
-	mi := &file_mail_proto_msgTypes[100]
+func (x *ListDeadLettersResponse) Reset() {
+	*x = ListDeadLettersResponse{}
+	mi := &file_mail_proto_msgTypes[106]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CancelReviewProtoRequest) String() string {
+func (x *ListDeadLettersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelReviewProtoRequest) ProtoMessage() {}
+func (*ListDeadLettersResponse) ProtoMessage() {}
 
-func (x *CancelReviewProtoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[100]
+func (x *ListDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[106]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6682,48 +7396,42 @@ func (x *CancelReviewProtoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelReviewProtoRequest.ProtoReflect.Descriptor instead.
-func (*CancelReviewProtoRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{100}
-}
-
-func (x *CancelReviewProtoRequest) GetReviewId() string {
-	if x != nil {
-		return x.ReviewId
-	}
-	return ""
+// Deprecated: Use ListDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{106}
 }
 
-func (x *CancelReviewProtoRequest) GetReason() string {
+func (x *ListDeadLettersResponse) GetDeadLetters() []*DeadLetter {
 	if x != nil {
-		return x.Reason
+		return x.DeadLetters
 	}
-	return ""
+	return nil
 }
 
-// CancelReviewProtoResponse is the response for CancelReview.
-type CancelReviewProtoResponse struct {
+// DeleteDeadLetterRequest is the request for DeleteDeadLetter.
+type DeleteDeadLetterRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Id            int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CancelReviewProtoResponse) Reset() {
-	*x = CancelReviewProtoResponse{}
-	mi := &file_mail_proto_msgTypes[101]
+func (x *DeleteDeadLetterRequest) Reset() {
+	*x = DeleteDeadLetterRequest{}
+	mi := &file_mail_proto_msgTypes[107]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CancelReviewProtoResponse) String() string {
+func (x *DeleteDeadLetterRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelReviewProtoResponse) ProtoMessage() {}
+func (*DeleteDeadLetterRequest) ProtoMessage() {}
 
-func (x *CancelReviewProtoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[101]
+func (x *DeleteDeadLetterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[107]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6734,41 +7442,48 @@ func (x *CancelReviewProtoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelReviewProtoResponse.ProtoReflect.Descriptor instead.
-func (*CancelReviewProtoResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{101}
+// Deprecated: Use DeleteDeadLetterRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDeadLetterRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{107}
 }
 
-func (x *CancelReviewProtoResponse) GetError() string {
+func (x *DeleteDeadLetterRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Error
+		return x.AgentId
 	}
-	return ""
+	return 0
 }
 
-// DeleteReviewProtoRequest is the request for DeleteReview.
-type DeleteReviewProtoRequest struct {
+func (x *DeleteDeadLetterRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// DeleteDeadLetterResponse is the response for DeleteDeadLetter.
+type DeleteDeadLetterResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteReviewProtoRequest) Reset() {
-	*x = DeleteReviewProtoRequest{}
-	mi := &file_mail_proto_msgTypes[102]
+func (x *DeleteDeadLetterResponse) Reset() {
+	*x = DeleteDeadLetterResponse{}
+	mi := &file_mail_proto_msgTypes[108]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteReviewProtoRequest) String() string {
+func (x *DeleteDeadLetterResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteReviewProtoRequest) ProtoMessage() {}
+func (*DeleteDeadLetterResponse) ProtoMessage() {}
 
-func (x *DeleteReviewProtoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[102]
+func (x *DeleteDeadLetterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[108]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6779,41 +7494,51 @@ func (x *DeleteReviewProtoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteReviewProtoRequest.ProtoReflect.Descriptor instead.
-func (*DeleteReviewProtoRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{102}
+// Deprecated: Use DeleteDeadLetterResponse.ProtoReflect.Descriptor instead.
+func (*DeleteDeadLetterResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{108}
 }
 
-func (x *DeleteReviewProtoRequest) GetReviewId() string {
+func (x *DeleteDeadLetterResponse) GetSuccess() bool {
 	if x != nil {
-		return x.ReviewId
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-// DeleteReviewProtoResponse is the response for DeleteReview.
-type DeleteReviewProtoResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// ScheduledMessage is a message queued to be sent at a later time.
+type ScheduledMessage struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	RecipientNames []string               `protobuf:"bytes,2,rep,name=recipient_names,json=recipientNames,proto3" json:"recipient_names,omitempty"`
+	TopicName      string                 `protobuf:"bytes,3,opt,name=topic_name,json=topicName,proto3" json:"topic_name,omitempty"`
+	ThreadId       string                 `protobuf:"bytes,4,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	Subject        string                 `protobuf:"bytes,5,opt,name=subject,proto3" json:"subject,omitempty"`
+	Body           string                 `protobuf:"bytes,6,opt,name=body,proto3" json:"body,omitempty"`
+	Priority       Priority               `protobuf:"varint,7,opt,name=priority,proto3,enum=subtraterpc.Priority" json:"priority,omitempty"`
+	SendAt         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"`
+	State          string                 `protobuf:"bytes,9,opt,name=state,proto3" json:"state,omitempty"`                           // "pending" or "failed".
+	LastError      string                 `protobuf:"bytes,10,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"` // Why delivery failed, if it did.
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *DeleteReviewProtoResponse) Reset() {
-	*x = DeleteReviewProtoResponse{}
-	mi := &file_mail_proto_msgTypes[103]
+func (x *ScheduledMessage) Reset() {
+	*x = ScheduledMessage{}
+	mi := &file_mail_proto_msgTypes[109]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteReviewProtoResponse) String() string {
+func (x *ScheduledMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteReviewProtoResponse) ProtoMessage() {}
+func (*ScheduledMessage) ProtoMessage() {}
 
-func (x *DeleteReviewProtoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[103]
+func (x *ScheduledMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[109]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6824,86 +7549,112 @@ func (x *DeleteReviewProtoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteReviewProtoResponse.ProtoReflect.Descriptor instead.
-func (*DeleteReviewProtoResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{103}
+// Deprecated: Use ScheduledMessage.ProtoReflect.Descriptor instead.
+func (*ScheduledMessage) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{109}
 }
 
-func (x *DeleteReviewProtoResponse) GetError() string {
+func (x *ScheduledMessage) GetId() int64 {
 	if x != nil {
-		return x.Error
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ScheduledMessage) GetRecipientNames() []string {
+	if x != nil {
+		return x.RecipientNames
+	}
+	return nil
+}
+
+func (x *ScheduledMessage) GetTopicName() string {
+	if x != nil {
+		return x.TopicName
 	}
 	return ""
 }
 
-// ListReviewIssuesRequest is the request for ListReviewIssues.
-type ListReviewIssuesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ScheduledMessage) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
 }
 
-func (x *ListReviewIssuesRequest) Reset() {
-	*x = ListReviewIssuesRequest{}
-	mi := &file_mail_proto_msgTypes[104]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ScheduledMessage) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
 }
 
-func (x *ListReviewIssuesRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ScheduledMessage) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
 }
 
-func (*ListReviewIssuesRequest) ProtoMessage() {}
+func (x *ScheduledMessage) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
 
-func (x *ListReviewIssuesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[104]
+func (x *ScheduledMessage) GetSendAt() *timestamppb.Timestamp {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.SendAt
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use ListReviewIssuesRequest.ProtoReflect.Descriptor instead.
-func (*ListReviewIssuesRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{104}
+func (x *ScheduledMessage) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
 }
 
-func (x *ListReviewIssuesRequest) GetReviewId() string {
+func (x *ScheduledMessage) GetLastError() string {
 	if x != nil {
-		return x.ReviewId
+		return x.LastError
 	}
 	return ""
 }
 
-// ListReviewIssuesResponse is the response for ListReviewIssues.
-type ListReviewIssuesResponse struct {
+func (x *ScheduledMessage) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// ListScheduledMessagesRequest is the request for ListScheduledMessages.
+type ListScheduledMessagesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Issues        []*ReviewIssueProto    `protobuf:"bytes,1,rep,name=issues,proto3" json:"issues,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // The sender whose scheduled messages to list.
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListReviewIssuesResponse) Reset() {
-	*x = ListReviewIssuesResponse{}
-	mi := &file_mail_proto_msgTypes[105]
+func (x *ListScheduledMessagesRequest) Reset() {
+	*x = ListScheduledMessagesRequest{}
+	mi := &file_mail_proto_msgTypes[110]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListReviewIssuesResponse) String() string {
+func (x *ListScheduledMessagesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListReviewIssuesResponse) ProtoMessage() {}
+func (*ListScheduledMessagesRequest) ProtoMessage() {}
 
-func (x *ListReviewIssuesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[105]
+func (x *ListScheduledMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[110]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6914,54 +7665,48 @@ func (x *ListReviewIssuesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListReviewIssuesResponse.ProtoReflect.Descriptor instead.
-func (*ListReviewIssuesResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{105}
+// Deprecated: Use ListScheduledMessagesRequest.ProtoReflect.Descriptor instead.
+func (*ListScheduledMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{110}
 }
 
-func (x *ListReviewIssuesResponse) GetIssues() []*ReviewIssueProto {
+func (x *ListScheduledMessagesRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Issues
+		return x.AgentId
 	}
-	return nil
+	return 0
 }
 
-// ReviewIssueProto represents a review issue in proto format.
-type ReviewIssueProto struct {
+func (x *ListScheduledMessagesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// ListScheduledMessagesResponse is the response for ListScheduledMessages.
+type ListScheduledMessagesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	ReviewId      string                 `protobuf:"bytes,2,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
-	IterationNum  int32                  `protobuf:"varint,3,opt,name=iteration_num,json=iterationNum,proto3" json:"iteration_num,omitempty"`
-	IssueType     string                 `protobuf:"bytes,4,opt,name=issue_type,json=issueType,proto3" json:"issue_type,omitempty"`
-	Severity      string                 `protobuf:"bytes,5,opt,name=severity,proto3" json:"severity,omitempty"`
-	FilePath      string                 `protobuf:"bytes,6,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
-	LineStart     int32                  `protobuf:"varint,7,opt,name=line_start,json=lineStart,proto3" json:"line_start,omitempty"`
-	LineEnd       int32                  `protobuf:"varint,8,opt,name=line_end,json=lineEnd,proto3" json:"line_end,omitempty"`
-	Title         string                 `protobuf:"bytes,9,opt,name=title,proto3" json:"title,omitempty"`
-	Description   string                 `protobuf:"bytes,10,opt,name=description,proto3" json:"description,omitempty"`
-	CodeSnippet   string                 `protobuf:"bytes,11,opt,name=code_snippet,json=codeSnippet,proto3" json:"code_snippet,omitempty"`
-	Suggestion    string                 `protobuf:"bytes,12,opt,name=suggestion,proto3" json:"suggestion,omitempty"`
-	ClaudeMdRef   string                 `protobuf:"bytes,13,opt,name=claude_md_ref,json=claudeMdRef,proto3" json:"claude_md_ref,omitempty"`
-	Status        string                 `protobuf:"bytes,14,opt,name=status,proto3" json:"status,omitempty"`
+	Messages      []*ScheduledMessage    `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReviewIssueProto) Reset() {
-	*x = ReviewIssueProto{}
-	mi := &file_mail_proto_msgTypes[106]
+func (x *ListScheduledMessagesResponse) Reset() {
+	*x = ListScheduledMessagesResponse{}
+	mi := &file_mail_proto_msgTypes[111]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReviewIssueProto) String() string {
+func (x *ListScheduledMessagesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReviewIssueProto) ProtoMessage() {}
+func (*ListScheduledMessagesResponse) ProtoMessage() {}
 
-func (x *ReviewIssueProto) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[106]
+func (x *ListScheduledMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[111]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -6972,193 +7717,328 @@ func (x *ReviewIssueProto) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReviewIssueProto.ProtoReflect.Descriptor instead.
-func (*ReviewIssueProto) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{106}
+// Deprecated: Use ListScheduledMessagesResponse.ProtoReflect.Descriptor instead.
+func (*ListScheduledMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{111}
 }
 
-func (x *ReviewIssueProto) GetId() int64 {
+func (x *ListScheduledMessagesResponse) GetMessages() []*ScheduledMessage {
 	if x != nil {
-		return x.Id
+		return x.Messages
 	}
-	return 0
+	return nil
 }
 
-func (x *ReviewIssueProto) GetReviewId() string {
+// CancelScheduledMessageRequest is the request for CancelScheduledMessage.
+type CancelScheduledMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Id            int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScheduledMessageRequest) Reset() {
+	*x = CancelScheduledMessageRequest{}
+	mi := &file_mail_proto_msgTypes[112]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduledMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduledMessageRequest) ProtoMessage() {}
+
+func (x *CancelScheduledMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[112]
 	if x != nil {
-		return x.ReviewId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *ReviewIssueProto) GetIterationNum() int32 {
+// Deprecated: Use CancelScheduledMessageRequest.ProtoReflect.Descriptor instead.
+func (*CancelScheduledMessageRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{112}
+}
+
+func (x *CancelScheduledMessageRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.IterationNum
+		return x.AgentId
 	}
 	return 0
 }
 
-func (x *ReviewIssueProto) GetIssueType() string {
+func (x *CancelScheduledMessageRequest) GetId() int64 {
 	if x != nil {
-		return x.IssueType
+		return x.Id
 	}
-	return ""
+	return 0
 }
 
-func (x *ReviewIssueProto) GetSeverity() string {
+// CancelScheduledMessageResponse is the response for CancelScheduledMessage.
+type CancelScheduledMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScheduledMessageResponse) Reset() {
+	*x = CancelScheduledMessageResponse{}
+	mi := &file_mail_proto_msgTypes[113]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduledMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduledMessageResponse) ProtoMessage() {}
+
+func (x *CancelScheduledMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[113]
 	if x != nil {
-		return x.Severity
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *ReviewIssueProto) GetFilePath() string {
+// Deprecated: Use CancelScheduledMessageResponse.ProtoReflect.Descriptor instead.
+func (*CancelScheduledMessageResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{113}
+}
+
+func (x *CancelScheduledMessageResponse) GetSuccess() bool {
 	if x != nil {
-		return x.FilePath
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-func (x *ReviewIssueProto) GetLineStart() int32 {
+// Schedule is a recurring message sent on a cron schedule.
+type Schedule struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	RecipientNames []string               `protobuf:"bytes,3,rep,name=recipient_names,json=recipientNames,proto3" json:"recipient_names,omitempty"`
+	TopicName      string                 `protobuf:"bytes,4,opt,name=topic_name,json=topicName,proto3" json:"topic_name,omitempty"`
+	Subject        string                 `protobuf:"bytes,5,opt,name=subject,proto3" json:"subject,omitempty"`
+	Body           string                 `protobuf:"bytes,6,opt,name=body,proto3" json:"body,omitempty"`
+	Priority       Priority               `protobuf:"varint,7,opt,name=priority,proto3,enum=subtraterpc.Priority" json:"priority,omitempty"`
+	CronExpr       string                 `protobuf:"bytes,8,opt,name=cron_expr,json=cronExpr,proto3" json:"cron_expr,omitempty"`
+	Timezone       string                 `protobuf:"bytes,9,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	CatchUp        string                 `protobuf:"bytes,10,opt,name=catch_up,json=catchUp,proto3" json:"catch_up,omitempty"` // "once" or "skip".
+	Paused         bool                   `protobuf:"varint,11,opt,name=paused,proto3" json:"paused,omitempty"`
+	NextRunAt      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=next_run_at,json=nextRunAt,proto3" json:"next_run_at,omitempty"`
+	LastRunAt      *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=last_run_at,json=lastRunAt,proto3" json:"last_run_at,omitempty"` // Unset if never run.
+	LastMessageId  int64                  `protobuf:"varint,14,opt,name=last_message_id,json=lastMessageId,proto3" json:"last_message_id,omitempty"`
+	LastError      string                 `protobuf:"bytes,15,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"` // Why the last run failed, if it did.
+	RunCount       int64                  `protobuf:"varint,16,opt,name=run_count,json=runCount,proto3" json:"run_count,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Schedule) Reset() {
+	*x = Schedule{}
+	mi := &file_mail_proto_msgTypes[114]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Schedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Schedule) ProtoMessage() {}
+
+func (x *Schedule) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[114]
 	if x != nil {
-		return x.LineStart
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *ReviewIssueProto) GetLineEnd() int32 {
+// Deprecated: Use Schedule.ProtoReflect.Descriptor instead.
+func (*Schedule) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{114}
+}
+
+func (x *Schedule) GetId() int64 {
 	if x != nil {
-		return x.LineEnd
+		return x.Id
 	}
 	return 0
 }
 
-func (x *ReviewIssueProto) GetTitle() string {
+func (x *Schedule) GetName() string {
 	if x != nil {
-		return x.Title
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ReviewIssueProto) GetDescription() string {
+func (x *Schedule) GetRecipientNames() []string {
 	if x != nil {
-		return x.Description
+		return x.RecipientNames
 	}
-	return ""
+	return nil
 }
 
-func (x *ReviewIssueProto) GetCodeSnippet() string {
+func (x *Schedule) GetTopicName() string {
 	if x != nil {
-		return x.CodeSnippet
+		return x.TopicName
 	}
 	return ""
 }
 
-func (x *ReviewIssueProto) GetSuggestion() string {
+func (x *Schedule) GetSubject() string {
 	if x != nil {
-		return x.Suggestion
+		return x.Subject
 	}
 	return ""
 }
 
-func (x *ReviewIssueProto) GetClaudeMdRef() string {
+func (x *Schedule) GetBody() string {
 	if x != nil {
-		return x.ClaudeMdRef
+		return x.Body
 	}
 	return ""
 }
 
-func (x *ReviewIssueProto) GetStatus() string {
+func (x *Schedule) GetPriority() Priority {
 	if x != nil {
-		return x.Status
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *Schedule) GetCronExpr() string {
+	if x != nil {
+		return x.CronExpr
 	}
 	return ""
 }
 
-// UpdateIssueStatusRequest is the request for UpdateIssueStatus.
-type UpdateIssueStatusRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
-	IssueId       int64                  `protobuf:"varint,2,opt,name=issue_id,json=issueId,proto3" json:"issue_id,omitempty"`
-	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // open, fixed, wont_fix, duplicate
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Schedule) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
 }
 
-func (x *UpdateIssueStatusRequest) Reset() {
-	*x = UpdateIssueStatusRequest{}
-	mi := &file_mail_proto_msgTypes[107]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Schedule) GetCatchUp() string {
+	if x != nil {
+		return x.CatchUp
+	}
+	return ""
 }
 
-func (x *UpdateIssueStatusRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Schedule) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
 }
 
-func (*UpdateIssueStatusRequest) ProtoMessage() {}
+func (x *Schedule) GetNextRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRunAt
+	}
+	return nil
+}
 
-func (x *UpdateIssueStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[107]
+func (x *Schedule) GetLastRunAt() *timestamppb.Timestamp {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.LastRunAt
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use UpdateIssueStatusRequest.ProtoReflect.Descriptor instead.
-func (*UpdateIssueStatusRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{107}
+func (x *Schedule) GetLastMessageId() int64 {
+	if x != nil {
+		return x.LastMessageId
+	}
+	return 0
 }
 
-func (x *UpdateIssueStatusRequest) GetReviewId() string {
+func (x *Schedule) GetLastError() string {
 	if x != nil {
-		return x.ReviewId
+		return x.LastError
 	}
 	return ""
 }
 
-func (x *UpdateIssueStatusRequest) GetIssueId() int64 {
+func (x *Schedule) GetRunCount() int64 {
 	if x != nil {
-		return x.IssueId
+		return x.RunCount
 	}
 	return 0
 }
 
-func (x *UpdateIssueStatusRequest) GetStatus() string {
+func (x *Schedule) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Status
+		return x.CreatedAt
 	}
-	return ""
+	return nil
 }
 
-// UpdateIssueStatusResponse is the response for UpdateIssueStatus.
-type UpdateIssueStatusResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+// CreateScheduleRequest is the request for CreateSchedule.
+type CreateScheduleRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AgentId int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // The owner, who the messages are sent from.
+	Name    string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Exactly one of recipient_names and topic_name must be set.
+	RecipientNames []string `protobuf:"bytes,3,rep,name=recipient_names,json=recipientNames,proto3" json:"recipient_names,omitempty"`
+	TopicName      string   `protobuf:"bytes,4,opt,name=topic_name,json=topicName,proto3" json:"topic_name,omitempty"`
+	Subject        string   `protobuf:"bytes,5,opt,name=subject,proto3" json:"subject,omitempty"`
+	Body           string   `protobuf:"bytes,6,opt,name=body,proto3" json:"body,omitempty"`
+	Priority       Priority `protobuf:"varint,7,opt,name=priority,proto3,enum=subtraterpc.Priority" json:"priority,omitempty"`
+	// A five-field cron expression, or @hourly, @daily, @weekly, @monthly
+	// or @yearly.
+	CronExpr string `protobuf:"bytes,8,opt,name=cron_expr,json=cronExpr,proto3" json:"cron_expr,omitempty"`
+	// IANA time zone the expression is evaluated in. Defaults to the
+	// daemon's local time zone.
+	Timezone string `protobuf:"bytes,9,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	// What to do with occurrences missed while the daemon was down: "once"
+	// (the default) or "skip".
+	CatchUp       string `protobuf:"bytes,10,opt,name=catch_up,json=catchUp,proto3" json:"catch_up,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateIssueStatusResponse) Reset() {
-	*x = UpdateIssueStatusResponse{}
-	mi := &file_mail_proto_msgTypes[108]
+func (x *CreateScheduleRequest) Reset() {
+	*x = CreateScheduleRequest{}
+	mi := &file_mail_proto_msgTypes[115]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateIssueStatusResponse) String() string {
+func (x *CreateScheduleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateIssueStatusResponse) ProtoMessage() {}
+func (*CreateScheduleRequest) ProtoMessage() {}
 
-func (x *UpdateIssueStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[108]
+func (x *CreateScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[115]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7169,41 +8049,104 @@ func (x *UpdateIssueStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateIssueStatusResponse.ProtoReflect.Descriptor instead.
-func (*UpdateIssueStatusResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{108}
+// Deprecated: Use CreateScheduleRequest.ProtoReflect.Descriptor instead.
+func (*CreateScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{115}
 }
 
-func (x *UpdateIssueStatusResponse) GetError() string {
+func (x *CreateScheduleRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Error
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *CreateScheduleRequest) GetName() string {
+	if x != nil {
+		return x.Name
 	}
 	return ""
 }
 
-// GetReviewDiffRequest is the request for GetReviewDiff.
-type GetReviewDiffRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *CreateScheduleRequest) GetRecipientNames() []string {
+	if x != nil {
+		return x.RecipientNames
+	}
+	return nil
 }
 
-func (x *GetReviewDiffRequest) Reset() {
-	*x = GetReviewDiffRequest{}
-	mi := &file_mail_proto_msgTypes[109]
+func (x *CreateScheduleRequest) GetTopicName() string {
+	if x != nil {
+		return x.TopicName
+	}
+	return ""
+}
+
+func (x *CreateScheduleRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *CreateScheduleRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *CreateScheduleRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+func (x *CreateScheduleRequest) GetCronExpr() string {
+	if x != nil {
+		return x.CronExpr
+	}
+	return ""
+}
+
+func (x *CreateScheduleRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *CreateScheduleRequest) GetCatchUp() string {
+	if x != nil {
+		return x.CatchUp
+	}
+	return ""
+}
+
+// ScheduleResponse is the response for schedule operations.
+type ScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schedule      *Schedule              `protobuf:"bytes,1,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleResponse) Reset() {
+	*x = ScheduleResponse{}
+	mi := &file_mail_proto_msgTypes[116]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetReviewDiffRequest) String() string {
+func (x *ScheduleResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetReviewDiffRequest) ProtoMessage() {}
+func (*ScheduleResponse) ProtoMessage() {}
 
-func (x *GetReviewDiffRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[109]
+func (x *ScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[116]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7214,45 +8157,41 @@ func (x *GetReviewDiffRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetReviewDiffRequest.ProtoReflect.Descriptor instead.
-func (*GetReviewDiffRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{109}
+// Deprecated: Use ScheduleResponse.ProtoReflect.Descriptor instead.
+func (*ScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{116}
 }
 
-func (x *GetReviewDiffRequest) GetReviewId() string {
+func (x *ScheduleResponse) GetSchedule() *Schedule {
 	if x != nil {
-		return x.ReviewId
+		return x.Schedule
 	}
-	return ""
+	return nil
 }
 
-// GetReviewDiffResponse is the response for GetReviewDiff.
-type GetReviewDiffResponse struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// The raw unified diff output from git diff.
-	Patch string `protobuf:"bytes,1,opt,name=patch,proto3" json:"patch,omitempty"`
-	// The git command that was run to produce the diff.
-	Command       string `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
-	Error         string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+// ListSchedulesRequest is the request for ListSchedules.
+type ListSchedulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetReviewDiffResponse) Reset() {
-	*x = GetReviewDiffResponse{}
-	mi := &file_mail_proto_msgTypes[110]
+func (x *ListSchedulesRequest) Reset() {
+	*x = ListSchedulesRequest{}
+	mi := &file_mail_proto_msgTypes[117]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetReviewDiffResponse) String() string {
+func (x *ListSchedulesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetReviewDiffResponse) ProtoMessage() {}
+func (*ListSchedulesRequest) ProtoMessage() {}
 
-func (x *GetReviewDiffResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[110]
+func (x *ListSchedulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[117]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7263,60 +8202,87 @@ func (x *GetReviewDiffResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetReviewDiffResponse.ProtoReflect.Descriptor instead.
-func (*GetReviewDiffResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{110}
+// Deprecated: Use ListSchedulesRequest.ProtoReflect.Descriptor instead.
+func (*ListSchedulesRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{117}
 }
 
-func (x *GetReviewDiffResponse) GetPatch() string {
+func (x *ListSchedulesRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Patch
+		return x.AgentId
 	}
-	return ""
+	return 0
 }
 
-func (x *GetReviewDiffResponse) GetCommand() string {
+// ListSchedulesResponse is the response for ListSchedules.
+type ListSchedulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schedules     []*Schedule            `protobuf:"bytes,1,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSchedulesResponse) Reset() {
+	*x = ListSchedulesResponse{}
+	mi := &file_mail_proto_msgTypes[118]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSchedulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSchedulesResponse) ProtoMessage() {}
+
+func (x *ListSchedulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[118]
 	if x != nil {
-		return x.Command
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *GetReviewDiffResponse) GetError() string {
+// Deprecated: Use ListSchedulesResponse.ProtoReflect.Descriptor instead.
+func (*ListSchedulesResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{118}
+}
+
+func (x *ListSchedulesResponse) GetSchedules() []*Schedule {
 	if x != nil {
-		return x.Error
+		return x.Schedules
 	}
-	return ""
+	return nil
 }
 
-// TaskListProto represents a registered task list.
-type TaskListProto struct {
+// DeleteScheduleRequest is the request for DeleteSchedule.
+type DeleteScheduleRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	ListId        string                 `protobuf:"bytes,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
-	AgentId       int64                  `protobuf:"varint,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	WatchPath     string                 `protobuf:"bytes,4,opt,name=watch_path,json=watchPath,proto3" json:"watch_path,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	LastSyncedAt  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_synced_at,json=lastSyncedAt,proto3" json:"last_synced_at,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TaskListProto) Reset() {
-	*x = TaskListProto{}
-	mi := &file_mail_proto_msgTypes[111]
+func (x *DeleteScheduleRequest) Reset() {
+	*x = DeleteScheduleRequest{}
+	mi := &file_mail_proto_msgTypes[119]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TaskListProto) String() string {
+func (x *DeleteScheduleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TaskListProto) ProtoMessage() {}
+func (*DeleteScheduleRequest) ProtoMessage() {}
 
-func (x *TaskListProto) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[111]
+func (x *DeleteScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[119]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7327,91 +8293,95 @@ func (x *TaskListProto) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TaskListProto.ProtoReflect.Descriptor instead.
-func (*TaskListProto) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{111}
+// Deprecated: Use DeleteScheduleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{119}
 }
 
-func (x *TaskListProto) GetId() int64 {
+func (x *DeleteScheduleRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Id
+		return x.AgentId
 	}
 	return 0
 }
 
-func (x *TaskListProto) GetListId() string {
+func (x *DeleteScheduleRequest) GetName() string {
 	if x != nil {
-		return x.ListId
+		return x.Name
 	}
 	return ""
 }
 
-func (x *TaskListProto) GetAgentId() int64 {
-	if x != nil {
-		return x.AgentId
-	}
-	return 0
+// DeleteScheduleResponse is the response for DeleteSchedule.
+type DeleteScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TaskListProto) GetWatchPath() string {
-	if x != nil {
-		return x.WatchPath
-	}
-	return ""
+func (x *DeleteScheduleResponse) Reset() {
+	*x = DeleteScheduleResponse{}
+	mi := &file_mail_proto_msgTypes[120]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *TaskListProto) GetCreatedAt() *timestamppb.Timestamp {
+func (x *DeleteScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteScheduleResponse) ProtoMessage() {}
+
+func (x *DeleteScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[120]
 	if x != nil {
-		return x.CreatedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *TaskListProto) GetLastSyncedAt() *timestamppb.Timestamp {
+// Deprecated: Use DeleteScheduleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{120}
+}
+
+func (x *DeleteScheduleResponse) GetSuccess() bool {
 	if x != nil {
-		return x.LastSyncedAt
+		return x.Success
 	}
-	return nil
+	return false
 }
 
-// TaskProto represents a Claude Code task.
-type TaskProto struct {
+// SetSchedulePausedRequest is the request for SetSchedulePaused.
+type SetSchedulePausedRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	AgentId       int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	ListId        string                 `protobuf:"bytes,3,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
-	ClaudeTaskId  string                 `protobuf:"bytes,4,opt,name=claude_task_id,json=claudeTaskId,proto3" json:"claude_task_id,omitempty"`
-	Subject       string                 `protobuf:"bytes,5,opt,name=subject,proto3" json:"subject,omitempty"`
-	Description   string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
-	ActiveForm    string                 `protobuf:"bytes,7,opt,name=active_form,json=activeForm,proto3" json:"active_form,omitempty"`
-	MetadataJson  string                 `protobuf:"bytes,8,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"`
-	Status        TaskStatus             `protobuf:"varint,9,opt,name=status,proto3,enum=subtraterpc.TaskStatus" json:"status,omitempty"`
-	Owner         string                 `protobuf:"bytes,10,opt,name=owner,proto3" json:"owner,omitempty"`
-	BlockedBy     []string               `protobuf:"bytes,11,rep,name=blocked_by,json=blockedBy,proto3" json:"blocked_by,omitempty"`
-	Blocks        []string               `protobuf:"bytes,12,rep,name=blocks,proto3" json:"blocks,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
-	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Paused        bool                   `protobuf:"varint,3,opt,name=paused,proto3" json:"paused,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TaskProto) Reset() {
-	*x = TaskProto{}
-	mi := &file_mail_proto_msgTypes[112]
+func (x *SetSchedulePausedRequest) Reset() {
+	*x = SetSchedulePausedRequest{}
+	mi := &file_mail_proto_msgTypes[121]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TaskProto) String() string {
+func (x *SetSchedulePausedRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TaskProto) ProtoMessage() {}
+func (*SetSchedulePausedRequest) ProtoMessage() {}
 
-func (x *TaskProto) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[112]
+func (x *SetSchedulePausedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[121]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7422,236 +8392,260 @@ func (x *TaskProto) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TaskProto.ProtoReflect.Descriptor instead.
-func (*TaskProto) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{112}
-}
-
-func (x *TaskProto) GetId() int64 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
+// Deprecated: Use SetSchedulePausedRequest.ProtoReflect.Descriptor instead.
+func (*SetSchedulePausedRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{121}
 }
 
-func (x *TaskProto) GetAgentId() int64 {
+func (x *SetSchedulePausedRequest) GetAgentId() int64 {
 	if x != nil {
 		return x.AgentId
 	}
 	return 0
 }
 
-func (x *TaskProto) GetListId() string {
+func (x *SetSchedulePausedRequest) GetName() string {
 	if x != nil {
-		return x.ListId
+		return x.Name
 	}
 	return ""
 }
 
-func (x *TaskProto) GetClaudeTaskId() string {
+func (x *SetSchedulePausedRequest) GetPaused() bool {
 	if x != nil {
-		return x.ClaudeTaskId
+		return x.Paused
 	}
-	return ""
+	return false
 }
 
-func (x *TaskProto) GetSubject() string {
-	if x != nil {
-		return x.Subject
-	}
-	return ""
+// Question is a structured question asked through mail.
+type Question struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	QuestionId     string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	MessageId      int64                  `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ThreadId       string                 `protobuf:"bytes,3,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	AskerId        int64                  `protobuf:"varint,4,opt,name=asker_id,json=askerId,proto3" json:"asker_id,omitempty"`
+	Question       string                 `protobuf:"bytes,5,opt,name=question,proto3" json:"question,omitempty"`
+	Options        []string               `protobuf:"bytes,6,rep,name=options,proto3" json:"options,omitempty"`
+	DefaultAnswer  string                 `protobuf:"bytes,7,opt,name=default_answer,json=defaultAnswer,proto3" json:"default_answer,omitempty"`
+	TimeoutSeconds int64                  `protobuf:"varint,8,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"` // Zero if the question never times out.
+	TimeoutAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=timeout_at,json=timeoutAt,proto3" json:"timeout_at,omitempty"`
+	Fallback       string                 `protobuf:"bytes,10,opt,name=fallback,proto3" json:"fallback,omitempty"`
+	EscalatedAt    *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=escalated_at,json=escalatedAt,proto3" json:"escalated_at,omitempty"` // Unset if not escalated.
+	// "pending", "answered", "defaulted" or "expired".
+	State string `protobuf:"bytes,12,opt,name=state,proto3" json:"state,omitempty"`
+	// The chosen option, or the reply text if it matched no option.
+	Answer string `protobuf:"bytes,13,opt,name=answer,proto3" json:"answer,omitempty"`
+	// The 1-based index of the answer among the options, or zero for a
+	// free-form answer.
+	OptionIndex     int32                  `protobuf:"varint,14,opt,name=option_index,json=optionIndex,proto3" json:"option_index,omitempty"`
+	AnsweredBy      int64                  `protobuf:"varint,15,opt,name=answered_by,json=answeredBy,proto3" json:"answered_by,omitempty"` // Zero if the question timed out.
+	AnsweredByName  string                 `protobuf:"bytes,16,opt,name=answered_by_name,json=answeredByName,proto3" json:"answered_by_name,omitempty"`
+	AnswerMessageId int64                  `protobuf:"varint,17,opt,name=answer_message_id,json=answerMessageId,proto3" json:"answer_message_id,omitempty"`
+	AnsweredAt      *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=answered_at,json=answeredAt,proto3" json:"answered_at,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *TaskProto) GetDescription() string {
-	if x != nil {
-		return x.Description
-	}
-	return ""
+func (x *Question) Reset() {
+	*x = Question{}
+	mi := &file_mail_proto_msgTypes[122]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *TaskProto) GetActiveForm() string {
-	if x != nil {
-		return x.ActiveForm
-	}
-	return ""
+func (x *Question) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *TaskProto) GetMetadataJson() string {
+func (*Question) ProtoMessage() {}
+
+func (x *Question) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[122]
 	if x != nil {
-		return x.MetadataJson
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Question.ProtoReflect.Descriptor instead.
+func (*Question) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{122}
+}
+
+func (x *Question) GetQuestionId() string {
+	if x != nil {
+		return x.QuestionId
 	}
 	return ""
 }
 
-func (x *TaskProto) GetStatus() TaskStatus {
+func (x *Question) GetMessageId() int64 {
 	if x != nil {
-		return x.Status
+		return x.MessageId
 	}
-	return TaskStatus_TASK_STATUS_UNSPECIFIED
+	return 0
 }
 
-func (x *TaskProto) GetOwner() string {
+func (x *Question) GetThreadId() string {
 	if x != nil {
-		return x.Owner
+		return x.ThreadId
 	}
 	return ""
 }
 
-func (x *TaskProto) GetBlockedBy() []string {
+func (x *Question) GetAskerId() int64 {
 	if x != nil {
-		return x.BlockedBy
+		return x.AskerId
 	}
-	return nil
+	return 0
 }
 
-func (x *TaskProto) GetBlocks() []string {
+func (x *Question) GetQuestion() string {
 	if x != nil {
-		return x.Blocks
+		return x.Question
 	}
-	return nil
+	return ""
 }
 
-func (x *TaskProto) GetCreatedAt() *timestamppb.Timestamp {
+func (x *Question) GetOptions() []string {
 	if x != nil {
-		return x.CreatedAt
+		return x.Options
 	}
 	return nil
 }
 
-func (x *TaskProto) GetUpdatedAt() *timestamppb.Timestamp {
+func (x *Question) GetDefaultAnswer() string {
 	if x != nil {
-		return x.UpdatedAt
+		return x.DefaultAnswer
 	}
-	return nil
+	return ""
 }
 
-func (x *TaskProto) GetStartedAt() *timestamppb.Timestamp {
+func (x *Question) GetTimeoutSeconds() int64 {
 	if x != nil {
-		return x.StartedAt
+		return x.TimeoutSeconds
 	}
-	return nil
+	return 0
 }
 
-func (x *TaskProto) GetCompletedAt() *timestamppb.Timestamp {
+func (x *Question) GetTimeoutAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.CompletedAt
+		return x.TimeoutAt
 	}
 	return nil
 }
 
-// TaskStatsProto contains aggregate task statistics.
-type TaskStatsProto struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	PendingCount    int64                  `protobuf:"varint,1,opt,name=pending_count,json=pendingCount,proto3" json:"pending_count,omitempty"`
-	InProgressCount int64                  `protobuf:"varint,2,opt,name=in_progress_count,json=inProgressCount,proto3" json:"in_progress_count,omitempty"`
-	CompletedCount  int64                  `protobuf:"varint,3,opt,name=completed_count,json=completedCount,proto3" json:"completed_count,omitempty"`
-	BlockedCount    int64                  `protobuf:"varint,4,opt,name=blocked_count,json=blockedCount,proto3" json:"blocked_count,omitempty"`
-	AvailableCount  int64                  `protobuf:"varint,5,opt,name=available_count,json=availableCount,proto3" json:"available_count,omitempty"`
-	CompletedToday  int64                  `protobuf:"varint,6,opt,name=completed_today,json=completedToday,proto3" json:"completed_today,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
-}
-
-func (x *TaskStatsProto) Reset() {
-	*x = TaskStatsProto{}
-	mi := &file_mail_proto_msgTypes[113]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Question) GetFallback() string {
+	if x != nil {
+		return x.Fallback
+	}
+	return ""
 }
 
-func (x *TaskStatsProto) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Question) GetEscalatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EscalatedAt
+	}
+	return nil
 }
 
-func (*TaskStatsProto) ProtoMessage() {}
-
-func (x *TaskStatsProto) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[113]
+func (x *Question) GetState() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.State
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use TaskStatsProto.ProtoReflect.Descriptor instead.
-func (*TaskStatsProto) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{113}
+func (x *Question) GetAnswer() string {
+	if x != nil {
+		return x.Answer
+	}
+	return ""
 }
 
-func (x *TaskStatsProto) GetPendingCount() int64 {
+func (x *Question) GetOptionIndex() int32 {
 	if x != nil {
-		return x.PendingCount
+		return x.OptionIndex
 	}
 	return 0
 }
 
-func (x *TaskStatsProto) GetInProgressCount() int64 {
+func (x *Question) GetAnsweredBy() int64 {
 	if x != nil {
-		return x.InProgressCount
+		return x.AnsweredBy
 	}
 	return 0
 }
 
-func (x *TaskStatsProto) GetCompletedCount() int64 {
+func (x *Question) GetAnsweredByName() string {
 	if x != nil {
-		return x.CompletedCount
+		return x.AnsweredByName
 	}
-	return 0
+	return ""
 }
 
-func (x *TaskStatsProto) GetBlockedCount() int64 {
+func (x *Question) GetAnswerMessageId() int64 {
 	if x != nil {
-		return x.BlockedCount
+		return x.AnswerMessageId
 	}
 	return 0
 }
 
-func (x *TaskStatsProto) GetAvailableCount() int64 {
+func (x *Question) GetAnsweredAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.AvailableCount
+		return x.AnsweredAt
 	}
-	return 0
+	return nil
 }
 
-func (x *TaskStatsProto) GetCompletedToday() int64 {
+func (x *Question) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.CompletedToday
+		return x.CreatedAt
 	}
-	return 0
+	return nil
 }
 
-// AgentTaskStatsProto contains task statistics for a specific agent.
-type AgentTaskStatsProto struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	AgentId         int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	AgentName       string                 `protobuf:"bytes,2,opt,name=agent_name,json=agentName,proto3" json:"agent_name,omitempty"`
-	PendingCount    int64                  `protobuf:"varint,3,opt,name=pending_count,json=pendingCount,proto3" json:"pending_count,omitempty"`
-	InProgressCount int64                  `protobuf:"varint,4,opt,name=in_progress_count,json=inProgressCount,proto3" json:"in_progress_count,omitempty"`
-	BlockedCount    int64                  `protobuf:"varint,5,opt,name=blocked_count,json=blockedCount,proto3" json:"blocked_count,omitempty"`
-	CompletedToday  int64                  `protobuf:"varint,6,opt,name=completed_today,json=completedToday,proto3" json:"completed_today,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+// AskQuestionRequest is the request for AskQuestion.
+type AskQuestionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AgentId        int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // The asker.
+	RecipientNames []string               `protobuf:"bytes,2,rep,name=recipient_names,json=recipientNames,proto3" json:"recipient_names,omitempty"`
+	Question       string                 `protobuf:"bytes,3,opt,name=question,proto3" json:"question,omitempty"`
+	Details        string                 `protobuf:"bytes,4,opt,name=details,proto3" json:"details,omitempty"` // Optional markdown context.
+	Options        []string               `protobuf:"bytes,5,rep,name=options,proto3" json:"options,omitempty"`
+	// Used if the question times out. Must be one of the options, if any.
+	DefaultAnswer string `protobuf:"bytes,6,opt,name=default_answer,json=defaultAnswer,proto3" json:"default_answer,omitempty"`
+	// How long the question waits for an answer, and again after it is
+	// escalated. Zero means it never times out.
+	TimeoutSeconds int64 `protobuf:"varint,7,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"`
+	// The agent the question is escalated to once it times out.
+	Fallback      string   `protobuf:"bytes,8,opt,name=fallback,proto3" json:"fallback,omitempty"`
+	Priority      Priority `protobuf:"varint,9,opt,name=priority,proto3,enum=subtraterpc.Priority" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AgentTaskStatsProto) Reset() {
-	*x = AgentTaskStatsProto{}
-	mi := &file_mail_proto_msgTypes[114]
+func (x *AskQuestionRequest) Reset() {
+	*x = AskQuestionRequest{}
+	mi := &file_mail_proto_msgTypes[123]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AgentTaskStatsProto) String() string {
+func (x *AskQuestionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AgentTaskStatsProto) ProtoMessage() {}
+func (*AskQuestionRequest) ProtoMessage() {}
 
-func (x *AgentTaskStatsProto) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[114]
+func (x *AskQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[123]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7662,78 +8656,97 @@ func (x *AgentTaskStatsProto) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AgentTaskStatsProto.ProtoReflect.Descriptor instead.
-func (*AgentTaskStatsProto) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{114}
+// Deprecated: Use AskQuestionRequest.ProtoReflect.Descriptor instead.
+func (*AskQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{123}
 }
 
-func (x *AgentTaskStatsProto) GetAgentId() int64 {
+func (x *AskQuestionRequest) GetAgentId() int64 {
 	if x != nil {
 		return x.AgentId
 	}
 	return 0
 }
 
-func (x *AgentTaskStatsProto) GetAgentName() string {
+func (x *AskQuestionRequest) GetRecipientNames() []string {
 	if x != nil {
-		return x.AgentName
+		return x.RecipientNames
+	}
+	return nil
+}
+
+func (x *AskQuestionRequest) GetQuestion() string {
+	if x != nil {
+		return x.Question
 	}
 	return ""
 }
 
-func (x *AgentTaskStatsProto) GetPendingCount() int64 {
+func (x *AskQuestionRequest) GetDetails() string {
 	if x != nil {
-		return x.PendingCount
+		return x.Details
 	}
-	return 0
+	return ""
 }
 
-func (x *AgentTaskStatsProto) GetInProgressCount() int64 {
+func (x *AskQuestionRequest) GetOptions() []string {
 	if x != nil {
-		return x.InProgressCount
+		return x.Options
 	}
-	return 0
+	return nil
 }
 
-func (x *AgentTaskStatsProto) GetBlockedCount() int64 {
+func (x *AskQuestionRequest) GetDefaultAnswer() string {
 	if x != nil {
-		return x.BlockedCount
+		return x.DefaultAnswer
 	}
-	return 0
+	return ""
 }
 
-func (x *AgentTaskStatsProto) GetCompletedToday() int64 {
+func (x *AskQuestionRequest) GetTimeoutSeconds() int64 {
 	if x != nil {
-		return x.CompletedToday
+		return x.TimeoutSeconds
 	}
 	return 0
 }
 
-// RegisterTaskListRequest is the request for RegisterTaskList.
-type RegisterTaskListRequest struct {
+func (x *AskQuestionRequest) GetFallback() string {
+	if x != nil {
+		return x.Fallback
+	}
+	return ""
+}
+
+func (x *AskQuestionRequest) GetPriority() Priority {
+	if x != nil {
+		return x.Priority
+	}
+	return Priority_PRIORITY_UNSPECIFIED
+}
+
+// GetQuestionRequest is the request for GetQuestion.
+type GetQuestionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
-	AgentId       int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	WatchPath     string                 `protobuf:"bytes,3,opt,name=watch_path,json=watchPath,proto3" json:"watch_path,omitempty"`
+	QuestionId    string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterTaskListRequest) Reset() {
-	*x = RegisterTaskListRequest{}
-	mi := &file_mail_proto_msgTypes[115]
+func (x *GetQuestionRequest) Reset() {
+	*x = GetQuestionRequest{}
+	mi := &file_mail_proto_msgTypes[124]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterTaskListRequest) String() string {
+func (x *GetQuestionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterTaskListRequest) ProtoMessage() {}
+func (*GetQuestionRequest) ProtoMessage() {}
 
-func (x *RegisterTaskListRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[115]
+func (x *GetQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[124]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7744,56 +8757,43 @@ func (x *RegisterTaskListRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterTaskListRequest.ProtoReflect.Descriptor instead.
-func (*RegisterTaskListRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{115}
-}
-
-func (x *RegisterTaskListRequest) GetListId() string {
-	if x != nil {
-		return x.ListId
-	}
-	return ""
-}
-
-func (x *RegisterTaskListRequest) GetAgentId() int64 {
-	if x != nil {
-		return x.AgentId
-	}
-	return 0
+// Deprecated: Use GetQuestionRequest.ProtoReflect.Descriptor instead.
+func (*GetQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{124}
 }
 
-func (x *RegisterTaskListRequest) GetWatchPath() string {
+func (x *GetQuestionRequest) GetQuestionId() string {
 	if x != nil {
-		return x.WatchPath
+		return x.QuestionId
 	}
 	return ""
 }
 
-// RegisterTaskListResponse is the response for RegisterTaskList.
-type RegisterTaskListResponse struct {
+// AnswerQuestionRequest is the request for AnswerQuestion.
+type AnswerQuestionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TaskList      *TaskListProto         `protobuf:"bytes,1,opt,name=task_list,json=taskList,proto3" json:"task_list,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	QuestionId    string                 `protobuf:"bytes,1,opt,name=question_id,json=questionId,proto3" json:"question_id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // The agent answering.
+	Answer        string                 `protobuf:"bytes,3,opt,name=answer,proto3" json:"answer,omitempty"`                   // An option's text or number, or free text.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterTaskListResponse) Reset() {
-	*x = RegisterTaskListResponse{}
-	mi := &file_mail_proto_msgTypes[116]
+func (x *AnswerQuestionRequest) Reset() {
+	*x = AnswerQuestionRequest{}
+	mi := &file_mail_proto_msgTypes[125]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterTaskListResponse) String() string {
+func (x *AnswerQuestionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterTaskListResponse) ProtoMessage() {}
+func (*AnswerQuestionRequest) ProtoMessage() {}
 
-func (x *RegisterTaskListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[116]
+func (x *AnswerQuestionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[125]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7804,48 +8804,55 @@ func (x *RegisterTaskListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterTaskListResponse.ProtoReflect.Descriptor instead.
-func (*RegisterTaskListResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{116}
+// Deprecated: Use AnswerQuestionRequest.ProtoReflect.Descriptor instead.
+func (*AnswerQuestionRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{125}
 }
 
-func (x *RegisterTaskListResponse) GetTaskList() *TaskListProto {
+func (x *AnswerQuestionRequest) GetQuestionId() string {
 	if x != nil {
-		return x.TaskList
+		return x.QuestionId
 	}
-	return nil
+	return ""
 }
 
-func (x *RegisterTaskListResponse) GetError() string {
+func (x *AnswerQuestionRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Error
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *AnswerQuestionRequest) GetAnswer() string {
+	if x != nil {
+		return x.Answer
 	}
 	return ""
 }
 
-// GetTaskListRequest is the request for GetTaskList.
-type GetTaskListRequest struct {
+// QuestionResponse is the response for question operations.
+type QuestionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	Question      *Question              `protobuf:"bytes,1,opt,name=question,proto3" json:"question,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTaskListRequest) Reset() {
-	*x = GetTaskListRequest{}
-	mi := &file_mail_proto_msgTypes[117]
+func (x *QuestionResponse) Reset() {
+	*x = QuestionResponse{}
+	mi := &file_mail_proto_msgTypes[126]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTaskListRequest) String() string {
+func (x *QuestionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTaskListRequest) ProtoMessage() {}
+func (*QuestionResponse) ProtoMessage() {}
 
-func (x *GetTaskListRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[117]
+func (x *QuestionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[126]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7856,42 +8863,49 @@ func (x *GetTaskListRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTaskListRequest.ProtoReflect.Descriptor instead.
-func (*GetTaskListRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{117}
+// Deprecated: Use QuestionResponse.ProtoReflect.Descriptor instead.
+func (*QuestionResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{126}
 }
 
-func (x *GetTaskListRequest) GetListId() string {
+func (x *QuestionResponse) GetQuestion() *Question {
 	if x != nil {
-		return x.ListId
+		return x.Question
 	}
-	return ""
+	return nil
 }
 
-// GetTaskListResponse is the response for GetTaskList.
-type GetTaskListResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TaskList      *TaskListProto         `protobuf:"bytes,1,opt,name=task_list,json=taskList,proto3" json:"task_list,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+// MessageRevision is a version of a message.
+type MessageRevision struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	MessageId int64                  `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// Versions are numbered from 1, the message as originally sent.
+	Revision int32  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	Subject  string `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	Body     string `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	// When this version was sent or edited in, and when an edit replaced
+	// it. replaced_at is unset for the current version.
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ReplacedAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=replaced_at,json=replacedAt,proto3" json:"replaced_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTaskListResponse) Reset() {
-	*x = GetTaskListResponse{}
-	mi := &file_mail_proto_msgTypes[118]
+func (x *MessageRevision) Reset() {
+	*x = MessageRevision{}
+	mi := &file_mail_proto_msgTypes[127]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTaskListResponse) String() string {
+func (x *MessageRevision) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTaskListResponse) ProtoMessage() {}
+func (*MessageRevision) ProtoMessage() {}
 
-func (x *GetTaskListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[118]
+func (x *MessageRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[127]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7902,48 +8916,79 @@ func (x *GetTaskListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTaskListResponse.ProtoReflect.Descriptor instead.
-func (*GetTaskListResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{118}
+// Deprecated: Use MessageRevision.ProtoReflect.Descriptor instead.
+func (*MessageRevision) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{127}
 }
 
-func (x *GetTaskListResponse) GetTaskList() *TaskListProto {
+func (x *MessageRevision) GetMessageId() int64 {
 	if x != nil {
-		return x.TaskList
+		return x.MessageId
 	}
-	return nil
+	return 0
 }
 
-func (x *GetTaskListResponse) GetError() string {
+func (x *MessageRevision) GetRevision() int32 {
 	if x != nil {
-		return x.Error
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *MessageRevision) GetSubject() string {
+	if x != nil {
+		return x.Subject
 	}
 	return ""
 }
 
-// ListTaskListsRequest is the request for ListTaskLists.
-type ListTaskListsRequest struct {
+func (x *MessageRevision) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *MessageRevision) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *MessageRevision) GetReplacedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReplacedAt
+	}
+	return nil
+}
+
+// EditMessageRequest is the request for EditMessage.
+type EditMessageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Optional filter by agent
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // The sender of the message.
+	MessageId     int64                  `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Subject       string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"` // Empty keeps the current subject.
+	Body          string                 `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`       // Empty keeps the current body.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListTaskListsRequest) Reset() {
-	*x = ListTaskListsRequest{}
-	mi := &file_mail_proto_msgTypes[119]
+func (x *EditMessageRequest) Reset() {
+	*x = EditMessageRequest{}
+	mi := &file_mail_proto_msgTypes[128]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListTaskListsRequest) String() string {
+func (x *EditMessageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListTaskListsRequest) ProtoMessage() {}
+func (*EditMessageRequest) ProtoMessage() {}
 
-func (x *ListTaskListsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[119]
+func (x *EditMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[128]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -7954,42 +8999,64 @@ func (x *ListTaskListsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListTaskListsRequest.ProtoReflect.Descriptor instead.
-func (*ListTaskListsRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{119}
+// Deprecated: Use EditMessageRequest.ProtoReflect.Descriptor instead.
+func (*EditMessageRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{128}
 }
 
-func (x *ListTaskListsRequest) GetAgentId() int64 {
+func (x *EditMessageRequest) GetAgentId() int64 {
 	if x != nil {
 		return x.AgentId
 	}
 	return 0
 }
 
-// ListTaskListsResponse is the response for ListTaskLists.
-type ListTaskListsResponse struct {
+func (x *EditMessageRequest) GetMessageId() int64 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
+}
+
+func (x *EditMessageRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *EditMessageRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+// EditMessageResponse is the response for EditMessage.
+type EditMessageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TaskLists     []*TaskListProto       `protobuf:"bytes,1,rep,name=task_lists,json=taskLists,proto3" json:"task_lists,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	MessageId     int64                  `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Revision      int32                  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"` // The revision number of the edited version.
+	EditedAt      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=edited_at,json=editedAt,proto3" json:"edited_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListTaskListsResponse) Reset() {
-	*x = ListTaskListsResponse{}
-	mi := &file_mail_proto_msgTypes[120]
+func (x *EditMessageResponse) Reset() {
+	*x = EditMessageResponse{}
+	mi := &file_mail_proto_msgTypes[129]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListTaskListsResponse) String() string {
+func (x *EditMessageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListTaskListsResponse) ProtoMessage() {}
+func (*EditMessageResponse) ProtoMessage() {}
 
-func (x *ListTaskListsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[120]
+func (x *EditMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[129]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8000,48 +9067,56 @@ func (x *ListTaskListsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListTaskListsResponse.ProtoReflect.Descriptor instead.
-func (*ListTaskListsResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{120}
+// Deprecated: Use EditMessageResponse.ProtoReflect.Descriptor instead.
+func (*EditMessageResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{129}
 }
 
-func (x *ListTaskListsResponse) GetTaskLists() []*TaskListProto {
+func (x *EditMessageResponse) GetMessageId() int64 {
 	if x != nil {
-		return x.TaskLists
+		return x.MessageId
 	}
-	return nil
+	return 0
 }
 
-func (x *ListTaskListsResponse) GetError() string {
+func (x *EditMessageResponse) GetRevision() int32 {
 	if x != nil {
-		return x.Error
+		return x.Revision
 	}
-	return ""
+	return 0
 }
 
-// UnregisterTaskListRequest is the request for UnregisterTaskList.
-type UnregisterTaskListRequest struct {
+func (x *EditMessageResponse) GetEditedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EditedAt
+	}
+	return nil
+}
+
+// RecallMessageRequest is the request for RecallMessage.
+type RecallMessageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // The sender of the message.
+	MessageId     int64                  `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UnregisterTaskListRequest) Reset() {
-	*x = UnregisterTaskListRequest{}
-	mi := &file_mail_proto_msgTypes[121]
+func (x *RecallMessageRequest) Reset() {
+	*x = RecallMessageRequest{}
+	mi := &file_mail_proto_msgTypes[130]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UnregisterTaskListRequest) String() string {
+func (x *RecallMessageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UnregisterTaskListRequest) ProtoMessage() {}
+func (*RecallMessageRequest) ProtoMessage() {}
 
-func (x *UnregisterTaskListRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[121]
+func (x *RecallMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[130]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8052,41 +9127,47 @@ func (x *UnregisterTaskListRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UnregisterTaskListRequest.ProtoReflect.Descriptor instead.
-func (*UnregisterTaskListRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{121}
+// Deprecated: Use RecallMessageRequest.ProtoReflect.Descriptor instead.
+func (*RecallMessageRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{130}
 }
 
-func (x *UnregisterTaskListRequest) GetListId() string {
+func (x *RecallMessageRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.ListId
+		return x.AgentId
 	}
-	return ""
+	return 0
 }
 
-// UnregisterTaskListResponse is the response for UnregisterTaskList.
-type UnregisterTaskListResponse struct {
+func (x *RecallMessageRequest) GetMessageId() int64 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
+}
+
+// RecallMessageResponse is the response for RecallMessage.
+type RecallMessageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UnregisterTaskListResponse) Reset() {
-	*x = UnregisterTaskListResponse{}
-	mi := &file_mail_proto_msgTypes[122]
+func (x *RecallMessageResponse) Reset() {
+	*x = RecallMessageResponse{}
+	mi := &file_mail_proto_msgTypes[131]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UnregisterTaskListResponse) String() string {
+func (x *RecallMessageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UnregisterTaskListResponse) ProtoMessage() {}
+func (*RecallMessageResponse) ProtoMessage() {}
 
-func (x *UnregisterTaskListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[122]
+func (x *RecallMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[131]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8097,53 +9178,35 @@ func (x *UnregisterTaskListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UnregisterTaskListResponse.ProtoReflect.Descriptor instead.
-func (*UnregisterTaskListResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{122}
-}
-
-func (x *UnregisterTaskListResponse) GetError() string {
-	if x != nil {
-		return x.Error
-	}
-	return ""
+// Deprecated: Use RecallMessageResponse.ProtoReflect.Descriptor instead.
+func (*RecallMessageResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{131}
 }
 
-// UpsertTaskRequest is the request for UpsertTask.
-type UpsertTaskRequest struct {
+// GetMessageHistoryRequest is the request for GetMessageHistory.
+type GetMessageHistoryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	ListId        string                 `protobuf:"bytes,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
-	ClaudeTaskId  string                 `protobuf:"bytes,3,opt,name=claude_task_id,json=claudeTaskId,proto3" json:"claude_task_id,omitempty"`
-	Subject       string                 `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
-	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
-	ActiveForm    string                 `protobuf:"bytes,6,opt,name=active_form,json=activeForm,proto3" json:"active_form,omitempty"`
-	MetadataJson  string                 `protobuf:"bytes,7,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"`
-	Status        TaskStatus             `protobuf:"varint,8,opt,name=status,proto3,enum=subtraterpc.TaskStatus" json:"status,omitempty"`
-	Owner         string                 `protobuf:"bytes,9,opt,name=owner,proto3" json:"owner,omitempty"`
-	BlockedBy     []string               `protobuf:"bytes,10,rep,name=blocked_by,json=blockedBy,proto3" json:"blocked_by,omitempty"`
-	Blocks        []string               `protobuf:"bytes,11,rep,name=blocks,proto3" json:"blocks,omitempty"`
-	FilePath      string                 `protobuf:"bytes,12,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
-	FileMtime     int64                  `protobuf:"varint,13,opt,name=file_mtime,json=fileMtime,proto3" json:"file_mtime,omitempty"`
+	MessageId     int64                  `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpsertTaskRequest) Reset() {
-	*x = UpsertTaskRequest{}
-	mi := &file_mail_proto_msgTypes[123]
+func (x *GetMessageHistoryRequest) Reset() {
+	*x = GetMessageHistoryRequest{}
+	mi := &file_mail_proto_msgTypes[132]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpsertTaskRequest) String() string {
+func (x *GetMessageHistoryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpsertTaskRequest) ProtoMessage() {}
+func (*GetMessageHistoryRequest) ProtoMessage() {}
 
-func (x *UpsertTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[123]
+func (x *GetMessageHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[132]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8154,126 +9217,183 @@ func (x *UpsertTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpsertTaskRequest.ProtoReflect.Descriptor instead.
-func (*UpsertTaskRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{123}
+// Deprecated: Use GetMessageHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetMessageHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{132}
 }
 
-func (x *UpsertTaskRequest) GetAgentId() int64 {
+func (x *GetMessageHistoryRequest) GetMessageId() int64 {
 	if x != nil {
-		return x.AgentId
+		return x.MessageId
 	}
 	return 0
 }
 
-func (x *UpsertTaskRequest) GetListId() string {
-	if x != nil {
-		return x.ListId
-	}
-	return ""
-}
-
-func (x *UpsertTaskRequest) GetClaudeTaskId() string {
+func (x *GetMessageHistoryRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.ClaudeTaskId
+		return x.AgentId
 	}
-	return ""
+	return 0
 }
 
-func (x *UpsertTaskRequest) GetSubject() string {
-	if x != nil {
-		return x.Subject
-	}
-	return ""
+// GetMessageHistoryResponse is the response for GetMessageHistory.
+type GetMessageHistoryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Every version of the message, oldest first, ending with the
+	// current one.
+	Revisions     []*MessageRevision `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpsertTaskRequest) GetDescription() string {
-	if x != nil {
-		return x.Description
-	}
-	return ""
+func (x *GetMessageHistoryResponse) Reset() {
+	*x = GetMessageHistoryResponse{}
+	mi := &file_mail_proto_msgTypes[133]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpsertTaskRequest) GetActiveForm() string {
-	if x != nil {
-		return x.ActiveForm
-	}
-	return ""
+func (x *GetMessageHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *UpsertTaskRequest) GetMetadataJson() string {
-	if x != nil {
-		return x.MetadataJson
-	}
-	return ""
-}
+func (*GetMessageHistoryResponse) ProtoMessage() {}
 
-func (x *UpsertTaskRequest) GetStatus() TaskStatus {
+func (x *GetMessageHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[133]
 	if x != nil {
-		return x.Status
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return TaskStatus_TASK_STATUS_UNSPECIFIED
+	return mi.MessageOf(x)
 }
 
-func (x *UpsertTaskRequest) GetOwner() string {
-	if x != nil {
-		return x.Owner
-	}
-	return ""
+// Deprecated: Use GetMessageHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetMessageHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{133}
 }
 
-func (x *UpsertTaskRequest) GetBlockedBy() []string {
+func (x *GetMessageHistoryResponse) GetRevisions() []*MessageRevision {
 	if x != nil {
-		return x.BlockedBy
+		return x.Revisions
 	}
 	return nil
 }
 
-func (x *UpsertTaskRequest) GetBlocks() []string {
+// LabelProto is a label an agent files messages under.
+type LabelProto struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	AgentId int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Name    string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// Messages in the agent's inbox carrying the label, excluding archived
+	// and trashed ones, and how many of those are unread.
+	MessageCount  int64                  `protobuf:"varint,4,opt,name=message_count,json=messageCount,proto3" json:"message_count,omitempty"`
+	UnreadCount   int64                  `protobuf:"varint,5,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LabelProto) Reset() {
+	*x = LabelProto{}
+	mi := &file_mail_proto_msgTypes[134]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LabelProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelProto) ProtoMessage() {}
+
+func (x *LabelProto) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[134]
 	if x != nil {
-		return x.Blocks
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *UpsertTaskRequest) GetFilePath() string {
+// Deprecated: Use LabelProto.ProtoReflect.Descriptor instead.
+func (*LabelProto) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{134}
+}
+
+func (x *LabelProto) GetId() int64 {
 	if x != nil {
-		return x.FilePath
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LabelProto) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *LabelProto) GetName() string {
+	if x != nil {
+		return x.Name
 	}
 	return ""
 }
 
-func (x *UpsertTaskRequest) GetFileMtime() int64 {
+func (x *LabelProto) GetMessageCount() int64 {
 	if x != nil {
-		return x.FileMtime
+		return x.MessageCount
 	}
 	return 0
 }
 
-// UpsertTaskResponse is the response for UpsertTask.
-type UpsertTaskResponse struct {
+func (x *LabelProto) GetUnreadCount() int64 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+func (x *LabelProto) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// CreateLabelRequest is the request for CreateLabel.
+type CreateLabelRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Task          *TaskProto             `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"` // Lowercase letters, digits, '.', '_', '/' or '-'.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpsertTaskResponse) Reset() {
-	*x = UpsertTaskResponse{}
-	mi := &file_mail_proto_msgTypes[124]
+func (x *CreateLabelRequest) Reset() {
+	*x = CreateLabelRequest{}
+	mi := &file_mail_proto_msgTypes[135]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpsertTaskResponse) String() string {
+func (x *CreateLabelRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpsertTaskResponse) ProtoMessage() {}
+func (*CreateLabelRequest) ProtoMessage() {}
 
-func (x *UpsertTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[124]
+func (x *CreateLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[135]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8284,49 +9404,48 @@ func (x *UpsertTaskResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpsertTaskResponse.ProtoReflect.Descriptor instead.
-func (*UpsertTaskResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{124}
+// Deprecated: Use CreateLabelRequest.ProtoReflect.Descriptor instead.
+func (*CreateLabelRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{135}
 }
 
-func (x *UpsertTaskResponse) GetTask() *TaskProto {
+func (x *CreateLabelRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Task
+		return x.AgentId
 	}
-	return nil
+	return 0
 }
 
-func (x *UpsertTaskResponse) GetError() string {
+func (x *CreateLabelRequest) GetName() string {
 	if x != nil {
-		return x.Error
+		return x.Name
 	}
 	return ""
 }
 
-// GetTaskProtoRequest is the request for GetTask.
-type GetTaskProtoRequest struct {
+// ListLabelsRequest is the request for ListLabels.
+type ListLabelsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
-	ClaudeTaskId  string                 `protobuf:"bytes,2,opt,name=claude_task_id,json=claudeTaskId,proto3" json:"claude_task_id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTaskProtoRequest) Reset() {
-	*x = GetTaskProtoRequest{}
-	mi := &file_mail_proto_msgTypes[125]
+func (x *ListLabelsRequest) Reset() {
+	*x = ListLabelsRequest{}
+	mi := &file_mail_proto_msgTypes[136]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTaskProtoRequest) String() string {
+func (x *ListLabelsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTaskProtoRequest) ProtoMessage() {}
+func (*ListLabelsRequest) ProtoMessage() {}
 
-func (x *GetTaskProtoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[125]
+func (x *ListLabelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[136]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8337,49 +9456,41 @@ func (x *GetTaskProtoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTaskProtoRequest.ProtoReflect.Descriptor instead.
-func (*GetTaskProtoRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{125}
-}
-
-func (x *GetTaskProtoRequest) GetListId() string {
-	if x != nil {
-		return x.ListId
-	}
-	return ""
+// Deprecated: Use ListLabelsRequest.ProtoReflect.Descriptor instead.
+func (*ListLabelsRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{136}
 }
 
-func (x *GetTaskProtoRequest) GetClaudeTaskId() string {
+func (x *ListLabelsRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.ClaudeTaskId
+		return x.AgentId
 	}
-	return ""
+	return 0
 }
 
-// GetTaskResponse is the response for GetTask.
-type GetTaskResponse struct {
+// ListLabelsResponse is the response for ListLabels.
+type ListLabelsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Task          *TaskProto             `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Labels        []*LabelProto          `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTaskResponse) Reset() {
-	*x = GetTaskResponse{}
-	mi := &file_mail_proto_msgTypes[126]
+func (x *ListLabelsResponse) Reset() {
+	*x = ListLabelsResponse{}
+	mi := &file_mail_proto_msgTypes[137]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTaskResponse) String() string {
+func (x *ListLabelsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTaskResponse) ProtoMessage() {}
+func (*ListLabelsResponse) ProtoMessage() {}
 
-func (x *GetTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[126]
+func (x *ListLabelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[137]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8390,54 +9501,42 @@ func (x *GetTaskResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTaskResponse.ProtoReflect.Descriptor instead.
-func (*GetTaskResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{126}
+// Deprecated: Use ListLabelsResponse.ProtoReflect.Descriptor instead.
+func (*ListLabelsResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{137}
 }
 
-func (x *GetTaskResponse) GetTask() *TaskProto {
+func (x *ListLabelsResponse) GetLabels() []*LabelProto {
 	if x != nil {
-		return x.Task
+		return x.Labels
 	}
 	return nil
 }
 
-func (x *GetTaskResponse) GetError() string {
-	if x != nil {
-		return x.Error
-	}
-	return ""
-}
-
-// ListTasksRequest is the request for ListTasks.
-type ListTasksRequest struct {
+// DeleteLabelRequest is the request for DeleteLabel.
+type DeleteLabelRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	ListId        string                 `protobuf:"bytes,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
-	Status        TaskStatus             `protobuf:"varint,3,opt,name=status,proto3,enum=subtraterpc.TaskStatus" json:"status,omitempty"`
-	ActiveOnly    bool                   `protobuf:"varint,4,opt,name=active_only,json=activeOnly,proto3" json:"active_only,omitempty"`
-	AvailableOnly bool                   `protobuf:"varint,5,opt,name=available_only,json=availableOnly,proto3" json:"available_only,omitempty"`
-	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,7,opt,name=offset,proto3" json:"offset,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListTasksRequest) Reset() {
-	*x = ListTasksRequest{}
-	mi := &file_mail_proto_msgTypes[127]
+func (x *DeleteLabelRequest) Reset() {
+	*x = DeleteLabelRequest{}
+	mi := &file_mail_proto_msgTypes[138]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListTasksRequest) String() string {
+func (x *DeleteLabelRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListTasksRequest) ProtoMessage() {}
+func (*DeleteLabelRequest) ProtoMessage() {}
 
-func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[127]
+func (x *DeleteLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[138]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8448,84 +9547,88 @@ func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
-func (*ListTasksRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{127}
+// Deprecated: Use DeleteLabelRequest.ProtoReflect.Descriptor instead.
+func (*DeleteLabelRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{138}
 }
 
-func (x *ListTasksRequest) GetAgentId() int64 {
+func (x *DeleteLabelRequest) GetAgentId() int64 {
 	if x != nil {
 		return x.AgentId
 	}
 	return 0
 }
 
-func (x *ListTasksRequest) GetListId() string {
+func (x *DeleteLabelRequest) GetName() string {
 	if x != nil {
-		return x.ListId
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ListTasksRequest) GetStatus() TaskStatus {
-	if x != nil {
-		return x.Status
-	}
-	return TaskStatus_TASK_STATUS_UNSPECIFIED
+// DeleteLabelResponse is the response for DeleteLabel.
+type DeleteLabelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListTasksRequest) GetActiveOnly() bool {
-	if x != nil {
-		return x.ActiveOnly
-	}
-	return false
+func (x *DeleteLabelResponse) Reset() {
+	*x = DeleteLabelResponse{}
+	mi := &file_mail_proto_msgTypes[139]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListTasksRequest) GetAvailableOnly() bool {
-	if x != nil {
-		return x.AvailableOnly
-	}
-	return false
+func (x *DeleteLabelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *ListTasksRequest) GetLimit() int32 {
+func (*DeleteLabelResponse) ProtoMessage() {}
+
+func (x *DeleteLabelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[139]
 	if x != nil {
-		return x.Limit
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *ListTasksRequest) GetOffset() int32 {
-	if x != nil {
-		return x.Offset
-	}
-	return 0
+// Deprecated: Use DeleteLabelResponse.ProtoReflect.Descriptor instead.
+func (*DeleteLabelResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{139}
 }
 
-// ListTasksResponse is the response for ListTasks.
-type ListTasksResponse struct {
+// LabelMessageRequest is the request for LabelMessage.
+type LabelMessageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Tasks         []*TaskProto           `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // A recipient of the message.
+	MessageId     int64                  `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Add           []string               `protobuf:"bytes,3,rep,name=add,proto3" json:"add,omitempty"`
+	Remove        []string               `protobuf:"bytes,4,rep,name=remove,proto3" json:"remove,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListTasksResponse) Reset() {
-	*x = ListTasksResponse{}
-	mi := &file_mail_proto_msgTypes[128]
+func (x *LabelMessageRequest) Reset() {
+	*x = LabelMessageRequest{}
+	mi := &file_mail_proto_msgTypes[140]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListTasksResponse) String() string {
+func (x *LabelMessageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListTasksResponse) ProtoMessage() {}
+func (*LabelMessageRequest) ProtoMessage() {}
 
-func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[128]
+func (x *LabelMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[140]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8536,50 +9639,63 @@ func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
-func (*ListTasksResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{128}
+// Deprecated: Use LabelMessageRequest.ProtoReflect.Descriptor instead.
+func (*LabelMessageRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{140}
 }
 
-func (x *ListTasksResponse) GetTasks() []*TaskProto {
+func (x *LabelMessageRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Tasks
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *LabelMessageRequest) GetMessageId() int64 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
+}
+
+func (x *LabelMessageRequest) GetAdd() []string {
+	if x != nil {
+		return x.Add
 	}
 	return nil
 }
 
-func (x *ListTasksResponse) GetError() string {
+func (x *LabelMessageRequest) GetRemove() []string {
 	if x != nil {
-		return x.Error
+		return x.Remove
 	}
-	return ""
+	return nil
 }
 
-// UpdateTaskStatusRequest is the request for UpdateTaskStatus.
-type UpdateTaskStatusRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
-	ClaudeTaskId  string                 `protobuf:"bytes,2,opt,name=claude_task_id,json=claudeTaskId,proto3" json:"claude_task_id,omitempty"`
-	Status        TaskStatus             `protobuf:"varint,3,opt,name=status,proto3,enum=subtraterpc.TaskStatus" json:"status,omitempty"`
+// LabelMessageResponse is the response for LabelMessage.
+type LabelMessageResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The labels the message carries afterwards.
+	Labels        []string `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateTaskStatusRequest) Reset() {
-	*x = UpdateTaskStatusRequest{}
-	mi := &file_mail_proto_msgTypes[129]
+func (x *LabelMessageResponse) Reset() {
+	*x = LabelMessageResponse{}
+	mi := &file_mail_proto_msgTypes[141]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateTaskStatusRequest) String() string {
+func (x *LabelMessageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateTaskStatusRequest) ProtoMessage() {}
+func (*LabelMessageResponse) ProtoMessage() {}
 
-func (x *UpdateTaskStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[129]
+func (x *LabelMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[141]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8590,55 +9706,121 @@ func (x *UpdateTaskStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateTaskStatusRequest.ProtoReflect.Descriptor instead.
-func (*UpdateTaskStatusRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{129}
+// Deprecated: Use LabelMessageResponse.ProtoReflect.Descriptor instead.
+func (*LabelMessageResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{141}
 }
 
-func (x *UpdateTaskStatusRequest) GetListId() string {
+func (x *LabelMessageResponse) GetLabels() []string {
 	if x != nil {
-		return x.ListId
+		return x.Labels
 	}
-	return ""
+	return nil
 }
 
-func (x *UpdateTaskStatusRequest) GetClaudeTaskId() string {
+// ThreadPreferenceProto is an agent's preferences for a thread. Muting and
+// following exclude each other.
+type ThreadPreferenceProto struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ThreadId      string                 `protobuf:"bytes,1,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Muted         bool                   `protobuf:"varint,3,opt,name=muted,proto3" json:"muted,omitempty"`
+	Following     bool                   `protobuf:"varint,4,opt,name=following,proto3" json:"following,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThreadPreferenceProto) Reset() {
+	*x = ThreadPreferenceProto{}
+	mi := &file_mail_proto_msgTypes[142]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThreadPreferenceProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThreadPreferenceProto) ProtoMessage() {}
+
+func (x *ThreadPreferenceProto) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[142]
 	if x != nil {
-		return x.ClaudeTaskId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThreadPreferenceProto.ProtoReflect.Descriptor instead.
+func (*ThreadPreferenceProto) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{142}
+}
+
+func (x *ThreadPreferenceProto) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
 	}
 	return ""
 }
 
-func (x *UpdateTaskStatusRequest) GetStatus() TaskStatus {
+func (x *ThreadPreferenceProto) GetAgentId() int64 {
 	if x != nil {
-		return x.Status
+		return x.AgentId
 	}
-	return TaskStatus_TASK_STATUS_UNSPECIFIED
+	return 0
 }
 
-// UpdateTaskStatusResponse is the response for UpdateTaskStatus.
-type UpdateTaskStatusResponse struct {
+func (x *ThreadPreferenceProto) GetMuted() bool {
+	if x != nil {
+		return x.Muted
+	}
+	return false
+}
+
+func (x *ThreadPreferenceProto) GetFollowing() bool {
+	if x != nil {
+		return x.Following
+	}
+	return false
+}
+
+func (x *ThreadPreferenceProto) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// SetThreadMutedRequest is the request for SetThreadMuted.
+type SetThreadMutedRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	Muted         bool                   `protobuf:"varint,3,opt,name=muted,proto3" json:"muted,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateTaskStatusResponse) Reset() {
-	*x = UpdateTaskStatusResponse{}
-	mi := &file_mail_proto_msgTypes[130]
+func (x *SetThreadMutedRequest) Reset() {
+	*x = SetThreadMutedRequest{}
+	mi := &file_mail_proto_msgTypes[143]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateTaskStatusResponse) String() string {
+func (x *SetThreadMutedRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateTaskStatusResponse) ProtoMessage() {}
+func (*SetThreadMutedRequest) ProtoMessage() {}
 
-func (x *UpdateTaskStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[130]
+func (x *SetThreadMutedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[143]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8649,43 +9831,57 @@ func (x *UpdateTaskStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateTaskStatusResponse.ProtoReflect.Descriptor instead.
-func (*UpdateTaskStatusResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{130}
+// Deprecated: Use SetThreadMutedRequest.ProtoReflect.Descriptor instead.
+func (*SetThreadMutedRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{143}
 }
 
-func (x *UpdateTaskStatusResponse) GetError() string {
+func (x *SetThreadMutedRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Error
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *SetThreadMutedRequest) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
 	}
 	return ""
 }
 
-// UpdateTaskOwnerRequest is the request for UpdateTaskOwner.
-type UpdateTaskOwnerRequest struct {
+func (x *SetThreadMutedRequest) GetMuted() bool {
+	if x != nil {
+		return x.Muted
+	}
+	return false
+}
+
+// SetThreadFollowingRequest is the request for SetThreadFollowing.
+type SetThreadFollowingRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
-	ClaudeTaskId  string                 `protobuf:"bytes,2,opt,name=claude_task_id,json=claudeTaskId,proto3" json:"claude_task_id,omitempty"`
-	Owner         string                 `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	Following     bool                   `protobuf:"varint,3,opt,name=following,proto3" json:"following,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateTaskOwnerRequest) Reset() {
-	*x = UpdateTaskOwnerRequest{}
-	mi := &file_mail_proto_msgTypes[131]
+func (x *SetThreadFollowingRequest) Reset() {
+	*x = SetThreadFollowingRequest{}
+	mi := &file_mail_proto_msgTypes[144]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateTaskOwnerRequest) String() string {
+func (x *SetThreadFollowingRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateTaskOwnerRequest) ProtoMessage() {}
+func (*SetThreadFollowingRequest) ProtoMessage() {}
 
-func (x *UpdateTaskOwnerRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[131]
+func (x *SetThreadFollowingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[144]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8696,55 +9892,200 @@ func (x *UpdateTaskOwnerRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateTaskOwnerRequest.ProtoReflect.Descriptor instead.
-func (*UpdateTaskOwnerRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{131}
+// Deprecated: Use SetThreadFollowingRequest.ProtoReflect.Descriptor instead.
+func (*SetThreadFollowingRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{144}
 }
 
-func (x *UpdateTaskOwnerRequest) GetListId() string {
+func (x *SetThreadFollowingRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.ListId
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *SetThreadFollowingRequest) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
 	}
 	return ""
 }
 
-func (x *UpdateTaskOwnerRequest) GetClaudeTaskId() string {
+func (x *SetThreadFollowingRequest) GetFollowing() bool {
 	if x != nil {
-		return x.ClaudeTaskId
+		return x.Following
+	}
+	return false
+}
+
+// ListThreadPreferencesRequest is the request for ListThreadPreferences.
+type ListThreadPreferencesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListThreadPreferencesRequest) Reset() {
+	*x = ListThreadPreferencesRequest{}
+	mi := &file_mail_proto_msgTypes[145]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListThreadPreferencesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListThreadPreferencesRequest) ProtoMessage() {}
+
+func (x *ListThreadPreferencesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[145]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListThreadPreferencesRequest.ProtoReflect.Descriptor instead.
+func (*ListThreadPreferencesRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{145}
+}
+
+func (x *ListThreadPreferencesRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+// ListThreadPreferencesResponse is the response for ListThreadPreferences.
+type ListThreadPreferencesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Most recently changed first.
+	Threads       []*ThreadPreferenceProto `protobuf:"bytes,1,rep,name=threads,proto3" json:"threads,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListThreadPreferencesResponse) Reset() {
+	*x = ListThreadPreferencesResponse{}
+	mi := &file_mail_proto_msgTypes[146]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListThreadPreferencesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListThreadPreferencesResponse) ProtoMessage() {}
+
+func (x *ListThreadPreferencesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[146]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListThreadPreferencesResponse.ProtoReflect.Descriptor instead.
+func (*ListThreadPreferencesResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{146}
+}
+
+func (x *ListThreadPreferencesResponse) GetThreads() []*ThreadPreferenceProto {
+	if x != nil {
+		return x.Threads
+	}
+	return nil
+}
+
+// MergeThreadsRequest is the request for MergeThreads. Threads tracked by a
+// review or plan review can't be merged.
+type MergeThreadsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromThreadId  string                 `protobuf:"bytes,1,opt,name=from_thread_id,json=fromThreadId,proto3" json:"from_thread_id,omitempty"` // The thread whose messages move.
+	IntoThreadId  string                 `protobuf:"bytes,2,opt,name=into_thread_id,json=intoThreadId,proto3" json:"into_thread_id,omitempty"` // The thread they move into.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergeThreadsRequest) Reset() {
+	*x = MergeThreadsRequest{}
+	mi := &file_mail_proto_msgTypes[147]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeThreadsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeThreadsRequest) ProtoMessage() {}
+
+func (x *MergeThreadsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[147]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeThreadsRequest.ProtoReflect.Descriptor instead.
+func (*MergeThreadsRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{147}
+}
+
+func (x *MergeThreadsRequest) GetFromThreadId() string {
+	if x != nil {
+		return x.FromThreadId
 	}
 	return ""
 }
 
-func (x *UpdateTaskOwnerRequest) GetOwner() string {
+func (x *MergeThreadsRequest) GetIntoThreadId() string {
 	if x != nil {
-		return x.Owner
+		return x.IntoThreadId
 	}
 	return ""
 }
 
-// UpdateTaskOwnerResponse is the response for UpdateTaskOwner.
-type UpdateTaskOwnerResponse struct {
+// MergeThreadsResponse is the response for MergeThreads.
+type MergeThreadsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	MessagesMoved int64                  `protobuf:"varint,1,opt,name=messages_moved,json=messagesMoved,proto3" json:"messages_moved,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateTaskOwnerResponse) Reset() {
-	*x = UpdateTaskOwnerResponse{}
-	mi := &file_mail_proto_msgTypes[132]
+func (x *MergeThreadsResponse) Reset() {
+	*x = MergeThreadsResponse{}
+	mi := &file_mail_proto_msgTypes[148]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateTaskOwnerResponse) String() string {
+func (x *MergeThreadsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateTaskOwnerResponse) ProtoMessage() {}
+func (*MergeThreadsResponse) ProtoMessage() {}
 
-func (x *UpdateTaskOwnerResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[132]
+func (x *MergeThreadsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[148]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8752,44 +10093,6945 @@ func (x *UpdateTaskOwnerResponse) ProtoReflect() protoreflect.Message {
 		}
 		return ms
 	}
-	return mi.MessageOf(x)
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeThreadsResponse.ProtoReflect.Descriptor instead.
+func (*MergeThreadsResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{148}
+}
+
+func (x *MergeThreadsResponse) GetMessagesMoved() int64 {
+	if x != nil {
+		return x.MessagesMoved
+	}
+	return 0
+}
+
+// SplitThreadRequest is the request for SplitThread. Threads tracked by a
+// review or plan review can't be split.
+type SplitThreadRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The first message to move. It can't be the first in its thread.
+	MessageId     int64 `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SplitThreadRequest) Reset() {
+	*x = SplitThreadRequest{}
+	mi := &file_mail_proto_msgTypes[149]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SplitThreadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SplitThreadRequest) ProtoMessage() {}
+
+func (x *SplitThreadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[149]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SplitThreadRequest.ProtoReflect.Descriptor instead.
+func (*SplitThreadRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{149}
+}
+
+func (x *SplitThreadRequest) GetMessageId() int64 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
+}
+
+// SplitThreadResponse is the response for SplitThread.
+type SplitThreadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ThreadId      string                 `protobuf:"bytes,1,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"` // The new thread.
+	MessagesMoved int64                  `protobuf:"varint,2,opt,name=messages_moved,json=messagesMoved,proto3" json:"messages_moved,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SplitThreadResponse) Reset() {
+	*x = SplitThreadResponse{}
+	mi := &file_mail_proto_msgTypes[150]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SplitThreadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SplitThreadResponse) ProtoMessage() {}
+
+func (x *SplitThreadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[150]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SplitThreadResponse.ProtoReflect.Descriptor instead.
+func (*SplitThreadResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{150}
+}
+
+func (x *SplitThreadResponse) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *SplitThreadResponse) GetMessagesMoved() int64 {
+	if x != nil {
+		return x.MessagesMoved
+	}
+	return 0
+}
+
+// UpdateAgentRequest is the request for UpdateAgent.
+type UpdateAgentRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProjectKey string                 `protobuf:"bytes,2,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
+	GitBranch  string                 `protobuf:"bytes,3,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`
+	// capabilities, when set, replace the agent's declared capabilities.
+	Capabilities  *AgentCapabilities `protobuf:"bytes,4,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAgentRequest) Reset() {
+	*x = UpdateAgentRequest{}
+	mi := &file_mail_proto_msgTypes[151]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAgentRequest) ProtoMessage() {}
+
+func (x *UpdateAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[151]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAgentRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAgentRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{151}
+}
+
+func (x *UpdateAgentRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateAgentRequest) GetProjectKey() string {
+	if x != nil {
+		return x.ProjectKey
+	}
+	return ""
+}
+
+func (x *UpdateAgentRequest) GetGitBranch() string {
+	if x != nil {
+		return x.GitBranch
+	}
+	return ""
+}
+
+func (x *UpdateAgentRequest) GetCapabilities() *AgentCapabilities {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+// UpdateAgentResponse is the response for UpdateAgent.
+type UpdateAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agent         *GetAgentResponse      `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAgentResponse) Reset() {
+	*x = UpdateAgentResponse{}
+	mi := &file_mail_proto_msgTypes[152]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAgentResponse) ProtoMessage() {}
+
+func (x *UpdateAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[152]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAgentResponse.ProtoReflect.Descriptor instead.
+func (*UpdateAgentResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{152}
+}
+
+func (x *UpdateAgentResponse) GetAgent() *GetAgentResponse {
+	if x != nil {
+		return x.Agent
+	}
+	return nil
+}
+
+// AgentWithStatus represents an agent with its current status.
+type AgentWithStatus struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ProjectKey            string                 `protobuf:"bytes,3,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
+	GitBranch             string                 `protobuf:"bytes,4,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`
+	Status                AgentStatus            `protobuf:"varint,5,opt,name=status,proto3,enum=subtraterpc.AgentStatus" json:"status,omitempty"`
+	LastActiveAt          *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_active_at,json=lastActiveAt,proto3" json:"last_active_at,omitempty"`
+	SessionId             int64                  `protobuf:"varint,7,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	SecondsSinceHeartbeat int64                  `protobuf:"varint,8,opt,name=seconds_since_heartbeat,json=secondsSinceHeartbeat,proto3" json:"seconds_since_heartbeat,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *AgentWithStatus) Reset() {
+	*x = AgentWithStatus{}
+	mi := &file_mail_proto_msgTypes[153]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentWithStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentWithStatus) ProtoMessage() {}
+
+func (x *AgentWithStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[153]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentWithStatus.ProtoReflect.Descriptor instead.
+func (*AgentWithStatus) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{153}
+}
+
+func (x *AgentWithStatus) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AgentWithStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AgentWithStatus) GetProjectKey() string {
+	if x != nil {
+		return x.ProjectKey
+	}
+	return ""
+}
+
+func (x *AgentWithStatus) GetGitBranch() string {
+	if x != nil {
+		return x.GitBranch
+	}
+	return ""
+}
+
+func (x *AgentWithStatus) GetStatus() AgentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AgentStatus_AGENT_STATUS_UNSPECIFIED
+}
+
+func (x *AgentWithStatus) GetLastActiveAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastActiveAt
+	}
+	return nil
+}
+
+func (x *AgentWithStatus) GetSessionId() int64 {
+	if x != nil {
+		return x.SessionId
+	}
+	return 0
+}
+
+func (x *AgentWithStatus) GetSecondsSinceHeartbeat() int64 {
+	if x != nil {
+		return x.SecondsSinceHeartbeat
+	}
+	return 0
+}
+
+// AgentStatusCounts represents counts of agents by status.
+type AgentStatusCounts struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Active        int32                  `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
+	Busy          int32                  `protobuf:"varint,2,opt,name=busy,proto3" json:"busy,omitempty"`
+	Idle          int32                  `protobuf:"varint,3,opt,name=idle,proto3" json:"idle,omitempty"`
+	Offline       int32                  `protobuf:"varint,4,opt,name=offline,proto3" json:"offline,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentStatusCounts) Reset() {
+	*x = AgentStatusCounts{}
+	mi := &file_mail_proto_msgTypes[154]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentStatusCounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentStatusCounts) ProtoMessage() {}
+
+func (x *AgentStatusCounts) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[154]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentStatusCounts.ProtoReflect.Descriptor instead.
+func (*AgentStatusCounts) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{154}
+}
+
+func (x *AgentStatusCounts) GetActive() int32 {
+	if x != nil {
+		return x.Active
+	}
+	return 0
+}
+
+func (x *AgentStatusCounts) GetBusy() int32 {
+	if x != nil {
+		return x.Busy
+	}
+	return 0
+}
+
+func (x *AgentStatusCounts) GetIdle() int32 {
+	if x != nil {
+		return x.Idle
+	}
+	return 0
+}
+
+func (x *AgentStatusCounts) GetOffline() int32 {
+	if x != nil {
+		return x.Offline
+	}
+	return 0
+}
+
+// GetAgentsStatusRequest is the request for GetAgentsStatus.
+type GetAgentsStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAgentsStatusRequest) Reset() {
+	*x = GetAgentsStatusRequest{}
+	mi := &file_mail_proto_msgTypes[155]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAgentsStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAgentsStatusRequest) ProtoMessage() {}
+
+func (x *GetAgentsStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[155]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAgentsStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetAgentsStatusRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{155}
+}
+
+// GetAgentsStatusResponse is the response for GetAgentsStatus.
+type GetAgentsStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agents        []*AgentWithStatus     `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	Counts        *AgentStatusCounts     `protobuf:"bytes,2,opt,name=counts,proto3" json:"counts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAgentsStatusResponse) Reset() {
+	*x = GetAgentsStatusResponse{}
+	mi := &file_mail_proto_msgTypes[156]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAgentsStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAgentsStatusResponse) ProtoMessage() {}
+
+func (x *GetAgentsStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[156]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAgentsStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetAgentsStatusResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{156}
+}
+
+func (x *GetAgentsStatusResponse) GetAgents() []*AgentWithStatus {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+func (x *GetAgentsStatusResponse) GetCounts() *AgentStatusCounts {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+// DiscoverAgentsRequest is the request for DiscoverAgents.
+type DiscoverAgentsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// status_filter filters by agent status (match any). Empty means all.
+	StatusFilter []AgentStatus `protobuf:"varint,1,rep,packed,name=status_filter,json=statusFilter,proto3,enum=subtraterpc.AgentStatus" json:"status_filter,omitempty"`
+	// project_filter filters by project key prefix.
+	ProjectFilter string `protobuf:"bytes,2,opt,name=project_filter,json=projectFilter,proto3" json:"project_filter,omitempty"`
+	// name_filter filters by agent name substring.
+	NameFilter string `protobuf:"bytes,3,opt,name=name_filter,json=nameFilter,proto3" json:"name_filter,omitempty"`
+	// capability_filter keeps agents declaring a capability, written as
+	// <name> or <kind>:<name>.
+	CapabilityFilter string `protobuf:"bytes,4,opt,name=capability_filter,json=capabilityFilter,proto3" json:"capability_filter,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DiscoverAgentsRequest) Reset() {
+	*x = DiscoverAgentsRequest{}
+	mi := &file_mail_proto_msgTypes[157]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoverAgentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoverAgentsRequest) ProtoMessage() {}
+
+func (x *DiscoverAgentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[157]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoverAgentsRequest.ProtoReflect.Descriptor instead.
+func (*DiscoverAgentsRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{157}
+}
+
+func (x *DiscoverAgentsRequest) GetStatusFilter() []AgentStatus {
+	if x != nil {
+		return x.StatusFilter
+	}
+	return nil
+}
+
+func (x *DiscoverAgentsRequest) GetProjectFilter() string {
+	if x != nil {
+		return x.ProjectFilter
+	}
+	return ""
+}
+
+func (x *DiscoverAgentsRequest) GetNameFilter() string {
+	if x != nil {
+		return x.NameFilter
+	}
+	return ""
+}
+
+func (x *DiscoverAgentsRequest) GetCapabilityFilter() string {
+	if x != nil {
+		return x.CapabilityFilter
+	}
+	return ""
+}
+
+// DiscoveredAgent represents an agent with full discovery metadata.
+type DiscoveredAgent struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ProjectKey            string                 `protobuf:"bytes,3,opt,name=project_key,json=projectKey,proto3" json:"project_key,omitempty"`
+	GitBranch             string                 `protobuf:"bytes,4,opt,name=git_branch,json=gitBranch,proto3" json:"git_branch,omitempty"`
+	Status                AgentStatus            `protobuf:"varint,5,opt,name=status,proto3,enum=subtraterpc.AgentStatus" json:"status,omitempty"`
+	LastActiveAt          *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_active_at,json=lastActiveAt,proto3" json:"last_active_at,omitempty"`
+	SecondsSinceHeartbeat int64                  `protobuf:"varint,7,opt,name=seconds_since_heartbeat,json=secondsSinceHeartbeat,proto3" json:"seconds_since_heartbeat,omitempty"`
+	SessionId             string                 `protobuf:"bytes,8,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Purpose               string                 `protobuf:"bytes,9,opt,name=purpose,proto3" json:"purpose,omitempty"`
+	WorkingDir            string                 `protobuf:"bytes,10,opt,name=working_dir,json=workingDir,proto3" json:"working_dir,omitempty"`
+	Hostname              string                 `protobuf:"bytes,11,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	UnreadCount           int64                  `protobuf:"varint,12,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
+	Capabilities          *AgentCapabilities     `protobuf:"bytes,13,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *DiscoveredAgent) Reset() {
+	*x = DiscoveredAgent{}
+	mi := &file_mail_proto_msgTypes[158]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoveredAgent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoveredAgent) ProtoMessage() {}
+
+func (x *DiscoveredAgent) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[158]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoveredAgent.ProtoReflect.Descriptor instead.
+func (*DiscoveredAgent) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{158}
+}
+
+func (x *DiscoveredAgent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DiscoveredAgent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DiscoveredAgent) GetProjectKey() string {
+	if x != nil {
+		return x.ProjectKey
+	}
+	return ""
+}
+
+func (x *DiscoveredAgent) GetGitBranch() string {
+	if x != nil {
+		return x.GitBranch
+	}
+	return ""
+}
+
+func (x *DiscoveredAgent) GetStatus() AgentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AgentStatus_AGENT_STATUS_UNSPECIFIED
+}
+
+func (x *DiscoveredAgent) GetLastActiveAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastActiveAt
+	}
+	return nil
+}
+
+func (x *DiscoveredAgent) GetSecondsSinceHeartbeat() int64 {
+	if x != nil {
+		return x.SecondsSinceHeartbeat
+	}
+	return 0
+}
+
+func (x *DiscoveredAgent) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *DiscoveredAgent) GetPurpose() string {
+	if x != nil {
+		return x.Purpose
+	}
+	return ""
+}
+
+func (x *DiscoveredAgent) GetWorkingDir() string {
+	if x != nil {
+		return x.WorkingDir
+	}
+	return ""
+}
+
+func (x *DiscoveredAgent) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *DiscoveredAgent) GetUnreadCount() int64 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+func (x *DiscoveredAgent) GetCapabilities() *AgentCapabilities {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+// DiscoverAgentsResponse is the response for DiscoverAgents.
+type DiscoverAgentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agents        []*DiscoveredAgent     `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	Counts        *AgentStatusCounts     `protobuf:"bytes,2,opt,name=counts,proto3" json:"counts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoverAgentsResponse) Reset() {
+	*x = DiscoverAgentsResponse{}
+	mi := &file_mail_proto_msgTypes[159]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoverAgentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoverAgentsResponse) ProtoMessage() {}
+
+func (x *DiscoverAgentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[159]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoverAgentsResponse.ProtoReflect.Descriptor instead.
+func (*DiscoverAgentsResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{159}
+}
+
+func (x *DiscoverAgentsResponse) GetAgents() []*DiscoveredAgent {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+func (x *DiscoverAgentsResponse) GetCounts() *AgentStatusCounts {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+// ListCapabilityRoutesRequest is the request for ListCapabilityRoutes.
+type ListCapabilityRoutesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// capability keeps the routes of one capability. Empty means all.
+	Capability    string `protobuf:"bytes,1,opt,name=capability,proto3" json:"capability,omitempty"`
+	Limit         int32  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCapabilityRoutesRequest) Reset() {
+	*x = ListCapabilityRoutesRequest{}
+	mi := &file_mail_proto_msgTypes[160]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCapabilityRoutesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCapabilityRoutesRequest) ProtoMessage() {}
+
+func (x *ListCapabilityRoutesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[160]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCapabilityRoutesRequest.ProtoReflect.Descriptor instead.
+func (*ListCapabilityRoutesRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{160}
+}
+
+func (x *ListCapabilityRoutesRequest) GetCapability() string {
+	if x != nil {
+		return x.Capability
+	}
+	return ""
+}
+
+func (x *ListCapabilityRoutesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// CapabilityRoute is the agent the router picked for a message or task
+// addressed to a capability, and the load it was picked on.
+type CapabilityRoute struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Capability      string                 `protobuf:"bytes,1,opt,name=capability,proto3" json:"capability,omitempty"`
+	AgentId         int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	AgentName       string                 `protobuf:"bytes,3,opt,name=agent_name,json=agentName,proto3" json:"agent_name,omitempty"`
+	MessageId       int64                  `protobuf:"varint,4,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // Set when a message was routed
+	TaskId          int64                  `protobuf:"varint,5,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`          // Set when a task was routed
+	Candidates      int64                  `protobuf:"varint,6,opt,name=candidates,proto3" json:"candidates,omitempty"`                // Online agents chosen from
+	UnreadCount     int64                  `protobuf:"varint,7,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
+	InProgressCount int64                  `protobuf:"varint,8,opt,name=in_progress_count,json=inProgressCount,proto3" json:"in_progress_count,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CapabilityRoute) Reset() {
+	*x = CapabilityRoute{}
+	mi := &file_mail_proto_msgTypes[161]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CapabilityRoute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CapabilityRoute) ProtoMessage() {}
+
+func (x *CapabilityRoute) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[161]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CapabilityRoute.ProtoReflect.Descriptor instead.
+func (*CapabilityRoute) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{161}
+}
+
+func (x *CapabilityRoute) GetCapability() string {
+	if x != nil {
+		return x.Capability
+	}
+	return ""
+}
+
+func (x *CapabilityRoute) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *CapabilityRoute) GetAgentName() string {
+	if x != nil {
+		return x.AgentName
+	}
+	return ""
+}
+
+func (x *CapabilityRoute) GetMessageId() int64 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
+}
+
+func (x *CapabilityRoute) GetTaskId() int64 {
+	if x != nil {
+		return x.TaskId
+	}
+	return 0
+}
+
+func (x *CapabilityRoute) GetCandidates() int64 {
+	if x != nil {
+		return x.Candidates
+	}
+	return 0
+}
+
+func (x *CapabilityRoute) GetUnreadCount() int64 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+func (x *CapabilityRoute) GetInProgressCount() int64 {
+	if x != nil {
+		return x.InProgressCount
+	}
+	return 0
+}
+
+func (x *CapabilityRoute) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// ListCapabilityRoutesResponse is the response for ListCapabilityRoutes.
+type ListCapabilityRoutesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Routes        []*CapabilityRoute     `protobuf:"bytes,1,rep,name=routes,proto3" json:"routes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCapabilityRoutesResponse) Reset() {
+	*x = ListCapabilityRoutesResponse{}
+	mi := &file_mail_proto_msgTypes[162]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCapabilityRoutesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCapabilityRoutesResponse) ProtoMessage() {}
+
+func (x *ListCapabilityRoutesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[162]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCapabilityRoutesResponse.ProtoReflect.Descriptor instead.
+func (*ListCapabilityRoutesResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{162}
+}
+
+func (x *ListCapabilityRoutesResponse) GetRoutes() []*CapabilityRoute {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+// Credential is a bearer credential. Its token is only ever returned when
+// it is minted.
+type Credential struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	AgentId   int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // 0 for an admin credential
+	AgentName string                 `protobuf:"bytes,3,opt,name=agent_name,json=agentName,proto3" json:"agent_name,omitempty"`
+	Admin     bool                   `protobuf:"varint,4,opt,name=admin,proto3" json:"admin,omitempty"`
+	Name      string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	// allowed_methods restricts the credential to these RPCs. Empty
+	// allows every RPC.
+	AllowedMethods []string               `protobuf:"bytes,6,rep,name=allowed_methods,json=allowedMethods,proto3" json:"allowed_methods,omitempty"`
+	ExpiresAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastUsedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=last_used_at,json=lastUsedAt,proto3" json:"last_used_at,omitempty"`
+	RevokedAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Credential) Reset() {
+	*x = Credential{}
+	mi := &file_mail_proto_msgTypes[163]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Credential) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Credential) ProtoMessage() {}
+
+func (x *Credential) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[163]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Credential.ProtoReflect.Descriptor instead.
+func (*Credential) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{163}
+}
+
+func (x *Credential) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Credential) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *Credential) GetAgentName() string {
+	if x != nil {
+		return x.AgentName
+	}
+	return ""
+}
+
+func (x *Credential) GetAdmin() bool {
+	if x != nil {
+		return x.Admin
+	}
+	return false
+}
+
+func (x *Credential) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Credential) GetAllowedMethods() []string {
+	if x != nil {
+		return x.AllowedMethods
+	}
+	return nil
+}
+
+func (x *Credential) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Credential) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Credential) GetLastUsedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUsedAt
+	}
+	return nil
+}
+
+func (x *Credential) GetRevokedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return nil
+}
+
+// MintCredentialRequest is the request for MintCredential. Exactly one of
+// agent_id and admin must be set.
+type MintCredentialRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AgentId        int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Admin          bool                   `protobuf:"varint,2,opt,name=admin,proto3" json:"admin,omitempty"`
+	Name           string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	AllowedMethods []string               `protobuf:"bytes,4,rep,name=allowed_methods,json=allowedMethods,proto3" json:"allowed_methods,omitempty"`
+	TtlSeconds     int64                  `protobuf:"varint,5,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // 0 means the token never expires
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MintCredentialRequest) Reset() {
+	*x = MintCredentialRequest{}
+	mi := &file_mail_proto_msgTypes[164]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MintCredentialRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MintCredentialRequest) ProtoMessage() {}
+
+func (x *MintCredentialRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[164]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MintCredentialRequest.ProtoReflect.Descriptor instead.
+func (*MintCredentialRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{164}
+}
+
+func (x *MintCredentialRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *MintCredentialRequest) GetAdmin() bool {
+	if x != nil {
+		return x.Admin
+	}
+	return false
+}
+
+func (x *MintCredentialRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MintCredentialRequest) GetAllowedMethods() []string {
+	if x != nil {
+		return x.AllowedMethods
+	}
+	return nil
+}
+
+func (x *MintCredentialRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+// MintCredentialResponse is the response for MintCredential.
+type MintCredentialResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Credential    *Credential            `protobuf:"bytes,2,opt,name=credential,proto3" json:"credential,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MintCredentialResponse) Reset() {
+	*x = MintCredentialResponse{}
+	mi := &file_mail_proto_msgTypes[165]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MintCredentialResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MintCredentialResponse) ProtoMessage() {}
+
+func (x *MintCredentialResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[165]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MintCredentialResponse.ProtoReflect.Descriptor instead.
+func (*MintCredentialResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{165}
+}
+
+func (x *MintCredentialResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *MintCredentialResponse) GetCredential() *Credential {
+	if x != nil {
+		return x.Credential
+	}
+	return nil
+}
+
+// ListCredentialsRequest is the request for ListCredentials.
+type ListCredentialsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AgentId        int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // 0 lists every credential
+	IncludeRevoked bool                   `protobuf:"varint,2,opt,name=include_revoked,json=includeRevoked,proto3" json:"include_revoked,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListCredentialsRequest) Reset() {
+	*x = ListCredentialsRequest{}
+	mi := &file_mail_proto_msgTypes[166]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCredentialsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCredentialsRequest) ProtoMessage() {}
+
+func (x *ListCredentialsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[166]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCredentialsRequest.ProtoReflect.Descriptor instead.
+func (*ListCredentialsRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{166}
+}
+
+func (x *ListCredentialsRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *ListCredentialsRequest) GetIncludeRevoked() bool {
+	if x != nil {
+		return x.IncludeRevoked
+	}
+	return false
+}
+
+// ListCredentialsResponse is the response for ListCredentials.
+type ListCredentialsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Credentials   []*Credential          `protobuf:"bytes,1,rep,name=credentials,proto3" json:"credentials,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCredentialsResponse) Reset() {
+	*x = ListCredentialsResponse{}
+	mi := &file_mail_proto_msgTypes[167]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCredentialsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCredentialsResponse) ProtoMessage() {}
+
+func (x *ListCredentialsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[167]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCredentialsResponse.ProtoReflect.Descriptor instead.
+func (*ListCredentialsResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{167}
+}
+
+func (x *ListCredentialsResponse) GetCredentials() []*Credential {
+	if x != nil {
+		return x.Credentials
+	}
+	return nil
+}
+
+// RevokeCredentialRequest is the request for RevokeCredential.
+type RevokeCredentialRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeCredentialRequest) Reset() {
+	*x = RevokeCredentialRequest{}
+	mi := &file_mail_proto_msgTypes[168]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeCredentialRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeCredentialRequest) ProtoMessage() {}
+
+func (x *RevokeCredentialRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[168]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeCredentialRequest.ProtoReflect.Descriptor instead.
+func (*RevokeCredentialRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{168}
+}
+
+func (x *RevokeCredentialRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// RevokeCredentialResponse is the response for RevokeCredential.
+type RevokeCredentialResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeCredentialResponse) Reset() {
+	*x = RevokeCredentialResponse{}
+	mi := &file_mail_proto_msgTypes[169]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeCredentialResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeCredentialResponse) ProtoMessage() {}
+
+func (x *RevokeCredentialResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[169]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeCredentialResponse.ProtoReflect.Descriptor instead.
+func (*RevokeCredentialResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{169}
+}
+
+// AgentLimit is a rate limit or storage quota. For a rate class (send,
+// publish, search or review), amount calls are allowed every
+// interval_seconds with up to burst at once. For body_bytes, amount is the
+// bytes of message body allowed a UTC day.
+type AgentLimit struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AgentId         int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // 0 for the default
+	AgentName       string                 `protobuf:"bytes,2,opt,name=agent_name,json=agentName,proto3" json:"agent_name,omitempty"`
+	Class           string                 `protobuf:"bytes,3,opt,name=class,proto3" json:"class,omitempty"`
+	Amount          int64                  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	IntervalSeconds int64                  `protobuf:"varint,5,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	Burst           int64                  `protobuf:"varint,6,opt,name=burst,proto3" json:"burst,omitempty"`
+	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Id              int64                  `protobuf:"varint,8,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AgentLimit) Reset() {
+	*x = AgentLimit{}
+	mi := &file_mail_proto_msgTypes[170]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentLimit) ProtoMessage() {}
+
+func (x *AgentLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[170]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentLimit.ProtoReflect.Descriptor instead.
+func (*AgentLimit) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{170}
+}
+
+func (x *AgentLimit) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *AgentLimit) GetAgentName() string {
+	if x != nil {
+		return x.AgentName
+	}
+	return ""
+}
+
+func (x *AgentLimit) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *AgentLimit) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *AgentLimit) GetIntervalSeconds() int64 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *AgentLimit) GetBurst() int64 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+func (x *AgentLimit) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *AgentLimit) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// SetLimitRequest is the request for SetLimit.
+type SetLimitRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AgentId         int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // 0 sets the default
+	Class           string                 `protobuf:"bytes,2,opt,name=class,proto3" json:"class,omitempty"`
+	Amount          int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	IntervalSeconds int64                  `protobuf:"varint,4,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"` // Ignored for body_bytes
+	Burst           int64                  `protobuf:"varint,5,opt,name=burst,proto3" json:"burst,omitempty"`                                            // 0 allows amount at once
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SetLimitRequest) Reset() {
+	*x = SetLimitRequest{}
+	mi := &file_mail_proto_msgTypes[171]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLimitRequest) ProtoMessage() {}
+
+func (x *SetLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[171]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLimitRequest.ProtoReflect.Descriptor instead.
+func (*SetLimitRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{171}
+}
+
+func (x *SetLimitRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *SetLimitRequest) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *SetLimitRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *SetLimitRequest) GetIntervalSeconds() int64 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *SetLimitRequest) GetBurst() int64 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+// SetLimitResponse is the response for SetLimit.
+type SetLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         *AgentLimit            `protobuf:"bytes,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLimitResponse) Reset() {
+	*x = SetLimitResponse{}
+	mi := &file_mail_proto_msgTypes[172]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLimitResponse) ProtoMessage() {}
+
+func (x *SetLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[172]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLimitResponse.ProtoReflect.Descriptor instead.
+func (*SetLimitResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{172}
+}
+
+func (x *SetLimitResponse) GetLimit() *AgentLimit {
+	if x != nil {
+		return x.Limit
+	}
+	return nil
+}
+
+// ClearLimitRequest is the request for ClearLimit.
+type ClearLimitRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // 0 clears the default
+	Class         string                 `protobuf:"bytes,2,opt,name=class,proto3" json:"class,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearLimitRequest) Reset() {
+	*x = ClearLimitRequest{}
+	mi := &file_mail_proto_msgTypes[173]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearLimitRequest) ProtoMessage() {}
+
+func (x *ClearLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[173]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearLimitRequest.ProtoReflect.Descriptor instead.
+func (*ClearLimitRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{173}
+}
+
+func (x *ClearLimitRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *ClearLimitRequest) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+// ClearLimitResponse is the response for ClearLimit.
+type ClearLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearLimitResponse) Reset() {
+	*x = ClearLimitResponse{}
+	mi := &file_mail_proto_msgTypes[174]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearLimitResponse) ProtoMessage() {}
+
+func (x *ClearLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[174]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearLimitResponse.ProtoReflect.Descriptor instead.
+func (*ClearLimitResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{174}
+}
+
+// ListLimitsRequest is the request for ListLimits.
+type ListLimitsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // 0 lists every limit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLimitsRequest) Reset() {
+	*x = ListLimitsRequest{}
+	mi := &file_mail_proto_msgTypes[175]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLimitsRequest) ProtoMessage() {}
+
+func (x *ListLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[175]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLimitsRequest.ProtoReflect.Descriptor instead.
+func (*ListLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{175}
+}
+
+func (x *ListLimitsRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+// ListLimitsResponse is the response for ListLimits.
+type ListLimitsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limits        []*AgentLimit          `protobuf:"bytes,1,rep,name=limits,proto3" json:"limits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLimitsResponse) Reset() {
+	*x = ListLimitsResponse{}
+	mi := &file_mail_proto_msgTypes[176]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLimitsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLimitsResponse) ProtoMessage() {}
+
+func (x *ListLimitsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[176]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLimitsResponse.ProtoReflect.Descriptor instead.
+func (*ListLimitsResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{176}
+}
+
+func (x *ListLimitsResponse) GetLimits() []*AgentLimit {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+// LimitUsage is an agent's standing against the limit on one class.
+type LimitUsage struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Class           string                 `protobuf:"bytes,1,opt,name=class,proto3" json:"class,omitempty"`
+	Limit           *AgentLimit            `protobuf:"bytes,2,opt,name=limit,proto3" json:"limit,omitempty"`     // Unset if the class is unlimited
+	Tokens          float64                `protobuf:"fixed64,3,opt,name=tokens,proto3" json:"tokens,omitempty"` // Calls allowed right now
+	Throttled       int64                  `protobuf:"varint,4,opt,name=throttled,proto3" json:"throttled,omitempty"`
+	LastThrottledAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_throttled_at,json=lastThrottledAt,proto3" json:"last_throttled_at,omitempty"`
+	UsedBytes       int64                  `protobuf:"varint,6,opt,name=used_bytes,json=usedBytes,proto3" json:"used_bytes,omitempty"` // Body bytes sent today, for body_bytes
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *LimitUsage) Reset() {
+	*x = LimitUsage{}
+	mi := &file_mail_proto_msgTypes[177]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LimitUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LimitUsage) ProtoMessage() {}
+
+func (x *LimitUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[177]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LimitUsage.ProtoReflect.Descriptor instead.
+func (*LimitUsage) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{177}
+}
+
+func (x *LimitUsage) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *LimitUsage) GetLimit() *AgentLimit {
+	if x != nil {
+		return x.Limit
+	}
+	return nil
+}
+
+func (x *LimitUsage) GetTokens() float64 {
+	if x != nil {
+		return x.Tokens
+	}
+	return 0
+}
+
+func (x *LimitUsage) GetThrottled() int64 {
+	if x != nil {
+		return x.Throttled
+	}
+	return 0
+}
+
+func (x *LimitUsage) GetLastThrottledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastThrottledAt
+	}
+	return nil
+}
+
+func (x *LimitUsage) GetUsedBytes() int64 {
+	if x != nil {
+		return x.UsedBytes
+	}
+	return 0
+}
+
+// GetUsageRequest is the request for GetUsage.
+type GetUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageRequest) Reset() {
+	*x = GetUsageRequest{}
+	mi := &file_mail_proto_msgTypes[178]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageRequest) ProtoMessage() {}
+
+func (x *GetUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[178]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{178}
+}
+
+func (x *GetUsageRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+// GetUsageResponse is the response for GetUsage.
+type GetUsageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Usage         []*LimitUsage          `protobuf:"bytes,1,rep,name=usage,proto3" json:"usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageResponse) Reset() {
+	*x = GetUsageResponse{}
+	mi := &file_mail_proto_msgTypes[179]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageResponse) ProtoMessage() {}
+
+func (x *GetUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[179]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetUsageResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{179}
+}
+
+func (x *GetUsageResponse) GetUsage() []*LimitUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+// HeartbeatRequest is the request for Heartbeat.
+// Either agent_id or agent_name must be provided. If both are given,
+// agent_id takes precedence.
+type HeartbeatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,proto3" json:"agent_id,omitempty"`
+	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,proto3" json:"session_id,omitempty"`
+	AgentName     string                 `protobuf:"bytes,3,opt,name=agent_name,proto3" json:"agent_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatRequest) Reset() {
+	*x = HeartbeatRequest{}
+	mi := &file_mail_proto_msgTypes[180]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatRequest) ProtoMessage() {}
+
+func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[180]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{180}
+}
+
+func (x *HeartbeatRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *HeartbeatRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *HeartbeatRequest) GetAgentName() string {
+	if x != nil {
+		return x.AgentName
+	}
+	return ""
+}
+
+// HeartbeatResponse is the response for Heartbeat.
+type HeartbeatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatResponse) Reset() {
+	*x = HeartbeatResponse{}
+	mi := &file_mail_proto_msgTypes[181]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatResponse) ProtoMessage() {}
+
+func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[181]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{181}
+}
+
+func (x *HeartbeatResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// SessionInfo represents a session.
+type SessionInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	AgentName     string                 `protobuf:"bytes,3,opt,name=agent_name,json=agentName,proto3" json:"agent_name,omitempty"`
+	Project       string                 `protobuf:"bytes,4,opt,name=project,proto3" json:"project,omitempty"`
+	Branch        string                 `protobuf:"bytes,5,opt,name=branch,proto3" json:"branch,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	EndedAt       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=ended_at,json=endedAt,proto3" json:"ended_at,omitempty"`
+	Status        SessionStatus          `protobuf:"varint,8,opt,name=status,proto3,enum=subtraterpc.SessionStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionInfo) Reset() {
+	*x = SessionInfo{}
+	mi := &file_mail_proto_msgTypes[182]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionInfo) ProtoMessage() {}
+
+func (x *SessionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[182]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionInfo.ProtoReflect.Descriptor instead.
+func (*SessionInfo) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{182}
+}
+
+func (x *SessionInfo) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SessionInfo) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *SessionInfo) GetAgentName() string {
+	if x != nil {
+		return x.AgentName
+	}
+	return ""
+}
+
+func (x *SessionInfo) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *SessionInfo) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+func (x *SessionInfo) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *SessionInfo) GetEndedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndedAt
+	}
+	return nil
+}
+
+func (x *SessionInfo) GetStatus() SessionStatus {
+	if x != nil {
+		return x.Status
+	}
+	return SessionStatus_SESSION_STATUS_UNSPECIFIED
+}
+
+// ListSessionsRequest is the request for ListSessions.
+type ListSessionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActiveOnly    bool                   `protobuf:"varint,1,opt,name=active_only,json=activeOnly,proto3" json:"active_only,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
+	mi := &file_mail_proto_msgTypes[183]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsRequest) ProtoMessage() {}
+
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[183]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{183}
+}
+
+func (x *ListSessionsRequest) GetActiveOnly() bool {
+	if x != nil {
+		return x.ActiveOnly
+	}
+	return false
+}
+
+func (x *ListSessionsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// ListSessionsResponse is the response for ListSessions.
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sessions      []*SessionInfo         `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	mi := &file_mail_proto_msgTypes[184]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[184]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{184}
+}
+
+func (x *ListSessionsResponse) GetSessions() []*SessionInfo {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+// GetSessionRequest is the request for GetSession.
+type GetSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     int64                  `protobuf:"varint,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSessionRequest) Reset() {
+	*x = GetSessionRequest{}
+	mi := &file_mail_proto_msgTypes[185]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSessionRequest) ProtoMessage() {}
+
+func (x *GetSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[185]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSessionRequest.ProtoReflect.Descriptor instead.
+func (*GetSessionRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{185}
+}
+
+func (x *GetSessionRequest) GetSessionId() int64 {
+	if x != nil {
+		return x.SessionId
+	}
+	return 0
+}
+
+// GetSessionResponse is the response for GetSession.
+type GetSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Session       *SessionInfo           `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSessionResponse) Reset() {
+	*x = GetSessionResponse{}
+	mi := &file_mail_proto_msgTypes[186]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSessionResponse) ProtoMessage() {}
+
+func (x *GetSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[186]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSessionResponse.ProtoReflect.Descriptor instead.
+func (*GetSessionResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{186}
+}
+
+func (x *GetSessionResponse) GetSession() *SessionInfo {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+// StartSessionRequest is the request for StartSession.
+type StartSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Project       string                 `protobuf:"bytes,2,opt,name=project,proto3" json:"project,omitempty"`
+	Branch        string                 `protobuf:"bytes,3,opt,name=branch,proto3" json:"branch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartSessionRequest) Reset() {
+	*x = StartSessionRequest{}
+	mi := &file_mail_proto_msgTypes[187]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartSessionRequest) ProtoMessage() {}
+
+func (x *StartSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[187]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartSessionRequest.ProtoReflect.Descriptor instead.
+func (*StartSessionRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{187}
+}
+
+func (x *StartSessionRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *StartSessionRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *StartSessionRequest) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+// StartSessionResponse is the response for StartSession.
+type StartSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Session       *SessionInfo           `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartSessionResponse) Reset() {
+	*x = StartSessionResponse{}
+	mi := &file_mail_proto_msgTypes[188]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartSessionResponse) ProtoMessage() {}
+
+func (x *StartSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[188]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartSessionResponse.ProtoReflect.Descriptor instead.
+func (*StartSessionResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{188}
+}
+
+func (x *StartSessionResponse) GetSession() *SessionInfo {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+// CompleteSessionRequest is the request for CompleteSession.
+type CompleteSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     int64                  `protobuf:"varint,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteSessionRequest) Reset() {
+	*x = CompleteSessionRequest{}
+	mi := &file_mail_proto_msgTypes[189]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteSessionRequest) ProtoMessage() {}
+
+func (x *CompleteSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[189]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteSessionRequest.ProtoReflect.Descriptor instead.
+func (*CompleteSessionRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{189}
+}
+
+func (x *CompleteSessionRequest) GetSessionId() int64 {
+	if x != nil {
+		return x.SessionId
+	}
+	return 0
+}
+
+// CompleteSessionResponse is the response for CompleteSession.
+type CompleteSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteSessionResponse) Reset() {
+	*x = CompleteSessionResponse{}
+	mi := &file_mail_proto_msgTypes[190]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteSessionResponse) ProtoMessage() {}
+
+func (x *CompleteSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[190]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteSessionResponse.ProtoReflect.Descriptor instead.
+func (*CompleteSessionResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{190}
+}
+
+func (x *CompleteSessionResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// ActivityInfo represents an activity entry.
+type ActivityInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	AgentName     string                 `protobuf:"bytes,3,opt,name=agent_name,json=agentName,proto3" json:"agent_name,omitempty"`
+	Type          ActivityType           `protobuf:"varint,4,opt,name=type,proto3,enum=subtraterpc.ActivityType" json:"type,omitempty"`
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	MetadataJson  string                 `protobuf:"bytes,7,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"` // JSON-encoded metadata
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActivityInfo) Reset() {
+	*x = ActivityInfo{}
+	mi := &file_mail_proto_msgTypes[191]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActivityInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivityInfo) ProtoMessage() {}
+
+func (x *ActivityInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[191]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivityInfo.ProtoReflect.Descriptor instead.
+func (*ActivityInfo) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{191}
+}
+
+func (x *ActivityInfo) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ActivityInfo) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *ActivityInfo) GetAgentName() string {
+	if x != nil {
+		return x.AgentName
+	}
+	return ""
+}
+
+func (x *ActivityInfo) GetType() ActivityType {
+	if x != nil {
+		return x.Type
+	}
+	return ActivityType_ACTIVITY_TYPE_UNSPECIFIED
+}
+
+func (x *ActivityInfo) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ActivityInfo) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ActivityInfo) GetMetadataJson() string {
+	if x != nil {
+		return x.MetadataJson
+	}
+	return ""
+}
+
+// ListActivitiesRequest is the request for ListActivities.
+type ListActivitiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`          // Filter by agent (0 = all)
+	Type          ActivityType           `protobuf:"varint,2,opt,name=type,proto3,enum=subtraterpc.ActivityType" json:"type,omitempty"` // Filter by type (UNSPECIFIED = all)
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActivitiesRequest) Reset() {
+	*x = ListActivitiesRequest{}
+	mi := &file_mail_proto_msgTypes[192]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActivitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActivitiesRequest) ProtoMessage() {}
+
+func (x *ListActivitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[192]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActivitiesRequest.ProtoReflect.Descriptor instead.
+func (*ListActivitiesRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{192}
+}
+
+func (x *ListActivitiesRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *ListActivitiesRequest) GetType() ActivityType {
+	if x != nil {
+		return x.Type
+	}
+	return ActivityType_ACTIVITY_TYPE_UNSPECIFIED
+}
+
+func (x *ListActivitiesRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListActivitiesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// ListActivitiesResponse is the response for ListActivities.
+type ListActivitiesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Activities    []*ActivityInfo        `protobuf:"bytes,1,rep,name=activities,proto3" json:"activities,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActivitiesResponse) Reset() {
+	*x = ListActivitiesResponse{}
+	mi := &file_mail_proto_msgTypes[193]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActivitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActivitiesResponse) ProtoMessage() {}
+
+func (x *ListActivitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[193]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActivitiesResponse.ProtoReflect.Descriptor instead.
+func (*ListActivitiesResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{193}
+}
+
+func (x *ListActivitiesResponse) GetActivities() []*ActivityInfo {
+	if x != nil {
+		return x.Activities
+	}
+	return nil
+}
+
+func (x *ListActivitiesResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListActivitiesResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListActivitiesResponse) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+// DashboardStats represents dashboard statistics.
+type DashboardStats struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ActiveAgents    int32                  `protobuf:"varint,1,opt,name=active_agents,json=activeAgents,proto3" json:"active_agents,omitempty"`
+	RunningSessions int32                  `protobuf:"varint,2,opt,name=running_sessions,json=runningSessions,proto3" json:"running_sessions,omitempty"`
+	PendingMessages int32                  `protobuf:"varint,3,opt,name=pending_messages,json=pendingMessages,proto3" json:"pending_messages,omitempty"`
+	CompletedToday  int32                  `protobuf:"varint,4,opt,name=completed_today,json=completedToday,proto3" json:"completed_today,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DashboardStats) Reset() {
+	*x = DashboardStats{}
+	mi := &file_mail_proto_msgTypes[194]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DashboardStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DashboardStats) ProtoMessage() {}
+
+func (x *DashboardStats) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[194]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DashboardStats.ProtoReflect.Descriptor instead.
+func (*DashboardStats) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{194}
+}
+
+func (x *DashboardStats) GetActiveAgents() int32 {
+	if x != nil {
+		return x.ActiveAgents
+	}
+	return 0
+}
+
+func (x *DashboardStats) GetRunningSessions() int32 {
+	if x != nil {
+		return x.RunningSessions
+	}
+	return 0
+}
+
+func (x *DashboardStats) GetPendingMessages() int32 {
+	if x != nil {
+		return x.PendingMessages
+	}
+	return 0
+}
+
+func (x *DashboardStats) GetCompletedToday() int32 {
+	if x != nil {
+		return x.CompletedToday
+	}
+	return 0
+}
+
+// GetDashboardStatsRequest is the request for GetDashboardStats.
+type GetDashboardStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDashboardStatsRequest) Reset() {
+	*x = GetDashboardStatsRequest{}
+	mi := &file_mail_proto_msgTypes[195]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDashboardStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDashboardStatsRequest) ProtoMessage() {}
+
+func (x *GetDashboardStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[195]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDashboardStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetDashboardStatsRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{195}
+}
+
+// GetDashboardStatsResponse is the response for GetDashboardStats.
+type GetDashboardStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         *DashboardStats        `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDashboardStatsResponse) Reset() {
+	*x = GetDashboardStatsResponse{}
+	mi := &file_mail_proto_msgTypes[196]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDashboardStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDashboardStatsResponse) ProtoMessage() {}
+
+func (x *GetDashboardStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[196]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDashboardStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetDashboardStatsResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{196}
+}
+
+func (x *GetDashboardStatsResponse) GetStats() *DashboardStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+// HealthCheckRequest is the request for HealthCheck.
+type HealthCheckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheckRequest) Reset() {
+	*x = HealthCheckRequest{}
+	mi := &file_mail_proto_msgTypes[197]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckRequest) ProtoMessage() {}
+
+func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[197]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckRequest.ProtoReflect.Descriptor instead.
+func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{197}
+}
+
+// HealthCheckResponse is the response for HealthCheck.
+type HealthCheckResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // "ok" or "error"
+	Time          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheckResponse) Reset() {
+	*x = HealthCheckResponse{}
+	mi := &file_mail_proto_msgTypes[198]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckResponse) ProtoMessage() {}
+
+func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[198]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckResponse.ProtoReflect.Descriptor instead.
+func (*HealthCheckResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{198}
+}
+
+func (x *HealthCheckResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *HealthCheckResponse) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+// BranchTarget specifies a full branch diff review.
+type BranchTarget struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// branch is the feature branch to review.
+	Branch string `protobuf:"bytes,1,opt,name=branch,proto3" json:"branch,omitempty"`
+	// base_branch is the branch to diff against (e.g., "main").
+	BaseBranch    string `protobuf:"bytes,2,opt,name=base_branch,json=baseBranch,proto3" json:"base_branch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BranchTarget) Reset() {
+	*x = BranchTarget{}
+	mi := &file_mail_proto_msgTypes[199]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BranchTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BranchTarget) ProtoMessage() {}
+
+func (x *BranchTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[199]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BranchTarget.ProtoReflect.Descriptor instead.
+func (*BranchTarget) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{199}
+}
+
+func (x *BranchTarget) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+func (x *BranchTarget) GetBaseBranch() string {
+	if x != nil {
+		return x.BaseBranch
+	}
+	return ""
+}
+
+// CommitTarget specifies a single commit review.
+type CommitTarget struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// sha is the commit SHA to review.
+	Sha string `protobuf:"bytes,1,opt,name=sha,proto3" json:"sha,omitempty"`
+	// branch is the branch name for display/naming purposes only.
+	Branch        string `protobuf:"bytes,2,opt,name=branch,proto3" json:"branch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitTarget) Reset() {
+	*x = CommitTarget{}
+	mi := &file_mail_proto_msgTypes[200]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitTarget) ProtoMessage() {}
+
+func (x *CommitTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[200]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitTarget.ProtoReflect.Descriptor instead.
+func (*CommitTarget) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{200}
+}
+
+func (x *CommitTarget) GetSha() string {
+	if x != nil {
+		return x.Sha
+	}
+	return ""
+}
+
+func (x *CommitTarget) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+// CommitRangeTarget specifies a range of commits to review.
+type CommitRangeTarget struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// start_sha is the first commit in the range (exclusive).
+	StartSha string `protobuf:"bytes,1,opt,name=start_sha,json=startSha,proto3" json:"start_sha,omitempty"`
+	// end_sha is the last commit in the range (inclusive).
+	EndSha string `protobuf:"bytes,2,opt,name=end_sha,json=endSha,proto3" json:"end_sha,omitempty"`
+	// branch is the branch name for display/naming purposes only.
+	Branch        string `protobuf:"bytes,3,opt,name=branch,proto3" json:"branch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitRangeTarget) Reset() {
+	*x = CommitRangeTarget{}
+	mi := &file_mail_proto_msgTypes[201]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitRangeTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitRangeTarget) ProtoMessage() {}
+
+func (x *CommitRangeTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[201]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitRangeTarget.ProtoReflect.Descriptor instead.
+func (*CommitRangeTarget) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{201}
+}
+
+func (x *CommitRangeTarget) GetStartSha() string {
+	if x != nil {
+		return x.StartSha
+	}
+	return ""
+}
+
+func (x *CommitRangeTarget) GetEndSha() string {
+	if x != nil {
+		return x.EndSha
+	}
+	return ""
+}
+
+func (x *CommitRangeTarget) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+// PRTarget specifies a pull request review.
+type PRTarget struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// number is the PR number.
+	Number int32 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	// branch is the PR's head branch.
+	Branch string `protobuf:"bytes,2,opt,name=branch,proto3" json:"branch,omitempty"`
+	// base_branch is the PR's base branch.
+	BaseBranch    string `protobuf:"bytes,3,opt,name=base_branch,json=baseBranch,proto3" json:"base_branch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PRTarget) Reset() {
+	*x = PRTarget{}
+	mi := &file_mail_proto_msgTypes[202]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PRTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PRTarget) ProtoMessage() {}
+
+func (x *PRTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[202]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PRTarget.ProtoReflect.Descriptor instead.
+func (*PRTarget) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{202}
+}
+
+func (x *PRTarget) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *PRTarget) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+func (x *PRTarget) GetBaseBranch() string {
+	if x != nil {
+		return x.BaseBranch
+	}
+	return ""
+}
+
+// CreateReviewRequest is the request for CreateReview.
+type CreateReviewRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// repo_path is the local path to the repository.
+	RepoPath string `protobuf:"bytes,4,opt,name=repo_path,json=repoPath,proto3" json:"repo_path,omitempty"`
+	// remote_url is the git remote URL.
+	RemoteUrl string `protobuf:"bytes,11,opt,name=remote_url,json=remoteUrl,proto3" json:"remote_url,omitempty"`
+	// review_type is the type of review: full, security, performance, architecture.
+	ReviewType string `protobuf:"bytes,6,opt,name=review_type,json=reviewType,proto3" json:"review_type,omitempty"`
+	// priority is the review priority: urgent, normal, low.
+	Priority string `protobuf:"bytes,7,opt,name=priority,proto3" json:"priority,omitempty"`
+	// reviewers is optional list of specific reviewers to assign.
+	Reviewers []string `protobuf:"bytes,8,rep,name=reviewers,proto3" json:"reviewers,omitempty"`
+	// description is an optional description of what to review.
+	Description string `protobuf:"bytes,9,opt,name=description,proto3" json:"description,omitempty"`
+	// requester_id is the agent ID requesting the review.
+	RequesterId int64 `protobuf:"varint,10,opt,name=requester_id,json=requesterId,proto3" json:"requester_id,omitempty"`
+	// target specifies what to review. Exactly one must be set.
+	//
+	// Types that are valid to be assigned to Target:
+	//
+	//	*CreateReviewRequest_BranchTarget
+	//	*CreateReviewRequest_CommitTarget
+	//	*CreateReviewRequest_CommitRangeTarget
+	//	*CreateReviewRequest_PrTarget
+	Target isCreateReviewRequest_Target `protobuf_oneof:"target"`
+	// Deprecated fields kept for backward compatibility.
+	// Use target oneof instead.
+	//
+	// Deprecated: Marked as deprecated in mail.proto.
+	Branch string `protobuf:"bytes,1,opt,name=branch,proto3" json:"branch,omitempty"`
+	// Deprecated: Marked as deprecated in mail.proto.
+	BaseBranch string `protobuf:"bytes,2,opt,name=base_branch,json=baseBranch,proto3" json:"base_branch,omitempty"`
+	// Deprecated: Marked as deprecated in mail.proto.
+	CommitSha string `protobuf:"bytes,3,opt,name=commit_sha,json=commitSha,proto3" json:"commit_sha,omitempty"`
+	// Deprecated: Marked as deprecated in mail.proto.
+	PrNumber      int32 `protobuf:"varint,5,opt,name=pr_number,json=prNumber,proto3" json:"pr_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReviewRequest) Reset() {
+	*x = CreateReviewRequest{}
+	mi := &file_mail_proto_msgTypes[203]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReviewRequest) ProtoMessage() {}
+
+func (x *CreateReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[203]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReviewRequest.ProtoReflect.Descriptor instead.
+func (*CreateReviewRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{203}
+}
+
+func (x *CreateReviewRequest) GetRepoPath() string {
+	if x != nil {
+		return x.RepoPath
+	}
+	return ""
+}
+
+func (x *CreateReviewRequest) GetRemoteUrl() string {
+	if x != nil {
+		return x.RemoteUrl
+	}
+	return ""
+}
+
+func (x *CreateReviewRequest) GetReviewType() string {
+	if x != nil {
+		return x.ReviewType
+	}
+	return ""
+}
+
+func (x *CreateReviewRequest) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *CreateReviewRequest) GetReviewers() []string {
+	if x != nil {
+		return x.Reviewers
+	}
+	return nil
+}
+
+func (x *CreateReviewRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateReviewRequest) GetRequesterId() int64 {
+	if x != nil {
+		return x.RequesterId
+	}
+	return 0
+}
+
+func (x *CreateReviewRequest) GetTarget() isCreateReviewRequest_Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *CreateReviewRequest) GetBranchTarget() *BranchTarget {
+	if x != nil {
+		if x, ok := x.Target.(*CreateReviewRequest_BranchTarget); ok {
+			return x.BranchTarget
+		}
+	}
+	return nil
+}
+
+func (x *CreateReviewRequest) GetCommitTarget() *CommitTarget {
+	if x != nil {
+		if x, ok := x.Target.(*CreateReviewRequest_CommitTarget); ok {
+			return x.CommitTarget
+		}
+	}
+	return nil
+}
+
+func (x *CreateReviewRequest) GetCommitRangeTarget() *CommitRangeTarget {
+	if x != nil {
+		if x, ok := x.Target.(*CreateReviewRequest_CommitRangeTarget); ok {
+			return x.CommitRangeTarget
+		}
+	}
+	return nil
+}
+
+func (x *CreateReviewRequest) GetPrTarget() *PRTarget {
+	if x != nil {
+		if x, ok := x.Target.(*CreateReviewRequest_PrTarget); ok {
+			return x.PrTarget
+		}
+	}
+	return nil
+}
+
+// Deprecated: Marked as deprecated in mail.proto.
+func (x *CreateReviewRequest) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in mail.proto.
+func (x *CreateReviewRequest) GetBaseBranch() string {
+	if x != nil {
+		return x.BaseBranch
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in mail.proto.
+func (x *CreateReviewRequest) GetCommitSha() string {
+	if x != nil {
+		return x.CommitSha
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in mail.proto.
+func (x *CreateReviewRequest) GetPrNumber() int32 {
+	if x != nil {
+		return x.PrNumber
+	}
+	return 0
+}
+
+type isCreateReviewRequest_Target interface {
+	isCreateReviewRequest_Target()
+}
+
+type CreateReviewRequest_BranchTarget struct {
+	// branch_target reviews a full branch diff against base.
+	BranchTarget *BranchTarget `protobuf:"bytes,20,opt,name=branch_target,json=branchTarget,proto3,oneof"`
+}
+
+type CreateReviewRequest_CommitTarget struct {
+	// commit_target reviews a single commit.
+	CommitTarget *CommitTarget `protobuf:"bytes,21,opt,name=commit_target,json=commitTarget,proto3,oneof"`
+}
+
+type CreateReviewRequest_CommitRangeTarget struct {
+	// commit_range_target reviews a range of commits.
+	CommitRangeTarget *CommitRangeTarget `protobuf:"bytes,22,opt,name=commit_range_target,json=commitRangeTarget,proto3,oneof"`
+}
+
+type CreateReviewRequest_PrTarget struct {
+	// pr_target reviews a pull request.
+	PrTarget *PRTarget `protobuf:"bytes,23,opt,name=pr_target,json=prTarget,proto3,oneof"`
+}
+
+func (*CreateReviewRequest_BranchTarget) isCreateReviewRequest_Target() {}
+
+func (*CreateReviewRequest_CommitTarget) isCreateReviewRequest_Target() {}
+
+func (*CreateReviewRequest_CommitRangeTarget) isCreateReviewRequest_Target() {}
+
+func (*CreateReviewRequest_PrTarget) isCreateReviewRequest_Target() {}
+
+// CreateReviewResponse is the response for CreateReview and ResubmitReview.
+type CreateReviewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReviewResponse) Reset() {
+	*x = CreateReviewResponse{}
+	mi := &file_mail_proto_msgTypes[204]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReviewResponse) ProtoMessage() {}
+
+func (x *CreateReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[204]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReviewResponse.ProtoReflect.Descriptor instead.
+func (*CreateReviewResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{204}
+}
+
+func (x *CreateReviewResponse) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *CreateReviewResponse) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *CreateReviewResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *CreateReviewResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// ListReviewsProtoRequest is the request for ListReviews.
+type ListReviewsProtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`                                 // Optional state filter
+	RequesterId   int64                  `protobuf:"varint,2,opt,name=requester_id,json=requesterId,proto3" json:"requester_id,omitempty"` // Optional requester filter
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsProtoRequest) Reset() {
+	*x = ListReviewsProtoRequest{}
+	mi := &file_mail_proto_msgTypes[205]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsProtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsProtoRequest) ProtoMessage() {}
+
+func (x *ListReviewsProtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[205]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsProtoRequest.ProtoReflect.Descriptor instead.
+func (*ListReviewsProtoRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{205}
+}
+
+func (x *ListReviewsProtoRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ListReviewsProtoRequest) GetRequesterId() int64 {
+	if x != nil {
+		return x.RequesterId
+	}
+	return 0
+}
+
+func (x *ListReviewsProtoRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListReviewsProtoRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// ListReviewsProtoResponse is the response for ListReviews.
+type ListReviewsProtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reviews       []*ReviewSummaryProto  `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsProtoResponse) Reset() {
+	*x = ListReviewsProtoResponse{}
+	mi := &file_mail_proto_msgTypes[206]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsProtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsProtoResponse) ProtoMessage() {}
+
+func (x *ListReviewsProtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[206]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsProtoResponse.ProtoReflect.Descriptor instead.
+func (*ListReviewsProtoResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{206}
+}
+
+func (x *ListReviewsProtoResponse) GetReviews() []*ReviewSummaryProto {
+	if x != nil {
+		return x.Reviews
+	}
+	return nil
+}
+
+// ReviewSummaryProto represents a review summary in proto format.
+type ReviewSummaryProto struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	ThreadId      string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	RequesterId   int64                  `protobuf:"varint,3,opt,name=requester_id,json=requesterId,proto3" json:"requester_id,omitempty"`
+	Branch        string                 `protobuf:"bytes,4,opt,name=branch,proto3" json:"branch,omitempty"`
+	State         string                 `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+	ReviewType    string                 `protobuf:"bytes,6,opt,name=review_type,json=reviewType,proto3" json:"review_type,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewSummaryProto) Reset() {
+	*x = ReviewSummaryProto{}
+	mi := &file_mail_proto_msgTypes[207]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewSummaryProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewSummaryProto) ProtoMessage() {}
+
+func (x *ReviewSummaryProto) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[207]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewSummaryProto.ProtoReflect.Descriptor instead.
+func (*ReviewSummaryProto) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{207}
+}
+
+func (x *ReviewSummaryProto) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *ReviewSummaryProto) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *ReviewSummaryProto) GetRequesterId() int64 {
+	if x != nil {
+		return x.RequesterId
+	}
+	return 0
+}
+
+func (x *ReviewSummaryProto) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+func (x *ReviewSummaryProto) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ReviewSummaryProto) GetReviewType() string {
+	if x != nil {
+		return x.ReviewType
+	}
+	return ""
+}
+
+func (x *ReviewSummaryProto) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+// GetReviewProtoRequest is the request for GetReview.
+type GetReviewProtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReviewProtoRequest) Reset() {
+	*x = GetReviewProtoRequest{}
+	mi := &file_mail_proto_msgTypes[208]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReviewProtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReviewProtoRequest) ProtoMessage() {}
+
+func (x *GetReviewProtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[208]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReviewProtoRequest.ProtoReflect.Descriptor instead.
+func (*GetReviewProtoRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{208}
+}
+
+func (x *GetReviewProtoRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+// ReviewDetailResponse is the response for GetReview.
+type ReviewDetailResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId   string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	ThreadId   string                 `protobuf:"bytes,2,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	State      string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	Branch     string                 `protobuf:"bytes,4,opt,name=branch,proto3" json:"branch,omitempty"`
+	BaseBranch string                 `protobuf:"bytes,5,opt,name=base_branch,json=baseBranch,proto3" json:"base_branch,omitempty"`
+	ReviewType string                 `protobuf:"bytes,6,opt,name=review_type,json=reviewType,proto3" json:"review_type,omitempty"`
+	Iterations int32                  `protobuf:"varint,7,opt,name=iterations,proto3" json:"iterations,omitempty"`
+	OpenIssues int64                  `protobuf:"varint,8,opt,name=open_issues,json=openIssues,proto3" json:"open_issues,omitempty"`
+	Error      string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	// Iteration details for displaying review history.
+	IterationDetails []*ReviewIterationProto `protobuf:"bytes,10,rep,name=iteration_details,json=iterationDetails,proto3" json:"iteration_details,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ReviewDetailResponse) Reset() {
+	*x = ReviewDetailResponse{}
+	mi := &file_mail_proto_msgTypes[209]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewDetailResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewDetailResponse) ProtoMessage() {}
+
+func (x *ReviewDetailResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[209]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewDetailResponse.ProtoReflect.Descriptor instead.
+func (*ReviewDetailResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{209}
+}
+
+func (x *ReviewDetailResponse) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *ReviewDetailResponse) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *ReviewDetailResponse) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ReviewDetailResponse) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+func (x *ReviewDetailResponse) GetBaseBranch() string {
+	if x != nil {
+		return x.BaseBranch
+	}
+	return ""
+}
+
+func (x *ReviewDetailResponse) GetReviewType() string {
+	if x != nil {
+		return x.ReviewType
+	}
+	return ""
+}
+
+func (x *ReviewDetailResponse) GetIterations() int32 {
+	if x != nil {
+		return x.Iterations
+	}
+	return 0
+}
+
+func (x *ReviewDetailResponse) GetOpenIssues() int64 {
+	if x != nil {
+		return x.OpenIssues
+	}
+	return 0
+}
+
+func (x *ReviewDetailResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ReviewDetailResponse) GetIterationDetails() []*ReviewIterationProto {
+	if x != nil {
+		return x.IterationDetails
+	}
+	return nil
+}
+
+// ReviewIterationProto represents a single review iteration with results.
+type ReviewIterationProto struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IterationNum  int32                  `protobuf:"varint,1,opt,name=iteration_num,json=iterationNum,proto3" json:"iteration_num,omitempty"`
+	ReviewerId    string                 `protobuf:"bytes,2,opt,name=reviewer_id,json=reviewerId,proto3" json:"reviewer_id,omitempty"`
+	Decision      string                 `protobuf:"bytes,3,opt,name=decision,proto3" json:"decision,omitempty"`
+	Summary       string                 `protobuf:"bytes,4,opt,name=summary,proto3" json:"summary,omitempty"`
+	FilesReviewed int32                  `protobuf:"varint,5,opt,name=files_reviewed,json=filesReviewed,proto3" json:"files_reviewed,omitempty"`
+	LinesAnalyzed int32                  `protobuf:"varint,6,opt,name=lines_analyzed,json=linesAnalyzed,proto3" json:"lines_analyzed,omitempty"`
+	DurationMs    int64                  `protobuf:"varint,7,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	CostUsd       float64                `protobuf:"fixed64,8,opt,name=cost_usd,json=costUsd,proto3" json:"cost_usd,omitempty"`
+	StartedAt     int64                  `protobuf:"varint,9,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt   int64                  `protobuf:"varint,10,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewIterationProto) Reset() {
+	*x = ReviewIterationProto{}
+	mi := &file_mail_proto_msgTypes[210]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewIterationProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewIterationProto) ProtoMessage() {}
+
+func (x *ReviewIterationProto) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[210]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewIterationProto.ProtoReflect.Descriptor instead.
+func (*ReviewIterationProto) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{210}
+}
+
+func (x *ReviewIterationProto) GetIterationNum() int32 {
+	if x != nil {
+		return x.IterationNum
+	}
+	return 0
+}
+
+func (x *ReviewIterationProto) GetReviewerId() string {
+	if x != nil {
+		return x.ReviewerId
+	}
+	return ""
+}
+
+func (x *ReviewIterationProto) GetDecision() string {
+	if x != nil {
+		return x.Decision
+	}
+	return ""
+}
+
+func (x *ReviewIterationProto) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *ReviewIterationProto) GetFilesReviewed() int32 {
+	if x != nil {
+		return x.FilesReviewed
+	}
+	return 0
+}
+
+func (x *ReviewIterationProto) GetLinesAnalyzed() int32 {
+	if x != nil {
+		return x.LinesAnalyzed
+	}
+	return 0
+}
+
+func (x *ReviewIterationProto) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *ReviewIterationProto) GetCostUsd() float64 {
+	if x != nil {
+		return x.CostUsd
+	}
+	return 0
+}
+
+func (x *ReviewIterationProto) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *ReviewIterationProto) GetCompletedAt() int64 {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return 0
+}
+
+// ResubmitReviewRequest is the request for ResubmitReview.
+type ResubmitReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	CommitSha     string                 `protobuf:"bytes,2,opt,name=commit_sha,json=commitSha,proto3" json:"commit_sha,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResubmitReviewRequest) Reset() {
+	*x = ResubmitReviewRequest{}
+	mi := &file_mail_proto_msgTypes[211]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResubmitReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResubmitReviewRequest) ProtoMessage() {}
+
+func (x *ResubmitReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[211]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResubmitReviewRequest.ProtoReflect.Descriptor instead.
+func (*ResubmitReviewRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{211}
+}
+
+func (x *ResubmitReviewRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *ResubmitReviewRequest) GetCommitSha() string {
+	if x != nil {
+		return x.CommitSha
+	}
+	return ""
+}
+
+// CancelReviewProtoRequest is the request for CancelReview.
+type CancelReviewProtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelReviewProtoRequest) Reset() {
+	*x = CancelReviewProtoRequest{}
+	mi := &file_mail_proto_msgTypes[212]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelReviewProtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelReviewProtoRequest) ProtoMessage() {}
+
+func (x *CancelReviewProtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[212]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelReviewProtoRequest.ProtoReflect.Descriptor instead.
+func (*CancelReviewProtoRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{212}
+}
+
+func (x *CancelReviewProtoRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *CancelReviewProtoRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// CancelReviewProtoResponse is the response for CancelReview.
+type CancelReviewProtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelReviewProtoResponse) Reset() {
+	*x = CancelReviewProtoResponse{}
+	mi := &file_mail_proto_msgTypes[213]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelReviewProtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelReviewProtoResponse) ProtoMessage() {}
+
+func (x *CancelReviewProtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[213]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelReviewProtoResponse.ProtoReflect.Descriptor instead.
+func (*CancelReviewProtoResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{213}
+}
+
+func (x *CancelReviewProtoResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// DeleteReviewProtoRequest is the request for DeleteReview.
+type DeleteReviewProtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReviewProtoRequest) Reset() {
+	*x = DeleteReviewProtoRequest{}
+	mi := &file_mail_proto_msgTypes[214]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReviewProtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReviewProtoRequest) ProtoMessage() {}
+
+func (x *DeleteReviewProtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[214]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReviewProtoRequest.ProtoReflect.Descriptor instead.
+func (*DeleteReviewProtoRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{214}
+}
+
+func (x *DeleteReviewProtoRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+// DeleteReviewProtoResponse is the response for DeleteReview.
+type DeleteReviewProtoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReviewProtoResponse) Reset() {
+	*x = DeleteReviewProtoResponse{}
+	mi := &file_mail_proto_msgTypes[215]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReviewProtoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReviewProtoResponse) ProtoMessage() {}
+
+func (x *DeleteReviewProtoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[215]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReviewProtoResponse.ProtoReflect.Descriptor instead.
+func (*DeleteReviewProtoResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{215}
+}
+
+func (x *DeleteReviewProtoResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// ListReviewIssuesRequest is the request for ListReviewIssues.
+type ListReviewIssuesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewIssuesRequest) Reset() {
+	*x = ListReviewIssuesRequest{}
+	mi := &file_mail_proto_msgTypes[216]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewIssuesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewIssuesRequest) ProtoMessage() {}
+
+func (x *ListReviewIssuesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[216]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewIssuesRequest.ProtoReflect.Descriptor instead.
+func (*ListReviewIssuesRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{216}
+}
+
+func (x *ListReviewIssuesRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+// ListReviewIssuesResponse is the response for ListReviewIssues.
+type ListReviewIssuesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Issues        []*ReviewIssueProto    `protobuf:"bytes,1,rep,name=issues,proto3" json:"issues,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewIssuesResponse) Reset() {
+	*x = ListReviewIssuesResponse{}
+	mi := &file_mail_proto_msgTypes[217]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewIssuesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewIssuesResponse) ProtoMessage() {}
+
+func (x *ListReviewIssuesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[217]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewIssuesResponse.ProtoReflect.Descriptor instead.
+func (*ListReviewIssuesResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{217}
+}
+
+func (x *ListReviewIssuesResponse) GetIssues() []*ReviewIssueProto {
+	if x != nil {
+		return x.Issues
+	}
+	return nil
+}
+
+// ReviewIssueProto represents a review issue in proto format.
+type ReviewIssueProto struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ReviewId      string                 `protobuf:"bytes,2,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	IterationNum  int32                  `protobuf:"varint,3,opt,name=iteration_num,json=iterationNum,proto3" json:"iteration_num,omitempty"`
+	IssueType     string                 `protobuf:"bytes,4,opt,name=issue_type,json=issueType,proto3" json:"issue_type,omitempty"`
+	Severity      string                 `protobuf:"bytes,5,opt,name=severity,proto3" json:"severity,omitempty"`
+	FilePath      string                 `protobuf:"bytes,6,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	LineStart     int32                  `protobuf:"varint,7,opt,name=line_start,json=lineStart,proto3" json:"line_start,omitempty"`
+	LineEnd       int32                  `protobuf:"varint,8,opt,name=line_end,json=lineEnd,proto3" json:"line_end,omitempty"`
+	Title         string                 `protobuf:"bytes,9,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,10,opt,name=description,proto3" json:"description,omitempty"`
+	CodeSnippet   string                 `protobuf:"bytes,11,opt,name=code_snippet,json=codeSnippet,proto3" json:"code_snippet,omitempty"`
+	Suggestion    string                 `protobuf:"bytes,12,opt,name=suggestion,proto3" json:"suggestion,omitempty"`
+	ClaudeMdRef   string                 `protobuf:"bytes,13,opt,name=claude_md_ref,json=claudeMdRef,proto3" json:"claude_md_ref,omitempty"`
+	Status        string                 `protobuf:"bytes,14,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewIssueProto) Reset() {
+	*x = ReviewIssueProto{}
+	mi := &file_mail_proto_msgTypes[218]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewIssueProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewIssueProto) ProtoMessage() {}
+
+func (x *ReviewIssueProto) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[218]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewIssueProto.ProtoReflect.Descriptor instead.
+func (*ReviewIssueProto) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{218}
+}
+
+func (x *ReviewIssueProto) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReviewIssueProto) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *ReviewIssueProto) GetIterationNum() int32 {
+	if x != nil {
+		return x.IterationNum
+	}
+	return 0
+}
+
+func (x *ReviewIssueProto) GetIssueType() string {
+	if x != nil {
+		return x.IssueType
+	}
+	return ""
+}
+
+func (x *ReviewIssueProto) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *ReviewIssueProto) GetFilePath() string {
+	if x != nil {
+		return x.FilePath
+	}
+	return ""
+}
+
+func (x *ReviewIssueProto) GetLineStart() int32 {
+	if x != nil {
+		return x.LineStart
+	}
+	return 0
+}
+
+func (x *ReviewIssueProto) GetLineEnd() int32 {
+	if x != nil {
+		return x.LineEnd
+	}
+	return 0
+}
+
+func (x *ReviewIssueProto) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *ReviewIssueProto) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ReviewIssueProto) GetCodeSnippet() string {
+	if x != nil {
+		return x.CodeSnippet
+	}
+	return ""
+}
+
+func (x *ReviewIssueProto) GetSuggestion() string {
+	if x != nil {
+		return x.Suggestion
+	}
+	return ""
+}
+
+func (x *ReviewIssueProto) GetClaudeMdRef() string {
+	if x != nil {
+		return x.ClaudeMdRef
+	}
+	return ""
+}
+
+func (x *ReviewIssueProto) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// UpdateIssueStatusRequest is the request for UpdateIssueStatus.
+type UpdateIssueStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	IssueId       int64                  `protobuf:"varint,2,opt,name=issue_id,json=issueId,proto3" json:"issue_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // open, fixed, wont_fix, duplicate
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateIssueStatusRequest) Reset() {
+	*x = UpdateIssueStatusRequest{}
+	mi := &file_mail_proto_msgTypes[219]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateIssueStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateIssueStatusRequest) ProtoMessage() {}
+
+func (x *UpdateIssueStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[219]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateIssueStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateIssueStatusRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{219}
+}
+
+func (x *UpdateIssueStatusRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *UpdateIssueStatusRequest) GetIssueId() int64 {
+	if x != nil {
+		return x.IssueId
+	}
+	return 0
+}
+
+func (x *UpdateIssueStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// UpdateIssueStatusResponse is the response for UpdateIssueStatus.
+type UpdateIssueStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateIssueStatusResponse) Reset() {
+	*x = UpdateIssueStatusResponse{}
+	mi := &file_mail_proto_msgTypes[220]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateIssueStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateIssueStatusResponse) ProtoMessage() {}
+
+func (x *UpdateIssueStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[220]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateIssueStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateIssueStatusResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{220}
+}
+
+func (x *UpdateIssueStatusResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// GetReviewDiffRequest is the request for GetReviewDiff.
+type GetReviewDiffRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReviewDiffRequest) Reset() {
+	*x = GetReviewDiffRequest{}
+	mi := &file_mail_proto_msgTypes[221]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReviewDiffRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReviewDiffRequest) ProtoMessage() {}
+
+func (x *GetReviewDiffRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[221]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReviewDiffRequest.ProtoReflect.Descriptor instead.
+func (*GetReviewDiffRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{221}
+}
+
+func (x *GetReviewDiffRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+// GetReviewDiffResponse is the response for GetReviewDiff.
+type GetReviewDiffResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The raw unified diff output from git diff.
+	Patch string `protobuf:"bytes,1,opt,name=patch,proto3" json:"patch,omitempty"`
+	// The git command that was run to produce the diff.
+	Command       string `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
+	Error         string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReviewDiffResponse) Reset() {
+	*x = GetReviewDiffResponse{}
+	mi := &file_mail_proto_msgTypes[222]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReviewDiffResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReviewDiffResponse) ProtoMessage() {}
+
+func (x *GetReviewDiffResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[222]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReviewDiffResponse.ProtoReflect.Descriptor instead.
+func (*GetReviewDiffResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{222}
+}
+
+func (x *GetReviewDiffResponse) GetPatch() string {
+	if x != nil {
+		return x.Patch
+	}
+	return ""
+}
+
+func (x *GetReviewDiffResponse) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *GetReviewDiffResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// TaskListProto represents a registered task list.
+type TaskListProto struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ListId        string                 `protobuf:"bytes,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	WatchPath     string                 `protobuf:"bytes,4,opt,name=watch_path,json=watchPath,proto3" json:"watch_path,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastSyncedAt  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_synced_at,json=lastSyncedAt,proto3" json:"last_synced_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskListProto) Reset() {
+	*x = TaskListProto{}
+	mi := &file_mail_proto_msgTypes[223]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskListProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskListProto) ProtoMessage() {}
+
+func (x *TaskListProto) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[223]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskListProto.ProtoReflect.Descriptor instead.
+func (*TaskListProto) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{223}
+}
+
+func (x *TaskListProto) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TaskListProto) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *TaskListProto) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *TaskListProto) GetWatchPath() string {
+	if x != nil {
+		return x.WatchPath
+	}
+	return ""
+}
+
+func (x *TaskListProto) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *TaskListProto) GetLastSyncedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSyncedAt
+	}
+	return nil
+}
+
+// TaskProto represents a Claude Code task.
+type TaskProto struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	ListId        string                 `protobuf:"bytes,3,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	ClaudeTaskId  string                 `protobuf:"bytes,4,opt,name=claude_task_id,json=claudeTaskId,proto3" json:"claude_task_id,omitempty"`
+	Subject       string                 `protobuf:"bytes,5,opt,name=subject,proto3" json:"subject,omitempty"`
+	Description   string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	ActiveForm    string                 `protobuf:"bytes,7,opt,name=active_form,json=activeForm,proto3" json:"active_form,omitempty"`
+	MetadataJson  string                 `protobuf:"bytes,8,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"`
+	Status        TaskStatus             `protobuf:"varint,9,opt,name=status,proto3,enum=subtraterpc.TaskStatus" json:"status,omitempty"`
+	Owner         string                 `protobuf:"bytes,10,opt,name=owner,proto3" json:"owner,omitempty"`
+	BlockedBy     []string               `protobuf:"bytes,11,rep,name=blocked_by,json=blockedBy,proto3" json:"blocked_by,omitempty"`
+	Blocks        []string               `protobuf:"bytes,12,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskProto) Reset() {
+	*x = TaskProto{}
+	mi := &file_mail_proto_msgTypes[224]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskProto) ProtoMessage() {}
+
+func (x *TaskProto) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[224]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskProto.ProtoReflect.Descriptor instead.
+func (*TaskProto) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{224}
+}
+
+func (x *TaskProto) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TaskProto) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *TaskProto) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *TaskProto) GetClaudeTaskId() string {
+	if x != nil {
+		return x.ClaudeTaskId
+	}
+	return ""
+}
+
+func (x *TaskProto) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *TaskProto) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TaskProto) GetActiveForm() string {
+	if x != nil {
+		return x.ActiveForm
+	}
+	return ""
+}
+
+func (x *TaskProto) GetMetadataJson() string {
+	if x != nil {
+		return x.MetadataJson
+	}
+	return ""
+}
+
+func (x *TaskProto) GetStatus() TaskStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TaskStatus_TASK_STATUS_UNSPECIFIED
+}
+
+func (x *TaskProto) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *TaskProto) GetBlockedBy() []string {
+	if x != nil {
+		return x.BlockedBy
+	}
+	return nil
+}
+
+func (x *TaskProto) GetBlocks() []string {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+func (x *TaskProto) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *TaskProto) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *TaskProto) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *TaskProto) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+// TaskStatsProto contains aggregate task statistics.
+type TaskStatsProto struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PendingCount    int64                  `protobuf:"varint,1,opt,name=pending_count,json=pendingCount,proto3" json:"pending_count,omitempty"`
+	InProgressCount int64                  `protobuf:"varint,2,opt,name=in_progress_count,json=inProgressCount,proto3" json:"in_progress_count,omitempty"`
+	CompletedCount  int64                  `protobuf:"varint,3,opt,name=completed_count,json=completedCount,proto3" json:"completed_count,omitempty"`
+	BlockedCount    int64                  `protobuf:"varint,4,opt,name=blocked_count,json=blockedCount,proto3" json:"blocked_count,omitempty"`
+	AvailableCount  int64                  `protobuf:"varint,5,opt,name=available_count,json=availableCount,proto3" json:"available_count,omitempty"`
+	CompletedToday  int64                  `protobuf:"varint,6,opt,name=completed_today,json=completedToday,proto3" json:"completed_today,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TaskStatsProto) Reset() {
+	*x = TaskStatsProto{}
+	mi := &file_mail_proto_msgTypes[225]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskStatsProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskStatsProto) ProtoMessage() {}
+
+func (x *TaskStatsProto) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[225]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskStatsProto.ProtoReflect.Descriptor instead.
+func (*TaskStatsProto) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{225}
+}
+
+func (x *TaskStatsProto) GetPendingCount() int64 {
+	if x != nil {
+		return x.PendingCount
+	}
+	return 0
+}
+
+func (x *TaskStatsProto) GetInProgressCount() int64 {
+	if x != nil {
+		return x.InProgressCount
+	}
+	return 0
+}
+
+func (x *TaskStatsProto) GetCompletedCount() int64 {
+	if x != nil {
+		return x.CompletedCount
+	}
+	return 0
+}
+
+func (x *TaskStatsProto) GetBlockedCount() int64 {
+	if x != nil {
+		return x.BlockedCount
+	}
+	return 0
+}
+
+func (x *TaskStatsProto) GetAvailableCount() int64 {
+	if x != nil {
+		return x.AvailableCount
+	}
+	return 0
+}
+
+func (x *TaskStatsProto) GetCompletedToday() int64 {
+	if x != nil {
+		return x.CompletedToday
+	}
+	return 0
+}
+
+// AgentTaskStatsProto contains task statistics for a specific agent.
+type AgentTaskStatsProto struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AgentId         int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	AgentName       string                 `protobuf:"bytes,2,opt,name=agent_name,json=agentName,proto3" json:"agent_name,omitempty"`
+	PendingCount    int64                  `protobuf:"varint,3,opt,name=pending_count,json=pendingCount,proto3" json:"pending_count,omitempty"`
+	InProgressCount int64                  `protobuf:"varint,4,opt,name=in_progress_count,json=inProgressCount,proto3" json:"in_progress_count,omitempty"`
+	BlockedCount    int64                  `protobuf:"varint,5,opt,name=blocked_count,json=blockedCount,proto3" json:"blocked_count,omitempty"`
+	CompletedToday  int64                  `protobuf:"varint,6,opt,name=completed_today,json=completedToday,proto3" json:"completed_today,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AgentTaskStatsProto) Reset() {
+	*x = AgentTaskStatsProto{}
+	mi := &file_mail_proto_msgTypes[226]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentTaskStatsProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentTaskStatsProto) ProtoMessage() {}
+
+func (x *AgentTaskStatsProto) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[226]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentTaskStatsProto.ProtoReflect.Descriptor instead.
+func (*AgentTaskStatsProto) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{226}
+}
+
+func (x *AgentTaskStatsProto) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *AgentTaskStatsProto) GetAgentName() string {
+	if x != nil {
+		return x.AgentName
+	}
+	return ""
+}
+
+func (x *AgentTaskStatsProto) GetPendingCount() int64 {
+	if x != nil {
+		return x.PendingCount
+	}
+	return 0
+}
+
+func (x *AgentTaskStatsProto) GetInProgressCount() int64 {
+	if x != nil {
+		return x.InProgressCount
+	}
+	return 0
+}
+
+func (x *AgentTaskStatsProto) GetBlockedCount() int64 {
+	if x != nil {
+		return x.BlockedCount
+	}
+	return 0
+}
+
+func (x *AgentTaskStatsProto) GetCompletedToday() int64 {
+	if x != nil {
+		return x.CompletedToday
+	}
+	return 0
+}
+
+// RegisterTaskListRequest is the request for RegisterTaskList.
+type RegisterTaskListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	WatchPath     string                 `protobuf:"bytes,3,opt,name=watch_path,json=watchPath,proto3" json:"watch_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterTaskListRequest) Reset() {
+	*x = RegisterTaskListRequest{}
+	mi := &file_mail_proto_msgTypes[227]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterTaskListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterTaskListRequest) ProtoMessage() {}
+
+func (x *RegisterTaskListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[227]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterTaskListRequest.ProtoReflect.Descriptor instead.
+func (*RegisterTaskListRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{227}
+}
+
+func (x *RegisterTaskListRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *RegisterTaskListRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *RegisterTaskListRequest) GetWatchPath() string {
+	if x != nil {
+		return x.WatchPath
+	}
+	return ""
+}
+
+// RegisterTaskListResponse is the response for RegisterTaskList.
+type RegisterTaskListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskList      *TaskListProto         `protobuf:"bytes,1,opt,name=task_list,json=taskList,proto3" json:"task_list,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterTaskListResponse) Reset() {
+	*x = RegisterTaskListResponse{}
+	mi := &file_mail_proto_msgTypes[228]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterTaskListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterTaskListResponse) ProtoMessage() {}
+
+func (x *RegisterTaskListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[228]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterTaskListResponse.ProtoReflect.Descriptor instead.
+func (*RegisterTaskListResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{228}
+}
+
+func (x *RegisterTaskListResponse) GetTaskList() *TaskListProto {
+	if x != nil {
+		return x.TaskList
+	}
+	return nil
+}
+
+func (x *RegisterTaskListResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// GetTaskListRequest is the request for GetTaskList.
+type GetTaskListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskListRequest) Reset() {
+	*x = GetTaskListRequest{}
+	mi := &file_mail_proto_msgTypes[229]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskListRequest) ProtoMessage() {}
+
+func (x *GetTaskListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[229]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskListRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskListRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{229}
+}
+
+func (x *GetTaskListRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+// GetTaskListResponse is the response for GetTaskList.
+type GetTaskListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskList      *TaskListProto         `protobuf:"bytes,1,opt,name=task_list,json=taskList,proto3" json:"task_list,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskListResponse) Reset() {
+	*x = GetTaskListResponse{}
+	mi := &file_mail_proto_msgTypes[230]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskListResponse) ProtoMessage() {}
+
+func (x *GetTaskListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[230]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskListResponse.ProtoReflect.Descriptor instead.
+func (*GetTaskListResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{230}
+}
+
+func (x *GetTaskListResponse) GetTaskList() *TaskListProto {
+	if x != nil {
+		return x.TaskList
+	}
+	return nil
+}
+
+func (x *GetTaskListResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// ListTaskListsRequest is the request for ListTaskLists.
+type ListTaskListsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // Optional filter by agent
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTaskListsRequest) Reset() {
+	*x = ListTaskListsRequest{}
+	mi := &file_mail_proto_msgTypes[231]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTaskListsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTaskListsRequest) ProtoMessage() {}
+
+func (x *ListTaskListsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[231]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTaskListsRequest.ProtoReflect.Descriptor instead.
+func (*ListTaskListsRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{231}
+}
+
+func (x *ListTaskListsRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+// ListTaskListsResponse is the response for ListTaskLists.
+type ListTaskListsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskLists     []*TaskListProto       `protobuf:"bytes,1,rep,name=task_lists,json=taskLists,proto3" json:"task_lists,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTaskListsResponse) Reset() {
+	*x = ListTaskListsResponse{}
+	mi := &file_mail_proto_msgTypes[232]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTaskListsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTaskListsResponse) ProtoMessage() {}
+
+func (x *ListTaskListsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[232]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTaskListsResponse.ProtoReflect.Descriptor instead.
+func (*ListTaskListsResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{232}
+}
+
+func (x *ListTaskListsResponse) GetTaskLists() []*TaskListProto {
+	if x != nil {
+		return x.TaskLists
+	}
+	return nil
+}
+
+func (x *ListTaskListsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// UnregisterTaskListRequest is the request for UnregisterTaskList.
+type UnregisterTaskListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterTaskListRequest) Reset() {
+	*x = UnregisterTaskListRequest{}
+	mi := &file_mail_proto_msgTypes[233]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterTaskListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterTaskListRequest) ProtoMessage() {}
+
+func (x *UnregisterTaskListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[233]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterTaskListRequest.ProtoReflect.Descriptor instead.
+func (*UnregisterTaskListRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{233}
+}
+
+func (x *UnregisterTaskListRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+// UnregisterTaskListResponse is the response for UnregisterTaskList.
+type UnregisterTaskListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterTaskListResponse) Reset() {
+	*x = UnregisterTaskListResponse{}
+	mi := &file_mail_proto_msgTypes[234]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterTaskListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterTaskListResponse) ProtoMessage() {}
+
+func (x *UnregisterTaskListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[234]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterTaskListResponse.ProtoReflect.Descriptor instead.
+func (*UnregisterTaskListResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{234}
+}
+
+func (x *UnregisterTaskListResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// UpsertTaskRequest is the request for UpsertTask.
+type UpsertTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	ListId        string                 `protobuf:"bytes,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	ClaudeTaskId  string                 `protobuf:"bytes,3,opt,name=claude_task_id,json=claudeTaskId,proto3" json:"claude_task_id,omitempty"`
+	Subject       string                 `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	ActiveForm    string                 `protobuf:"bytes,6,opt,name=active_form,json=activeForm,proto3" json:"active_form,omitempty"`
+	MetadataJson  string                 `protobuf:"bytes,7,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"`
+	Status        TaskStatus             `protobuf:"varint,8,opt,name=status,proto3,enum=subtraterpc.TaskStatus" json:"status,omitempty"`
+	Owner         string                 `protobuf:"bytes,9,opt,name=owner,proto3" json:"owner,omitempty"`
+	BlockedBy     []string               `protobuf:"bytes,10,rep,name=blocked_by,json=blockedBy,proto3" json:"blocked_by,omitempty"`
+	Blocks        []string               `protobuf:"bytes,11,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	FilePath      string                 `protobuf:"bytes,12,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	FileMtime     int64                  `protobuf:"varint,13,opt,name=file_mtime,json=fileMtime,proto3" json:"file_mtime,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertTaskRequest) Reset() {
+	*x = UpsertTaskRequest{}
+	mi := &file_mail_proto_msgTypes[235]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertTaskRequest) ProtoMessage() {}
+
+func (x *UpsertTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[235]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertTaskRequest.ProtoReflect.Descriptor instead.
+func (*UpsertTaskRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{235}
+}
+
+func (x *UpsertTaskRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *UpsertTaskRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *UpsertTaskRequest) GetClaudeTaskId() string {
+	if x != nil {
+		return x.ClaudeTaskId
+	}
+	return ""
+}
+
+func (x *UpsertTaskRequest) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *UpsertTaskRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpsertTaskRequest) GetActiveForm() string {
+	if x != nil {
+		return x.ActiveForm
+	}
+	return ""
+}
+
+func (x *UpsertTaskRequest) GetMetadataJson() string {
+	if x != nil {
+		return x.MetadataJson
+	}
+	return ""
+}
+
+func (x *UpsertTaskRequest) GetStatus() TaskStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TaskStatus_TASK_STATUS_UNSPECIFIED
+}
+
+func (x *UpsertTaskRequest) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *UpsertTaskRequest) GetBlockedBy() []string {
+	if x != nil {
+		return x.BlockedBy
+	}
+	return nil
+}
+
+func (x *UpsertTaskRequest) GetBlocks() []string {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+func (x *UpsertTaskRequest) GetFilePath() string {
+	if x != nil {
+		return x.FilePath
+	}
+	return ""
+}
+
+func (x *UpsertTaskRequest) GetFileMtime() int64 {
+	if x != nil {
+		return x.FileMtime
+	}
+	return 0
+}
+
+// UpsertTaskResponse is the response for UpsertTask.
+type UpsertTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *TaskProto             `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertTaskResponse) Reset() {
+	*x = UpsertTaskResponse{}
+	mi := &file_mail_proto_msgTypes[236]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertTaskResponse) ProtoMessage() {}
+
+func (x *UpsertTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[236]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertTaskResponse.ProtoReflect.Descriptor instead.
+func (*UpsertTaskResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{236}
+}
+
+func (x *UpsertTaskResponse) GetTask() *TaskProto {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *UpsertTaskResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// GetTaskProtoRequest is the request for GetTask.
+type GetTaskProtoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	ClaudeTaskId  string                 `protobuf:"bytes,2,opt,name=claude_task_id,json=claudeTaskId,proto3" json:"claude_task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskProtoRequest) Reset() {
+	*x = GetTaskProtoRequest{}
+	mi := &file_mail_proto_msgTypes[237]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskProtoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskProtoRequest) ProtoMessage() {}
+
+func (x *GetTaskProtoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[237]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskProtoRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskProtoRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{237}
+}
+
+func (x *GetTaskProtoRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *GetTaskProtoRequest) GetClaudeTaskId() string {
+	if x != nil {
+		return x.ClaudeTaskId
+	}
+	return ""
+}
+
+// GetTaskResponse is the response for GetTask.
+type GetTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *TaskProto             `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskResponse) Reset() {
+	*x = GetTaskResponse{}
+	mi := &file_mail_proto_msgTypes[238]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskResponse) ProtoMessage() {}
+
+func (x *GetTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[238]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskResponse.ProtoReflect.Descriptor instead.
+func (*GetTaskResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{238}
+}
+
+func (x *GetTaskResponse) GetTask() *TaskProto {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *GetTaskResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// ListTasksRequest is the request for ListTasks.
+type ListTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	ListId        string                 `protobuf:"bytes,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	Status        TaskStatus             `protobuf:"varint,3,opt,name=status,proto3,enum=subtraterpc.TaskStatus" json:"status,omitempty"`
+	ActiveOnly    bool                   `protobuf:"varint,4,opt,name=active_only,json=activeOnly,proto3" json:"active_only,omitempty"`
+	AvailableOnly bool                   `protobuf:"varint,5,opt,name=available_only,json=availableOnly,proto3" json:"available_only,omitempty"`
+	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,7,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksRequest) Reset() {
+	*x = ListTasksRequest{}
+	mi := &file_mail_proto_msgTypes[239]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksRequest) ProtoMessage() {}
+
+func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[239]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{239}
+}
+
+func (x *ListTasksRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetStatus() TaskStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TaskStatus_TASK_STATUS_UNSPECIFIED
+}
+
+func (x *ListTasksRequest) GetActiveOnly() bool {
+	if x != nil {
+		return x.ActiveOnly
+	}
+	return false
+}
+
+func (x *ListTasksRequest) GetAvailableOnly() bool {
+	if x != nil {
+		return x.AvailableOnly
+	}
+	return false
+}
+
+func (x *ListTasksRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// ListTasksResponse is the response for ListTasks.
+type ListTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*TaskProto           `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksResponse) Reset() {
+	*x = ListTasksResponse{}
+	mi := &file_mail_proto_msgTypes[240]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksResponse) ProtoMessage() {}
+
+func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[240]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
+func (*ListTasksResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{240}
+}
+
+func (x *ListTasksResponse) GetTasks() []*TaskProto {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *ListTasksResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// UpdateTaskStatusRequest is the request for UpdateTaskStatus.
+type UpdateTaskStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	ClaudeTaskId  string                 `protobuf:"bytes,2,opt,name=claude_task_id,json=claudeTaskId,proto3" json:"claude_task_id,omitempty"`
+	Status        TaskStatus             `protobuf:"varint,3,opt,name=status,proto3,enum=subtraterpc.TaskStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTaskStatusRequest) Reset() {
+	*x = UpdateTaskStatusRequest{}
+	mi := &file_mail_proto_msgTypes[241]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskStatusRequest) ProtoMessage() {}
+
+func (x *UpdateTaskStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[241]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTaskStatusRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{241}
+}
+
+func (x *UpdateTaskStatusRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *UpdateTaskStatusRequest) GetClaudeTaskId() string {
+	if x != nil {
+		return x.ClaudeTaskId
+	}
+	return ""
+}
+
+func (x *UpdateTaskStatusRequest) GetStatus() TaskStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TaskStatus_TASK_STATUS_UNSPECIFIED
+}
+
+// UpdateTaskStatusResponse is the response for UpdateTaskStatus.
+type UpdateTaskStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTaskStatusResponse) Reset() {
+	*x = UpdateTaskStatusResponse{}
+	mi := &file_mail_proto_msgTypes[242]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskStatusResponse) ProtoMessage() {}
+
+func (x *UpdateTaskStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[242]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateTaskStatusResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{242}
+}
+
+func (x *UpdateTaskStatusResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// UpdateTaskOwnerRequest is the request for UpdateTaskOwner.
+type UpdateTaskOwnerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	ClaudeTaskId  string                 `protobuf:"bytes,2,opt,name=claude_task_id,json=claudeTaskId,proto3" json:"claude_task_id,omitempty"`
+	Owner         string                 `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTaskOwnerRequest) Reset() {
+	*x = UpdateTaskOwnerRequest{}
+	mi := &file_mail_proto_msgTypes[243]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskOwnerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskOwnerRequest) ProtoMessage() {}
+
+func (x *UpdateTaskOwnerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[243]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskOwnerRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTaskOwnerRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{243}
+}
+
+func (x *UpdateTaskOwnerRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *UpdateTaskOwnerRequest) GetClaudeTaskId() string {
+	if x != nil {
+		return x.ClaudeTaskId
+	}
+	return ""
+}
+
+func (x *UpdateTaskOwnerRequest) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+// UpdateTaskOwnerResponse is the response for UpdateTaskOwner.
+type UpdateTaskOwnerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTaskOwnerResponse) Reset() {
+	*x = UpdateTaskOwnerResponse{}
+	mi := &file_mail_proto_msgTypes[244]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskOwnerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskOwnerResponse) ProtoMessage() {}
+
+func (x *UpdateTaskOwnerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[244]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskOwnerResponse.ProtoReflect.Descriptor instead.
+func (*UpdateTaskOwnerResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{244}
+}
+
+func (x *UpdateTaskOwnerResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// DeleteTaskRequest is the request for DeleteTask.
+type DeleteTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTaskRequest) Reset() {
+	*x = DeleteTaskRequest{}
+	mi := &file_mail_proto_msgTypes[245]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTaskRequest) ProtoMessage() {}
+
+func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[245]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTaskRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTaskRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{245}
+}
+
+func (x *DeleteTaskRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// DeleteTaskResponse is the response for DeleteTask.
+type DeleteTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTaskResponse) Reset() {
+	*x = DeleteTaskResponse{}
+	mi := &file_mail_proto_msgTypes[246]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTaskResponse) ProtoMessage() {}
+
+func (x *DeleteTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[246]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTaskResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTaskResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{246}
+}
+
+func (x *DeleteTaskResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// GetTaskStatsRequest is the request for GetTaskStats.
+type GetTaskStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	ListId        string                 `protobuf:"bytes,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	TodaySince    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=today_since,json=todaySince,proto3" json:"today_since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskStatsRequest) Reset() {
+	*x = GetTaskStatsRequest{}
+	mi := &file_mail_proto_msgTypes[247]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskStatsRequest) ProtoMessage() {}
+
+func (x *GetTaskStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[247]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskStatsRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{247}
+}
+
+func (x *GetTaskStatsRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *GetTaskStatsRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+func (x *GetTaskStatsRequest) GetTodaySince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TodaySince
+	}
+	return nil
+}
+
+// GetTaskStatsResponse is the response for GetTaskStats.
+type GetTaskStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         *TaskStatsProto        `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskStatsResponse) Reset() {
+	*x = GetTaskStatsResponse{}
+	mi := &file_mail_proto_msgTypes[248]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskStatsResponse) ProtoMessage() {}
+
+func (x *GetTaskStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[248]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetTaskStatsResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{248}
+}
+
+func (x *GetTaskStatsResponse) GetStats() *TaskStatsProto {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *GetTaskStatsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// GetAllAgentTaskStatsRequest is the request for GetAllAgentTaskStats.
+type GetAllAgentTaskStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TodaySince    *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=today_since,json=todaySince,proto3" json:"today_since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllAgentTaskStatsRequest) Reset() {
+	*x = GetAllAgentTaskStatsRequest{}
+	mi := &file_mail_proto_msgTypes[249]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllAgentTaskStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllAgentTaskStatsRequest) ProtoMessage() {}
+
+func (x *GetAllAgentTaskStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[249]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllAgentTaskStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetAllAgentTaskStatsRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{249}
+}
+
+func (x *GetAllAgentTaskStatsRequest) GetTodaySince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.TodaySince
+	}
+	return nil
+}
+
+// GetAllAgentTaskStatsResponse is the response for GetAllAgentTaskStats.
+type GetAllAgentTaskStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         []*AgentTaskStatsProto `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAllAgentTaskStatsResponse) Reset() {
+	*x = GetAllAgentTaskStatsResponse{}
+	mi := &file_mail_proto_msgTypes[250]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAllAgentTaskStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAllAgentTaskStatsResponse) ProtoMessage() {}
+
+func (x *GetAllAgentTaskStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[250]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAllAgentTaskStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetAllAgentTaskStatsResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{250}
+}
+
+func (x *GetAllAgentTaskStatsResponse) GetStats() []*AgentTaskStatsProto {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *GetAllAgentTaskStatsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// SyncTaskListRequest is the request for SyncTaskList.
+type SyncTaskListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncTaskListRequest) Reset() {
+	*x = SyncTaskListRequest{}
+	mi := &file_mail_proto_msgTypes[251]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncTaskListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncTaskListRequest) ProtoMessage() {}
+
+func (x *SyncTaskListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[251]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncTaskListRequest.ProtoReflect.Descriptor instead.
+func (*SyncTaskListRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{251}
+}
+
+func (x *SyncTaskListRequest) GetListId() string {
+	if x != nil {
+		return x.ListId
+	}
+	return ""
+}
+
+// SyncTaskListResponse is the response for SyncTaskList.
+type SyncTaskListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TasksUpdated  int32                  `protobuf:"varint,1,opt,name=tasks_updated,json=tasksUpdated,proto3" json:"tasks_updated,omitempty"`
+	TasksDeleted  int32                  `protobuf:"varint,2,opt,name=tasks_deleted,json=tasksDeleted,proto3" json:"tasks_deleted,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncTaskListResponse) Reset() {
+	*x = SyncTaskListResponse{}
+	mi := &file_mail_proto_msgTypes[252]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncTaskListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncTaskListResponse) ProtoMessage() {}
+
+func (x *SyncTaskListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[252]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncTaskListResponse.ProtoReflect.Descriptor instead.
+func (*SyncTaskListResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{252}
+}
+
+func (x *SyncTaskListResponse) GetTasksUpdated() int32 {
+	if x != nil {
+		return x.TasksUpdated
+	}
+	return 0
+}
+
+func (x *SyncTaskListResponse) GetTasksDeleted() int32 {
+	if x != nil {
+		return x.TasksDeleted
+	}
+	return 0
+}
+
+func (x *SyncTaskListResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// PruneOldTasksRequest is the request for PruneOldTasks.
+type PruneOldTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OlderThan     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=older_than,json=olderThan,proto3" json:"older_than,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PruneOldTasksRequest) Reset() {
+	*x = PruneOldTasksRequest{}
+	mi := &file_mail_proto_msgTypes[253]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PruneOldTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PruneOldTasksRequest) ProtoMessage() {}
+
+func (x *PruneOldTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[253]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PruneOldTasksRequest.ProtoReflect.Descriptor instead.
+func (*PruneOldTasksRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{253}
+}
+
+func (x *PruneOldTasksRequest) GetOlderThan() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OlderThan
+	}
+	return nil
+}
+
+// PruneOldTasksResponse is the response for PruneOldTasks.
+type PruneOldTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PruneOldTasksResponse) Reset() {
+	*x = PruneOldTasksResponse{}
+	mi := &file_mail_proto_msgTypes[254]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PruneOldTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PruneOldTasksResponse) ProtoMessage() {}
+
+func (x *PruneOldTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[254]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PruneOldTasksResponse.ProtoReflect.Descriptor instead.
+func (*PruneOldTasksResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{254}
+}
+
+func (x *PruneOldTasksResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// PlanReviewProto represents a plan review record.
+type PlanReviewProto struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	PlanReviewId    string                 `protobuf:"bytes,2,opt,name=plan_review_id,json=planReviewId,proto3" json:"plan_review_id,omitempty"`
+	MessageId       int64                  `protobuf:"varint,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ThreadId        string                 `protobuf:"bytes,4,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	RequesterId     int64                  `protobuf:"varint,5,opt,name=requester_id,json=requesterId,proto3" json:"requester_id,omitempty"`
+	ReviewerName    string                 `protobuf:"bytes,6,opt,name=reviewer_name,json=reviewerName,proto3" json:"reviewer_name,omitempty"`
+	PlanPath        string                 `protobuf:"bytes,7,opt,name=plan_path,json=planPath,proto3" json:"plan_path,omitempty"`
+	PlanTitle       string                 `protobuf:"bytes,8,opt,name=plan_title,json=planTitle,proto3" json:"plan_title,omitempty"`
+	PlanSummary     string                 `protobuf:"bytes,9,opt,name=plan_summary,json=planSummary,proto3" json:"plan_summary,omitempty"`
+	State           string                 `protobuf:"bytes,10,opt,name=state,proto3" json:"state,omitempty"`
+	ReviewerComment string                 `protobuf:"bytes,11,opt,name=reviewer_comment,json=reviewerComment,proto3" json:"reviewer_comment,omitempty"`
+	ReviewedBy      int64                  `protobuf:"varint,12,opt,name=reviewed_by,json=reviewedBy,proto3" json:"reviewed_by,omitempty"`
+	SessionId       string                 `protobuf:"bytes,13,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	CreatedAt       int64                  `protobuf:"varint,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       int64                  `protobuf:"varint,15,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ReviewedAt      int64                  `protobuf:"varint,16,opt,name=reviewed_at,json=reviewedAt,proto3" json:"reviewed_at,omitempty"`
+	Error           string                 `protobuf:"bytes,17,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PlanReviewProto) Reset() {
+	*x = PlanReviewProto{}
+	mi := &file_mail_proto_msgTypes[255]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanReviewProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanReviewProto) ProtoMessage() {}
+
+func (x *PlanReviewProto) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[255]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanReviewProto.ProtoReflect.Descriptor instead.
+func (*PlanReviewProto) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{255}
+}
+
+func (x *PlanReviewProto) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PlanReviewProto) GetPlanReviewId() string {
+	if x != nil {
+		return x.PlanReviewId
+	}
+	return ""
+}
+
+func (x *PlanReviewProto) GetMessageId() int64 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
+}
+
+func (x *PlanReviewProto) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
+}
+
+func (x *PlanReviewProto) GetRequesterId() int64 {
+	if x != nil {
+		return x.RequesterId
+	}
+	return 0
+}
+
+func (x *PlanReviewProto) GetReviewerName() string {
+	if x != nil {
+		return x.ReviewerName
+	}
+	return ""
+}
+
+func (x *PlanReviewProto) GetPlanPath() string {
+	if x != nil {
+		return x.PlanPath
+	}
+	return ""
+}
+
+func (x *PlanReviewProto) GetPlanTitle() string {
+	if x != nil {
+		return x.PlanTitle
+	}
+	return ""
+}
+
+func (x *PlanReviewProto) GetPlanSummary() string {
+	if x != nil {
+		return x.PlanSummary
+	}
+	return ""
+}
+
+func (x *PlanReviewProto) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *PlanReviewProto) GetReviewerComment() string {
+	if x != nil {
+		return x.ReviewerComment
+	}
+	return ""
+}
+
+func (x *PlanReviewProto) GetReviewedBy() int64 {
+	if x != nil {
+		return x.ReviewedBy
+	}
+	return 0
+}
+
+func (x *PlanReviewProto) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *PlanReviewProto) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *PlanReviewProto) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
 }
 
-// Deprecated: Use UpdateTaskOwnerResponse.ProtoReflect.Descriptor instead.
-func (*UpdateTaskOwnerResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{132}
+func (x *PlanReviewProto) GetReviewedAt() int64 {
+	if x != nil {
+		return x.ReviewedAt
+	}
+	return 0
 }
 
-func (x *UpdateTaskOwnerResponse) GetError() string {
+func (x *PlanReviewProto) GetError() string {
 	if x != nil {
 		return x.Error
 	}
 	return ""
 }
 
-// DeleteTaskRequest is the request for DeleteTask.
-type DeleteTaskRequest struct {
+// CreatePlanReviewRequest is the request for CreatePlanReview.
+type CreatePlanReviewRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	PlanReviewId  string                 `protobuf:"bytes,1,opt,name=plan_review_id,json=planReviewId,proto3" json:"plan_review_id,omitempty"`
+	MessageId     int64                  `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ThreadId      string                 `protobuf:"bytes,3,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	RequesterId   int64                  `protobuf:"varint,4,opt,name=requester_id,json=requesterId,proto3" json:"requester_id,omitempty"`
+	ReviewerName  string                 `protobuf:"bytes,5,opt,name=reviewer_name,json=reviewerName,proto3" json:"reviewer_name,omitempty"`
+	PlanPath      string                 `protobuf:"bytes,6,opt,name=plan_path,json=planPath,proto3" json:"plan_path,omitempty"`
+	PlanTitle     string                 `protobuf:"bytes,7,opt,name=plan_title,json=planTitle,proto3" json:"plan_title,omitempty"`
+	PlanSummary   string                 `protobuf:"bytes,8,opt,name=plan_summary,json=planSummary,proto3" json:"plan_summary,omitempty"`
+	SessionId     string                 `protobuf:"bytes,9,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteTaskRequest) Reset() {
-	*x = DeleteTaskRequest{}
-	mi := &file_mail_proto_msgTypes[133]
+func (x *CreatePlanReviewRequest) Reset() {
+	*x = CreatePlanReviewRequest{}
+	mi := &file_mail_proto_msgTypes[256]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteTaskRequest) String() string {
+func (x *CreatePlanReviewRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteTaskRequest) ProtoMessage() {}
+func (*CreatePlanReviewRequest) ProtoMessage() {}
 
-func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[133]
+func (x *CreatePlanReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[256]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8800,88 +17042,97 @@ func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteTaskRequest.ProtoReflect.Descriptor instead.
-func (*DeleteTaskRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{133}
+// Deprecated: Use CreatePlanReviewRequest.ProtoReflect.Descriptor instead.
+func (*CreatePlanReviewRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{256}
 }
 
-func (x *DeleteTaskRequest) GetId() int64 {
+func (x *CreatePlanReviewRequest) GetPlanReviewId() string {
 	if x != nil {
-		return x.Id
+		return x.PlanReviewId
+	}
+	return ""
+}
+
+func (x *CreatePlanReviewRequest) GetMessageId() int64 {
+	if x != nil {
+		return x.MessageId
 	}
 	return 0
 }
 
-// DeleteTaskResponse is the response for DeleteTask.
-type DeleteTaskResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *CreatePlanReviewRequest) GetThreadId() string {
+	if x != nil {
+		return x.ThreadId
+	}
+	return ""
 }
 
-func (x *DeleteTaskResponse) Reset() {
-	*x = DeleteTaskResponse{}
-	mi := &file_mail_proto_msgTypes[134]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *CreatePlanReviewRequest) GetRequesterId() int64 {
+	if x != nil {
+		return x.RequesterId
+	}
+	return 0
 }
 
-func (x *DeleteTaskResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *CreatePlanReviewRequest) GetReviewerName() string {
+	if x != nil {
+		return x.ReviewerName
+	}
+	return ""
 }
 
-func (*DeleteTaskResponse) ProtoMessage() {}
+func (x *CreatePlanReviewRequest) GetPlanPath() string {
+	if x != nil {
+		return x.PlanPath
+	}
+	return ""
+}
 
-func (x *DeleteTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[134]
+func (x *CreatePlanReviewRequest) GetPlanTitle() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.PlanTitle
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use DeleteTaskResponse.ProtoReflect.Descriptor instead.
-func (*DeleteTaskResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{134}
+func (x *CreatePlanReviewRequest) GetPlanSummary() string {
+	if x != nil {
+		return x.PlanSummary
+	}
+	return ""
 }
 
-func (x *DeleteTaskResponse) GetError() string {
+func (x *CreatePlanReviewRequest) GetSessionId() string {
 	if x != nil {
-		return x.Error
+		return x.SessionId
 	}
 	return ""
 }
 
-// GetTaskStatsRequest is the request for GetTaskStats.
-type GetTaskStatsRequest struct {
+// GetPlanReviewRequest is the request for GetPlanReview.
+type GetPlanReviewRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	ListId        string                 `protobuf:"bytes,2,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
-	TodaySince    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=today_since,json=todaySince,proto3" json:"today_since,omitempty"`
+	PlanReviewId  string                 `protobuf:"bytes,1,opt,name=plan_review_id,json=planReviewId,proto3" json:"plan_review_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTaskStatsRequest) Reset() {
-	*x = GetTaskStatsRequest{}
-	mi := &file_mail_proto_msgTypes[135]
+func (x *GetPlanReviewRequest) Reset() {
+	*x = GetPlanReviewRequest{}
+	mi := &file_mail_proto_msgTypes[257]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTaskStatsRequest) String() string {
+func (x *GetPlanReviewRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTaskStatsRequest) ProtoMessage() {}
+func (*GetPlanReviewRequest) ProtoMessage() {}
 
-func (x *GetTaskStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[135]
+func (x *GetPlanReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[257]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8892,56 +17143,41 @@ func (x *GetTaskStatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTaskStatsRequest.ProtoReflect.Descriptor instead.
-func (*GetTaskStatsRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{135}
-}
-
-func (x *GetTaskStatsRequest) GetAgentId() int64 {
-	if x != nil {
-		return x.AgentId
-	}
-	return 0
+// Deprecated: Use GetPlanReviewRequest.ProtoReflect.Descriptor instead.
+func (*GetPlanReviewRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{257}
 }
 
-func (x *GetTaskStatsRequest) GetListId() string {
+func (x *GetPlanReviewRequest) GetPlanReviewId() string {
 	if x != nil {
-		return x.ListId
+		return x.PlanReviewId
 	}
 	return ""
 }
 
-func (x *GetTaskStatsRequest) GetTodaySince() *timestamppb.Timestamp {
-	if x != nil {
-		return x.TodaySince
-	}
-	return nil
-}
-
-// GetTaskStatsResponse is the response for GetTaskStats.
-type GetTaskStatsResponse struct {
+// GetPlanReviewByThreadRequest is the request for GetPlanReviewByThread.
+type GetPlanReviewByThreadRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Stats         *TaskStatsProto        `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	ThreadId      string                 `protobuf:"bytes,1,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTaskStatsResponse) Reset() {
-	*x = GetTaskStatsResponse{}
-	mi := &file_mail_proto_msgTypes[136]
+func (x *GetPlanReviewByThreadRequest) Reset() {
+	*x = GetPlanReviewByThreadRequest{}
+	mi := &file_mail_proto_msgTypes[258]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTaskStatsResponse) String() string {
+func (x *GetPlanReviewByThreadRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTaskStatsResponse) ProtoMessage() {}
+func (*GetPlanReviewByThreadRequest) ProtoMessage() {}
 
-func (x *GetTaskStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[136]
+func (x *GetPlanReviewByThreadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[258]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -8952,48 +17188,41 @@ func (x *GetTaskStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTaskStatsResponse.ProtoReflect.Descriptor instead.
-func (*GetTaskStatsResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{136}
-}
-
-func (x *GetTaskStatsResponse) GetStats() *TaskStatsProto {
-	if x != nil {
-		return x.Stats
-	}
-	return nil
+// Deprecated: Use GetPlanReviewByThreadRequest.ProtoReflect.Descriptor instead.
+func (*GetPlanReviewByThreadRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{258}
 }
 
-func (x *GetTaskStatsResponse) GetError() string {
+func (x *GetPlanReviewByThreadRequest) GetThreadId() string {
 	if x != nil {
-		return x.Error
+		return x.ThreadId
 	}
 	return ""
 }
 
-// GetAllAgentTaskStatsRequest is the request for GetAllAgentTaskStats.
-type GetAllAgentTaskStatsRequest struct {
+// GetPlanReviewBySessionRequest is the request for GetPlanReviewBySession.
+type GetPlanReviewBySessionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TodaySince    *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=today_since,json=todaySince,proto3" json:"today_since,omitempty"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAllAgentTaskStatsRequest) Reset() {
-	*x = GetAllAgentTaskStatsRequest{}
-	mi := &file_mail_proto_msgTypes[137]
+func (x *GetPlanReviewBySessionRequest) Reset() {
+	*x = GetPlanReviewBySessionRequest{}
+	mi := &file_mail_proto_msgTypes[259]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAllAgentTaskStatsRequest) String() string {
+func (x *GetPlanReviewBySessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAllAgentTaskStatsRequest) ProtoMessage() {}
+func (*GetPlanReviewBySessionRequest) ProtoMessage() {}
 
-func (x *GetAllAgentTaskStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[137]
+func (x *GetPlanReviewBySessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[259]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9004,42 +17233,44 @@ func (x *GetAllAgentTaskStatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAllAgentTaskStatsRequest.ProtoReflect.Descriptor instead.
-func (*GetAllAgentTaskStatsRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{137}
+// Deprecated: Use GetPlanReviewBySessionRequest.ProtoReflect.Descriptor instead.
+func (*GetPlanReviewBySessionRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{259}
 }
 
-func (x *GetAllAgentTaskStatsRequest) GetTodaySince() *timestamppb.Timestamp {
+func (x *GetPlanReviewBySessionRequest) GetSessionId() string {
 	if x != nil {
-		return x.TodaySince
+		return x.SessionId
 	}
-	return nil
+	return ""
 }
 
-// GetAllAgentTaskStatsResponse is the response for GetAllAgentTaskStats.
-type GetAllAgentTaskStatsResponse struct {
+// ListPlanReviewsRequest is the request for ListPlanReviews.
+type ListPlanReviewsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Stats         []*AgentTaskStatsProto `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	RequesterId   int64                  `protobuf:"varint,2,opt,name=requester_id,json=requesterId,proto3" json:"requester_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAllAgentTaskStatsResponse) Reset() {
-	*x = GetAllAgentTaskStatsResponse{}
-	mi := &file_mail_proto_msgTypes[138]
+func (x *ListPlanReviewsRequest) Reset() {
+	*x = ListPlanReviewsRequest{}
+	mi := &file_mail_proto_msgTypes[260]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAllAgentTaskStatsResponse) String() string {
+func (x *ListPlanReviewsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAllAgentTaskStatsResponse) ProtoMessage() {}
+func (*ListPlanReviewsRequest) ProtoMessage() {}
 
-func (x *GetAllAgentTaskStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[138]
+func (x *ListPlanReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[260]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9050,48 +17281,62 @@ func (x *GetAllAgentTaskStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAllAgentTaskStatsResponse.ProtoReflect.Descriptor instead.
-func (*GetAllAgentTaskStatsResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{138}
+// Deprecated: Use ListPlanReviewsRequest.ProtoReflect.Descriptor instead.
+func (*ListPlanReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{260}
+}
+
+func (x *ListPlanReviewsRequest) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ListPlanReviewsRequest) GetRequesterId() int64 {
+	if x != nil {
+		return x.RequesterId
+	}
+	return 0
 }
 
-func (x *GetAllAgentTaskStatsResponse) GetStats() []*AgentTaskStatsProto {
+func (x *ListPlanReviewsRequest) GetLimit() int32 {
 	if x != nil {
-		return x.Stats
+		return x.Limit
 	}
-	return nil
+	return 0
 }
 
-func (x *GetAllAgentTaskStatsResponse) GetError() string {
+func (x *ListPlanReviewsRequest) GetOffset() int32 {
 	if x != nil {
-		return x.Error
+		return x.Offset
 	}
-	return ""
+	return 0
 }
 
-// SyncTaskListRequest is the request for SyncTaskList.
-type SyncTaskListRequest struct {
+// ListPlanReviewsResponse is the response for ListPlanReviews.
+type ListPlanReviewsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ListId        string                 `protobuf:"bytes,1,opt,name=list_id,json=listId,proto3" json:"list_id,omitempty"`
+	PlanReviews   []*PlanReviewProto     `protobuf:"bytes,1,rep,name=plan_reviews,json=planReviews,proto3" json:"plan_reviews,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SyncTaskListRequest) Reset() {
-	*x = SyncTaskListRequest{}
-	mi := &file_mail_proto_msgTypes[139]
+func (x *ListPlanReviewsResponse) Reset() {
+	*x = ListPlanReviewsResponse{}
+	mi := &file_mail_proto_msgTypes[261]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SyncTaskListRequest) String() string {
+func (x *ListPlanReviewsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SyncTaskListRequest) ProtoMessage() {}
+func (*ListPlanReviewsResponse) ProtoMessage() {}
 
-func (x *SyncTaskListRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[139]
+func (x *ListPlanReviewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[261]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9102,43 +17347,44 @@ func (x *SyncTaskListRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SyncTaskListRequest.ProtoReflect.Descriptor instead.
-func (*SyncTaskListRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{139}
+// Deprecated: Use ListPlanReviewsResponse.ProtoReflect.Descriptor instead.
+func (*ListPlanReviewsResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{261}
 }
 
-func (x *SyncTaskListRequest) GetListId() string {
+func (x *ListPlanReviewsResponse) GetPlanReviews() []*PlanReviewProto {
 	if x != nil {
-		return x.ListId
+		return x.PlanReviews
 	}
-	return ""
+	return nil
 }
 
-// SyncTaskListResponse is the response for SyncTaskList.
-type SyncTaskListResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TasksUpdated  int32                  `protobuf:"varint,1,opt,name=tasks_updated,json=tasksUpdated,proto3" json:"tasks_updated,omitempty"`
-	TasksDeleted  int32                  `protobuf:"varint,2,opt,name=tasks_deleted,json=tasksDeleted,proto3" json:"tasks_deleted,omitempty"`
-	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// UpdatePlanReviewStatusRequest is the request for UpdatePlanReviewStatus.
+type UpdatePlanReviewStatusRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PlanReviewId    string                 `protobuf:"bytes,1,opt,name=plan_review_id,json=planReviewId,proto3" json:"plan_review_id,omitempty"`
+	State           string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	ReviewerComment string                 `protobuf:"bytes,3,opt,name=reviewer_comment,json=reviewerComment,proto3" json:"reviewer_comment,omitempty"`
+	ReviewedBy      int64                  `protobuf:"varint,4,opt,name=reviewed_by,json=reviewedBy,proto3" json:"reviewed_by,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *SyncTaskListResponse) Reset() {
-	*x = SyncTaskListResponse{}
-	mi := &file_mail_proto_msgTypes[140]
+func (x *UpdatePlanReviewStatusRequest) Reset() {
+	*x = UpdatePlanReviewStatusRequest{}
+	mi := &file_mail_proto_msgTypes[262]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SyncTaskListResponse) String() string {
+func (x *UpdatePlanReviewStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SyncTaskListResponse) ProtoMessage() {}
+func (*UpdatePlanReviewStatusRequest) ProtoMessage() {}
 
-func (x *SyncTaskListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[140]
+func (x *UpdatePlanReviewStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[262]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9149,55 +17395,62 @@ func (x *SyncTaskListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SyncTaskListResponse.ProtoReflect.Descriptor instead.
-func (*SyncTaskListResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{140}
+// Deprecated: Use UpdatePlanReviewStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePlanReviewStatusRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{262}
 }
 
-func (x *SyncTaskListResponse) GetTasksUpdated() int32 {
+func (x *UpdatePlanReviewStatusRequest) GetPlanReviewId() string {
 	if x != nil {
-		return x.TasksUpdated
+		return x.PlanReviewId
 	}
-	return 0
+	return ""
 }
 
-func (x *SyncTaskListResponse) GetTasksDeleted() int32 {
+func (x *UpdatePlanReviewStatusRequest) GetState() string {
 	if x != nil {
-		return x.TasksDeleted
+		return x.State
 	}
-	return 0
+	return ""
 }
 
-func (x *SyncTaskListResponse) GetError() string {
+func (x *UpdatePlanReviewStatusRequest) GetReviewerComment() string {
 	if x != nil {
-		return x.Error
+		return x.ReviewerComment
 	}
 	return ""
 }
 
-// PruneOldTasksRequest is the request for PruneOldTasks.
-type PruneOldTasksRequest struct {
+func (x *UpdatePlanReviewStatusRequest) GetReviewedBy() int64 {
+	if x != nil {
+		return x.ReviewedBy
+	}
+	return 0
+}
+
+// DeletePlanReviewRequest is the request for DeletePlanReview.
+type DeletePlanReviewRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OlderThan     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=older_than,json=olderThan,proto3" json:"older_than,omitempty"`
+	PlanReviewId  string                 `protobuf:"bytes,1,opt,name=plan_review_id,json=planReviewId,proto3" json:"plan_review_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PruneOldTasksRequest) Reset() {
-	*x = PruneOldTasksRequest{}
-	mi := &file_mail_proto_msgTypes[141]
+func (x *DeletePlanReviewRequest) Reset() {
+	*x = DeletePlanReviewRequest{}
+	mi := &file_mail_proto_msgTypes[263]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PruneOldTasksRequest) String() string {
+func (x *DeletePlanReviewRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PruneOldTasksRequest) ProtoMessage() {}
+func (*DeletePlanReviewRequest) ProtoMessage() {}
 
-func (x *PruneOldTasksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[141]
+func (x *DeletePlanReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[263]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9208,41 +17461,41 @@ func (x *PruneOldTasksRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PruneOldTasksRequest.ProtoReflect.Descriptor instead.
-func (*PruneOldTasksRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{141}
+// Deprecated: Use DeletePlanReviewRequest.ProtoReflect.Descriptor instead.
+func (*DeletePlanReviewRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{263}
 }
 
-func (x *PruneOldTasksRequest) GetOlderThan() *timestamppb.Timestamp {
+func (x *DeletePlanReviewRequest) GetPlanReviewId() string {
 	if x != nil {
-		return x.OlderThan
+		return x.PlanReviewId
 	}
-	return nil
+	return ""
 }
 
-// PruneOldTasksResponse is the response for PruneOldTasks.
-type PruneOldTasksResponse struct {
+// DeletePlanReviewResponse is the response for DeletePlanReview.
+type DeletePlanReviewResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PruneOldTasksResponse) Reset() {
-	*x = PruneOldTasksResponse{}
-	mi := &file_mail_proto_msgTypes[142]
+func (x *DeletePlanReviewResponse) Reset() {
+	*x = DeletePlanReviewResponse{}
+	mi := &file_mail_proto_msgTypes[264]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PruneOldTasksResponse) String() string {
+func (x *DeletePlanReviewResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PruneOldTasksResponse) ProtoMessage() {}
+func (*DeletePlanReviewResponse) ProtoMessage() {}
 
-func (x *PruneOldTasksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[142]
+func (x *DeletePlanReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[264]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9253,57 +17506,48 @@ func (x *PruneOldTasksResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PruneOldTasksResponse.ProtoReflect.Descriptor instead.
-func (*PruneOldTasksResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{142}
+// Deprecated: Use DeletePlanReviewResponse.ProtoReflect.Descriptor instead.
+func (*DeletePlanReviewResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{264}
 }
 
-func (x *PruneOldTasksResponse) GetError() string {
+func (x *DeletePlanReviewResponse) GetError() string {
 	if x != nil {
 		return x.Error
 	}
 	return ""
 }
 
-// PlanReviewProto represents a plan review record.
-type PlanReviewProto struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Id              int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	PlanReviewId    string                 `protobuf:"bytes,2,opt,name=plan_review_id,json=planReviewId,proto3" json:"plan_review_id,omitempty"`
-	MessageId       int64                  `protobuf:"varint,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	ThreadId        string                 `protobuf:"bytes,4,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
-	RequesterId     int64                  `protobuf:"varint,5,opt,name=requester_id,json=requesterId,proto3" json:"requester_id,omitempty"`
-	ReviewerName    string                 `protobuf:"bytes,6,opt,name=reviewer_name,json=reviewerName,proto3" json:"reviewer_name,omitempty"`
-	PlanPath        string                 `protobuf:"bytes,7,opt,name=plan_path,json=planPath,proto3" json:"plan_path,omitempty"`
-	PlanTitle       string                 `protobuf:"bytes,8,opt,name=plan_title,json=planTitle,proto3" json:"plan_title,omitempty"`
-	PlanSummary     string                 `protobuf:"bytes,9,opt,name=plan_summary,json=planSummary,proto3" json:"plan_summary,omitempty"`
-	State           string                 `protobuf:"bytes,10,opt,name=state,proto3" json:"state,omitempty"`
-	ReviewerComment string                 `protobuf:"bytes,11,opt,name=reviewer_comment,json=reviewerComment,proto3" json:"reviewer_comment,omitempty"`
-	ReviewedBy      int64                  `protobuf:"varint,12,opt,name=reviewed_by,json=reviewedBy,proto3" json:"reviewed_by,omitempty"`
-	SessionId       string                 `protobuf:"bytes,13,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	CreatedAt       int64                  `protobuf:"varint,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt       int64                  `protobuf:"varint,15,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	ReviewedAt      int64                  `protobuf:"varint,16,opt,name=reviewed_at,json=reviewedAt,proto3" json:"reviewed_at,omitempty"`
-	Error           string                 `protobuf:"bytes,17,opt,name=error,proto3" json:"error,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+// InboxRuleConditions are the conditions a message must meet for a rule to
+// match. Empty conditions are ignored; the rest must all hold.
+type InboxRuleConditions struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SenderName     string                 `protobuf:"bytes,1,opt,name=sender_name,json=senderName,proto3" json:"sender_name,omitempty"`
+	SenderPrefix   string                 `protobuf:"bytes,2,opt,name=sender_prefix,json=senderPrefix,proto3" json:"sender_prefix,omitempty"`
+	SubjectPattern string                 `protobuf:"bytes,3,opt,name=subject_pattern,json=subjectPattern,proto3" json:"subject_pattern,omitempty"` // Regular expression.
+	TopicName      string                 `protobuf:"bytes,4,opt,name=topic_name,json=topicName,proto3" json:"topic_name,omitempty"`
+	Priority       string                 `protobuf:"bytes,5,opt,name=priority,proto3" json:"priority,omitempty"` // "urgent", "normal" or "low".
+	MetadataKey    string                 `protobuf:"bytes,6,opt,name=metadata_key,json=metadataKey,proto3" json:"metadata_key,omitempty"`
+	MetadataValue  string                 `protobuf:"bytes,7,opt,name=metadata_value,json=metadataValue,proto3" json:"metadata_value,omitempty"` // Empty matches any value for the key.
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *PlanReviewProto) Reset() {
-	*x = PlanReviewProto{}
-	mi := &file_mail_proto_msgTypes[143]
+func (x *InboxRuleConditions) Reset() {
+	*x = InboxRuleConditions{}
+	mi := &file_mail_proto_msgTypes[265]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PlanReviewProto) String() string {
+func (x *InboxRuleConditions) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PlanReviewProto) ProtoMessage() {}
+func (*InboxRuleConditions) ProtoMessage() {}
 
-func (x *PlanReviewProto) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[143]
+func (x *InboxRuleConditions) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[265]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9314,161 +17558,178 @@ func (x *PlanReviewProto) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PlanReviewProto.ProtoReflect.Descriptor instead.
-func (*PlanReviewProto) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{143}
+// Deprecated: Use InboxRuleConditions.ProtoReflect.Descriptor instead.
+func (*InboxRuleConditions) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{265}
 }
 
-func (x *PlanReviewProto) GetId() int64 {
+func (x *InboxRuleConditions) GetSenderName() string {
 	if x != nil {
-		return x.Id
+		return x.SenderName
 	}
-	return 0
+	return ""
 }
 
-func (x *PlanReviewProto) GetPlanReviewId() string {
+func (x *InboxRuleConditions) GetSenderPrefix() string {
 	if x != nil {
-		return x.PlanReviewId
+		return x.SenderPrefix
 	}
 	return ""
 }
 
-func (x *PlanReviewProto) GetMessageId() int64 {
+func (x *InboxRuleConditions) GetSubjectPattern() string {
 	if x != nil {
-		return x.MessageId
+		return x.SubjectPattern
 	}
-	return 0
+	return ""
 }
 
-func (x *PlanReviewProto) GetThreadId() string {
+func (x *InboxRuleConditions) GetTopicName() string {
 	if x != nil {
-		return x.ThreadId
+		return x.TopicName
 	}
 	return ""
 }
 
-func (x *PlanReviewProto) GetRequesterId() int64 {
+func (x *InboxRuleConditions) GetPriority() string {
 	if x != nil {
-		return x.RequesterId
+		return x.Priority
 	}
-	return 0
+	return ""
 }
 
-func (x *PlanReviewProto) GetReviewerName() string {
+func (x *InboxRuleConditions) GetMetadataKey() string {
 	if x != nil {
-		return x.ReviewerName
+		return x.MetadataKey
 	}
 	return ""
 }
 
-func (x *PlanReviewProto) GetPlanPath() string {
+func (x *InboxRuleConditions) GetMetadataValue() string {
 	if x != nil {
-		return x.PlanPath
+		return x.MetadataValue
 	}
 	return ""
 }
 
-func (x *PlanReviewProto) GetPlanTitle() string {
-	if x != nil {
-		return x.PlanTitle
-	}
-	return ""
+// InboxRuleActions are what a rule does to the messages it matches.
+type InboxRuleActions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Archive       bool                   `protobuf:"varint,1,opt,name=archive,proto3" json:"archive,omitempty"`
+	Star          bool                   `protobuf:"varint,2,opt,name=star,proto3" json:"star,omitempty"`
+	Snooze        string                 `protobuf:"bytes,3,opt,name=snooze,proto3" json:"snooze,omitempty"`                        // A duration ("2h") or time of day ("09:00").
+	Category      string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`                    // "primary" or "notifications".
+	ForwardTo     string                 `protobuf:"bytes,5,opt,name=forward_to,json=forwardTo,proto3" json:"forward_to,omitempty"` // Agent to also deliver the message to.
+	Label         string                 `protobuf:"bytes,6,opt,name=label,proto3" json:"label,omitempty"`                          // Label to file the message under.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PlanReviewProto) GetPlanSummary() string {
-	if x != nil {
-		return x.PlanSummary
-	}
-	return ""
+func (x *InboxRuleActions) Reset() {
+	*x = InboxRuleActions{}
+	mi := &file_mail_proto_msgTypes[266]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *PlanReviewProto) GetState() string {
-	if x != nil {
-		return x.State
-	}
-	return ""
+func (x *InboxRuleActions) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *PlanReviewProto) GetReviewerComment() string {
+func (*InboxRuleActions) ProtoMessage() {}
+
+func (x *InboxRuleActions) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[266]
 	if x != nil {
-		return x.ReviewerComment
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *PlanReviewProto) GetReviewedBy() int64 {
+// Deprecated: Use InboxRuleActions.ProtoReflect.Descriptor instead.
+func (*InboxRuleActions) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{266}
+}
+
+func (x *InboxRuleActions) GetArchive() bool {
 	if x != nil {
-		return x.ReviewedBy
+		return x.Archive
 	}
-	return 0
+	return false
 }
 
-func (x *PlanReviewProto) GetSessionId() string {
+func (x *InboxRuleActions) GetStar() bool {
 	if x != nil {
-		return x.SessionId
+		return x.Star
 	}
-	return ""
+	return false
 }
 
-func (x *PlanReviewProto) GetCreatedAt() int64 {
+func (x *InboxRuleActions) GetSnooze() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.Snooze
 	}
-	return 0
+	return ""
 }
 
-func (x *PlanReviewProto) GetUpdatedAt() int64 {
+func (x *InboxRuleActions) GetCategory() string {
 	if x != nil {
-		return x.UpdatedAt
+		return x.Category
 	}
-	return 0
+	return ""
 }
 
-func (x *PlanReviewProto) GetReviewedAt() int64 {
+func (x *InboxRuleActions) GetForwardTo() string {
 	if x != nil {
-		return x.ReviewedAt
+		return x.ForwardTo
 	}
-	return 0
+	return ""
 }
 
-func (x *PlanReviewProto) GetError() string {
+func (x *InboxRuleActions) GetLabel() string {
 	if x != nil {
-		return x.Error
+		return x.Label
 	}
 	return ""
 }
 
-// CreatePlanReviewRequest is the request for CreatePlanReview.
-type CreatePlanReviewRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	PlanReviewId  string                 `protobuf:"bytes,1,opt,name=plan_review_id,json=planReviewId,proto3" json:"plan_review_id,omitempty"`
-	MessageId     int64                  `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	ThreadId      string                 `protobuf:"bytes,3,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
-	RequesterId   int64                  `protobuf:"varint,4,opt,name=requester_id,json=requesterId,proto3" json:"requester_id,omitempty"`
-	ReviewerName  string                 `protobuf:"bytes,5,opt,name=reviewer_name,json=reviewerName,proto3" json:"reviewer_name,omitempty"`
-	PlanPath      string                 `protobuf:"bytes,6,opt,name=plan_path,json=planPath,proto3" json:"plan_path,omitempty"`
-	PlanTitle     string                 `protobuf:"bytes,7,opt,name=plan_title,json=planTitle,proto3" json:"plan_title,omitempty"`
-	PlanSummary   string                 `protobuf:"bytes,8,opt,name=plan_summary,json=planSummary,proto3" json:"plan_summary,omitempty"`
-	SessionId     string                 `protobuf:"bytes,9,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// InboxRuleProto is an inbox rule.
+type InboxRuleProto struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	AgentId        int64                  `protobuf:"varint,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Name           string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Enabled        bool                   `protobuf:"varint,4,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	StopProcessing bool                   `protobuf:"varint,5,opt,name=stop_processing,json=stopProcessing,proto3" json:"stop_processing,omitempty"`
+	Conditions     *InboxRuleConditions   `protobuf:"bytes,6,opt,name=conditions,proto3" json:"conditions,omitempty"`
+	Actions        *InboxRuleActions      `protobuf:"bytes,7,opt,name=actions,proto3" json:"actions,omitempty"`
+	MatchCount     int64                  `protobuf:"varint,8,opt,name=match_count,json=matchCount,proto3" json:"match_count,omitempty"`
+	LastMatchedAt  *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=last_matched_at,json=lastMatchedAt,proto3" json:"last_matched_at,omitempty"` // null if never matched
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *CreatePlanReviewRequest) Reset() {
-	*x = CreatePlanReviewRequest{}
-	mi := &file_mail_proto_msgTypes[144]
+func (x *InboxRuleProto) Reset() {
+	*x = InboxRuleProto{}
+	mi := &file_mail_proto_msgTypes[267]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreatePlanReviewRequest) String() string {
+func (x *InboxRuleProto) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreatePlanReviewRequest) ProtoMessage() {}
+func (*InboxRuleProto) ProtoMessage() {}
 
-func (x *CreatePlanReviewRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[144]
+func (x *InboxRuleProto) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[267]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9479,97 +17740,115 @@ func (x *CreatePlanReviewRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreatePlanReviewRequest.ProtoReflect.Descriptor instead.
-func (*CreatePlanReviewRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{144}
+// Deprecated: Use InboxRuleProto.ProtoReflect.Descriptor instead.
+func (*InboxRuleProto) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{267}
 }
 
-func (x *CreatePlanReviewRequest) GetPlanReviewId() string {
+func (x *InboxRuleProto) GetId() int64 {
 	if x != nil {
-		return x.PlanReviewId
+		return x.Id
 	}
-	return ""
+	return 0
 }
 
-func (x *CreatePlanReviewRequest) GetMessageId() int64 {
+func (x *InboxRuleProto) GetAgentId() int64 {
 	if x != nil {
-		return x.MessageId
+		return x.AgentId
 	}
 	return 0
 }
 
-func (x *CreatePlanReviewRequest) GetThreadId() string {
+func (x *InboxRuleProto) GetName() string {
 	if x != nil {
-		return x.ThreadId
+		return x.Name
 	}
 	return ""
 }
 
-func (x *CreatePlanReviewRequest) GetRequesterId() int64 {
+func (x *InboxRuleProto) GetEnabled() bool {
 	if x != nil {
-		return x.RequesterId
+		return x.Enabled
 	}
-	return 0
+	return false
 }
 
-func (x *CreatePlanReviewRequest) GetReviewerName() string {
+func (x *InboxRuleProto) GetStopProcessing() bool {
 	if x != nil {
-		return x.ReviewerName
+		return x.StopProcessing
 	}
-	return ""
+	return false
 }
 
-func (x *CreatePlanReviewRequest) GetPlanPath() string {
+func (x *InboxRuleProto) GetConditions() *InboxRuleConditions {
 	if x != nil {
-		return x.PlanPath
+		return x.Conditions
 	}
-	return ""
+	return nil
 }
 
-func (x *CreatePlanReviewRequest) GetPlanTitle() string {
+func (x *InboxRuleProto) GetActions() *InboxRuleActions {
 	if x != nil {
-		return x.PlanTitle
+		return x.Actions
 	}
-	return ""
+	return nil
 }
 
-func (x *CreatePlanReviewRequest) GetPlanSummary() string {
+func (x *InboxRuleProto) GetMatchCount() int64 {
 	if x != nil {
-		return x.PlanSummary
+		return x.MatchCount
 	}
-	return ""
+	return 0
 }
 
-func (x *CreatePlanReviewRequest) GetSessionId() string {
+func (x *InboxRuleProto) GetLastMatchedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.SessionId
+		return x.LastMatchedAt
 	}
-	return ""
+	return nil
 }
 
-// GetPlanReviewRequest is the request for GetPlanReview.
-type GetPlanReviewRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	PlanReviewId  string                 `protobuf:"bytes,1,opt,name=plan_review_id,json=planReviewId,proto3" json:"plan_review_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *InboxRuleProto) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
 }
 
-func (x *GetPlanReviewRequest) Reset() {
-	*x = GetPlanReviewRequest{}
-	mi := &file_mail_proto_msgTypes[145]
+func (x *InboxRuleProto) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// CreateInboxRuleRequest is the request for CreateInboxRule.
+type CreateInboxRuleRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AgentId        int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	StopProcessing bool                   `protobuf:"varint,3,opt,name=stop_processing,json=stopProcessing,proto3" json:"stop_processing,omitempty"` // Skip the agent's later rules on a match.
+	Conditions     *InboxRuleConditions   `protobuf:"bytes,4,opt,name=conditions,proto3" json:"conditions,omitempty"`
+	Actions        *InboxRuleActions      `protobuf:"bytes,5,opt,name=actions,proto3" json:"actions,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateInboxRuleRequest) Reset() {
+	*x = CreateInboxRuleRequest{}
+	mi := &file_mail_proto_msgTypes[268]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPlanReviewRequest) String() string {
+func (x *CreateInboxRuleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPlanReviewRequest) ProtoMessage() {}
+func (*CreateInboxRuleRequest) ProtoMessage() {}
 
-func (x *GetPlanReviewRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[145]
+func (x *CreateInboxRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[268]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9580,41 +17859,69 @@ func (x *GetPlanReviewRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPlanReviewRequest.ProtoReflect.Descriptor instead.
-func (*GetPlanReviewRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{145}
+// Deprecated: Use CreateInboxRuleRequest.ProtoReflect.Descriptor instead.
+func (*CreateInboxRuleRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{268}
 }
 
-func (x *GetPlanReviewRequest) GetPlanReviewId() string {
+func (x *CreateInboxRuleRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.PlanReviewId
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *CreateInboxRuleRequest) GetName() string {
+	if x != nil {
+		return x.Name
 	}
 	return ""
 }
 
-// GetPlanReviewByThreadRequest is the request for GetPlanReviewByThread.
-type GetPlanReviewByThreadRequest struct {
+func (x *CreateInboxRuleRequest) GetStopProcessing() bool {
+	if x != nil {
+		return x.StopProcessing
+	}
+	return false
+}
+
+func (x *CreateInboxRuleRequest) GetConditions() *InboxRuleConditions {
+	if x != nil {
+		return x.Conditions
+	}
+	return nil
+}
+
+func (x *CreateInboxRuleRequest) GetActions() *InboxRuleActions {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+// ListInboxRulesRequest is the request for ListInboxRules.
+type ListInboxRulesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ThreadId      string                 `protobuf:"bytes,1,opt,name=thread_id,json=threadId,proto3" json:"thread_id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPlanReviewByThreadRequest) Reset() {
-	*x = GetPlanReviewByThreadRequest{}
-	mi := &file_mail_proto_msgTypes[146]
+func (x *ListInboxRulesRequest) Reset() {
+	*x = ListInboxRulesRequest{}
+	mi := &file_mail_proto_msgTypes[269]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPlanReviewByThreadRequest) String() string {
+func (x *ListInboxRulesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPlanReviewByThreadRequest) ProtoMessage() {}
+func (*ListInboxRulesRequest) ProtoMessage() {}
 
-func (x *GetPlanReviewByThreadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[146]
+func (x *ListInboxRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[269]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9625,41 +17932,41 @@ func (x *GetPlanReviewByThreadRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPlanReviewByThreadRequest.ProtoReflect.Descriptor instead.
-func (*GetPlanReviewByThreadRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{146}
+// Deprecated: Use ListInboxRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListInboxRulesRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{269}
 }
 
-func (x *GetPlanReviewByThreadRequest) GetThreadId() string {
+func (x *ListInboxRulesRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.ThreadId
+		return x.AgentId
 	}
-	return ""
+	return 0
 }
 
-// GetPlanReviewBySessionRequest is the request for GetPlanReviewBySession.
-type GetPlanReviewBySessionRequest struct {
+// ListInboxRulesResponse is the response for ListInboxRules.
+type ListInboxRulesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Rules         []*InboxRuleProto      `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPlanReviewBySessionRequest) Reset() {
-	*x = GetPlanReviewBySessionRequest{}
-	mi := &file_mail_proto_msgTypes[147]
+func (x *ListInboxRulesResponse) Reset() {
+	*x = ListInboxRulesResponse{}
+	mi := &file_mail_proto_msgTypes[270]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPlanReviewBySessionRequest) String() string {
+func (x *ListInboxRulesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPlanReviewBySessionRequest) ProtoMessage() {}
+func (*ListInboxRulesResponse) ProtoMessage() {}
 
-func (x *GetPlanReviewBySessionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[147]
+func (x *ListInboxRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[270]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9670,44 +17977,42 @@ func (x *GetPlanReviewBySessionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPlanReviewBySessionRequest.ProtoReflect.Descriptor instead.
-func (*GetPlanReviewBySessionRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{147}
+// Deprecated: Use ListInboxRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListInboxRulesResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{270}
 }
 
-func (x *GetPlanReviewBySessionRequest) GetSessionId() string {
+func (x *ListInboxRulesResponse) GetRules() []*InboxRuleProto {
 	if x != nil {
-		return x.SessionId
+		return x.Rules
 	}
-	return ""
+	return nil
 }
 
-// ListPlanReviewsRequest is the request for ListPlanReviews.
-type ListPlanReviewsRequest struct {
+// DeleteInboxRuleRequest is the request for DeleteInboxRule.
+type DeleteInboxRuleRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
-	RequesterId   int64                  `protobuf:"varint,2,opt,name=requester_id,json=requesterId,proto3" json:"requester_id,omitempty"`
-	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListPlanReviewsRequest) Reset() {
-	*x = ListPlanReviewsRequest{}
-	mi := &file_mail_proto_msgTypes[148]
+func (x *DeleteInboxRuleRequest) Reset() {
+	*x = DeleteInboxRuleRequest{}
+	mi := &file_mail_proto_msgTypes[271]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListPlanReviewsRequest) String() string {
+func (x *DeleteInboxRuleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListPlanReviewsRequest) ProtoMessage() {}
+func (*DeleteInboxRuleRequest) ProtoMessage() {}
 
-func (x *ListPlanReviewsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[148]
+func (x *DeleteInboxRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[271]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9718,62 +18023,47 @@ func (x *ListPlanReviewsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListPlanReviewsRequest.ProtoReflect.Descriptor instead.
-func (*ListPlanReviewsRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{148}
-}
-
-func (x *ListPlanReviewsRequest) GetState() string {
-	if x != nil {
-		return x.State
-	}
-	return ""
-}
-
-func (x *ListPlanReviewsRequest) GetRequesterId() int64 {
-	if x != nil {
-		return x.RequesterId
-	}
-	return 0
+// Deprecated: Use DeleteInboxRuleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteInboxRuleRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{271}
 }
 
-func (x *ListPlanReviewsRequest) GetLimit() int32 {
+func (x *DeleteInboxRuleRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.Limit
+		return x.AgentId
 	}
 	return 0
 }
 
-func (x *ListPlanReviewsRequest) GetOffset() int32 {
+func (x *DeleteInboxRuleRequest) GetName() string {
 	if x != nil {
-		return x.Offset
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-// ListPlanReviewsResponse is the response for ListPlanReviews.
-type ListPlanReviewsResponse struct {
+// DeleteInboxRuleResponse is the response for DeleteInboxRule.
+type DeleteInboxRuleResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PlanReviews   []*PlanReviewProto     `protobuf:"bytes,1,rep,name=plan_reviews,json=planReviews,proto3" json:"plan_reviews,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListPlanReviewsResponse) Reset() {
-	*x = ListPlanReviewsResponse{}
-	mi := &file_mail_proto_msgTypes[149]
+func (x *DeleteInboxRuleResponse) Reset() {
+	*x = DeleteInboxRuleResponse{}
+	mi := &file_mail_proto_msgTypes[272]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListPlanReviewsResponse) String() string {
+func (x *DeleteInboxRuleResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListPlanReviewsResponse) ProtoMessage() {}
+func (*DeleteInboxRuleResponse) ProtoMessage() {}
 
-func (x *ListPlanReviewsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[149]
+func (x *DeleteInboxRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[272]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9784,110 +18074,99 @@ func (x *ListPlanReviewsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListPlanReviewsResponse.ProtoReflect.Descriptor instead.
-func (*ListPlanReviewsResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{149}
-}
-
-func (x *ListPlanReviewsResponse) GetPlanReviews() []*PlanReviewProto {
-	if x != nil {
-		return x.PlanReviews
-	}
-	return nil
+// Deprecated: Use DeleteInboxRuleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteInboxRuleResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{272}
 }
 
-// UpdatePlanReviewStatusRequest is the request for UpdatePlanReviewStatus.
-type UpdatePlanReviewStatusRequest struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	PlanReviewId    string                 `protobuf:"bytes,1,opt,name=plan_review_id,json=planReviewId,proto3" json:"plan_review_id,omitempty"`
-	State           string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
-	ReviewerComment string                 `protobuf:"bytes,3,opt,name=reviewer_comment,json=reviewerComment,proto3" json:"reviewer_comment,omitempty"`
-	ReviewedBy      int64                  `protobuf:"varint,4,opt,name=reviewed_by,json=reviewedBy,proto3" json:"reviewed_by,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+// SetInboxRuleEnabledRequest is the request for SetInboxRuleEnabled.
+type SetInboxRuleEnabledRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Enabled       bool                   `protobuf:"varint,3,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdatePlanReviewStatusRequest) Reset() {
-	*x = UpdatePlanReviewStatusRequest{}
-	mi := &file_mail_proto_msgTypes[150]
+func (x *SetInboxRuleEnabledRequest) Reset() {
+	*x = SetInboxRuleEnabledRequest{}
+	mi := &file_mail_proto_msgTypes[273]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdatePlanReviewStatusRequest) String() string {
+func (x *SetInboxRuleEnabledRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdatePlanReviewStatusRequest) ProtoMessage() {}
+func (*SetInboxRuleEnabledRequest) ProtoMessage() {}
 
-func (x *UpdatePlanReviewStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[150]
+func (x *SetInboxRuleEnabledRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[273]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use UpdatePlanReviewStatusRequest.ProtoReflect.Descriptor instead.
-func (*UpdatePlanReviewStatusRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{150}
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (x *UpdatePlanReviewStatusRequest) GetPlanReviewId() string {
-	if x != nil {
-		return x.PlanReviewId
-	}
-	return ""
+// Deprecated: Use SetInboxRuleEnabledRequest.ProtoReflect.Descriptor instead.
+func (*SetInboxRuleEnabledRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{273}
 }
 
-func (x *UpdatePlanReviewStatusRequest) GetState() string {
+func (x *SetInboxRuleEnabledRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.State
+		return x.AgentId
 	}
-	return ""
+	return 0
 }
 
-func (x *UpdatePlanReviewStatusRequest) GetReviewerComment() string {
+func (x *SetInboxRuleEnabledRequest) GetName() string {
 	if x != nil {
-		return x.ReviewerComment
+		return x.Name
 	}
 	return ""
 }
 
-func (x *UpdatePlanReviewStatusRequest) GetReviewedBy() int64 {
+func (x *SetInboxRuleEnabledRequest) GetEnabled() bool {
 	if x != nil {
-		return x.ReviewedBy
+		return x.Enabled
 	}
-	return 0
+	return false
 }
 
-// DeletePlanReviewRequest is the request for DeletePlanReview.
-type DeletePlanReviewRequest struct {
+// TestInboxRuleRequest is the request for TestInboxRule. It tries either a
+// saved rule by name or the given conditions.
+type TestInboxRuleRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PlanReviewId  string                 `protobuf:"bytes,1,opt,name=plan_review_id,json=planReviewId,proto3" json:"plan_review_id,omitempty"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Conditions    *InboxRuleConditions   `protobuf:"bytes,3,opt,name=conditions,proto3" json:"conditions,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"` // Messages to try, newest first. Defaults to 100.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeletePlanReviewRequest) Reset() {
-	*x = DeletePlanReviewRequest{}
-	mi := &file_mail_proto_msgTypes[151]
+func (x *TestInboxRuleRequest) Reset() {
+	*x = TestInboxRuleRequest{}
+	mi := &file_mail_proto_msgTypes[274]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeletePlanReviewRequest) String() string {
+func (x *TestInboxRuleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeletePlanReviewRequest) ProtoMessage() {}
+func (*TestInboxRuleRequest) ProtoMessage() {}
 
-func (x *DeletePlanReviewRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[151]
+func (x *TestInboxRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[274]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9898,41 +18177,63 @@ func (x *DeletePlanReviewRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeletePlanReviewRequest.ProtoReflect.Descriptor instead.
-func (*DeletePlanReviewRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{151}
+// Deprecated: Use TestInboxRuleRequest.ProtoReflect.Descriptor instead.
+func (*TestInboxRuleRequest) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{274}
 }
 
-func (x *DeletePlanReviewRequest) GetPlanReviewId() string {
+func (x *TestInboxRuleRequest) GetAgentId() int64 {
 	if x != nil {
-		return x.PlanReviewId
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *TestInboxRuleRequest) GetName() string {
+	if x != nil {
+		return x.Name
 	}
 	return ""
 }
 
-// DeletePlanReviewResponse is the response for DeletePlanReview.
-type DeletePlanReviewResponse struct {
+func (x *TestInboxRuleRequest) GetConditions() *InboxRuleConditions {
+	if x != nil {
+		return x.Conditions
+	}
+	return nil
+}
+
+func (x *TestInboxRuleRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// TestInboxRuleResponse is the response for TestInboxRule.
+type TestInboxRuleResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Error         string                 `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Scanned       int32                  `protobuf:"varint,1,opt,name=scanned,proto3" json:"scanned,omitempty"`
+	Matches       []*InboxMessage        `protobuf:"bytes,2,rep,name=matches,proto3" json:"matches,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeletePlanReviewResponse) Reset() {
-	*x = DeletePlanReviewResponse{}
-	mi := &file_mail_proto_msgTypes[152]
+func (x *TestInboxRuleResponse) Reset() {
+	*x = TestInboxRuleResponse{}
+	mi := &file_mail_proto_msgTypes[275]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeletePlanReviewResponse) String() string {
+func (x *TestInboxRuleResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeletePlanReviewResponse) ProtoMessage() {}
+func (*TestInboxRuleResponse) ProtoMessage() {}
 
-func (x *DeletePlanReviewResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[152]
+func (x *TestInboxRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mail_proto_msgTypes[275]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -9943,16 +18244,23 @@ func (x *DeletePlanReviewResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeletePlanReviewResponse.ProtoReflect.Descriptor instead.
-func (*DeletePlanReviewResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{152}
+// Deprecated: Use TestInboxRuleResponse.ProtoReflect.Descriptor instead.
+func (*TestInboxRuleResponse) Descriptor() ([]byte, []int) {
+	return file_mail_proto_rawDescGZIP(), []int{275}
 }
 
-func (x *DeletePlanReviewResponse) GetError() string {
+func (x *TestInboxRuleResponse) GetScanned() int32 {
 	if x != nil {
-		return x.Error
+		return x.Scanned
 	}
-	return ""
+	return 0
+}
+
+func (x *TestInboxRuleResponse) GetMatches() []*InboxMessage {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
 }
 
 // PlanAnnotationProto represents a plan annotation record.
@@ -9976,7 +18284,7 @@ type PlanAnnotationProto struct {
 
 func (x *PlanAnnotationProto) Reset() {
 	*x = PlanAnnotationProto{}
-	mi := &file_mail_proto_msgTypes[153]
+	mi := &file_mail_proto_msgTypes[276]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -9988,7 +18296,7 @@ func (x *PlanAnnotationProto) String() string {
 func (*PlanAnnotationProto) ProtoMessage() {}
 
 func (x *PlanAnnotationProto) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[153]
+	mi := &file_mail_proto_msgTypes[276]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -10001,7 +18309,7 @@ func (x *PlanAnnotationProto) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PlanAnnotationProto.ProtoReflect.Descriptor instead.
 func (*PlanAnnotationProto) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{153}
+	return file_mail_proto_rawDescGZIP(), []int{276}
 }
 
 func (x *PlanAnnotationProto) GetId() int64 {
@@ -10111,7 +18419,7 @@ type DiffAnnotationProto struct {
 
 func (x *DiffAnnotationProto) Reset() {
 	*x = DiffAnnotationProto{}
-	mi := &file_mail_proto_msgTypes[154]
+	mi := &file_mail_proto_msgTypes[277]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -10123,7 +18431,7 @@ func (x *DiffAnnotationProto) String() string {
 func (*DiffAnnotationProto) ProtoMessage() {}
 
 func (x *DiffAnnotationProto) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[154]
+	mi := &file_mail_proto_msgTypes[277]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -10136,7 +18444,7 @@ func (x *DiffAnnotationProto) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DiffAnnotationProto.ProtoReflect.Descriptor instead.
 func (*DiffAnnotationProto) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{154}
+	return file_mail_proto_rawDescGZIP(), []int{277}
 }
 
 func (x *DiffAnnotationProto) GetId() int64 {
@@ -10255,7 +18563,7 @@ type CreatePlanAnnotationRequest struct {
 
 func (x *CreatePlanAnnotationRequest) Reset() {
 	*x = CreatePlanAnnotationRequest{}
-	mi := &file_mail_proto_msgTypes[155]
+	mi := &file_mail_proto_msgTypes[278]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -10267,7 +18575,7 @@ func (x *CreatePlanAnnotationRequest) String() string {
 func (*CreatePlanAnnotationRequest) ProtoMessage() {}
 
 func (x *CreatePlanAnnotationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[155]
+	mi := &file_mail_proto_msgTypes[278]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -10280,7 +18588,7 @@ func (x *CreatePlanAnnotationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreatePlanAnnotationRequest.ProtoReflect.Descriptor instead.
 func (*CreatePlanAnnotationRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{155}
+	return file_mail_proto_rawDescGZIP(), []int{278}
 }
 
 func (x *CreatePlanAnnotationRequest) GetPlanReviewId() string {
@@ -10356,7 +18664,7 @@ type ListPlanAnnotationsRequest struct {
 
 func (x *ListPlanAnnotationsRequest) Reset() {
 	*x = ListPlanAnnotationsRequest{}
-	mi := &file_mail_proto_msgTypes[156]
+	mi := &file_mail_proto_msgTypes[279]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -10368,7 +18676,7 @@ func (x *ListPlanAnnotationsRequest) String() string {
 func (*ListPlanAnnotationsRequest) ProtoMessage() {}
 
 func (x *ListPlanAnnotationsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[156]
+	mi := &file_mail_proto_msgTypes[279]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -10381,7 +18689,7 @@ func (x *ListPlanAnnotationsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListPlanAnnotationsRequest.ProtoReflect.Descriptor instead.
 func (*ListPlanAnnotationsRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{156}
+	return file_mail_proto_rawDescGZIP(), []int{279}
 }
 
 func (x *ListPlanAnnotationsRequest) GetPlanReviewId() string {
@@ -10401,7 +18709,7 @@ type ListPlanAnnotationsResponse struct {
 
 func (x *ListPlanAnnotationsResponse) Reset() {
 	*x = ListPlanAnnotationsResponse{}
-	mi := &file_mail_proto_msgTypes[157]
+	mi := &file_mail_proto_msgTypes[280]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -10413,7 +18721,7 @@ func (x *ListPlanAnnotationsResponse) String() string {
 func (*ListPlanAnnotationsResponse) ProtoMessage() {}
 
 func (x *ListPlanAnnotationsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[157]
+	mi := &file_mail_proto_msgTypes[280]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -10426,7 +18734,7 @@ func (x *ListPlanAnnotationsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListPlanAnnotationsResponse.ProtoReflect.Descriptor instead.
 func (*ListPlanAnnotationsResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{157}
+	return file_mail_proto_rawDescGZIP(), []int{280}
 }
 
 func (x *ListPlanAnnotationsResponse) GetAnnotations() []*PlanAnnotationProto {
@@ -10451,7 +18759,7 @@ type UpdatePlanAnnotationRequest struct {
 
 func (x *UpdatePlanAnnotationRequest) Reset() {
 	*x = UpdatePlanAnnotationRequest{}
-	mi := &file_mail_proto_msgTypes[158]
+	mi := &file_mail_proto_msgTypes[281]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -10463,7 +18771,7 @@ func (x *UpdatePlanAnnotationRequest) String() string {
 func (*UpdatePlanAnnotationRequest) ProtoMessage() {}
 
 func (x *UpdatePlanAnnotationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[158]
+	mi := &file_mail_proto_msgTypes[281]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -10476,7 +18784,7 @@ func (x *UpdatePlanAnnotationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdatePlanAnnotationRequest.ProtoReflect.Descriptor instead.
 func (*UpdatePlanAnnotationRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{158}
+	return file_mail_proto_rawDescGZIP(), []int{281}
 }
 
 func (x *UpdatePlanAnnotationRequest) GetAnnotationId() string {
@@ -10531,7 +18839,7 @@ type DeletePlanAnnotationRequest struct {
 
 func (x *DeletePlanAnnotationRequest) Reset() {
 	*x = DeletePlanAnnotationRequest{}
-	mi := &file_mail_proto_msgTypes[159]
+	mi := &file_mail_proto_msgTypes[282]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -10543,7 +18851,7 @@ func (x *DeletePlanAnnotationRequest) String() string {
 func (*DeletePlanAnnotationRequest) ProtoMessage() {}
 
 func (x *DeletePlanAnnotationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[159]
+	mi := &file_mail_proto_msgTypes[282]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -10556,7 +18864,7 @@ func (x *DeletePlanAnnotationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeletePlanAnnotationRequest.ProtoReflect.Descriptor instead.
 func (*DeletePlanAnnotationRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{159}
+	return file_mail_proto_rawDescGZIP(), []int{282}
 }
 
 func (x *DeletePlanAnnotationRequest) GetAnnotationId() string {
@@ -10576,7 +18884,7 @@ type DeleteAnnotationResponse struct {
 
 func (x *DeleteAnnotationResponse) Reset() {
 	*x = DeleteAnnotationResponse{}
-	mi := &file_mail_proto_msgTypes[160]
+	mi := &file_mail_proto_msgTypes[283]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -10588,7 +18896,7 @@ func (x *DeleteAnnotationResponse) String() string {
 func (*DeleteAnnotationResponse) ProtoMessage() {}
 
 func (x *DeleteAnnotationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[160]
+	mi := &file_mail_proto_msgTypes[283]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -10601,7 +18909,7 @@ func (x *DeleteAnnotationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteAnnotationResponse.ProtoReflect.Descriptor instead.
 func (*DeleteAnnotationResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{160}
+	return file_mail_proto_rawDescGZIP(), []int{283}
 }
 
 func (x *DeleteAnnotationResponse) GetError() string {
@@ -10631,7 +18939,7 @@ type CreateDiffAnnotationRequest struct {
 
 func (x *CreateDiffAnnotationRequest) Reset() {
 	*x = CreateDiffAnnotationRequest{}
-	mi := &file_mail_proto_msgTypes[161]
+	mi := &file_mail_proto_msgTypes[284]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -10643,7 +18951,7 @@ func (x *CreateDiffAnnotationRequest) String() string {
 func (*CreateDiffAnnotationRequest) ProtoMessage() {}
 
 func (x *CreateDiffAnnotationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[161]
+	mi := &file_mail_proto_msgTypes[284]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -10656,7 +18964,7 @@ func (x *CreateDiffAnnotationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateDiffAnnotationRequest.ProtoReflect.Descriptor instead.
 func (*CreateDiffAnnotationRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{161}
+	return file_mail_proto_rawDescGZIP(), []int{284}
 }
 
 func (x *CreateDiffAnnotationRequest) GetAnnotationId() string {
@@ -10746,7 +19054,7 @@ type ListDiffAnnotationsRequest struct {
 
 func (x *ListDiffAnnotationsRequest) Reset() {
 	*x = ListDiffAnnotationsRequest{}
-	mi := &file_mail_proto_msgTypes[162]
+	mi := &file_mail_proto_msgTypes[285]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -10758,7 +19066,7 @@ func (x *ListDiffAnnotationsRequest) String() string {
 func (*ListDiffAnnotationsRequest) ProtoMessage() {}
 
 func (x *ListDiffAnnotationsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[162]
+	mi := &file_mail_proto_msgTypes[285]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -10771,7 +19079,7 @@ func (x *ListDiffAnnotationsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDiffAnnotationsRequest.ProtoReflect.Descriptor instead.
 func (*ListDiffAnnotationsRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{162}
+	return file_mail_proto_rawDescGZIP(), []int{285}
 }
 
 func (x *ListDiffAnnotationsRequest) GetMessageId() int64 {
@@ -10791,7 +19099,7 @@ type ListDiffAnnotationsResponse struct {
 
 func (x *ListDiffAnnotationsResponse) Reset() {
 	*x = ListDiffAnnotationsResponse{}
-	mi := &file_mail_proto_msgTypes[163]
+	mi := &file_mail_proto_msgTypes[286]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -10803,7 +19111,7 @@ func (x *ListDiffAnnotationsResponse) String() string {
 func (*ListDiffAnnotationsResponse) ProtoMessage() {}
 
 func (x *ListDiffAnnotationsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[163]
+	mi := &file_mail_proto_msgTypes[286]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -10816,7 +19124,7 @@ func (x *ListDiffAnnotationsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListDiffAnnotationsResponse.ProtoReflect.Descriptor instead.
 func (*ListDiffAnnotationsResponse) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{163}
+	return file_mail_proto_rawDescGZIP(), []int{286}
 }
 
 func (x *ListDiffAnnotationsResponse) GetAnnotations() []*DiffAnnotationProto {
@@ -10839,7 +19147,7 @@ type UpdateDiffAnnotationRequest struct {
 
 func (x *UpdateDiffAnnotationRequest) Reset() {
 	*x = UpdateDiffAnnotationRequest{}
-	mi := &file_mail_proto_msgTypes[164]
+	mi := &file_mail_proto_msgTypes[287]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -10851,7 +19159,7 @@ func (x *UpdateDiffAnnotationRequest) String() string {
 func (*UpdateDiffAnnotationRequest) ProtoMessage() {}
 
 func (x *UpdateDiffAnnotationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[164]
+	mi := &file_mail_proto_msgTypes[287]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -10864,7 +19172,7 @@ func (x *UpdateDiffAnnotationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateDiffAnnotationRequest.ProtoReflect.Descriptor instead.
 func (*UpdateDiffAnnotationRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{164}
+	return file_mail_proto_rawDescGZIP(), []int{287}
 }
 
 func (x *UpdateDiffAnnotationRequest) GetAnnotationId() string {
@@ -10905,7 +19213,7 @@ type DeleteDiffAnnotationRequest struct {
 
 func (x *DeleteDiffAnnotationRequest) Reset() {
 	*x = DeleteDiffAnnotationRequest{}
-	mi := &file_mail_proto_msgTypes[165]
+	mi := &file_mail_proto_msgTypes[288]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -10917,7 +19225,7 @@ func (x *DeleteDiffAnnotationRequest) String() string {
 func (*DeleteDiffAnnotationRequest) ProtoMessage() {}
 
 func (x *DeleteDiffAnnotationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mail_proto_msgTypes[165]
+	mi := &file_mail_proto_msgTypes[288]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -10930,7 +19238,7 @@ func (x *DeleteDiffAnnotationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteDiffAnnotationRequest.ProtoReflect.Descriptor instead.
 func (*DeleteDiffAnnotationRequest) Descriptor() ([]byte, []int) {
-	return file_mail_proto_rawDescGZIP(), []int{165}
+	return file_mail_proto_rawDescGZIP(), []int{288}
 }
 
 func (x *DeleteDiffAnnotationRequest) GetAnnotationId() string {
@@ -10945,7 +19253,7 @@ var File_mail_proto protoreflect.FileDescriptor
 const file_mail_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"mail.proto\x12\vsubtraterpc\x1a\x1fgoogle/protobuf/timestamp.proto\"\xdc\x05\n" +
+	"mail.proto\x12\vsubtraterpc\x1a\x1fgoogle/protobuf/timestamp.proto\"\xad\x06\n" +
 	"\fInboxMessage\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
 	"\tthread_id\x18\x02 \x01(\tR\bthreadId\x12\x19\n" +
@@ -10967,7 +19275,9 @@ const file_mail_proto_rawDesc = "" +
 	"\rsnoozed_until\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\fsnoozedUntil\x123\n" +
 	"\aread_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\x06readAt\x12C\n" +
 	"\x0facknowledged_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\x0eacknowledgedAt\x12'\n" +
-	"\x0frecipient_names\x18\x11 \x03(\tR\x0erecipientNames\"\x85\x03\n" +
+	"\x0frecipient_names\x18\x11 \x03(\tR\x0erecipientNames\x127\n" +
+	"\tedited_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\beditedAt\x12\x16\n" +
+	"\x06labels\x18\x13 \x03(\tR\x06labels\"\xff\x04\n" +
 	"\x0fSendMailRequest\x12\x1b\n" +
 	"\tsender_id\x18\x01 \x01(\x03R\bsenderId\x12'\n" +
 	"\x0frecipient_names\x18\x02 \x03(\tR\x0erecipientNames\x12\x1d\n" +
@@ -10981,11 +19291,33 @@ const file_mail_proto_rawDesc = "" +
 	"deadlineAt\x12)\n" +
 	"\x10attachments_json\x18\t \x01(\tR\x0fattachmentsJson\x12'\n" +
 	"\x0fidempotency_key\x18\n" +
-	" \x01(\tR\x0eidempotencyKey\"N\n" +
+	" \x01(\tR\x0eidempotencyKey\x12>\n" +
+	"\rdelivery_mode\x18\v \x01(\x0e2\x19.subtraterpc.DeliveryModeR\fdeliveryMode\x123\n" +
+	"\asend_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\x06sendAt\x12F\n" +
+	"\bmetadata\x18\r \x03(\v2*.subtraterpc.SendMailRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xf2\x01\n" +
 	"\x10SendMailResponse\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\x03R\tmessageId\x12\x1b\n" +
-	"\tthread_id\x18\x02 \x01(\tR\bthreadId\"\xa2\x02\n" +
+	"\tthread_id\x18\x02 \x01(\tR\bthreadId\x12?\n" +
+	"\n" +
+	"expansions\x18\x03 \x03(\v2\x1f.subtraterpc.RecipientExpansionR\n" +
+	"expansions\x12>\n" +
+	"\n" +
+	"deliveries\x18\x04 \x03(\v2\x1e.subtraterpc.RecipientDeliveryR\n" +
+	"deliveries\x12!\n" +
+	"\fscheduled_id\x18\x05 \x01(\x03R\vscheduledId\"\x9a\x01\n" +
+	"\x11RecipientDelivery\x12\x1c\n" +
+	"\trecipient\x18\x01 \x01(\tR\trecipient\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\x03R\aagentId\x124\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x1c.subtraterpc.RecipientStatusR\x06status\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"O\n" +
+	"\x12RecipientExpansion\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x1f\n" +
+	"\vagent_names\x18\x02 \x03(\tR\n" +
+	"agentNames\"\xdb\x02\n" +
 	"\x11FetchInboxRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x1f\n" +
@@ -10995,26 +19327,35 @@ const file_mail_proto_rawDesc = "" +
 	"\tsent_only\x18\x05 \x01(\bR\bsentOnly\x12,\n" +
 	"\x12sender_name_prefix\x18\x06 \x01(\tR\x10senderNamePrefix\x12\x16\n" +
 	"\x06offset\x18\a \x01(\x05R\x06offset\x12\x1a\n" +
-	"\bcategory\x18\b \x01(\tR\bcategory\"\x96\x01\n" +
+	"\bcategory\x18\b \x01(\tR\bcategory\x12!\n" +
+	"\foverdue_only\x18\t \x01(\bR\voverdueOnly\x12\x14\n" +
+	"\x05label\x18\n" +
+	" \x01(\tR\x05label\"\x96\x01\n" +
 	"\x12FetchInboxResponse\x125\n" +
 	"\bmessages\x18\x01 \x03(\v2\x19.subtraterpc.InboxMessageR\bmessages\x12I\n" +
-	"\x0fcategory_counts\x18\x02 \x01(\v2 .subtraterpc.InboxCategoryCountsR\x0ecategoryCounts\"\x87\x01\n" +
+	"\x0fcategory_counts\x18\x02 \x01(\v2 .subtraterpc.InboxCategoryCountsR\x0ecategoryCounts\"\xb8\x01\n" +
 	"\x13InboxCategoryCounts\x12\x18\n" +
 	"\aprimary\x18\x01 \x01(\x03R\aprimary\x12$\n" +
 	"\rnotifications\x18\x03 \x01(\x03R\rnotifications\x12\x16\n" +
 	"\x06unread\x18\x04 \x01(\x03R\x06unread\x12\x18\n" +
-	"\astarred\x18\x05 \x01(\x03R\astarred\"N\n" +
+	"\astarred\x18\x05 \x01(\x03R\astarred\x12/\n" +
+	"\x06labels\x18\x06 \x03(\v2\x17.subtraterpc.LabelProtoR\x06labels\"N\n" +
 	"\x12ReadMessageRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1d\n" +
 	"\n" +
-	"message_id\x18\x02 \x01(\x03R\tmessageId\"J\n" +
+	"message_id\x18\x02 \x01(\x03R\tmessageId\"\xc7\x01\n" +
 	"\x13ReadMessageResponse\x123\n" +
-	"\amessage\x18\x01 \x01(\v2\x19.subtraterpc.InboxMessageR\amessage\"K\n" +
+	"\amessage\x18\x01 \x01(\v2\x19.subtraterpc.InboxMessageR\amessage\x12?\n" +
+	"\n" +
+	"expansions\x18\x02 \x03(\v2\x1f.subtraterpc.RecipientExpansionR\n" +
+	"expansions\x12:\n" +
+	"\trevisions\x18\x03 \x03(\v2\x1c.subtraterpc.MessageRevisionR\trevisions\"K\n" +
 	"\x11ReadThreadRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1b\n" +
-	"\tthread_id\x18\x02 \x01(\tR\bthreadId\"K\n" +
+	"\tthread_id\x18\x02 \x01(\tR\bthreadId\"\x87\x01\n" +
 	"\x12ReadThreadResponse\x125\n" +
-	"\bmessages\x18\x01 \x03(\v2\x19.subtraterpc.InboxMessageR\bmessages\"\xc7\x01\n" +
+	"\bmessages\x18\x01 \x03(\v2\x19.subtraterpc.InboxMessageR\bmessages\x12:\n" +
+	"\trevisions\x18\x02 \x03(\v2\x1c.subtraterpc.MessageRevisionR\trevisions\"\xc7\x01\n" +
 	"\x12UpdateStateRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1d\n" +
 	"\n" +
@@ -11053,7 +19394,33 @@ const file_mail_proto_rawDesc = "" +
 	"\x03key\x18\x01 \x01(\x03R\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"2\n" +
 	"\x15SubscribeInboxRequest\x12\x19\n" +
-	"\bagent_id\x18\x01 \x01(\x03R\aagentId\"\xd6\x01\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\"\xf5\x02\n" +
+	"\x11WatchInboxRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x16\n" +
+	"\x06holder\x18\x02 \x01(\tR\x06holder\x12(\n" +
+	"\x10after_message_id\x18\x03 \x01(\x03R\x0eafterMessageId\x125\n" +
+	"\n" +
+	"priorities\x18\x04 \x03(\x0e2\x15.subtraterpc.PriorityR\n" +
+	"priorities\x12!\n" +
+	"\fsender_names\x18\x05 \x03(\tR\vsenderNames\x12\x1f\n" +
+	"\vtopic_names\x18\x06 \x03(\tR\n" +
+	"topicNames\x12\x1d\n" +
+	"\n" +
+	"thread_ids\x18\a \x03(\tR\tthreadIds\x12!\n" +
+	"\fsaved_search\x18\b \x01(\tR\vsavedSearch\x12*\n" +
+	"\x11lease_ttl_seconds\x18\t \x01(\x05R\x0fleaseTtlSeconds\x12\x1a\n" +
+	"\btakeover\x18\n" +
+	" \x01(\bR\btakeover\"\xbf\x01\n" +
+	"\x0fWatchInboxEvent\x12/\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1b.subtraterpc.WatchEventTypeR\x04type\x125\n" +
+	"\bmessages\x18\x02 \x03(\v2\x19.subtraterpc.InboxMessageR\bmessages\x12D\n" +
+	"\x10lease_expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x0eleaseExpiresAt\".\n" +
+	"\x11GetWatcherRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\"\x88\x01\n" +
+	"\x12GetWatcherResponse\x12\x14\n" +
+	"\x05armed\x18\x01 \x01(\bR\x05armed\x12\x16\n" +
+	"\x06holder\x18\x02 \x01(\tR\x06holder\x12D\n" +
+	"\x10lease_expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x0eleaseExpiresAt\"\xda\x02\n" +
 	"\x0ePublishRequest\x12\x1b\n" +
 	"\tsender_id\x18\x01 \x01(\x03R\bsenderId\x12\x1d\n" +
 	"\n" +
@@ -11061,15 +19428,22 @@ const file_mail_proto_rawDesc = "" +
 	"\asubject\x18\x03 \x01(\tR\asubject\x12\x12\n" +
 	"\x04body\x18\x04 \x01(\tR\x04body\x121\n" +
 	"\bpriority\x18\x05 \x01(\x0e2\x15.subtraterpc.PriorityR\bpriority\x12'\n" +
-	"\x0fidempotency_key\x18\x06 \x01(\tR\x0eidempotencyKey\"[\n" +
+	"\x0fidempotency_key\x18\x06 \x01(\tR\x0eidempotencyKey\x12E\n" +
+	"\bmetadata\x18\a \x03(\v2).subtraterpc.PublishRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"s\n" +
 	"\x0fPublishResponse\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\x03R\tmessageId\x12)\n" +
-	"\x10recipients_count\x18\x02 \x01(\x05R\x0frecipientsCount\"L\n" +
+	"\x10recipients_count\x18\x02 \x01(\x05R\x0frecipientsCount\x12\x16\n" +
+	"\x06queued\x18\x03 \x01(\bR\x06queued\"k\n" +
 	"\x10SubscribeRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1d\n" +
 	"\n" +
-	"topic_name\x18\x02 \x01(\tR\ttopicName\"H\n" +
+	"topic_name\x18\x02 \x01(\tR\ttopicName\x12\x1d\n" +
+	"\n" +
+	"topic_type\x18\x03 \x01(\tR\ttopicType\"H\n" +
 	"\x11SubscribeResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x19\n" +
 	"\btopic_id\x18\x02 \x01(\x03R\atopicId\"N\n" +
@@ -11078,7 +19452,37 @@ const file_mail_proto_rawDesc = "" +
 	"\n" +
 	"topic_name\x18\x02 \x01(\tR\ttopicName\"/\n" +
 	"\x13UnsubscribeResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xaa\x01\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x92\x01\n" +
+	"\x18ClaimQueueMessageRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1d\n" +
+	"\n" +
+	"topic_name\x18\x02 \x01(\tR\ttopicName\x12<\n" +
+	"\x1avisibility_timeout_seconds\x18\x03 \x01(\x03R\x18visibilityTimeoutSeconds\"\xd7\x01\n" +
+	"\x19ClaimQueueMessageResponse\x12\x18\n" +
+	"\aclaimed\x18\x01 \x01(\bR\aclaimed\x123\n" +
+	"\amessage\x18\x02 \x01(\v2\x19.subtraterpc.InboxMessageR\amessage\x12D\n" +
+	"\x10lease_expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x0eleaseExpiresAt\x12%\n" +
+	"\x0edelivery_count\x18\x04 \x01(\x05R\rdeliveryCount\"R\n" +
+	"\x16AckQueueMessageRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\x03R\tmessageId\"3\n" +
+	"\x17AckQueueMessageResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"n\n" +
+	"\x1aReleaseQueueMessageRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\x03R\tmessageId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"7\n" +
+	"\x1bReleaseQueueMessageResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x91\x01\n" +
+	"\x17ExtendQueueLeaseRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\x03R\tmessageId\x12<\n" +
+	"\x1avisibility_timeout_seconds\x18\x03 \x01(\x03R\x18visibilityTimeoutSeconds\"`\n" +
+	"\x18ExtendQueueLeaseResponse\x12D\n" +
+	"\x10lease_expires_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x0eleaseExpiresAt\"\xd7\x01\n" +
 	"\x05Topic\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
@@ -11086,41 +19490,134 @@ const file_mail_proto_rawDesc = "" +
 	"topic_type\x18\x03 \x01(\tR\ttopicType\x129\n" +
 	"\n" +
 	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12#\n" +
-	"\rmessage_count\x18\x05 \x01(\x03R\fmessageCount\"W\n" +
+	"\rmessage_count\x18\x05 \x01(\x03R\fmessageCount\x12+\n" +
+	"\x11retention_seconds\x18\x06 \x01(\x03R\x10retentionSeconds\"W\n" +
 	"\x11ListTopicsRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12'\n" +
 	"\x0fsubscribed_only\x18\x02 \x01(\bR\x0esubscribedOnly\"@\n" +
 	"\x12ListTopicsResponse\x12*\n" +
-	"\x06topics\x18\x01 \x03(\v2\x12.subtraterpc.TopicR\x06topics\"q\n" +
+	"\x06topics\x18\x01 \x03(\v2\x12.subtraterpc.TopicR\x06topics\"\x86\x02\n" +
 	"\rSearchRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x14\n" +
 	"\x05query\x18\x02 \x01(\tR\x05query\x12\x19\n" +
 	"\btopic_id\x18\x03 \x01(\x03R\atopicId\x12\x14\n" +
-	"\x05limit\x18\x04 \x01(\x05R\x05limit\"E\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x14\n" +
+	"\x05label\x18\x05 \x01(\tR\x05label\x12\x16\n" +
+	"\x06cursor\x18\x06 \x01(\tR\x06cursor\x12!\n" +
+	"\finclude_body\x18\a \x01(\bR\vincludeBody\x12!\n" +
+	"\fsaved_search\x18\b \x01(\tR\vsavedSearch\x12\x1f\n" +
+	"\vmessage_ids\x18\t \x03(\x03R\n" +
+	"messageIds\"\x85\x01\n" +
+	"\tSearchHit\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x03R\tmessageId\x12\x18\n" +
+	"\asnippet\x18\x02 \x01(\tR\asnippet\x12+\n" +
+	"\x11subject_highlight\x18\x03 \x01(\tR\x10subjectHighlight\x12\x12\n" +
+	"\x04rank\x18\x04 \x01(\x01R\x04rank\"\xa8\x01\n" +
 	"\x0eSearchResponse\x123\n" +
-	"\aresults\x18\x01 \x03(\v2\x19.subtraterpc.InboxMessageR\aresults\"[\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.subtraterpc.InboxMessageR\aresults\x12*\n" +
+	"\x04hits\x18\x02 \x03(\v2\x16.subtraterpc.SearchHitR\x04hits\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\x12\x1f\n" +
+	"\vnext_cursor\x18\x04 \x01(\tR\n" +
+	"nextCursor\"\x88\x01\n" +
+	"\x10SearchAllRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x12-\n" +
+	"\x05kinds\x18\x03 \x03(\x0e2\x17.subtraterpc.SearchKindR\x05kinds\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"\xf9\x01\n" +
+	"\fSearchAllHit\x12+\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x17.subtraterpc.SearchKindR\x04kind\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03ref\x18\x03 \x01(\tR\x03ref\x12\x1b\n" +
+	"\tthread_id\x18\x04 \x01(\tR\bthreadId\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\x12\x18\n" +
+	"\asnippet\x18\x06 \x01(\tR\asnippet\x12\x12\n" +
+	"\x04rank\x18\a \x01(\x01R\x04rank\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"X\n" +
+	"\x11SearchAllResponse\x12-\n" +
+	"\x04hits\x18\x01 \x03(\v2\x19.subtraterpc.SearchAllHitR\x04hits\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"\xdd\x01\n" +
+	"\x10SavedSearchProto\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05query\x18\x04 \x01(\tR\x05query\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"X\n" +
+	"\x11SaveSearchRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05query\x18\x03 \x01(\tR\x05query\"5\n" +
+	"\x18ListSavedSearchesRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\"V\n" +
+	"\x19ListSavedSearchesResponse\x129\n" +
+	"\bsearches\x18\x01 \x03(\v2\x1d.subtraterpc.SavedSearchProtoR\bsearches\"I\n" +
+	"\x18DeleteSavedSearchRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\x1b\n" +
+	"\x19DeleteSavedSearchResponse\"\x93\x01\n" +
+	"\x15SemanticSearchRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06hybrid\x18\x04 \x01(\bR\x06hybrid\x12\x1b\n" +
+	"\tmin_score\x18\x05 \x01(\x01R\bminScore\"\x99\x01\n" +
+	"\x16SimilarMessagesRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\x03R\tmessageId\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x1b\n" +
+	"\tmin_score\x18\x05 \x01(\x01R\bminScore\"\x9b\x01\n" +
+	"\vSemanticHit\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x03R\tmessageId\x12\x18\n" +
+	"\asnippet\x18\x02 \x01(\tR\asnippet\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x01R\x05score\x12\x1e\n" +
+	"\n" +
+	"similarity\x18\x04 \x01(\x01R\n" +
+	"similarity\x12\x1d\n" +
+	"\n" +
+	"text_score\x18\x05 \x01(\x01R\ttextScore\"{\n" +
+	"\x16SemanticSearchResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.subtraterpc.InboxMessageR\aresults\x12,\n" +
+	"\x04hits\x18\x02 \x03(\v2\x18.subtraterpc.SemanticHitR\x04hits\"[\n" +
 	"\x19HasUnackedStatusToRequest\x12\x1b\n" +
 	"\tsender_id\x18\x01 \x01(\x03R\bsenderId\x12!\n" +
 	"\frecipient_id\x18\x02 \x01(\x03R\vrecipientId\"=\n" +
 	"\x1aHasUnackedStatusToResponse\x12\x1f\n" +
 	"\vhas_pending\x18\x01 \x01(\bR\n" +
-	"hasPending\"j\n" +
+	"hasPending\"\xcd\x01\n" +
 	"\x14RegisterAgentRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1f\n" +
 	"\vproject_key\x18\x02 \x01(\tR\n" +
 	"projectKey\x12\x1d\n" +
 	"\n" +
-	"git_branch\x18\x03 \x01(\tR\tgitBranch\"\x86\x01\n" +
+	"git_branch\x18\x03 \x01(\tR\tgitBranch\x12B\n" +
+	"\fcapabilities\x18\x04 \x01(\v2\x1e.subtraterpc.AgentCapabilitiesR\fcapabilities\x12\x1d\n" +
+	"\n" +
+	"mint_token\x18\x05 \x01(\bR\tmintToken\"\xe0\x01\n" +
 	"\x15RegisterAgentResponse\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1f\n" +
 	"\vproject_key\x18\x03 \x01(\tR\n" +
 	"projectKey\x12\x1d\n" +
 	"\n" +
-	"git_branch\x18\x04 \x01(\tR\tgitBranch\"@\n" +
+	"git_branch\x18\x04 \x01(\tR\tgitBranch\x12B\n" +
+	"\fcapabilities\x18\x05 \x01(\v2\x1e.subtraterpc.AgentCapabilitiesR\fcapabilities\x12\x14\n" +
+	"\x05token\x18\x06 \x01(\tR\x05token\"\x9e\x01\n" +
+	"\x11AgentCapabilities\x12\x1c\n" +
+	"\tlanguages\x18\x01 \x03(\tR\tlanguages\x12\x14\n" +
+	"\x05repos\x18\x02 \x03(\tR\x05repos\x12\x14\n" +
+	"\x05tools\x18\x03 \x03(\tR\x05tools\x12\x16\n" +
+	"\x06skills\x18\x04 \x03(\tR\x06skills\x12'\n" +
+	"\x0fmax_concurrency\x18\x05 \x01(\x03R\x0emaxConcurrency\"@\n" +
 	"\x0fGetAgentRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\"\x92\x02\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\xd6\x02\n" +
 	"\x10GetAgentResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1f\n" +
@@ -11132,23 +19629,27 @@ const file_mail_proto_rawDesc = "" +
 	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12@\n" +
 	"\x0elast_active_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\flastActiveAt\x12\x1d\n" +
 	"\n" +
-	"git_branch\x18\a \x01(\tR\tgitBranch\")\n" +
+	"git_branch\x18\a \x01(\tR\tgitBranch\x12B\n" +
+	"\fcapabilities\x18\b \x01(\v2\x1e.subtraterpc.AgentCapabilitiesR\fcapabilities\")\n" +
 	"\x11ListAgentsRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\"K\n" +
 	"\x12ListAgentsResponse\x125\n" +
-	"\x06agents\x18\x01 \x03(\v2\x1d.subtraterpc.GetAgentResponseR\x06agents\"v\n" +
+	"\x06agents\x18\x01 \x03(\v2\x1d.subtraterpc.GetAgentResponseR\x06agents\"\x95\x01\n" +
 	"\x15EnsureIdentityRequest\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1f\n" +
 	"\vproject_dir\x18\x02 \x01(\tR\n" +
 	"projectDir\x12\x1d\n" +
 	"\n" +
-	"git_branch\x18\x03 \x01(\tR\tgitBranch\"l\n" +
+	"git_branch\x18\x03 \x01(\tR\tgitBranch\x12\x1d\n" +
+	"\n" +
+	"mint_token\x18\x04 \x01(\bR\tmintToken\"\x82\x01\n" +
 	"\x16EnsureIdentityResponse\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1d\n" +
 	"\n" +
 	"agent_name\x18\x02 \x01(\tR\tagentName\x12\x18\n" +
-	"\acreated\x18\x03 \x01(\bR\acreated\"\xf5\x01\n" +
+	"\acreated\x18\x03 \x01(\bR\acreated\x12\x14\n" +
+	"\x05token\x18\x04 \x01(\tR\x05token\"\xf5\x01\n" +
 	"\x13SaveIdentityRequest\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
@@ -11162,14 +19663,23 @@ const file_mail_proto_rawDesc = "" +
 	"\x12DeleteAgentRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\"/\n" +
 	"\x13DeleteAgentResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"d\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x96\x02\n" +
 	"\x14ReplyToThreadRequest\x12\x1b\n" +
 	"\tsender_id\x18\x01 \x01(\x03R\bsenderId\x12\x1b\n" +
 	"\tthread_id\x18\x02 \x01(\tR\bthreadId\x12\x12\n" +
-	"\x04body\x18\x03 \x01(\tR\x04body\"6\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x04 \x01(\x03R\tmessageId\x12\x1f\n" +
+	"\vsender_only\x18\x05 \x01(\bR\n" +
+	"senderOnly\x12\x14\n" +
+	"\x05quote\x18\x06 \x01(\bR\x05quote\x121\n" +
+	"\bpriority\x18\a \x01(\x0e2\x15.subtraterpc.PriorityR\bpriority\x12'\n" +
+	"\x0fidempotency_key\x18\b \x01(\tR\x0eidempotencyKey\"|\n" +
 	"\x15ReplyToThreadResponse\x12\x1d\n" +
 	"\n" +
-	"message_id\x18\x01 \x01(\x03R\tmessageId\"N\n" +
+	"message_id\x18\x01 \x01(\x03R\tmessageId\x12\x1b\n" +
+	"\tthread_id\x18\x02 \x01(\tR\bthreadId\x12'\n" +
+	"\x0frecipient_names\x18\x03 \x03(\tR\x0erecipientNames\"N\n" +
 	"\x14ArchiveThreadRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1b\n" +
 	"\tthread_id\x18\x02 \x01(\tR\bthreadId\"^\n" +
@@ -11184,9 +19694,10 @@ const file_mail_proto_rawDesc = "" +
 	"\x10messages_deleted\x18\x02 \x01(\x05R\x0fmessagesDeleted\"Q\n" +
 	"\x17MarkThreadUnreadRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1b\n" +
-	"\tthread_id\x18\x02 \x01(\tR\bthreadId\"4\n" +
+	"\tthread_id\x18\x02 \x01(\tR\bthreadId\"]\n" +
 	"\x18MarkThreadUnreadResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\",\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12'\n" +
+	"\x0fmessages_marked\x18\x02 \x01(\x05R\x0emessagesMarked\",\n" +
 	"\x0fGetTopicRequest\x12\x19\n" +
 	"\btopic_id\x18\x01 \x01(\x03R\atopicId\"<\n" +
 	"\x10GetTopicResponse\x12(\n" +
@@ -11212,13 +19723,306 @@ const file_mail_proto_rawDesc = "" +
 	"message_id\x18\x02 \x01(\x03R\tmessageId\x12.\n" +
 	"\x13mark_sender_deleted\x18\x03 \x01(\bR\x11markSenderDeleted\"1\n" +
 	"\x15DeleteMessageResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"d\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"0\n" +
+	"\x15CollectGarbageRequest\x12\x17\n" +
+	"\adry_run\x18\x01 \x01(\bR\x06dryRun\"\xf4\x01\n" +
+	"\x14TopicRetentionReport\x12\x19\n" +
+	"\btopic_id\x18\x01 \x01(\x03R\atopicId\x12\x1d\n" +
+	"\n" +
+	"topic_name\x18\x02 \x01(\tR\ttopicName\x12+\n" +
+	"\x11retention_seconds\x18\x03 \x01(\x03R\x10retentionSeconds\x12#\n" +
+	"\rexpired_count\x18\x04 \x01(\x03R\fexpiredCount\x12'\n" +
+	"\x0fdeletable_count\x18\x05 \x01(\x03R\x0edeletableCount\x12'\n" +
+	"\x0frecipient_count\x18\x06 \x01(\x03R\x0erecipientCount\"\x80\x02\n" +
+	"\x16CollectGarbageResponse\x12\x17\n" +
+	"\adry_run\x18\x01 \x01(\bR\x06dryRun\x129\n" +
+	"\x06topics\x18\x02 \x03(\v2!.subtraterpc.TopicRetentionReportR\x06topics\x12)\n" +
+	"\x10messages_deleted\x18\x03 \x01(\x03R\x0fmessagesDeleted\x12-\n" +
+	"\x12recipients_deleted\x18\x04 \x01(\x03R\x11recipientsDeleted\x128\n" +
+	"\x18consumer_offsets_deleted\x18\x05 \x01(\x03R\x16consumerOffsetsDeleted\"e\n" +
+	"\x13SetLegalHoldRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x03R\tmessageId\x12\x1b\n" +
+	"\tthread_id\x18\x02 \x01(\tR\bthreadId\x12\x12\n" +
+	"\x04hold\x18\x03 \x01(\bR\x04hold\"A\n" +
+	"\x14SetLegalHoldResponse\x12)\n" +
+	"\x10messages_updated\x18\x01 \x01(\x03R\x0fmessagesUpdated\"f\n" +
+	"\x18SetTopicRetentionRequest\x12\x1d\n" +
+	"\n" +
+	"topic_name\x18\x01 \x01(\tR\ttopicName\x12+\n" +
+	"\x11retention_seconds\x18\x02 \x01(\x03R\x10retentionSeconds\"E\n" +
+	"\x19SetTopicRetentionResponse\x12(\n" +
+	"\x05topic\x18\x01 \x01(\v2\x12.subtraterpc.TopicR\x05topic\"\xce\x01\n" +
+	"\x0eRecipientGroup\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12!\n" +
+	"\fmember_count\x18\x04 \x01(\x03R\vmemberCount\x12\x18\n" +
+	"\amembers\x18\x05 \x03(\tR\amembers\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x8c\x01\n" +
+	"\x1bCreateRecipientGroupRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x03 \x01(\x03R\tcreatedBy\x12\x18\n" +
+	"\amembers\x18\x04 \x03(\tR\amembers\".\n" +
+	"\x18GetRecipientGroupRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"K\n" +
+	"\x16RecipientGroupResponse\x121\n" +
+	"\x05group\x18\x01 \x01(\v2\x1b.subtraterpc.RecipientGroupR\x05group\"\x1c\n" +
+	"\x1aListRecipientGroupsRequest\"R\n" +
+	"\x1bListRecipientGroupsResponse\x123\n" +
+	"\x06groups\x18\x01 \x03(\v2\x1b.subtraterpc.RecipientGroupR\x06groups\"1\n" +
+	"\x1bDeleteRecipientGroupRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"8\n" +
+	"\x1cDeleteRecipientGroupResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"L\n" +
+	"\x1cRecipientGroupMembersRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\amembers\x18\x02 \x03(\tR\amembers\"\x99\x02\n" +
+	"\n" +
+	"DeadLetter\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\x03R\tmessageId\x12\x1b\n" +
+	"\tthread_id\x18\x03 \x01(\tR\bthreadId\x12\x18\n" +
+	"\asubject\x18\x04 \x01(\tR\asubject\x12\x1c\n" +
+	"\trecipient\x18\x05 \x01(\tR\trecipient\x124\n" +
+	"\x06status\x18\x06 \x01(\x0e2\x1c.subtraterpc.RecipientStatusR\x06status\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"I\n" +
+	"\x16ListDeadLettersRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"U\n" +
+	"\x17ListDeadLettersResponse\x12:\n" +
+	"\fdead_letters\x18\x01 \x03(\v2\x17.subtraterpc.DeadLetterR\vdeadLetters\"D\n" +
+	"\x17DeleteDeadLetterRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\"4\n" +
+	"\x18DeleteDeadLetterResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x8d\x03\n" +
+	"\x10ScheduledMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12'\n" +
+	"\x0frecipient_names\x18\x02 \x03(\tR\x0erecipientNames\x12\x1d\n" +
+	"\n" +
+	"topic_name\x18\x03 \x01(\tR\ttopicName\x12\x1b\n" +
+	"\tthread_id\x18\x04 \x01(\tR\bthreadId\x12\x18\n" +
+	"\asubject\x18\x05 \x01(\tR\asubject\x12\x12\n" +
+	"\x04body\x18\x06 \x01(\tR\x04body\x121\n" +
+	"\bpriority\x18\a \x01(\x0e2\x15.subtraterpc.PriorityR\bpriority\x123\n" +
+	"\asend_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x06sendAt\x12\x14\n" +
+	"\x05state\x18\t \x01(\tR\x05state\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\n" +
+	" \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"O\n" +
+	"\x1cListScheduledMessagesRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"Z\n" +
+	"\x1dListScheduledMessagesResponse\x129\n" +
+	"\bmessages\x18\x01 \x03(\v2\x1d.subtraterpc.ScheduledMessageR\bmessages\"J\n" +
+	"\x1dCancelScheduledMessageRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\":\n" +
+	"\x1eCancelScheduledMessageResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xda\x04\n" +
+	"\bSchedule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12'\n" +
+	"\x0frecipient_names\x18\x03 \x03(\tR\x0erecipientNames\x12\x1d\n" +
+	"\n" +
+	"topic_name\x18\x04 \x01(\tR\ttopicName\x12\x18\n" +
+	"\asubject\x18\x05 \x01(\tR\asubject\x12\x12\n" +
+	"\x04body\x18\x06 \x01(\tR\x04body\x121\n" +
+	"\bpriority\x18\a \x01(\x0e2\x15.subtraterpc.PriorityR\bpriority\x12\x1b\n" +
+	"\tcron_expr\x18\b \x01(\tR\bcronExpr\x12\x1a\n" +
+	"\btimezone\x18\t \x01(\tR\btimezone\x12\x19\n" +
+	"\bcatch_up\x18\n" +
+	" \x01(\tR\acatchUp\x12\x16\n" +
+	"\x06paused\x18\v \x01(\bR\x06paused\x12:\n" +
+	"\vnext_run_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tnextRunAt\x12:\n" +
+	"\vlast_run_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tlastRunAt\x12&\n" +
+	"\x0flast_message_id\x18\x0e \x01(\x03R\rlastMessageId\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x0f \x01(\tR\tlastError\x12\x1b\n" +
+	"\trun_count\x18\x10 \x01(\x03R\brunCount\x129\n" +
+	"\n" +
+	"created_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xc3\x02\n" +
+	"\x15CreateScheduleRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12'\n" +
+	"\x0frecipient_names\x18\x03 \x03(\tR\x0erecipientNames\x12\x1d\n" +
+	"\n" +
+	"topic_name\x18\x04 \x01(\tR\ttopicName\x12\x18\n" +
+	"\asubject\x18\x05 \x01(\tR\asubject\x12\x12\n" +
+	"\x04body\x18\x06 \x01(\tR\x04body\x121\n" +
+	"\bpriority\x18\a \x01(\x0e2\x15.subtraterpc.PriorityR\bpriority\x12\x1b\n" +
+	"\tcron_expr\x18\b \x01(\tR\bcronExpr\x12\x1a\n" +
+	"\btimezone\x18\t \x01(\tR\btimezone\x12\x19\n" +
+	"\bcatch_up\x18\n" +
+	" \x01(\tR\acatchUp\"E\n" +
+	"\x10ScheduleResponse\x121\n" +
+	"\bschedule\x18\x01 \x01(\v2\x15.subtraterpc.ScheduleR\bschedule\"1\n" +
+	"\x14ListSchedulesRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\"L\n" +
+	"\x15ListSchedulesResponse\x123\n" +
+	"\tschedules\x18\x01 \x03(\v2\x15.subtraterpc.ScheduleR\tschedules\"F\n" +
+	"\x15DeleteScheduleRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"2\n" +
+	"\x16DeleteScheduleResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"a\n" +
+	"\x18SetSchedulePausedRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06paused\x18\x03 \x01(\bR\x06paused\"\xde\x05\n" +
+	"\bQuestion\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\x03R\tmessageId\x12\x1b\n" +
+	"\tthread_id\x18\x03 \x01(\tR\bthreadId\x12\x19\n" +
+	"\basker_id\x18\x04 \x01(\x03R\aaskerId\x12\x1a\n" +
+	"\bquestion\x18\x05 \x01(\tR\bquestion\x12\x18\n" +
+	"\aoptions\x18\x06 \x03(\tR\aoptions\x12%\n" +
+	"\x0edefault_answer\x18\a \x01(\tR\rdefaultAnswer\x12'\n" +
+	"\x0ftimeout_seconds\x18\b \x01(\x03R\x0etimeoutSeconds\x129\n" +
+	"\n" +
+	"timeout_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\ttimeoutAt\x12\x1a\n" +
+	"\bfallback\x18\n" +
+	" \x01(\tR\bfallback\x12=\n" +
+	"\fescalated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vescalatedAt\x12\x14\n" +
+	"\x05state\x18\f \x01(\tR\x05state\x12\x16\n" +
+	"\x06answer\x18\r \x01(\tR\x06answer\x12!\n" +
+	"\foption_index\x18\x0e \x01(\x05R\voptionIndex\x12\x1f\n" +
+	"\vanswered_by\x18\x0f \x01(\x03R\n" +
+	"answeredBy\x12(\n" +
+	"\x10answered_by_name\x18\x10 \x01(\tR\x0eansweredByName\x12*\n" +
+	"\x11answer_message_id\x18\x11 \x01(\x03R\x0fanswerMessageId\x12;\n" +
+	"\vanswered_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"answeredAt\x129\n" +
+	"\n" +
+	"created_at\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xc7\x02\n" +
+	"\x12AskQuestionRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12'\n" +
+	"\x0frecipient_names\x18\x02 \x03(\tR\x0erecipientNames\x12\x1a\n" +
+	"\bquestion\x18\x03 \x01(\tR\bquestion\x12\x18\n" +
+	"\adetails\x18\x04 \x01(\tR\adetails\x12\x18\n" +
+	"\aoptions\x18\x05 \x03(\tR\aoptions\x12%\n" +
+	"\x0edefault_answer\x18\x06 \x01(\tR\rdefaultAnswer\x12'\n" +
+	"\x0ftimeout_seconds\x18\a \x01(\x03R\x0etimeoutSeconds\x12\x1a\n" +
+	"\bfallback\x18\b \x01(\tR\bfallback\x121\n" +
+	"\bpriority\x18\t \x01(\x0e2\x15.subtraterpc.PriorityR\bpriority\"5\n" +
+	"\x12GetQuestionRequest\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\"k\n" +
+	"\x15AnswerQuestionRequest\x12\x1f\n" +
+	"\vquestion_id\x18\x01 \x01(\tR\n" +
+	"questionId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\x03R\aagentId\x12\x16\n" +
+	"\x06answer\x18\x03 \x01(\tR\x06answer\"E\n" +
+	"\x10QuestionResponse\x121\n" +
+	"\bquestion\x18\x01 \x01(\v2\x15.subtraterpc.QuestionR\bquestion\"\xf2\x01\n" +
+	"\x0fMessageRevision\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x03R\tmessageId\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x05R\brevision\x12\x18\n" +
+	"\asubject\x18\x03 \x01(\tR\asubject\x12\x12\n" +
+	"\x04body\x18\x04 \x01(\tR\x04body\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12;\n" +
+	"\vreplaced_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"replacedAt\"|\n" +
+	"\x12EditMessageRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\x03R\tmessageId\x12\x18\n" +
+	"\asubject\x18\x03 \x01(\tR\asubject\x12\x12\n" +
+	"\x04body\x18\x04 \x01(\tR\x04body\"\x89\x01\n" +
+	"\x13EditMessageResponse\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x03R\tmessageId\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x05R\brevision\x127\n" +
+	"\tedited_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\beditedAt\"P\n" +
+	"\x14RecallMessageRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\x03R\tmessageId\"\x17\n" +
+	"\x15RecallMessageResponse\"T\n" +
+	"\x18GetMessageHistoryRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x03R\tmessageId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\x03R\aagentId\"W\n" +
+	"\x19GetMessageHistoryResponse\x12:\n" +
+	"\trevisions\x18\x01 \x03(\v2\x1c.subtraterpc.MessageRevisionR\trevisions\"\xce\x01\n" +
+	"\n" +
+	"LabelProto\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12#\n" +
+	"\rmessage_count\x18\x04 \x01(\x03R\fmessageCount\x12!\n" +
+	"\funread_count\x18\x05 \x01(\x03R\vunreadCount\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"C\n" +
+	"\x12CreateLabelRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\".\n" +
+	"\x11ListLabelsRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\"E\n" +
+	"\x12ListLabelsResponse\x12/\n" +
+	"\x06labels\x18\x01 \x03(\v2\x17.subtraterpc.LabelProtoR\x06labels\"C\n" +
+	"\x12DeleteLabelRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\x15\n" +
+	"\x13DeleteLabelResponse\"y\n" +
+	"\x13LabelMessageRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\x03R\tmessageId\x12\x10\n" +
+	"\x03add\x18\x03 \x03(\tR\x03add\x12\x16\n" +
+	"\x06remove\x18\x04 \x03(\tR\x06remove\".\n" +
+	"\x14LabelMessageResponse\x12\x16\n" +
+	"\x06labels\x18\x01 \x03(\tR\x06labels\"\xbe\x01\n" +
+	"\x15ThreadPreferenceProto\x12\x1b\n" +
+	"\tthread_id\x18\x01 \x01(\tR\bthreadId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\x03R\aagentId\x12\x14\n" +
+	"\x05muted\x18\x03 \x01(\bR\x05muted\x12\x1c\n" +
+	"\tfollowing\x18\x04 \x01(\bR\tfollowing\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"e\n" +
+	"\x15SetThreadMutedRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1b\n" +
+	"\tthread_id\x18\x02 \x01(\tR\bthreadId\x12\x14\n" +
+	"\x05muted\x18\x03 \x01(\bR\x05muted\"q\n" +
+	"\x19SetThreadFollowingRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1b\n" +
+	"\tthread_id\x18\x02 \x01(\tR\bthreadId\x12\x1c\n" +
+	"\tfollowing\x18\x03 \x01(\bR\tfollowing\"9\n" +
+	"\x1cListThreadPreferencesRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\"]\n" +
+	"\x1dListThreadPreferencesResponse\x12<\n" +
+	"\athreads\x18\x01 \x03(\v2\".subtraterpc.ThreadPreferenceProtoR\athreads\"a\n" +
+	"\x13MergeThreadsRequest\x12$\n" +
+	"\x0efrom_thread_id\x18\x01 \x01(\tR\ffromThreadId\x12$\n" +
+	"\x0einto_thread_id\x18\x02 \x01(\tR\fintoThreadId\"=\n" +
+	"\x14MergeThreadsResponse\x12%\n" +
+	"\x0emessages_moved\x18\x01 \x01(\x03R\rmessagesMoved\"3\n" +
+	"\x12SplitThreadRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x03R\tmessageId\"Y\n" +
+	"\x13SplitThreadResponse\x12\x1b\n" +
+	"\tthread_id\x18\x01 \x01(\tR\bthreadId\x12%\n" +
+	"\x0emessages_moved\x18\x02 \x01(\x03R\rmessagesMoved\"\xa8\x01\n" +
 	"\x12UpdateAgentRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1f\n" +
 	"\vproject_key\x18\x02 \x01(\tR\n" +
 	"projectKey\x12\x1d\n" +
 	"\n" +
-	"git_branch\x18\x03 \x01(\tR\tgitBranch\"J\n" +
+	"git_branch\x18\x03 \x01(\tR\tgitBranch\x12B\n" +
+	"\fcapabilities\x18\x04 \x01(\v2\x1e.subtraterpc.AgentCapabilitiesR\fcapabilities\"J\n" +
 	"\x13UpdateAgentResponse\x123\n" +
 	"\x05agent\x18\x01 \x01(\v2\x1d.subtraterpc.GetAgentResponseR\x05agent\"\xc0\x02\n" +
 	"\x0fAgentWithStatus\x12\x0e\n" +
@@ -11241,12 +20045,13 @@ const file_mail_proto_rawDesc = "" +
 	"\x16GetAgentsStatusRequest\"\x87\x01\n" +
 	"\x17GetAgentsStatusResponse\x124\n" +
 	"\x06agents\x18\x01 \x03(\v2\x1c.subtraterpc.AgentWithStatusR\x06agents\x126\n" +
-	"\x06counts\x18\x02 \x01(\v2\x1e.subtraterpc.AgentStatusCountsR\x06counts\"\x9e\x01\n" +
+	"\x06counts\x18\x02 \x01(\v2\x1e.subtraterpc.AgentStatusCountsR\x06counts\"\xcb\x01\n" +
 	"\x15DiscoverAgentsRequest\x12=\n" +
 	"\rstatus_filter\x18\x01 \x03(\x0e2\x18.subtraterpc.AgentStatusR\fstatusFilter\x12%\n" +
 	"\x0eproject_filter\x18\x02 \x01(\tR\rprojectFilter\x12\x1f\n" +
 	"\vname_filter\x18\x03 \x01(\tR\n" +
-	"nameFilter\"\xba\x03\n" +
+	"nameFilter\x12+\n" +
+	"\x11capability_filter\x18\x04 \x01(\tR\x10capabilityFilter\"\xfe\x03\n" +
 	"\x0fDiscoveredAgent\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1f\n" +
@@ -11264,10 +20069,114 @@ const file_mail_proto_rawDesc = "" +
 	" \x01(\tR\n" +
 	"workingDir\x12\x1a\n" +
 	"\bhostname\x18\v \x01(\tR\bhostname\x12!\n" +
-	"\funread_count\x18\f \x01(\x03R\vunreadCount\"\x86\x01\n" +
+	"\funread_count\x18\f \x01(\x03R\vunreadCount\x12B\n" +
+	"\fcapabilities\x18\r \x01(\v2\x1e.subtraterpc.AgentCapabilitiesR\fcapabilities\"\x86\x01\n" +
 	"\x16DiscoverAgentsResponse\x124\n" +
 	"\x06agents\x18\x01 \x03(\v2\x1c.subtraterpc.DiscoveredAgentR\x06agents\x126\n" +
-	"\x06counts\x18\x02 \x01(\v2\x1e.subtraterpc.AgentStatusCountsR\x06counts\"n\n" +
+	"\x06counts\x18\x02 \x01(\v2\x1e.subtraterpc.AgentStatusCountsR\x06counts\"S\n" +
+	"\x1bListCapabilityRoutesRequest\x12\x1e\n" +
+	"\n" +
+	"capability\x18\x01 \x01(\tR\n" +
+	"capability\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"\xcd\x02\n" +
+	"\x0fCapabilityRoute\x12\x1e\n" +
+	"\n" +
+	"capability\x18\x01 \x01(\tR\n" +
+	"capability\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\x03R\aagentId\x12\x1d\n" +
+	"\n" +
+	"agent_name\x18\x03 \x01(\tR\tagentName\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x04 \x01(\x03R\tmessageId\x12\x17\n" +
+	"\atask_id\x18\x05 \x01(\x03R\x06taskId\x12\x1e\n" +
+	"\n" +
+	"candidates\x18\x06 \x01(\x03R\n" +
+	"candidates\x12!\n" +
+	"\funread_count\x18\a \x01(\x03R\vunreadCount\x12*\n" +
+	"\x11in_progress_count\x18\b \x01(\x03R\x0finProgressCount\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"T\n" +
+	"\x1cListCapabilityRoutesResponse\x124\n" +
+	"\x06routes\x18\x01 \x03(\v2\x1c.subtraterpc.CapabilityRouteR\x06routes\"\x98\x03\n" +
+	"\n" +
+	"Credential\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\x03R\aagentId\x12\x1d\n" +
+	"\n" +
+	"agent_name\x18\x03 \x01(\tR\tagentName\x12\x14\n" +
+	"\x05admin\x18\x04 \x01(\bR\x05admin\x12\x12\n" +
+	"\x04name\x18\x05 \x01(\tR\x04name\x12'\n" +
+	"\x0fallowed_methods\x18\x06 \x03(\tR\x0eallowedMethods\x129\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12<\n" +
+	"\flast_used_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastUsedAt\x129\n" +
+	"\n" +
+	"revoked_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\trevokedAt\"\xa6\x01\n" +
+	"\x15MintCredentialRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x14\n" +
+	"\x05admin\x18\x02 \x01(\bR\x05admin\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12'\n" +
+	"\x0fallowed_methods\x18\x04 \x03(\tR\x0eallowedMethods\x12\x1f\n" +
+	"\vttl_seconds\x18\x05 \x01(\x03R\n" +
+	"ttlSeconds\"g\n" +
+	"\x16MintCredentialResponse\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x127\n" +
+	"\n" +
+	"credential\x18\x02 \x01(\v2\x17.subtraterpc.CredentialR\n" +
+	"credential\"\\\n" +
+	"\x16ListCredentialsRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12'\n" +
+	"\x0finclude_revoked\x18\x02 \x01(\bR\x0eincludeRevoked\"T\n" +
+	"\x17ListCredentialsResponse\x129\n" +
+	"\vcredentials\x18\x01 \x03(\v2\x17.subtraterpc.CredentialR\vcredentials\")\n" +
+	"\x17RevokeCredentialRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x1a\n" +
+	"\x18RevokeCredentialResponse\"\x80\x02\n" +
+	"\n" +
+	"AgentLimit\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x1d\n" +
+	"\n" +
+	"agent_name\x18\x02 \x01(\tR\tagentName\x12\x14\n" +
+	"\x05class\x18\x03 \x01(\tR\x05class\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x03R\x06amount\x12)\n" +
+	"\x10interval_seconds\x18\x05 \x01(\x03R\x0fintervalSeconds\x12\x14\n" +
+	"\x05burst\x18\x06 \x01(\x03R\x05burst\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x0e\n" +
+	"\x02id\x18\b \x01(\x03R\x02id\"\x9b\x01\n" +
+	"\x0fSetLimitRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x14\n" +
+	"\x05class\x18\x02 \x01(\tR\x05class\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12)\n" +
+	"\x10interval_seconds\x18\x04 \x01(\x03R\x0fintervalSeconds\x12\x14\n" +
+	"\x05burst\x18\x05 \x01(\x03R\x05burst\"A\n" +
+	"\x10SetLimitResponse\x12-\n" +
+	"\x05limit\x18\x01 \x01(\v2\x17.subtraterpc.AgentLimitR\x05limit\"D\n" +
+	"\x11ClearLimitRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x14\n" +
+	"\x05class\x18\x02 \x01(\tR\x05class\"\x14\n" +
+	"\x12ClearLimitResponse\".\n" +
+	"\x11ListLimitsRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\"E\n" +
+	"\x12ListLimitsResponse\x12/\n" +
+	"\x06limits\x18\x01 \x03(\v2\x17.subtraterpc.AgentLimitR\x06limits\"\xee\x01\n" +
+	"\n" +
+	"LimitUsage\x12\x14\n" +
+	"\x05class\x18\x01 \x01(\tR\x05class\x12-\n" +
+	"\x05limit\x18\x02 \x01(\v2\x17.subtraterpc.AgentLimitR\x05limit\x12\x16\n" +
+	"\x06tokens\x18\x03 \x01(\x01R\x06tokens\x12\x1c\n" +
+	"\tthrottled\x18\x04 \x01(\x03R\tthrottled\x12F\n" +
+	"\x11last_throttled_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x0flastThrottledAt\x12\x1d\n" +
+	"\n" +
+	"used_bytes\x18\x06 \x01(\x03R\tusedBytes\",\n" +
+	"\x0fGetUsageRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\"A\n" +
+	"\x10GetUsageResponse\x12-\n" +
+	"\x05usage\x18\x01 \x03(\v2\x17.subtraterpc.LimitUsageR\x05usage\"n\n" +
 	"\x10HeartbeatRequest\x12\x1a\n" +
 	"\bagent_id\x18\x01 \x01(\x03R\bagent_id\x12\x1e\n" +
 	"\n" +
@@ -11700,7 +20609,73 @@ const file_mail_proto_rawDesc = "" +
 	"\x17DeletePlanReviewRequest\x12$\n" +
 	"\x0eplan_review_id\x18\x01 \x01(\tR\fplanReviewId\"0\n" +
 	"\x18DeletePlanReviewResponse\x12\x14\n" +
-	"\x05error\x18\x01 \x01(\tR\x05error\"\x90\x03\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error\"\x89\x02\n" +
+	"\x13InboxRuleConditions\x12\x1f\n" +
+	"\vsender_name\x18\x01 \x01(\tR\n" +
+	"senderName\x12#\n" +
+	"\rsender_prefix\x18\x02 \x01(\tR\fsenderPrefix\x12'\n" +
+	"\x0fsubject_pattern\x18\x03 \x01(\tR\x0esubjectPattern\x12\x1d\n" +
+	"\n" +
+	"topic_name\x18\x04 \x01(\tR\ttopicName\x12\x1a\n" +
+	"\bpriority\x18\x05 \x01(\tR\bpriority\x12!\n" +
+	"\fmetadata_key\x18\x06 \x01(\tR\vmetadataKey\x12%\n" +
+	"\x0emetadata_value\x18\a \x01(\tR\rmetadataValue\"\xa9\x01\n" +
+	"\x10InboxRuleActions\x12\x18\n" +
+	"\aarchive\x18\x01 \x01(\bR\aarchive\x12\x12\n" +
+	"\x04star\x18\x02 \x01(\bR\x04star\x12\x16\n" +
+	"\x06snooze\x18\x03 \x01(\tR\x06snooze\x12\x1a\n" +
+	"\bcategory\x18\x04 \x01(\tR\bcategory\x12\x1d\n" +
+	"\n" +
+	"forward_to\x18\x05 \x01(\tR\tforwardTo\x12\x14\n" +
+	"\x05label\x18\x06 \x01(\tR\x05label\"\xe8\x03\n" +
+	"\x0eInboxRuleProto\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x18\n" +
+	"\aenabled\x18\x04 \x01(\bR\aenabled\x12'\n" +
+	"\x0fstop_processing\x18\x05 \x01(\bR\x0estopProcessing\x12@\n" +
+	"\n" +
+	"conditions\x18\x06 \x01(\v2 .subtraterpc.InboxRuleConditionsR\n" +
+	"conditions\x127\n" +
+	"\aactions\x18\a \x01(\v2\x1d.subtraterpc.InboxRuleActionsR\aactions\x12\x1f\n" +
+	"\vmatch_count\x18\b \x01(\x03R\n" +
+	"matchCount\x12B\n" +
+	"\x0flast_matched_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\rlastMatchedAt\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xeb\x01\n" +
+	"\x16CreateInboxRuleRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12'\n" +
+	"\x0fstop_processing\x18\x03 \x01(\bR\x0estopProcessing\x12@\n" +
+	"\n" +
+	"conditions\x18\x04 \x01(\v2 .subtraterpc.InboxRuleConditionsR\n" +
+	"conditions\x127\n" +
+	"\aactions\x18\x05 \x01(\v2\x1d.subtraterpc.InboxRuleActionsR\aactions\"2\n" +
+	"\x15ListInboxRulesRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\"K\n" +
+	"\x16ListInboxRulesResponse\x121\n" +
+	"\x05rules\x18\x01 \x03(\v2\x1b.subtraterpc.InboxRuleProtoR\x05rules\"G\n" +
+	"\x16DeleteInboxRuleRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\x19\n" +
+	"\x17DeleteInboxRuleResponse\"e\n" +
+	"\x1aSetInboxRuleEnabledRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aenabled\x18\x03 \x01(\bR\aenabled\"\x9d\x01\n" +
+	"\x14TestInboxRuleRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12@\n" +
+	"\n" +
+	"conditions\x18\x03 \x01(\v2 .subtraterpc.InboxRuleConditionsR\n" +
+	"conditions\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"f\n" +
+	"\x15TestInboxRuleResponse\x12\x18\n" +
+	"\ascanned\x18\x01 \x01(\x05R\ascanned\x123\n" +
+	"\amatches\x18\x02 \x03(\v2\x19.subtraterpc.InboxMessageR\amatches\"\x90\x03\n" +
 	"\x13PlanAnnotationProto\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12$\n" +
 	"\x0eplan_review_id\x18\x02 \x01(\tR\fplanReviewId\x12#\n" +
@@ -11805,7 +20780,30 @@ const file_mail_proto_rawDesc = "" +
 	"\rSTATE_STARRED\x10\x03\x12\x11\n" +
 	"\rSTATE_SNOOZED\x10\x04\x12\x12\n" +
 	"\x0eSTATE_ARCHIVED\x10\x05\x12\x0f\n" +
-	"\vSTATE_TRASH\x10\x06*\x8c\x01\n" +
+	"\vSTATE_TRASH\x10\x06*C\n" +
+	"\fDeliveryMode\x12\x18\n" +
+	"\x14DELIVERY_MODE_ATOMIC\x10\x00\x12\x19\n" +
+	"\x15DELIVERY_MODE_PARTIAL\x10\x01*\xad\x01\n" +
+	"\x0fRecipientStatus\x12 \n" +
+	"\x1cRECIPIENT_STATUS_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aRECIPIENT_STATUS_DELIVERED\x10\x01\x12\x1b\n" +
+	"\x17RECIPIENT_STATUS_QUEUED\x10\x02\x12\x1c\n" +
+	"\x18RECIPIENT_STATUS_UNKNOWN\x10\x03\x12\x1d\n" +
+	"\x19RECIPIENT_STATUS_REJECTED\x10\x04*\x8a\x01\n" +
+	"\x0eWatchEventType\x12 \n" +
+	"\x1cWATCH_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16WATCH_EVENT_TYPE_ARMED\x10\x01\x12\x19\n" +
+	"\x15WATCH_EVENT_TYPE_WAKE\x10\x02\x12\x1f\n" +
+	"\x1bWATCH_EVENT_TYPE_LEASE_LOST\x10\x03*\xd5\x01\n" +
+	"\n" +
+	"SearchKind\x12\x1b\n" +
+	"\x17SEARCH_KIND_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13SEARCH_KIND_MESSAGE\x10\x01\x12\x1c\n" +
+	"\x18SEARCH_KIND_REVIEW_ISSUE\x10\x02\x12\x1b\n" +
+	"\x17SEARCH_KIND_PLAN_REVIEW\x10\x03\x12\x14\n" +
+	"\x10SEARCH_KIND_TASK\x10\x04\x12\x1f\n" +
+	"\x1bSEARCH_KIND_PLAN_ANNOTATION\x10\x05\x12\x1f\n" +
+	"\x1bSEARCH_KIND_DIFF_ANNOTATION\x10\x06*\x8c\x01\n" +
 	"\vAgentStatus\x12\x1c\n" +
 	"\x18AGENT_STATUS_UNSPECIFIED\x10\x00\x12\x17\n" +
 	"\x13AGENT_STATUS_ACTIVE\x10\x01\x12\x15\n" +
@@ -11816,7 +20814,7 @@ const file_mail_proto_rawDesc = "" +
 	"\x1aSESSION_STATUS_UNSPECIFIED\x10\x00\x12\x19\n" +
 	"\x15SESSION_STATUS_ACTIVE\x10\x01\x12\x1c\n" +
 	"\x18SESSION_STATUS_COMPLETED\x10\x02\x12\x1c\n" +
-	"\x18SESSION_STATUS_ABANDONED\x10\x03*\xf6\x01\n" +
+	"\x18SESSION_STATUS_ABANDONED\x10\x03*\x96\x02\n" +
 	"\fActivityType\x12\x1d\n" +
 	"\x19ACTIVITY_TYPE_UNSPECIFIED\x10\x00\x12\x1e\n" +
 	"\x1aACTIVITY_TYPE_MESSAGE_SENT\x10\x01\x12\x1e\n" +
@@ -11824,7 +20822,8 @@ const file_mail_proto_rawDesc = "" +
 	"\x1dACTIVITY_TYPE_SESSION_STARTED\x10\x03\x12#\n" +
 	"\x1fACTIVITY_TYPE_SESSION_COMPLETED\x10\x04\x12\"\n" +
 	"\x1eACTIVITY_TYPE_AGENT_REGISTERED\x10\x05\x12\x1b\n" +
-	"\x17ACTIVITY_TYPE_HEARTBEAT\x10\x06*\x93\x01\n" +
+	"\x17ACTIVITY_TYPE_HEARTBEAT\x10\x06\x12\x1e\n" +
+	"\x1aACTIVITY_TYPE_SCHEDULE_RUN\x10\a*\x93\x01\n" +
 	"\n" +
 	"TaskStatus\x12\x1b\n" +
 	"\x17TASK_STATUS_UNSPECIFIED\x10\x00\x12\x17\n" +
@@ -11837,7 +20836,7 @@ const file_mail_proto_rawDesc = "" +
 	"\x19PLAN_REVIEW_STATE_PENDING\x10\x01\x12\x1e\n" +
 	"\x1aPLAN_REVIEW_STATE_APPROVED\x10\x02\x12\x1e\n" +
 	"\x1aPLAN_REVIEW_STATE_REJECTED\x10\x03\x12'\n" +
-	"#PLAN_REVIEW_STATE_CHANGES_REQUESTED\x10\x042\xa8\x0e\n" +
+	"#PLAN_REVIEW_STATE_CHANGES_REQUESTED\x10\x042\x91.\n" +
 	"\x04Mail\x12G\n" +
 	"\bSendMail\x12\x1c.subtraterpc.SendMailRequest\x1a\x1d.subtraterpc.SendMailResponse\x12M\n" +
 	"\n" +
@@ -11850,13 +20849,28 @@ const file_mail_proto_rawDesc = "" +
 	"AckMessage\x12\x1e.subtraterpc.AckMessageRequest\x1a\x1f.subtraterpc.AckMessageResponse\x12J\n" +
 	"\tGetStatus\x12\x1d.subtraterpc.GetStatusRequest\x1a\x1e.subtraterpc.GetStatusResponse\x12P\n" +
 	"\vPollChanges\x12\x1f.subtraterpc.PollChangesRequest\x1a .subtraterpc.PollChangesResponse\x12Q\n" +
-	"\x0eSubscribeInbox\x12\".subtraterpc.SubscribeInboxRequest\x1a\x19.subtraterpc.InboxMessage0\x01\x12D\n" +
+	"\x0eSubscribeInbox\x12\".subtraterpc.SubscribeInboxRequest\x1a\x19.subtraterpc.InboxMessage0\x01\x12L\n" +
+	"\n" +
+	"WatchInbox\x12\x1e.subtraterpc.WatchInboxRequest\x1a\x1c.subtraterpc.WatchInboxEvent0\x01\x12M\n" +
+	"\n" +
+	"GetWatcher\x12\x1e.subtraterpc.GetWatcherRequest\x1a\x1f.subtraterpc.GetWatcherResponse\x12D\n" +
 	"\aPublish\x12\x1b.subtraterpc.PublishRequest\x1a\x1c.subtraterpc.PublishResponse\x12J\n" +
 	"\tSubscribe\x12\x1d.subtraterpc.SubscribeRequest\x1a\x1e.subtraterpc.SubscribeResponse\x12P\n" +
-	"\vUnsubscribe\x12\x1f.subtraterpc.UnsubscribeRequest\x1a .subtraterpc.UnsubscribeResponse\x12M\n" +
+	"\vUnsubscribe\x12\x1f.subtraterpc.UnsubscribeRequest\x1a .subtraterpc.UnsubscribeResponse\x12b\n" +
+	"\x11ClaimQueueMessage\x12%.subtraterpc.ClaimQueueMessageRequest\x1a&.subtraterpc.ClaimQueueMessageResponse\x12\\\n" +
+	"\x0fAckQueueMessage\x12#.subtraterpc.AckQueueMessageRequest\x1a$.subtraterpc.AckQueueMessageResponse\x12h\n" +
+	"\x13ReleaseQueueMessage\x12'.subtraterpc.ReleaseQueueMessageRequest\x1a(.subtraterpc.ReleaseQueueMessageResponse\x12_\n" +
+	"\x10ExtendQueueLease\x12$.subtraterpc.ExtendQueueLeaseRequest\x1a%.subtraterpc.ExtendQueueLeaseResponse\x12M\n" +
 	"\n" +
 	"ListTopics\x12\x1e.subtraterpc.ListTopicsRequest\x1a\x1f.subtraterpc.ListTopicsResponse\x12A\n" +
-	"\x06Search\x12\x1a.subtraterpc.SearchRequest\x1a\x1b.subtraterpc.SearchResponse\x12e\n" +
+	"\x06Search\x12\x1a.subtraterpc.SearchRequest\x1a\x1b.subtraterpc.SearchResponse\x12J\n" +
+	"\tSearchAll\x12\x1d.subtraterpc.SearchAllRequest\x1a\x1e.subtraterpc.SearchAllResponse\x12Y\n" +
+	"\x0eSemanticSearch\x12\".subtraterpc.SemanticSearchRequest\x1a#.subtraterpc.SemanticSearchResponse\x12[\n" +
+	"\x0fSimilarMessages\x12#.subtraterpc.SimilarMessagesRequest\x1a#.subtraterpc.SemanticSearchResponse\x12K\n" +
+	"\n" +
+	"SaveSearch\x12\x1e.subtraterpc.SaveSearchRequest\x1a\x1d.subtraterpc.SavedSearchProto\x12b\n" +
+	"\x11ListSavedSearches\x12%.subtraterpc.ListSavedSearchesRequest\x1a&.subtraterpc.ListSavedSearchesResponse\x12b\n" +
+	"\x11DeleteSavedSearch\x12%.subtraterpc.DeleteSavedSearchRequest\x1a&.subtraterpc.DeleteSavedSearchResponse\x12e\n" +
 	"\x12HasUnackedStatusTo\x12&.subtraterpc.HasUnackedStatusToRequest\x1a'.subtraterpc.HasUnackedStatusToResponse\x12V\n" +
 	"\rReplyToThread\x12!.subtraterpc.ReplyToThreadRequest\x1a\".subtraterpc.ReplyToThreadResponse\x12V\n" +
 	"\rArchiveThread\x12!.subtraterpc.ArchiveThreadRequest\x1a\".subtraterpc.ArchiveThreadResponse\x12S\n" +
@@ -11864,7 +20878,40 @@ const file_mail_proto_rawDesc = "" +
 	"\x10MarkThreadUnread\x12$.subtraterpc.MarkThreadUnreadRequest\x1a%.subtraterpc.MarkThreadUnreadResponse\x12G\n" +
 	"\bGetTopic\x12\x1c.subtraterpc.GetTopicRequest\x1a\x1d.subtraterpc.GetTopicResponse\x12q\n" +
 	"\x16AutocompleteRecipients\x12*.subtraterpc.AutocompleteRecipientsRequest\x1a+.subtraterpc.AutocompleteRecipientsResponse\x12V\n" +
-	"\rDeleteMessage\x12!.subtraterpc.DeleteMessageRequest\x1a\".subtraterpc.DeleteMessageResponse2\xd0\x06\n" +
+	"\rDeleteMessage\x12!.subtraterpc.DeleteMessageRequest\x1a\".subtraterpc.DeleteMessageResponse\x12Y\n" +
+	"\x0eCollectGarbage\x12\".subtraterpc.CollectGarbageRequest\x1a#.subtraterpc.CollectGarbageResponse\x12S\n" +
+	"\fSetLegalHold\x12 .subtraterpc.SetLegalHoldRequest\x1a!.subtraterpc.SetLegalHoldResponse\x12b\n" +
+	"\x11SetTopicRetention\x12%.subtraterpc.SetTopicRetentionRequest\x1a&.subtraterpc.SetTopicRetentionResponse\x12e\n" +
+	"\x14CreateRecipientGroup\x12(.subtraterpc.CreateRecipientGroupRequest\x1a#.subtraterpc.RecipientGroupResponse\x12_\n" +
+	"\x11GetRecipientGroup\x12%.subtraterpc.GetRecipientGroupRequest\x1a#.subtraterpc.RecipientGroupResponse\x12h\n" +
+	"\x13ListRecipientGroups\x12'.subtraterpc.ListRecipientGroupsRequest\x1a(.subtraterpc.ListRecipientGroupsResponse\x12k\n" +
+	"\x14DeleteRecipientGroup\x12(.subtraterpc.DeleteRecipientGroupRequest\x1a).subtraterpc.DeleteRecipientGroupResponse\x12j\n" +
+	"\x18AddRecipientGroupMembers\x12).subtraterpc.RecipientGroupMembersRequest\x1a#.subtraterpc.RecipientGroupResponse\x12m\n" +
+	"\x1bRemoveRecipientGroupMembers\x12).subtraterpc.RecipientGroupMembersRequest\x1a#.subtraterpc.RecipientGroupResponse\x12\\\n" +
+	"\x0fListDeadLetters\x12#.subtraterpc.ListDeadLettersRequest\x1a$.subtraterpc.ListDeadLettersResponse\x12_\n" +
+	"\x10DeleteDeadLetter\x12$.subtraterpc.DeleteDeadLetterRequest\x1a%.subtraterpc.DeleteDeadLetterResponse\x12n\n" +
+	"\x15ListScheduledMessages\x12).subtraterpc.ListScheduledMessagesRequest\x1a*.subtraterpc.ListScheduledMessagesResponse\x12q\n" +
+	"\x16CancelScheduledMessage\x12*.subtraterpc.CancelScheduledMessageRequest\x1a+.subtraterpc.CancelScheduledMessageResponse\x12S\n" +
+	"\x0eCreateSchedule\x12\".subtraterpc.CreateScheduleRequest\x1a\x1d.subtraterpc.ScheduleResponse\x12V\n" +
+	"\rListSchedules\x12!.subtraterpc.ListSchedulesRequest\x1a\".subtraterpc.ListSchedulesResponse\x12Y\n" +
+	"\x0eDeleteSchedule\x12\".subtraterpc.DeleteScheduleRequest\x1a#.subtraterpc.DeleteScheduleResponse\x12Y\n" +
+	"\x11SetSchedulePaused\x12%.subtraterpc.SetSchedulePausedRequest\x1a\x1d.subtraterpc.ScheduleResponse\x12M\n" +
+	"\vAskQuestion\x12\x1f.subtraterpc.AskQuestionRequest\x1a\x1d.subtraterpc.QuestionResponse\x12M\n" +
+	"\vGetQuestion\x12\x1f.subtraterpc.GetQuestionRequest\x1a\x1d.subtraterpc.QuestionResponse\x12S\n" +
+	"\x0eAnswerQuestion\x12\".subtraterpc.AnswerQuestionRequest\x1a\x1d.subtraterpc.QuestionResponse\x12P\n" +
+	"\vEditMessage\x12\x1f.subtraterpc.EditMessageRequest\x1a .subtraterpc.EditMessageResponse\x12V\n" +
+	"\rRecallMessage\x12!.subtraterpc.RecallMessageRequest\x1a\".subtraterpc.RecallMessageResponse\x12b\n" +
+	"\x11GetMessageHistory\x12%.subtraterpc.GetMessageHistoryRequest\x1a&.subtraterpc.GetMessageHistoryResponse\x12G\n" +
+	"\vCreateLabel\x12\x1f.subtraterpc.CreateLabelRequest\x1a\x17.subtraterpc.LabelProto\x12M\n" +
+	"\n" +
+	"ListLabels\x12\x1e.subtraterpc.ListLabelsRequest\x1a\x1f.subtraterpc.ListLabelsResponse\x12P\n" +
+	"\vDeleteLabel\x12\x1f.subtraterpc.DeleteLabelRequest\x1a .subtraterpc.DeleteLabelResponse\x12S\n" +
+	"\fLabelMessage\x12 .subtraterpc.LabelMessageRequest\x1a!.subtraterpc.LabelMessageResponse\x12X\n" +
+	"\x0eSetThreadMuted\x12\".subtraterpc.SetThreadMutedRequest\x1a\".subtraterpc.ThreadPreferenceProto\x12`\n" +
+	"\x12SetThreadFollowing\x12&.subtraterpc.SetThreadFollowingRequest\x1a\".subtraterpc.ThreadPreferenceProto\x12n\n" +
+	"\x15ListThreadPreferences\x12).subtraterpc.ListThreadPreferencesRequest\x1a*.subtraterpc.ListThreadPreferencesResponse\x12S\n" +
+	"\fMergeThreads\x12 .subtraterpc.MergeThreadsRequest\x1a!.subtraterpc.MergeThreadsResponse\x12P\n" +
+	"\vSplitThread\x12\x1f.subtraterpc.SplitThreadRequest\x1a .subtraterpc.SplitThreadResponse2\x87\f\n" +
 	"\x05Agent\x12V\n" +
 	"\rRegisterAgent\x12!.subtraterpc.RegisterAgentRequest\x1a\".subtraterpc.RegisterAgentResponse\x12G\n" +
 	"\bGetAgent\x12\x1c.subtraterpc.GetAgentRequest\x1a\x1d.subtraterpc.GetAgentResponse\x12M\n" +
@@ -11876,7 +20923,17 @@ const file_mail_proto_rawDesc = "" +
 	"\tHeartbeat\x12\x1d.subtraterpc.HeartbeatRequest\x1a\x1e.subtraterpc.HeartbeatResponse\x12Y\n" +
 	"\x0eEnsureIdentity\x12\".subtraterpc.EnsureIdentityRequest\x1a#.subtraterpc.EnsureIdentityResponse\x12S\n" +
 	"\fSaveIdentity\x12 .subtraterpc.SaveIdentityRequest\x1a!.subtraterpc.SaveIdentityResponse\x12Y\n" +
-	"\x0eDiscoverAgents\x12\".subtraterpc.DiscoverAgentsRequest\x1a#.subtraterpc.DiscoverAgentsResponse2\xe0\x02\n" +
+	"\x0eDiscoverAgents\x12\".subtraterpc.DiscoverAgentsRequest\x1a#.subtraterpc.DiscoverAgentsResponse\x12k\n" +
+	"\x14ListCapabilityRoutes\x12(.subtraterpc.ListCapabilityRoutesRequest\x1a).subtraterpc.ListCapabilityRoutesResponse\x12Y\n" +
+	"\x0eMintCredential\x12\".subtraterpc.MintCredentialRequest\x1a#.subtraterpc.MintCredentialResponse\x12\\\n" +
+	"\x0fListCredentials\x12#.subtraterpc.ListCredentialsRequest\x1a$.subtraterpc.ListCredentialsResponse\x12_\n" +
+	"\x10RevokeCredential\x12$.subtraterpc.RevokeCredentialRequest\x1a%.subtraterpc.RevokeCredentialResponse\x12G\n" +
+	"\bSetLimit\x12\x1c.subtraterpc.SetLimitRequest\x1a\x1d.subtraterpc.SetLimitResponse\x12M\n" +
+	"\n" +
+	"ClearLimit\x12\x1e.subtraterpc.ClearLimitRequest\x1a\x1f.subtraterpc.ClearLimitResponse\x12M\n" +
+	"\n" +
+	"ListLimits\x12\x1e.subtraterpc.ListLimitsRequest\x1a\x1f.subtraterpc.ListLimitsResponse\x12G\n" +
+	"\bGetUsage\x12\x1c.subtraterpc.GetUsageRequest\x1a\x1d.subtraterpc.GetUsageResponse2\xe0\x02\n" +
 	"\aSession\x12S\n" +
 	"\fListSessions\x12 .subtraterpc.ListSessionsRequest\x1a!.subtraterpc.ListSessionsResponse\x12M\n" +
 	"\n" +
@@ -11922,7 +20979,13 @@ const file_mail_proto_rawDesc = "" +
 	"\x16GetPlanReviewBySession\x12*.subtraterpc.GetPlanReviewBySessionRequest\x1a\x1c.subtraterpc.PlanReviewProto\x12\\\n" +
 	"\x0fListPlanReviews\x12#.subtraterpc.ListPlanReviewsRequest\x1a$.subtraterpc.ListPlanReviewsResponse\x12b\n" +
 	"\x16UpdatePlanReviewStatus\x12*.subtraterpc.UpdatePlanReviewStatusRequest\x1a\x1c.subtraterpc.PlanReviewProto\x12_\n" +
-	"\x10DeletePlanReview\x12$.subtraterpc.DeletePlanReviewRequest\x1a%.subtraterpc.DeletePlanReviewResponse2\xc9\x06\n" +
+	"\x10DeletePlanReview\x12$.subtraterpc.DeletePlanReviewRequest\x1a%.subtraterpc.DeletePlanReviewResponse2\xd5\x03\n" +
+	"\x10InboxRuleService\x12S\n" +
+	"\x0fCreateInboxRule\x12#.subtraterpc.CreateInboxRuleRequest\x1a\x1b.subtraterpc.InboxRuleProto\x12Y\n" +
+	"\x0eListInboxRules\x12\".subtraterpc.ListInboxRulesRequest\x1a#.subtraterpc.ListInboxRulesResponse\x12\\\n" +
+	"\x0fDeleteInboxRule\x12#.subtraterpc.DeleteInboxRuleRequest\x1a$.subtraterpc.DeleteInboxRuleResponse\x12[\n" +
+	"\x13SetInboxRuleEnabled\x12'.subtraterpc.SetInboxRuleEnabledRequest\x1a\x1b.subtraterpc.InboxRuleProto\x12V\n" +
+	"\rTestInboxRule\x12!.subtraterpc.TestInboxRuleRequest\x1a\".subtraterpc.TestInboxRuleResponse2\xc9\x06\n" +
 	"\x11AnnotationService\x12b\n" +
 	"\x14CreatePlanAnnotation\x12(.subtraterpc.CreatePlanAnnotationRequest\x1a .subtraterpc.PlanAnnotationProto\x12h\n" +
 	"\x13ListPlanAnnotations\x12'.subtraterpc.ListPlanAnnotationsRequest\x1a(.subtraterpc.ListPlanAnnotationsResponse\x12b\n" +
@@ -11945,429 +21008,765 @@ func file_mail_proto_rawDescGZIP() []byte {
 	return file_mail_proto_rawDescData
 }
 
-var file_mail_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
-var file_mail_proto_msgTypes = make([]protoimpl.MessageInfo, 169)
+var file_mail_proto_enumTypes = make([]protoimpl.EnumInfo, 11)
+var file_mail_proto_msgTypes = make([]protoimpl.MessageInfo, 294)
 var file_mail_proto_goTypes = []any{
 	(Priority)(0),                          // 0: subtraterpc.Priority
 	(MessageState)(0),                      // 1: subtraterpc.MessageState
-	(AgentStatus)(0),                       // 2: subtraterpc.AgentStatus
-	(SessionStatus)(0),                     // 3: subtraterpc.SessionStatus
-	(ActivityType)(0),                      // 4: subtraterpc.ActivityType
-	(TaskStatus)(0),                        // 5: subtraterpc.TaskStatus
-	(PlanReviewState)(0),                   // 6: subtraterpc.PlanReviewState
-	(*InboxMessage)(nil),                   // 7: subtraterpc.InboxMessage
-	(*SendMailRequest)(nil),                // 8: subtraterpc.SendMailRequest
-	(*SendMailResponse)(nil),               // 9: subtraterpc.SendMailResponse
-	(*FetchInboxRequest)(nil),              // 10: subtraterpc.FetchInboxRequest
-	(*FetchInboxResponse)(nil),             // 11: subtraterpc.FetchInboxResponse
-	(*InboxCategoryCounts)(nil),            // 12: subtraterpc.InboxCategoryCounts
-	(*ReadMessageRequest)(nil),             // 13: subtraterpc.ReadMessageRequest
-	(*ReadMessageResponse)(nil),            // 14: subtraterpc.ReadMessageResponse
-	(*ReadThreadRequest)(nil),              // 15: subtraterpc.ReadThreadRequest
-	(*ReadThreadResponse)(nil),             // 16: subtraterpc.ReadThreadResponse
-	(*UpdateStateRequest)(nil),             // 17: subtraterpc.UpdateStateRequest
-	(*UpdateStateResponse)(nil),            // 18: subtraterpc.UpdateStateResponse
-	(*AckMessageRequest)(nil),              // 19: subtraterpc.AckMessageRequest
-	(*AckMessageResponse)(nil),             // 20: subtraterpc.AckMessageResponse
-	(*GetStatusRequest)(nil),               // 21: subtraterpc.GetStatusRequest
-	(*GetStatusResponse)(nil),              // 22: subtraterpc.GetStatusResponse
-	(*PollChangesRequest)(nil),             // 23: subtraterpc.PollChangesRequest
-	(*PollChangesResponse)(nil),            // 24: subtraterpc.PollChangesResponse
-	(*SubscribeInboxRequest)(nil),          // 25: subtraterpc.SubscribeInboxRequest
-	(*PublishRequest)(nil),                 // 26: subtraterpc.PublishRequest
-	(*PublishResponse)(nil),                // 27: subtraterpc.PublishResponse
-	(*SubscribeRequest)(nil),               // 28: subtraterpc.SubscribeRequest
-	(*SubscribeResponse)(nil),              // 29: subtraterpc.SubscribeResponse
-	(*UnsubscribeRequest)(nil),             // 30: subtraterpc.UnsubscribeRequest
-	(*UnsubscribeResponse)(nil),            // 31: subtraterpc.UnsubscribeResponse
-	(*Topic)(nil),                          // 32: subtraterpc.Topic
-	(*ListTopicsRequest)(nil),              // 33: subtraterpc.ListTopicsRequest
-	(*ListTopicsResponse)(nil),             // 34: subtraterpc.ListTopicsResponse
-	(*SearchRequest)(nil),                  // 35: subtraterpc.SearchRequest
-	(*SearchResponse)(nil),                 // 36: subtraterpc.SearchResponse
-	(*HasUnackedStatusToRequest)(nil),      // 37: subtraterpc.HasUnackedStatusToRequest
-	(*HasUnackedStatusToResponse)(nil),     // 38: subtraterpc.HasUnackedStatusToResponse
-	(*RegisterAgentRequest)(nil),           // 39: subtraterpc.RegisterAgentRequest
-	(*RegisterAgentResponse)(nil),          // 40: subtraterpc.RegisterAgentResponse
-	(*GetAgentRequest)(nil),                // 41: subtraterpc.GetAgentRequest
-	(*GetAgentResponse)(nil),               // 42: subtraterpc.GetAgentResponse
-	(*ListAgentsRequest)(nil),              // 43: subtraterpc.ListAgentsRequest
-	(*ListAgentsResponse)(nil),             // 44: subtraterpc.ListAgentsResponse
-	(*EnsureIdentityRequest)(nil),          // 45: subtraterpc.EnsureIdentityRequest
-	(*EnsureIdentityResponse)(nil),         // 46: subtraterpc.EnsureIdentityResponse
-	(*SaveIdentityRequest)(nil),            // 47: subtraterpc.SaveIdentityRequest
-	(*SaveIdentityResponse)(nil),           // 48: subtraterpc.SaveIdentityResponse
-	(*DeleteAgentRequest)(nil),             // 49: subtraterpc.DeleteAgentRequest
-	(*DeleteAgentResponse)(nil),            // 50: subtraterpc.DeleteAgentResponse
-	(*ReplyToThreadRequest)(nil),           // 51: subtraterpc.ReplyToThreadRequest
-	(*ReplyToThreadResponse)(nil),          // 52: subtraterpc.ReplyToThreadResponse
-	(*ArchiveThreadRequest)(nil),           // 53: subtraterpc.ArchiveThreadRequest
-	(*ArchiveThreadResponse)(nil),          // 54: subtraterpc.ArchiveThreadResponse
-	(*DeleteThreadRequest)(nil),            // 55: subtraterpc.DeleteThreadRequest
-	(*DeleteThreadResponse)(nil),           // 56: subtraterpc.DeleteThreadResponse
-	(*MarkThreadUnreadRequest)(nil),        // 57: subtraterpc.MarkThreadUnreadRequest
-	(*MarkThreadUnreadResponse)(nil),       // 58: subtraterpc.MarkThreadUnreadResponse
-	(*GetTopicRequest)(nil),                // 59: subtraterpc.GetTopicRequest
-	(*GetTopicResponse)(nil),               // 60: subtraterpc.GetTopicResponse
-	(*AutocompleteRecipientsRequest)(nil),  // 61: subtraterpc.AutocompleteRecipientsRequest
-	(*AutocompleteRecipient)(nil),          // 62: subtraterpc.AutocompleteRecipient
-	(*AutocompleteRecipientsResponse)(nil), // 63: subtraterpc.AutocompleteRecipientsResponse
-	(*DeleteMessageRequest)(nil),           // 64: subtraterpc.DeleteMessageRequest
-	(*DeleteMessageResponse)(nil),          // 65: subtraterpc.DeleteMessageResponse
-	(*UpdateAgentRequest)(nil),             // 66: subtraterpc.UpdateAgentRequest
-	(*UpdateAgentResponse)(nil),            // 67: subtraterpc.UpdateAgentResponse
-	(*AgentWithStatus)(nil),                // 68: subtraterpc.AgentWithStatus
-	(*AgentStatusCounts)(nil),              // 69: subtraterpc.AgentStatusCounts
-	(*GetAgentsStatusRequest)(nil),         // 70: subtraterpc.GetAgentsStatusRequest
-	(*GetAgentsStatusResponse)(nil),        // 71: subtraterpc.GetAgentsStatusResponse
-	(*DiscoverAgentsRequest)(nil),          // 72: subtraterpc.DiscoverAgentsRequest
-	(*DiscoveredAgent)(nil),                // 73: subtraterpc.DiscoveredAgent
-	(*DiscoverAgentsResponse)(nil),         // 74: subtraterpc.DiscoverAgentsResponse
-	(*HeartbeatRequest)(nil),               // 75: subtraterpc.HeartbeatRequest
-	(*HeartbeatResponse)(nil),              // 76: subtraterpc.HeartbeatResponse
-	(*SessionInfo)(nil),                    // 77: subtraterpc.SessionInfo
-	(*ListSessionsRequest)(nil),            // 78: subtraterpc.ListSessionsRequest
-	(*ListSessionsResponse)(nil),           // 79: subtraterpc.ListSessionsResponse
-	(*GetSessionRequest)(nil),              // 80: subtraterpc.GetSessionRequest
-	(*GetSessionResponse)(nil),             // 81: subtraterpc.GetSessionResponse
-	(*StartSessionRequest)(nil),            // 82: subtraterpc.StartSessionRequest
-	(*StartSessionResponse)(nil),           // 83: subtraterpc.StartSessionResponse
-	(*CompleteSessionRequest)(nil),         // 84: subtraterpc.CompleteSessionRequest
-	(*CompleteSessionResponse)(nil),        // 85: subtraterpc.CompleteSessionResponse
-	(*ActivityInfo)(nil),                   // 86: subtraterpc.ActivityInfo
-	(*ListActivitiesRequest)(nil),          // 87: subtraterpc.ListActivitiesRequest
-	(*ListActivitiesResponse)(nil),         // 88: subtraterpc.ListActivitiesResponse
-	(*DashboardStats)(nil),                 // 89: subtraterpc.DashboardStats
-	(*GetDashboardStatsRequest)(nil),       // 90: subtraterpc.GetDashboardStatsRequest
-	(*GetDashboardStatsResponse)(nil),      // 91: subtraterpc.GetDashboardStatsResponse
-	(*HealthCheckRequest)(nil),             // 92: subtraterpc.HealthCheckRequest
-	(*HealthCheckResponse)(nil),            // 93: subtraterpc.HealthCheckResponse
-	(*BranchTarget)(nil),                   // 94: subtraterpc.BranchTarget
-	(*CommitTarget)(nil),                   // 95: subtraterpc.CommitTarget
-	(*CommitRangeTarget)(nil),              // 96: subtraterpc.CommitRangeTarget
-	(*PRTarget)(nil),                       // 97: subtraterpc.PRTarget
-	(*CreateReviewRequest)(nil),            // 98: subtraterpc.CreateReviewRequest
-	(*CreateReviewResponse)(nil),           // 99: subtraterpc.CreateReviewResponse
-	(*ListReviewsProtoRequest)(nil),        // 100: subtraterpc.ListReviewsProtoRequest
-	(*ListReviewsProtoResponse)(nil),       // 101: subtraterpc.ListReviewsProtoResponse
-	(*ReviewSummaryProto)(nil),             // 102: subtraterpc.ReviewSummaryProto
-	(*GetReviewProtoRequest)(nil),          // 103: subtraterpc.GetReviewProtoRequest
-	(*ReviewDetailResponse)(nil),           // 104: subtraterpc.ReviewDetailResponse
-	(*ReviewIterationProto)(nil),           // 105: subtraterpc.ReviewIterationProto
-	(*ResubmitReviewRequest)(nil),          // 106: subtraterpc.ResubmitReviewRequest
-	(*CancelReviewProtoRequest)(nil),       // 107: subtraterpc.CancelReviewProtoRequest
-	(*CancelReviewProtoResponse)(nil),      // 108: subtraterpc.CancelReviewProtoResponse
-	(*DeleteReviewProtoRequest)(nil),       // 109: subtraterpc.DeleteReviewProtoRequest
-	(*DeleteReviewProtoResponse)(nil),      // 110: subtraterpc.DeleteReviewProtoResponse
-	(*ListReviewIssuesRequest)(nil),        // 111: subtraterpc.ListReviewIssuesRequest
-	(*ListReviewIssuesResponse)(nil),       // 112: subtraterpc.ListReviewIssuesResponse
-	(*ReviewIssueProto)(nil),               // 113: subtraterpc.ReviewIssueProto
-	(*UpdateIssueStatusRequest)(nil),       // 114: subtraterpc.UpdateIssueStatusRequest
-	(*UpdateIssueStatusResponse)(nil),      // 115: subtraterpc.UpdateIssueStatusResponse
-	(*GetReviewDiffRequest)(nil),           // 116: subtraterpc.GetReviewDiffRequest
-	(*GetReviewDiffResponse)(nil),          // 117: subtraterpc.GetReviewDiffResponse
-	(*TaskListProto)(nil),                  // 118: subtraterpc.TaskListProto
-	(*TaskProto)(nil),                      // 119: subtraterpc.TaskProto
-	(*TaskStatsProto)(nil),                 // 120: subtraterpc.TaskStatsProto
-	(*AgentTaskStatsProto)(nil),            // 121: subtraterpc.AgentTaskStatsProto
-	(*RegisterTaskListRequest)(nil),        // 122: subtraterpc.RegisterTaskListRequest
-	(*RegisterTaskListResponse)(nil),       // 123: subtraterpc.RegisterTaskListResponse
-	(*GetTaskListRequest)(nil),             // 124: subtraterpc.GetTaskListRequest
-	(*GetTaskListResponse)(nil),            // 125: subtraterpc.GetTaskListResponse
-	(*ListTaskListsRequest)(nil),           // 126: subtraterpc.ListTaskListsRequest
-	(*ListTaskListsResponse)(nil),          // 127: subtraterpc.ListTaskListsResponse
-	(*UnregisterTaskListRequest)(nil),      // 128: subtraterpc.UnregisterTaskListRequest
-	(*UnregisterTaskListResponse)(nil),     // 129: subtraterpc.UnregisterTaskListResponse
-	(*UpsertTaskRequest)(nil),              // 130: subtraterpc.UpsertTaskRequest
-	(*UpsertTaskResponse)(nil),             // 131: subtraterpc.UpsertTaskResponse
-	(*GetTaskProtoRequest)(nil),            // 132: subtraterpc.GetTaskProtoRequest
-	(*GetTaskResponse)(nil),                // 133: subtraterpc.GetTaskResponse
-	(*ListTasksRequest)(nil),               // 134: subtraterpc.ListTasksRequest
-	(*ListTasksResponse)(nil),              // 135: subtraterpc.ListTasksResponse
-	(*UpdateTaskStatusRequest)(nil),        // 136: subtraterpc.UpdateTaskStatusRequest
-	(*UpdateTaskStatusResponse)(nil),       // 137: subtraterpc.UpdateTaskStatusResponse
-	(*UpdateTaskOwnerRequest)(nil),         // 138: subtraterpc.UpdateTaskOwnerRequest
-	(*UpdateTaskOwnerResponse)(nil),        // 139: subtraterpc.UpdateTaskOwnerResponse
-	(*DeleteTaskRequest)(nil),              // 140: subtraterpc.DeleteTaskRequest
-	(*DeleteTaskResponse)(nil),             // 141: subtraterpc.DeleteTaskResponse
-	(*GetTaskStatsRequest)(nil),            // 142: subtraterpc.GetTaskStatsRequest
-	(*GetTaskStatsResponse)(nil),           // 143: subtraterpc.GetTaskStatsResponse
-	(*GetAllAgentTaskStatsRequest)(nil),    // 144: subtraterpc.GetAllAgentTaskStatsRequest
-	(*GetAllAgentTaskStatsResponse)(nil),   // 145: subtraterpc.GetAllAgentTaskStatsResponse
-	(*SyncTaskListRequest)(nil),            // 146: subtraterpc.SyncTaskListRequest
-	(*SyncTaskListResponse)(nil),           // 147: subtraterpc.SyncTaskListResponse
-	(*PruneOldTasksRequest)(nil),           // 148: subtraterpc.PruneOldTasksRequest
-	(*PruneOldTasksResponse)(nil),          // 149: subtraterpc.PruneOldTasksResponse
-	(*PlanReviewProto)(nil),                // 150: subtraterpc.PlanReviewProto
-	(*CreatePlanReviewRequest)(nil),        // 151: subtraterpc.CreatePlanReviewRequest
-	(*GetPlanReviewRequest)(nil),           // 152: subtraterpc.GetPlanReviewRequest
-	(*GetPlanReviewByThreadRequest)(nil),   // 153: subtraterpc.GetPlanReviewByThreadRequest
-	(*GetPlanReviewBySessionRequest)(nil),  // 154: subtraterpc.GetPlanReviewBySessionRequest
-	(*ListPlanReviewsRequest)(nil),         // 155: subtraterpc.ListPlanReviewsRequest
-	(*ListPlanReviewsResponse)(nil),        // 156: subtraterpc.ListPlanReviewsResponse
-	(*UpdatePlanReviewStatusRequest)(nil),  // 157: subtraterpc.UpdatePlanReviewStatusRequest
-	(*DeletePlanReviewRequest)(nil),        // 158: subtraterpc.DeletePlanReviewRequest
-	(*DeletePlanReviewResponse)(nil),       // 159: subtraterpc.DeletePlanReviewResponse
-	(*PlanAnnotationProto)(nil),            // 160: subtraterpc.PlanAnnotationProto
-	(*DiffAnnotationProto)(nil),            // 161: subtraterpc.DiffAnnotationProto
-	(*CreatePlanAnnotationRequest)(nil),    // 162: subtraterpc.CreatePlanAnnotationRequest
-	(*ListPlanAnnotationsRequest)(nil),     // 163: subtraterpc.ListPlanAnnotationsRequest
-	(*ListPlanAnnotationsResponse)(nil),    // 164: subtraterpc.ListPlanAnnotationsResponse
-	(*UpdatePlanAnnotationRequest)(nil),    // 165: subtraterpc.UpdatePlanAnnotationRequest
-	(*DeletePlanAnnotationRequest)(nil),    // 166: subtraterpc.DeletePlanAnnotationRequest
-	(*DeleteAnnotationResponse)(nil),       // 167: subtraterpc.DeleteAnnotationResponse
-	(*CreateDiffAnnotationRequest)(nil),    // 168: subtraterpc.CreateDiffAnnotationRequest
-	(*ListDiffAnnotationsRequest)(nil),     // 169: subtraterpc.ListDiffAnnotationsRequest
-	(*ListDiffAnnotationsResponse)(nil),    // 170: subtraterpc.ListDiffAnnotationsResponse
-	(*UpdateDiffAnnotationRequest)(nil),    // 171: subtraterpc.UpdateDiffAnnotationRequest
-	(*DeleteDiffAnnotationRequest)(nil),    // 172: subtraterpc.DeleteDiffAnnotationRequest
-	nil,                                    // 173: subtraterpc.PollChangesRequest.SinceOffsetsEntry
-	nil,                                    // 174: subtraterpc.PollChangesResponse.NewOffsetsEntry
-	nil,                                    // 175: subtraterpc.SaveIdentityRequest.ConsumerOffsetsEntry
-	(*timestamppb.Timestamp)(nil),          // 176: google.protobuf.Timestamp
+	(DeliveryMode)(0),                      // 2: subtraterpc.DeliveryMode
+	(RecipientStatus)(0),                   // 3: subtraterpc.RecipientStatus
+	(WatchEventType)(0),                    // 4: subtraterpc.WatchEventType
+	(SearchKind)(0),                        // 5: subtraterpc.SearchKind
+	(AgentStatus)(0),                       // 6: subtraterpc.AgentStatus
+	(SessionStatus)(0),                     // 7: subtraterpc.SessionStatus
+	(ActivityType)(0),                      // 8: subtraterpc.ActivityType
+	(TaskStatus)(0),                        // 9: subtraterpc.TaskStatus
+	(PlanReviewState)(0),                   // 10: subtraterpc.PlanReviewState
+	(*InboxMessage)(nil),                   // 11: subtraterpc.InboxMessage
+	(*SendMailRequest)(nil),                // 12: subtraterpc.SendMailRequest
+	(*SendMailResponse)(nil),               // 13: subtraterpc.SendMailResponse
+	(*RecipientDelivery)(nil),              // 14: subtraterpc.RecipientDelivery
+	(*RecipientExpansion)(nil),             // 15: subtraterpc.RecipientExpansion
+	(*FetchInboxRequest)(nil),              // 16: subtraterpc.FetchInboxRequest
+	(*FetchInboxResponse)(nil),             // 17: subtraterpc.FetchInboxResponse
+	(*InboxCategoryCounts)(nil),            // 18: subtraterpc.InboxCategoryCounts
+	(*ReadMessageRequest)(nil),             // 19: subtraterpc.ReadMessageRequest
+	(*ReadMessageResponse)(nil),            // 20: subtraterpc.ReadMessageResponse
+	(*ReadThreadRequest)(nil),              // 21: subtraterpc.ReadThreadRequest
+	(*ReadThreadResponse)(nil),             // 22: subtraterpc.ReadThreadResponse
+	(*UpdateStateRequest)(nil),             // 23: subtraterpc.UpdateStateRequest
+	(*UpdateStateResponse)(nil),            // 24: subtraterpc.UpdateStateResponse
+	(*AckMessageRequest)(nil),              // 25: subtraterpc.AckMessageRequest
+	(*AckMessageResponse)(nil),             // 26: subtraterpc.AckMessageResponse
+	(*GetStatusRequest)(nil),               // 27: subtraterpc.GetStatusRequest
+	(*GetStatusResponse)(nil),              // 28: subtraterpc.GetStatusResponse
+	(*PollChangesRequest)(nil),             // 29: subtraterpc.PollChangesRequest
+	(*PollChangesResponse)(nil),            // 30: subtraterpc.PollChangesResponse
+	(*SubscribeInboxRequest)(nil),          // 31: subtraterpc.SubscribeInboxRequest
+	(*WatchInboxRequest)(nil),              // 32: subtraterpc.WatchInboxRequest
+	(*WatchInboxEvent)(nil),                // 33: subtraterpc.WatchInboxEvent
+	(*GetWatcherRequest)(nil),              // 34: subtraterpc.GetWatcherRequest
+	(*GetWatcherResponse)(nil),             // 35: subtraterpc.GetWatcherResponse
+	(*PublishRequest)(nil),                 // 36: subtraterpc.PublishRequest
+	(*PublishResponse)(nil),                // 37: subtraterpc.PublishResponse
+	(*SubscribeRequest)(nil),               // 38: subtraterpc.SubscribeRequest
+	(*SubscribeResponse)(nil),              // 39: subtraterpc.SubscribeResponse
+	(*UnsubscribeRequest)(nil),             // 40: subtraterpc.UnsubscribeRequest
+	(*UnsubscribeResponse)(nil),            // 41: subtraterpc.UnsubscribeResponse
+	(*ClaimQueueMessageRequest)(nil),       // 42: subtraterpc.ClaimQueueMessageRequest
+	(*ClaimQueueMessageResponse)(nil),      // 43: subtraterpc.ClaimQueueMessageResponse
+	(*AckQueueMessageRequest)(nil),         // 44: subtraterpc.AckQueueMessageRequest
+	(*AckQueueMessageResponse)(nil),        // 45: subtraterpc.AckQueueMessageResponse
+	(*ReleaseQueueMessageRequest)(nil),     // 46: subtraterpc.ReleaseQueueMessageRequest
+	(*ReleaseQueueMessageResponse)(nil),    // 47: subtraterpc.ReleaseQueueMessageResponse
+	(*ExtendQueueLeaseRequest)(nil),        // 48: subtraterpc.ExtendQueueLeaseRequest
+	(*ExtendQueueLeaseResponse)(nil),       // 49: subtraterpc.ExtendQueueLeaseResponse
+	(*Topic)(nil),                          // 50: subtraterpc.Topic
+	(*ListTopicsRequest)(nil),              // 51: subtraterpc.ListTopicsRequest
+	(*ListTopicsResponse)(nil),             // 52: subtraterpc.ListTopicsResponse
+	(*SearchRequest)(nil),                  // 53: subtraterpc.SearchRequest
+	(*SearchHit)(nil),                      // 54: subtraterpc.SearchHit
+	(*SearchResponse)(nil),                 // 55: subtraterpc.SearchResponse
+	(*SearchAllRequest)(nil),               // 56: subtraterpc.SearchAllRequest
+	(*SearchAllHit)(nil),                   // 57: subtraterpc.SearchAllHit
+	(*SearchAllResponse)(nil),              // 58: subtraterpc.SearchAllResponse
+	(*SavedSearchProto)(nil),               // 59: subtraterpc.SavedSearchProto
+	(*SaveSearchRequest)(nil),              // 60: subtraterpc.SaveSearchRequest
+	(*ListSavedSearchesRequest)(nil),       // 61: subtraterpc.ListSavedSearchesRequest
+	(*ListSavedSearchesResponse)(nil),      // 62: subtraterpc.ListSavedSearchesResponse
+	(*DeleteSavedSearchRequest)(nil),       // 63: subtraterpc.DeleteSavedSearchRequest
+	(*DeleteSavedSearchResponse)(nil),      // 64: subtraterpc.DeleteSavedSearchResponse
+	(*SemanticSearchRequest)(nil),          // 65: subtraterpc.SemanticSearchRequest
+	(*SimilarMessagesRequest)(nil),         // 66: subtraterpc.SimilarMessagesRequest
+	(*SemanticHit)(nil),                    // 67: subtraterpc.SemanticHit
+	(*SemanticSearchResponse)(nil),         // 68: subtraterpc.SemanticSearchResponse
+	(*HasUnackedStatusToRequest)(nil),      // 69: subtraterpc.HasUnackedStatusToRequest
+	(*HasUnackedStatusToResponse)(nil),     // 70: subtraterpc.HasUnackedStatusToResponse
+	(*RegisterAgentRequest)(nil),           // 71: subtraterpc.RegisterAgentRequest
+	(*RegisterAgentResponse)(nil),          // 72: subtraterpc.RegisterAgentResponse
+	(*AgentCapabilities)(nil),              // 73: subtraterpc.AgentCapabilities
+	(*GetAgentRequest)(nil),                // 74: subtraterpc.GetAgentRequest
+	(*GetAgentResponse)(nil),               // 75: subtraterpc.GetAgentResponse
+	(*ListAgentsRequest)(nil),              // 76: subtraterpc.ListAgentsRequest
+	(*ListAgentsResponse)(nil),             // 77: subtraterpc.ListAgentsResponse
+	(*EnsureIdentityRequest)(nil),          // 78: subtraterpc.EnsureIdentityRequest
+	(*EnsureIdentityResponse)(nil),         // 79: subtraterpc.EnsureIdentityResponse
+	(*SaveIdentityRequest)(nil),            // 80: subtraterpc.SaveIdentityRequest
+	(*SaveIdentityResponse)(nil),           // 81: subtraterpc.SaveIdentityResponse
+	(*DeleteAgentRequest)(nil),             // 82: subtraterpc.DeleteAgentRequest
+	(*DeleteAgentResponse)(nil),            // 83: subtraterpc.DeleteAgentResponse
+	(*ReplyToThreadRequest)(nil),           // 84: subtraterpc.ReplyToThreadRequest
+	(*ReplyToThreadResponse)(nil),          // 85: subtraterpc.ReplyToThreadResponse
+	(*ArchiveThreadRequest)(nil),           // 86: subtraterpc.ArchiveThreadRequest
+	(*ArchiveThreadResponse)(nil),          // 87: subtraterpc.ArchiveThreadResponse
+	(*DeleteThreadRequest)(nil),            // 88: subtraterpc.DeleteThreadRequest
+	(*DeleteThreadResponse)(nil),           // 89: subtraterpc.DeleteThreadResponse
+	(*MarkThreadUnreadRequest)(nil),        // 90: subtraterpc.MarkThreadUnreadRequest
+	(*MarkThreadUnreadResponse)(nil),       // 91: subtraterpc.MarkThreadUnreadResponse
+	(*GetTopicRequest)(nil),                // 92: subtraterpc.GetTopicRequest
+	(*GetTopicResponse)(nil),               // 93: subtraterpc.GetTopicResponse
+	(*AutocompleteRecipientsRequest)(nil),  // 94: subtraterpc.AutocompleteRecipientsRequest
+	(*AutocompleteRecipient)(nil),          // 95: subtraterpc.AutocompleteRecipient
+	(*AutocompleteRecipientsResponse)(nil), // 96: subtraterpc.AutocompleteRecipientsResponse
+	(*DeleteMessageRequest)(nil),           // 97: subtraterpc.DeleteMessageRequest
+	(*DeleteMessageResponse)(nil),          // 98: subtraterpc.DeleteMessageResponse
+	(*CollectGarbageRequest)(nil),          // 99: subtraterpc.CollectGarbageRequest
+	(*TopicRetentionReport)(nil),           // 100: subtraterpc.TopicRetentionReport
+	(*CollectGarbageResponse)(nil),         // 101: subtraterpc.CollectGarbageResponse
+	(*SetLegalHoldRequest)(nil),            // 102: subtraterpc.SetLegalHoldRequest
+	(*SetLegalHoldResponse)(nil),           // 103: subtraterpc.SetLegalHoldResponse
+	(*SetTopicRetentionRequest)(nil),       // 104: subtraterpc.SetTopicRetentionRequest
+	(*SetTopicRetentionResponse)(nil),      // 105: subtraterpc.SetTopicRetentionResponse
+	(*RecipientGroup)(nil),                 // 106: subtraterpc.RecipientGroup
+	(*CreateRecipientGroupRequest)(nil),    // 107: subtraterpc.CreateRecipientGroupRequest
+	(*GetRecipientGroupRequest)(nil),       // 108: subtraterpc.GetRecipientGroupRequest
+	(*RecipientGroupResponse)(nil),         // 109: subtraterpc.RecipientGroupResponse
+	(*ListRecipientGroupsRequest)(nil),     // 110: subtraterpc.ListRecipientGroupsRequest
+	(*ListRecipientGroupsResponse)(nil),    // 111: subtraterpc.ListRecipientGroupsResponse
+	(*DeleteRecipientGroupRequest)(nil),    // 112: subtraterpc.DeleteRecipientGroupRequest
+	(*DeleteRecipientGroupResponse)(nil),   // 113: subtraterpc.DeleteRecipientGroupResponse
+	(*RecipientGroupMembersRequest)(nil),   // 114: subtraterpc.RecipientGroupMembersRequest
+	(*DeadLetter)(nil),                     // 115: subtraterpc.DeadLetter
+	(*ListDeadLettersRequest)(nil),         // 116: subtraterpc.ListDeadLettersRequest
+	(*ListDeadLettersResponse)(nil),        // 117: subtraterpc.ListDeadLettersResponse
+	(*DeleteDeadLetterRequest)(nil),        // 118: subtraterpc.DeleteDeadLetterRequest
+	(*DeleteDeadLetterResponse)(nil),       // 119: subtraterpc.DeleteDeadLetterResponse
+	(*ScheduledMessage)(nil),               // 120: subtraterpc.ScheduledMessage
+	(*ListScheduledMessagesRequest)(nil),   // 121: subtraterpc.ListScheduledMessagesRequest
+	(*ListScheduledMessagesResponse)(nil),  // 122: subtraterpc.ListScheduledMessagesResponse
+	(*CancelScheduledMessageRequest)(nil),  // 123: subtraterpc.CancelScheduledMessageRequest
+	(*CancelScheduledMessageResponse)(nil), // 124: subtraterpc.CancelScheduledMessageResponse
+	(*Schedule)(nil),                       // 125: subtraterpc.Schedule
+	(*CreateScheduleRequest)(nil),          // 126: subtraterpc.CreateScheduleRequest
+	(*ScheduleResponse)(nil),               // 127: subtraterpc.ScheduleResponse
+	(*ListSchedulesRequest)(nil),           // 128: subtraterpc.ListSchedulesRequest
+	(*ListSchedulesResponse)(nil),          // 129: subtraterpc.ListSchedulesResponse
+	(*DeleteScheduleRequest)(nil),          // 130: subtraterpc.DeleteScheduleRequest
+	(*DeleteScheduleResponse)(nil),         // 131: subtraterpc.DeleteScheduleResponse
+	(*SetSchedulePausedRequest)(nil),       // 132: subtraterpc.SetSchedulePausedRequest
+	(*Question)(nil),                       // 133: subtraterpc.Question
+	(*AskQuestionRequest)(nil),             // 134: subtraterpc.AskQuestionRequest
+	(*GetQuestionRequest)(nil),             // 135: subtraterpc.GetQuestionRequest
+	(*AnswerQuestionRequest)(nil),          // 136: subtraterpc.AnswerQuestionRequest
+	(*QuestionResponse)(nil),               // 137: subtraterpc.QuestionResponse
+	(*MessageRevision)(nil),                // 138: subtraterpc.MessageRevision
+	(*EditMessageRequest)(nil),             // 139: subtraterpc.EditMessageRequest
+	(*EditMessageResponse)(nil),            // 140: subtraterpc.EditMessageResponse
+	(*RecallMessageRequest)(nil),           // 141: subtraterpc.RecallMessageRequest
+	(*RecallMessageResponse)(nil),          // 142: subtraterpc.RecallMessageResponse
+	(*GetMessageHistoryRequest)(nil),       // 143: subtraterpc.GetMessageHistoryRequest
+	(*GetMessageHistoryResponse)(nil),      // 144: subtraterpc.GetMessageHistoryResponse
+	(*LabelProto)(nil),                     // 145: subtraterpc.LabelProto
+	(*CreateLabelRequest)(nil),             // 146: subtraterpc.CreateLabelRequest
+	(*ListLabelsRequest)(nil),              // 147: subtraterpc.ListLabelsRequest
+	(*ListLabelsResponse)(nil),             // 148: subtraterpc.ListLabelsResponse
+	(*DeleteLabelRequest)(nil),             // 149: subtraterpc.DeleteLabelRequest
+	(*DeleteLabelResponse)(nil),            // 150: subtraterpc.DeleteLabelResponse
+	(*LabelMessageRequest)(nil),            // 151: subtraterpc.LabelMessageRequest
+	(*LabelMessageResponse)(nil),           // 152: subtraterpc.LabelMessageResponse
+	(*ThreadPreferenceProto)(nil),          // 153: subtraterpc.ThreadPreferenceProto
+	(*SetThreadMutedRequest)(nil),          // 154: subtraterpc.SetThreadMutedRequest
+	(*SetThreadFollowingRequest)(nil),      // 155: subtraterpc.SetThreadFollowingRequest
+	(*ListThreadPreferencesRequest)(nil),   // 156: subtraterpc.ListThreadPreferencesRequest
+	(*ListThreadPreferencesResponse)(nil),  // 157: subtraterpc.ListThreadPreferencesResponse
+	(*MergeThreadsRequest)(nil),            // 158: subtraterpc.MergeThreadsRequest
+	(*MergeThreadsResponse)(nil),           // 159: subtraterpc.MergeThreadsResponse
+	(*SplitThreadRequest)(nil),             // 160: subtraterpc.SplitThreadRequest
+	(*SplitThreadResponse)(nil),            // 161: subtraterpc.SplitThreadResponse
+	(*UpdateAgentRequest)(nil),             // 162: subtraterpc.UpdateAgentRequest
+	(*UpdateAgentResponse)(nil),            // 163: subtraterpc.UpdateAgentResponse
+	(*AgentWithStatus)(nil),                // 164: subtraterpc.AgentWithStatus
+	(*AgentStatusCounts)(nil),              // 165: subtraterpc.AgentStatusCounts
+	(*GetAgentsStatusRequest)(nil),         // 166: subtraterpc.GetAgentsStatusRequest
+	(*GetAgentsStatusResponse)(nil),        // 167: subtraterpc.GetAgentsStatusResponse
+	(*DiscoverAgentsRequest)(nil),          // 168: subtraterpc.DiscoverAgentsRequest
+	(*DiscoveredAgent)(nil),                // 169: subtraterpc.DiscoveredAgent
+	(*DiscoverAgentsResponse)(nil),         // 170: subtraterpc.DiscoverAgentsResponse
+	(*ListCapabilityRoutesRequest)(nil),    // 171: subtraterpc.ListCapabilityRoutesRequest
+	(*CapabilityRoute)(nil),                // 172: subtraterpc.CapabilityRoute
+	(*ListCapabilityRoutesResponse)(nil),   // 173: subtraterpc.ListCapabilityRoutesResponse
+	(*Credential)(nil),                     // 174: subtraterpc.Credential
+	(*MintCredentialRequest)(nil),          // 175: subtraterpc.MintCredentialRequest
+	(*MintCredentialResponse)(nil),         // 176: subtraterpc.MintCredentialResponse
+	(*ListCredentialsRequest)(nil),         // 177: subtraterpc.ListCredentialsRequest
+	(*ListCredentialsResponse)(nil),        // 178: subtraterpc.ListCredentialsResponse
+	(*RevokeCredentialRequest)(nil),        // 179: subtraterpc.RevokeCredentialRequest
+	(*RevokeCredentialResponse)(nil),       // 180: subtraterpc.RevokeCredentialResponse
+	(*AgentLimit)(nil),                     // 181: subtraterpc.AgentLimit
+	(*SetLimitRequest)(nil),                // 182: subtraterpc.SetLimitRequest
+	(*SetLimitResponse)(nil),               // 183: subtraterpc.SetLimitResponse
+	(*ClearLimitRequest)(nil),              // 184: subtraterpc.ClearLimitRequest
+	(*ClearLimitResponse)(nil),             // 185: subtraterpc.ClearLimitResponse
+	(*ListLimitsRequest)(nil),              // 186: subtraterpc.ListLimitsRequest
+	(*ListLimitsResponse)(nil),             // 187: subtraterpc.ListLimitsResponse
+	(*LimitUsage)(nil),                     // 188: subtraterpc.LimitUsage
+	(*GetUsageRequest)(nil),                // 189: subtraterpc.GetUsageRequest
+	(*GetUsageResponse)(nil),               // 190: subtraterpc.GetUsageResponse
+	(*HeartbeatRequest)(nil),               // 191: subtraterpc.HeartbeatRequest
+	(*HeartbeatResponse)(nil),              // 192: subtraterpc.HeartbeatResponse
+	(*SessionInfo)(nil),                    // 193: subtraterpc.SessionInfo
+	(*ListSessionsRequest)(nil),            // 194: subtraterpc.ListSessionsRequest
+	(*ListSessionsResponse)(nil),           // 195: subtraterpc.ListSessionsResponse
+	(*GetSessionRequest)(nil),              // 196: subtraterpc.GetSessionRequest
+	(*GetSessionResponse)(nil),             // 197: subtraterpc.GetSessionResponse
+	(*StartSessionRequest)(nil),            // 198: subtraterpc.StartSessionRequest
+	(*StartSessionResponse)(nil),           // 199: subtraterpc.StartSessionResponse
+	(*CompleteSessionRequest)(nil),         // 200: subtraterpc.CompleteSessionRequest
+	(*CompleteSessionResponse)(nil),        // 201: subtraterpc.CompleteSessionResponse
+	(*ActivityInfo)(nil),                   // 202: subtraterpc.ActivityInfo
+	(*ListActivitiesRequest)(nil),          // 203: subtraterpc.ListActivitiesRequest
+	(*ListActivitiesResponse)(nil),         // 204: subtraterpc.ListActivitiesResponse
+	(*DashboardStats)(nil),                 // 205: subtraterpc.DashboardStats
+	(*GetDashboardStatsRequest)(nil),       // 206: subtraterpc.GetDashboardStatsRequest
+	(*GetDashboardStatsResponse)(nil),      // 207: subtraterpc.GetDashboardStatsResponse
+	(*HealthCheckRequest)(nil),             // 208: subtraterpc.HealthCheckRequest
+	(*HealthCheckResponse)(nil),            // 209: subtraterpc.HealthCheckResponse
+	(*BranchTarget)(nil),                   // 210: subtraterpc.BranchTarget
+	(*CommitTarget)(nil),                   // 211: subtraterpc.CommitTarget
+	(*CommitRangeTarget)(nil),              // 212: subtraterpc.CommitRangeTarget
+	(*PRTarget)(nil),                       // 213: subtraterpc.PRTarget
+	(*CreateReviewRequest)(nil),            // 214: subtraterpc.CreateReviewRequest
+	(*CreateReviewResponse)(nil),           // 215: subtraterpc.CreateReviewResponse
+	(*ListReviewsProtoRequest)(nil),        // 216: subtraterpc.ListReviewsProtoRequest
+	(*ListReviewsProtoResponse)(nil),       // 217: subtraterpc.ListReviewsProtoResponse
+	(*ReviewSummaryProto)(nil),             // 218: subtraterpc.ReviewSummaryProto
+	(*GetReviewProtoRequest)(nil),          // 219: subtraterpc.GetReviewProtoRequest
+	(*ReviewDetailResponse)(nil),           // 220: subtraterpc.ReviewDetailResponse
+	(*ReviewIterationProto)(nil),           // 221: subtraterpc.ReviewIterationProto
+	(*ResubmitReviewRequest)(nil),          // 222: subtraterpc.ResubmitReviewRequest
+	(*CancelReviewProtoRequest)(nil),       // 223: subtraterpc.CancelReviewProtoRequest
+	(*CancelReviewProtoResponse)(nil),      // 224: subtraterpc.CancelReviewProtoResponse
+	(*DeleteReviewProtoRequest)(nil),       // 225: subtraterpc.DeleteReviewProtoRequest
+	(*DeleteReviewProtoResponse)(nil),      // 226: subtraterpc.DeleteReviewProtoResponse
+	(*ListReviewIssuesRequest)(nil),        // 227: subtraterpc.ListReviewIssuesRequest
+	(*ListReviewIssuesResponse)(nil),       // 228: subtraterpc.ListReviewIssuesResponse
+	(*ReviewIssueProto)(nil),               // 229: subtraterpc.ReviewIssueProto
+	(*UpdateIssueStatusRequest)(nil),       // 230: subtraterpc.UpdateIssueStatusRequest
+	(*UpdateIssueStatusResponse)(nil),      // 231: subtraterpc.UpdateIssueStatusResponse
+	(*GetReviewDiffRequest)(nil),           // 232: subtraterpc.GetReviewDiffRequest
+	(*GetReviewDiffResponse)(nil),          // 233: subtraterpc.GetReviewDiffResponse
+	(*TaskListProto)(nil),                  // 234: subtraterpc.TaskListProto
+	(*TaskProto)(nil),                      // 235: subtraterpc.TaskProto
+	(*TaskStatsProto)(nil),                 // 236: subtraterpc.TaskStatsProto
+	(*AgentTaskStatsProto)(nil),            // 237: subtraterpc.AgentTaskStatsProto
+	(*RegisterTaskListRequest)(nil),        // 238: subtraterpc.RegisterTaskListRequest
+	(*RegisterTaskListResponse)(nil),       // 239: subtraterpc.RegisterTaskListResponse
+	(*GetTaskListRequest)(nil),             // 240: subtraterpc.GetTaskListRequest
+	(*GetTaskListResponse)(nil),            // 241: subtraterpc.GetTaskListResponse
+	(*ListTaskListsRequest)(nil),           // 242: subtraterpc.ListTaskListsRequest
+	(*ListTaskListsResponse)(nil),          // 243: subtraterpc.ListTaskListsResponse
+	(*UnregisterTaskListRequest)(nil),      // 244: subtraterpc.UnregisterTaskListRequest
+	(*UnregisterTaskListResponse)(nil),     // 245: subtraterpc.UnregisterTaskListResponse
+	(*UpsertTaskRequest)(nil),              // 246: subtraterpc.UpsertTaskRequest
+	(*UpsertTaskResponse)(nil),             // 247: subtraterpc.UpsertTaskResponse
+	(*GetTaskProtoRequest)(nil),            // 248: subtraterpc.GetTaskProtoRequest
+	(*GetTaskResponse)(nil),                // 249: subtraterpc.GetTaskResponse
+	(*ListTasksRequest)(nil),               // 250: subtraterpc.ListTasksRequest
+	(*ListTasksResponse)(nil),              // 251: subtraterpc.ListTasksResponse
+	(*UpdateTaskStatusRequest)(nil),        // 252: subtraterpc.UpdateTaskStatusRequest
+	(*UpdateTaskStatusResponse)(nil),       // 253: subtraterpc.UpdateTaskStatusResponse
+	(*UpdateTaskOwnerRequest)(nil),         // 254: subtraterpc.UpdateTaskOwnerRequest
+	(*UpdateTaskOwnerResponse)(nil),        // 255: subtraterpc.UpdateTaskOwnerResponse
+	(*DeleteTaskRequest)(nil),              // 256: subtraterpc.DeleteTaskRequest
+	(*DeleteTaskResponse)(nil),             // 257: subtraterpc.DeleteTaskResponse
+	(*GetTaskStatsRequest)(nil),            // 258: subtraterpc.GetTaskStatsRequest
+	(*GetTaskStatsResponse)(nil),           // 259: subtraterpc.GetTaskStatsResponse
+	(*GetAllAgentTaskStatsRequest)(nil),    // 260: subtraterpc.GetAllAgentTaskStatsRequest
+	(*GetAllAgentTaskStatsResponse)(nil),   // 261: subtraterpc.GetAllAgentTaskStatsResponse
+	(*SyncTaskListRequest)(nil),            // 262: subtraterpc.SyncTaskListRequest
+	(*SyncTaskListResponse)(nil),           // 263: subtraterpc.SyncTaskListResponse
+	(*PruneOldTasksRequest)(nil),           // 264: subtraterpc.PruneOldTasksRequest
+	(*PruneOldTasksResponse)(nil),          // 265: subtraterpc.PruneOldTasksResponse
+	(*PlanReviewProto)(nil),                // 266: subtraterpc.PlanReviewProto
+	(*CreatePlanReviewRequest)(nil),        // 267: subtraterpc.CreatePlanReviewRequest
+	(*GetPlanReviewRequest)(nil),           // 268: subtraterpc.GetPlanReviewRequest
+	(*GetPlanReviewByThreadRequest)(nil),   // 269: subtraterpc.GetPlanReviewByThreadRequest
+	(*GetPlanReviewBySessionRequest)(nil),  // 270: subtraterpc.GetPlanReviewBySessionRequest
+	(*ListPlanReviewsRequest)(nil),         // 271: subtraterpc.ListPlanReviewsRequest
+	(*ListPlanReviewsResponse)(nil),        // 272: subtraterpc.ListPlanReviewsResponse
+	(*UpdatePlanReviewStatusRequest)(nil),  // 273: subtraterpc.UpdatePlanReviewStatusRequest
+	(*DeletePlanReviewRequest)(nil),        // 274: subtraterpc.DeletePlanReviewRequest
+	(*DeletePlanReviewResponse)(nil),       // 275: subtraterpc.DeletePlanReviewResponse
+	(*InboxRuleConditions)(nil),            // 276: subtraterpc.InboxRuleConditions
+	(*InboxRuleActions)(nil),               // 277: subtraterpc.InboxRuleActions
+	(*InboxRuleProto)(nil),                 // 278: subtraterpc.InboxRuleProto
+	(*CreateInboxRuleRequest)(nil),         // 279: subtraterpc.CreateInboxRuleRequest
+	(*ListInboxRulesRequest)(nil),          // 280: subtraterpc.ListInboxRulesRequest
+	(*ListInboxRulesResponse)(nil),         // 281: subtraterpc.ListInboxRulesResponse
+	(*DeleteInboxRuleRequest)(nil),         // 282: subtraterpc.DeleteInboxRuleRequest
+	(*DeleteInboxRuleResponse)(nil),        // 283: subtraterpc.DeleteInboxRuleResponse
+	(*SetInboxRuleEnabledRequest)(nil),     // 284: subtraterpc.SetInboxRuleEnabledRequest
+	(*TestInboxRuleRequest)(nil),           // 285: subtraterpc.TestInboxRuleRequest
+	(*TestInboxRuleResponse)(nil),          // 286: subtraterpc.TestInboxRuleResponse
+	(*PlanAnnotationProto)(nil),            // 287: subtraterpc.PlanAnnotationProto
+	(*DiffAnnotationProto)(nil),            // 288: subtraterpc.DiffAnnotationProto
+	(*CreatePlanAnnotationRequest)(nil),    // 289: subtraterpc.CreatePlanAnnotationRequest
+	(*ListPlanAnnotationsRequest)(nil),     // 290: subtraterpc.ListPlanAnnotationsRequest
+	(*ListPlanAnnotationsResponse)(nil),    // 291: subtraterpc.ListPlanAnnotationsResponse
+	(*UpdatePlanAnnotationRequest)(nil),    // 292: subtraterpc.UpdatePlanAnnotationRequest
+	(*DeletePlanAnnotationRequest)(nil),    // 293: subtraterpc.DeletePlanAnnotationRequest
+	(*DeleteAnnotationResponse)(nil),       // 294: subtraterpc.DeleteAnnotationResponse
+	(*CreateDiffAnnotationRequest)(nil),    // 295: subtraterpc.CreateDiffAnnotationRequest
+	(*ListDiffAnnotationsRequest)(nil),     // 296: subtraterpc.ListDiffAnnotationsRequest
+	(*ListDiffAnnotationsResponse)(nil),    // 297: subtraterpc.ListDiffAnnotationsResponse
+	(*UpdateDiffAnnotationRequest)(nil),    // 298: subtraterpc.UpdateDiffAnnotationRequest
+	(*DeleteDiffAnnotationRequest)(nil),    // 299: subtraterpc.DeleteDiffAnnotationRequest
+	nil,                                    // 300: subtraterpc.SendMailRequest.MetadataEntry
+	nil,                                    // 301: subtraterpc.PollChangesRequest.SinceOffsetsEntry
+	nil,                                    // 302: subtraterpc.PollChangesResponse.NewOffsetsEntry
+	nil,                                    // 303: subtraterpc.PublishRequest.MetadataEntry
+	nil,                                    // 304: subtraterpc.SaveIdentityRequest.ConsumerOffsetsEntry
+	(*timestamppb.Timestamp)(nil),          // 305: google.protobuf.Timestamp
 }
 var file_mail_proto_depIdxs = []int32{
 	0,   // 0: subtraterpc.InboxMessage.priority:type_name -> subtraterpc.Priority
 	1,   // 1: subtraterpc.InboxMessage.state:type_name -> subtraterpc.MessageState
-	176, // 2: subtraterpc.InboxMessage.created_at:type_name -> google.protobuf.Timestamp
-	176, // 3: subtraterpc.InboxMessage.deadline_at:type_name -> google.protobuf.Timestamp
-	176, // 4: subtraterpc.InboxMessage.snoozed_until:type_name -> google.protobuf.Timestamp
-	176, // 5: subtraterpc.InboxMessage.read_at:type_name -> google.protobuf.Timestamp
-	176, // 6: subtraterpc.InboxMessage.acknowledged_at:type_name -> google.protobuf.Timestamp
-	0,   // 7: subtraterpc.SendMailRequest.priority:type_name -> subtraterpc.Priority
-	176, // 8: subtraterpc.SendMailRequest.deadline_at:type_name -> google.protobuf.Timestamp
-	1,   // 9: subtraterpc.FetchInboxRequest.state_filter:type_name -> subtraterpc.MessageState
-	7,   // 10: subtraterpc.FetchInboxResponse.messages:type_name -> subtraterpc.InboxMessage
-	12,  // 11: subtraterpc.FetchInboxResponse.category_counts:type_name -> subtraterpc.InboxCategoryCounts
-	7,   // 12: subtraterpc.ReadMessageResponse.message:type_name -> subtraterpc.InboxMessage
-	7,   // 13: subtraterpc.ReadThreadResponse.messages:type_name -> subtraterpc.InboxMessage
-	1,   // 14: subtraterpc.UpdateStateRequest.new_state:type_name -> subtraterpc.MessageState
-	176, // 15: subtraterpc.UpdateStateRequest.snoozed_until:type_name -> google.protobuf.Timestamp
-	173, // 16: subtraterpc.PollChangesRequest.since_offsets:type_name -> subtraterpc.PollChangesRequest.SinceOffsetsEntry
-	7,   // 17: subtraterpc.PollChangesResponse.new_messages:type_name -> subtraterpc.InboxMessage
-	174, // 18: subtraterpc.PollChangesResponse.new_offsets:type_name -> subtraterpc.PollChangesResponse.NewOffsetsEntry
-	0,   // 19: subtraterpc.PublishRequest.priority:type_name -> subtraterpc.Priority
-	176, // 20: subtraterpc.Topic.created_at:type_name -> google.protobuf.Timestamp
-	32,  // 21: subtraterpc.ListTopicsResponse.topics:type_name -> subtraterpc.Topic
-	7,   // 22: subtraterpc.SearchResponse.results:type_name -> subtraterpc.InboxMessage
-	176, // 23: subtraterpc.GetAgentResponse.created_at:type_name -> google.protobuf.Timestamp
-	176, // 24: subtraterpc.GetAgentResponse.last_active_at:type_name -> google.protobuf.Timestamp
-	42,  // 25: subtraterpc.ListAgentsResponse.agents:type_name -> subtraterpc.GetAgentResponse
-	175, // 26: subtraterpc.SaveIdentityRequest.consumer_offsets:type_name -> subtraterpc.SaveIdentityRequest.ConsumerOffsetsEntry
-	32,  // 27: subtraterpc.GetTopicResponse.topic:type_name -> subtraterpc.Topic
-	62,  // 28: subtraterpc.AutocompleteRecipientsResponse.recipients:type_name -> subtraterpc.AutocompleteRecipient
-	42,  // 29: subtraterpc.UpdateAgentResponse.agent:type_name -> subtraterpc.GetAgentResponse
-	2,   // 30: subtraterpc.AgentWithStatus.status:type_name -> subtraterpc.AgentStatus
-	176, // 31: subtraterpc.AgentWithStatus.last_active_at:type_name -> google.protobuf.Timestamp
-	68,  // 32: subtraterpc.GetAgentsStatusResponse.agents:type_name -> subtraterpc.AgentWithStatus
-	69,  // 33: subtraterpc.GetAgentsStatusResponse.counts:type_name -> subtraterpc.AgentStatusCounts
-	2,   // 34: subtraterpc.DiscoverAgentsRequest.status_filter:type_name -> subtraterpc.AgentStatus
-	2,   // 35: subtraterpc.DiscoveredAgent.status:type_name -> subtraterpc.AgentStatus
-	176, // 36: subtraterpc.DiscoveredAgent.last_active_at:type_name -> google.protobuf.Timestamp
-	73,  // 37: subtraterpc.DiscoverAgentsResponse.agents:type_name -> subtraterpc.DiscoveredAgent
-	69,  // 38: subtraterpc.DiscoverAgentsResponse.counts:type_name -> subtraterpc.AgentStatusCounts
-	176, // 39: subtraterpc.SessionInfo.started_at:type_name -> google.protobuf.Timestamp
-	176, // 40: subtraterpc.SessionInfo.ended_at:type_name -> google.protobuf.Timestamp
-	3,   // 41: subtraterpc.SessionInfo.status:type_name -> subtraterpc.SessionStatus
-	77,  // 42: subtraterpc.ListSessionsResponse.sessions:type_name -> subtraterpc.SessionInfo
-	77,  // 43: subtraterpc.GetSessionResponse.session:type_name -> subtraterpc.SessionInfo
-	77,  // 44: subtraterpc.StartSessionResponse.session:type_name -> subtraterpc.SessionInfo
-	4,   // 45: subtraterpc.ActivityInfo.type:type_name -> subtraterpc.ActivityType
-	176, // 46: subtraterpc.ActivityInfo.created_at:type_name -> google.protobuf.Timestamp
-	4,   // 47: subtraterpc.ListActivitiesRequest.type:type_name -> subtraterpc.ActivityType
-	86,  // 48: subtraterpc.ListActivitiesResponse.activities:type_name -> subtraterpc.ActivityInfo
-	89,  // 49: subtraterpc.GetDashboardStatsResponse.stats:type_name -> subtraterpc.DashboardStats
-	176, // 50: subtraterpc.HealthCheckResponse.time:type_name -> google.protobuf.Timestamp
-	94,  // 51: subtraterpc.CreateReviewRequest.branch_target:type_name -> subtraterpc.BranchTarget
-	95,  // 52: subtraterpc.CreateReviewRequest.commit_target:type_name -> subtraterpc.CommitTarget
-	96,  // 53: subtraterpc.CreateReviewRequest.commit_range_target:type_name -> subtraterpc.CommitRangeTarget
-	97,  // 54: subtraterpc.CreateReviewRequest.pr_target:type_name -> subtraterpc.PRTarget
-	102, // 55: subtraterpc.ListReviewsProtoResponse.reviews:type_name -> subtraterpc.ReviewSummaryProto
-	105, // 56: subtraterpc.ReviewDetailResponse.iteration_details:type_name -> subtraterpc.ReviewIterationProto
-	113, // 57: subtraterpc.ListReviewIssuesResponse.issues:type_name -> subtraterpc.ReviewIssueProto
-	176, // 58: subtraterpc.TaskListProto.created_at:type_name -> google.protobuf.Timestamp
-	176, // 59: subtraterpc.TaskListProto.last_synced_at:type_name -> google.protobuf.Timestamp
-	5,   // 60: subtraterpc.TaskProto.status:type_name -> subtraterpc.TaskStatus
-	176, // 61: subtraterpc.TaskProto.created_at:type_name -> google.protobuf.Timestamp
-	176, // 62: subtraterpc.TaskProto.updated_at:type_name -> google.protobuf.Timestamp
-	176, // 63: subtraterpc.TaskProto.started_at:type_name -> google.protobuf.Timestamp
-	176, // 64: subtraterpc.TaskProto.completed_at:type_name -> google.protobuf.Timestamp
-	118, // 65: subtraterpc.RegisterTaskListResponse.task_list:type_name -> subtraterpc.TaskListProto
-	118, // 66: subtraterpc.GetTaskListResponse.task_list:type_name -> subtraterpc.TaskListProto
-	118, // 67: subtraterpc.ListTaskListsResponse.task_lists:type_name -> subtraterpc.TaskListProto
-	5,   // 68: subtraterpc.UpsertTaskRequest.status:type_name -> subtraterpc.TaskStatus
-	119, // 69: subtraterpc.UpsertTaskResponse.task:type_name -> subtraterpc.TaskProto
-	119, // 70: subtraterpc.GetTaskResponse.task:type_name -> subtraterpc.TaskProto
-	5,   // 71: subtraterpc.ListTasksRequest.status:type_name -> subtraterpc.TaskStatus
-	119, // 72: subtraterpc.ListTasksResponse.tasks:type_name -> subtraterpc.TaskProto
-	5,   // 73: subtraterpc.UpdateTaskStatusRequest.status:type_name -> subtraterpc.TaskStatus
-	176, // 74: subtraterpc.GetTaskStatsRequest.today_since:type_name -> google.protobuf.Timestamp
-	120, // 75: subtraterpc.GetTaskStatsResponse.stats:type_name -> subtraterpc.TaskStatsProto
-	176, // 76: subtraterpc.GetAllAgentTaskStatsRequest.today_since:type_name -> google.protobuf.Timestamp
-	121, // 77: subtraterpc.GetAllAgentTaskStatsResponse.stats:type_name -> subtraterpc.AgentTaskStatsProto
-	176, // 78: subtraterpc.PruneOldTasksRequest.older_than:type_name -> google.protobuf.Timestamp
-	150, // 79: subtraterpc.ListPlanReviewsResponse.plan_reviews:type_name -> subtraterpc.PlanReviewProto
-	160, // 80: subtraterpc.ListPlanAnnotationsResponse.annotations:type_name -> subtraterpc.PlanAnnotationProto
-	161, // 81: subtraterpc.ListDiffAnnotationsResponse.annotations:type_name -> subtraterpc.DiffAnnotationProto
-	8,   // 82: subtraterpc.Mail.SendMail:input_type -> subtraterpc.SendMailRequest
-	10,  // 83: subtraterpc.Mail.FetchInbox:input_type -> subtraterpc.FetchInboxRequest
-	13,  // 84: subtraterpc.Mail.ReadMessage:input_type -> subtraterpc.ReadMessageRequest
-	15,  // 85: subtraterpc.Mail.ReadThread:input_type -> subtraterpc.ReadThreadRequest
-	17,  // 86: subtraterpc.Mail.UpdateState:input_type -> subtraterpc.UpdateStateRequest
-	19,  // 87: subtraterpc.Mail.AckMessage:input_type -> subtraterpc.AckMessageRequest
-	21,  // 88: subtraterpc.Mail.GetStatus:input_type -> subtraterpc.GetStatusRequest
-	23,  // 89: subtraterpc.Mail.PollChanges:input_type -> subtraterpc.PollChangesRequest
-	25,  // 90: subtraterpc.Mail.SubscribeInbox:input_type -> subtraterpc.SubscribeInboxRequest
-	26,  // 91: subtraterpc.Mail.Publish:input_type -> subtraterpc.PublishRequest
-	28,  // 92: subtraterpc.Mail.Subscribe:input_type -> subtraterpc.SubscribeRequest
-	30,  // 93: subtraterpc.Mail.Unsubscribe:input_type -> subtraterpc.UnsubscribeRequest
-	33,  // 94: subtraterpc.Mail.ListTopics:input_type -> subtraterpc.ListTopicsRequest
-	35,  // 95: subtraterpc.Mail.Search:input_type -> subtraterpc.SearchRequest
-	37,  // 96: subtraterpc.Mail.HasUnackedStatusTo:input_type -> subtraterpc.HasUnackedStatusToRequest
-	51,  // 97: subtraterpc.Mail.ReplyToThread:input_type -> subtraterpc.ReplyToThreadRequest
-	53,  // 98: subtraterpc.Mail.ArchiveThread:input_type -> subtraterpc.ArchiveThreadRequest
-	55,  // 99: subtraterpc.Mail.DeleteThread:input_type -> subtraterpc.DeleteThreadRequest
-	57,  // 100: subtraterpc.Mail.MarkThreadUnread:input_type -> subtraterpc.MarkThreadUnreadRequest
-	59,  // 101: subtraterpc.Mail.GetTopic:input_type -> subtraterpc.GetTopicRequest
-	61,  // 102: subtraterpc.Mail.AutocompleteRecipients:input_type -> subtraterpc.AutocompleteRecipientsRequest
-	64,  // 103: subtraterpc.Mail.DeleteMessage:input_type -> subtraterpc.DeleteMessageRequest
-	39,  // 104: subtraterpc.Agent.RegisterAgent:input_type -> subtraterpc.RegisterAgentRequest
-	41,  // 105: subtraterpc.Agent.GetAgent:input_type -> subtraterpc.GetAgentRequest
-	43,  // 106: subtraterpc.Agent.ListAgents:input_type -> subtraterpc.ListAgentsRequest
-	49,  // 107: subtraterpc.Agent.DeleteAgent:input_type -> subtraterpc.DeleteAgentRequest
-	66,  // 108: subtraterpc.Agent.UpdateAgent:input_type -> subtraterpc.UpdateAgentRequest
-	70,  // 109: subtraterpc.Agent.GetAgentsStatus:input_type -> subtraterpc.GetAgentsStatusRequest
-	75,  // 110: subtraterpc.Agent.Heartbeat:input_type -> subtraterpc.HeartbeatRequest
-	45,  // 111: subtraterpc.Agent.EnsureIdentity:input_type -> subtraterpc.EnsureIdentityRequest
-	47,  // 112: subtraterpc.Agent.SaveIdentity:input_type -> subtraterpc.SaveIdentityRequest
-	72,  // 113: subtraterpc.Agent.DiscoverAgents:input_type -> subtraterpc.DiscoverAgentsRequest
-	78,  // 114: subtraterpc.Session.ListSessions:input_type -> subtraterpc.ListSessionsRequest
-	80,  // 115: subtraterpc.Session.GetSession:input_type -> subtraterpc.GetSessionRequest
-	82,  // 116: subtraterpc.Session.StartSession:input_type -> subtraterpc.StartSessionRequest
-	84,  // 117: subtraterpc.Session.CompleteSession:input_type -> subtraterpc.CompleteSessionRequest
-	87,  // 118: subtraterpc.Activity.ListActivities:input_type -> subtraterpc.ListActivitiesRequest
-	90,  // 119: subtraterpc.Stats.GetDashboardStats:input_type -> subtraterpc.GetDashboardStatsRequest
-	92,  // 120: subtraterpc.Stats.HealthCheck:input_type -> subtraterpc.HealthCheckRequest
-	122, // 121: subtraterpc.TaskService.RegisterTaskList:input_type -> subtraterpc.RegisterTaskListRequest
-	124, // 122: subtraterpc.TaskService.GetTaskList:input_type -> subtraterpc.GetTaskListRequest
-	126, // 123: subtraterpc.TaskService.ListTaskLists:input_type -> subtraterpc.ListTaskListsRequest
-	128, // 124: subtraterpc.TaskService.UnregisterTaskList:input_type -> subtraterpc.UnregisterTaskListRequest
-	130, // 125: subtraterpc.TaskService.UpsertTask:input_type -> subtraterpc.UpsertTaskRequest
-	132, // 126: subtraterpc.TaskService.GetTask:input_type -> subtraterpc.GetTaskProtoRequest
-	134, // 127: subtraterpc.TaskService.ListTasks:input_type -> subtraterpc.ListTasksRequest
-	136, // 128: subtraterpc.TaskService.UpdateTaskStatus:input_type -> subtraterpc.UpdateTaskStatusRequest
-	138, // 129: subtraterpc.TaskService.UpdateTaskOwner:input_type -> subtraterpc.UpdateTaskOwnerRequest
-	140, // 130: subtraterpc.TaskService.DeleteTask:input_type -> subtraterpc.DeleteTaskRequest
-	142, // 131: subtraterpc.TaskService.GetTaskStats:input_type -> subtraterpc.GetTaskStatsRequest
-	144, // 132: subtraterpc.TaskService.GetAllAgentTaskStats:input_type -> subtraterpc.GetAllAgentTaskStatsRequest
-	146, // 133: subtraterpc.TaskService.SyncTaskList:input_type -> subtraterpc.SyncTaskListRequest
-	148, // 134: subtraterpc.TaskService.PruneOldTasks:input_type -> subtraterpc.PruneOldTasksRequest
-	98,  // 135: subtraterpc.ReviewService.CreateReview:input_type -> subtraterpc.CreateReviewRequest
-	100, // 136: subtraterpc.ReviewService.ListReviews:input_type -> subtraterpc.ListReviewsProtoRequest
-	103, // 137: subtraterpc.ReviewService.GetReview:input_type -> subtraterpc.GetReviewProtoRequest
-	106, // 138: subtraterpc.ReviewService.ResubmitReview:input_type -> subtraterpc.ResubmitReviewRequest
-	107, // 139: subtraterpc.ReviewService.CancelReview:input_type -> subtraterpc.CancelReviewProtoRequest
-	109, // 140: subtraterpc.ReviewService.DeleteReview:input_type -> subtraterpc.DeleteReviewProtoRequest
-	111, // 141: subtraterpc.ReviewService.ListReviewIssues:input_type -> subtraterpc.ListReviewIssuesRequest
-	114, // 142: subtraterpc.ReviewService.UpdateIssueStatus:input_type -> subtraterpc.UpdateIssueStatusRequest
-	116, // 143: subtraterpc.ReviewService.GetReviewDiff:input_type -> subtraterpc.GetReviewDiffRequest
-	151, // 144: subtraterpc.PlanReviewService.CreatePlanReview:input_type -> subtraterpc.CreatePlanReviewRequest
-	152, // 145: subtraterpc.PlanReviewService.GetPlanReview:input_type -> subtraterpc.GetPlanReviewRequest
-	153, // 146: subtraterpc.PlanReviewService.GetPlanReviewByThread:input_type -> subtraterpc.GetPlanReviewByThreadRequest
-	154, // 147: subtraterpc.PlanReviewService.GetPlanReviewBySession:input_type -> subtraterpc.GetPlanReviewBySessionRequest
-	155, // 148: subtraterpc.PlanReviewService.ListPlanReviews:input_type -> subtraterpc.ListPlanReviewsRequest
-	157, // 149: subtraterpc.PlanReviewService.UpdatePlanReviewStatus:input_type -> subtraterpc.UpdatePlanReviewStatusRequest
-	158, // 150: subtraterpc.PlanReviewService.DeletePlanReview:input_type -> subtraterpc.DeletePlanReviewRequest
-	162, // 151: subtraterpc.AnnotationService.CreatePlanAnnotation:input_type -> subtraterpc.CreatePlanAnnotationRequest
-	163, // 152: subtraterpc.AnnotationService.ListPlanAnnotations:input_type -> subtraterpc.ListPlanAnnotationsRequest
-	165, // 153: subtraterpc.AnnotationService.UpdatePlanAnnotation:input_type -> subtraterpc.UpdatePlanAnnotationRequest
-	166, // 154: subtraterpc.AnnotationService.DeletePlanAnnotation:input_type -> subtraterpc.DeletePlanAnnotationRequest
-	168, // 155: subtraterpc.AnnotationService.CreateDiffAnnotation:input_type -> subtraterpc.CreateDiffAnnotationRequest
-	169, // 156: subtraterpc.AnnotationService.ListDiffAnnotations:input_type -> subtraterpc.ListDiffAnnotationsRequest
-	171, // 157: subtraterpc.AnnotationService.UpdateDiffAnnotation:input_type -> subtraterpc.UpdateDiffAnnotationRequest
-	172, // 158: subtraterpc.AnnotationService.DeleteDiffAnnotation:input_type -> subtraterpc.DeleteDiffAnnotationRequest
-	9,   // 159: subtraterpc.Mail.SendMail:output_type -> subtraterpc.SendMailResponse
-	11,  // 160: subtraterpc.Mail.FetchInbox:output_type -> subtraterpc.FetchInboxResponse
-	14,  // 161: subtraterpc.Mail.ReadMessage:output_type -> subtraterpc.ReadMessageResponse
-	16,  // 162: subtraterpc.Mail.ReadThread:output_type -> subtraterpc.ReadThreadResponse
-	18,  // 163: subtraterpc.Mail.UpdateState:output_type -> subtraterpc.UpdateStateResponse
-	20,  // 164: subtraterpc.Mail.AckMessage:output_type -> subtraterpc.AckMessageResponse
-	22,  // 165: subtraterpc.Mail.GetStatus:output_type -> subtraterpc.GetStatusResponse
-	24,  // 166: subtraterpc.Mail.PollChanges:output_type -> subtraterpc.PollChangesResponse
-	7,   // 167: subtraterpc.Mail.SubscribeInbox:output_type -> subtraterpc.InboxMessage
-	27,  // 168: subtraterpc.Mail.Publish:output_type -> subtraterpc.PublishResponse
-	29,  // 169: subtraterpc.Mail.Subscribe:output_type -> subtraterpc.SubscribeResponse
-	31,  // 170: subtraterpc.Mail.Unsubscribe:output_type -> subtraterpc.UnsubscribeResponse
-	34,  // 171: subtraterpc.Mail.ListTopics:output_type -> subtraterpc.ListTopicsResponse
-	36,  // 172: subtraterpc.Mail.Search:output_type -> subtraterpc.SearchResponse
-	38,  // 173: subtraterpc.Mail.HasUnackedStatusTo:output_type -> subtraterpc.HasUnackedStatusToResponse
-	52,  // 174: subtraterpc.Mail.ReplyToThread:output_type -> subtraterpc.ReplyToThreadResponse
-	54,  // 175: subtraterpc.Mail.ArchiveThread:output_type -> subtraterpc.ArchiveThreadResponse
-	56,  // 176: subtraterpc.Mail.DeleteThread:output_type -> subtraterpc.DeleteThreadResponse
-	58,  // 177: subtraterpc.Mail.MarkThreadUnread:output_type -> subtraterpc.MarkThreadUnreadResponse
-	60,  // 178: subtraterpc.Mail.GetTopic:output_type -> subtraterpc.GetTopicResponse
-	63,  // 179: subtraterpc.Mail.AutocompleteRecipients:output_type -> subtraterpc.AutocompleteRecipientsResponse
-	65,  // 180: subtraterpc.Mail.DeleteMessage:output_type -> subtraterpc.DeleteMessageResponse
-	40,  // 181: subtraterpc.Agent.RegisterAgent:output_type -> subtraterpc.RegisterAgentResponse
-	42,  // 182: subtraterpc.Agent.GetAgent:output_type -> subtraterpc.GetAgentResponse
-	44,  // 183: subtraterpc.Agent.ListAgents:output_type -> subtraterpc.ListAgentsResponse
-	50,  // 184: subtraterpc.Agent.DeleteAgent:output_type -> subtraterpc.DeleteAgentResponse
-	67,  // 185: subtraterpc.Agent.UpdateAgent:output_type -> subtraterpc.UpdateAgentResponse
-	71,  // 186: subtraterpc.Agent.GetAgentsStatus:output_type -> subtraterpc.GetAgentsStatusResponse
-	76,  // 187: subtraterpc.Agent.Heartbeat:output_type -> subtraterpc.HeartbeatResponse
-	46,  // 188: subtraterpc.Agent.EnsureIdentity:output_type -> subtraterpc.EnsureIdentityResponse
-	48,  // 189: subtraterpc.Agent.SaveIdentity:output_type -> subtraterpc.SaveIdentityResponse
-	74,  // 190: subtraterpc.Agent.DiscoverAgents:output_type -> subtraterpc.DiscoverAgentsResponse
-	79,  // 191: subtraterpc.Session.ListSessions:output_type -> subtraterpc.ListSessionsResponse
-	81,  // 192: subtraterpc.Session.GetSession:output_type -> subtraterpc.GetSessionResponse
-	83,  // 193: subtraterpc.Session.StartSession:output_type -> subtraterpc.StartSessionResponse
-	85,  // 194: subtraterpc.Session.CompleteSession:output_type -> subtraterpc.CompleteSessionResponse
-	88,  // 195: subtraterpc.Activity.ListActivities:output_type -> subtraterpc.ListActivitiesResponse
-	91,  // 196: subtraterpc.Stats.GetDashboardStats:output_type -> subtraterpc.GetDashboardStatsResponse
-	93,  // 197: subtraterpc.Stats.HealthCheck:output_type -> subtraterpc.HealthCheckResponse
-	123, // 198: subtraterpc.TaskService.RegisterTaskList:output_type -> subtraterpc.RegisterTaskListResponse
-	125, // 199: subtraterpc.TaskService.GetTaskList:output_type -> subtraterpc.GetTaskListResponse
-	127, // 200: subtraterpc.TaskService.ListTaskLists:output_type -> subtraterpc.ListTaskListsResponse
-	129, // 201: subtraterpc.TaskService.UnregisterTaskList:output_type -> subtraterpc.UnregisterTaskListResponse
-	131, // 202: subtraterpc.TaskService.UpsertTask:output_type -> subtraterpc.UpsertTaskResponse
-	133, // 203: subtraterpc.TaskService.GetTask:output_type -> subtraterpc.GetTaskResponse
-	135, // 204: subtraterpc.TaskService.ListTasks:output_type -> subtraterpc.ListTasksResponse
-	137, // 205: subtraterpc.TaskService.UpdateTaskStatus:output_type -> subtraterpc.UpdateTaskStatusResponse
-	139, // 206: subtraterpc.TaskService.UpdateTaskOwner:output_type -> subtraterpc.UpdateTaskOwnerResponse
-	141, // 207: subtraterpc.TaskService.DeleteTask:output_type -> subtraterpc.DeleteTaskResponse
-	143, // 208: subtraterpc.TaskService.GetTaskStats:output_type -> subtraterpc.GetTaskStatsResponse
-	145, // 209: subtraterpc.TaskService.GetAllAgentTaskStats:output_type -> subtraterpc.GetAllAgentTaskStatsResponse
-	147, // 210: subtraterpc.TaskService.SyncTaskList:output_type -> subtraterpc.SyncTaskListResponse
-	149, // 211: subtraterpc.TaskService.PruneOldTasks:output_type -> subtraterpc.PruneOldTasksResponse
-	99,  // 212: subtraterpc.ReviewService.CreateReview:output_type -> subtraterpc.CreateReviewResponse
-	101, // 213: subtraterpc.ReviewService.ListReviews:output_type -> subtraterpc.ListReviewsProtoResponse
-	104, // 214: subtraterpc.ReviewService.GetReview:output_type -> subtraterpc.ReviewDetailResponse
-	99,  // 215: subtraterpc.ReviewService.ResubmitReview:output_type -> subtraterpc.CreateReviewResponse
-	108, // 216: subtraterpc.ReviewService.CancelReview:output_type -> subtraterpc.CancelReviewProtoResponse
-	110, // 217: subtraterpc.ReviewService.DeleteReview:output_type -> subtraterpc.DeleteReviewProtoResponse
-	112, // 218: subtraterpc.ReviewService.ListReviewIssues:output_type -> subtraterpc.ListReviewIssuesResponse
-	115, // 219: subtraterpc.ReviewService.UpdateIssueStatus:output_type -> subtraterpc.UpdateIssueStatusResponse
-	117, // 220: subtraterpc.ReviewService.GetReviewDiff:output_type -> subtraterpc.GetReviewDiffResponse
-	150, // 221: subtraterpc.PlanReviewService.CreatePlanReview:output_type -> subtraterpc.PlanReviewProto
-	150, // 222: subtraterpc.PlanReviewService.GetPlanReview:output_type -> subtraterpc.PlanReviewProto
-	150, // 223: subtraterpc.PlanReviewService.GetPlanReviewByThread:output_type -> subtraterpc.PlanReviewProto
-	150, // 224: subtraterpc.PlanReviewService.GetPlanReviewBySession:output_type -> subtraterpc.PlanReviewProto
-	156, // 225: subtraterpc.PlanReviewService.ListPlanReviews:output_type -> subtraterpc.ListPlanReviewsResponse
-	150, // 226: subtraterpc.PlanReviewService.UpdatePlanReviewStatus:output_type -> subtraterpc.PlanReviewProto
-	159, // 227: subtraterpc.PlanReviewService.DeletePlanReview:output_type -> subtraterpc.DeletePlanReviewResponse
-	160, // 228: subtraterpc.AnnotationService.CreatePlanAnnotation:output_type -> subtraterpc.PlanAnnotationProto
-	164, // 229: subtraterpc.AnnotationService.ListPlanAnnotations:output_type -> subtraterpc.ListPlanAnnotationsResponse
-	160, // 230: subtraterpc.AnnotationService.UpdatePlanAnnotation:output_type -> subtraterpc.PlanAnnotationProto
-	167, // 231: subtraterpc.AnnotationService.DeletePlanAnnotation:output_type -> subtraterpc.DeleteAnnotationResponse
-	161, // 232: subtraterpc.AnnotationService.CreateDiffAnnotation:output_type -> subtraterpc.DiffAnnotationProto
-	170, // 233: subtraterpc.AnnotationService.ListDiffAnnotations:output_type -> subtraterpc.ListDiffAnnotationsResponse
-	161, // 234: subtraterpc.AnnotationService.UpdateDiffAnnotation:output_type -> subtraterpc.DiffAnnotationProto
-	167, // 235: subtraterpc.AnnotationService.DeleteDiffAnnotation:output_type -> subtraterpc.DeleteAnnotationResponse
-	159, // [159:236] is the sub-list for method output_type
-	82,  // [82:159] is the sub-list for method input_type
-	82,  // [82:82] is the sub-list for extension type_name
-	82,  // [82:82] is the sub-list for extension extendee
-	0,   // [0:82] is the sub-list for field type_name
+	305, // 2: subtraterpc.InboxMessage.created_at:type_name -> google.protobuf.Timestamp
+	305, // 3: subtraterpc.InboxMessage.deadline_at:type_name -> google.protobuf.Timestamp
+	305, // 4: subtraterpc.InboxMessage.snoozed_until:type_name -> google.protobuf.Timestamp
+	305, // 5: subtraterpc.InboxMessage.read_at:type_name -> google.protobuf.Timestamp
+	305, // 6: subtraterpc.InboxMessage.acknowledged_at:type_name -> google.protobuf.Timestamp
+	305, // 7: subtraterpc.InboxMessage.edited_at:type_name -> google.protobuf.Timestamp
+	0,   // 8: subtraterpc.SendMailRequest.priority:type_name -> subtraterpc.Priority
+	305, // 9: subtraterpc.SendMailRequest.deadline_at:type_name -> google.protobuf.Timestamp
+	2,   // 10: subtraterpc.SendMailRequest.delivery_mode:type_name -> subtraterpc.DeliveryMode
+	305, // 11: subtraterpc.SendMailRequest.send_at:type_name -> google.protobuf.Timestamp
+	300, // 12: subtraterpc.SendMailRequest.metadata:type_name -> subtraterpc.SendMailRequest.MetadataEntry
+	15,  // 13: subtraterpc.SendMailResponse.expansions:type_name -> subtraterpc.RecipientExpansion
+	14,  // 14: subtraterpc.SendMailResponse.deliveries:type_name -> subtraterpc.RecipientDelivery
+	3,   // 15: subtraterpc.RecipientDelivery.status:type_name -> subtraterpc.RecipientStatus
+	1,   // 16: subtraterpc.FetchInboxRequest.state_filter:type_name -> subtraterpc.MessageState
+	11,  // 17: subtraterpc.FetchInboxResponse.messages:type_name -> subtraterpc.InboxMessage
+	18,  // 18: subtraterpc.FetchInboxResponse.category_counts:type_name -> subtraterpc.InboxCategoryCounts
+	145, // 19: subtraterpc.InboxCategoryCounts.labels:type_name -> subtraterpc.LabelProto
+	11,  // 20: subtraterpc.ReadMessageResponse.message:type_name -> subtraterpc.InboxMessage
+	15,  // 21: subtraterpc.ReadMessageResponse.expansions:type_name -> subtraterpc.RecipientExpansion
+	138, // 22: subtraterpc.ReadMessageResponse.revisions:type_name -> subtraterpc.MessageRevision
+	11,  // 23: subtraterpc.ReadThreadResponse.messages:type_name -> subtraterpc.InboxMessage
+	138, // 24: subtraterpc.ReadThreadResponse.revisions:type_name -> subtraterpc.MessageRevision
+	1,   // 25: subtraterpc.UpdateStateRequest.new_state:type_name -> subtraterpc.MessageState
+	305, // 26: subtraterpc.UpdateStateRequest.snoozed_until:type_name -> google.protobuf.Timestamp
+	301, // 27: subtraterpc.PollChangesRequest.since_offsets:type_name -> subtraterpc.PollChangesRequest.SinceOffsetsEntry
+	11,  // 28: subtraterpc.PollChangesResponse.new_messages:type_name -> subtraterpc.InboxMessage
+	302, // 29: subtraterpc.PollChangesResponse.new_offsets:type_name -> subtraterpc.PollChangesResponse.NewOffsetsEntry
+	0,   // 30: subtraterpc.WatchInboxRequest.priorities:type_name -> subtraterpc.Priority
+	4,   // 31: subtraterpc.WatchInboxEvent.type:type_name -> subtraterpc.WatchEventType
+	11,  // 32: subtraterpc.WatchInboxEvent.messages:type_name -> subtraterpc.InboxMessage
+	305, // 33: subtraterpc.WatchInboxEvent.lease_expires_at:type_name -> google.protobuf.Timestamp
+	305, // 34: subtraterpc.GetWatcherResponse.lease_expires_at:type_name -> google.protobuf.Timestamp
+	0,   // 35: subtraterpc.PublishRequest.priority:type_name -> subtraterpc.Priority
+	303, // 36: subtraterpc.PublishRequest.metadata:type_name -> subtraterpc.PublishRequest.MetadataEntry
+	11,  // 37: subtraterpc.ClaimQueueMessageResponse.message:type_name -> subtraterpc.InboxMessage
+	305, // 38: subtraterpc.ClaimQueueMessageResponse.lease_expires_at:type_name -> google.protobuf.Timestamp
+	305, // 39: subtraterpc.ExtendQueueLeaseResponse.lease_expires_at:type_name -> google.protobuf.Timestamp
+	305, // 40: subtraterpc.Topic.created_at:type_name -> google.protobuf.Timestamp
+	50,  // 41: subtraterpc.ListTopicsResponse.topics:type_name -> subtraterpc.Topic
+	11,  // 42: subtraterpc.SearchResponse.results:type_name -> subtraterpc.InboxMessage
+	54,  // 43: subtraterpc.SearchResponse.hits:type_name -> subtraterpc.SearchHit
+	5,   // 44: subtraterpc.SearchAllRequest.kinds:type_name -> subtraterpc.SearchKind
+	5,   // 45: subtraterpc.SearchAllHit.kind:type_name -> subtraterpc.SearchKind
+	305, // 46: subtraterpc.SearchAllHit.created_at:type_name -> google.protobuf.Timestamp
+	57,  // 47: subtraterpc.SearchAllResponse.hits:type_name -> subtraterpc.SearchAllHit
+	305, // 48: subtraterpc.SavedSearchProto.created_at:type_name -> google.protobuf.Timestamp
+	305, // 49: subtraterpc.SavedSearchProto.updated_at:type_name -> google.protobuf.Timestamp
+	59,  // 50: subtraterpc.ListSavedSearchesResponse.searches:type_name -> subtraterpc.SavedSearchProto
+	11,  // 51: subtraterpc.SemanticSearchResponse.results:type_name -> subtraterpc.InboxMessage
+	67,  // 52: subtraterpc.SemanticSearchResponse.hits:type_name -> subtraterpc.SemanticHit
+	73,  // 53: subtraterpc.RegisterAgentRequest.capabilities:type_name -> subtraterpc.AgentCapabilities
+	73,  // 54: subtraterpc.RegisterAgentResponse.capabilities:type_name -> subtraterpc.AgentCapabilities
+	305, // 55: subtraterpc.GetAgentResponse.created_at:type_name -> google.protobuf.Timestamp
+	305, // 56: subtraterpc.GetAgentResponse.last_active_at:type_name -> google.protobuf.Timestamp
+	73,  // 57: subtraterpc.GetAgentResponse.capabilities:type_name -> subtraterpc.AgentCapabilities
+	75,  // 58: subtraterpc.ListAgentsResponse.agents:type_name -> subtraterpc.GetAgentResponse
+	304, // 59: subtraterpc.SaveIdentityRequest.consumer_offsets:type_name -> subtraterpc.SaveIdentityRequest.ConsumerOffsetsEntry
+	0,   // 60: subtraterpc.ReplyToThreadRequest.priority:type_name -> subtraterpc.Priority
+	50,  // 61: subtraterpc.GetTopicResponse.topic:type_name -> subtraterpc.Topic
+	95,  // 62: subtraterpc.AutocompleteRecipientsResponse.recipients:type_name -> subtraterpc.AutocompleteRecipient
+	100, // 63: subtraterpc.CollectGarbageResponse.topics:type_name -> subtraterpc.TopicRetentionReport
+	50,  // 64: subtraterpc.SetTopicRetentionResponse.topic:type_name -> subtraterpc.Topic
+	305, // 65: subtraterpc.RecipientGroup.created_at:type_name -> google.protobuf.Timestamp
+	106, // 66: subtraterpc.RecipientGroupResponse.group:type_name -> subtraterpc.RecipientGroup
+	106, // 67: subtraterpc.ListRecipientGroupsResponse.groups:type_name -> subtraterpc.RecipientGroup
+	3,   // 68: subtraterpc.DeadLetter.status:type_name -> subtraterpc.RecipientStatus
+	305, // 69: subtraterpc.DeadLetter.created_at:type_name -> google.protobuf.Timestamp
+	115, // 70: subtraterpc.ListDeadLettersResponse.dead_letters:type_name -> subtraterpc.DeadLetter
+	0,   // 71: subtraterpc.ScheduledMessage.priority:type_name -> subtraterpc.Priority
+	305, // 72: subtraterpc.ScheduledMessage.send_at:type_name -> google.protobuf.Timestamp
+	305, // 73: subtraterpc.ScheduledMessage.created_at:type_name -> google.protobuf.Timestamp
+	120, // 74: subtraterpc.ListScheduledMessagesResponse.messages:type_name -> subtraterpc.ScheduledMessage
+	0,   // 75: subtraterpc.Schedule.priority:type_name -> subtraterpc.Priority
+	305, // 76: subtraterpc.Schedule.next_run_at:type_name -> google.protobuf.Timestamp
+	305, // 77: subtraterpc.Schedule.last_run_at:type_name -> google.protobuf.Timestamp
+	305, // 78: subtraterpc.Schedule.created_at:type_name -> google.protobuf.Timestamp
+	0,   // 79: subtraterpc.CreateScheduleRequest.priority:type_name -> subtraterpc.Priority
+	125, // 80: subtraterpc.ScheduleResponse.schedule:type_name -> subtraterpc.Schedule
+	125, // 81: subtraterpc.ListSchedulesResponse.schedules:type_name -> subtraterpc.Schedule
+	305, // 82: subtraterpc.Question.timeout_at:type_name -> google.protobuf.Timestamp
+	305, // 83: subtraterpc.Question.escalated_at:type_name -> google.protobuf.Timestamp
+	305, // 84: subtraterpc.Question.answered_at:type_name -> google.protobuf.Timestamp
+	305, // 85: subtraterpc.Question.created_at:type_name -> google.protobuf.Timestamp
+	0,   // 86: subtraterpc.AskQuestionRequest.priority:type_name -> subtraterpc.Priority
+	133, // 87: subtraterpc.QuestionResponse.question:type_name -> subtraterpc.Question
+	305, // 88: subtraterpc.MessageRevision.created_at:type_name -> google.protobuf.Timestamp
+	305, // 89: subtraterpc.MessageRevision.replaced_at:type_name -> google.protobuf.Timestamp
+	305, // 90: subtraterpc.EditMessageResponse.edited_at:type_name -> google.protobuf.Timestamp
+	138, // 91: subtraterpc.GetMessageHistoryResponse.revisions:type_name -> subtraterpc.MessageRevision
+	305, // 92: subtraterpc.LabelProto.created_at:type_name -> google.protobuf.Timestamp
+	145, // 93: subtraterpc.ListLabelsResponse.labels:type_name -> subtraterpc.LabelProto
+	305, // 94: subtraterpc.ThreadPreferenceProto.updated_at:type_name -> google.protobuf.Timestamp
+	153, // 95: subtraterpc.ListThreadPreferencesResponse.threads:type_name -> subtraterpc.ThreadPreferenceProto
+	73,  // 96: subtraterpc.UpdateAgentRequest.capabilities:type_name -> subtraterpc.AgentCapabilities
+	75,  // 97: subtraterpc.UpdateAgentResponse.agent:type_name -> subtraterpc.GetAgentResponse
+	6,   // 98: subtraterpc.AgentWithStatus.status:type_name -> subtraterpc.AgentStatus
+	305, // 99: subtraterpc.AgentWithStatus.last_active_at:type_name -> google.protobuf.Timestamp
+	164, // 100: subtraterpc.GetAgentsStatusResponse.agents:type_name -> subtraterpc.AgentWithStatus
+	165, // 101: subtraterpc.GetAgentsStatusResponse.counts:type_name -> subtraterpc.AgentStatusCounts
+	6,   // 102: subtraterpc.DiscoverAgentsRequest.status_filter:type_name -> subtraterpc.AgentStatus
+	6,   // 103: subtraterpc.DiscoveredAgent.status:type_name -> subtraterpc.AgentStatus
+	305, // 104: subtraterpc.DiscoveredAgent.last_active_at:type_name -> google.protobuf.Timestamp
+	73,  // 105: subtraterpc.DiscoveredAgent.capabilities:type_name -> subtraterpc.AgentCapabilities
+	169, // 106: subtraterpc.DiscoverAgentsResponse.agents:type_name -> subtraterpc.DiscoveredAgent
+	165, // 107: subtraterpc.DiscoverAgentsResponse.counts:type_name -> subtraterpc.AgentStatusCounts
+	305, // 108: subtraterpc.CapabilityRoute.created_at:type_name -> google.protobuf.Timestamp
+	172, // 109: subtraterpc.ListCapabilityRoutesResponse.routes:type_name -> subtraterpc.CapabilityRoute
+	305, // 110: subtraterpc.Credential.expires_at:type_name -> google.protobuf.Timestamp
+	305, // 111: subtraterpc.Credential.created_at:type_name -> google.protobuf.Timestamp
+	305, // 112: subtraterpc.Credential.last_used_at:type_name -> google.protobuf.Timestamp
+	305, // 113: subtraterpc.Credential.revoked_at:type_name -> google.protobuf.Timestamp
+	174, // 114: subtraterpc.MintCredentialResponse.credential:type_name -> subtraterpc.Credential
+	174, // 115: subtraterpc.ListCredentialsResponse.credentials:type_name -> subtraterpc.Credential
+	305, // 116: subtraterpc.AgentLimit.updated_at:type_name -> google.protobuf.Timestamp
+	181, // 117: subtraterpc.SetLimitResponse.limit:type_name -> subtraterpc.AgentLimit
+	181, // 118: subtraterpc.ListLimitsResponse.limits:type_name -> subtraterpc.AgentLimit
+	181, // 119: subtraterpc.LimitUsage.limit:type_name -> subtraterpc.AgentLimit
+	305, // 120: subtraterpc.LimitUsage.last_throttled_at:type_name -> google.protobuf.Timestamp
+	188, // 121: subtraterpc.GetUsageResponse.usage:type_name -> subtraterpc.LimitUsage
+	305, // 122: subtraterpc.SessionInfo.started_at:type_name -> google.protobuf.Timestamp
+	305, // 123: subtraterpc.SessionInfo.ended_at:type_name -> google.protobuf.Timestamp
+	7,   // 124: subtraterpc.SessionInfo.status:type_name -> subtraterpc.SessionStatus
+	193, // 125: subtraterpc.ListSessionsResponse.sessions:type_name -> subtraterpc.SessionInfo
+	193, // 126: subtraterpc.GetSessionResponse.session:type_name -> subtraterpc.SessionInfo
+	193, // 127: subtraterpc.StartSessionResponse.session:type_name -> subtraterpc.SessionInfo
+	8,   // 128: subtraterpc.ActivityInfo.type:type_name -> subtraterpc.ActivityType
+	305, // 129: subtraterpc.ActivityInfo.created_at:type_name -> google.protobuf.Timestamp
+	8,   // 130: subtraterpc.ListActivitiesRequest.type:type_name -> subtraterpc.ActivityType
+	202, // 131: subtraterpc.ListActivitiesResponse.activities:type_name -> subtraterpc.ActivityInfo
+	205, // 132: subtraterpc.GetDashboardStatsResponse.stats:type_name -> subtraterpc.DashboardStats
+	305, // 133: subtraterpc.HealthCheckResponse.time:type_name -> google.protobuf.Timestamp
+	210, // 134: subtraterpc.CreateReviewRequest.branch_target:type_name -> subtraterpc.BranchTarget
+	211, // 135: subtraterpc.CreateReviewRequest.commit_target:type_name -> subtraterpc.CommitTarget
+	212, // 136: subtraterpc.CreateReviewRequest.commit_range_target:type_name -> subtraterpc.CommitRangeTarget
+	213, // 137: subtraterpc.CreateReviewRequest.pr_target:type_name -> subtraterpc.PRTarget
+	218, // 138: subtraterpc.ListReviewsProtoResponse.reviews:type_name -> subtraterpc.ReviewSummaryProto
+	221, // 139: subtraterpc.ReviewDetailResponse.iteration_details:type_name -> subtraterpc.ReviewIterationProto
+	229, // 140: subtraterpc.ListReviewIssuesResponse.issues:type_name -> subtraterpc.ReviewIssueProto
+	305, // 141: subtraterpc.TaskListProto.created_at:type_name -> google.protobuf.Timestamp
+	305, // 142: subtraterpc.TaskListProto.last_synced_at:type_name -> google.protobuf.Timestamp
+	9,   // 143: subtraterpc.TaskProto.status:type_name -> subtraterpc.TaskStatus
+	305, // 144: subtraterpc.TaskProto.created_at:type_name -> google.protobuf.Timestamp
+	305, // 145: subtraterpc.TaskProto.updated_at:type_name -> google.protobuf.Timestamp
+	305, // 146: subtraterpc.TaskProto.started_at:type_name -> google.protobuf.Timestamp
+	305, // 147: subtraterpc.TaskProto.completed_at:type_name -> google.protobuf.Timestamp
+	234, // 148: subtraterpc.RegisterTaskListResponse.task_list:type_name -> subtraterpc.TaskListProto
+	234, // 149: subtraterpc.GetTaskListResponse.task_list:type_name -> subtraterpc.TaskListProto
+	234, // 150: subtraterpc.ListTaskListsResponse.task_lists:type_name -> subtraterpc.TaskListProto
+	9,   // 151: subtraterpc.UpsertTaskRequest.status:type_name -> subtraterpc.TaskStatus
+	235, // 152: subtraterpc.UpsertTaskResponse.task:type_name -> subtraterpc.TaskProto
+	235, // 153: subtraterpc.GetTaskResponse.task:type_name -> subtraterpc.TaskProto
+	9,   // 154: subtraterpc.ListTasksRequest.status:type_name -> subtraterpc.TaskStatus
+	235, // 155: subtraterpc.ListTasksResponse.tasks:type_name -> subtraterpc.TaskProto
+	9,   // 156: subtraterpc.UpdateTaskStatusRequest.status:type_name -> subtraterpc.TaskStatus
+	305, // 157: subtraterpc.GetTaskStatsRequest.today_since:type_name -> google.protobuf.Timestamp
+	236, // 158: subtraterpc.GetTaskStatsResponse.stats:type_name -> subtraterpc.TaskStatsProto
+	305, // 159: subtraterpc.GetAllAgentTaskStatsRequest.today_since:type_name -> google.protobuf.Timestamp
+	237, // 160: subtraterpc.GetAllAgentTaskStatsResponse.stats:type_name -> subtraterpc.AgentTaskStatsProto
+	305, // 161: subtraterpc.PruneOldTasksRequest.older_than:type_name -> google.protobuf.Timestamp
+	266, // 162: subtraterpc.ListPlanReviewsResponse.plan_reviews:type_name -> subtraterpc.PlanReviewProto
+	276, // 163: subtraterpc.InboxRuleProto.conditions:type_name -> subtraterpc.InboxRuleConditions
+	277, // 164: subtraterpc.InboxRuleProto.actions:type_name -> subtraterpc.InboxRuleActions
+	305, // 165: subtraterpc.InboxRuleProto.last_matched_at:type_name -> google.protobuf.Timestamp
+	305, // 166: subtraterpc.InboxRuleProto.created_at:type_name -> google.protobuf.Timestamp
+	305, // 167: subtraterpc.InboxRuleProto.updated_at:type_name -> google.protobuf.Timestamp
+	276, // 168: subtraterpc.CreateInboxRuleRequest.conditions:type_name -> subtraterpc.InboxRuleConditions
+	277, // 169: subtraterpc.CreateInboxRuleRequest.actions:type_name -> subtraterpc.InboxRuleActions
+	278, // 170: subtraterpc.ListInboxRulesResponse.rules:type_name -> subtraterpc.InboxRuleProto
+	276, // 171: subtraterpc.TestInboxRuleRequest.conditions:type_name -> subtraterpc.InboxRuleConditions
+	11,  // 172: subtraterpc.TestInboxRuleResponse.matches:type_name -> subtraterpc.InboxMessage
+	287, // 173: subtraterpc.ListPlanAnnotationsResponse.annotations:type_name -> subtraterpc.PlanAnnotationProto
+	288, // 174: subtraterpc.ListDiffAnnotationsResponse.annotations:type_name -> subtraterpc.DiffAnnotationProto
+	12,  // 175: subtraterpc.Mail.SendMail:input_type -> subtraterpc.SendMailRequest
+	16,  // 176: subtraterpc.Mail.FetchInbox:input_type -> subtraterpc.FetchInboxRequest
+	19,  // 177: subtraterpc.Mail.ReadMessage:input_type -> subtraterpc.ReadMessageRequest
+	21,  // 178: subtraterpc.Mail.ReadThread:input_type -> subtraterpc.ReadThreadRequest
+	23,  // 179: subtraterpc.Mail.UpdateState:input_type -> subtraterpc.UpdateStateRequest
+	25,  // 180: subtraterpc.Mail.AckMessage:input_type -> subtraterpc.AckMessageRequest
+	27,  // 181: subtraterpc.Mail.GetStatus:input_type -> subtraterpc.GetStatusRequest
+	29,  // 182: subtraterpc.Mail.PollChanges:input_type -> subtraterpc.PollChangesRequest
+	31,  // 183: subtraterpc.Mail.SubscribeInbox:input_type -> subtraterpc.SubscribeInboxRequest
+	32,  // 184: subtraterpc.Mail.WatchInbox:input_type -> subtraterpc.WatchInboxRequest
+	34,  // 185: subtraterpc.Mail.GetWatcher:input_type -> subtraterpc.GetWatcherRequest
+	36,  // 186: subtraterpc.Mail.Publish:input_type -> subtraterpc.PublishRequest
+	38,  // 187: subtraterpc.Mail.Subscribe:input_type -> subtraterpc.SubscribeRequest
+	40,  // 188: subtraterpc.Mail.Unsubscribe:input_type -> subtraterpc.UnsubscribeRequest
+	42,  // 189: subtraterpc.Mail.ClaimQueueMessage:input_type -> subtraterpc.ClaimQueueMessageRequest
+	44,  // 190: subtraterpc.Mail.AckQueueMessage:input_type -> subtraterpc.AckQueueMessageRequest
+	46,  // 191: subtraterpc.Mail.ReleaseQueueMessage:input_type -> subtraterpc.ReleaseQueueMessageRequest
+	48,  // 192: subtraterpc.Mail.ExtendQueueLease:input_type -> subtraterpc.ExtendQueueLeaseRequest
+	51,  // 193: subtraterpc.Mail.ListTopics:input_type -> subtraterpc.ListTopicsRequest
+	53,  // 194: subtraterpc.Mail.Search:input_type -> subtraterpc.SearchRequest
+	56,  // 195: subtraterpc.Mail.SearchAll:input_type -> subtraterpc.SearchAllRequest
+	65,  // 196: subtraterpc.Mail.SemanticSearch:input_type -> subtraterpc.SemanticSearchRequest
+	66,  // 197: subtraterpc.Mail.SimilarMessages:input_type -> subtraterpc.SimilarMessagesRequest
+	60,  // 198: subtraterpc.Mail.SaveSearch:input_type -> subtraterpc.SaveSearchRequest
+	61,  // 199: subtraterpc.Mail.ListSavedSearches:input_type -> subtraterpc.ListSavedSearchesRequest
+	63,  // 200: subtraterpc.Mail.DeleteSavedSearch:input_type -> subtraterpc.DeleteSavedSearchRequest
+	69,  // 201: subtraterpc.Mail.HasUnackedStatusTo:input_type -> subtraterpc.HasUnackedStatusToRequest
+	84,  // 202: subtraterpc.Mail.ReplyToThread:input_type -> subtraterpc.ReplyToThreadRequest
+	86,  // 203: subtraterpc.Mail.ArchiveThread:input_type -> subtraterpc.ArchiveThreadRequest
+	88,  // 204: subtraterpc.Mail.DeleteThread:input_type -> subtraterpc.DeleteThreadRequest
+	90,  // 205: subtraterpc.Mail.MarkThreadUnread:input_type -> subtraterpc.MarkThreadUnreadRequest
+	92,  // 206: subtraterpc.Mail.GetTopic:input_type -> subtraterpc.GetTopicRequest
+	94,  // 207: subtraterpc.Mail.AutocompleteRecipients:input_type -> subtraterpc.AutocompleteRecipientsRequest
+	97,  // 208: subtraterpc.Mail.DeleteMessage:input_type -> subtraterpc.DeleteMessageRequest
+	99,  // 209: subtraterpc.Mail.CollectGarbage:input_type -> subtraterpc.CollectGarbageRequest
+	102, // 210: subtraterpc.Mail.SetLegalHold:input_type -> subtraterpc.SetLegalHoldRequest
+	104, // 211: subtraterpc.Mail.SetTopicRetention:input_type -> subtraterpc.SetTopicRetentionRequest
+	107, // 212: subtraterpc.Mail.CreateRecipientGroup:input_type -> subtraterpc.CreateRecipientGroupRequest
+	108, // 213: subtraterpc.Mail.GetRecipientGroup:input_type -> subtraterpc.GetRecipientGroupRequest
+	110, // 214: subtraterpc.Mail.ListRecipientGroups:input_type -> subtraterpc.ListRecipientGroupsRequest
+	112, // 215: subtraterpc.Mail.DeleteRecipientGroup:input_type -> subtraterpc.DeleteRecipientGroupRequest
+	114, // 216: subtraterpc.Mail.AddRecipientGroupMembers:input_type -> subtraterpc.RecipientGroupMembersRequest
+	114, // 217: subtraterpc.Mail.RemoveRecipientGroupMembers:input_type -> subtraterpc.RecipientGroupMembersRequest
+	116, // 218: subtraterpc.Mail.ListDeadLetters:input_type -> subtraterpc.ListDeadLettersRequest
+	118, // 219: subtraterpc.Mail.DeleteDeadLetter:input_type -> subtraterpc.DeleteDeadLetterRequest
+	121, // 220: subtraterpc.Mail.ListScheduledMessages:input_type -> subtraterpc.ListScheduledMessagesRequest
+	123, // 221: subtraterpc.Mail.CancelScheduledMessage:input_type -> subtraterpc.CancelScheduledMessageRequest
+	126, // 222: subtraterpc.Mail.CreateSchedule:input_type -> subtraterpc.CreateScheduleRequest
+	128, // 223: subtraterpc.Mail.ListSchedules:input_type -> subtraterpc.ListSchedulesRequest
+	130, // 224: subtraterpc.Mail.DeleteSchedule:input_type -> subtraterpc.DeleteScheduleRequest
+	132, // 225: subtraterpc.Mail.SetSchedulePaused:input_type -> subtraterpc.SetSchedulePausedRequest
+	134, // 226: subtraterpc.Mail.AskQuestion:input_type -> subtraterpc.AskQuestionRequest
+	135, // 227: subtraterpc.Mail.GetQuestion:input_type -> subtraterpc.GetQuestionRequest
+	136, // 228: subtraterpc.Mail.AnswerQuestion:input_type -> subtraterpc.AnswerQuestionRequest
+	139, // 229: subtraterpc.Mail.EditMessage:input_type -> subtraterpc.EditMessageRequest
+	141, // 230: subtraterpc.Mail.RecallMessage:input_type -> subtraterpc.RecallMessageRequest
+	143, // 231: subtraterpc.Mail.GetMessageHistory:input_type -> subtraterpc.GetMessageHistoryRequest
+	146, // 232: subtraterpc.Mail.CreateLabel:input_type -> subtraterpc.CreateLabelRequest
+	147, // 233: subtraterpc.Mail.ListLabels:input_type -> subtraterpc.ListLabelsRequest
+	149, // 234: subtraterpc.Mail.DeleteLabel:input_type -> subtraterpc.DeleteLabelRequest
+	151, // 235: subtraterpc.Mail.LabelMessage:input_type -> subtraterpc.LabelMessageRequest
+	154, // 236: subtraterpc.Mail.SetThreadMuted:input_type -> subtraterpc.SetThreadMutedRequest
+	155, // 237: subtraterpc.Mail.SetThreadFollowing:input_type -> subtraterpc.SetThreadFollowingRequest
+	156, // 238: subtraterpc.Mail.ListThreadPreferences:input_type -> subtraterpc.ListThreadPreferencesRequest
+	158, // 239: subtraterpc.Mail.MergeThreads:input_type -> subtraterpc.MergeThreadsRequest
+	160, // 240: subtraterpc.Mail.SplitThread:input_type -> subtraterpc.SplitThreadRequest
+	71,  // 241: subtraterpc.Agent.RegisterAgent:input_type -> subtraterpc.RegisterAgentRequest
+	74,  // 242: subtraterpc.Agent.GetAgent:input_type -> subtraterpc.GetAgentRequest
+	76,  // 243: subtraterpc.Agent.ListAgents:input_type -> subtraterpc.ListAgentsRequest
+	82,  // 244: subtraterpc.Agent.DeleteAgent:input_type -> subtraterpc.DeleteAgentRequest
+	162, // 245: subtraterpc.Agent.UpdateAgent:input_type -> subtraterpc.UpdateAgentRequest
+	166, // 246: subtraterpc.Agent.GetAgentsStatus:input_type -> subtraterpc.GetAgentsStatusRequest
+	191, // 247: subtraterpc.Agent.Heartbeat:input_type -> subtraterpc.HeartbeatRequest
+	78,  // 248: subtraterpc.Agent.EnsureIdentity:input_type -> subtraterpc.EnsureIdentityRequest
+	80,  // 249: subtraterpc.Agent.SaveIdentity:input_type -> subtraterpc.SaveIdentityRequest
+	168, // 250: subtraterpc.Agent.DiscoverAgents:input_type -> subtraterpc.DiscoverAgentsRequest
+	171, // 251: subtraterpc.Agent.ListCapabilityRoutes:input_type -> subtraterpc.ListCapabilityRoutesRequest
+	175, // 252: subtraterpc.Agent.MintCredential:input_type -> subtraterpc.MintCredentialRequest
+	177, // 253: subtraterpc.Agent.ListCredentials:input_type -> subtraterpc.ListCredentialsRequest
+	179, // 254: subtraterpc.Agent.RevokeCredential:input_type -> subtraterpc.RevokeCredentialRequest
+	182, // 255: subtraterpc.Agent.SetLimit:input_type -> subtraterpc.SetLimitRequest
+	184, // 256: subtraterpc.Agent.ClearLimit:input_type -> subtraterpc.ClearLimitRequest
+	186, // 257: subtraterpc.Agent.ListLimits:input_type -> subtraterpc.ListLimitsRequest
+	189, // 258: subtraterpc.Agent.GetUsage:input_type -> subtraterpc.GetUsageRequest
+	194, // 259: subtraterpc.Session.ListSessions:input_type -> subtraterpc.ListSessionsRequest
+	196, // 260: subtraterpc.Session.GetSession:input_type -> subtraterpc.GetSessionRequest
+	198, // 261: subtraterpc.Session.StartSession:input_type -> subtraterpc.StartSessionRequest
+	200, // 262: subtraterpc.Session.CompleteSession:input_type -> subtraterpc.CompleteSessionRequest
+	203, // 263: subtraterpc.Activity.ListActivities:input_type -> subtraterpc.ListActivitiesRequest
+	206, // 264: subtraterpc.Stats.GetDashboardStats:input_type -> subtraterpc.GetDashboardStatsRequest
+	208, // 265: subtraterpc.Stats.HealthCheck:input_type -> subtraterpc.HealthCheckRequest
+	238, // 266: subtraterpc.TaskService.RegisterTaskList:input_type -> subtraterpc.RegisterTaskListRequest
+	240, // 267: subtraterpc.TaskService.GetTaskList:input_type -> subtraterpc.GetTaskListRequest
+	242, // 268: subtraterpc.TaskService.ListTaskLists:input_type -> subtraterpc.ListTaskListsRequest
+	244, // 269: subtraterpc.TaskService.UnregisterTaskList:input_type -> subtraterpc.UnregisterTaskListRequest
+	246, // 270: subtraterpc.TaskService.UpsertTask:input_type -> subtraterpc.UpsertTaskRequest
+	248, // 271: subtraterpc.TaskService.GetTask:input_type -> subtraterpc.GetTaskProtoRequest
+	250, // 272: subtraterpc.TaskService.ListTasks:input_type -> subtraterpc.ListTasksRequest
+	252, // 273: subtraterpc.TaskService.UpdateTaskStatus:input_type -> subtraterpc.UpdateTaskStatusRequest
+	254, // 274: subtraterpc.TaskService.UpdateTaskOwner:input_type -> subtraterpc.UpdateTaskOwnerRequest
+	256, // 275: subtraterpc.TaskService.DeleteTask:input_type -> subtraterpc.DeleteTaskRequest
+	258, // 276: subtraterpc.TaskService.GetTaskStats:input_type -> subtraterpc.GetTaskStatsRequest
+	260, // 277: subtraterpc.TaskService.GetAllAgentTaskStats:input_type -> subtraterpc.GetAllAgentTaskStatsRequest
+	262, // 278: subtraterpc.TaskService.SyncTaskList:input_type -> subtraterpc.SyncTaskListRequest
+	264, // 279: subtraterpc.TaskService.PruneOldTasks:input_type -> subtraterpc.PruneOldTasksRequest
+	214, // 280: subtraterpc.ReviewService.CreateReview:input_type -> subtraterpc.CreateReviewRequest
+	216, // 281: subtraterpc.ReviewService.ListReviews:input_type -> subtraterpc.ListReviewsProtoRequest
+	219, // 282: subtraterpc.ReviewService.GetReview:input_type -> subtraterpc.GetReviewProtoRequest
+	222, // 283: subtraterpc.ReviewService.ResubmitReview:input_type -> subtraterpc.ResubmitReviewRequest
+	223, // 284: subtraterpc.ReviewService.CancelReview:input_type -> subtraterpc.CancelReviewProtoRequest
+	225, // 285: subtraterpc.ReviewService.DeleteReview:input_type -> subtraterpc.DeleteReviewProtoRequest
+	227, // 286: subtraterpc.ReviewService.ListReviewIssues:input_type -> subtraterpc.ListReviewIssuesRequest
+	230, // 287: subtraterpc.ReviewService.UpdateIssueStatus:input_type -> subtraterpc.UpdateIssueStatusRequest
+	232, // 288: subtraterpc.ReviewService.GetReviewDiff:input_type -> subtraterpc.GetReviewDiffRequest
+	267, // 289: subtraterpc.PlanReviewService.CreatePlanReview:input_type -> subtraterpc.CreatePlanReviewRequest
+	268, // 290: subtraterpc.PlanReviewService.GetPlanReview:input_type -> subtraterpc.GetPlanReviewRequest
+	269, // 291: subtraterpc.PlanReviewService.GetPlanReviewByThread:input_type -> subtraterpc.GetPlanReviewByThreadRequest
+	270, // 292: subtraterpc.PlanReviewService.GetPlanReviewBySession:input_type -> subtraterpc.GetPlanReviewBySessionRequest
+	271, // 293: subtraterpc.PlanReviewService.ListPlanReviews:input_type -> subtraterpc.ListPlanReviewsRequest
+	273, // 294: subtraterpc.PlanReviewService.UpdatePlanReviewStatus:input_type -> subtraterpc.UpdatePlanReviewStatusRequest
+	274, // 295: subtraterpc.PlanReviewService.DeletePlanReview:input_type -> subtraterpc.DeletePlanReviewRequest
+	279, // 296: subtraterpc.InboxRuleService.CreateInboxRule:input_type -> subtraterpc.CreateInboxRuleRequest
+	280, // 297: subtraterpc.InboxRuleService.ListInboxRules:input_type -> subtraterpc.ListInboxRulesRequest
+	282, // 298: subtraterpc.InboxRuleService.DeleteInboxRule:input_type -> subtraterpc.DeleteInboxRuleRequest
+	284, // 299: subtraterpc.InboxRuleService.SetInboxRuleEnabled:input_type -> subtraterpc.SetInboxRuleEnabledRequest
+	285, // 300: subtraterpc.InboxRuleService.TestInboxRule:input_type -> subtraterpc.TestInboxRuleRequest
+	289, // 301: subtraterpc.AnnotationService.CreatePlanAnnotation:input_type -> subtraterpc.CreatePlanAnnotationRequest
+	290, // 302: subtraterpc.AnnotationService.ListPlanAnnotations:input_type -> subtraterpc.ListPlanAnnotationsRequest
+	292, // 303: subtraterpc.AnnotationService.UpdatePlanAnnotation:input_type -> subtraterpc.UpdatePlanAnnotationRequest
+	293, // 304: subtraterpc.AnnotationService.DeletePlanAnnotation:input_type -> subtraterpc.DeletePlanAnnotationRequest
+	295, // 305: subtraterpc.AnnotationService.CreateDiffAnnotation:input_type -> subtraterpc.CreateDiffAnnotationRequest
+	296, // 306: subtraterpc.AnnotationService.ListDiffAnnotations:input_type -> subtraterpc.ListDiffAnnotationsRequest
+	298, // 307: subtraterpc.AnnotationService.UpdateDiffAnnotation:input_type -> subtraterpc.UpdateDiffAnnotationRequest
+	299, // 308: subtraterpc.AnnotationService.DeleteDiffAnnotation:input_type -> subtraterpc.DeleteDiffAnnotationRequest
+	13,  // 309: subtraterpc.Mail.SendMail:output_type -> subtraterpc.SendMailResponse
+	17,  // 310: subtraterpc.Mail.FetchInbox:output_type -> subtraterpc.FetchInboxResponse
+	20,  // 311: subtraterpc.Mail.ReadMessage:output_type -> subtraterpc.ReadMessageResponse
+	22,  // 312: subtraterpc.Mail.ReadThread:output_type -> subtraterpc.ReadThreadResponse
+	24,  // 313: subtraterpc.Mail.UpdateState:output_type -> subtraterpc.UpdateStateResponse
+	26,  // 314: subtraterpc.Mail.AckMessage:output_type -> subtraterpc.AckMessageResponse
+	28,  // 315: subtraterpc.Mail.GetStatus:output_type -> subtraterpc.GetStatusResponse
+	30,  // 316: subtraterpc.Mail.PollChanges:output_type -> subtraterpc.PollChangesResponse
+	11,  // 317: subtraterpc.Mail.SubscribeInbox:output_type -> subtraterpc.InboxMessage
+	33,  // 318: subtraterpc.Mail.WatchInbox:output_type -> subtraterpc.WatchInboxEvent
+	35,  // 319: subtraterpc.Mail.GetWatcher:output_type -> subtraterpc.GetWatcherResponse
+	37,  // 320: subtraterpc.Mail.Publish:output_type -> subtraterpc.PublishResponse
+	39,  // 321: subtraterpc.Mail.Subscribe:output_type -> subtraterpc.SubscribeResponse
+	41,  // 322: subtraterpc.Mail.Unsubscribe:output_type -> subtraterpc.UnsubscribeResponse
+	43,  // 323: subtraterpc.Mail.ClaimQueueMessage:output_type -> subtraterpc.ClaimQueueMessageResponse
+	45,  // 324: subtraterpc.Mail.AckQueueMessage:output_type -> subtraterpc.AckQueueMessageResponse
+	47,  // 325: subtraterpc.Mail.ReleaseQueueMessage:output_type -> subtraterpc.ReleaseQueueMessageResponse
+	49,  // 326: subtraterpc.Mail.ExtendQueueLease:output_type -> subtraterpc.ExtendQueueLeaseResponse
+	52,  // 327: subtraterpc.Mail.ListTopics:output_type -> subtraterpc.ListTopicsResponse
+	55,  // 328: subtraterpc.Mail.Search:output_type -> subtraterpc.SearchResponse
+	58,  // 329: subtraterpc.Mail.SearchAll:output_type -> subtraterpc.SearchAllResponse
+	68,  // 330: subtraterpc.Mail.SemanticSearch:output_type -> subtraterpc.SemanticSearchResponse
+	68,  // 331: subtraterpc.Mail.SimilarMessages:output_type -> subtraterpc.SemanticSearchResponse
+	59,  // 332: subtraterpc.Mail.SaveSearch:output_type -> subtraterpc.SavedSearchProto
+	62,  // 333: subtraterpc.Mail.ListSavedSearches:output_type -> subtraterpc.ListSavedSearchesResponse
+	64,  // 334: subtraterpc.Mail.DeleteSavedSearch:output_type -> subtraterpc.DeleteSavedSearchResponse
+	70,  // 335: subtraterpc.Mail.HasUnackedStatusTo:output_type -> subtraterpc.HasUnackedStatusToResponse
+	85,  // 336: subtraterpc.Mail.ReplyToThread:output_type -> subtraterpc.ReplyToThreadResponse
+	87,  // 337: subtraterpc.Mail.ArchiveThread:output_type -> subtraterpc.ArchiveThreadResponse
+	89,  // 338: subtraterpc.Mail.DeleteThread:output_type -> subtraterpc.DeleteThreadResponse
+	91,  // 339: subtraterpc.Mail.MarkThreadUnread:output_type -> subtraterpc.MarkThreadUnreadResponse
+	93,  // 340: subtraterpc.Mail.GetTopic:output_type -> subtraterpc.GetTopicResponse
+	96,  // 341: subtraterpc.Mail.AutocompleteRecipients:output_type -> subtraterpc.AutocompleteRecipientsResponse
+	98,  // 342: subtraterpc.Mail.DeleteMessage:output_type -> subtraterpc.DeleteMessageResponse
+	101, // 343: subtraterpc.Mail.CollectGarbage:output_type -> subtraterpc.CollectGarbageResponse
+	103, // 344: subtraterpc.Mail.SetLegalHold:output_type -> subtraterpc.SetLegalHoldResponse
+	105, // 345: subtraterpc.Mail.SetTopicRetention:output_type -> subtraterpc.SetTopicRetentionResponse
+	109, // 346: subtraterpc.Mail.CreateRecipientGroup:output_type -> subtraterpc.RecipientGroupResponse
+	109, // 347: subtraterpc.Mail.GetRecipientGroup:output_type -> subtraterpc.RecipientGroupResponse
+	111, // 348: subtraterpc.Mail.ListRecipientGroups:output_type -> subtraterpc.ListRecipientGroupsResponse
+	113, // 349: subtraterpc.Mail.DeleteRecipientGroup:output_type -> subtraterpc.DeleteRecipientGroupResponse
+	109, // 350: subtraterpc.Mail.AddRecipientGroupMembers:output_type -> subtraterpc.RecipientGroupResponse
+	109, // 351: subtraterpc.Mail.RemoveRecipientGroupMembers:output_type -> subtraterpc.RecipientGroupResponse
+	117, // 352: subtraterpc.Mail.ListDeadLetters:output_type -> subtraterpc.ListDeadLettersResponse
+	119, // 353: subtraterpc.Mail.DeleteDeadLetter:output_type -> subtraterpc.DeleteDeadLetterResponse
+	122, // 354: subtraterpc.Mail.ListScheduledMessages:output_type -> subtraterpc.ListScheduledMessagesResponse
+	124, // 355: subtraterpc.Mail.CancelScheduledMessage:output_type -> subtraterpc.CancelScheduledMessageResponse
+	127, // 356: subtraterpc.Mail.CreateSchedule:output_type -> subtraterpc.ScheduleResponse
+	129, // 357: subtraterpc.Mail.ListSchedules:output_type -> subtraterpc.ListSchedulesResponse
+	131, // 358: subtraterpc.Mail.DeleteSchedule:output_type -> subtraterpc.DeleteScheduleResponse
+	127, // 359: subtraterpc.Mail.SetSchedulePaused:output_type -> subtraterpc.ScheduleResponse
+	137, // 360: subtraterpc.Mail.AskQuestion:output_type -> subtraterpc.QuestionResponse
+	137, // 361: subtraterpc.Mail.GetQuestion:output_type -> subtraterpc.QuestionResponse
+	137, // 362: subtraterpc.Mail.AnswerQuestion:output_type -> subtraterpc.QuestionResponse
+	140, // 363: subtraterpc.Mail.EditMessage:output_type -> subtraterpc.EditMessageResponse
+	142, // 364: subtraterpc.Mail.RecallMessage:output_type -> subtraterpc.RecallMessageResponse
+	144, // 365: subtraterpc.Mail.GetMessageHistory:output_type -> subtraterpc.GetMessageHistoryResponse
+	145, // 366: subtraterpc.Mail.CreateLabel:output_type -> subtraterpc.LabelProto
+	148, // 367: subtraterpc.Mail.ListLabels:output_type -> subtraterpc.ListLabelsResponse
+	150, // 368: subtraterpc.Mail.DeleteLabel:output_type -> subtraterpc.DeleteLabelResponse
+	152, // 369: subtraterpc.Mail.LabelMessage:output_type -> subtraterpc.LabelMessageResponse
+	153, // 370: subtraterpc.Mail.SetThreadMuted:output_type -> subtraterpc.ThreadPreferenceProto
+	153, // 371: subtraterpc.Mail.SetThreadFollowing:output_type -> subtraterpc.ThreadPreferenceProto
+	157, // 372: subtraterpc.Mail.ListThreadPreferences:output_type -> subtraterpc.ListThreadPreferencesResponse
+	159, // 373: subtraterpc.Mail.MergeThreads:output_type -> subtraterpc.MergeThreadsResponse
+	161, // 374: subtraterpc.Mail.SplitThread:output_type -> subtraterpc.SplitThreadResponse
+	72,  // 375: subtraterpc.Agent.RegisterAgent:output_type -> subtraterpc.RegisterAgentResponse
+	75,  // 376: subtraterpc.Agent.GetAgent:output_type -> subtraterpc.GetAgentResponse
+	77,  // 377: subtraterpc.Agent.ListAgents:output_type -> subtraterpc.ListAgentsResponse
+	83,  // 378: subtraterpc.Agent.DeleteAgent:output_type -> subtraterpc.DeleteAgentResponse
+	163, // 379: subtraterpc.Agent.UpdateAgent:output_type -> subtraterpc.UpdateAgentResponse
+	167, // 380: subtraterpc.Agent.GetAgentsStatus:output_type -> subtraterpc.GetAgentsStatusResponse
+	192, // 381: subtraterpc.Agent.Heartbeat:output_type -> subtraterpc.HeartbeatResponse
+	79,  // 382: subtraterpc.Agent.EnsureIdentity:output_type -> subtraterpc.EnsureIdentityResponse
+	81,  // 383: subtraterpc.Agent.SaveIdentity:output_type -> subtraterpc.SaveIdentityResponse
+	170, // 384: subtraterpc.Agent.DiscoverAgents:output_type -> subtraterpc.DiscoverAgentsResponse
+	173, // 385: subtraterpc.Agent.ListCapabilityRoutes:output_type -> subtraterpc.ListCapabilityRoutesResponse
+	176, // 386: subtraterpc.Agent.MintCredential:output_type -> subtraterpc.MintCredentialResponse
+	178, // 387: subtraterpc.Agent.ListCredentials:output_type -> subtraterpc.ListCredentialsResponse
+	180, // 388: subtraterpc.Agent.RevokeCredential:output_type -> subtraterpc.RevokeCredentialResponse
+	183, // 389: subtraterpc.Agent.SetLimit:output_type -> subtraterpc.SetLimitResponse
+	185, // 390: subtraterpc.Agent.ClearLimit:output_type -> subtraterpc.ClearLimitResponse
+	187, // 391: subtraterpc.Agent.ListLimits:output_type -> subtraterpc.ListLimitsResponse
+	190, // 392: subtraterpc.Agent.GetUsage:output_type -> subtraterpc.GetUsageResponse
+	195, // 393: subtraterpc.Session.ListSessions:output_type -> subtraterpc.ListSessionsResponse
+	197, // 394: subtraterpc.Session.GetSession:output_type -> subtraterpc.GetSessionResponse
+	199, // 395: subtraterpc.Session.StartSession:output_type -> subtraterpc.StartSessionResponse
+	201, // 396: subtraterpc.Session.CompleteSession:output_type -> subtraterpc.CompleteSessionResponse
+	204, // 397: subtraterpc.Activity.ListActivities:output_type -> subtraterpc.ListActivitiesResponse
+	207, // 398: subtraterpc.Stats.GetDashboardStats:output_type -> subtraterpc.GetDashboardStatsResponse
+	209, // 399: subtraterpc.Stats.HealthCheck:output_type -> subtraterpc.HealthCheckResponse
+	239, // 400: subtraterpc.TaskService.RegisterTaskList:output_type -> subtraterpc.RegisterTaskListResponse
+	241, // 401: subtraterpc.TaskService.GetTaskList:output_type -> subtraterpc.GetTaskListResponse
+	243, // 402: subtraterpc.TaskService.ListTaskLists:output_type -> subtraterpc.ListTaskListsResponse
+	245, // 403: subtraterpc.TaskService.UnregisterTaskList:output_type -> subtraterpc.UnregisterTaskListResponse
+	247, // 404: subtraterpc.TaskService.UpsertTask:output_type -> subtraterpc.UpsertTaskResponse
+	249, // 405: subtraterpc.TaskService.GetTask:output_type -> subtraterpc.GetTaskResponse
+	251, // 406: subtraterpc.TaskService.ListTasks:output_type -> subtraterpc.ListTasksResponse
+	253, // 407: subtraterpc.TaskService.UpdateTaskStatus:output_type -> subtraterpc.UpdateTaskStatusResponse
+	255, // 408: subtraterpc.TaskService.UpdateTaskOwner:output_type -> subtraterpc.UpdateTaskOwnerResponse
+	257, // 409: subtraterpc.TaskService.DeleteTask:output_type -> subtraterpc.DeleteTaskResponse
+	259, // 410: subtraterpc.TaskService.GetTaskStats:output_type -> subtraterpc.GetTaskStatsResponse
+	261, // 411: subtraterpc.TaskService.GetAllAgentTaskStats:output_type -> subtraterpc.GetAllAgentTaskStatsResponse
+	263, // 412: subtraterpc.TaskService.SyncTaskList:output_type -> subtraterpc.SyncTaskListResponse
+	265, // 413: subtraterpc.TaskService.PruneOldTasks:output_type -> subtraterpc.PruneOldTasksResponse
+	215, // 414: subtraterpc.ReviewService.CreateReview:output_type -> subtraterpc.CreateReviewResponse
+	217, // 415: subtraterpc.ReviewService.ListReviews:output_type -> subtraterpc.ListReviewsProtoResponse
+	220, // 416: subtraterpc.ReviewService.GetReview:output_type -> subtraterpc.ReviewDetailResponse
+	215, // 417: subtraterpc.ReviewService.ResubmitReview:output_type -> subtraterpc.CreateReviewResponse
+	224, // 418: subtraterpc.ReviewService.CancelReview:output_type -> subtraterpc.CancelReviewProtoResponse
+	226, // 419: subtraterpc.ReviewService.DeleteReview:output_type -> subtraterpc.DeleteReviewProtoResponse
+	228, // 420: subtraterpc.ReviewService.ListReviewIssues:output_type -> subtraterpc.ListReviewIssuesResponse
+	231, // 421: subtraterpc.ReviewService.UpdateIssueStatus:output_type -> subtraterpc.UpdateIssueStatusResponse
+	233, // 422: subtraterpc.ReviewService.GetReviewDiff:output_type -> subtraterpc.GetReviewDiffResponse
+	266, // 423: subtraterpc.PlanReviewService.CreatePlanReview:output_type -> subtraterpc.PlanReviewProto
+	266, // 424: subtraterpc.PlanReviewService.GetPlanReview:output_type -> subtraterpc.PlanReviewProto
+	266, // 425: subtraterpc.PlanReviewService.GetPlanReviewByThread:output_type -> subtraterpc.PlanReviewProto
+	266, // 426: subtraterpc.PlanReviewService.GetPlanReviewBySession:output_type -> subtraterpc.PlanReviewProto
+	272, // 427: subtraterpc.PlanReviewService.ListPlanReviews:output_type -> subtraterpc.ListPlanReviewsResponse
+	266, // 428: subtraterpc.PlanReviewService.UpdatePlanReviewStatus:output_type -> subtraterpc.PlanReviewProto
+	275, // 429: subtraterpc.PlanReviewService.DeletePlanReview:output_type -> subtraterpc.DeletePlanReviewResponse
+	278, // 430: subtraterpc.InboxRuleService.CreateInboxRule:output_type -> subtraterpc.InboxRuleProto
+	281, // 431: subtraterpc.InboxRuleService.ListInboxRules:output_type -> subtraterpc.ListInboxRulesResponse
+	283, // 432: subtraterpc.InboxRuleService.DeleteInboxRule:output_type -> subtraterpc.DeleteInboxRuleResponse
+	278, // 433: subtraterpc.InboxRuleService.SetInboxRuleEnabled:output_type -> subtraterpc.InboxRuleProto
+	286, // 434: subtraterpc.InboxRuleService.TestInboxRule:output_type -> subtraterpc.TestInboxRuleResponse
+	287, // 435: subtraterpc.AnnotationService.CreatePlanAnnotation:output_type -> subtraterpc.PlanAnnotationProto
+	291, // 436: subtraterpc.AnnotationService.ListPlanAnnotations:output_type -> subtraterpc.ListPlanAnnotationsResponse
+	287, // 437: subtraterpc.AnnotationService.UpdatePlanAnnotation:output_type -> subtraterpc.PlanAnnotationProto
+	294, // 438: subtraterpc.AnnotationService.DeletePlanAnnotation:output_type -> subtraterpc.DeleteAnnotationResponse
+	288, // 439: subtraterpc.AnnotationService.CreateDiffAnnotation:output_type -> subtraterpc.DiffAnnotationProto
+	297, // 440: subtraterpc.AnnotationService.ListDiffAnnotations:output_type -> subtraterpc.ListDiffAnnotationsResponse
+	288, // 441: subtraterpc.AnnotationService.UpdateDiffAnnotation:output_type -> subtraterpc.DiffAnnotationProto
+	294, // 442: subtraterpc.AnnotationService.DeleteDiffAnnotation:output_type -> subtraterpc.DeleteAnnotationResponse
+	309, // [309:443] is the sub-list for method output_type
+	175, // [175:309] is the sub-list for method input_type
+	175, // [175:175] is the sub-list for extension type_name
+	175, // [175:175] is the sub-list for extension extendee
+	0,   // [0:175] is the sub-list for field type_name
 }
 
 func init() { file_mail_proto_init() }
@@ -12375,7 +21774,7 @@ func file_mail_proto_init() {
 	if File_mail_proto != nil {
 		return
 	}
-	file_mail_proto_msgTypes[91].OneofWrappers = []any{
+	file_mail_proto_msgTypes[203].OneofWrappers = []any{
 		(*CreateReviewRequest_BranchTarget)(nil),
 		(*CreateReviewRequest_CommitTarget)(nil),
 		(*CreateReviewRequest_CommitRangeTarget)(nil),
@@ -12386,10 +21785,10 @@ func file_mail_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mail_proto_rawDesc), len(file_mail_proto_rawDesc)),
-			NumEnums:      7,
-			NumMessages:   169,
+			NumEnums:      11,
+			NumMessages:   294,
 			NumExtensions: 0,
-			NumServices:   9,
+			NumServices:   10,
 		},
 		GoTypes:           file_mail_proto_goTypes,
 		DependencyIndexes: file_mail_proto_depIdxs,
@@ -19,28 +19,72 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Mail_SendMail_FullMethodName               = "/subtraterpc.Mail/SendMail"
-	Mail_FetchInbox_FullMethodName             = "/subtraterpc.Mail/FetchInbox"
-	Mail_ReadMessage_FullMethodName            = "/subtraterpc.Mail/ReadMessage"
-	Mail_ReadThread_FullMethodName             = "/subtraterpc.Mail/ReadThread"
-	Mail_UpdateState_FullMethodName            = "/subtraterpc.Mail/UpdateState"
-	Mail_AckMessage_FullMethodName             = "/subtraterpc.Mail/AckMessage"
-	Mail_GetStatus_FullMethodName              = "/subtraterpc.Mail/GetStatus"
-	Mail_PollChanges_FullMethodName            = "/subtraterpc.Mail/PollChanges"
-	Mail_SubscribeInbox_FullMethodName         = "/subtraterpc.Mail/SubscribeInbox"
-	Mail_Publish_FullMethodName                = "/subtraterpc.Mail/Publish"
-	Mail_Subscribe_FullMethodName              = "/subtraterpc.Mail/Subscribe"
-	Mail_Unsubscribe_FullMethodName            = "/subtraterpc.Mail/Unsubscribe"
-	Mail_ListTopics_FullMethodName             = "/subtraterpc.Mail/ListTopics"
-	Mail_Search_FullMethodName                 = "/subtraterpc.Mail/Search"
-	Mail_HasUnackedStatusTo_FullMethodName     = "/subtraterpc.Mail/HasUnackedStatusTo"
-	Mail_ReplyToThread_FullMethodName          = "/subtraterpc.Mail/ReplyToThread"
-	Mail_ArchiveThread_FullMethodName          = "/subtraterpc.Mail/ArchiveThread"
-	Mail_DeleteThread_FullMethodName           = "/subtraterpc.Mail/DeleteThread"
-	Mail_MarkThreadUnread_FullMethodName       = "/subtraterpc.Mail/MarkThreadUnread"
-	Mail_GetTopic_FullMethodName               = "/subtraterpc.Mail/GetTopic"
-	Mail_AutocompleteRecipients_FullMethodName = "/subtraterpc.Mail/AutocompleteRecipients"
-	Mail_DeleteMessage_FullMethodName          = "/subtraterpc.Mail/DeleteMessage"
+	Mail_SendMail_FullMethodName                    = "/subtraterpc.Mail/SendMail"
+	Mail_FetchInbox_FullMethodName                  = "/subtraterpc.Mail/FetchInbox"
+	Mail_ReadMessage_FullMethodName                 = "/subtraterpc.Mail/ReadMessage"
+	Mail_ReadThread_FullMethodName                  = "/subtraterpc.Mail/ReadThread"
+	Mail_UpdateState_FullMethodName                 = "/subtraterpc.Mail/UpdateState"
+	Mail_AckMessage_FullMethodName                  = "/subtraterpc.Mail/AckMessage"
+	Mail_GetStatus_FullMethodName                   = "/subtraterpc.Mail/GetStatus"
+	Mail_PollChanges_FullMethodName                 = "/subtraterpc.Mail/PollChanges"
+	Mail_SubscribeInbox_FullMethodName              = "/subtraterpc.Mail/SubscribeInbox"
+	Mail_WatchInbox_FullMethodName                  = "/subtraterpc.Mail/WatchInbox"
+	Mail_GetWatcher_FullMethodName                  = "/subtraterpc.Mail/GetWatcher"
+	Mail_Publish_FullMethodName                     = "/subtraterpc.Mail/Publish"
+	Mail_Subscribe_FullMethodName                   = "/subtraterpc.Mail/Subscribe"
+	Mail_Unsubscribe_FullMethodName                 = "/subtraterpc.Mail/Unsubscribe"
+	Mail_ClaimQueueMessage_FullMethodName           = "/subtraterpc.Mail/ClaimQueueMessage"
+	Mail_AckQueueMessage_FullMethodName             = "/subtraterpc.Mail/AckQueueMessage"
+	Mail_ReleaseQueueMessage_FullMethodName         = "/subtraterpc.Mail/ReleaseQueueMessage"
+	Mail_ExtendQueueLease_FullMethodName            = "/subtraterpc.Mail/ExtendQueueLease"
+	Mail_ListTopics_FullMethodName                  = "/subtraterpc.Mail/ListTopics"
+	Mail_Search_FullMethodName                      = "/subtraterpc.Mail/Search"
+	Mail_SearchAll_FullMethodName                   = "/subtraterpc.Mail/SearchAll"
+	Mail_SemanticSearch_FullMethodName              = "/subtraterpc.Mail/SemanticSearch"
+	Mail_SimilarMessages_FullMethodName             = "/subtraterpc.Mail/SimilarMessages"
+	Mail_SaveSearch_FullMethodName                  = "/subtraterpc.Mail/SaveSearch"
+	Mail_ListSavedSearches_FullMethodName           = "/subtraterpc.Mail/ListSavedSearches"
+	Mail_DeleteSavedSearch_FullMethodName           = "/subtraterpc.Mail/DeleteSavedSearch"
+	Mail_HasUnackedStatusTo_FullMethodName          = "/subtraterpc.Mail/HasUnackedStatusTo"
+	Mail_ReplyToThread_FullMethodName               = "/subtraterpc.Mail/ReplyToThread"
+	Mail_ArchiveThread_FullMethodName               = "/subtraterpc.Mail/ArchiveThread"
+	Mail_DeleteThread_FullMethodName                = "/subtraterpc.Mail/DeleteThread"
+	Mail_MarkThreadUnread_FullMethodName            = "/subtraterpc.Mail/MarkThreadUnread"
+	Mail_GetTopic_FullMethodName                    = "/subtraterpc.Mail/GetTopic"
+	Mail_AutocompleteRecipients_FullMethodName      = "/subtraterpc.Mail/AutocompleteRecipients"
+	Mail_DeleteMessage_FullMethodName               = "/subtraterpc.Mail/DeleteMessage"
+	Mail_CollectGarbage_FullMethodName              = "/subtraterpc.Mail/CollectGarbage"
+	Mail_SetLegalHold_FullMethodName                = "/subtraterpc.Mail/SetLegalHold"
+	Mail_SetTopicRetention_FullMethodName           = "/subtraterpc.Mail/SetTopicRetention"
+	Mail_CreateRecipientGroup_FullMethodName        = "/subtraterpc.Mail/CreateRecipientGroup"
+	Mail_GetRecipientGroup_FullMethodName           = "/subtraterpc.Mail/GetRecipientGroup"
+	Mail_ListRecipientGroups_FullMethodName         = "/subtraterpc.Mail/ListRecipientGroups"
+	Mail_DeleteRecipientGroup_FullMethodName        = "/subtraterpc.Mail/DeleteRecipientGroup"
+	Mail_AddRecipientGroupMembers_FullMethodName    = "/subtraterpc.Mail/AddRecipientGroupMembers"
+	Mail_RemoveRecipientGroupMembers_FullMethodName = "/subtraterpc.Mail/RemoveRecipientGroupMembers"
+	Mail_ListDeadLetters_FullMethodName             = "/subtraterpc.Mail/ListDeadLetters"
+	Mail_DeleteDeadLetter_FullMethodName            = "/subtraterpc.Mail/DeleteDeadLetter"
+	Mail_ListScheduledMessages_FullMethodName       = "/subtraterpc.Mail/ListScheduledMessages"
+	Mail_CancelScheduledMessage_FullMethodName      = "/subtraterpc.Mail/CancelScheduledMessage"
+	Mail_CreateSchedule_FullMethodName              = "/subtraterpc.Mail/CreateSchedule"
+	Mail_ListSchedules_FullMethodName               = "/subtraterpc.Mail/ListSchedules"
+	Mail_DeleteSchedule_FullMethodName              = "/subtraterpc.Mail/DeleteSchedule"
+	Mail_SetSchedulePaused_FullMethodName           = "/subtraterpc.Mail/SetSchedulePaused"
+	Mail_AskQuestion_FullMethodName                 = "/subtraterpc.Mail/AskQuestion"
+	Mail_GetQuestion_FullMethodName                 = "/subtraterpc.Mail/GetQuestion"
+	Mail_AnswerQuestion_FullMethodName              = "/subtraterpc.Mail/AnswerQuestion"
+	Mail_EditMessage_FullMethodName                 = "/subtraterpc.Mail/EditMessage"
+	Mail_RecallMessage_FullMethodName               = "/subtraterpc.Mail/RecallMessage"
+	Mail_GetMessageHistory_FullMethodName           = "/subtraterpc.Mail/GetMessageHistory"
+	Mail_CreateLabel_FullMethodName                 = "/subtraterpc.Mail/CreateLabel"
+	Mail_ListLabels_FullMethodName                  = "/subtraterpc.Mail/ListLabels"
+	Mail_DeleteLabel_FullMethodName                 = "/subtraterpc.Mail/DeleteLabel"
+	Mail_LabelMessage_FullMethodName                = "/subtraterpc.Mail/LabelMessage"
+	Mail_SetThreadMuted_FullMethodName              = "/subtraterpc.Mail/SetThreadMuted"
+	Mail_SetThreadFollowing_FullMethodName          = "/subtraterpc.Mail/SetThreadFollowing"
+	Mail_ListThreadPreferences_FullMethodName       = "/subtraterpc.Mail/ListThreadPreferences"
+	Mail_MergeThreads_FullMethodName                = "/subtraterpc.Mail/MergeThreads"
+	Mail_SplitThread_FullMethodName                 = "/subtraterpc.Mail/SplitThread"
 )
 
 // MailClient is the client API for Mail service.
@@ -68,16 +112,47 @@ type MailClient interface {
 	PollChanges(ctx context.Context, in *PollChangesRequest, opts ...grpc.CallOption) (*PollChangesResponse, error)
 	// SubscribeInbox creates a stream of new inbox messages.
 	SubscribeInbox(ctx context.Context, in *SubscribeInboxRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[InboxMessage], error)
+	// WatchInbox arms a watcher on an agent's inbox, taking its watcher
+	// lease, and streams the mail that wakes it. Unread backlog newer
+	// than after_message_id is sent straight away; mail pushed again,
+	// such as a message whose snooze ended, wakes it however old.
+	WatchInbox(ctx context.Context, in *WatchInboxRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchInboxEvent], error)
+	// GetWatcher reports the watcher holding an agent's watcher lease.
+	GetWatcher(ctx context.Context, in *GetWatcherRequest, opts ...grpc.CallOption) (*GetWatcherResponse, error)
 	// Publish sends a message to a pub/sub topic.
 	Publish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (*PublishResponse, error)
 	// Subscribe subscribes an agent to a topic.
 	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (*SubscribeResponse, error)
 	// Unsubscribe removes an agent's subscription to a topic.
 	Unsubscribe(ctx context.Context, in *UnsubscribeRequest, opts ...grpc.CallOption) (*UnsubscribeResponse, error)
+	// ClaimQueueMessage leases the next available message on a queue topic
+	// to a single subscriber.
+	ClaimQueueMessage(ctx context.Context, in *ClaimQueueMessageRequest, opts ...grpc.CallOption) (*ClaimQueueMessageResponse, error)
+	// AckQueueMessage completes a lease so the message is never redelivered.
+	AckQueueMessage(ctx context.Context, in *AckQueueMessageRequest, opts ...grpc.CallOption) (*AckQueueMessageResponse, error)
+	// ReleaseQueueMessage hands a leased message back to the queue.
+	ReleaseQueueMessage(ctx context.Context, in *ReleaseQueueMessageRequest, opts ...grpc.CallOption) (*ReleaseQueueMessageResponse, error)
+	// ExtendQueueLease pushes out the expiry of a lease.
+	ExtendQueueLease(ctx context.Context, in *ExtendQueueLeaseRequest, opts ...grpc.CallOption) (*ExtendQueueLeaseResponse, error)
 	// ListTopics lists available topics.
 	ListTopics(ctx context.Context, in *ListTopicsRequest, opts ...grpc.CallOption) (*ListTopicsResponse, error)
 	// Search performs full-text search across messages.
 	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	// SearchAll performs full-text search across messages, review issues,
+	// plan reviews, tasks and annotations, returning typed, ranked hits.
+	SearchAll(ctx context.Context, in *SearchAllRequest, opts ...grpc.CallOption) (*SearchAllResponse, error)
+	// SemanticSearch finds the messages closest in meaning to a query, or
+	// in hybrid mode merges them with the query's BM25 matches.
+	SemanticSearch(ctx context.Context, in *SemanticSearchRequest, opts ...grpc.CallOption) (*SemanticSearchResponse, error)
+	// SimilarMessages finds the nearest neighbours of a message or a text.
+	SimilarMessages(ctx context.Context, in *SimilarMessagesRequest, opts ...grpc.CallOption) (*SemanticSearchResponse, error)
+	// SaveSearch saves a search query under a name, replacing the query of
+	// a search the agent already saved under it. Run it with Search.
+	SaveSearch(ctx context.Context, in *SaveSearchRequest, opts ...grpc.CallOption) (*SavedSearchProto, error)
+	// ListSavedSearches lists the agent's saved searches by name.
+	ListSavedSearches(ctx context.Context, in *ListSavedSearchesRequest, opts ...grpc.CallOption) (*ListSavedSearchesResponse, error)
+	// DeleteSavedSearch deletes one of the agent's saved searches.
+	DeleteSavedSearch(ctx context.Context, in *DeleteSavedSearchRequest, opts ...grpc.CallOption) (*DeleteSavedSearchResponse, error)
 	// HasUnackedStatusTo checks if there are unacked status messages from
 	// sender to recipient. Used for deduplication in status-update command.
 	HasUnackedStatusTo(ctx context.Context, in *HasUnackedStatusToRequest, opts ...grpc.CallOption) (*HasUnackedStatusToResponse, error)
@@ -95,6 +170,86 @@ type MailClient interface {
 	AutocompleteRecipients(ctx context.Context, in *AutocompleteRecipientsRequest, opts ...grpc.CallOption) (*AutocompleteRecipientsResponse, error)
 	// DeleteMessage marks a message as deleted/trash.
 	DeleteMessage(ctx context.Context, in *DeleteMessageRequest, opts ...grpc.CallOption) (*DeleteMessageResponse, error)
+	// CollectGarbage enforces topic retention, deleting expired messages and
+	// stale consumer offsets, or reports what would be deleted on a dry run.
+	CollectGarbage(ctx context.Context, in *CollectGarbageRequest, opts ...grpc.CallOption) (*CollectGarbageResponse, error)
+	// SetLegalHold places or lifts a legal hold on a message or a whole
+	// thread, exempting it from retention.
+	SetLegalHold(ctx context.Context, in *SetLegalHoldRequest, opts ...grpc.CallOption) (*SetLegalHoldResponse, error)
+	// SetTopicRetention sets how long a topic's messages are kept before
+	// retention deletes them.
+	SetTopicRetention(ctx context.Context, in *SetTopicRetentionRequest, opts ...grpc.CallOption) (*SetTopicRetentionResponse, error)
+	// CreateRecipientGroup creates a named group of agents that can be
+	// addressed as "@<name>".
+	CreateRecipientGroup(ctx context.Context, in *CreateRecipientGroupRequest, opts ...grpc.CallOption) (*RecipientGroupResponse, error)
+	// GetRecipientGroup returns a recipient group and its members.
+	GetRecipientGroup(ctx context.Context, in *GetRecipientGroupRequest, opts ...grpc.CallOption) (*RecipientGroupResponse, error)
+	// ListRecipientGroups lists all recipient groups.
+	ListRecipientGroups(ctx context.Context, in *ListRecipientGroupsRequest, opts ...grpc.CallOption) (*ListRecipientGroupsResponse, error)
+	// DeleteRecipientGroup deletes a recipient group.
+	DeleteRecipientGroup(ctx context.Context, in *DeleteRecipientGroupRequest, opts ...grpc.CallOption) (*DeleteRecipientGroupResponse, error)
+	// AddRecipientGroupMembers adds agents to a recipient group.
+	AddRecipientGroupMembers(ctx context.Context, in *RecipientGroupMembersRequest, opts ...grpc.CallOption) (*RecipientGroupResponse, error)
+	// RemoveRecipientGroupMembers removes agents from a recipient group.
+	RemoveRecipientGroupMembers(ctx context.Context, in *RecipientGroupMembersRequest, opts ...grpc.CallOption) (*RecipientGroupResponse, error)
+	// ListDeadLetters lists the sender's undeliverable recipients, newest
+	// first.
+	ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error)
+	// DeleteDeadLetter removes an entry from the sender's dead-letter mailbox.
+	DeleteDeadLetter(ctx context.Context, in *DeleteDeadLetterRequest, opts ...grpc.CallOption) (*DeleteDeadLetterResponse, error)
+	// ListScheduledMessages lists the sender's scheduled messages that have
+	// not been sent or canceled, soonest first.
+	ListScheduledMessages(ctx context.Context, in *ListScheduledMessagesRequest, opts ...grpc.CallOption) (*ListScheduledMessagesResponse, error)
+	// CancelScheduledMessage cancels a scheduled message before it is sent.
+	CancelScheduledMessage(ctx context.Context, in *CancelScheduledMessageRequest, opts ...grpc.CallOption) (*CancelScheduledMessageResponse, error)
+	// CreateSchedule creates a recurring message sent on a cron schedule.
+	CreateSchedule(ctx context.Context, in *CreateScheduleRequest, opts ...grpc.CallOption) (*ScheduleResponse, error)
+	// ListSchedules lists the agent's recurring schedules by name.
+	ListSchedules(ctx context.Context, in *ListSchedulesRequest, opts ...grpc.CallOption) (*ListSchedulesResponse, error)
+	// DeleteSchedule deletes one of the agent's recurring schedules.
+	DeleteSchedule(ctx context.Context, in *DeleteScheduleRequest, opts ...grpc.CallOption) (*DeleteScheduleResponse, error)
+	// SetSchedulePaused pauses or resumes one of the agent's recurring
+	// schedules.
+	SetSchedulePaused(ctx context.Context, in *SetSchedulePausedRequest, opts ...grpc.CallOption) (*ScheduleResponse, error)
+	// AskQuestion sends a structured question. The first reply in its
+	// thread from one of the agents asked is recorded as the answer.
+	AskQuestion(ctx context.Context, in *AskQuestionRequest, opts ...grpc.CallOption) (*QuestionResponse, error)
+	// GetQuestion returns a question and, once resolved, its answer.
+	GetQuestion(ctx context.Context, in *GetQuestionRequest, opts ...grpc.CallOption) (*QuestionResponse, error)
+	// AnswerQuestion answers a question by replying in its thread.
+	AnswerQuestion(ctx context.Context, in *AnswerQuestionRequest, opts ...grpc.CallOption) (*QuestionResponse, error)
+	// EditMessage replaces the subject or body of a sent message, keeping
+	// the replaced version in its revision history. Recipients are
+	// notified of the edit.
+	EditMessage(ctx context.Context, in *EditMessageRequest, opts ...grpc.CallOption) (*EditMessageResponse, error)
+	// RecallMessage deletes a sent message that no recipient has read yet.
+	RecallMessage(ctx context.Context, in *RecallMessageRequest, opts ...grpc.CallOption) (*RecallMessageResponse, error)
+	// GetMessageHistory returns a message's current version along with
+	// the versions its edits replaced.
+	GetMessageHistory(ctx context.Context, in *GetMessageHistoryRequest, opts ...grpc.CallOption) (*GetMessageHistoryResponse, error)
+	// CreateLabel creates a label the agent can file its messages under.
+	CreateLabel(ctx context.Context, in *CreateLabelRequest, opts ...grpc.CallOption) (*LabelProto, error)
+	// ListLabels lists the agent's labels by name, with their counts.
+	ListLabels(ctx context.Context, in *ListLabelsRequest, opts ...grpc.CallOption) (*ListLabelsResponse, error)
+	// DeleteLabel deletes one of the agent's labels, removing it from the
+	// messages that carry it.
+	DeleteLabel(ctx context.Context, in *DeleteLabelRequest, opts ...grpc.CallOption) (*DeleteLabelResponse, error)
+	// LabelMessage applies and removes labels on the agent's copy of a
+	// message. Labels that don't exist yet are created.
+	LabelMessage(ctx context.Context, in *LabelMessageRequest, opts ...grpc.CallOption) (*LabelMessageResponse, error)
+	// SetThreadMuted mutes or unmutes a thread for the agent. New replies
+	// to a muted thread arrive already read and aren't pushed to it.
+	SetThreadMuted(ctx context.Context, in *SetThreadMutedRequest, opts ...grpc.CallOption) (*ThreadPreferenceProto, error)
+	// SetThreadFollowing follows or unfollows a thread for the agent.
+	// Replies to a followed thread reach it even when it isn't addressed.
+	SetThreadFollowing(ctx context.Context, in *SetThreadFollowingRequest, opts ...grpc.CallOption) (*ThreadPreferenceProto, error)
+	// ListThreadPreferences lists the threads the agent muted or follows.
+	ListThreadPreferences(ctx context.Context, in *ListThreadPreferencesRequest, opts ...grpc.CallOption) (*ListThreadPreferencesResponse, error)
+	// MergeThreads moves every message of one thread into another.
+	MergeThreads(ctx context.Context, in *MergeThreadsRequest, opts ...grpc.CallOption) (*MergeThreadsResponse, error)
+	// SplitThread moves a message and every later message in its thread
+	// into a new thread.
+	SplitThread(ctx context.Context, in *SplitThreadRequest, opts ...grpc.CallOption) (*SplitThreadResponse, error)
 }
 
 type mailClient struct {
@@ -204,6 +359,35 @@ func (c *mailClient) SubscribeInbox(ctx context.Context, in *SubscribeInboxReque
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Mail_SubscribeInboxClient = grpc.ServerStreamingClient[InboxMessage]
 
+func (c *mailClient) WatchInbox(ctx context.Context, in *WatchInboxRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchInboxEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Mail_ServiceDesc.Streams[1], Mail_WatchInbox_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchInboxRequest, WatchInboxEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Mail_WatchInboxClient = grpc.ServerStreamingClient[WatchInboxEvent]
+
+func (c *mailClient) GetWatcher(ctx context.Context, in *GetWatcherRequest, opts ...grpc.CallOption) (*GetWatcherResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetWatcherResponse)
+	err := c.cc.Invoke(ctx, Mail_GetWatcher_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mailClient) Publish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (*PublishResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(PublishResponse)
@@ -234,6 +418,46 @@ func (c *mailClient) Unsubscribe(ctx context.Context, in *UnsubscribeRequest, op
 	return out, nil
 }
 
+func (c *mailClient) ClaimQueueMessage(ctx context.Context, in *ClaimQueueMessageRequest, opts ...grpc.CallOption) (*ClaimQueueMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClaimQueueMessageResponse)
+	err := c.cc.Invoke(ctx, Mail_ClaimQueueMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) AckQueueMessage(ctx context.Context, in *AckQueueMessageRequest, opts ...grpc.CallOption) (*AckQueueMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AckQueueMessageResponse)
+	err := c.cc.Invoke(ctx, Mail_AckQueueMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) ReleaseQueueMessage(ctx context.Context, in *ReleaseQueueMessageRequest, opts ...grpc.CallOption) (*ReleaseQueueMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseQueueMessageResponse)
+	err := c.cc.Invoke(ctx, Mail_ReleaseQueueMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) ExtendQueueLease(ctx context.Context, in *ExtendQueueLeaseRequest, opts ...grpc.CallOption) (*ExtendQueueLeaseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExtendQueueLeaseResponse)
+	err := c.cc.Invoke(ctx, Mail_ExtendQueueLease_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mailClient) ListTopics(ctx context.Context, in *ListTopicsRequest, opts ...grpc.CallOption) (*ListTopicsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListTopicsResponse)
@@ -254,6 +478,66 @@ func (c *mailClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *mailClient) SearchAll(ctx context.Context, in *SearchAllRequest, opts ...grpc.CallOption) (*SearchAllResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchAllResponse)
+	err := c.cc.Invoke(ctx, Mail_SearchAll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) SemanticSearch(ctx context.Context, in *SemanticSearchRequest, opts ...grpc.CallOption) (*SemanticSearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SemanticSearchResponse)
+	err := c.cc.Invoke(ctx, Mail_SemanticSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) SimilarMessages(ctx context.Context, in *SimilarMessagesRequest, opts ...grpc.CallOption) (*SemanticSearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SemanticSearchResponse)
+	err := c.cc.Invoke(ctx, Mail_SimilarMessages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) SaveSearch(ctx context.Context, in *SaveSearchRequest, opts ...grpc.CallOption) (*SavedSearchProto, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SavedSearchProto)
+	err := c.cc.Invoke(ctx, Mail_SaveSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) ListSavedSearches(ctx context.Context, in *ListSavedSearchesRequest, opts ...grpc.CallOption) (*ListSavedSearchesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSavedSearchesResponse)
+	err := c.cc.Invoke(ctx, Mail_ListSavedSearches_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) DeleteSavedSearch(ctx context.Context, in *DeleteSavedSearchRequest, opts ...grpc.CallOption) (*DeleteSavedSearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteSavedSearchResponse)
+	err := c.cc.Invoke(ctx, Mail_DeleteSavedSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mailClient) HasUnackedStatusTo(ctx context.Context, in *HasUnackedStatusToRequest, opts ...grpc.CallOption) (*HasUnackedStatusToResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(HasUnackedStatusToResponse)
@@ -334,110 +618,577 @@ func (c *mailClient) DeleteMessage(ctx context.Context, in *DeleteMessageRequest
 	return out, nil
 }
 
-// MailServer is the server API for Mail service.
-// All implementations must embed UnimplementedMailServer
-// for forward compatibility.
-//
-// Mail is the main RPC service for the Subtrate mail system. It provides
-// methods for sending, receiving, and managing messages between agents.
-type MailServer interface {
-	// SendMail sends a new message to one or more recipients.
-	SendMail(context.Context, *SendMailRequest) (*SendMailResponse, error)
-	// FetchInbox retrieves messages from an agent's inbox.
-	FetchInbox(context.Context, *FetchInboxRequest) (*FetchInboxResponse, error)
-	// ReadMessage retrieves a single message by ID and marks it as read.
-	ReadMessage(context.Context, *ReadMessageRequest) (*ReadMessageResponse, error)
-	// ReadThread retrieves all messages in a thread.
-	ReadThread(context.Context, *ReadThreadRequest) (*ReadThreadResponse, error)
-	// UpdateState changes the state of a message (star, snooze, archive, trash).
-	UpdateState(context.Context, *UpdateStateRequest) (*UpdateStateResponse, error)
-	// AckMessage acknowledges receipt of a message with a deadline.
-	AckMessage(context.Context, *AckMessageRequest) (*AckMessageResponse, error)
-	// GetStatus returns the mail status for an agent.
-	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
-	// PollChanges checks for new messages since given offsets.
-	PollChanges(context.Context, *PollChangesRequest) (*PollChangesResponse, error)
-	// SubscribeInbox creates a stream of new inbox messages.
-	SubscribeInbox(*SubscribeInboxRequest, grpc.ServerStreamingServer[InboxMessage]) error
-	// Publish sends a message to a pub/sub topic.
-	Publish(context.Context, *PublishRequest) (*PublishResponse, error)
-	// Subscribe subscribes an agent to a topic.
-	Subscribe(context.Context, *SubscribeRequest) (*SubscribeResponse, error)
-	// Unsubscribe removes an agent's subscription to a topic.
-	Unsubscribe(context.Context, *UnsubscribeRequest) (*UnsubscribeResponse, error)
-	// ListTopics lists available topics.
-	ListTopics(context.Context, *ListTopicsRequest) (*ListTopicsResponse, error)
-	// Search performs full-text search across messages.
-	Search(context.Context, *SearchRequest) (*SearchResponse, error)
-	// HasUnackedStatusTo checks if there are unacked status messages from
-	// sender to recipient. Used for deduplication in status-update command.
-	HasUnackedStatusTo(context.Context, *HasUnackedStatusToRequest) (*HasUnackedStatusToResponse, error)
-	// ReplyToThread sends a reply message to an existing thread.
-	ReplyToThread(context.Context, *ReplyToThreadRequest) (*ReplyToThreadResponse, error)
-	// ArchiveThread archives all messages in a thread.
-	ArchiveThread(context.Context, *ArchiveThreadRequest) (*ArchiveThreadResponse, error)
-	// DeleteThread deletes all messages in a thread.
-	DeleteThread(context.Context, *DeleteThreadRequest) (*DeleteThreadResponse, error)
-	// MarkThreadUnread marks a thread as unread.
-	MarkThreadUnread(context.Context, *MarkThreadUnreadRequest) (*MarkThreadUnreadResponse, error)
-	// GetTopic retrieves a single topic by ID.
-	GetTopic(context.Context, *GetTopicRequest) (*GetTopicResponse, error)
-	// AutocompleteRecipients returns matching agents for autocomplete.
-	AutocompleteRecipients(context.Context, *AutocompleteRecipientsRequest) (*AutocompleteRecipientsResponse, error)
-	// DeleteMessage marks a message as deleted/trash.
-	DeleteMessage(context.Context, *DeleteMessageRequest) (*DeleteMessageResponse, error)
-	mustEmbedUnimplementedMailServer()
+func (c *mailClient) CollectGarbage(ctx context.Context, in *CollectGarbageRequest, opts ...grpc.CallOption) (*CollectGarbageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CollectGarbageResponse)
+	err := c.cc.Invoke(ctx, Mail_CollectGarbage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// UnimplementedMailServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedMailServer struct{}
-
-func (UnimplementedMailServer) SendMail(context.Context, *SendMailRequest) (*SendMailResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SendMail not implemented")
-}
-func (UnimplementedMailServer) FetchInbox(context.Context, *FetchInboxRequest) (*FetchInboxResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method FetchInbox not implemented")
+func (c *mailClient) SetLegalHold(ctx context.Context, in *SetLegalHoldRequest, opts ...grpc.CallOption) (*SetLegalHoldResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetLegalHoldResponse)
+	err := c.cc.Invoke(ctx, Mail_SetLegalHold_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMailServer) ReadMessage(context.Context, *ReadMessageRequest) (*ReadMessageResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ReadMessage not implemented")
+
+func (c *mailClient) SetTopicRetention(ctx context.Context, in *SetTopicRetentionRequest, opts ...grpc.CallOption) (*SetTopicRetentionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetTopicRetentionResponse)
+	err := c.cc.Invoke(ctx, Mail_SetTopicRetention_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMailServer) ReadThread(context.Context, *ReadThreadRequest) (*ReadThreadResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ReadThread not implemented")
+
+func (c *mailClient) CreateRecipientGroup(ctx context.Context, in *CreateRecipientGroupRequest, opts ...grpc.CallOption) (*RecipientGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecipientGroupResponse)
+	err := c.cc.Invoke(ctx, Mail_CreateRecipientGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMailServer) UpdateState(context.Context, *UpdateStateRequest) (*UpdateStateResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateState not implemented")
+
+func (c *mailClient) GetRecipientGroup(ctx context.Context, in *GetRecipientGroupRequest, opts ...grpc.CallOption) (*RecipientGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecipientGroupResponse)
+	err := c.cc.Invoke(ctx, Mail_GetRecipientGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMailServer) AckMessage(context.Context, *AckMessageRequest) (*AckMessageResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AckMessage not implemented")
+
+func (c *mailClient) ListRecipientGroups(ctx context.Context, in *ListRecipientGroupsRequest, opts ...grpc.CallOption) (*ListRecipientGroupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRecipientGroupsResponse)
+	err := c.cc.Invoke(ctx, Mail_ListRecipientGroups_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMailServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+
+func (c *mailClient) DeleteRecipientGroup(ctx context.Context, in *DeleteRecipientGroupRequest, opts ...grpc.CallOption) (*DeleteRecipientGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteRecipientGroupResponse)
+	err := c.cc.Invoke(ctx, Mail_DeleteRecipientGroup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMailServer) PollChanges(context.Context, *PollChangesRequest) (*PollChangesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PollChanges not implemented")
+
+func (c *mailClient) AddRecipientGroupMembers(ctx context.Context, in *RecipientGroupMembersRequest, opts ...grpc.CallOption) (*RecipientGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecipientGroupResponse)
+	err := c.cc.Invoke(ctx, Mail_AddRecipientGroupMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMailServer) SubscribeInbox(*SubscribeInboxRequest, grpc.ServerStreamingServer[InboxMessage]) error {
-	return status.Errorf(codes.Unimplemented, "method SubscribeInbox not implemented")
+
+func (c *mailClient) RemoveRecipientGroupMembers(ctx context.Context, in *RecipientGroupMembersRequest, opts ...grpc.CallOption) (*RecipientGroupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecipientGroupResponse)
+	err := c.cc.Invoke(ctx, Mail_RemoveRecipientGroupMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMailServer) Publish(context.Context, *PublishRequest) (*PublishResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Publish not implemented")
+
+func (c *mailClient) ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDeadLettersResponse)
+	err := c.cc.Invoke(ctx, Mail_ListDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMailServer) Subscribe(context.Context, *SubscribeRequest) (*SubscribeResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+
+func (c *mailClient) DeleteDeadLetter(ctx context.Context, in *DeleteDeadLetterRequest, opts ...grpc.CallOption) (*DeleteDeadLetterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteDeadLetterResponse)
+	err := c.cc.Invoke(ctx, Mail_DeleteDeadLetter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMailServer) Unsubscribe(context.Context, *UnsubscribeRequest) (*UnsubscribeResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Unsubscribe not implemented")
+
+func (c *mailClient) ListScheduledMessages(ctx context.Context, in *ListScheduledMessagesRequest, opts ...grpc.CallOption) (*ListScheduledMessagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListScheduledMessagesResponse)
+	err := c.cc.Invoke(ctx, Mail_ListScheduledMessages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMailServer) ListTopics(context.Context, *ListTopicsRequest) (*ListTopicsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListTopics not implemented")
+
+func (c *mailClient) CancelScheduledMessage(ctx context.Context, in *CancelScheduledMessageRequest, opts ...grpc.CallOption) (*CancelScheduledMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelScheduledMessageResponse)
+	err := c.cc.Invoke(ctx, Mail_CancelScheduledMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
-func (UnimplementedMailServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+
+func (c *mailClient) CreateSchedule(ctx context.Context, in *CreateScheduleRequest, opts ...grpc.CallOption) (*ScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScheduleResponse)
+	err := c.cc.Invoke(ctx, Mail_CreateSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) ListSchedules(ctx context.Context, in *ListSchedulesRequest, opts ...grpc.CallOption) (*ListSchedulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSchedulesResponse)
+	err := c.cc.Invoke(ctx, Mail_ListSchedules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) DeleteSchedule(ctx context.Context, in *DeleteScheduleRequest, opts ...grpc.CallOption) (*DeleteScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteScheduleResponse)
+	err := c.cc.Invoke(ctx, Mail_DeleteSchedule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) SetSchedulePaused(ctx context.Context, in *SetSchedulePausedRequest, opts ...grpc.CallOption) (*ScheduleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScheduleResponse)
+	err := c.cc.Invoke(ctx, Mail_SetSchedulePaused_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) AskQuestion(ctx context.Context, in *AskQuestionRequest, opts ...grpc.CallOption) (*QuestionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuestionResponse)
+	err := c.cc.Invoke(ctx, Mail_AskQuestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) GetQuestion(ctx context.Context, in *GetQuestionRequest, opts ...grpc.CallOption) (*QuestionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuestionResponse)
+	err := c.cc.Invoke(ctx, Mail_GetQuestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) AnswerQuestion(ctx context.Context, in *AnswerQuestionRequest, opts ...grpc.CallOption) (*QuestionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuestionResponse)
+	err := c.cc.Invoke(ctx, Mail_AnswerQuestion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) EditMessage(ctx context.Context, in *EditMessageRequest, opts ...grpc.CallOption) (*EditMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EditMessageResponse)
+	err := c.cc.Invoke(ctx, Mail_EditMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) RecallMessage(ctx context.Context, in *RecallMessageRequest, opts ...grpc.CallOption) (*RecallMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecallMessageResponse)
+	err := c.cc.Invoke(ctx, Mail_RecallMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) GetMessageHistory(ctx context.Context, in *GetMessageHistoryRequest, opts ...grpc.CallOption) (*GetMessageHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMessageHistoryResponse)
+	err := c.cc.Invoke(ctx, Mail_GetMessageHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) CreateLabel(ctx context.Context, in *CreateLabelRequest, opts ...grpc.CallOption) (*LabelProto, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LabelProto)
+	err := c.cc.Invoke(ctx, Mail_CreateLabel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) ListLabels(ctx context.Context, in *ListLabelsRequest, opts ...grpc.CallOption) (*ListLabelsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLabelsResponse)
+	err := c.cc.Invoke(ctx, Mail_ListLabels_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) DeleteLabel(ctx context.Context, in *DeleteLabelRequest, opts ...grpc.CallOption) (*DeleteLabelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteLabelResponse)
+	err := c.cc.Invoke(ctx, Mail_DeleteLabel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) LabelMessage(ctx context.Context, in *LabelMessageRequest, opts ...grpc.CallOption) (*LabelMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LabelMessageResponse)
+	err := c.cc.Invoke(ctx, Mail_LabelMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) SetThreadMuted(ctx context.Context, in *SetThreadMutedRequest, opts ...grpc.CallOption) (*ThreadPreferenceProto, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ThreadPreferenceProto)
+	err := c.cc.Invoke(ctx, Mail_SetThreadMuted_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) SetThreadFollowing(ctx context.Context, in *SetThreadFollowingRequest, opts ...grpc.CallOption) (*ThreadPreferenceProto, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ThreadPreferenceProto)
+	err := c.cc.Invoke(ctx, Mail_SetThreadFollowing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) ListThreadPreferences(ctx context.Context, in *ListThreadPreferencesRequest, opts ...grpc.CallOption) (*ListThreadPreferencesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListThreadPreferencesResponse)
+	err := c.cc.Invoke(ctx, Mail_ListThreadPreferences_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) MergeThreads(ctx context.Context, in *MergeThreadsRequest, opts ...grpc.CallOption) (*MergeThreadsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MergeThreadsResponse)
+	err := c.cc.Invoke(ctx, Mail_MergeThreads_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mailClient) SplitThread(ctx context.Context, in *SplitThreadRequest, opts ...grpc.CallOption) (*SplitThreadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SplitThreadResponse)
+	err := c.cc.Invoke(ctx, Mail_SplitThread_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MailServer is the server API for Mail service.
+// All implementations must embed UnimplementedMailServer
+// for forward compatibility.
+//
+// Mail is the main RPC service for the Subtrate mail system. It provides
+// methods for sending, receiving, and managing messages between agents.
+type MailServer interface {
+	// SendMail sends a new message to one or more recipients.
+	SendMail(context.Context, *SendMailRequest) (*SendMailResponse, error)
+	// FetchInbox retrieves messages from an agent's inbox.
+	FetchInbox(context.Context, *FetchInboxRequest) (*FetchInboxResponse, error)
+	// ReadMessage retrieves a single message by ID and marks it as read.
+	ReadMessage(context.Context, *ReadMessageRequest) (*ReadMessageResponse, error)
+	// ReadThread retrieves all messages in a thread.
+	ReadThread(context.Context, *ReadThreadRequest) (*ReadThreadResponse, error)
+	// UpdateState changes the state of a message (star, snooze, archive, trash).
+	UpdateState(context.Context, *UpdateStateRequest) (*UpdateStateResponse, error)
+	// AckMessage acknowledges receipt of a message with a deadline.
+	AckMessage(context.Context, *AckMessageRequest) (*AckMessageResponse, error)
+	// GetStatus returns the mail status for an agent.
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	// PollChanges checks for new messages since given offsets.
+	PollChanges(context.Context, *PollChangesRequest) (*PollChangesResponse, error)
+	// SubscribeInbox creates a stream of new inbox messages.
+	SubscribeInbox(*SubscribeInboxRequest, grpc.ServerStreamingServer[InboxMessage]) error
+	// WatchInbox arms a watcher on an agent's inbox, taking its watcher
+	// lease, and streams the mail that wakes it. Unread backlog newer
+	// than after_message_id is sent straight away; mail pushed again,
+	// such as a message whose snooze ended, wakes it however old.
+	WatchInbox(*WatchInboxRequest, grpc.ServerStreamingServer[WatchInboxEvent]) error
+	// GetWatcher reports the watcher holding an agent's watcher lease.
+	GetWatcher(context.Context, *GetWatcherRequest) (*GetWatcherResponse, error)
+	// Publish sends a message to a pub/sub topic.
+	Publish(context.Context, *PublishRequest) (*PublishResponse, error)
+	// Subscribe subscribes an agent to a topic.
+	Subscribe(context.Context, *SubscribeRequest) (*SubscribeResponse, error)
+	// Unsubscribe removes an agent's subscription to a topic.
+	Unsubscribe(context.Context, *UnsubscribeRequest) (*UnsubscribeResponse, error)
+	// ClaimQueueMessage leases the next available message on a queue topic
+	// to a single subscriber.
+	ClaimQueueMessage(context.Context, *ClaimQueueMessageRequest) (*ClaimQueueMessageResponse, error)
+	// AckQueueMessage completes a lease so the message is never redelivered.
+	AckQueueMessage(context.Context, *AckQueueMessageRequest) (*AckQueueMessageResponse, error)
+	// ReleaseQueueMessage hands a leased message back to the queue.
+	ReleaseQueueMessage(context.Context, *ReleaseQueueMessageRequest) (*ReleaseQueueMessageResponse, error)
+	// ExtendQueueLease pushes out the expiry of a lease.
+	ExtendQueueLease(context.Context, *ExtendQueueLeaseRequest) (*ExtendQueueLeaseResponse, error)
+	// ListTopics lists available topics.
+	ListTopics(context.Context, *ListTopicsRequest) (*ListTopicsResponse, error)
+	// Search performs full-text search across messages.
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	// SearchAll performs full-text search across messages, review issues,
+	// plan reviews, tasks and annotations, returning typed, ranked hits.
+	SearchAll(context.Context, *SearchAllRequest) (*SearchAllResponse, error)
+	// SemanticSearch finds the messages closest in meaning to a query, or
+	// in hybrid mode merges them with the query's BM25 matches.
+	SemanticSearch(context.Context, *SemanticSearchRequest) (*SemanticSearchResponse, error)
+	// SimilarMessages finds the nearest neighbours of a message or a text.
+	SimilarMessages(context.Context, *SimilarMessagesRequest) (*SemanticSearchResponse, error)
+	// SaveSearch saves a search query under a name, replacing the query of
+	// a search the agent already saved under it. Run it with Search.
+	SaveSearch(context.Context, *SaveSearchRequest) (*SavedSearchProto, error)
+	// ListSavedSearches lists the agent's saved searches by name.
+	ListSavedSearches(context.Context, *ListSavedSearchesRequest) (*ListSavedSearchesResponse, error)
+	// DeleteSavedSearch deletes one of the agent's saved searches.
+	DeleteSavedSearch(context.Context, *DeleteSavedSearchRequest) (*DeleteSavedSearchResponse, error)
+	// HasUnackedStatusTo checks if there are unacked status messages from
+	// sender to recipient. Used for deduplication in status-update command.
+	HasUnackedStatusTo(context.Context, *HasUnackedStatusToRequest) (*HasUnackedStatusToResponse, error)
+	// ReplyToThread sends a reply message to an existing thread.
+	ReplyToThread(context.Context, *ReplyToThreadRequest) (*ReplyToThreadResponse, error)
+	// ArchiveThread archives all messages in a thread.
+	ArchiveThread(context.Context, *ArchiveThreadRequest) (*ArchiveThreadResponse, error)
+	// DeleteThread deletes all messages in a thread.
+	DeleteThread(context.Context, *DeleteThreadRequest) (*DeleteThreadResponse, error)
+	// MarkThreadUnread marks a thread as unread.
+	MarkThreadUnread(context.Context, *MarkThreadUnreadRequest) (*MarkThreadUnreadResponse, error)
+	// GetTopic retrieves a single topic by ID.
+	GetTopic(context.Context, *GetTopicRequest) (*GetTopicResponse, error)
+	// AutocompleteRecipients returns matching agents for autocomplete.
+	AutocompleteRecipients(context.Context, *AutocompleteRecipientsRequest) (*AutocompleteRecipientsResponse, error)
+	// DeleteMessage marks a message as deleted/trash.
+	DeleteMessage(context.Context, *DeleteMessageRequest) (*DeleteMessageResponse, error)
+	// CollectGarbage enforces topic retention, deleting expired messages and
+	// stale consumer offsets, or reports what would be deleted on a dry run.
+	CollectGarbage(context.Context, *CollectGarbageRequest) (*CollectGarbageResponse, error)
+	// SetLegalHold places or lifts a legal hold on a message or a whole
+	// thread, exempting it from retention.
+	SetLegalHold(context.Context, *SetLegalHoldRequest) (*SetLegalHoldResponse, error)
+	// SetTopicRetention sets how long a topic's messages are kept before
+	// retention deletes them.
+	SetTopicRetention(context.Context, *SetTopicRetentionRequest) (*SetTopicRetentionResponse, error)
+	// CreateRecipientGroup creates a named group of agents that can be
+	// addressed as "@<name>".
+	CreateRecipientGroup(context.Context, *CreateRecipientGroupRequest) (*RecipientGroupResponse, error)
+	// GetRecipientGroup returns a recipient group and its members.
+	GetRecipientGroup(context.Context, *GetRecipientGroupRequest) (*RecipientGroupResponse, error)
+	// ListRecipientGroups lists all recipient groups.
+	ListRecipientGroups(context.Context, *ListRecipientGroupsRequest) (*ListRecipientGroupsResponse, error)
+	// DeleteRecipientGroup deletes a recipient group.
+	DeleteRecipientGroup(context.Context, *DeleteRecipientGroupRequest) (*DeleteRecipientGroupResponse, error)
+	// AddRecipientGroupMembers adds agents to a recipient group.
+	AddRecipientGroupMembers(context.Context, *RecipientGroupMembersRequest) (*RecipientGroupResponse, error)
+	// RemoveRecipientGroupMembers removes agents from a recipient group.
+	RemoveRecipientGroupMembers(context.Context, *RecipientGroupMembersRequest) (*RecipientGroupResponse, error)
+	// ListDeadLetters lists the sender's undeliverable recipients, newest
+	// first.
+	ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error)
+	// DeleteDeadLetter removes an entry from the sender's dead-letter mailbox.
+	DeleteDeadLetter(context.Context, *DeleteDeadLetterRequest) (*DeleteDeadLetterResponse, error)
+	// ListScheduledMessages lists the sender's scheduled messages that have
+	// not been sent or canceled, soonest first.
+	ListScheduledMessages(context.Context, *ListScheduledMessagesRequest) (*ListScheduledMessagesResponse, error)
+	// CancelScheduledMessage cancels a scheduled message before it is sent.
+	CancelScheduledMessage(context.Context, *CancelScheduledMessageRequest) (*CancelScheduledMessageResponse, error)
+	// CreateSchedule creates a recurring message sent on a cron schedule.
+	CreateSchedule(context.Context, *CreateScheduleRequest) (*ScheduleResponse, error)
+	// ListSchedules lists the agent's recurring schedules by name.
+	ListSchedules(context.Context, *ListSchedulesRequest) (*ListSchedulesResponse, error)
+	// DeleteSchedule deletes one of the agent's recurring schedules.
+	DeleteSchedule(context.Context, *DeleteScheduleRequest) (*DeleteScheduleResponse, error)
+	// SetSchedulePaused pauses or resumes one of the agent's recurring
+	// schedules.
+	SetSchedulePaused(context.Context, *SetSchedulePausedRequest) (*ScheduleResponse, error)
+	// AskQuestion sends a structured question. The first reply in its
+	// thread from one of the agents asked is recorded as the answer.
+	AskQuestion(context.Context, *AskQuestionRequest) (*QuestionResponse, error)
+	// GetQuestion returns a question and, once resolved, its answer.
+	GetQuestion(context.Context, *GetQuestionRequest) (*QuestionResponse, error)
+	// AnswerQuestion answers a question by replying in its thread.
+	AnswerQuestion(context.Context, *AnswerQuestionRequest) (*QuestionResponse, error)
+	// EditMessage replaces the subject or body of a sent message, keeping
+	// the replaced version in its revision history. Recipients are
+	// notified of the edit.
+	EditMessage(context.Context, *EditMessageRequest) (*EditMessageResponse, error)
+	// RecallMessage deletes a sent message that no recipient has read yet.
+	RecallMessage(context.Context, *RecallMessageRequest) (*RecallMessageResponse, error)
+	// GetMessageHistory returns a message's current version along with
+	// the versions its edits replaced.
+	GetMessageHistory(context.Context, *GetMessageHistoryRequest) (*GetMessageHistoryResponse, error)
+	// CreateLabel creates a label the agent can file its messages under.
+	CreateLabel(context.Context, *CreateLabelRequest) (*LabelProto, error)
+	// ListLabels lists the agent's labels by name, with their counts.
+	ListLabels(context.Context, *ListLabelsRequest) (*ListLabelsResponse, error)
+	// DeleteLabel deletes one of the agent's labels, removing it from the
+	// messages that carry it.
+	DeleteLabel(context.Context, *DeleteLabelRequest) (*DeleteLabelResponse, error)
+	// LabelMessage applies and removes labels on the agent's copy of a
+	// message. Labels that don't exist yet are created.
+	LabelMessage(context.Context, *LabelMessageRequest) (*LabelMessageResponse, error)
+	// SetThreadMuted mutes or unmutes a thread for the agent. New replies
+	// to a muted thread arrive already read and aren't pushed to it.
+	SetThreadMuted(context.Context, *SetThreadMutedRequest) (*ThreadPreferenceProto, error)
+	// SetThreadFollowing follows or unfollows a thread for the agent.
+	// Replies to a followed thread reach it even when it isn't addressed.
+	SetThreadFollowing(context.Context, *SetThreadFollowingRequest) (*ThreadPreferenceProto, error)
+	// ListThreadPreferences lists the threads the agent muted or follows.
+	ListThreadPreferences(context.Context, *ListThreadPreferencesRequest) (*ListThreadPreferencesResponse, error)
+	// MergeThreads moves every message of one thread into another.
+	MergeThreads(context.Context, *MergeThreadsRequest) (*MergeThreadsResponse, error)
+	// SplitThread moves a message and every later message in its thread
+	// into a new thread.
+	SplitThread(context.Context, *SplitThreadRequest) (*SplitThreadResponse, error)
+	mustEmbedUnimplementedMailServer()
+}
+
+// UnimplementedMailServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMailServer struct{}
+
+func (UnimplementedMailServer) SendMail(context.Context, *SendMailRequest) (*SendMailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendMail not implemented")
+}
+func (UnimplementedMailServer) FetchInbox(context.Context, *FetchInboxRequest) (*FetchInboxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchInbox not implemented")
+}
+func (UnimplementedMailServer) ReadMessage(context.Context, *ReadMessageRequest) (*ReadMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadMessage not implemented")
+}
+func (UnimplementedMailServer) ReadThread(context.Context, *ReadThreadRequest) (*ReadThreadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadThread not implemented")
+}
+func (UnimplementedMailServer) UpdateState(context.Context, *UpdateStateRequest) (*UpdateStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateState not implemented")
+}
+func (UnimplementedMailServer) AckMessage(context.Context, *AckMessageRequest) (*AckMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AckMessage not implemented")
+}
+func (UnimplementedMailServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedMailServer) PollChanges(context.Context, *PollChangesRequest) (*PollChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PollChanges not implemented")
+}
+func (UnimplementedMailServer) SubscribeInbox(*SubscribeInboxRequest, grpc.ServerStreamingServer[InboxMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeInbox not implemented")
+}
+func (UnimplementedMailServer) WatchInbox(*WatchInboxRequest, grpc.ServerStreamingServer[WatchInboxEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchInbox not implemented")
+}
+func (UnimplementedMailServer) GetWatcher(context.Context, *GetWatcherRequest) (*GetWatcherResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWatcher not implemented")
+}
+func (UnimplementedMailServer) Publish(context.Context, *PublishRequest) (*PublishResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Publish not implemented")
+}
+func (UnimplementedMailServer) Subscribe(context.Context, *SubscribeRequest) (*SubscribeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedMailServer) Unsubscribe(context.Context, *UnsubscribeRequest) (*UnsubscribeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unsubscribe not implemented")
+}
+func (UnimplementedMailServer) ClaimQueueMessage(context.Context, *ClaimQueueMessageRequest) (*ClaimQueueMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClaimQueueMessage not implemented")
+}
+func (UnimplementedMailServer) AckQueueMessage(context.Context, *AckQueueMessageRequest) (*AckQueueMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AckQueueMessage not implemented")
+}
+func (UnimplementedMailServer) ReleaseQueueMessage(context.Context, *ReleaseQueueMessageRequest) (*ReleaseQueueMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseQueueMessage not implemented")
+}
+func (UnimplementedMailServer) ExtendQueueLease(context.Context, *ExtendQueueLeaseRequest) (*ExtendQueueLeaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtendQueueLease not implemented")
+}
+func (UnimplementedMailServer) ListTopics(context.Context, *ListTopicsRequest) (*ListTopicsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTopics not implemented")
+}
+func (UnimplementedMailServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
 }
+func (UnimplementedMailServer) SearchAll(context.Context, *SearchAllRequest) (*SearchAllResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchAll not implemented")
+}
+func (UnimplementedMailServer) SemanticSearch(context.Context, *SemanticSearchRequest) (*SemanticSearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SemanticSearch not implemented")
+}
+func (UnimplementedMailServer) SimilarMessages(context.Context, *SimilarMessagesRequest) (*SemanticSearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SimilarMessages not implemented")
+}
+func (UnimplementedMailServer) SaveSearch(context.Context, *SaveSearchRequest) (*SavedSearchProto, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveSearch not implemented")
+}
+func (UnimplementedMailServer) ListSavedSearches(context.Context, *ListSavedSearchesRequest) (*ListSavedSearchesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSavedSearches not implemented")
+}
+func (UnimplementedMailServer) DeleteSavedSearch(context.Context, *DeleteSavedSearchRequest) (*DeleteSavedSearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSavedSearch not implemented")
+}
 func (UnimplementedMailServer) HasUnackedStatusTo(context.Context, *HasUnackedStatusToRequest) (*HasUnackedStatusToResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HasUnackedStatusTo not implemented")
 }
@@ -462,412 +1213,1293 @@ func (UnimplementedMailServer) AutocompleteRecipients(context.Context, *Autocomp
 func (UnimplementedMailServer) DeleteMessage(context.Context, *DeleteMessageRequest) (*DeleteMessageResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteMessage not implemented")
 }
-func (UnimplementedMailServer) mustEmbedUnimplementedMailServer() {}
-func (UnimplementedMailServer) testEmbeddedByValue()              {}
-
-// UnsafeMailServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to MailServer will
-// result in compilation errors.
-type UnsafeMailServer interface {
-	mustEmbedUnimplementedMailServer()
+func (UnimplementedMailServer) CollectGarbage(context.Context, *CollectGarbageRequest) (*CollectGarbageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CollectGarbage not implemented")
+}
+func (UnimplementedMailServer) SetLegalHold(context.Context, *SetLegalHoldRequest) (*SetLegalHoldResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLegalHold not implemented")
+}
+func (UnimplementedMailServer) SetTopicRetention(context.Context, *SetTopicRetentionRequest) (*SetTopicRetentionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTopicRetention not implemented")
+}
+func (UnimplementedMailServer) CreateRecipientGroup(context.Context, *CreateRecipientGroupRequest) (*RecipientGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRecipientGroup not implemented")
+}
+func (UnimplementedMailServer) GetRecipientGroup(context.Context, *GetRecipientGroupRequest) (*RecipientGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecipientGroup not implemented")
+}
+func (UnimplementedMailServer) ListRecipientGroups(context.Context, *ListRecipientGroupsRequest) (*ListRecipientGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRecipientGroups not implemented")
+}
+func (UnimplementedMailServer) DeleteRecipientGroup(context.Context, *DeleteRecipientGroupRequest) (*DeleteRecipientGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRecipientGroup not implemented")
+}
+func (UnimplementedMailServer) AddRecipientGroupMembers(context.Context, *RecipientGroupMembersRequest) (*RecipientGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRecipientGroupMembers not implemented")
+}
+func (UnimplementedMailServer) RemoveRecipientGroupMembers(context.Context, *RecipientGroupMembersRequest) (*RecipientGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveRecipientGroupMembers not implemented")
+}
+func (UnimplementedMailServer) ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeadLetters not implemented")
+}
+func (UnimplementedMailServer) DeleteDeadLetter(context.Context, *DeleteDeadLetterRequest) (*DeleteDeadLetterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDeadLetter not implemented")
+}
+func (UnimplementedMailServer) ListScheduledMessages(context.Context, *ListScheduledMessagesRequest) (*ListScheduledMessagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScheduledMessages not implemented")
+}
+func (UnimplementedMailServer) CancelScheduledMessage(context.Context, *CancelScheduledMessageRequest) (*CancelScheduledMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelScheduledMessage not implemented")
+}
+func (UnimplementedMailServer) CreateSchedule(context.Context, *CreateScheduleRequest) (*ScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSchedule not implemented")
+}
+func (UnimplementedMailServer) ListSchedules(context.Context, *ListSchedulesRequest) (*ListSchedulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSchedules not implemented")
+}
+func (UnimplementedMailServer) DeleteSchedule(context.Context, *DeleteScheduleRequest) (*DeleteScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSchedule not implemented")
+}
+func (UnimplementedMailServer) SetSchedulePaused(context.Context, *SetSchedulePausedRequest) (*ScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSchedulePaused not implemented")
+}
+func (UnimplementedMailServer) AskQuestion(context.Context, *AskQuestionRequest) (*QuestionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AskQuestion not implemented")
+}
+func (UnimplementedMailServer) GetQuestion(context.Context, *GetQuestionRequest) (*QuestionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuestion not implemented")
+}
+func (UnimplementedMailServer) AnswerQuestion(context.Context, *AnswerQuestionRequest) (*QuestionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnswerQuestion not implemented")
+}
+func (UnimplementedMailServer) EditMessage(context.Context, *EditMessageRequest) (*EditMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditMessage not implemented")
+}
+func (UnimplementedMailServer) RecallMessage(context.Context, *RecallMessageRequest) (*RecallMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecallMessage not implemented")
+}
+func (UnimplementedMailServer) GetMessageHistory(context.Context, *GetMessageHistoryRequest) (*GetMessageHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMessageHistory not implemented")
+}
+func (UnimplementedMailServer) CreateLabel(context.Context, *CreateLabelRequest) (*LabelProto, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateLabel not implemented")
+}
+func (UnimplementedMailServer) ListLabels(context.Context, *ListLabelsRequest) (*ListLabelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLabels not implemented")
+}
+func (UnimplementedMailServer) DeleteLabel(context.Context, *DeleteLabelRequest) (*DeleteLabelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteLabel not implemented")
+}
+func (UnimplementedMailServer) LabelMessage(context.Context, *LabelMessageRequest) (*LabelMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LabelMessage not implemented")
+}
+func (UnimplementedMailServer) SetThreadMuted(context.Context, *SetThreadMutedRequest) (*ThreadPreferenceProto, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetThreadMuted not implemented")
+}
+func (UnimplementedMailServer) SetThreadFollowing(context.Context, *SetThreadFollowingRequest) (*ThreadPreferenceProto, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetThreadFollowing not implemented")
+}
+func (UnimplementedMailServer) ListThreadPreferences(context.Context, *ListThreadPreferencesRequest) (*ListThreadPreferencesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListThreadPreferences not implemented")
+}
+func (UnimplementedMailServer) MergeThreads(context.Context, *MergeThreadsRequest) (*MergeThreadsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeThreads not implemented")
+}
+func (UnimplementedMailServer) SplitThread(context.Context, *SplitThreadRequest) (*SplitThreadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SplitThread not implemented")
+}
+func (UnimplementedMailServer) mustEmbedUnimplementedMailServer() {}
+func (UnimplementedMailServer) testEmbeddedByValue()              {}
+
+// UnsafeMailServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MailServer will
+// result in compilation errors.
+type UnsafeMailServer interface {
+	mustEmbedUnimplementedMailServer()
+}
+
+func RegisterMailServer(s grpc.ServiceRegistrar, srv MailServer) {
+	// If the following call pancis, it indicates UnimplementedMailServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Mail_ServiceDesc, srv)
+}
+
+func _Mail_SendMail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendMailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).SendMail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_SendMail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).SendMail(ctx, req.(*SendMailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_FetchInbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchInboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).FetchInbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_FetchInbox_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).FetchInbox(ctx, req.(*FetchInboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_ReadMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).ReadMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_ReadMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).ReadMessage(ctx, req.(*ReadMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_ReadThread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadThreadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).ReadThread(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_ReadThread_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).ReadThread(ctx, req.(*ReadThreadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_UpdateState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).UpdateState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_UpdateState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).UpdateState(ctx, req.(*UpdateStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_AckMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).AckMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_AckMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).AckMessage(ctx, req.(*AckMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_PollChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PollChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).PollChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_PollChanges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).PollChanges(ctx, req.(*PollChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_SubscribeInbox_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeInboxRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MailServer).SubscribeInbox(m, &grpc.GenericServerStream[SubscribeInboxRequest, InboxMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Mail_SubscribeInboxServer = grpc.ServerStreamingServer[InboxMessage]
+
+func _Mail_WatchInbox_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchInboxRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MailServer).WatchInbox(m, &grpc.GenericServerStream[WatchInboxRequest, WatchInboxEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Mail_WatchInboxServer = grpc.ServerStreamingServer[WatchInboxEvent]
+
+func _Mail_GetWatcher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWatcherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).GetWatcher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_GetWatcher_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).GetWatcher(ctx, req.(*GetWatcherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_Publish_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).Publish(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_Publish_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).Publish(ctx, req.(*PublishRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_Subscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubscribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).Subscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_Subscribe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).Subscribe(ctx, req.(*SubscribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_Unsubscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnsubscribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).Unsubscribe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_Unsubscribe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).Unsubscribe(ctx, req.(*UnsubscribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_ClaimQueueMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClaimQueueMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).ClaimQueueMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_ClaimQueueMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).ClaimQueueMessage(ctx, req.(*ClaimQueueMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_AckQueueMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckQueueMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).AckQueueMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_AckQueueMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).AckQueueMessage(ctx, req.(*AckQueueMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_ReleaseQueueMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseQueueMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).ReleaseQueueMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_ReleaseQueueMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).ReleaseQueueMessage(ctx, req.(*ReleaseQueueMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_ExtendQueueLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtendQueueLeaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).ExtendQueueLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_ExtendQueueLease_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).ExtendQueueLease(ctx, req.(*ExtendQueueLeaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_ListTopics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTopicsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).ListTopics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_ListTopics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).ListTopics(ctx, req.(*ListTopicsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_SearchAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).SearchAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_SearchAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).SearchAll(ctx, req.(*SearchAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_SemanticSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SemanticSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).SemanticSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_SemanticSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).SemanticSearch(ctx, req.(*SemanticSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_SimilarMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimilarMessagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).SimilarMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_SimilarMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).SimilarMessages(ctx, req.(*SimilarMessagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_SaveSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).SaveSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_SaveSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).SaveSearch(ctx, req.(*SaveSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_ListSavedSearches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSavedSearchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).ListSavedSearches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_ListSavedSearches_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).ListSavedSearches(ctx, req.(*ListSavedSearchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_DeleteSavedSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSavedSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).DeleteSavedSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_DeleteSavedSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).DeleteSavedSearch(ctx, req.(*DeleteSavedSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_HasUnackedStatusTo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HasUnackedStatusToRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).HasUnackedStatusTo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_HasUnackedStatusTo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).HasUnackedStatusTo(ctx, req.(*HasUnackedStatusToRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_ReplyToThread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplyToThreadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).ReplyToThread(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_ReplyToThread_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).ReplyToThread(ctx, req.(*ReplyToThreadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_ArchiveThread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveThreadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).ArchiveThread(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_ArchiveThread_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).ArchiveThread(ctx, req.(*ArchiveThreadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_DeleteThread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteThreadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).DeleteThread(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_DeleteThread_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).DeleteThread(ctx, req.(*DeleteThreadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_MarkThreadUnread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkThreadUnreadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).MarkThreadUnread(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_MarkThreadUnread_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).MarkThreadUnread(ctx, req.(*MarkThreadUnreadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_GetTopic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTopicRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).GetTopic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_GetTopic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).GetTopic(ctx, req.(*GetTopicRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_AutocompleteRecipients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AutocompleteRecipientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).AutocompleteRecipients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_AutocompleteRecipients_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).AutocompleteRecipients(ctx, req.(*AutocompleteRecipientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_DeleteMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).DeleteMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_DeleteMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).DeleteMessage(ctx, req.(*DeleteMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_CollectGarbage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectGarbageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).CollectGarbage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_CollectGarbage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).CollectGarbage(ctx, req.(*CollectGarbageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_SetLegalHold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLegalHoldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).SetLegalHold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_SetLegalHold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).SetLegalHold(ctx, req.(*SetLegalHoldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_SetTopicRetention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTopicRetentionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).SetTopicRetention(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_SetTopicRetention_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).SetTopicRetention(ctx, req.(*SetTopicRetentionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_CreateRecipientGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRecipientGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).CreateRecipientGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_CreateRecipientGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).CreateRecipientGroup(ctx, req.(*CreateRecipientGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_GetRecipientGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecipientGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).GetRecipientGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_GetRecipientGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).GetRecipientGroup(ctx, req.(*GetRecipientGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_ListRecipientGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRecipientGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).ListRecipientGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_ListRecipientGroups_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).ListRecipientGroups(ctx, req.(*ListRecipientGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_DeleteRecipientGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRecipientGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).DeleteRecipientGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_DeleteRecipientGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).DeleteRecipientGroup(ctx, req.(*DeleteRecipientGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_AddRecipientGroupMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecipientGroupMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).AddRecipientGroupMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_AddRecipientGroupMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).AddRecipientGroupMembers(ctx, req.(*RecipientGroupMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Mail_RemoveRecipientGroupMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecipientGroupMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MailServer).RemoveRecipientGroupMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_RemoveRecipientGroupMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).RemoveRecipientGroupMembers(ctx, req.(*RecipientGroupMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterMailServer(s grpc.ServiceRegistrar, srv MailServer) {
-	// If the following call pancis, it indicates UnimplementedMailServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func _Mail_ListDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&Mail_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(MailServer).ListDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_ListDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).ListDeadLetters(ctx, req.(*ListDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_SendMail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendMailRequest)
+func _Mail_DeleteDeadLetter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDeadLetterRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).SendMail(ctx, in)
+		return srv.(MailServer).DeleteDeadLetter(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_SendMail_FullMethodName,
+		FullMethod: Mail_DeleteDeadLetter_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).SendMail(ctx, req.(*SendMailRequest))
+		return srv.(MailServer).DeleteDeadLetter(ctx, req.(*DeleteDeadLetterRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_FetchInbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(FetchInboxRequest)
+func _Mail_ListScheduledMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScheduledMessagesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).FetchInbox(ctx, in)
+		return srv.(MailServer).ListScheduledMessages(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_FetchInbox_FullMethodName,
+		FullMethod: Mail_ListScheduledMessages_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).FetchInbox(ctx, req.(*FetchInboxRequest))
+		return srv.(MailServer).ListScheduledMessages(ctx, req.(*ListScheduledMessagesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_ReadMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ReadMessageRequest)
+func _Mail_CancelScheduledMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelScheduledMessageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).ReadMessage(ctx, in)
+		return srv.(MailServer).CancelScheduledMessage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_ReadMessage_FullMethodName,
+		FullMethod: Mail_CancelScheduledMessage_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).ReadMessage(ctx, req.(*ReadMessageRequest))
+		return srv.(MailServer).CancelScheduledMessage(ctx, req.(*CancelScheduledMessageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_ReadThread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ReadThreadRequest)
+func _Mail_CreateSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateScheduleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).ReadThread(ctx, in)
+		return srv.(MailServer).CreateSchedule(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_ReadThread_FullMethodName,
+		FullMethod: Mail_CreateSchedule_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).ReadThread(ctx, req.(*ReadThreadRequest))
+		return srv.(MailServer).CreateSchedule(ctx, req.(*CreateScheduleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_UpdateState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateStateRequest)
+func _Mail_ListSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSchedulesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).UpdateState(ctx, in)
+		return srv.(MailServer).ListSchedules(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_UpdateState_FullMethodName,
+		FullMethod: Mail_ListSchedules_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).UpdateState(ctx, req.(*UpdateStateRequest))
+		return srv.(MailServer).ListSchedules(ctx, req.(*ListSchedulesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_AckMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AckMessageRequest)
+func _Mail_DeleteSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteScheduleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).AckMessage(ctx, in)
+		return srv.(MailServer).DeleteSchedule(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_AckMessage_FullMethodName,
+		FullMethod: Mail_DeleteSchedule_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).AckMessage(ctx, req.(*AckMessageRequest))
+		return srv.(MailServer).DeleteSchedule(ctx, req.(*DeleteScheduleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetStatusRequest)
+func _Mail_SetSchedulePaused_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSchedulePausedRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).GetStatus(ctx, in)
+		return srv.(MailServer).SetSchedulePaused(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_GetStatus_FullMethodName,
+		FullMethod: Mail_SetSchedulePaused_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).GetStatus(ctx, req.(*GetStatusRequest))
+		return srv.(MailServer).SetSchedulePaused(ctx, req.(*SetSchedulePausedRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_PollChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PollChangesRequest)
+func _Mail_AskQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AskQuestionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).PollChanges(ctx, in)
+		return srv.(MailServer).AskQuestion(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_PollChanges_FullMethodName,
+		FullMethod: Mail_AskQuestion_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).PollChanges(ctx, req.(*PollChangesRequest))
+		return srv.(MailServer).AskQuestion(ctx, req.(*AskQuestionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_SubscribeInbox_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(SubscribeInboxRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _Mail_GetQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuestionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(MailServer).SubscribeInbox(m, &grpc.GenericServerStream[SubscribeInboxRequest, InboxMessage]{ServerStream: stream})
+	if interceptor == nil {
+		return srv.(MailServer).GetQuestion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Mail_GetQuestion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MailServer).GetQuestion(ctx, req.(*GetQuestionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Mail_SubscribeInboxServer = grpc.ServerStreamingServer[InboxMessage]
-
-func _Mail_Publish_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PublishRequest)
+func _Mail_AnswerQuestion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnswerQuestionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).Publish(ctx, in)
+		return srv.(MailServer).AnswerQuestion(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_Publish_FullMethodName,
+		FullMethod: Mail_AnswerQuestion_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).Publish(ctx, req.(*PublishRequest))
+		return srv.(MailServer).AnswerQuestion(ctx, req.(*AnswerQuestionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_Subscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SubscribeRequest)
+func _Mail_EditMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditMessageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).Subscribe(ctx, in)
+		return srv.(MailServer).EditMessage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_Subscribe_FullMethodName,
+		FullMethod: Mail_EditMessage_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).Subscribe(ctx, req.(*SubscribeRequest))
+		return srv.(MailServer).EditMessage(ctx, req.(*EditMessageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_Unsubscribe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UnsubscribeRequest)
+func _Mail_RecallMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecallMessageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).Unsubscribe(ctx, in)
+		return srv.(MailServer).RecallMessage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_Unsubscribe_FullMethodName,
+		FullMethod: Mail_RecallMessage_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).Unsubscribe(ctx, req.(*UnsubscribeRequest))
+		return srv.(MailServer).RecallMessage(ctx, req.(*RecallMessageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_ListTopics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListTopicsRequest)
+func _Mail_GetMessageHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMessageHistoryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).ListTopics(ctx, in)
+		return srv.(MailServer).GetMessageHistory(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_ListTopics_FullMethodName,
+		FullMethod: Mail_GetMessageHistory_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).ListTopics(ctx, req.(*ListTopicsRequest))
+		return srv.(MailServer).GetMessageHistory(ctx, req.(*GetMessageHistoryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SearchRequest)
+func _Mail_CreateLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLabelRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).Search(ctx, in)
+		return srv.(MailServer).CreateLabel(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_Search_FullMethodName,
+		FullMethod: Mail_CreateLabel_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).Search(ctx, req.(*SearchRequest))
+		return srv.(MailServer).CreateLabel(ctx, req.(*CreateLabelRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_HasUnackedStatusTo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(HasUnackedStatusToRequest)
+func _Mail_ListLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLabelsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).HasUnackedStatusTo(ctx, in)
+		return srv.(MailServer).ListLabels(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_HasUnackedStatusTo_FullMethodName,
+		FullMethod: Mail_ListLabels_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).HasUnackedStatusTo(ctx, req.(*HasUnackedStatusToRequest))
+		return srv.(MailServer).ListLabels(ctx, req.(*ListLabelsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_ReplyToThread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ReplyToThreadRequest)
+func _Mail_DeleteLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteLabelRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).ReplyToThread(ctx, in)
+		return srv.(MailServer).DeleteLabel(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_ReplyToThread_FullMethodName,
+		FullMethod: Mail_DeleteLabel_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).ReplyToThread(ctx, req.(*ReplyToThreadRequest))
+		return srv.(MailServer).DeleteLabel(ctx, req.(*DeleteLabelRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_ArchiveThread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ArchiveThreadRequest)
+func _Mail_LabelMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LabelMessageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).ArchiveThread(ctx, in)
+		return srv.(MailServer).LabelMessage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_ArchiveThread_FullMethodName,
+		FullMethod: Mail_LabelMessage_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).ArchiveThread(ctx, req.(*ArchiveThreadRequest))
+		return srv.(MailServer).LabelMessage(ctx, req.(*LabelMessageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_DeleteThread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteThreadRequest)
+func _Mail_SetThreadMuted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetThreadMutedRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).DeleteThread(ctx, in)
+		return srv.(MailServer).SetThreadMuted(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_DeleteThread_FullMethodName,
+		FullMethod: Mail_SetThreadMuted_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).DeleteThread(ctx, req.(*DeleteThreadRequest))
+		return srv.(MailServer).SetThreadMuted(ctx, req.(*SetThreadMutedRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_MarkThreadUnread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MarkThreadUnreadRequest)
+func _Mail_SetThreadFollowing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetThreadFollowingRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).MarkThreadUnread(ctx, in)
+		return srv.(MailServer).SetThreadFollowing(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_MarkThreadUnread_FullMethodName,
+		FullMethod: Mail_SetThreadFollowing_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).MarkThreadUnread(ctx, req.(*MarkThreadUnreadRequest))
+		return srv.(MailServer).SetThreadFollowing(ctx, req.(*SetThreadFollowingRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_GetTopic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetTopicRequest)
+func _Mail_ListThreadPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListThreadPreferencesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).GetTopic(ctx, in)
+		return srv.(MailServer).ListThreadPreferences(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_GetTopic_FullMethodName,
+		FullMethod: Mail_ListThreadPreferences_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).GetTopic(ctx, req.(*GetTopicRequest))
+		return srv.(MailServer).ListThreadPreferences(ctx, req.(*ListThreadPreferencesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_AutocompleteRecipients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AutocompleteRecipientsRequest)
+func _Mail_MergeThreads_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeThreadsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).AutocompleteRecipients(ctx, in)
+		return srv.(MailServer).MergeThreads(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_AutocompleteRecipients_FullMethodName,
+		FullMethod: Mail_MergeThreads_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).AutocompleteRecipients(ctx, req.(*AutocompleteRecipientsRequest))
+		return srv.(MailServer).MergeThreads(ctx, req.(*MergeThreadsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Mail_DeleteMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteMessageRequest)
+func _Mail_SplitThread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SplitThreadRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(MailServer).DeleteMessage(ctx, in)
+		return srv.(MailServer).SplitThread(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Mail_DeleteMessage_FullMethodName,
+		FullMethod: Mail_SplitThread_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MailServer).DeleteMessage(ctx, req.(*DeleteMessageRequest))
+		return srv.(MailServer).SplitThread(ctx, req.(*SplitThreadRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -880,88 +2512,260 @@ var Mail_ServiceDesc = grpc.ServiceDesc{
 	HandlerType: (*MailServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "SendMail",
-			Handler:    _Mail_SendMail_Handler,
+			MethodName: "SendMail",
+			Handler:    _Mail_SendMail_Handler,
+		},
+		{
+			MethodName: "FetchInbox",
+			Handler:    _Mail_FetchInbox_Handler,
+		},
+		{
+			MethodName: "ReadMessage",
+			Handler:    _Mail_ReadMessage_Handler,
+		},
+		{
+			MethodName: "ReadThread",
+			Handler:    _Mail_ReadThread_Handler,
+		},
+		{
+			MethodName: "UpdateState",
+			Handler:    _Mail_UpdateState_Handler,
+		},
+		{
+			MethodName: "AckMessage",
+			Handler:    _Mail_AckMessage_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _Mail_GetStatus_Handler,
+		},
+		{
+			MethodName: "PollChanges",
+			Handler:    _Mail_PollChanges_Handler,
+		},
+		{
+			MethodName: "GetWatcher",
+			Handler:    _Mail_GetWatcher_Handler,
+		},
+		{
+			MethodName: "Publish",
+			Handler:    _Mail_Publish_Handler,
+		},
+		{
+			MethodName: "Subscribe",
+			Handler:    _Mail_Subscribe_Handler,
+		},
+		{
+			MethodName: "Unsubscribe",
+			Handler:    _Mail_Unsubscribe_Handler,
+		},
+		{
+			MethodName: "ClaimQueueMessage",
+			Handler:    _Mail_ClaimQueueMessage_Handler,
+		},
+		{
+			MethodName: "AckQueueMessage",
+			Handler:    _Mail_AckQueueMessage_Handler,
+		},
+		{
+			MethodName: "ReleaseQueueMessage",
+			Handler:    _Mail_ReleaseQueueMessage_Handler,
+		},
+		{
+			MethodName: "ExtendQueueLease",
+			Handler:    _Mail_ExtendQueueLease_Handler,
+		},
+		{
+			MethodName: "ListTopics",
+			Handler:    _Mail_ListTopics_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _Mail_Search_Handler,
+		},
+		{
+			MethodName: "SearchAll",
+			Handler:    _Mail_SearchAll_Handler,
+		},
+		{
+			MethodName: "SemanticSearch",
+			Handler:    _Mail_SemanticSearch_Handler,
+		},
+		{
+			MethodName: "SimilarMessages",
+			Handler:    _Mail_SimilarMessages_Handler,
+		},
+		{
+			MethodName: "SaveSearch",
+			Handler:    _Mail_SaveSearch_Handler,
+		},
+		{
+			MethodName: "ListSavedSearches",
+			Handler:    _Mail_ListSavedSearches_Handler,
+		},
+		{
+			MethodName: "DeleteSavedSearch",
+			Handler:    _Mail_DeleteSavedSearch_Handler,
+		},
+		{
+			MethodName: "HasUnackedStatusTo",
+			Handler:    _Mail_HasUnackedStatusTo_Handler,
+		},
+		{
+			MethodName: "ReplyToThread",
+			Handler:    _Mail_ReplyToThread_Handler,
+		},
+		{
+			MethodName: "ArchiveThread",
+			Handler:    _Mail_ArchiveThread_Handler,
+		},
+		{
+			MethodName: "DeleteThread",
+			Handler:    _Mail_DeleteThread_Handler,
+		},
+		{
+			MethodName: "MarkThreadUnread",
+			Handler:    _Mail_MarkThreadUnread_Handler,
+		},
+		{
+			MethodName: "GetTopic",
+			Handler:    _Mail_GetTopic_Handler,
+		},
+		{
+			MethodName: "AutocompleteRecipients",
+			Handler:    _Mail_AutocompleteRecipients_Handler,
+		},
+		{
+			MethodName: "DeleteMessage",
+			Handler:    _Mail_DeleteMessage_Handler,
+		},
+		{
+			MethodName: "CollectGarbage",
+			Handler:    _Mail_CollectGarbage_Handler,
+		},
+		{
+			MethodName: "SetLegalHold",
+			Handler:    _Mail_SetLegalHold_Handler,
+		},
+		{
+			MethodName: "SetTopicRetention",
+			Handler:    _Mail_SetTopicRetention_Handler,
+		},
+		{
+			MethodName: "CreateRecipientGroup",
+			Handler:    _Mail_CreateRecipientGroup_Handler,
+		},
+		{
+			MethodName: "GetRecipientGroup",
+			Handler:    _Mail_GetRecipientGroup_Handler,
 		},
 		{
-			MethodName: "FetchInbox",
-			Handler:    _Mail_FetchInbox_Handler,
+			MethodName: "ListRecipientGroups",
+			Handler:    _Mail_ListRecipientGroups_Handler,
 		},
 		{
-			MethodName: "ReadMessage",
-			Handler:    _Mail_ReadMessage_Handler,
+			MethodName: "DeleteRecipientGroup",
+			Handler:    _Mail_DeleteRecipientGroup_Handler,
 		},
 		{
-			MethodName: "ReadThread",
-			Handler:    _Mail_ReadThread_Handler,
+			MethodName: "AddRecipientGroupMembers",
+			Handler:    _Mail_AddRecipientGroupMembers_Handler,
 		},
 		{
-			MethodName: "UpdateState",
-			Handler:    _Mail_UpdateState_Handler,
+			MethodName: "RemoveRecipientGroupMembers",
+			Handler:    _Mail_RemoveRecipientGroupMembers_Handler,
 		},
 		{
-			MethodName: "AckMessage",
-			Handler:    _Mail_AckMessage_Handler,
+			MethodName: "ListDeadLetters",
+			Handler:    _Mail_ListDeadLetters_Handler,
 		},
 		{
-			MethodName: "GetStatus",
-			Handler:    _Mail_GetStatus_Handler,
+			MethodName: "DeleteDeadLetter",
+			Handler:    _Mail_DeleteDeadLetter_Handler,
 		},
 		{
-			MethodName: "PollChanges",
-			Handler:    _Mail_PollChanges_Handler,
+			MethodName: "ListScheduledMessages",
+			Handler:    _Mail_ListScheduledMessages_Handler,
 		},
 		{
-			MethodName: "Publish",
-			Handler:    _Mail_Publish_Handler,
+			MethodName: "CancelScheduledMessage",
+			Handler:    _Mail_CancelScheduledMessage_Handler,
 		},
 		{
-			MethodName: "Subscribe",
-			Handler:    _Mail_Subscribe_Handler,
+			MethodName: "CreateSchedule",
+			Handler:    _Mail_CreateSchedule_Handler,
 		},
 		{
-			MethodName: "Unsubscribe",
-			Handler:    _Mail_Unsubscribe_Handler,
+			MethodName: "ListSchedules",
+			Handler:    _Mail_ListSchedules_Handler,
 		},
 		{
-			MethodName: "ListTopics",
-			Handler:    _Mail_ListTopics_Handler,
+			MethodName: "DeleteSchedule",
+			Handler:    _Mail_DeleteSchedule_Handler,
 		},
 		{
-			MethodName: "Search",
-			Handler:    _Mail_Search_Handler,
+			MethodName: "SetSchedulePaused",
+			Handler:    _Mail_SetSchedulePaused_Handler,
 		},
 		{
-			MethodName: "HasUnackedStatusTo",
-			Handler:    _Mail_HasUnackedStatusTo_Handler,
+			MethodName: "AskQuestion",
+			Handler:    _Mail_AskQuestion_Handler,
 		},
 		{
-			MethodName: "ReplyToThread",
-			Handler:    _Mail_ReplyToThread_Handler,
+			MethodName: "GetQuestion",
+			Handler:    _Mail_GetQuestion_Handler,
 		},
 		{
-			MethodName: "ArchiveThread",
-			Handler:    _Mail_ArchiveThread_Handler,
+			MethodName: "AnswerQuestion",
+			Handler:    _Mail_AnswerQuestion_Handler,
 		},
 		{
-			MethodName: "DeleteThread",
-			Handler:    _Mail_DeleteThread_Handler,
+			MethodName: "EditMessage",
+			Handler:    _Mail_EditMessage_Handler,
 		},
 		{
-			MethodName: "MarkThreadUnread",
-			Handler:    _Mail_MarkThreadUnread_Handler,
+			MethodName: "RecallMessage",
+			Handler:    _Mail_RecallMessage_Handler,
 		},
 		{
-			MethodName: "GetTopic",
-			Handler:    _Mail_GetTopic_Handler,
+			MethodName: "GetMessageHistory",
+			Handler:    _Mail_GetMessageHistory_Handler,
 		},
 		{
-			MethodName: "AutocompleteRecipients",
-			Handler:    _Mail_AutocompleteRecipients_Handler,
+			MethodName: "CreateLabel",
+			Handler:    _Mail_CreateLabel_Handler,
 		},
 		{
-			MethodName: "DeleteMessage",
-			Handler:    _Mail_DeleteMessage_Handler,
+			MethodName: "ListLabels",
+			Handler:    _Mail_ListLabels_Handler,
+		},
+		{
+			MethodName: "DeleteLabel",
+			Handler:    _Mail_DeleteLabel_Handler,
+		},
+		{
+			MethodName: "LabelMessage",
+			Handler:    _Mail_LabelMessage_Handler,
+		},
+		{
+			MethodName: "SetThreadMuted",
+			Handler:    _Mail_SetThreadMuted_Handler,
+		},
+		{
+			MethodName: "SetThreadFollowing",
+			Handler:    _Mail_SetThreadFollowing_Handler,
+		},
+		{
+			MethodName: "ListThreadPreferences",
+			Handler:    _Mail_ListThreadPreferences_Handler,
+		},
+		{
+			MethodName: "MergeThreads",
+			Handler:    _Mail_MergeThreads_Handler,
+		},
+		{
+			MethodName: "SplitThread",
+			Handler:    _Mail_SplitThread_Handler,
 		},
 	},
 	Streams: []grpc.StreamDesc{
@@ -970,21 +2774,34 @@ var Mail_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Mail_SubscribeInbox_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchInbox",
+			Handler:       _Mail_WatchInbox_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "mail.proto",
 }
 
 const (
-	Agent_RegisterAgent_FullMethodName   = "/subtraterpc.Agent/RegisterAgent"
-	Agent_GetAgent_FullMethodName        = "/subtraterpc.Agent/GetAgent"
-	Agent_ListAgents_FullMethodName      = "/subtraterpc.Agent/ListAgents"
-	Agent_DeleteAgent_FullMethodName     = "/subtraterpc.Agent/DeleteAgent"
-	Agent_UpdateAgent_FullMethodName     = "/subtraterpc.Agent/UpdateAgent"
-	Agent_GetAgentsStatus_FullMethodName = "/subtraterpc.Agent/GetAgentsStatus"
-	Agent_Heartbeat_FullMethodName       = "/subtraterpc.Agent/Heartbeat"
-	Agent_EnsureIdentity_FullMethodName  = "/subtraterpc.Agent/EnsureIdentity"
-	Agent_SaveIdentity_FullMethodName    = "/subtraterpc.Agent/SaveIdentity"
-	Agent_DiscoverAgents_FullMethodName  = "/subtraterpc.Agent/DiscoverAgents"
+	Agent_RegisterAgent_FullMethodName        = "/subtraterpc.Agent/RegisterAgent"
+	Agent_GetAgent_FullMethodName             = "/subtraterpc.Agent/GetAgent"
+	Agent_ListAgents_FullMethodName           = "/subtraterpc.Agent/ListAgents"
+	Agent_DeleteAgent_FullMethodName          = "/subtraterpc.Agent/DeleteAgent"
+	Agent_UpdateAgent_FullMethodName          = "/subtraterpc.Agent/UpdateAgent"
+	Agent_GetAgentsStatus_FullMethodName      = "/subtraterpc.Agent/GetAgentsStatus"
+	Agent_Heartbeat_FullMethodName            = "/subtraterpc.Agent/Heartbeat"
+	Agent_EnsureIdentity_FullMethodName       = "/subtraterpc.Agent/EnsureIdentity"
+	Agent_SaveIdentity_FullMethodName         = "/subtraterpc.Agent/SaveIdentity"
+	Agent_DiscoverAgents_FullMethodName       = "/subtraterpc.Agent/DiscoverAgents"
+	Agent_ListCapabilityRoutes_FullMethodName = "/subtraterpc.Agent/ListCapabilityRoutes"
+	Agent_MintCredential_FullMethodName       = "/subtraterpc.Agent/MintCredential"
+	Agent_ListCredentials_FullMethodName      = "/subtraterpc.Agent/ListCredentials"
+	Agent_RevokeCredential_FullMethodName     = "/subtraterpc.Agent/RevokeCredential"
+	Agent_SetLimit_FullMethodName             = "/subtraterpc.Agent/SetLimit"
+	Agent_ClearLimit_FullMethodName           = "/subtraterpc.Agent/ClearLimit"
+	Agent_ListLimits_FullMethodName           = "/subtraterpc.Agent/ListLimits"
+	Agent_GetUsage_FullMethodName             = "/subtraterpc.Agent/GetUsage"
 )
 
 // AgentClient is the client API for Agent service.
@@ -1014,6 +2831,27 @@ type AgentClient interface {
 	// DiscoverAgents returns all agents with discovery metadata including
 	// status, working directory, purpose, hostname, and unread counts.
 	DiscoverAgents(ctx context.Context, in *DiscoverAgentsRequest, opts ...grpc.CallOption) (*DiscoverAgentsResponse, error)
+	// ListCapabilityRoutes lists the agents the router picked for mail and
+	// tasks addressed to a capability, newest first.
+	ListCapabilityRoutes(ctx context.Context, in *ListCapabilityRoutesRequest, opts ...grpc.CallOption) (*ListCapabilityRoutesResponse, error)
+	// MintCredential mints a bearer token for an agent, or an admin token.
+	// Only an admin credential may mint.
+	MintCredential(ctx context.Context, in *MintCredentialRequest, opts ...grpc.CallOption) (*MintCredentialResponse, error)
+	// ListCredentials lists credentials, newest first.
+	ListCredentials(ctx context.Context, in *ListCredentialsRequest, opts ...grpc.CallOption) (*ListCredentialsResponse, error)
+	// RevokeCredential revokes a credential so its token stops working.
+	RevokeCredential(ctx context.Context, in *RevokeCredentialRequest, opts ...grpc.CallOption) (*RevokeCredentialResponse, error)
+	// SetLimit sets an agent's rate limit or storage quota for a class, or
+	// the default for every agent. Only an admin credential may set one.
+	SetLimit(ctx context.Context, in *SetLimitRequest, opts ...grpc.CallOption) (*SetLimitResponse, error)
+	// ClearLimit removes an agent's limit for a class, or the default.
+	ClearLimit(ctx context.Context, in *ClearLimitRequest, opts ...grpc.CallOption) (*ClearLimitResponse, error)
+	// ListLimits lists the default limits followed by the agents' own.
+	ListLimits(ctx context.Context, in *ListLimitsRequest, opts ...grpc.CallOption) (*ListLimitsResponse, error)
+	// GetUsage reports an agent's standing against its limits: the tokens
+	// left in each bucket, how many calls were throttled, and the bytes
+	// of message body sent today.
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error)
 }
 
 type agentClient struct {
@@ -1124,6 +2962,86 @@ func (c *agentClient) DiscoverAgents(ctx context.Context, in *DiscoverAgentsRequ
 	return out, nil
 }
 
+func (c *agentClient) ListCapabilityRoutes(ctx context.Context, in *ListCapabilityRoutesRequest, opts ...grpc.CallOption) (*ListCapabilityRoutesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCapabilityRoutesResponse)
+	err := c.cc.Invoke(ctx, Agent_ListCapabilityRoutes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) MintCredential(ctx context.Context, in *MintCredentialRequest, opts ...grpc.CallOption) (*MintCredentialResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MintCredentialResponse)
+	err := c.cc.Invoke(ctx, Agent_MintCredential_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) ListCredentials(ctx context.Context, in *ListCredentialsRequest, opts ...grpc.CallOption) (*ListCredentialsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCredentialsResponse)
+	err := c.cc.Invoke(ctx, Agent_ListCredentials_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) RevokeCredential(ctx context.Context, in *RevokeCredentialRequest, opts ...grpc.CallOption) (*RevokeCredentialResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeCredentialResponse)
+	err := c.cc.Invoke(ctx, Agent_RevokeCredential_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) SetLimit(ctx context.Context, in *SetLimitRequest, opts ...grpc.CallOption) (*SetLimitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetLimitResponse)
+	err := c.cc.Invoke(ctx, Agent_SetLimit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) ClearLimit(ctx context.Context, in *ClearLimitRequest, opts ...grpc.CallOption) (*ClearLimitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearLimitResponse)
+	err := c.cc.Invoke(ctx, Agent_ClearLimit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) ListLimits(ctx context.Context, in *ListLimitsRequest, opts ...grpc.CallOption) (*ListLimitsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLimitsResponse)
+	err := c.cc.Invoke(ctx, Agent_ListLimits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUsageResponse)
+	err := c.cc.Invoke(ctx, Agent_GetUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentServer is the server API for Agent service.
 // All implementations must embed UnimplementedAgentServer
 // for forward compatibility.
@@ -1151,6 +3069,27 @@ type AgentServer interface {
 	// DiscoverAgents returns all agents with discovery metadata including
 	// status, working directory, purpose, hostname, and unread counts.
 	DiscoverAgents(context.Context, *DiscoverAgentsRequest) (*DiscoverAgentsResponse, error)
+	// ListCapabilityRoutes lists the agents the router picked for mail and
+	// tasks addressed to a capability, newest first.
+	ListCapabilityRoutes(context.Context, *ListCapabilityRoutesRequest) (*ListCapabilityRoutesResponse, error)
+	// MintCredential mints a bearer token for an agent, or an admin token.
+	// Only an admin credential may mint.
+	MintCredential(context.Context, *MintCredentialRequest) (*MintCredentialResponse, error)
+	// ListCredentials lists credentials, newest first.
+	ListCredentials(context.Context, *ListCredentialsRequest) (*ListCredentialsResponse, error)
+	// RevokeCredential revokes a credential so its token stops working.
+	RevokeCredential(context.Context, *RevokeCredentialRequest) (*RevokeCredentialResponse, error)
+	// SetLimit sets an agent's rate limit or storage quota for a class, or
+	// the default for every agent. Only an admin credential may set one.
+	SetLimit(context.Context, *SetLimitRequest) (*SetLimitResponse, error)
+	// ClearLimit removes an agent's limit for a class, or the default.
+	ClearLimit(context.Context, *ClearLimitRequest) (*ClearLimitResponse, error)
+	// ListLimits lists the default limits followed by the agents' own.
+	ListLimits(context.Context, *ListLimitsRequest) (*ListLimitsResponse, error)
+	// GetUsage reports an agent's standing against its limits: the tokens
+	// left in each bucket, how many calls were throttled, and the bytes
+	// of message body sent today.
+	GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error)
 	mustEmbedUnimplementedAgentServer()
 }
 
@@ -1191,6 +3130,30 @@ func (UnimplementedAgentServer) SaveIdentity(context.Context, *SaveIdentityReque
 func (UnimplementedAgentServer) DiscoverAgents(context.Context, *DiscoverAgentsRequest) (*DiscoverAgentsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DiscoverAgents not implemented")
 }
+func (UnimplementedAgentServer) ListCapabilityRoutes(context.Context, *ListCapabilityRoutesRequest) (*ListCapabilityRoutesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCapabilityRoutes not implemented")
+}
+func (UnimplementedAgentServer) MintCredential(context.Context, *MintCredentialRequest) (*MintCredentialResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MintCredential not implemented")
+}
+func (UnimplementedAgentServer) ListCredentials(context.Context, *ListCredentialsRequest) (*ListCredentialsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCredentials not implemented")
+}
+func (UnimplementedAgentServer) RevokeCredential(context.Context, *RevokeCredentialRequest) (*RevokeCredentialResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeCredential not implemented")
+}
+func (UnimplementedAgentServer) SetLimit(context.Context, *SetLimitRequest) (*SetLimitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLimit not implemented")
+}
+func (UnimplementedAgentServer) ClearLimit(context.Context, *ClearLimitRequest) (*ClearLimitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearLimit not implemented")
+}
+func (UnimplementedAgentServer) ListLimits(context.Context, *ListLimitsRequest) (*ListLimitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLimits not implemented")
+}
+func (UnimplementedAgentServer) GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
 func (UnimplementedAgentServer) mustEmbedUnimplementedAgentServer() {}
 func (UnimplementedAgentServer) testEmbeddedByValue()               {}
 
@@ -1201,193 +3164,337 @@ type UnsafeAgentServer interface {
 	mustEmbedUnimplementedAgentServer()
 }
 
-func RegisterAgentServer(s grpc.ServiceRegistrar, srv AgentServer) {
-	// If the following call pancis, it indicates UnimplementedAgentServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func RegisterAgentServer(s grpc.ServiceRegistrar, srv AgentServer) {
+	// If the following call pancis, it indicates UnimplementedAgentServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Agent_ServiceDesc, srv)
+}
+
+func _Agent_RegisterAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).RegisterAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_RegisterAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).RegisterAgent(ctx, req.(*RegisterAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_GetAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).GetAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_GetAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).GetAgent(ctx, req.(*GetAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_ListAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).ListAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_ListAgents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).ListAgents(ctx, req.(*ListAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_DeleteAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).DeleteAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_DeleteAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).DeleteAgent(ctx, req.(*DeleteAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_UpdateAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).UpdateAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_UpdateAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).UpdateAgent(ctx, req.(*UpdateAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_GetAgentsStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAgentsStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).GetAgentsStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_GetAgentsStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).GetAgentsStatus(ctx, req.(*GetAgentsStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_EnsureIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnsureIdentityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&Agent_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(AgentServer).EnsureIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_EnsureIdentity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).EnsureIdentity(ctx, req.(*EnsureIdentityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Agent_RegisterAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RegisterAgentRequest)
+func _Agent_SaveIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveIdentityRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AgentServer).RegisterAgent(ctx, in)
+		return srv.(AgentServer).SaveIdentity(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Agent_RegisterAgent_FullMethodName,
+		FullMethod: Agent_SaveIdentity_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AgentServer).RegisterAgent(ctx, req.(*RegisterAgentRequest))
+		return srv.(AgentServer).SaveIdentity(ctx, req.(*SaveIdentityRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Agent_GetAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetAgentRequest)
+func _Agent_DiscoverAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscoverAgentsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AgentServer).GetAgent(ctx, in)
+		return srv.(AgentServer).DiscoverAgents(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Agent_GetAgent_FullMethodName,
+		FullMethod: Agent_DiscoverAgents_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AgentServer).GetAgent(ctx, req.(*GetAgentRequest))
+		return srv.(AgentServer).DiscoverAgents(ctx, req.(*DiscoverAgentsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Agent_ListAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListAgentsRequest)
+func _Agent_ListCapabilityRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCapabilityRoutesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AgentServer).ListAgents(ctx, in)
+		return srv.(AgentServer).ListCapabilityRoutes(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Agent_ListAgents_FullMethodName,
+		FullMethod: Agent_ListCapabilityRoutes_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AgentServer).ListAgents(ctx, req.(*ListAgentsRequest))
+		return srv.(AgentServer).ListCapabilityRoutes(ctx, req.(*ListCapabilityRoutesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Agent_DeleteAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteAgentRequest)
+func _Agent_MintCredential_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MintCredentialRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AgentServer).DeleteAgent(ctx, in)
+		return srv.(AgentServer).MintCredential(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Agent_DeleteAgent_FullMethodName,
+		FullMethod: Agent_MintCredential_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AgentServer).DeleteAgent(ctx, req.(*DeleteAgentRequest))
+		return srv.(AgentServer).MintCredential(ctx, req.(*MintCredentialRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Agent_UpdateAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateAgentRequest)
+func _Agent_ListCredentials_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCredentialsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AgentServer).UpdateAgent(ctx, in)
+		return srv.(AgentServer).ListCredentials(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Agent_UpdateAgent_FullMethodName,
+		FullMethod: Agent_ListCredentials_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AgentServer).UpdateAgent(ctx, req.(*UpdateAgentRequest))
+		return srv.(AgentServer).ListCredentials(ctx, req.(*ListCredentialsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Agent_GetAgentsStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetAgentsStatusRequest)
+func _Agent_RevokeCredential_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeCredentialRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AgentServer).GetAgentsStatus(ctx, in)
+		return srv.(AgentServer).RevokeCredential(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Agent_GetAgentsStatus_FullMethodName,
+		FullMethod: Agent_RevokeCredential_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AgentServer).GetAgentsStatus(ctx, req.(*GetAgentsStatusRequest))
+		return srv.(AgentServer).RevokeCredential(ctx, req.(*RevokeCredentialRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Agent_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(HeartbeatRequest)
+func _Agent_SetLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLimitRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AgentServer).Heartbeat(ctx, in)
+		return srv.(AgentServer).SetLimit(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Agent_Heartbeat_FullMethodName,
+		FullMethod: Agent_SetLimit_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AgentServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+		return srv.(AgentServer).SetLimit(ctx, req.(*SetLimitRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Agent_EnsureIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EnsureIdentityRequest)
+func _Agent_ClearLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearLimitRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AgentServer).EnsureIdentity(ctx, in)
+		return srv.(AgentServer).ClearLimit(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Agent_EnsureIdentity_FullMethodName,
+		FullMethod: Agent_ClearLimit_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AgentServer).EnsureIdentity(ctx, req.(*EnsureIdentityRequest))
+		return srv.(AgentServer).ClearLimit(ctx, req.(*ClearLimitRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Agent_SaveIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SaveIdentityRequest)
+func _Agent_ListLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLimitsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AgentServer).SaveIdentity(ctx, in)
+		return srv.(AgentServer).ListLimits(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Agent_SaveIdentity_FullMethodName,
+		FullMethod: Agent_ListLimits_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AgentServer).SaveIdentity(ctx, req.(*SaveIdentityRequest))
+		return srv.(AgentServer).ListLimits(ctx, req.(*ListLimitsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Agent_DiscoverAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DiscoverAgentsRequest)
+func _Agent_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AgentServer).DiscoverAgents(ctx, in)
+		return srv.(AgentServer).GetUsage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Agent_DiscoverAgents_FullMethodName,
+		FullMethod: Agent_GetUsage_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AgentServer).DiscoverAgents(ctx, req.(*DiscoverAgentsRequest))
+		return srv.(AgentServer).GetUsage(ctx, req.(*GetUsageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -1439,6 +3546,38 @@ var Agent_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DiscoverAgents",
 			Handler:    _Agent_DiscoverAgents_Handler,
 		},
+		{
+			MethodName: "ListCapabilityRoutes",
+			Handler:    _Agent_ListCapabilityRoutes_Handler,
+		},
+		{
+			MethodName: "MintCredential",
+			Handler:    _Agent_MintCredential_Handler,
+		},
+		{
+			MethodName: "ListCredentials",
+			Handler:    _Agent_ListCredentials_Handler,
+		},
+		{
+			MethodName: "RevokeCredential",
+			Handler:    _Agent_RevokeCredential_Handler,
+		},
+		{
+			MethodName: "SetLimit",
+			Handler:    _Agent_SetLimit_Handler,
+		},
+		{
+			MethodName: "ClearLimit",
+			Handler:    _Agent_ClearLimit_Handler,
+		},
+		{
+			MethodName: "ListLimits",
+			Handler:    _Agent_ListLimits_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _Agent_GetUsage_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "mail.proto",
@@ -3332,6 +5471,278 @@ var PlanReviewService_ServiceDesc = grpc.ServiceDesc{
 	Metadata: "mail.proto",
 }
 
+const (
+	InboxRuleService_CreateInboxRule_FullMethodName     = "/subtraterpc.InboxRuleService/CreateInboxRule"
+	InboxRuleService_ListInboxRules_FullMethodName      = "/subtraterpc.InboxRuleService/ListInboxRules"
+	InboxRuleService_DeleteInboxRule_FullMethodName     = "/subtraterpc.InboxRuleService/DeleteInboxRule"
+	InboxRuleService_SetInboxRuleEnabled_FullMethodName = "/subtraterpc.InboxRuleService/SetInboxRuleEnabled"
+	InboxRuleService_TestInboxRule_FullMethodName       = "/subtraterpc.InboxRuleService/TestInboxRule"
+)
+
+// InboxRuleServiceClient is the client API for InboxRuleService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// InboxRuleService manages the per-agent rules that file incoming messages
+// as they are delivered.
+type InboxRuleServiceClient interface {
+	// CreateInboxRule adds a rule after the agent's existing rules.
+	CreateInboxRule(ctx context.Context, in *CreateInboxRuleRequest, opts ...grpc.CallOption) (*InboxRuleProto, error)
+	// ListInboxRules lists an agent's rules in the order they are applied.
+	ListInboxRules(ctx context.Context, in *ListInboxRulesRequest, opts ...grpc.CallOption) (*ListInboxRulesResponse, error)
+	// DeleteInboxRule deletes one of an agent's rules.
+	DeleteInboxRule(ctx context.Context, in *DeleteInboxRuleRequest, opts ...grpc.CallOption) (*DeleteInboxRuleResponse, error)
+	// SetInboxRuleEnabled enables or disables one of an agent's rules.
+	SetInboxRuleEnabled(ctx context.Context, in *SetInboxRuleEnabledRequest, opts ...grpc.CallOption) (*InboxRuleProto, error)
+	// TestInboxRule reports which of the agent's recently received messages
+	// a rule would match, without changing them.
+	TestInboxRule(ctx context.Context, in *TestInboxRuleRequest, opts ...grpc.CallOption) (*TestInboxRuleResponse, error)
+}
+
+type inboxRuleServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInboxRuleServiceClient(cc grpc.ClientConnInterface) InboxRuleServiceClient {
+	return &inboxRuleServiceClient{cc}
+}
+
+func (c *inboxRuleServiceClient) CreateInboxRule(ctx context.Context, in *CreateInboxRuleRequest, opts ...grpc.CallOption) (*InboxRuleProto, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InboxRuleProto)
+	err := c.cc.Invoke(ctx, InboxRuleService_CreateInboxRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inboxRuleServiceClient) ListInboxRules(ctx context.Context, in *ListInboxRulesRequest, opts ...grpc.CallOption) (*ListInboxRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInboxRulesResponse)
+	err := c.cc.Invoke(ctx, InboxRuleService_ListInboxRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inboxRuleServiceClient) DeleteInboxRule(ctx context.Context, in *DeleteInboxRuleRequest, opts ...grpc.CallOption) (*DeleteInboxRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteInboxRuleResponse)
+	err := c.cc.Invoke(ctx, InboxRuleService_DeleteInboxRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inboxRuleServiceClient) SetInboxRuleEnabled(ctx context.Context, in *SetInboxRuleEnabledRequest, opts ...grpc.CallOption) (*InboxRuleProto, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InboxRuleProto)
+	err := c.cc.Invoke(ctx, InboxRuleService_SetInboxRuleEnabled_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inboxRuleServiceClient) TestInboxRule(ctx context.Context, in *TestInboxRuleRequest, opts ...grpc.CallOption) (*TestInboxRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TestInboxRuleResponse)
+	err := c.cc.Invoke(ctx, InboxRuleService_TestInboxRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InboxRuleServiceServer is the server API for InboxRuleService service.
+// All implementations must embed UnimplementedInboxRuleServiceServer
+// for forward compatibility.
+//
+// InboxRuleService manages the per-agent rules that file incoming messages
+// as they are delivered.
+type InboxRuleServiceServer interface {
+	// CreateInboxRule adds a rule after the agent's existing rules.
+	CreateInboxRule(context.Context, *CreateInboxRuleRequest) (*InboxRuleProto, error)
+	// ListInboxRules lists an agent's rules in the order they are applied.
+	ListInboxRules(context.Context, *ListInboxRulesRequest) (*ListInboxRulesResponse, error)
+	// DeleteInboxRule deletes one of an agent's rules.
+	DeleteInboxRule(context.Context, *DeleteInboxRuleRequest) (*DeleteInboxRuleResponse, error)
+	// SetInboxRuleEnabled enables or disables one of an agent's rules.
+	SetInboxRuleEnabled(context.Context, *SetInboxRuleEnabledRequest) (*InboxRuleProto, error)
+	// TestInboxRule reports which of the agent's recently received messages
+	// a rule would match, without changing them.
+	TestInboxRule(context.Context, *TestInboxRuleRequest) (*TestInboxRuleResponse, error)
+	mustEmbedUnimplementedInboxRuleServiceServer()
+}
+
+// UnimplementedInboxRuleServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedInboxRuleServiceServer struct{}
+
+func (UnimplementedInboxRuleServiceServer) CreateInboxRule(context.Context, *CreateInboxRuleRequest) (*InboxRuleProto, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateInboxRule not implemented")
+}
+func (UnimplementedInboxRuleServiceServer) ListInboxRules(context.Context, *ListInboxRulesRequest) (*ListInboxRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInboxRules not implemented")
+}
+func (UnimplementedInboxRuleServiceServer) DeleteInboxRule(context.Context, *DeleteInboxRuleRequest) (*DeleteInboxRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteInboxRule not implemented")
+}
+func (UnimplementedInboxRuleServiceServer) SetInboxRuleEnabled(context.Context, *SetInboxRuleEnabledRequest) (*InboxRuleProto, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetInboxRuleEnabled not implemented")
+}
+func (UnimplementedInboxRuleServiceServer) TestInboxRule(context.Context, *TestInboxRuleRequest) (*TestInboxRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TestInboxRule not implemented")
+}
+func (UnimplementedInboxRuleServiceServer) mustEmbedUnimplementedInboxRuleServiceServer() {}
+func (UnimplementedInboxRuleServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeInboxRuleServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InboxRuleServiceServer will
+// result in compilation errors.
+type UnsafeInboxRuleServiceServer interface {
+	mustEmbedUnimplementedInboxRuleServiceServer()
+}
+
+func RegisterInboxRuleServiceServer(s grpc.ServiceRegistrar, srv InboxRuleServiceServer) {
+	// If the following call pancis, it indicates UnimplementedInboxRuleServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&InboxRuleService_ServiceDesc, srv)
+}
+
+func _InboxRuleService_CreateInboxRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateInboxRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InboxRuleServiceServer).CreateInboxRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InboxRuleService_CreateInboxRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InboxRuleServiceServer).CreateInboxRule(ctx, req.(*CreateInboxRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InboxRuleService_ListInboxRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInboxRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InboxRuleServiceServer).ListInboxRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InboxRuleService_ListInboxRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InboxRuleServiceServer).ListInboxRules(ctx, req.(*ListInboxRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InboxRuleService_DeleteInboxRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteInboxRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InboxRuleServiceServer).DeleteInboxRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InboxRuleService_DeleteInboxRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InboxRuleServiceServer).DeleteInboxRule(ctx, req.(*DeleteInboxRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InboxRuleService_SetInboxRuleEnabled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetInboxRuleEnabledRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InboxRuleServiceServer).SetInboxRuleEnabled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InboxRuleService_SetInboxRuleEnabled_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InboxRuleServiceServer).SetInboxRuleEnabled(ctx, req.(*SetInboxRuleEnabledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InboxRuleService_TestInboxRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TestInboxRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InboxRuleServiceServer).TestInboxRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InboxRuleService_TestInboxRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InboxRuleServiceServer).TestInboxRule(ctx, req.(*TestInboxRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InboxRuleService_ServiceDesc is the grpc.ServiceDesc for InboxRuleService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InboxRuleService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "subtraterpc.InboxRuleService",
+	HandlerType: (*InboxRuleServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateInboxRule",
+			Handler:    _InboxRuleService_CreateInboxRule_Handler,
+		},
+		{
+			MethodName: "ListInboxRules",
+			Handler:    _InboxRuleService_ListInboxRules_Handler,
+		},
+		{
+			MethodName: "DeleteInboxRule",
+			Handler:    _InboxRuleService_DeleteInboxRule_Handler,
+		},
+		{
+			MethodName: "SetInboxRuleEnabled",
+			Handler:    _InboxRuleService_SetInboxRuleEnabled_Handler,
+		},
+		{
+			MethodName: "TestInboxRule",
+			Handler:    _InboxRuleService_TestInboxRule_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "mail.proto",
+}
+
 const (
 	AnnotationService_CreatePlanAnnotation_FullMethodName = "/subtraterpc.AnnotationService/CreatePlanAnnotation"
 	AnnotationService_ListPlanAnnotations_FullMethodName  = "/subtraterpc.AnnotationService/ListPlanAnnotations"
@@ -3,6 +3,7 @@ package subtraterpc
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"github.com/roasbeef/subtrate/internal/agent"
 	"github.com/roasbeef/subtrate/internal/db/sqlc"
 	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/search"
 	"github.com/roasbeef/subtrate/internal/store"
 )
 
@@ -46,6 +48,11 @@ func (s *Server) SendMail(ctx context.Context, req *SendMailRequest) (*SendMailR
 		t := req.DeadlineAt.AsTime()
 		deadline = &t
 	}
+	var sendAt *time.Time
+	if req.SendAt != nil && req.SendAt.IsValid() {
+		t := req.SendAt.AsTime()
+		sendAt = &t
+	}
 
 	sendReq := mail.SendMailRequest{
 		SenderID:       req.SenderId,
@@ -58,6 +65,9 @@ func (s *Server) SendMail(ctx context.Context, req *SendMailRequest) (*SendMailR
 		Deadline:       deadline,
 		Attachments:    req.AttachmentsJson,
 		IdempotencyKey: req.IdempotencyKey,
+		DeliveryMode:   convertDeliveryMode(req.DeliveryMode),
+		SendAt:         sendAt,
+		Metadata:       req.Metadata,
 	}
 
 	// Send via the shared mail client (actor system).
@@ -65,8 +75,11 @@ func (s *Server) SendMail(ctx context.Context, req *SendMailRequest) (*SendMailR
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to send mail: %v", err)
 	}
+	if isLimitError(resp.Error) {
+		return nil, limitStatusError(ctx, resp.Error)
+	}
 	if resp.Error != nil {
-		return nil, status.Errorf(codes.Internal, "failed to send mail: %v", resp.Error)
+		return nil, recipientStatusError("send mail", resp.Error)
 	}
 	messageID := resp.MessageID
 	threadID := resp.ThreadID
@@ -81,11 +94,15 @@ func (s *Server) SendMail(ctx context.Context, req *SendMailRequest) (*SendMailR
 	} else if req.TopicName != "" {
 		recipientsList = req.TopicName
 	}
+	verb := "Sent"
+	if resp.ScheduledID != 0 {
+		verb = "Scheduled"
+	}
 	// Record activity (ignore errors - non-critical).
 	_, _ = s.store.Queries().CreateActivity(ctx, sqlc.CreateActivityParams{
 		AgentID:      req.SenderId,
 		ActivityType: "message",
-		Description:  fmt.Sprintf("Sent \"%s\" to %s", req.Subject, recipientsList),
+		Description:  fmt.Sprintf("%s \"%s\" to %s", verb, req.Subject, recipientsList),
 		Metadata:     sql.NullString{},
 		CreatedAt:    time.Now().Unix(),
 	})
@@ -96,8 +113,11 @@ func (s *Server) SendMail(ctx context.Context, req *SendMailRequest) (*SendMailR
 	// HubNotificationBridge.
 
 	return &SendMailResponse{
-		MessageId: messageID,
-		ThreadId:  threadID,
+		MessageId:   messageID,
+		ThreadId:    threadID,
+		Expansions:  convertRecipientExpansions(resp.Expansions),
+		Deliveries:  convertRecipientDeliveries(resp.Deliveries),
+		ScheduledId: resp.ScheduledID,
 	}, nil
 }
 
@@ -157,6 +177,8 @@ func (s *Server) FetchInbox(ctx context.Context, req *FetchInboxRequest) (*Fetch
 		StateFilter: stateFilter,
 		SentOnly:    req.SentOnly,
 		Category:    storeInboxCategory(req.Category),
+		OverdueOnly: req.OverdueOnly,
+		Label:       req.Label,
 	}
 
 	// Fetch via the shared mail client (actor system).
@@ -175,11 +197,13 @@ func (s *Server) FetchInbox(ctx context.Context, req *FetchInboxRequest) (*Fetch
 		Messages: protoMsgs,
 	}
 	if resp.HasCategoryCounts {
+		counts := resp.CategoryCounts
 		pbResp.CategoryCounts = &InboxCategoryCounts{
-			Primary:       resp.CategoryCounts.Primary,
-			Notifications: resp.CategoryCounts.Notifications,
-			Unread:        resp.CategoryCounts.Unread,
-			Starred:       resp.CategoryCounts.Starred,
+			Primary:       counts.Primary,
+			Notifications: counts.Notifications,
+			Unread:        counts.Unread,
+			Starred:       counts.Starred,
+			Labels:        convertLabels(counts.Labels),
 		}
 	}
 	return pbResp, nil
@@ -221,8 +245,26 @@ func (s *Server) ReadMessage(ctx context.Context, req *ReadMessageRequest) (*Rea
 	protoMsg := convertMessage(msg)
 	s.populateRecipients(ctx, []*InboxMessage{protoMsg})
 
+	expansions, err := s.mailSvc.GetRecipientExpansions(ctx, msg.ID)
+	if err != nil {
+		// Non-fatal: the message is still readable without them.
+		slog.Warn("failed to fetch recipient expansions",
+			"message_id", msg.ID, "error", err,
+		)
+	}
+
+	history, err := s.mailSvc.GetMessageHistory(ctx, req.AgentId, msg.ID)
+	if err != nil {
+		// Non-fatal: the current version is still readable.
+		slog.Warn("failed to fetch message revisions",
+			"message_id", msg.ID, "error", err,
+		)
+	}
+
 	return &ReadMessageResponse{
-		Message: protoMsg,
+		Message:    protoMsg,
+		Expansions: convertRecipientExpansions(expansions),
+		Revisions:  convertMessageRevisions(history.Revisions),
 	}, nil
 }
 
@@ -250,8 +292,20 @@ func (s *Server) ReadThread(ctx context.Context, req *ReadThreadRequest) (*ReadT
 	protoMsgs := convertMessages(msgs)
 	s.populateRecipients(ctx, protoMsgs)
 
+	revisions, err := s.mailSvc.ListThreadMessageRevisions(
+		ctx, agentID, req.ThreadId,
+	)
+	if err != nil {
+		// Non-fatal: the thread is still readable without its edit
+		// history.
+		slog.Warn("failed to fetch thread revisions",
+			"thread_id", req.ThreadId, "error", err,
+		)
+	}
+
 	return &ReadThreadResponse{
-		Messages: protoMsgs,
+		Messages:  protoMsgs,
+		Revisions: convertMessageRevisions(revisions),
 	}, nil
 }
 
@@ -472,6 +526,7 @@ func (s *Server) Publish(ctx context.Context, req *PublishRequest) (*PublishResp
 		Body:           req.Body,
 		Priority:       priority,
 		IdempotencyKey: req.IdempotencyKey,
+		Metadata:       req.Metadata,
 	}
 
 	// Publish via the shared mail client (actor system).
@@ -479,6 +534,9 @@ func (s *Server) Publish(ctx context.Context, req *PublishRequest) (*PublishResp
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to publish: %v", err)
 	}
+	if isLimitError(resp.Error) {
+		return nil, limitStatusError(ctx, resp.Error)
+	}
 	if resp.Error != nil {
 		return nil, status.Errorf(codes.Internal, "failed to publish: %v", resp.Error)
 	}
@@ -488,6 +546,7 @@ func (s *Server) Publish(ctx context.Context, req *PublishRequest) (*PublishResp
 	return &PublishResponse{
 		MessageId:       messageID,
 		RecipientsCount: int32(recipientsCount),
+		Queued:          resp.Queued,
 	}, nil
 }
 
@@ -500,7 +559,18 @@ func (s *Server) Subscribe(ctx context.Context, req *SubscribeRequest) (*Subscri
 		return nil, status.Error(codes.InvalidArgument, "topic_name is required")
 	}
 
-	topicID, err := s.mailSvc.Subscribe(ctx, req.AgentId, req.TopicName)
+	switch req.TopicType {
+	case "", mail.TopicTypeBroadcast, mail.TopicTypeQueue:
+	default:
+		return nil, status.Errorf(
+			codes.InvalidArgument, "invalid topic_type: %q",
+			req.TopicType,
+		)
+	}
+
+	topicID, err := s.mailSvc.SubscribeTopic(
+		ctx, req.AgentId, req.TopicName, req.TopicType,
+	)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to subscribe: %v", err)
 	}
@@ -541,22 +611,26 @@ func (s *Server) ListTopics(ctx context.Context, req *ListTopicsRequest) (*ListT
 	protoTopics := make([]*Topic, len(topics))
 	for i, t := range topics {
 		protoTopics[i] = &Topic{
-			Id:           t.ID,
-			Name:         t.Name,
-			TopicType:    t.TopicType,
-			CreatedAt:    timestamppb.New(t.CreatedAt),
-			MessageCount: t.MessageCount,
+			Id:               t.ID,
+			Name:             t.Name,
+			TopicType:        t.TopicType,
+			CreatedAt:        timestamppb.New(t.CreatedAt),
+			MessageCount:     t.MessageCount,
+			RetentionSeconds: t.RetentionSeconds,
 		}
 	}
 
 	return &ListTopicsResponse{Topics: protoTopics}, nil
 }
 
-// Search performs full-text search across messages.
-// If agent_id is 0, performs global search across all messages.
+// Search runs a search query, in the language of the search package, over
+// an agent's messages, a page of ranked hits at a time. If agent_id is 0, it
+// searches every message. saved_search runs one of the agent's saved
+// searches instead, narrowed by the query if one is given.
 func (s *Server) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
-	if req.Query == "" {
-		return nil, status.Error(codes.InvalidArgument, "query is required")
+	if req.Query == "" && req.SavedSearch == "" {
+		return nil, status.Error(codes.InvalidArgument,
+			"query or saved_search is required")
 	}
 
 	limit := int32(50)
@@ -564,21 +638,190 @@ func (s *Server) Search(ctx context.Context, req *SearchRequest) (*SearchRespons
 		limit = req.Limit
 	}
 
-	results, err := s.mailSvc.Search(ctx, mail.SearchRequest{
-		AgentID: req.AgentId, // 0 = global search
-		Query:   req.Query,
-		TopicID: req.TopicId,
-		Limit:   limit,
+	resp, err := s.mailSvc.Search(ctx, mail.SearchRequest{
+		AgentID:     req.AgentId, // 0 = global search
+		Query:       req.Query,
+		TopicID:     req.TopicId,
+		Limit:       limit,
+		Label:       req.Label,
+		Cursor:      req.Cursor,
+		IncludeBody: req.IncludeBody,
+		SavedSearch: req.SavedSearch,
+		MessageIDs:  req.MessageIds,
 	})
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to search: %v", err)
+		return nil, savedSearchStatusError("search", err)
+	}
+
+	messages := make([]mail.InboxMessage, len(resp.Hits))
+	hits := make([]*SearchHit, len(resp.Hits))
+	for i, hit := range resp.Hits {
+		messages[i] = hit.InboxMessage
+		hits[i] = &SearchHit{
+			MessageId:        hit.ID,
+			Snippet:          hit.Snippet,
+			SubjectHighlight: hit.SubjectHighlight,
+			Rank:             hit.Rank,
+		}
 	}
 
 	return &SearchResponse{
-		Results: convertMessages(results),
+		Results:    convertMessages(messages),
+		Hits:       hits,
+		Total:      resp.Total,
+		NextCursor: resp.NextCursor,
 	}, nil
 }
 
+// SearchAll runs a search query over messages, review issues, plan reviews,
+// tasks and annotations, returning their hits merged by rank. If agent_id is
+// 0, every message is searched.
+func (s *Server) SearchAll(ctx context.Context,
+	req *SearchAllRequest,
+) (*SearchAllResponse, error) {
+	if req.Query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+
+	kinds := make([]search.Kind, 0, len(req.Kinds))
+	for _, k := range req.Kinds {
+		kind, ok := searchKindFromProto[k]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"unknown search kind %v", k)
+		}
+		kinds = append(kinds, kind)
+	}
+
+	resp, err := s.mailSvc.SearchAll(ctx, mail.SearchAllRequest{
+		AgentID: req.AgentId,
+		Query:   req.Query,
+		Kinds:   kinds,
+		Limit:   req.Limit,
+	})
+	if errors.Is(err, search.ErrInvalidQuery) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal,
+			"failed to search: %v", err)
+	}
+
+	hits := make([]*SearchAllHit, len(resp.Hits))
+	for i, hit := range resp.Hits {
+		hits[i] = &SearchAllHit{
+			Kind:      searchKindToProto[hit.Kind],
+			Id:        hit.ID,
+			Ref:       hit.Ref,
+			ThreadId:  hit.ThreadID,
+			Title:     hit.Title,
+			Snippet:   hit.Snippet,
+			Rank:      hit.Rank,
+			CreatedAt: timestamppb.New(hit.CreatedAt),
+		}
+	}
+
+	return &SearchAllResponse{Hits: hits, Total: resp.Total}, nil
+}
+
+// searchKindToProto maps search kinds to their proto enum values.
+var searchKindToProto = map[search.Kind]SearchKind{
+	search.KindMessage:        SearchKind_SEARCH_KIND_MESSAGE,
+	search.KindReviewIssue:    SearchKind_SEARCH_KIND_REVIEW_ISSUE,
+	search.KindPlanReview:     SearchKind_SEARCH_KIND_PLAN_REVIEW,
+	search.KindTask:           SearchKind_SEARCH_KIND_TASK,
+	search.KindPlanAnnotation: SearchKind_SEARCH_KIND_PLAN_ANNOTATION,
+	search.KindDiffAnnotation: SearchKind_SEARCH_KIND_DIFF_ANNOTATION,
+}
+
+// searchKindFromProto maps proto search kinds back to search kinds.
+var searchKindFromProto = func() map[SearchKind]search.Kind {
+	m := make(map[SearchKind]search.Kind, len(searchKindToProto))
+	for k, v := range searchKindToProto {
+		m[v] = k
+	}
+
+	return m
+}()
+
+// SemanticSearch finds the messages closest in meaning to a query, among
+// the ones its filters keep. If agent_id is 0, every message is searched.
+func (s *Server) SemanticSearch(ctx context.Context,
+	req *SemanticSearchRequest,
+) (*SemanticSearchResponse, error) {
+	if req.Query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+
+	resp, err := s.mailSvc.SemanticSearch(ctx, mail.SemanticSearchRequest{
+		AgentID:  req.AgentId,
+		Query:    req.Query,
+		Limit:    req.Limit,
+		Hybrid:   req.Hybrid,
+		MinScore: req.MinScore,
+	})
+	if errors.Is(err, search.ErrInvalidQuery) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal,
+			"failed to search: %v", err)
+	}
+
+	return semanticSearchResponse(resp), nil
+}
+
+// SimilarMessages finds the nearest neighbours of a message, or of a text if
+// message_id is 0.
+func (s *Server) SimilarMessages(ctx context.Context,
+	req *SimilarMessagesRequest,
+) (*SemanticSearchResponse, error) {
+	if req.MessageId == 0 && req.Text == "" {
+		return nil, status.Error(codes.InvalidArgument,
+			"message_id or text is required")
+	}
+
+	resp, err := s.mailSvc.SimilarMessages(ctx, mail.SimilarMessagesRequest{
+		AgentID:   req.AgentId,
+		MessageID: req.MessageId,
+		Text:      req.Text,
+		Limit:     req.Limit,
+		MinScore:  req.MinScore,
+	})
+	if errors.Is(err, mail.ErrMessageNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal,
+			"failed to find similar messages: %v", err)
+	}
+
+	return semanticSearchResponse(resp), nil
+}
+
+// semanticSearchResponse converts the hits of a semantic search.
+func semanticSearchResponse(
+	resp mail.SemanticSearchResponse,
+) *SemanticSearchResponse {
+	messages := make([]mail.InboxMessage, len(resp.Hits))
+	hits := make([]*SemanticHit, len(resp.Hits))
+	for i, hit := range resp.Hits {
+		messages[i] = hit.InboxMessage
+		hits[i] = &SemanticHit{
+			MessageId:  hit.ID,
+			Snippet:    hit.Snippet,
+			Score:      hit.Score,
+			Similarity: hit.Similarity,
+			TextScore:  hit.TextScore,
+		}
+	}
+
+	return &SemanticSearchResponse{
+		Results: convertMessages(messages),
+		Hits:    hits,
+	}
+}
+
 // AutocompleteRecipients returns matching agents for the recipient
 // autocomplete in the compose UI. Searches by name, project_key, and
 // git_branch.
@@ -681,6 +924,13 @@ func (s *Server) ReplyToThread(
 		}
 		senderID = userAgent.ID
 	}
+
+	// A reply to a specific message takes its recipients from that
+	// message rather than from the whole thread.
+	if req.MessageId != 0 {
+		return s.replyToMessage(ctx, senderID, req)
+	}
+
 	if req.ThreadId == "" {
 		return nil, status.Error(codes.InvalidArgument, "thread_id is required")
 	}
@@ -735,15 +985,86 @@ func (s *Server) ReplyToThread(
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to send reply: %v", err)
 	}
+	if isLimitError(resp.Error) {
+		return nil, limitStatusError(ctx, resp.Error)
+	}
 	if resp.Error != nil {
 		return nil, status.Errorf(codes.Internal, "failed to send reply: %v", resp.Error)
 	}
 
 	return &ReplyToThreadResponse{
-		MessageId: resp.MessageID,
+		MessageId:      resp.MessageID,
+		ThreadId:       req.ThreadId,
+		RecipientNames: recipientNames,
 	}, nil
 }
 
+// replyToMessage sends a reply to a specific message, by default to its
+// sender and every other recipient of it.
+func (s *Server) replyToMessage(ctx context.Context, senderID int64,
+	req *ReplyToThreadRequest,
+) (*ReplyToThreadResponse, error) {
+	priority := mail.PriorityNormal
+	switch req.Priority {
+	case Priority_PRIORITY_LOW:
+		priority = mail.PriorityLow
+	case Priority_PRIORITY_URGENT:
+		priority = mail.PriorityUrgent
+	}
+
+	sendReq, err := s.mailSvc.PrepareReply(ctx, mail.ReplyRequest{
+		SenderID:       senderID,
+		MessageID:      req.MessageId,
+		Body:           req.Body,
+		SenderOnly:     req.SenderOnly,
+		Quote:          req.Quote,
+		Priority:       priority,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		return nil, replyStatusError(err)
+	}
+	if req.ThreadId != "" && req.ThreadId != sendReq.ThreadID {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"message %d isn't in thread %s", req.MessageId,
+			req.ThreadId)
+	}
+
+	resp, err := s.sendMailActor(ctx, sendReq)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal,
+			"failed to send reply: %v", err)
+	}
+	if resp.Error != nil {
+		return nil, status.Errorf(codes.Internal,
+			"failed to send reply: %v", resp.Error)
+	}
+
+	return &ReplyToThreadResponse{
+		MessageId:      resp.MessageID,
+		ThreadId:       resp.ThreadID,
+		RecipientNames: sendReq.RecipientNames,
+	}, nil
+}
+
+// replyStatusError maps errors from preparing a reply onto gRPC status
+// codes.
+func replyStatusError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, mail.ErrMessageNotFound):
+		code = codes.NotFound
+
+	case errors.Is(err, mail.ErrUnauthorized):
+		code = codes.PermissionDenied
+
+	case errors.Is(err, mail.ErrInvalidReply):
+		code = codes.InvalidArgument
+	}
+
+	return status.Errorf(code, "failed to reply: %v", err)
+}
+
 // hasPrefix checks if a string has a prefix (case-insensitive).
 func hasPrefix(s, prefix string) bool {
 	if len(s) < len(prefix) {
@@ -769,17 +1090,20 @@ func (s *Server) ArchiveThread(
 		return nil, status.Error(codes.InvalidArgument, "thread_id is required")
 	}
 
-	// Get all messages in the thread.
-	msgs, err := s.mailSvc.ReadThread(ctx, agentID, req.ThreadId)
+	// Get the messages in the thread the agent received.
+	msgIDs, err := s.mailSvc.ReceivedThreadMessages(
+		ctx, agentID, req.ThreadId,
+	)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to read thread: %v", err)
 	}
 
 	// Archive each message.
 	archivedCount := 0
-	for _, msg := range msgs {
+	for _, msgID := range msgIDs {
 		resp, err := s.updateMessageStateActor(
-			ctx, agentID, msg.ID, mail.StateArchivedStr.String(), nil,
+			ctx, agentID, msgID, mail.StateArchivedStr.String(),
+			nil,
 		)
 		if err != nil {
 			continue
@@ -812,17 +1136,19 @@ func (s *Server) DeleteThread(
 		return nil, status.Error(codes.InvalidArgument, "thread_id is required")
 	}
 
-	// Get all messages in the thread.
-	msgs, err := s.mailSvc.ReadThread(ctx, agentID, req.ThreadId)
+	// Get the messages in the thread the agent received.
+	msgIDs, err := s.mailSvc.ReceivedThreadMessages(
+		ctx, agentID, req.ThreadId,
+	)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to read thread: %v", err)
 	}
 
 	// Move each message to trash.
 	deletedCount := 0
-	for _, msg := range msgs {
+	for _, msgID := range msgIDs {
 		resp, err := s.updateMessageStateActor(
-			ctx, agentID, msg.ID, mail.StateTrashStr.String(), nil,
+			ctx, agentID, msgID, mail.StateTrashStr.String(), nil,
 		)
 		if err != nil {
 			continue
@@ -855,20 +1181,32 @@ func (s *Server) MarkThreadUnread(
 		return nil, status.Error(codes.InvalidArgument, "thread_id is required")
 	}
 
-	// Get all messages in the thread.
-	msgs, err := s.mailSvc.ReadThread(ctx, agentID, req.ThreadId)
+	// Get the messages in the thread the agent received.
+	msgIDs, err := s.mailSvc.ReceivedThreadMessages(
+		ctx, agentID, req.ThreadId,
+	)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to read thread: %v", err)
 	}
 
 	// Mark each message as unread.
-	for _, msg := range msgs {
-		_, _ = s.updateMessageStateActor(
-			ctx, agentID, msg.ID, mail.StateUnreadStr.String(), nil,
+	markedCount := 0
+	for _, msgID := range msgIDs {
+		resp, err := s.updateMessageStateActor(
+			ctx, agentID, msgID, mail.StateUnreadStr.String(), nil,
 		)
+		if err != nil {
+			continue
+		}
+		if resp.Error == nil {
+			markedCount++
+		}
 	}
 
-	return &MarkThreadUnreadResponse{Success: true}, nil
+	return &MarkThreadUnreadResponse{
+		Success:        true,
+		MessagesMarked: int32(markedCount),
+	}, nil
 }
 
 // DeleteMessage moves a single message to trash.
@@ -1037,6 +1375,8 @@ func convertMessage(m *mail.InboxMessage) *InboxMessage {
 		SnoozedUntil:     timeToTimestamp(m.SnoozedUntil),
 		ReadAt:           timeToTimestamp(m.ReadAt),
 		AcknowledgedAt:   timeToTimestamp(m.AckedAt),
+		EditedAt:         timeToTimestamp(m.EditedAt),
+		Labels:           m.Labels,
 	}
 }
 
@@ -1189,6 +1529,18 @@ func (s *Server) RegisterAgent(ctx context.Context, req *RegisterAgentRequest) (
 		return nil, status.Error(codes.InvalidArgument, "name is required")
 	}
 
+	// Check the capabilities before registering, so bad ones don't leave
+	// an agent registered without them.
+	caps := capabilitiesFromProto(req.Capabilities)
+	if err := caps.Validate(); err != nil {
+		return nil, capabilityStatusError("register agent", err)
+	}
+	if req.MintToken {
+		if err := s.requireAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	agent, err := s.agentReg.RegisterAgent(
 		ctx, req.Name, req.ProjectKey, req.GitBranch,
 	)
@@ -1196,12 +1548,28 @@ func (s *Server) RegisterAgent(ctx context.Context, req *RegisterAgentRequest) (
 		return nil, status.Errorf(codes.Internal, "failed to register agent: %v", err)
 	}
 
-	return &RegisterAgentResponse{
-		AgentId:    agent.ID,
-		Name:       agent.Name,
-		ProjectKey: agent.ProjectKey.String,
-		GitBranch:  agent.GitBranch.String,
-	}, nil
+	if !caps.IsEmpty() {
+		caps, err = s.mailSvc.SetAgentCapabilities(ctx, agent.ID, caps)
+		if err != nil {
+			return nil, capabilityStatusError("register agent", err)
+		}
+	}
+
+	resp := &RegisterAgentResponse{
+		AgentId:      agent.ID,
+		Name:         agent.Name,
+		ProjectKey:   agent.ProjectKey.String,
+		GitBranch:    agent.GitBranch.String,
+		Capabilities: capabilitiesToProto(caps),
+	}
+	if req.MintToken {
+		resp.Token, err = s.mintAgentToken(ctx, agent.ID, "register")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
 }
 
 // GetAgent retrieves an agent by ID or name.
@@ -1226,6 +1594,11 @@ func (s *Server) GetAgent(ctx context.Context, req *GetAgentRequest) (*GetAgentR
 		)
 	}
 
+	caps, err := s.agentCapabilities(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &GetAgentResponse{
 		Id:           a.ID,
 		Name:         a.Name,
@@ -1233,6 +1606,7 @@ func (s *Server) GetAgent(ctx context.Context, req *GetAgentRequest) (*GetAgentR
 		GitBranch:    a.GitBranch.String,
 		CreatedAt:    int64ToTimestamp(a.CreatedAt),
 		LastActiveAt: int64ToTimestamp(a.LastActiveAt),
+		Capabilities: caps,
 	}, nil
 }
 
@@ -1241,6 +1615,11 @@ func (s *Server) EnsureIdentity(ctx context.Context, req *EnsureIdentityRequest)
 	if req.SessionId == "" {
 		return nil, status.Error(codes.InvalidArgument, "session_id is required")
 	}
+	if req.MintToken {
+		if err := s.requireAdmin(ctx); err != nil {
+			return nil, err
+		}
+	}
 
 	identity, err := s.identityMgr.EnsureIdentity(
 		ctx, req.SessionId, req.ProjectDir, req.GitBranch,
@@ -1249,11 +1628,21 @@ func (s *Server) EnsureIdentity(ctx context.Context, req *EnsureIdentityRequest)
 		return nil, status.Errorf(codes.Internal, "failed to ensure identity: %v", err)
 	}
 
-	return &EnsureIdentityResponse{
+	resp := &EnsureIdentityResponse{
 		AgentId:   identity.AgentID,
 		AgentName: identity.AgentName,
 		Created:   identity.CreatedAt.After(identity.LastActiveAt.Add(-1 * time.Second)),
-	}, nil
+	}
+	if req.MintToken {
+		resp.Token, err = s.mintAgentToken(
+			ctx, identity.AgentID, "session "+req.SessionId,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
 }
 
 // SaveIdentity persists an agent's current state.
@@ -1651,6 +2040,8 @@ func activityTypeToString(t ActivityType) string {
 		return "agent_registered"
 	case ActivityType_ACTIVITY_TYPE_HEARTBEAT:
 		return "heartbeat"
+	case ActivityType_ACTIVITY_TYPE_SCHEDULE_RUN:
+		return mail.ActivityTypeScheduleRun
 	default:
 		return ""
 	}
@@ -1671,6 +2062,8 @@ func stringToActivityType(s string) ActivityType {
 		return ActivityType_ACTIVITY_TYPE_AGENT_REGISTERED
 	case "heartbeat":
 		return ActivityType_ACTIVITY_TYPE_HEARTBEAT
+	case mail.ActivityTypeScheduleRun:
+		return ActivityType_ACTIVITY_TYPE_SCHEDULE_RUN
 	default:
 		return ActivityType_ACTIVITY_TYPE_UNSPECIFIED
 	}
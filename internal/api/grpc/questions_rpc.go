@@ -0,0 +1,164 @@
+package subtraterpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AskQuestion sends a structured question.
+func (s *Server) AskQuestion(ctx context.Context,
+	req *AskQuestionRequest,
+) (*QuestionResponse, error) {
+	if req.AgentId == 0 || req.Question == "" ||
+		len(req.RecipientNames) == 0 {
+
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"agent_id, recipient_names and question are required",
+		)
+	}
+
+	priority := mail.PriorityNormal
+	switch req.Priority {
+	case Priority_PRIORITY_LOW:
+		priority = mail.PriorityLow
+	case Priority_PRIORITY_URGENT:
+		priority = mail.PriorityUrgent
+	}
+
+	question, err := s.mailSvc.AskQuestion(ctx, mail.AskQuestionRequest{
+		SenderID:       req.AgentId,
+		RecipientNames: req.RecipientNames,
+		Question:       req.Question,
+		Details:        req.Details,
+		Options:        req.Options,
+		DefaultAnswer:  req.DefaultAnswer,
+		Timeout:        time.Duration(req.TimeoutSeconds) * time.Second,
+		Fallback:       req.Fallback,
+		Priority:       priority,
+	})
+	if isLimitError(err) {
+		return nil, limitStatusError(ctx, err)
+	}
+	if err != nil {
+		return nil, questionStatusError("ask question", err)
+	}
+
+	return &QuestionResponse{
+		Question: s.convertQuestion(ctx, question),
+	}, nil
+}
+
+// GetQuestion returns a question and, once resolved, its answer.
+func (s *Server) GetQuestion(ctx context.Context,
+	req *GetQuestionRequest,
+) (*QuestionResponse, error) {
+	if req.QuestionId == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "question_id is required",
+		)
+	}
+
+	question, err := s.mailSvc.GetQuestion(ctx, req.QuestionId)
+	if err != nil {
+		return nil, questionStatusError("get question", err)
+	}
+
+	return &QuestionResponse{
+		Question: s.convertQuestion(ctx, question),
+	}, nil
+}
+
+// AnswerQuestion answers a question by replying in its thread.
+func (s *Server) AnswerQuestion(ctx context.Context,
+	req *AnswerQuestionRequest,
+) (*QuestionResponse, error) {
+	if req.QuestionId == "" || req.AgentId == 0 || req.Answer == "" {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"question_id, agent_id and answer are required",
+		)
+	}
+
+	question, err := s.mailSvc.AnswerQuestion(
+		ctx, req.QuestionId, req.AgentId, req.Answer,
+	)
+	if err != nil {
+		return nil, questionStatusError("answer question", err)
+	}
+
+	return &QuestionResponse{
+		Question: s.convertQuestion(ctx, question),
+	}, nil
+}
+
+// questionStatusError maps question errors from the mail service onto gRPC
+// status codes.
+func questionStatusError(operation string, err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, mail.ErrQuestionNotFound):
+		code = codes.NotFound
+
+	case errors.Is(err, mail.ErrQuestionResolved):
+		code = codes.FailedPrecondition
+
+	case errors.Is(err, mail.ErrInvalidQuestion):
+		code = codes.InvalidArgument
+
+	case errors.Is(err, mail.ErrUnauthorized):
+		code = codes.PermissionDenied
+	}
+
+	return status.Errorf(code, "failed to %s: %v", operation, err)
+}
+
+// convertQuestion converts a question to its proto form, resolving the name
+// of the agent that answered it.
+func (s *Server) convertQuestion(ctx context.Context,
+	question store.Question,
+) *Question {
+	resp := &Question{
+		QuestionId:      question.QuestionID,
+		MessageId:       question.MessageID,
+		ThreadId:        question.ThreadID,
+		AskerId:         question.AskerID,
+		Question:        question.Question,
+		Options:         question.Options,
+		DefaultAnswer:   question.DefaultAnswer,
+		TimeoutSeconds:  int64(question.Timeout / time.Second),
+		Fallback:        question.Fallback,
+		State:           question.State,
+		Answer:          question.Answer,
+		OptionIndex:     int32(mail.QuestionOptionIndex(question)),
+		AnsweredBy:      question.AnsweredBy,
+		AnswerMessageId: question.AnswerMessageID,
+		CreatedAt:       timestamppb.New(question.CreatedAt),
+	}
+	if question.TimeoutAt != nil {
+		resp.TimeoutAt = timestamppb.New(*question.TimeoutAt)
+	}
+	if question.EscalatedAt != nil {
+		resp.EscalatedAt = timestamppb.New(*question.EscalatedAt)
+	}
+	if question.AnsweredAt != nil {
+		resp.AnsweredAt = timestamppb.New(*question.AnsweredAt)
+	}
+	if question.AnsweredBy != 0 {
+		agent, err := s.store.Queries().GetAgent(
+			ctx, question.AnsweredBy,
+		)
+		if err == nil {
+			resp.AnsweredByName = agent.Name
+		}
+	}
+
+	return resp
+}
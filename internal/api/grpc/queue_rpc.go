@@ -0,0 +1,141 @@
+package subtraterpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// queueStatusError maps queue errors from the mail service onto gRPC status
+// codes.
+func queueStatusError(operation string, err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, mail.ErrTopicNotFound):
+		code = codes.NotFound
+
+	case errors.Is(err, mail.ErrNotQueueTopic):
+		code = codes.InvalidArgument
+
+	case errors.Is(err, mail.ErrNotSubscribed):
+		code = codes.PermissionDenied
+
+	case errors.Is(err, mail.ErrLeaseNotHeld):
+		code = codes.FailedPrecondition
+	}
+
+	return status.Errorf(code, "failed to %s: %v", operation, err)
+}
+
+// ClaimQueueMessage leases the next available message on a queue topic to
+// the calling agent.
+func (s *Server) ClaimQueueMessage(ctx context.Context,
+	req *ClaimQueueMessageRequest,
+) (*ClaimQueueMessageResponse, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "agent_id is required")
+	}
+	if req.TopicName == "" {
+		return nil, status.Error(codes.InvalidArgument, "topic_name is required")
+	}
+	if req.VisibilityTimeoutSeconds < 0 {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"visibility_timeout_seconds must not be negative",
+		)
+	}
+
+	claim, err := s.mailSvc.ClaimQueueMessage(
+		ctx, req.AgentId, req.TopicName,
+		time.Duration(req.VisibilityTimeoutSeconds)*time.Second,
+	)
+	if err != nil {
+		return nil, queueStatusError("claim message", err)
+	}
+	if claim == nil {
+		return &ClaimQueueMessageResponse{}, nil
+	}
+
+	return &ClaimQueueMessageResponse{
+		Claimed:        true,
+		Message:        convertMessage(&claim.Message),
+		LeaseExpiresAt: timestamppb.New(claim.LeaseExpiresAt),
+		DeliveryCount:  int32(claim.DeliveryCount),
+	}, nil
+}
+
+// AckQueueMessage completes the calling agent's lease on a queue message.
+func (s *Server) AckQueueMessage(ctx context.Context,
+	req *AckQueueMessageRequest,
+) (*AckQueueMessageResponse, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "agent_id is required")
+	}
+	if req.MessageId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "message_id is required")
+	}
+
+	err := s.mailSvc.AckQueueMessage(ctx, req.AgentId, req.MessageId)
+	if err != nil {
+		return nil, queueStatusError("ack message", err)
+	}
+
+	return &AckQueueMessageResponse{Success: true}, nil
+}
+
+// ReleaseQueueMessage hands the calling agent's lease on a queue message back
+// to the queue.
+func (s *Server) ReleaseQueueMessage(ctx context.Context,
+	req *ReleaseQueueMessageRequest,
+) (*ReleaseQueueMessageResponse, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "agent_id is required")
+	}
+	if req.MessageId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "message_id is required")
+	}
+
+	err := s.mailSvc.ReleaseQueueMessage(
+		ctx, req.AgentId, req.MessageId, req.Reason,
+	)
+	if err != nil {
+		return nil, queueStatusError("release message", err)
+	}
+
+	return &ReleaseQueueMessageResponse{Success: true}, nil
+}
+
+// ExtendQueueLease pushes out the calling agent's lease on a queue message.
+func (s *Server) ExtendQueueLease(ctx context.Context,
+	req *ExtendQueueLeaseRequest,
+) (*ExtendQueueLeaseResponse, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "agent_id is required")
+	}
+	if req.MessageId == 0 {
+		return nil, status.Error(codes.InvalidArgument, "message_id is required")
+	}
+	if req.VisibilityTimeoutSeconds < 0 {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"visibility_timeout_seconds must not be negative",
+		)
+	}
+
+	expiresAt, err := s.mailSvc.ExtendQueueLease(
+		ctx, req.AgentId, req.MessageId,
+		time.Duration(req.VisibilityTimeoutSeconds)*time.Second,
+	)
+	if err != nil {
+		return nil, queueStatusError("extend lease", err)
+	}
+
+	return &ExtendQueueLeaseResponse{
+		LeaseExpiresAt: timestamppb.New(expiresAt),
+	}, nil
+}
@@ -0,0 +1,130 @@
+package subtraterpc
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// retryAfterHeader is the response header a refused call's retry delay is
+// sent in, in whole seconds. The REST gateway forwards it as Retry-After.
+const retryAfterHeader = "retry-after"
+
+// rateLimitedMethods maps the RPCs that are rate limited to their class.
+// CreateReview isn't listed, as the review service charges every review it
+// creates, however it was asked to.
+var rateLimitedMethods = map[string]ratelimit.Class{
+	"SendMail":         ratelimit.ClassSend,
+	"ReplyToThread":    ratelimit.ClassSend,
+	"AskQuestion":      ratelimit.ClassSend,
+	"EditMessage":      ratelimit.ClassSend,
+	"Publish":          ratelimit.ClassPublish,
+	"Search":           ratelimit.ClassSearch,
+	"SearchAll":        ratelimit.ClassSearch,
+	"SemanticSearch":   ratelimit.ClassSearch,
+	"SimilarMessages":  ratelimit.ClassSearch,
+	"CreatePlanReview": ratelimit.ClassReview,
+}
+
+// callerFields are the request fields naming the agent a rate limited call
+// is charged to, in the order they're checked.
+var callerFields = []protoreflect.Name{
+	"sender_id", "agent_id", "requester_id",
+}
+
+// rateLimitUnaryInterceptor charges rate limited RPCs to the calling
+// agent's token bucket, refusing them with ResourceExhausted once it is
+// empty. It runs after authentication, so an agent credential's calls are
+// charged to its own agent.
+func (s *Server) rateLimitUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	class, ok := rateLimitedMethods[path.Base(info.FullMethod)]
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	agentID := callerAgentID(req)
+	if agentID == 0 {
+		return handler(ctx, req)
+	}
+
+	if err := s.limiter.Allow(ctx, agentID, class); err != nil {
+		return nil, limitStatusError(ctx, err)
+	}
+
+	return handler(ctx, req)
+}
+
+// callerAgentID returns the agent a request is made by, or 0 if it names
+// none.
+func callerAgentID(req interface{}) int64 {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return 0
+	}
+
+	m := msg.ProtoReflect()
+	for _, name := range callerFields {
+		fd := m.Descriptor().Fields().ByName(name)
+		if fd == nil || fd.Kind() != protoreflect.Int64Kind ||
+			fd.IsList() {
+
+			continue
+		}
+
+		if id := m.Get(fd).Int(); id != 0 {
+			return id
+		}
+	}
+
+	return 0
+}
+
+// limitStatusError converts an error refusing a call over a rate limit or
+// quota to a ResourceExhausted status carrying the retry delay, which is
+// also sent in the retry-after header. Other errors become Internal.
+func limitStatusError(ctx context.Context, err error) error {
+	retryAfter, ok := ratelimit.RetryAfter(err)
+	if !ok {
+		return status.Errorf(codes.Internal, "failed to check rate "+
+			"limit: %v", err)
+	}
+
+	seconds := int64((retryAfter + time.Second - 1) / time.Second)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(
+		retryAfterHeader, strconv.FormatInt(seconds, 10),
+	))
+
+	st := status.New(codes.ResourceExhausted, err.Error())
+	detailed, detailErr := st.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(retryAfter),
+	})
+	if detailErr != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+// isLimitError reports whether err refused a call over a rate limit or
+// quota.
+func isLimitError(err error) bool {
+	return errors.Is(err, ratelimit.ErrRateLimited) ||
+		errors.Is(err, ratelimit.ErrQuotaExceeded)
+}
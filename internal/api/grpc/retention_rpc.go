@@ -0,0 +1,135 @@
+package subtraterpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CollectGarbage enforces topic retention, or reports what would be deleted
+// when dry_run is set.
+func (s *Server) CollectGarbage(ctx context.Context,
+	req *CollectGarbageRequest,
+) (*CollectGarbageResponse, error) {
+	compactor := mail.NewRetentionCompactor(mail.RetentionCompactorConfig{
+		Store: s.retentionStore,
+	})
+
+	report, err := compactor.Compact(ctx, time.Now(), req.DryRun)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.Internal, "failed to collect garbage: %v", err,
+		)
+	}
+
+	topics := make([]*TopicRetentionReport, len(report.Topics))
+	for i, topic := range report.Topics {
+		topics[i] = &TopicRetentionReport{
+			TopicId:          topic.TopicID,
+			TopicName:        topic.TopicName,
+			RetentionSeconds: topic.RetentionSeconds,
+			ExpiredCount:     topic.ExpiredCount,
+			DeletableCount:   topic.DeletableCount,
+			RecipientCount:   topic.RecipientCount,
+		}
+	}
+
+	return &CollectGarbageResponse{
+		DryRun:                 report.DryRun,
+		Topics:                 topics,
+		MessagesDeleted:        report.MessagesDeleted,
+		RecipientsDeleted:      report.RecipientsDeleted,
+		ConsumerOffsetsDeleted: report.ConsumerOffsetsDeleted,
+	}, nil
+}
+
+// SetLegalHold places or lifts a legal hold on a message or a thread.
+func (s *Server) SetLegalHold(ctx context.Context,
+	req *SetLegalHoldRequest,
+) (*SetLegalHoldResponse, error) {
+	if (req.MessageId == 0) == (req.ThreadId == "") {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"exactly one of message_id and thread_id is required",
+		)
+	}
+
+	var (
+		updated int64
+		err     error
+	)
+	if req.MessageId != 0 {
+		updated, err = s.retentionStore.SetMessageLegalHold(
+			ctx, req.MessageId, req.Hold,
+		)
+	} else {
+		updated, err = s.retentionStore.SetThreadLegalHold(
+			ctx, req.ThreadId, req.Hold,
+		)
+	}
+	if err != nil {
+		return nil, status.Errorf(
+			codes.Internal, "failed to set legal hold: %v", err,
+		)
+	}
+	if updated == 0 {
+		return nil, status.Error(codes.NotFound, "no matching messages")
+	}
+
+	return &SetLegalHoldResponse{MessagesUpdated: updated}, nil
+}
+
+// SetTopicRetention sets how long a topic's messages are kept before
+// retention deletes them. A retention of 0 keeps them forever.
+func (s *Server) SetTopicRetention(ctx context.Context,
+	req *SetTopicRetentionRequest,
+) (*SetTopicRetentionResponse, error) {
+	if req.TopicName == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "topic_name is required",
+		)
+	}
+	if req.RetentionSeconds < 0 {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"retention_seconds can't be negative",
+		)
+	}
+
+	topic, err := s.retentionStore.GetTopicByName(ctx, req.TopicName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(
+			codes.NotFound, "topic %q not found", req.TopicName,
+		)
+	}
+	if err != nil {
+		return nil, status.Errorf(
+			codes.Internal, "failed to get topic: %v", err,
+		)
+	}
+
+	err = s.retentionStore.UpdateTopicRetention(
+		ctx, topic.ID, req.RetentionSeconds,
+	)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.Internal, "failed to set topic retention: %v", err,
+		)
+	}
+
+	return &SetTopicRetentionResponse{
+		Topic: &Topic{
+			Id:               topic.ID,
+			Name:             topic.Name,
+			TopicType:        topic.TopicType,
+			CreatedAt:        timestamppb.New(topic.CreatedAt),
+			RetentionSeconds: req.RetentionSeconds,
+		},
+	}, nil
+}
@@ -3,6 +3,7 @@ package subtraterpc
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/roasbeef/subtrate/internal/review"
@@ -35,6 +36,30 @@ func (s *Server) resolveReviewID(
 	return resolved, nil
 }
 
+// requireReviewRequester returns an error unless the caller may act on a
+// review as the agent that requested it.
+func (s *Server) requireReviewRequester(ctx context.Context,
+	reviewID string,
+) error {
+	if s.authSvc == nil {
+		return nil
+	}
+
+	r, err := s.store.Queries().GetReview(ctx, reviewID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Errorf(
+			codes.NotFound, "review %s not found", reviewID,
+		)
+	}
+	if err != nil {
+		return status.Errorf(
+			codes.Internal, "failed to get review: %v", err,
+		)
+	}
+
+	return s.requireOwner(ctx, r.RequesterID, "review")
+}
+
 // CreateReview creates a new code review request via the review actor.
 func (s *Server) CreateReview(
 	ctx context.Context, req *CreateReviewRequest,
@@ -114,6 +139,9 @@ func (s *Server) CreateReview(
 			codes.Internal, "unexpected response type",
 		)
 	}
+	if isLimitError(createResp.Error) {
+		return nil, limitStatusError(ctx, createResp.Error)
+	}
 
 	result := &CreateReviewResponse{
 		ReviewId: createResp.ReviewID,
@@ -258,6 +286,9 @@ func (s *Server) ResubmitReview(
 	if err != nil {
 		return nil, err
 	}
+	if err := s.requireReviewRequester(ctx, reviewID); err != nil {
+		return nil, err
+	}
 
 	resp, err := s.askReview(ctx, review.ResubmitMsg{
 		ReviewID:  reviewID,
@@ -301,6 +332,9 @@ func (s *Server) CancelReview(
 	if err != nil {
 		return nil, err
 	}
+	if err := s.requireReviewRequester(ctx, reviewID); err != nil {
+		return nil, err
+	}
 
 	resp, err := s.askReview(ctx, review.CancelReviewMsg{
 		ReviewID: reviewID,
@@ -341,6 +375,9 @@ func (s *Server) DeleteReview(
 	if err != nil {
 		return nil, err
 	}
+	if err := s.requireReviewRequester(ctx, reviewID); err != nil {
+		return nil, err
+	}
 
 	resp, err := s.askReview(ctx, review.DeleteReviewMsg{
 		ReviewID: reviewID,
@@ -445,6 +482,9 @@ func (s *Server) UpdateIssueStatus(
 	if err != nil {
 		return nil, err
 	}
+	if err := s.requireReviewRequester(ctx, reviewID); err != nil {
+		return nil, err
+	}
 
 	resp, err := s.askReview(ctx, review.UpdateIssueMsg{
 		ReviewID: reviewID,
@@ -0,0 +1,128 @@
+package subtraterpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// EditMessage replaces the subject or body of a sent message.
+func (s *Server) EditMessage(ctx context.Context,
+	req *EditMessageRequest,
+) (*EditMessageResponse, error) {
+	if req.AgentId == 0 || req.MessageId == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"agent_id and message_id are required",
+		)
+	}
+
+	resp, err := s.mailSvc.EditMessage(ctx, mail.EditMessageRequest{
+		SenderID:  req.AgentId,
+		MessageID: req.MessageId,
+		Subject:   req.Subject,
+		Body:      req.Body,
+	})
+	if isLimitError(err) {
+		return nil, limitStatusError(ctx, err)
+	}
+	if err != nil {
+		return nil, revisionStatusError("edit message", err)
+	}
+
+	return &EditMessageResponse{
+		MessageId: resp.Message.ID,
+		Revision:  int32(resp.Revision),
+		EditedAt:  timeToTimestamp(resp.Message.EditedAt),
+	}, nil
+}
+
+// RecallMessage deletes a sent message that no recipient has read yet.
+func (s *Server) RecallMessage(ctx context.Context,
+	req *RecallMessageRequest,
+) (*RecallMessageResponse, error) {
+	if req.AgentId == 0 || req.MessageId == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"agent_id and message_id are required",
+		)
+	}
+
+	err := s.mailSvc.RecallMessage(ctx, req.AgentId, req.MessageId)
+	if err != nil {
+		return nil, revisionStatusError("recall message", err)
+	}
+
+	return &RecallMessageResponse{}, nil
+}
+
+// GetMessageHistory returns every version of a message, oldest first.
+func (s *Server) GetMessageHistory(ctx context.Context,
+	req *GetMessageHistoryRequest,
+) (*GetMessageHistoryResponse, error) {
+	if req.AgentId == 0 || req.MessageId == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"agent_id and message_id are required",
+		)
+	}
+
+	history, err := s.mailSvc.GetMessageHistory(
+		ctx, req.AgentId, req.MessageId,
+	)
+	if err != nil {
+		return nil, revisionStatusError("get message history", err)
+	}
+
+	return &GetMessageHistoryResponse{
+		Revisions: convertMessageRevisions(history.Versions()),
+	}, nil
+}
+
+// revisionStatusError maps message edit and recall errors from the mail
+// service onto gRPC status codes.
+func revisionStatusError(operation string, err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, mail.ErrMessageNotFound):
+		code = codes.NotFound
+
+	case errors.Is(err, mail.ErrUnauthorized):
+		code = codes.PermissionDenied
+
+	case errors.Is(err, mail.ErrInvalidEdit):
+		code = codes.InvalidArgument
+
+	case errors.Is(err, mail.ErrMessageLegalHold),
+		errors.Is(err, mail.ErrMessageNotRecallable):
+
+		code = codes.FailedPrecondition
+	}
+
+	return status.Errorf(code, "failed to %s: %v", operation, err)
+}
+
+// convertMessageRevisions converts message versions to their proto form. The
+// current version of a message has no replaced_at.
+func convertMessageRevisions(
+	revisions []store.MessageRevision,
+) []*MessageRevision {
+	result := make([]*MessageRevision, len(revisions))
+	for i, rev := range revisions {
+		result[i] = &MessageRevision{
+			MessageId:  rev.MessageID,
+			Revision:   int32(rev.Revision),
+			Subject:    sanitizeUTF8(rev.Subject),
+			Body:       sanitizeUTF8(rev.Body),
+			CreatedAt:  timestamppb.New(rev.CreatedAt),
+			ReplacedAt: timeToTimestamp(&rev.ReplacedAt),
+		}
+	}
+
+	return result
+}
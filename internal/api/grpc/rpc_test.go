@@ -3,22 +3,31 @@ package subtraterpc
 import (
 	"context"
 	"log/slog"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/roasbeef/subtrate/internal/activity"
 	"github.com/roasbeef/subtrate/internal/agent"
+	"github.com/roasbeef/subtrate/internal/auth"
 	"github.com/roasbeef/subtrate/internal/baselib/actor"
 	"github.com/roasbeef/subtrate/internal/db"
 	"github.com/roasbeef/subtrate/internal/mail"
 	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/roasbeef/subtrate/internal/tlscert"
 )
 
 // testHarness holds all the components needed for gRPC integration tests.
@@ -85,6 +94,15 @@ func newTestHarness(t *testing.T) *testHarness {
 		mailSvc,
 	)
 
+	// The notification hub backs the streaming RPCs.
+	notificationHub := actor.RegisterWithSystem(
+		actorSystem,
+		"notification-hub",
+		mail.NotificationHubKey,
+		mail.NewNotificationHub(),
+	)
+	mailSvc.SetNotificationHub(notificationHub)
+
 	activitySvc := activity.NewService(activity.ServiceConfig{
 		Store: storage,
 	})
@@ -110,7 +128,7 @@ func newTestHarness(t *testing.T) *testHarness {
 	heartbeatMgr := agent.NewHeartbeatManager(agentReg, nil)
 
 	// Create and start server.
-	server := NewServer(cfg, sqliteStore.Store, mailSvc, agentReg, identityMgr, heartbeatMgr, nil)
+	server := NewServer(cfg, sqliteStore.Store, mailSvc, agentReg, identityMgr, heartbeatMgr, notificationHub)
 	err = server.Start()
 	require.NoError(t, err)
 
@@ -1723,6 +1741,64 @@ func TestMailService_Subscribe_SameTopic(t *testing.T) {
 	require.Equal(t, resp1.TopicId, resp2.TopicId)
 }
 
+func TestMailService_SetTopicRetention(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+
+	ctx := context.Background()
+
+	agentID := h.createTestAgent("RetentionAgent")
+	_, err := h.mailClient.Subscribe(ctx, &SubscribeRequest{
+		AgentId:   agentID,
+		TopicName: "retention-topic",
+	})
+	require.NoError(t, err)
+
+	resp, err := h.mailClient.SetTopicRetention(
+		ctx, &SetTopicRetentionRequest{
+			TopicName:        "retention-topic",
+			RetentionSeconds: 30 * 24 * 60 * 60,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "retention-topic", resp.Topic.Name)
+	require.EqualValues(t, 30*24*60*60, resp.Topic.RetentionSeconds)
+
+	topics, err := h.mailClient.ListTopics(ctx, &ListTopicsRequest{})
+	require.NoError(t, err)
+
+	var retention int64 = -1
+	for _, topic := range topics.Topics {
+		if topic.Name == "retention-topic" {
+			retention = topic.RetentionSeconds
+		}
+	}
+	require.EqualValues(t, 30*24*60*60, retention)
+
+	// A retention of 0 keeps the topic's messages forever.
+	resp, err = h.mailClient.SetTopicRetention(
+		ctx, &SetTopicRetentionRequest{TopicName: "retention-topic"},
+	)
+	require.NoError(t, err)
+	require.Zero(t, resp.Topic.RetentionSeconds)
+
+	_, err = h.mailClient.SetTopicRetention(
+		ctx, &SetTopicRetentionRequest{
+			TopicName:        "retention-topic",
+			RetentionSeconds: -1,
+		},
+	)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = h.mailClient.SetTopicRetention(
+		ctx, &SetTopicRetentionRequest{
+			TopicName:        "no-such-topic",
+			RetentionSeconds: 60,
+		},
+	)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
 func TestMailService_Publish(t *testing.T) {
 	h := newTestHarness(t)
 	defer h.Close()
@@ -2293,6 +2369,63 @@ func TestReplyToThread_MultiParticipant(t *testing.T) {
 	require.Len(t, threadResp.Messages, 4)
 }
 
+// TestReplyToThread_Message tests replying to a specific message, which goes
+// to its sender and other recipients rather than to the thread's senders.
+func TestReplyToThread_Message(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+
+	ctx := context.Background()
+
+	senderID := h.createTestAgent("ReplyAllSender")
+	replierID := h.createTestAgent("ReplyAllReplier")
+	h.createTestAgent("ReplyAllCc")
+	outsiderID := h.createTestAgent("ReplyAllOutsider")
+
+	initialResp, err := h.mailClient.SendMail(ctx, &SendMailRequest{
+		SenderId: senderID,
+		RecipientNames: []string{
+			"ReplyAllReplier", "ReplyAllCc",
+		},
+		Subject: "Design review",
+		Body:    "Please take a look.",
+	})
+	require.NoError(t, err)
+
+	replyResp, err := h.mailClient.ReplyToThread(ctx, &ReplyToThreadRequest{
+		SenderId:  replierID,
+		MessageId: initialResp.MessageId,
+		Body:      "Looks good.",
+		Quote:     true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, initialResp.ThreadId, replyResp.ThreadId)
+	require.Equal(t, []string{"ReplyAllSender", "ReplyAllCc"},
+		replyResp.RecipientNames)
+
+	readResp, err := h.mailClient.ReadMessage(ctx, &ReadMessageRequest{
+		AgentId:   senderID,
+		MessageId: replyResp.MessageId,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Re: Design review", readResp.Message.Subject)
+	require.Contains(t, readResp.Message.Body, "> Please take a look.")
+
+	_, err = h.mailClient.ReplyToThread(ctx, &ReplyToThreadRequest{
+		SenderId:  outsiderID,
+		MessageId: initialResp.MessageId,
+		Body:      "Me too.",
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = h.mailClient.ReplyToThread(ctx, &ReplyToThreadRequest{
+		SenderId:  replierID,
+		MessageId: 999999,
+		Body:      "Hello?",
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
 // TestReplyToThread_ValidationErrors tests error cases for ReplyToThread.
 func TestReplyToThread_ValidationErrors(t *testing.T) {
 	h := newTestHarness(t)
@@ -2322,3 +2455,820 @@ func TestReplyToThread_ValidationErrors(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "thread not found")
 }
+
+// TestSearch_Query tests that Search applies query filters and rejects
+// malformed queries as invalid arguments.
+func TestSearch_Query(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+
+	ctx := context.Background()
+
+	senderID := h.createTestAgent("QuerySender")
+	recipientID := h.createTestAgent("QueryRecipient")
+
+	for _, priority := range []Priority{
+		Priority_PRIORITY_URGENT, Priority_PRIORITY_LOW,
+	} {
+		_, err := h.mailClient.SendMail(ctx, &SendMailRequest{
+			SenderId:       senderID,
+			RecipientNames: []string{"QueryRecipient"},
+			Subject:        "Rollout " + priority.String(),
+			Body:           "Rollout status.",
+			Priority:       priority,
+		})
+		require.NoError(t, err)
+	}
+
+	resp, err := h.mailClient.Search(ctx, &SearchRequest{
+		AgentId: recipientID,
+		Query:   "rollout from:QuerySender priority:urgent",
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	require.Equal(t, Priority_PRIORITY_URGENT, resp.Results[0].Priority)
+	require.Equal(t, "QuerySender", resp.Results[0].SenderName)
+
+	_, err = h.mailClient.Search(ctx, &SearchRequest{
+		AgentId: recipientID,
+		Query:   `rollout "unterminated`,
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Contains(t, err.Error(), "unterminated quote at column 9")
+
+	// Results come a page at a time, with snippets in place of bodies
+	// unless they're asked for.
+	seen := make(map[int64]bool)
+	cursor := ""
+	for page := 0; page < 2; page++ {
+		resp, err = h.mailClient.Search(ctx, &SearchRequest{
+			AgentId: recipientID,
+			Query:   "rollout",
+			Limit:   1,
+			Cursor:  cursor,
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 2, resp.Total)
+		require.Len(t, resp.Results, 1)
+		require.Len(t, resp.Hits, 1)
+		require.Empty(t, resp.Results[0].Body)
+		require.Equal(t, resp.Results[0].Id, resp.Hits[0].MessageId)
+		require.Equal(t, "**Rollout** status.", resp.Hits[0].Snippet)
+		require.Contains(t, resp.Hits[0].SubjectHighlight,
+			"**Rollout**")
+
+		seen[resp.Results[0].Id] = true
+		cursor = resp.NextCursor
+	}
+	require.Len(t, seen, 2)
+	require.Empty(t, cursor)
+
+	resp, err = h.mailClient.Search(ctx, &SearchRequest{
+		AgentId:     recipientID,
+		Query:       "rollout",
+		IncludeBody: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Rollout status.", resp.Results[0].Body)
+
+	_, err = h.mailClient.Search(ctx, &SearchRequest{
+		AgentId: recipientID,
+		Query:   "rollout",
+		Cursor:  "not-a-cursor",
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// TestWatchInbox tests that a watcher wakes only for mail passing its
+// filters, and that the server holds a single watcher lease per agent that
+// a second watcher can only take over explicitly.
+func TestWatchInbox(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	watcherID := h.createTestAgent("Watcher")
+	bobID := h.createTestAgent("Bob")
+	carolID := h.createTestAgent("Carol")
+
+	send := func(senderID int64, subject string, priority Priority) {
+		t.Helper()
+
+		_, err := h.mailClient.SendMail(ctx, &SendMailRequest{
+			SenderId:       senderID,
+			RecipientNames: []string{"Watcher"},
+			Subject:        subject,
+			Body:           "Body.",
+			Priority:       priority,
+		})
+		require.NoError(t, err)
+	}
+
+	// Backlog that doesn't pass the filters doesn't wake the watcher.
+	send(bobID, "Backlog", Priority_PRIORITY_NORMAL)
+
+	noHolder, err := h.mailClient.WatchInbox(ctx, &WatchInboxRequest{
+		AgentId: watcherID,
+	})
+	require.NoError(t, err)
+	_, err = noHolder.Recv()
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	first, err := h.mailClient.WatchInbox(ctx, &WatchInboxRequest{
+		AgentId:     watcherID,
+		Holder:      "host-a:1",
+		Priorities:  []Priority{Priority_PRIORITY_URGENT},
+		SenderNames: []string{"Bob"},
+	})
+	require.NoError(t, err)
+
+	event, err := first.Recv()
+	require.NoError(t, err)
+	require.Equal(t, WatchEventType_WATCH_EVENT_TYPE_ARMED, event.Type)
+	require.True(t, event.LeaseExpiresAt.AsTime().After(time.Now()))
+
+	send(carolID, "Wrong sender", Priority_PRIORITY_URGENT)
+	send(bobID, "Wrong priority", Priority_PRIORITY_LOW)
+	send(bobID, "Wake up", Priority_PRIORITY_URGENT)
+
+	event, err = first.Recv()
+	require.NoError(t, err)
+	require.Equal(t, WatchEventType_WATCH_EVENT_TYPE_WAKE, event.Type)
+	require.Len(t, event.Messages, 1)
+	require.Equal(t, "Wake up", event.Messages[0].Subject)
+
+	watcher, err := h.mailClient.GetWatcher(ctx, &GetWatcherRequest{
+		AgentId: watcherID,
+	})
+	require.NoError(t, err)
+	require.True(t, watcher.Armed)
+	require.Equal(t, "host-a:1", watcher.Holder)
+
+	// A second watcher is turned away while the first holds the lease.
+	second, err := h.mailClient.WatchInbox(ctx, &WatchInboxRequest{
+		AgentId: watcherID,
+		Holder:  "host-b:2",
+	})
+	require.NoError(t, err)
+	_, err = second.Recv()
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	// Unless it takes the lease over, which stands the first down.
+	third, err := h.mailClient.WatchInbox(ctx, &WatchInboxRequest{
+		AgentId:  watcherID,
+		Holder:   "host-b:2",
+		Takeover: true,
+	})
+	require.NoError(t, err)
+	event, err = third.Recv()
+	require.NoError(t, err)
+	require.Equal(t, WatchEventType_WATCH_EVENT_TYPE_ARMED, event.Type)
+
+	event, err = first.Recv()
+	require.NoError(t, err)
+	require.Equal(
+		t, WatchEventType_WATCH_EVENT_TYPE_LEASE_LOST, event.Type,
+	)
+
+	// With no filters, the unread backlog wakes it straight away.
+	event, err = third.Recv()
+	require.NoError(t, err)
+	require.Equal(t, WatchEventType_WATCH_EVENT_TYPE_WAKE, event.Type)
+	require.Len(t, event.Messages, 4)
+
+	watcher, err = h.mailClient.GetWatcher(ctx, &GetWatcherRequest{
+		AgentId: watcherID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "host-b:2", watcher.Holder)
+
+	unknown, err := h.mailClient.WatchInbox(ctx, &WatchInboxRequest{
+		AgentId:     watcherID,
+		Holder:      "host-c:3",
+		SenderNames: []string{"Nobody"},
+	})
+	require.NoError(t, err)
+	_, err = unknown.Recv()
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// TestWatchInboxSnoozeWake tests that a message waking from its snooze wakes
+// the watcher, even when it's older than the mail the watcher has seen.
+func TestWatchInboxSnoozeWake(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	watcherID := h.createTestAgent("Watcher")
+	bobID := h.createTestAgent("Bob")
+
+	send := func(subject string) int64 {
+		t.Helper()
+
+		resp, err := h.mailClient.SendMail(ctx, &SendMailRequest{
+			SenderId:       bobID,
+			RecipientNames: []string{"Watcher"},
+			Subject:        subject,
+			Body:           "Body.",
+			Priority:       Priority_PRIORITY_NORMAL,
+		})
+		require.NoError(t, err)
+
+		return resp.MessageId
+	}
+
+	snoozedID := send("Later")
+	_, err := h.mailClient.UpdateState(ctx, &UpdateStateRequest{
+		AgentId:      watcherID,
+		MessageId:    snoozedID,
+		NewState:     MessageState_STATE_SNOOZED,
+		SnoozedUntil: timestamppb.New(time.Now().Add(time.Second)),
+	})
+	require.NoError(t, err)
+	seenID := send("Seen")
+
+	stream, err := h.mailClient.WatchInbox(ctx, &WatchInboxRequest{
+		AgentId:        watcherID,
+		Holder:         "host-a:1",
+		AfterMessageId: seenID,
+	})
+	require.NoError(t, err)
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, WatchEventType_WATCH_EVENT_TYPE_ARMED, event.Type)
+
+	// The backlog holds nothing newer than what the watcher has seen, so
+	// the next wake is the snoozed message coming back.
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, WatchEventType_WATCH_EVENT_TYPE_WAKE, event.Type)
+	require.Len(t, event.Messages, 1)
+	require.Equal(t, snoozedID, event.Messages[0].Id)
+}
+
+// TestAgentCapabilities_RPC tests that capabilities declared through
+// RegisterAgent and UpdateAgent drive discovery and cap: routing.
+func TestAgentCapabilities_RPC(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+
+	ctx := context.Background()
+
+	sender, err := h.agentClient.RegisterAgent(
+		ctx, &RegisterAgentRequest{Name: "Sender"},
+	)
+	require.NoError(t, err)
+
+	reviewer, err := h.agentClient.RegisterAgent(
+		ctx, &RegisterAgentRequest{
+			Name: "Reviewer",
+			Capabilities: &AgentCapabilities{
+				Languages: []string{"Go"},
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"go"}, reviewer.Capabilities.Languages)
+
+	_, err = h.agentClient.RegisterAgent(ctx, &RegisterAgentRequest{
+		Name: "Broken",
+		Capabilities: &AgentCapabilities{
+			Skills: []string{"two words"},
+		},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	updated, err := h.agentClient.UpdateAgent(ctx, &UpdateAgentRequest{
+		Id: reviewer.AgentId,
+		Capabilities: &AgentCapabilities{
+			Languages:      []string{"go"},
+			Skills:         []string{"go-review"},
+			MaxConcurrency: 2,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(
+		t, []string{"go-review"},
+		updated.Agent.Capabilities.Skills,
+	)
+	require.EqualValues(t, 2, updated.Agent.Capabilities.MaxConcurrency)
+
+	discovered, err := h.agentClient.DiscoverAgents(
+		ctx, &DiscoverAgentsRequest{CapabilityFilter: "cap:go-review"},
+	)
+	require.NoError(t, err)
+	require.Len(t, discovered.Agents, 1)
+	require.Equal(t, "Reviewer", discovered.Agents[0].Name)
+
+	sendResp, err := h.mailClient.SendMail(ctx, &SendMailRequest{
+		SenderId:       sender.AgentId,
+		RecipientNames: []string{"cap:go-review"},
+		Subject:        "Please review",
+		Body:           "PR #42",
+		Priority:       Priority_PRIORITY_NORMAL,
+	})
+	require.NoError(t, err)
+
+	_, err = h.mailClient.SendMail(ctx, &SendMailRequest{
+		SenderId:       sender.AgentId,
+		RecipientNames: []string{"cap:rust"},
+		Subject:        "Nobody",
+		Priority:       Priority_PRIORITY_NORMAL,
+	})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	routes, err := h.agentClient.ListCapabilityRoutes(
+		ctx, &ListCapabilityRoutesRequest{Capability: "go-review"},
+	)
+	require.NoError(t, err)
+	require.Len(t, routes.Routes, 1)
+	require.Equal(t, "Reviewer", routes.Routes[0].AgentName)
+	require.Equal(t, sendResp.MessageId, routes.Routes[0].MessageId)
+}
+
+// TestCredentials_RPC tests that with auth enabled every RPC needs a live
+// credential, and that agent credentials can only act as their own agent.
+func TestCredentials_RPC(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+
+	ctx := context.Background()
+	aliceID := h.createTestAgent("Alice")
+	bobID := h.createTestAgent("Bob")
+
+	// Credentials can be minted before auth is turned on.
+	admin, err := h.agentClient.MintCredential(ctx, &MintCredentialRequest{
+		Admin: true,
+		Name:  "test",
+	})
+	require.NoError(t, err)
+	require.True(t, admin.Credential.Admin)
+
+	// Start a second server over the same database with auth enabled.
+	cfg := h.server.cfg
+	cfg.ListenAddr = "localhost:0"
+	cfg.Auth = auth.NewService(h.storage)
+	server := NewServer(
+		cfg, h.server.store, h.mailSvc, h.agentReg, h.identityMgr,
+		h.server.heartbeatMgr, h.server.notificationHub,
+	)
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	conn, err := grpc.NewClient(
+		server.listener.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	mailClient := NewMailClient(conn)
+	agentClient := NewAgentClient(conn)
+	withToken := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(
+			ctx, "authorization", "Bearer "+token,
+		)
+	}
+	adminCtx := withToken(admin.Token)
+
+	// Without a credential only the health check is allowed.
+	_, err = agentClient.ListAgents(ctx, &ListAgentsRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = agentClient.ListAgents(withToken("sbt_bogus"),
+		&ListAgentsRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = NewStatsClient(conn).HealthCheck(ctx, &HealthCheckRequest{})
+	require.NoError(t, err)
+
+	minted, err := agentClient.MintCredential(
+		adminCtx, &MintCredentialRequest{AgentId: aliceID},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "Alice", minted.Credential.AgentName)
+	aliceCtx := withToken(minted.Token)
+
+	// Alice's sender is derived from her credential, and she can't send
+	// as Bob.
+	sent, err := mailClient.SendMail(aliceCtx, &SendMailRequest{
+		RecipientNames: []string{"Bob"},
+		Subject:        "Hi",
+		Body:           "From Alice",
+	})
+	require.NoError(t, err)
+
+	_, err = mailClient.FetchInbox(ctx, &FetchInboxRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	inbox, err := mailClient.FetchInbox(
+		adminCtx, &FetchInboxRequest{AgentId: bobID},
+	)
+	require.NoError(t, err)
+	require.Len(t, inbox.Messages, 1)
+	require.Equal(t, sent.MessageId, inbox.Messages[0].Id)
+	require.Equal(t, aliceID, inbox.Messages[0].SenderId)
+
+	_, err = mailClient.SendMail(aliceCtx, &SendMailRequest{
+		SenderId:       bobID,
+		RecipientNames: []string{"Alice"},
+		Subject:        "Spoofed",
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = mailClient.FetchInbox(
+		aliceCtx, &FetchInboxRequest{AgentId: bobID},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Streams are bound the same way.
+	stream, err := mailClient.SubscribeInbox(
+		aliceCtx, &SubscribeInboxRequest{AgentId: bobID},
+	)
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Admin operations and minting need an admin credential.
+	_, err = agentClient.DeleteAgent(
+		aliceCtx, &DeleteAgentRequest{Id: bobID},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = mailClient.MergeThreads(aliceCtx, &MergeThreadsRequest{
+		FromThreadId: "a",
+		IntoThreadId: "b",
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = mailClient.SplitThread(
+		aliceCtx, &SplitThreadRequest{MessageId: sent.MessageId},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Groups, tasks and plan reviews can't be created, taken or
+	// reviewed as another agent.
+	_, err = mailClient.CreateRecipientGroup(
+		aliceCtx, &CreateRecipientGroupRequest{
+			Name:      "spoofed",
+			CreatedBy: bobID,
+		},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = NewTaskServiceClient(conn).UpdateTaskOwner(
+		aliceCtx, &UpdateTaskOwnerRequest{
+			ListId:       "list",
+			ClaudeTaskId: "1",
+			Owner:        "Bob",
+		},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = NewPlanReviewServiceClient(conn).UpdatePlanReviewStatus(
+		aliceCtx, &UpdatePlanReviewStatusRequest{
+			PlanReviewId: "plan",
+			State:        "approved",
+			ReviewedBy:   bobID,
+		},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Shared groups, plan reviews and annotations can only be deleted,
+	// annotated or have their members changed by an admin, and Bob's
+	// tasks and reviews only by Bob.
+	_, err = mailClient.DeleteRecipientGroup(
+		aliceCtx, &DeleteRecipientGroupRequest{Name: "team"},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = mailClient.AddRecipientGroupMembers(
+		aliceCtx, &RecipientGroupMembersRequest{
+			Name:    "team",
+			Members: []string{"Alice"},
+		},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = mailClient.RemoveRecipientGroupMembers(
+		aliceCtx, &RecipientGroupMembersRequest{
+			Name:    "team",
+			Members: []string{"Bob"},
+		},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = NewPlanReviewServiceClient(conn).DeletePlanReview(
+		aliceCtx, &DeletePlanReviewRequest{PlanReviewId: "plan"},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = NewAnnotationServiceClient(conn).DeleteDiffAnnotation(
+		aliceCtx, &DeleteDiffAnnotationRequest{AnnotationId: "note"},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = h.storage.CreateTaskList(ctx, store.CreateTaskListParams{
+		ListID:  "bob-list",
+		AgentID: bobID,
+	})
+	require.NoError(t, err)
+	task, err := h.storage.UpsertTask(ctx, store.UpsertTaskParams{
+		AgentID:      bobID,
+		ListID:       "bob-list",
+		ClaudeTaskID: "1",
+		Subject:      "Bob's task",
+		Status:       "pending",
+	})
+	require.NoError(t, err)
+	_, err = h.storage.CreateReview(ctx, store.CreateReviewParams{
+		ReviewID:    "bob-review",
+		ThreadID:    "bob-review-thread",
+		RequesterID: bobID,
+		Branch:      "main",
+		BaseBranch:  "main",
+		CommitSHA:   "abc123",
+		RepoPath:    "/tmp/repo",
+		ReviewType:  "full",
+		Priority:    "normal",
+	})
+	require.NoError(t, err)
+
+	taskClient := NewTaskServiceClient(conn)
+	_, err = taskClient.DeleteTask(
+		aliceCtx, &DeleteTaskRequest{Id: task.ID},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = taskClient.UpdateTaskStatus(
+		aliceCtx, &UpdateTaskStatusRequest{
+			ListId:       "bob-list",
+			ClaudeTaskId: "1",
+			Status:       TaskStatus_TASK_STATUS_COMPLETED,
+		},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = taskClient.UnregisterTaskList(
+		aliceCtx, &UnregisterTaskListRequest{ListId: "bob-list"},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	reviewClient := NewReviewServiceClient(conn)
+	_, err = reviewClient.DeleteReview(
+		aliceCtx, &DeleteReviewProtoRequest{ReviewId: "bob-review"},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = reviewClient.CancelReview(
+		aliceCtx, &CancelReviewProtoRequest{ReviewId: "bob-review"},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = reviewClient.ResubmitReview(
+		aliceCtx, &ResubmitReviewRequest{
+			ReviewId:  "bob-review",
+			CommitSha: "def456",
+		},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = reviewClient.UpdateIssueStatus(
+		aliceCtx, &UpdateIssueStatusRequest{
+			ReviewId: "bob-review",
+			IssueId:  1,
+			Status:   "fixed",
+		},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	bobCred, err := agentClient.MintCredential(
+		adminCtx, &MintCredentialRequest{AgentId: bobID},
+	)
+	require.NoError(t, err)
+	bobCtx := withToken(bobCred.Token)
+	_, err = taskClient.UpdateTaskStatus(
+		bobCtx, &UpdateTaskStatusRequest{
+			ListId:       "bob-list",
+			ClaudeTaskId: "1",
+			Status:       TaskStatus_TASK_STATUS_IN_PROGRESS,
+		},
+	)
+	require.NoError(t, err)
+	_, err = taskClient.DeleteTask(
+		bobCtx, &DeleteTaskRequest{Id: task.ID},
+	)
+	require.NoError(t, err)
+	_, err = taskClient.UnregisterTaskList(
+		bobCtx, &UnregisterTaskListRequest{ListId: "bob-list"},
+	)
+	require.NoError(t, err)
+
+	_, err = agentClient.RegisterAgent(aliceCtx, &RegisterAgentRequest{
+		Name:      "Carol",
+		MintToken: true,
+	})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	carol, err := agentClient.RegisterAgent(
+		adminCtx, &RegisterAgentRequest{Name: "Carol", MintToken: true},
+	)
+	require.NoError(t, err)
+	require.NotEmpty(t, carol.Token)
+	_, err = agentClient.Heartbeat(
+		withToken(carol.Token), &HeartbeatRequest{AgentName: "Carol"},
+	)
+	require.NoError(t, err)
+
+	// Message history is bound to the caller, so Carol can't read the
+	// message Alice sent Bob, while Bob can.
+	_, err = mailClient.GetMessageHistory(
+		withToken(carol.Token),
+		&GetMessageHistoryRequest{MessageId: sent.MessageId},
+	)
+	require.Equal(t, codes.NotFound, status.Code(err))
+	history, err := mailClient.GetMessageHistory(
+		withToken(bobCred.Token),
+		&GetMessageHistoryRequest{MessageId: sent.MessageId},
+	)
+	require.NoError(t, err)
+	require.Len(t, history.Revisions, 1)
+
+	// A credential restricted to some RPCs can't call others.
+	restricted, err := agentClient.MintCredential(
+		adminCtx, &MintCredentialRequest{
+			AgentId:        aliceID,
+			AllowedMethods: []string{"SendMail"},
+		},
+	)
+	require.NoError(t, err)
+	_, err = mailClient.FetchInbox(
+		withToken(restricted.Token), &FetchInboxRequest{},
+	)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// A revoked credential stops working at once.
+	_, err = agentClient.RevokeCredential(
+		adminCtx, &RevokeCredentialRequest{Id: minted.Credential.Id},
+	)
+	require.NoError(t, err)
+	_, err = mailClient.FetchInbox(aliceCtx, &FetchInboxRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	creds, err := agentClient.ListCredentials(
+		adminCtx, &ListCredentialsRequest{AgentId: aliceID},
+	)
+	require.NoError(t, err)
+	require.Len(t, creds.Credentials, 1)
+	require.Equal(t, []string{"SendMail"},
+		creds.Credentials[0].AllowedMethods)
+}
+
+// TestServer_UnixSocketAndTLS tests that the server serves TLS on its TCP
+// listener, to clients pinning its certificate only, and plaintext on an
+// owner-only unix socket, and that a stale socket is replaced.
+func TestServer_UnixSocketAndTLS(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+
+	dir := t.TempDir()
+	cert, err := tlscert.Ensure(
+		filepath.Join(dir, tlscert.CertFilename),
+		filepath.Join(dir, tlscert.KeyFilename), nil,
+	)
+	require.NoError(t, err)
+
+	// Leave a stale socket behind, as a crashed daemon would.
+	socketPath := filepath.Join(dir, "substrated.sock")
+	stale, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	cfg := h.server.cfg
+	cfg.ListenAddr = "localhost:0"
+	cfg.UnixSocketPath = socketPath
+	cfg.TLSConfig = tlscert.ServerConfig(cert)
+	server := NewServer(
+		cfg, h.server.store, h.mailSvc, h.agentReg, h.identityMgr,
+		h.server.heartbeatMgr, h.server.notificationHub,
+	)
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	info, err := os.Stat(socketPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	require.Equal(t, socketPath, server.UnixAddr())
+
+	// A second server can't take over a socket that is being served.
+	cfg.ListenAddr = ""
+	cfg.TLSConfig = nil
+	second := NewServer(
+		cfg, h.server.store, h.mailSvc, h.agentReg, h.identityMgr,
+		h.server.heartbeatMgr, h.server.notificationHub,
+	)
+	require.ErrorContains(t, second.Start(), "already in use")
+
+	ctx := context.Background()
+	healthCheck := func(target string,
+		creds credentials.TransportCredentials,
+	) error {
+		conn, err := grpc.NewClient(
+			target, grpc.WithTransportCredentials(creds),
+		)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = NewStatsClient(conn).HealthCheck(
+			ctx, &HealthCheckRequest{},
+		)
+		return err
+	}
+
+	pinnedTLS := func(fingerprint string) credentials.TransportCredentials {
+		tlsCfg, err := tlscert.PinnedConfig(fingerprint)
+		require.NoError(t, err)
+
+		return credentials.NewTLS(tlsCfg)
+	}
+
+	// The unix socket is plaintext.
+	err = healthCheck(
+		UnixTarget(socketPath), insecure.NewCredentials(),
+	)
+	require.NoError(t, err)
+
+	// The TCP listener only completes a handshake with clients pinning
+	// its certificate, and refuses plaintext.
+	fingerprint := tlscert.Fingerprint(cert.Certificate[0])
+	err = healthCheck(server.Addr(), pinnedTLS(fingerprint))
+	require.NoError(t, err)
+
+	err = healthCheck(server.Addr(), pinnedTLS(strings.Repeat("00", 32)))
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.ErrorContains(t, err, tlscert.ErrFingerprintMismatch.Error())
+
+	err = healthCheck(server.Addr(), insecure.NewCredentials())
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	// The socket is removed on shutdown.
+	require.NoError(t, server.Stop())
+	_, err = os.Stat(socketPath)
+	require.True(t, os.IsNotExist(err))
+}
+
+// TestMailService_RateLimit tests that a send past the sender's rate limit
+// is refused with ResourceExhausted, carrying the retry delay both as a
+// RetryInfo detail and in the retry-after header.
+func TestMailService_RateLimit(t *testing.T) {
+	h := newTestHarness(t)
+	defer h.Close()
+
+	ctx := context.Background()
+	senderID := h.createTestAgent("Sender")
+	recipientID := h.createTestAgent("Recipient")
+
+	_, err := h.agentClient.SetLimit(ctx, &SetLimitRequest{
+		AgentId:         senderID,
+		Class:           "send",
+		Amount:          1,
+		IntervalSeconds: 60,
+	})
+	require.NoError(t, err)
+
+	send := func(header *metadata.MD) error {
+		_, err := h.mailClient.SendMail(ctx, &SendMailRequest{
+			SenderId:       senderID,
+			RecipientNames: []string{"Recipient"},
+			Subject:        "Test Subject",
+			Body:           "Test body content",
+			Priority:       Priority_PRIORITY_NORMAL,
+		}, grpc.Header(header))
+		return err
+	}
+
+	var header metadata.MD
+	require.NoError(t, send(&header))
+
+	err = send(&header)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	require.Positive(t, retryInfo.RetryDelay.AsDuration())
+	require.LessOrEqual(t, retryInfo.RetryDelay.AsDuration(), time.Minute)
+	require.Equal(t, []string{"60"}, header.Get(retryAfterHeader))
+
+	// The sender's usage counts the refusal.
+	usage, err := h.agentClient.GetUsage(ctx, &GetUsageRequest{
+		AgentId: senderID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "send", usage.Usage[0].Class)
+	require.EqualValues(t, 1, usage.Usage[0].Throttled)
+
+	// Editing a sent message draws on the same limit.
+	inbox, err := h.mailClient.FetchInbox(ctx, &FetchInboxRequest{
+		AgentId: recipientID,
+	})
+	require.NoError(t, err)
+	require.Len(t, inbox.Messages, 1)
+
+	_, err = h.mailClient.EditMessage(ctx, &EditMessageRequest{
+		AgentId:   senderID,
+		MessageId: inbox.Messages[0].Id,
+		Body:      "Edited body content",
+	})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
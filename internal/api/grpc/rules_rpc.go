@@ -0,0 +1,213 @@
+package subtraterpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CreateInboxRule adds a rule after the agent's existing inbox rules.
+func (s *Server) CreateInboxRule(ctx context.Context,
+	req *CreateInboxRuleRequest,
+) (*InboxRuleProto, error) {
+	if req.AgentId == 0 || req.Name == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id and name are required",
+		)
+	}
+
+	rule, err := s.mailSvc.CreateInboxRule(ctx, mail.CreateInboxRuleRequest{
+		AgentID:        req.AgentId,
+		Name:           req.Name,
+		StopProcessing: req.StopProcessing,
+		Conditions:     inboxRuleConditionsFromProto(req.Conditions),
+		Actions:        inboxRuleActionsFromProto(req.Actions),
+	})
+	if err != nil {
+		return nil, inboxRuleStatusError("create inbox rule", err)
+	}
+
+	return convertInboxRule(rule), nil
+}
+
+// ListInboxRules lists an agent's inbox rules in the order they are applied.
+func (s *Server) ListInboxRules(ctx context.Context,
+	req *ListInboxRulesRequest,
+) (*ListInboxRulesResponse, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id is required",
+		)
+	}
+
+	rules, err := s.mailSvc.ListInboxRules(ctx, req.AgentId)
+	if err != nil {
+		return nil, inboxRuleStatusError("list inbox rules", err)
+	}
+
+	resp := &ListInboxRulesResponse{
+		Rules: make([]*InboxRuleProto, len(rules)),
+	}
+	for i, rule := range rules {
+		resp.Rules[i] = convertInboxRule(rule)
+	}
+
+	return resp, nil
+}
+
+// DeleteInboxRule deletes one of an agent's inbox rules.
+func (s *Server) DeleteInboxRule(ctx context.Context,
+	req *DeleteInboxRuleRequest,
+) (*DeleteInboxRuleResponse, error) {
+	if req.AgentId == 0 || req.Name == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id and name are required",
+		)
+	}
+
+	err := s.mailSvc.DeleteInboxRule(ctx, req.AgentId, req.Name)
+	if err != nil {
+		return nil, inboxRuleStatusError("delete inbox rule", err)
+	}
+
+	return &DeleteInboxRuleResponse{}, nil
+}
+
+// SetInboxRuleEnabled enables or disables one of an agent's inbox rules.
+func (s *Server) SetInboxRuleEnabled(ctx context.Context,
+	req *SetInboxRuleEnabledRequest,
+) (*InboxRuleProto, error) {
+	if req.AgentId == 0 || req.Name == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id and name are required",
+		)
+	}
+
+	rule, err := s.mailSvc.SetInboxRuleEnabled(
+		ctx, req.AgentId, req.Name, req.Enabled,
+	)
+	if err != nil {
+		return nil, inboxRuleStatusError("update inbox rule", err)
+	}
+
+	return convertInboxRule(rule), nil
+}
+
+// TestInboxRule reports which of the agent's recently received messages a
+// rule would match, without changing them.
+func (s *Server) TestInboxRule(ctx context.Context,
+	req *TestInboxRuleRequest,
+) (*TestInboxRuleResponse, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id is required",
+		)
+	}
+
+	resp, err := s.mailSvc.TestInboxRule(ctx, mail.TestInboxRuleRequest{
+		AgentID:    req.AgentId,
+		Name:       req.Name,
+		Conditions: inboxRuleConditionsFromProto(req.Conditions),
+		Limit:      int(req.Limit),
+	})
+	if err != nil {
+		return nil, inboxRuleStatusError("test inbox rule", err)
+	}
+
+	return &TestInboxRuleResponse{
+		Scanned: int32(resp.Scanned),
+		Matches: convertMessagesForList(resp.Matches),
+	}, nil
+}
+
+// inboxRuleStatusError maps inbox rule errors from the mail service onto
+// gRPC status codes.
+func inboxRuleStatusError(operation string, err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, mail.ErrInboxRuleNotFound):
+		code = codes.NotFound
+
+	case errors.Is(err, mail.ErrInboxRuleExists):
+		code = codes.AlreadyExists
+
+	case errors.Is(err, mail.ErrInvalidInboxRule):
+		code = codes.InvalidArgument
+	}
+
+	return status.Errorf(code, "failed to %s: %v", operation, err)
+}
+
+// inboxRuleConditionsFromProto converts inbox rule conditions from their
+// proto form. Missing conditions are empty.
+func inboxRuleConditionsFromProto(
+	c *InboxRuleConditions,
+) store.InboxRuleConditions {
+	if c == nil {
+		return store.InboxRuleConditions{}
+	}
+
+	return store.InboxRuleConditions{
+		SenderName:     c.SenderName,
+		SenderPrefix:   c.SenderPrefix,
+		SubjectPattern: c.SubjectPattern,
+		TopicName:      c.TopicName,
+		Priority:       c.Priority,
+		MetadataKey:    c.MetadataKey,
+		MetadataValue:  c.MetadataValue,
+	}
+}
+
+// inboxRuleActionsFromProto converts inbox rule actions from their proto
+// form. Missing actions are empty.
+func inboxRuleActionsFromProto(a *InboxRuleActions) store.InboxRuleActions {
+	if a == nil {
+		return store.InboxRuleActions{}
+	}
+
+	return store.InboxRuleActions{
+		Archive:   a.Archive,
+		Star:      a.Star,
+		Snooze:    a.Snooze,
+		Category:  store.InboxCategory(a.Category),
+		ForwardTo: a.ForwardTo,
+		Label:     a.Label,
+	}
+}
+
+// convertInboxRule converts an inbox rule to its proto form.
+func convertInboxRule(rule store.InboxRule) *InboxRuleProto {
+	return &InboxRuleProto{
+		Id:             rule.ID,
+		AgentId:        rule.AgentID,
+		Name:           rule.Name,
+		Enabled:        rule.Enabled,
+		StopProcessing: rule.StopProcessing,
+		Conditions: &InboxRuleConditions{
+			SenderName:     rule.SenderName,
+			SenderPrefix:   rule.SenderPrefix,
+			SubjectPattern: rule.SubjectPattern,
+			TopicName:      rule.TopicName,
+			Priority:       rule.Priority,
+			MetadataKey:    rule.MetadataKey,
+			MetadataValue:  rule.MetadataValue,
+		},
+		Actions: &InboxRuleActions{
+			Archive:   rule.Archive,
+			Star:      rule.Star,
+			Snooze:    rule.Snooze,
+			Category:  string(rule.Category),
+			ForwardTo: rule.ForwardTo,
+			Label:     rule.Label,
+		},
+		MatchCount:    rule.MatchCount,
+		LastMatchedAt: timeToTimestamp(rule.LastMatchedAt),
+		CreatedAt:     timestamppb.New(rule.CreatedAt),
+		UpdatedAt:     timestamppb.New(rule.UpdatedAt),
+	}
+}
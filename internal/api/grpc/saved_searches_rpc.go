@@ -0,0 +1,106 @@
+package subtraterpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/search"
+	"github.com/roasbeef/subtrate/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SaveSearch saves a search query under a name for the agent.
+func (s *Server) SaveSearch(ctx context.Context,
+	req *SaveSearchRequest,
+) (*SavedSearchProto, error) {
+	if req.AgentId == 0 || req.Name == "" || req.Query == "" {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"agent_id, name and query are required",
+		)
+	}
+
+	saved, err := s.mailSvc.SaveSearch(
+		ctx, req.AgentId, req.Name, req.Query,
+	)
+	if err != nil {
+		return nil, savedSearchStatusError("save search", err)
+	}
+
+	return convertSavedSearch(saved), nil
+}
+
+// ListSavedSearches lists the agent's saved searches by name.
+func (s *Server) ListSavedSearches(ctx context.Context,
+	req *ListSavedSearchesRequest,
+) (*ListSavedSearchesResponse, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id is required",
+		)
+	}
+
+	searches, err := s.mailSvc.ListSavedSearches(ctx, req.AgentId)
+	if err != nil {
+		return nil, savedSearchStatusError("list saved searches", err)
+	}
+
+	resp := &ListSavedSearchesResponse{
+		Searches: make([]*SavedSearchProto, len(searches)),
+	}
+	for i, saved := range searches {
+		resp.Searches[i] = convertSavedSearch(saved)
+	}
+
+	return resp, nil
+}
+
+// DeleteSavedSearch deletes one of the agent's saved searches.
+func (s *Server) DeleteSavedSearch(ctx context.Context,
+	req *DeleteSavedSearchRequest,
+) (*DeleteSavedSearchResponse, error) {
+	if req.AgentId == 0 || req.Name == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id and name are required",
+		)
+	}
+
+	err := s.mailSvc.DeleteSavedSearch(ctx, req.AgentId, req.Name)
+	if err != nil {
+		return nil, savedSearchStatusError("delete saved search", err)
+	}
+
+	return &DeleteSavedSearchResponse{}, nil
+}
+
+// savedSearchStatusError maps saved search errors from the mail service onto
+// gRPC status codes.
+func savedSearchStatusError(operation string, err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, mail.ErrSavedSearchNotFound):
+		code = codes.NotFound
+
+	case errors.Is(err, mail.ErrInvalidSavedSearch),
+		errors.Is(err, search.ErrInvalidQuery):
+
+		code = codes.InvalidArgument
+	}
+
+	return status.Errorf(code, "failed to %s: %v", operation, err)
+}
+
+// convertSavedSearch converts a saved search to its proto form.
+func convertSavedSearch(saved store.SavedSearch) *SavedSearchProto {
+	return &SavedSearchProto{
+		Id:        saved.ID,
+		AgentId:   saved.AgentID,
+		Name:      saved.Name,
+		Query:     saved.Query,
+		CreatedAt: timestamppb.New(saved.CreatedAt),
+		UpdatedAt: timestamppb.New(saved.UpdatedAt),
+	}
+}
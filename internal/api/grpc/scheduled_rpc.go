@@ -0,0 +1,93 @@
+package subtraterpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ListScheduledMessages lists the sender's scheduled messages that have not
+// been sent or canceled, soonest first.
+func (s *Server) ListScheduledMessages(ctx context.Context,
+	req *ListScheduledMessagesRequest,
+) (*ListScheduledMessagesResponse, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id is required",
+		)
+	}
+
+	msgs, err := s.mailSvc.ListScheduledMessages(
+		ctx, req.AgentId, int(req.Limit),
+	)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.Internal, "failed to list scheduled messages: %v",
+			err,
+		)
+	}
+
+	resp := &ListScheduledMessagesResponse{
+		Messages: make([]*ScheduledMessage, len(msgs)),
+	}
+	for i, m := range msgs {
+		resp.Messages[i] = convertScheduledMessage(m)
+	}
+
+	return resp, nil
+}
+
+// CancelScheduledMessage cancels a scheduled message before it is sent.
+func (s *Server) CancelScheduledMessage(ctx context.Context,
+	req *CancelScheduledMessageRequest,
+) (*CancelScheduledMessageResponse, error) {
+	if req.AgentId == 0 || req.Id == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id and id are required",
+		)
+	}
+
+	err := s.mailSvc.CancelScheduledMessage(ctx, req.AgentId, req.Id)
+	switch {
+	case errors.Is(err, mail.ErrScheduledMessageNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+
+	case err != nil:
+		return nil, status.Errorf(
+			codes.Internal,
+			"failed to cancel scheduled message: %v", err,
+		)
+	}
+
+	return &CancelScheduledMessageResponse{Success: true}, nil
+}
+
+// convertScheduledMessage converts a scheduled message to its proto form.
+func convertScheduledMessage(m store.ScheduledMessage) *ScheduledMessage {
+	priority := Priority_PRIORITY_NORMAL
+	switch mail.Priority(m.Priority) {
+	case mail.PriorityLow:
+		priority = Priority_PRIORITY_LOW
+	case mail.PriorityUrgent:
+		priority = Priority_PRIORITY_URGENT
+	}
+
+	return &ScheduledMessage{
+		Id:             m.ID,
+		RecipientNames: m.RecipientNames,
+		TopicName:      m.TopicName,
+		ThreadId:       m.ThreadID,
+		Subject:        m.Subject,
+		Body:           m.Body,
+		Priority:       priority,
+		SendAt:         timestamppb.New(m.SendAt),
+		State:          m.State,
+		LastError:      m.LastError,
+		CreatedAt:      timestamppb.New(m.CreatedAt),
+	}
+}
@@ -0,0 +1,177 @@
+package subtraterpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CreateSchedule creates a recurring message sent on a cron schedule.
+func (s *Server) CreateSchedule(ctx context.Context,
+	req *CreateScheduleRequest,
+) (*ScheduleResponse, error) {
+	if req.AgentId == 0 || req.Name == "" || req.CronExpr == "" {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"agent_id, name and cron_expr are required",
+		)
+	}
+
+	priority := mail.PriorityNormal
+	switch req.Priority {
+	case Priority_PRIORITY_LOW:
+		priority = mail.PriorityLow
+	case Priority_PRIORITY_URGENT:
+		priority = mail.PriorityUrgent
+	}
+
+	sched, err := s.mailSvc.CreateSchedule(ctx, mail.CreateScheduleRequest{
+		Name:           req.Name,
+		SenderID:       req.AgentId,
+		RecipientNames: req.RecipientNames,
+		TopicName:      req.TopicName,
+		Subject:        req.Subject,
+		Body:           req.Body,
+		Priority:       priority,
+		CronExpr:       req.CronExpr,
+		Timezone:       req.Timezone,
+		CatchUp:        mail.CatchUpPolicy(req.CatchUp),
+	})
+	if err != nil {
+		return nil, scheduleStatusError("create schedule", err)
+	}
+
+	return &ScheduleResponse{Schedule: convertSchedule(sched)}, nil
+}
+
+// ListSchedules lists the agent's recurring schedules by name.
+func (s *Server) ListSchedules(ctx context.Context,
+	req *ListSchedulesRequest,
+) (*ListSchedulesResponse, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id is required",
+		)
+	}
+
+	schedules, err := s.mailSvc.ListSchedules(ctx, req.AgentId)
+	if err != nil {
+		return nil, scheduleStatusError("list schedules", err)
+	}
+
+	resp := &ListSchedulesResponse{
+		Schedules: make([]*Schedule, len(schedules)),
+	}
+	for i, sched := range schedules {
+		resp.Schedules[i] = convertSchedule(sched)
+	}
+
+	return resp, nil
+}
+
+// DeleteSchedule deletes one of the agent's recurring schedules.
+func (s *Server) DeleteSchedule(ctx context.Context,
+	req *DeleteScheduleRequest,
+) (*DeleteScheduleResponse, error) {
+	if req.AgentId == 0 || req.Name == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id and name are required",
+		)
+	}
+
+	err := s.mailSvc.DeleteSchedule(ctx, req.AgentId, req.Name)
+	if err != nil {
+		return nil, scheduleStatusError("delete schedule", err)
+	}
+
+	return &DeleteScheduleResponse{Success: true}, nil
+}
+
+// SetSchedulePaused pauses or resumes one of the agent's recurring
+// schedules.
+func (s *Server) SetSchedulePaused(ctx context.Context,
+	req *SetSchedulePausedRequest,
+) (*ScheduleResponse, error) {
+	if req.AgentId == 0 || req.Name == "" {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id and name are required",
+		)
+	}
+
+	var (
+		sched store.Schedule
+		err   error
+	)
+	if req.Paused {
+		sched, err = s.mailSvc.PauseSchedule(
+			ctx, req.AgentId, req.Name,
+		)
+	} else {
+		sched, err = s.mailSvc.ResumeSchedule(
+			ctx, req.AgentId, req.Name,
+		)
+	}
+	if err != nil {
+		return nil, scheduleStatusError("update schedule", err)
+	}
+
+	return &ScheduleResponse{Schedule: convertSchedule(sched)}, nil
+}
+
+// scheduleStatusError maps schedule errors from the mail service onto gRPC
+// status codes.
+func scheduleStatusError(operation string, err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, mail.ErrScheduleNotFound):
+		code = codes.NotFound
+
+	case errors.Is(err, mail.ErrScheduleExists):
+		code = codes.AlreadyExists
+
+	case errors.Is(err, mail.ErrInvalidSchedule):
+		code = codes.InvalidArgument
+	}
+
+	return status.Errorf(code, "failed to %s: %v", operation, err)
+}
+
+// convertSchedule converts a recurring schedule to its proto form.
+func convertSchedule(sched store.Schedule) *Schedule {
+	priority := Priority_PRIORITY_NORMAL
+	switch mail.Priority(sched.Priority) {
+	case mail.PriorityLow:
+		priority = Priority_PRIORITY_LOW
+	case mail.PriorityUrgent:
+		priority = Priority_PRIORITY_URGENT
+	}
+
+	resp := &Schedule{
+		Id:             sched.ID,
+		Name:           sched.Name,
+		RecipientNames: sched.RecipientNames,
+		TopicName:      sched.TopicName,
+		Subject:        sched.Subject,
+		Body:           sched.Body,
+		Priority:       priority,
+		CronExpr:       sched.CronExpr,
+		Timezone:       sched.Timezone,
+		CatchUp:        sched.CatchUp,
+		Paused:         sched.Paused,
+		NextRunAt:      timestamppb.New(sched.NextRunAt),
+		LastMessageId:  sched.LastMessageID,
+		LastError:      sched.LastError,
+		RunCount:       sched.RunCount,
+		CreatedAt:      timestamppb.New(sched.CreatedAt),
+	}
+	if sched.LastRunAt != nil {
+		resp.LastRunAt = timestamppb.New(*sched.LastRunAt)
+	}
+
+	return resp
+}
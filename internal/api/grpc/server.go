@@ -2,6 +2,7 @@ package subtraterpc
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log/slog"
 	"net"
@@ -16,18 +17,29 @@ import (
 
 	"github.com/roasbeef/subtrate/internal/activity"
 	"github.com/roasbeef/subtrate/internal/agent"
+	"github.com/roasbeef/subtrate/internal/auth"
 	"github.com/roasbeef/subtrate/internal/db"
 	"github.com/roasbeef/subtrate/internal/mail"
 	"github.com/roasbeef/subtrate/internal/mailclient"
+	"github.com/roasbeef/subtrate/internal/ratelimit"
 	"github.com/roasbeef/subtrate/internal/review"
 	"github.com/roasbeef/subtrate/internal/store"
 )
 
 // ServerConfig holds configuration for the gRPC server.
 type ServerConfig struct {
-	// ListenAddr is the address to listen on (e.g., "localhost:10009").
+	// ListenAddr is the TCP address to listen on (e.g., "localhost:10009"),
+	// or empty to only listen on the unix socket.
 	ListenAddr string
 
+	// UnixSocketPath is the path of a unix socket to also listen on
+	// (optional). The socket is only accessible to its owner.
+	UnixSocketPath string
+
+	// TLSConfig serves the TCP listener over TLS when set (optional). The
+	// unix socket is protected by its file permissions instead.
+	TLSConfig *tls.Config
+
 	// ServerPingTime is the duration after which the server pings the client.
 	// If not set, defaults to 5 minutes.
 	ServerPingTime time.Duration
@@ -51,6 +63,10 @@ type ServerConfig struct {
 
 	// ReviewRef is the actor reference for review operations (optional).
 	ReviewRef review.ReviewActorRef
+
+	// Auth checks the bearer credential on every RPC when set (optional).
+	// Agent credentials can then only act as their own agent.
+	Auth *auth.Service
 }
 
 // DefaultServerConfig returns a ServerConfig with sensible defaults.
@@ -71,6 +87,7 @@ type Server struct {
 	taskStore       store.TaskStore            // Task CRUD via store interface.
 	planReviewStore store.PlanReviewStore      // Plan review CRUD via store interface.
 	annotationStore store.AnnotationStore      // Annotation CRUD via store interface.
+	retentionStore  store.Storage              // Retention compaction via store interface.
 	mailSvc         *mail.Service              // Direct service for operations not via actor.
 	mailClient      *mailclient.Client         // Shared mail client (required).
 	actClient       *mailclient.ActivityClient // Shared activity client (required).
@@ -89,8 +106,24 @@ type Server struct {
 	// notifications (e.g., WebSocket broadcasts). Optional.
 	taskNotifier TaskChangeNotifier
 
-	grpcServer *grpc.Server
-	listener   net.Listener
+	// authSvc checks credentials, or is nil when authentication is
+	// disabled.
+	authSvc *auth.Service
+
+	// credentials mints and revokes credentials. Unlike authSvc it is
+	// always set, so tokens can be minted before auth is turned on.
+	credentials *auth.Service
+
+	// limiter holds agents to their rate limits.
+	limiter *ratelimit.Limiter
+
+	// watchLeases tracks the watcher lease of each agent whose inbox is
+	// watched over WatchInbox.
+	watchLeases *mail.WatchLeases
+
+	grpcServer   *grpc.Server
+	listener     net.Listener
+	unixListener net.Listener
 
 	started bool
 	mu      sync.RWMutex
@@ -108,6 +141,7 @@ type Server struct {
 	UnimplementedTaskServiceServer
 	UnimplementedPlanReviewServiceServer
 	UnimplementedAnnotationServiceServer
+	UnimplementedInboxRuleServiceServer
 }
 
 // NewServer creates a new gRPC server instance.
@@ -124,12 +158,18 @@ func NewServer(
 	// CRUD operations without the actor system.
 	taskSt := store.FromDB(dbStore.DB())
 
+	credentials := cfg.Auth
+	if credentials == nil {
+		credentials = auth.NewService(taskSt)
+	}
+
 	return &Server{
 		cfg:             cfg,
 		store:           dbStore,
 		taskStore:       taskSt,
 		planReviewStore: taskSt,
 		annotationStore: taskSt,
+		retentionStore:  taskSt,
 		mailSvc:         mailSvc,
 		mailClient:      mailclient.NewClient(cfg.MailRef),
 		actClient:       mailclient.NewActivityClient(cfg.ActivityRef),
@@ -138,6 +178,10 @@ func NewServer(
 		heartbeatMgr:    heartbeatMgr,
 		notificationHub: notificationHub,
 		reviewRef:       cfg.ReviewRef,
+		authSvc:         cfg.Auth,
+		credentials:     credentials,
+		limiter:         ratelimit.NewLimiter(taskSt),
+		watchLeases:     mail.NewWatchLeases(),
 		quit:            make(chan struct{}),
 	}
 }
@@ -163,12 +207,42 @@ func (s *Server) Start() error {
 		return fmt.Errorf("server already started")
 	}
 
-	// Create listener.
-	lis, err := net.Listen("tcp", s.cfg.ListenAddr)
-	if err != nil {
-		return fmt.Errorf("failed to listen on %s: %w", s.cfg.ListenAddr, err)
+	if s.cfg.ListenAddr == "" && s.cfg.UnixSocketPath == "" {
+		return fmt.Errorf("no listen address or unix socket configured")
+	}
+
+	// Create listeners.
+	var listeners []net.Listener
+	if s.cfg.ListenAddr != "" {
+		lis, err := net.Listen("tcp", s.cfg.ListenAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w",
+				s.cfg.ListenAddr, err)
+		}
+
+		// gRPC clients require HTTP/2 to be negotiated over TLS.
+		if s.cfg.TLSConfig != nil {
+			tlsCfg := s.cfg.TLSConfig.Clone()
+			tlsCfg.NextProtos = []string{"h2"}
+			lis = tls.NewListener(lis, tlsCfg)
+		}
+
+		s.listener = lis
+		listeners = append(listeners, lis)
+	}
+	if s.cfg.UnixSocketPath != "" {
+		lis, err := listenUnix(s.cfg.UnixSocketPath)
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+
+			return err
+		}
+
+		s.unixListener = lis
+		listeners = append(listeners, lis)
 	}
-	s.listener = lis
 
 	// Build server options with keepalive and interceptors.
 	opts := s.buildServerOptions()
@@ -186,21 +260,26 @@ func (s *Server) Start() error {
 	RegisterTaskServiceServer(s.grpcServer, s)
 	RegisterPlanReviewServiceServer(s.grpcServer, s)
 	RegisterAnnotationServiceServer(s.grpcServer, s)
-
-	// Start serving in a goroutine.
-	s.wg.Add(1)
-	go func() {
-		defer s.wg.Done()
-		slog.Info("gRPC server listening", "addr", s.cfg.ListenAddr)
-		if err := s.grpcServer.Serve(lis); err != nil {
-			// Only log if not a graceful shutdown.
-			select {
-			case <-s.quit:
-			default:
-				slog.Error("gRPC server error", "error", err)
+	RegisterInboxRuleServiceServer(s.grpcServer, s)
+
+	// Start serving each listener in a goroutine.
+	for _, lis := range listeners {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			slog.Info("gRPC server listening",
+				"addr", lis.Addr().String())
+			if err := s.grpcServer.Serve(lis); err != nil {
+				// Only log if not a graceful shutdown.
+				select {
+				case <-s.quit:
+				default:
+					slog.Error("gRPC server error",
+						"error", err)
+				}
 			}
-		}
-	}()
+		}()
+	}
 
 	s.started = true
 	return nil
@@ -247,22 +326,28 @@ func (s *Server) buildServerOptions() []grpc.ServerOption {
 	// containing large diff attachments can be served without error.
 	const maxMsgSize = 100 * 1024 * 1024 // 100 MB
 
+	// Chain unary interceptors: logging -> authentication (when
+	// enabled) -> request validation -> rate limiting.
+	unary := []grpc.UnaryServerInterceptor{s.loggingUnaryInterceptor}
+	stream := []grpc.StreamServerInterceptor{s.loggingStreamInterceptor}
+	if s.authSvc != nil {
+		unary = append(unary, s.authUnaryInterceptor)
+		stream = append(stream, s.authStreamInterceptor)
+	}
+	unary = append(
+		unary, s.validationUnaryInterceptor,
+		s.rateLimitUnaryInterceptor,
+	)
+
 	return []grpc.ServerOption{
 		grpc.KeepaliveParams(serverKeepalive),
 		grpc.KeepaliveEnforcementPolicy(clientKeepalive),
 		grpc.MaxRecvMsgSize(maxMsgSize),
 		grpc.MaxSendMsgSize(maxMsgSize),
-
-		// Chain unary interceptors: logging -> request validation.
-		grpc.ChainUnaryInterceptor(
-			s.loggingUnaryInterceptor,
-			s.validationUnaryInterceptor,
-		),
+		grpc.ChainUnaryInterceptor(unary...),
 
 		// Chain stream interceptors for streaming RPCs.
-		grpc.ChainStreamInterceptor(
-			s.loggingStreamInterceptor,
-		),
+		grpc.ChainStreamInterceptor(stream...),
 	}
 }
 
@@ -362,6 +447,18 @@ func (s *Server) Addr() string {
 	return s.listener.Addr().String()
 }
 
+// UnixAddr returns the path of the unix socket the server is listening on,
+// or an empty string if it isn't listening on one.
+func (s *Server) UnixAddr() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.unixListener == nil {
+		return ""
+	}
+	return s.unixListener.Addr().String()
+}
+
 // IsRunning returns whether the server is currently running.
 func (s *Server) IsRunning() bool {
 	s.mu.RLock()
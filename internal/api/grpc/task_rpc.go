@@ -2,9 +2,12 @@ package subtraterpc
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"time"
 
+	"github.com/roasbeef/subtrate/internal/mail"
 	"github.com/roasbeef/subtrate/internal/store"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -114,6 +117,10 @@ func (s *Server) UnregisterTaskList(
 		)
 	}
 
+	if err := s.requireTaskListOwner(ctx, req.ListId); err != nil {
+		return nil, err
+	}
+
 	// Delete tasks first, then the list.
 	if err := s.taskStore.DeleteTasksByList(
 		ctx, req.ListId,
@@ -150,21 +157,38 @@ func (s *Server) UpsertTask(
 	blockedByJSON := marshalStringSlice(req.BlockedBy)
 	blocksJSON := marshalStringSlice(req.Blocks)
 
-	task, err := s.taskStore.UpsertTask(
-		ctx, store.UpsertTaskParams{
-			AgentID:      req.AgentId,
-			ListID:       req.ListId,
-			ClaudeTaskID: req.ClaudeTaskId,
-			Subject:      req.Subject,
-			Description:  req.Description,
-			ActiveForm:   req.ActiveForm,
-			Metadata:     req.MetadataJson,
-			Status:       taskStatusToString(req.Status),
-			Owner:        req.Owner,
-			BlockedBy:    blockedByJSON,
-			Blocks:       blocksJSON,
-		},
-	)
+	params := store.UpsertTaskParams{
+		AgentID:      req.AgentId,
+		ListID:       req.ListId,
+		ClaudeTaskID: req.ClaudeTaskId,
+		Subject:      req.Subject,
+		Description:  req.Description,
+		ActiveForm:   req.ActiveForm,
+		Metadata:     req.MetadataJson,
+		Status:       taskStatusToString(req.Status),
+		Owner:        req.Owner,
+		BlockedBy:    blockedByJSON,
+		Blocks:       blocksJSON,
+	}
+
+	// A task owned by a capability goes to the agent the router picks.
+	if mail.IsCapabilityAddress(req.Owner) {
+		task, _, err := s.mailSvc.UpsertRoutedTask(ctx, params)
+		if err != nil {
+			return nil, capabilityStatusError("upsert task", err)
+		}
+
+		s.notifyTaskChange("upsert", map[string]any{
+			"list_id":        req.ListId,
+			"claude_task_id": req.ClaudeTaskId,
+			"agent_id":       req.AgentId,
+			"status":         taskStatusToString(req.Status),
+		})
+
+		return &UpsertTaskResponse{Task: taskToProto(task)}, nil
+	}
+
+	task, err := s.taskStore.UpsertTask(ctx, params)
 	if err != nil {
 		return nil, status.Errorf(
 			codes.Internal, "failed to upsert task: %v", err,
@@ -273,9 +297,14 @@ func (s *Server) UpdateTaskStatus(
 		)
 	}
 
+	err := s.requireListTaskOwner(ctx, req.ListId, req.ClaudeTaskId)
+	if err != nil {
+		return nil, err
+	}
+
 	statusStr := taskStatusToString(req.Status)
 
-	err := s.taskStore.UpdateTaskStatus(
+	err = s.taskStore.UpdateTaskStatus(
 		ctx, req.ListId, req.ClaudeTaskId,
 		statusStr, time.Now(),
 	)
@@ -306,26 +335,111 @@ func (s *Server) UpdateTaskOwner(
 		)
 	}
 
-	err := s.taskStore.UpdateTaskOwner(
-		ctx, req.ListId, req.ClaudeTaskId,
-		req.Owner, time.Now(),
-	)
-	if err != nil {
-		return nil, status.Errorf(
-			codes.Internal,
-			"failed to update task owner: %v", err,
+	// A capability owner is replaced by the agent the router picks.
+	owner := req.Owner
+	if mail.IsCapabilityAddress(owner) {
+		route, err := s.mailSvc.RouteTaskOwner(
+			ctx, req.ListId, req.ClaudeTaskId, owner,
 		)
+		if err != nil {
+			return nil, capabilityStatusError(
+				"update task owner", err,
+			)
+		}
+		owner = route.AgentName
+	} else {
+		err := s.taskStore.UpdateTaskOwner(
+			ctx, req.ListId, req.ClaudeTaskId,
+			owner, time.Now(),
+		)
+		if err != nil {
+			return nil, status.Errorf(
+				codes.Internal,
+				"failed to update task owner: %v", err,
+			)
+		}
 	}
 
 	s.notifyTaskChange("owner", map[string]any{
 		"list_id":        req.ListId,
 		"claude_task_id": req.ClaudeTaskId,
-		"owner":          req.Owner,
+		"owner":          owner,
 	})
 
 	return &UpdateTaskOwnerResponse{}, nil
 }
 
+// requireTaskOwner returns an error unless the caller may act on a task as
+// the agent whose list it's on.
+func (s *Server) requireTaskOwner(ctx context.Context, taskID int64) error {
+	if s.authSvc == nil {
+		return nil
+	}
+
+	task, err := s.taskStore.GetTask(ctx, taskID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Errorf(
+			codes.NotFound, "task %d not found", taskID,
+		)
+	}
+	if err != nil {
+		return status.Errorf(
+			codes.Internal, "failed to get task: %v", err,
+		)
+	}
+
+	return s.requireOwner(ctx, task.AgentID, "task")
+}
+
+// requireListTaskOwner is requireTaskOwner for a task named by its list and
+// Claude task ID.
+func (s *Server) requireListTaskOwner(ctx context.Context, listID,
+	claudeTaskID string,
+) error {
+	if s.authSvc == nil {
+		return nil
+	}
+
+	task, err := s.taskStore.GetTaskByClaudeID(ctx, listID, claudeTaskID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Errorf(
+			codes.NotFound, "task %s not found in list %s",
+			claudeTaskID, listID,
+		)
+	}
+	if err != nil {
+		return status.Errorf(
+			codes.Internal, "failed to get task: %v", err,
+		)
+	}
+
+	return s.requireOwner(ctx, task.AgentID, "task")
+}
+
+// requireTaskListOwner returns an error unless the caller may act on a task
+// list as the agent that registered it.
+func (s *Server) requireTaskListOwner(ctx context.Context,
+	listID string,
+) error {
+	if s.authSvc == nil {
+		return nil
+	}
+
+	list, err := s.taskStore.GetTaskList(ctx, listID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Errorf(
+			codes.NotFound, "task list %s not found", listID,
+		)
+	}
+	if err != nil {
+		return status.Errorf(
+			codes.Internal, "failed to get task list: %v", err,
+		)
+	}
+
+	return s.requireOwner(ctx, list.AgentID, "task list")
+}
+
 // DeleteTask deletes a task.
 func (s *Server) DeleteTask(
 	ctx context.Context, req *DeleteTaskRequest,
@@ -336,6 +450,10 @@ func (s *Server) DeleteTask(
 		)
 	}
 
+	if err := s.requireTaskOwner(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
 	err := s.taskStore.DeleteTask(ctx, req.Id)
 	if err != nil {
 		return nil, status.Errorf(
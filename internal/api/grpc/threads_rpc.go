@@ -0,0 +1,165 @@
+package subtraterpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SetThreadMuted mutes or unmutes a thread for the agent.
+func (s *Server) SetThreadMuted(ctx context.Context,
+	req *SetThreadMutedRequest,
+) (*ThreadPreferenceProto, error) {
+	if req.AgentId == 0 || req.ThreadId == "" {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"agent_id and thread_id are required",
+		)
+	}
+
+	pref, err := s.mailSvc.SetThreadMuted(
+		ctx, req.AgentId, req.ThreadId, req.Muted,
+	)
+	if err != nil {
+		return nil, threadStatusError("set thread muted", err)
+	}
+
+	return convertThreadPreference(pref), nil
+}
+
+// SetThreadFollowing follows or unfollows a thread for the agent.
+func (s *Server) SetThreadFollowing(ctx context.Context,
+	req *SetThreadFollowingRequest,
+) (*ThreadPreferenceProto, error) {
+	if req.AgentId == 0 || req.ThreadId == "" {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"agent_id and thread_id are required",
+		)
+	}
+
+	pref, err := s.mailSvc.SetThreadFollowing(
+		ctx, req.AgentId, req.ThreadId, req.Following,
+	)
+	if err != nil {
+		return nil, threadStatusError("set thread following", err)
+	}
+
+	return convertThreadPreference(pref), nil
+}
+
+// ListThreadPreferences lists the threads the agent muted or follows.
+func (s *Server) ListThreadPreferences(ctx context.Context,
+	req *ListThreadPreferencesRequest,
+) (*ListThreadPreferencesResponse, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id is required",
+		)
+	}
+
+	prefs, err := s.mailSvc.ListThreadPreferences(ctx, req.AgentId)
+	if err != nil {
+		return nil, threadStatusError("list thread preferences", err)
+	}
+
+	threads := make([]*ThreadPreferenceProto, len(prefs))
+	for i, pref := range prefs {
+		threads[i] = convertThreadPreference(pref)
+	}
+
+	return &ListThreadPreferencesResponse{Threads: threads}, nil
+}
+
+// MergeThreads moves every message of one thread into another.
+func (s *Server) MergeThreads(ctx context.Context,
+	req *MergeThreadsRequest,
+) (*MergeThreadsResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.FromThreadId == "" || req.IntoThreadId == "" {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"from_thread_id and into_thread_id are required",
+		)
+	}
+
+	moved, err := s.mailSvc.MergeThreads(
+		ctx, req.FromThreadId, req.IntoThreadId,
+	)
+	if err != nil {
+		return nil, threadStatusError("merge threads", err)
+	}
+
+	return &MergeThreadsResponse{MessagesMoved: moved}, nil
+}
+
+// SplitThread moves a message and every later message in its thread into a
+// new thread.
+func (s *Server) SplitThread(ctx context.Context,
+	req *SplitThreadRequest,
+) (*SplitThreadResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.MessageId == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "message_id is required",
+		)
+	}
+
+	resp, err := s.mailSvc.SplitThread(ctx, req.MessageId)
+	if err != nil {
+		return nil, threadStatusError("split thread", err)
+	}
+
+	return &SplitThreadResponse{
+		ThreadId:      resp.ThreadID,
+		MessagesMoved: resp.MessagesMoved,
+	}, nil
+}
+
+// threadStatusError maps thread errors from the mail service onto gRPC
+// status codes.
+func threadStatusError(operation string, err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, mail.ErrThreadNotFound),
+		errors.Is(err, mail.ErrMessageNotFound):
+
+		code = codes.NotFound
+
+	case errors.Is(err, mail.ErrInvalidThreadOp):
+		code = codes.InvalidArgument
+
+	case errors.Is(err, mail.ErrThreadInUse):
+		code = codes.FailedPrecondition
+	}
+
+	return status.Errorf(code, "failed to %s: %v", operation, err)
+}
+
+// convertThreadPreference converts a thread preference to its proto form.
+func convertThreadPreference(
+	pref store.ThreadPreference,
+) *ThreadPreferenceProto {
+	result := &ThreadPreferenceProto{
+		ThreadId:  pref.ThreadID,
+		AgentId:   pref.AgentID,
+		Muted:     pref.Muted,
+		Following: pref.Following,
+	}
+	if !pref.UpdatedAt.IsZero() {
+		result.UpdatedAt = timestamppb.New(pref.UpdatedAt)
+	}
+
+	return result
+}
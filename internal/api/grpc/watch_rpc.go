@@ -0,0 +1,334 @@
+package subtraterpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/mail"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// WatchInbox arms a watcher on an agent's inbox and streams the mail that
+// wakes it. The watcher holds the agent's watcher lease for as long as the
+// stream is open, renewing it as it goes; a second watcher is refused with
+// AlreadyExists unless it asks to take the lease over, in which case the
+// first is sent a lease lost event and its stream ends.
+func (s *Server) WatchInbox(req *WatchInboxRequest,
+	stream Mail_WatchInboxServer,
+) error {
+	if req.AgentId == 0 || req.Holder == "" {
+		return status.Error(
+			codes.InvalidArgument,
+			"agent_id and holder are required",
+		)
+	}
+
+	ctx := stream.Context()
+	filter, err := s.watchFilter(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	// A saved search that doesn't run now never will, so fail before
+	// arming rather than park forever.
+	if req.SavedSearch != "" {
+		_, err := s.mailSvc.Search(ctx, mail.SearchRequest{
+			AgentID:     req.AgentId,
+			SavedSearch: req.SavedSearch,
+			Limit:       1,
+		})
+		if err != nil {
+			return savedSearchStatusError("run saved search", err)
+		}
+	}
+
+	ttl := time.Duration(req.LeaseTtlSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = mail.DefaultWatchLeaseTTL
+	}
+	lease, err := s.watchLeases.Acquire(
+		req.AgentId, req.Holder, ttl, req.Takeover,
+	)
+	if errors.Is(err, mail.ErrWatcherArmed) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to arm: %v", err)
+	}
+	defer s.watchLeases.Release(lease)
+
+	// Subscribe before reading the backlog, so mail arriving in between
+	// isn't missed.
+	msgCh := make(chan mail.InboxMessage, 100)
+	subscriberID := fmt.Sprintf(
+		"grpc-watch-%d-%d", req.AgentId, time.Now().UnixNano(),
+	)
+	subFuture := s.notificationHub.Ask(ctx, mail.SubscribeAgentMsg{
+		AgentID:      req.AgentId,
+		SubscriberID: subscriberID,
+		DeliveryChan: msgCh,
+	})
+	if _, err := subFuture.Await(ctx).Unpack(); err != nil {
+		return status.Errorf(codes.Internal, "failed to subscribe: %v",
+			err)
+	}
+	defer func() {
+		// The stream's context may be cancelled by now.
+		cleanupCtx, cancel := context.WithTimeout(
+			context.Background(), 5*time.Second,
+		)
+		defer cancel()
+
+		unsubFuture := s.notificationHub.Ask(
+			cleanupCtx, mail.UnsubscribeAgentMsg{
+				AgentID:      req.AgentId,
+				SubscriberID: subscriberID,
+			},
+		)
+		unsubFuture.Await(cleanupCtx)
+		close(msgCh)
+	}()
+
+	err = stream.Send(&WatchInboxEvent{
+		Type:           WatchEventType_WATCH_EVENT_TYPE_ARMED,
+		LeaseExpiresAt: timestamppb.New(lease.ExpiresAt),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Unread backlog the watcher hasn't seen wakes it straight away.
+	lastID := req.AfterMessageId
+	backlog, err := s.pollChangesActor(ctx, req.AgentId, nil)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to poll inbox: %v",
+			err)
+	}
+	lastID, err = s.sendWatchWake(
+		ctx, stream, req, filter, lastID, backlog.NewMessages,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Renew well before the lease lapses, so a slow renewal can't let
+	// another watcher arm.
+	renew := time.NewTicker(ttl / 3)
+	defer renew.Stop()
+
+	leaseLost := func() error {
+		return stream.Send(&WatchInboxEvent{
+			Type: WatchEventType_WATCH_EVENT_TYPE_LEASE_LOST,
+		})
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-s.quit:
+			return status.Error(
+				codes.Unavailable, "server shutting down",
+			)
+
+		case <-lease.Lost():
+			return leaseLost()
+
+		case <-renew.C:
+			if _, err := s.watchLeases.Renew(lease); err != nil {
+				return leaseLost()
+			}
+
+		case msg := <-msgCh:
+			// Batch up whatever else already arrived, so a burst
+			// of mail wakes the watcher once. Edits of mail don't
+			// wake it again.
+			var msgs []mail.InboxMessage
+		drain:
+			for {
+				if msg.EditedAt == nil {
+					msgs = append(msgs, msg)
+				}
+
+				select {
+				case msg = <-msgCh:
+				default:
+					break drain
+				}
+			}
+
+			lastID, err = s.sendWatchWake(
+				ctx, stream, req, filter, lastID, msgs,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// GetWatcher reports the watcher holding an agent's watcher lease, if any.
+func (s *Server) GetWatcher(ctx context.Context,
+	req *GetWatcherRequest,
+) (*GetWatcherResponse, error) {
+	if req.AgentId == 0 {
+		return nil, status.Error(
+			codes.InvalidArgument, "agent_id is required",
+		)
+	}
+
+	holder, expiresAt, ok := s.watchLeases.Holder(req.AgentId)
+	if !ok {
+		return &GetWatcherResponse{}, nil
+	}
+
+	return &GetWatcherResponse{
+		Armed:          true,
+		Holder:         holder,
+		LeaseExpiresAt: timestamppb.New(expiresAt),
+	}, nil
+}
+
+// watchFilter builds the wake filter of a WatchInbox request, resolving the
+// sender and topic names it gives.
+func (s *Server) watchFilter(ctx context.Context,
+	req *WatchInboxRequest,
+) (mail.WatchFilter, error) {
+	filter := mail.WatchFilter{ThreadIDs: req.ThreadIds}
+
+	for _, p := range req.Priorities {
+		priority := mail.PriorityNormal
+		switch p {
+		case Priority_PRIORITY_LOW:
+			priority = mail.PriorityLow
+		case Priority_PRIORITY_URGENT:
+			priority = mail.PriorityUrgent
+		}
+		filter.Priorities = append(filter.Priorities, priority)
+	}
+
+	for _, name := range req.SenderNames {
+		a, err := s.store.Queries().GetAgentByName(ctx, name)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return filter, status.Errorf(
+				codes.NotFound, "unknown sender %q", name,
+			)
+
+		case err != nil:
+			return filter, status.Errorf(
+				codes.Internal, "failed to get agent: %v", err,
+			)
+		}
+		filter.SenderIDs = append(filter.SenderIDs, a.ID)
+	}
+
+	for _, name := range req.TopicNames {
+		t, err := s.store.Queries().GetTopicByName(ctx, name)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return filter, status.Errorf(
+				codes.NotFound, "unknown topic %q", name,
+			)
+
+		case err != nil:
+			return filter, status.Errorf(
+				codes.Internal, "failed to get topic: %v", err,
+			)
+		}
+		filter.TopicIDs = append(filter.TopicIDs, t.ID)
+	}
+
+	return filter, nil
+}
+
+// sendWatchWake sends a wake event for the messages that pass the watcher's
+// filters, if there are any, returning the newest message ID the watcher has
+// now been woken for. New mail no newer than lastID was already woken for, so
+// it's skipped, while repushed mail, such as a message whose snooze ended,
+// wakes the watcher however old it is.
+func (s *Server) sendWatchWake(ctx context.Context,
+	stream Mail_WatchInboxServer, req *WatchInboxRequest,
+	filter mail.WatchFilter, lastID int64, msgs []mail.InboxMessage,
+) (int64, error) {
+	var wake []mail.InboxMessage
+	for _, msg := range msgs {
+		fresh := msg.ID > lastID || msg.Repushed
+		if fresh && filter.Matches(msg) {
+			wake = append(wake, msg)
+		}
+	}
+
+	if req.SavedSearch != "" && len(wake) > 0 {
+		var err error
+		wake, err = s.matchSavedSearch(
+			ctx, req.AgentId, req.SavedSearch, wake,
+		)
+		if err != nil {
+			return lastID, savedSearchStatusError(
+				"run saved search", err,
+			)
+		}
+	}
+	if len(wake) == 0 {
+		return lastID, nil
+	}
+
+	protoMsgs := convertMessages(wake)
+	s.populateRecipients(ctx, protoMsgs)
+
+	err := stream.Send(&WatchInboxEvent{
+		Type:     WatchEventType_WATCH_EVENT_TYPE_WAKE,
+		Messages: protoMsgs,
+	})
+	if err != nil {
+		return lastID, err
+	}
+
+	for _, msg := range wake {
+		lastID = max(lastID, msg.ID)
+	}
+
+	return lastID, nil
+}
+
+// matchSavedSearch returns the messages one of the agent's saved searches
+// matches, in their original order.
+func (s *Server) matchSavedSearch(ctx context.Context, agentID int64,
+	name string, msgs []mail.InboxMessage,
+) ([]mail.InboxMessage, error) {
+	ids := make([]int64, len(msgs))
+	for i, msg := range msgs {
+		ids[i] = msg.ID
+	}
+
+	resp, err := s.mailSvc.Search(ctx, mail.SearchRequest{
+		AgentID:     agentID,
+		SavedSearch: name,
+		MessageIDs:  ids,
+		Limit:       int32(len(ids)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make(map[int64]bool, len(resp.Hits))
+	for _, hit := range resp.Hits {
+		matched[hit.ID] = true
+	}
+
+	var kept []mail.InboxMessage
+	for _, msg := range msgs {
+		if matched[msg.ID] {
+			kept = append(kept, msg)
+		}
+	}
+
+	return kept, nil
+}
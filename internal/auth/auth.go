@@ -0,0 +1,346 @@
+// Package auth mints and checks the bearer credentials agents and the web UI
+// present to the daemon. A credential belongs either to one agent, whose
+// identity the server then derives every request's sender from, or to an
+// administrator who may act as anyone.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+const (
+	// TokenPrefix starts every token, so a leaked one is easy to spot.
+	TokenPrefix = "sbt_"
+
+	// tokenBytes is how many random bytes a token carries.
+	tokenBytes = 32
+
+	// touchInterval is how often at most a credential's last use is
+	// written back, so that every request doesn't cost a write.
+	touchInterval = time.Minute
+)
+
+var (
+	// ErrMissingCredential is returned when a request carries no token.
+	ErrMissingCredential = errors.New("missing credential")
+
+	// ErrInvalidCredential is returned when a token is malformed or
+	// unknown.
+	ErrInvalidCredential = errors.New("invalid credential")
+
+	// ErrCredentialExpired is returned when a token is past its expiry.
+	ErrCredentialExpired = errors.New("credential expired")
+
+	// ErrCredentialRevoked is returned when a token has been revoked.
+	ErrCredentialRevoked = errors.New("credential revoked")
+
+	// ErrMethodNotAllowed is returned when a credential is restricted to
+	// RPCs that don't include the one called.
+	ErrMethodNotAllowed = errors.New("method not allowed")
+
+	// ErrAdminRequired is returned when an agent credential is used for
+	// something only an administrator may do.
+	ErrAdminRequired = errors.New("admin credential required")
+
+	// ErrCredentialNotFound is returned when revoking a credential that
+	// doesn't exist or was already revoked.
+	ErrCredentialNotFound = errors.New("credential not found")
+)
+
+// MintRequest describes a credential to mint.
+type MintRequest struct {
+	// AgentID is the agent the credential acts as, or zero for an admin
+	// credential.
+	AgentID int64
+
+	// Name is a free-form label, e.g. where the token is used.
+	Name string
+
+	// AllowedMethods restricts the credential to these RPCs, given by
+	// method name (SendMail) or full name (/subtraterpc.Mail/SendMail).
+	// Empty allows every RPC.
+	AllowedMethods []string
+
+	// TTL is how long the credential is valid for, or zero for no
+	// expiry.
+	TTL time.Duration
+}
+
+// Service mints, checks and revokes credentials.
+type Service struct {
+	store store.Storage
+
+	// mu guards touched.
+	mu sync.Mutex
+
+	// touched records when each credential's last use was written.
+	touched map[int64]time.Time
+}
+
+// NewService creates a credential service backed by the given store.
+func NewService(st store.Storage) *Service {
+	return &Service{
+		store:   st,
+		touched: make(map[int64]time.Time),
+	}
+}
+
+// Mint creates a credential and returns its token, which is only ever
+// available here: the store keeps just its hash.
+func (s *Service) Mint(ctx context.Context,
+	req MintRequest,
+) (string, store.Credential, error) {
+	if req.TTL < 0 {
+		return "", store.Credential{}, fmt.Errorf("negative ttl %v",
+			req.TTL)
+	}
+
+	var methods []string
+	for _, m := range req.AllowedMethods {
+		m = strings.TrimSpace(m)
+		if m == "" {
+			continue
+		}
+		if strings.Contains(m, ",") {
+			return "", store.Credential{}, fmt.Errorf("invalid "+
+				"method %q", m)
+		}
+		methods = append(methods, m)
+	}
+
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", store.Credential{}, fmt.Errorf("generate token: %w",
+			err)
+	}
+	token := TokenPrefix + hex.EncodeToString(buf)
+
+	params := store.CreateCredentialParams{
+		AgentID:        req.AgentID,
+		Name:           req.Name,
+		TokenHash:      HashToken(token),
+		AllowedMethods: methods,
+	}
+	if req.TTL > 0 {
+		expiresAt := time.Now().Add(req.TTL)
+		params.ExpiresAt = &expiresAt
+	}
+
+	cred, err := s.store.CreateCredential(ctx, params)
+	if err != nil {
+		return "", store.Credential{}, fmt.Errorf("create credential: "+
+			"%w", err)
+	}
+
+	return token, cred, nil
+}
+
+// Authenticate returns the live credential a token belongs to.
+func (s *Service) Authenticate(ctx context.Context,
+	token string,
+) (store.Credential, error) {
+	if token == "" {
+		return store.Credential{}, ErrMissingCredential
+	}
+	if !strings.HasPrefix(token, TokenPrefix) {
+		return store.Credential{}, ErrInvalidCredential
+	}
+
+	cred, err := s.store.GetCredentialByHash(ctx, HashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return store.Credential{}, ErrInvalidCredential
+	}
+	if err != nil {
+		return store.Credential{}, fmt.Errorf("get credential: %w",
+			err)
+	}
+
+	now := time.Now()
+	switch {
+	case cred.RevokedAt != nil:
+		return store.Credential{}, ErrCredentialRevoked
+
+	case cred.ExpiresAt != nil && !now.Before(*cred.ExpiresAt):
+		return store.Credential{}, ErrCredentialExpired
+	}
+
+	s.touch(ctx, cred.ID, now)
+
+	return cred, nil
+}
+
+// touch records a credential's use, at most once per touchInterval. A
+// failure only costs an out of date last-used time, so it is ignored.
+func (s *Service) touch(ctx context.Context, id int64, now time.Time) {
+	s.mu.Lock()
+	last, ok := s.touched[id]
+	if ok && now.Sub(last) < touchInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.touched[id] = now
+	s.mu.Unlock()
+
+	_ = s.store.TouchCredential(ctx, id, now)
+}
+
+// List lists credentials, newest first, for one agent or for everyone if
+// agentID is zero.
+func (s *Service) List(ctx context.Context, agentID int64,
+	includeRevoked bool,
+) ([]store.Credential, error) {
+	return s.store.ListCredentials(ctx, agentID, includeRevoked)
+}
+
+// Revoke revokes a credential so its token stops working at once.
+func (s *Service) Revoke(ctx context.Context, id int64) error {
+	revoked, err := s.store.RevokeCredential(ctx, id, time.Now())
+	if err != nil {
+		return fmt.Errorf("revoke credential: %w", err)
+	}
+	if !revoked {
+		return fmt.Errorf("%w: %d", ErrCredentialNotFound, id)
+	}
+
+	return nil
+}
+
+// EnsureAdminToken returns the admin token kept in the file at tokenPath,
+// minting one and writing it with owner-only permissions if the file is
+// missing or its token no longer works.
+func (s *Service) EnsureAdminToken(ctx context.Context,
+	tokenPath string,
+) (string, error) {
+	data, err := os.ReadFile(tokenPath)
+	switch {
+	case err == nil:
+		token := strings.TrimSpace(string(data))
+		cred, err := s.Authenticate(ctx, token)
+		if err == nil && cred.IsAdmin() {
+			return token, nil
+		}
+
+	case !errors.Is(err, os.ErrNotExist):
+		return "", fmt.Errorf("read admin token: %w", err)
+	}
+
+	token, _, err := s.Mint(ctx, MintRequest{Name: "admin token file"})
+	if err != nil {
+		return "", err
+	}
+
+	if err := WriteTokenFile(tokenPath, token); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// WriteTokenFile writes a token to a file only its owner can read,
+// creating the parent directory if needed.
+func WriteTokenFile(tokenPath, token string) error {
+	err := os.MkdirAll(filepath.Dir(tokenPath), 0700)
+	if err != nil {
+		return fmt.Errorf("create token dir: %w", err)
+	}
+
+	err = os.WriteFile(tokenPath, []byte(token+"\n"), 0600)
+	if err != nil {
+		return fmt.Errorf("write token file: %w", err)
+	}
+
+	return nil
+}
+
+// ReadTokenFile reads the token in a file written by WriteTokenFile.
+func ReadTokenFile(tokenPath string) (string, error) {
+	data, err := os.ReadFile(tokenPath)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// DefaultAdminTokenFile returns where the daemon keeps its admin token by
+// default, ~/.subtrate/admin.token.
+func DefaultAdminTokenFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".subtrate", "admin.token"), nil
+}
+
+// HashToken returns the hex SHA-256 hash a token is stored under.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseBearer extracts the token from an Authorization header value of the
+// form "Bearer <token>".
+func ParseBearer(header string) (string, error) {
+	if header == "" {
+		return "", ErrMissingCredential
+	}
+
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return "", ErrInvalidCredential
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", ErrMissingCredential
+	}
+
+	return token, nil
+}
+
+// MethodAllowed reports whether a credential may call the RPC with the given
+// full method name, e.g. /subtraterpc.Mail/SendMail.
+func MethodAllowed(cred store.Credential, fullMethod string) bool {
+	if len(cred.AllowedMethods) == 0 {
+		return true
+	}
+
+	name := path.Base(fullMethod)
+	for _, m := range cred.AllowedMethods {
+		if m == fullMethod || strings.EqualFold(m, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// credentialKey is the context key the authenticated credential is kept
+// under.
+type credentialKey struct{}
+
+// NewContext returns a context carrying the authenticated credential.
+func NewContext(ctx context.Context, cred store.Credential) context.Context {
+	return context.WithValue(ctx, credentialKey{}, cred)
+}
+
+// FromContext returns the authenticated credential in a context, if any.
+func FromContext(ctx context.Context) (store.Credential, bool) {
+	cred, ok := ctx.Value(credentialKey{}).(store.Credential)
+	return cred, ok
+}
@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db"
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore creates a temporary test database with migrations applied.
+func testStore(t *testing.T) store.Storage {
+	t.Helper()
+
+	dir := t.TempDir()
+	sqlDB, err := db.OpenSQLite(filepath.Join(dir, "test.db"))
+	require.NoError(t, err)
+
+	err = db.RunMigrations(sqlDB, "../db/migrations")
+	require.NoError(t, err)
+
+	storage := store.FromDB(sqlDB)
+	t.Cleanup(func() {
+		storage.Close()
+	})
+
+	return storage
+}
+
+// TestCredentials tests that minted tokens authenticate as their agent until
+// they expire or are revoked, and that deleting an agent drops its
+// credentials.
+func TestCredentials(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage := testStore(t)
+	svc := NewService(storage)
+
+	alice, err := storage.CreateAgent(ctx, store.CreateAgentParams{
+		Name: "Alice",
+	})
+	require.NoError(t, err)
+
+	token, cred, err := svc.Mint(ctx, MintRequest{
+		AgentID:        alice.ID,
+		Name:           "laptop",
+		AllowedMethods: []string{"SendMail", " ", "FetchInbox"},
+	})
+	require.NoError(t, err)
+	require.Contains(t, token, TokenPrefix)
+	require.Equal(t, "Alice", cred.AgentName)
+	require.False(t, cred.IsAdmin())
+
+	got, err := svc.Authenticate(ctx, token)
+	require.NoError(t, err)
+	require.Equal(t, cred.ID, got.ID)
+	require.Equal(t, []string{"SendMail", "FetchInbox"}, got.AllowedMethods)
+	require.True(t, MethodAllowed(got, "/subtraterpc.Mail/SendMail"))
+	require.False(t, MethodAllowed(got, "/subtraterpc.Mail/DeleteAgent"))
+
+	_, err = svc.Authenticate(ctx, "")
+	require.ErrorIs(t, err, ErrMissingCredential)
+	_, err = svc.Authenticate(ctx, TokenPrefix+"nope")
+	require.ErrorIs(t, err, ErrInvalidCredential)
+	_, err = svc.Authenticate(ctx, token[len(TokenPrefix):])
+	require.ErrorIs(t, err, ErrInvalidCredential)
+
+	// The token's last use is recorded.
+	creds, err := svc.List(ctx, alice.ID, false)
+	require.NoError(t, err)
+	require.Len(t, creds, 1)
+	require.NotNil(t, creds[0].LastUsedAt)
+
+	// A token that has expired is refused.
+	expiring, _, err := svc.Mint(ctx, MintRequest{
+		AgentID: alice.ID, TTL: time.Nanosecond,
+	})
+	require.NoError(t, err)
+	_, err = svc.Authenticate(ctx, expiring)
+	require.ErrorIs(t, err, ErrCredentialExpired)
+
+	// Revoking a token stops it working, and it can't be revoked twice.
+	require.NoError(t, svc.Revoke(ctx, cred.ID))
+	_, err = svc.Authenticate(ctx, token)
+	require.ErrorIs(t, err, ErrCredentialRevoked)
+	require.ErrorIs(t, svc.Revoke(ctx, cred.ID), ErrCredentialNotFound)
+
+	creds, err = svc.List(ctx, alice.ID, false)
+	require.NoError(t, err)
+	require.Len(t, creds, 1)
+	creds, err = svc.List(ctx, 0, true)
+	require.NoError(t, err)
+	require.Len(t, creds, 2)
+
+	// Deleting the agent deletes its credentials.
+	require.NoError(t, storage.DeleteAgent(ctx, alice.ID))
+	creds, err = svc.List(ctx, 0, true)
+	require.NoError(t, err)
+	require.Empty(t, creds)
+}
+
+// TestEnsureAdminToken tests that the admin token file is created once and
+// replaced if its token stops working.
+func TestEnsureAdminToken(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	svc := NewService(testStore(t))
+	tokenPath := filepath.Join(t.TempDir(), "auth", "admin.token")
+
+	token, err := svc.EnsureAdminToken(ctx, tokenPath)
+	require.NoError(t, err)
+
+	info, err := os.Stat(tokenPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	cred, err := svc.Authenticate(ctx, token)
+	require.NoError(t, err)
+	require.True(t, cred.IsAdmin())
+
+	again, err := svc.EnsureAdminToken(ctx, tokenPath)
+	require.NoError(t, err)
+	require.Equal(t, token, again)
+
+	require.NoError(t, svc.Revoke(ctx, cred.ID))
+	again, err = svc.EnsureAdminToken(ctx, tokenPath)
+	require.NoError(t, err)
+	require.NotEqual(t, token, again)
+
+	fromFile, err := ReadTokenFile(tokenPath)
+	require.NoError(t, err)
+	require.Equal(t, again, fromFile)
+}
+
+// TestParseBearer tests parsing Authorization header values.
+func TestParseBearer(t *testing.T) {
+	t.Parallel()
+
+	token, err := ParseBearer("Bearer sbt_abc")
+	require.NoError(t, err)
+	require.Equal(t, "sbt_abc", token)
+
+	token, err = ParseBearer("bearer  sbt_abc ")
+	require.NoError(t, err)
+	require.Equal(t, "sbt_abc", token)
+
+	_, err = ParseBearer("")
+	require.ErrorIs(t, err, ErrMissingCredential)
+	_, err = ParseBearer("Basic Zm9vOmJhcg==")
+	require.ErrorIs(t, err, ErrInvalidCredential)
+}
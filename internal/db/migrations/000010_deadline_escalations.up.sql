@@ -0,0 +1,52 @@
+-- Rebuild the activities table so its CHECK constraint accepts the review
+-- activity types (which migration 3 only attempted to allow via a trigger)
+-- and the new deadline escalation type. SQLite doesn't support altering a
+-- CHECK constraint, so we copy the rows into a fresh table.
+DROP TRIGGER IF EXISTS check_activity_type_reviews;
+
+CREATE TABLE activities_new (
+    id INTEGER PRIMARY KEY,
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    activity_type TEXT NOT NULL CHECK (activity_type IN (
+        'commit', 'message', 'session_start', 'session_complete',
+        'decision', 'error', 'blocker', 'heartbeat', 'task_complete',
+        'review_requested', 'review_started', 'review_completed',
+        'review_approved', 'review_rejected', 'issue_resolved',
+        'deadline_escalation'
+    )),
+    description TEXT NOT NULL,
+    metadata TEXT, -- JSON blob for additional context.
+    created_at INTEGER NOT NULL
+);
+
+INSERT INTO activities_new (
+    id, agent_id, activity_type, description, metadata, created_at
+)
+SELECT id, agent_id, activity_type, description, metadata, created_at
+FROM activities;
+
+DROP TABLE activities;
+ALTER TABLE activities_new RENAME TO activities;
+
+CREATE INDEX IF NOT EXISTS idx_activities_agent ON activities(agent_id);
+CREATE INDEX IF NOT EXISTS idx_activities_type ON activities(activity_type);
+CREATE INDEX IF NOT EXISTS idx_activities_created ON activities(created_at DESC);
+
+-- Message escalations track how far each overdue recipient has been
+-- escalated so the deadline monitor never repeats a level, even across
+-- daemon restarts.
+CREATE TABLE message_escalations (
+    message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+
+    -- Highest escalation level applied so far (1-based).
+    level INTEGER NOT NULL,
+
+    -- Timestamps (Unix epoch seconds).
+    last_escalated_at INTEGER NOT NULL,
+
+    PRIMARY KEY (message_id, agent_id)
+);
+
+CREATE INDEX idx_messages_deadline ON messages(deadline_at)
+    WHERE deadline_at IS NOT NULL;
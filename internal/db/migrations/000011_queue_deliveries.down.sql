@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_queue_deliveries_lease;
+DROP INDEX IF EXISTS idx_queue_deliveries_topic_state;
+DROP TABLE IF EXISTS queue_deliveries;
@@ -0,0 +1,29 @@
+-- Queue deliveries track the competing-consumer lifecycle of messages
+-- published to 'queue' topics. Each message is leased to exactly one
+-- subscriber at a time; unacked leases expire and the message becomes
+-- claimable again until it runs out of delivery attempts and is moved to
+-- the topic's dead-letter topic.
+CREATE TABLE queue_deliveries (
+    message_id INTEGER PRIMARY KEY REFERENCES messages(id) ON DELETE CASCADE,
+    topic_id INTEGER NOT NULL REFERENCES topics(id) ON DELETE CASCADE,
+
+    state TEXT NOT NULL DEFAULT 'pending'
+        CHECK (state IN ('pending', 'leased', 'acked', 'dead_lettered')),
+
+    -- Current lease holder, NULL unless leased.
+    claimed_by INTEGER REFERENCES agents(id) ON DELETE SET NULL,
+    lease_expires_at INTEGER,
+
+    -- Number of times the message has been claimed.
+    delivery_count INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+
+    -- Timestamps (Unix epoch seconds).
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+CREATE INDEX idx_queue_deliveries_topic_state
+    ON queue_deliveries(topic_id, state, created_at);
+CREATE INDEX idx_queue_deliveries_lease
+    ON queue_deliveries(state, lease_expires_at);
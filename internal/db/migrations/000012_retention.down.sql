@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_messages_legal_hold;
+
+-- Note: SQLite doesn't support DROP COLUMN directly before 3.35.0.
+ALTER TABLE messages DROP COLUMN legal_hold;
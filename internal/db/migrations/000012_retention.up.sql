@@ -0,0 +1,6 @@
+-- Legal hold flag: messages under a legal hold are never removed by the
+-- retention compactor, regardless of their topic's retention_seconds.
+ALTER TABLE messages ADD COLUMN legal_hold INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_messages_legal_hold
+    ON messages(legal_hold) WHERE legal_hold = 1;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS message_recipient_expansions;
+DROP INDEX IF EXISTS idx_recipient_group_members_agent;
+DROP TABLE IF EXISTS recipient_group_members;
+DROP TABLE IF EXISTS recipient_groups;
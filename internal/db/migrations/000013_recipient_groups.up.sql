@@ -0,0 +1,28 @@
+-- Recipient groups are named sets of agents that can be addressed as a
+-- single recipient, e.g. "@backend-team".
+CREATE TABLE recipient_groups (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_by INTEGER REFERENCES agents(id) ON DELETE SET NULL,
+    created_at INTEGER NOT NULL
+);
+
+CREATE TABLE recipient_group_members (
+    group_id INTEGER NOT NULL REFERENCES recipient_groups(id) ON DELETE CASCADE,
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    added_at INTEGER NOT NULL,
+    PRIMARY KEY (group_id, agent_id)
+);
+
+CREATE INDEX idx_recipient_group_members_agent
+    ON recipient_group_members(agent_id);
+
+-- Recipient expansions record, for auditing, which agents each group or
+-- dynamic alias in a message's recipient list resolved to at send time.
+CREATE TABLE message_recipient_expansions (
+    message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    address TEXT NOT NULL,
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    PRIMARY KEY (message_id, address, agent_id)
+);
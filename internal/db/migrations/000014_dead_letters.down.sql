@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_dead_letters_sender;
+DROP TABLE IF EXISTS dead_letters;
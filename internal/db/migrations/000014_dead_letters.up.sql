@@ -0,0 +1,15 @@
+-- Dead letters record the addressees of a message that could not be
+-- delivered to, either because no agent or group matched the name or because
+-- a delivery policy rejected the recipient. Each sender can inspect their
+-- own dead letters.
+CREATE TABLE dead_letters (
+    id INTEGER PRIMARY KEY,
+    message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    sender_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    recipient TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('unknown', 'rejected')),
+    reason TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL
+);
+
+CREATE INDEX idx_dead_letters_sender ON dead_letters(sender_id, created_at);
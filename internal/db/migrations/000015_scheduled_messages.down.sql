@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_scheduled_messages_sender;
+DROP INDEX IF EXISTS idx_scheduled_messages_due;
+DROP TABLE IF EXISTS scheduled_messages;
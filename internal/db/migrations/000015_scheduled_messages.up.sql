@@ -0,0 +1,36 @@
+-- Scheduled messages hold send requests that should be delivered at a later
+-- time. The idempotency key is fixed when the message is scheduled and is
+-- reused for the eventual send, so a scheduler that crashes after sending but
+-- before marking the row as sent never delivers the message twice.
+CREATE TABLE scheduled_messages (
+    id INTEGER PRIMARY KEY,
+    sender_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+
+    -- JSON array of recipient names, groups and aliases, resolved at
+    -- delivery time.
+    recipients TEXT NOT NULL DEFAULT '[]',
+    topic_name TEXT NOT NULL DEFAULT '',
+    thread_id TEXT NOT NULL DEFAULT '',
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    priority TEXT NOT NULL DEFAULT 'normal',
+    deadline_at INTEGER,
+    attachments TEXT NOT NULL DEFAULT '',
+    delivery_mode TEXT NOT NULL DEFAULT 'atomic',
+    idempotency_key TEXT NOT NULL UNIQUE,
+
+    send_at INTEGER NOT NULL,
+    state TEXT NOT NULL DEFAULT 'pending'
+        CHECK (state IN ('pending', 'sent', 'canceled', 'failed')),
+
+    -- The message created by the send, once delivered.
+    message_id INTEGER REFERENCES messages(id) ON DELETE SET NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+CREATE INDEX idx_scheduled_messages_due
+    ON scheduled_messages(state, send_at);
+CREATE INDEX idx_scheduled_messages_sender
+    ON scheduled_messages(sender_id, state);
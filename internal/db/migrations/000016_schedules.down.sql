@@ -0,0 +1,33 @@
+-- Restore the activities CHECK constraint from migration 10. Schedule run
+-- activities can't be represented and are dropped.
+CREATE TABLE activities_old (
+    id INTEGER PRIMARY KEY,
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    activity_type TEXT NOT NULL CHECK (activity_type IN (
+        'commit', 'message', 'session_start', 'session_complete',
+        'decision', 'error', 'blocker', 'heartbeat', 'task_complete',
+        'review_requested', 'review_started', 'review_completed',
+        'review_approved', 'review_rejected', 'issue_resolved',
+        'deadline_escalation'
+    )),
+    description TEXT NOT NULL,
+    metadata TEXT, -- JSON blob for additional context.
+    created_at INTEGER NOT NULL
+);
+
+INSERT INTO activities_old (
+    id, agent_id, activity_type, description, metadata, created_at
+)
+SELECT id, agent_id, activity_type, description, metadata, created_at
+FROM activities
+WHERE activity_type != 'schedule_run';
+
+DROP TABLE activities;
+ALTER TABLE activities_old RENAME TO activities;
+
+CREATE INDEX IF NOT EXISTS idx_activities_agent ON activities(agent_id);
+CREATE INDEX IF NOT EXISTS idx_activities_type ON activities(activity_type);
+CREATE INDEX IF NOT EXISTS idx_activities_created ON activities(created_at DESC);
+
+DROP INDEX IF EXISTS idx_schedules_due;
+DROP TABLE IF EXISTS schedules;
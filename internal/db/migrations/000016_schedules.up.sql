@@ -0,0 +1,76 @@
+-- Schedules are recurring messages that the daemon sends on a cron
+-- expression, evaluated in the schedule's time zone. next_run_at always holds
+-- the next occurrence that has not been handled yet, so after downtime the
+-- scheduler can tell which occurrences were missed and apply the schedule's
+-- catch-up policy.
+--
+-- IDs are never reused, since each run's idempotency key is derived from the
+-- schedule ID and occurrence time.
+CREATE TABLE schedules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+
+    -- The agent that owns the schedule and that its messages are sent
+    -- from.
+    sender_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+
+    -- JSON array of recipient names, groups and aliases, resolved at each
+    -- run. Exactly one of recipients and topic_name is set.
+    recipients TEXT NOT NULL DEFAULT '[]',
+    topic_name TEXT NOT NULL DEFAULT '',
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    priority TEXT NOT NULL DEFAULT 'normal',
+
+    cron_expr TEXT NOT NULL,
+    timezone TEXT NOT NULL DEFAULT 'Local',
+
+    -- What to do with occurrences missed while the daemon was down: 'once'
+    -- sends a single catch-up message, 'skip' drops them.
+    catch_up TEXT NOT NULL DEFAULT 'once'
+        CHECK (catch_up IN ('once', 'skip')),
+    paused INTEGER NOT NULL DEFAULT 0,
+
+    next_run_at INTEGER NOT NULL,
+    last_run_at INTEGER,
+    last_message_id INTEGER REFERENCES messages(id) ON DELETE SET NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    run_count INTEGER NOT NULL DEFAULT 0,
+
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+
+    UNIQUE (sender_id, name)
+);
+
+CREATE INDEX idx_schedules_due ON schedules(paused, next_run_at);
+
+-- Rebuild the activities table so its CHECK constraint accepts the
+-- schedule_run activity type.
+CREATE TABLE activities_new (
+    id INTEGER PRIMARY KEY,
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    activity_type TEXT NOT NULL CHECK (activity_type IN (
+        'commit', 'message', 'session_start', 'session_complete',
+        'decision', 'error', 'blocker', 'heartbeat', 'task_complete',
+        'review_requested', 'review_started', 'review_completed',
+        'review_approved', 'review_rejected', 'issue_resolved',
+        'deadline_escalation', 'schedule_run'
+    )),
+    description TEXT NOT NULL,
+    metadata TEXT, -- JSON blob for additional context.
+    created_at INTEGER NOT NULL
+);
+
+INSERT INTO activities_new (
+    id, agent_id, activity_type, description, metadata, created_at
+)
+SELECT id, agent_id, activity_type, description, metadata, created_at
+FROM activities;
+
+DROP TABLE activities;
+ALTER TABLE activities_new RENAME TO activities;
+
+CREATE INDEX IF NOT EXISTS idx_activities_agent ON activities(agent_id);
+CREATE INDEX IF NOT EXISTS idx_activities_type ON activities(activity_type);
+CREATE INDEX IF NOT EXISTS idx_activities_created ON activities(created_at DESC);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_questions_timeout;
+DROP INDEX IF EXISTS idx_questions_thread;
+DROP TABLE IF EXISTS questions;
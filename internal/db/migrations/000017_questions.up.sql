@@ -0,0 +1,52 @@
+-- Questions are structured ask/answer messages. Each question is sent as an
+-- ordinary message, and the first reply in its thread from someone other
+-- than the asker is recorded as the answer, whichever interface the reply
+-- came from. A question that isn't answered before its timeout is escalated
+-- to its fallback recipient, and after that falls back to its default
+-- answer or expires.
+CREATE TABLE questions (
+    id INTEGER PRIMARY KEY,
+
+    -- Public identifier of the question, used by 'substrate ask wait'.
+    question_id TEXT NOT NULL UNIQUE,
+
+    -- The message that asked the question and the thread answers are
+    -- correlated by.
+    message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    thread_id TEXT NOT NULL,
+    asker_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+
+    question TEXT NOT NULL,
+
+    -- JSON array of the suggested answers, empty for a free-form question.
+    options TEXT NOT NULL DEFAULT '[]',
+    default_answer TEXT NOT NULL DEFAULT '',
+
+    -- How long the question waits at each stage, in seconds, and when the
+    -- current stage times out. Both are unset if the question never times
+    -- out.
+    timeout_seconds INTEGER NOT NULL DEFAULT 0,
+    timeout_at INTEGER,
+
+    -- The agent the question is escalated to, and when it was.
+    fallback TEXT NOT NULL DEFAULT '',
+    escalated_at INTEGER,
+
+    -- 'answered' questions were answered by a reply, 'defaulted' ones took
+    -- their default answer after timing out and 'expired' ones had none.
+    state TEXT NOT NULL DEFAULT 'pending'
+        CHECK (state IN ('pending', 'answered', 'defaulted', 'expired')),
+    answer TEXT NOT NULL DEFAULT '',
+    answered_by INTEGER REFERENCES agents(id) ON DELETE SET NULL,
+    answer_message_id INTEGER REFERENCES messages(id) ON DELETE SET NULL,
+    answered_at INTEGER,
+
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+-- Replies look up the pending questions in their thread.
+CREATE INDEX idx_questions_thread ON questions(thread_id, state);
+
+-- The question monitor looks up pending questions that have timed out.
+CREATE INDEX idx_questions_timeout ON questions(state, timeout_at);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS message_revisions;
+
+-- Note: SQLite doesn't support DROP COLUMN directly before 3.35.0.
+ALTER TABLE messages DROP COLUMN edited_at;
@@ -0,0 +1,25 @@
+-- Edited messages: edited_at is when a message's sender last edited it, and
+-- is unset for messages that were never edited.
+ALTER TABLE messages ADD COLUMN edited_at INTEGER;
+
+-- Every edit records the version of the message it replaced, so the full
+-- history of a message is its revisions followed by its current subject and
+-- body. Revisions are numbered from 1, the message as originally sent. The
+-- messages_au trigger keeps the search index pointed at the current
+-- version.
+CREATE TABLE message_revisions (
+    id INTEGER PRIMARY KEY,
+    message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    subject TEXT NOT NULL,
+    body_md TEXT NOT NULL,
+
+    -- When this version was created: the send time for revision 1, and
+    -- the time of the edit that produced it otherwise.
+    created_at INTEGER NOT NULL,
+
+    -- When this version was replaced by an edit.
+    replaced_at INTEGER NOT NULL,
+
+    UNIQUE (message_id, revision)
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS inbox_rules;
+
+-- Note: SQLite doesn't support DROP COLUMN directly before 3.35.0.
+ALTER TABLE scheduled_messages DROP COLUMN metadata;
+ALTER TABLE message_recipients DROP COLUMN category;
@@ -0,0 +1,49 @@
+-- Inbox rules are per-agent filters applied to every message as it is
+-- delivered to the agent, whether it was sent directly or published to a
+-- topic the agent subscribes to. An agent's enabled rules are applied in
+-- the order they were created, and a matching rule with stop_processing set
+-- keeps later rules from being applied.
+CREATE TABLE inbox_rules (
+    id INTEGER PRIMARY KEY,
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    enabled INTEGER NOT NULL DEFAULT 1,
+    stop_processing INTEGER NOT NULL DEFAULT 0,
+
+    -- Conditions. A rule matches a message when every condition that is
+    -- set matches. subject_pattern is a regular expression, and
+    -- metadata_key matches messages whose metadata has the key, with
+    -- metadata_value if that is set too.
+    sender_name TEXT NOT NULL DEFAULT '',
+    sender_prefix TEXT NOT NULL DEFAULT '',
+    subject_pattern TEXT NOT NULL DEFAULT '',
+    topic_name TEXT NOT NULL DEFAULT '',
+    priority TEXT NOT NULL DEFAULT '',
+    metadata_key TEXT NOT NULL DEFAULT '',
+    metadata_value TEXT NOT NULL DEFAULT '',
+
+    -- Actions. snooze is a duration or a time of day, category overrides
+    -- the inbox category derived from the subject, and forward_to names an
+    -- agent the message is also delivered to.
+    archive INTEGER NOT NULL DEFAULT 0,
+    star INTEGER NOT NULL DEFAULT 0,
+    snooze TEXT NOT NULL DEFAULT '',
+    category TEXT NOT NULL DEFAULT ''
+        CHECK (category IN ('', 'primary', 'notifications')),
+    forward_to TEXT NOT NULL DEFAULT '',
+
+    match_count INTEGER NOT NULL DEFAULT 0,
+    last_matched_at INTEGER,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+
+    UNIQUE (agent_id, name)
+);
+
+-- The inbox category a rule filed a message under for a recipient. Empty
+-- means the category is derived from the subject, as before.
+ALTER TABLE message_recipients ADD COLUMN category TEXT NOT NULL DEFAULT '';
+
+-- Metadata of a scheduled message, a JSON object copied to the message when
+-- it is sent.
+ALTER TABLE scheduled_messages ADD COLUMN metadata TEXT NOT NULL DEFAULT '';
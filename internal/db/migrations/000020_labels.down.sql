@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS message_labels;
+DROP TABLE IF EXISTS labels;
+
+-- Note: SQLite doesn't support DROP COLUMN directly before 3.35.0.
+ALTER TABLE inbox_rules DROP COLUMN label;
@@ -0,0 +1,31 @@
+-- Labels are user-defined inbox categories an agent files its messages
+-- under, on top of the built-in Primary and Notifications categories. A
+-- message can carry several labels per recipient, applied by hand or by an
+-- inbox rule.
+CREATE TABLE labels (
+    id INTEGER PRIMARY KEY,
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+
+    UNIQUE (agent_id, name)
+);
+
+-- The labels a recipient applied to a message. Rows go away with the
+-- label or with the recipient's copy of the message.
+CREATE TABLE message_labels (
+    message_id INTEGER NOT NULL,
+    agent_id INTEGER NOT NULL,
+    label_id INTEGER NOT NULL REFERENCES labels(id) ON DELETE CASCADE,
+    created_at INTEGER NOT NULL,
+
+    PRIMARY KEY (message_id, agent_id, label_id),
+    FOREIGN KEY (message_id, agent_id)
+        REFERENCES message_recipients(message_id, agent_id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_message_labels_label ON message_labels(label_id);
+
+-- The label an inbox rule applies to the messages it matches, if any.
+ALTER TABLE inbox_rules ADD COLUMN label TEXT NOT NULL DEFAULT '';
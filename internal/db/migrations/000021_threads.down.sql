@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_threads_agent;
+DROP TABLE IF EXISTS threads;
@@ -0,0 +1,18 @@
+-- Threads hold each agent's preferences for a conversation, keyed by the
+-- thread_id its messages share. A muted thread's new replies skip the
+-- unread state and aren't pushed to the agent; a followed thread's replies
+-- are delivered to the agent even when it isn't addressed. Agents with no
+-- preference for a thread have no row.
+CREATE TABLE threads (
+    thread_id TEXT NOT NULL,
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    muted INTEGER NOT NULL DEFAULT 0,
+    following INTEGER NOT NULL DEFAULT 0,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+
+    PRIMARY KEY (thread_id, agent_id),
+    CHECK (NOT (muted AND following))
+);
+
+CREATE INDEX idx_threads_agent ON threads(agent_id, updated_at);
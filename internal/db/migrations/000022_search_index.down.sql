@@ -0,0 +1,24 @@
+DROP TRIGGER IF EXISTS diff_annotations_au;
+DROP TRIGGER IF EXISTS diff_annotations_ad;
+DROP TRIGGER IF EXISTS diff_annotations_ai;
+DROP TABLE IF EXISTS diff_annotations_fts;
+
+DROP TRIGGER IF EXISTS plan_annotations_au;
+DROP TRIGGER IF EXISTS plan_annotations_ad;
+DROP TRIGGER IF EXISTS plan_annotations_ai;
+DROP TABLE IF EXISTS plan_annotations_fts;
+
+DROP TRIGGER IF EXISTS agent_tasks_au;
+DROP TRIGGER IF EXISTS agent_tasks_ad;
+DROP TRIGGER IF EXISTS agent_tasks_ai;
+DROP TABLE IF EXISTS agent_tasks_fts;
+
+DROP TRIGGER IF EXISTS plan_reviews_au;
+DROP TRIGGER IF EXISTS plan_reviews_ad;
+DROP TRIGGER IF EXISTS plan_reviews_ai;
+DROP TABLE IF EXISTS plan_reviews_fts;
+
+DROP TRIGGER IF EXISTS review_issues_au;
+DROP TRIGGER IF EXISTS review_issues_ad;
+DROP TRIGGER IF EXISTS review_issues_ai;
+DROP TABLE IF EXISTS review_issues_fts;
@@ -0,0 +1,115 @@
+-- Full-text indexes over the other entities substrate search reaches: review
+-- issues, plan reviews, agent tasks, and plan and diff annotations. Like
+-- messages_fts, each is an external content table kept in sync by triggers,
+-- and rebuilt here to index the rows that already exist.
+CREATE VIRTUAL TABLE IF NOT EXISTS review_issues_fts USING fts5(
+    title,
+    description,
+    content=review_issues,
+    content_rowid=id
+);
+
+CREATE TRIGGER IF NOT EXISTS review_issues_ai AFTER INSERT ON review_issues BEGIN
+    INSERT INTO review_issues_fts(rowid, title, description) VALUES (new.id, new.title, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS review_issues_ad AFTER DELETE ON review_issues BEGIN
+    INSERT INTO review_issues_fts(review_issues_fts, rowid, title, description) VALUES('delete', old.id, old.title, old.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS review_issues_au AFTER UPDATE OF title, description ON review_issues BEGIN
+    INSERT INTO review_issues_fts(review_issues_fts, rowid, title, description) VALUES('delete', old.id, old.title, old.description);
+    INSERT INTO review_issues_fts(rowid, title, description) VALUES (new.id, new.title, new.description);
+END;
+
+INSERT INTO review_issues_fts(review_issues_fts) VALUES('rebuild');
+
+CREATE VIRTUAL TABLE IF NOT EXISTS plan_reviews_fts USING fts5(
+    plan_title,
+    plan_summary,
+    content=plan_reviews,
+    content_rowid=id
+);
+
+CREATE TRIGGER IF NOT EXISTS plan_reviews_ai AFTER INSERT ON plan_reviews BEGIN
+    INSERT INTO plan_reviews_fts(rowid, plan_title, plan_summary) VALUES (new.id, new.plan_title, new.plan_summary);
+END;
+
+CREATE TRIGGER IF NOT EXISTS plan_reviews_ad AFTER DELETE ON plan_reviews BEGIN
+    INSERT INTO plan_reviews_fts(plan_reviews_fts, rowid, plan_title, plan_summary) VALUES('delete', old.id, old.plan_title, old.plan_summary);
+END;
+
+CREATE TRIGGER IF NOT EXISTS plan_reviews_au AFTER UPDATE OF plan_title, plan_summary ON plan_reviews BEGIN
+    INSERT INTO plan_reviews_fts(plan_reviews_fts, rowid, plan_title, plan_summary) VALUES('delete', old.id, old.plan_title, old.plan_summary);
+    INSERT INTO plan_reviews_fts(rowid, plan_title, plan_summary) VALUES (new.id, new.plan_title, new.plan_summary);
+END;
+
+INSERT INTO plan_reviews_fts(plan_reviews_fts) VALUES('rebuild');
+
+CREATE VIRTUAL TABLE IF NOT EXISTS agent_tasks_fts USING fts5(
+    subject,
+    description,
+    content=agent_tasks,
+    content_rowid=id
+);
+
+CREATE TRIGGER IF NOT EXISTS agent_tasks_ai AFTER INSERT ON agent_tasks BEGIN
+    INSERT INTO agent_tasks_fts(rowid, subject, description) VALUES (new.id, new.subject, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS agent_tasks_ad AFTER DELETE ON agent_tasks BEGIN
+    INSERT INTO agent_tasks_fts(agent_tasks_fts, rowid, subject, description) VALUES('delete', old.id, old.subject, old.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS agent_tasks_au AFTER UPDATE OF subject, description ON agent_tasks BEGIN
+    INSERT INTO agent_tasks_fts(agent_tasks_fts, rowid, subject, description) VALUES('delete', old.id, old.subject, old.description);
+    INSERT INTO agent_tasks_fts(rowid, subject, description) VALUES (new.id, new.subject, new.description);
+END;
+
+INSERT INTO agent_tasks_fts(agent_tasks_fts) VALUES('rebuild');
+
+-- Plan annotations index the reviewer's note and the plan text it's on.
+CREATE VIRTUAL TABLE IF NOT EXISTS plan_annotations_fts USING fts5(
+    text,
+    original_text,
+    content=plan_annotations,
+    content_rowid=id
+);
+
+CREATE TRIGGER IF NOT EXISTS plan_annotations_ai AFTER INSERT ON plan_annotations BEGIN
+    INSERT INTO plan_annotations_fts(rowid, text, original_text) VALUES (new.id, new.text, new.original_text);
+END;
+
+CREATE TRIGGER IF NOT EXISTS plan_annotations_ad AFTER DELETE ON plan_annotations BEGIN
+    INSERT INTO plan_annotations_fts(plan_annotations_fts, rowid, text, original_text) VALUES('delete', old.id, old.text, old.original_text);
+END;
+
+CREATE TRIGGER IF NOT EXISTS plan_annotations_au AFTER UPDATE OF text, original_text ON plan_annotations BEGIN
+    INSERT INTO plan_annotations_fts(plan_annotations_fts, rowid, text, original_text) VALUES('delete', old.id, old.text, old.original_text);
+    INSERT INTO plan_annotations_fts(rowid, text, original_text) VALUES (new.id, new.text, new.original_text);
+END;
+
+INSERT INTO plan_annotations_fts(plan_annotations_fts) VALUES('rebuild');
+
+-- Diff annotations index the note and the file it's on.
+CREATE VIRTUAL TABLE IF NOT EXISTS diff_annotations_fts USING fts5(
+    text,
+    file_path,
+    content=diff_annotations,
+    content_rowid=id
+);
+
+CREATE TRIGGER IF NOT EXISTS diff_annotations_ai AFTER INSERT ON diff_annotations BEGIN
+    INSERT INTO diff_annotations_fts(rowid, text, file_path) VALUES (new.id, new.text, new.file_path);
+END;
+
+CREATE TRIGGER IF NOT EXISTS diff_annotations_ad AFTER DELETE ON diff_annotations BEGIN
+    INSERT INTO diff_annotations_fts(diff_annotations_fts, rowid, text, file_path) VALUES('delete', old.id, old.text, old.file_path);
+END;
+
+CREATE TRIGGER IF NOT EXISTS diff_annotations_au AFTER UPDATE OF text, file_path ON diff_annotations BEGIN
+    INSERT INTO diff_annotations_fts(diff_annotations_fts, rowid, text, file_path) VALUES('delete', old.id, old.text, old.file_path);
+    INSERT INTO diff_annotations_fts(rowid, text, file_path) VALUES (new.id, new.text, new.file_path);
+END;
+
+INSERT INTO diff_annotations_fts(diff_annotations_fts) VALUES('rebuild');
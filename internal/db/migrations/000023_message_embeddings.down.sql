@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS message_embeddings_au;
+DROP INDEX IF EXISTS idx_message_embeddings_model;
+DROP TABLE IF EXISTS message_embeddings;
@@ -0,0 +1,23 @@
+-- Vector embeddings of messages for semantic search, one per message and
+-- embedding model, since vectors from different models can't be compared.
+-- They're filled in asynchronously after a message is sent, and dropped
+-- whenever its subject or body changes so that it's embedded again.
+CREATE TABLE IF NOT EXISTS message_embeddings (
+    message_id INTEGER NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    model TEXT NOT NULL,
+
+    -- The vector as little-endian float32s, dimensions long.
+    dimensions INTEGER NOT NULL,
+    embedding BLOB NOT NULL,
+
+    created_at INTEGER NOT NULL,
+
+    PRIMARY KEY (message_id, model)
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_embeddings_model
+    ON message_embeddings(model, message_id);
+
+CREATE TRIGGER IF NOT EXISTS message_embeddings_au AFTER UPDATE OF subject, body_md ON messages BEGIN
+    DELETE FROM message_embeddings WHERE message_id = new.id;
+END;
@@ -0,0 +1,13 @@
+-- Saved searches are search queries an agent stored under a name, to rerun
+-- when triaging or to filter what wakes its watcher. The query is stored as
+-- written, so relative dates such as after:1d are resolved on every run.
+CREATE TABLE saved_searches (
+    id INTEGER PRIMARY KEY,
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    query TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+
+    UNIQUE (agent_id, name)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS capability_routes;
+ALTER TABLE agents DROP COLUMN max_concurrency;
+DROP TABLE IF EXISTS agent_capabilities;
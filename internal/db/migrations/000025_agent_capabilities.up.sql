@@ -0,0 +1,35 @@
+-- Agent capabilities are what an agent declared it can do: the languages it
+-- works in, the repos it knows, the tools it has and free-form skills such
+-- as can-review-security. Mail and tasks addressed to cap:<name> are routed
+-- to one online agent with the capability.
+CREATE TABLE agent_capabilities (
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL, -- language, repo, tool or skill
+    name TEXT NOT NULL,
+
+    PRIMARY KEY (agent_id, kind, name)
+);
+
+CREATE INDEX idx_agent_capabilities_name ON agent_capabilities(name, kind);
+
+-- The most work an agent takes on at once. Agents with this many tasks in
+-- progress aren't routed more, and 0 means no limit.
+ALTER TABLE agents ADD COLUMN max_concurrency INTEGER NOT NULL DEFAULT 0;
+
+-- Capability routes record which agent the router picked for a message or
+-- task addressed to a capability, and the load it was picked on.
+CREATE TABLE capability_routes (
+    id INTEGER PRIMARY KEY,
+    capability TEXT NOT NULL, -- the address without its cap: prefix
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    message_id INTEGER REFERENCES messages(id) ON DELETE CASCADE,
+    task_id INTEGER REFERENCES agent_tasks(id) ON DELETE CASCADE,
+    candidates INTEGER NOT NULL, -- the online agents the router chose from
+    unread_count INTEGER NOT NULL,
+    in_progress_count INTEGER NOT NULL,
+    created_at INTEGER NOT NULL
+);
+
+CREATE INDEX idx_capability_routes_capability
+    ON capability_routes(capability, created_at);
+CREATE INDEX idx_capability_routes_task ON capability_routes(task_id);
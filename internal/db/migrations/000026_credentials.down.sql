@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_credentials_agent;
+DROP TABLE IF EXISTS credentials;
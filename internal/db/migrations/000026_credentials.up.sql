@@ -0,0 +1,21 @@
+-- Credentials are the bearer tokens clients authenticate to the daemon
+-- with. Only a hash of each token is stored, so a leaked database doesn't
+-- leak working tokens. An agent credential acts as its agent, while an
+-- admin credential (agent_id NULL) may act as any agent.
+CREATE TABLE credentials (
+    id INTEGER PRIMARY KEY,
+    agent_id INTEGER REFERENCES agents(id) ON DELETE CASCADE,
+    name TEXT NOT NULL DEFAULT '',
+    token_hash TEXT NOT NULL UNIQUE,
+
+    -- allowed_methods is a comma-separated list of the RPCs the
+    -- credential may call, empty for all of them.
+    allowed_methods TEXT NOT NULL DEFAULT '',
+
+    expires_at INTEGER,
+    created_at INTEGER NOT NULL,
+    last_used_at INTEGER,
+    revoked_at INTEGER
+);
+
+CREATE INDEX idx_credentials_agent ON credentials(agent_id);
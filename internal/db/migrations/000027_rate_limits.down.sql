@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS rate_limit_buckets;
+DROP INDEX IF EXISTS idx_agent_limits_agent;
+DROP INDEX IF EXISTS idx_agent_limits_default;
+DROP TABLE IF EXISTS agent_limits;
@@ -0,0 +1,42 @@
+-- Agent limits cap how often agents may call each class of RPC and how
+-- much message body they may store a day. A row with agent_id NULL is the
+-- default for every agent, and an agent's own row overrides it.
+CREATE TABLE agent_limits (
+    id INTEGER PRIMARY KEY,
+    agent_id INTEGER REFERENCES agents(id) ON DELETE CASCADE,
+
+    -- class is the RPC class limited (send, publish, search, review) or
+    -- body_bytes for the daily storage quota.
+    class TEXT NOT NULL,
+
+    -- amount calls are allowed every interval_seconds, up to burst at
+    -- once. For body_bytes, amount is the bytes allowed a UTC day.
+    amount INTEGER NOT NULL,
+    interval_seconds INTEGER NOT NULL,
+    burst INTEGER NOT NULL,
+
+    updated_at INTEGER NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_agent_limits_default
+    ON agent_limits(class) WHERE agent_id IS NULL;
+CREATE UNIQUE INDEX idx_agent_limits_agent
+    ON agent_limits(agent_id, class) WHERE agent_id IS NOT NULL;
+
+-- Rate limit buckets hold each agent's token bucket per RPC class. They
+-- live in the database so the daemon and direct-database clients draw
+-- from the same buckets.
+CREATE TABLE rate_limit_buckets (
+    agent_id INTEGER NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    class TEXT NOT NULL,
+    tokens REAL NOT NULL,
+
+    -- updated_at_ms is when tokens was last refilled, in unix
+    -- milliseconds.
+    updated_at_ms INTEGER NOT NULL,
+
+    throttled_count INTEGER NOT NULL DEFAULT 0,
+    last_throttled_at INTEGER,
+
+    PRIMARY KEY (agent_id, class)
+);
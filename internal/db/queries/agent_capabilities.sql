@@ -0,0 +1,67 @@
+-- name: CreateAgentCapability :exec
+INSERT INTO agent_capabilities (agent_id, kind, name)
+VALUES (?, ?, ?)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteAgentCapabilities :exec
+DELETE FROM agent_capabilities WHERE agent_id = ?;
+
+-- name: ListAgentCapabilities :many
+SELECT * FROM agent_capabilities WHERE agent_id = ? ORDER BY kind, name;
+
+-- name: ListAllAgentCapabilities :many
+SELECT * FROM agent_capabilities ORDER BY agent_id, kind, name;
+
+-- name: UpdateAgentMaxConcurrency :exec
+UPDATE agents SET max_concurrency = ? WHERE id = ?;
+
+-- name: ListCapabilityCandidates :many
+-- List the agents with a capability, of any kind when kind is empty, along
+-- with the unread mail and in-progress tasks the router weighs them by. A
+-- task counts against its owner, or the agent whose list it is on when it
+-- has none.
+SELECT
+    a.id, a.name, a.project_key, a.git_branch,
+    a.current_session_id, a.created_at, a.last_active_at,
+    a.purpose, a.working_dir, a.hostname, a.max_concurrency,
+    CAST((
+        SELECT COUNT(*) FROM message_recipients mr
+        WHERE mr.agent_id = a.id AND mr.state = 'unread'
+    ) AS INTEGER) AS unread_count,
+    CAST((
+        SELECT COUNT(*) FROM agent_tasks t
+        WHERE t.status = 'in_progress' AND (
+            t.owner = a.name OR
+            (COALESCE(t.owner, '') = '' AND t.agent_id = a.id)
+        )
+    ) AS INTEGER) AS in_progress_count
+FROM agents a
+WHERE a.id IN (
+    SELECT c.agent_id FROM agent_capabilities c
+    WHERE c.name = @name AND (CAST(@kind AS TEXT) = '' OR c.kind = @kind)
+)
+ORDER BY a.id;
+
+-- name: CreateCapabilityRoute :one
+INSERT INTO capability_routes (
+    capability, agent_id, message_id, task_id, candidates,
+    unread_count, in_progress_count, created_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListCapabilityRoutes :many
+-- List the newest routes, of one capability when capability is empty.
+SELECT r.*, a.name AS agent_name
+FROM capability_routes r
+JOIN agents a ON a.id = r.agent_id
+WHERE CAST(@capability AS TEXT) = '' OR r.capability = @capability
+ORDER BY r.id DESC
+LIMIT @max_results;
+
+-- name: GetLatestTaskRoute :one
+SELECT r.*, a.name AS agent_name
+FROM capability_routes r
+JOIN agents a ON a.id = r.agent_id
+WHERE r.task_id = ?
+ORDER BY r.id DESC
+LIMIT 1;
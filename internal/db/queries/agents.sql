@@ -32,6 +32,9 @@ UPDATE agents SET current_session_id = ?, last_active_at = ? WHERE id = ?;
 -- name: UpdateAgentGitBranch :exec
 UPDATE agents SET git_branch = ?, project_key = ?, last_active_at = ? WHERE id = ?;
 
+-- name: UpdateAgentProject :exec
+UPDATE agents SET project_key = ?, git_branch = ? WHERE id = ?;
+
 -- name: SearchAgents :many
 SELECT * FROM agents
 WHERE name LIKE '%' || @query || '%'
@@ -0,0 +1,33 @@
+-- name: CreateCredential :one
+INSERT INTO credentials (
+    agent_id, name, token_hash, allowed_methods, expires_at, created_at
+) VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetCredentialByHash :one
+-- Look up a credential by the hash of its token, with its agent's name.
+SELECT
+    c.*,
+    CAST(COALESCE(a.name, '') AS TEXT) AS agent_name
+FROM credentials c
+LEFT JOIN agents a ON a.id = c.agent_id
+WHERE c.token_hash = ?;
+
+-- name: ListCredentials :many
+-- List credentials, newest first, optionally only an agent's and
+-- optionally including revoked ones.
+SELECT
+    c.*,
+    CAST(COALESCE(a.name, '') AS TEXT) AS agent_name
+FROM credentials c
+LEFT JOIN agents a ON a.id = c.agent_id
+WHERE (CAST(@agent_id AS INTEGER) = 0 OR c.agent_id = @agent_id)
+  AND (CAST(@include_revoked AS INTEGER) = 1 OR c.revoked_at IS NULL)
+ORDER BY c.created_at DESC, c.id DESC;
+
+-- name: RevokeCredential :execrows
+UPDATE credentials SET revoked_at = ?
+WHERE id = ? AND revoked_at IS NULL;
+
+-- name: TouchCredential :exec
+UPDATE credentials SET last_used_at = ? WHERE id = ?;
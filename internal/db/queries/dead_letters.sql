@@ -0,0 +1,18 @@
+-- name: CreateDeadLetter :exec
+INSERT INTO dead_letters (
+    message_id, sender_id, recipient, status, reason, created_at
+) VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ListDeadLetters :many
+-- List a sender's dead letters, newest first, along with the message they
+-- belong to.
+SELECT d.id, d.message_id, d.sender_id, d.recipient, d.status, d.reason,
+    d.created_at, m.thread_id, m.subject
+FROM dead_letters d
+JOIN messages m ON m.id = d.message_id
+WHERE d.sender_id = ?
+ORDER BY d.created_at DESC, d.id DESC
+LIMIT ?;
+
+-- name: DeleteDeadLetter :execrows
+DELETE FROM dead_letters WHERE id = ? AND sender_id = ?;
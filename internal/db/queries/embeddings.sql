@@ -0,0 +1,20 @@
+-- name: UpsertMessageEmbedding :exec
+-- Store a message's embedding under a model, replacing any it had.
+INSERT INTO message_embeddings (
+    message_id, model, dimensions, embedding, created_at
+) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (message_id, model) DO UPDATE SET
+    dimensions = excluded.dimensions,
+    embedding = excluded.embedding,
+    created_at = excluded.created_at;
+
+-- name: GetMessageEmbedding :one
+SELECT * FROM message_embeddings WHERE message_id = ? AND model = ?;
+
+-- name: ListMessagesToEmbed :many
+-- List the oldest messages without an embedding under a model.
+SELECT m.* FROM messages m
+LEFT JOIN message_embeddings e ON e.message_id = m.id AND e.model = ?
+WHERE e.message_id IS NULL
+ORDER BY m.id ASC
+LIMIT ?;
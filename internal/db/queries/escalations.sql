@@ -0,0 +1,33 @@
+-- name: ListOverdueRecipients :many
+-- List recipients of unacked messages whose next escalation level is due,
+-- most overdue first. level_delays is a JSON array of each level's delay
+-- past the deadline in seconds, so recipients still waiting on their next
+-- level don't crowd newly overdue ones out of the batch.
+SELECT mr.message_id, mr.agent_id, mr.state, m.thread_id, m.sender_id,
+    m.subject, m.priority, m.deadline_at,
+    CAST(COALESCE(e.level, 0) AS INTEGER) AS escalation_level
+FROM message_recipients mr
+JOIN messages m ON mr.message_id = m.id
+LEFT JOIN message_escalations e
+    ON e.message_id = mr.message_id AND e.agent_id = mr.agent_id
+WHERE m.deadline_at IS NOT NULL
+    AND m.deadline_at <= @now
+    AND mr.acked_at IS NULL
+    AND mr.state NOT IN ('archived', 'trash')
+    AND COALESCE(e.level, 0) < json_array_length(CAST(@level_delays AS TEXT))
+    AND m.deadline_at + json_extract(
+        CAST(@level_delays AS TEXT), '$[' || COALESCE(e.level, 0) || ']'
+    ) <= @now
+ORDER BY m.deadline_at ASC
+LIMIT @max_results;
+
+-- name: UpsertMessageEscalation :exec
+INSERT INTO message_escalations (message_id, agent_id, level, last_escalated_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (message_id, agent_id) DO UPDATE SET
+    level = excluded.level,
+    last_escalated_at = excluded.last_escalated_at;
+
+-- name: GetMessageEscalation :one
+SELECT * FROM message_escalations
+WHERE message_id = ? AND agent_id = ?;
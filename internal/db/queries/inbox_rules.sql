@@ -0,0 +1,48 @@
+-- name: CreateInboxRule :one
+INSERT INTO inbox_rules (
+    agent_id, name, enabled, stop_processing, sender_name, sender_prefix,
+    subject_pattern, topic_name, priority, metadata_key, metadata_value,
+    archive, star, snooze, category, forward_to, label, created_at,
+    updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetInboxRuleByName :one
+SELECT * FROM inbox_rules WHERE agent_id = ? AND name = ?;
+
+-- name: ListInboxRules :many
+-- List an agent's rules in the order they are applied.
+SELECT * FROM inbox_rules
+WHERE agent_id = ?
+ORDER BY id;
+
+-- name: DeleteInboxRule :execrows
+DELETE FROM inbox_rules WHERE agent_id = ? AND name = ?;
+
+-- name: SetInboxRuleEnabled :execrows
+UPDATE inbox_rules
+SET enabled = ?, updated_at = ?
+WHERE agent_id = ? AND name = ?;
+
+-- name: RecordInboxRuleMatch :exec
+UPDATE inbox_rules
+SET match_count = match_count + 1, last_matched_at = ?
+WHERE id = ?;
+
+-- name: SetRecipientCategory :exec
+-- File a message under an inbox category for one recipient.
+UPDATE message_recipients
+SET category = ?
+WHERE message_id = ? AND agent_id = ?;
+
+-- name: ListReceivedMessages :many
+-- An agent's most recently received messages in any state but trash, for
+-- trying inbox rules against.
+SELECT m.*, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
+FROM messages m
+JOIN message_recipients mr ON m.id = mr.message_id
+LEFT JOIN agents a ON m.sender_id = a.id
+WHERE mr.agent_id = ?
+    AND mr.state != 'trash'
+ORDER BY m.created_at DESC, m.id DESC
+LIMIT ?;
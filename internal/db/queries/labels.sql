@@ -0,0 +1,65 @@
+-- name: CreateLabel :one
+INSERT INTO labels (agent_id, name, created_at)
+VALUES (?, ?, ?)
+RETURNING *;
+
+-- name: GetLabelByName :one
+SELECT * FROM labels WHERE agent_id = ? AND name = ?;
+
+-- name: ListLabels :many
+-- List an agent's labels by name, with how many of the messages carrying
+-- each are in the agent's inbox and how many of those are unread.
+SELECT l.*,
+    CAST(COALESCE(SUM(CASE
+        WHEN mr.state NOT IN ('archived', 'trash')
+        THEN 1 ELSE 0 END), 0) AS INTEGER) AS message_count,
+    CAST(COALESCE(SUM(CASE
+        WHEN mr.state = 'unread'
+        THEN 1 ELSE 0 END), 0) AS INTEGER) AS unread_count
+FROM labels l
+LEFT JOIN message_labels ml ON ml.label_id = l.id
+LEFT JOIN message_recipients mr
+    ON mr.message_id = ml.message_id AND mr.agent_id = ml.agent_id
+WHERE l.agent_id = ?
+GROUP BY l.id
+ORDER BY l.name;
+
+-- name: DeleteLabel :execrows
+DELETE FROM labels WHERE agent_id = ? AND name = ?;
+
+-- name: AddMessageLabel :exec
+-- Apply a label to a recipient's copy of a message. Applying a label twice
+-- is a no-op.
+INSERT OR IGNORE INTO message_labels (
+    message_id, agent_id, label_id, created_at
+) VALUES (?, ?, ?, ?);
+
+-- name: RemoveMessageLabel :execrows
+DELETE FROM message_labels
+WHERE message_id = ? AND agent_id = ? AND label_id = ?;
+
+-- name: ListMessageLabels :many
+-- The labels an agent applied to each of a batch of messages, by name.
+SELECT ml.message_id, l.name
+FROM message_labels ml
+JOIN labels l ON l.id = ml.label_id
+WHERE ml.agent_id = ? AND ml.message_id IN (sqlc.slice('message_ids'))
+ORDER BY ml.message_id, l.name;
+
+-- name: GetInboxMessagesByLabel :many
+-- The messages an agent filed under a label, in any state but trash so
+-- archived messages can still be found by label.
+SELECT m.*, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at,
+    a.name as sender_name, a.project_key as sender_project_key,
+    a.git_branch as sender_git_branch
+FROM messages m
+JOIN message_recipients mr ON m.id = mr.message_id
+JOIN message_labels ml
+    ON ml.message_id = mr.message_id AND ml.agent_id = mr.agent_id
+JOIN labels l ON l.id = ml.label_id
+LEFT JOIN agents a ON m.sender_id = a.id
+WHERE mr.agent_id = ?
+    AND l.name = ?
+    AND mr.state != 'trash'
+ORDER BY m.created_at DESC, m.id DESC
+LIMIT ? OFFSET ?;
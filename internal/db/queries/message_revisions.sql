@@ -0,0 +1,42 @@
+-- name: CreateMessageRevision :one
+INSERT INTO message_revisions (
+    message_id, revision, subject, body_md, created_at, replaced_at
+) VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListMessageRevisions :many
+SELECT * FROM message_revisions
+WHERE message_id = ?
+ORDER BY revision ASC;
+
+-- name: ListThreadMessageRevisions :many
+-- Get the revisions of every edited message in a thread.
+SELECT r.* FROM message_revisions r
+JOIN messages m ON r.message_id = m.id
+WHERE m.thread_id = ?
+ORDER BY r.message_id ASC, r.revision ASC;
+
+-- name: UpdateMessageContent :execrows
+-- Replace a message's subject and body with an edited version. Messages
+-- under a legal hold can't be edited. The messages_au trigger re-indexes
+-- the message for search.
+UPDATE messages
+SET subject = ?, body_md = ?, edited_at = ?
+WHERE id = ? AND legal_hold = 0;
+
+-- name: RecallMessage :execrows
+-- Delete a message its sender wants back, as long as no recipient has read
+-- or otherwise acted on it yet. Messages under a legal hold or backing a
+-- plan review are kept.
+DELETE FROM messages
+WHERE messages.id = sqlc.arg(id)
+    AND messages.sender_id = sqlc.arg(sender_id)
+    AND messages.legal_hold = 0
+    AND NOT EXISTS (
+        SELECT 1 FROM message_recipients mr
+        WHERE mr.message_id = messages.id
+            AND (mr.state != 'unread' OR mr.read_at IS NOT NULL)
+    )
+    AND NOT EXISTS (
+        SELECT 1 FROM plan_reviews pr WHERE pr.message_id = messages.id
+    );
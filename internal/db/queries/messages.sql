@@ -1,8 +1,9 @@
 -- name: CreateMessage :one
 INSERT INTO messages (
     thread_id, topic_id, log_offset, sender_id, subject, body_md,
-    priority, deadline_at, attachments, created_at, idempotency_key
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    priority, deadline_at, attachments, metadata, created_at,
+    idempotency_key
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetMessage :one
@@ -121,8 +122,8 @@ LIMIT ? OFFSET ?;
 
 -- name: GetInboxMessagesPrimary :many
 -- Primary inbox view: every non-archived, non-trashed message that
--- isn't hook-generated chatter. Used by the Primary tab as the
--- default "real inbox" view.
+-- isn't hook-generated chatter, unless an inbox rule filed it elsewhere.
+-- Used by the Primary tab as the default "real inbox" view.
 SELECT m.*, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at,
     a.name as sender_name, a.project_key as sender_project_key,
     a.git_branch as sender_git_branch
@@ -131,16 +132,19 @@ JOIN message_recipients mr ON m.id = mr.message_id
 LEFT JOIN agents a ON m.sender_id = a.id
 WHERE mr.agent_id = ?
     AND mr.state NOT IN ('archived', 'trash')
-    AND m.subject NOT LIKE '[Permission]%'
-    AND m.subject NOT LIKE '[Notification]%'
-    AND m.subject NOT LIKE '[Idle]%'
-    AND m.subject NOT LIKE '[Status]%'
+    AND (mr.category = 'primary'
+         OR (mr.category = ''
+             AND m.subject NOT LIKE '[Permission]%'
+             AND m.subject NOT LIKE '[Notification]%'
+             AND m.subject NOT LIKE '[Idle]%'
+             AND m.subject NOT LIKE '[Status]%'))
 ORDER BY m.created_at DESC
 LIMIT ? OFFSET ?;
 
 -- name: GetInboxMessagesNotifications :many
 -- Hook-generated notification messages (Permission/Idle/Status/
--- Notification subjects). Used by the Notifications tab in the web UI.
+-- Notification subjects) and messages an inbox rule filed as
+-- notifications. Used by the Notifications tab in the web UI.
 SELECT m.*, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at,
     a.name as sender_name, a.project_key as sender_project_key,
     a.git_branch as sender_git_branch
@@ -149,36 +153,42 @@ JOIN message_recipients mr ON m.id = mr.message_id
 LEFT JOIN agents a ON m.sender_id = a.id
 WHERE mr.agent_id = ?
     AND mr.state NOT IN ('archived', 'trash')
-    AND (m.subject LIKE '[Permission]%'
-         OR m.subject LIKE '[Notification]%'
-         OR m.subject LIKE '[Idle]%'
-         OR m.subject LIKE '[Status]%')
+    AND (mr.category = 'notifications'
+         OR (mr.category = ''
+             AND (m.subject LIKE '[Permission]%'
+                  OR m.subject LIKE '[Notification]%'
+                  OR m.subject LIKE '[Idle]%'
+                  OR m.subject LIKE '[Status]%')))
 ORDER BY m.created_at DESC
 LIMIT ? OFFSET ?;
 
 -- name: CountInboxCategories :one
 -- Returns total counts per inbox category for an agent, excluding
 -- archived and trashed messages. Drives tab labels and stats in the
--- web UI without forcing a second round trip.
+-- web UI without forcing a second round trip. A category set by an
+-- inbox rule takes precedence over the one derived from the subject.
 SELECT
     CAST(COALESCE(SUM(CASE
-        WHEN m.subject NOT LIKE '[Permission]%'
+        WHEN mr.category = 'primary' OR (mr.category = ''
+            AND m.subject NOT LIKE '[Permission]%'
             AND m.subject NOT LIKE '[Notification]%'
             AND m.subject NOT LIKE '[Idle]%'
-            AND m.subject NOT LIKE '[Status]%'
+            AND m.subject NOT LIKE '[Status]%')
         THEN 1 ELSE 0 END), 0) AS INTEGER) AS primary_count,
     CAST(COALESCE(SUM(CASE
-        WHEN m.subject LIKE '[Permission]%'
-            OR m.subject LIKE '[Notification]%'
-            OR m.subject LIKE '[Idle]%'
-            OR m.subject LIKE '[Status]%'
+        WHEN mr.category = 'notifications' OR (mr.category = ''
+            AND (m.subject LIKE '[Permission]%'
+                OR m.subject LIKE '[Notification]%'
+                OR m.subject LIKE '[Idle]%'
+                OR m.subject LIKE '[Status]%'))
         THEN 1 ELSE 0 END), 0) AS INTEGER) AS notifications_count,
     CAST(COALESCE(SUM(CASE
-        WHEN mr.state = 'unread'
-            AND m.subject NOT LIKE '[Permission]%'
-            AND m.subject NOT LIKE '[Notification]%'
-            AND m.subject NOT LIKE '[Idle]%'
-            AND m.subject NOT LIKE '[Status]%'
+        WHEN mr.state = 'unread' AND (mr.category = 'primary'
+            OR (mr.category = ''
+                AND m.subject NOT LIKE '[Permission]%'
+                AND m.subject NOT LIKE '[Notification]%'
+                AND m.subject NOT LIKE '[Idle]%'
+                AND m.subject NOT LIKE '[Status]%'))
         THEN 1 ELSE 0 END), 0) AS INTEGER) AS unread_count,
     CAST(COALESCE(SUM(CASE
         WHEN mr.state = 'starred'
@@ -219,6 +229,43 @@ UPDATE message_recipients
 SET state = 'unread', snoozed_until = NULL
 WHERE state = 'snoozed' AND snoozed_until <= ?;
 
+-- name: ListSnoozedRecipients :many
+-- List every snoozed recipient that has a pending wake time along with the
+-- message's thread. Used on daemon startup to re-arm wake timers.
+SELECT mr.message_id, mr.agent_id, mr.snoozed_until, mr.read_at,
+    mr.acked_at, m.thread_id
+FROM message_recipients mr
+JOIN messages m ON mr.message_id = m.id
+WHERE mr.state = 'snoozed'
+    AND mr.snoozed_until IS NOT NULL
+ORDER BY mr.snoozed_until ASC;
+
+-- name: PersistRecipientState :exec
+-- Persist a recipient state transition emitted by the thread FSM. All
+-- timestamp columns are written so snoozed_until is cleared whenever the
+-- recipient leaves the snoozed state.
+UPDATE message_recipients
+SET state = ?, read_at = ?, acked_at = ?, snoozed_until = ?
+WHERE message_id = ? AND agent_id = ?;
+
+-- name: GetOverdueMessages :many
+-- Inbox view of unacked messages whose deadline has passed, most overdue
+-- first. An agent_id of 0 returns overdue messages across all agents.
+SELECT m.*, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
+FROM messages m
+JOIN message_recipients mr ON m.id = mr.message_id
+LEFT JOIN agents a ON m.sender_id = a.id
+WHERE (mr.agent_id = @agent_id OR @agent_id = 0)
+    AND mr.state NOT IN ('archived', 'trash')
+    AND mr.acked_at IS NULL
+    AND m.deadline_at IS NOT NULL
+    AND m.deadline_at <= @now
+ORDER BY m.deadline_at ASC
+LIMIT @limit OFFSET @offset;
+
+-- name: UpdateMessagePriority :exec
+UPDATE messages SET priority = ? WHERE id = ?;
+
 -- name: GetArchivedMessages :many
 SELECT m.*, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at
 FROM messages m
@@ -0,0 +1,42 @@
+-- name: CreateQuestion :one
+INSERT INTO questions (
+    question_id, message_id, thread_id, asker_id, question, options,
+    default_answer, timeout_seconds, timeout_at, fallback, created_at,
+    updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetQuestion :one
+SELECT * FROM questions WHERE question_id = ?;
+
+-- name: ListPendingQuestionsByThread :many
+-- List the questions in a thread that are still waiting for an answer.
+SELECT * FROM questions
+WHERE thread_id = ? AND state = 'pending'
+ORDER BY id;
+
+-- name: ListTimedOutQuestions :many
+-- List pending questions whose current timeout has passed, oldest first.
+SELECT * FROM questions
+WHERE state = 'pending' AND timeout_at IS NOT NULL AND timeout_at <= ?
+ORDER BY timeout_at, id
+LIMIT ?;
+
+-- name: ResolveQuestion :execrows
+-- Record the answer to a pending question. Questions that were resolved in
+-- the meantime are left alone.
+UPDATE questions
+SET state = ?,
+    answer = ?,
+    answered_by = ?,
+    answer_message_id = ?,
+    answered_at = ?,
+    updated_at = ?
+WHERE id = ? AND state = 'pending';
+
+-- name: EscalateQuestion :exec
+-- Record that a question was escalated to its fallback recipient and start
+-- its next timeout.
+UPDATE questions
+SET escalated_at = ?, timeout_at = ?, updated_at = ?
+WHERE id = ?;
@@ -0,0 +1,84 @@
+-- name: CreateQueueDelivery :exec
+INSERT INTO queue_deliveries (message_id, topic_id, created_at, updated_at)
+VALUES (?, ?, ?, ?);
+
+-- name: GetQueueDelivery :one
+SELECT * FROM queue_deliveries WHERE message_id = ?;
+
+-- name: ClaimNextQueueDelivery :one
+-- Lease the oldest claimable message on a queue topic to an agent. A
+-- message is claimable when it is pending, or when its previous lease has
+-- expired, and it still has delivery attempts left.
+UPDATE queue_deliveries
+SET state = 'leased',
+    claimed_by = @agent_id,
+    lease_expires_at = @lease_expires_at,
+    delivery_count = delivery_count + 1,
+    updated_at = @now
+WHERE message_id = (
+    SELECT qd.message_id FROM queue_deliveries qd
+    WHERE qd.topic_id = @topic_id
+        AND qd.delivery_count < @max_deliveries
+        AND (
+            qd.state = 'pending'
+            OR (qd.state = 'leased' AND qd.lease_expires_at <= @now)
+        )
+    ORDER BY qd.created_at ASC, qd.message_id ASC
+    LIMIT 1
+)
+RETURNING *;
+
+-- name: AckQueueDelivery :execrows
+-- Complete a lease. Only the current lease holder may ack, and only until
+-- the message has been released or claimed by someone else.
+UPDATE queue_deliveries
+SET state = 'acked', lease_expires_at = NULL, updated_at = ?
+WHERE message_id = ? AND claimed_by = ? AND state = 'leased';
+
+-- name: ReleaseQueueDelivery :execrows
+-- Hand a leased message back to the queue so it can be redelivered.
+UPDATE queue_deliveries
+SET state = 'pending', claimed_by = NULL, lease_expires_at = NULL,
+    last_error = ?, updated_at = ?
+WHERE message_id = ? AND claimed_by = ? AND state = 'leased';
+
+-- name: ExtendQueueLease :execrows
+UPDATE queue_deliveries
+SET lease_expires_at = ?, updated_at = ?
+WHERE message_id = ? AND claimed_by = ? AND state = 'leased';
+
+-- name: ListExpiredQueueLeases :many
+-- List leases that expired without being acked, so their holders' copies of
+-- the messages can be archived before they're redelivered.
+SELECT * FROM queue_deliveries
+WHERE state = 'leased' AND lease_expires_at <= @now
+ORDER BY created_at ASC, message_id ASC;
+
+-- name: ReleaseExpiredQueueLeases :execrows
+-- Return every expired lease that still has delivery attempts left to the
+-- pending state.
+UPDATE queue_deliveries
+SET state = 'pending', claimed_by = NULL, lease_expires_at = NULL,
+    last_error = 'lease expired', updated_at = @now
+WHERE state = 'leased'
+    AND lease_expires_at <= @now
+    AND delivery_count < @max_deliveries;
+
+-- name: ListExhaustedQueueDeliveries :many
+-- List messages that have used up their delivery attempts without being
+-- acked, along with their topic name.
+SELECT qd.*, t.name AS topic_name
+FROM queue_deliveries qd
+JOIN topics t ON qd.topic_id = t.id
+WHERE qd.delivery_count >= @max_deliveries
+    AND (
+        qd.state = 'pending'
+        OR (qd.state = 'leased' AND qd.lease_expires_at <= @now)
+    )
+ORDER BY qd.created_at ASC;
+
+-- name: MarkQueueDeliveryDeadLettered :exec
+UPDATE queue_deliveries
+SET state = 'dead_lettered', claimed_by = NULL, lease_expires_at = NULL,
+    updated_at = ?
+WHERE message_id = ?;
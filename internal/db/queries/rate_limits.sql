@@ -0,0 +1,53 @@
+-- name: GetEffectiveAgentLimit :one
+-- Get the limit that applies to an agent for a class: the agent's own if
+-- it has one, else the default.
+SELECT * FROM agent_limits
+WHERE class = @class AND (agent_id = @agent_id OR agent_id IS NULL)
+ORDER BY agent_id IS NULL
+LIMIT 1;
+
+-- name: DeleteAgentLimit :execrows
+-- Delete an agent's limit for a class, or the default if agent_id is NULL.
+DELETE FROM agent_limits WHERE agent_id IS @agent_id AND class = @class;
+
+-- name: CreateAgentLimit :one
+INSERT INTO agent_limits (
+    agent_id, class, amount, interval_seconds, burst, updated_at
+) VALUES (?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: ListAgentLimits :many
+-- List the default limits and every agent's, or only the ones that apply
+-- to an agent if agent_id is non-zero.
+SELECT
+    l.*,
+    CAST(COALESCE(a.name, '') AS TEXT) AS agent_name
+FROM agent_limits l
+LEFT JOIN agents a ON a.id = l.agent_id
+WHERE CAST(@agent_id AS INTEGER) = 0
+   OR l.agent_id IS NULL
+   OR l.agent_id = @agent_id
+ORDER BY l.agent_id IS NOT NULL, a.name, l.class;
+
+-- name: GetRateLimitBucket :one
+SELECT * FROM rate_limit_buckets WHERE agent_id = ? AND class = ?;
+
+-- name: UpsertRateLimitBucket :exec
+INSERT INTO rate_limit_buckets (
+    agent_id, class, tokens, updated_at_ms, throttled_count,
+    last_throttled_at
+) VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (agent_id, class) DO UPDATE SET
+    tokens = excluded.tokens,
+    updated_at_ms = excluded.updated_at_ms,
+    throttled_count = excluded.throttled_count,
+    last_throttled_at = excluded.last_throttled_at;
+
+-- name: ListRateLimitBuckets :many
+SELECT * FROM rate_limit_buckets WHERE agent_id = ? ORDER BY class;
+
+-- name: SumSenderBodyBytes :one
+-- Sum the size of the message bodies an agent sent since a time.
+SELECT CAST(COALESCE(SUM(LENGTH(CAST(body_md AS BLOB))), 0) AS INTEGER)
+FROM messages
+WHERE sender_id = ? AND created_at >= ?;
@@ -0,0 +1,64 @@
+-- name: CreateRecipientGroup :one
+INSERT INTO recipient_groups (name, description, created_by, created_at)
+VALUES (?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetRecipientGroupByName :one
+SELECT * FROM recipient_groups WHERE name = ?;
+
+-- name: ListRecipientGroups :many
+SELECT g.id, g.name, g.description, g.created_by, g.created_at,
+    CAST(COUNT(m.agent_id) AS INTEGER) AS member_count
+FROM recipient_groups g
+LEFT JOIN recipient_group_members m ON m.group_id = g.id
+GROUP BY g.id
+ORDER BY g.name;
+
+-- name: DeleteRecipientGroup :execrows
+DELETE FROM recipient_groups WHERE id = ?;
+
+-- name: AddRecipientGroupMember :exec
+INSERT INTO recipient_group_members (group_id, agent_id, added_at)
+VALUES (?, ?, ?)
+ON CONFLICT (group_id, agent_id) DO NOTHING;
+
+-- name: RemoveRecipientGroupMember :execrows
+DELETE FROM recipient_group_members WHERE group_id = ? AND agent_id = ?;
+
+-- name: ListRecipientGroupMembers :many
+SELECT a.* FROM agents a
+JOIN recipient_group_members m ON m.agent_id = a.id
+WHERE m.group_id = ?
+ORDER BY a.name;
+
+-- name: ListAgentsMatchingProject :many
+-- List agents whose project key is the given key, or a path ending in it,
+-- so "@project:subtrate" matches "/home/dev/src/subtrate".
+SELECT * FROM agents
+WHERE project_key = @project OR project_key LIKE '%/' || @project
+ORDER BY name;
+
+-- name: ListAgentsByBranch :many
+SELECT * FROM agents WHERE git_branch = ? ORDER BY name;
+
+-- name: ListAgentsActiveSince :many
+SELECT * FROM agents WHERE last_active_at >= ? ORDER BY name;
+
+-- name: ListAgentsByNamePattern :many
+-- List agents whose name matches a LIKE pattern. Callers escape literal
+-- wildcards with a backslash.
+SELECT * FROM agents
+WHERE name LIKE @pattern ESCAPE '\'
+ORDER BY name;
+
+-- name: CreateRecipientExpansion :exec
+INSERT INTO message_recipient_expansions (message_id, address, agent_id)
+VALUES (?, ?, ?)
+ON CONFLICT (message_id, address, agent_id) DO NOTHING;
+
+-- name: ListRecipientExpansions :many
+SELECT e.address, e.agent_id, a.name AS agent_name
+FROM message_recipient_expansions e
+JOIN agents a ON a.id = e.agent_id
+WHERE e.message_id = ?
+ORDER BY e.address, a.name;
@@ -0,0 +1,131 @@
+-- name: SummarizeExpiredMessages :many
+-- Summarize, per topic, the messages that have outlived their topic's
+-- retention window. Messages that are protected from deletion are counted
+-- as expired but not as deletable: starred messages, messages a recipient
+-- hasn't read yet, has snoozed or hasn't acked by its deadline, messages
+-- under a legal hold, messages in threads with an open review, plan review
+-- or pending question, plan review messages, and queue messages that haven't
+-- been acked or dead-lettered.
+SELECT t.id AS topic_id, t.name AS topic_name,
+    CAST(t.retention_seconds AS INTEGER) AS retention_seconds,
+    COUNT(*) AS expired_count,
+    CAST(COALESCE(SUM(1 - e.protected), 0) AS INTEGER) AS deletable_count,
+    CAST(COALESCE(SUM((1 - e.protected) * e.recipients), 0) AS INTEGER)
+        AS recipient_count
+FROM (
+    SELECT m.topic_id,
+        CASE WHEN (
+            m.legal_hold = 1
+            OR EXISTS (
+                SELECT 1 FROM message_recipients mr
+                WHERE mr.message_id = m.id AND (
+                    mr.state IN ('starred', 'unread', 'snoozed')
+                    OR (m.deadline_at IS NOT NULL AND mr.acked_at IS NULL)
+                )
+            )
+            OR EXISTS (
+                SELECT 1 FROM questions q
+                WHERE q.thread_id = m.thread_id AND q.state = 'pending'
+            )
+            OR EXISTS (
+                SELECT 1 FROM reviews r
+                WHERE r.thread_id = m.thread_id
+                    AND r.state NOT IN ('approved', 'rejected', 'cancelled')
+            )
+            OR EXISTS (
+                SELECT 1 FROM plan_reviews pr
+                WHERE pr.thread_id = m.thread_id
+                    AND pr.state IN ('pending', 'changes_requested')
+            )
+            OR EXISTS (
+                SELECT 1 FROM plan_reviews pr WHERE pr.message_id = m.id
+            )
+            OR EXISTS (
+                SELECT 1 FROM queue_deliveries qd
+                WHERE qd.message_id = m.id
+                    AND qd.state IN ('pending', 'leased')
+            )
+        ) THEN 1 ELSE 0 END AS protected,
+        (
+            SELECT COUNT(*) FROM message_recipients mr
+            WHERE mr.message_id = m.id
+        ) AS recipients
+    FROM messages m
+    JOIN topics mt ON m.topic_id = mt.id
+    WHERE mt.retention_seconds > 0
+        AND m.created_at + mt.retention_seconds < @now
+) e
+JOIN topics t ON e.topic_id = t.id
+GROUP BY t.id
+ORDER BY t.name;
+
+-- name: DeleteExpiredMessages :execrows
+-- Delete a batch of messages that have outlived their topic's retention
+-- window and aren't protected (see SummarizeExpiredMessages). Recipient
+-- rows, escalations, queue deliveries and annotations cascade, and the FTS
+-- index is kept in sync by the messages_ad trigger.
+DELETE FROM messages
+WHERE id IN (
+    SELECT m.id FROM messages m
+    JOIN topics t ON m.topic_id = t.id
+    WHERE t.retention_seconds > 0
+        AND m.created_at + t.retention_seconds < @now
+        AND m.legal_hold = 0
+        AND NOT EXISTS (
+            SELECT 1 FROM message_recipients mr
+            WHERE mr.message_id = m.id AND (
+                mr.state IN ('starred', 'unread', 'snoozed')
+                OR (m.deadline_at IS NOT NULL AND mr.acked_at IS NULL)
+            )
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM questions q
+            WHERE q.thread_id = m.thread_id AND q.state = 'pending'
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM reviews r
+            WHERE r.thread_id = m.thread_id
+                AND r.state NOT IN ('approved', 'rejected', 'cancelled')
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM plan_reviews pr
+            WHERE pr.thread_id = m.thread_id
+                AND pr.state IN ('pending', 'changes_requested')
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM plan_reviews pr WHERE pr.message_id = m.id
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM queue_deliveries qd
+            WHERE qd.message_id = m.id AND qd.state IN ('pending', 'leased')
+        )
+    ORDER BY m.id
+    LIMIT @max_results
+);
+
+-- name: CountStaleConsumerOffsets :one
+-- Count consumer offsets left behind by agents that are no longer
+-- subscribed to the topic.
+SELECT COUNT(*) FROM consumer_offsets co
+WHERE NOT EXISTS (
+    SELECT 1 FROM subscriptions s
+    WHERE s.agent_id = co.agent_id AND s.topic_id = co.topic_id
+);
+
+-- name: DeleteStaleConsumerOffsets :execrows
+DELETE FROM consumer_offsets
+WHERE NOT EXISTS (
+    SELECT 1 FROM subscriptions s
+    WHERE s.agent_id = consumer_offsets.agent_id
+        AND s.topic_id = consumer_offsets.topic_id
+);
+
+-- name: OptimizeMessagesFTS :exec
+-- Merge the FTS index segments left fragmented by bulk deletes.
+INSERT INTO messages_fts(messages_fts) VALUES ('optimize');
+
+-- name: SetMessageLegalHold :execrows
+UPDATE messages SET legal_hold = ? WHERE id = ?;
+
+-- name: SetThreadLegalHold :execrows
+UPDATE messages SET legal_hold = ? WHERE thread_id = ?;
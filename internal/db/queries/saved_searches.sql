@@ -0,0 +1,17 @@
+-- name: UpsertSavedSearch :one
+-- Save a search under a name, replacing the query of an existing one.
+INSERT INTO saved_searches (agent_id, name, query, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (agent_id, name) DO UPDATE SET
+    query = excluded.query,
+    updated_at = excluded.updated_at
+RETURNING *;
+
+-- name: GetSavedSearch :one
+SELECT * FROM saved_searches WHERE agent_id = ? AND name = ?;
+
+-- name: ListSavedSearches :many
+SELECT * FROM saved_searches WHERE agent_id = ? ORDER BY name;
+
+-- name: DeleteSavedSearch :execrows
+DELETE FROM saved_searches WHERE agent_id = ? AND name = ?;
@@ -0,0 +1,46 @@
+-- name: CreateScheduledMessage :one
+INSERT INTO scheduled_messages (
+    sender_id, recipients, topic_name, thread_id, subject, body, priority,
+    deadline_at, attachments, metadata, delivery_mode, idempotency_key,
+    send_at, created_at, updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetScheduledMessageByIdempotencyKey :one
+SELECT * FROM scheduled_messages WHERE idempotency_key = ?;
+
+-- name: ListScheduledMessages :many
+-- List a sender's scheduled messages that have not been sent or canceled,
+-- soonest first.
+SELECT * FROM scheduled_messages
+WHERE sender_id = ? AND state IN ('pending', 'failed')
+ORDER BY send_at, id
+LIMIT ?;
+
+-- name: ListDueScheduledMessages :many
+-- List pending scheduled messages whose send time has passed, oldest first.
+SELECT * FROM scheduled_messages
+WHERE state = 'pending' AND send_at <= ?
+ORDER BY send_at, id
+LIMIT ?;
+
+-- name: CancelScheduledMessage :execrows
+UPDATE scheduled_messages
+SET state = 'canceled', updated_at = ?
+WHERE id = ? AND sender_id = ? AND state IN ('pending', 'failed');
+
+-- name: MarkScheduledMessageSent :exec
+UPDATE scheduled_messages
+SET state = 'sent', message_id = ?, last_error = '', updated_at = ?
+WHERE id = ?;
+
+-- name: MarkScheduledMessageFailed :exec
+UPDATE scheduled_messages
+SET state = 'failed', last_error = ?, updated_at = ?
+WHERE id = ? AND state = 'pending';
+
+-- name: DeferScheduledMessage :exec
+-- Push a pending scheduled message's send time back, recording why.
+UPDATE scheduled_messages
+SET send_at = ?, last_error = ?, updated_at = ?
+WHERE id = ? AND state = 'pending';
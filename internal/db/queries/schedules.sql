@@ -0,0 +1,48 @@
+-- name: CreateSchedule :one
+INSERT INTO schedules (
+    name, sender_id, recipients, topic_name, subject, body, priority,
+    cron_expr, timezone, catch_up, next_run_at, created_at, updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetScheduleByName :one
+SELECT * FROM schedules WHERE sender_id = ? AND name = ?;
+
+-- name: ListSchedules :many
+-- List an agent's schedules by name.
+SELECT * FROM schedules
+WHERE sender_id = ?
+ORDER BY name;
+
+-- name: ListDueSchedules :many
+-- List active schedules whose next run has come due, oldest first.
+SELECT * FROM schedules
+WHERE paused = 0 AND next_run_at <= ?
+ORDER BY next_run_at, id
+LIMIT ?;
+
+-- name: DeleteSchedule :execrows
+DELETE FROM schedules WHERE sender_id = ? AND name = ?;
+
+-- name: SetSchedulePaused :execrows
+UPDATE schedules
+SET paused = ?, next_run_at = ?, updated_at = ?
+WHERE sender_id = ? AND name = ?;
+
+-- name: RecordScheduleRun :exec
+-- Record a run of a schedule and move it on to its next occurrence.
+UPDATE schedules
+SET next_run_at = ?,
+    last_run_at = ?,
+    last_message_id = ?,
+    last_error = ?,
+    run_count = run_count + 1,
+    updated_at = ?
+WHERE id = ?;
+
+-- name: AdvanceSchedule :exec
+-- Move a schedule on to its next occurrence without recording a run, for
+-- occurrences that were skipped.
+UPDATE schedules
+SET next_run_at = ?, updated_at = ?
+WHERE id = ?;
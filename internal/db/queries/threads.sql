@@ -0,0 +1,66 @@
+-- name: UpsertThreadPreference :one
+INSERT INTO threads (
+    thread_id, agent_id, muted, following, created_at, updated_at
+) VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (thread_id, agent_id) DO UPDATE SET
+    muted = excluded.muted,
+    following = excluded.following,
+    updated_at = excluded.updated_at
+RETURNING *;
+
+-- name: GetThreadPreference :one
+SELECT * FROM threads WHERE thread_id = ? AND agent_id = ?;
+
+-- name: DeleteThreadPreference :execrows
+DELETE FROM threads WHERE thread_id = ? AND agent_id = ?;
+
+-- name: ListThreadPreferences :many
+-- List the threads an agent muted or follows, most recently changed first.
+SELECT * FROM threads
+WHERE agent_id = ?
+ORDER BY updated_at DESC, thread_id;
+
+-- name: ListThreadPreferencesByThread :many
+SELECT * FROM threads WHERE thread_id = ? ORDER BY agent_id;
+
+-- name: CopyThreadPreferences :exec
+-- Give a thread the preferences agents set on another, keeping any they
+-- already set on it.
+INSERT OR IGNORE INTO threads (
+    thread_id, agent_id, muted, following, created_at, updated_at
+)
+SELECT sqlc.arg(to_thread_id), t.agent_id, t.muted, t.following,
+    sqlc.arg(now), sqlc.arg(now)
+FROM threads t
+WHERE t.thread_id = sqlc.arg(from_thread_id);
+
+-- name: DeleteThreadPreferences :exec
+DELETE FROM threads WHERE thread_id = ?;
+
+-- name: CountThreadMessages :one
+SELECT COUNT(*) FROM messages WHERE thread_id = ?;
+
+-- name: CountThreadReviews :one
+-- Count the code reviews and plan reviews that track a thread.
+SELECT
+    (SELECT COUNT(*) FROM reviews r WHERE r.thread_id = sqlc.arg(thread_id))
+    + (SELECT COUNT(*) FROM plan_reviews pr
+       WHERE pr.thread_id = sqlc.arg(thread_id));
+
+-- name: MoveThreadMessages :execrows
+-- Move the messages of a thread from a given message on into another
+-- thread. A min_message_id of zero moves the whole thread.
+UPDATE messages SET thread_id = sqlc.arg(to_thread_id)
+WHERE thread_id = sqlc.arg(from_thread_id)
+  AND id >= sqlc.arg(min_message_id);
+
+-- name: MoveThreadQuestions :exec
+-- Move the questions asked by the messages MoveThreadMessages moves.
+UPDATE questions SET thread_id = sqlc.arg(to_thread_id)
+WHERE thread_id = sqlc.arg(from_thread_id)
+  AND message_id >= sqlc.arg(min_message_id);
+
+-- name: MoveScheduledThreadMessages :exec
+-- Point messages still waiting to be sent into a thread at another.
+UPDATE scheduled_messages SET thread_id = sqlc.arg(to_thread_id)
+WHERE thread_id = sqlc.arg(from_thread_id) AND state = 'pending';
@@ -6,31 +6,52 @@ import (
 	"fmt"
 
 	"github.com/roasbeef/subtrate/internal/db/sqlc"
+	"github.com/roasbeef/subtrate/internal/search"
 )
 
 // SearchResult represents a message search result with its state.
 type SearchResult struct {
 	sqlc.Message
 	State string
-	Rank  float64
+
+	// Rank is the message's BM25 rank, with subject matches weighted by
+	// search.DefaultSubjectWeight. Lower ranks are better matches.
+	Rank float64
+
+	// Snippet is the excerpt of the body around the matched terms, which
+	// are wrapped in search.HighlightStart and search.HighlightEnd.
+	Snippet string
+}
+
+// rankColumns are the rank and snippet columns of a search result.
+const rankColumns = `bm25(messages_fts, ?, 1.0) AS score,
+		       snippet(messages_fts, 1, ?, ?, ?, ?)`
+
+// rankArgs returns the arguments of rankColumns.
+func rankArgs() []any {
+	return []any{
+		search.DefaultSubjectWeight, search.HighlightStart,
+		search.HighlightEnd, search.SnippetEllipsis,
+		search.SnippetTokens,
+	}
 }
 
-// SearchMessages performs a full-text search across all messages using FTS5.
-// The query should use FTS5 query syntax (e.g., "word1 word2" for AND,
-// "word1 OR word2" for OR).
+// SearchMessages performs a full-text search across all messages using FTS5,
+// best matches first. The query should use FTS5 query syntax (e.g., "word1
+// word2" for AND, "word1 OR word2" for OR).
 func (s *Store) SearchMessages(ctx context.Context, query string,
 	limit int,
 ) ([]SearchResult, error) {
 	rows, err := s.DB().QueryContext(ctx, `
 		SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id,
 		       m.subject, m.body_md, m.priority, m.deadline_at,
-		       m.attachments, m.created_at, fts.rank
+		       m.attachments, m.created_at, `+rankColumns+`
 		FROM messages m
 		JOIN messages_fts fts ON m.id = fts.rowid
 		WHERE messages_fts MATCH ?
-		ORDER BY fts.rank
+		ORDER BY score
 		LIMIT ?
-	`, query, limit)
+	`, append(rankArgs(), query, limit)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search messages: %w", err)
 	}
@@ -45,7 +66,7 @@ func (s *Store) SearchMessages(ctx context.Context, query string,
 		err := rows.Scan(
 			&r.ID, &r.ThreadID, &r.TopicID, &r.LogOffset, &r.SenderID,
 			&r.Subject, &r.BodyMd, &r.Priority, &deadlineAt,
-			&attachments, &r.CreatedAt, &r.Rank,
+			&attachments, &r.CreatedAt, &r.Rank, &r.Snippet,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan search result: %w", err)
@@ -64,21 +85,21 @@ func (s *Store) SearchMessages(ctx context.Context, query string,
 }
 
 // SearchMessagesForAgent performs a full-text search for messages visible to
-// a specific agent.
+// a specific agent, best matches first.
 func (s *Store) SearchMessagesForAgent(ctx context.Context, query string,
 	agentID int64, limit int,
 ) ([]SearchResult, error) {
 	rows, err := s.DB().QueryContext(ctx, `
 		SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id,
 		       m.subject, m.body_md, m.priority, m.deadline_at,
-		       m.attachments, m.created_at, mr.state, fts.rank
+		       m.attachments, m.created_at, mr.state, `+rankColumns+`
 		FROM messages m
 		JOIN messages_fts fts ON m.id = fts.rowid
 		JOIN message_recipients mr ON m.id = mr.message_id
 		WHERE messages_fts MATCH ? AND mr.agent_id = ?
-		ORDER BY fts.rank
+		ORDER BY score
 		LIMIT ?
-	`, query, agentID, limit)
+	`, append(rankArgs(), query, agentID, limit)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search messages for agent: %w", err)
 	}
@@ -94,6 +115,7 @@ func (s *Store) SearchMessagesForAgent(ctx context.Context, query string,
 			&r.ID, &r.ThreadID, &r.TopicID, &r.LogOffset, &r.SenderID,
 			&r.Subject, &r.BodyMd, &r.Priority, &deadlineAt,
 			&attachments, &r.CreatedAt, &r.State, &r.Rank,
+			&r.Snippet,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan search result: %w", err)
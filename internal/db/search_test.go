@@ -75,6 +75,63 @@ func TestSearchMessages(t *testing.T) {
 	require.Len(t, results, 1)
 }
 
+// TestSearchMessages_Ranking tests that subject matches outrank body matches
+// and that results carry highlighted snippets.
+func TestSearchMessages_Ranking(t *testing.T) {
+	t.Parallel()
+
+	store, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	now := time.Now().Unix()
+	agent, err := store.Queries().CreateAgent(ctx, sqlc.CreateAgentParams{
+		Name:         "Ranker",
+		CreatedAt:    now,
+		LastActiveAt: now,
+	})
+	require.NoError(t, err)
+
+	topic, err := store.Queries().CreateTopic(ctx, sqlc.CreateTopicParams{
+		Name:      "rank-topic",
+		TopicType: "broadcast",
+		CreatedAt: now,
+	})
+	require.NoError(t, err)
+
+	messages := []struct {
+		subject string
+		body    string
+	}{
+		{"Weekly notes", "The rollout of the cache went well"},
+		{"Cache rollout", "Details to follow"},
+	}
+	for i, m := range messages {
+		_, err := store.Queries().CreateMessage(
+			ctx, sqlc.CreateMessageParams{
+				ThreadID:  "rank-" + string(rune('A'+i)),
+				TopicID:   topic.ID,
+				LogOffset: int64(i + 1),
+				SenderID:  agent.ID,
+				Subject:   m.subject,
+				BodyMd:    m.body,
+				Priority:  "normal",
+				CreatedAt: now,
+			},
+		)
+		require.NoError(t, err)
+	}
+
+	results, err := store.SearchMessages(ctx, "cache", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, "Cache rollout", results[0].Subject)
+	require.Less(t, results[0].Rank, results[1].Rank)
+	require.Equal(t, "The rollout of the **cache** went well",
+		results[1].Snippet)
+}
+
 func TestSearchMessagesForAgent(t *testing.T) {
 	t.Parallel()
 
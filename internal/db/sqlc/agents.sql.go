@@ -13,7 +13,7 @@ import (
 const CreateAgent = `-- name: CreateAgent :one
 INSERT INTO agents (name, project_key, git_branch, current_session_id, created_at, last_active_at)
 VALUES (?, ?, ?, ?, ?, ?)
-RETURNING id, name, project_key, git_branch, current_session_id, created_at, last_active_at, purpose, working_dir, hostname
+RETURNING id, name, project_key, git_branch, current_session_id, created_at, last_active_at, purpose, working_dir, hostname, max_concurrency
 `
 
 type CreateAgentParams struct {
@@ -46,6 +46,7 @@ func (q *Queries) CreateAgent(ctx context.Context, arg CreateAgentParams) (Agent
 		&i.Purpose,
 		&i.WorkingDir,
 		&i.Hostname,
+		&i.MaxConcurrency,
 	)
 	return i, err
 }
@@ -162,7 +163,7 @@ func (q *Queries) DiscoverAgents(ctx context.Context) ([]DiscoverAgentsRow, erro
 }
 
 const GetAgent = `-- name: GetAgent :one
-SELECT id, name, project_key, git_branch, current_session_id, created_at, last_active_at, purpose, working_dir, hostname FROM agents WHERE id = ?
+SELECT id, name, project_key, git_branch, current_session_id, created_at, last_active_at, purpose, working_dir, hostname, max_concurrency FROM agents WHERE id = ?
 `
 
 func (q *Queries) GetAgent(ctx context.Context, id int64) (Agent, error) {
@@ -179,12 +180,13 @@ func (q *Queries) GetAgent(ctx context.Context, id int64) (Agent, error) {
 		&i.Purpose,
 		&i.WorkingDir,
 		&i.Hostname,
+		&i.MaxConcurrency,
 	)
 	return i, err
 }
 
 const GetAgentByName = `-- name: GetAgentByName :one
-SELECT id, name, project_key, git_branch, current_session_id, created_at, last_active_at, purpose, working_dir, hostname FROM agents WHERE name = ?
+SELECT id, name, project_key, git_branch, current_session_id, created_at, last_active_at, purpose, working_dir, hostname, max_concurrency FROM agents WHERE name = ?
 `
 
 func (q *Queries) GetAgentByName(ctx context.Context, name string) (Agent, error) {
@@ -201,12 +203,13 @@ func (q *Queries) GetAgentByName(ctx context.Context, name string) (Agent, error
 		&i.Purpose,
 		&i.WorkingDir,
 		&i.Hostname,
+		&i.MaxConcurrency,
 	)
 	return i, err
 }
 
 const GetAgentBySessionID = `-- name: GetAgentBySessionID :one
-SELECT a.id, a.name, a.project_key, a.git_branch, a.current_session_id, a.created_at, a.last_active_at, a.purpose, a.working_dir, a.hostname FROM agents a
+SELECT a.id, a.name, a.project_key, a.git_branch, a.current_session_id, a.created_at, a.last_active_at, a.purpose, a.working_dir, a.hostname, a.max_concurrency FROM agents a
 JOIN session_identities si ON a.id = si.agent_id
 WHERE si.session_id = ?
 `
@@ -225,6 +228,7 @@ func (q *Queries) GetAgentBySessionID(ctx context.Context, sessionID string) (Ag
 		&i.Purpose,
 		&i.WorkingDir,
 		&i.Hostname,
+		&i.MaxConcurrency,
 	)
 	return i, err
 }
@@ -266,7 +270,7 @@ func (q *Queries) GetSessionIdentityByProject(ctx context.Context, projectKey sq
 }
 
 const ListAgents = `-- name: ListAgents :many
-SELECT id, name, project_key, git_branch, current_session_id, created_at, last_active_at, purpose, working_dir, hostname FROM agents ORDER BY last_active_at DESC
+SELECT id, name, project_key, git_branch, current_session_id, created_at, last_active_at, purpose, working_dir, hostname, max_concurrency FROM agents ORDER BY last_active_at DESC
 `
 
 func (q *Queries) ListAgents(ctx context.Context) ([]Agent, error) {
@@ -289,6 +293,7 @@ func (q *Queries) ListAgents(ctx context.Context) ([]Agent, error) {
 			&i.Purpose,
 			&i.WorkingDir,
 			&i.Hostname,
+			&i.MaxConcurrency,
 		); err != nil {
 			return nil, err
 		}
@@ -304,7 +309,7 @@ func (q *Queries) ListAgents(ctx context.Context) ([]Agent, error) {
 }
 
 const ListAgentsByProject = `-- name: ListAgentsByProject :many
-SELECT id, name, project_key, git_branch, current_session_id, created_at, last_active_at, purpose, working_dir, hostname FROM agents WHERE project_key = ? ORDER BY last_active_at DESC
+SELECT id, name, project_key, git_branch, current_session_id, created_at, last_active_at, purpose, working_dir, hostname, max_concurrency FROM agents WHERE project_key = ? ORDER BY last_active_at DESC
 `
 
 func (q *Queries) ListAgentsByProject(ctx context.Context, projectKey sql.NullString) ([]Agent, error) {
@@ -327,6 +332,7 @@ func (q *Queries) ListAgentsByProject(ctx context.Context, projectKey sql.NullSt
 			&i.Purpose,
 			&i.WorkingDir,
 			&i.Hostname,
+			&i.MaxConcurrency,
 		); err != nil {
 			return nil, err
 		}
@@ -376,7 +382,7 @@ func (q *Queries) ListSessionIdentitiesByAgent(ctx context.Context, agentID int6
 }
 
 const SearchAgents = `-- name: SearchAgents :many
-SELECT id, name, project_key, git_branch, current_session_id, created_at, last_active_at, purpose, working_dir, hostname FROM agents
+SELECT id, name, project_key, git_branch, current_session_id, created_at, last_active_at, purpose, working_dir, hostname, max_concurrency FROM agents
 WHERE name LIKE '%' || ?1 || '%'
    OR project_key LIKE '%' || ?1 || '%'
    OR git_branch LIKE '%' || ?1 || '%'
@@ -409,6 +415,7 @@ func (q *Queries) SearchAgents(ctx context.Context, arg SearchAgentsParams) ([]A
 			&i.Purpose,
 			&i.WorkingDir,
 			&i.Hostname,
+			&i.MaxConcurrency,
 		); err != nil {
 			return nil, err
 		}
@@ -500,6 +507,21 @@ func (q *Queries) UpdateAgentName(ctx context.Context, arg UpdateAgentNameParams
 	return err
 }
 
+const UpdateAgentProject = `-- name: UpdateAgentProject :exec
+UPDATE agents SET project_key = ?, git_branch = ? WHERE id = ?
+`
+
+type UpdateAgentProjectParams struct {
+	ProjectKey sql.NullString
+	GitBranch  sql.NullString
+	ID         int64
+}
+
+func (q *Queries) UpdateAgentProject(ctx context.Context, arg UpdateAgentProjectParams) error {
+	_, err := q.db.ExecContext(ctx, UpdateAgentProject, arg.ProjectKey, arg.GitBranch, arg.ID)
+	return err
+}
+
 const UpdateAgentSession = `-- name: UpdateAgentSession :exec
 UPDATE agents SET current_session_id = ?, last_active_at = ? WHERE id = ?
 `
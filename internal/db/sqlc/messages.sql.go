@@ -26,23 +26,26 @@ func (q *Queries) CountArchivedByAgent(ctx context.Context, agentID int64) (int6
 const CountInboxCategories = `-- name: CountInboxCategories :one
 SELECT
     CAST(COALESCE(SUM(CASE
-        WHEN m.subject NOT LIKE '[Permission]%'
+        WHEN mr.category = 'primary' OR (mr.category = ''
+            AND m.subject NOT LIKE '[Permission]%'
             AND m.subject NOT LIKE '[Notification]%'
             AND m.subject NOT LIKE '[Idle]%'
-            AND m.subject NOT LIKE '[Status]%'
+            AND m.subject NOT LIKE '[Status]%')
         THEN 1 ELSE 0 END), 0) AS INTEGER) AS primary_count,
     CAST(COALESCE(SUM(CASE
-        WHEN m.subject LIKE '[Permission]%'
-            OR m.subject LIKE '[Notification]%'
-            OR m.subject LIKE '[Idle]%'
-            OR m.subject LIKE '[Status]%'
+        WHEN mr.category = 'notifications' OR (mr.category = ''
+            AND (m.subject LIKE '[Permission]%'
+                OR m.subject LIKE '[Notification]%'
+                OR m.subject LIKE '[Idle]%'
+                OR m.subject LIKE '[Status]%'))
         THEN 1 ELSE 0 END), 0) AS INTEGER) AS notifications_count,
     CAST(COALESCE(SUM(CASE
-        WHEN mr.state = 'unread'
-            AND m.subject NOT LIKE '[Permission]%'
-            AND m.subject NOT LIKE '[Notification]%'
-            AND m.subject NOT LIKE '[Idle]%'
-            AND m.subject NOT LIKE '[Status]%'
+        WHEN mr.state = 'unread' AND (mr.category = 'primary'
+            OR (mr.category = ''
+                AND m.subject NOT LIKE '[Permission]%'
+                AND m.subject NOT LIKE '[Notification]%'
+                AND m.subject NOT LIKE '[Idle]%'
+                AND m.subject NOT LIKE '[Status]%'))
         THEN 1 ELSE 0 END), 0) AS INTEGER) AS unread_count,
     CAST(COALESCE(SUM(CASE
         WHEN mr.state = 'starred'
@@ -62,7 +65,8 @@ type CountInboxCategoriesRow struct {
 
 // Returns total counts per inbox category for an agent, excluding
 // archived and trashed messages. Drives tab labels and stats in the
-// web UI without forcing a second round trip.
+// web UI without forcing a second round trip. A category set by an
+// inbox rule takes precedence over the one derived from the subject.
 func (q *Queries) CountInboxCategories(ctx context.Context, agentID int64) (CountInboxCategoriesRow, error) {
 	row := q.db.QueryRowContext(ctx, CountInboxCategories, agentID)
 	var i CountInboxCategoriesRow
@@ -139,9 +143,10 @@ func (q *Queries) CountUnreadUrgentByAgent(ctx context.Context, agentID int64) (
 const CreateMessage = `-- name: CreateMessage :one
 INSERT INTO messages (
     thread_id, topic_id, log_offset, sender_id, subject, body_md,
-    priority, deadline_at, attachments, created_at, idempotency_key
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-RETURNING id, thread_id, topic_id, log_offset, sender_id, subject, body_md, priority, deadline_at, attachments, created_at, deleted_by_sender, metadata, idempotency_key
+    priority, deadline_at, attachments, metadata, created_at,
+    idempotency_key
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, thread_id, topic_id, log_offset, sender_id, subject, body_md, priority, deadline_at, attachments, created_at, deleted_by_sender, metadata, idempotency_key, legal_hold, edited_at
 `
 
 type CreateMessageParams struct {
@@ -154,6 +159,7 @@ type CreateMessageParams struct {
 	Priority       string
 	DeadlineAt     sql.NullInt64
 	Attachments    sql.NullString
+	Metadata       sql.NullString
 	CreatedAt      int64
 	IdempotencyKey sql.NullString
 }
@@ -169,6 +175,7 @@ func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (M
 		arg.Priority,
 		arg.DeadlineAt,
 		arg.Attachments,
+		arg.Metadata,
 		arg.CreatedAt,
 		arg.IdempotencyKey,
 	)
@@ -188,6 +195,8 @@ func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (M
 		&i.DeletedBySender,
 		&i.Metadata,
 		&i.IdempotencyKey,
+		&i.LegalHold,
+		&i.EditedAt,
 	)
 	return i, err
 }
@@ -234,7 +243,7 @@ func (q *Queries) DeleteMessagesByTopicOlderThan(ctx context.Context, arg Delete
 }
 
 const GetAllInboxMessages = `-- name: GetAllInboxMessages :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at, mr.agent_id as recipient_agent_id, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at, mr.agent_id as recipient_agent_id, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
 FROM messages m
 JOIN message_recipients mr ON m.id = mr.message_id
 LEFT JOIN agents a ON m.sender_id = a.id
@@ -258,6 +267,8 @@ type GetAllInboxMessagesRow struct {
 	DeletedBySender  int64
 	Metadata         sql.NullString
 	IdempotencyKey   sql.NullString
+	LegalHold        int64
+	EditedAt         sql.NullInt64
 	State            string
 	SnoozedUntil     sql.NullInt64
 	ReadAt           sql.NullInt64
@@ -293,6 +304,8 @@ func (q *Queries) GetAllInboxMessages(ctx context.Context, limit int64) ([]GetAl
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.State,
 			&i.SnoozedUntil,
 			&i.ReadAt,
@@ -316,7 +329,7 @@ func (q *Queries) GetAllInboxMessages(ctx context.Context, limit int64) ([]GetAl
 }
 
 const GetAllInboxMessagesPaginated = `-- name: GetAllInboxMessagesPaginated :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at, mr.agent_id as recipient_agent_id, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at, mr.agent_id as recipient_agent_id, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
 FROM messages m
 JOIN message_recipients mr ON m.id = mr.message_id
 LEFT JOIN agents a ON m.sender_id = a.id
@@ -345,6 +358,8 @@ type GetAllInboxMessagesPaginatedRow struct {
 	DeletedBySender  int64
 	Metadata         sql.NullString
 	IdempotencyKey   sql.NullString
+	LegalHold        int64
+	EditedAt         sql.NullInt64
 	State            string
 	SnoozedUntil     sql.NullInt64
 	ReadAt           sql.NullInt64
@@ -380,6 +395,8 @@ func (q *Queries) GetAllInboxMessagesPaginated(ctx context.Context, arg GetAllIn
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.State,
 			&i.SnoozedUntil,
 			&i.ReadAt,
@@ -403,7 +420,7 @@ func (q *Queries) GetAllInboxMessagesPaginated(ctx context.Context, arg GetAllIn
 }
 
 const GetAllSentMessages = `-- name: GetAllSentMessages :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
 FROM messages m
 JOIN agents a ON m.sender_id = a.id
 WHERE m.deleted_by_sender = 0
@@ -426,6 +443,8 @@ type GetAllSentMessagesRow struct {
 	DeletedBySender  int64
 	Metadata         sql.NullString
 	IdempotencyKey   sql.NullString
+	LegalHold        int64
+	EditedAt         sql.NullInt64
 	SenderName       string
 	SenderProjectKey sql.NullString
 	SenderGitBranch  sql.NullString
@@ -456,6 +475,8 @@ func (q *Queries) GetAllSentMessages(ctx context.Context, limit int64) ([]GetAll
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.SenderName,
 			&i.SenderProjectKey,
 			&i.SenderGitBranch,
@@ -474,7 +495,7 @@ func (q *Queries) GetAllSentMessages(ctx context.Context, limit int64) ([]GetAll
 }
 
 const GetArchivedMessages = `-- name: GetArchivedMessages :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at
 FROM messages m
 JOIN message_recipients mr ON m.id = mr.message_id
 WHERE mr.agent_id = ?
@@ -503,6 +524,8 @@ type GetArchivedMessagesRow struct {
 	DeletedBySender int64
 	Metadata        sql.NullString
 	IdempotencyKey  sql.NullString
+	LegalHold       int64
+	EditedAt        sql.NullInt64
 	State           string
 	SnoozedUntil    sql.NullInt64
 	ReadAt          sql.NullInt64
@@ -533,6 +556,8 @@ func (q *Queries) GetArchivedMessages(ctx context.Context, arg GetArchivedMessag
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.State,
 			&i.SnoozedUntil,
 			&i.ReadAt,
@@ -552,7 +577,7 @@ func (q *Queries) GetArchivedMessages(ctx context.Context, arg GetArchivedMessag
 }
 
 const GetInboxMessages = `-- name: GetInboxMessages :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
 FROM messages m
 JOIN message_recipients mr ON m.id = mr.message_id
 LEFT JOIN agents a ON m.sender_id = a.id
@@ -583,6 +608,8 @@ type GetInboxMessagesRow struct {
 	DeletedBySender  int64
 	Metadata         sql.NullString
 	IdempotencyKey   sql.NullString
+	LegalHold        int64
+	EditedAt         sql.NullInt64
 	State            string
 	SnoozedUntil     sql.NullInt64
 	ReadAt           sql.NullInt64
@@ -616,6 +643,8 @@ func (q *Queries) GetInboxMessages(ctx context.Context, arg GetInboxMessagesPara
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.State,
 			&i.SnoozedUntil,
 			&i.ReadAt,
@@ -638,7 +667,7 @@ func (q *Queries) GetInboxMessages(ctx context.Context, arg GetInboxMessagesPara
 }
 
 const GetInboxMessagesNotifications = `-- name: GetInboxMessagesNotifications :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at,
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at,
     a.name as sender_name, a.project_key as sender_project_key,
     a.git_branch as sender_git_branch
 FROM messages m
@@ -646,10 +675,12 @@ JOIN message_recipients mr ON m.id = mr.message_id
 LEFT JOIN agents a ON m.sender_id = a.id
 WHERE mr.agent_id = ?
     AND mr.state NOT IN ('archived', 'trash')
-    AND (m.subject LIKE '[Permission]%'
-         OR m.subject LIKE '[Notification]%'
-         OR m.subject LIKE '[Idle]%'
-         OR m.subject LIKE '[Status]%')
+    AND (mr.category = 'notifications'
+         OR (mr.category = ''
+             AND (m.subject LIKE '[Permission]%'
+                  OR m.subject LIKE '[Notification]%'
+                  OR m.subject LIKE '[Idle]%'
+                  OR m.subject LIKE '[Status]%')))
 ORDER BY m.created_at DESC
 LIMIT ? OFFSET ?
 `
@@ -675,6 +706,8 @@ type GetInboxMessagesNotificationsRow struct {
 	DeletedBySender  int64
 	Metadata         sql.NullString
 	IdempotencyKey   sql.NullString
+	LegalHold        int64
+	EditedAt         sql.NullInt64
 	State            string
 	SnoozedUntil     sql.NullInt64
 	ReadAt           sql.NullInt64
@@ -685,7 +718,8 @@ type GetInboxMessagesNotificationsRow struct {
 }
 
 // Hook-generated notification messages (Permission/Idle/Status/
-// Notification subjects). Used by the Notifications tab in the web UI.
+// Notification subjects) and messages an inbox rule filed as
+// notifications. Used by the Notifications tab in the web UI.
 func (q *Queries) GetInboxMessagesNotifications(ctx context.Context, arg GetInboxMessagesNotificationsParams) ([]GetInboxMessagesNotificationsRow, error) {
 	rows, err := q.db.QueryContext(ctx, GetInboxMessagesNotifications, arg.AgentID, arg.Limit, arg.Offset)
 	if err != nil {
@@ -710,6 +744,8 @@ func (q *Queries) GetInboxMessagesNotifications(ctx context.Context, arg GetInbo
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.State,
 			&i.SnoozedUntil,
 			&i.ReadAt,
@@ -732,7 +768,7 @@ func (q *Queries) GetInboxMessagesNotifications(ctx context.Context, arg GetInbo
 }
 
 const GetInboxMessagesPrimary = `-- name: GetInboxMessagesPrimary :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at,
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at,
     a.name as sender_name, a.project_key as sender_project_key,
     a.git_branch as sender_git_branch
 FROM messages m
@@ -740,10 +776,12 @@ JOIN message_recipients mr ON m.id = mr.message_id
 LEFT JOIN agents a ON m.sender_id = a.id
 WHERE mr.agent_id = ?
     AND mr.state NOT IN ('archived', 'trash')
-    AND m.subject NOT LIKE '[Permission]%'
-    AND m.subject NOT LIKE '[Notification]%'
-    AND m.subject NOT LIKE '[Idle]%'
-    AND m.subject NOT LIKE '[Status]%'
+    AND (mr.category = 'primary'
+         OR (mr.category = ''
+             AND m.subject NOT LIKE '[Permission]%'
+             AND m.subject NOT LIKE '[Notification]%'
+             AND m.subject NOT LIKE '[Idle]%'
+             AND m.subject NOT LIKE '[Status]%'))
 ORDER BY m.created_at DESC
 LIMIT ? OFFSET ?
 `
@@ -769,6 +807,8 @@ type GetInboxMessagesPrimaryRow struct {
 	DeletedBySender  int64
 	Metadata         sql.NullString
 	IdempotencyKey   sql.NullString
+	LegalHold        int64
+	EditedAt         sql.NullInt64
 	State            string
 	SnoozedUntil     sql.NullInt64
 	ReadAt           sql.NullInt64
@@ -779,8 +819,8 @@ type GetInboxMessagesPrimaryRow struct {
 }
 
 // Primary inbox view: every non-archived, non-trashed message that
-// isn't hook-generated chatter. Used by the Primary tab as the
-// default "real inbox" view.
+// isn't hook-generated chatter, unless an inbox rule filed it elsewhere.
+// Used by the Primary tab as the default "real inbox" view.
 func (q *Queries) GetInboxMessagesPrimary(ctx context.Context, arg GetInboxMessagesPrimaryParams) ([]GetInboxMessagesPrimaryRow, error) {
 	rows, err := q.db.QueryContext(ctx, GetInboxMessagesPrimary, arg.AgentID, arg.Limit, arg.Offset)
 	if err != nil {
@@ -805,6 +845,8 @@ func (q *Queries) GetInboxMessagesPrimary(ctx context.Context, arg GetInboxMessa
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.State,
 			&i.SnoozedUntil,
 			&i.ReadAt,
@@ -838,7 +880,7 @@ func (q *Queries) GetMaxLogOffset(ctx context.Context, topicID int64) (interface
 }
 
 const GetMessage = `-- name: GetMessage :one
-SELECT id, thread_id, topic_id, log_offset, sender_id, subject, body_md, priority, deadline_at, attachments, created_at, deleted_by_sender, metadata, idempotency_key FROM messages WHERE id = ?
+SELECT id, thread_id, topic_id, log_offset, sender_id, subject, body_md, priority, deadline_at, attachments, created_at, deleted_by_sender, metadata, idempotency_key, legal_hold, edited_at FROM messages WHERE id = ?
 `
 
 func (q *Queries) GetMessage(ctx context.Context, id int64) (Message, error) {
@@ -859,12 +901,14 @@ func (q *Queries) GetMessage(ctx context.Context, id int64) (Message, error) {
 		&i.DeletedBySender,
 		&i.Metadata,
 		&i.IdempotencyKey,
+		&i.LegalHold,
+		&i.EditedAt,
 	)
 	return i, err
 }
 
 const GetMessageByIdempotencyKey = `-- name: GetMessageByIdempotencyKey :one
-SELECT id, thread_id, topic_id, log_offset, sender_id, subject, body_md, priority, deadline_at, attachments, created_at, deleted_by_sender, metadata, idempotency_key FROM messages WHERE idempotency_key = ? LIMIT 1
+SELECT id, thread_id, topic_id, log_offset, sender_id, subject, body_md, priority, deadline_at, attachments, created_at, deleted_by_sender, metadata, idempotency_key, legal_hold, edited_at FROM messages WHERE idempotency_key = ? LIMIT 1
 `
 
 func (q *Queries) GetMessageByIdempotencyKey(ctx context.Context, idempotencyKey sql.NullString) (Message, error) {
@@ -885,12 +929,14 @@ func (q *Queries) GetMessageByIdempotencyKey(ctx context.Context, idempotencyKey
 		&i.DeletedBySender,
 		&i.Metadata,
 		&i.IdempotencyKey,
+		&i.LegalHold,
+		&i.EditedAt,
 	)
 	return i, err
 }
 
 const GetMessageRecipient = `-- name: GetMessageRecipient :one
-SELECT message_id, agent_id, state, snoozed_until, read_at, acked_at FROM message_recipients
+SELECT message_id, agent_id, state, snoozed_until, read_at, acked_at, category FROM message_recipients
 WHERE message_id = ? AND agent_id = ?
 `
 
@@ -909,12 +955,13 @@ func (q *Queries) GetMessageRecipient(ctx context.Context, arg GetMessageRecipie
 		&i.SnoozedUntil,
 		&i.ReadAt,
 		&i.AckedAt,
+		&i.Category,
 	)
 	return i, err
 }
 
 const GetMessageRecipients = `-- name: GetMessageRecipients :many
-SELECT message_id, agent_id, state, snoozed_until, read_at, acked_at FROM message_recipients
+SELECT message_id, agent_id, state, snoozed_until, read_at, acked_at, category FROM message_recipients
 WHERE message_id = ?
 `
 
@@ -934,6 +981,7 @@ func (q *Queries) GetMessageRecipients(ctx context.Context, messageID int64) ([]
 			&i.SnoozedUntil,
 			&i.ReadAt,
 			&i.AckedAt,
+			&i.Category,
 		); err != nil {
 			return nil, err
 		}
@@ -949,7 +997,7 @@ func (q *Queries) GetMessageRecipients(ctx context.Context, messageID int64) ([]
 }
 
 const GetMessageRecipientsWithAgentsBulk = `-- name: GetMessageRecipientsWithAgentsBulk :many
-SELECT mr.message_id, mr.agent_id, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at, a.name as agent_name
+SELECT mr.message_id, mr.agent_id, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at, mr.category, a.name as agent_name
 FROM message_recipients mr
 LEFT JOIN agents a ON mr.agent_id = a.id
 WHERE mr.message_id IN (/*SLICE:message_ids*/?)
@@ -962,6 +1010,7 @@ type GetMessageRecipientsWithAgentsBulkRow struct {
 	SnoozedUntil sql.NullInt64
 	ReadAt       sql.NullInt64
 	AckedAt      sql.NullInt64
+	Category     string
 	AgentName    sql.NullString
 }
 
@@ -993,6 +1042,7 @@ func (q *Queries) GetMessageRecipientsWithAgentsBulk(ctx context.Context, messag
 			&i.SnoozedUntil,
 			&i.ReadAt,
 			&i.AckedAt,
+			&i.Category,
 			&i.AgentName,
 		); err != nil {
 			return nil, err
@@ -1009,7 +1059,7 @@ func (q *Queries) GetMessageRecipientsWithAgentsBulk(ctx context.Context, messag
 }
 
 const GetMessagesBySenderNamePrefix = `-- name: GetMessagesBySenderNamePrefix :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at,
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at,
        mr.agent_id as recipient_agent_id,
        a.name as sender_name, a.project_key as sender_project_key,
        a.git_branch as sender_git_branch
@@ -1043,6 +1093,8 @@ type GetMessagesBySenderNamePrefixRow struct {
 	DeletedBySender  int64
 	Metadata         sql.NullString
 	IdempotencyKey   sql.NullString
+	LegalHold        int64
+	EditedAt         sql.NullInt64
 	State            string
 	SnoozedUntil     sql.NullInt64
 	ReadAt           sql.NullInt64
@@ -1080,6 +1132,8 @@ func (q *Queries) GetMessagesBySenderNamePrefix(ctx context.Context, arg GetMess
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.State,
 			&i.SnoozedUntil,
 			&i.ReadAt,
@@ -1103,7 +1157,7 @@ func (q *Queries) GetMessagesBySenderNamePrefix(ctx context.Context, arg GetMess
 }
 
 const GetMessagesByThread = `-- name: GetMessagesByThread :many
-SELECT id, thread_id, topic_id, log_offset, sender_id, subject, body_md, priority, deadline_at, attachments, created_at, deleted_by_sender, metadata, idempotency_key FROM messages WHERE thread_id = ? ORDER BY created_at ASC
+SELECT id, thread_id, topic_id, log_offset, sender_id, subject, body_md, priority, deadline_at, attachments, created_at, deleted_by_sender, metadata, idempotency_key, legal_hold, edited_at FROM messages WHERE thread_id = ? ORDER BY created_at ASC
 `
 
 func (q *Queries) GetMessagesByThread(ctx context.Context, threadID string) ([]Message, error) {
@@ -1130,6 +1184,8 @@ func (q *Queries) GetMessagesByThread(ctx context.Context, threadID string) ([]M
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1145,7 +1201,7 @@ func (q *Queries) GetMessagesByThread(ctx context.Context, threadID string) ([]M
 }
 
 const GetMessagesByThreadWithSender = `-- name: GetMessagesByThreadWithSender :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
 FROM messages m
 LEFT JOIN agents a ON m.sender_id = a.id
 WHERE m.thread_id = ?
@@ -1167,6 +1223,8 @@ type GetMessagesByThreadWithSenderRow struct {
 	DeletedBySender  int64
 	Metadata         sql.NullString
 	IdempotencyKey   sql.NullString
+	LegalHold        int64
+	EditedAt         sql.NullInt64
 	SenderName       sql.NullString
 	SenderProjectKey sql.NullString
 	SenderGitBranch  sql.NullString
@@ -1197,6 +1255,8 @@ func (q *Queries) GetMessagesByThreadWithSender(ctx context.Context, threadID st
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.SenderName,
 			&i.SenderProjectKey,
 			&i.SenderGitBranch,
@@ -1215,7 +1275,7 @@ func (q *Queries) GetMessagesByThreadWithSender(ctx context.Context, threadID st
 }
 
 const GetMessagesByTopic = `-- name: GetMessagesByTopic :many
-SELECT id, thread_id, topic_id, log_offset, sender_id, subject, body_md, priority, deadline_at, attachments, created_at, deleted_by_sender, metadata, idempotency_key FROM messages WHERE topic_id = ? ORDER BY log_offset ASC
+SELECT id, thread_id, topic_id, log_offset, sender_id, subject, body_md, priority, deadline_at, attachments, created_at, deleted_by_sender, metadata, idempotency_key, legal_hold, edited_at FROM messages WHERE topic_id = ? ORDER BY log_offset ASC
 `
 
 func (q *Queries) GetMessagesByTopic(ctx context.Context, topicID int64) ([]Message, error) {
@@ -1242,6 +1302,8 @@ func (q *Queries) GetMessagesByTopic(ctx context.Context, topicID int64) ([]Mess
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1257,7 +1319,7 @@ func (q *Queries) GetMessagesByTopic(ctx context.Context, topicID int64) ([]Mess
 }
 
 const GetMessagesSinceOffset = `-- name: GetMessagesSinceOffset :many
-SELECT id, thread_id, topic_id, log_offset, sender_id, subject, body_md, priority, deadline_at, attachments, created_at, deleted_by_sender, metadata, idempotency_key FROM messages
+SELECT id, thread_id, topic_id, log_offset, sender_id, subject, body_md, priority, deadline_at, attachments, created_at, deleted_by_sender, metadata, idempotency_key, legal_hold, edited_at FROM messages
 WHERE topic_id = ? AND log_offset > ?
 ORDER BY log_offset ASC
 LIMIT ?
@@ -1293,6 +1355,109 @@ func (q *Queries) GetMessagesSinceOffset(ctx context.Context, arg GetMessagesSin
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const GetOverdueMessages = `-- name: GetOverdueMessages :many
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
+FROM messages m
+JOIN message_recipients mr ON m.id = mr.message_id
+LEFT JOIN agents a ON m.sender_id = a.id
+WHERE (mr.agent_id = ?1 OR ?1 = 0)
+    AND mr.state NOT IN ('archived', 'trash')
+    AND mr.acked_at IS NULL
+    AND m.deadline_at IS NOT NULL
+    AND m.deadline_at <= ?2
+ORDER BY m.deadline_at ASC
+LIMIT ?4 OFFSET ?3
+`
+
+type GetOverdueMessagesParams struct {
+	AgentID int64
+	Now     sql.NullInt64
+	Offset  int64
+	Limit   int64
+}
+
+type GetOverdueMessagesRow struct {
+	ID               int64
+	ThreadID         string
+	TopicID          int64
+	LogOffset        int64
+	SenderID         int64
+	Subject          string
+	BodyMd           string
+	Priority         string
+	DeadlineAt       sql.NullInt64
+	Attachments      sql.NullString
+	CreatedAt        int64
+	DeletedBySender  int64
+	Metadata         sql.NullString
+	IdempotencyKey   sql.NullString
+	LegalHold        int64
+	EditedAt         sql.NullInt64
+	State            string
+	SnoozedUntil     sql.NullInt64
+	ReadAt           sql.NullInt64
+	AckedAt          sql.NullInt64
+	SenderName       sql.NullString
+	SenderProjectKey sql.NullString
+	SenderGitBranch  sql.NullString
+}
+
+// Inbox view of unacked messages whose deadline has passed, most overdue
+// first. An agent_id of 0 returns overdue messages across all agents.
+func (q *Queries) GetOverdueMessages(ctx context.Context, arg GetOverdueMessagesParams) ([]GetOverdueMessagesRow, error) {
+	rows, err := q.db.QueryContext(ctx, GetOverdueMessages,
+		arg.AgentID,
+		arg.Now,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOverdueMessagesRow
+	for rows.Next() {
+		var i GetOverdueMessagesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ThreadID,
+			&i.TopicID,
+			&i.LogOffset,
+			&i.SenderID,
+			&i.Subject,
+			&i.BodyMd,
+			&i.Priority,
+			&i.DeadlineAt,
+			&i.Attachments,
+			&i.CreatedAt,
+			&i.DeletedBySender,
+			&i.Metadata,
+			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
+			&i.State,
+			&i.SnoozedUntil,
+			&i.ReadAt,
+			&i.AckedAt,
+			&i.SenderName,
+			&i.SenderProjectKey,
+			&i.SenderGitBranch,
 		); err != nil {
 			return nil, err
 		}
@@ -1308,7 +1473,7 @@ func (q *Queries) GetMessagesSinceOffset(ctx context.Context, arg GetMessagesSin
 }
 
 const GetSentMessages = `-- name: GetSentMessages :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
 FROM messages m
 LEFT JOIN agents a ON m.sender_id = a.id
 WHERE m.sender_id = ? AND m.deleted_by_sender = 0
@@ -1336,6 +1501,8 @@ type GetSentMessagesRow struct {
 	DeletedBySender  int64
 	Metadata         sql.NullString
 	IdempotencyKey   sql.NullString
+	LegalHold        int64
+	EditedAt         sql.NullInt64
 	SenderName       sql.NullString
 	SenderProjectKey sql.NullString
 	SenderGitBranch  sql.NullString
@@ -1366,6 +1533,8 @@ func (q *Queries) GetSentMessages(ctx context.Context, arg GetSentMessagesParams
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.SenderName,
 			&i.SenderProjectKey,
 			&i.SenderGitBranch,
@@ -1384,7 +1553,7 @@ func (q *Queries) GetSentMessages(ctx context.Context, arg GetSentMessagesParams
 }
 
 const GetSnoozedMessages = `-- name: GetSnoozedMessages :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at
 FROM messages m
 JOIN message_recipients mr ON m.id = mr.message_id
 WHERE mr.agent_id = ?
@@ -1413,6 +1582,8 @@ type GetSnoozedMessagesRow struct {
 	DeletedBySender int64
 	Metadata        sql.NullString
 	IdempotencyKey  sql.NullString
+	LegalHold       int64
+	EditedAt        sql.NullInt64
 	State           string
 	SnoozedUntil    sql.NullInt64
 	ReadAt          sql.NullInt64
@@ -1443,6 +1614,8 @@ func (q *Queries) GetSnoozedMessages(ctx context.Context, arg GetSnoozedMessages
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.State,
 			&i.SnoozedUntil,
 			&i.ReadAt,
@@ -1462,7 +1635,7 @@ func (q *Queries) GetSnoozedMessages(ctx context.Context, arg GetSnoozedMessages
 }
 
 const GetSnoozedMessagesReadyToWake = `-- name: GetSnoozedMessagesReadyToWake :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, mr.agent_id as recipient_agent_id
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, mr.agent_id as recipient_agent_id
 FROM messages m
 JOIN message_recipients mr ON m.id = mr.message_id
 WHERE mr.state = 'snoozed'
@@ -1485,6 +1658,8 @@ type GetSnoozedMessagesReadyToWakeRow struct {
 	DeletedBySender  int64
 	Metadata         sql.NullString
 	IdempotencyKey   sql.NullString
+	LegalHold        int64
+	EditedAt         sql.NullInt64
 	RecipientAgentID int64
 }
 
@@ -1512,6 +1687,8 @@ func (q *Queries) GetSnoozedMessagesReadyToWake(ctx context.Context, snoozedUnti
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.RecipientAgentID,
 		); err != nil {
 			return nil, err
@@ -1528,7 +1705,7 @@ func (q *Queries) GetSnoozedMessagesReadyToWake(ctx context.Context, snoozedUnti
 }
 
 const GetStarredMessages = `-- name: GetStarredMessages :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at
 FROM messages m
 JOIN message_recipients mr ON m.id = mr.message_id
 WHERE mr.agent_id = ?
@@ -1557,6 +1734,8 @@ type GetStarredMessagesRow struct {
 	DeletedBySender int64
 	Metadata        sql.NullString
 	IdempotencyKey  sql.NullString
+	LegalHold       int64
+	EditedAt        sql.NullInt64
 	State           string
 	SnoozedUntil    sql.NullInt64
 	ReadAt          sql.NullInt64
@@ -1587,6 +1766,8 @@ func (q *Queries) GetStarredMessages(ctx context.Context, arg GetStarredMessages
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.State,
 			&i.SnoozedUntil,
 			&i.ReadAt,
@@ -1606,7 +1787,7 @@ func (q *Queries) GetStarredMessages(ctx context.Context, arg GetStarredMessages
 }
 
 const GetTrashMessages = `-- name: GetTrashMessages :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at
 FROM messages m
 JOIN message_recipients mr ON m.id = mr.message_id
 WHERE mr.agent_id = ?
@@ -1635,6 +1816,8 @@ type GetTrashMessagesRow struct {
 	DeletedBySender int64
 	Metadata        sql.NullString
 	IdempotencyKey  sql.NullString
+	LegalHold       int64
+	EditedAt        sql.NullInt64
 	State           string
 	SnoozedUntil    sql.NullInt64
 	ReadAt          sql.NullInt64
@@ -1665,6 +1848,8 @@ func (q *Queries) GetTrashMessages(ctx context.Context, arg GetTrashMessagesPara
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.State,
 			&i.SnoozedUntil,
 			&i.ReadAt,
@@ -1684,7 +1869,7 @@ func (q *Queries) GetTrashMessages(ctx context.Context, arg GetTrashMessagesPara
 }
 
 const GetUnreadMessages = `-- name: GetUnreadMessages :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, mr.state, mr.snoozed_until, mr.read_at, mr.acked_at, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
 FROM messages m
 JOIN message_recipients mr ON m.id = mr.message_id
 LEFT JOIN agents a ON m.sender_id = a.id
@@ -1715,6 +1900,8 @@ type GetUnreadMessagesRow struct {
 	DeletedBySender  int64
 	Metadata         sql.NullString
 	IdempotencyKey   sql.NullString
+	LegalHold        int64
+	EditedAt         sql.NullInt64
 	State            string
 	SnoozedUntil     sql.NullInt64
 	ReadAt           sql.NullInt64
@@ -1748,6 +1935,8 @@ func (q *Queries) GetUnreadMessages(ctx context.Context, arg GetUnreadMessagesPa
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.State,
 			&i.SnoozedUntil,
 			&i.ReadAt,
@@ -1793,7 +1982,7 @@ func (q *Queries) HasUnackedStatusToAgent(ctx context.Context, arg HasUnackedSta
 }
 
 const ListMessagesByPriority = `-- name: ListMessagesByPriority :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key FROM messages m
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at FROM messages m
 JOIN message_recipients mr ON m.id = mr.message_id
 WHERE mr.agent_id = ? AND m.priority = ?
 ORDER BY m.created_at DESC
@@ -1830,6 +2019,59 @@ func (q *Queries) ListMessagesByPriority(ctx context.Context, arg ListMessagesBy
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListSnoozedRecipients = `-- name: ListSnoozedRecipients :many
+SELECT mr.message_id, mr.agent_id, mr.snoozed_until, mr.read_at,
+    mr.acked_at, m.thread_id
+FROM message_recipients mr
+JOIN messages m ON mr.message_id = m.id
+WHERE mr.state = 'snoozed'
+    AND mr.snoozed_until IS NOT NULL
+ORDER BY mr.snoozed_until ASC
+`
+
+type ListSnoozedRecipientsRow struct {
+	MessageID    int64
+	AgentID      int64
+	SnoozedUntil sql.NullInt64
+	ReadAt       sql.NullInt64
+	AckedAt      sql.NullInt64
+	ThreadID     string
+}
+
+// List every snoozed recipient that has a pending wake time along with the
+// message's thread. Used on daemon startup to re-arm wake timers.
+func (q *Queries) ListSnoozedRecipients(ctx context.Context) ([]ListSnoozedRecipientsRow, error) {
+	rows, err := q.db.QueryContext(ctx, ListSnoozedRecipients)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSnoozedRecipientsRow
+	for rows.Next() {
+		var i ListSnoozedRecipientsRow
+		if err := rows.Scan(
+			&i.MessageID,
+			&i.AgentID,
+			&i.SnoozedUntil,
+			&i.ReadAt,
+			&i.AckedAt,
+			&i.ThreadID,
 		); err != nil {
 			return nil, err
 		}
@@ -1858,8 +2100,38 @@ func (q *Queries) MarkMessageDeletedBySender(ctx context.Context, arg MarkMessag
 	return err
 }
 
+const PersistRecipientState = `-- name: PersistRecipientState :exec
+UPDATE message_recipients
+SET state = ?, read_at = ?, acked_at = ?, snoozed_until = ?
+WHERE message_id = ? AND agent_id = ?
+`
+
+type PersistRecipientStateParams struct {
+	State        string
+	ReadAt       sql.NullInt64
+	AckedAt      sql.NullInt64
+	SnoozedUntil sql.NullInt64
+	MessageID    int64
+	AgentID      int64
+}
+
+// Persist a recipient state transition emitted by the thread FSM. All
+// timestamp columns are written so snoozed_until is cleared whenever the
+// recipient leaves the snoozed state.
+func (q *Queries) PersistRecipientState(ctx context.Context, arg PersistRecipientStateParams) error {
+	_, err := q.db.ExecContext(ctx, PersistRecipientState,
+		arg.State,
+		arg.ReadAt,
+		arg.AckedAt,
+		arg.SnoozedUntil,
+		arg.MessageID,
+		arg.AgentID,
+	)
+	return err
+}
+
 const SearchMessages = `-- name: SearchMessages :many
-SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
+SELECT m.id, m.thread_id, m.topic_id, m.log_offset, m.sender_id, m.subject, m.body_md, m.priority, m.deadline_at, m.attachments, m.created_at, m.deleted_by_sender, m.metadata, m.idempotency_key, m.legal_hold, m.edited_at, a.name as sender_name, a.project_key as sender_project_key, a.git_branch as sender_git_branch
 FROM messages m
 LEFT JOIN agents a ON m.sender_id = a.id
 WHERE m.subject LIKE ? OR m.body_md LIKE ?
@@ -1887,6 +2159,8 @@ type SearchMessagesRow struct {
 	DeletedBySender  int64
 	Metadata         sql.NullString
 	IdempotencyKey   sql.NullString
+	LegalHold        int64
+	EditedAt         sql.NullInt64
 	SenderName       sql.NullString
 	SenderProjectKey sql.NullString
 	SenderGitBranch  sql.NullString
@@ -1918,6 +2192,8 @@ func (q *Queries) SearchMessages(ctx context.Context, arg SearchMessagesParams)
 			&i.DeletedBySender,
 			&i.Metadata,
 			&i.IdempotencyKey,
+			&i.LegalHold,
+			&i.EditedAt,
 			&i.SenderName,
 			&i.SenderProjectKey,
 			&i.SenderGitBranch,
@@ -1956,6 +2232,20 @@ func (q *Queries) UpdateAllThreadRecipientState(ctx context.Context, arg UpdateA
 	return result.RowsAffected()
 }
 
+const UpdateMessagePriority = `-- name: UpdateMessagePriority :exec
+UPDATE messages SET priority = ? WHERE id = ?
+`
+
+type UpdateMessagePriorityParams struct {
+	Priority string
+	ID       int64
+}
+
+func (q *Queries) UpdateMessagePriority(ctx context.Context, arg UpdateMessagePriorityParams) error {
+	_, err := q.db.ExecContext(ctx, UpdateMessagePriority, arg.Priority, arg.ID)
+	return err
+}
+
 const UpdateRecipientAcked = `-- name: UpdateRecipientAcked :exec
 UPDATE message_recipients
 SET acked_at = ?
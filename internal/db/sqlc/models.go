@@ -28,6 +28,23 @@ type Agent struct {
 	Purpose          sql.NullString
 	WorkingDir       sql.NullString
 	Hostname         sql.NullString
+	MaxConcurrency   int64
+}
+
+type AgentCapability struct {
+	AgentID int64
+	Kind    string
+	Name    string
+}
+
+type AgentLimit struct {
+	ID              int64
+	AgentID         sql.NullInt64
+	Class           string
+	Amount          int64
+	IntervalSeconds int64
+	Burst           int64
+	UpdatedAt       int64
 }
 
 type AgentSummary struct {
@@ -61,6 +78,11 @@ type AgentTask struct {
 	FileMtime    sql.NullInt64
 }
 
+type AgentTasksFt struct {
+	Subject     string
+	Description string
+}
+
 type AvailableTask struct {
 	ID           int64
 	AgentID      int64
@@ -82,6 +104,18 @@ type AvailableTask struct {
 	FileMtime    sql.NullInt64
 }
 
+type CapabilityRoute struct {
+	ID              int64
+	Capability      string
+	AgentID         int64
+	MessageID       sql.NullInt64
+	TaskID          sql.NullInt64
+	Candidates      int64
+	UnreadCount     int64
+	InProgressCount int64
+	CreatedAt       int64
+}
+
 type ConsumerOffset struct {
 	AgentID    int64
 	TopicID    int64
@@ -89,6 +123,28 @@ type ConsumerOffset struct {
 	UpdatedAt  int64
 }
 
+type Credential struct {
+	ID             int64
+	AgentID        sql.NullInt64
+	Name           string
+	TokenHash      string
+	AllowedMethods string
+	ExpiresAt      sql.NullInt64
+	CreatedAt      int64
+	LastUsedAt     sql.NullInt64
+	RevokedAt      sql.NullInt64
+}
+
+type DeadLetter struct {
+	ID        int64
+	MessageID int64
+	SenderID  int64
+	Recipient string
+	Status    string
+	Reason    string
+	CreatedAt int64
+}
+
 type DiffAnnotation struct {
 	ID             int64
 	AnnotationID   string
@@ -107,6 +163,43 @@ type DiffAnnotation struct {
 	UpdatedAt      int64
 }
 
+type DiffAnnotationsFt struct {
+	Text     string
+	FilePath string
+}
+
+type InboxRule struct {
+	ID             int64
+	AgentID        int64
+	Name           string
+	Enabled        int64
+	StopProcessing int64
+	SenderName     string
+	SenderPrefix   string
+	SubjectPattern string
+	TopicName      string
+	Priority       string
+	MetadataKey    string
+	MetadataValue  string
+	Archive        int64
+	Star           int64
+	Snooze         string
+	Category       string
+	ForwardTo      string
+	MatchCount     int64
+	LastMatchedAt  sql.NullInt64
+	CreatedAt      int64
+	UpdatedAt      int64
+	Label          string
+}
+
+type Label struct {
+	ID        int64
+	AgentID   int64
+	Name      string
+	CreatedAt int64
+}
+
 type Message struct {
 	ID              int64
 	ThreadID        string
@@ -122,6 +215,30 @@ type Message struct {
 	DeletedBySender int64
 	Metadata        sql.NullString
 	IdempotencyKey  sql.NullString
+	LegalHold       int64
+	EditedAt        sql.NullInt64
+}
+
+type MessageEmbedding struct {
+	MessageID  int64
+	Model      string
+	Dimensions int64
+	Embedding  []byte
+	CreatedAt  int64
+}
+
+type MessageEscalation struct {
+	MessageID       int64
+	AgentID         int64
+	Level           int64
+	LastEscalatedAt int64
+}
+
+type MessageLabel struct {
+	MessageID int64
+	AgentID   int64
+	LabelID   int64
+	CreatedAt int64
 }
 
 type MessageRecipient struct {
@@ -131,6 +248,23 @@ type MessageRecipient struct {
 	SnoozedUntil sql.NullInt64
 	ReadAt       sql.NullInt64
 	AckedAt      sql.NullInt64
+	Category     string
+}
+
+type MessageRecipientExpansion struct {
+	MessageID int64
+	Address   string
+	AgentID   int64
+}
+
+type MessageRevision struct {
+	ID         int64
+	MessageID  int64
+	Revision   int64
+	Subject    string
+	BodyMd     string
+	CreatedAt  int64
+	ReplacedAt int64
 }
 
 type MessagesFt struct {
@@ -168,6 +302,11 @@ type PlanAnnotation struct {
 	UpdatedAt      int64
 }
 
+type PlanAnnotationsFt struct {
+	Text         string
+	OriginalText string
+}
+
 type PlanReview struct {
 	ID              int64
 	PlanReviewID    string
@@ -187,6 +326,68 @@ type PlanReview struct {
 	ReviewedAt      sql.NullInt64
 }
 
+type PlanReviewsFt struct {
+	PlanTitle   string
+	PlanSummary string
+}
+
+type Question struct {
+	ID              int64
+	QuestionID      string
+	MessageID       int64
+	ThreadID        string
+	AskerID         int64
+	Question        string
+	Options         string
+	DefaultAnswer   string
+	TimeoutSeconds  int64
+	TimeoutAt       sql.NullInt64
+	Fallback        string
+	EscalatedAt     sql.NullInt64
+	State           string
+	Answer          string
+	AnsweredBy      sql.NullInt64
+	AnswerMessageID sql.NullInt64
+	AnsweredAt      sql.NullInt64
+	CreatedAt       int64
+	UpdatedAt       int64
+}
+
+type QueueDelivery struct {
+	MessageID      int64
+	TopicID        int64
+	State          string
+	ClaimedBy      sql.NullInt64
+	LeaseExpiresAt sql.NullInt64
+	DeliveryCount  int64
+	LastError      sql.NullString
+	CreatedAt      int64
+	UpdatedAt      int64
+}
+
+type RateLimitBucket struct {
+	AgentID         int64
+	Class           string
+	Tokens          float64
+	UpdatedAtMs     int64
+	ThrottledCount  int64
+	LastThrottledAt sql.NullInt64
+}
+
+type RecipientGroup struct {
+	ID          int64
+	Name        string
+	Description string
+	CreatedBy   sql.NullInt64
+	CreatedAt   int64
+}
+
+type RecipientGroupMember struct {
+	GroupID int64
+	AgentID int64
+	AddedAt int64
+}
+
 type Review struct {
 	ID          int64
 	ReviewID    string
@@ -226,6 +427,11 @@ type ReviewIssue struct {
 	CreatedAt           int64
 }
 
+type ReviewIssuesFt struct {
+	Title       string
+	Description string
+}
+
 type ReviewIteration struct {
 	ID                int64
 	ReviewID          string
@@ -244,6 +450,59 @@ type ReviewIteration struct {
 	CompletedAt       sql.NullInt64
 }
 
+type SavedSearch struct {
+	ID        int64
+	AgentID   int64
+	Name      string
+	Query     string
+	CreatedAt int64
+	UpdatedAt int64
+}
+
+type Schedule struct {
+	ID            int64
+	Name          string
+	SenderID      int64
+	Recipients    string
+	TopicName     string
+	Subject       string
+	Body          string
+	Priority      string
+	CronExpr      string
+	Timezone      string
+	CatchUp       string
+	Paused        int64
+	NextRunAt     int64
+	LastRunAt     sql.NullInt64
+	LastMessageID sql.NullInt64
+	LastError     string
+	RunCount      int64
+	CreatedAt     int64
+	UpdatedAt     int64
+}
+
+type ScheduledMessage struct {
+	ID             int64
+	SenderID       int64
+	Recipients     string
+	TopicName      string
+	ThreadID       string
+	Subject        string
+	Body           string
+	Priority       string
+	DeadlineAt     sql.NullInt64
+	Attachments    string
+	DeliveryMode   string
+	IdempotencyKey string
+	SendAt         int64
+	State          string
+	MessageID      sql.NullInt64
+	LastError      string
+	CreatedAt      int64
+	UpdatedAt      int64
+	Metadata       string
+}
+
 type SessionIdentity struct {
 	SessionID    string
 	AgentID      int64
@@ -269,6 +528,15 @@ type TaskList struct {
 	LastSyncedAt sql.NullInt64
 }
 
+type Thread struct {
+	ThreadID  string
+	AgentID   int64
+	Muted     int64
+	Following int64
+	CreatedAt int64
+	UpdatedAt int64
+}
+
 type Topic struct {
 	ID               int64
 	Name             string
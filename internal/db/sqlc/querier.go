@@ -10,12 +10,31 @@ import (
 )
 
 type Querier interface {
+	// Complete a lease. Only the current lease holder may ack, and only until
+	// the message has been released or claimed by someone else.
+	AckQueueDelivery(ctx context.Context, arg AckQueueDeliveryParams) (int64, error)
+	// Apply a label to a recipient's copy of a message. Applying a label twice
+	// is a no-op.
+	AddMessageLabel(ctx context.Context, arg AddMessageLabelParams) error
+	AddRecipientGroupMember(ctx context.Context, arg AddRecipientGroupMemberParams) error
+	// Move a schedule on to its next occurrence without recording a run, for
+	// occurrences that were skipped.
+	AdvanceSchedule(ctx context.Context, arg AdvanceScheduleParams) error
+	CancelScheduledMessage(ctx context.Context, arg CancelScheduledMessageParams) (int64, error)
+	// Lease the oldest claimable message on a queue topic to an agent. A
+	// message is claimable when it is pending, or when its previous lease has
+	// expired, and it still has delivery attempts left.
+	ClaimNextQueueDelivery(ctx context.Context, arg ClaimNextQueueDeliveryParams) (QueueDelivery, error)
 	ClearAllOperations(ctx context.Context) error
+	// Give a thread the preferences agents set on another, keeping any they
+	// already set on it.
+	CopyThreadPreferences(ctx context.Context, arg CopyThreadPreferencesParams) error
 	CountActivitiesByAgentToday(ctx context.Context, arg CountActivitiesByAgentTodayParams) (int64, error)
 	CountArchivedByAgent(ctx context.Context, agentID int64) (int64, error)
 	// Returns total counts per inbox category for an agent, excluding
 	// archived and trashed messages. Drives tab labels and stats in the
-	// web UI without forcing a second round trip.
+	// web UI without forcing a second round trip. A category set by an
+	// inbox rule takes precedence over the one derived from the subject.
 	CountInboxCategories(ctx context.Context, agentID int64) (CountInboxCategoriesRow, error)
 	CountOpenIssues(ctx context.Context, reviewID string) (int64, error)
 	CountPendingOperations(ctx context.Context) (int64, error)
@@ -24,23 +43,43 @@ type Querier interface {
 	CountReviewsByState(ctx context.Context, state string) (int64, error)
 	CountSentByAgent(ctx context.Context, senderID int64) (int64, error)
 	CountSnoozedByAgent(ctx context.Context, agentID int64) (int64, error)
+	// Count consumer offsets left behind by agents that are no longer
+	// subscribed to the topic.
+	CountStaleConsumerOffsets(ctx context.Context) (int64, error)
 	CountStarredByAgent(ctx context.Context, agentID int64) (int64, error)
 	CountSubscribersByTopic(ctx context.Context, topicID int64) (int64, error)
 	// Task count query for list
 	CountTasksByList(ctx context.Context, listID string) (int64, error)
+	CountThreadMessages(ctx context.Context, threadID string) (int64, error)
+	// Count the code reviews and plan reviews that track a thread.
+	CountThreadReviews(ctx context.Context, threadID string) (int64, error)
 	CountUnreadByAgent(ctx context.Context, agentID int64) (int64, error)
 	CountUnreadUrgentByAgent(ctx context.Context, agentID int64) (int64, error)
 	CreateActivity(ctx context.Context, arg CreateActivityParams) (Activity, error)
 	CreateAgent(ctx context.Context, arg CreateAgentParams) (Agent, error)
+	CreateAgentCapability(ctx context.Context, arg CreateAgentCapabilityParams) error
+	CreateAgentLimit(ctx context.Context, arg CreateAgentLimitParams) (AgentLimit, error)
 	CreateAgentSummary(ctx context.Context, arg CreateAgentSummaryParams) (AgentSummary, error)
+	CreateCapabilityRoute(ctx context.Context, arg CreateCapabilityRouteParams) (CapabilityRoute, error)
+	CreateCredential(ctx context.Context, arg CreateCredentialParams) (Credential, error)
+	CreateDeadLetter(ctx context.Context, arg CreateDeadLetterParams) error
 	CreateDiffAnnotation(ctx context.Context, arg CreateDiffAnnotationParams) (DiffAnnotation, error)
+	CreateInboxRule(ctx context.Context, arg CreateInboxRuleParams) (InboxRule, error)
+	CreateLabel(ctx context.Context, arg CreateLabelParams) (Label, error)
 	CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error)
 	CreateMessageRecipient(ctx context.Context, arg CreateMessageRecipientParams) error
+	CreateMessageRevision(ctx context.Context, arg CreateMessageRevisionParams) (MessageRevision, error)
 	CreatePlanAnnotation(ctx context.Context, arg CreatePlanAnnotationParams) (PlanAnnotation, error)
 	CreatePlanReview(ctx context.Context, arg CreatePlanReviewParams) (PlanReview, error)
+	CreateQuestion(ctx context.Context, arg CreateQuestionParams) (Question, error)
+	CreateQueueDelivery(ctx context.Context, arg CreateQueueDeliveryParams) error
+	CreateRecipientExpansion(ctx context.Context, arg CreateRecipientExpansionParams) error
+	CreateRecipientGroup(ctx context.Context, arg CreateRecipientGroupParams) (RecipientGroup, error)
 	CreateReview(ctx context.Context, arg CreateReviewParams) (Review, error)
 	CreateReviewIssue(ctx context.Context, arg CreateReviewIssueParams) (ReviewIssue, error)
 	CreateReviewIteration(ctx context.Context, arg CreateReviewIterationParams) (ReviewIteration, error)
+	CreateSchedule(ctx context.Context, arg CreateScheduleParams) (Schedule, error)
+	CreateScheduledMessage(ctx context.Context, arg CreateScheduledMessageParams) (ScheduledMessage, error)
 	CreateSessionIdentity(ctx context.Context, arg CreateSessionIdentityParams) error
 	CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) error
 	// Task CRUD queries
@@ -48,9 +87,22 @@ type Querier interface {
 	// Task list registration queries
 	CreateTaskList(ctx context.Context, arg CreateTaskListParams) (TaskList, error)
 	CreateTopic(ctx context.Context, arg CreateTopicParams) (Topic, error)
+	// Push a pending scheduled message's send time back, recording why.
+	DeferScheduledMessage(ctx context.Context, arg DeferScheduledMessageParams) error
 	DeleteAgent(ctx context.Context, id int64) error
+	DeleteAgentCapabilities(ctx context.Context, agentID int64) error
+	// Delete an agent's limit for a class, or the default if agent_id is NULL.
+	DeleteAgentLimit(ctx context.Context, arg DeleteAgentLimitParams) (int64, error)
+	DeleteDeadLetter(ctx context.Context, arg DeleteDeadLetterParams) (int64, error)
 	DeleteDiffAnnotation(ctx context.Context, annotationID string) error
 	DeleteDiffAnnotationsByMessage(ctx context.Context, messageID int64) error
+	// Delete a batch of messages that have outlived their topic's retention
+	// window and aren't protected (see SummarizeExpiredMessages). Recipient
+	// rows, escalations, queue deliveries and annotations cascade, and the FTS
+	// index is kept in sync by the messages_ad trigger.
+	DeleteExpiredMessages(ctx context.Context, arg DeleteExpiredMessagesParams) (int64, error)
+	DeleteInboxRule(ctx context.Context, arg DeleteInboxRuleParams) (int64, error)
+	DeleteLabel(ctx context.Context, arg DeleteLabelParams) (int64, error)
 	DeleteMessage(ctx context.Context, id int64) error
 	DeleteMessagesByTopicOlderThan(ctx context.Context, arg DeleteMessagesByTopicOlderThanParams) (int64, error)
 	DeleteOldActivities(ctx context.Context, createdAt int64) error
@@ -58,19 +110,29 @@ type Querier interface {
 	DeletePlanAnnotation(ctx context.Context, annotationID string) error
 	DeletePlanAnnotationsByReview(ctx context.Context, planReviewID string) error
 	DeletePlanReview(ctx context.Context, planReviewID string) error
+	DeleteRecipientGroup(ctx context.Context, id int64) (int64, error)
 	DeleteReview(ctx context.Context, reviewID string) error
 	DeleteReviewIssues(ctx context.Context, reviewID string) error
 	DeleteReviewIterations(ctx context.Context, reviewID string) error
+	DeleteSavedSearch(ctx context.Context, arg DeleteSavedSearchParams) (int64, error)
+	DeleteSchedule(ctx context.Context, arg DeleteScheduleParams) (int64, error)
 	DeleteSessionIdentity(ctx context.Context, sessionID string) error
+	DeleteStaleConsumerOffsets(ctx context.Context) (int64, error)
 	DeleteSubscription(ctx context.Context, arg DeleteSubscriptionParams) error
 	// Cleanup queries
 	DeleteTask(ctx context.Context, id int64) error
 	DeleteTaskList(ctx context.Context, listID string) error
 	DeleteTasksByList(ctx context.Context, listID string) error
+	DeleteThreadPreference(ctx context.Context, arg DeleteThreadPreferenceParams) (int64, error)
+	DeleteThreadPreferences(ctx context.Context, threadID string) error
 	DeleteTopic(ctx context.Context, id int64) error
 	DiscoverAgents(ctx context.Context) ([]DiscoverAgentsRow, error)
 	DrainPendingOperations(ctx context.Context) ([]PendingOperation, error)
 	EnqueueOperation(ctx context.Context, arg EnqueueOperationParams) (PendingOperation, error)
+	// Record that a question was escalated to its fallback recipient and start
+	// its next timeout.
+	EscalateQuestion(ctx context.Context, arg EscalateQuestionParams) error
+	ExtendQueueLease(ctx context.Context, arg ExtendQueueLeaseParams) (int64, error)
 	GetActivity(ctx context.Context, id int64) (Activity, error)
 	GetAgent(ctx context.Context, id int64) (Agent, error)
 	GetAgentByName(ctx context.Context, name string) (Agent, error)
@@ -86,20 +148,34 @@ type Querier interface {
 	GetAllTaskStats(ctx context.Context) (GetAllTaskStatsRow, error)
 	GetArchivedMessages(ctx context.Context, arg GetArchivedMessagesParams) ([]GetArchivedMessagesRow, error)
 	GetConsumerOffset(ctx context.Context, arg GetConsumerOffsetParams) (int64, error)
+	// Look up a credential by the hash of its token, with its agent's name.
+	GetCredentialByHash(ctx context.Context, tokenHash string) (GetCredentialByHashRow, error)
 	GetDiffAnnotation(ctx context.Context, annotationID string) (DiffAnnotation, error)
+	// Get the limit that applies to an agent for a class: the agent's own if
+	// it has one, else the default.
+	GetEffectiveAgentLimit(ctx context.Context, arg GetEffectiveAgentLimitParams) (AgentLimit, error)
 	GetInboxMessages(ctx context.Context, arg GetInboxMessagesParams) ([]GetInboxMessagesRow, error)
+	// The messages an agent filed under a label, in any state but trash so
+	// archived messages can still be found by label.
+	GetInboxMessagesByLabel(ctx context.Context, arg GetInboxMessagesByLabelParams) ([]GetInboxMessagesByLabelRow, error)
 	// Hook-generated notification messages (Permission/Idle/Status/
-	// Notification subjects). Used by the Notifications tab in the web UI.
+	// Notification subjects) and messages an inbox rule filed as
+	// notifications. Used by the Notifications tab in the web UI.
 	GetInboxMessagesNotifications(ctx context.Context, arg GetInboxMessagesNotificationsParams) ([]GetInboxMessagesNotificationsRow, error)
 	// Primary inbox view: every non-archived, non-trashed message that
-	// isn't hook-generated chatter. Used by the Primary tab as the
-	// default "real inbox" view.
+	// isn't hook-generated chatter, unless an inbox rule filed it elsewhere.
+	// Used by the Primary tab as the default "real inbox" view.
 	GetInboxMessagesPrimary(ctx context.Context, arg GetInboxMessagesPrimaryParams) ([]GetInboxMessagesPrimaryRow, error)
+	GetInboxRuleByName(ctx context.Context, arg GetInboxRuleByNameParams) (InboxRule, error)
+	GetLabelByName(ctx context.Context, arg GetLabelByNameParams) (Label, error)
 	GetLatestAgentSummary(ctx context.Context, agentID int64) (AgentSummary, error)
 	GetLatestIteration(ctx context.Context, reviewID string) (ReviewIteration, error)
+	GetLatestTaskRoute(ctx context.Context, taskID sql.NullInt64) (GetLatestTaskRouteRow, error)
 	GetMaxLogOffset(ctx context.Context, topicID int64) (interface{}, error)
 	GetMessage(ctx context.Context, id int64) (Message, error)
 	GetMessageByIdempotencyKey(ctx context.Context, idempotencyKey sql.NullString) (Message, error)
+	GetMessageEmbedding(ctx context.Context, arg GetMessageEmbeddingParams) (MessageEmbedding, error)
+	GetMessageEscalation(ctx context.Context, arg GetMessageEscalationParams) (MessageEscalation, error)
 	GetMessageRecipient(ctx context.Context, arg GetMessageRecipientParams) (MessageRecipient, error)
 	GetMessageRecipients(ctx context.Context, messageID int64) ([]MessageRecipient, error)
 	// Fetch recipients for multiple messages at once with agent names.
@@ -117,13 +193,20 @@ type Querier interface {
 	GetOpenReviewIssues(ctx context.Context, reviewID string) ([]ReviewIssue, error)
 	GetOrCreateAgentInboxTopic(ctx context.Context, arg GetOrCreateAgentInboxTopicParams) (Topic, error)
 	GetOrCreateTopic(ctx context.Context, arg GetOrCreateTopicParams) (Topic, error)
+	// Inbox view of unacked messages whose deadline has passed, most overdue
+	// first. An agent_id of 0 returns overdue messages across all agents.
+	GetOverdueMessages(ctx context.Context, arg GetOverdueMessagesParams) ([]GetOverdueMessagesRow, error)
 	GetPlanAnnotation(ctx context.Context, annotationID string) (PlanAnnotation, error)
 	GetPlanReview(ctx context.Context, planReviewID string) (PlanReview, error)
 	GetPlanReviewByID(ctx context.Context, id int64) (PlanReview, error)
 	GetPlanReviewByMessage(ctx context.Context, messageID sql.NullInt64) (PlanReview, error)
 	GetPlanReviewBySession(ctx context.Context, sessionID sql.NullString) (PlanReview, error)
 	GetPlanReviewByThread(ctx context.Context, threadID string) (PlanReview, error)
+	GetQuestion(ctx context.Context, questionID string) (Question, error)
+	GetQueueDelivery(ctx context.Context, messageID int64) (QueueDelivery, error)
 	GetQueueStats(ctx context.Context) (GetQueueStatsRow, error)
+	GetRateLimitBucket(ctx context.Context, arg GetRateLimitBucketParams) (RateLimitBucket, error)
+	GetRecipientGroupByName(ctx context.Context, name string) (RecipientGroup, error)
 	GetReview(ctx context.Context, reviewID string) (Review, error)
 	GetReviewByID(ctx context.Context, id int64) (Review, error)
 	GetReviewIssue(ctx context.Context, id int64) (ReviewIssue, error)
@@ -131,6 +214,9 @@ type Querier interface {
 	GetReviewIssuesByIteration(ctx context.Context, arg GetReviewIssuesByIterationParams) ([]ReviewIssue, error)
 	GetReviewIteration(ctx context.Context, arg GetReviewIterationParams) (ReviewIteration, error)
 	GetReviewIterations(ctx context.Context, reviewID string) ([]ReviewIteration, error)
+	GetSavedSearch(ctx context.Context, arg GetSavedSearchParams) (SavedSearch, error)
+	GetScheduleByName(ctx context.Context, arg GetScheduleByNameParams) (Schedule, error)
+	GetScheduledMessageByIdempotencyKey(ctx context.Context, idempotencyKey string) (ScheduledMessage, error)
 	// Get messages sent by a specific agent with sender details.
 	GetSentMessages(ctx context.Context, arg GetSentMessagesParams) ([]GetSentMessagesRow, error)
 	GetSessionIdentity(ctx context.Context, sessionID string) (SessionIdentity, error)
@@ -146,6 +232,7 @@ type Querier interface {
 	// Task statistics queries
 	GetTaskStatsByAgent(ctx context.Context, agentID int64) (GetTaskStatsByAgentRow, error)
 	GetTaskStatsByList(ctx context.Context, listID string) (GetTaskStatsByListRow, error)
+	GetThreadPreference(ctx context.Context, arg GetThreadPreferenceParams) (Thread, error)
 	GetTopic(ctx context.Context, id int64) (Topic, error)
 	GetTopicByName(ctx context.Context, name string) (Topic, error)
 	GetTrashMessages(ctx context.Context, arg GetTrashMessagesParams) ([]GetTrashMessagesRow, error)
@@ -159,27 +246,96 @@ type Querier interface {
 	ListActivitiesByAgent(ctx context.Context, arg ListActivitiesByAgentParams) ([]Activity, error)
 	ListActivitiesByType(ctx context.Context, arg ListActivitiesByTypeParams) ([]Activity, error)
 	ListActivitiesSince(ctx context.Context, arg ListActivitiesSinceParams) ([]Activity, error)
+	ListAgentCapabilities(ctx context.Context, agentID int64) ([]AgentCapability, error)
+	// List the default limits and every agent's, or only the ones that apply
+	// to an agent if agent_id is non-zero.
+	ListAgentLimits(ctx context.Context, agentID int64) ([]ListAgentLimitsRow, error)
 	ListAgents(ctx context.Context) ([]Agent, error)
+	ListAgentsActiveSince(ctx context.Context, lastActiveAt int64) ([]Agent, error)
+	ListAgentsByBranch(ctx context.Context, gitBranch sql.NullString) ([]Agent, error)
+	// List agents whose name matches a LIKE pattern. Callers escape literal
+	// wildcards with a backslash.
+	ListAgentsByNamePattern(ctx context.Context, pattern string) ([]Agent, error)
 	ListAgentsByProject(ctx context.Context, projectKey sql.NullString) ([]Agent, error)
+	// List agents whose project key is the given key, or a path ending in it,
+	// so "@project:subtrate" matches "/home/dev/src/subtrate".
+	ListAgentsMatchingProject(ctx context.Context, project sql.NullString) ([]Agent, error)
+	ListAllAgentCapabilities(ctx context.Context) ([]AgentCapability, error)
 	ListAllTasks(ctx context.Context, arg ListAllTasksParams) ([]AgentTask, error)
 	ListAvailableTasks(ctx context.Context, agentID int64) ([]AgentTask, error)
 	ListBlockedTasks(ctx context.Context, agentID int64) ([]AgentTask, error)
+	// List the agents with a capability, of any kind when kind is empty, along
+	// with the unread mail and in-progress tasks the router weighs them by. A
+	// task counts against its owner, or the agent whose list it is on when it
+	// has none.
+	ListCapabilityCandidates(ctx context.Context, arg ListCapabilityCandidatesParams) ([]ListCapabilityCandidatesRow, error)
+	// List the newest routes, of one capability when capability is empty.
+	ListCapabilityRoutes(ctx context.Context, arg ListCapabilityRoutesParams) ([]ListCapabilityRoutesRow, error)
 	ListConsumerOffsetsByAgent(ctx context.Context, agentID int64) ([]ListConsumerOffsetsByAgentRow, error)
+	// List credentials, newest first, optionally only an agent's and
+	// optionally including revoked ones.
+	ListCredentials(ctx context.Context, arg ListCredentialsParams) ([]ListCredentialsRow, error)
+	// List a sender's dead letters, newest first, along with the message they
+	// belong to.
+	ListDeadLetters(ctx context.Context, arg ListDeadLettersParams) ([]ListDeadLettersRow, error)
 	ListDiffAnnotationsByMessage(ctx context.Context, messageID int64) ([]DiffAnnotation, error)
+	// List pending scheduled messages whose send time has passed, oldest first.
+	ListDueScheduledMessages(ctx context.Context, arg ListDueScheduledMessagesParams) ([]ScheduledMessage, error)
+	// List active schedules whose next run has come due, oldest first.
+	ListDueSchedules(ctx context.Context, arg ListDueSchedulesParams) ([]Schedule, error)
+	// List messages that have used up their delivery attempts without being
+	// acked, along with their topic name.
+	ListExhaustedQueueDeliveries(ctx context.Context, arg ListExhaustedQueueDeliveriesParams) ([]ListExhaustedQueueDeliveriesRow, error)
+	// List leases that expired without being acked, so their holders' copies of
+	// the messages can be archived before they're redelivered.
+	ListExpiredQueueLeases(ctx context.Context, now sql.NullInt64) ([]QueueDelivery, error)
 	ListInProgressTasks(ctx context.Context, agentID int64) ([]AgentTask, error)
+	// List an agent's rules in the order they are applied.
+	ListInboxRules(ctx context.Context, agentID int64) ([]InboxRule, error)
+	// List an agent's labels by name, with how many of the messages carrying
+	// each are in the agent's inbox and how many of those are unread.
+	ListLabels(ctx context.Context, agentID int64) ([]ListLabelsRow, error)
+	// The labels an agent applied to each of a batch of messages, by name.
+	ListMessageLabels(ctx context.Context, arg ListMessageLabelsParams) ([]ListMessageLabelsRow, error)
+	ListMessageRevisions(ctx context.Context, messageID int64) ([]MessageRevision, error)
 	ListMessagesByPriority(ctx context.Context, arg ListMessagesByPriorityParams) ([]Message, error)
+	// List the oldest messages without an embedding under a model.
+	ListMessagesToEmbed(ctx context.Context, arg ListMessagesToEmbedParams) ([]Message, error)
+	// List recipients of unacked messages whose next escalation level is due,
+	// most overdue first. level_delays is a JSON array of each level's delay
+	// past the deadline in seconds, so recipients still waiting on their next
+	// level don't crowd newly overdue ones out of the batch.
+	ListOverdueRecipients(ctx context.Context, arg ListOverdueRecipientsParams) ([]ListOverdueRecipientsRow, error)
 	ListPendingOperations(ctx context.Context) ([]PendingOperation, error)
+	// List the questions in a thread that are still waiting for an answer.
+	ListPendingQuestionsByThread(ctx context.Context, threadID string) ([]Question, error)
 	ListPendingTasks(ctx context.Context, agentID int64) ([]AgentTask, error)
 	ListPlanAnnotationsByReview(ctx context.Context, planReviewID string) ([]PlanAnnotation, error)
 	ListPlanReviews(ctx context.Context, arg ListPlanReviewsParams) ([]PlanReview, error)
 	ListPlanReviewsByRequester(ctx context.Context, arg ListPlanReviewsByRequesterParams) ([]PlanReview, error)
 	ListPlanReviewsByState(ctx context.Context, arg ListPlanReviewsByStateParams) ([]PlanReview, error)
+	ListRateLimitBuckets(ctx context.Context, agentID int64) ([]RateLimitBucket, error)
+	// An agent's most recently received messages in any state but trash, for
+	// trying inbox rules against.
+	ListReceivedMessages(ctx context.Context, arg ListReceivedMessagesParams) ([]ListReceivedMessagesRow, error)
 	ListRecentActivities(ctx context.Context, limit int64) ([]Activity, error)
 	ListRecentCompletedTasks(ctx context.Context, arg ListRecentCompletedTasksParams) ([]AgentTask, error)
+	ListRecipientExpansions(ctx context.Context, messageID int64) ([]ListRecipientExpansionsRow, error)
+	ListRecipientGroupMembers(ctx context.Context, groupID int64) ([]Agent, error)
+	ListRecipientGroups(ctx context.Context) ([]ListRecipientGroupsRow, error)
 	ListReviews(ctx context.Context, arg ListReviewsParams) ([]Review, error)
 	ListReviewsByRequester(ctx context.Context, arg ListReviewsByRequesterParams) ([]Review, error)
 	ListReviewsByState(ctx context.Context, arg ListReviewsByStateParams) ([]Review, error)
+	ListSavedSearches(ctx context.Context, agentID int64) ([]SavedSearch, error)
+	// List a sender's scheduled messages that have not been sent or canceled,
+	// soonest first.
+	ListScheduledMessages(ctx context.Context, arg ListScheduledMessagesParams) ([]ScheduledMessage, error)
+	// List an agent's schedules by name.
+	ListSchedules(ctx context.Context, senderID int64) ([]Schedule, error)
 	ListSessionIdentitiesByAgent(ctx context.Context, agentID int64) ([]SessionIdentity, error)
+	// List every snoozed recipient that has a pending wake time along with the
+	// message's thread. Used on daemon startup to re-arm wake timers.
+	ListSnoozedRecipients(ctx context.Context) ([]ListSnoozedRecipientsRow, error)
 	ListSubscriptionsByAgent(ctx context.Context, agentID int64) ([]Topic, error)
 	ListSubscriptionsByTopic(ctx context.Context, topicID int64) ([]Agent, error)
 	ListTaskLists(ctx context.Context) ([]TaskList, error)
@@ -189,29 +345,92 @@ type Querier interface {
 	ListTasksByAgentWithLimit(ctx context.Context, arg ListTasksByAgentWithLimitParams) ([]AgentTask, error)
 	ListTasksByList(ctx context.Context, listID string) ([]AgentTask, error)
 	ListTasksByStatus(ctx context.Context, arg ListTasksByStatusParams) ([]AgentTask, error)
+	// Get the revisions of every edited message in a thread.
+	ListThreadMessageRevisions(ctx context.Context, threadID string) ([]MessageRevision, error)
+	// List the threads an agent muted or follows, most recently changed first.
+	ListThreadPreferences(ctx context.Context, agentID int64) ([]Thread, error)
+	ListThreadPreferencesByThread(ctx context.Context, threadID string) ([]Thread, error)
+	// List pending questions whose current timeout has passed, oldest first.
+	ListTimedOutQuestions(ctx context.Context, arg ListTimedOutQuestionsParams) ([]Question, error)
 	ListTopics(ctx context.Context) ([]Topic, error)
 	ListTopicsByType(ctx context.Context, topicType string) ([]Topic, error)
 	ListTopicsWithMessageCount(ctx context.Context) ([]ListTopicsWithMessageCountRow, error)
 	MarkMessageDeletedBySender(ctx context.Context, arg MarkMessageDeletedBySenderParams) error
 	MarkOperationDelivered(ctx context.Context, id int64) error
 	MarkOperationFailed(ctx context.Context, arg MarkOperationFailedParams) error
+	MarkQueueDeliveryDeadLettered(ctx context.Context, arg MarkQueueDeliveryDeadLetteredParams) error
+	MarkScheduledMessageFailed(ctx context.Context, arg MarkScheduledMessageFailedParams) error
+	MarkScheduledMessageSent(ctx context.Context, arg MarkScheduledMessageSentParams) error
 	MarkTasksDeletedByList(ctx context.Context, arg MarkTasksDeletedByListParams) error
+	// Point messages still waiting to be sent into a thread at another.
+	MoveScheduledThreadMessages(ctx context.Context, arg MoveScheduledThreadMessagesParams) error
+	// Move the messages of a thread from a given message on into another
+	// thread. A min_message_id of zero moves the whole thread.
+	MoveThreadMessages(ctx context.Context, arg MoveThreadMessagesParams) (int64, error)
+	// Move the questions asked by the messages MoveThreadMessages moves.
+	MoveThreadQuestions(ctx context.Context, arg MoveThreadQuestionsParams) error
+	// Merge the FTS index segments left fragmented by bulk deletes.
+	OptimizeMessagesFTS(ctx context.Context) error
+	// Persist a recipient state transition emitted by the thread FSM. All
+	// timestamp columns are written so snoozed_until is cleared whenever the
+	// recipient leaves the snoozed state.
+	PersistRecipientState(ctx context.Context, arg PersistRecipientStateParams) error
 	PruneOldTasks(ctx context.Context, completedAt sql.NullInt64) error
 	PurgeExpiredOperations(ctx context.Context, expiresAt int64) (int64, error)
+	// Delete a message its sender wants back, as long as no recipient has read
+	// or otherwise acted on it yet. Messages under a legal hold or backing a
+	// plan review are kept.
+	RecallMessage(ctx context.Context, arg RecallMessageParams) (int64, error)
+	RecordInboxRuleMatch(ctx context.Context, arg RecordInboxRuleMatchParams) error
+	// Record a run of a schedule and move it on to its next occurrence.
+	RecordScheduleRun(ctx context.Context, arg RecordScheduleRunParams) error
+	// Return every expired lease that still has delivery attempts left to the
+	// pending state.
+	ReleaseExpiredQueueLeases(ctx context.Context, arg ReleaseExpiredQueueLeasesParams) (int64, error)
+	// Hand a leased message back to the queue so it can be redelivered.
+	ReleaseQueueDelivery(ctx context.Context, arg ReleaseQueueDeliveryParams) (int64, error)
+	RemoveMessageLabel(ctx context.Context, arg RemoveMessageLabelParams) (int64, error)
+	RemoveRecipientGroupMember(ctx context.Context, arg RemoveRecipientGroupMemberParams) (int64, error)
+	// Record the answer to a pending question. Questions that were resolved in
+	// the meantime are left alone.
+	ResolveQuestion(ctx context.Context, arg ResolveQuestionParams) (int64, error)
 	ResolveReviewID(ctx context.Context, dollar_1 sql.NullString) (string, error)
+	RevokeCredential(ctx context.Context, arg RevokeCredentialParams) (int64, error)
 	SearchAgents(ctx context.Context, arg SearchAgentsParams) ([]Agent, error)
 	// Simple LIKE-based search on subject and body. FTS5 is available but this
 	// covers basic cases. The search term should be passed with wildcards.
 	SearchMessages(ctx context.Context, arg SearchMessagesParams) ([]SearchMessagesRow, error)
+	SetInboxRuleEnabled(ctx context.Context, arg SetInboxRuleEnabledParams) (int64, error)
+	SetMessageLegalHold(ctx context.Context, arg SetMessageLegalHoldParams) (int64, error)
+	// File a message under an inbox category for one recipient.
+	SetRecipientCategory(ctx context.Context, arg SetRecipientCategoryParams) error
+	SetSchedulePaused(ctx context.Context, arg SetSchedulePausedParams) (int64, error)
+	SetThreadLegalHold(ctx context.Context, arg SetThreadLegalHoldParams) (int64, error)
+	// Sum the size of the message bodies an agent sent since a time.
+	SumSenderBodyBytes(ctx context.Context, arg SumSenderBodyBytesParams) (int64, error)
+	// Summarize, per topic, the messages that have outlived their topic's
+	// retention window. Messages that are protected from deletion are counted
+	// as expired but not as deletable: starred messages, messages under a legal
+	// hold, messages in threads with an open review or plan review, plan review
+	// messages, and queue messages that haven't been acked or dead-lettered.
+	SummarizeExpiredMessages(ctx context.Context, now int64) ([]SummarizeExpiredMessagesRow, error)
+	TouchCredential(ctx context.Context, arg TouchCredentialParams) error
 	UpdateAgentDiscoveryInfo(ctx context.Context, arg UpdateAgentDiscoveryInfoParams) error
 	UpdateAgentGitBranch(ctx context.Context, arg UpdateAgentGitBranchParams) error
 	UpdateAgentLastActive(ctx context.Context, arg UpdateAgentLastActiveParams) error
+	UpdateAgentMaxConcurrency(ctx context.Context, arg UpdateAgentMaxConcurrencyParams) error
 	UpdateAgentName(ctx context.Context, arg UpdateAgentNameParams) error
+	UpdateAgentProject(ctx context.Context, arg UpdateAgentProjectParams) error
 	UpdateAgentSession(ctx context.Context, arg UpdateAgentSessionParams) error
 	// Update the state of all message recipients in a thread for ALL agents.
 	// Used for global view archive/trash operations.
 	UpdateAllThreadRecipientState(ctx context.Context, arg UpdateAllThreadRecipientStateParams) (int64, error)
 	UpdateDiffAnnotation(ctx context.Context, arg UpdateDiffAnnotationParams) (DiffAnnotation, error)
+	// Replace a message's subject and body with an edited version. Messages
+	// under a legal hold can't be edited. The messages_au trigger re-indexes
+	// the message for search.
+	UpdateMessageContent(ctx context.Context, arg UpdateMessageContentParams) (int64, error)
+	UpdateMessagePriority(ctx context.Context, arg UpdateMessagePriorityParams) error
 	UpdatePlanAnnotation(ctx context.Context, arg UpdatePlanAnnotationParams) (PlanAnnotation, error)
 	UpdatePlanReviewState(ctx context.Context, arg UpdatePlanReviewStateParams) error
 	UpdateRecipientAcked(ctx context.Context, arg UpdateRecipientAckedParams) error
@@ -229,7 +448,14 @@ type Querier interface {
 	UpdateThreadRecipientState(ctx context.Context, arg UpdateThreadRecipientStateParams) (int64, error)
 	UpdateTopicRetention(ctx context.Context, arg UpdateTopicRetentionParams) error
 	UpsertConsumerOffset(ctx context.Context, arg UpsertConsumerOffsetParams) error
+	// Store a message's embedding under a model, replacing any it had.
+	UpsertMessageEmbedding(ctx context.Context, arg UpsertMessageEmbeddingParams) error
+	UpsertMessageEscalation(ctx context.Context, arg UpsertMessageEscalationParams) error
+	UpsertRateLimitBucket(ctx context.Context, arg UpsertRateLimitBucketParams) error
+	// Save a search under a name, replacing the query of an existing one.
+	UpsertSavedSearch(ctx context.Context, arg UpsertSavedSearchParams) (SavedSearch, error)
 	UpsertTask(ctx context.Context, arg UpsertTaskParams) (AgentTask, error)
+	UpsertThreadPreference(ctx context.Context, arg UpsertThreadPreferenceParams) (Thread, error)
 	WakeSnoozedMessages(ctx context.Context, snoozedUntil sql.NullInt64) (int64, error)
 }
 
@@ -0,0 +1,86 @@
+// Package embed turns text into vectors whose cosine similarity measures how
+// alike in meaning the texts are, for semantic search and duplicate detection.
+package embed
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Vector is an embedding of a text.
+type Vector []float32
+
+// Embedder embeds texts as vectors. Vectors are only comparable with others
+// from the same model.
+type Embedder interface {
+	// Model names the embedding model, and changes whenever the vectors
+	// it produces do.
+	Model() string
+
+	// Embed returns a vector for each of the texts, in order.
+	Embed(ctx context.Context, texts []string) ([]Vector, error)
+}
+
+// Cosine returns the cosine similarity of two vectors, from -1 to 1, or zero
+// if either is empty or their dimensions differ.
+func Cosine(a, b Vector) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / math.Sqrt(normA*normB)
+}
+
+// normalize scales a vector to unit length in place, leaving zero vectors
+// alone.
+func normalize(v Vector) {
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	if norm == 0 {
+		return
+	}
+
+	scale := 1 / math.Sqrt(norm)
+	for i := range v {
+		v[i] = float32(float64(v[i]) * scale)
+	}
+}
+
+// Encode serializes a vector as little-endian float32s.
+func Encode(v Vector) []byte {
+	b := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(x))
+	}
+
+	return b
+}
+
+// Decode deserializes a vector serialized by Encode.
+func Decode(b []byte) (Vector, error) {
+	if len(b)%4 != 0 {
+		return nil, fmt.Errorf("embedding of %d bytes isn't a whole "+
+			"number of float32s", len(b))
+	}
+
+	v := make(Vector, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+
+	return v, nil
+}
@@ -0,0 +1,105 @@
+package embed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHashEmbedder tests that hashed vectors are deterministic unit vectors
+// that place related texts closer than unrelated ones.
+func TestHashEmbedder(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	h := NewHashEmbedder(0)
+	require.Equal(t, "hash-ngram-256", h.Model())
+
+	vectors, err := h.Embed(ctx, []string{
+		"Deploy the API server to staging",
+		"deploying the api server to staging!",
+		"Lunch order for Friday",
+		"",
+	})
+	require.NoError(t, err)
+	require.Len(t, vectors, 4)
+	require.Len(t, vectors[0], DefaultDimensions)
+	require.InDelta(t, 1, Cosine(vectors[0], vectors[0]), 1e-6)
+
+	again, err := h.Embed(ctx, []string{"Deploy the API server to staging"})
+	require.NoError(t, err)
+	require.Equal(t, vectors[0], again[0])
+
+	related := Cosine(vectors[0], vectors[1])
+	unrelated := Cosine(vectors[0], vectors[2])
+	require.Greater(t, related, 0.7)
+	require.Less(t, unrelated, 0.3)
+
+	require.Zero(t, Cosine(vectors[0], vectors[3]))
+	require.Zero(t, Cosine(vectors[0], Vector{1, 0}))
+}
+
+// TestEncode tests that vectors survive serialization.
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	v := Vector{0.5, -1.25, 0, 3e-8}
+	got, err := Decode(Encode(v))
+	require.NoError(t, err)
+	require.Equal(t, v, got)
+
+	_, err = Decode([]byte{1, 2, 3})
+	require.Error(t, err)
+}
+
+// TestHTTPEmbedder tests that the HTTP embedder speaks the OpenAI embeddings
+// API, ordering vectors by their index.
+func TestHTTPEmbedder(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "Bearer secret",
+				r.Header.Get("Authorization"))
+
+			var req embeddingRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			require.NoError(t, err)
+			require.Equal(t, "tiny", req.Model)
+
+			if req.Input[0] == "fail" {
+				http.Error(w, "overloaded",
+					http.StatusServiceUnavailable)
+				return
+			}
+
+			w.Write([]byte(`{"data": [
+				{"index": 1, "embedding": [0, 1]},
+				{"index": 0, "embedding": [1, 0]}
+			]}`))
+		},
+	))
+	defer srv.Close()
+
+	h, err := NewHTTPEmbedder(HTTPConfig{
+		URL:    srv.URL,
+		Model:  "tiny",
+		APIKey: "secret",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "tiny", h.Model())
+
+	vectors, err := h.Embed(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	require.Equal(t, []Vector{{1, 0}, {0, 1}}, vectors)
+
+	_, err = h.Embed(context.Background(), []string{"fail"})
+	require.ErrorContains(t, err, "overloaded")
+
+	_, err = NewHTTPEmbedder(HTTPConfig{URL: srv.URL})
+	require.Error(t, err)
+}
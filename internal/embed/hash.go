@@ -0,0 +1,93 @@
+package embed
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// DefaultDimensions is the number of dimensions HashEmbedder vectors
+	// have by default.
+	DefaultDimensions = 256
+
+	// hashTrigramWeight and hashBigramWeight weigh a word's character
+	// trigrams and pairs of adjacent words against the word itself.
+	hashTrigramWeight = 0.25
+	hashBigramWeight  = 0.5
+)
+
+// HashEmbedder is a deterministic local Embedder. It hashes a text's words,
+// the character trigrams of each word and its pairs of adjacent words into
+// the dimensions of a vector, so texts sharing words, word stems or phrases
+// point the same way. It knows nothing of synonyms, but needs no model or
+// network, which makes it the default and what tests run against.
+type HashEmbedder struct {
+	dims int
+}
+
+// NewHashEmbedder creates a HashEmbedder whose vectors have the given number
+// of dimensions, or DefaultDimensions if it isn't positive.
+func NewHashEmbedder(dims int) *HashEmbedder {
+	if dims <= 0 {
+		dims = DefaultDimensions
+	}
+
+	return &HashEmbedder{dims: dims}
+}
+
+// Model names the embedder and its dimensions.
+func (h *HashEmbedder) Model() string {
+	return fmt.Sprintf("hash-ngram-%d", h.dims)
+}
+
+// Embed returns a unit vector for each of the texts. Texts without words get
+// the zero vector.
+func (h *HashEmbedder) Embed(_ context.Context,
+	texts []string,
+) ([]Vector, error) {
+	vectors := make([]Vector, len(texts))
+	for i, text := range texts {
+		vectors[i] = h.embed(text)
+	}
+
+	return vectors, nil
+}
+
+// embed embeds a single text.
+func (h *HashEmbedder) embed(text string) Vector {
+	v := make(Vector, h.dims)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		h.add(v, "w:"+word, 1)
+
+		padded := []rune("^" + word + "$")
+		for j := 0; j+3 <= len(padded); j++ {
+			h.add(v, "t:"+string(padded[j:j+3]), hashTrigramWeight)
+		}
+
+		if i > 0 {
+			h.add(v, "b:"+words[i-1]+" "+word, hashBigramWeight)
+		}
+	}
+	normalize(v)
+
+	return v
+}
+
+// add adds a feature's weight to the dimension it hashes to. A bit of the
+// hash picks the sign, so that collisions cancel out rather than pile up.
+func (h *HashEmbedder) add(v Vector, feature string, weight float32) {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(feature))
+	sum := hasher.Sum64()
+
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	v[sum%uint64(h.dims)] += weight
+}
@@ -0,0 +1,137 @@
+package embed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultHTTPTimeout bounds each request an HTTPEmbedder makes.
+const DefaultHTTPTimeout = 30 * time.Second
+
+// HTTPConfig configures an HTTPEmbedder.
+type HTTPConfig struct {
+	// URL is the embeddings endpoint, such as
+	// https://api.openai.com/v1/embeddings.
+	URL string
+
+	// Model is the model the endpoint embeds with.
+	Model string
+
+	// APIKey, if set, is sent as a bearer token.
+	APIKey string
+
+	// Client is the HTTP client requests are made with. Defaults to one
+	// with DefaultHTTPTimeout.
+	Client *http.Client
+}
+
+// HTTPEmbedder embeds texts with an external provider speaking the OpenAI
+// embeddings API, which most hosted and self-hosted embedding servers
+// implement.
+type HTTPEmbedder struct {
+	cfg HTTPConfig
+}
+
+// NewHTTPEmbedder creates an HTTPEmbedder for the configured endpoint.
+func NewHTTPEmbedder(cfg HTTPConfig) (*HTTPEmbedder, error) {
+	switch {
+	case cfg.URL == "":
+		return nil, fmt.Errorf("embedding endpoint URL is required")
+
+	case cfg.Model == "":
+		return nil, fmt.Errorf("embedding model is required")
+	}
+
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: DefaultHTTPTimeout}
+	}
+
+	return &HTTPEmbedder{cfg: cfg}, nil
+}
+
+// Model returns the configured model.
+func (h *HTTPEmbedder) Model() string {
+	return h.cfg.Model
+}
+
+// embeddingRequest is the body of an embeddings request.
+type embeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embeddingResponse is the body of an embeddings response.
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed requests the texts' vectors from the endpoint in one batch.
+func (h *HTTPEmbedder) Embed(ctx context.Context,
+	texts []string,
+) ([]Vector, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	body, err := json.Marshal(embeddingRequest{
+		Model: h.cfg.Model,
+		Input: texts,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, h.cfg.URL, bytes.NewReader(body),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedding request: "+
+			"%w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+h.cfg.APIKey)
+	}
+
+	resp, err := h.cfg.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("embedding request failed: %s: %s",
+			resp.Status, bytes.TrimSpace(msg))
+	}
+
+	var parsed embeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("failed to decode embeddings: %w", err)
+	}
+
+	vectors := make([]Vector, len(texts))
+	for _, d := range parsed.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding index %d out of "+
+				"range", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	for i, v := range vectors {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("no embedding returned for "+
+				"text %d", i)
+		}
+	}
+
+	return vectors, nil
+}
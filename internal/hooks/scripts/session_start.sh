@@ -3,7 +3,8 @@
 #
 # This hook runs when a Claude Code session starts. It:
 # 1. Exports CLAUDE_SESSION_ID to the environment (via CLAUDE_ENV_FILE)
-# 2. Sends a heartbeat to mark the agent as active
+# 2. Sends a heartbeat to mark the agent as active, declaring the
+#    capabilities in SUBSTRATE_CAPABILITIES if set
 # 3. Checks for any pending messages to inject as context
 #
 # Output format: plain text for context injection
@@ -18,11 +19,21 @@ if [ -n "$session_id" ] && [ -n "$CLAUDE_ENV_FILE" ]; then
     echo "CLAUDE_SESSION_ID=$session_id" >> "$CLAUDE_ENV_FILE"
 fi
 
+# Declare capabilities for cap: routing when configured, e.g.
+# SUBSTRATE_CAPABILITIES="language:go,repo:roasbeef/substrate,go-review".
+cap_args=()
+if [ -n "$SUBSTRATE_CAPABILITIES" ]; then
+    cap_args+=(--cap "$SUBSTRATE_CAPABILITIES")
+fi
+if [ -n "$SUBSTRATE_MAX_CONCURRENCY" ]; then
+    cap_args+=(--max-concurrency "$SUBSTRATE_MAX_CONCURRENCY")
+fi
+
 # Send heartbeat to mark session start.
 if [ -n "$session_id" ]; then
-    substrate heartbeat --session-start --session-id "$session_id" --format context 2>/dev/null || true
+    substrate heartbeat --session-start --session-id "$session_id" "${cap_args[@]}" --format context 2>/dev/null || true
 else
-    substrate heartbeat --session-start --format context 2>/dev/null || true
+    substrate heartbeat --session-start "${cap_args[@]}" --format context 2>/dev/null || true
 fi
 
 # Poll for new messages (non-blocking).
@@ -0,0 +1,592 @@
+package mail
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+// CapabilityPrefix marks a recipient or task owner as a capability to route
+// to rather than an agent name. "cap:go-review" matches agents declaring
+// go-review under any kind, while "cap:language:go" only matches agents
+// declaring go as a language.
+const CapabilityPrefix = "cap:"
+
+// capabilityNameRE is the set of valid capability names. Besides the
+// characters of group names it allows '+' and '#' for languages such as c++
+// and c#, and '/' for repos such as roasbeef/substrate.
+var capabilityNameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9+#./_-]*$`)
+
+// AgentCapabilities are the capabilities an agent declares, which the router
+// matches mail and tasks addressed to "cap:<name>" against.
+type AgentCapabilities struct {
+	// Languages are the programming languages the agent works in.
+	Languages []string
+
+	// Repos are the repositories the agent knows.
+	Repos []string
+
+	// Tools are the tools the agent has, such as MCP servers.
+	Tools []string
+
+	// Skills are anything else the agent can do, such as
+	// can-review-security or go-review.
+	Skills []string
+
+	// MaxConcurrency is the most tasks the agent takes on at once. The
+	// router passes over agents with that many tasks in progress. 0
+	// means no limit.
+	MaxConcurrency int64
+}
+
+// IsEmpty reports whether no capabilities or limit are declared.
+func (c AgentCapabilities) IsEmpty() bool {
+	return len(c.Languages) == 0 && len(c.Repos) == 0 &&
+		len(c.Tools) == 0 && len(c.Skills) == 0 && c.MaxConcurrency == 0
+}
+
+// Has reports whether the capabilities include the named one, declared
+// under the given kind unless kind is empty.
+func (c AgentCapabilities) Has(kind, name string) bool {
+	kinds := map[string][]string{
+		store.CapabilityLanguage: c.Languages,
+		store.CapabilityRepo:     c.Repos,
+		store.CapabilityTool:     c.Tools,
+		store.CapabilitySkill:    c.Skills,
+	}
+	for k, names := range kinds {
+		if (kind == "" || kind == k) && slices.Contains(names, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate checks that the capabilities are well formed.
+func (c AgentCapabilities) Validate() error {
+	_, err := c.storeCapabilities()
+	return err
+}
+
+// storeCapabilities validates and normalises the capabilities into their
+// stored form. Names are lowercased and duplicates dropped.
+func (c AgentCapabilities) storeCapabilities() ([]store.AgentCapability,
+	error,
+) {
+	if c.MaxConcurrency < 0 {
+		return nil, fmt.Errorf("%w: max concurrency must not be "+
+			"negative", ErrInvalidCapability)
+	}
+
+	kinds := []struct {
+		kind  string
+		names []string
+	}{
+		{store.CapabilityLanguage, c.Languages},
+		{store.CapabilityRepo, c.Repos},
+		{store.CapabilityTool, c.Tools},
+		{store.CapabilitySkill, c.Skills},
+	}
+
+	var caps []store.AgentCapability
+	for _, k := range kinds {
+		for _, name := range k.names {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if !capabilityNameRE.MatchString(name) {
+				return nil, fmt.Errorf("%w: %s %q must be "+
+					"lowercase letters, digits, '+', '#', "+
+					"'.', '/', '_' or '-'",
+					ErrInvalidCapability, k.kind, name)
+			}
+
+			capability := store.AgentCapability{
+				Kind: k.kind,
+				Name: name,
+			}
+			if !slices.Contains(caps, capability) {
+				caps = append(caps, capability)
+			}
+		}
+	}
+
+	return caps, nil
+}
+
+// capabilitiesFromStore groups stored capabilities by kind.
+func capabilitiesFromStore(caps []store.AgentCapability,
+	maxConcurrency int64,
+) AgentCapabilities {
+	result := AgentCapabilities{MaxConcurrency: maxConcurrency}
+	for _, c := range caps {
+		switch c.Kind {
+		case store.CapabilityLanguage:
+			result.Languages = append(result.Languages, c.Name)
+		case store.CapabilityRepo:
+			result.Repos = append(result.Repos, c.Name)
+		case store.CapabilityTool:
+			result.Tools = append(result.Tools, c.Name)
+		case store.CapabilitySkill:
+			result.Skills = append(result.Skills, c.Name)
+		}
+	}
+
+	return result
+}
+
+// IsCapabilityAddress reports whether a recipient or task owner addresses a
+// capability rather than a single agent.
+func IsCapabilityAddress(name string) bool {
+	return strings.HasPrefix(name, CapabilityPrefix)
+}
+
+// ParseCapability parses a capability as written after the "cap:" prefix of
+// an address, or as given to agent discovery, into the kind it is
+// restricted to, if any, and its name. A leading "cap:" is ignored.
+func ParseCapability(capability string) (string, string, error) {
+	capability = strings.ToLower(strings.TrimSpace(
+		strings.TrimPrefix(capability, CapabilityPrefix),
+	))
+
+	kind, name, hasKind := strings.Cut(capability, ":")
+	if !hasKind {
+		kind, name = "", capability
+	}
+
+	switch kind {
+	case "", store.CapabilityLanguage, store.CapabilityRepo,
+		store.CapabilityTool, store.CapabilitySkill:
+
+	default:
+		return "", "", fmt.Errorf("%w: unknown kind %q, want "+
+			"language, repo, tool or skill", ErrInvalidCapability,
+			kind)
+	}
+
+	if !capabilityNameRE.MatchString(name) {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidCapability,
+			capability)
+	}
+
+	return kind, name, nil
+}
+
+// ParseAgentCapabilities parses capabilities written as "kind:name", such
+// as "language:go" or "repo:roasbeef/substrate", into their kinds. A name
+// without a kind is taken as a skill.
+func ParseAgentCapabilities(specs []string) (AgentCapabilities, error) {
+	var caps AgentCapabilities
+	for _, spec := range specs {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+
+		kind, name, err := ParseCapability(spec)
+		if err != nil {
+			return AgentCapabilities{}, err
+		}
+
+		switch kind {
+		case store.CapabilityLanguage:
+			caps.Languages = append(caps.Languages, name)
+		case store.CapabilityRepo:
+			caps.Repos = append(caps.Repos, name)
+		case store.CapabilityTool:
+			caps.Tools = append(caps.Tools, name)
+		default:
+			caps.Skills = append(caps.Skills, name)
+		}
+	}
+
+	return caps, nil
+}
+
+// SetAgentCapabilities replaces the capabilities and concurrency limit an
+// agent declares, returning them as stored.
+func (s *Service) SetAgentCapabilities(ctx context.Context, agentID int64,
+	caps AgentCapabilities,
+) (AgentCapabilities, error) {
+	stored, err := caps.storeCapabilities()
+	if err != nil {
+		return AgentCapabilities{}, err
+	}
+
+	err = s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+
+		if _, err := txStore.GetAgent(ctx, agentID); err != nil {
+			return fmt.Errorf("agent not found: %w", err)
+		}
+
+		err := txStore.SetAgentCapabilities(ctx, agentID, stored)
+		if err != nil {
+			return fmt.Errorf("failed to set capabilities: %w", err)
+		}
+
+		return txStore.UpdateAgentMaxConcurrency(
+			ctx, agentID, caps.MaxConcurrency,
+		)
+	})
+	if err != nil {
+		return AgentCapabilities{}, err
+	}
+
+	return s.GetAgentCapabilities(ctx, agentID)
+}
+
+// GetAgentCapabilities returns the capabilities an agent declares.
+func (s *Service) GetAgentCapabilities(ctx context.Context,
+	agentID int64,
+) (AgentCapabilities, error) {
+	agent, err := s.store.GetAgent(ctx, agentID)
+	if err != nil {
+		return AgentCapabilities{}, fmt.Errorf("agent not found: %w",
+			err)
+	}
+
+	caps, err := s.store.ListAgentCapabilities(ctx, agentID)
+	if err != nil {
+		return AgentCapabilities{}, fmt.Errorf("failed to list "+
+			"capabilities: %w", err)
+	}
+
+	return capabilitiesFromStore(caps, agent.MaxConcurrency), nil
+}
+
+// ListAgentCapabilities returns the capabilities of every agent that
+// declares any, keyed by agent ID.
+func (s *Service) ListAgentCapabilities(
+	ctx context.Context,
+) (map[int64]AgentCapabilities, error) {
+	var result map[int64]AgentCapabilities
+	err := s.store.WithReadTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+
+		caps, err := txStore.ListAllAgentCapabilities(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list capabilities: %w",
+				err)
+		}
+
+		agents, err := txStore.ListAgents(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list agents: %w", err)
+		}
+
+		result = make(map[int64]AgentCapabilities)
+		for _, a := range agents {
+			if len(caps[a.ID]) == 0 && a.MaxConcurrency == 0 {
+				continue
+			}
+			result[a.ID] = capabilitiesFromStore(
+				caps[a.ID], a.MaxConcurrency,
+			)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// CapabilityRoute is the agent the router picked for a message or task
+// addressed to a capability, and the load it was picked on.
+type CapabilityRoute struct {
+	// Capability is the address without its "cap:" prefix.
+	Capability string
+
+	// AgentID and AgentName identify the agent picked.
+	AgentID   int64
+	AgentName string
+
+	// MessageID or TaskID is what was routed, the other being 0.
+	MessageID int64
+	TaskID    int64
+
+	// Candidates is the number of online agents with the capability
+	// and room for more work that the router chose from.
+	Candidates int64
+
+	// UnreadCount and InProgressCount are the picked agent's unread mail
+	// and in-progress tasks at the time.
+	UnreadCount     int64
+	InProgressCount int64
+
+	CreatedAt time.Time
+}
+
+// capabilityRouteFromStore converts a stored capability route.
+func capabilityRouteFromStore(r store.CapabilityRoute) CapabilityRoute {
+	route := CapabilityRoute{
+		Capability:      r.Capability,
+		AgentID:         r.AgentID,
+		AgentName:       r.AgentName,
+		Candidates:      r.Candidates,
+		UnreadCount:     r.UnreadCount,
+		InProgressCount: r.InProgressCount,
+		CreatedAt:       r.CreatedAt,
+	}
+	if r.MessageID != nil {
+		route.MessageID = *r.MessageID
+	}
+	if r.TaskID != nil {
+		route.TaskID = *r.TaskID
+	}
+
+	return route
+}
+
+// routeCapability picks the agent a capability address is routed to: the
+// least loaded of the online agents with the capability, by unread mail
+// plus tasks in progress. Agents at their concurrency limit are passed
+// over, and ties go to the agent seen most recently. The agent excluded,
+// typically the sender, is never picked. The choice isn't recorded.
+func (s *Service) routeCapability(ctx context.Context,
+	txStore store.Storage, address string, excludeID int64,
+) (CapabilityRoute, error) {
+	kind, name, err := ParseCapability(address)
+	if err != nil {
+		return CapabilityRoute{}, err
+	}
+	capability := capabilityKey(kind, name)
+
+	candidates, err := txStore.ListCapabilityCandidates(ctx, kind, name)
+	if err != nil {
+		return CapabilityRoute{}, fmt.Errorf("failed to list agents "+
+			"with %s: %w", capability, err)
+	}
+
+	var capable, online, available []store.CapabilityCandidate
+	for _, c := range candidates {
+		if c.ID == excludeID {
+			continue
+		}
+		capable = append(capable, c)
+
+		if time.Since(c.LastActiveAt) > s.onlineThreshold {
+			continue
+		}
+		online = append(online, c)
+
+		if c.MaxConcurrency == 0 ||
+			c.InProgressCount < c.MaxConcurrency {
+
+			available = append(available, c)
+		}
+	}
+
+	switch {
+	case len(capable) == 0:
+		return CapabilityRoute{}, fmt.Errorf("%w: no agent has %s",
+			ErrNoCapableAgent, capability)
+
+	case len(online) == 0:
+		return CapabilityRoute{}, fmt.Errorf("%w: no agent with %s "+
+			"is online", ErrNoCapableAgent, capability)
+
+	case len(available) == 0:
+		return CapabilityRoute{}, fmt.Errorf("%w: every online agent "+
+			"with %s is at its concurrency limit",
+			ErrNoCapableAgent, capability)
+	}
+
+	pick := slices.MinFunc(available, func(a,
+		b store.CapabilityCandidate) int {
+
+		loadA := a.UnreadCount + a.InProgressCount
+		loadB := b.UnreadCount + b.InProgressCount
+
+		return cmp.Or(
+			cmp.Compare(loadA, loadB),
+			b.LastActiveAt.Compare(a.LastActiveAt),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+
+	return CapabilityRoute{
+		Capability:      capability,
+		AgentID:         pick.ID,
+		AgentName:       pick.Name,
+		Candidates:      int64(len(available)),
+		UnreadCount:     pick.UnreadCount,
+		InProgressCount: pick.InProgressCount,
+	}, nil
+}
+
+// recordRoute stores the route taken for a message or task, so the choice
+// can be audited later.
+func recordRoute(ctx context.Context, txStore store.Storage,
+	route CapabilityRoute,
+) (CapabilityRoute, error) {
+	params := store.CreateCapabilityRouteParams{
+		Capability:      route.Capability,
+		AgentID:         route.AgentID,
+		Candidates:      route.Candidates,
+		UnreadCount:     route.UnreadCount,
+		InProgressCount: route.InProgressCount,
+	}
+	if route.MessageID != 0 {
+		params.MessageID = &route.MessageID
+	}
+	if route.TaskID != 0 {
+		params.TaskID = &route.TaskID
+	}
+
+	recorded, err := txStore.CreateCapabilityRoute(ctx, params)
+	if err != nil {
+		return CapabilityRoute{}, fmt.Errorf("failed to record route "+
+			"to %s: %w", route.Capability, err)
+	}
+
+	return capabilityRouteFromStore(recorded), nil
+}
+
+// UpsertRoutedTask creates or updates a task whose owner is a capability
+// address, assigning it to the agent the router picks and recording the
+// choice. A task already routed for the same capability keeps its agent, so
+// syncing a task list again doesn't reassign its tasks. Task storage doesn't
+// take part in transactions, so neither does routing a task.
+func (s *Service) UpsertRoutedTask(ctx context.Context,
+	params store.UpsertTaskParams,
+) (store.Task, CapabilityRoute, error) {
+	route, err := s.routeTask(
+		ctx, params.ListID, params.ClaudeTaskID, params.Owner,
+	)
+	if err != nil {
+		return store.Task{}, CapabilityRoute{}, err
+	}
+
+	params.Owner = route.AgentName
+	task, err := s.store.UpsertTask(ctx, params)
+	if err != nil {
+		return store.Task{}, CapabilityRoute{}, fmt.Errorf("failed "+
+			"to upsert task: %w", err)
+	}
+
+	if route.TaskID == 0 {
+		route.TaskID = task.ID
+		route, err = recordRoute(ctx, s.store, route)
+	}
+
+	return task, route, err
+}
+
+// RouteTaskOwner assigns an existing task to the agent the router picks for
+// a capability address, recording the choice. A task already routed for
+// the same capability keeps its agent.
+func (s *Service) RouteTaskOwner(ctx context.Context, listID,
+	claudeTaskID, address string,
+) (CapabilityRoute, error) {
+	task, err := s.store.GetTaskByClaudeID(ctx, listID, claudeTaskID)
+	if err != nil {
+		return CapabilityRoute{}, fmt.Errorf("task not found: %w", err)
+	}
+
+	route, err := s.routeTask(ctx, listID, claudeTaskID, address)
+	if err != nil {
+		return CapabilityRoute{}, err
+	}
+
+	err = s.store.UpdateTaskOwner(
+		ctx, listID, claudeTaskID, route.AgentName, time.Now(),
+	)
+	if err != nil {
+		return CapabilityRoute{}, fmt.Errorf("failed to update task "+
+			"owner: %w", err)
+	}
+
+	if route.TaskID != 0 {
+		return route, nil
+	}
+	route.TaskID = task.ID
+
+	return recordRoute(ctx, s.store, route)
+}
+
+// routeTask returns the route of a task addressed to a capability. If the
+// task was already routed for the capability, that route is returned with
+// its task ID set. Otherwise a new, unrecorded route is returned.
+func (s *Service) routeTask(ctx context.Context, listID, claudeTaskID,
+	address string,
+) (CapabilityRoute, error) {
+	task, err := s.store.GetTaskByClaudeID(ctx, listID, claudeTaskID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+
+	case err != nil:
+		return CapabilityRoute{}, fmt.Errorf("failed to get task: %w",
+			err)
+
+	default:
+		prev, err := s.store.GetLatestTaskRoute(ctx, task.ID)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+
+		case err != nil:
+			return CapabilityRoute{}, fmt.Errorf("failed to get "+
+				"task route: %w", err)
+
+		case sameCapability(prev.Capability, address):
+			return capabilityRouteFromStore(prev), nil
+		}
+	}
+
+	return s.routeCapability(ctx, s.store, address, 0)
+}
+
+// sameCapability reports whether a recorded route's capability is the one
+// an address names.
+func sameCapability(capability, address string) bool {
+	kind, name, err := ParseCapability(address)
+
+	return err == nil && capability == capabilityKey(kind, name)
+}
+
+// capabilityKey returns the form of a capability its routes are recorded
+// under: its name, prefixed by its kind if it is restricted to one.
+func capabilityKey(kind, name string) string {
+	if kind == "" {
+		return name
+	}
+
+	return kind + ":" + name
+}
+
+// ListCapabilityRoutes returns the newest routes the router took, of one
+// capability unless it is empty. A leading "cap:" is ignored.
+func (s *Service) ListCapabilityRoutes(ctx context.Context,
+	capability string, limit int,
+) ([]CapabilityRoute, error) {
+	if capability != "" {
+		kind, name, err := ParseCapability(capability)
+		if err != nil {
+			return nil, err
+		}
+		capability = capabilityKey(kind, name)
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	rows, err := s.store.ListCapabilityRoutes(ctx, capability, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routes: %w", err)
+	}
+
+	routes := make([]CapabilityRoute, len(rows))
+	for i, r := range rows {
+		routes[i] = capabilityRouteFromStore(r)
+	}
+
+	return routes, nil
+}
@@ -0,0 +1,275 @@
+package mail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAgentCapabilities tests that capabilities are validated, normalised
+// and replaced as a whole.
+func TestAgentCapabilities(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	alice := createTestAgent(t, storage, "Alice")
+	createTestAgent(t, storage, "Bob")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	_, err := svc.SetAgentCapabilities(ctx, alice.ID, AgentCapabilities{
+		Skills: []string{"go review"},
+	})
+	require.ErrorIs(t, err, ErrInvalidCapability)
+	_, err = svc.SetAgentCapabilities(ctx, alice.ID, AgentCapabilities{
+		MaxConcurrency: -1,
+	})
+	require.ErrorIs(t, err, ErrInvalidCapability)
+
+	caps, err := svc.SetAgentCapabilities(ctx, alice.ID, AgentCapabilities{
+		Languages:      []string{"Go", "go", "c++"},
+		Repos:          []string{"roasbeef/substrate"},
+		Skills:         []string{"can-review-security"},
+		MaxConcurrency: 2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AgentCapabilities{
+		Languages:      []string{"c++", "go"},
+		Repos:          []string{"roasbeef/substrate"},
+		Skills:         []string{"can-review-security"},
+		MaxConcurrency: 2,
+	}, caps)
+
+	all, err := svc.ListAgentCapabilities(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[int64]AgentCapabilities{alice.ID: caps}, all)
+
+	// Setting capabilities replaces the old ones.
+	caps, err = svc.SetAgentCapabilities(ctx, alice.ID, AgentCapabilities{
+		Tools: []string{"gopls"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, AgentCapabilities{Tools: []string{"gopls"}}, caps)
+
+	kind, name, err := ParseCapability("cap:Language:Go")
+	require.NoError(t, err)
+	require.Equal(t, "language", kind)
+	require.Equal(t, "go", name)
+
+	_, _, err = ParseCapability("cap:team:core")
+	require.ErrorIs(t, err, ErrInvalidCapability)
+
+	caps, err = ParseAgentCapabilities([]string{
+		"language:go", "repo:roasbeef/substrate", "go-review", "",
+	})
+	require.NoError(t, err)
+	require.Equal(t, AgentCapabilities{
+		Languages: []string{"go"},
+		Repos:     []string{"roasbeef/substrate"},
+		Skills:    []string{"go-review"},
+	}, caps)
+	require.True(t, caps.Has("", "go"))
+	require.False(t, caps.Has(store.CapabilityTool, "go"))
+}
+
+// TestCapabilityRouting tests that mail addressed to a capability goes to
+// the least loaded online agent with it that isn't at its concurrency
+// limit, and that the choice is recorded.
+func TestCapabilityRouting(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	alice := createTestAgent(t, storage, "Alice")
+	bob := createTestAgent(t, storage, "Bob")
+	carol := createTestAgent(t, storage, "Carol")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	setCaps := func(agentID int64, caps AgentCapabilities) {
+		_, err := svc.SetAgentCapabilities(ctx, agentID, caps)
+		require.NoError(t, err)
+	}
+	setCaps(alice.ID, AgentCapabilities{
+		Languages: []string{"go"},
+		Skills:    []string{"go-review"},
+	})
+	setCaps(bob.ID, AgentCapabilities{
+		Skills:         []string{"go-review"},
+		MaxConcurrency: 1,
+	})
+	setCaps(carol.ID, AgentCapabilities{Skills: []string{"go-review"}})
+
+	// Alice was seen most recently, Bob a minute ago, and Carol is
+	// offline.
+	now := time.Now()
+	lastSeen := map[int64]time.Time{
+		alice.ID: now,
+		bob.ID:   now.Add(-time.Minute),
+		carol.ID: now.Add(-time.Hour),
+	}
+	for agentID, ts := range lastSeen {
+		require.NoError(t, storage.UpdateLastActive(ctx, agentID, ts))
+	}
+
+	send := func(senderID int64, address string) (SendMailResponse,
+		error,
+	) {
+		return svc.Send(ctx, SendMailRequest{
+			SenderID:       senderID,
+			RecipientNames: []string{address},
+			Subject:        "Review " + address,
+			Priority:       PriorityNormal,
+		})
+	}
+	requireRouted := func(address, want string) int64 {
+		t.Helper()
+
+		resp, err := send(sender.ID, address)
+		require.NoError(t, err)
+		require.Equal(t, []RecipientExpansion{{
+			Address:    address,
+			AgentNames: []string{want},
+		}}, resp.Expansions)
+
+		return resp.MessageID
+	}
+
+	// With no load either way, the tie goes to the agent seen most
+	// recently. Its unread mail then sends the next message to Bob.
+	first := requireRouted("cap:go-review", "Alice")
+	requireRouted("cap:go-review", "Bob")
+
+	// Once Bob reaches his concurrency limit he is passed over, even
+	// though Alice now has more unread mail.
+	_, err := storage.CreateTaskList(ctx, store.CreateTaskListParams{
+		ListID:  "list-1",
+		AgentID: bob.ID,
+	})
+	require.NoError(t, err)
+	_, err = storage.UpsertTask(ctx, store.UpsertTaskParams{
+		AgentID:      bob.ID,
+		ListID:       "list-1",
+		ClaudeTaskID: "1",
+		Subject:      "Fix flake",
+		Status:       "in_progress",
+		Owner:        "Bob",
+	})
+	require.NoError(t, err)
+	requireRouted("cap:go-review", "Alice")
+
+	// A kind restricts the match, and the sender is never picked.
+	requireRouted("cap:language:go", "Alice")
+
+	_, err = send(sender.ID, "cap:tool:go-review")
+	require.ErrorIs(t, err, ErrNoCapableAgent)
+	_, err = send(alice.ID, "cap:language:go")
+	require.ErrorIs(t, err, ErrNoCapableAgent)
+	_, err = send(sender.ID, "cap:team:go")
+	require.ErrorIs(t, err, ErrInvalidCapability)
+
+	// A partial send reports a capability nobody has as unknown.
+	resp, err := svc.Send(ctx, SendMailRequest{
+		SenderID:       sender.ID,
+		RecipientNames: []string{"Bob", "cap:rust"},
+		Subject:        "Partial",
+		Priority:       PriorityNormal,
+		DeliveryMode:   DeliveryModePartial,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Deliveries, 2)
+	require.Equal(t, RecipientUnknown, resp.Deliveries[1].Status)
+
+	// Each route is recorded, newest first.
+	routes, err := svc.ListCapabilityRoutes(ctx, "cap:go-review", 0)
+	require.NoError(t, err)
+	require.Len(t, routes, 3)
+	require.Equal(t, "Alice", routes[2].AgentName)
+	require.Equal(t, first, routes[2].MessageID)
+	require.EqualValues(t, 2, routes[2].Candidates)
+	require.EqualValues(t, 1, routes[0].Candidates)
+}
+
+// TestRoutedTask tests that a task owned by a capability is assigned to the
+// agent the router picks, and keeps it when synced again.
+func TestRoutedTask(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	lead := createTestAgent(t, storage, "Lead")
+	alice := createTestAgent(t, storage, "Alice")
+	bob := createTestAgent(t, storage, "Bob")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	for _, a := range []store.Agent{alice, bob} {
+		_, err := svc.SetAgentCapabilities(ctx, a.ID, AgentCapabilities{
+			Skills: []string{"can-review-security"},
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, storage.UpdateLastActive(
+		ctx, bob.ID, time.Now().Add(-time.Minute),
+	))
+
+	_, err := storage.CreateTaskList(ctx, store.CreateTaskListParams{
+		ListID:  "list-1",
+		AgentID: lead.ID,
+	})
+	require.NoError(t, err)
+
+	params := store.UpsertTaskParams{
+		AgentID:      lead.ID,
+		ListID:       "list-1",
+		ClaudeTaskID: "1",
+		Subject:      "Audit the auth middleware",
+		Status:       "in_progress",
+		Owner:        "cap:can-review-security",
+	}
+	task, route, err := svc.UpsertRoutedTask(ctx, params)
+	require.NoError(t, err)
+	require.Equal(t, "Alice", task.Owner)
+	require.Equal(t, task.ID, route.TaskID)
+	require.EqualValues(t, 2, route.Candidates)
+
+	// Alice is now the busier of the two, but syncing the task again
+	// leaves it with her rather than routing it afresh.
+	task, again, err := svc.UpsertRoutedTask(ctx, params)
+	require.NoError(t, err)
+	require.Equal(t, "Alice", task.Owner)
+	require.Equal(t, route, again)
+
+	routes, err := svc.ListCapabilityRoutes(ctx, "", 0)
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+
+	// Reassigning the task to another capability routes it again.
+	_, err = svc.SetAgentCapabilities(ctx, bob.ID, AgentCapabilities{
+		Skills: []string{"can-review-security", "go-review"},
+	})
+	require.NoError(t, err)
+	route, err = svc.RouteTaskOwner(
+		ctx, "list-1", "1", "cap:go-review",
+	)
+	require.NoError(t, err)
+	require.Equal(t, "Bob", route.AgentName)
+
+	task, err = storage.GetTaskByClaudeID(ctx, "list-1", "1")
+	require.NoError(t, err)
+	require.Equal(t, "Bob", task.Owner)
+}
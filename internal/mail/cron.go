@@ -0,0 +1,259 @@
+package mail
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronField describes the allowed range and names of one field of a cron
+// expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{
+		name: "month", min: 1, max: 12,
+		names: map[string]int{
+			"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5,
+			"jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10,
+			"nov": 11, "dec": 12,
+		},
+	}
+	cronDow = cronField{
+		name: "day of week", min: 0, max: 7,
+		names: map[string]int{
+			"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4,
+			"fri": 5, "sat": 6,
+		},
+	}
+)
+
+// cronMacros are the shorthand expressions accepted in place of the five
+// fields.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSearchYears bounds how far ahead Next looks for a matching time, so an
+// expression that can never match (such as February 30th) terminates.
+const cronSearchYears = 5
+
+// CronSpec is a parsed five-field cron expression: minute, hour, day of
+// month, month and day of week. Each field is a bit set of the values it
+// matches.
+type CronSpec struct {
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny record whether the day fields were "*". As in
+	// standard cron, when both day fields are restricted a day matches
+	// if either of them does.
+	domAny, dowAny bool
+}
+
+// ParseCronSpec parses a standard five-field cron expression such as
+// "0 9 * * mon-fri", or one of the macros @hourly, @daily, @weekly,
+// @monthly and @yearly. Fields accept "*", single values, ranges ("1-5"),
+// steps ("*/15", "0-30/10") and comma-separated lists of these. Month and
+// weekday names may be used, and both 0 and 7 mean Sunday.
+func ParseCronSpec(expr string) (CronSpec, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return CronSpec{}, fmt.Errorf("cron expression %q must have "+
+			"5 fields, got %d", expr, len(fields))
+	}
+
+	var spec CronSpec
+	fieldSets := []struct {
+		bits  *uint64
+		field cronField
+	}{
+		{&spec.minute, cronMinute},
+		{&spec.hour, cronHour},
+		{&spec.dom, cronDom},
+		{&spec.month, cronMonth},
+		{&spec.dow, cronDow},
+	}
+	for i, f := range fieldSets {
+		bits, err := parseCronField(fields[i], f.field)
+		if err != nil {
+			return CronSpec{}, err
+		}
+		*f.bits = bits
+	}
+
+	// Sunday may be written as 7.
+	if spec.dow&(1<<7) != 0 {
+		spec.dow |= 1
+	}
+
+	spec.domAny = fields[2] == "*"
+	spec.dowAny = fields[4] == "*"
+
+	return spec, nil
+}
+
+// parseCronField parses one comma-separated field of a cron expression into
+// a bit set.
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangeStr, stepStr, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s "+
+					"field", stepStr, f.name)
+			}
+		}
+
+		var lo, hi int
+		switch {
+		case rangeStr == "*":
+			lo, hi = f.min, f.max
+
+		case strings.Contains(rangeStr, "-"):
+			loStr, hiStr, _ := strings.Cut(rangeStr, "-")
+
+			var err error
+			if lo, err = f.value(loStr); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(hiStr); err != nil {
+				return 0, err
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("invalid range %q in %s "+
+					"field", rangeStr, f.name)
+			}
+
+		default:
+			var err error
+			if lo, err = f.value(rangeStr); err != nil {
+				return 0, err
+			}
+
+			// "5/15" means every 15 starting at 5.
+			hi = lo
+			if hasStep {
+				hi = f.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+// value parses a single number or name of the field and checks its range.
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", s, f.name)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s value %d out of range %d-%d", f.name,
+			v, f.min, f.max)
+	}
+
+	return v, nil
+}
+
+// Next returns the first time strictly after the given time that matches the
+// spec, evaluated in the location of after. It returns the zero time if
+// nothing matches within the next few years.
+func (c CronSpec) Next(after time.Time) time.Time {
+	loc := after.Location()
+
+	// Start at the next whole minute.
+	t := time.Date(
+		after.Year(), after.Month(), after.Day(), after.Hour(),
+		after.Minute(), 0, 0, loc,
+	).Add(time.Minute)
+
+	limit := t.Year() + cronSearchYears
+
+	for t.Year() <= limit {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if !c.dayMatches(t) {
+			t = time.Date(
+				t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc,
+			)
+			continue
+		}
+
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			next := time.Date(
+				t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0,
+				0, loc,
+			)
+
+			// Across a daylight saving change the next wall
+			// clock hour may not exist, so always move forward.
+			if !next.After(t) {
+				next = t.Add(time.Hour)
+			}
+			t = next
+			continue
+		}
+
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// dayMatches reports whether the day of t matches the day of month and day
+// of week fields.
+func (c CronSpec) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dowMatch
+	case c.dowAny:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
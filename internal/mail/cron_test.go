@@ -0,0 +1,152 @@
+package mail
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCronSpecNext tests that cron expressions match the expected next
+// occurrences, including day-of-week names, the day-of-month/day-of-week OR
+// rule, and daylight saving changes.
+func TestCronSpecNext(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	at := func(loc *time.Location, s string) time.Time {
+		tm, err := time.ParseInLocation("2006-01-02 15:04", s, loc)
+		require.NoError(t, err)
+		return tm
+	}
+
+	tests := []struct {
+		name  string
+		expr  string
+		loc   *time.Location
+		after string
+		want  []string
+	}{
+		{
+			// 2026-10-16 is a Friday.
+			name:  "weekdays at nine",
+			expr:  "0 9 * * mon-fri",
+			loc:   time.UTC,
+			after: "2026-10-16 08:00",
+			want: []string{
+				"2026-10-16 09:00", "2026-10-19 09:00",
+				"2026-10-20 09:00",
+			},
+		},
+		{
+			name:  "every fifteen minutes",
+			expr:  "*/15 * * * *",
+			loc:   time.UTC,
+			after: "2026-10-16 23:40",
+			want: []string{
+				"2026-10-16 23:45", "2026-10-17 00:00",
+				"2026-10-17 00:15",
+			},
+		},
+		{
+			name:  "ranges and lists",
+			expr:  "0,30 8-9 1,15 * *",
+			loc:   time.UTC,
+			after: "2026-10-15 09:30",
+			want: []string{
+				"2026-11-01 08:00", "2026-11-01 08:30",
+				"2026-11-01 09:00",
+			},
+		},
+		{
+			// Both day fields are restricted, so either matches.
+			name:  "day of month or day of week",
+			expr:  "0 0 13 * fri",
+			loc:   time.UTC,
+			after: "2026-10-16 00:00",
+			want: []string{
+				"2026-10-23 00:00", "2026-10-30 00:00",
+				"2026-11-06 00:00", "2026-11-13 00:00",
+			},
+		},
+		{
+			name:  "sunday as seven",
+			expr:  "@weekly",
+			loc:   time.UTC,
+			after: "2026-10-16 00:00",
+			want:  []string{"2026-10-18 00:00", "2026-10-25 00:00"},
+		},
+		{
+			name:  "leap day",
+			expr:  "0 12 29 feb *",
+			loc:   time.UTC,
+			after: "2026-10-16 00:00",
+			want:  []string{"2028-02-29 12:00", "2032-02-29 12:00"},
+		},
+		{
+			// Clocks go back on 2026-11-01, and the occurrence
+			// stays at nine local time.
+			name:  "local time across fall back",
+			expr:  "0 9 * * *",
+			loc:   newYork,
+			after: "2026-10-31 10:00",
+			want:  []string{"2026-11-01 09:00", "2026-11-02 09:00"},
+		},
+		{
+			// 02:30 doesn't exist on 2026-03-08 in New York.
+			name:  "skipped hour on spring forward",
+			expr:  "30 2 * * *",
+			loc:   newYork,
+			after: "2026-03-07 03:00",
+			want:  []string{"2026-03-09 02:30", "2026-03-10 02:30"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, err := ParseCronSpec(tc.expr)
+			require.NoError(t, err)
+
+			next := at(tc.loc, tc.after)
+			for _, want := range tc.want {
+				next = spec.Next(next)
+				require.Equal(
+					t, at(tc.loc, want).String(),
+					next.String(),
+				)
+			}
+		})
+	}
+}
+
+// TestParseCronSpecErrors tests that malformed cron expressions are
+// rejected.
+func TestParseCronSpecErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * * funday",
+		"@sometimes",
+	} {
+		_, err := ParseCronSpec(expr)
+		require.Error(t, err, "expr %q", expr)
+	}
+
+	// An expression that parses but can never match has no next
+	// occurrence.
+	spec, err := ParseCronSpec("0 0 30 feb *")
+	require.NoError(t, err)
+	require.True(t, spec.Next(time.Now()).IsZero())
+}
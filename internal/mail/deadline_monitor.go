@@ -0,0 +1,457 @@
+package mail
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+const (
+	// DefaultDeadlineCheckInterval is how often the deadline monitor
+	// scans for overdue messages.
+	DefaultDeadlineCheckInterval = time.Minute
+
+	// DefaultEscalationFallbackAgent is the agent copied on overdue
+	// messages when the policy doesn't name one.
+	DefaultEscalationFallbackAgent = "User"
+
+	// defaultEscalationBatchSize caps the number of overdue recipients
+	// escalated per check.
+	defaultEscalationBatchSize = 100
+
+	// ActivityTypeDeadlineEscalation is the activity type recorded when
+	// an overdue message is escalated.
+	ActivityTypeDeadlineEscalation = "deadline_escalation"
+)
+
+// EscalationAction is a single step taken when an overdue message reaches an
+// escalation level.
+type EscalationAction string
+
+const (
+	// EscalationRenotify pushes the message to the recipient again as if
+	// it had just been delivered.
+	EscalationRenotify EscalationAction = "renotify"
+
+	// EscalationRaisePriority raises the message priority to urgent.
+	EscalationRaisePriority EscalationAction = "priority"
+
+	// EscalationCopyFallback delivers a copy of the message to the
+	// policy's fallback agent.
+	EscalationCopyFallback EscalationAction = "fallback"
+
+	// EscalationRecordActivity records a deadline_escalation activity for
+	// the recipient.
+	EscalationRecordActivity EscalationAction = "activity"
+)
+
+// validEscalationActions is the set of actions accepted by
+// ParseEscalationPolicy.
+var validEscalationActions = []EscalationAction{
+	EscalationRenotify, EscalationRaisePriority,
+	EscalationCopyFallback, EscalationRecordActivity,
+}
+
+// EscalationLevel is a single rung of an escalation chain. Its actions run
+// once a message has been overdue for at least After.
+type EscalationLevel struct {
+	// After is how long past the deadline this level kicks in.
+	After time.Duration
+
+	// Actions are the steps taken when the level is reached.
+	Actions []EscalationAction
+}
+
+// EscalationPolicy is an ordered chain of escalation levels applied to
+// unacked messages once their deadline passes.
+type EscalationPolicy struct {
+	// Levels are the escalation levels, ordered by After.
+	Levels []EscalationLevel
+
+	// FallbackAgent is the name of the agent copied by the fallback
+	// action.
+	FallbackAgent string
+}
+
+// DefaultEscalationPolicy returns the escalation chain used by the daemon
+// when none is configured: re-notify the recipient as soon as the deadline
+// passes, raise the priority after 30 minutes, and copy the fallback agent
+// after two hours.
+func DefaultEscalationPolicy() EscalationPolicy {
+	return EscalationPolicy{
+		Levels: []EscalationLevel{
+			{
+				After: 0,
+				Actions: []EscalationAction{
+					EscalationRenotify, EscalationRecordActivity,
+				},
+			},
+			{
+				After: 30 * time.Minute,
+				Actions: []EscalationAction{
+					EscalationRaisePriority, EscalationRenotify,
+				},
+			},
+			{
+				After: 2 * time.Hour,
+				Actions: []EscalationAction{
+					EscalationCopyFallback,
+					EscalationRecordActivity,
+				},
+			},
+		},
+		FallbackAgent: DefaultEscalationFallbackAgent,
+	}
+}
+
+// ParseEscalationPolicy parses an escalation chain of the form
+// "0s:renotify+activity,30m:priority,2h:fallback+activity". Each level is a
+// duration past the deadline followed by the actions to take, and levels
+// must be listed in increasing order.
+func ParseEscalationPolicy(spec, fallbackAgent string) (EscalationPolicy,
+	error) {
+
+	policy := EscalationPolicy{FallbackAgent: fallbackAgent}
+	if policy.FallbackAgent == "" {
+		policy.FallbackAgent = DefaultEscalationFallbackAgent
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		afterStr, actionsStr, ok := strings.Cut(part, ":")
+		if !ok {
+			return policy, fmt.Errorf("invalid escalation level %q: "+
+				"expected <duration>:<actions>", part)
+		}
+
+		after, err := time.ParseDuration(strings.TrimSpace(afterStr))
+		if err != nil {
+			return policy, fmt.Errorf("invalid escalation delay "+
+				"%q: %w", afterStr, err)
+		}
+		if after < 0 {
+			return policy, fmt.Errorf("escalation delay %v must "+
+				"not be negative", after)
+		}
+
+		numLevels := len(policy.Levels)
+		if numLevels > 0 && after <= policy.Levels[numLevels-1].After {
+			return policy, fmt.Errorf("escalation delay %v must be "+
+				"greater than the previous level", after)
+		}
+
+		level := EscalationLevel{After: after}
+		for _, name := range strings.Split(actionsStr, "+") {
+			action := EscalationAction(strings.TrimSpace(name))
+			if !slices.Contains(validEscalationActions, action) {
+				return policy, fmt.Errorf("unknown escalation "+
+					"action %q", name)
+			}
+			level.Actions = append(level.Actions, action)
+		}
+
+		policy.Levels = append(policy.Levels, level)
+	}
+
+	if len(policy.Levels) == 0 {
+		return policy, fmt.Errorf("escalation policy has no levels")
+	}
+
+	return policy, nil
+}
+
+// targetLevel returns the number of levels that have been reached by a
+// message that is overdue by the given duration.
+func (p EscalationPolicy) targetLevel(overdue time.Duration) int {
+	var level int
+	for _, l := range p.Levels {
+		if overdue < l.After {
+			break
+		}
+		level++
+	}
+
+	return level
+}
+
+// levelDelays returns how long past the deadline each level is reached.
+func (p EscalationPolicy) levelDelays() []time.Duration {
+	delays := make([]time.Duration, len(p.Levels))
+	for i, l := range p.Levels {
+		delays[i] = l.After
+	}
+
+	return delays
+}
+
+// actionsBetween returns the de-duplicated actions of the levels after from
+// up to and including to. Levels skipped while the daemon was down are
+// folded into a single escalation so the recipient is only notified once.
+func (p EscalationPolicy) actionsBetween(from, to int) []EscalationAction {
+	var actions []EscalationAction
+	for _, l := range p.Levels[from:to] {
+		for _, action := range l.Actions {
+			if !slices.Contains(actions, action) {
+				actions = append(actions, action)
+			}
+		}
+	}
+
+	return actions
+}
+
+// DeadlineMonitorConfig holds configuration for the deadline monitor.
+type DeadlineMonitorConfig struct {
+	// Store is the storage backend holding messages and escalation
+	// state.
+	Store store.Storage
+
+	// NotificationHub is the optional notification hub used to re-notify
+	// recipients and the fallback agent.
+	NotificationHub NotificationActorRef
+
+	// Policy is the escalation chain applied to overdue messages.
+	Policy EscalationPolicy
+
+	// CheckInterval is how often to scan for overdue messages.
+	CheckInterval time.Duration
+
+	// Log is the logger used for escalation failures.
+	Log *slog.Logger
+}
+
+// DeadlineMonitor periodically scans for unacked messages whose deadline has
+// passed and walks them up the configured escalation chain. The level
+// reached by each recipient is persisted, so no level is ever applied twice,
+// even across daemon restarts.
+type DeadlineMonitor struct {
+	cfg DeadlineMonitorConfig
+	log *slog.Logger
+}
+
+// NewDeadlineMonitor creates a new deadline monitor.
+func NewDeadlineMonitor(cfg DeadlineMonitorConfig) *DeadlineMonitor {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = DefaultDeadlineCheckInterval
+	}
+	if cfg.Policy.FallbackAgent == "" {
+		cfg.Policy.FallbackAgent = DefaultEscalationFallbackAgent
+	}
+
+	log := cfg.Log
+	if log == nil {
+		log = slog.Default()
+	}
+
+	return &DeadlineMonitor{
+		cfg: cfg,
+		log: log.With("component", "deadline-monitor"),
+	}
+}
+
+// Run checks for overdue messages immediately and then on every check
+// interval. It blocks until ctx is cancelled.
+func (m *DeadlineMonitor) Run(ctx context.Context) {
+	if len(m.cfg.Policy.Levels) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.CheckDeadlines(ctx, time.Now()); err != nil {
+			m.log.Warn("Failed to check message deadlines",
+				"error", err,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckDeadlines escalates every overdue recipient that has reached a new
+// level of the policy as of now. It returns the number of recipients
+// escalated.
+func (m *DeadlineMonitor) CheckDeadlines(ctx context.Context,
+	now time.Time,
+) (int, error) {
+	overdue, err := m.cfg.Store.ListOverdueRecipients(
+		ctx, now, m.cfg.Policy.levelDelays(),
+		defaultEscalationBatchSize,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list overdue messages: %w", err)
+	}
+
+	var numEscalated int
+	for _, r := range overdue {
+		level := m.cfg.Policy.targetLevel(now.Sub(r.DeadlineAt))
+		if level <= r.EscalationLevel {
+			continue
+		}
+
+		if err := m.escalate(ctx, r, level); err != nil {
+			m.log.Warn("Failed to escalate overdue message",
+				"message_id", r.MessageID,
+				"agent_id", r.AgentID,
+				"level", level,
+				"error", err,
+			)
+			continue
+		}
+		numEscalated++
+	}
+
+	return numEscalated, nil
+}
+
+// escalate applies the actions of every level the recipient has newly
+// reached and records the new level. Database changes happen in a single
+// transaction, notifications are only sent once it has committed.
+func (m *DeadlineMonitor) escalate(ctx context.Context,
+	r store.OverdueRecipient, level int,
+) error {
+	actions := m.cfg.Policy.actionsBetween(r.EscalationLevel, level)
+
+	var fallbackID int64
+	err := m.cfg.Store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		for _, action := range actions {
+			var err error
+			switch action {
+			case EscalationRaisePriority:
+				if r.Priority == string(PriorityUrgent) {
+					continue
+				}
+				err = txStore.UpdateMessagePriority(
+					ctx, r.MessageID, string(PriorityUrgent),
+				)
+
+			case EscalationCopyFallback:
+				fallbackID, err = m.copyFallback(
+					ctx, txStore, r, level,
+				)
+
+			case EscalationRecordActivity:
+				var metadata []byte
+				metadata, err = json.Marshal(map[string]any{
+					"message_id": r.MessageID,
+					"thread_id":  r.ThreadID,
+					"level":      level,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to encode "+
+						"metadata: %w", err)
+				}
+
+				err = txStore.CreateActivity(
+					ctx, store.CreateActivityParams{
+						AgentID:      r.AgentID,
+						ActivityType: ActivityTypeDeadlineEscalation,
+						Description: fmt.Sprintf(
+							"Message %q is overdue "+
+								"(escalation level %d)",
+							r.Subject, level,
+						),
+						Metadata: string(metadata),
+					},
+				)
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", action, err)
+			}
+		}
+
+		return txStore.UpsertMessageEscalation(
+			ctx, store.UpsertMessageEscalationParams{
+				MessageID: r.MessageID,
+				AgentID:   r.AgentID,
+				Level:     level,
+			},
+		)
+	})
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(actions, EscalationRenotify) {
+		pushInboxMessage(
+			ctx, m.cfg.NotificationHub, m.cfg.Store, r.MessageID,
+			r.State, r.AgentID,
+		)
+	}
+	if fallbackID != 0 {
+		pushInboxMessage(
+			ctx, m.cfg.NotificationHub, m.cfg.Store, r.MessageID,
+			StateUnreadStr.String(), fallbackID,
+		)
+	}
+
+	return nil
+}
+
+// copyFallback delivers the message to the fallback agent and returns its
+// ID, or 0 if the fallback agent doesn't exist or already has a copy. The
+// copy starts at the recipient's escalation level so the fallback agent
+// isn't walked through the earlier levels again.
+func (m *DeadlineMonitor) copyFallback(ctx context.Context,
+	txStore store.Storage, r store.OverdueRecipient, level int,
+) (int64, error) {
+	fallback, err := txStore.GetAgentByName(
+		ctx, m.cfg.Policy.FallbackAgent,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		m.log.Warn("Escalation fallback agent not found",
+			"agent", m.cfg.Policy.FallbackAgent,
+		)
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = txStore.GetMessageRecipient(ctx, r.MessageID, fallback.ID)
+	switch {
+	case err == nil:
+		return 0, nil
+
+	case !errors.Is(err, sql.ErrNoRows):
+		return 0, err
+	}
+
+	err = txStore.CreateMessageRecipient(ctx, r.MessageID, fallback.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	err = txStore.UpsertMessageEscalation(
+		ctx, store.UpsertMessageEscalationParams{
+			MessageID: r.MessageID,
+			AgentID:   fallback.ID,
+			Level:     level,
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return fallback.ID, nil
+}
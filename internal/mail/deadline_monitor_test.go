@@ -0,0 +1,397 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/baselib/actor"
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseEscalationPolicy tests parsing of escalation chain specs.
+func TestParseEscalationPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		spec     string
+		fallback string
+		want     EscalationPolicy
+		wantErr  bool
+	}{
+		{
+			name:     "full chain",
+			spec:     "0s:renotify+activity, 30m:priority, 2h:fallback",
+			fallback: "Lead",
+			want: EscalationPolicy{
+				Levels: []EscalationLevel{
+					{
+						After: 0,
+						Actions: []EscalationAction{
+							EscalationRenotify,
+							EscalationRecordActivity,
+						},
+					},
+					{
+						After: 30 * time.Minute,
+						Actions: []EscalationAction{
+							EscalationRaisePriority,
+						},
+					},
+					{
+						After: 2 * time.Hour,
+						Actions: []EscalationAction{
+							EscalationCopyFallback,
+						},
+					},
+				},
+				FallbackAgent: "Lead",
+			},
+		},
+		{
+			name: "default fallback",
+			spec: "1h:fallback",
+			want: EscalationPolicy{
+				Levels: []EscalationLevel{
+					{
+						After: time.Hour,
+						Actions: []EscalationAction{
+							EscalationCopyFallback,
+						},
+					},
+				},
+				FallbackAgent: DefaultEscalationFallbackAgent,
+			},
+		},
+		{
+			name:    "empty",
+			spec:    " , ",
+			wantErr: true,
+		},
+		{
+			name:    "missing actions",
+			spec:    "30m",
+			wantErr: true,
+		},
+		{
+			name:    "bad duration",
+			spec:    "soon:renotify",
+			wantErr: true,
+		},
+		{
+			name:    "unknown action",
+			spec:    "0s:page",
+			wantErr: true,
+		},
+		{
+			name:    "out of order",
+			spec:    "1h:renotify,30m:priority",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy, err := ParseEscalationPolicy(tc.spec, tc.fallback)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, policy)
+		})
+	}
+}
+
+// TestEscalationPolicyLevels tests how overdue durations map onto levels and
+// that skipped levels fold their actions together.
+func TestEscalationPolicyLevels(t *testing.T) {
+	t.Parallel()
+
+	policy := DefaultEscalationPolicy()
+
+	require.Equal(t, 0, policy.targetLevel(-time.Second))
+	require.Equal(t, 1, policy.targetLevel(0))
+	require.Equal(t, 1, policy.targetLevel(29*time.Minute))
+	require.Equal(t, 2, policy.targetLevel(30*time.Minute))
+	require.Equal(t, 3, policy.targetLevel(24*time.Hour))
+
+	require.Equal(t, []EscalationAction{
+		EscalationRenotify, EscalationRecordActivity,
+		EscalationRaisePriority, EscalationCopyFallback,
+	}, policy.actionsBetween(0, 3))
+	require.Equal(t, []EscalationAction{
+		EscalationCopyFallback, EscalationRecordActivity,
+	}, policy.actionsBetween(2, 3))
+}
+
+// TestDeadlineMonitorEscalates tests that an overdue message walks up the
+// escalation chain exactly once per level, and that the persisted levels
+// stop a fresh monitor from escalating it again after a restart.
+func TestDeadlineMonitorEscalates(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	recipient := createTestAgent(t, storage, "Recipient")
+	fallback := createTestAgent(t, storage, "User")
+
+	system := actor.NewActorSystem()
+	defer shutdownSystem(t, system)
+
+	notifHubRef := NotificationHubKey.Spawn(
+		system, "test-notif-hub", NewNotificationHub(),
+	)
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	// The thread ID needs escaping in the escalation's activity metadata.
+	deadline := time.Now().Add(time.Minute)
+	resp, err := svc.Send(ctx, SendMailRequest{
+		SenderID:       sender.ID,
+		RecipientNames: []string{recipient.Name},
+		Subject:        "Need an answer",
+		Body:           "Please reply before the deadline",
+		Priority:       PriorityNormal,
+		Deadline:       &deadline,
+		ThreadID:       `thread "quoted" \ slashed`,
+	})
+	require.NoError(t, err)
+
+	deliveryChan := make(chan InboxMessage, 10)
+	_, err = notifHubRef.Ask(ctx, SubscribeAgentMsg{
+		AgentID:      recipient.ID,
+		SubscriberID: "test-sub",
+		DeliveryChan: deliveryChan,
+	}).Await(ctx).Unpack()
+	require.NoError(t, err)
+
+	monitor := NewDeadlineMonitor(DeadlineMonitorConfig{
+		Store:           storage,
+		NotificationHub: notifHubRef,
+		Policy:          DefaultEscalationPolicy(),
+	})
+
+	// Nothing is overdue before the deadline.
+	numEscalated, err := monitor.CheckDeadlines(ctx, time.Now())
+	require.NoError(t, err)
+	require.Zero(t, numEscalated)
+
+	// Forty minutes past the deadline the first two levels apply at
+	// once: the recipient is re-notified a single time and the priority
+	// is raised.
+	now := deadline.Add(40 * time.Minute)
+	numEscalated, err = monitor.CheckDeadlines(ctx, now)
+	require.NoError(t, err)
+	require.Equal(t, 1, numEscalated)
+
+	select {
+	case msg := <-deliveryChan:
+		require.Equal(t, resp.MessageID, msg.ID)
+		require.Equal(t, PriorityUrgent, msg.Priority)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout waiting for re-notification")
+	}
+
+	msg, err := storage.GetMessage(ctx, resp.MessageID)
+	require.NoError(t, err)
+	require.Equal(t, string(PriorityUrgent), msg.Priority)
+
+	activities, err := storage.ListActivitiesByAgent(ctx, recipient.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, activities, 1)
+	require.Equal(
+		t, ActivityTypeDeadlineEscalation, activities[0].ActivityType,
+	)
+
+	var metadata struct {
+		MessageID int64  `json:"message_id"`
+		ThreadID  string `json:"thread_id"`
+		Level     int    `json:"level"`
+	}
+	err = json.Unmarshal([]byte(activities[0].Metadata), &metadata)
+	require.NoError(t, err)
+	require.Equal(t, resp.MessageID, metadata.MessageID)
+	require.Equal(t, resp.ThreadID, metadata.ThreadID)
+	require.Equal(t, 2, metadata.Level)
+
+	esc, err := storage.GetMessageEscalation(
+		ctx, resp.MessageID, recipient.ID,
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, esc.Level)
+
+	// Checking again at the same time is a no-op.
+	numEscalated, err = monitor.CheckDeadlines(ctx, now)
+	require.NoError(t, err)
+	require.Zero(t, numEscalated)
+
+	// A fresh monitor, as after a restart, picks up the final level
+	// only once it is due, copying the fallback agent.
+	monitor = NewDeadlineMonitor(DeadlineMonitorConfig{
+		Store:  storage,
+		Policy: DefaultEscalationPolicy(),
+	})
+	numEscalated, err = monitor.CheckDeadlines(ctx, now)
+	require.NoError(t, err)
+	require.Zero(t, numEscalated)
+
+	now = deadline.Add(3 * time.Hour)
+	numEscalated, err = monitor.CheckDeadlines(ctx, now)
+	require.NoError(t, err)
+	require.Equal(t, 1, numEscalated)
+
+	recip, err := storage.GetMessageRecipient(
+		ctx, resp.MessageID, fallback.ID,
+	)
+	require.NoError(t, err)
+	require.Equal(t, StateUnreadStr.String(), recip.State)
+
+	// The fallback copy starts at the final level, so nobody is
+	// escalated again.
+	numEscalated, err = monitor.CheckDeadlines(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, numEscalated)
+
+	activities, err = storage.ListActivitiesByAgent(ctx, recipient.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, activities, 2)
+}
+
+// TestDeadlineMonitorSkipsAcked tests that acknowledged messages are never
+// escalated and that the overdue inbox filter only returns unacked messages
+// past their deadline.
+func TestDeadlineMonitorSkipsAcked(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	recipient := createTestAgent(t, storage, "Recipient")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	send := func(subject string, deadline *time.Time) int64 {
+		resp, err := svc.Send(ctx, SendMailRequest{
+			SenderID:       sender.ID,
+			RecipientNames: []string{recipient.Name},
+			Subject:        subject,
+			Body:           "Body",
+			Priority:       PriorityNormal,
+			Deadline:       deadline,
+		})
+		require.NoError(t, err)
+
+		return resp.MessageID
+	}
+
+	past := time.Now().Add(-time.Hour)
+	evenEarlier := time.Now().Add(-2 * time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	overdueID := send("Overdue", &past)
+	olderID := send("Older", &evenEarlier)
+	ackedID := send("Acked", &past)
+	send("Not due", &future)
+	send("No deadline", nil)
+
+	require.NoError(t, svc.AckMessage(ctx, recipient.ID, ackedID))
+
+	resp := svc.handleFetchInbox(ctx, FetchInboxRequest{
+		AgentID:     recipient.ID,
+		OverdueOnly: true,
+	})
+	require.NoError(t, resp.Error)
+	require.Len(t, resp.Messages, 2)
+	require.Equal(t, olderID, resp.Messages[0].ID)
+	require.Equal(t, overdueID, resp.Messages[1].ID)
+
+	// The global view returns the same messages.
+	resp = svc.handleFetchInbox(ctx, FetchInboxRequest{
+		OverdueOnly: true,
+	})
+	require.NoError(t, resp.Error)
+	require.Len(t, resp.Messages, 2)
+
+	monitor := NewDeadlineMonitor(DeadlineMonitorConfig{
+		Store:  storage,
+		Policy: DefaultEscalationPolicy(),
+	})
+	numEscalated, err := monitor.CheckDeadlines(ctx, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, 2, numEscalated)
+
+	_, err = storage.GetMessageEscalation(ctx, ackedID, recipient.ID)
+	require.Error(t, err)
+}
+
+// TestListOverdueRecipientsOnlyDue tests that recipients still waiting on
+// their next escalation level aren't listed, so they can't fill the batch
+// ahead of newly overdue messages with later deadlines.
+func TestListOverdueRecipientsOnlyDue(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	recipient := createTestAgent(t, storage, "Recipient")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	send := func(deadline time.Time) int64 {
+		resp, err := svc.Send(ctx, SendMailRequest{
+			SenderID:       sender.ID,
+			RecipientNames: []string{recipient.Name},
+			Subject:        "Deadline",
+			Body:           "Body",
+			Priority:       PriorityNormal,
+			Deadline:       &deadline,
+		})
+		require.NoError(t, err)
+
+		return resp.MessageID
+	}
+
+	// The older message has reached the first level, and its second is
+	// half an hour past its deadline, which is still ten minutes away.
+	now := time.Now()
+	waitingID := send(now.Add(-20 * time.Minute))
+	err := storage.UpsertMessageEscalation(
+		ctx, store.UpsertMessageEscalationParams{
+			MessageID: waitingID,
+			AgentID:   recipient.ID,
+			Level:     1,
+		},
+	)
+	require.NoError(t, err)
+	newID := send(now.Add(-time.Minute))
+
+	delays := DefaultEscalationPolicy().levelDelays()
+	overdue, err := storage.ListOverdueRecipients(ctx, now, delays, 1)
+	require.NoError(t, err)
+	require.Len(t, overdue, 1)
+	require.Equal(t, newID, overdue[0].MessageID)
+
+	// Once the second level is due, the older message comes first.
+	later := now.Add(15 * time.Minute)
+	overdue, err = storage.ListOverdueRecipients(ctx, later, delays, 1)
+	require.NoError(t, err)
+	require.Len(t, overdue, 1)
+	require.Equal(t, waitingID, overdue[0].MessageID)
+	require.Equal(t, 1, overdue[0].EscalationLevel)
+}
@@ -0,0 +1,174 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+// DefaultDeadLetterLimit is the number of dead letters returned when no
+// limit is given.
+const DefaultDeadLetterLimit = 50
+
+// DeliveryMode controls how a send handles recipients that can't be
+// delivered to.
+type DeliveryMode string
+
+const (
+	// DeliveryModeAtomic delivers to every recipient or to none: a single
+	// unknown or rejected recipient fails the whole send. This is the
+	// default.
+	DeliveryModeAtomic DeliveryMode = "atomic"
+
+	// DeliveryModePartial delivers to every recipient that can be
+	// delivered to, and records the rest in the sender's dead-letter
+	// mailbox.
+	DeliveryModePartial DeliveryMode = "partial"
+)
+
+// RecipientStatus is the outcome of delivering a message to one recipient.
+type RecipientStatus string
+
+const (
+	// RecipientDelivered means the message is in the recipient's inbox.
+	RecipientDelivered RecipientStatus = "delivered"
+
+	// RecipientQueued means the message is in the recipient's inbox, but
+	// the recipient is offline and will see it when they next check in.
+	RecipientQueued RecipientStatus = "queued"
+
+	// RecipientUnknown means no agent, group or alias matched the
+	// recipient name, or an alias matched no agents.
+	RecipientUnknown RecipientStatus = "unknown"
+
+	// RecipientRejected means a delivery policy refused the recipient.
+	RecipientRejected RecipientStatus = "rejected"
+)
+
+// Undeliverable reports whether the message did not reach the recipient.
+func (s RecipientStatus) Undeliverable() bool {
+	return s == RecipientUnknown || s == RecipientRejected
+}
+
+// RecipientDelivery is the delivery outcome for one recipient of a message.
+type RecipientDelivery struct {
+	// Recipient is the agent name, or the address as written by the
+	// sender if it didn't resolve to an agent.
+	Recipient string
+
+	// AgentID is the recipient agent's ID, or zero for unknown
+	// recipients.
+	AgentID int64
+
+	// Status is the outcome of the delivery.
+	Status RecipientStatus
+
+	// Reason explains why the recipient is queued, unknown or rejected.
+	Reason string
+}
+
+// RecipientPolicy decides whether a sender may deliver a message to a
+// recipient. A non-nil error rejects the recipient, and its text is
+// reported as the reason.
+type RecipientPolicy func(ctx context.Context,
+	sender, recipient store.Agent) error
+
+// ProjectIsolationPolicy is a RecipientPolicy that keeps agents to mailing
+// agents of their own project. Agents with no project key, such as the User,
+// are reachable from every project and may mail any agent.
+func ProjectIsolationPolicy(_ context.Context,
+	sender, recipient store.Agent) error {
+
+	if sender.ProjectKey == "" || recipient.ProjectKey == "" ||
+		sender.ProjectKey == recipient.ProjectKey {
+
+		return nil
+	}
+
+	return fmt.Errorf("%s is in project %s, not %s", recipient.Name,
+		recipient.ProjectKey, sender.ProjectKey)
+}
+
+// deliverTo checks a resolved recipient against the delivery policy and
+// fills in the delivery's status.
+func (s *Service) deliverTo(ctx context.Context, sender,
+	recipient store.Agent, delivery *RecipientDelivery,
+) {
+	if s.recipientPolicy != nil {
+		err := s.recipientPolicy(ctx, sender, recipient)
+		if err != nil {
+			delivery.Status = RecipientRejected
+			delivery.Reason = err.Error()
+
+			return
+		}
+	}
+
+	if time.Since(recipient.LastActiveAt) > s.onlineThreshold {
+		delivery.Status = RecipientQueued
+		delivery.Reason = "agent is offline"
+
+		return
+	}
+
+	delivery.Status = RecipientDelivered
+}
+
+// recordDeadLetters stores the undeliverable recipients of a message in the
+// sender's dead-letter mailbox.
+func recordDeadLetters(ctx context.Context, txStore store.Storage,
+	messageID, senderID int64, deliveries []RecipientDelivery,
+) error {
+	for _, d := range deliveries {
+		if !d.Status.Undeliverable() {
+			continue
+		}
+
+		err := txStore.CreateDeadLetter(ctx, store.CreateDeadLetterParams{
+			MessageID: messageID,
+			SenderID:  senderID,
+			Recipient: d.Recipient,
+			Status:    string(d.Status),
+			Reason:    d.Reason,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record dead letter for "+
+				"%s: %w", d.Recipient, err)
+		}
+	}
+
+	return nil
+}
+
+// ListDeadLetters returns the sender's dead letters, newest first.
+func (s *Service) ListDeadLetters(ctx context.Context, senderID int64,
+	limit int,
+) ([]store.DeadLetter, error) {
+	if limit <= 0 {
+		limit = DefaultDeadLetterLimit
+	}
+
+	letters, err := s.store.ListDeadLetters(ctx, senderID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	return letters, nil
+}
+
+// DeleteDeadLetter removes one of the sender's dead letters.
+func (s *Service) DeleteDeadLetter(ctx context.Context, senderID,
+	id int64,
+) error {
+	deleted, err := s.store.DeleteDeadLetter(ctx, id, senderID)
+	if err != nil {
+		return fmt.Errorf("failed to delete dead letter: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %d", ErrDeadLetterNotFound, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPartialDelivery tests that a partial send delivers to every
+// resolvable recipient, reports a status per recipient, and dead-letters the
+// rest, while an atomic send still fails as a whole.
+func TestPartialDelivery(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	alice := createTestAgent(t, storage, "Alice")
+	bob := createTestAgent(t, storage, "Bob")
+	carol := createTestAgent(t, storage, "Carol")
+	all := []store.Agent{sender, alice, bob, carol}
+
+	// Bob hasn't been seen in an hour, so mail to him is queued.
+	err := storage.UpdateLastActive(ctx, bob.ID, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	svc := NewService(ServiceConfig{
+		Store: storage,
+		RecipientPolicy: func(ctx context.Context,
+			sender, recipient store.Agent) error {
+
+			if recipient.Name == "Carol" {
+				return errors.New("not accepting mail")
+			}
+			return nil
+		},
+	})
+	defer svc.OnStop(ctx)
+
+	req := SendMailRequest{
+		SenderID: sender.ID,
+		RecipientNames: []string{
+			"Alice", "Ghost", "Bob", "Carol", "@nobody", "Alice",
+		},
+		Subject:  "Status",
+		Body:     "Body",
+		Priority: PriorityNormal,
+	}
+
+	// By default the send is all-or-nothing.
+	_, err = svc.Send(ctx, req)
+	require.ErrorIs(t, err, ErrAgentNotFound)
+
+	req.RecipientNames = []string{"Alice", "Carol"}
+	_, err = svc.Send(ctx, req)
+	require.ErrorIs(t, err, ErrRecipientRejected)
+
+	// A partial send reaches Alice and Bob and reports the rest.
+	req.RecipientNames = []string{
+		"Alice", "Ghost", "Bob", "Carol", "@nobody", "Alice",
+	}
+	req.DeliveryMode = DeliveryModePartial
+	resp, err := svc.Send(ctx, req)
+	require.NoError(t, err)
+
+	statuses := make(map[string]RecipientStatus)
+	var order []string
+	for _, d := range resp.Deliveries {
+		statuses[d.Recipient] = d.Status
+		order = append(order, d.Recipient)
+	}
+	require.Equal(
+		t, []string{"Alice", "Ghost", "Bob", "Carol", "@nobody"}, order,
+	)
+	require.Equal(t, map[string]RecipientStatus{
+		"Alice":   RecipientDelivered,
+		"Ghost":   RecipientUnknown,
+		"Bob":     RecipientQueued,
+		"Carol":   RecipientRejected,
+		"@nobody": RecipientUnknown,
+	}, statuses)
+
+	require.Equal(
+		t, []string{"Alice", "Bob"},
+		inboxRecipients(t, storage, all, resp.MessageID),
+	)
+
+	letters, err := svc.ListDeadLetters(ctx, sender.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, letters, 3)
+
+	dead := make(map[string]store.DeadLetter)
+	for _, l := range letters {
+		require.Equal(t, resp.MessageID, l.MessageID)
+		require.Equal(t, "Status", l.Subject)
+		dead[l.Recipient] = l
+	}
+	require.Equal(t, string(RecipientUnknown), dead["Ghost"].Status)
+	require.Equal(t, string(RecipientUnknown), dead["@nobody"].Status)
+	require.Equal(t, string(RecipientRejected), dead["Carol"].Status)
+	require.Equal(t, "not accepting mail", dead["Carol"].Reason)
+
+	// Only the sender can see and clear their dead letters.
+	others, err := svc.ListDeadLetters(ctx, alice.ID, 0)
+	require.NoError(t, err)
+	require.Empty(t, others)
+
+	id := dead["Ghost"].ID
+	err = svc.DeleteDeadLetter(ctx, alice.ID, id)
+	require.ErrorIs(t, err, ErrDeadLetterNotFound)
+	require.NoError(t, svc.DeleteDeadLetter(ctx, sender.ID, id))
+	err = svc.DeleteDeadLetter(ctx, sender.ID, id)
+	require.ErrorIs(t, err, ErrDeadLetterNotFound)
+
+	// A partial send that reaches nobody still succeeds, so the sender
+	// can find the message in their dead-letter mailbox.
+	req.RecipientNames = []string{"Ghost"}
+	resp, err = svc.Send(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, resp.Deliveries, 1)
+	require.Equal(t, RecipientUnknown, resp.Deliveries[0].Status)
+	require.Empty(t, inboxRecipients(t, storage, all, resp.MessageID))
+
+	letters, err = svc.ListDeadLetters(ctx, sender.ID, 1)
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	require.Equal(t, resp.MessageID, letters[0].MessageID)
+}
+
+// TestProjectIsolationPolicy tests that the project isolation policy only
+// rejects mail between agents of different projects.
+func TestProjectIsolationPolicy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	user := store.Agent{Name: "User"}
+	alpha := store.Agent{Name: "Alpha", ProjectKey: "/src/alpha"}
+	alpha2 := store.Agent{Name: "Alpha2", ProjectKey: "/src/alpha"}
+	beta := store.Agent{Name: "Beta", ProjectKey: "/src/beta"}
+
+	require.NoError(t, ProjectIsolationPolicy(ctx, alpha, alpha2))
+	require.NoError(t, ProjectIsolationPolicy(ctx, alpha, user))
+	require.NoError(t, ProjectIsolationPolicy(ctx, user, beta))
+
+	err := ProjectIsolationPolicy(ctx, alpha, beta)
+	require.EqualError(
+		t, err, "Beta is in project /src/beta, not /src/alpha",
+	)
+}
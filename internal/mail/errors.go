@@ -19,4 +19,145 @@ var (
 	// ErrUnauthorized is returned when an agent is not authorized to
 	// perform an operation.
 	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrNotQueueTopic is returned when a queue operation targets a topic
+	// that isn't a queue.
+	ErrNotQueueTopic = errors.New("not a queue topic")
+
+	// ErrNotSubscribed is returned when an agent claims from a queue it
+	// isn't subscribed to.
+	ErrNotSubscribed = errors.New("not subscribed to topic")
+
+	// ErrLeaseNotHeld is returned when an agent acks, releases or extends
+	// a queue message it doesn't currently hold the lease on.
+	ErrLeaseNotHeld = errors.New("queue lease not held")
+
+	// ErrRecipientGroupNotFound is returned when a recipient names a
+	// group that doesn't exist.
+	ErrRecipientGroupNotFound = errors.New("recipient group not found")
+
+	// ErrRecipientGroupExists is returned when creating a group whose
+	// name is already taken.
+	ErrRecipientGroupExists = errors.New("recipient group already exists")
+
+	// ErrInvalidGroupName is returned when a group name is malformed or
+	// shadows a dynamic alias.
+	ErrInvalidGroupName = errors.New("invalid recipient group name")
+
+	// ErrUnknownRecipientAlias is returned when a recipient uses an
+	// unknown "@kind:value" alias.
+	ErrUnknownRecipientAlias = errors.New("unknown recipient alias")
+
+	// ErrEmptyRecipientAlias is returned when a group or alias resolves
+	// to no agents at send time.
+	ErrEmptyRecipientAlias = errors.New("recipient alias matched no " +
+		"agents")
+
+	// ErrRecipientRejected is returned when a delivery policy refuses a
+	// recipient of an all-or-nothing send.
+	ErrRecipientRejected = errors.New("recipient rejected by policy")
+
+	// ErrDeadLetterNotFound is returned when a sender has no dead letter
+	// with the given ID.
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+	// ErrScheduledMessageNotFound is returned when a sender has no unsent
+	// scheduled message with the given ID.
+	ErrScheduledMessageNotFound = errors.New("scheduled message not found")
+
+	// ErrScheduleNotFound is returned when an agent has no recurring
+	// schedule with the given name.
+	ErrScheduleNotFound = errors.New("schedule not found")
+
+	// ErrScheduleExists is returned when creating a schedule whose name
+	// the agent already uses.
+	ErrScheduleExists = errors.New("schedule already exists")
+
+	// ErrInvalidSchedule is returned when a schedule's name, cron
+	// expression, time zone or target is malformed.
+	ErrInvalidSchedule = errors.New("invalid schedule")
+
+	// ErrQuestionNotFound is returned when no question has the given ID.
+	ErrQuestionNotFound = errors.New("question not found")
+
+	// ErrQuestionResolved is returned when answering a question that was
+	// already answered, defaulted or expired.
+	ErrQuestionResolved = errors.New("question already resolved")
+
+	// ErrInvalidQuestion is returned when a question or answer is
+	// malformed.
+	ErrInvalidQuestion = errors.New("invalid question")
+
+	// ErrInvalidEdit is returned when a message edit changes nothing.
+	ErrInvalidEdit = errors.New("invalid message edit")
+
+	// ErrMessageLegalHold is returned when editing a message that is
+	// under a legal hold.
+	ErrMessageLegalHold = errors.New("message under legal hold")
+
+	// ErrMessageNotRecallable is returned when recalling a message that a
+	// recipient has already read, or that must be kept.
+	ErrMessageNotRecallable = errors.New("message can't be recalled")
+
+	// ErrInboxRuleNotFound is returned when an agent has no inbox rule
+	// with the given name.
+	ErrInboxRuleNotFound = errors.New("inbox rule not found")
+
+	// ErrInboxRuleExists is returned when creating an inbox rule whose
+	// name the agent already uses.
+	ErrInboxRuleExists = errors.New("inbox rule already exists")
+
+	// ErrInvalidInboxRule is returned when an inbox rule's name,
+	// conditions or actions are malformed.
+	ErrInvalidInboxRule = errors.New("invalid inbox rule")
+
+	// ErrLabelNotFound is returned when an agent has no label with the
+	// given name.
+	ErrLabelNotFound = errors.New("label not found")
+
+	// ErrLabelExists is returned when creating a label whose name the
+	// agent already uses.
+	ErrLabelExists = errors.New("label already exists")
+
+	// ErrInvalidLabel is returned when a label name is malformed.
+	ErrInvalidLabel = errors.New("invalid label")
+
+	// ErrSavedSearchNotFound is returned when an agent has no search
+	// saved under the given name.
+	ErrSavedSearchNotFound = errors.New("saved search not found")
+
+	// ErrInvalidSavedSearch is returned when a saved search's name or
+	// query is malformed.
+	ErrInvalidSavedSearch = errors.New("invalid saved search")
+
+	// ErrThreadNotFound is returned when a thread has no messages.
+	ErrThreadNotFound = errors.New("thread not found")
+
+	// ErrThreadInUse is returned when merging or splitting a thread that
+	// a code review or plan review tracks by its ID.
+	ErrThreadInUse = errors.New("thread tracked by a review")
+
+	// ErrInvalidThreadOp is returned when a merge or split wouldn't
+	// change anything, such as merging a thread into itself.
+	ErrInvalidThreadOp = errors.New("invalid thread operation")
+
+	// ErrInvalidReply is returned when a reply has no body or nobody to
+	// go to.
+	ErrInvalidReply = errors.New("invalid reply")
+
+	// ErrWatcherArmed is returned when another watcher holds the lease
+	// on an agent's inbox.
+	ErrWatcherArmed = errors.New("another watcher is already armed")
+
+	// ErrWatchLeaseLost is returned when a watcher's lease expired or
+	// was taken over by another watcher.
+	ErrWatchLeaseLost = errors.New("watcher lease lost")
+
+	// ErrInvalidCapability is returned when a capability or capability
+	// address is malformed.
+	ErrInvalidCapability = errors.New("invalid capability")
+
+	// ErrNoCapableAgent is returned when no online agent with a
+	// capability can take mail or a task addressed to it.
+	ErrNoCapableAgent = errors.New("no capable agent")
 )
@@ -0,0 +1,226 @@
+package mail
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+// labelNameRE is the set of valid label names. Slashes nest labels, as in
+// "projects/substrate".
+var labelNameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9._/-]*$`)
+
+// validateLabelName checks that a label name is well formed.
+func validateLabelName(name string) error {
+	if !labelNameRE.MatchString(name) {
+		return fmt.Errorf("%w: name %q must be lowercase letters, "+
+			"digits, '.', '_', '/' or '-'", ErrInvalidLabel, name)
+	}
+
+	return nil
+}
+
+// CreateLabel creates a label an agent can file its messages under.
+func (s *Service) CreateLabel(ctx context.Context, agentID int64,
+	name string,
+) (store.Label, error) {
+	if err := validateLabelName(name); err != nil {
+		return store.Label{}, err
+	}
+
+	if _, err := s.store.GetAgent(ctx, agentID); err != nil {
+		return store.Label{}, fmt.Errorf("agent not found: %w", err)
+	}
+
+	_, err := s.store.GetLabelByName(ctx, agentID, name)
+	switch {
+	case err == nil:
+		return store.Label{}, fmt.Errorf("%w: %s", ErrLabelExists, name)
+
+	case !errors.Is(err, sql.ErrNoRows):
+		return store.Label{}, fmt.Errorf("failed to get label: %w", err)
+	}
+
+	label, err := s.store.CreateLabel(ctx, agentID, name)
+	if err != nil {
+		return store.Label{}, fmt.Errorf("failed to create label: %w",
+			err)
+	}
+
+	return label, nil
+}
+
+// ListLabels returns the agent's labels by name, with how many messages in
+// its inbox carry each.
+func (s *Service) ListLabels(ctx context.Context,
+	agentID int64,
+) ([]store.Label, error) {
+	labels, err := s.store.ListLabels(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+
+	return labels, nil
+}
+
+// DeleteLabel deletes one of the agent's labels and removes it from the
+// messages that carry it. The messages themselves are left as they are.
+func (s *Service) DeleteLabel(ctx context.Context, agentID int64,
+	name string,
+) error {
+	deleted, err := s.store.DeleteLabel(ctx, agentID, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete label: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %s", ErrLabelNotFound, name)
+	}
+
+	return nil
+}
+
+// LabelMessageRequest adds labels to and removes labels from an agent's copy
+// of a message.
+type LabelMessageRequest struct {
+	// AgentID is the recipient whose copy of the message is labeled.
+	AgentID int64
+
+	// MessageID is the message to label.
+	MessageID int64
+
+	// Add are the labels to apply. Labels the agent doesn't have yet are
+	// created.
+	Add []string
+
+	// Remove are the labels to take off. Removing a label the message
+	// doesn't carry is a no-op.
+	Remove []string
+}
+
+// LabelMessage applies and removes labels on an agent's copy of a message it
+// received, returning the labels the message carries afterwards. Labels are
+// per recipient, so other recipients of the message don't see them.
+func (s *Service) LabelMessage(ctx context.Context,
+	req LabelMessageRequest,
+) ([]string, error) {
+	for _, name := range req.Add {
+		if err := validateLabelName(name); err != nil {
+			return nil, err
+		}
+	}
+
+	var labels []string
+	err := s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		_, err := txStore.GetMessageRecipient(
+			ctx, req.MessageID, req.AgentID,
+		)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("%w: agent %d didn't receive "+
+				"message %d", ErrMessageNotFound, req.AgentID,
+				req.MessageID)
+
+		case err != nil:
+			return fmt.Errorf("failed to get recipient: %w", err)
+		}
+
+		for _, name := range req.Add {
+			err := addMessageLabel(
+				ctx, txStore, req.MessageID, req.AgentID, name,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, name := range req.Remove {
+			label, err := txStore.GetLabelByName(
+				ctx, req.AgentID, name,
+			)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return fmt.Errorf("%w: %s", ErrLabelNotFound,
+					name)
+
+			case err != nil:
+				return fmt.Errorf("failed to get label: %w",
+					err)
+			}
+
+			_, err = txStore.RemoveMessageLabel(
+				ctx, req.MessageID, req.AgentID, label.ID,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to remove label: %w",
+					err)
+			}
+		}
+
+		byMessage, err := txStore.ListMessageLabels(
+			ctx, req.AgentID, []int64{req.MessageID},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to list labels: %w", err)
+		}
+		labels = byMessage[req.MessageID]
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+// addMessageLabel applies a label to a recipient's copy of a message,
+// creating the label if the recipient doesn't have it yet.
+func addMessageLabel(ctx context.Context, txStore store.Storage,
+	messageID, agentID int64, name string,
+) error {
+	label, err := txStore.GetLabelByName(ctx, agentID, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		label, err = txStore.CreateLabel(ctx, agentID, name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get label: %w", err)
+	}
+
+	err = txStore.AddMessageLabel(ctx, messageID, agentID, label.ID)
+	if err != nil {
+		return fmt.Errorf("failed to apply label: %w", err)
+	}
+
+	return nil
+}
+
+// attachLabels fills in the labels an agent applied to each of the given
+// messages.
+func (s *Service) attachLabels(ctx context.Context, agentID int64,
+	msgs []InboxMessage,
+) error {
+	if agentID == 0 || len(msgs) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(msgs))
+	for i, msg := range msgs {
+		ids[i] = msg.ID
+	}
+
+	labels, err := s.store.ListMessageLabels(ctx, agentID, ids)
+	if err != nil {
+		return fmt.Errorf("failed to list message labels: %w", err)
+	}
+	for i := range msgs {
+		msgs[i].Labels = labels[msgs[i].ID]
+	}
+
+	return nil
+}
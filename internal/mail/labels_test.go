@@ -0,0 +1,256 @@
+package mail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLabelMessage tests applying and removing labels by hand, and filtering
+// the inbox, its counts and search by label.
+func TestLabelMessage(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	user := createTestAgent(t, storage, "User")
+	alice := createTestAgent(t, storage, "Alice")
+	bob := createTestAgent(t, storage, "Bob")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	send := func(subject string) int64 {
+		resp := svc.handleSendMail(ctx, SendMailRequest{
+			SenderID:       alice.ID,
+			RecipientNames: []string{"User", "Bob"},
+			Subject:        subject,
+			Body:           "Deploy notes for " + subject,
+			Priority:       PriorityNormal,
+		})
+		require.NoError(t, resp.Error)
+
+		return resp.MessageID
+	}
+	deploy := send("Deploy api")
+	release := send("Release web")
+	send("Lunch")
+
+	// Applying a label creates it, and a message can carry several.
+	labels, err := svc.LabelMessage(ctx, LabelMessageRequest{
+		AgentID:   user.ID,
+		MessageID: deploy,
+		Add:       []string{"ops", "projects/api"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"ops", "projects/api"}, labels)
+
+	labels, err = svc.LabelMessage(ctx, LabelMessageRequest{
+		AgentID:   user.ID,
+		MessageID: release,
+		Add:       []string{"ops"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"ops"}, labels)
+
+	// Labels are per recipient.
+	resp := svc.handleFetchInbox(ctx, FetchInboxRequest{
+		AgentID: bob.ID,
+		Label:   "ops",
+	})
+	require.NoError(t, resp.Error)
+	require.Empty(t, resp.Messages)
+
+	resp = svc.handleFetchInbox(ctx, FetchInboxRequest{
+		AgentID: user.ID,
+		Label:   "ops",
+	})
+	require.NoError(t, resp.Error)
+	require.Len(t, resp.Messages, 2)
+	require.Equal(t, release, resp.Messages[0].ID)
+	require.Equal(t, []string{"ops"}, resp.Messages[0].Labels)
+	require.Equal(t, deploy, resp.Messages[1].ID)
+	require.Equal(
+		t, []string{"ops", "projects/api"}, resp.Messages[1].Labels,
+	)
+
+	// Archived messages keep their labels and can still be found by
+	// them, but no longer count towards the label in the inbox.
+	require.NoError(t, storage.PersistRecipientState(
+		ctx, store.PersistRecipientStateParams{
+			MessageID: release,
+			AgentID:   user.ID,
+			State:     StateArchivedStr.String(),
+		},
+	))
+
+	resp = svc.handleFetchInbox(ctx, FetchInboxRequest{
+		AgentID: user.ID,
+		Label:   "ops",
+	})
+	require.NoError(t, resp.Error)
+	require.Len(t, resp.Messages, 2)
+
+	require.True(t, resp.HasCategoryCounts)
+	require.Len(t, resp.CategoryCounts.Labels, 2)
+	ops := resp.CategoryCounts.Labels[0]
+	require.Equal(t, "ops", ops.Name)
+	require.EqualValues(t, 1, ops.MessageCount)
+	require.EqualValues(t, 1, ops.UnreadCount)
+
+	// Search can be restricted to a label.
+	results, err := svc.Search(ctx, SearchRequest{
+		AgentID: user.ID,
+		Query:   "Deploy",
+	})
+	require.NoError(t, err)
+	require.Len(t, results.Hits, 3)
+
+	results, err = svc.Search(ctx, SearchRequest{
+		AgentID: user.ID,
+		Query:   "Deploy",
+		Label:   "projects/api",
+	})
+	require.NoError(t, err)
+	require.Len(t, results.Hits, 1)
+	require.Equal(t, deploy, results.Hits[0].ID)
+	require.Equal(t, []string{"ops", "projects/api"},
+		results.Hits[0].Labels)
+
+	// Removing a label leaves the others.
+	labels, err = svc.LabelMessage(ctx, LabelMessageRequest{
+		AgentID:   user.ID,
+		MessageID: deploy,
+		Remove:    []string{"ops"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"projects/api"}, labels)
+
+	// Deleting a label takes it off every message.
+	require.NoError(t, svc.DeleteLabel(ctx, user.ID, "projects/api"))
+	read := svc.handleReadMessage(ctx, ReadMessageRequest{
+		AgentID:   user.ID,
+		MessageID: deploy,
+	})
+	require.NoError(t, read.Error)
+	require.Empty(t, read.Message.Labels)
+
+	err = svc.DeleteLabel(ctx, user.ID, "projects/api")
+	require.ErrorIs(t, err, ErrLabelNotFound)
+
+	_, err = svc.LabelMessage(ctx, LabelMessageRequest{
+		AgentID:   user.ID,
+		MessageID: deploy,
+		Remove:    []string{"missing"},
+	})
+	require.ErrorIs(t, err, ErrLabelNotFound)
+
+	// Only recipients can label a message.
+	_, err = svc.LabelMessage(ctx, LabelMessageRequest{
+		AgentID:   alice.ID,
+		MessageID: deploy,
+		Add:       []string{"ops"},
+	})
+	require.ErrorIs(t, err, ErrMessageNotFound)
+
+	_, err = svc.LabelMessage(ctx, LabelMessageRequest{
+		AgentID:   user.ID,
+		MessageID: deploy,
+		Add:       []string{"Bad Label"},
+	})
+	require.ErrorIs(t, err, ErrInvalidLabel)
+}
+
+// TestCreateLabel tests creating and listing labels.
+func TestCreateLabel(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	user := createTestAgent(t, storage, "User")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	_, err := svc.CreateLabel(ctx, user.ID, "waiting")
+	require.NoError(t, err)
+	_, err = svc.CreateLabel(ctx, user.ID, "follow-up")
+	require.NoError(t, err)
+
+	_, err = svc.CreateLabel(ctx, user.ID, "waiting")
+	require.ErrorIs(t, err, ErrLabelExists)
+
+	_, err = svc.CreateLabel(ctx, user.ID, "/waiting")
+	require.ErrorIs(t, err, ErrInvalidLabel)
+
+	labels, err := svc.ListLabels(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, labels, 2)
+	require.Equal(t, "follow-up", labels[0].Name)
+	require.Equal(t, "waiting", labels[1].Name)
+	require.Zero(t, labels[0].MessageCount)
+}
+
+// TestInboxRuleLabel tests that inbox rules can label the messages they
+// match.
+func TestInboxRuleLabel(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	user := createTestAgent(t, storage, "User")
+	reviewer := createTestAgent(t, storage, "reviewer-alpha")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	_, err := svc.CreateInboxRule(ctx, CreateInboxRuleRequest{
+		AgentID: user.ID,
+		Name:    "reviews",
+		Conditions: store.InboxRuleConditions{
+			SenderPrefix: "reviewer-",
+		},
+		Actions: store.InboxRuleActions{
+			Archive: true,
+			Label:   "reviews",
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = svc.CreateInboxRule(ctx, CreateInboxRuleRequest{
+		AgentID: user.ID,
+		Name:    "bad-label",
+		Conditions: store.InboxRuleConditions{
+			SenderPrefix: "reviewer-",
+		},
+		Actions: store.InboxRuleActions{Label: "Reviews!"},
+	})
+	require.ErrorIs(t, err, ErrInvalidInboxRule)
+
+	resp := svc.handleSendMail(ctx, SendMailRequest{
+		SenderID:       reviewer.ID,
+		RecipientNames: []string{"User"},
+		Subject:        "Review done",
+		Body:           "LGTM.",
+		Priority:       PriorityNormal,
+	})
+	require.NoError(t, resp.Error)
+
+	fetch := svc.handleFetchInbox(ctx, FetchInboxRequest{
+		AgentID: user.ID,
+		Label:   "reviews",
+	})
+	require.NoError(t, fetch.Error)
+	require.Len(t, fetch.Messages, 1)
+	require.Equal(t, resp.MessageID, fetch.Messages[0].ID)
+	require.Equal(t, "archived", fetch.Messages[0].State)
+	require.Equal(t, []string{"reviews"}, fetch.Messages[0].Labels)
+}
@@ -0,0 +1,120 @@
+package mail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/ratelimit"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRateLimits tests that a service with a rate limiter refuses sends and
+// searches past an agent's limit, and that every service holds senders to
+// their daily storage quota, including when they edit a message.
+func TestRateLimits(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	alice := createTestAgent(t, storage, "Alice")
+	bob := createTestAgent(t, storage, "Bob")
+
+	limiter := ratelimit.NewLimiter(storage)
+	svc := NewService(ServiceConfig{
+		Store:       storage,
+		RateLimiter: limiter,
+	})
+	defer svc.OnStop(ctx)
+
+	_, err := limiter.Set(ctx, alice.ID, ratelimit.ClassSend, 1,
+		time.Minute, 0)
+	require.NoError(t, err)
+	_, err = limiter.Set(ctx, alice.ID, ratelimit.ClassSearch, 1,
+		time.Minute, 0)
+	require.NoError(t, err)
+
+	req := SendMailRequest{
+		SenderID:       alice.ID,
+		RecipientNames: []string{"Bob"},
+		Subject:        "hi",
+		Body:           "hello",
+		Priority:       PriorityNormal,
+	}
+	sent, err := svc.Send(ctx, req)
+	require.NoError(t, err)
+
+	resp := svc.Receive(ctx, req)
+	sendResp, err := resp.Unpack()
+	require.NoError(t, err)
+	require.ErrorIs(t, sendResp.(SendMailResponse).Error,
+		ratelimit.ErrRateLimited)
+
+	_, err = svc.Search(ctx, SearchRequest{AgentID: alice.ID, Query: "hi"})
+	require.NoError(t, err)
+	_, err = svc.Search(ctx, SearchRequest{AgentID: alice.ID, Query: "hi"})
+	require.ErrorIs(t, err, ratelimit.ErrRateLimited)
+
+	// Edits share the send limit.
+	_, err = svc.EditMessage(ctx, EditMessageRequest{
+		SenderID:  alice.ID,
+		MessageID: sent.MessageID,
+		Body:      "hello again",
+	})
+	require.ErrorIs(t, err, ratelimit.ErrRateLimited)
+
+	// Bob isn't rate limited, but may only store 10 bytes a day, which
+	// even a service without a rate limiter enforces.
+	_, err = limiter.Set(ctx, bob.ID, ratelimit.ClassBodyBytes, 10, 0, 0)
+	require.NoError(t, err)
+
+	plain := NewServiceWithStore(storage)
+	defer plain.OnStop(ctx)
+
+	full, err := plain.Send(ctx, SendMailRequest{
+		SenderID:       bob.ID,
+		RecipientNames: []string{"Alice"},
+		Subject:        "re",
+		Priority:       PriorityNormal,
+		Body:           "0123456789",
+	})
+	require.NoError(t, err)
+
+	_, err = plain.Send(ctx, SendMailRequest{
+		SenderID:       bob.ID,
+		RecipientNames: []string{"Alice"},
+		Subject:        "re",
+		Priority:       PriorityNormal,
+		Body:           "!",
+	})
+	require.ErrorIs(t, err, ratelimit.ErrQuotaExceeded)
+
+	_, err = plain.Subscribe(ctx, alice.ID, "news")
+	require.NoError(t, err)
+	_, err = plain.Publish(ctx, PublishRequest{
+		SenderID:  bob.ID,
+		TopicName: "news",
+		Subject:   "news",
+		Body:      "!",
+		Priority:  PriorityNormal,
+	})
+	require.ErrorIs(t, err, ratelimit.ErrQuotaExceeded)
+
+	// Growing a sent message's body past the quota is refused too, while
+	// shrinking it is fine.
+	_, err = plain.EditMessage(ctx, EditMessageRequest{
+		SenderID:  bob.ID,
+		MessageID: full.MessageID,
+		Body:      "0123456789!",
+	})
+	require.ErrorIs(t, err, ratelimit.ErrQuotaExceeded)
+
+	_, err = plain.EditMessage(ctx, EditMessageRequest{
+		SenderID:  bob.ID,
+		MessageID: full.MessageID,
+		Body:      "012345678",
+	})
+	require.NoError(t, err)
+}
@@ -139,6 +139,23 @@ type SendMailRequest struct {
 	// with the same key already exists, the original response is returned
 	// instead of creating a duplicate.
 	IdempotencyKey string
+
+	// DeliveryMode controls what happens when some recipients can't be
+	// delivered to. The default, DeliveryModeAtomic, fails the whole send.
+	DeliveryMode DeliveryMode
+
+	// SendAt is an optional time to deliver the message at. If it is in
+	// the future, the message is scheduled instead of sent, and the
+	// recipients are resolved when it is delivered.
+	SendAt *time.Time
+
+	// Metadata is optional key/value metadata stored with the message,
+	// which recipients' inbox rules can match on.
+	Metadata map[string]string
+
+	// question, if set, records the message as a structured question in
+	// the same transaction. Only AskQuestion sets it.
+	question *store.CreateQuestionParams
 }
 
 // MessageType implements actor.Message.
@@ -152,6 +169,18 @@ type SendMailResponse struct {
 	// ThreadID is the thread ID of the message.
 	ThreadID string
 
+	// Expansions lists the agents each recipient group or alias resolved
+	// to.
+	Expansions []RecipientExpansion
+
+	// Deliveries reports the outcome for each recipient, in the order
+	// they were addressed.
+	Deliveries []RecipientDelivery
+
+	// ScheduledID is the ID of the scheduled message if the send was
+	// deferred to SendAt. MessageID is zero until it is delivered.
+	ScheduledID int64
+
 	// Error is any error that occurred during sending.
 	Error error
 }
@@ -189,6 +218,14 @@ type FetchInboxRequest struct {
 	// Category filters the inbox by server-side partition (Primary,
 	// Agents, Notifications). Empty disables category filtering.
 	Category store.InboxCategory
+
+	// OverdueOnly filters to unacked messages whose deadline has passed,
+	// ordered by how overdue they are.
+	OverdueOnly bool
+
+	// Label filters to the messages the agent filed under a label, in
+	// any state but trash. Empty disables label filtering.
+	Label string
 }
 
 // MessageType implements actor.Message.
@@ -212,6 +249,20 @@ type InboxMessage struct {
 	ReadAt           *time.Time
 	AckedAt          *time.Time
 	CreatedAt        time.Time
+
+	// EditedAt is when the sender last edited the message, or nil if it
+	// was never edited. Edit notifications carry the edited message with
+	// it set.
+	EditedAt *time.Time
+
+	// Labels are the names of the labels the recipient filed the message
+	// under. They are only filled in for a single agent's view.
+	Labels []string
+	// Repushed is set when an existing message is pushed again rather
+	// than just sent, such as when its snooze ends or its deadline
+	// re-notifies the recipient, so watchers that already saw it are
+	// woken again.
+	Repushed bool
 }
 
 // FetchInboxResponse is the response to a FetchInboxRequest.
@@ -372,6 +423,10 @@ type PublishRequest struct {
 	// with the same key already exists, the original response is returned
 	// instead of creating a duplicate.
 	IdempotencyKey string
+
+	// Metadata is optional key/value metadata stored with the message,
+	// which subscribers' inbox rules can match on.
+	Metadata map[string]string
 }
 
 // MessageType implements actor.Message.
@@ -385,5 +440,10 @@ type PublishResponse struct {
 	// RecipientsCount is the number of agents that received the message.
 	RecipientsCount int
 
+	// Queued is true when the topic is a queue, in which case the message
+	// waits to be claimed by a single subscriber instead of being fanned
+	// out.
+	Queued bool
+
 	Error error
 }
@@ -30,6 +30,10 @@ type NotificationHub struct {
 
 	// topicSubscribers maps topic IDs to their subscribers.
 	topicSubscribers map[int64][]subscriber
+
+	// firehose maps subscriber IDs to the channels of subscribers that
+	// receive every notification.
+	firehose map[string]chan<- HubNotification
 }
 
 // NewNotificationHub creates a new notification hub actor.
@@ -37,6 +41,7 @@ func NewNotificationHub() *NotificationHub {
 	return &NotificationHub{
 		agentSubscribers: make(map[int64][]subscriber),
 		topicSubscribers: make(map[int64][]subscriber),
+		firehose:         make(map[string]chan<- HubNotification),
 	}
 }
 
@@ -62,6 +67,26 @@ func (n *NotificationHub) Receive(ctx context.Context,
 		resp := n.handleNotifyTopic(m)
 		return fn.Ok[NotificationResponse](resp)
 
+	case NotifyStateChangeMsg:
+		change := m.Change
+		n.notifyFirehose(HubNotification{
+			AgentIDs:    []int64{change.AgentID},
+			StateChange: &change,
+		})
+		return fn.Ok[NotificationResponse](NotifyStateChangeResponse{})
+
+	case SubscribeFirehoseMsg:
+		n.firehose[m.SubscriberID] = m.DeliveryChan
+		return fn.Ok[NotificationResponse](
+			SubscribeFirehoseResponse{Success: true},
+		)
+
+	case UnsubscribeFirehoseMsg:
+		delete(n.firehose, m.SubscriberID)
+		return fn.Ok[NotificationResponse](
+			UnsubscribeFirehoseResponse{Success: true},
+		)
+
 	default:
 		return fn.Err[NotificationResponse](
 			ErrUnknownRequestType,
@@ -131,6 +156,11 @@ func (n *NotificationHub) handleNotifyAgent(
 		}
 	}
 
+	n.notifyFirehose(HubNotification{
+		AgentIDs: []int64{msg.AgentID},
+		Message:  msg.Message,
+	})
+
 	return NotifyAgentResponse{DeliveredCount: deliveredCount}
 }
 
@@ -151,9 +181,27 @@ func (n *NotificationHub) handleNotifyTopic(
 		}
 	}
 
+	n.notifyFirehose(HubNotification{
+		AgentIDs:  msg.AgentIDs,
+		TopicID:   msg.TopicID,
+		TopicName: msg.TopicName,
+		Message:   msg.Message,
+	})
+
 	return NotifyTopicResponse{DeliveredCount: deliveredCount}
 }
 
+// notifyFirehose sends a notification to every firehose subscriber.
+func (n *NotificationHub) notifyFirehose(notif HubNotification) {
+	for _, ch := range n.firehose {
+		// Non-blocking send, as for agent subscribers.
+		select {
+		case ch <- notif:
+		default:
+		}
+	}
+}
+
 // SubscriberCount returns the number of active subscribers for an agent.
 // This is a convenience method for testing; in production, use Ask with a
 // dedicated message type.
@@ -212,6 +212,73 @@ func TestNotificationHubNotifyTopic(t *testing.T) {
 	}
 }
 
+// TestNotificationHubFirehose tests that firehose subscribers receive every
+// notification along with the agents and topic it was for.
+func TestNotificationHubFirehose(t *testing.T) {
+	t.Parallel()
+
+	system := actor.NewActorSystem()
+	defer system.Shutdown(context.Background())
+
+	hub := NewNotificationHub()
+	hubRef := NotificationHubKey.Spawn(system, "test-hub", hub)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	firehose := make(chan HubNotification, 10)
+	_, err := hubRef.Ask(ctx, SubscribeFirehoseMsg{
+		SubscriberID: "fire",
+		DeliveryChan: firehose,
+	}).Await(ctx).Unpack()
+	require.NoError(t, err)
+
+	// Agent notifications arrive with the agent they were for, even
+	// though nobody subscribed to that agent.
+	hubRef.Ask(ctx, NotifyAgentMsg{
+		AgentID: 7,
+		Message: InboxMessage{ID: 1},
+	}).Await(ctx)
+
+	hubRef.Ask(ctx, NotifyTopicMsg{
+		TopicID:   10,
+		TopicName: "builds",
+		AgentIDs:  []int64{1, 2},
+		Message:   InboxMessage{ID: 2},
+	}).Await(ctx)
+
+	select {
+	case n := <-firehose:
+		require.Equal(t, []int64{7}, n.AgentIDs)
+		require.Zero(t, n.TopicID)
+		require.Equal(t, int64(1), n.Message.ID)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("agent notification not delivered to firehose")
+	}
+
+	select {
+	case n := <-firehose:
+		require.Equal(t, []int64{1, 2}, n.AgentIDs)
+		require.Equal(t, int64(10), n.TopicID)
+		require.Equal(t, "builds", n.TopicName)
+		require.Equal(t, int64(2), n.Message.ID)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("topic notification not delivered to firehose")
+	}
+
+	_, err = hubRef.Ask(ctx, UnsubscribeFirehoseMsg{
+		SubscriberID: "fire",
+	}).Await(ctx).Unpack()
+	require.NoError(t, err)
+
+	hubRef.Ask(ctx, NotifyAgentMsg{AgentID: 7}).Await(ctx)
+	select {
+	case <-firehose:
+		t.Fatal("unsubscribed firehose received a notification")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
 // TestNotificationHubNonBlockingSend tests that notifications don't block.
 func TestNotificationHubNonBlockingSend(t *testing.T) {
 	t.Parallel()
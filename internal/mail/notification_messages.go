@@ -17,16 +17,22 @@ type NotificationResponse interface {
 }
 
 // Ensure all request types implement NotificationRequest.
-func (SubscribeAgentMsg) isNotificationRequest()   {}
-func (UnsubscribeAgentMsg) isNotificationRequest() {}
-func (NotifyAgentMsg) isNotificationRequest()      {}
-func (NotifyTopicMsg) isNotificationRequest()      {}
+func (SubscribeAgentMsg) isNotificationRequest()      {}
+func (UnsubscribeAgentMsg) isNotificationRequest()    {}
+func (NotifyAgentMsg) isNotificationRequest()         {}
+func (NotifyTopicMsg) isNotificationRequest()         {}
+func (NotifyStateChangeMsg) isNotificationRequest()   {}
+func (SubscribeFirehoseMsg) isNotificationRequest()   {}
+func (UnsubscribeFirehoseMsg) isNotificationRequest() {}
 
 // Ensure all response types implement NotificationResponse.
-func (SubscribeAgentResponse) isNotificationResponse()   {}
-func (UnsubscribeAgentResponse) isNotificationResponse() {}
-func (NotifyAgentResponse) isNotificationResponse()      {}
-func (NotifyTopicResponse) isNotificationResponse()      {}
+func (SubscribeAgentResponse) isNotificationResponse()      {}
+func (UnsubscribeAgentResponse) isNotificationResponse()    {}
+func (NotifyAgentResponse) isNotificationResponse()         {}
+func (NotifyTopicResponse) isNotificationResponse()         {}
+func (NotifyStateChangeResponse) isNotificationResponse()   {}
+func (SubscribeFirehoseResponse) isNotificationResponse()   {}
+func (UnsubscribeFirehoseResponse) isNotificationResponse() {}
 
 // SubscribeAgentMsg registers a subscriber for an agent's messages.
 type SubscribeAgentMsg struct {
@@ -97,6 +103,10 @@ type NotifyTopicMsg struct {
 	// TopicID is the topic whose subscribers should be notified.
 	TopicID int64
 
+	// TopicName is the name of the topic, passed on to firehose
+	// subscribers.
+	TopicName string
+
 	// AgentIDs are the recipient agents to notify.
 	AgentIDs []int64
 
@@ -113,6 +123,85 @@ type NotifyTopicResponse struct {
 	DeliveredCount int
 }
 
+// NotifyStateChangeMsg tells firehose subscribers that a recipient's copy
+// of a message changed state, such as being read or archived. Agent
+// subscribers only receive new mail, so they aren't told.
+type NotifyStateChangeMsg struct {
+	actor.BaseMessage
+
+	// Change is the state change the thread FSM emitted.
+	Change NotifyStateChange
+}
+
+// MessageType implements actor.Message.
+func (NotifyStateChangeMsg) MessageType() string {
+	return "NotifyStateChangeMsg"
+}
+
+// NotifyStateChangeResponse is the response to NotifyStateChangeMsg.
+type NotifyStateChangeResponse struct{}
+
+// SubscribeFirehoseMsg registers a subscriber for every notification the hub
+// delivers, whichever agents or topic it is for.
+type SubscribeFirehoseMsg struct {
+	actor.BaseMessage
+
+	// SubscriberID is a unique identifier for this subscriber.
+	SubscriberID string
+
+	// DeliveryChan is the channel to send notifications to.
+	DeliveryChan chan<- HubNotification
+}
+
+// MessageType implements actor.Message.
+func (SubscribeFirehoseMsg) MessageType() string {
+	return "SubscribeFirehoseMsg"
+}
+
+// SubscribeFirehoseResponse is the response to SubscribeFirehoseMsg.
+type SubscribeFirehoseResponse struct {
+	Success bool
+}
+
+// UnsubscribeFirehoseMsg removes a firehose subscriber.
+type UnsubscribeFirehoseMsg struct {
+	actor.BaseMessage
+
+	// SubscriberID identifies which subscriber to remove.
+	SubscriberID string
+}
+
+// MessageType implements actor.Message.
+func (UnsubscribeFirehoseMsg) MessageType() string {
+	return "UnsubscribeFirehoseMsg"
+}
+
+// UnsubscribeFirehoseResponse is the response to UnsubscribeFirehoseMsg.
+type UnsubscribeFirehoseResponse struct {
+	Success bool
+}
+
+// HubNotification is a notification as delivered to firehose subscribers,
+// which unlike agent subscribers need to be told who it was for.
+type HubNotification struct {
+	// AgentIDs are the agents notified. Agent 0 stands for the global
+	// feed every message is also notified to.
+	AgentIDs []int64
+
+	// TopicID and TopicName are the topic the message was published to,
+	// set for topic notifications only.
+	TopicID   int64
+	TopicName string
+
+	// Message is the inbox message delivered. It is unset for state
+	// changes.
+	Message InboxMessage
+
+	// StateChange is set instead of Message when the notification is of
+	// the agent's copy of a message changing state.
+	StateChange *NotifyStateChange
+}
+
 // subscriber holds information about a single subscription.
 type subscriber struct {
 	id           string
@@ -0,0 +1,501 @@
+package mail
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/roasbeef/subtrate/internal/ratelimit"
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+const (
+	// questionTimeoutBatchSize caps the number of timed out questions
+	// handled per tick.
+	questionTimeoutBatchSize = 100
+)
+
+// QuestionState is where a question is in its lifecycle.
+type QuestionState string
+
+const (
+	// QuestionPending questions are waiting for an answer.
+	QuestionPending QuestionState = "pending"
+
+	// QuestionAnswered questions were answered by a reply in their
+	// thread.
+	QuestionAnswered QuestionState = "answered"
+
+	// QuestionDefaulted questions timed out and took their default
+	// answer.
+	QuestionDefaulted QuestionState = "defaulted"
+
+	// QuestionExpired questions timed out without a default answer.
+	QuestionExpired QuestionState = "expired"
+)
+
+// Resolved returns true if the question is no longer waiting for an answer.
+func (s QuestionState) Resolved() bool {
+	return s != QuestionPending
+}
+
+// AskQuestionRequest describes a structured question.
+type AskQuestionRequest struct {
+	// SenderID is the agent asking the question.
+	SenderID int64
+
+	// RecipientNames are the agents, groups or aliases asked.
+	RecipientNames []string
+
+	// Question is the question itself, used as the message subject.
+	Question string
+
+	// Details is optional markdown context shown above the options.
+	Details string
+
+	// Options are the suggested answers. Recipients may still answer
+	// with free text.
+	Options []string
+
+	// DefaultAnswer is the answer used if the question times out. If
+	// options are given it must be one of them.
+	DefaultAnswer string
+
+	// Timeout is how long the question waits for an answer, and then
+	// how long it waits again after escalating to Fallback. Zero means
+	// the question never times out.
+	Timeout time.Duration
+
+	// Fallback is the agent the question is escalated to once it times
+	// out. It requires a timeout.
+	Fallback string
+
+	// Priority is the priority of the question, normal by default.
+	Priority Priority
+}
+
+// AskQuestion sends a question and records it so that the first reply in its
+// thread from one of the agents asked is correlated back as the answer.
+func (s *Service) AskQuestion(ctx context.Context,
+	req AskQuestionRequest,
+) (store.Question, error) {
+	err := s.allow(ctx, req.SenderID, ratelimit.ClassSend)
+	if err != nil {
+		return store.Question{}, err
+	}
+
+	req.Question = strings.TrimSpace(req.Question)
+	if req.Question == "" {
+		return store.Question{}, fmt.Errorf("%w: question is required",
+			ErrInvalidQuestion)
+	}
+	if len(req.RecipientNames) == 0 {
+		return store.Question{}, fmt.Errorf("%w: at least one "+
+			"recipient is required", ErrInvalidQuestion)
+	}
+
+	req.Options = slices.Clone(req.Options)
+	seen := make(map[string]bool, len(req.Options))
+	for i, option := range req.Options {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			return store.Question{}, fmt.Errorf("%w: option %d is "+
+				"empty", ErrInvalidQuestion, i+1)
+		}
+
+		key := strings.ToLower(option)
+		if seen[key] {
+			return store.Question{}, fmt.Errorf("%w: duplicate "+
+				"option %q", ErrInvalidQuestion, option)
+		}
+		seen[key] = true
+		req.Options[i] = option
+	}
+
+	if req.DefaultAnswer != "" && len(req.Options) > 0 {
+		option, ok := matchQuestionOption(
+			req.Options, req.DefaultAnswer,
+		)
+		if !ok {
+			return store.Question{}, fmt.Errorf("%w: default "+
+				"%q is not one of the options",
+				ErrInvalidQuestion, req.DefaultAnswer)
+		}
+		req.DefaultAnswer = option
+	}
+
+	if req.Timeout < 0 {
+		return store.Question{}, fmt.Errorf("%w: timeout must not be "+
+			"negative", ErrInvalidQuestion)
+	}
+	if req.Fallback != "" && req.Timeout == 0 {
+		return store.Question{}, fmt.Errorf("%w: a fallback requires "+
+			"a timeout", ErrInvalidQuestion)
+	}
+
+	if req.Priority == "" {
+		req.Priority = PriorityNormal
+	}
+
+	// The question is recorded in the same transaction as its message,
+	// so an answer can never arrive before the question exists.
+	params := &store.CreateQuestionParams{
+		QuestionID:    uuid.New().String(),
+		AskerID:       req.SenderID,
+		Question:      req.Question,
+		Options:       req.Options,
+		DefaultAnswer: req.DefaultAnswer,
+		Timeout:       req.Timeout,
+		Fallback:      req.Fallback,
+	}
+	resp := s.handleSendMail(ctx, SendMailRequest{
+		SenderID:       req.SenderID,
+		RecipientNames: req.RecipientNames,
+		Subject:        req.Question,
+		Body:           formatQuestionBody(req, params.QuestionID),
+		Priority:       req.Priority,
+		question:       params,
+	})
+	if resp.Error != nil {
+		return store.Question{}, resp.Error
+	}
+
+	return s.GetQuestion(ctx, params.QuestionID)
+}
+
+// GetQuestion returns a question and, once it is resolved, its answer.
+func (s *Service) GetQuestion(ctx context.Context,
+	questionID string,
+) (store.Question, error) {
+	question, err := s.store.GetQuestion(ctx, questionID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return store.Question{}, fmt.Errorf("%w: %s",
+			ErrQuestionNotFound, questionID)
+
+	case err != nil:
+		return store.Question{}, fmt.Errorf("failed to get question: "+
+			"%w", err)
+	}
+
+	return question, nil
+}
+
+// AnswerQuestion answers a question by replying in its thread as the given
+// agent. The reply is correlated back like any other, so it returns the
+// question as resolved by the reply.
+func (s *Service) AnswerQuestion(ctx context.Context, questionID string,
+	answererID int64, answer string,
+) (store.Question, error) {
+	question, err := s.GetQuestion(ctx, questionID)
+	if err != nil {
+		return store.Question{}, err
+	}
+
+	answer = strings.TrimSpace(answer)
+	switch {
+	case answer == "":
+		return store.Question{}, fmt.Errorf("%w: answer is required",
+			ErrInvalidQuestion)
+
+	case answererID == question.AskerID:
+		return store.Question{}, fmt.Errorf("%w: agents can't answer "+
+			"their own questions", ErrInvalidQuestion)
+
+	case QuestionState(question.State).Resolved():
+		return store.Question{}, fmt.Errorf("%w: %s is %s",
+			ErrQuestionResolved, questionID, question.State)
+	}
+
+	ok, err := mayAnswerQuestion(ctx, s.store, question, answererID)
+	if err != nil {
+		return store.Question{}, err
+	}
+	if !ok {
+		return store.Question{}, fmt.Errorf("%w: agent %d wasn't "+
+			"asked question %s", ErrUnauthorized, answererID,
+			questionID)
+	}
+
+	asker, err := s.store.GetAgent(ctx, question.AskerID)
+	if err != nil {
+		return store.Question{}, fmt.Errorf("asker not found: %w", err)
+	}
+
+	resp := s.handleSendMail(ctx, SendMailRequest{
+		SenderID:       answererID,
+		RecipientNames: []string{asker.Name},
+		Subject:        "Re: " + question.Question,
+		Body:           answer,
+		Priority:       PriorityNormal,
+		ThreadID:       question.ThreadID,
+	})
+	if resp.Error != nil {
+		return store.Question{}, resp.Error
+	}
+
+	return s.GetQuestion(ctx, questionID)
+}
+
+// QuestionOptionIndex returns the 1-based position of a question's answer
+// among its options, or zero if it was answered with free text or not at all.
+func QuestionOptionIndex(question store.Question) int {
+	if question.Answer == "" {
+		return 0
+	}
+
+	return slices.Index(question.Options, question.Answer) + 1
+}
+
+// correlateAnswers records a message sent into a thread as the answer to the
+// thread's pending questions that the sender may answer. Messages from anyone
+// else, such as the asker or a follower of the thread, are left alone.
+func correlateAnswers(ctx context.Context, txStore store.Storage,
+	threadID string, senderID, messageID int64, body string,
+) error {
+	questions, err := txStore.ListPendingQuestionsByThread(ctx, threadID)
+	if err != nil {
+		return fmt.Errorf("failed to list pending questions: %w", err)
+	}
+
+	for _, question := range questions {
+		if question.AskerID == senderID {
+			continue
+		}
+
+		mayAnswer, err := mayAnswerQuestion(
+			ctx, txStore, question, senderID,
+		)
+		if err != nil {
+			return err
+		}
+		if !mayAnswer {
+			continue
+		}
+
+		answer, ok := matchQuestionOption(question.Options, body)
+		if !ok {
+			answer = strings.TrimSpace(body)
+		}
+
+		_, err = txStore.ResolveQuestion(
+			ctx, store.ResolveQuestionParams{
+				ID:              question.ID,
+				State:           string(QuestionAnswered),
+				Answer:          answer,
+				AnsweredBy:      senderID,
+				AnswerMessageID: messageID,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record answer: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// mayAnswerQuestion reports whether an agent may answer a question: it must
+// be one of the agents the question was sent to or, once the question was
+// escalated, one it was escalated to.
+func mayAnswerQuestion(ctx context.Context, txStore store.Storage,
+	question store.Question, agentID int64,
+) (bool, error) {
+	ok, err := isMessageRecipient(ctx, txStore, question.MessageID, agentID)
+	if err != nil || ok {
+		return ok, err
+	}
+
+	if question.EscalatedAt == nil {
+		return false, nil
+	}
+
+	escalation, err := txStore.GetMessageByIdempotencyKey(
+		ctx, questionEscalationKey(question),
+	)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+
+	case err != nil:
+		return false, fmt.Errorf("failed to get escalation: %w", err)
+	}
+
+	return isMessageRecipient(ctx, txStore, escalation.ID, agentID)
+}
+
+// isMessageRecipient reports whether an agent received a message.
+func isMessageRecipient(ctx context.Context, txStore store.Storage,
+	messageID, agentID int64,
+) (bool, error) {
+	_, err := txStore.GetMessageRecipient(ctx, messageID, agentID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+
+	case err != nil:
+		return false, fmt.Errorf("failed to get recipient: %w", err)
+	}
+
+	return true, nil
+}
+
+// questionEscalationKey is the idempotency key of the message escalating a
+// question to its fallback.
+func questionEscalationKey(question store.Question) string {
+	return "question-" + question.QuestionID + "-escalation"
+}
+
+// matchQuestionOption matches a reply against a question's options. The
+// reply, or its first line, may name an option by its text or its number in
+// the list, ignoring case and trailing punctuation.
+func matchQuestionOption(options []string, reply string) (string, bool) {
+	if len(options) == 0 {
+		return "", false
+	}
+
+	reply = strings.TrimSpace(reply)
+	firstLine, _, _ := strings.Cut(reply, "\n")
+
+	for _, candidate := range []string{reply, firstLine} {
+		candidate = strings.TrimRight(
+			strings.TrimSpace(candidate), ".)!",
+		)
+
+		n, err := strconv.Atoi(candidate)
+		if err == nil && n >= 1 && n <= len(options) {
+			return options[n-1], true
+		}
+
+		for _, option := range options {
+			if strings.EqualFold(candidate, option) {
+				return option, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// RunQuestionTimeouts handles pending questions whose timeout has passed as
+// of now. A question with a fallback that hasn't been escalated yet is sent
+// to the fallback and given another timeout. Otherwise it takes its default
+// answer, or expires if it has none. It returns the number of questions
+// handled.
+func (s *Service) RunQuestionTimeouts(ctx context.Context,
+	now time.Time,
+) (int, error) {
+	questions, err := s.store.ListTimedOutQuestions(
+		ctx, now, questionTimeoutBatchSize,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list timed out questions: %w",
+			err)
+	}
+
+	for _, question := range questions {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		// A fallback that can't be reached is not retried: the
+		// question falls back to its default instead of waiting on it
+		// forever.
+		if question.Fallback != "" && question.EscalatedAt == nil &&
+			s.escalateQuestion(ctx, question) == nil {
+
+			err := s.store.EscalateQuestion(
+				ctx, question.ID, now.Add(question.Timeout),
+			)
+			if err != nil {
+				return 0, fmt.Errorf("failed to escalate "+
+					"question: %w", err)
+			}
+			continue
+		}
+
+		state := QuestionExpired
+		if question.DefaultAnswer != "" {
+			state = QuestionDefaulted
+		}
+		_, err := s.store.ResolveQuestion(
+			ctx, store.ResolveQuestionParams{
+				ID:     question.ID,
+				State:  string(state),
+				Answer: question.DefaultAnswer,
+			},
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to resolve question: %w",
+				err)
+		}
+	}
+
+	return len(questions), nil
+}
+
+// escalateQuestion sends a timed out question to its fallback recipient in
+// the question's thread, so the fallback's reply is correlated like any
+// other.
+func (s *Service) escalateQuestion(ctx context.Context,
+	question store.Question,
+) error {
+	// The original message already lists the options and the question
+	// ID, so the fallback gets it as is with a note on top.
+	msg, err := s.store.GetMessage(ctx, question.MessageID)
+	if err != nil {
+		return fmt.Errorf("failed to get question message: %w", err)
+	}
+	body := fmt.Sprintf("Escalated to you because nobody answered "+
+		"within %s.\n\n---\n\n%s", question.Timeout, msg.Body)
+
+	resp := s.handleSendMail(ctx, SendMailRequest{
+		SenderID:       question.AskerID,
+		RecipientNames: []string{question.Fallback},
+		Subject:        "Escalated: " + question.Question,
+		Body:           body,
+		Priority:       PriorityUrgent,
+		ThreadID:       question.ThreadID,
+		IdempotencyKey: questionEscalationKey(question),
+	})
+
+	return resp.Error
+}
+
+// formatQuestionBody renders a question as the markdown body of its message.
+func formatQuestionBody(req AskQuestionRequest, questionID string) string {
+	var b strings.Builder
+	if details := strings.TrimSpace(req.Details); details != "" {
+		fmt.Fprintf(&b, "%s\n\n", details)
+	}
+
+	if len(req.Options) > 0 {
+		b.WriteString("**Options:**\n\n")
+		for i, option := range req.Options {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, option)
+		}
+		b.WriteString("\nReply in this thread with an option's " +
+			"number or text, or with your own answer.\n")
+	} else {
+		b.WriteString("Reply in this thread with your answer.\n")
+	}
+
+	switch {
+	case req.DefaultAnswer != "" && req.Timeout > 0:
+		fmt.Fprintf(&b, "\nIf there's no answer within %s, the "+
+			"answer is: %s\n", req.Timeout, req.DefaultAnswer)
+
+	case req.Timeout > 0:
+		fmt.Fprintf(&b, "\nPlease answer within %s.\n", req.Timeout)
+	}
+
+	fmt.Fprintf(&b, "\nQuestion ID: %s\n", questionID)
+
+	return b.String()
+}
@@ -0,0 +1,281 @@
+package mail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestAskQuestion tests that questions are validated, and that replies in a
+// question's thread are correlated back as its answer whichever way they are
+// sent.
+func TestAskQuestion(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	asker := createTestAgent(t, storage, "Asker")
+	user := createTestAgent(t, storage, "User")
+	bystander := createTestAgent(t, storage, "Bystander")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	valid := AskQuestionRequest{
+		SenderID:       asker.ID,
+		RecipientNames: []string{"User"},
+		Question:       "Which database should the cache use?",
+		Options:        []string{"SQLite", "Postgres"},
+		DefaultAnswer:  "sqlite",
+	}
+
+	invalid := []func(*AskQuestionRequest){
+		func(r *AskQuestionRequest) { r.Question = " " },
+		func(r *AskQuestionRequest) { r.RecipientNames = nil },
+		func(r *AskQuestionRequest) { r.Options = []string{"A", ""} },
+		func(r *AskQuestionRequest) { r.Options = []string{"A", "a"} },
+		func(r *AskQuestionRequest) { r.DefaultAnswer = "Redis" },
+		func(r *AskQuestionRequest) { r.Fallback = "Asker" },
+		func(r *AskQuestionRequest) { r.Timeout = -time.Minute },
+	}
+	for i, mutate := range invalid {
+		req := valid
+		mutate(&req)
+		_, err := svc.AskQuestion(ctx, req)
+		require.ErrorIs(t, err, ErrInvalidQuestion, "case %d", i)
+	}
+
+	question, err := svc.AskQuestion(ctx, valid)
+	require.NoError(t, err)
+	require.Equal(t, string(QuestionPending), question.State)
+	require.Equal(t, "SQLite", question.DefaultAnswer)
+	require.Equal(t, []string{"SQLite", "Postgres"}, question.Options)
+	require.Nil(t, question.TimeoutAt)
+
+	// The question arrives as an ordinary message listing its options.
+	msg, err := storage.GetMessage(ctx, question.MessageID)
+	require.NoError(t, err)
+	require.Equal(t, valid.Question, msg.Subject)
+	require.Contains(t, msg.Body, "2. Postgres")
+	require.Contains(t, msg.Body, question.QuestionID)
+
+	// The asker following up in its own thread doesn't answer it.
+	resp := svc.handleSendMail(ctx, SendMailRequest{
+		SenderID:       asker.ID,
+		RecipientNames: []string{"User"},
+		Subject:        "Re: " + valid.Question,
+		Body:           "Any thoughts?",
+		Priority:       PriorityNormal,
+		ThreadID:       question.ThreadID,
+	})
+	require.NoError(t, resp.Error)
+
+	question, err = svc.GetQuestion(ctx, question.QuestionID)
+	require.NoError(t, err)
+	require.Equal(t, string(QuestionPending), question.State)
+
+	// Nor does an agent that wasn't asked, whether it replies in the
+	// thread or answers directly.
+	resp = svc.handleSendMail(ctx, SendMailRequest{
+		SenderID:       bystander.ID,
+		RecipientNames: []string{"Asker"},
+		Subject:        "Re: " + valid.Question,
+		Body:           "1",
+		Priority:       PriorityNormal,
+		ThreadID:       question.ThreadID,
+	})
+	require.NoError(t, resp.Error)
+
+	_, err = svc.AnswerQuestion(
+		ctx, question.QuestionID, bystander.ID, "1",
+	)
+	require.ErrorIs(t, err, ErrUnauthorized)
+
+	question, err = svc.GetQuestion(ctx, question.QuestionID)
+	require.NoError(t, err)
+	require.Equal(t, string(QuestionPending), question.State)
+
+	// A reply naming an option by number is recorded as that option.
+	resp = svc.handleSendMail(ctx, SendMailRequest{
+		SenderID:       user.ID,
+		RecipientNames: []string{"Asker"},
+		Subject:        "Re: " + valid.Question,
+		Body:           "2.\n\nIt's already deployed.",
+		Priority:       PriorityNormal,
+		ThreadID:       question.ThreadID,
+	})
+	require.NoError(t, resp.Error)
+
+	question, err = svc.GetQuestion(ctx, question.QuestionID)
+	require.NoError(t, err)
+	require.Equal(t, string(QuestionAnswered), question.State)
+	require.Equal(t, "Postgres", question.Answer)
+	require.Equal(t, user.ID, question.AnsweredBy)
+	require.Equal(t, resp.MessageID, question.AnswerMessageID)
+	require.NotNil(t, question.AnsweredAt)
+
+	// Later replies don't change the answer.
+	_, err = svc.AnswerQuestion(ctx, question.QuestionID, user.ID, "1")
+	require.ErrorIs(t, err, ErrQuestionResolved)
+
+	// A free-form question records the reply text as is.
+	valid.Options = nil
+	valid.DefaultAnswer = ""
+	question, err = svc.AskQuestion(ctx, valid)
+	require.NoError(t, err)
+
+	_, err = svc.AnswerQuestion(ctx, question.QuestionID, asker.ID, "x")
+	require.ErrorIs(t, err, ErrInvalidQuestion)
+
+	question, err = svc.AnswerQuestion(
+		ctx, question.QuestionID, user.ID, " Whatever is simplest. ",
+	)
+	require.NoError(t, err)
+	require.Equal(t, string(QuestionAnswered), question.State)
+	require.Equal(t, "Whatever is simplest.", question.Answer)
+
+	// The answer was delivered to the asker like any reply, as was the
+	// bystander's.
+	inbox, err := storage.GetInboxMessages(ctx, asker.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, inbox, 3)
+
+	_, err = svc.GetQuestion(ctx, "no-such-question")
+	require.ErrorIs(t, err, ErrQuestionNotFound)
+}
+
+// TestRunQuestionTimeouts tests that an unanswered question is escalated to
+// its fallback once, that the fallback's answer is correlated, and that a
+// question nobody answers takes its default or expires.
+func TestRunQuestionTimeouts(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	asker := createTestAgent(t, storage, "Asker")
+	createTestAgent(t, storage, "User")
+	lead := createTestAgent(t, storage, "Lead")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	ask := func(fallback, defaultAnswer string) string {
+		question, err := svc.AskQuestion(ctx, AskQuestionRequest{
+			SenderID:       asker.ID,
+			RecipientNames: []string{"User"},
+			Question:       "Ship the migration today?",
+			Options:        []string{"Yes", "No"},
+			DefaultAnswer:  defaultAnswer,
+			Timeout:        time.Hour,
+			Fallback:       fallback,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, question.TimeoutAt)
+
+		return question.QuestionID
+	}
+
+	get := func(questionID string) string {
+		question, err := svc.GetQuestion(ctx, questionID)
+		require.NoError(t, err)
+		return question.State
+	}
+
+	escalated := ask("Lead", "No")
+	defaulted := ask("", "No")
+	expired := ask("", "")
+	now := time.Now()
+
+	// The fallback can't answer before the question is escalated to it.
+	_, err := svc.AnswerQuestion(ctx, escalated, lead.ID, "yes")
+	require.ErrorIs(t, err, ErrUnauthorized)
+
+	// Nothing has timed out yet.
+	n, err := svc.RunQuestionTimeouts(ctx, now)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	n, err = svc.RunQuestionTimeouts(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	require.Equal(t, string(QuestionPending), get(escalated))
+	require.Equal(t, string(QuestionDefaulted), get(defaulted))
+	require.Equal(t, string(QuestionExpired), get(expired))
+
+	question, err := svc.GetQuestion(ctx, defaulted)
+	require.NoError(t, err)
+	require.Equal(t, "No", question.Answer)
+	require.Zero(t, question.AnsweredBy)
+
+	// The fallback got the question in its thread.
+	inbox, err := storage.GetInboxMessages(ctx, lead.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, inbox, 1)
+	require.Contains(t, inbox[0].Body, "Escalated to you")
+	require.Contains(t, inbox[0].Body, "1. Yes")
+
+	question, err = svc.GetQuestion(ctx, escalated)
+	require.NoError(t, err)
+	require.NotNil(t, question.EscalatedAt)
+	require.Equal(t, question.ThreadID, inbox[0].ThreadID)
+
+	// An escalated question gets a second timeout but isn't escalated
+	// again.
+	n, err = svc.RunQuestionTimeouts(ctx, now.Add(90*time.Minute))
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	question, err = svc.AnswerQuestion(ctx, escalated, lead.ID, "yes")
+	require.NoError(t, err)
+	require.Equal(t, "Yes", question.Answer)
+	require.Equal(t, lead.ID, question.AnsweredBy)
+
+	n, err = svc.RunQuestionTimeouts(ctx, now.Add(3*time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	// A question whose fallback doesn't exist falls back to its default
+	// straight away.
+	ghost := ask("Ghost", "Yes")
+	n, err = svc.RunQuestionTimeouts(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, string(QuestionDefaulted), get(ghost))
+}
+
+// TestMatchQuestionOption tests how replies are matched against a question's
+// options.
+func TestMatchQuestionOption(t *testing.T) {
+	t.Parallel()
+
+	options := []string{"SQLite", "Postgres", "Both"}
+	tests := []struct {
+		reply string
+		want  string
+		ok    bool
+	}{
+		{reply: "Postgres", want: "Postgres", ok: true},
+		{reply: "  sqlite. ", want: "SQLite", ok: true},
+		{reply: "3", want: "Both", ok: true},
+		{reply: "2)", want: "Postgres", ok: true},
+		{reply: "both!\nBut SQLite first.", want: "Both", ok: true},
+		{reply: "4", ok: false},
+		{reply: "Postgres, probably", ok: false},
+		{reply: "", ok: false},
+	}
+	for _, tc := range tests {
+		got, ok := matchQuestionOption(options, tc.reply)
+		require.Equal(t, tc.ok, ok, "reply %q", tc.reply)
+		require.Equal(t, tc.want, got, "reply %q", tc.reply)
+	}
+
+	_, ok := matchQuestionOption(nil, "1")
+	require.False(t, ok)
+}
@@ -0,0 +1,580 @@
+package mail
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/agent"
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+const (
+	// RecipientAliasPrefix marks a recipient name as a recipient group or
+	// dynamic alias rather than an agent name.
+	RecipientAliasPrefix = "@"
+
+	// AliasProject expands "@project:<key>" to every agent whose project
+	// key is <key> or a path ending in it.
+	AliasProject = "project"
+
+	// AliasBranch expands "@branch:<name>" to every agent working on the
+	// given git branch.
+	AliasBranch = "branch"
+
+	// AliasOnline expands "@online" to every agent that has sent a
+	// heartbeat within the online threshold.
+	AliasOnline = "online"
+
+	// AliasReviewers expands "@reviewers" to every reviewer agent.
+	AliasReviewers = "reviewers"
+
+	// DefaultOnlineThreshold is how recently an agent must have sent a
+	// heartbeat to be included in "@online". It matches the window in
+	// which the heartbeat manager reports an agent as active.
+	DefaultOnlineThreshold = agent.DefaultActiveThreshold
+
+	// reviewerAgentPrefix is the name prefix of reviewer agents, see
+	// review.ReviewerAgentName.
+	reviewerAgentPrefix = "reviewer-"
+)
+
+// groupNameRE is the set of valid recipient group names.
+var groupNameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// RecipientExpansion records the agents a recipient group or dynamic alias
+// resolved to when a message was sent.
+type RecipientExpansion struct {
+	// Address is the group or alias as written by the sender, e.g.
+	// "@backend-team" or "@branch:main".
+	Address string
+
+	// AgentNames are the names of the agents the address resolved to.
+	AgentNames []string
+}
+
+// resolvedRecipients is the outcome of resolving a message's recipient
+// names.
+type resolvedRecipients struct {
+	// deliveries has an entry for each distinct recipient agent, and for
+	// each address that didn't resolve, in the order they were first
+	// named. Agent entries have no status until the message is delivered.
+	deliveries []RecipientDelivery
+
+	// agents are the resolved recipient agents keyed by ID.
+	agents map[int64]store.Agent
+
+	// expansions records the agents behind each group or alias.
+	expansions []resolvedExpansion
+
+	// routes records the agent the router picked for each capability
+	// address. Their message IDs are set once the message is created.
+	routes []CapabilityRoute
+}
+
+// resolvedExpansion is a group or alias along with the agents it resolved
+// to.
+type resolvedExpansion struct {
+	address string
+	agents  []store.Agent
+}
+
+// IsRecipientAlias reports whether a recipient name refers to a recipient
+// group or dynamic alias rather than a single agent.
+func IsRecipientAlias(name string) bool {
+	return strings.HasPrefix(name, RecipientAliasPrefix)
+}
+
+// ValidateGroupName checks that a recipient group name is well formed and
+// doesn't shadow a dynamic alias. A leading "@" is ignored.
+func ValidateGroupName(name string) error {
+	name = strings.TrimPrefix(name, RecipientAliasPrefix)
+	if !groupNameRE.MatchString(name) {
+		return fmt.Errorf("%w: %q must be lowercase letters, digits, "+
+			"'.', '_' or '-'", ErrInvalidGroupName, name)
+	}
+
+	switch name {
+	case AliasOnline, AliasReviewers, AliasProject, AliasBranch:
+		return fmt.Errorf("%w: %q is a reserved alias",
+			ErrInvalidGroupName, name)
+	}
+
+	return nil
+}
+
+// resolveRecipients resolves the recipient names of a message to agents.
+// Plain names must match an agent exactly. Names starting with "@" are
+// expanded to the members of the named recipient group, or to the agents
+// matching a dynamic alias at the time of sending. The sender is never
+// included in an expansion, but may still be named directly. An address
+// that resolves to no agents is an error, so a typo doesn't silently drop
+// a message, unless partial is set, in which case it is reported as an
+// unknown recipient instead. Names starting with "cap:" are routed to one
+// online agent with the capability, see routeCapability.
+func (s *Service) resolveRecipients(ctx context.Context,
+	txStore store.Storage, senderID int64, names []string, partial bool,
+) (resolvedRecipients, error) {
+	resolved := resolvedRecipients{
+		agents: make(map[int64]store.Agent),
+	}
+	addRecipient := func(a store.Agent) {
+		if _, ok := resolved.agents[a.ID]; ok {
+			return
+		}
+		resolved.agents[a.ID] = a
+		resolved.deliveries = append(
+			resolved.deliveries, RecipientDelivery{
+				Recipient: a.Name,
+				AgentID:   a.ID,
+			},
+		)
+	}
+	addUnknown := func(name string, err error) {
+		resolved.deliveries = append(
+			resolved.deliveries, RecipientDelivery{
+				Recipient: name,
+				Status:    RecipientUnknown,
+				Reason:    err.Error(),
+			},
+		)
+	}
+
+	for _, name := range names {
+		if IsCapabilityAddress(name) {
+			route, err := s.routeCapability(
+				ctx, txStore, name, senderID,
+			)
+			switch {
+			case partial && isUnroutable(err):
+				addUnknown(name, err)
+				continue
+
+			case err != nil:
+				return resolved, err
+			}
+
+			recipient, err := txStore.GetAgent(ctx, route.AgentID)
+			if err != nil {
+				return resolved, fmt.Errorf("failed to get "+
+					"recipient %q: %w", route.AgentName,
+					err)
+			}
+			addRecipient(recipient)
+
+			resolved.routes = append(resolved.routes, route)
+			resolved.expansions = append(
+				resolved.expansions, resolvedExpansion{
+					address: name,
+					agents:  []store.Agent{recipient},
+				},
+			)
+
+			continue
+		}
+
+		if !IsRecipientAlias(name) {
+			recipient, err := txStore.GetAgentByName(ctx, name)
+			switch {
+			case errors.Is(err, sql.ErrNoRows) && partial:
+				addUnknown(name, ErrAgentNotFound)
+				continue
+
+			case errors.Is(err, sql.ErrNoRows):
+				return resolved, fmt.Errorf("recipient %q not "+
+					"found: %w", name, ErrAgentNotFound)
+
+			case err != nil:
+				return resolved, fmt.Errorf("failed to get "+
+					"recipient %q: %w", name, err)
+			}
+			addRecipient(recipient)
+
+			continue
+		}
+
+		agents, err := s.expandAlias(ctx, txStore, name)
+		switch {
+		case partial && isUnresolvedAlias(err):
+			addUnknown(name, err)
+			continue
+
+		case err != nil:
+			return resolved, err
+		}
+
+		expansion := resolvedExpansion{address: name}
+		for _, a := range agents {
+			if a.ID == senderID {
+				continue
+			}
+			expansion.agents = append(expansion.agents, a)
+			addRecipient(a)
+		}
+		if len(expansion.agents) == 0 {
+			err := fmt.Errorf("%w: %s", ErrEmptyRecipientAlias, name)
+			if !partial {
+				return resolved, err
+			}
+			addUnknown(name, err)
+
+			continue
+		}
+
+		resolved.expansions = append(resolved.expansions, expansion)
+	}
+
+	return resolved, nil
+}
+
+// isUnresolvedAlias reports whether an alias expansion failed because the
+// alias doesn't name anything, as opposed to a storage failure.
+func isUnresolvedAlias(err error) bool {
+	return errors.Is(err, ErrRecipientGroupNotFound) ||
+		errors.Is(err, ErrUnknownRecipientAlias)
+}
+
+// isUnroutable reports whether routing a capability address failed because
+// no agent can take it, as opposed to a storage failure.
+func isUnroutable(err error) bool {
+	return errors.Is(err, ErrNoCapableAgent) ||
+		errors.Is(err, ErrInvalidCapability)
+}
+
+// expandAlias returns the agents a recipient group or dynamic alias
+// currently refers to.
+func (s *Service) expandAlias(ctx context.Context, txStore store.Storage,
+	address string,
+) ([]store.Agent, error) {
+	alias := strings.TrimPrefix(address, RecipientAliasPrefix)
+	kind, arg, hasArg := strings.Cut(alias, ":")
+
+	var (
+		agents []store.Agent
+		err    error
+	)
+	switch {
+	case kind == AliasProject && hasArg && arg != "":
+		agents, err = txStore.ListAgentsMatchingProject(ctx, arg)
+
+	case kind == AliasBranch && hasArg && arg != "":
+		agents, err = txStore.ListAgentsByBranch(ctx, arg)
+
+	case alias == AliasOnline:
+		agents, err = txStore.ListAgentsActiveSince(
+			ctx, time.Now().Add(-s.onlineThreshold),
+		)
+
+	case alias == AliasReviewers:
+		agents, err = txStore.ListAgentsByNamePrefix(
+			ctx, reviewerAgentPrefix,
+		)
+
+	case hasArg:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownRecipientAlias,
+			address)
+
+	default:
+		var group store.RecipientGroup
+		group, err = txStore.GetRecipientGroupByName(ctx, alias)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s",
+				ErrRecipientGroupNotFound, address)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get group: %w", err)
+		}
+
+		agents, err = txStore.ListRecipientGroupMembers(ctx, group.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand %s: %w", address, err)
+	}
+
+	return agents, nil
+}
+
+// recordExpansions stores the expansions of a message's recipient groups
+// and aliases for auditing, and returns them in the form reported to the
+// sender.
+func recordExpansions(ctx context.Context, txStore store.Storage,
+	messageID int64, expansions []resolvedExpansion,
+) ([]RecipientExpansion, error) {
+	if len(expansions) == 0 {
+		return nil, nil
+	}
+
+	recorded := make([]RecipientExpansion, len(expansions))
+	for i, e := range expansions {
+		recorded[i].Address = e.address
+		for _, a := range e.agents {
+			err := txStore.CreateRecipientExpansion(
+				ctx, messageID, e.address, a.ID,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to record "+
+					"expansion of %s: %w", e.address, err)
+			}
+			recorded[i].AgentNames = append(
+				recorded[i].AgentNames, a.Name,
+			)
+		}
+	}
+
+	return recorded, nil
+}
+
+// GetRecipientExpansions returns the recorded expansions of a message's
+// recipient groups and aliases, ordered by address.
+func (s *Service) GetRecipientExpansions(ctx context.Context,
+	messageID int64,
+) ([]RecipientExpansion, error) {
+	rows, err := s.store.ListRecipientExpansions(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expansions: %w", err)
+	}
+
+	var expansions []RecipientExpansion
+	for _, row := range rows {
+		n := len(expansions)
+		if n == 0 || expansions[n-1].Address != row.Address {
+			expansions = append(expansions, RecipientExpansion{
+				Address: row.Address,
+			})
+			n++
+		}
+		expansions[n-1].AgentNames = append(
+			expansions[n-1].AgentNames, row.AgentName,
+		)
+	}
+
+	return expansions, nil
+}
+
+// RecipientGroup is a recipient group along with its members.
+type RecipientGroup struct {
+	store.RecipientGroup
+
+	// Members are the names of the agents in the group.
+	Members []string
+}
+
+// CreateRecipientGroup creates a recipient group with the given members.
+func (s *Service) CreateRecipientGroup(ctx context.Context, name,
+	description string, createdBy int64, members []string,
+) (RecipientGroup, error) {
+	name = strings.TrimPrefix(name, RecipientAliasPrefix)
+	if err := ValidateGroupName(name); err != nil {
+		return RecipientGroup{}, err
+	}
+
+	var group RecipientGroup
+	err := s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		_, err := txStore.GetRecipientGroupByName(ctx, name)
+		switch {
+		case err == nil:
+			return fmt.Errorf("%w: @%s", ErrRecipientGroupExists,
+				name)
+
+		case !errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("failed to get group: %w", err)
+		}
+
+		params := store.CreateRecipientGroupParams{
+			Name:        name,
+			Description: description,
+		}
+		if createdBy != 0 {
+			params.CreatedBy = &createdBy
+		}
+		created, err := txStore.CreateRecipientGroup(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to create group: %w", err)
+		}
+
+		group, err = addGroupMembers(ctx, txStore, created, members)
+		return err
+	})
+	if err != nil {
+		return RecipientGroup{}, err
+	}
+
+	return group, nil
+}
+
+// GetRecipientGroup returns a recipient group and its members.
+func (s *Service) GetRecipientGroup(ctx context.Context,
+	name string,
+) (RecipientGroup, error) {
+	group, err := getRecipientGroup(ctx, s.store, name)
+	if err != nil {
+		return RecipientGroup{}, err
+	}
+
+	return groupWithMembers(ctx, s.store, group)
+}
+
+// ListRecipientGroups lists all recipient groups with their member counts.
+func (s *Service) ListRecipientGroups(ctx context.Context) (
+	[]store.RecipientGroup, error) {
+
+	groups, err := s.store.ListRecipientGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// DeleteRecipientGroup deletes a recipient group. Messages already sent to
+// the group keep their recipients and recorded expansion.
+func (s *Service) DeleteRecipientGroup(ctx context.Context,
+	name string,
+) error {
+	group, err := getRecipientGroup(ctx, s.store, name)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.store.DeleteRecipientGroup(ctx, group.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("%w: @%s", ErrRecipientGroupNotFound,
+			group.Name)
+	}
+
+	return nil
+}
+
+// AddRecipientGroupMembers adds agents to a recipient group and returns the
+// updated group.
+func (s *Service) AddRecipientGroupMembers(ctx context.Context, name string,
+	members []string,
+) (RecipientGroup, error) {
+	var group RecipientGroup
+	err := s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		g, err := getRecipientGroup(ctx, txStore, name)
+		if err != nil {
+			return err
+		}
+
+		group, err = addGroupMembers(ctx, txStore, g, members)
+		return err
+	})
+	if err != nil {
+		return RecipientGroup{}, err
+	}
+
+	return group, nil
+}
+
+// RemoveRecipientGroupMembers removes agents from a recipient group and
+// returns the updated group. Naming an agent that isn't a member is a
+// no-op.
+func (s *Service) RemoveRecipientGroupMembers(ctx context.Context,
+	name string, members []string,
+) (RecipientGroup, error) {
+	var group RecipientGroup
+	err := s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		g, err := getRecipientGroup(ctx, txStore, name)
+		if err != nil {
+			return err
+		}
+
+		for _, member := range members {
+			a, err := txStore.GetAgentByName(ctx, member)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrAgentNotFound,
+					member)
+			}
+
+			_, err = txStore.RemoveRecipientGroupMember(
+				ctx, g.ID, a.ID,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to remove member: %w",
+					err)
+			}
+		}
+
+		group, err = groupWithMembers(ctx, txStore, g)
+		return err
+	})
+	if err != nil {
+		return RecipientGroup{}, err
+	}
+
+	return group, nil
+}
+
+// getRecipientGroup looks up a recipient group by name, with or without the
+// leading "@".
+func getRecipientGroup(ctx context.Context, st store.Storage,
+	name string,
+) (store.RecipientGroup, error) {
+	name = strings.TrimPrefix(name, RecipientAliasPrefix)
+
+	group, err := st.GetRecipientGroupByName(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return group, fmt.Errorf("%w: @%s", ErrRecipientGroupNotFound,
+			name)
+	}
+	if err != nil {
+		return group, fmt.Errorf("failed to get group: %w", err)
+	}
+
+	return group, nil
+}
+
+// addGroupMembers adds the named agents to a group and returns the group
+// with its updated member list.
+func addGroupMembers(ctx context.Context, txStore store.Storage,
+	group store.RecipientGroup, members []string,
+) (RecipientGroup, error) {
+	for _, member := range members {
+		a, err := txStore.GetAgentByName(ctx, member)
+		if err != nil {
+			return RecipientGroup{}, fmt.Errorf("%w: %s",
+				ErrAgentNotFound, member)
+		}
+
+		err = txStore.AddRecipientGroupMember(ctx, group.ID, a.ID)
+		if err != nil {
+			return RecipientGroup{}, fmt.Errorf("failed to add "+
+				"member: %w", err)
+		}
+	}
+
+	return groupWithMembers(ctx, txStore, group)
+}
+
+// groupWithMembers attaches the current member list to a group.
+func groupWithMembers(ctx context.Context, st store.Storage,
+	group store.RecipientGroup,
+) (RecipientGroup, error) {
+	agents, err := st.ListRecipientGroupMembers(ctx, group.ID)
+	if err != nil {
+		return RecipientGroup{}, fmt.Errorf("failed to list members: %w",
+			err)
+	}
+
+	result := RecipientGroup{RecipientGroup: group}
+	for _, a := range agents {
+		result.Members = append(result.Members, a.Name)
+	}
+	result.MemberCount = int64(len(result.Members))
+
+	return result, nil
+}
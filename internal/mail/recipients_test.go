@@ -0,0 +1,251 @@
+package mail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+// createProjectAgent creates an agent working on the given project and
+// branch.
+func createProjectAgent(t *testing.T, storage store.Storage, name,
+	projectKey, branch string,
+) store.Agent {
+	t.Helper()
+
+	agent, err := storage.CreateAgent(
+		context.Background(), store.CreateAgentParams{
+			Name:       name,
+			ProjectKey: projectKey,
+			GitBranch:  branch,
+		},
+	)
+	require.NoError(t, err)
+
+	return agent
+}
+
+// inboxRecipients returns the names of the agents a message was delivered
+// to.
+func inboxRecipients(t *testing.T, storage store.Storage,
+	agents []store.Agent, messageID int64,
+) []string {
+	t.Helper()
+
+	var names []string
+	for _, a := range agents {
+		_, err := storage.GetMessageRecipient(
+			context.Background(), messageID, a.ID,
+		)
+		if err == nil {
+			names = append(names, a.Name)
+		}
+	}
+
+	return names
+}
+
+// TestSendToRecipientGroup tests that a group expands to its members, that
+// recipients named more than once get a single copy, that the sender is
+// left out of the expansion, and that the expansion is recorded.
+func TestSendToRecipientGroup(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	alice := createTestAgent(t, storage, "Alice")
+	bob := createTestAgent(t, storage, "Bob")
+	carol := createTestAgent(t, storage, "Carol")
+	all := []store.Agent{sender, alice, bob, carol}
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	group, err := svc.CreateRecipientGroup(
+		ctx, "@backend-team", "Backend", sender.ID,
+		[]string{"Sender", "Alice", "Bob"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "backend-team", group.Name)
+	require.Equal(t, []string{"Alice", "Bob", "Sender"}, group.Members)
+
+	_, err = svc.CreateRecipientGroup(
+		ctx, "backend-team", "", sender.ID, nil,
+	)
+	require.ErrorIs(t, err, ErrRecipientGroupExists)
+
+	resp, err := svc.Send(ctx, SendMailRequest{
+		SenderID:       sender.ID,
+		RecipientNames: []string{"@backend-team", "Alice", "Carol"},
+		Subject:        "Deploy",
+		Body:           "Shipping",
+		Priority:       PriorityNormal,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []RecipientExpansion{{
+		Address:    "@backend-team",
+		AgentNames: []string{"Alice", "Bob"},
+	}}, resp.Expansions)
+
+	require.Equal(
+		t, []string{"Alice", "Bob", "Carol"},
+		inboxRecipients(t, storage, all, resp.MessageID),
+	)
+
+	expansions, err := svc.GetRecipientExpansions(ctx, resp.MessageID)
+	require.NoError(t, err)
+	require.Equal(t, resp.Expansions, expansions)
+
+	// Membership changes only affect later messages.
+	group, err = svc.RemoveRecipientGroupMembers(
+		ctx, "backend-team", []string{"Bob"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Alice", "Sender"}, group.Members)
+
+	expansions, err = svc.GetRecipientExpansions(ctx, resp.MessageID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Alice", "Bob"}, expansions[0].AgentNames)
+
+	// Once the group is deleted, sending to it fails.
+	require.NoError(t, svc.DeleteRecipientGroup(ctx, "@backend-team"))
+
+	_, err = svc.Send(ctx, SendMailRequest{
+		SenderID:       sender.ID,
+		RecipientNames: []string{"@backend-team"},
+		Subject:        "Again",
+	})
+	require.ErrorIs(t, err, ErrRecipientGroupNotFound)
+}
+
+// TestSendToDynamicAliases tests that the project, branch, online and
+// reviewers aliases resolve against the agents table at send time.
+func TestSendToDynamicAliases(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createProjectAgent(
+		t, storage, "Sender", "/src/subtrate", "main",
+	)
+	mainAgent := createProjectAgent(
+		t, storage, "MainAgent", "/home/dev/subtrate", "main",
+	)
+	featureAgent := createProjectAgent(
+		t, storage, "FeatureAgent", "/src/subtrate", "feature",
+	)
+	otherAgent := createProjectAgent(
+		t, storage, "OtherAgent", "/src/other", "main",
+	)
+	reviewer := createTestAgent(t, storage, "reviewer-main")
+	all := []store.Agent{
+		sender, mainAgent, featureAgent, otherAgent, reviewer,
+	}
+
+	// The other project's agent hasn't been seen in an hour.
+	err := storage.UpdateLastActive(
+		ctx, otherAgent.ID, time.Now().Add(-time.Hour),
+	)
+	require.NoError(t, err)
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	tests := []struct {
+		address string
+		want    []string
+	}{
+		{
+			address: "@project:subtrate",
+			want:    []string{"FeatureAgent", "MainAgent"},
+		},
+		{
+			address: "@branch:main",
+			want:    []string{"MainAgent", "OtherAgent"},
+		},
+		{
+			address: "@online",
+			want: []string{
+				"FeatureAgent", "MainAgent", "reviewer-main",
+			},
+		},
+		{
+			address: "@reviewers",
+			want:    []string{"reviewer-main"},
+		},
+	}
+	for _, tc := range tests {
+		resp, err := svc.Send(ctx, SendMailRequest{
+			SenderID:       sender.ID,
+			RecipientNames: []string{tc.address},
+			Subject:        tc.address,
+			Priority:       PriorityNormal,
+		})
+		require.NoError(t, err, tc.address)
+		require.Len(t, resp.Expansions, 1, tc.address)
+		require.Equal(t, tc.address, resp.Expansions[0].Address)
+		require.ElementsMatch(
+			t, tc.want, resp.Expansions[0].AgentNames, tc.address,
+		)
+		require.ElementsMatch(
+			t, tc.want, inboxRecipients(t, storage, all, resp.MessageID),
+			tc.address,
+		)
+	}
+}
+
+// TestRecipientAliasErrors tests that unknown aliases, aliases that match
+// nobody and invalid group names are rejected.
+func TestRecipientAliasErrors(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	createTestAgent(t, storage, "Alice")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	send := func(address string) error {
+		_, err := svc.Send(ctx, SendMailRequest{
+			SenderID:       sender.ID,
+			RecipientNames: []string{"Alice", address},
+			Subject:        "Hello",
+		})
+		return err
+	}
+
+	require.ErrorIs(t, send("@nobody"), ErrRecipientGroupNotFound)
+	require.ErrorIs(t, send("@team:core"), ErrUnknownRecipientAlias)
+	require.ErrorIs(t, send("@branch:missing"), ErrEmptyRecipientAlias)
+
+	// An empty group matches nobody either.
+	_, err := svc.CreateRecipientGroup(ctx, "empty", "", 0, nil)
+	require.NoError(t, err)
+	require.ErrorIs(t, send("@empty"), ErrEmptyRecipientAlias)
+
+	for _, name := range []string{"online", "Bad Name", "", "a:b"} {
+		_, err := svc.CreateRecipientGroup(ctx, name, "", 0, nil)
+		require.ErrorIs(t, err, ErrInvalidGroupName, name)
+	}
+
+	_, err = svc.CreateRecipientGroup(
+		ctx, "team", "", 0, []string{"Missing"},
+	)
+	require.ErrorIs(t, err, ErrAgentNotFound)
+
+	// The failed create was rolled back.
+	_, err = svc.GetRecipientGroup(ctx, "team")
+	require.ErrorIs(t, err, ErrRecipientGroupNotFound)
+}
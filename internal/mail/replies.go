@@ -0,0 +1,168 @@
+package mail
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+// ReplyRequest describes a reply to a message.
+type ReplyRequest struct {
+	// SenderID is the agent replying, which must have sent or received
+	// the message.
+	SenderID int64
+
+	// MessageID is the message being replied to.
+	MessageID int64
+
+	// Body is the reply's body.
+	Body string
+
+	// SenderOnly replies to the message's sender alone instead of to
+	// everyone it was sent to. It has no effect on replies to the
+	// agent's own messages, which go to the message's recipients.
+	SenderOnly bool
+
+	// Quote appends the replied-to message to the reply, quoted.
+	Quote bool
+
+	// Priority is the reply's priority, PriorityNormal if empty.
+	Priority Priority
+
+	// IdempotencyKey deduplicates retried replies, such as ones drained
+	// from the offline queue.
+	IdempotencyKey string
+}
+
+// PrepareReply builds the send request for a reply to a message. The reply
+// joins the message's thread, takes its subject with a "Re: " prefix and, by
+// default, goes to the message's sender and every other recipient of it,
+// leaving out the agent replying.
+func (s *Service) PrepareReply(ctx context.Context,
+	req ReplyRequest,
+) (SendMailRequest, error) {
+	if strings.TrimSpace(req.Body) == "" {
+		return SendMailRequest{}, fmt.Errorf("%w: a body is required",
+			ErrInvalidReply)
+	}
+
+	msg, err := s.store.GetMessage(ctx, req.MessageID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return SendMailRequest{}, fmt.Errorf("%w: %d",
+			ErrMessageNotFound, req.MessageID)
+
+	case err != nil:
+		return SendMailRequest{}, fmt.Errorf("failed to get "+
+			"message: %w", err)
+	}
+
+	sender, err := s.store.GetAgent(ctx, msg.SenderID)
+	if err != nil {
+		return SendMailRequest{}, fmt.Errorf("failed to get sender: %w",
+			err)
+	}
+
+	recipients, err := s.store.GetMessageRecipients(ctx, msg.ID)
+	if err != nil {
+		return SendMailRequest{}, fmt.Errorf("failed to get "+
+			"recipients: %w", err)
+	}
+
+	names, err := replyRecipients(req, sender, recipients)
+	if err != nil {
+		return SendMailRequest{}, err
+	}
+
+	body := req.Body
+	if req.Quote {
+		body = strings.TrimRight(body, "\n") + "\n\n" +
+			quoteMessage(sender.Name, msg)
+	}
+
+	priority := req.Priority
+	if priority == "" {
+		priority = PriorityNormal
+	}
+
+	return SendMailRequest{
+		SenderID:       req.SenderID,
+		RecipientNames: names,
+		ThreadID:       msg.ThreadID,
+		Subject:        replySubject(msg.Subject),
+		Body:           body,
+		Priority:       priority,
+		IdempotencyKey: req.IdempotencyKey,
+	}, nil
+}
+
+// replyRecipients returns the names of the agents a reply goes to, checking
+// that the agent replying took part in the message.
+func replyRecipients(req ReplyRequest, sender store.Agent,
+	recipients []store.MessageRecipientWithAgent,
+) ([]string, error) {
+	participant := sender.ID == req.SenderID
+	for _, r := range recipients {
+		if r.AgentID == req.SenderID {
+			participant = true
+		}
+	}
+	if !participant {
+		return nil, fmt.Errorf("%w: only the sender and recipients of "+
+			"message %d can reply to it", ErrUnauthorized,
+			req.MessageID)
+	}
+
+	var names []string
+	if sender.ID != req.SenderID {
+		names = append(names, sender.Name)
+		if req.SenderOnly {
+			return names, nil
+		}
+	}
+	for _, r := range recipients {
+		if r.AgentID == req.SenderID || r.AgentID == sender.ID {
+			continue
+		}
+		names = append(names, r.AgentName)
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: message %d has nobody else to "+
+			"reply to", ErrInvalidReply, req.MessageID)
+	}
+
+	return names, nil
+}
+
+// replySubject prefixes a subject with "Re: " unless it already has it.
+func replySubject(subject string) string {
+	if strings.HasPrefix(strings.ToLower(subject), "re: ") {
+		return subject
+	}
+
+	return "Re: " + subject
+}
+
+// quoteMessage renders a message as a quoted block for a reply, headed by
+// who sent it and when.
+func quoteMessage(senderName string, msg store.Message) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "On %s, %s wrote:\n",
+		msg.CreatedAt.UTC().Format("2006-01-02 15:04 UTC"), senderName)
+
+	lines := strings.Split(strings.TrimRight(msg.Body, "\n"), "\n")
+	for _, line := range lines {
+		if line == "" {
+			sb.WriteString(">\n")
+			continue
+		}
+		sb.WriteString("> " + line + "\n")
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
@@ -0,0 +1,100 @@
+package mail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrepareReply tests that replies go to everyone on the replied-to
+// message but the agent replying, and quote it on request.
+func TestPrepareReply(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	alice := createTestAgent(t, storage, "Alice")
+	bob := createTestAgent(t, storage, "Bob")
+	createTestAgent(t, storage, "Carol")
+	dave := createTestAgent(t, storage, "Dave")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	orig := svc.handleSendMail(ctx, SendMailRequest{
+		SenderID:       alice.ID,
+		RecipientNames: []string{"Bob", "Carol"},
+		Subject:        "Release plan",
+		Body:           "Shipping on Friday.\n\nAny blockers?",
+		Priority:       PriorityNormal,
+	})
+	require.NoError(t, orig.Error)
+
+	// Bob replying to all reaches Alice and Carol on the same thread.
+	req, err := svc.PrepareReply(ctx, ReplyRequest{
+		SenderID:  bob.ID,
+		MessageID: orig.MessageID,
+		Body:      "None from me.",
+		Quote:     true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Alice", "Carol"}, req.RecipientNames)
+	require.Equal(t, orig.ThreadID, req.ThreadID)
+	require.Equal(t, "Re: Release plan", req.Subject)
+	require.Equal(t, PriorityNormal, req.Priority)
+	require.Contains(t, req.Body, "None from me.\n\nOn ")
+	require.Contains(t, req.Body, "Alice wrote:\n"+
+		"> Shipping on Friday.\n>\n> Any blockers?")
+
+	reply := svc.handleSendMail(ctx, req)
+	require.NoError(t, reply.Error)
+	require.Equal(t, orig.ThreadID, reply.ThreadID)
+
+	// Replying to the sender alone leaves Carol out, and the subject
+	// isn't prefixed twice.
+	req, err = svc.PrepareReply(ctx, ReplyRequest{
+		SenderID:   alice.ID,
+		MessageID:  reply.MessageID,
+		Body:       "Thanks.",
+		SenderOnly: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Bob"}, req.RecipientNames)
+	require.Equal(t, "Re: Release plan", req.Subject)
+	require.Equal(t, "Thanks.", req.Body)
+
+	// Replying to your own message goes to its recipients.
+	req, err = svc.PrepareReply(ctx, ReplyRequest{
+		SenderID:   alice.ID,
+		MessageID:  orig.MessageID,
+		Body:       "Moved to Monday.",
+		SenderOnly: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Bob", "Carol"}, req.RecipientNames)
+
+	// Agents that didn't take part in the message can't reply to it.
+	_, err = svc.PrepareReply(ctx, ReplyRequest{
+		SenderID:  dave.ID,
+		MessageID: orig.MessageID,
+		Body:      "Me too.",
+	})
+	require.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = svc.PrepareReply(ctx, ReplyRequest{
+		SenderID:  bob.ID,
+		MessageID: orig.MessageID,
+		Body:      "  ",
+	})
+	require.ErrorIs(t, err, ErrInvalidReply)
+
+	_, err = svc.PrepareReply(ctx, ReplyRequest{
+		SenderID:  bob.ID,
+		MessageID: 9999,
+		Body:      "Hello?",
+	})
+	require.ErrorIs(t, err, ErrMessageNotFound)
+}
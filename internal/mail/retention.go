@@ -0,0 +1,189 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+const (
+	// DefaultRetentionInterval is how often the retention compactor
+	// enforces topic retention.
+	DefaultRetentionInterval = time.Hour
+
+	// DefaultRetentionBatchSize caps the number of messages deleted per
+	// statement so a large backlog doesn't hold the write lock for long.
+	DefaultRetentionBatchSize = 500
+)
+
+// RetentionCompactorConfig holds configuration for the retention compactor.
+type RetentionCompactorConfig struct {
+	// Store is the storage backend holding messages and offsets.
+	Store store.Storage
+
+	// Interval is how often to enforce retention.
+	Interval time.Duration
+
+	// BatchSize is the number of messages deleted per statement.
+	BatchSize int
+
+	// Log is the logger used for compaction results and failures.
+	Log *slog.Logger
+}
+
+// RetentionReport describes the outcome of a compaction pass.
+type RetentionReport struct {
+	// DryRun is true if nothing was deleted and the counts describe what
+	// would have been.
+	DryRun bool
+
+	// Topics summarizes the expired messages of each topic.
+	Topics []store.ExpiredTopicSummary
+
+	// MessagesDeleted is the number of messages deleted.
+	MessagesDeleted int64
+
+	// RecipientsDeleted is the number of recipient rows deleted along
+	// with the messages.
+	RecipientsDeleted int64
+
+	// ConsumerOffsetsDeleted is the number of stale consumer offsets
+	// deleted.
+	ConsumerOffsetsDeleted int64
+}
+
+// RetentionCompactor periodically deletes messages that have outlived their
+// topic's retention_seconds, along with their recipient rows and search
+// index entries, and drops consumer offsets left behind by agents that
+// unsubscribed. Starred messages, messages a recipient hasn't read, has
+// snoozed or still owes an ack for by its deadline, messages under a legal
+// hold, messages in threads with an open review, plan review or pending
+// question, and queue messages still awaiting a consumer are always kept.
+type RetentionCompactor struct {
+	cfg RetentionCompactorConfig
+	log *slog.Logger
+}
+
+// NewRetentionCompactor creates a new retention compactor.
+func NewRetentionCompactor(cfg RetentionCompactorConfig) *RetentionCompactor {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultRetentionInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultRetentionBatchSize
+	}
+
+	log := cfg.Log
+	if log == nil {
+		log = slog.Default()
+	}
+
+	return &RetentionCompactor{
+		cfg: cfg,
+		log: log.With("component", "retention-compactor"),
+	}
+}
+
+// Run compacts the store immediately and then on every interval. It blocks
+// until ctx is cancelled.
+func (c *RetentionCompactor) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := c.Compact(ctx, time.Now(), false)
+		switch {
+		case err != nil:
+			c.log.Warn("Failed to enforce topic retention",
+				"error", err,
+			)
+
+		case report.MessagesDeleted > 0 ||
+			report.ConsumerOffsetsDeleted > 0:
+
+			c.log.Info("Enforced topic retention",
+				"messages", report.MessagesDeleted,
+				"recipients", report.RecipientsDeleted,
+				"consumer_offsets", report.ConsumerOffsetsDeleted,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Compact enforces topic retention as of now. With dryRun set nothing is
+// deleted and the report describes what a real pass would remove.
+func (c *RetentionCompactor) Compact(ctx context.Context, now time.Time,
+	dryRun bool,
+) (RetentionReport, error) {
+	report := RetentionReport{DryRun: dryRun}
+
+	topics, err := c.cfg.Store.SummarizeExpiredMessages(ctx, now)
+	if err != nil {
+		return report, fmt.Errorf("failed to summarize expired "+
+			"messages: %w", err)
+	}
+	report.Topics = topics
+
+	if dryRun {
+		for _, topic := range topics {
+			report.MessagesDeleted += topic.DeletableCount
+			report.RecipientsDeleted += topic.RecipientCount
+		}
+
+		report.ConsumerOffsetsDeleted, err =
+			c.cfg.Store.CountStaleConsumerOffsets(ctx)
+		if err != nil {
+			return report, fmt.Errorf("failed to count stale "+
+				"consumer offsets: %w", err)
+		}
+
+		return report, nil
+	}
+
+	for _, topic := range topics {
+		report.RecipientsDeleted += topic.RecipientCount
+	}
+
+	// Delete in batches so other writers get a turn in between.
+	for {
+		deleted, err := c.cfg.Store.DeleteExpiredMessages(
+			ctx, now, c.cfg.BatchSize,
+		)
+		if err != nil {
+			return report, fmt.Errorf("failed to delete expired "+
+				"messages: %w", err)
+		}
+		report.MessagesDeleted += deleted
+
+		if deleted < int64(c.cfg.BatchSize) {
+			break
+		}
+	}
+
+	report.ConsumerOffsetsDeleted, err =
+		c.cfg.Store.DeleteStaleConsumerOffsets(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to delete stale consumer "+
+			"offsets: %w", err)
+	}
+
+	// Deleted rows leave tombstones behind in the search index, so merge
+	// it once there's something to reclaim.
+	if report.MessagesDeleted > 0 {
+		if err := c.cfg.Store.OptimizeSearchIndex(ctx); err != nil {
+			return report, fmt.Errorf("failed to optimize search "+
+				"index: %w", err)
+		}
+	}
+
+	return report, nil
+}
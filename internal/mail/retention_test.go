@@ -0,0 +1,269 @@
+package mail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRetentionCompactor tests that expired messages are deleted along with
+// their recipient rows, that protected messages are kept, and that a dry run
+// deletes nothing.
+func TestRetentionCompactor(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	recipient := createTestAgent(t, storage, "Recipient")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	send := func(subject string) int64 {
+		resp, err := svc.Send(ctx, SendMailRequest{
+			SenderID:       sender.ID,
+			RecipientNames: []string{recipient.Name},
+			Subject:        subject,
+			Body:           "Body",
+			Priority:       PriorityNormal,
+		})
+		require.NoError(t, err)
+
+		return resp.MessageID
+	}
+
+	plainID := send("Plain")
+	starredID := send("Starred")
+	heldID := send("Held")
+
+	err := svc.UpdateState(ctx, UpdateStateRequest{
+		AgentID:   recipient.ID,
+		MessageID: starredID,
+		NewState:  StateStarredStr.String(),
+	})
+	require.NoError(t, err)
+
+	// Only mail the recipient has read can expire.
+	for _, id := range []int64{plainID, heldID} {
+		err = svc.UpdateState(ctx, UpdateStateRequest{
+			AgentID:   recipient.ID,
+			MessageID: id,
+			NewState:  StateReadStr.String(),
+		})
+		require.NoError(t, err)
+	}
+
+	updated, err := storage.SetMessageLegalHold(ctx, heldID, true)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, updated)
+
+	compactor := NewRetentionCompactor(RetentionCompactorConfig{
+		Store:     storage,
+		BatchSize: 1,
+	})
+
+	// Nothing has outlived the default seven day retention yet.
+	report, err := compactor.Compact(ctx, time.Now(), false)
+	require.NoError(t, err)
+	require.Empty(t, report.Topics)
+	require.Zero(t, report.MessagesDeleted)
+
+	// A dry run past the retention window reports every expired message
+	// but only counts the unprotected one as deletable.
+	later := time.Now().Add(8 * 24 * time.Hour)
+	report, err = compactor.Compact(ctx, later, true)
+	require.NoError(t, err)
+	require.True(t, report.DryRun)
+	require.Len(t, report.Topics, 1)
+	require.EqualValues(t, 3, report.Topics[0].ExpiredCount)
+	require.EqualValues(t, 1, report.Topics[0].DeletableCount)
+	require.EqualValues(t, 1, report.MessagesDeleted)
+	require.EqualValues(t, 1, report.RecipientsDeleted)
+
+	_, err = storage.GetMessage(ctx, plainID)
+	require.NoError(t, err)
+
+	// The real pass deletes the plain message and its recipient row.
+	report, err = compactor.Compact(ctx, later, false)
+	require.NoError(t, err)
+	require.False(t, report.DryRun)
+	require.EqualValues(t, 1, report.MessagesDeleted)
+	require.EqualValues(t, 1, report.RecipientsDeleted)
+
+	_, err = storage.GetMessage(ctx, plainID)
+	require.Error(t, err)
+	_, err = storage.GetMessageRecipient(ctx, plainID, recipient.ID)
+	require.Error(t, err)
+
+	for _, id := range []int64{starredID, heldID} {
+		_, err = storage.GetMessage(ctx, id)
+		require.NoError(t, err)
+	}
+
+	// Once the legal hold is lifted the message is fair game.
+	_, err = storage.SetMessageLegalHold(ctx, heldID, false)
+	require.NoError(t, err)
+
+	report, err = compactor.Compact(ctx, later, false)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, report.MessagesDeleted)
+
+	_, err = storage.GetMessage(ctx, heldID)
+	require.Error(t, err)
+	_, err = storage.GetMessage(ctx, starredID)
+	require.NoError(t, err)
+}
+
+// TestRetentionCompactorKeepsPendingMail tests that messages a recipient
+// hasn't read, has snoozed or still has to ack, and open questions, are kept
+// however old they are.
+func TestRetentionCompactorKeepsPendingMail(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	recipient := createTestAgent(t, storage, "Recipient")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	send := func(subject string, deadline *time.Time) int64 {
+		resp, err := svc.Send(ctx, SendMailRequest{
+			SenderID:       sender.ID,
+			RecipientNames: []string{recipient.Name},
+			Subject:        subject,
+			Body:           "Body",
+			Priority:       PriorityNormal,
+			Deadline:       deadline,
+		})
+		require.NoError(t, err)
+
+		return resp.MessageID
+	}
+	setState := func(id int64, state RecipientState, until *time.Time) {
+		err := svc.UpdateState(ctx, UpdateStateRequest{
+			AgentID:      recipient.ID,
+			MessageID:    id,
+			NewState:     state.String(),
+			SnoozedUntil: until,
+		})
+		require.NoError(t, err)
+	}
+
+	unreadID := send("Unread", nil)
+
+	snoozedID := send("Snoozed", nil)
+	until := time.Now().Add(30 * 24 * time.Hour)
+	setState(snoozedID, StateSnoozedStr, &until)
+
+	deadline := time.Now().Add(time.Hour)
+	deadlineID := send("Deadline", &deadline)
+	setState(deadlineID, StateReadStr, nil)
+
+	question, err := svc.AskQuestion(ctx, AskQuestionRequest{
+		SenderID:       sender.ID,
+		RecipientNames: []string{recipient.Name},
+		Question:       "Ship it?",
+	})
+	require.NoError(t, err)
+	setState(question.MessageID, StateReadStr, nil)
+
+	compactor := NewRetentionCompactor(RetentionCompactorConfig{
+		Store: storage,
+	})
+
+	later := time.Now().Add(8 * 24 * time.Hour)
+	report, err := compactor.Compact(ctx, later, false)
+	require.NoError(t, err)
+	require.Len(t, report.Topics, 1)
+	require.EqualValues(t, 4, report.Topics[0].ExpiredCount)
+	require.Zero(t, report.MessagesDeleted)
+
+	// Once the deadline is acked nobody is waiting on the message.
+	require.NoError(t, svc.AckMessage(ctx, recipient.ID, deadlineID))
+
+	report, err = compactor.Compact(ctx, later, false)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, report.MessagesDeleted)
+
+	_, err = storage.GetMessage(ctx, deadlineID)
+	require.Error(t, err)
+
+	for _, id := range []int64{unreadID, snoozedID, question.MessageID} {
+		_, err = storage.GetMessage(ctx, id)
+		require.NoError(t, err)
+	}
+}
+
+// TestRetentionCompactorKeepsOpenReviews tests that messages in a thread
+// with an open plan review are kept until the review is resolved.
+func TestRetentionCompactorKeepsOpenReviews(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	recipient := createTestAgent(t, storage, "Recipient")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	resp, err := svc.Send(ctx, SendMailRequest{
+		SenderID:       sender.ID,
+		RecipientNames: []string{recipient.Name},
+		Subject:        "Plan",
+		Body:           "Body",
+		Priority:       PriorityNormal,
+	})
+	require.NoError(t, err)
+
+	err = svc.UpdateState(ctx, UpdateStateRequest{
+		AgentID:   recipient.ID,
+		MessageID: resp.MessageID,
+		NewState:  StateReadStr.String(),
+	})
+	require.NoError(t, err)
+
+	_, err = storage.CreatePlanReview(ctx, store.CreatePlanReviewParams{
+		PlanReviewID: "pr-1",
+		ThreadID:     resp.ThreadID,
+		RequesterID:  sender.ID,
+		ReviewerName: recipient.Name,
+		PlanPath:     "plan.md",
+		PlanTitle:    "Plan",
+		SessionID:    "session",
+	})
+	require.NoError(t, err)
+
+	compactor := NewRetentionCompactor(RetentionCompactorConfig{
+		Store: storage,
+	})
+
+	later := time.Now().Add(8 * 24 * time.Hour)
+	report, err := compactor.Compact(ctx, later, false)
+	require.NoError(t, err)
+	require.Zero(t, report.MessagesDeleted)
+
+	err = storage.UpdatePlanReviewState(
+		ctx, store.UpdatePlanReviewStateParams{
+			PlanReviewID: "pr-1",
+			State:        "approved",
+		},
+	)
+	require.NoError(t, err)
+
+	report, err = compactor.Compact(ctx, later, false)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, report.MessagesDeleted)
+}
@@ -0,0 +1,395 @@
+package mail
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/ratelimit"
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+// EditMessageRequest describes an edit to a sent message.
+type EditMessageRequest struct {
+	// SenderID is the agent editing the message, which must be its
+	// sender.
+	SenderID int64
+
+	// MessageID is the message being edited.
+	MessageID int64
+
+	// Subject is the new subject, or empty to keep the current one.
+	Subject string
+
+	// Body is the new body, or empty to keep the current one.
+	Body string
+}
+
+// EditMessageResponse is the result of editing a message.
+type EditMessageResponse struct {
+	// Message is the message as edited.
+	Message store.Message
+
+	// Revision is the revision number of the edited version. The
+	// message as originally sent is revision 1.
+	Revision int
+}
+
+// EditMessage replaces the subject and body of a message its sender already
+// sent, recording the version it replaces in the message's revision history.
+// The search index and embedding follow the edit, and every recipient is
+// notified with the edited message. Edits count against the sender's send
+// rate limit, and growing the body counts against its daily storage quota.
+func (s *Service) EditMessage(ctx context.Context,
+	req EditMessageRequest,
+) (EditMessageResponse, error) {
+	err := s.allow(ctx, req.SenderID, ratelimit.ClassSend)
+	if err != nil {
+		return EditMessageResponse{}, err
+	}
+
+	if strings.TrimSpace(req.Subject) == "" &&
+		strings.TrimSpace(req.Body) == "" {
+
+		return EditMessageResponse{}, fmt.Errorf("%w: a new subject "+
+			"or body is required", ErrInvalidEdit)
+	}
+
+	var (
+		response   EditMessageResponse
+		recipients []store.MessageRecipientWithAgent
+		senderName string
+	)
+	err = s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		msg, err := getSenderMessage(
+			ctx, txStore, req.SenderID, req.MessageID,
+		)
+		if err != nil {
+			return err
+		}
+
+		subject, body := msg.Subject, msg.Body
+		if strings.TrimSpace(req.Subject) != "" {
+			subject = req.Subject
+		}
+		if strings.TrimSpace(req.Body) != "" {
+			body = req.Body
+		}
+		if subject == msg.Subject && body == msg.Body {
+			return fmt.Errorf("%w: message %d already has that "+
+				"subject and body", ErrInvalidEdit, msg.ID)
+		}
+
+		// Hold the sender to its daily storage quota for whatever the
+		// edit adds to the body.
+		if growth := len(body) - len(msg.Body); growth > 0 {
+			err := ratelimit.CheckQuota(
+				ctx, txStore, req.SenderID, int64(growth),
+				time.Now(),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		revisions, err := txStore.ListMessageRevisions(ctx, msg.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list revisions: %w", err)
+		}
+
+		// The version being replaced was created when the message was
+		// sent, or by the previous edit.
+		createdAt := msg.CreatedAt
+		if msg.EditedAt != nil {
+			createdAt = *msg.EditedAt
+		}
+
+		now := time.Now()
+		_, err = txStore.CreateMessageRevision(
+			ctx, store.CreateMessageRevisionParams{
+				MessageID:  msg.ID,
+				Revision:   len(revisions) + 1,
+				Subject:    msg.Subject,
+				Body:       msg.Body,
+				CreatedAt:  createdAt,
+				ReplacedAt: now,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record revision: %w", err)
+		}
+
+		ok, err := txStore.UpdateMessageContent(
+			ctx, msg.ID, subject, body, now,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to edit message: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("%w: message %d can't be edited",
+				ErrMessageLegalHold, msg.ID)
+		}
+
+		recipients, err = txStore.GetMessageRecipients(ctx, msg.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get recipients: %w", err)
+		}
+
+		sender, err := txStore.GetAgent(ctx, msg.SenderID)
+		if err != nil {
+			return fmt.Errorf("failed to get sender: %w", err)
+		}
+		senderName = sender.Name
+
+		msg.Subject = subject
+		msg.Body = body
+		msg.EditedAt = &now
+		response = EditMessageResponse{
+			Message:  msg,
+			Revision: len(revisions) + 2,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return EditMessageResponse{}, err
+	}
+	s.wakeEmbeddingIndexer()
+
+	s.notifyEdit(ctx, response.Message, senderName, recipients)
+
+	return response, nil
+}
+
+// notifyEdit tells every recipient of an edited message, and global inbox
+// viewers, about the edit. The notification carries the edited message with
+// its EditedAt set, which is how subscribers tell it apart from a new one.
+func (s *Service) notifyEdit(ctx context.Context, msg store.Message,
+	senderName string, recipients []store.MessageRecipientWithAgent,
+) {
+	if s.notifHub == nil {
+		return
+	}
+
+	notifMsg := storeMessageToMail(msg)
+	notifMsg.SenderName = senderName
+
+	for _, recipient := range recipients {
+		recipientMsg := notifMsg
+		recipientMsg.State = recipient.State
+		recipientMsg.SnoozedUntil = recipient.SnoozedUntil
+		recipientMsg.ReadAt = recipient.ReadAt
+		recipientMsg.AckedAt = recipient.AckedAt
+
+		s.notifHub.Tell(ctx, NotifyAgentMsg{
+			AgentID: recipient.AgentID,
+			Message: recipientMsg,
+		})
+	}
+
+	s.notifHub.Tell(ctx, NotifyAgentMsg{
+		AgentID: 0,
+		Message: notifMsg,
+	})
+}
+
+// MessageHistory is a message along with the versions of it that edits
+// replaced.
+type MessageHistory struct {
+	// Message is the current version of the message.
+	Message store.Message
+
+	// Revisions are the replaced versions, oldest first. A message that
+	// was never edited has none.
+	Revisions []store.MessageRevision
+}
+
+// Versions returns every version of the message, oldest first, ending with
+// its current version. The current version has a zero ID and ReplacedAt.
+func (h MessageHistory) Versions() []store.MessageRevision {
+	createdAt := h.Message.CreatedAt
+	if h.Message.EditedAt != nil {
+		createdAt = *h.Message.EditedAt
+	}
+
+	versions := make([]store.MessageRevision, 0, len(h.Revisions)+1)
+	versions = append(versions, h.Revisions...)
+	return append(versions, store.MessageRevision{
+		MessageID: h.Message.ID,
+		Revision:  len(h.Revisions) + 1,
+		Subject:   h.Message.Subject,
+		Body:      h.Message.Body,
+		CreatedAt: createdAt,
+	})
+}
+
+// GetMessageHistory returns a message along with its revision history. Only
+// the message's sender and recipients may see it; anyone else is told the
+// message doesn't exist.
+func (s *Service) GetMessageHistory(ctx context.Context,
+	agentID, messageID int64,
+) (MessageHistory, error) {
+	msg, err := s.store.GetMessage(ctx, messageID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return MessageHistory{}, fmt.Errorf("%w: %d",
+			ErrMessageNotFound, messageID)
+
+	case err != nil:
+		return MessageHistory{}, fmt.Errorf("failed to get message: %w",
+			err)
+	}
+
+	ok, err := canViewMessage(ctx, s.store, agentID, msg.ID, msg.SenderID)
+	if err != nil {
+		return MessageHistory{}, err
+	}
+	if !ok {
+		return MessageHistory{}, fmt.Errorf("%w: %d",
+			ErrMessageNotFound, messageID)
+	}
+
+	revisions, err := s.store.ListMessageRevisions(ctx, messageID)
+	if err != nil {
+		return MessageHistory{}, fmt.Errorf("failed to list "+
+			"revisions: %w", err)
+	}
+
+	return MessageHistory{Message: msg, Revisions: revisions}, nil
+}
+
+// ListThreadMessageRevisions lists the versions that edits replaced for every
+// message in a thread, grouped by message and oldest first. Only revisions of
+// messages the agent sent or received are listed, and a thread the agent has
+// no part in is reported as not found.
+func (s *Service) ListThreadMessageRevisions(ctx context.Context,
+	agentID int64, threadID string,
+) ([]store.MessageRevision, error) {
+	msgs, err := s.store.GetMessagesByThreadWithSender(ctx, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thread messages: %w", err)
+	}
+
+	visible := make(map[int64]bool, len(msgs))
+	for _, msg := range msgs {
+		ok, err := canViewMessage(
+			ctx, s.store, agentID, msg.ID, msg.SenderID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			visible[msg.ID] = true
+		}
+	}
+	if len(visible) == 0 {
+		return nil, fmt.Errorf("%w: agent %d has no messages in "+
+			"thread %s", ErrMessageNotFound, agentID, threadID)
+	}
+
+	revisions, err := s.store.ListThreadMessageRevisions(ctx, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	filtered := make([]store.MessageRevision, 0, len(revisions))
+	for _, rev := range revisions {
+		if visible[rev.MessageID] {
+			filtered = append(filtered, rev)
+		}
+	}
+
+	return filtered, nil
+}
+
+// canViewMessage reports whether an agent sent or received a message.
+func canViewMessage(ctx context.Context, storage store.Storage,
+	agentID, messageID, senderID int64,
+) (bool, error) {
+	if agentID == senderID {
+		return true, nil
+	}
+
+	_, err := storage.GetMessageRecipient(ctx, messageID, agentID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+
+	case err != nil:
+		return false, fmt.Errorf("failed to get recipient: %w", err)
+	}
+
+	return true, nil
+}
+
+// RecallMessage deletes a message its sender sent by mistake. Only messages
+// that every recipient still has unread can be recalled, so nobody loses a
+// message they have already seen. Messages under a legal hold or backing a
+// plan review can't be recalled either.
+func (s *Service) RecallMessage(ctx context.Context,
+	senderID, messageID int64,
+) error {
+	return s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		msg, err := getSenderMessage(ctx, txStore, senderID, messageID)
+		if err != nil {
+			return err
+		}
+
+		recipients, err := txStore.GetMessageRecipients(ctx, msg.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get recipients: %w", err)
+		}
+		for _, recipient := range recipients {
+			if recipient.State != StateUnreadStr.String() ||
+				recipient.ReadAt != nil {
+
+				return fmt.Errorf("%w: %s has already read "+
+					"message %d", ErrMessageNotRecallable,
+					recipient.AgentName, msg.ID)
+			}
+		}
+
+		ok, err := txStore.RecallMessage(ctx, msg.ID, senderID)
+		if err != nil {
+			return fmt.Errorf("failed to recall message: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("%w: message %d is under a legal "+
+				"hold or backs a plan review",
+				ErrMessageNotRecallable, msg.ID)
+		}
+
+		return nil
+	})
+}
+
+// getSenderMessage fetches a message on behalf of its sender, failing if the
+// message doesn't exist or was sent by someone else.
+func getSenderMessage(ctx context.Context, txStore store.Storage,
+	senderID, messageID int64,
+) (store.Message, error) {
+	msg, err := txStore.GetMessage(ctx, messageID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return store.Message{}, fmt.Errorf("%w: %d", ErrMessageNotFound,
+			messageID)
+
+	case err != nil:
+		return store.Message{}, fmt.Errorf("failed to get message: %w",
+			err)
+	}
+
+	if msg.SenderID != senderID {
+		return store.Message{}, fmt.Errorf("%w: only the sender of "+
+			"message %d can change it", ErrUnauthorized, messageID)
+	}
+
+	return msg, nil
+}
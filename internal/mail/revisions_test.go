@@ -0,0 +1,311 @@
+package mail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/baselib/actor"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEditMessage tests that only a message's sender can edit it, that each
+// edit records the version it replaced, that search follows the edit and that
+// recipients are notified.
+func TestEditMessage(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	recipient := createTestAgent(t, storage, "Recipient")
+
+	// Subscribe the recipient to the notification hub so the edit
+	// notification can be seen.
+	system := actor.NewActorSystem()
+	defer shutdownSystem(t, system)
+
+	notifHubRef := NotificationHubKey.Spawn(
+		system, "test-notif-hub", NewNotificationHub(),
+	)
+	notifications := make(chan InboxMessage, 10)
+	_, err := notifHubRef.Ask(ctx, SubscribeAgentMsg{
+		AgentID:      recipient.ID,
+		SubscriberID: "test-sub",
+		DeliveryChan: notifications,
+	}).Await(ctx).Unpack()
+	require.NoError(t, err)
+
+	svc := NewService(ServiceConfig{
+		Store:           storage,
+		NotificationHub: notifHubRef,
+	})
+	defer svc.OnStop(ctx)
+
+	resp := svc.handleSendMail(ctx, SendMailRequest{
+		SenderID:       sender.ID,
+		RecipientNames: []string{"Recipient"},
+		Subject:        "Deploy plan",
+		Body:           "Deploy to the western cluster first.",
+		Priority:       PriorityNormal,
+	})
+	require.NoError(t, resp.Error)
+
+	// A message that was never edited has no revisions.
+	history, err := svc.GetMessageHistory(ctx, sender.ID, resp.MessageID)
+	require.NoError(t, err)
+	require.Empty(t, history.Revisions)
+	require.Nil(t, history.Message.EditedAt)
+
+	// Only the sender can edit, and an edit has to change something.
+	_, err = svc.EditMessage(ctx, EditMessageRequest{
+		SenderID:  recipient.ID,
+		MessageID: resp.MessageID,
+		Body:      "Deploy to prod.",
+	})
+	require.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = svc.EditMessage(ctx, EditMessageRequest{
+		SenderID:  sender.ID,
+		MessageID: resp.MessageID,
+	})
+	require.ErrorIs(t, err, ErrInvalidEdit)
+
+	_, err = svc.EditMessage(ctx, EditMessageRequest{
+		SenderID:  sender.ID,
+		MessageID: resp.MessageID,
+		Subject:   "Deploy plan",
+	})
+	require.ErrorIs(t, err, ErrInvalidEdit)
+
+	_, err = svc.EditMessage(ctx, EditMessageRequest{
+		SenderID:  sender.ID,
+		MessageID: resp.MessageID + 100,
+		Body:      "Deploy to prod.",
+	})
+	require.ErrorIs(t, err, ErrMessageNotFound)
+
+	// Drain the notification for the send itself.
+	select {
+	case <-notifications:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no send notification")
+	}
+
+	edit, err := svc.EditMessage(ctx, EditMessageRequest{
+		SenderID:  sender.ID,
+		MessageID: resp.MessageID,
+		Body:      "Deploy to the eastern cluster first.",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, edit.Revision)
+	require.Equal(t, "Deploy plan", edit.Message.Subject)
+	require.NotNil(t, edit.Message.EditedAt)
+
+	select {
+	case notif := <-notifications:
+		require.Equal(t, resp.MessageID, notif.ID)
+		require.Equal(
+			t, "Deploy to the eastern cluster first.", notif.Body,
+		)
+		require.NotNil(t, notif.EditedAt)
+		require.Equal(t, StateUnreadStr.String(), notif.State)
+
+	case <-time.After(5 * time.Second):
+		t.Fatal("no edit notification")
+	}
+
+	edit, err = svc.EditMessage(ctx, EditMessageRequest{
+		SenderID:  sender.ID,
+		MessageID: resp.MessageID,
+		Subject:   "Deploy plan (revised)",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, edit.Revision)
+
+	// The history holds every replaced version, oldest first.
+	history, err = svc.GetMessageHistory(ctx, sender.ID, resp.MessageID)
+	require.NoError(t, err)
+	require.Equal(t, "Deploy plan (revised)", history.Message.Subject)
+	revisions := history.Revisions
+	require.Len(t, revisions, 2)
+	require.Equal(t, 1, revisions[0].Revision)
+	require.Equal(
+		t, "Deploy to the western cluster first.", revisions[0].Body,
+	)
+	require.Equal(t, 2, revisions[1].Revision)
+	require.Equal(t, "Deploy plan", revisions[1].Subject)
+	require.Equal(
+		t, "Deploy to the eastern cluster first.", revisions[1].Body,
+	)
+
+	threadRevisions, err := svc.ListThreadMessageRevisions(
+		ctx, recipient.ID, resp.ThreadID,
+	)
+	require.NoError(t, err)
+	require.Equal(t, revisions, threadRevisions)
+
+	// Recipients read the edited version.
+	msg, err := svc.ReadMessage(ctx, recipient.ID, resp.MessageID)
+	require.NoError(t, err)
+	require.Equal(t, "Deploy plan (revised)", msg.Subject)
+	require.Equal(t, "Deploy to the eastern cluster first.", msg.Body)
+	require.NotNil(t, msg.EditedAt)
+
+	// Search only finds the current version.
+	results, err := storage.SearchMessagesForAgent(
+		ctx, "eastern", recipient.ID, 10,
+	)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	results, err = storage.SearchMessagesForAgent(
+		ctx, "western", recipient.ID, 10,
+	)
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	// Messages under a legal hold can't be edited.
+	_, err = storage.SetMessageLegalHold(ctx, resp.MessageID, true)
+	require.NoError(t, err)
+
+	_, err = svc.EditMessage(ctx, EditMessageRequest{
+		SenderID:  sender.ID,
+		MessageID: resp.MessageID,
+		Body:      "Deploy to prod.",
+	})
+	require.ErrorIs(t, err, ErrMessageLegalHold)
+
+	history, err = svc.GetMessageHistory(ctx, sender.ID, resp.MessageID)
+	require.NoError(t, err)
+	require.Len(t, history.Revisions, 2)
+}
+
+// TestMessageHistoryAccess tests that only a message's sender and recipients
+// can see its revision history.
+func TestMessageHistoryAccess(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	recipient := createTestAgent(t, storage, "Recipient")
+	outsider := createTestAgent(t, storage, "Outsider")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	resp := svc.handleSendMail(ctx, SendMailRequest{
+		SenderID:       sender.ID,
+		RecipientNames: []string{"Recipient"},
+		Subject:        "Credentials",
+		Body:           "The staging password is hunter2.",
+		Priority:       PriorityNormal,
+	})
+	require.NoError(t, resp.Error)
+
+	_, err := svc.EditMessage(ctx, EditMessageRequest{
+		SenderID:  sender.ID,
+		MessageID: resp.MessageID,
+		Body:      "Ask me for the staging password.",
+	})
+	require.NoError(t, err)
+
+	for _, agentID := range []int64{sender.ID, recipient.ID} {
+		history, err := svc.GetMessageHistory(
+			ctx, agentID, resp.MessageID,
+		)
+		require.NoError(t, err)
+		require.Len(t, history.Revisions, 1)
+
+		revisions, err := svc.ListThreadMessageRevisions(
+			ctx, agentID, resp.ThreadID,
+		)
+		require.NoError(t, err)
+		require.Len(t, revisions, 1)
+	}
+
+	// An agent that neither sent nor received the message is told it
+	// doesn't exist.
+	_, err = svc.GetMessageHistory(ctx, outsider.ID, resp.MessageID)
+	require.ErrorIs(t, err, ErrMessageNotFound)
+
+	_, err = svc.ListThreadMessageRevisions(
+		ctx, outsider.ID, resp.ThreadID,
+	)
+	require.ErrorIs(t, err, ErrMessageNotFound)
+}
+
+// TestRecallMessage tests that a sender can recall a message nobody has read
+// yet, and that read messages can't be recalled.
+func TestRecallMessage(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	first := createTestAgent(t, storage, "First")
+	createTestAgent(t, storage, "Second")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	send := func() int64 {
+		resp := svc.handleSendMail(ctx, SendMailRequest{
+			SenderID:       sender.ID,
+			RecipientNames: []string{"First", "Second"},
+			Subject:        "Wrong instructions",
+			Body:           "Drop the users table.",
+			Priority:       PriorityNormal,
+		})
+		require.NoError(t, resp.Error)
+
+		return resp.MessageID
+	}
+
+	// An unread message can be recalled by its sender only.
+	unread := send()
+	err := svc.RecallMessage(ctx, first.ID, unread)
+	require.ErrorIs(t, err, ErrUnauthorized)
+
+	_, err = svc.EditMessage(ctx, EditMessageRequest{
+		SenderID:  sender.ID,
+		MessageID: unread,
+		Body:      "Drop the sessions table.",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.RecallMessage(ctx, sender.ID, unread))
+
+	_, err = storage.GetMessage(ctx, unread)
+	require.Error(t, err)
+
+	inbox, err := storage.GetInboxMessages(ctx, first.ID, 10, 0)
+	require.NoError(t, err)
+	require.Empty(t, inbox)
+
+	err = svc.RecallMessage(ctx, sender.ID, unread)
+	require.ErrorIs(t, err, ErrMessageNotFound)
+
+	// Once any recipient has read it, it can't be recalled.
+	read := send()
+	_, err = svc.ReadMessage(ctx, first.ID, read)
+	require.NoError(t, err)
+
+	err = svc.RecallMessage(ctx, sender.ID, read)
+	require.ErrorIs(t, err, ErrMessageNotRecallable)
+
+	// Neither can a message under a legal hold.
+	held := send()
+	_, err = storage.SetMessageLegalHold(ctx, held, true)
+	require.NoError(t, err)
+
+	err = svc.RecallMessage(ctx, sender.ID, held)
+	require.ErrorIs(t, err, ErrMessageNotRecallable)
+}
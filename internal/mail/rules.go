@@ -0,0 +1,720 @@
+package mail
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+const (
+	// DefaultRuleTestLimit is the number of recently received messages an
+	// inbox rule is tried against by default.
+	DefaultRuleTestLimit = 100
+
+	// maxRuleTestLimit caps the number of messages a rule is tried
+	// against.
+	maxRuleTestLimit = 1000
+)
+
+// CreateInboxRuleRequest describes an inbox rule.
+type CreateInboxRuleRequest struct {
+	// AgentID is the agent whose inbox the rule applies to.
+	AgentID int64
+
+	// Name identifies the rule among the agent's rules.
+	Name string
+
+	// StopProcessing keeps the agent's later rules from being applied
+	// to the messages this rule matches.
+	StopProcessing bool
+
+	// Conditions decide which messages the rule matches. At least one is
+	// required.
+	Conditions store.InboxRuleConditions
+
+	// Actions are applied to the messages the rule matches. At least one
+	// is required, and at most one of archive, star and snooze.
+	Actions store.InboxRuleActions
+}
+
+// CreateInboxRule validates and stores an inbox rule. It applies to messages
+// delivered from now on, after the agent's existing rules.
+func (s *Service) CreateInboxRule(ctx context.Context,
+	req CreateInboxRuleRequest,
+) (store.InboxRule, error) {
+	if !groupNameRE.MatchString(req.Name) {
+		return store.InboxRule{}, fmt.Errorf("%w: name %q must be "+
+			"lowercase letters, digits, '.', '_' or '-'",
+			ErrInvalidInboxRule, req.Name)
+	}
+
+	if err := validateRuleConditions(req.Conditions); err != nil {
+		return store.InboxRule{}, err
+	}
+	if err := validateRuleActions(req.Actions); err != nil {
+		return store.InboxRule{}, err
+	}
+
+	owner, err := s.store.GetAgent(ctx, req.AgentID)
+	if err != nil {
+		return store.InboxRule{}, fmt.Errorf("agent not found: %w", err)
+	}
+
+	if forwardTo := req.Actions.ForwardTo; forwardTo != "" {
+		if forwardTo == owner.Name {
+			return store.InboxRule{}, fmt.Errorf("%w: can't "+
+				"forward to the rule's own agent",
+				ErrInvalidInboxRule)
+		}
+
+		_, err := s.store.GetAgentByName(ctx, forwardTo)
+		if err != nil {
+			return store.InboxRule{}, fmt.Errorf("%w: unknown "+
+				"agent %q to forward to", ErrInvalidInboxRule,
+				forwardTo)
+		}
+	}
+
+	_, err = s.store.GetInboxRuleByName(ctx, req.AgentID, req.Name)
+	switch {
+	case err == nil:
+		return store.InboxRule{}, fmt.Errorf("%w: %s",
+			ErrInboxRuleExists, req.Name)
+
+	case !errors.Is(err, sql.ErrNoRows):
+		return store.InboxRule{}, fmt.Errorf("failed to get inbox "+
+			"rule: %w", err)
+	}
+
+	rule, err := s.store.CreateInboxRule(ctx, store.CreateInboxRuleParams{
+		AgentID:             req.AgentID,
+		Name:                req.Name,
+		StopProcessing:      req.StopProcessing,
+		InboxRuleConditions: req.Conditions,
+		InboxRuleActions:    req.Actions,
+	})
+	if err != nil {
+		return store.InboxRule{}, fmt.Errorf("failed to create inbox "+
+			"rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// ListInboxRules returns the agent's inbox rules in the order they are
+// applied.
+func (s *Service) ListInboxRules(ctx context.Context,
+	agentID int64,
+) ([]store.InboxRule, error) {
+	rules, err := s.store.ListInboxRules(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inbox rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// DeleteInboxRule deletes one of the agent's inbox rules. Messages it already
+// acted on are left as they are.
+func (s *Service) DeleteInboxRule(ctx context.Context, agentID int64,
+	name string,
+) error {
+	deleted, err := s.store.DeleteInboxRule(ctx, agentID, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete inbox rule: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %s", ErrInboxRuleNotFound, name)
+	}
+
+	return nil
+}
+
+// SetInboxRuleEnabled enables or disables one of the agent's inbox rules,
+// returning the updated rule.
+func (s *Service) SetInboxRuleEnabled(ctx context.Context, agentID int64,
+	name string, enabled bool,
+) (store.InboxRule, error) {
+	var rule store.InboxRule
+	err := s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		ok, err := txStore.SetInboxRuleEnabled(
+			ctx, agentID, name, enabled,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update inbox rule: %w",
+				err)
+		}
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrInboxRuleNotFound, name)
+		}
+
+		rule, err = txStore.GetInboxRuleByName(ctx, agentID, name)
+		if err != nil {
+			return fmt.Errorf("failed to get inbox rule: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return store.InboxRule{}, err
+	}
+
+	return rule, nil
+}
+
+// TestInboxRuleRequest describes a dry run of an inbox rule.
+type TestInboxRuleRequest struct {
+	// AgentID is the agent whose received messages are tried.
+	AgentID int64
+
+	// Name names a saved rule to try. If empty, Conditions are tried
+	// instead.
+	Name string
+
+	// Conditions are the conditions to try when Name is empty.
+	Conditions store.InboxRuleConditions
+
+	// Limit is the number of recently received messages to try,
+	// DefaultRuleTestLimit by default.
+	Limit int
+}
+
+// TestInboxRuleResponse is the result of a dry run of an inbox rule.
+type TestInboxRuleResponse struct {
+	// Scanned is the number of messages the rule was tried against.
+	Scanned int
+
+	// Matches are the messages the rule matched, newest first.
+	Matches []InboxMessage
+}
+
+// TestInboxRule tries an inbox rule's conditions against the messages the
+// agent received most recently, in any state but trash, without changing
+// anything. It shows what a rule would have matched before it is created.
+func (s *Service) TestInboxRule(ctx context.Context,
+	req TestInboxRuleRequest,
+) (TestInboxRuleResponse, error) {
+	conditions := req.Conditions
+	if req.Name != "" {
+		rule, err := s.store.GetInboxRuleByName(
+			ctx, req.AgentID, req.Name,
+		)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return TestInboxRuleResponse{}, fmt.Errorf("%w: %s",
+				ErrInboxRuleNotFound, req.Name)
+
+		case err != nil:
+			return TestInboxRuleResponse{}, fmt.Errorf("failed to "+
+				"get inbox rule: %w", err)
+		}
+		conditions = rule.InboxRuleConditions
+	}
+	if err := validateRuleConditions(conditions); err != nil {
+		return TestInboxRuleResponse{}, err
+	}
+	matcher := newRuleMatcher(conditions)
+
+	limit := req.Limit
+	switch {
+	case limit <= 0:
+		limit = DefaultRuleTestLimit
+	case limit > maxRuleTestLimit:
+		limit = maxRuleTestLimit
+	}
+
+	msgs, err := s.store.ListReceivedMessages(ctx, req.AgentID, limit)
+	if err != nil {
+		return TestInboxRuleResponse{}, fmt.Errorf("failed to list "+
+			"messages: %w", err)
+	}
+
+	resp := TestInboxRuleResponse{Scanned: len(msgs)}
+	topicNames := make(map[int64]string)
+	for _, msg := range msgs {
+		topicName, ok := topicNames[msg.TopicID]
+		if !ok {
+			topic, err := s.store.GetTopic(ctx, msg.TopicID)
+			if err != nil {
+				return TestInboxRuleResponse{}, fmt.Errorf(
+					"failed to get topic: %w", err,
+				)
+			}
+			topicName = topic.Name
+			topicNames[msg.TopicID] = topicName
+		}
+
+		candidate := ruleMessage{
+			senderName: msg.SenderName,
+			subject:    msg.Subject,
+			topicName:  topicName,
+			priority:   msg.Priority,
+			metadata:   decodeMetadata(msg.Metadata),
+		}
+		if matcher.matches(candidate) {
+			resp.Matches = append(
+				resp.Matches, storeInboxToMail(msg),
+			)
+		}
+	}
+
+	return resp, nil
+}
+
+// ruleMessage is a message as inbox rules see it when it is delivered.
+type ruleMessage struct {
+	senderName string
+	subject    string
+	topicName  string
+	priority   string
+	metadata   map[string]string
+}
+
+// ruleMatcher is a rule's conditions with its subject pattern compiled, so
+// the pattern is compiled once rather than for every message it's checked
+// against.
+type ruleMatcher struct {
+	cond store.InboxRuleConditions
+
+	// subject is the compiled subject pattern, or nil if the rule has
+	// none.
+	subject *regexp.Regexp
+
+	// invalid is set if the subject pattern doesn't compile, in which
+	// case the rule matches nothing.
+	invalid bool
+}
+
+// newRuleMatcher compiles a rule's conditions for matching.
+func newRuleMatcher(cond store.InboxRuleConditions) ruleMatcher {
+	matcher := ruleMatcher{cond: cond}
+	if cond.SubjectPattern != "" {
+		// Patterns are validated when the rule is created.
+		re, err := regexp.Compile(cond.SubjectPattern)
+		if err != nil {
+			matcher.invalid = true
+		}
+		matcher.subject = re
+	}
+
+	return matcher
+}
+
+// matches reports whether a message meets every condition of a rule that
+// is set.
+func (m ruleMatcher) matches(msg ruleMessage) bool {
+	if m.invalid {
+		return false
+	}
+
+	cond := m.cond
+	if cond.SenderName != "" && cond.SenderName != msg.senderName {
+		return false
+	}
+	if cond.SenderPrefix != "" &&
+		!strings.HasPrefix(msg.senderName, cond.SenderPrefix) {
+
+		return false
+	}
+	if cond.TopicName != "" && cond.TopicName != msg.topicName {
+		return false
+	}
+	if cond.Priority != "" && cond.Priority != msg.priority {
+		return false
+	}
+	if cond.MetadataKey != "" {
+		value, ok := msg.metadata[cond.MetadataKey]
+		if !ok {
+			return false
+		}
+		if cond.MetadataValue != "" && cond.MetadataValue != value {
+			return false
+		}
+	}
+	if m.subject != nil && !m.subject.MatchString(msg.subject) {
+		return false
+	}
+
+	return true
+}
+
+// ruleOutcome is what an agent's inbox rules decided for a message delivered
+// to it.
+type ruleOutcome struct {
+	// matched are the IDs of the rules that matched.
+	matched []int64
+
+	// archive, snooze and star are the state actions of the matching
+	// rules. Archiving takes precedence over snoozing, and snoozing over
+	// starring.
+	archive      bool
+	snoozedUntil *time.Time
+	star         bool
+
+	// category is the category set by the last matching rule that set
+	// one.
+	category store.InboxCategory
+
+	// forwardTo are the agents the matching rules forward to.
+	forwardTo []string
+
+	// labels are the labels the matching rules apply.
+	labels []string
+}
+
+// state returns the recipient state the outcome sets, or an empty string if
+// the message stays unread.
+func (o ruleOutcome) state() RecipientState {
+	switch {
+	case o.archive:
+		return StateArchivedStr
+	case o.snoozedUntil != nil:
+		return StateSnoozedStr
+	case o.star:
+		return StateStarredStr
+	default:
+		return ""
+	}
+}
+
+// quiet reports whether the outcome moves the message out of the agent's
+// inbox, so the agent shouldn't be notified of it.
+func (o ruleOutcome) quiet() bool {
+	return o.archive || o.snoozedUntil != nil
+}
+
+// evaluateInboxRules applies an agent's enabled rules to a message in order,
+// until a matching rule stops processing. Each rule's subject pattern is
+// compiled at most once per call.
+func evaluateInboxRules(rules []store.InboxRule, msg ruleMessage,
+	now time.Time,
+) ruleOutcome {
+	var outcome ruleOutcome
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		if !newRuleMatcher(rule.InboxRuleConditions).matches(msg) {
+			continue
+		}
+		outcome.matched = append(outcome.matched, rule.ID)
+
+		actions := rule.InboxRuleActions
+		outcome.archive = outcome.archive || actions.Archive
+		outcome.star = outcome.star || actions.Star
+		if actions.Snooze != "" && outcome.snoozedUntil == nil {
+			// Snoozes are validated when the rule is created.
+			until, err := snoozeUntil(actions.Snooze, now)
+			if err == nil {
+				outcome.snoozedUntil = &until
+			}
+		}
+		if actions.Category != store.InboxCategoryAll {
+			outcome.category = actions.Category
+		}
+		if actions.ForwardTo != "" {
+			outcome.forwardTo = append(
+				outcome.forwardTo, actions.ForwardTo,
+			)
+		}
+		if actions.Label != "" {
+			outcome.labels = append(outcome.labels, actions.Label)
+		}
+
+		if rule.StopProcessing {
+			break
+		}
+	}
+
+	return outcome
+}
+
+// applyInboxRules applies each recipient's inbox rules to a message that was
+// just delivered to them, within the delivering transaction. Forwarded
+// messages are delivered to the forwarding target as another recipient of
+// the same message, and the target's own rules apply in turn; an agent is
+// never delivered a message twice, so forwarding can't loop. It returns every
+// recipient the message was forwarded to, the recipients that should be
+// notified of the message, which excludes those whose rules archived or
+// snoozed it, and the outbox events of the state changes the rules made,
+// to be dispatched once the transaction commits.
+func (s *Service) applyInboxRules(ctx context.Context, txStore store.Storage,
+	messageID int64, threadID string, recipientIDs []int64,
+	msg ruleMessage,
+) ([]int64, []int64, []ThreadOutboxEvent, error) {
+	now := time.Now()
+
+	delivered := make(map[int64]bool, len(recipientIDs))
+	for _, id := range recipientIDs {
+		delivered[id] = true
+	}
+
+	var (
+		forwarded []int64
+		notify    []int64
+		outbox    []ThreadOutboxEvent
+		queue     = append([]int64(nil), recipientIDs...)
+	)
+	for len(queue) > 0 {
+		agentID := queue[0]
+		queue = queue[1:]
+
+		rules, err := txStore.ListInboxRules(ctx, agentID)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to list "+
+				"inbox rules: %w", err)
+		}
+
+		outcome := evaluateInboxRules(rules, msg, now)
+		events, err := s.applyRuleOutcome(
+			ctx, txStore, messageID, threadID, agentID, outcome,
+		)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		outbox = append(outbox, events...)
+		if !outcome.quiet() {
+			notify = append(notify, agentID)
+		}
+
+		for _, name := range outcome.forwardTo {
+			target, err := txStore.GetAgentByName(ctx, name)
+			if errors.Is(err, sql.ErrNoRows) {
+				// The target was deleted after the rule was
+				// created.
+				continue
+			}
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("failed "+
+					"to get forward target: %w", err)
+			}
+			if delivered[target.ID] {
+				continue
+			}
+
+			err = txStore.CreateMessageRecipient(
+				ctx, messageID, target.ID,
+			)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("failed "+
+					"to forward message: %w", err)
+			}
+			delivered[target.ID] = true
+			forwarded = append(forwarded, target.ID)
+			queue = append(queue, target.ID)
+		}
+	}
+
+	return forwarded, notify, outbox, nil
+}
+
+// applyRuleOutcome records the rules that matched a message for a recipient
+// and sets the recipient state, category and labels they decided on. The
+// state change goes through the thread FSM like any other, so a snooze
+// schedules its wake; its outbox events are returned.
+func (s *Service) applyRuleOutcome(ctx context.Context,
+	txStore store.Storage, messageID int64, threadID string,
+	agentID int64, outcome ruleOutcome,
+) ([]ThreadOutboxEvent, error) {
+	now := time.Now()
+	for _, ruleID := range outcome.matched {
+		err := txStore.RecordInboxRuleMatch(ctx, ruleID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record rule "+
+				"match: %w", err)
+		}
+	}
+
+	var outbox []ThreadOutboxEvent
+	if state := outcome.state(); state != "" {
+		recipient, err := txStore.GetMessageRecipient(
+			ctx, messageID, agentID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply inbox rule: "+
+				"%w", err)
+		}
+
+		_, outbox, err = s.runTransition(
+			ctx, txStore, recipient, threadID,
+			func(ctx context.Context,
+				fsm *ThreadFSM) ([]ThreadOutboxEvent, error) {
+
+				return s.threadFSMs.TransitionTo(
+					ctx, fsm, state, outcome.snoozedUntil,
+				)
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply inbox rule: "+
+				"%w", err)
+		}
+	}
+
+	if outcome.category != store.InboxCategoryAll {
+		err := txStore.SetRecipientCategory(
+			ctx, messageID, agentID, outcome.category,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply inbox rule: "+
+				"%w", err)
+		}
+	}
+
+	for _, label := range outcome.labels {
+		err := addMessageLabel(ctx, txStore, messageID, agentID, label)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply inbox rule: "+
+				"%w", err)
+		}
+	}
+
+	return outbox, nil
+}
+
+// validateRuleConditions checks that a rule has at least one condition and
+// that each is well formed.
+func validateRuleConditions(cond store.InboxRuleConditions) error {
+	if cond == (store.InboxRuleConditions{}) {
+		return fmt.Errorf("%w: at least one condition is required",
+			ErrInvalidInboxRule)
+	}
+
+	if cond.SubjectPattern != "" {
+		if _, err := regexp.Compile(cond.SubjectPattern); err != nil {
+			return fmt.Errorf("%w: subject pattern: %v",
+				ErrInvalidInboxRule, err)
+		}
+	}
+
+	switch Priority(cond.Priority) {
+	case "", PriorityUrgent, PriorityNormal, PriorityLow:
+	default:
+		return fmt.Errorf("%w: unknown priority %q",
+			ErrInvalidInboxRule, cond.Priority)
+	}
+
+	if cond.MetadataValue != "" && cond.MetadataKey == "" {
+		return fmt.Errorf("%w: a metadata value needs a metadata key",
+			ErrInvalidInboxRule)
+	}
+
+	return nil
+}
+
+// validateRuleActions checks that a rule has at least one action, at most one
+// of archive, star and snooze, and that each is well formed.
+func validateRuleActions(actions store.InboxRuleActions) error {
+	if actions == (store.InboxRuleActions{}) {
+		return fmt.Errorf("%w: at least one action is required",
+			ErrInvalidInboxRule)
+	}
+
+	var numStates int
+	for _, set := range []bool{
+		actions.Archive, actions.Star, actions.Snooze != "",
+	} {
+		if set {
+			numStates++
+		}
+	}
+	if numStates > 1 {
+		return fmt.Errorf("%w: only one of archive, star and snooze "+
+			"can be set", ErrInvalidInboxRule)
+	}
+
+	if actions.Snooze != "" {
+		_, err := snoozeUntil(actions.Snooze, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	switch actions.Category {
+	case store.InboxCategoryAll, store.InboxCategoryPrimary,
+		store.InboxCategoryNotifications:
+
+	default:
+		return fmt.Errorf("%w: unknown category %q",
+			ErrInvalidInboxRule, actions.Category)
+	}
+
+	if actions.Label != "" && !labelNameRE.MatchString(actions.Label) {
+		return fmt.Errorf("%w: malformed label %q", ErrInvalidInboxRule,
+			actions.Label)
+	}
+
+	return nil
+}
+
+// snoozeUntil resolves an inbox rule's snooze action to the time a message
+// delivered at now wakes up. The snooze is either a positive duration, such
+// as "2h", or a time of day in the daemon's time zone, such as "09:00", in
+// which case the message wakes at its next occurrence.
+func snoozeUntil(snooze string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(snooze); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("%w: snooze %q must be "+
+				"positive", ErrInvalidInboxRule, snooze)
+		}
+
+		return now.Add(d), nil
+	}
+
+	clock, err := time.Parse("15:04", snooze)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: snooze %q is neither a "+
+			"duration nor a time of day (HH:MM)",
+			ErrInvalidInboxRule, snooze)
+	}
+
+	local := now.Local()
+	until := time.Date(
+		local.Year(), local.Month(), local.Day(), clock.Hour(),
+		clock.Minute(), 0, 0, local.Location(),
+	)
+	if !until.After(local) {
+		until = until.AddDate(0, 0, 1)
+	}
+
+	return until, nil
+}
+
+// encodeMetadata encodes message metadata for storage. Messages without
+// metadata store none.
+func encodeMetadata(metadata map[string]string) (string, error) {
+	if len(metadata) == 0 {
+		return "", nil
+	}
+
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	return string(encoded), nil
+}
+
+// decodeMetadata decodes stored message metadata. Metadata that isn't a JSON
+// object of strings, which older messages may carry, decodes to nothing.
+func decodeMetadata(encoded string) map[string]string {
+	if encoded == "" {
+		return nil
+	}
+
+	var metadata map[string]string
+	if err := json.Unmarshal([]byte(encoded), &metadata); err != nil {
+		return nil
+	}
+
+	return metadata
+}
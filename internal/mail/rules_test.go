@@ -0,0 +1,520 @@
+package mail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInboxRulesOnSend tests that a recipient's inbox rules file messages
+// sent to it as they are delivered, and that forwarding can't loop.
+func TestInboxRulesOnSend(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	user := createTestAgent(t, storage, "User")
+	reviewer := createTestAgent(t, storage, "reviewer-alpha")
+	alice := createTestAgent(t, storage, "Alice")
+	bob := createTestAgent(t, storage, "Bob")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	createRule := func(agentID int64, name string,
+		cond store.InboxRuleConditions,
+		actions store.InboxRuleActions, stop bool,
+	) {
+		_, err := svc.CreateInboxRule(ctx, CreateInboxRuleRequest{
+			AgentID:        agentID,
+			Name:           name,
+			StopProcessing: stop,
+			Conditions:     cond,
+			Actions:        actions,
+		})
+		require.NoError(t, err)
+	}
+	send := func(senderID int64, to []string, subject string,
+		priority Priority, metadata map[string]string,
+	) int64 {
+		resp := svc.handleSendMail(ctx, SendMailRequest{
+			SenderID:       senderID,
+			RecipientNames: to,
+			Subject:        subject,
+			Body:           "body",
+			Priority:       priority,
+			Metadata:       metadata,
+		})
+		require.NoError(t, resp.Error)
+
+		return resp.MessageID
+	}
+	recipient := func(msgID, agentID int64) store.MessageRecipient {
+		r, err := storage.GetMessageRecipient(ctx, msgID, agentID)
+		require.NoError(t, err)
+
+		return r
+	}
+
+	// Status mail from reviewers is archived, and stops processing so
+	// the star rule after it doesn't apply.
+	createRule(user.ID, "reviewers", store.InboxRuleConditions{
+		SenderPrefix: "reviewer-",
+	}, store.InboxRuleActions{Archive: true}, true)
+	createRule(user.ID, "star-all", store.InboxRuleConditions{
+		SubjectPattern: `.`,
+	}, store.InboxRuleActions{Star: true}, false)
+
+	reviewed := send(
+		reviewer.ID, []string{"User"}, "Review done", PriorityNormal,
+		nil,
+	)
+	require.Equal(t, "archived", recipient(reviewed, user.ID).State)
+
+	direct := send(
+		alice.ID, []string{"User"}, "Question", PriorityNormal, nil,
+	)
+	require.Equal(t, "starred", recipient(direct, user.ID).State)
+
+	inbox, err := storage.GetInboxMessages(ctx, user.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, inbox, 1)
+	require.Equal(t, direct, inbox[0].ID)
+
+	rules, err := svc.ListInboxRules(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Equal(t, "reviewers", rules[0].Name)
+	require.EqualValues(t, 1, rules[0].MatchCount)
+	require.NotNil(t, rules[0].LastMatchedAt)
+	require.EqualValues(t, 1, rules[1].MatchCount)
+
+	// Disabled rules don't apply.
+	_, err = svc.SetInboxRuleEnabled(ctx, user.ID, "star-all", false)
+	require.NoError(t, err)
+
+	plain := send(alice.ID, []string{"User"}, "Lunch", PriorityNormal, nil)
+	require.Equal(t, "unread", recipient(plain, user.ID).State)
+
+	// Metadata and priority conditions, a snooze and a category.
+	createRule(user.ID, "ci-failures", store.InboxRuleConditions{
+		MetadataKey:   "ci",
+		MetadataValue: "failed",
+		Priority:      string(PriorityLow),
+	}, store.InboxRuleActions{
+		Snooze:   "2h",
+		Category: store.InboxCategoryNotifications,
+	}, false)
+
+	failed := send(
+		alice.ID, []string{"User"}, "Build 12", PriorityLow,
+		map[string]string{"ci": "failed"},
+	)
+	r := recipient(failed, user.ID)
+	require.Equal(t, "snoozed", r.State)
+	require.NotNil(t, r.SnoozedUntil)
+	require.WithinDuration(
+		t, time.Now().Add(2*time.Hour), *r.SnoozedUntil, time.Minute,
+	)
+	require.Equal(t, store.InboxCategoryNotifications, r.Category)
+
+	passed := send(
+		alice.ID, []string{"User"}, "Build 13", PriorityLow,
+		map[string]string{"ci": "passed"},
+	)
+	require.Equal(t, "unread", recipient(passed, user.ID).State)
+
+	// A category set by a rule overrides the one the subject implies.
+	counts, err := storage.CountInboxCategories(ctx, user.ID)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, counts.Notifications)
+
+	// Forwarding delivers the message to the target as well, and the
+	// target's rules apply in turn without looping back.
+	createRule(alice.ID, "urgent-to-bob", store.InboxRuleConditions{
+		Priority: string(PriorityUrgent),
+	}, store.InboxRuleActions{ForwardTo: "Bob"}, false)
+	createRule(bob.ID, "urgent-to-alice", store.InboxRuleConditions{
+		Priority: string(PriorityUrgent),
+	}, store.InboxRuleActions{ForwardTo: "Alice", Star: true}, false)
+
+	urgent := send(
+		user.ID, []string{"Alice"}, "Outage", PriorityUrgent, nil,
+	)
+	require.Equal(t, "unread", recipient(urgent, alice.ID).State)
+	require.Equal(t, "starred", recipient(urgent, bob.ID).State)
+
+	recipients, err := storage.GetMessageRecipients(ctx, urgent)
+	require.NoError(t, err)
+	require.Len(t, recipients, 2)
+}
+
+// TestInboxRuleSnoozeWakes tests that a message snoozed by an inbox rule is
+// woken back to unread when its snooze ends, like a snooze made by hand.
+func TestInboxRuleSnoozeWakes(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	recipient := createTestAgent(t, storage, "Recipient")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	_, err := svc.CreateInboxRule(ctx, CreateInboxRuleRequest{
+		AgentID: recipient.ID,
+		Name:    "later",
+		Conditions: store.InboxRuleConditions{
+			SenderName: "Sender",
+		},
+		Actions: store.InboxRuleActions{Snooze: "200ms"},
+	})
+	require.NoError(t, err)
+
+	resp := svc.handleSendMail(ctx, SendMailRequest{
+		SenderID:       sender.ID,
+		RecipientNames: []string{recipient.Name},
+		Subject:        "Snooze me",
+		Body:           "body",
+		Priority:       PriorityNormal,
+	})
+	require.NoError(t, resp.Error)
+
+	r, err := storage.GetMessageRecipient(ctx, resp.MessageID, recipient.ID)
+	require.NoError(t, err)
+	require.Equal(t, StateSnoozedStr.String(), r.State)
+
+	require.Eventually(t, func() bool {
+		r, err := storage.GetMessageRecipient(
+			ctx, resp.MessageID, recipient.ID,
+		)
+		require.NoError(t, err)
+
+		return r.State == StateUnreadStr.String()
+	}, 3*time.Second, 20*time.Millisecond)
+}
+
+// TestInboxRulesOnPublish tests that subscribers' inbox rules apply to
+// messages published to a topic.
+func TestInboxRulesOnPublish(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	publisher := createTestAgent(t, storage, "Publisher")
+	quiet := createTestAgent(t, storage, "Quiet")
+	loud := createTestAgent(t, storage, "Loud")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	for _, agentID := range []int64{quiet.ID, loud.ID} {
+		_, err := svc.Subscribe(ctx, agentID, "builds")
+		require.NoError(t, err)
+	}
+
+	_, err := svc.CreateInboxRule(ctx, CreateInboxRuleRequest{
+		AgentID: quiet.ID,
+		Name:    "builds",
+		Conditions: store.InboxRuleConditions{
+			TopicName: "builds",
+		},
+		Actions: store.InboxRuleActions{Archive: true},
+	})
+	require.NoError(t, err)
+
+	resp, err := svc.Publish(ctx, PublishRequest{
+		SenderID:  publisher.ID,
+		TopicName: "builds",
+		Subject:   "Build 7 passed",
+		Body:      "All green.",
+		Priority:  PriorityNormal,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, resp.RecipientsCount)
+
+	r, err := storage.GetMessageRecipient(ctx, resp.MessageID, quiet.ID)
+	require.NoError(t, err)
+	require.Equal(t, "archived", r.State)
+
+	r, err = storage.GetMessageRecipient(ctx, resp.MessageID, loud.ID)
+	require.NoError(t, err)
+	require.Equal(t, "unread", r.State)
+}
+
+// TestCreateInboxRuleValidation tests that malformed inbox rules are
+// rejected.
+func TestCreateInboxRuleValidation(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	user := createTestAgent(t, storage, "User")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	valid := store.InboxRuleConditions{SenderName: "Alice"}
+	archive := store.InboxRuleActions{Archive: true}
+
+	tests := []struct {
+		name       string
+		ruleName   string
+		conditions store.InboxRuleConditions
+		actions    store.InboxRuleActions
+	}{{
+		name:       "bad name",
+		ruleName:   "Bad Name",
+		conditions: valid,
+		actions:    archive,
+	}, {
+		name:     "no conditions",
+		ruleName: "empty",
+		actions:  archive,
+	}, {
+		name:       "no actions",
+		ruleName:   "noop",
+		conditions: valid,
+	}, {
+		name:     "bad pattern",
+		ruleName: "pattern",
+		conditions: store.InboxRuleConditions{
+			SubjectPattern: "(",
+		},
+		actions: archive,
+	}, {
+		name:     "bad priority",
+		ruleName: "priority",
+		conditions: store.InboxRuleConditions{
+			Priority: "critical",
+		},
+		actions: archive,
+	}, {
+		name:     "value without key",
+		ruleName: "metadata",
+		conditions: store.InboxRuleConditions{
+			MetadataValue: "failed",
+		},
+		actions: archive,
+	}, {
+		name:       "two states",
+		ruleName:   "states",
+		conditions: valid,
+		actions:    store.InboxRuleActions{Archive: true, Star: true},
+	}, {
+		name:       "bad snooze",
+		ruleName:   "snooze",
+		conditions: valid,
+		actions:    store.InboxRuleActions{Snooze: "tomorrow"},
+	}, {
+		name:       "bad category",
+		ruleName:   "category",
+		conditions: valid,
+		actions:    store.InboxRuleActions{Category: "social"},
+	}, {
+		name:       "unknown forward target",
+		ruleName:   "forward",
+		conditions: valid,
+		actions:    store.InboxRuleActions{ForwardTo: "Nobody"},
+	}, {
+		name:       "forward to self",
+		ruleName:   "self",
+		conditions: valid,
+		actions:    store.InboxRuleActions{ForwardTo: "User"},
+	}}
+	for _, tc := range tests {
+		_, err := svc.CreateInboxRule(ctx, CreateInboxRuleRequest{
+			AgentID:    user.ID,
+			Name:       tc.ruleName,
+			Conditions: tc.conditions,
+			Actions:    tc.actions,
+		})
+		require.ErrorIs(t, err, ErrInvalidInboxRule, tc.name)
+	}
+
+	_, err := svc.CreateInboxRule(ctx, CreateInboxRuleRequest{
+		AgentID:    user.ID,
+		Name:       "alice",
+		Conditions: valid,
+		Actions:    archive,
+	})
+	require.NoError(t, err)
+
+	_, err = svc.CreateInboxRule(ctx, CreateInboxRuleRequest{
+		AgentID:    user.ID,
+		Name:       "alice",
+		Conditions: valid,
+		Actions:    archive,
+	})
+	require.ErrorIs(t, err, ErrInboxRuleExists)
+
+	require.NoError(t, svc.DeleteInboxRule(ctx, user.ID, "alice"))
+	err = svc.DeleteInboxRule(ctx, user.ID, "alice")
+	require.ErrorIs(t, err, ErrInboxRuleNotFound)
+
+	_, err = svc.SetInboxRuleEnabled(ctx, user.ID, "alice", true)
+	require.ErrorIs(t, err, ErrInboxRuleNotFound)
+}
+
+// TestTestInboxRule tests that a dry run reports the recently received
+// messages a rule would match without changing them.
+func TestTestInboxRule(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	user := createTestAgent(t, storage, "User")
+	reviewer := createTestAgent(t, storage, "reviewer-alpha")
+	alice := createTestAgent(t, storage, "Alice")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	for i := 0; i < 3; i++ {
+		resp := svc.handleSendMail(ctx, SendMailRequest{
+			SenderID:       reviewer.ID,
+			RecipientNames: []string{"User"},
+			Subject:        "[Status] reviewing",
+			Body:           "Still reviewing.",
+			Priority:       PriorityNormal,
+		})
+		require.NoError(t, resp.Error)
+	}
+	resp := svc.handleSendMail(ctx, SendMailRequest{
+		SenderID:       alice.ID,
+		RecipientNames: []string{"User"},
+		Subject:        "Question",
+		Body:           "Can you look at this?",
+		Priority:       PriorityNormal,
+	})
+	require.NoError(t, resp.Error)
+
+	conditions := store.InboxRuleConditions{SenderPrefix: "reviewer-"}
+	result, err := svc.TestInboxRule(ctx, TestInboxRuleRequest{
+		AgentID:    user.ID,
+		Conditions: conditions,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, result.Scanned)
+	require.Len(t, result.Matches, 3)
+	for _, msg := range result.Matches {
+		require.Equal(t, "reviewer-alpha", msg.SenderName)
+		require.Equal(t, "unread", msg.State)
+	}
+
+	// The limit bounds the messages tried, newest first.
+	result, err = svc.TestInboxRule(ctx, TestInboxRuleRequest{
+		AgentID:    user.ID,
+		Conditions: conditions,
+		Limit:      1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Scanned)
+	require.Empty(t, result.Matches)
+
+	// A saved rule can be tried by name.
+	_, err = svc.CreateInboxRule(ctx, CreateInboxRuleRequest{
+		AgentID: user.ID,
+		Name:    "question",
+		Conditions: store.InboxRuleConditions{
+			SubjectPattern: `^Question`,
+		},
+		Actions: store.InboxRuleActions{Star: true},
+	})
+	require.NoError(t, err)
+
+	result, err = svc.TestInboxRule(ctx, TestInboxRuleRequest{
+		AgentID: user.ID,
+		Name:    "question",
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Matches, 1)
+	require.Equal(t, resp.MessageID, result.Matches[0].ID)
+
+	_, err = svc.TestInboxRule(ctx, TestInboxRuleRequest{
+		AgentID: user.ID,
+		Name:    "missing",
+	})
+	require.ErrorIs(t, err, ErrInboxRuleNotFound)
+
+	_, err = svc.TestInboxRule(ctx, TestInboxRuleRequest{
+		AgentID: user.ID,
+	})
+	require.ErrorIs(t, err, ErrInvalidInboxRule)
+}
+
+// TestSnoozeUntil tests resolving inbox rule snoozes.
+func TestSnoozeUntil(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 10, 30, 0, 0, time.Local)
+
+	until, err := snoozeUntil("90m", now)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(90*time.Minute), until)
+
+	// A time of day later today wakes today, and one that has passed
+	// wakes tomorrow.
+	until, err = snoozeUntil("17:00", now)
+	require.NoError(t, err)
+	require.Equal(
+		t, time.Date(2025, 6, 1, 17, 0, 0, 0, time.Local), until,
+	)
+
+	until, err = snoozeUntil("09:00", now)
+	require.NoError(t, err)
+	require.Equal(
+		t, time.Date(2025, 6, 2, 9, 0, 0, 0, time.Local), until,
+	)
+
+	_, err = snoozeUntil("-1h", now)
+	require.ErrorIs(t, err, ErrInvalidInboxRule)
+
+	_, err = snoozeUntil("25:00", now)
+	require.ErrorIs(t, err, ErrInvalidInboxRule)
+}
+
+// TestRuleMatcher tests that a compiled rule matches on its subject pattern
+// along with its other conditions, and that a pattern that doesn't compile
+// matches nothing.
+func TestRuleMatcher(t *testing.T) {
+	t.Parallel()
+
+	matcher := newRuleMatcher(store.InboxRuleConditions{
+		SenderPrefix:   "CI",
+		SubjectPattern: `^build #\d+ failed`,
+	})
+	require.NotNil(t, matcher.subject)
+
+	require.True(t, matcher.matches(ruleMessage{
+		senderName: "CIBot",
+		subject:    "build #42 failed on main",
+	}))
+	require.False(t, matcher.matches(ruleMessage{
+		senderName: "CIBot",
+		subject:    "build #42 passed",
+	}))
+	require.False(t, matcher.matches(ruleMessage{
+		senderName: "Alice",
+		subject:    "build #42 failed on main",
+	}))
+
+	invalid := newRuleMatcher(store.InboxRuleConditions{
+		SubjectPattern: "(",
+	})
+	require.False(t, invalid.matches(ruleMessage{subject: "("}))
+}
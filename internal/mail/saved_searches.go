@@ -0,0 +1,109 @@
+package mail
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/search"
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+// SaveSearch saves a search query for an agent under a name, replacing the
+// query of one it already saved under that name. Names follow the rules of
+// label names. The query is checked the way Search would run it, so queries
+// that don't parse, or that name agents or topics that don't exist, fail
+// with an error wrapping search.ErrInvalidQuery.
+func (s *Service) SaveSearch(ctx context.Context, agentID int64, name,
+	query string,
+) (store.SavedSearch, error) {
+	if !labelNameRE.MatchString(name) {
+		return store.SavedSearch{}, fmt.Errorf("%w: name %q must be "+
+			"lowercase letters, digits, '.', '_', '/' or '-'",
+			ErrInvalidSavedSearch, name)
+	}
+
+	if _, err := s.store.GetAgent(ctx, agentID); err != nil {
+		return store.SavedSearch{}, fmt.Errorf("agent not found: %w",
+			err)
+	}
+
+	parsed, err := search.Parse(query, time.Now())
+	if err != nil {
+		return store.SavedSearch{}, err
+	}
+	_, err = s.searchParams(ctx, SearchRequest{
+		AgentID: agentID,
+		Query:   query,
+	}, parsed)
+	if err != nil {
+		return store.SavedSearch{}, err
+	}
+
+	saved, err := s.store.UpsertSavedSearch(
+		ctx, agentID, name, strings.TrimSpace(query),
+	)
+	if err != nil {
+		return store.SavedSearch{}, fmt.Errorf("failed to save "+
+			"search: %w", err)
+	}
+
+	return saved, nil
+}
+
+// ListSavedSearches returns the agent's saved searches by name.
+func (s *Service) ListSavedSearches(ctx context.Context,
+	agentID int64,
+) ([]store.SavedSearch, error) {
+	searches, err := s.store.ListSavedSearches(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved searches: %w",
+			err)
+	}
+
+	return searches, nil
+}
+
+// DeleteSavedSearch deletes one of the agent's saved searches.
+func (s *Service) DeleteSavedSearch(ctx context.Context, agentID int64,
+	name string,
+) error {
+	deleted, err := s.store.DeleteSavedSearch(ctx, agentID, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %s", ErrSavedSearchNotFound, name)
+	}
+
+	return nil
+}
+
+// savedSearchQuery returns the query an agent saved under a name, with the
+// extra terms and filters appended to narrow it.
+func (s *Service) savedSearchQuery(ctx context.Context, agentID int64, name,
+	extra string,
+) (string, error) {
+	if agentID == 0 {
+		return "", fmt.Errorf("%w: saved searches need an agent to "+
+			"search as", ErrInvalidSavedSearch)
+	}
+
+	saved, err := s.store.GetSavedSearch(ctx, agentID, name)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return "", fmt.Errorf("%w: %s", ErrSavedSearchNotFound, name)
+
+	case err != nil:
+		return "", fmt.Errorf("failed to get saved search: %w", err)
+	}
+
+	if strings.TrimSpace(extra) == "" {
+		return saved.Query, nil
+	}
+
+	return saved.Query + " " + extra, nil
+}
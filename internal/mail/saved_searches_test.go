@@ -0,0 +1,117 @@
+package mail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/roasbeef/subtrate/internal/search"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSavedSearch tests saving, running, narrowing and deleting an agent's
+// saved searches.
+func TestSavedSearch(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	user := createTestAgent(t, storage, "User")
+	reviewer := createTestAgent(t, storage, "Reviewer")
+	alice := createTestAgent(t, storage, "Alice")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	send := func(senderID int64, subject string,
+		priority Priority,
+	) int64 {
+		resp := svc.handleSendMail(ctx, SendMailRequest{
+			SenderID:       senderID,
+			RecipientNames: []string{"User"},
+			Subject:        subject,
+			Body:           "Notes for " + subject,
+			Priority:       priority,
+		})
+		require.NoError(t, resp.Error)
+
+		return resp.MessageID
+	}
+	flaky := send(reviewer.ID, "Flaky htlc test", PriorityUrgent)
+	nit := send(reviewer.ID, "Naming nit", PriorityNormal)
+	send(alice.ID, "Urgent lunch order", PriorityUrgent)
+
+	// Malformed names and queries, or queries naming unknown agents,
+	// aren't saved.
+	_, err := svc.SaveSearch(ctx, user.ID, "Triage", "is:unread")
+	require.ErrorIs(t, err, ErrInvalidSavedSearch)
+	_, err = svc.SaveSearch(ctx, user.ID, "triage", "from:Nobody")
+	require.ErrorIs(t, err, search.ErrInvalidQuery)
+	_, err = svc.SaveSearch(ctx, user.ID, "triage", "")
+	require.ErrorIs(t, err, search.ErrInvalidQuery)
+
+	saved, err := svc.SaveSearch(
+		ctx, user.ID, "triage", "from:Reviewer after:1d",
+	)
+	require.NoError(t, err)
+	require.Equal(t, "triage", saved.Name)
+
+	run := func(name, query string) []int64 {
+		t.Helper()
+
+		resp, err := svc.Search(ctx, SearchRequest{
+			AgentID:     user.ID,
+			SavedSearch: name,
+			Query:       query,
+		})
+		require.NoError(t, err)
+
+		ids := make([]int64, len(resp.Hits))
+		for i, hit := range resp.Hits {
+			ids[i] = hit.ID
+		}
+
+		return ids
+	}
+	require.ElementsMatch(t, []int64{flaky, nit}, run("triage", ""))
+
+	// Extra terms and filters narrow the saved query.
+	require.Equal(t, []int64{flaky}, run("triage", "priority:urgent"))
+	require.Equal(t, []int64{nit}, run("triage", "nit"))
+
+	// Saving under the same name replaces the query.
+	_, err = svc.SaveSearch(ctx, user.ID, "triage", "priority:urgent")
+	require.NoError(t, err)
+	require.Len(t, run("triage", ""), 2)
+
+	// A search restricted to a batch of messages reports which of them
+	// the saved query matches.
+	resp, err := svc.Search(ctx, SearchRequest{
+		AgentID:     user.ID,
+		SavedSearch: "triage",
+		MessageIDs:  []int64{flaky, nit},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Hits, 1)
+	require.Equal(t, flaky, resp.Hits[0].ID)
+
+	// Saved searches are per agent.
+	_, err = svc.Search(ctx, SearchRequest{
+		AgentID:     alice.ID,
+		SavedSearch: "triage",
+	})
+	require.ErrorIs(t, err, ErrSavedSearchNotFound)
+
+	_, err = svc.SaveSearch(ctx, user.ID, "reviewers", "from:Reviewer")
+	require.NoError(t, err)
+	searches, err := svc.ListSavedSearches(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, searches, 2)
+	require.Equal(t, "reviewers", searches[0].Name)
+	require.Equal(t, "priority:urgent", searches[1].Query)
+
+	require.NoError(t, svc.DeleteSavedSearch(ctx, user.ID, "reviewers"))
+	err = svc.DeleteSavedSearch(ctx, user.ID, "reviewers")
+	require.ErrorIs(t, err, ErrSavedSearchNotFound)
+}
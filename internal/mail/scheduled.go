@@ -0,0 +1,291 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/roasbeef/subtrate/internal/ratelimit"
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+const (
+	// DefaultScheduledSendInterval is how often the message scheduler
+	// delivers scheduled messages that have come due.
+	DefaultScheduledSendInterval = 15 * time.Second
+
+	// DefaultScheduledLimit is the number of scheduled messages returned
+	// when no limit is given.
+	DefaultScheduledLimit = 50
+
+	// scheduledSendBatchSize caps the number of scheduled messages
+	// delivered per tick.
+	scheduledSendBatchSize = 100
+)
+
+// scheduleSend stores a send request to be delivered at its send time. The
+// request's idempotency key, or a fresh one, is stored with it and reused
+// for the eventual send.
+func (s *Service) scheduleSend(ctx context.Context,
+	req SendMailRequest,
+) SendMailResponse {
+	var response SendMailResponse
+
+	if len(req.RecipientNames) == 0 && req.TopicName == "" {
+		response.Error = fmt.Errorf("no recipients or topic specified")
+		return response
+	}
+
+	// Scheduling the same request twice returns the original schedule.
+	if req.IdempotencyKey != "" {
+		existing, err := s.store.GetScheduledMessageByIdempotencyKey(
+			ctx, req.IdempotencyKey,
+		)
+		if err == nil {
+			return SendMailResponse{
+				ThreadID:    existing.ThreadID,
+				ScheduledID: existing.ID,
+			}
+		}
+	}
+
+	if _, err := s.store.GetAgent(ctx, req.SenderID); err != nil {
+		response.Error = fmt.Errorf("sender not found: %w", err)
+		return response
+	}
+
+	key := req.IdempotencyKey
+	if key == "" {
+		key = uuid.New().String()
+	}
+
+	// The thread is fixed now so the sender can refer to it before the
+	// message goes out.
+	threadID := req.ThreadID
+	if threadID == "" {
+		threadID = uuid.New().String()
+	}
+
+	deliveryMode := req.DeliveryMode
+	if deliveryMode == "" {
+		deliveryMode = DeliveryModeAtomic
+	}
+
+	metadata, err := encodeMetadata(req.Metadata)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+
+	sched, err := s.store.CreateScheduledMessage(
+		ctx, store.CreateScheduledMessageParams{
+			SenderID:       req.SenderID,
+			RecipientNames: req.RecipientNames,
+			TopicName:      req.TopicName,
+			ThreadID:       threadID,
+			Subject:        req.Subject,
+			Body:           req.Body,
+			Priority:       string(req.Priority),
+			DeadlineAt:     req.Deadline,
+			Attachments:    req.Attachments,
+			Metadata:       metadata,
+			DeliveryMode:   string(deliveryMode),
+			IdempotencyKey: key,
+			SendAt:         *req.SendAt,
+		},
+	)
+	if err != nil {
+		response.Error = fmt.Errorf("failed to schedule message: %w",
+			err)
+		return response
+	}
+
+	response.ThreadID = sched.ThreadID
+	response.ScheduledID = sched.ID
+
+	return response
+}
+
+// ListScheduledMessages returns the sender's scheduled messages that have
+// not been sent or canceled, soonest first. Messages whose delivery failed
+// are included along with the error.
+func (s *Service) ListScheduledMessages(ctx context.Context, senderID int64,
+	limit int,
+) ([]store.ScheduledMessage, error) {
+	if limit <= 0 {
+		limit = DefaultScheduledLimit
+	}
+
+	msgs, err := s.store.ListScheduledMessages(ctx, senderID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled messages: %w",
+			err)
+	}
+
+	return msgs, nil
+}
+
+// CancelScheduledMessage cancels one of the sender's scheduled messages
+// before it is sent.
+func (s *Service) CancelScheduledMessage(ctx context.Context, senderID,
+	id int64,
+) error {
+	canceled, err := s.store.CancelScheduledMessage(ctx, id, senderID)
+	if err != nil {
+		return fmt.Errorf("failed to cancel scheduled message: %w", err)
+	}
+	if !canceled {
+		return fmt.Errorf("%w: %d", ErrScheduledMessageNotFound, id)
+	}
+
+	return nil
+}
+
+// DeliverScheduledMessages sends every pending scheduled message whose send
+// time is at or before now, and returns the number sent. Each message is sent
+// with the idempotency key it was scheduled with, so a message that was sent
+// but not yet marked as such when the daemon stopped is recognised instead of
+// delivered twice. A message refused by the sender's rate limit or daily
+// quota stays pending and is pushed back until the limit allows it. Messages
+// that fail to send for any other reason are marked as failed and are not
+// retried.
+func (s *Service) DeliverScheduledMessages(ctx context.Context,
+	now time.Time,
+) (int, error) {
+	due, err := s.store.ListDueScheduledMessages(
+		ctx, now, scheduledSendBatchSize,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due scheduled "+
+			"messages: %w", err)
+	}
+
+	var numSent int
+	for _, sched := range due {
+		resp := s.handleSendMail(ctx, scheduledSendRequest(sched))
+		if resp.Error != nil {
+			// A send cut short by shutdown is retried on the
+			// next start rather than marked as failed.
+			if ctx.Err() != nil {
+				return numSent, ctx.Err()
+			}
+
+			// A send refused by the sender's rate limit or quota
+			// stays pending until the limit allows it.
+			retryAfter, limited := ratelimit.RetryAfter(resp.Error)
+			if limited {
+				err := s.store.DeferScheduledMessage(
+					ctx, sched.ID, now.Add(retryAfter),
+					resp.Error.Error(),
+				)
+				if err != nil {
+					return numSent, fmt.Errorf("failed to "+
+						"defer scheduled message "+
+						"%d: %w", sched.ID, err)
+				}
+				continue
+			}
+
+			err := s.store.MarkScheduledMessageFailed(
+				ctx, sched.ID, resp.Error.Error(),
+			)
+			if err != nil {
+				return numSent, fmt.Errorf("failed to mark "+
+					"scheduled message %d as failed: %w",
+					sched.ID, err)
+			}
+			continue
+		}
+
+		err := s.store.MarkScheduledMessageSent(
+			ctx, sched.ID, resp.MessageID,
+		)
+		if err != nil {
+			return numSent, fmt.Errorf("failed to mark scheduled "+
+				"message %d as sent: %w", sched.ID, err)
+		}
+		numSent++
+	}
+
+	return numSent, nil
+}
+
+// RunMessageScheduler delivers due scheduled messages, runs due recurring
+// schedules and handles timed out questions immediately and then on every
+// interval until the context is cancelled.
+func (s *Service) RunMessageScheduler(ctx context.Context,
+	interval time.Duration, log *slog.Logger,
+) {
+	if interval <= 0 {
+		interval = DefaultScheduledSendInterval
+	}
+	if log == nil {
+		log = slog.Default()
+	}
+	log = log.With("component", "message-scheduler")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		numSent, err := s.DeliverScheduledMessages(ctx, time.Now())
+		switch {
+		case err != nil && !errors.Is(err, context.Canceled):
+			log.ErrorContext(ctx, "Failed to deliver scheduled "+
+				"messages", "error", err)
+
+		case numSent > 0:
+			log.InfoContext(ctx, "Delivered scheduled messages",
+				"count", numSent)
+		}
+
+		numRuns, err := s.RunDueSchedules(ctx, time.Now())
+		switch {
+		case err != nil && !errors.Is(err, context.Canceled):
+			log.ErrorContext(ctx, "Failed to run recurring "+
+				"schedules", "error", err)
+
+		case numRuns > 0:
+			log.InfoContext(ctx, "Ran recurring schedules",
+				"count", numRuns)
+		}
+
+		numTimedOut, err := s.RunQuestionTimeouts(ctx, time.Now())
+		switch {
+		case err != nil && !errors.Is(err, context.Canceled):
+			log.ErrorContext(ctx, "Failed to handle question "+
+				"timeouts", "error", err)
+
+		case numTimedOut > 0:
+			log.InfoContext(ctx, "Handled question timeouts",
+				"count", numTimedOut)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scheduledSendRequest rebuilds the send request for a scheduled message.
+func scheduledSendRequest(sched store.ScheduledMessage) SendMailRequest {
+	return SendMailRequest{
+		SenderID:       sched.SenderID,
+		RecipientNames: sched.RecipientNames,
+		TopicName:      sched.TopicName,
+		Subject:        sched.Subject,
+		Body:           sched.Body,
+		Priority:       Priority(sched.Priority),
+		Deadline:       sched.DeadlineAt,
+		ThreadID:       sched.ThreadID,
+		Attachments:    sched.Attachments,
+		IdempotencyKey: sched.IdempotencyKey,
+		DeliveryMode:   DeliveryMode(sched.DeliveryMode),
+		Metadata:       decodeMetadata(sched.Metadata),
+	}
+}
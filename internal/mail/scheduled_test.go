@@ -0,0 +1,216 @@
+package mail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/ratelimit"
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScheduledSend tests that a send with a future send time is held until
+// the scheduler delivers it, that scheduled messages can be listed and
+// canceled by their sender, and that a failed send is reported.
+func TestScheduledSend(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	alice := createTestAgent(t, storage, "Alice")
+	all := []store.Agent{sender, alice}
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	schedule := func(subject string, sendAt time.Time,
+		recipients ...string) SendMailResponse {
+
+		resp, err := svc.Send(ctx, SendMailRequest{
+			SenderID:       sender.ID,
+			RecipientNames: recipients,
+			Subject:        subject,
+			Body:           "Body",
+			Priority:       PriorityNormal,
+			SendAt:         &sendAt,
+		})
+		require.NoError(t, err)
+
+		return resp
+	}
+
+	now := time.Now()
+	soon := schedule("Soon", now.Add(time.Hour), "Alice")
+	later := schedule("Later", now.Add(2*time.Hour), "Alice")
+	ghost := schedule("Ghost", now.Add(time.Hour), "Ghost")
+	require.NotZero(t, soon.ScheduledID)
+	require.Zero(t, soon.MessageID)
+	require.NotEmpty(t, soon.ThreadID)
+
+	// A send time in the past sends right away.
+	resp := schedule("Now", now.Add(-time.Minute), "Alice")
+	require.Zero(t, resp.ScheduledID)
+	require.NotZero(t, resp.MessageID)
+
+	pending, err := svc.ListScheduledMessages(ctx, sender.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	require.Equal(t, "Soon", pending[0].Subject)
+	require.Equal(t, []string{"Alice"}, pending[0].RecipientNames)
+	require.Equal(t, "Later", pending[2].Subject)
+	soonKey := pending[0].IdempotencyKey
+
+	others, err := svc.ListScheduledMessages(ctx, alice.ID, 0)
+	require.NoError(t, err)
+	require.Empty(t, others)
+
+	// Only the sender can cancel a scheduled message.
+	err = svc.CancelScheduledMessage(ctx, alice.ID, later.ScheduledID)
+	require.ErrorIs(t, err, ErrScheduledMessageNotFound)
+	require.NoError(t, svc.CancelScheduledMessage(
+		ctx, sender.ID, later.ScheduledID,
+	))
+
+	// Nothing is due yet.
+	numSent, err := svc.DeliverScheduledMessages(ctx, now)
+	require.NoError(t, err)
+	require.Zero(t, numSent)
+
+	// Once due, the message is delivered on the thread it was given when
+	// it was scheduled, and the unknown recipient is marked as failed.
+	numSent, err = svc.DeliverScheduledMessages(
+		ctx, now.Add(3*time.Hour),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, numSent)
+
+	pending, err = svc.ListScheduledMessages(ctx, sender.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, ghost.ScheduledID, pending[0].ID)
+	require.Equal(t, "failed", pending[0].State)
+	require.Contains(t, pending[0].LastError, "Ghost")
+
+	sent, err := storage.GetScheduledMessageByIdempotencyKey(ctx, soonKey)
+	require.NoError(t, err)
+	require.Equal(t, "sent", sent.State)
+
+	msg, err := storage.GetMessage(ctx, sent.MessageID)
+	require.NoError(t, err)
+	require.Equal(t, soon.ThreadID, msg.ThreadID)
+	require.Equal(
+		t, []string{"Alice"},
+		inboxRecipients(t, storage, all, sent.MessageID),
+	)
+
+	// A sent message can no longer be canceled.
+	err = svc.CancelScheduledMessage(ctx, sender.ID, soon.ScheduledID)
+	require.ErrorIs(t, err, ErrScheduledMessageNotFound)
+}
+
+// TestScheduledSendRestart tests that a scheduled message that was sent but
+// not marked as sent before a restart is not delivered a second time.
+func TestScheduledSendRestart(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	alice := createTestAgent(t, storage, "Alice")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	sendAt := time.Now().Add(time.Hour)
+	resp, err := svc.Send(ctx, SendMailRequest{
+		SenderID:       sender.ID,
+		RecipientNames: []string{"Alice"},
+		Subject:        "Reminder",
+		Body:           "Body",
+		Priority:       PriorityNormal,
+		SendAt:         &sendAt,
+	})
+	require.NoError(t, err)
+
+	// Simulate a scheduler that sent the message and then stopped before
+	// recording it.
+	due, err := storage.ListDueScheduledMessages(
+		ctx, sendAt, scheduledSendBatchSize,
+	)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+
+	first := svc.handleSendMail(ctx, scheduledSendRequest(due[0]))
+	require.NoError(t, first.Error)
+
+	numSent, err := svc.DeliverScheduledMessages(ctx, sendAt)
+	require.NoError(t, err)
+	require.Equal(t, 1, numSent)
+
+	sched, err := storage.GetScheduledMessageByIdempotencyKey(
+		ctx, due[0].IdempotencyKey,
+	)
+	require.NoError(t, err)
+	require.Equal(t, resp.ScheduledID, sched.ID)
+	require.Equal(t, first.MessageID, sched.MessageID)
+
+	inbox, err := storage.GetInboxMessages(ctx, alice.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, inbox, 1)
+}
+
+// TestScheduledSendQuotaDeferred tests that a scheduled message refused by
+// the sender's daily quota stays pending and is pushed back until the quota
+// resets, instead of failing.
+func TestScheduledSendQuotaDeferred(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	createTestAgent(t, storage, "Alice")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	_, err := ratelimit.NewLimiter(storage).Set(
+		ctx, sender.ID, ratelimit.ClassBodyBytes, 1, 0, 0,
+	)
+	require.NoError(t, err)
+
+	sendAt := time.Now().Add(time.Hour)
+	resp, err := svc.Send(ctx, SendMailRequest{
+		SenderID:       sender.ID,
+		RecipientNames: []string{"Alice"},
+		Subject:        "Reminder",
+		Body:           "Body",
+		Priority:       PriorityNormal,
+		SendAt:         &sendAt,
+	})
+	require.NoError(t, err)
+
+	numSent, err := svc.DeliverScheduledMessages(ctx, sendAt)
+	require.NoError(t, err)
+	require.Zero(t, numSent)
+
+	pending, err := svc.ListScheduledMessages(ctx, sender.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, resp.ScheduledID, pending[0].ID)
+	require.Equal(t, "pending", pending[0].State)
+	require.Contains(t, pending[0].LastError, "quota")
+	require.True(t, pending[0].SendAt.After(sendAt))
+
+	// It isn't retried until its new send time.
+	numSent, err = svc.DeliverScheduledMessages(ctx, sendAt)
+	require.NoError(t, err)
+	require.Zero(t, numSent)
+}
@@ -0,0 +1,485 @@
+package mail
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+const (
+	// ActivityTypeScheduleRun is the activity type recorded each time a
+	// recurring schedule runs.
+	ActivityTypeScheduleRun = "schedule_run"
+
+	// DefaultScheduleTimezone is the time zone used when a schedule
+	// doesn't name one: the local time zone of the daemon.
+	DefaultScheduleTimezone = "Local"
+
+	// scheduleSkipGrace is how late a run may be before a schedule with
+	// the skip catch-up policy treats it as missed. It absorbs the delay
+	// between an occurrence and the scheduler tick that notices it.
+	scheduleSkipGrace = 5 * time.Minute
+
+	// scheduleRunBatchSize caps the number of schedules run per tick.
+	scheduleRunBatchSize = 100
+)
+
+// CatchUpPolicy decides what a recurring schedule does with occurrences
+// that were missed while the daemon was down.
+type CatchUpPolicy string
+
+const (
+	// CatchUpOnce sends a single message for all missed occurrences, as
+	// soon as the daemon is back.
+	CatchUpOnce CatchUpPolicy = "once"
+
+	// CatchUpSkip drops missed occurrences and waits for the next one.
+	CatchUpSkip CatchUpPolicy = "skip"
+)
+
+// CreateScheduleRequest describes a recurring message.
+type CreateScheduleRequest struct {
+	// Name identifies the schedule among its owner's schedules.
+	Name string
+
+	// SenderID is the agent that owns the schedule and sends its
+	// messages.
+	SenderID int64
+
+	// RecipientNames are the agents, groups or aliases to send to. They
+	// are resolved at each run. Exactly one of RecipientNames and
+	// TopicName must be set.
+	RecipientNames []string
+
+	// TopicName is the topic to publish to.
+	TopicName string
+
+	// Subject and Body are the content of each message.
+	Subject string
+	Body    string
+
+	// Priority is the priority of each message, normal by default.
+	Priority Priority
+
+	// CronExpr is a five-field cron expression or macro, see
+	// ParseCronSpec.
+	CronExpr string
+
+	// Timezone is the IANA time zone the cron expression is evaluated
+	// in, the daemon's local time zone by default.
+	Timezone string
+
+	// CatchUp is the policy for missed occurrences, CatchUpOnce by
+	// default.
+	CatchUp CatchUpPolicy
+}
+
+// CreateSchedule validates and stores a recurring schedule, returning it
+// with its first run time.
+func (s *Service) CreateSchedule(ctx context.Context,
+	req CreateScheduleRequest,
+) (store.Schedule, error) {
+	if !groupNameRE.MatchString(req.Name) {
+		return store.Schedule{}, fmt.Errorf("%w: name %q must be "+
+			"lowercase letters, digits, '.', '_' or '-'",
+			ErrInvalidSchedule, req.Name)
+	}
+
+	hasRecipients := len(req.RecipientNames) > 0
+	hasTopic := req.TopicName != ""
+	if hasRecipients == hasTopic {
+		return store.Schedule{}, fmt.Errorf("%w: exactly one of "+
+			"recipients and topic is required", ErrInvalidSchedule)
+	}
+
+	if req.Subject == "" {
+		return store.Schedule{}, fmt.Errorf("%w: subject is required",
+			ErrInvalidSchedule)
+	}
+
+	if req.Priority == "" {
+		req.Priority = PriorityNormal
+	}
+	if req.Timezone == "" {
+		req.Timezone = DefaultScheduleTimezone
+	}
+
+	switch req.CatchUp {
+	case "":
+		req.CatchUp = CatchUpOnce
+	case CatchUpOnce, CatchUpSkip:
+	default:
+		return store.Schedule{}, fmt.Errorf("%w: unknown catch-up "+
+			"policy %q", ErrInvalidSchedule, req.CatchUp)
+	}
+
+	spec, loc, err := parseSchedule(req.CronExpr, req.Timezone)
+	if err != nil {
+		return store.Schedule{}, err
+	}
+
+	nextRun := spec.Next(time.Now().In(loc))
+	if nextRun.IsZero() {
+		return store.Schedule{}, fmt.Errorf("%w: %q never fires",
+			ErrInvalidSchedule, req.CronExpr)
+	}
+
+	if _, err := s.store.GetAgent(ctx, req.SenderID); err != nil {
+		return store.Schedule{}, fmt.Errorf("sender not found: %w", err)
+	}
+
+	_, err = s.store.GetScheduleByName(ctx, req.SenderID, req.Name)
+	switch {
+	case err == nil:
+		return store.Schedule{}, fmt.Errorf("%w: %s", ErrScheduleExists,
+			req.Name)
+
+	case !errors.Is(err, sql.ErrNoRows):
+		return store.Schedule{}, fmt.Errorf("failed to get schedule: "+
+			"%w", err)
+	}
+
+	sched, err := s.store.CreateSchedule(ctx, store.CreateScheduleParams{
+		Name:           req.Name,
+		SenderID:       req.SenderID,
+		RecipientNames: req.RecipientNames,
+		TopicName:      req.TopicName,
+		Subject:        req.Subject,
+		Body:           req.Body,
+		Priority:       string(req.Priority),
+		CronExpr:       req.CronExpr,
+		Timezone:       req.Timezone,
+		CatchUp:        string(req.CatchUp),
+		NextRunAt:      nextRun,
+	})
+	if err != nil {
+		return store.Schedule{}, fmt.Errorf("failed to create "+
+			"schedule: %w", err)
+	}
+
+	return sched, nil
+}
+
+// ListSchedules returns the agent's schedules by name.
+func (s *Service) ListSchedules(ctx context.Context,
+	senderID int64,
+) ([]store.Schedule, error) {
+	schedules, err := s.store.ListSchedules(ctx, senderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// DeleteSchedule deletes one of the agent's schedules. Messages it already
+// sent are kept.
+func (s *Service) DeleteSchedule(ctx context.Context, senderID int64,
+	name string,
+) error {
+	deleted, err := s.store.DeleteSchedule(ctx, senderID, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete schedule: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
+	}
+
+	return nil
+}
+
+// PauseSchedule stops one of the agent's schedules from running until it is
+// resumed.
+func (s *Service) PauseSchedule(ctx context.Context, senderID int64,
+	name string,
+) (store.Schedule, error) {
+	sched, err := s.getSchedule(ctx, senderID, name)
+	if err != nil {
+		return store.Schedule{}, err
+	}
+
+	return s.setSchedulePaused(ctx, sched, true, sched.NextRunAt)
+}
+
+// ResumeSchedule resumes one of the agent's paused schedules. Occurrences
+// that fell while it was paused are not caught up; it next runs at its
+// first occurrence from now.
+func (s *Service) ResumeSchedule(ctx context.Context, senderID int64,
+	name string,
+) (store.Schedule, error) {
+	sched, err := s.getSchedule(ctx, senderID, name)
+	if err != nil {
+		return store.Schedule{}, err
+	}
+
+	spec, loc, err := parseSchedule(sched.CronExpr, sched.Timezone)
+	if err != nil {
+		return store.Schedule{}, err
+	}
+
+	nextRun := spec.Next(time.Now().In(loc))
+	if nextRun.IsZero() {
+		return store.Schedule{}, fmt.Errorf("%w: %q never fires",
+			ErrInvalidSchedule, sched.CronExpr)
+	}
+
+	return s.setSchedulePaused(ctx, sched, false, nextRun)
+}
+
+// RunDueSchedules runs every schedule whose next occurrence is at or before
+// now, and returns the number of messages sent.
+//
+// If more than one occurrence has passed since a schedule last ran, the
+// daemon was down or the schedule was starved, and its catch-up policy
+// applies: CatchUpOnce sends a single message for the latest missed
+// occurrence, while CatchUpSkip sends nothing unless that occurrence is
+// within a short grace period. Each message is sent with an idempotency key
+// derived from the schedule and occurrence, so a run that was sent but not
+// recorded before the daemon stopped is not sent twice.
+func (s *Service) RunDueSchedules(ctx context.Context,
+	now time.Time,
+) (int, error) {
+	due, err := s.store.ListDueSchedules(ctx, now, scheduleRunBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due schedules: %w", err)
+	}
+
+	var (
+		numSent int
+		errs    []error
+	)
+	for _, sched := range due {
+		sent, err := s.runSchedule(ctx, sched, now)
+		if ctx.Err() != nil {
+			return numSent, ctx.Err()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("schedule %d (%s): %w",
+				sched.ID, sched.Name, err))
+			continue
+		}
+		if sent {
+			numSent++
+		}
+	}
+
+	return numSent, errors.Join(errs...)
+}
+
+// runSchedule handles a due schedule, returning whether it sent a message.
+// A failed send is recorded on the schedule rather than returned, since the
+// schedule moves on to its next occurrence either way.
+func (s *Service) runSchedule(ctx context.Context, sched store.Schedule,
+	now time.Time,
+) (bool, error) {
+	spec, loc, err := parseSchedule(sched.CronExpr, sched.Timezone)
+	if err != nil {
+		// The schedule was valid when created, so this can only
+		// happen if the time zone database changed. Pause it rather
+		// than retrying it on every tick.
+		_, pauseErr := s.setSchedulePaused(
+			ctx, sched, true, sched.NextRunAt,
+		)
+		return false, errors.Join(err, pauseErr)
+	}
+
+	// Find the latest occurrence that has passed. NextRunAt is the
+	// earliest one that hasn't been handled yet.
+	occurrence := sched.NextRunAt
+	missed := 0
+	for {
+		next := spec.Next(occurrence.In(loc))
+		if next.IsZero() || next.After(now) {
+			break
+		}
+		occurrence = next
+		missed++
+	}
+
+	nextRun := spec.Next(now.In(loc))
+	if nextRun.IsZero() {
+		// The expression has no further occurrences, so park the
+		// schedule after this run.
+		_, err := s.setSchedulePaused(ctx, sched, true, now)
+		if err != nil {
+			return false, err
+		}
+		nextRun = now
+	}
+
+	late := now.Sub(occurrence) > scheduleSkipGrace
+	if CatchUpPolicy(sched.CatchUp) == CatchUpSkip && late {
+		return false, s.store.AdvanceSchedule(ctx, sched.ID, nextRun)
+	}
+
+	messageID, sendErr := s.sendScheduleRun(ctx, sched, occurrence)
+	if sendErr != nil && ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	run := store.RecordScheduleRunParams{
+		ID:        sched.ID,
+		RunAt:     now,
+		NextRunAt: nextRun,
+		MessageID: messageID,
+	}
+	description := fmt.Sprintf("Schedule %q sent %q to %s", sched.Name,
+		sched.Subject, scheduleTarget(sched))
+	if sendErr != nil {
+		run.LastError = sendErr.Error()
+		description = fmt.Sprintf("Schedule %q failed to send %q to "+
+			"%s: %v", sched.Name, sched.Subject,
+			scheduleTarget(sched), sendErr)
+	}
+
+	metadata, err := json.Marshal(map[string]any{
+		"schedule_id": sched.ID,
+		"schedule":    sched.Name,
+		"message_id":  messageID,
+		"occurrence":  occurrence.Unix(),
+		"missed":      missed,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	err = s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		if err := txStore.RecordScheduleRun(ctx, run); err != nil {
+			return fmt.Errorf("failed to record run: %w", err)
+		}
+
+		return txStore.CreateActivity(ctx, store.CreateActivityParams{
+			AgentID:      sched.SenderID,
+			ActivityType: ActivityTypeScheduleRun,
+			Description:  description,
+			Metadata:     string(metadata),
+		})
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return sendErr == nil, nil
+}
+
+// sendScheduleRun sends a schedule's message for one occurrence and returns
+// the message ID.
+func (s *Service) sendScheduleRun(ctx context.Context, sched store.Schedule,
+	occurrence time.Time,
+) (int64, error) {
+	key := fmt.Sprintf("schedule-%d-%d", sched.ID, occurrence.Unix())
+
+	if sched.TopicName != "" {
+		resp := s.handlePublish(ctx, PublishRequest{
+			SenderID:       sched.SenderID,
+			TopicName:      sched.TopicName,
+			Subject:        sched.Subject,
+			Body:           sched.Body,
+			Priority:       Priority(sched.Priority),
+			IdempotencyKey: key,
+		})
+		return resp.MessageID, resp.Error
+	}
+
+	resp := s.handleSendMail(ctx, SendMailRequest{
+		SenderID:       sched.SenderID,
+		RecipientNames: sched.RecipientNames,
+		Subject:        sched.Subject,
+		Body:           sched.Body,
+		Priority:       Priority(sched.Priority),
+		IdempotencyKey: key,
+	})
+	return resp.MessageID, resp.Error
+}
+
+// getSchedule looks up one of the agent's schedules by name.
+func (s *Service) getSchedule(ctx context.Context, senderID int64,
+	name string,
+) (store.Schedule, error) {
+	sched, err := s.store.GetScheduleByName(ctx, senderID, name)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return store.Schedule{}, fmt.Errorf("%w: %s",
+			ErrScheduleNotFound, name)
+
+	case err != nil:
+		return store.Schedule{}, fmt.Errorf("failed to get schedule: "+
+			"%w", err)
+	}
+
+	return sched, nil
+}
+
+// setSchedulePaused updates a schedule's paused flag and next run and
+// returns the updated schedule.
+func (s *Service) setSchedulePaused(ctx context.Context,
+	sched store.Schedule, paused bool, nextRun time.Time,
+) (store.Schedule, error) {
+	updated, err := s.store.SetSchedulePaused(
+		ctx, sched.SenderID, sched.Name, paused, nextRun,
+	)
+	if err != nil {
+		return store.Schedule{}, fmt.Errorf("failed to update "+
+			"schedule: %w", err)
+	}
+	if !updated {
+		return store.Schedule{}, fmt.Errorf("%w: %s",
+			ErrScheduleNotFound, sched.Name)
+	}
+
+	sched.Paused = paused
+	sched.NextRunAt = time.Unix(nextRun.Unix(), 0)
+
+	return sched, nil
+}
+
+// parseSchedule parses a schedule's cron expression and time zone.
+func parseSchedule(cronExpr, timezone string) (CronSpec, *time.Location,
+	error) {
+
+	spec, err := ParseCronSpec(cronExpr)
+	if err != nil {
+		return CronSpec{}, nil, fmt.Errorf("%w: %v", ErrInvalidSchedule,
+			err)
+	}
+
+	loc, err := LoadScheduleLocation(timezone)
+	if err != nil {
+		return CronSpec{}, nil, err
+	}
+
+	return spec, loc, nil
+}
+
+// LoadScheduleLocation returns the time zone a schedule is evaluated in. An
+// empty name or "Local" is the daemon's local time zone.
+func LoadScheduleLocation(name string) (*time.Location, error) {
+	if name == "" || name == DefaultScheduleTimezone {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown time zone %q",
+			ErrInvalidSchedule, name)
+	}
+
+	return loc, nil
+}
+
+// scheduleTarget describes where a schedule sends its messages.
+func scheduleTarget(sched store.Schedule) string {
+	if sched.TopicName != "" {
+		return "topic " + sched.TopicName
+	}
+
+	return strings.Join(sched.RecipientNames, ", ")
+}
@@ -0,0 +1,238 @@
+package mail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSchedules tests that recurring schedules are validated on creation and
+// can be listed, paused, resumed and deleted by their owner.
+func TestSchedules(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	owner := createTestAgent(t, storage, "Owner")
+	other := createTestAgent(t, storage, "Other")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	valid := CreateScheduleRequest{
+		Name:           "sweep",
+		SenderID:       owner.ID,
+		RecipientNames: []string{"Other"},
+		Subject:        "Run the flaky test sweep",
+		CronExpr:       "0 9 * * mon-fri",
+		Timezone:       "America/New_York",
+	}
+
+	invalid := []func(*CreateScheduleRequest){
+		func(r *CreateScheduleRequest) { r.Name = "Flaky Sweep" },
+		func(r *CreateScheduleRequest) { r.RecipientNames = nil },
+		func(r *CreateScheduleRequest) { r.TopicName = "builds" },
+		func(r *CreateScheduleRequest) { r.Subject = "" },
+		func(r *CreateScheduleRequest) { r.CronExpr = "0 9 * *" },
+		func(r *CreateScheduleRequest) { r.CronExpr = "0 0 31 feb *" },
+		func(r *CreateScheduleRequest) { r.Timezone = "Mars/Olympus" },
+		func(r *CreateScheduleRequest) { r.CatchUp = "all" },
+	}
+	for i, mutate := range invalid {
+		req := valid
+		mutate(&req)
+		_, err := svc.CreateSchedule(ctx, req)
+		require.ErrorIs(t, err, ErrInvalidSchedule, "case %d", i)
+	}
+
+	sched, err := svc.CreateSchedule(ctx, valid)
+	require.NoError(t, err)
+	require.Equal(t, "normal", sched.Priority)
+	require.Equal(t, string(CatchUpOnce), sched.CatchUp)
+
+	// The first run is the next weekday at nine in New York.
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	firstRun := sched.NextRunAt.In(newYork)
+	require.True(t, firstRun.After(time.Now()))
+	require.Equal(t, 9, firstRun.Hour())
+	require.Zero(t, firstRun.Minute())
+	require.NotEqual(t, time.Saturday, firstRun.Weekday())
+	require.NotEqual(t, time.Sunday, firstRun.Weekday())
+
+	_, err = svc.CreateSchedule(ctx, valid)
+	require.ErrorIs(t, err, ErrScheduleExists)
+
+	// Schedule names are per owner.
+	valid.SenderID = other.ID
+	valid.RecipientNames = []string{"Owner"}
+	_, err = svc.CreateSchedule(ctx, valid)
+	require.NoError(t, err)
+
+	schedules, err := svc.ListSchedules(ctx, owner.ID)
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	require.Equal(t, []string{"Other"}, schedules[0].RecipientNames)
+
+	paused, err := svc.PauseSchedule(ctx, owner.ID, "sweep")
+	require.NoError(t, err)
+	require.True(t, paused.Paused)
+
+	// A paused schedule is never due, so only the other agent's schedule
+	// runs.
+	numSent, err := svc.RunDueSchedules(ctx, firstRun.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	require.Equal(t, 1, numSent)
+
+	inbox, err := storage.GetInboxMessages(ctx, other.ID, 10, 0)
+	require.NoError(t, err)
+	require.Empty(t, inbox)
+
+	resumed, err := svc.ResumeSchedule(ctx, owner.ID, "sweep")
+	require.NoError(t, err)
+	require.False(t, resumed.Paused)
+	require.Equal(t, sched.NextRunAt.Unix(), resumed.NextRunAt.Unix())
+
+	_, err = svc.PauseSchedule(ctx, owner.ID, "nightly")
+	require.ErrorIs(t, err, ErrScheduleNotFound)
+
+	require.NoError(t, svc.DeleteSchedule(ctx, owner.ID, "sweep"))
+	err = svc.DeleteSchedule(ctx, owner.ID, "sweep")
+	require.ErrorIs(t, err, ErrScheduleNotFound)
+
+	schedules, err = svc.ListSchedules(ctx, other.ID)
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+}
+
+// TestRunDueSchedules tests that a schedule sends on each occurrence, sends
+// once or not at all after downtime depending on its catch-up policy, records
+// failures, and shows up in the activity feed.
+func TestRunDueSchedules(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	owner := createTestAgent(t, storage, "Owner")
+	worker := createTestAgent(t, storage, "SilverWolf")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	create := func(name string, catchUp CatchUpPolicy,
+		recipients ...string) time.Time {
+
+		sched, err := svc.CreateSchedule(ctx, CreateScheduleRequest{
+			Name:           name,
+			SenderID:       owner.ID,
+			RecipientNames: recipients,
+			Subject:        "Run the flaky test sweep",
+			Body:           "Report anything that fails twice.",
+			CronExpr:       "0 * * * *",
+			CatchUp:        catchUp,
+		})
+		require.NoError(t, err)
+
+		return sched.NextRunAt
+	}
+
+	firstRun := create("sweep", CatchUpOnce, "SilverWolf")
+
+	inboxLen := func() int {
+		inbox, err := storage.GetInboxMessages(ctx, worker.ID, 50, 0)
+		require.NoError(t, err)
+		return len(inbox)
+	}
+
+	// Nothing is due before the first occurrence.
+	numSent, err := svc.RunDueSchedules(ctx, firstRun.Add(-time.Second))
+	require.NoError(t, err)
+	require.Zero(t, numSent)
+
+	// A run that was sent but not recorded before a restart is not sent
+	// again.
+	sched, err := storage.GetScheduleByName(ctx, owner.ID, "sweep")
+	require.NoError(t, err)
+	_, err = svc.sendScheduleRun(ctx, sched, firstRun)
+	require.NoError(t, err)
+
+	numSent, err = svc.RunDueSchedules(ctx, firstRun)
+	require.NoError(t, err)
+	require.Equal(t, 1, numSent)
+	require.Equal(t, 1, inboxLen())
+
+	sched, err = storage.GetScheduleByName(ctx, owner.ID, "sweep")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), sched.RunCount)
+	require.NotZero(t, sched.LastMessageID)
+	require.Equal(t, firstRun.Add(time.Hour).Unix(), sched.NextRunAt.Unix())
+
+	// After a day of downtime, the missed runs collapse into one.
+	numSent, err = svc.RunDueSchedules(ctx, firstRun.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, numSent)
+	require.Equal(t, 2, inboxLen())
+
+	sched, err = storage.GetScheduleByName(ctx, owner.ID, "sweep")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), sched.RunCount)
+	require.Equal(t, firstRun.Add(25*time.Hour).Unix(),
+		sched.NextRunAt.Unix())
+
+	// A skipping schedule runs when on time but drops missed runs.
+	require.NoError(t, svc.DeleteSchedule(ctx, owner.ID, "sweep"))
+	create("sweep", CatchUpSkip, "SilverWolf")
+
+	numSent, err = svc.RunDueSchedules(ctx, firstRun.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 1, numSent)
+	require.Equal(t, 3, inboxLen())
+
+	numSent, err = svc.RunDueSchedules(ctx, firstRun.Add(24*time.Hour+
+		10*time.Minute))
+	require.NoError(t, err)
+	require.Zero(t, numSent)
+	require.Equal(t, 3, inboxLen())
+
+	sched, err = storage.GetScheduleByName(ctx, owner.ID, "sweep")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), sched.RunCount)
+	require.Equal(t, firstRun.Add(25*time.Hour).Unix(),
+		sched.NextRunAt.Unix())
+
+	// A run that can't be delivered is recorded on the schedule and the
+	// schedule moves on.
+	require.NoError(t, svc.DeleteSchedule(ctx, owner.ID, "sweep"))
+	create("ghost", CatchUpOnce, "Ghost")
+
+	numSent, err = svc.RunDueSchedules(ctx, firstRun)
+	require.NoError(t, err)
+	require.Zero(t, numSent)
+
+	sched, err = storage.GetScheduleByName(ctx, owner.ID, "ghost")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), sched.RunCount)
+	require.Zero(t, sched.LastMessageID)
+	require.Contains(t, sched.LastError, "Ghost")
+	require.True(t, sched.NextRunAt.After(firstRun))
+
+	// Every run is in the owner's activity feed.
+	activities, err := storage.ListActivitiesByAgent(ctx, owner.ID, 50)
+	require.NoError(t, err)
+
+	var runs []string
+	for _, a := range activities {
+		if a.ActivityType == ActivityTypeScheduleRun {
+			runs = append(runs, a.Description)
+		}
+	}
+	require.Len(t, runs, 4)
+	require.Contains(t, runs, `Schedule "sweep" sent "Run the flaky `+
+		`test sweep" to SilverWolf`)
+}
@@ -0,0 +1,279 @@
+package mail
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/ratelimit"
+	"github.com/roasbeef/subtrate/internal/search"
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+// SearchRequest is the request for Search.
+type SearchRequest struct {
+	// AgentID is the agent searching its messages, or zero to search
+	// every message.
+	AgentID int64
+
+	// Query is the search query, in the language search.Parse accepts.
+	Query string
+
+	// TopicID restricts the search to a topic, like topic:.
+	TopicID int64
+
+	// Limit caps the number of results, 50 if not positive.
+	Limit int32
+
+	// Label restricts an agent's search to the messages it filed under
+	// the label, like label:.
+	Label string
+
+	// Cursor is the NextCursor of the previous page of results, or empty
+	// for the first page.
+	Cursor string
+
+	// IncludeBody returns the messages' full bodies. Without it they're
+	// left empty, and the hits' snippets stand in for them.
+	IncludeBody bool
+
+	// SavedSearch names one of the agent's saved searches to run. Query,
+	// if also set, narrows its results further.
+	SavedSearch string
+
+	// MessageIDs, if set, restricts the search to these messages, such
+	// as to check which of a batch of new messages a query matches.
+	MessageIDs []int64
+}
+
+// SearchHit is a message matched by a search.
+type SearchHit struct {
+	InboxMessage
+
+	// Snippet is the excerpt of the body around the matched terms, which
+	// are wrapped in search.HighlightStart and search.HighlightEnd. For
+	// filter-only queries it's the start of the body.
+	Snippet string
+
+	// SubjectHighlight is the subject with its matched terms
+	// highlighted.
+	SubjectHighlight string
+
+	// Rank is the message's BM25 rank, lower being better, or zero for
+	// filter-only queries.
+	Rank float64
+}
+
+// SearchResponse is the response for Search.
+type SearchResponse struct {
+	// Hits are the page's results, best matches first, or newest first
+	// for filter-only queries.
+	Hits []SearchHit
+
+	// Total is the number of messages the query matches across every
+	// page.
+	Total int64
+
+	// NextCursor fetches the next page of results, and is empty on the
+	// last page.
+	NextCursor string
+}
+
+// Search runs a search query over the messages an agent sent or received,
+// or over every message if AgentID is zero, a page at a time. Queries that
+// don't parse, or that name agents or topics that don't exist, fail with an
+// error wrapping search.ErrInvalidQuery.
+func (s *Service) Search(ctx context.Context,
+	req SearchRequest,
+) (SearchResponse, error) {
+	err := s.allow(ctx, req.AgentID, ratelimit.ClassSearch)
+	if err != nil {
+		return SearchResponse{}, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 50
+	}
+
+	if req.SavedSearch != "" {
+		var err error
+		req.Query, err = s.savedSearchQuery(
+			ctx, req.AgentID, req.SavedSearch, req.Query,
+		)
+		if err != nil {
+			return SearchResponse{}, err
+		}
+	}
+
+	query, err := search.Parse(req.Query, time.Now())
+	if err != nil {
+		return SearchResponse{}, err
+	}
+
+	params, err := s.searchParams(ctx, req, query)
+	if err != nil {
+		return SearchResponse{}, err
+	}
+	params.SubjectWeight = s.searchSubjectWeight
+	params.IncludeBody = req.IncludeBody
+	params.MessageIDs = req.MessageIDs
+
+	// Cursors only resume the query ordering they came from.
+	ranked := params.Match != ""
+	if req.Cursor != "" {
+		cursor, err := search.ParseCursor(req.Cursor)
+		if err != nil {
+			return SearchResponse{}, err
+		}
+		if cursor.Ranked != ranked {
+			return SearchResponse{}, fmt.Errorf("%w: the cursor "+
+				"belongs to a different query",
+				search.ErrInvalidQuery)
+		}
+		params.Cursor = &cursor
+	}
+
+	// One extra hit tells whether there's a next page.
+	params.Limit = limit + 1
+	rows, err := s.store.SearchMessagesWithFilters(ctx, params)
+	if err != nil {
+		return SearchResponse{}, fmt.Errorf("search failed: %w", err)
+	}
+
+	var resp SearchResponse
+	if len(rows) > limit {
+		rows = rows[:limit]
+		resp.NextCursor = rows[limit-1].Cursor(ranked).Encode()
+	}
+
+	resp.Total, err = s.store.CountMessagesWithFilters(ctx, params)
+	if err != nil {
+		return SearchResponse{}, fmt.Errorf("failed to count search "+
+			"results: %w", err)
+	}
+
+	messages := make([]InboxMessage, 0, len(rows))
+	for _, r := range rows {
+		messages = append(messages, storeInboxToMail(r.InboxMessage))
+	}
+	if err := s.attachLabels(ctx, req.AgentID, messages); err != nil {
+		return SearchResponse{}, err
+	}
+
+	resp.Hits = make([]SearchHit, len(rows))
+	for i, r := range rows {
+		resp.Hits[i] = SearchHit{
+			InboxMessage:     messages[i],
+			Snippet:          r.Snippet,
+			SubjectHighlight: r.SubjectHighlight,
+			Rank:             r.Rank,
+		}
+	}
+
+	return resp, nil
+}
+
+// searchParams resolves the names a parsed query refers to into the store's
+// search filters. from:me and to:me refer to the agent searching.
+func (s *Service) searchParams(ctx context.Context, req SearchRequest,
+	query search.Query,
+) (store.MessageSearchParams, error) {
+	if query.IsEmpty() && req.TopicID == 0 && req.Label == "" {
+		return store.MessageSearchParams{}, fmt.Errorf("%w: the query "+
+			"is empty", search.ErrInvalidQuery)
+	}
+
+	params := store.MessageSearchParams{
+		AgentID:        req.AgentID,
+		Match:          query.Match,
+		Exclude:        query.Exclude,
+		TopicID:        req.TopicID,
+		ThreadID:       query.Thread,
+		Priority:       query.Priority,
+		State:          query.State,
+		Label:          query.Label,
+		After:          query.After,
+		Before:         query.Before,
+		HasAttachments: query.HasAttachment,
+	}
+	if query.HasDiff {
+		params.BodyContains = search.DiffMarker
+	}
+
+	if req.Label != "" {
+		if params.Label != "" && params.Label != req.Label {
+			return store.MessageSearchParams{}, fmt.Errorf("%w: "+
+				"conflicting label filters %q and %q",
+				search.ErrInvalidQuery, req.Label, params.Label)
+		}
+		params.Label = req.Label
+	}
+	if params.Label != "" && req.AgentID == 0 {
+		return store.MessageSearchParams{}, fmt.Errorf("%w: label: "+
+			"needs an agent to search as", search.ErrInvalidQuery)
+	}
+
+	var err error
+	params.SenderID, err = s.searchAgentID(ctx, req.AgentID, "from",
+		query.From)
+	if err != nil {
+		return store.MessageSearchParams{}, err
+	}
+	params.RecipientID, err = s.searchAgentID(ctx, req.AgentID, "to",
+		query.To)
+	if err != nil {
+		return store.MessageSearchParams{}, err
+	}
+
+	if query.Topic != "" {
+		topic, err := s.store.GetTopicByName(ctx, query.Topic)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return store.MessageSearchParams{}, fmt.Errorf("%w: "+
+				"unknown topic %q", search.ErrInvalidQuery,
+				query.Topic)
+
+		case err != nil:
+			return store.MessageSearchParams{}, fmt.Errorf(
+				"failed to get topic: %w", err)
+		}
+
+		if req.TopicID != 0 && req.TopicID != topic.ID {
+			return store.MessageSearchParams{}, fmt.Errorf("%w: "+
+				"topic:%s conflicts with the topic searched in",
+				search.ErrInvalidQuery, query.Topic)
+		}
+		params.TopicID = topic.ID
+	}
+
+	return params, nil
+}
+
+// searchAgentID resolves the agent a from: or to: filter names, or returns
+// zero if the filter isn't set.
+func (s *Service) searchAgentID(ctx context.Context, searcherID int64,
+	key, name string,
+) (int64, error) {
+	switch {
+	case name == "":
+		return 0, nil
+
+	case name == "me" && searcherID != 0:
+		return searcherID, nil
+	}
+
+	agent, err := s.store.GetAgentByName(ctx, name)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, fmt.Errorf("%w: unknown agent %q in %s:",
+			search.ErrInvalidQuery, name, key)
+
+	case err != nil:
+		return 0, fmt.Errorf("failed to get agent: %w", err)
+	}
+
+	return agent.ID, nil
+}
@@ -0,0 +1,127 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/ratelimit"
+	"github.com/roasbeef/subtrate/internal/search"
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+// SearchAllRequest is the request for SearchAll.
+type SearchAllRequest struct {
+	// AgentID restricts the messages searched to the ones the agent sent
+	// or received, or zero to search every message.
+	AgentID int64
+
+	// Query is the search query. Only its terms and its before: and
+	// after: filters apply across kinds.
+	Query string
+
+	// Kinds are the kinds of entities to search, or every kind if
+	// empty.
+	Kinds []search.Kind
+
+	// Limit caps the number of hits, 50 if not positive.
+	Limit int32
+}
+
+// SearchAllResponse is the response for SearchAll.
+type SearchAllResponse struct {
+	// Hits are the matched entities of every kind, best matches first.
+	Hits []store.SearchAllHit
+
+	// Total is the number of entities the query matches in all.
+	Total int64
+}
+
+// SearchAll runs a search query over messages, review issues, plan reviews,
+// tasks and annotations at once, merging their hits by rank. Queries
+// without terms, or with filters that only apply to messages, fail with an
+// error wrapping search.ErrInvalidQuery.
+func (s *Service) SearchAll(ctx context.Context,
+	req SearchAllRequest,
+) (SearchAllResponse, error) {
+	err := s.allow(ctx, req.AgentID, ratelimit.ClassSearch)
+	if err != nil {
+		return SearchAllResponse{}, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query, err := search.Parse(req.Query, time.Now())
+	if err != nil {
+		return SearchAllResponse{}, err
+	}
+	if op := messageOnlyFilter(query); op != "" {
+		return SearchAllResponse{}, fmt.Errorf("%w: %s only applies "+
+			"to message searches", search.ErrInvalidQuery, op)
+	}
+	if query.Match == "" {
+		return SearchAllResponse{}, fmt.Errorf("%w: searching every "+
+			"kind needs search terms", search.ErrInvalidQuery)
+	}
+
+	params := store.SearchAllParams{
+		AgentID:     req.AgentID,
+		Match:       query.Match,
+		Exclude:     query.Exclude,
+		Kinds:       req.Kinds,
+		After:       query.After,
+		Before:      query.Before,
+		TitleWeight: s.searchSubjectWeight,
+		Limit:       limit,
+	}
+
+	hits, err := s.store.SearchAll(ctx, params)
+	if err != nil {
+		return SearchAllResponse{}, fmt.Errorf("search failed: %w", err)
+	}
+	total, err := s.store.CountSearchAll(ctx, params)
+	if err != nil {
+		return SearchAllResponse{}, fmt.Errorf("failed to count "+
+			"search results: %w", err)
+	}
+
+	return SearchAllResponse{Hits: hits, Total: total}, nil
+}
+
+// messageOnlyFilter returns the first operator of a query that only
+// applies to messages, or empty if it has none.
+func messageOnlyFilter(q search.Query) string {
+	switch {
+	case q.From != "":
+		return "from:"
+
+	case q.To != "":
+		return "to:"
+
+	case q.Topic != "":
+		return "topic:"
+
+	case q.Thread != "":
+		return "thread:"
+
+	case q.Label != "":
+		return "label:"
+
+	case q.Priority != "":
+		return "priority:"
+
+	case q.State != "":
+		return "state:"
+
+	case q.HasDiff:
+		return "has:diff"
+
+	case q.HasAttachment:
+		return "has:attachment"
+	}
+
+	return ""
+}
@@ -0,0 +1,62 @@
+package mail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/roasbeef/subtrate/internal/search"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSearchAll tests that searching every kind returns typed hits, and
+// that queries leaning on message-only filters are rejected.
+func TestSearchAll(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	alice := createTestAgent(t, storage, "Alice")
+	createTestAgent(t, storage, "Bob")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	resp := svc.handleSendMail(ctx, SendMailRequest{
+		SenderID:       alice.ID,
+		RecipientNames: []string{"Bob"},
+		Subject:        "Flaky payments test",
+		Body:           "The payments itest flakes on CI.",
+		Priority:       PriorityNormal,
+	})
+	require.NoError(t, resp.Error)
+
+	all, err := svc.SearchAll(ctx, SearchAllRequest{
+		AgentID: alice.ID,
+		Query:   "payments",
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, all.Total)
+	require.Len(t, all.Hits, 1)
+	require.Equal(t, search.KindMessage, all.Hits[0].Kind)
+	require.Equal(t, resp.MessageID, all.Hits[0].ID)
+	require.Equal(t, "Flaky **payments** test", all.Hits[0].Title)
+
+	all, err = svc.SearchAll(ctx, SearchAllRequest{
+		AgentID: alice.ID,
+		Query:   "payments",
+		Kinds:   []search.Kind{search.KindTask},
+	})
+	require.NoError(t, err)
+	require.Zero(t, all.Total)
+	require.Empty(t, all.Hits)
+
+	for _, query := range []string{"", "from:Alice payments", "has:diff"} {
+		_, err := svc.SearchAll(ctx, SearchAllRequest{
+			AgentID: alice.ID,
+			Query:   query,
+		})
+		require.ErrorIs(t, err, search.ErrInvalidQuery, query)
+	}
+}
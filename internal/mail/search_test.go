@@ -0,0 +1,249 @@
+package mail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/roasbeef/subtrate/internal/search"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSearch tests that search queries combine full-text terms with their
+// filters, and that bad queries fail with ErrInvalidQuery.
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	alice := createTestAgent(t, storage, "Alice")
+	bob := createTestAgent(t, storage, "Bob")
+	carol := createTestAgent(t, storage, "Carol")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	send := func(req SendMailRequest) SendMailResponse {
+		t.Helper()
+
+		if req.Priority == "" {
+			req.Priority = PriorityNormal
+		}
+		resp := svc.handleSendMail(ctx, req)
+		require.NoError(t, resp.Error)
+
+		return resp
+	}
+	deployAPI := send(SendMailRequest{
+		SenderID:       alice.ID,
+		RecipientNames: []string{"Bob", "Carol"},
+		Subject:        "Deploy api",
+		Body:           "Rolling out the api deploy.",
+		Priority:       PriorityUrgent,
+	})
+	review := send(SendMailRequest{
+		SenderID:       alice.ID,
+		RecipientNames: []string{"Bob"},
+		Subject:        "Review my change",
+		Body:           search.DiffMarker + "\ndiff --git a/x b/x",
+	})
+	deployWeb := send(SendMailRequest{
+		SenderID:       carol.ID,
+		RecipientNames: []string{"Bob"},
+		Subject:        "Deploy web",
+		Body:           "Web deploy notes.",
+		Priority:       PriorityLow,
+		Attachments:    `[{"name":"deploy.log"}]`,
+	})
+	lunch := send(SendMailRequest{
+		SenderID:       bob.ID,
+		RecipientNames: []string{"Alice"},
+		Subject:        "Lunch plans",
+		Body:           "Tacos?",
+	})
+
+	err := svc.UpdateState(ctx, UpdateStateRequest{
+		AgentID:   bob.ID,
+		MessageID: deployWeb.MessageID,
+		NewState:  "starred",
+	})
+	require.NoError(t, err)
+
+	find := func(agentID int64, query string) []int64 {
+		t.Helper()
+
+		resp, err := svc.Search(ctx, SearchRequest{
+			AgentID: agentID,
+			Query:   query,
+		})
+		require.NoError(t, err, query)
+		require.EqualValues(t, len(resp.Hits), resp.Total, query)
+
+		ids := make([]int64, len(resp.Hits))
+		for i, r := range resp.Hits {
+			ids[i] = r.ID
+		}
+
+		return ids
+	}
+
+	// Direct messages are filed under their first recipient's inbox
+	// topic.
+	msg, err := storage.GetMessage(ctx, deployAPI.MessageID)
+	require.NoError(t, err)
+	topic, err := storage.GetTopic(ctx, msg.TopicID)
+	require.NoError(t, err)
+
+	// An agent searches the messages it received and the ones it sent.
+	tests := []struct {
+		agentID int64
+		query   string
+		want    []int64
+	}{
+		{bob.ID, "deploy", []int64{
+			deployAPI.MessageID, deployWeb.MessageID,
+		}},
+		{bob.ID, "deploy OR tacos", []int64{
+			deployAPI.MessageID, deployWeb.MessageID,
+			lunch.MessageID,
+		}},
+		{bob.ID, "deploy -web", []int64{deployAPI.MessageID}},
+		{bob.ID, "depl* from:Alice", []int64{deployAPI.MessageID}},
+		{bob.ID, "to:Carol", []int64{deployAPI.MessageID}},
+		{bob.ID, "from:me", []int64{lunch.MessageID}},
+		{bob.ID, "has:diff", []int64{review.MessageID}},
+		{bob.ID, "has:attachment", []int64{deployWeb.MessageID}},
+		{bob.ID, "is:starred", []int64{deployWeb.MessageID}},
+		{bob.ID, "is:unread", []int64{
+			deployAPI.MessageID, review.MessageID,
+		}},
+		{bob.ID, "priority:urgent", []int64{deployAPI.MessageID}},
+		{bob.ID, "thread:" + review.ThreadID, []int64{
+			review.MessageID,
+		}},
+		{bob.ID, search.QuoteFilter("topic", topic.Name), []int64{
+			deployAPI.MessageID, review.MessageID,
+			deployWeb.MessageID,
+		}},
+		{bob.ID, "after:1h -lunch", []int64{
+			deployAPI.MessageID, review.MessageID,
+			deployWeb.MessageID,
+		}},
+		{bob.ID, "before:1h", nil},
+		{carol.ID, "deploy", []int64{
+			deployAPI.MessageID, deployWeb.MessageID,
+		}},
+		{alice.ID, "deploy web", nil},
+
+		// A global search matches any recipient's state.
+		{0, "deploy is:starred", []int64{deployWeb.MessageID}},
+	}
+	for _, tc := range tests {
+		require.ElementsMatch(t, tc.want, find(tc.agentID, tc.query),
+			tc.query)
+	}
+
+	// Bad queries and unknown names fail clearly.
+	for _, query := range []string{
+		`"deploy`, "deploy OR", "from:Nobody", "topic:nowhere",
+		"label:ops", "  ",
+	} {
+		_, err := svc.Search(ctx, SearchRequest{Query: query})
+		require.ErrorIs(t, err, search.ErrInvalidQuery, query)
+	}
+}
+
+// TestSearchPages tests that search results are ranked with subject matches
+// first, carry snippets, and page with cursors.
+func TestSearchPages(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	alice := createTestAgent(t, storage, "Alice")
+	createTestAgent(t, storage, "Bob")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	var ids []int64
+	for _, subject := range []string{
+		"Notes", "Notes", "Cache migration", "Notes", "Notes",
+	} {
+		resp := svc.handleSendMail(ctx, SendMailRequest{
+			SenderID:       alice.ID,
+			RecipientNames: []string{"Bob"},
+			Subject:        subject,
+			Body: "Yesterday we talked about the cache " +
+				"and its\nmigration plan.",
+			Priority: PriorityNormal,
+		})
+		require.NoError(t, resp.Error)
+		ids = append(ids, resp.MessageID)
+	}
+
+	// pages collects every page of a query's results.
+	pages := func(query string) []SearchHit {
+		t.Helper()
+
+		var (
+			hits   []SearchHit
+			cursor string
+		)
+		for {
+			resp, err := svc.Search(ctx, SearchRequest{
+				AgentID: alice.ID,
+				Query:   query,
+				Limit:   2,
+				Cursor:  cursor,
+			})
+			require.NoError(t, err, query)
+			require.EqualValues(t, 5, resp.Total, query)
+			require.LessOrEqual(t, len(resp.Hits), 2)
+
+			hits = append(hits, resp.Hits...)
+			if resp.NextCursor == "" {
+				return hits
+			}
+			cursor = resp.NextCursor
+		}
+	}
+
+	// The subject match ranks first, and ties go to the newest message.
+	hits := pages("cache")
+	require.Len(t, hits, 5)
+	require.Equal(t, ids[2], hits[0].ID)
+	require.Equal(t, "**Cache** migration", hits[0].SubjectHighlight)
+	require.Equal(t, []int64{ids[4], ids[3], ids[1], ids[0]}, []int64{
+		hits[1].ID, hits[2].ID, hits[3].ID, hits[4].ID,
+	})
+	require.Equal(t, "Yesterday we talked about the **cache** and its "+
+		"migration plan.", hits[1].Snippet)
+	require.Empty(t, hits[1].Body)
+
+	// Filter-only queries page newest first, with the start of the body.
+	hits = pages("from:Alice")
+	require.Len(t, hits, 5)
+	for i, hit := range hits {
+		require.Equal(t, ids[4-i], hit.ID)
+		require.Equal(t, "Yesterday we talked about the cache and "+
+			"its migration plan.", hit.Snippet)
+	}
+
+	// A cursor only resumes the kind of query it came from.
+	resp, err := svc.Search(ctx, SearchRequest{
+		AgentID: alice.ID,
+		Query:   "cache",
+		Limit:   1,
+	})
+	require.NoError(t, err)
+	_, err = svc.Search(ctx, SearchRequest{
+		AgentID: alice.ID,
+		Query:   "from:Alice",
+		Cursor:  resp.NextCursor,
+	})
+	require.ErrorIs(t, err, search.ErrInvalidQuery)
+}
@@ -0,0 +1,494 @@
+package mail
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/embed"
+	"github.com/roasbeef/subtrate/internal/ratelimit"
+	"github.com/roasbeef/subtrate/internal/search"
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+const (
+	// DefaultEmbeddingInterval is how often the embedding indexer looks
+	// for messages it hasn't embedded yet, on top of being woken by every
+	// send.
+	DefaultEmbeddingInterval = time.Minute
+
+	// DefaultSemanticHybridWeight is how much a message's similarity
+	// counts against its BM25 rank in a hybrid search by default.
+	DefaultSemanticHybridWeight = 0.5
+
+	// DefaultDuplicateThreshold is the similarity above which a new
+	// message is taken for a duplicate of an earlier one.
+	DefaultDuplicateThreshold = 0.85
+
+	// embeddingBatchSize is the number of messages embedded at a time.
+	embeddingBatchSize = 32
+
+	// hybridCandidates is the least number of BM25 results a hybrid
+	// search merges with the similar messages.
+	hybridCandidates = 50
+)
+
+// SemanticSearchRequest is the request for SemanticSearch.
+type SemanticSearchRequest struct {
+	// AgentID is the agent searching its messages, or zero to search
+	// every message.
+	AgentID int64
+
+	// Query is the search query. Its terms are matched by meaning, and
+	// its filters apply as they do in Search.
+	Query string
+
+	// Limit caps the number of hits, 20 if not positive.
+	Limit int32
+
+	// Hybrid merges the messages the terms match by BM25 with the ones
+	// similar in meaning, weighing the two scores together.
+	Hybrid bool
+
+	// MinScore drops the hits scoring less.
+	MinScore float64
+}
+
+// SimilarMessagesRequest is the request for SimilarMessages.
+type SimilarMessagesRequest struct {
+	// AgentID is the agent whose messages are compared, or zero to
+	// compare every message.
+	AgentID int64
+
+	// MessageID is the message to find the neighbours of. It's left out
+	// of the hits.
+	MessageID int64
+
+	// Text is the text to find the neighbours of, if MessageID isn't
+	// set, such as a message about to be sent.
+	Text string
+
+	// Limit caps the number of hits, 20 if not positive.
+	Limit int32
+
+	// MinScore drops the hits less similar.
+	MinScore float64
+}
+
+// SemanticHit is a message found by its meaning.
+type SemanticHit struct {
+	InboxMessage
+
+	// Snippet is the start of the message's body.
+	Snippet string
+
+	// Score is what the hits are ordered by: the similarity, or in a
+	// hybrid search the weighted sum of the similarity and TextScore.
+	Score float64
+
+	// Similarity is the cosine similarity of the message's embedding
+	// and the query's, from -1 to 1.
+	Similarity float64
+
+	// TextScore is the message's BM25 rank relative to the best one,
+	// from 0 to 1, in a hybrid search.
+	TextScore float64
+}
+
+// SemanticSearchResponse is the response for SemanticSearch and
+// SimilarMessages.
+type SemanticSearchResponse struct {
+	// Hits are the closest messages, best first.
+	Hits []SemanticHit
+}
+
+// EmbedText returns the text a message with the given subject and body is
+// embedded as.
+func EmbedText(subject, body string) string {
+	return subject + "\n\n" + body
+}
+
+// Embedder returns the embedder messages are embedded with.
+func (s *Service) Embedder() embed.Embedder {
+	return s.embedder
+}
+
+// IndexEmbeddings embeds every message without an embedding under the
+// embedder's model, returning how many it embedded.
+func (s *Service) IndexEmbeddings(ctx context.Context) (int, error) {
+	model := s.embedder.Model()
+
+	var indexed int
+	for {
+		msgs, err := s.store.ListMessagesToEmbed(
+			ctx, model, embeddingBatchSize,
+		)
+		if err != nil {
+			return indexed, fmt.Errorf("failed to list messages "+
+				"to embed: %w", err)
+		}
+		if len(msgs) == 0 {
+			return indexed, nil
+		}
+
+		texts := make([]string, len(msgs))
+		for i, msg := range msgs {
+			texts[i] = EmbedText(msg.Subject, msg.Body)
+		}
+		vectors, err := s.embedder.Embed(ctx, texts)
+		if err != nil {
+			return indexed, fmt.Errorf("failed to embed "+
+				"messages: %w", err)
+		}
+
+		for i, msg := range msgs {
+			err := s.store.UpsertMessageEmbedding(
+				ctx, store.UpsertMessageEmbeddingParams{
+					MessageID: msg.ID,
+					Model:     model,
+					Vector:    vectors[i],
+				},
+			)
+			if err != nil {
+				return indexed, fmt.Errorf("failed to store "+
+					"embedding: %w", err)
+			}
+			indexed++
+		}
+	}
+}
+
+// wakeEmbeddingIndexer asks the embedding indexer to embed new messages
+// without waiting for its next interval.
+func (s *Service) wakeEmbeddingIndexer() {
+	select {
+	case s.embedWake <- struct{}{}:
+	default:
+	}
+}
+
+// RunEmbeddingIndexer embeds new messages as they're sent, and any missed
+// on every interval, until the context is cancelled.
+func (s *Service) RunEmbeddingIndexer(ctx context.Context,
+	interval time.Duration, log *slog.Logger,
+) {
+	if interval <= 0 {
+		interval = DefaultEmbeddingInterval
+	}
+	if log == nil {
+		log = slog.Default()
+	}
+	log = log.With("component", "embedding-indexer",
+		"model", s.embedder.Model())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := s.IndexEmbeddings(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			log.ErrorContext(ctx, "Failed to embed messages",
+				"error", err)
+
+		case n > 0:
+			log.DebugContext(ctx, "Embedded messages", "count", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+		case <-s.embedWake:
+		}
+	}
+}
+
+// SemanticSearch finds the messages closest in meaning to a query's terms,
+// among the ones its filters keep. Messages are only found once the indexer
+// has embedded them. Queries that don't parse, or that have no terms, fail
+// with an error wrapping search.ErrInvalidQuery.
+func (s *Service) SemanticSearch(ctx context.Context,
+	req SemanticSearchRequest,
+) (SemanticSearchResponse, error) {
+	err := s.allow(ctx, req.AgentID, ratelimit.ClassSearch)
+	if err != nil {
+		return SemanticSearchResponse{}, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 20
+	}
+
+	query, err := search.Parse(req.Query, time.Now())
+	if err != nil {
+		return SemanticSearchResponse{}, err
+	}
+	if query.Text == "" {
+		return SemanticSearchResponse{}, fmt.Errorf("%w: semantic "+
+			"search needs search terms", search.ErrInvalidQuery)
+	}
+
+	params, err := s.searchParams(ctx, SearchRequest{
+		AgentID: req.AgentID,
+		Query:   req.Query,
+	}, query)
+	if err != nil {
+		return SemanticSearchResponse{}, err
+	}
+
+	vectors, err := s.embedder.Embed(ctx, []string{query.Text})
+	if err != nil {
+		return SemanticSearchResponse{}, fmt.Errorf("failed to embed "+
+			"query: %w", err)
+	}
+	scores, err := s.similarities(ctx, vectors[0], 0, params)
+	if err != nil {
+		return SemanticSearchResponse{}, err
+	}
+
+	if req.Hybrid {
+		params.SubjectWeight = s.searchSubjectWeight
+		params.Limit = max(4*limit, hybridCandidates)
+		textHits, err := s.store.SearchMessagesWithFilters(
+			ctx, params,
+		)
+		if err != nil {
+			return SemanticSearchResponse{}, fmt.Errorf("search "+
+				"failed: %w", err)
+		}
+		s.mergeTextScores(scores, textHits)
+	}
+
+	return s.semanticHits(ctx, req.AgentID, scores, limit, req.MinScore)
+}
+
+// SimilarMessages finds the nearest neighbours of a message, or of some text
+// such as a message about to be sent.
+func (s *Service) SimilarMessages(ctx context.Context,
+	req SimilarMessagesRequest,
+) (SemanticSearchResponse, error) {
+	err := s.allow(ctx, req.AgentID, ratelimit.ClassSearch)
+	if err != nil {
+		return SemanticSearchResponse{}, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 20
+	}
+
+	var vector embed.Vector
+	switch {
+	case req.MessageID != 0:
+		var err error
+		vector, err = s.messageEmbedding(ctx, req.MessageID)
+		if err != nil {
+			return SemanticSearchResponse{}, err
+		}
+
+	case req.Text != "":
+		vectors, err := s.embedder.Embed(ctx, []string{req.Text})
+		if err != nil {
+			return SemanticSearchResponse{}, fmt.Errorf("failed "+
+				"to embed text: %w", err)
+		}
+		vector = vectors[0]
+
+	default:
+		return SemanticSearchResponse{}, fmt.Errorf("%w: a message "+
+			"or text to compare is required",
+			search.ErrInvalidQuery)
+	}
+
+	scores, err := s.similarities(
+		ctx, vector, req.MessageID, store.MessageSearchParams{
+			AgentID: req.AgentID,
+		},
+	)
+	if err != nil {
+		return SemanticSearchResponse{}, err
+	}
+
+	return s.semanticHits(ctx, req.AgentID, scores, limit, req.MinScore)
+}
+
+// messageEmbedding returns a message's embedding, embedding it now if the
+// indexer hasn't yet.
+func (s *Service) messageEmbedding(ctx context.Context,
+	messageID int64,
+) (embed.Vector, error) {
+	e, err := s.store.GetMessageEmbedding(
+		ctx, messageID, s.embedder.Model(),
+	)
+	switch {
+	case err == nil:
+		return e.Vector, nil
+
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("failed to get embedding: %w", err)
+	}
+
+	msg, err := s.store.GetMessage(ctx, messageID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("%w: %d", ErrMessageNotFound, messageID)
+
+	case err != nil:
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	vectors, err := s.embedder.Embed(
+		ctx, []string{EmbedText(msg.Subject, msg.Body)},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed message: %w", err)
+	}
+
+	return vectors[0], nil
+}
+
+// semanticScore is a candidate hit's scores.
+type semanticScore struct {
+	messageID  int64
+	similarity float64
+	textScore  float64
+	score      float64
+}
+
+// similarities scores every embedded message the search params keep by its
+// similarity to the vector, leaving out the excluded message.
+func (s *Service) similarities(ctx context.Context, vector embed.Vector,
+	exclude int64, params store.MessageSearchParams,
+) (map[int64]*semanticScore, error) {
+	embeddings, err := s.store.ListMessageEmbeddings(
+		ctx, s.embedder.Model(), params,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[int64]*semanticScore, len(embeddings))
+	for _, e := range embeddings {
+		if e.MessageID == exclude {
+			continue
+		}
+
+		sim := embed.Cosine(vector, e.Vector)
+		scores[e.MessageID] = &semanticScore{
+			messageID:  e.MessageID,
+			similarity: sim,
+			score:      sim,
+		}
+	}
+
+	return scores, nil
+}
+
+// mergeTextScores folds the BM25 ranks of a full-text search into the
+// similarity scores. Each rank is taken relative to the best, and weighed
+// against the similarity by the service's hybrid weight.
+func (s *Service) mergeTextScores(scores map[int64]*semanticScore,
+	hits []store.MessageSearchHit,
+) {
+	var best float64
+	for _, hit := range hits {
+		best = min(best, hit.Rank)
+	}
+
+	for _, hit := range hits {
+		sc, ok := scores[hit.ID]
+		if !ok {
+			sc = &semanticScore{messageID: hit.ID}
+			scores[hit.ID] = sc
+		}
+
+		sc.textScore = 1
+		if best < 0 {
+			sc.textScore = hit.Rank / best
+		}
+	}
+
+	w := s.semanticHybridWeight
+	for _, sc := range scores {
+		sc.score = w*max(sc.similarity, 0) + (1-w)*sc.textScore
+	}
+}
+
+// semanticHits returns the messages of the best scores, best first.
+func (s *Service) semanticHits(ctx context.Context, agentID int64,
+	scores map[int64]*semanticScore, limit int, minScore float64,
+) (SemanticSearchResponse, error) {
+	ranked := make([]*semanticScore, 0, len(scores))
+	for _, sc := range scores {
+		if sc.score > 0 && sc.score >= minScore {
+			ranked = append(ranked, sc)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].messageID > ranked[j].messageID
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	if len(ranked) == 0 {
+		return SemanticSearchResponse{}, nil
+	}
+
+	ids := make([]int64, len(ranked))
+	for i, sc := range ranked {
+		ids[i] = sc.messageID
+	}
+	rows, err := s.store.SearchMessagesWithFilters(
+		ctx, store.MessageSearchParams{
+			AgentID:    agentID,
+			MessageIDs: ids,
+			Limit:      len(ids),
+		},
+	)
+	if err != nil {
+		return SemanticSearchResponse{}, fmt.Errorf("failed to get "+
+			"messages: %w", err)
+	}
+	byID := make(map[int64]store.MessageSearchHit, len(rows))
+	for _, r := range rows {
+		byID[r.ID] = r
+	}
+
+	// Messages deleted since they were scored are left out.
+	var (
+		messages []InboxMessage
+		hits     []SemanticHit
+	)
+	for _, sc := range ranked {
+		r, ok := byID[sc.messageID]
+		if !ok {
+			continue
+		}
+
+		messages = append(messages, storeInboxToMail(r.InboxMessage))
+		hits = append(hits, SemanticHit{
+			Snippet:    r.Snippet,
+			Score:      sc.score,
+			Similarity: sc.similarity,
+			TextScore:  sc.textScore,
+		})
+	}
+	if err := s.attachLabels(ctx, agentID, messages); err != nil {
+		return SemanticSearchResponse{}, err
+	}
+	for i := range hits {
+		hits[i].InboxMessage = messages[i]
+	}
+
+	return SemanticSearchResponse{Hits: hits}, nil
+}
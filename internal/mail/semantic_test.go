@@ -0,0 +1,140 @@
+package mail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/roasbeef/subtrate/internal/search"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSemanticSearch tests that messages are found by their meaning once
+// they're embedded, alone or merged with BM25 ranks, and that near
+// duplicates of a text are found.
+func TestSemanticSearch(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	alice := createTestAgent(t, storage, "Alice")
+	createTestAgent(t, storage, "Bob")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	send := func(subject, body string) int64 {
+		t.Helper()
+
+		resp := svc.handleSendMail(ctx, SendMailRequest{
+			SenderID:       alice.ID,
+			RecipientNames: []string{"Bob"},
+			Subject:        subject,
+			Body:           body,
+			Priority:       PriorityNormal,
+		})
+		require.NoError(t, resp.Error)
+
+		return resp.MessageID
+	}
+	deploy := send("Deploying the api server",
+		"The api server deployment to staging starts at noon.")
+	lunch := send("Lunch", "Pizza or tacos on Friday?")
+	flaky := send("Flaky payments test",
+		"The payments itest failed again on CI.")
+
+	// Nothing is found until the messages are embedded.
+	resp, err := svc.SemanticSearch(ctx, SemanticSearchRequest{
+		AgentID: alice.ID,
+		Query:   "deploy the api servers",
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Hits)
+
+	n, err := svc.IndexEmbeddings(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	n, err = svc.IndexEmbeddings(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	resp, err = svc.SemanticSearch(ctx, SemanticSearchRequest{
+		AgentID: alice.ID,
+		Query:   "deploy the api servers",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Hits)
+	require.Equal(t, deploy, resp.Hits[0].ID)
+	require.Equal(t, "Alice", resp.Hits[0].SenderName)
+	require.Equal(t, resp.Hits[0].Similarity, resp.Hits[0].Score)
+	for i := 1; i < len(resp.Hits); i++ {
+		require.Less(t, resp.Hits[i].Score, resp.Hits[0].Score)
+	}
+
+	// Filters narrow what's compared.
+	resp, err = svc.SemanticSearch(ctx, SemanticSearchRequest{
+		AgentID: alice.ID,
+		Query:   "deploy the api servers from:Bob",
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Hits)
+
+	// A hybrid search weighs in the BM25 rank of the messages the terms
+	// match.
+	resp, err = svc.SemanticSearch(ctx, SemanticSearchRequest{
+		AgentID: alice.ID,
+		Query:   "payments",
+		Hybrid:  true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, flaky, resp.Hits[0].ID)
+	require.Equal(t, 1.0, resp.Hits[0].TextScore)
+	require.Greater(t, resp.Hits[0].Score, 0.5)
+
+	// Near duplicates of a text, or of a message, are its closest
+	// neighbours.
+	resp, err = svc.SimilarMessages(ctx, SimilarMessagesRequest{
+		AgentID:  alice.ID,
+		Text:     EmbedText("Lunch", "Pizza or tacos on Friday?"),
+		MinScore: DefaultDuplicateThreshold,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Hits, 1)
+	require.Equal(t, lunch, resp.Hits[0].ID)
+	require.InDelta(t, 1, resp.Hits[0].Score, 1e-6)
+
+	resp, err = svc.SimilarMessages(ctx, SimilarMessagesRequest{
+		AgentID:   alice.ID,
+		MessageID: lunch,
+	})
+	require.NoError(t, err)
+	for _, hit := range resp.Hits {
+		require.NotEqual(t, lunch, hit.ID)
+	}
+
+	_, err = svc.SimilarMessages(ctx, SimilarMessagesRequest{
+		MessageID: 999,
+	})
+	require.ErrorIs(t, err, ErrMessageNotFound)
+
+	// Edited messages are embedded again.
+	_, err = svc.EditMessage(ctx, EditMessageRequest{
+		SenderID:  alice.ID,
+		MessageID: lunch,
+		Body:      "Burgers on Friday?",
+	})
+	require.NoError(t, err)
+	n, err = svc.IndexEmbeddings(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	for _, query := range []string{"", "from:Alice", "-lunch"} {
+		_, err := svc.SemanticSearch(ctx, SemanticSearchRequest{
+			AgentID: alice.ID,
+			Query:   query,
+		})
+		require.ErrorIs(t, err, search.ErrInvalidQuery, query)
+	}
+}
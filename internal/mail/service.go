@@ -2,12 +2,18 @@ package mail
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/lightningnetwork/lnd/fn/v2"
 	"github.com/roasbeef/subtrate/internal/baselib/actor"
+	"github.com/roasbeef/subtrate/internal/embed"
+	"github.com/roasbeef/subtrate/internal/ratelimit"
+	"github.com/roasbeef/subtrate/internal/search"
 	"github.com/roasbeef/subtrate/internal/store"
 )
 
@@ -56,26 +62,132 @@ type ServiceConfig struct {
 	// set, the service notifies recipients via the hub after sending messages
 	// using fire-and-forget Tell semantics for optimal performance.
 	NotificationHub NotificationActorRef
+
+	// QueueVisibilityTimeout is the default lease duration for claimed
+	// queue messages. Defaults to DefaultQueueVisibilityTimeout.
+	QueueVisibilityTimeout time.Duration
+
+	// QueueMaxDeliveries is the number of deliveries a queue message gets
+	// before it is dead-lettered. Defaults to DefaultQueueMaxDeliveries.
+	QueueMaxDeliveries int
+
+	// OnlineThreshold is how recently an agent must have sent a heartbeat
+	// to be reached by the "@online" alias. Defaults to
+	// DefaultOnlineThreshold.
+	OnlineThreshold time.Duration
+
+	// RecipientPolicy optionally decides whether a sender may deliver to
+	// a recipient. Rejected recipients fail an atomic send, and are
+	// dead-lettered by a partial one.
+	RecipientPolicy RecipientPolicy
+
+	// SearchSubjectWeight is how much more a search term matched in a
+	// message's subject counts towards its rank than one matched in its
+	// body. Defaults to search.DefaultSubjectWeight.
+	SearchSubjectWeight float64
+
+	// Embedder embeds messages for semantic search. Defaults to an
+	// embed.HashEmbedder.
+	Embedder embed.Embedder
+
+	// SemanticHybridWeight is how much a message's similarity counts
+	// against its BM25 rank in a hybrid search, from 0 to 1. Defaults to
+	// DefaultSemanticHybridWeight.
+	SemanticHybridWeight float64
+
+	// RateLimiter optionally holds callers to their rate limits. Clients
+	// using the database directly set it; the daemon leaves it unset,
+	// since its RPCs are already limited as they arrive.
+	RateLimiter *ratelimit.Limiter
 }
 
 // Service is the mail service actor behavior.
 type Service struct {
 	store    store.Storage
 	notifHub NotificationActorRef
+
+	// threadFSMs drives every recipient state change through the thread
+	// state machine.
+	threadFSMs *ThreadFSMManager
+
+	// wakes executes the wake timers emitted by the thread state machine
+	// for snoozed messages.
+	wakes *WakeScheduler
+
+	// queueVisibility and queueMaxDeliveries control lease durations and
+	// dead-lettering for queue topics.
+	queueVisibility    time.Duration
+	queueMaxDeliveries int
+
+	// onlineThreshold bounds the heartbeat age of "@online" recipients,
+	// and of recipients a message is delivered to rather than queued for.
+	onlineThreshold time.Duration
+
+	// recipientPolicy, if set, can reject individual recipients.
+	recipientPolicy RecipientPolicy
+
+	// searchSubjectWeight weights subject matches when ranking search
+	// results.
+	searchSubjectWeight float64
+
+	// embedder embeds messages for semantic search, and embedWake wakes
+	// the embedding indexer after a message is sent or edited.
+	embedder  embed.Embedder
+	embedWake chan struct{}
+
+	// semanticHybridWeight weights similarity against BM25 rank in
+	// hybrid searches.
+	semanticHybridWeight float64
+
+	// rateLimiter, if set, holds callers to their rate limits.
+	rateLimiter *ratelimit.Limiter
 }
 
 // NewService creates a new mail service with the given configuration.
 func NewService(cfg ServiceConfig) *Service {
-	return &Service{
-		store:    cfg.Store,
-		notifHub: cfg.NotificationHub,
+	s := &Service{
+		store:              cfg.Store,
+		notifHub:           cfg.NotificationHub,
+		threadFSMs:         NewThreadFSMManager(DefaultTrashRetention),
+		queueVisibility:    cfg.QueueVisibilityTimeout,
+		queueMaxDeliveries: cfg.QueueMaxDeliveries,
+		onlineThreshold:    cfg.OnlineThreshold,
+		recipientPolicy:    cfg.RecipientPolicy,
+
+		searchSubjectWeight: cfg.SearchSubjectWeight,
+
+		embedder:             cfg.Embedder,
+		embedWake:            make(chan struct{}, 1),
+		semanticHybridWeight: cfg.SemanticHybridWeight,
+		rateLimiter:          cfg.RateLimiter,
+	}
+	if s.queueVisibility <= 0 {
+		s.queueVisibility = DefaultQueueVisibilityTimeout
+	}
+	if s.queueMaxDeliveries <= 0 {
+		s.queueMaxDeliveries = DefaultQueueMaxDeliveries
+	}
+	if s.onlineThreshold <= 0 {
+		s.onlineThreshold = DefaultOnlineThreshold
+	}
+	if s.searchSubjectWeight <= 0 {
+		s.searchSubjectWeight = search.DefaultSubjectWeight
 	}
+	if s.embedder == nil {
+		s.embedder = embed.NewHashEmbedder(embed.DefaultDimensions)
+	}
+	if s.semanticHybridWeight <= 0 || s.semanticHybridWeight > 1 {
+		s.semanticHybridWeight = DefaultSemanticHybridWeight
+	}
+	s.wakes = NewWakeScheduler(s.WakeMessage)
+
+	return s
 }
 
 // NewServiceWithStore creates a new mail service with just a store (no
 // notifications). This is a convenience constructor for simple use cases.
 func NewServiceWithStore(s store.Storage) *Service {
-	return &Service{store: s}
+	return NewService(ServiceConfig{Store: s})
 }
 
 // SetNotificationHub sets the notification hub actor reference. This allows
@@ -84,6 +196,18 @@ func (s *Service) SetNotificationHub(hub NotificationActorRef) {
 	s.notifHub = hub
 }
 
+// allow takes a token from an agent's rate limit for a class, if the
+// service has a rate limiter.
+func (s *Service) allow(ctx context.Context, agentID int64,
+	class ratelimit.Class,
+) error {
+	if s.rateLimiter == nil {
+		return nil
+	}
+
+	return s.rateLimiter.Allow(ctx, agentID, class)
+}
+
 // Receive implements actor.ActorBehavior by dispatching to type-specific
 // handlers.
 func (s *Service) Receive(ctx context.Context,
@@ -91,6 +215,11 @@ func (s *Service) Receive(ctx context.Context,
 ) fn.Result[MailResponse] {
 	switch m := msg.(type) {
 	case SendMailRequest:
+		err := s.allow(ctx, m.SenderID, ratelimit.ClassSend)
+		if err != nil {
+			return fn.Ok[MailResponse](SendMailResponse{Error: err})
+		}
+
 		resp := s.handleSendMail(ctx, m)
 		return fn.Ok[MailResponse](resp)
 
@@ -119,6 +248,11 @@ func (s *Service) Receive(ctx context.Context,
 		return fn.Ok[MailResponse](resp)
 
 	case PublishRequest:
+		err := s.allow(ctx, m.SenderID, ratelimit.ClassPublish)
+		if err != nil {
+			return fn.Ok[MailResponse](PublishResponse{Error: err})
+		}
+
 		resp := s.handlePublish(ctx, m)
 		return fn.Ok[MailResponse](resp)
 
@@ -149,18 +283,41 @@ func (s *Service) handleSendMail(ctx context.Context,
 		}
 	}
 
+	// A send time in the future defers the message to the scheduler.
+	if req.SendAt != nil && req.SendAt.After(time.Now()) {
+		return s.scheduleSend(ctx, req)
+	}
+
+	metadata, err := encodeMetadata(req.Metadata)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+
 	// Variables to capture data for notification after successful transaction.
-	var recipientIDs []int64
+	var recipientIDs, notifyIDs []int64
 	var senderName string
 	var msgCreatedAt time.Time
+	var msgTopicID int64
+	var outbox []ThreadOutboxEvent
 
-	err := s.store.WithTx(ctx, func(ctx context.Context,
+	err = s.store.WithTx(ctx, func(ctx context.Context,
 		txStore store.Storage,
 	) error {
 		// Reset mutable state captured from the outer scope so
 		// that transaction retries (on SQLITE_BUSY) don't
 		// accumulate duplicate values from prior attempts.
 		recipientIDs = recipientIDs[:0]
+		outbox = nil
+
+		// Hold the sender to its daily storage quota.
+		err := ratelimit.CheckQuota(
+			ctx, txStore, req.SenderID, int64(len(req.Body)),
+			time.Now(),
+		)
+		if err != nil {
+			return err
+		}
 
 		// Generate thread ID if not provided.
 		threadID := req.ThreadID
@@ -169,14 +326,14 @@ func (s *Service) handleSendMail(ctx context.Context,
 		}
 		response.ThreadID = threadID
 
-		// Resolve recipient agent IDs.
-		for _, name := range req.RecipientNames {
-			agent, err := txStore.GetAgentByName(ctx, name)
-			if err != nil {
-				return fmt.Errorf("recipient %q not found: %w",
-					name, err)
-			}
-			recipientIDs = append(recipientIDs, agent.ID)
+		// Resolve recipient agents, expanding any groups and dynamic
+		// aliases.
+		partial := req.DeliveryMode == DeliveryModePartial
+		resolved, err := s.resolveRecipients(
+			ctx, txStore, req.SenderID, req.RecipientNames, partial,
+		)
+		if err != nil {
+			return err
 		}
 
 		// Get or create the sender's inbox topic for direct messages.
@@ -186,37 +343,66 @@ func (s *Service) handleSendMail(ctx context.Context,
 		}
 		senderName = sender.Name
 
-		// For direct messages, use the first recipient's inbox topic.
-		var topicID int64
-		if len(recipientIDs) > 0 {
-			recipient, err := txStore.GetAgent(ctx, recipientIDs[0])
-			if err != nil {
-				return fmt.Errorf("failed to get recipient: %w",
-					err)
+		// Decide the outcome for each resolved recipient. A rejected
+		// recipient fails an atomic send outright.
+		deliveries := slices.Clone(resolved.deliveries)
+		for i := range deliveries {
+			d := &deliveries[i]
+			if d.Status == RecipientUnknown {
+				continue
 			}
 
-			topic, err := txStore.GetOrCreateAgentInboxTopic(
+			s.deliverTo(ctx, sender, resolved.agents[d.AgentID], d)
+			if d.Status == RecipientRejected && !partial {
+				return fmt.Errorf("%w: %s: %s",
+					ErrRecipientRejected, d.Recipient,
+					d.Reason)
+			}
+			if !d.Status.Undeliverable() {
+				recipientIDs = append(recipientIDs, d.AgentID)
+			}
+		}
+		response.Deliveries = deliveries
+
+		// For direct messages, use the first recipient's inbox topic.
+		// A partial send that reached nobody is filed under the
+		// sender's own inbox topic so the dead letters have a message
+		// to point at.
+		var topic store.Topic
+		switch {
+		case len(recipientIDs) > 0:
+			recipient := resolved.agents[recipientIDs[0]]
+			topic, err = txStore.GetOrCreateAgentInboxTopic(
 				ctx, recipient.Name,
 			)
 			if err != nil {
 				return fmt.Errorf("failed to get inbox topic: "+
 					"%w", err)
 			}
-			topicID = topic.ID
-		} else if req.TopicName != "" {
+
+		case req.TopicName != "":
 			// Use the specified topic for pub/sub.
-			topic, err := txStore.GetTopicByName(ctx, req.TopicName)
+			topic, err = txStore.GetTopicByName(ctx, req.TopicName)
 			if err != nil {
 				return fmt.Errorf("topic %q not found: %w",
 					req.TopicName, err)
 			}
-			topicID = topic.ID
-		} else {
+
+		case partial && len(deliveries) > 0:
+			topic, err = txStore.GetOrCreateAgentInboxTopic(
+				ctx, sender.Name,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to get inbox topic: "+
+					"%w", err)
+			}
+
+		default:
 			return fmt.Errorf("no recipients or topic specified")
 		}
 
 		// Get the next log offset for the topic.
-		logOffset, err := txStore.NextLogOffset(ctx, topicID)
+		logOffset, err := txStore.NextLogOffset(ctx, topic.ID)
 		if err != nil {
 			return fmt.Errorf("failed to get next offset: %w", err)
 		}
@@ -224,7 +410,7 @@ func (s *Service) handleSendMail(ctx context.Context,
 		// Create the message.
 		msg, err := txStore.CreateMessage(ctx, store.CreateMessageParams{
 			ThreadID:       threadID,
-			TopicID:        topicID,
+			TopicID:        topic.ID,
 			LogOffset:      logOffset,
 			SenderID:       sender.ID,
 			Subject:        req.Subject,
@@ -233,15 +419,72 @@ func (s *Service) handleSendMail(ctx context.Context,
 			DeadlineAt:     req.Deadline,
 			Attachments:    req.Attachments,
 			IdempotencyKey: req.IdempotencyKey,
+			Metadata:       metadata,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create message: %w", err)
 		}
 		response.MessageID = msg.ID
 		msgCreatedAt = msg.CreatedAt
+		msgTopicID = msg.TopicID
+
+		// A question is recorded with its message. Any other message
+		// sent into a thread may answer the thread's questions.
+		switch {
+		case req.question != nil:
+			params := *req.question
+			params.MessageID = msg.ID
+			params.ThreadID = threadID
+			_, err := txStore.CreateQuestion(ctx, params)
+			if err != nil {
+				return fmt.Errorf("failed to record question: "+
+					"%w", err)
+			}
+
+		case req.ThreadID != "":
+			err := correlateAnswers(
+				ctx, txStore, threadID, sender.ID, msg.ID,
+				req.Body,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Record what each group and alias expanded to, so the
+		// recipients of the message can be audited later.
+		response.Expansions, err = recordExpansions(
+			ctx, txStore, msg.ID, resolved.expansions,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Likewise record the agent picked for each capability.
+		for _, route := range resolved.routes {
+			route.MessageID = msg.ID
+			_, err := recordRoute(ctx, txStore, route)
+			if err != nil {
+				return err
+			}
+		}
+
+		// A reply also reaches the agents following its thread.
+		var threadPrefs []store.ThreadPreference
+		if req.ThreadID != "" {
+			threadPrefs, err = txStore.
+				ListThreadPreferencesByThread(ctx, threadID)
+			if err != nil {
+				return fmt.Errorf("failed to get thread "+
+					"preferences: %w", err)
+			}
+		}
+		deliverIDs := slices.Concat(recipientIDs, threadFollowers(
+			threadPrefs, sender.ID, recipientIDs,
+		))
 
 		// Create recipient entries.
-		for _, recipientID := range recipientIDs {
+		for _, recipientID := range deliverIDs {
 			err := txStore.CreateMessageRecipient(
 				ctx, msg.ID, recipientID,
 			)
@@ -251,20 +494,56 @@ func (s *Service) handleSendMail(ctx context.Context,
 			}
 		}
 
-		return nil
+		// Apply each recipient's inbox rules, which may file the
+		// message away or forward it to more recipients.
+		_, notifyIDs, outbox, err = s.applyInboxRules(
+			ctx, txStore, msg.ID, msg.ThreadID, deliverIDs,
+			ruleMessage{
+				senderName: sender.Name,
+				subject:    req.Subject,
+				topicName:  topic.Name,
+				priority:   string(req.Priority),
+				metadata:   req.Metadata,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		// Recipients that muted the thread get the reply already
+		// read, and aren't notified of it.
+		var muteOutbox []ThreadOutboxEvent
+		notifyIDs, muteOutbox, err = s.muteThreadRecipients(
+			ctx, txStore, msg.ID, msg.ThreadID, threadPrefs,
+			notifyIDs,
+		)
+		if err != nil {
+			return err
+		}
+		outbox = append(outbox, muteOutbox...)
+
+		// File anything that couldn't be delivered in the sender's
+		// dead-letter mailbox.
+		return recordDeadLetters(
+			ctx, txStore, msg.ID, sender.ID, deliveries,
+		)
 	})
 	if err != nil {
 		response.Error = err
 		return response
 	}
+	s.wakeEmbeddingIndexer()
+	s.dispatchOutbox(ctx, outbox)
 
 	// After successful transaction, notify recipients via notification hub actor.
 	// Use fire-and-forget Tell for optimal performance - we don't need to wait
-	// for the notification to be delivered.
-	if s.notifHub != nil && len(recipientIDs) > 0 {
+	// for the notification to be delivered. Recipients whose inbox rules
+	// archived or snoozed the message aren't notified.
+	if s.notifHub != nil && len(notifyIDs) > 0 {
 		notifMsg := InboxMessage{
 			ID:         response.MessageID,
 			ThreadID:   response.ThreadID,
+			TopicID:    msgTopicID,
 			SenderID:   req.SenderID,
 			SenderName: senderName,
 			Subject:    req.Subject,
@@ -276,7 +555,7 @@ func (s *Service) handleSendMail(ctx context.Context,
 		}
 
 		// Notify each recipient via the hub actor using fire-and-forget.
-		for _, recipientID := range recipientIDs {
+		for _, recipientID := range notifyIDs {
 			s.notifHub.Tell(ctx, NotifyAgentMsg{
 				AgentID: recipientID,
 				Message: notifMsg,
@@ -377,6 +656,25 @@ func (s *Service) handleFetchInbox(ctx context.Context,
 		return response
 	}
 
+	if req.OverdueOnly {
+		msgs, err := s.store.GetOverdueMessages(
+			ctx, req.AgentID, time.Now(), limit, req.Offset,
+		)
+		if err != nil {
+			response.Error = fmt.Errorf("failed to fetch overdue: "+
+				"%w", err)
+			return response
+		}
+
+		for _, m := range msgs {
+			response.Messages = append(
+				response.Messages, storeInboxToMail(m),
+			)
+		}
+
+		return response
+	}
+
 	if req.UnreadOnly {
 		msgs, err := s.store.GetUnreadMessages(
 			ctx, req.AgentID, limit, req.Offset,
@@ -394,6 +692,11 @@ func (s *Service) handleFetchInbox(ctx context.Context,
 			)
 		}
 
+		err = s.attachLabels(ctx, req.AgentID, response.Messages)
+		if err != nil {
+			response.Error = err
+		}
+
 		return response
 	}
 
@@ -405,6 +708,10 @@ func (s *Service) handleFetchInbox(ctx context.Context,
 		msgs, err = s.store.GetAllInboxMessages(
 			ctx, limit, req.Offset,
 		)
+	case req.Label != "":
+		msgs, err = s.store.GetInboxMessagesByLabel(
+			ctx, req.AgentID, req.Label, limit, req.Offset,
+		)
 	case req.Category != store.InboxCategoryAll:
 		msgs, err = s.store.GetInboxMessagesByCategory(
 			ctx, req.AgentID, req.Category, limit, req.Offset,
@@ -423,6 +730,12 @@ func (s *Service) handleFetchInbox(ctx context.Context,
 		response.Messages = append(response.Messages, storeInboxToMail(m))
 	}
 
+	err = s.attachLabels(ctx, req.AgentID, response.Messages)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+
 	// Populate category counts for per-agent fetches so the UI can
 	// drive tab labels and stats off the same round trip. Skip for the
 	// global view (AgentID=0) since counts are agent-scoped.
@@ -448,11 +761,16 @@ func (s *Service) handleFetchInbox(ctx context.Context,
 func (s *Service) handleReadMessage(ctx context.Context,
 	req ReadMessageRequest,
 ) ReadMessageResponse {
-	var response ReadMessageResponse
+	var (
+		response ReadMessageResponse
+		outbox   []ThreadOutboxEvent
+	)
 
 	err := s.store.WithTx(ctx, func(ctx context.Context,
 		txStore store.Storage,
 	) error {
+		outbox = nil
+
 		// Get the message.
 		msg, err := txStore.GetMessage(ctx, req.MessageID)
 		if err != nil {
@@ -469,13 +787,13 @@ func (s *Service) handleReadMessage(ctx context.Context,
 
 		// Mark as read if currently unread.
 		if recipient.State == StateUnreadStr.String() {
-			err = txStore.MarkMessageRead(ctx, req.MessageID, req.AgentID)
+			recipient, outbox, err = s.runTransition(
+				ctx, txStore, recipient, msg.ThreadID,
+				s.threadFSMs.MarkRead,
+			)
 			if err != nil {
 				return fmt.Errorf("failed to mark read: %w", err)
 			}
-			recipient.State = StateReadStr.String()
-			now := time.Now()
-			recipient.ReadAt = &now
 		}
 
 		// Build response.
@@ -493,59 +811,59 @@ func (s *Service) handleReadMessage(ctx context.Context,
 			SnoozedUntil: recipient.SnoozedUntil,
 			ReadAt:       recipient.ReadAt,
 			AckedAt:      recipient.AckedAt,
+			EditedAt:     msg.EditedAt,
+		}
+
+		labels, err := txStore.ListMessageLabels(
+			ctx, req.AgentID, []int64{msg.ID},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to list labels: %w", err)
 		}
+		inboxMsg.Labels = labels[msg.ID]
 
 		response.Message = &inboxMsg
 		return nil
 	})
 	if err != nil {
 		response.Error = err
+		return response
 	}
+
+	s.dispatchOutbox(ctx, outbox)
+
 	return response
 }
 
-// handleUpdateState processes an UpdateStateRequest.
+// handleUpdateState processes an UpdateStateRequest. The requested state is
+// reached by driving the recipient's thread FSM, so side effects such as wake
+// timers for snoozed messages are scheduled along with the state change.
 func (s *Service) handleUpdateState(ctx context.Context,
 	req UpdateStateRequest,
 ) UpdateStateResponse {
 	var response UpdateStateResponse
 
-	if req.NewState == "snoozed" {
-		if req.SnoozedUntil == nil {
-			response.Error = fmt.Errorf(
-				"snoozed_until required for snooze",
-			)
-			return response
-		}
+	if req.NewState == StateSnoozedStr.String() && req.SnoozedUntil == nil {
+		response.Error = fmt.Errorf("snoozed_until required for snooze")
+		return response
+	}
 
-		err := s.store.SnoozeMessage(
-			ctx, req.MessageID, req.AgentID, *req.SnoozedUntil,
-		)
-		if err != nil {
-			response.Error = fmt.Errorf("failed to snooze: %w", err)
-			return response
-		}
-	} else if req.NewState == StateReadStr.String() {
-		// Use MarkMessageRead for read transitions so the
-		// read_at timestamp is properly set in the DB.
-		err := s.store.MarkMessageRead(
-			ctx, req.MessageID, req.AgentID,
-		)
-		if err != nil {
-			response.Error = fmt.Errorf(
-				"failed to mark read: %w", err,
+	err := s.transition(
+		ctx, req.AgentID, req.MessageID,
+		func(ctx context.Context,
+			fsm *ThreadFSM) ([]ThreadOutboxEvent, error) {
+
+			return s.threadFSMs.TransitionTo(
+				ctx, fsm, RecipientState(req.NewState),
+				req.SnoozedUntil,
 			)
-			return response
-		}
-	} else {
-		err := s.store.UpdateRecipientState(
-			ctx, req.MessageID, req.AgentID, req.NewState,
-		)
-		if err != nil {
-			response.Error = fmt.Errorf("failed to update state: "+
-				"%w", err)
-			return response
-		}
+		},
+	)
+
+	// As with a plain UPDATE, a missing recipient row is a no-op.
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		response.Error = fmt.Errorf("failed to update state: %w", err)
+		return response
 	}
 
 	response.Success = true
@@ -558,9 +876,11 @@ func (s *Service) handleAckMessage(ctx context.Context,
 ) AckMessageResponse {
 	var response AckMessageResponse
 
-	err := s.store.AckMessage(ctx, req.MessageID, req.AgentID)
+	// Acking a claimed queue message also completes its lease. Agents
+	// that don't hold a lease on the message are unaffected.
+	err := s.ackMessage(ctx, req.AgentID, req.MessageID, false)
 	if err != nil {
-		response.Error = fmt.Errorf("failed to ack: %w", err)
+		response.Error = err
 		return response
 	}
 
@@ -641,6 +961,11 @@ func (s *Service) handlePollChanges(ctx context.Context,
 
 	// Poll each topic for new messages since the given offset.
 	for _, topic := range topics {
+		// Queue messages are only handed out through claims.
+		if topic.TopicType == TopicTypeQueue {
+			continue
+		}
+
 		sinceOffset := req.SinceOffsets[topic.ID]
 
 		msgs, err := s.store.GetMessagesSinceOffset(
@@ -692,6 +1017,7 @@ func storeInboxToMail(m store.InboxMessage) InboxMessage {
 		SnoozedUntil:     m.SnoozedUntil,
 		ReadAt:           m.ReadAt,
 		AckedAt:          m.AckedAt,
+		EditedAt:         m.EditedAt,
 	}
 }
 
@@ -707,6 +1033,7 @@ func storeMessageToMail(m store.Message) InboxMessage {
 		Priority:  Priority(m.Priority),
 		CreatedAt: m.CreatedAt,
 		Deadline:  m.DeadlineAt,
+		EditedAt:  m.EditedAt,
 	}
 }
 
@@ -730,6 +1057,7 @@ func storeInboxMessageToMail(m store.InboxMessage) InboxMessage {
 		SnoozedUntil:     m.SnoozedUntil,
 		ReadAt:           m.ReadAt,
 		AckedAt:          m.AckedAt,
+		EditedAt:         m.EditedAt,
 	}
 }
 
@@ -737,7 +1065,12 @@ func storeInboxMessageToMail(m store.InboxMessage) InboxMessage {
 func (s *Service) handlePublish(ctx context.Context,
 	req PublishRequest,
 ) PublishResponse {
-	var response PublishResponse
+	var (
+		response  PublishResponse
+		notifMsg  InboxMessage
+		notifyIDs []int64
+		outbox    []ThreadOutboxEvent
+	)
 
 	// Idempotency check: if a key is provided and a message with that
 	// key already exists, return the original response immediately.
@@ -752,12 +1085,30 @@ func (s *Service) handlePublish(ctx context.Context,
 		}
 	}
 
-	err := s.store.WithTx(ctx, func(ctx context.Context,
+	metadata, err := encodeMetadata(req.Metadata)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+
+	err = s.store.WithTx(ctx, func(ctx context.Context,
 		txStore store.Storage,
 	) error {
 		// Reset mutable state so transaction retries (on
 		// SQLITE_BUSY) don't accumulate stale values.
 		response.RecipientsCount = 0
+		response.Queued = false
+		notifyIDs = nil
+		outbox = nil
+
+		// Hold the sender to its daily storage quota.
+		err := ratelimit.CheckQuota(
+			ctx, txStore, req.SenderID, int64(len(req.Body)),
+			time.Now(),
+		)
+		if err != nil {
+			return err
+		}
 
 		// Get the topic.
 		topic, err := txStore.GetTopicByName(ctx, req.TopicName)
@@ -785,11 +1136,31 @@ func (s *Service) handlePublish(ctx context.Context,
 			Body:           req.Body,
 			Priority:       string(req.Priority),
 			IdempotencyKey: req.IdempotencyKey,
+			Metadata:       metadata,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create message: %w", err)
 		}
 		response.MessageID = msg.ID
+		notifMsg = InboxMessage{
+			ID:        msg.ID,
+			ThreadID:  threadID,
+			TopicID:   topic.ID,
+			SenderID:  req.SenderID,
+			Subject:   req.Subject,
+			Body:      req.Body,
+			Priority:  req.Priority,
+			State:     StateUnreadStr.String(),
+			CreatedAt: msg.CreatedAt,
+		}
+
+		// Queue topics deliver each message to a single consumer,
+		// so rather than fanning out the message waits to be
+		// claimed.
+		if topic.TopicType == TopicTypeQueue {
+			response.Queued = true
+			return txStore.CreateQueueDelivery(ctx, msg.ID, topic.ID)
+		}
 
 		// Get all subscribers to the topic.
 		subscribers, err := txStore.ListSubscriptionsByTopic(
@@ -800,6 +1171,7 @@ func (s *Service) handlePublish(ctx context.Context,
 		}
 
 		// Create recipient entries for all subscribers.
+		subscriberIDs := make([]int64, 0, len(subscribers))
 		for _, sub := range subscribers {
 			err := txStore.CreateMessageRecipient(
 				ctx, msg.ID, sub.ID,
@@ -808,13 +1180,58 @@ func (s *Service) handlePublish(ctx context.Context,
 				return fmt.Errorf("failed to create recipient "+
 					"entry: %w", err)
 			}
-			response.RecipientsCount++
+			subscriberIDs = append(subscriberIDs, sub.ID)
+		}
+
+		sender, err := txStore.GetAgent(ctx, req.SenderID)
+		if err != nil {
+			return fmt.Errorf("sender not found: %w", err)
+		}
+
+		notifMsg.SenderName = sender.Name
+
+		// Apply each subscriber's inbox rules. Forwarded copies count
+		// as recipients too.
+		var forwarded []int64
+		forwarded, notifyIDs, outbox, err = s.applyInboxRules(
+			ctx, txStore, msg.ID, msg.ThreadID, subscriberIDs,
+			ruleMessage{
+				senderName: sender.Name,
+				subject:    req.Subject,
+				topicName:  topic.Name,
+				priority:   string(req.Priority),
+				metadata:   req.Metadata,
+			},
+		)
+		if err != nil {
+			return err
 		}
+		response.RecipientsCount = len(subscriberIDs) + len(forwarded)
 
 		return nil
 	})
 	if err != nil {
 		response.Error = err
+		return response
+	}
+	s.wakeEmbeddingIndexer()
+	s.dispatchOutbox(ctx, outbox)
+
+	// Notify the subscribers the message was delivered to, along with
+	// the topic's own watchers, and the global feed as sends do. Queued
+	// messages have no recipients until claimed, so only the latter hear
+	// of them.
+	if s.notifHub != nil {
+		s.notifHub.Tell(ctx, NotifyTopicMsg{
+			TopicID:   notifMsg.TopicID,
+			TopicName: req.TopicName,
+			AgentIDs:  notifyIDs,
+			Message:   notifMsg,
+		})
+		s.notifHub.Tell(ctx, NotifyAgentMsg{
+			AgentID: 0,
+			Message: notifMsg,
+		})
 	}
 
 	return response
@@ -826,6 +1243,11 @@ func (s *Service) handlePublish(ctx context.Context,
 
 // Send sends a mail message synchronously.
 func (s *Service) Send(ctx context.Context, req SendMailRequest) (SendMailResponse, error) {
+	err := s.allow(ctx, req.SenderID, ratelimit.ClassSend)
+	if err != nil {
+		return SendMailResponse{Error: err}, err
+	}
+
 	resp := s.handleSendMail(ctx, req)
 	return resp, resp.Error
 }
@@ -890,19 +1312,38 @@ func (s *Service) PollChanges(ctx context.Context, req PollChangesRequest) (Poll
 
 // Publish publishes a message to a topic synchronously.
 func (s *Service) Publish(ctx context.Context, req PublishRequest) (PublishResponse, error) {
+	err := s.allow(ctx, req.SenderID, ratelimit.ClassPublish)
+	if err != nil {
+		return PublishResponse{Error: err}, err
+	}
+
 	resp := s.handlePublish(ctx, req)
 	return resp, resp.Error
 }
 
-// Subscribe subscribes an agent to a topic.
+// Subscribe subscribes an agent to a topic, creating it as a broadcast topic
+// if it doesn't exist yet.
 func (s *Service) Subscribe(ctx context.Context, agentID int64, topicName string) (int64, error) {
+	return s.SubscribeTopic(ctx, agentID, topicName, TopicTypeBroadcast)
+}
+
+// SubscribeTopic subscribes an agent to a topic, creating it with the given
+// type if it doesn't exist yet. The type of an existing topic is left
+// untouched.
+func (s *Service) SubscribeTopic(ctx context.Context, agentID int64,
+	topicName, topicType string,
+) (int64, error) {
+	if topicType == "" {
+		topicType = TopicTypeBroadcast
+	}
+
 	var topicID int64
 
 	err := s.store.WithTx(ctx, func(ctx context.Context,
 		txStore store.Storage,
 	) error {
 		// Get or create the topic.
-		topic, err := txStore.GetOrCreateTopic(ctx, topicName, "broadcast")
+		topic, err := txStore.GetOrCreateTopic(ctx, topicName, topicType)
 		if err != nil {
 			return fmt.Errorf("failed to get/create topic: %w", err)
 		}
@@ -946,6 +1387,10 @@ type TopicInfo struct {
 	TopicType    string
 	CreatedAt    time.Time
 	MessageCount int64
+
+	// RetentionSeconds is how long the topic's messages are kept, or 0 if
+	// they're kept forever.
+	RetentionSeconds int64
 }
 
 // ListTopics lists topics, optionally filtered by agent subscription.
@@ -959,10 +1404,11 @@ func (s *Service) ListTopics(ctx context.Context, req ListTopicsRequest) ([]Topi
 		}
 		for _, r := range rows {
 			topics = append(topics, TopicInfo{
-				ID:        r.ID,
-				Name:      r.Name,
-				TopicType: r.TopicType,
-				CreatedAt: r.CreatedAt,
+				ID:               r.ID,
+				Name:             r.Name,
+				TopicType:        r.TopicType,
+				CreatedAt:        r.CreatedAt,
+				RetentionSeconds: r.RetentionSeconds,
 			})
 		}
 	} else {
@@ -972,11 +1418,12 @@ func (s *Service) ListTopics(ctx context.Context, req ListTopicsRequest) ([]Topi
 		}
 		for _, r := range rows {
 			topics = append(topics, TopicInfo{
-				ID:           r.ID,
-				Name:         r.Name,
-				TopicType:    r.TopicType,
-				CreatedAt:    r.CreatedAt,
-				MessageCount: r.MessageCount,
+				ID:               r.ID,
+				Name:             r.Name,
+				TopicType:        r.TopicType,
+				CreatedAt:        r.CreatedAt,
+				MessageCount:     r.MessageCount,
+				RetentionSeconds: r.RetentionSeconds,
 			})
 		}
 	}
@@ -990,51 +1437,6 @@ type ListTopicsRequest struct {
 	SubscribedOnly bool
 }
 
-// SearchRequest is the request for Search.
-type SearchRequest struct {
-	AgentID int64
-	Query   string
-	TopicID int64
-	Limit   int32
-}
-
-// Search performs full-text search across messages.
-func (s *Service) Search(ctx context.Context, req SearchRequest) ([]InboxMessage, error) {
-	limit := int(req.Limit)
-	if limit <= 0 {
-		limit = 50
-	}
-
-	if req.AgentID == 0 {
-		// Global search across all messages.
-		rows, err := s.store.SearchMessages(ctx, req.Query, limit)
-		if err != nil {
-			return nil, fmt.Errorf("search failed: %w", err)
-		}
-
-		var messages []InboxMessage
-		for _, r := range rows {
-			messages = append(messages, storeInboxToMail(r))
-		}
-		return messages, nil
-	}
-
-	// Agent-specific search.
-	rows, err := s.store.SearchMessagesForAgent(
-		ctx, req.Query, req.AgentID, limit,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("search failed: %w", err)
-	}
-
-	var messages []InboxMessage
-	for _, r := range rows {
-		messages = append(messages, storeMessageToMail(r))
-	}
-
-	return messages, nil
-}
-
 // SubscribeInbox creates a streaming subscription to an agent's inbox.
 // Returns a channel that receives new messages and a cancel function.
 func (s *Service) SubscribeInbox(ctx context.Context, agentID int64) (<-chan InboxMessage, func(), error) {
@@ -0,0 +1,629 @@
+package mail
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+const (
+	// TopicTypeQueue is the topic type with competing-consumer
+	// semantics: each message is delivered to exactly one subscriber.
+	TopicTypeQueue = "queue"
+
+	// TopicTypeBroadcast is the topic type that fans every message out to
+	// all subscribers.
+	TopicTypeBroadcast = "broadcast"
+
+	// DefaultQueueVisibilityTimeout is how long a claimed queue message
+	// stays invisible to other consumers before it is redelivered.
+	DefaultQueueVisibilityTimeout = 5 * time.Minute
+
+	// DefaultQueueMaxDeliveries is the number of deliveries a queue message
+	// gets before it is moved to the dead-letter topic.
+	DefaultQueueMaxDeliveries = 5
+
+	// DefaultQueueReapInterval is how often expired leases are reaped.
+	DefaultQueueReapInterval = 15 * time.Second
+
+	// deadLetterSuffix is appended to a queue topic's name to form the
+	// name of its dead-letter topic.
+	deadLetterSuffix = "/dead-letter"
+)
+
+// DeadLetterTopicName returns the name of the dead-letter topic for the given
+// queue topic.
+func DeadLetterTopicName(topicName string) string {
+	return topicName + deadLetterSuffix
+}
+
+// QueueClaim is a queue message leased to a single consumer.
+type QueueClaim struct {
+	// Message is the claimed message.
+	Message InboxMessage
+
+	// LeaseExpiresAt is when the message becomes visible to other
+	// consumers again unless it is acked or the lease is extended.
+	LeaseExpiresAt time.Time
+
+	// DeliveryCount is the number of times the message has been claimed,
+	// including this one.
+	DeliveryCount int
+}
+
+// visibilityOrDefault returns the given visibility timeout, falling back to
+// the service default when it is unset.
+func (s *Service) visibilityOrDefault(visibility time.Duration) time.Duration {
+	if visibility <= 0 {
+		return s.queueVisibility
+	}
+
+	return visibility
+}
+
+// ClaimQueueMessage leases the oldest available message on a queue topic to
+// the agent, hiding it from other consumers until the visibility timeout
+// passes. Messages whose previous lease expired are redelivered. A nil claim
+// is returned when there is nothing to claim.
+func (s *Service) ClaimQueueMessage(ctx context.Context, agentID int64,
+	topicName string, visibility time.Duration,
+) (*QueueClaim, error) {
+	visibility = s.visibilityOrDefault(visibility)
+
+	var (
+		claim  *QueueClaim
+		outbox []ThreadOutboxEvent
+	)
+	err := s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		claim, outbox = nil, nil
+
+		topic, err := txStore.GetTopicByName(ctx, topicName)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrTopicNotFound, topicName)
+		}
+		if topic.TopicType != TopicTypeQueue {
+			return fmt.Errorf("%w: %s", ErrNotQueueTopic, topicName)
+		}
+
+		subscribed, err := isSubscribed(ctx, txStore, topic.ID, agentID)
+		if err != nil {
+			return err
+		}
+		if !subscribed {
+			return fmt.Errorf("%w: %s", ErrNotSubscribed, topicName)
+		}
+
+		// Expired leases are released before claiming, so their
+		// holders' copies are archived before the message moves on.
+		now := time.Now()
+		outbox, err = s.releaseExpiredLeases(ctx, txStore, now)
+		if err != nil {
+			return err
+		}
+
+		delivery, err := txStore.ClaimNextQueueDelivery(
+			ctx, store.ClaimQueueDeliveryParams{
+				TopicID:        topic.ID,
+				AgentID:        agentID,
+				LeaseExpiresAt: now.Add(visibility),
+				MaxDeliveries:  s.queueMaxDeliveries,
+			},
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to claim message: %w", err)
+		}
+
+		msg, err := txStore.GetMessage(ctx, delivery.MessageID)
+		if err != nil {
+			return fmt.Errorf("failed to get message: %w", err)
+		}
+
+		// The claimant gets the message in its inbox like any other
+		// delivery. An agent that released the message earlier
+		// already has an archived copy, which is brought back.
+		recipient, err := txStore.GetMessageRecipient(
+			ctx, msg.ID, agentID,
+		)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			err = txStore.CreateMessageRecipient(ctx, msg.ID, agentID)
+			if err != nil {
+				return fmt.Errorf("failed to create recipient "+
+					"entry: %w", err)
+			}
+
+		case err != nil:
+			return fmt.Errorf("failed to get recipient: %w", err)
+
+		default:
+			var events []ThreadOutboxEvent
+			_, events, err = s.runTransition(
+				ctx, txStore, recipient, msg.ThreadID,
+				func(ctx context.Context,
+					fsm *ThreadFSM) ([]ThreadOutboxEvent, error) {
+
+					return s.threadFSMs.TransitionTo(
+						ctx, fsm, StateUnreadStr, nil,
+					)
+				},
+			)
+			if err != nil {
+				return err
+			}
+			outbox = append(outbox, events...)
+		}
+
+		inboxMsg := storeMessageToMail(msg)
+		inboxMsg.State = StateUnreadStr.String()
+		if sender, err := txStore.GetAgent(ctx, msg.SenderID); err == nil {
+			inboxMsg.SenderName = sender.Name
+			inboxMsg.SenderProjectKey = sender.ProjectKey
+			inboxMsg.SenderGitBranch = sender.GitBranch
+		}
+
+		claim = &QueueClaim{
+			Message:       inboxMsg,
+			DeliveryCount: delivery.DeliveryCount,
+		}
+		if delivery.LeaseExpiresAt != nil {
+			claim.LeaseExpiresAt = *delivery.LeaseExpiresAt
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.dispatchOutbox(ctx, outbox)
+
+	return claim, nil
+}
+
+// AckQueueMessage completes the agent's lease on a queue message so it is
+// never redelivered, and acks the agent's copy of the message. Both happen in
+// one transaction, so a lease that expires mid-ack leaves the copy unacked.
+func (s *Service) AckQueueMessage(ctx context.Context, agentID,
+	messageID int64,
+) error {
+	return s.ackMessage(ctx, agentID, messageID, true)
+}
+
+// ackMessage completes the agent's lease on a message, if it holds one, and
+// acks its copy of the message within a single transaction. When leaseHeld
+// is set, ErrLeaseNotHeld is returned unless the agent holds the lease.
+func (s *Service) ackMessage(ctx context.Context, agentID, messageID int64,
+	leaseHeld bool,
+) error {
+	var outbox []ThreadOutboxEvent
+	err := s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		outbox = nil
+
+		acked, err := txStore.AckQueueDelivery(ctx, messageID, agentID)
+		if err != nil {
+			return fmt.Errorf("failed to ack queue message: %w",
+				err)
+		}
+		if leaseHeld && !acked {
+			return fmt.Errorf("%w: message %d", ErrLeaseNotHeld,
+				messageID)
+		}
+
+		recipient, err := txStore.GetMessageRecipient(
+			ctx, messageID, agentID,
+		)
+
+		// As with a plain UPDATE, a missing recipient row is a no-op.
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to ack: %w", err)
+		}
+
+		msg, err := txStore.GetMessage(ctx, messageID)
+		if err != nil {
+			return fmt.Errorf("failed to ack: %w", err)
+		}
+
+		_, outbox, err = s.runTransition(
+			ctx, txStore, recipient, msg.ThreadID, s.threadFSMs.Ack,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to ack: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.dispatchOutbox(ctx, outbox)
+
+	return nil
+}
+
+// releaseExpiredLeases archives the copies of the agents whose leases
+// expired, so a stale holder isn't left with an unread copy of a message
+// that's redelivered to someone else, then returns the expired leases with
+// attempts left to the queue. Exhausted leases stay put for the reaper to
+// dead-letter.
+func (s *Service) releaseExpiredLeases(ctx context.Context,
+	txStore store.Storage, now time.Time,
+) ([]ThreadOutboxEvent, error) {
+	expired, err := txStore.ListExpiredQueueLeases(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired leases: %w", err)
+	}
+
+	var outbox []ThreadOutboxEvent
+	for _, lease := range expired {
+		events, err := s.archiveHolderCopy(ctx, txStore, lease)
+		if err != nil {
+			return nil, err
+		}
+		outbox = append(outbox, events...)
+	}
+
+	_, err = txStore.ReleaseExpiredQueueLeases(
+		ctx, now, s.queueMaxDeliveries,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release expired leases: %w",
+			err)
+	}
+
+	return outbox, nil
+}
+
+// archiveHolderCopy archives the lease holder's copy of a queue message. A
+// copy the holder already archived or trashed is left alone.
+func (s *Service) archiveHolderCopy(ctx context.Context,
+	txStore store.Storage, lease store.QueueDelivery,
+) ([]ThreadOutboxEvent, error) {
+	if lease.ClaimedBy == 0 {
+		return nil, nil
+	}
+
+	recipient, err := txStore.GetMessageRecipient(
+		ctx, lease.MessageID, lease.ClaimedBy,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipient: %w", err)
+	}
+
+	switch RecipientState(recipient.State) {
+	case StateArchivedStr, StateTrashStr:
+		return nil, nil
+	}
+
+	msg, err := txStore.GetMessage(ctx, lease.MessageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	_, outbox, err := s.runTransition(
+		ctx, txStore, recipient, msg.ThreadID,
+		func(ctx context.Context,
+			fsm *ThreadFSM) ([]ThreadOutboxEvent, error) {
+
+			return s.threadFSMs.TransitionTo(
+				ctx, fsm, StateArchivedStr, nil,
+			)
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive message: %w", err)
+	}
+
+	return outbox, nil
+}
+
+// ReleaseQueueMessage hands the agent's lease on a queue message back to the
+// queue, making it immediately available to other consumers, and archives the
+// agent's copy. If the message has used up its delivery attempts it is moved
+// to the dead-letter topic instead.
+func (s *Service) ReleaseQueueMessage(ctx context.Context, agentID,
+	messageID int64, reason string,
+) error {
+	var (
+		outbox      []ThreadOutboxEvent
+		deadLetters []deadLetter
+	)
+	err := s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		outbox, deadLetters = nil, nil
+
+		released, err := txStore.ReleaseQueueDelivery(
+			ctx, messageID, agentID, reason,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to release queue message: %w",
+				err)
+		}
+		if !released {
+			return fmt.Errorf("%w: message %d", ErrLeaseNotHeld,
+				messageID)
+		}
+
+		msg, err := txStore.GetMessage(ctx, messageID)
+		if err != nil {
+			return fmt.Errorf("failed to get message: %w", err)
+		}
+
+		recipient, err := txStore.GetMessageRecipient(
+			ctx, messageID, agentID,
+		)
+		if err == nil {
+			_, outbox, err = s.runTransition(
+				ctx, txStore, recipient, msg.ThreadID,
+				func(ctx context.Context,
+					fsm *ThreadFSM) ([]ThreadOutboxEvent, error) {
+
+					return s.threadFSMs.TransitionTo(
+						ctx, fsm, StateArchivedStr, nil,
+					)
+				},
+			)
+		}
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to archive message: %w", err)
+		}
+
+		delivery, err := txStore.GetQueueDelivery(ctx, messageID)
+		if err != nil {
+			return fmt.Errorf("failed to get delivery: %w", err)
+		}
+		if delivery.DeliveryCount < s.queueMaxDeliveries {
+			return nil
+		}
+
+		topic, err := txStore.GetTopic(ctx, delivery.TopicID)
+		if err != nil {
+			return fmt.Errorf("failed to get topic: %w", err)
+		}
+		delivery.TopicName = topic.Name
+
+		dl, err := deadLetterMessage(ctx, txStore, delivery)
+		if err != nil {
+			return err
+		}
+		deadLetters = append(deadLetters, dl)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.dispatchOutbox(ctx, outbox)
+	s.notifyDeadLetters(ctx, deadLetters)
+
+	return nil
+}
+
+// ExtendQueueLease pushes out the agent's lease on a queue message so a long
+// running consumer keeps it hidden from others. The new expiry is returned.
+func (s *Service) ExtendQueueLease(ctx context.Context, agentID,
+	messageID int64, visibility time.Duration,
+) (time.Time, error) {
+	expiresAt := time.Now().Add(s.visibilityOrDefault(visibility))
+
+	extended, err := s.store.ExtendQueueLease(
+		ctx, messageID, agentID, expiresAt,
+	)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to extend lease: %w", err)
+	}
+	if !extended {
+		return time.Time{}, fmt.Errorf("%w: message %d",
+			ErrLeaseNotHeld, messageID)
+	}
+
+	return expiresAt, nil
+}
+
+// ReapQueueLeases archives the copies held under expired leases, returns
+// the expired leases with attempts left to the queue for redelivery, and
+// moves messages that have used up their delivery attempts to their
+// dead-letter topics. The number of dead-lettered messages is returned.
+func (s *Service) ReapQueueLeases(ctx context.Context, now time.Time) (int,
+	error) {
+
+	var (
+		outbox      []ThreadOutboxEvent
+		deadLetters []deadLetter
+	)
+	err := s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		deadLetters = nil
+
+		var err error
+		outbox, err = s.releaseExpiredLeases(ctx, txStore, now)
+		if err != nil {
+			return err
+		}
+
+		exhausted, err := txStore.ListExhaustedQueueDeliveries(
+			ctx, now, s.queueMaxDeliveries,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to list exhausted "+
+				"deliveries: %w", err)
+		}
+
+		for _, delivery := range exhausted {
+			dl, err := deadLetterMessage(ctx, txStore, delivery)
+			if err != nil {
+				return err
+			}
+			deadLetters = append(deadLetters, dl)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.dispatchOutbox(ctx, outbox)
+	s.notifyDeadLetters(ctx, deadLetters)
+
+	return len(deadLetters), nil
+}
+
+// RunQueueReaper reaps queue leases on every interval until the context is
+// cancelled.
+func (s *Service) RunQueueReaper(ctx context.Context, interval time.Duration,
+	log *slog.Logger,
+) {
+	if interval <= 0 {
+		interval = DefaultQueueReapInterval
+	}
+	if log == nil {
+		log = slog.Default()
+	}
+	log = log.With("component", "queue-reaper")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case now := <-ticker.C:
+			numDead, err := s.ReapQueueLeases(ctx, now)
+			if err != nil {
+				log.ErrorContext(ctx, "Failed to reap queue leases",
+					"error", err)
+				continue
+			}
+			if numDead > 0 {
+				log.InfoContext(ctx, "Moved queue messages to "+
+					"dead-letter topics", "count", numDead)
+			}
+		}
+	}
+}
+
+// deadLetter records a message copied to a dead-letter topic so its
+// subscribers can be notified once the transaction commits.
+type deadLetter struct {
+	messageID   int64
+	subscribers []int64
+}
+
+// deadLetterMessage copies an exhausted queue message into its dead-letter
+// topic within the given transaction, delivering it to every subscriber of
+// that topic, and marks the original as dead-lettered. The copy stays in the
+// original thread so the failure history remains attached to it.
+func deadLetterMessage(ctx context.Context, txStore store.Storage,
+	delivery store.QueueDelivery,
+) (deadLetter, error) {
+	var dl deadLetter
+
+	msg, err := txStore.GetMessage(ctx, delivery.MessageID)
+	if err != nil {
+		return dl, fmt.Errorf("failed to get message: %w", err)
+	}
+
+	dlqTopic, err := txStore.GetOrCreateTopic(
+		ctx, DeadLetterTopicName(delivery.TopicName),
+		TopicTypeBroadcast,
+	)
+	if err != nil {
+		return dl, fmt.Errorf("failed to get dead-letter topic: %w", err)
+	}
+
+	logOffset, err := txStore.NextLogOffset(ctx, dlqTopic.ID)
+	if err != nil {
+		return dl, fmt.Errorf("failed to get next offset: %w", err)
+	}
+
+	dlqMsg, err := txStore.CreateMessage(ctx, store.CreateMessageParams{
+		ThreadID:   msg.ThreadID,
+		TopicID:    dlqTopic.ID,
+		LogOffset:  logOffset,
+		SenderID:   msg.SenderID,
+		Subject:    msg.Subject,
+		Body:       msg.Body,
+		Priority:   msg.Priority,
+		DeadlineAt: msg.DeadlineAt,
+	})
+	if err != nil {
+		return dl, fmt.Errorf("failed to create dead-letter message: %w",
+			err)
+	}
+	dl.messageID = dlqMsg.ID
+
+	subscribers, err := txStore.ListSubscriptionsByTopic(ctx, dlqTopic.ID)
+	if err != nil {
+		return dl, fmt.Errorf("failed to get subscribers: %w", err)
+	}
+	for _, sub := range subscribers {
+		err := txStore.CreateMessageRecipient(ctx, dlqMsg.ID, sub.ID)
+		if err != nil {
+			return dl, fmt.Errorf("failed to create recipient "+
+				"entry: %w", err)
+		}
+		dl.subscribers = append(dl.subscribers, sub.ID)
+	}
+
+	err = txStore.MarkQueueDeliveryDeadLettered(ctx, delivery.MessageID)
+	if err != nil {
+		return dl, fmt.Errorf("failed to mark dead-lettered: %w", err)
+	}
+
+	return dl, nil
+}
+
+// notifyDeadLetters pushes dead-lettered messages to the subscribers of their
+// dead-letter topics.
+func (s *Service) notifyDeadLetters(ctx context.Context,
+	deadLetters []deadLetter,
+) {
+	for _, dl := range deadLetters {
+		pushInboxMessage(
+			ctx, s.notifHub, s.store, dl.messageID,
+			StateUnreadStr.String(), dl.subscribers...,
+		)
+	}
+}
+
+// isSubscribed reports whether the agent is subscribed to the topic.
+func isSubscribed(ctx context.Context, txStore store.Storage, topicID,
+	agentID int64,
+) (bool, error) {
+	subscribers, err := txStore.ListSubscriptionsByTopic(ctx, topicID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get subscribers: %w", err)
+	}
+
+	for _, sub := range subscribers {
+		if sub.ID == agentID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,381 @@
+package mail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+// publishToQueue publishes a message to a queue topic and asserts that it was
+// queued rather than fanned out.
+func publishToQueue(t *testing.T, svc *Service, senderID int64,
+	topicName, subject string,
+) int64 {
+	t.Helper()
+
+	resp, err := svc.Publish(context.Background(), PublishRequest{
+		SenderID:  senderID,
+		TopicName: topicName,
+		Subject:   subject,
+		Body:      "Work item",
+		Priority:  PriorityNormal,
+	})
+	require.NoError(t, err)
+	require.True(t, resp.Queued)
+	require.Zero(t, resp.RecipientsCount)
+
+	return resp.MessageID
+}
+
+// TestQueueCompetingConsumers tests that each queue message is claimed by
+// exactly one subscriber and is completed by an ack.
+func TestQueueCompetingConsumers(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	producer := createTestAgent(t, storage, "Producer")
+	worker1 := createTestAgent(t, storage, "Worker1")
+	worker2 := createTestAgent(t, storage, "Worker2")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	for _, worker := range []store.Agent{worker1, worker2} {
+		_, err := svc.SubscribeTopic(
+			ctx, worker.ID, "jobs", TopicTypeQueue,
+		)
+		require.NoError(t, err)
+	}
+
+	firstID := publishToQueue(t, svc, producer.ID, "jobs", "First")
+	secondID := publishToQueue(t, svc, producer.ID, "jobs", "Second")
+
+	// Queue messages never show up through polling.
+	pollResp, err := svc.PollChanges(ctx, PollChangesRequest{
+		AgentID: worker1.ID,
+	})
+	require.NoError(t, err)
+	require.Empty(t, pollResp.NewMessages)
+
+	// Each worker claims a different message, oldest first.
+	claim1, err := svc.ClaimQueueMessage(ctx, worker1.ID, "jobs", 0)
+	require.NoError(t, err)
+	require.NotNil(t, claim1)
+	require.Equal(t, firstID, claim1.Message.ID)
+	require.Equal(t, "Producer", claim1.Message.SenderName)
+	require.Equal(t, 1, claim1.DeliveryCount)
+	require.True(t, claim1.LeaseExpiresAt.After(time.Now()))
+
+	claim2, err := svc.ClaimQueueMessage(ctx, worker2.ID, "jobs", 0)
+	require.NoError(t, err)
+	require.NotNil(t, claim2)
+	require.Equal(t, secondID, claim2.Message.ID)
+
+	claim, err := svc.ClaimQueueMessage(ctx, worker1.ID, "jobs", 0)
+	require.NoError(t, err)
+	require.Nil(t, claim)
+
+	// The claimed message lands in the claimant's inbox only.
+	_, err = storage.GetMessageRecipient(ctx, firstID, worker1.ID)
+	require.NoError(t, err)
+	_, err = storage.GetMessageRecipient(ctx, firstID, worker2.ID)
+	require.Error(t, err)
+
+	// Only the lease holder may ack.
+	err = svc.AckQueueMessage(ctx, worker2.ID, firstID)
+	require.ErrorIs(t, err, ErrLeaseNotHeld)
+
+	require.NoError(t, svc.AckQueueMessage(ctx, worker1.ID, firstID))
+
+	delivery, err := storage.GetQueueDelivery(ctx, firstID)
+	require.NoError(t, err)
+	require.Equal(t, "acked", delivery.State)
+
+	recip, err := storage.GetMessageRecipient(ctx, firstID, worker1.ID)
+	require.NoError(t, err)
+	require.NotNil(t, recip.AckedAt)
+
+	// A plain ack completes the lease too.
+	require.NoError(t, svc.AckMessage(ctx, worker2.ID, secondID))
+
+	delivery, err = storage.GetQueueDelivery(ctx, secondID)
+	require.NoError(t, err)
+	require.Equal(t, "acked", delivery.State)
+
+	// Acked messages are never redelivered.
+	_, err = svc.ReapQueueLeases(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	claim, err = svc.ClaimQueueMessage(ctx, worker1.ID, "jobs", 0)
+	require.NoError(t, err)
+	require.Nil(t, claim)
+}
+
+// TestQueueClaimErrors tests that claims are rejected for broadcast topics and
+// for agents that aren't subscribed to the queue.
+func TestQueueClaimErrors(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	agent := createTestAgent(t, storage, "Agent")
+	createTestTopic(t, storage, "news", TopicTypeBroadcast)
+	createTestTopic(t, storage, "jobs", TopicTypeQueue)
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	_, err := svc.ClaimQueueMessage(ctx, agent.ID, "news", 0)
+	require.ErrorIs(t, err, ErrNotQueueTopic)
+
+	_, err = svc.ClaimQueueMessage(ctx, agent.ID, "jobs", 0)
+	require.ErrorIs(t, err, ErrNotSubscribed)
+
+	_, err = svc.ClaimQueueMessage(ctx, agent.ID, "missing", 0)
+	require.ErrorIs(t, err, ErrTopicNotFound)
+}
+
+// TestQueueRedeliveryAndDeadLetter tests that expired and released leases are
+// redelivered, and that a message is moved to the dead-letter topic once it
+// has used up its delivery attempts.
+func TestQueueRedeliveryAndDeadLetter(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	producer := createTestAgent(t, storage, "Producer")
+	worker1 := createTestAgent(t, storage, "Worker1")
+	worker2 := createTestAgent(t, storage, "Worker2")
+	operator := createTestAgent(t, storage, "Operator")
+
+	svc := NewService(ServiceConfig{
+		Store:              storage,
+		QueueMaxDeliveries: 3,
+	})
+	defer svc.OnStop(ctx)
+
+	for _, worker := range []store.Agent{worker1, worker2} {
+		_, err := svc.SubscribeTopic(
+			ctx, worker.ID, "jobs", TopicTypeQueue,
+		)
+		require.NoError(t, err)
+	}
+	_, err := svc.Subscribe(
+		ctx, operator.ID, DeadLetterTopicName("jobs"),
+	)
+	require.NoError(t, err)
+
+	msgID := publishToQueue(t, svc, producer.ID, "jobs", "Flaky")
+
+	// The first lease expires without an ack, so the reaper hands the
+	// message back to the queue for someone else.
+	claim, err := svc.ClaimQueueMessage(
+		ctx, worker1.ID, "jobs", time.Minute,
+	)
+	require.NoError(t, err)
+	require.NotNil(t, claim)
+
+	numDead, err := svc.ReapQueueLeases(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, numDead)
+
+	delivery, err := storage.GetQueueDelivery(ctx, msgID)
+	require.NoError(t, err)
+	require.Equal(t, "pending", delivery.State)
+	require.Equal(t, "lease expired", delivery.LastError)
+
+	// The old lease holder's copy is archived, and it can no longer ack
+	// or extend.
+	err = svc.AckQueueMessage(ctx, worker1.ID, msgID)
+	require.ErrorIs(t, err, ErrLeaseNotHeld)
+	_, err = svc.ExtendQueueLease(ctx, worker1.ID, msgID, time.Minute)
+	require.ErrorIs(t, err, ErrLeaseNotHeld)
+
+	recip, err := storage.GetMessageRecipient(ctx, msgID, worker1.ID)
+	require.NoError(t, err)
+	require.Equal(t, StateArchivedStr.String(), recip.State)
+	require.Nil(t, recip.AckedAt)
+
+	// The second worker claims it, extends the lease, then gives up on
+	// it explicitly, archiving its copy.
+	claim, err = svc.ClaimQueueMessage(ctx, worker2.ID, "jobs", 0)
+	require.NoError(t, err)
+	require.NotNil(t, claim)
+	require.Equal(t, 2, claim.DeliveryCount)
+
+	expiresAt, err := svc.ExtendQueueLease(
+		ctx, worker2.ID, msgID, time.Hour,
+	)
+	require.NoError(t, err)
+	require.True(t, expiresAt.After(claim.LeaseExpiresAt))
+
+	err = svc.ReleaseQueueMessage(ctx, worker2.ID, msgID, "bad input")
+	require.NoError(t, err)
+
+	recip, err = storage.GetMessageRecipient(ctx, msgID, worker2.ID)
+	require.NoError(t, err)
+	require.Equal(t, StateArchivedStr.String(), recip.State)
+
+	// The third and final delivery goes back to the second worker, whose
+	// archived copy is brought back to the inbox. Releasing it again
+	// exhausts the message, which is dead-lettered right away.
+	claim, err = svc.ClaimQueueMessage(ctx, worker2.ID, "jobs", 0)
+	require.NoError(t, err)
+	require.NotNil(t, claim)
+	require.Equal(t, 3, claim.DeliveryCount)
+
+	recip, err = storage.GetMessageRecipient(ctx, msgID, worker2.ID)
+	require.NoError(t, err)
+	require.Equal(t, StateUnreadStr.String(), recip.State)
+
+	err = svc.ReleaseQueueMessage(ctx, worker2.ID, msgID, "bad input")
+	require.NoError(t, err)
+
+	delivery, err = storage.GetQueueDelivery(ctx, msgID)
+	require.NoError(t, err)
+	require.Equal(t, "dead_lettered", delivery.State)
+
+	claim, err = svc.ClaimQueueMessage(ctx, worker1.ID, "jobs", 0)
+	require.NoError(t, err)
+	require.Nil(t, claim)
+
+	// The operator watching the dead-letter topic received a copy in
+	// the same thread.
+	inbox, err := svc.FetchInbox(ctx, FetchInboxRequest{
+		AgentID: operator.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, inbox, 1)
+	require.Equal(t, "Flaky", inbox[0].Subject)
+
+	orig, err := storage.GetMessage(ctx, msgID)
+	require.NoError(t, err)
+	require.Equal(t, orig.ThreadID, inbox[0].ThreadID)
+}
+
+// TestQueueReclaimArchivesStaleCopy tests that when an expired lease is
+// claimed by another consumer, the stale holder's copy is archived and its
+// late ack neither succeeds nor acks the copy.
+func TestQueueReclaimArchivesStaleCopy(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	producer := createTestAgent(t, storage, "Producer")
+	worker1 := createTestAgent(t, storage, "Worker1")
+	worker2 := createTestAgent(t, storage, "Worker2")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	for _, worker := range []store.Agent{worker1, worker2} {
+		_, err := svc.SubscribeTopic(
+			ctx, worker.ID, "jobs", TopicTypeQueue,
+		)
+		require.NoError(t, err)
+	}
+
+	msgID := publishToQueue(t, svc, producer.ID, "jobs", "Stale")
+
+	// The first lease expires as soon as it's granted.
+	claim, err := svc.ClaimQueueMessage(
+		ctx, worker1.ID, "jobs", time.Nanosecond,
+	)
+	require.NoError(t, err)
+	require.NotNil(t, claim)
+
+	claim, err = svc.ClaimQueueMessage(ctx, worker2.ID, "jobs", 0)
+	require.NoError(t, err)
+	require.NotNil(t, claim)
+	require.Equal(t, msgID, claim.Message.ID)
+	require.Equal(t, 2, claim.DeliveryCount)
+
+	recip, err := storage.GetMessageRecipient(ctx, msgID, worker1.ID)
+	require.NoError(t, err)
+	require.Equal(t, StateArchivedStr.String(), recip.State)
+
+	err = svc.AckQueueMessage(ctx, worker1.ID, msgID)
+	require.ErrorIs(t, err, ErrLeaseNotHeld)
+
+	recip, err = storage.GetMessageRecipient(ctx, msgID, worker1.ID)
+	require.NoError(t, err)
+	require.Nil(t, recip.AckedAt)
+
+	// The new holder acks its copy along with the lease.
+	require.NoError(t, svc.AckQueueMessage(ctx, worker2.ID, msgID))
+
+	delivery, err := storage.GetQueueDelivery(ctx, msgID)
+	require.NoError(t, err)
+	require.Equal(t, "acked", delivery.State)
+
+	recip, err = storage.GetMessageRecipient(ctx, msgID, worker2.ID)
+	require.NoError(t, err)
+	require.NotNil(t, recip.AckedAt)
+}
+
+// TestQueueReaperDeadLettersExpiredLeases tests that the reaper dead-letters
+// a message whose final lease expired without an ack.
+func TestQueueReaperDeadLettersExpiredLeases(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	producer := createTestAgent(t, storage, "Producer")
+	worker := createTestAgent(t, storage, "Worker")
+
+	svc := NewService(ServiceConfig{
+		Store:              storage,
+		QueueMaxDeliveries: 1,
+	})
+	defer svc.OnStop(ctx)
+
+	_, err := svc.SubscribeTopic(ctx, worker.ID, "jobs", TopicTypeQueue)
+	require.NoError(t, err)
+
+	msgID := publishToQueue(t, svc, producer.ID, "jobs", "Slow")
+
+	claim, err := svc.ClaimQueueMessage(ctx, worker.ID, "jobs", 0)
+	require.NoError(t, err)
+	require.NotNil(t, claim)
+
+	// Nothing happens while the lease is still live.
+	numDead, err := svc.ReapQueueLeases(ctx, time.Now())
+	require.NoError(t, err)
+	require.Zero(t, numDead)
+
+	numDead, err = svc.ReapQueueLeases(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, numDead)
+
+	delivery, err := storage.GetQueueDelivery(ctx, msgID)
+	require.NoError(t, err)
+	require.Equal(t, "dead_lettered", delivery.State)
+
+	dlq, err := storage.GetTopicByName(ctx, DeadLetterTopicName("jobs"))
+	require.NoError(t, err)
+	require.Equal(t, TopicTypeBroadcast, dlq.TopicType)
+
+	msgs, err := storage.GetMessagesSinceOffset(ctx, dlq.ID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	require.Equal(t, "Slow", msgs[0].Subject)
+
+	// Reaping again is a no-op.
+	numDead, err = svc.ReapQueueLeases(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, numDead)
+}
@@ -0,0 +1,269 @@
+package mail
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+// transitionFunc applies one or more events to a recipient's thread FSM and
+// returns the resulting outbox events.
+type transitionFunc func(ctx context.Context,
+	fsm *ThreadFSM) ([]ThreadOutboxEvent, error)
+
+// transition loads the thread FSM for a recipient, applies fn to it and
+// persists the resulting state within a single transaction. Outbox events
+// with effects outside the database are dispatched once the transaction has
+// committed.
+func (s *Service) transition(ctx context.Context, agentID, messageID int64,
+	fn transitionFunc,
+) error {
+	var outbox []ThreadOutboxEvent
+	err := s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		recipient, err := txStore.GetMessageRecipient(
+			ctx, messageID, agentID,
+		)
+		if err != nil {
+			return err
+		}
+
+		msg, err := txStore.GetMessage(ctx, messageID)
+		if err != nil {
+			return err
+		}
+
+		_, outbox, err = s.runTransition(
+			ctx, txStore, recipient, msg.ThreadID, fn,
+		)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.dispatchOutbox(ctx, outbox)
+
+	return nil
+}
+
+// runTransition rebuilds the thread FSM from the recipient row, applies fn
+// and persists every PersistStateChange it emits using the given store. The
+// updated recipient row is returned along with the full set of outbox events.
+func (s *Service) runTransition(ctx context.Context, txStore store.Storage,
+	recipient store.MessageRecipient, threadID string, fn transitionFunc,
+) (store.MessageRecipient, []ThreadOutboxEvent, error) {
+	fsm := s.threadFSMs.LoadFSM(
+		recipient.AgentID, recipient.MessageID, threadID,
+		recipient.State, recipient.SnoozedUntil, recipient.ReadAt,
+		recipient.AckedAt,
+	)
+
+	outbox, err := fn(ctx, fsm)
+	if err != nil {
+		return recipient, nil, err
+	}
+
+	// Fold all state changes into the recipient row so multi-step
+	// transitions result in a single write.
+	var changed bool
+	for _, event := range outbox {
+		persist, ok := event.(PersistStateChange)
+		if !ok {
+			continue
+		}
+		recipient = applyStateChange(recipient, persist)
+		changed = true
+	}
+	if !changed {
+		return recipient, outbox, nil
+	}
+
+	err = txStore.PersistRecipientState(
+		ctx, store.PersistRecipientStateParams{
+			MessageID:    recipient.MessageID,
+			AgentID:      recipient.AgentID,
+			State:        recipient.State,
+			ReadAt:       recipient.ReadAt,
+			AckedAt:      recipient.AckedAt,
+			SnoozedUntil: recipient.SnoozedUntil,
+		},
+	)
+	if err != nil {
+		return recipient, nil, fmt.Errorf("failed to persist state: %w",
+			err)
+	}
+
+	return recipient, outbox, nil
+}
+
+// applyStateChange merges a PersistStateChange into a recipient row. Nil
+// timestamps leave the existing value untouched, except for snoozed_until
+// which is cleared whenever the recipient leaves the snoozed state.
+func applyStateChange(r store.MessageRecipient,
+	change PersistStateChange,
+) store.MessageRecipient {
+	r.State = change.NewState
+	if change.ReadAt != nil {
+		r.ReadAt = change.ReadAt
+	}
+	if change.AckedAt != nil {
+		r.AckedAt = change.AckedAt
+	}
+
+	switch {
+	case change.NewState != StateSnoozedStr.String():
+		r.SnoozedUntil = nil
+
+	case change.SnoozedUntil != nil:
+		r.SnoozedUntil = change.SnoozedUntil
+	}
+
+	return r
+}
+
+// dispatchOutbox executes the outbox events that have effects outside the
+// database. State changes have already been persisted by runTransition, and
+// purging trashed messages is left to retention.
+func (s *Service) dispatchOutbox(ctx context.Context,
+	outbox []ThreadOutboxEvent,
+) {
+	for _, event := range outbox {
+		switch e := event.(type) {
+		case ScheduleWake:
+			s.wakes.Schedule(e.AgentID, e.MessageID, e.WakeAt)
+
+		case CancelScheduledWake:
+			s.wakes.Cancel(e.AgentID, e.MessageID)
+
+		case NotifyStateChange:
+			// A woken message is effectively new mail again, so
+			// push it to any watchers of the recipient's inbox.
+			// Other changes are only of interest to views of the
+			// inbox, such as its unread counts.
+			if e.OldState == StateSnoozedStr.String() &&
+				e.NewState == StateUnreadStr.String() {
+
+				s.notifyWoken(ctx, e.AgentID, e.MessageID)
+			} else if s.notifHub != nil {
+				s.notifHub.Tell(ctx, NotifyStateChangeMsg{
+					Change: e,
+				})
+			}
+		}
+	}
+}
+
+// notifyWoken pushes a message that has returned to the unread state to the
+// recipient's subscribers and to the global inbox view.
+func (s *Service) notifyWoken(ctx context.Context, agentID, messageID int64) {
+	pushInboxMessage(
+		ctx, s.notifHub, s.store, messageID, StateUnreadStr.String(),
+		agentID,
+	)
+}
+
+// pushInboxMessage loads a message along with its sender and pushes it
+// through the notification hub to each of the given agents' subscribers and
+// to the global inbox view, as if it had just been delivered. It's marked as
+// repushed, since the message isn't new.
+func pushInboxMessage(ctx context.Context, hub NotificationActorRef,
+	s store.Storage, messageID int64, state string, agentIDs ...int64,
+) {
+	if hub == nil || len(agentIDs) == 0 {
+		return
+	}
+
+	msg, err := s.GetMessage(ctx, messageID)
+	if err != nil {
+		return
+	}
+
+	notifMsg := storeMessageToMail(msg)
+	notifMsg.State = state
+	notifMsg.Repushed = true
+	if sender, err := s.GetAgent(ctx, msg.SenderID); err == nil {
+		notifMsg.SenderName = sender.Name
+		notifMsg.SenderProjectKey = sender.ProjectKey
+		notifMsg.SenderGitBranch = sender.GitBranch
+	}
+
+	for _, agentID := range agentIDs {
+		hub.Tell(ctx, NotifyAgentMsg{
+			AgentID: agentID,
+			Message: notifMsg,
+		})
+	}
+	hub.Tell(ctx, NotifyAgentMsg{
+		AgentID: 0,
+		Message: notifMsg,
+	})
+}
+
+// WakeMessage returns a snoozed message to the unread state once its snooze
+// has expired. It is called by the wake scheduler, and is a no-op if the
+// message has since left the snoozed state. If the snooze was extended, the
+// wake is re-armed for the new time instead.
+func (s *Service) WakeMessage(ctx context.Context, agentID,
+	messageID int64,
+) error {
+	err := s.transition(
+		ctx, agentID, messageID,
+		func(ctx context.Context,
+			fsm *ThreadFSM) ([]ThreadOutboxEvent, error) {
+
+			snoozed, ok := fsm.State().(*StateSnoozed)
+			if !ok {
+				return nil, nil
+			}
+			if time.Now().Before(snoozed.SnoozedUntil) {
+				return fsm.Resume(ctx)
+			}
+
+			return s.threadFSMs.Wake(ctx, fsm)
+		},
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	return err
+}
+
+// RecoverSnoozedMessages re-arms the wake timers for all snoozed messages
+// using the snooze times persisted in the database. This should be called on
+// startup, messages whose snooze expired while the daemon was down are woken
+// immediately. It returns the number of wakes scheduled.
+func (s *Service) RecoverSnoozedMessages(ctx context.Context) (int, error) {
+	recipients, err := s.store.ListSnoozedRecipients(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list snoozed messages: %w", err)
+	}
+
+	for _, r := range recipients {
+		fsm := s.threadFSMs.LoadFSM(
+			r.AgentID, r.MessageID, r.ThreadID, r.State,
+			r.SnoozedUntil, r.ReadAt, r.AckedAt,
+		)
+
+		outbox, err := fsm.Resume(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to resume message %d: %w",
+				r.MessageID, err)
+		}
+		s.dispatchOutbox(ctx, outbox)
+	}
+
+	return len(recipients), nil
+}
+
+// OnStop implements actor.Stoppable and cancels all pending wake timers.
+func (s *Service) OnStop(ctx context.Context) error {
+	s.wakes.Stop()
+	return nil
+}
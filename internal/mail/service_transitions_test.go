@@ -0,0 +1,291 @@
+package mail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/baselib/actor"
+	"github.com/stretchr/testify/require"
+)
+
+// TestServiceSnoozeWakesMessage tests that a snoozed message returns to the
+// unread state once its snooze expires and is pushed to inbox watchers.
+func TestServiceSnoozeWakesMessage(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	recipient := createTestAgent(t, storage, "Recipient")
+
+	system := actor.NewActorSystem()
+	defer shutdownSystem(t, system)
+
+	notifHubRef := NotificationHubKey.Spawn(
+		system, "test-notif-hub", NewNotificationHub(),
+	)
+
+	svc := NewService(ServiceConfig{
+		Store:           storage,
+		NotificationHub: notifHubRef,
+	})
+	defer svc.OnStop(ctx)
+
+	resp, err := svc.Send(ctx, SendMailRequest{
+		SenderID:       sender.ID,
+		RecipientNames: []string{recipient.Name},
+		Subject:        "Snooze me",
+		Body:           "Wake me up later",
+		Priority:       PriorityNormal,
+	})
+	require.NoError(t, err)
+
+	// Only subscribe after sending so the only delivery we see is the
+	// wake.
+	deliveryChan := make(chan InboxMessage, 10)
+	_, err = notifHubRef.Ask(ctx, SubscribeAgentMsg{
+		AgentID:      recipient.ID,
+		SubscriberID: "test-sub",
+		DeliveryChan: deliveryChan,
+	}).Await(ctx).Unpack()
+	require.NoError(t, err)
+
+	until := time.Now().Add(200 * time.Millisecond)
+	err = svc.UpdateState(ctx, UpdateStateRequest{
+		AgentID:      recipient.ID,
+		MessageID:    resp.MessageID,
+		NewState:     StateSnoozedStr.String(),
+		SnoozedUntil: &until,
+	})
+	require.NoError(t, err)
+
+	recip, err := storage.GetMessageRecipient(
+		ctx, resp.MessageID, recipient.ID,
+	)
+	require.NoError(t, err)
+	require.Equal(t, StateSnoozedStr.String(), recip.State)
+	require.NotNil(t, recip.SnoozedUntil)
+
+	select {
+	case msg := <-deliveryChan:
+		require.Equal(t, resp.MessageID, msg.ID)
+		require.Equal(t, "Sender", msg.SenderName)
+		require.Equal(t, StateUnreadStr.String(), msg.State)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout waiting for woken message")
+	}
+
+	recip, err = storage.GetMessageRecipient(
+		ctx, resp.MessageID, recipient.ID,
+	)
+	require.NoError(t, err)
+	require.Equal(t, StateUnreadStr.String(), recip.State)
+	require.Nil(t, recip.SnoozedUntil)
+}
+
+// TestServiceRecoverSnoozedMessages tests that snoozes persisted by one
+// service instance are re-armed by a fresh instance, as after a daemon
+// restart, and that snoozes which expired in the meantime wake immediately.
+func TestServiceRecoverSnoozedMessages(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	recipient := createTestAgent(t, storage, "Recipient")
+
+	// Snooze two messages with the first service, then stop it before
+	// either wake fires.
+	oldSvc := NewServiceWithStore(storage)
+
+	snooze := func(until time.Time) int64 {
+		resp, err := oldSvc.Send(ctx, SendMailRequest{
+			SenderID:       sender.ID,
+			RecipientNames: []string{recipient.Name},
+			Subject:        "Snoozed",
+			Body:           "Body",
+			Priority:       PriorityNormal,
+		})
+		require.NoError(t, err)
+
+		err = oldSvc.UpdateState(ctx, UpdateStateRequest{
+			AgentID:      recipient.ID,
+			MessageID:    resp.MessageID,
+			NewState:     StateSnoozedStr.String(),
+			SnoozedUntil: &until,
+		})
+		require.NoError(t, err)
+
+		return resp.MessageID
+	}
+
+	expiredID := snooze(time.Now().Add(time.Hour))
+	pendingID := snooze(time.Now().Add(time.Hour))
+	require.NoError(t, oldSvc.OnStop(ctx))
+
+	// Simulate the first snooze expiring while the daemon was down.
+	past := time.Now().Add(-time.Minute)
+	require.NoError(t, storage.SnoozeMessage(
+		ctx, expiredID, recipient.ID, past,
+	))
+
+	newSvc := NewServiceWithStore(storage)
+	defer newSvc.OnStop(ctx)
+
+	numRecovered, err := newSvc.RecoverSnoozedMessages(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, numRecovered)
+
+	require.Eventually(t, func() bool {
+		recip, err := storage.GetMessageRecipient(
+			ctx, expiredID, recipient.ID,
+		)
+		require.NoError(t, err)
+		return recip.State == StateUnreadStr.String()
+	}, 3*time.Second, 20*time.Millisecond)
+
+	// The other message is still snoozed with its wake pending.
+	recip, err := storage.GetMessageRecipient(ctx, pendingID, recipient.ID)
+	require.NoError(t, err)
+	require.Equal(t, StateSnoozedStr.String(), recip.State)
+	require.Equal(t, 1, newSvc.wakes.Pending())
+}
+
+// TestServiceStateChangesCancelWake tests that moving a snoozed message to
+// another state cancels its wake, and that reads and acks persist their
+// timestamps through the FSM.
+func TestServiceStateChangesCancelWake(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	recipient := createTestAgent(t, storage, "Recipient")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	resp, err := svc.Send(ctx, SendMailRequest{
+		SenderID:       sender.ID,
+		RecipientNames: []string{recipient.Name},
+		Subject:        "Test",
+		Body:           "Body",
+		Priority:       PriorityNormal,
+	})
+	require.NoError(t, err)
+
+	until := time.Now().Add(time.Hour)
+	err = svc.UpdateState(ctx, UpdateStateRequest{
+		AgentID:      recipient.ID,
+		MessageID:    resp.MessageID,
+		NewState:     StateSnoozedStr.String(),
+		SnoozedUntil: &until,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, svc.wakes.Pending())
+
+	// Archiving drops the pending wake and the snooze time.
+	err = svc.UpdateState(ctx, UpdateStateRequest{
+		AgentID:   recipient.ID,
+		MessageID: resp.MessageID,
+		NewState:  StateArchivedStr.String(),
+	})
+	require.NoError(t, err)
+	require.Zero(t, svc.wakes.Pending())
+
+	recip, err := storage.GetMessageRecipient(
+		ctx, resp.MessageID, recipient.ID,
+	)
+	require.NoError(t, err)
+	require.Equal(t, StateArchivedStr.String(), recip.State)
+	require.Nil(t, recip.SnoozedUntil)
+
+	// Marking the archived message unread brings it back to the inbox,
+	// and acking it implicitly reads it.
+	err = svc.UpdateState(ctx, UpdateStateRequest{
+		AgentID:   recipient.ID,
+		MessageID: resp.MessageID,
+		NewState:  StateUnreadStr.String(),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.AckMessage(ctx, recipient.ID, resp.MessageID))
+
+	recip, err = storage.GetMessageRecipient(
+		ctx, resp.MessageID, recipient.ID,
+	)
+	require.NoError(t, err)
+	require.Equal(t, StateReadStr.String(), recip.State)
+	require.NotNil(t, recip.ReadAt)
+	require.NotNil(t, recip.AckedAt)
+}
+
+// TestServiceStateChangeNotifiesFirehose tests that changing the state of a
+// recipient's copy of a message is sent to firehose subscribers, which keep
+// views such as unread counts current.
+func TestServiceStateChangeNotifiesFirehose(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sender := createTestAgent(t, storage, "Sender")
+	recipient := createTestAgent(t, storage, "Recipient")
+
+	system := actor.NewActorSystem()
+	defer shutdownSystem(t, system)
+
+	notifHubRef := NotificationHubKey.Spawn(
+		system, "test-notif-hub", NewNotificationHub(),
+	)
+
+	svc := NewService(ServiceConfig{
+		Store:           storage,
+		NotificationHub: notifHubRef,
+	})
+	defer svc.OnStop(ctx)
+
+	resp, err := svc.Send(ctx, SendMailRequest{
+		SenderID:       sender.ID,
+		RecipientNames: []string{recipient.Name},
+		Subject:        "Archive me",
+		Body:           "Done with this",
+		Priority:       PriorityNormal,
+	})
+	require.NoError(t, err)
+
+	// Subscribe after sending so the only notification is the archive.
+	firehose := make(chan HubNotification, 10)
+	_, err = notifHubRef.Ask(ctx, SubscribeFirehoseMsg{
+		SubscriberID: "test-fire",
+		DeliveryChan: firehose,
+	}).Await(ctx).Unpack()
+	require.NoError(t, err)
+
+	err = svc.UpdateState(ctx, UpdateStateRequest{
+		AgentID:   recipient.ID,
+		MessageID: resp.MessageID,
+		NewState:  StateArchivedStr.String(),
+	})
+	require.NoError(t, err)
+
+	select {
+	case notif := <-firehose:
+		require.Equal(t, []int64{recipient.ID}, notif.AgentIDs)
+		require.NotNil(t, notif.StateChange)
+		require.Equal(t, resp.MessageID, notif.StateChange.MessageID)
+		require.Equal(t, StateArchivedStr.String(),
+			notif.StateChange.NewState)
+
+	case <-time.After(time.Second):
+		t.Fatal("state change not delivered to firehose")
+	}
+}
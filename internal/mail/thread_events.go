@@ -12,6 +12,7 @@ type ThreadEvent interface {
 
 // Ensure all event types implement ThreadEvent.
 func (ReadEvent) isThreadEvent()         {}
+func (MarkUnreadEvent) isThreadEvent()   {}
 func (StarEvent) isThreadEvent()         {}
 func (UnstarEvent) isThreadEvent()       {}
 func (SnoozeEvent) isThreadEvent()       {}
@@ -30,6 +31,13 @@ type ReadEvent struct {
 	ReadAt    time.Time
 }
 
+// MarkUnreadEvent returns a message to the unread state so it demands the
+// recipient's attention again.
+type MarkUnreadEvent struct {
+	AgentID   int64
+	MessageID int64
+}
+
 // StarEvent stars a message for the recipient.
 type StarEvent struct {
 	AgentID   int64
@@ -277,3 +277,152 @@ func (m *ThreadFSMManager) Ack(ctx context.Context,
 		AckedAt:   time.Now(),
 	})
 }
+
+// MarkUnread creates a MarkUnreadEvent and returns the outbox events.
+func (m *ThreadFSMManager) MarkUnread(ctx context.Context,
+	fsm *ThreadFSM,
+) ([]ThreadOutboxEvent, error) {
+	return fsm.ProcessEvent(ctx, MarkUnreadEvent{
+		AgentID:   fsm.env.AgentID,
+		MessageID: fsm.env.MessageID,
+	})
+}
+
+// maxTransitionSteps bounds the number of events TransitionTo will apply
+// while walking towards a target state. No path needs more than two today,
+// e.g. trash -> unread -> starred.
+const maxTransitionSteps = 3
+
+// TransitionTo drives the FSM to the target state by applying whichever
+// sequence of events gets it there, e.g. restoring a trashed message before
+// starring it. The outbox events of every step are returned in order. A
+// non-nil snoozedUntil is required when the target is the snoozed state.
+func (m *ThreadFSMManager) TransitionTo(ctx context.Context, fsm *ThreadFSM,
+	target RecipientState, snoozedUntil *time.Time,
+) ([]ThreadOutboxEvent, error) {
+	if !target.IsValid() {
+		return nil, fmt.Errorf("invalid state: %q", target)
+	}
+	if target == StateSnoozedStr && snoozedUntil == nil {
+		return nil, fmt.Errorf("snoozed_until required for snooze")
+	}
+
+	var outbox []ThreadOutboxEvent
+	for range maxTransitionSteps {
+		event := m.nextEvent(fsm, target, snoozedUntil)
+		if event == nil {
+			return outbox, nil
+		}
+
+		events, err := fsm.ProcessEvent(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+		outbox = append(outbox, events...)
+	}
+
+	return nil, fmt.Errorf("unable to transition from %s to %s",
+		fsm.StateString(), target)
+}
+
+// nextEvent returns the next event that moves the FSM closer to the target
+// state, or nil if the FSM has already reached it.
+func (m *ThreadFSMManager) nextEvent(fsm *ThreadFSM, target RecipientState,
+	snoozedUntil *time.Time,
+) ThreadEvent {
+	agentID, messageID := fsm.env.AgentID, fsm.env.MessageID
+
+	// Trashed messages must be restored before they can go anywhere
+	// else, and archived ones unarchived unless they're being read.
+	switch fsm.State().(type) {
+	case *StateTrash:
+		if target == StateTrashStr {
+			return nil
+		}
+		return RestoreEvent{AgentID: agentID, MessageID: messageID}
+
+	case *StateArchived:
+		switch target {
+		case StateArchivedStr:
+			return nil
+
+		case StateTrashStr:
+			return TrashEvent{AgentID: agentID, MessageID: messageID}
+
+		case StateUnreadStr:
+			return MarkUnreadEvent{
+				AgentID: agentID, MessageID: messageID,
+			}
+
+		default:
+			return UnarchiveEvent{
+				AgentID: agentID, MessageID: messageID,
+			}
+		}
+	}
+
+	switch target {
+	case StateUnreadStr:
+		switch fsm.State().(type) {
+		case *StateUnread:
+			return nil
+		case *StateSnoozed:
+			return WakeEvent{AgentID: agentID, MessageID: messageID}
+		default:
+			return MarkUnreadEvent{
+				AgentID: agentID, MessageID: messageID,
+			}
+		}
+
+	case StateReadStr:
+		switch fsm.State().(type) {
+		case *StateRead:
+			return nil
+		case *StateStarred:
+			return UnstarEvent{AgentID: agentID, MessageID: messageID}
+		default:
+			return ReadEvent{
+				AgentID:   agentID,
+				MessageID: messageID,
+				ReadAt:    time.Now(),
+			}
+		}
+
+	case StateStarredStr:
+		switch fsm.State().(type) {
+		case *StateStarred:
+			return nil
+		case *StateSnoozed:
+			return ReadEvent{
+				AgentID:   agentID,
+				MessageID: messageID,
+				ReadAt:    time.Now(),
+			}
+		default:
+			return StarEvent{AgentID: agentID, MessageID: messageID}
+		}
+
+	case StateSnoozedStr:
+		switch st := fsm.State().(type) {
+		case *StateSnoozed:
+			if st.SnoozedUntil.Equal(*snoozedUntil) {
+				return nil
+			}
+		case *StateStarred:
+			return UnstarEvent{AgentID: agentID, MessageID: messageID}
+		}
+		return SnoozeEvent{
+			AgentID:      agentID,
+			MessageID:    messageID,
+			SnoozedUntil: *snoozedUntil,
+		}
+
+	case StateArchivedStr:
+		return ArchiveEvent{AgentID: agentID, MessageID: messageID}
+
+	case StateTrashStr:
+		return TrashEvent{AgentID: agentID, MessageID: messageID}
+	}
+
+	return nil
+}
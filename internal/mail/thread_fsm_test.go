@@ -381,6 +381,151 @@ func TestThreadFSM_SnoozedToRead(t *testing.T) {
 	require.Equal(t, int64(100), cancelWake.MessageID)
 }
 
+func TestThreadFSM_MarkUnread(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manager := NewThreadFSMManager(DefaultTrashRetention)
+	fsm := manager.CreateFSM(1, 100, "thread-1")
+
+	_, err := manager.MarkRead(ctx, fsm)
+	require.NoError(t, err)
+
+	outbox, err := manager.MarkUnread(ctx, fsm)
+	require.NoError(t, err)
+	require.Equal(t, "unread", fsm.StateString())
+	require.Len(t, outbox, 2)
+
+	notify, ok := outbox[1].(NotifyStateChange)
+	require.True(t, ok)
+	require.Equal(t, "read", notify.OldState)
+	require.Equal(t, "unread", notify.NewState)
+
+	// Marking an unread message unread again is a no-op.
+	outbox, err = manager.MarkUnread(ctx, fsm)
+	require.NoError(t, err)
+	require.Empty(t, outbox)
+}
+
+func TestThreadFSM_SnoozedAckKeepsWake(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manager := NewThreadFSMManager(DefaultTrashRetention)
+	fsm := manager.CreateFSM(1, 100, "thread-1")
+
+	wakeTime := time.Now().Add(time.Hour)
+	_, err := manager.Snooze(ctx, fsm, wakeTime)
+	require.NoError(t, err)
+
+	outbox, err := manager.Ack(ctx, fsm)
+	require.NoError(t, err)
+	require.Equal(t, "snoozed", fsm.StateString())
+
+	// Only the ack is persisted, the snooze time is carried over and
+	// no wake is cancelled.
+	require.Len(t, outbox, 1)
+	persist, ok := outbox[0].(PersistStateChange)
+	require.True(t, ok)
+	require.NotNil(t, persist.AckedAt)
+	require.Equal(t, wakeTime, *persist.SnoozedUntil)
+}
+
+func TestThreadFSM_SnoozedToArchived(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manager := NewThreadFSMManager(DefaultTrashRetention)
+	fsm := manager.CreateFSM(1, 100, "thread-1")
+
+	_, err := manager.Snooze(ctx, fsm, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	outbox, err := manager.Archive(ctx, fsm)
+	require.NoError(t, err)
+	require.Equal(t, "archived", fsm.StateString())
+
+	_, ok := outbox[1].(CancelScheduledWake)
+	require.True(t, ok)
+}
+
+func TestThreadFSM_TransitionTo(t *testing.T) {
+	t.Parallel()
+
+	snoozeTime := time.Now().Add(time.Hour)
+	states := []RecipientState{
+		StateUnreadStr, StateReadStr, StateStarredStr,
+		StateSnoozedStr, StateArchivedStr, StateTrashStr,
+	}
+
+	// Every state must be reachable from every other state.
+	for _, from := range states {
+		for _, to := range states {
+			t.Run(from.String()+"->"+to.String(), func(t *testing.T) {
+				ctx := context.Background()
+				manager := NewThreadFSMManager(
+					DefaultTrashRetention,
+				)
+				fsm := manager.CreateFSM(1, 100, "thread-1")
+
+				_, err := manager.TransitionTo(
+					ctx, fsm, from, &snoozeTime,
+				)
+				require.NoError(t, err)
+				require.Equal(t, from.String(),
+					fsm.StateString())
+
+				_, err = manager.TransitionTo(
+					ctx, fsm, to, &snoozeTime,
+				)
+				require.NoError(t, err)
+				require.Equal(t, to.String(), fsm.StateString())
+			})
+		}
+	}
+}
+
+func TestThreadFSM_TransitionToMultiStep(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manager := NewThreadFSMManager(DefaultTrashRetention)
+	fsm := manager.CreateFSM(1, 100, "thread-1")
+
+	_, err := manager.Trash(ctx, fsm)
+	require.NoError(t, err)
+
+	// Starring a trashed message restores it first.
+	outbox, err := manager.TransitionTo(ctx, fsm, StateStarredStr, nil)
+	require.NoError(t, err)
+	require.Equal(t, "starred", fsm.StateString())
+
+	var persisted []string
+	for _, event := range outbox {
+		if persist, ok := event.(PersistStateChange); ok {
+			persisted = append(persisted, persist.NewState)
+		}
+	}
+	require.Equal(t, []string{"unread", "starred"}, persisted)
+
+	// Re-snoozing to a different time re-arms the wake.
+	first := time.Now().Add(time.Hour)
+	second := first.Add(time.Hour)
+	_, err = manager.TransitionTo(ctx, fsm, StateSnoozedStr, &first)
+	require.NoError(t, err)
+
+	outbox, err = manager.TransitionTo(ctx, fsm, StateSnoozedStr, &second)
+	require.NoError(t, err)
+	require.Contains(t, outbox, ThreadOutboxEvent(ScheduleWake{
+		AgentID:   1,
+		MessageID: 100,
+		WakeAt:    second,
+	}))
+
+	// Snoozing without a wake time is rejected.
+	_, err = manager.TransitionTo(ctx, fsm, StateSnoozedStr, nil)
+	require.Error(t, err)
+
+	_, err = manager.TransitionTo(ctx, fsm, "bogus", nil)
+	require.Error(t, err)
+}
+
 func TestStateFromString(t *testing.T) {
 	testCases := []struct {
 		input    string
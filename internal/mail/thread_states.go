@@ -198,6 +198,10 @@ func (s *StateUnread) ProcessEvent(ctx context.Context, event ThreadEvent,
 			},
 		}, nil
 
+	case MarkUnreadEvent:
+		// Already unread, no-op.
+		return &ThreadTransition{NextState: s}, nil
+
 	case ResumeThreadEvent:
 		// On resume, stay in unread state with no outbox events.
 		return &ThreadTransition{NextState: s}, nil
@@ -329,6 +333,25 @@ func (s *StateRead) ProcessEvent(ctx context.Context, event ThreadEvent,
 			},
 		}, nil
 
+	case MarkUnreadEvent:
+		return &ThreadTransition{
+			NextState: &StateUnread{},
+			OutboxEvents: []ThreadOutboxEvent{
+				PersistStateChange{
+					AgentID:   env.AgentID,
+					MessageID: env.MessageID,
+					NewState:  "unread",
+				},
+				NotifyStateChange{
+					AgentID:   env.AgentID,
+					MessageID: env.MessageID,
+					ThreadID:  env.ThreadID,
+					OldState:  "read",
+					NewState:  "unread",
+				},
+			},
+		}, nil
+
 	case ReadEvent:
 		// Already read, no-op.
 		return &ThreadTransition{NextState: s}, nil
@@ -437,6 +460,25 @@ func (s *StateStarred) ProcessEvent(ctx context.Context, event ThreadEvent,
 			},
 		}, nil
 
+	case MarkUnreadEvent:
+		return &ThreadTransition{
+			NextState: &StateUnread{},
+			OutboxEvents: []ThreadOutboxEvent{
+				PersistStateChange{
+					AgentID:   env.AgentID,
+					MessageID: env.MessageID,
+					NewState:  "unread",
+				},
+				NotifyStateChange{
+					AgentID:   env.AgentID,
+					MessageID: env.MessageID,
+					ThreadID:  env.ThreadID,
+					OldState:  "starred",
+					NewState:  "unread",
+				},
+			},
+		}, nil
+
 	case ReadEvent, StarEvent:
 		// Already starred/read, no-op.
 		return &ThreadTransition{NextState: s}, nil
@@ -572,6 +614,53 @@ func (s *StateSnoozed) ProcessEvent(ctx context.Context, event ThreadEvent,
 			},
 		}, nil
 
+	case ArchiveEvent:
+		// Archiving drops the pending wake along with the snooze.
+		return &ThreadTransition{
+			NextState: &StateArchived{
+				ReadAt:  s.ReadAt,
+				AckedAt: s.AckedAt,
+			},
+			OutboxEvents: []ThreadOutboxEvent{
+				PersistStateChange{
+					AgentID:   env.AgentID,
+					MessageID: env.MessageID,
+					NewState:  "archived",
+				},
+				CancelScheduledWake{
+					AgentID:   env.AgentID,
+					MessageID: env.MessageID,
+				},
+				NotifyStateChange{
+					AgentID:   env.AgentID,
+					MessageID: env.MessageID,
+					ThreadID:  env.ThreadID,
+					OldState:  "snoozed",
+					NewState:  "archived",
+				},
+			},
+		}, nil
+
+	case AckEvent:
+		// Acking doesn't disturb the snooze, the wake stays scheduled.
+		return &ThreadTransition{
+			NextState: &StateSnoozed{
+				SnoozedUntil: s.SnoozedUntil,
+				WasRead:      s.WasRead,
+				ReadAt:       s.ReadAt,
+				AckedAt:      &e.AckedAt,
+			},
+			OutboxEvents: []ThreadOutboxEvent{
+				PersistStateChange{
+					AgentID:      env.AgentID,
+					MessageID:    env.MessageID,
+					NewState:     "snoozed",
+					AckedAt:      &e.AckedAt,
+					SnoozedUntil: &s.SnoozedUntil,
+				},
+			},
+		}, nil
+
 	case ResumeThreadEvent:
 		// On resume, re-schedule the wake event.
 		return &ThreadTransition{
@@ -605,7 +694,7 @@ func (*StateArchived) String() string   { return "archived" }
 func (s *StateArchived) ProcessEvent(ctx context.Context, event ThreadEvent,
 	env *ThreadEnvironment,
 ) (*ThreadTransition, error) {
-	switch event.(type) {
+	switch e := event.(type) {
 	case UnarchiveEvent:
 		// Restore to read state.
 		return &ThreadTransition{
@@ -651,6 +740,42 @@ func (s *StateArchived) ProcessEvent(ctx context.Context, event ThreadEvent,
 			},
 		}, nil
 
+	case MarkUnreadEvent:
+		return &ThreadTransition{
+			NextState: &StateUnread{},
+			OutboxEvents: []ThreadOutboxEvent{
+				PersistStateChange{
+					AgentID:   env.AgentID,
+					MessageID: env.MessageID,
+					NewState:  "unread",
+				},
+				NotifyStateChange{
+					AgentID:   env.AgentID,
+					MessageID: env.MessageID,
+					ThreadID:  env.ThreadID,
+					OldState:  "archived",
+					NewState:  "unread",
+				},
+			},
+		}, nil
+
+	case AckEvent:
+		return &ThreadTransition{
+			NextState: &StateArchived{
+				ReadAt:     s.ReadAt,
+				AckedAt:    &e.AckedAt,
+				WasStarred: s.WasStarred,
+			},
+			OutboxEvents: []ThreadOutboxEvent{
+				PersistStateChange{
+					AgentID:   env.AgentID,
+					MessageID: env.MessageID,
+					NewState:  "archived",
+					AckedAt:   &e.AckedAt,
+				},
+			},
+		}, nil
+
 	case ResumeThreadEvent:
 		return &ThreadTransition{NextState: s}, nil
 
@@ -0,0 +1,411 @@
+package mail
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+// SetThreadMuted mutes or unmutes a thread for an agent. New replies to a
+// muted thread are delivered already read and aren't pushed to the agent's
+// live subscribers. Muting a thread stops following it.
+func (s *Service) SetThreadMuted(ctx context.Context, agentID int64,
+	threadID string, muted bool,
+) (store.ThreadPreference, error) {
+	return s.setThreadPreference(
+		ctx, agentID, threadID, func(pref *store.ThreadPreference) {
+			pref.Muted = muted
+			if muted {
+				pref.Following = false
+			}
+		},
+	)
+}
+
+// SetThreadFollowing follows or unfollows a thread for an agent. Replies to
+// a followed thread are delivered to the agent even when it isn't
+// addressed. Following a thread unmutes it.
+func (s *Service) SetThreadFollowing(ctx context.Context, agentID int64,
+	threadID string, following bool,
+) (store.ThreadPreference, error) {
+	return s.setThreadPreference(
+		ctx, agentID, threadID, func(pref *store.ThreadPreference) {
+			pref.Following = following
+			if following {
+				pref.Muted = false
+			}
+		},
+	)
+}
+
+// ListThreadPreferences lists the threads an agent muted or follows, most
+// recently changed first.
+func (s *Service) ListThreadPreferences(ctx context.Context,
+	agentID int64,
+) ([]store.ThreadPreference, error) {
+	prefs, err := s.store.ListThreadPreferences(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list thread preferences: %w",
+			err)
+	}
+
+	return prefs, nil
+}
+
+// ReceivedThreadMessages lists the IDs of the messages in a thread that an
+// agent received, oldest first, leaving out the ones it sent. Filing a
+// thread only moves these.
+func (s *Service) ReceivedThreadMessages(ctx context.Context, agentID int64,
+	threadID string,
+) ([]int64, error) {
+	msgs, err := s.ReadThread(ctx, agentID, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(msgs))
+	for i, msg := range msgs {
+		ids[i] = msg.ID
+	}
+
+	recipients, err := s.store.GetMessageRecipientsBulk(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recipients: %w", err)
+	}
+
+	var received []int64
+	for _, id := range ids {
+		for _, r := range recipients[id] {
+			if r.AgentID == agentID {
+				received = append(received, id)
+				break
+			}
+		}
+	}
+
+	return received, nil
+}
+
+// setThreadPreference applies a change to an agent's preferences for a
+// thread. An agent left with no preference for the thread has its row
+// removed.
+func (s *Service) setThreadPreference(ctx context.Context, agentID int64,
+	threadID string, update func(*store.ThreadPreference),
+) (store.ThreadPreference, error) {
+	var result store.ThreadPreference
+	err := s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		if err := requireThread(ctx, txStore, threadID); err != nil {
+			return err
+		}
+		if _, err := txStore.GetAgent(ctx, agentID); err != nil {
+			return fmt.Errorf("agent not found: %w", err)
+		}
+
+		pref, err := txStore.GetThreadPreference(ctx, threadID, agentID)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			pref = store.ThreadPreference{
+				ThreadID: threadID,
+				AgentID:  agentID,
+			}
+
+		case err != nil:
+			return fmt.Errorf("failed to get thread preference: %w",
+				err)
+		}
+
+		update(&pref)
+		if !pref.Muted && !pref.Following {
+			_, err := txStore.DeleteThreadPreference(
+				ctx, threadID, agentID,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to clear thread "+
+					"preference: %w", err)
+			}
+			result = pref
+
+			return nil
+		}
+
+		result, err = txStore.SetThreadPreference(ctx, pref)
+		if err != nil {
+			return fmt.Errorf("failed to set thread preference: %w",
+				err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return store.ThreadPreference{}, err
+	}
+
+	return result, nil
+}
+
+// MergeThreads moves every message of one thread into another, for when
+// agents accidentally forked a conversation. Questions asked in the merged
+// thread, messages scheduled into it and agents' preferences for it follow
+// the messages; where an agent set preferences on both threads, the ones on
+// the surviving thread win. It returns how many messages moved.
+func (s *Service) MergeThreads(ctx context.Context, fromThreadID,
+	intoThreadID string,
+) (int64, error) {
+	if fromThreadID == "" || intoThreadID == "" {
+		return 0, fmt.Errorf("%w: both threads are required",
+			ErrInvalidThreadOp)
+	}
+	if fromThreadID == intoThreadID {
+		return 0, fmt.Errorf("%w: can't merge a thread into itself",
+			ErrInvalidThreadOp)
+	}
+
+	var moved int64
+	err := s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		for _, threadID := range []string{fromThreadID, intoThreadID} {
+			err := requireMovableThread(ctx, txStore, threadID)
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		moved, err = txStore.MoveThreadMessages(
+			ctx, store.MoveThreadMessagesParams{
+				FromThreadID: fromThreadID,
+				ToThreadID:   intoThreadID,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to move messages: %w", err)
+		}
+
+		err = txStore.MoveScheduledThreadMessages(
+			ctx, fromThreadID, intoThreadID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to move scheduled messages: "+
+				"%w", err)
+		}
+
+		return moveThreadPreferences(
+			ctx, txStore, fromThreadID, intoThreadID,
+		)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return moved, nil
+}
+
+// SplitThreadResponse is the result of splitting a thread.
+type SplitThreadResponse struct {
+	// ThreadID is the new thread the messages moved to.
+	ThreadID string
+
+	// MessagesMoved is how many messages moved to the new thread.
+	MessagesMoved int64
+}
+
+// SplitThread moves a message and every later message in its thread into a
+// new thread, for when a conversation drifted onto another topic. Questions
+// asked by the moved messages move with them, and agents keep the
+// preferences they set on the original thread for the new one.
+func (s *Service) SplitThread(ctx context.Context,
+	messageID int64,
+) (SplitThreadResponse, error) {
+	var response SplitThreadResponse
+	err := s.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		msg, err := txStore.GetMessage(ctx, messageID)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("%w: %d", ErrMessageNotFound,
+				messageID)
+
+		case err != nil:
+			return fmt.Errorf("failed to get message: %w", err)
+		}
+
+		err = requireMovableThread(ctx, txStore, msg.ThreadID)
+		if err != nil {
+			return err
+		}
+
+		threadID := uuid.New().String()
+		moved, err := txStore.MoveThreadMessages(
+			ctx, store.MoveThreadMessagesParams{
+				FromThreadID: msg.ThreadID,
+				ToThreadID:   threadID,
+				MinMessageID: msg.ID,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to move messages: %w", err)
+		}
+
+		// Splitting at the first message would just rename the
+		// thread.
+		left, err := txStore.CountThreadMessages(ctx, msg.ThreadID)
+		if err != nil {
+			return fmt.Errorf("failed to count messages: %w", err)
+		}
+		if left == 0 {
+			return fmt.Errorf("%w: message %d starts its thread",
+				ErrInvalidThreadOp, msg.ID)
+		}
+
+		err = txStore.CopyThreadPreferences(ctx, msg.ThreadID, threadID)
+		if err != nil {
+			return fmt.Errorf("failed to copy thread preferences: "+
+				"%w", err)
+		}
+
+		response = SplitThreadResponse{
+			ThreadID:      threadID,
+			MessagesMoved: moved,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return SplitThreadResponse{}, err
+	}
+
+	return response, nil
+}
+
+// requireThread checks that a thread has messages.
+func requireThread(ctx context.Context, txStore store.Storage,
+	threadID string,
+) error {
+	count, err := txStore.CountThreadMessages(ctx, threadID)
+	if err != nil {
+		return fmt.Errorf("failed to count messages: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: %s", ErrThreadNotFound, threadID)
+	}
+
+	return nil
+}
+
+// requireMovableThread checks that a thread has messages that can be moved
+// to another thread. Reviews and plan reviews find their messages by thread
+// ID, so their threads must stay as they are.
+func requireMovableThread(ctx context.Context, txStore store.Storage,
+	threadID string,
+) error {
+	if err := requireThread(ctx, txStore, threadID); err != nil {
+		return err
+	}
+
+	reviews, err := txStore.CountThreadReviews(ctx, threadID)
+	if err != nil {
+		return fmt.Errorf("failed to count reviews: %w", err)
+	}
+	if reviews > 0 {
+		return fmt.Errorf("%w: %s", ErrThreadInUse, threadID)
+	}
+
+	return nil
+}
+
+// moveThreadPreferences moves agents' preferences from one thread to
+// another, keeping any they already set on the other thread.
+func moveThreadPreferences(ctx context.Context, txStore store.Storage,
+	fromThreadID, toThreadID string,
+) error {
+	err := txStore.CopyThreadPreferences(ctx, fromThreadID, toThreadID)
+	if err != nil {
+		return fmt.Errorf("failed to copy thread preferences: %w", err)
+	}
+
+	err = txStore.DeleteThreadPreferences(ctx, fromThreadID)
+	if err != nil {
+		return fmt.Errorf("failed to clear thread preferences: %w", err)
+	}
+
+	return nil
+}
+
+// threadFollowers returns the agents following a thread that a reply isn't
+// already addressed to, leaving out its sender.
+func threadFollowers(prefs []store.ThreadPreference, senderID int64,
+	recipientIDs []int64,
+) []int64 {
+	var followers []int64
+	for _, pref := range prefs {
+		if !pref.Following || pref.AgentID == senderID ||
+			slices.Contains(recipientIDs, pref.AgentID) {
+
+			continue
+		}
+
+		followers = append(followers, pref.AgentID)
+	}
+
+	return followers
+}
+
+// muteThreadRecipients marks a reply read for the recipients that muted its
+// thread, unless their inbox rules already filed it elsewhere, and returns
+// the recipients still to be notified of it, along with the outbox events of
+// marking it read, to be dispatched once the transaction commits.
+func (s *Service) muteThreadRecipients(ctx context.Context,
+	txStore store.Storage, messageID int64, threadID string,
+	prefs []store.ThreadPreference, notifyIDs []int64,
+) ([]int64, []ThreadOutboxEvent, error) {
+	muted := make(map[int64]bool)
+	for _, pref := range prefs {
+		if pref.Muted {
+			muted[pref.AgentID] = true
+		}
+	}
+	if len(muted) == 0 {
+		return notifyIDs, nil, nil
+	}
+
+	var outbox []ThreadOutboxEvent
+	notify := make([]int64, 0, len(notifyIDs))
+	for _, agentID := range notifyIDs {
+		if !muted[agentID] {
+			notify = append(notify, agentID)
+			continue
+		}
+
+		recipient, err := txStore.GetMessageRecipient(
+			ctx, messageID, agentID,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get "+
+				"recipient: %w", err)
+		}
+		if recipient.State != StateUnreadStr.String() {
+			continue
+		}
+
+		_, events, err := s.runTransition(
+			ctx, txStore, recipient, threadID,
+			s.threadFSMs.MarkRead,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to mute reply: %w",
+				err)
+		}
+		outbox = append(outbox, events...)
+	}
+
+	return notify, outbox, nil
+}
@@ -0,0 +1,289 @@
+package mail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/baselib/actor"
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+// TestThreadMuteFollow tests that muted threads deliver replies already read
+// and that followed threads deliver replies to agents that weren't
+// addressed.
+func TestThreadMuteFollow(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	alice := createTestAgent(t, storage, "Alice")
+	bob := createTestAgent(t, storage, "Bob")
+	carol := createTestAgent(t, storage, "Carol")
+	dave := createTestAgent(t, storage, "Dave")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	first := svc.handleSendMail(ctx, SendMailRequest{
+		SenderID:       alice.ID,
+		RecipientNames: []string{"Bob", "Carol"},
+		Subject:        "Release plan",
+		Body:           "Shipping on Friday.",
+		Priority:       PriorityNormal,
+	})
+	require.NoError(t, first.Error)
+	threadID := first.ThreadID
+
+	pref, err := svc.SetThreadMuted(ctx, bob.ID, threadID, true)
+	require.NoError(t, err)
+	require.True(t, pref.Muted)
+
+	pref, err = svc.SetThreadFollowing(ctx, dave.ID, threadID, true)
+	require.NoError(t, err)
+	require.True(t, pref.Following)
+
+	// Alice follows too, but never receives her own replies.
+	_, err = svc.SetThreadFollowing(ctx, alice.ID, threadID, true)
+	require.NoError(t, err)
+
+	reply := svc.handleSendMail(ctx, SendMailRequest{
+		SenderID:       alice.ID,
+		RecipientNames: []string{"Bob", "Carol"},
+		Subject:        "Re: Release plan",
+		Body:           "Moved to Monday.",
+		Priority:       PriorityNormal,
+		ThreadID:       threadID,
+	})
+	require.NoError(t, reply.Error)
+
+	state := func(agentID int64) string {
+		t.Helper()
+
+		recipient, err := storage.GetMessageRecipient(
+			ctx, reply.MessageID, agentID,
+		)
+		require.NoError(t, err)
+
+		return recipient.State
+	}
+	require.Equal(t, "read", state(bob.ID))
+	require.Equal(t, "unread", state(carol.ID))
+	require.Equal(t, "unread", state(dave.ID))
+
+	_, err = storage.GetMessageRecipient(ctx, reply.MessageID, alice.ID)
+	require.Error(t, err)
+
+	// Filing the thread only touches the messages each agent received.
+	received, err := svc.ReceivedThreadMessages(ctx, bob.ID, threadID)
+	require.NoError(t, err)
+	require.Equal(t, []int64{first.MessageID, reply.MessageID}, received)
+
+	received, err = svc.ReceivedThreadMessages(ctx, dave.ID, threadID)
+	require.NoError(t, err)
+	require.Equal(t, []int64{reply.MessageID}, received)
+
+	received, err = svc.ReceivedThreadMessages(ctx, alice.ID, threadID)
+	require.NoError(t, err)
+	require.Empty(t, received)
+
+	// Muting and following exclude each other, and clearing both forgets
+	// the thread.
+	pref, err = svc.SetThreadMuted(ctx, dave.ID, threadID, true)
+	require.NoError(t, err)
+	require.True(t, pref.Muted)
+	require.False(t, pref.Following)
+
+	_, err = svc.SetThreadMuted(ctx, dave.ID, threadID, false)
+	require.NoError(t, err)
+	prefs, err := svc.ListThreadPreferences(ctx, dave.ID)
+	require.NoError(t, err)
+	require.Empty(t, prefs)
+
+	prefs, err = svc.ListThreadPreferences(ctx, bob.ID)
+	require.NoError(t, err)
+	require.Len(t, prefs, 1)
+	require.Equal(t, threadID, prefs[0].ThreadID)
+
+	_, err = svc.SetThreadMuted(ctx, bob.ID, "no-such-thread", true)
+	require.ErrorIs(t, err, ErrThreadNotFound)
+}
+
+// TestThreadMuteNotifiesStateChange tests that a reply marked read for an
+// agent that muted its thread is reported as a state change like any other
+// read.
+func TestThreadMuteNotifiesStateChange(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	alice := createTestAgent(t, storage, "Alice")
+	bob := createTestAgent(t, storage, "Bob")
+
+	system := actor.NewActorSystem()
+	defer shutdownSystem(t, system)
+
+	notifHubRef := NotificationHubKey.Spawn(
+		system, "test-notif-hub", NewNotificationHub(),
+	)
+
+	svc := NewService(ServiceConfig{
+		Store:           storage,
+		NotificationHub: notifHubRef,
+	})
+	defer svc.OnStop(ctx)
+
+	first := svc.handleSendMail(ctx, SendMailRequest{
+		SenderID:       alice.ID,
+		RecipientNames: []string{"Bob"},
+		Subject:        "Release plan",
+		Body:           "Shipping on Friday.",
+		Priority:       PriorityNormal,
+	})
+	require.NoError(t, first.Error)
+
+	_, err := svc.SetThreadMuted(ctx, bob.ID, first.ThreadID, true)
+	require.NoError(t, err)
+
+	firehose := make(chan HubNotification, 10)
+	_, err = notifHubRef.Ask(ctx, SubscribeFirehoseMsg{
+		SubscriberID: "test-fire",
+		DeliveryChan: firehose,
+	}).Await(ctx).Unpack()
+	require.NoError(t, err)
+
+	reply := svc.handleSendMail(ctx, SendMailRequest{
+		SenderID:       alice.ID,
+		RecipientNames: []string{"Bob"},
+		Subject:        "Re: Release plan",
+		Body:           "Moved to Monday.",
+		Priority:       PriorityNormal,
+		ThreadID:       first.ThreadID,
+	})
+	require.NoError(t, reply.Error)
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case notif := <-firehose:
+			change := notif.StateChange
+			if change == nil ||
+				change.MessageID != reply.MessageID {
+
+				continue
+			}
+			require.Equal(t, bob.ID, change.AgentID)
+			require.Equal(
+				t, StateUnreadStr.String(), change.OldState,
+			)
+			require.Equal(
+				t, StateReadStr.String(), change.NewState,
+			)
+
+			return
+
+		case <-timeout:
+			t.Fatal("muted reply's read not delivered to firehose")
+		}
+	}
+}
+
+// TestMergeSplitThreads tests moving messages between threads.
+func TestMergeSplitThreads(t *testing.T) {
+	t.Parallel()
+
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	alice := createTestAgent(t, storage, "Alice")
+	bob := createTestAgent(t, storage, "Bob")
+
+	svc := NewServiceWithStore(storage)
+	defer svc.OnStop(ctx)
+
+	send := func(subject, threadID string) SendMailResponse {
+		resp := svc.handleSendMail(ctx, SendMailRequest{
+			SenderID:       alice.ID,
+			RecipientNames: []string{"Bob"},
+			Subject:        subject,
+			Body:           "Details for " + subject,
+			Priority:       PriorityNormal,
+			ThreadID:       threadID,
+		})
+		require.NoError(t, resp.Error)
+
+		return resp
+	}
+	threadLen := func(threadID string) int {
+		t.Helper()
+
+		msgs, err := svc.ReadThread(ctx, bob.ID, threadID)
+		require.NoError(t, err)
+
+		return len(msgs)
+	}
+
+	first := send("Migration", "")
+	send("Re: Migration", first.ThreadID)
+	fork := send("Migration (again)", "")
+
+	_, err := svc.SetThreadMuted(ctx, bob.ID, fork.ThreadID, true)
+	require.NoError(t, err)
+
+	// Merging moves the forked thread's messages and preferences.
+	moved, err := svc.MergeThreads(ctx, fork.ThreadID, first.ThreadID)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, moved)
+	require.Equal(t, 3, threadLen(first.ThreadID))
+	require.Zero(t, threadLen(fork.ThreadID))
+
+	pref, err := storage.GetThreadPreference(ctx, first.ThreadID, bob.ID)
+	require.NoError(t, err)
+	require.True(t, pref.Muted)
+
+	_, err = svc.MergeThreads(ctx, fork.ThreadID, first.ThreadID)
+	require.ErrorIs(t, err, ErrThreadNotFound)
+
+	_, err = svc.MergeThreads(ctx, first.ThreadID, first.ThreadID)
+	require.ErrorIs(t, err, ErrInvalidThreadOp)
+
+	// Splitting at the merged message moves it into a new thread that
+	// keeps the original's preferences.
+	split, err := svc.SplitThread(ctx, fork.MessageID)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, split.MessagesMoved)
+	require.NotEqual(t, first.ThreadID, split.ThreadID)
+	require.Equal(t, 2, threadLen(first.ThreadID))
+	require.Equal(t, 1, threadLen(split.ThreadID))
+
+	pref, err = storage.GetThreadPreference(ctx, split.ThreadID, bob.ID)
+	require.NoError(t, err)
+	require.True(t, pref.Muted)
+
+	_, err = svc.SplitThread(ctx, first.MessageID)
+	require.ErrorIs(t, err, ErrInvalidThreadOp)
+	require.Equal(t, 2, threadLen(first.ThreadID))
+
+	// Threads that a review tracks stay as they are.
+	_, err = storage.CreateReview(ctx, store.CreateReviewParams{
+		ReviewID:    "review-1",
+		ThreadID:    first.ThreadID,
+		RequesterID: alice.ID,
+		Branch:      "main",
+		BaseBranch:  "main",
+		CommitSHA:   "abc123",
+		RepoPath:    "/tmp/repo",
+		ReviewType:  "full",
+		Priority:    "normal",
+	})
+	require.NoError(t, err)
+
+	_, err = svc.MergeThreads(ctx, split.ThreadID, first.ThreadID)
+	require.ErrorIs(t, err, ErrThreadInUse)
+}
@@ -0,0 +1,149 @@
+package mail
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// WakeFunc is invoked by the WakeScheduler once a snoozed message's wake time
+// has arrived.
+type WakeFunc func(ctx context.Context, agentID, messageID int64) error
+
+// wakeKey identifies a single scheduled wake for a message recipient pair.
+type wakeKey struct {
+	agentID   int64
+	messageID int64
+}
+
+// scheduledWake is an armed timer along with the generation it was armed
+// in, which lets a firing timer tell whether it has since been replaced.
+type scheduledWake struct {
+	timer *time.Timer
+	gen   uint64
+}
+
+// WakeScheduler executes the ScheduleWake and CancelScheduledWake outbox
+// events emitted by the thread FSM. Timers are held in memory only: the
+// durable record is the snoozed_until column on message_recipients, which the
+// mail service reloads on startup to re-arm any pending wakes.
+type WakeScheduler struct {
+	wake WakeFunc
+	log  *slog.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	gen    uint64
+	timers map[wakeKey]scheduledWake
+}
+
+// NewWakeScheduler creates a new wake scheduler that calls wake for each
+// message whose timer fires.
+func NewWakeScheduler(wake WakeFunc) *WakeScheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &WakeScheduler{
+		wake:   wake,
+		log:    slog.Default().With("component", "wake_scheduler"),
+		ctx:    ctx,
+		cancel: cancel,
+		timers: make(map[wakeKey]scheduledWake),
+	}
+}
+
+// Schedule arms a timer that wakes the message at the given time, replacing
+// any wake already scheduled for the same recipient. Wake times in the past
+// fire immediately.
+func (w *WakeScheduler) Schedule(agentID, messageID int64, at time.Time) {
+	key := wakeKey{agentID: agentID, messageID: messageID}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Don't arm new timers once the scheduler has been stopped.
+	if w.ctx.Err() != nil {
+		return
+	}
+
+	if existing, ok := w.timers[key]; ok {
+		existing.timer.Stop()
+	}
+
+	// The timer may fire before AfterFunc returns, so it identifies
+	// itself by generation rather than by the timer, which is only
+	// recorded once we have it. fire waits on the lock we hold until
+	// then.
+	w.gen++
+	gen := w.gen
+	timer := time.AfterFunc(time.Until(at), func() {
+		w.fire(key, gen)
+	})
+	w.timers[key] = scheduledWake{timer: timer, gen: gen}
+}
+
+// Cancel stops the scheduled wake for the recipient, if any.
+func (w *WakeScheduler) Cancel(agentID, messageID int64) {
+	key := wakeKey{agentID: agentID, messageID: messageID}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if scheduled, ok := w.timers[key]; ok {
+		scheduled.timer.Stop()
+		delete(w.timers, key)
+	}
+}
+
+// Pending returns the number of wakes currently scheduled.
+func (w *WakeScheduler) Pending() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.timers)
+}
+
+// Stop cancels all pending timers. Any wake that is already executing is
+// handed a cancelled context.
+func (w *WakeScheduler) Stop() {
+	w.cancel()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for key, scheduled := range w.timers {
+		scheduled.timer.Stop()
+		delete(w.timers, key)
+	}
+}
+
+// fire runs the wake callback for the timer armed in generation gen, which
+// has expired.
+func (w *WakeScheduler) fire(key wakeKey, gen uint64) {
+	w.mu.Lock()
+
+	// The timer may have been replaced or cancelled after it expired but
+	// before we acquired the lock, in which case there's nothing to do.
+	scheduled, ok := w.timers[key]
+	if !ok || scheduled.gen != gen {
+		w.mu.Unlock()
+		return
+	}
+	delete(w.timers, key)
+	w.mu.Unlock()
+
+	if w.ctx.Err() != nil {
+		return
+	}
+
+	err := w.wake(w.ctx, key.agentID, key.messageID)
+	if err != nil {
+		w.log.Warn("Failed to wake snoozed message",
+			"agent_id", key.agentID,
+			"message_id", key.messageID,
+			"error", err,
+		)
+	}
+}
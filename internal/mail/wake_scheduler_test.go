@@ -0,0 +1,110 @@
+package mail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// wakeRecorder returns a WakeFunc that reports each fired wake on a channel.
+func wakeRecorder() (WakeFunc, chan wakeKey) {
+	fired := make(chan wakeKey, 10)
+	wake := func(ctx context.Context, agentID, messageID int64) error {
+		fired <- wakeKey{agentID: agentID, messageID: messageID}
+		return nil
+	}
+
+	return wake, fired
+}
+
+// TestWakeScheduler_Fires tests that a scheduled wake fires once its time
+// arrives and is then removed from the pending set.
+func TestWakeScheduler_Fires(t *testing.T) {
+	t.Parallel()
+
+	wake, fired := wakeRecorder()
+	scheduler := NewWakeScheduler(wake)
+	defer scheduler.Stop()
+
+	scheduler.Schedule(1, 100, time.Now().Add(50*time.Millisecond))
+	require.Equal(t, 1, scheduler.Pending())
+
+	select {
+	case key := <-fired:
+		require.Equal(t, wakeKey{agentID: 1, messageID: 100}, key)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for wake")
+	}
+
+	require.Eventually(t, func() bool {
+		return scheduler.Pending() == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+// TestWakeScheduler_PastDue tests that a wake time in the past fires
+// immediately, as happens when recovering snoozes after downtime.
+func TestWakeScheduler_PastDue(t *testing.T) {
+	t.Parallel()
+
+	wake, fired := wakeRecorder()
+	scheduler := NewWakeScheduler(wake)
+	defer scheduler.Stop()
+
+	scheduler.Schedule(1, 100, time.Now().Add(-time.Hour))
+
+	select {
+	case <-fired:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for past due wake")
+	}
+}
+
+// TestWakeScheduler_CancelAndReplace tests that cancelled wakes never fire
+// and that rescheduling replaces the existing timer.
+func TestWakeScheduler_CancelAndReplace(t *testing.T) {
+	t.Parallel()
+
+	wake, fired := wakeRecorder()
+	scheduler := NewWakeScheduler(wake)
+	defer scheduler.Stop()
+
+	// Cancel a wake before it fires.
+	scheduler.Schedule(1, 100, time.Now().Add(50*time.Millisecond))
+	scheduler.Cancel(1, 100)
+	require.Zero(t, scheduler.Pending())
+
+	// Push a second wake out so only the replacement remains.
+	scheduler.Schedule(1, 200, time.Now().Add(50*time.Millisecond))
+	scheduler.Schedule(1, 200, time.Now().Add(time.Hour))
+	require.Equal(t, 1, scheduler.Pending())
+
+	select {
+	case key := <-fired:
+		t.Fatalf("unexpected wake: %+v", key)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// TestWakeScheduler_Stop tests that stopping the scheduler drops all pending
+// wakes and ignores new ones.
+func TestWakeScheduler_Stop(t *testing.T) {
+	t.Parallel()
+
+	wake, fired := wakeRecorder()
+	scheduler := NewWakeScheduler(wake)
+
+	scheduler.Schedule(1, 100, time.Now().Add(50*time.Millisecond))
+	scheduler.Stop()
+	require.Zero(t, scheduler.Pending())
+
+	scheduler.Schedule(1, 200, time.Now())
+	require.Zero(t, scheduler.Pending())
+
+	select {
+	case key := <-fired:
+		t.Fatalf("unexpected wake: %+v", key)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,177 @@
+package mail
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultWatchLeaseTTL is how long a watcher's lease lasts without being
+// renewed.
+const DefaultWatchLeaseTTL = time.Minute
+
+// WatchFilter narrows the mail that wakes a watcher. Each empty field matches
+// any message; otherwise a message must match one of its values, in every
+// non-empty field.
+type WatchFilter struct {
+	// Priorities are the priorities to wake for.
+	Priorities []Priority
+
+	// SenderIDs are the senders to wake for.
+	SenderIDs []int64
+
+	// TopicIDs are the topics to wake for mail published to.
+	TopicIDs []int64
+
+	// ThreadIDs are the threads to wake for.
+	ThreadIDs []string
+}
+
+// Matches reports whether a message passes the filter.
+func (f WatchFilter) Matches(msg InboxMessage) bool {
+	switch {
+	case len(f.Priorities) > 0 &&
+		!slices.Contains(f.Priorities, msg.Priority):
+
+		return false
+
+	case len(f.SenderIDs) > 0 &&
+		!slices.Contains(f.SenderIDs, msg.SenderID):
+
+		return false
+
+	case len(f.TopicIDs) > 0 && !slices.Contains(f.TopicIDs, msg.TopicID):
+		return false
+
+	case len(f.ThreadIDs) > 0 &&
+		!slices.Contains(f.ThreadIDs, msg.ThreadID):
+
+		return false
+	}
+
+	return true
+}
+
+// WatchLease is a watcher's claim on an agent's inbox. Only one watcher per
+// agent holds a lease at a time, so that two watchers, perhaps on different
+// hosts, can't both wake the agent for the same mail.
+type WatchLease struct {
+	// AgentID is the agent whose inbox is watched.
+	AgentID int64
+
+	// Holder identifies the watcher, such as its host and PID.
+	Holder string
+
+	// ExpiresAt is when the lease lapses unless renewed.
+	ExpiresAt time.Time
+
+	// ttl is how long each renewal extends the lease by.
+	ttl time.Duration
+
+	// lost is closed once the lease is taken over or released.
+	lost chan struct{}
+}
+
+// Lost returns a channel closed once the lease was taken over by another
+// watcher or released.
+func (l *WatchLease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// WatchLeases tracks the watcher lease of each agent. Leases live in memory,
+// as a daemon restart ends the watch streams holding them anyway.
+type WatchLeases struct {
+	mu     sync.Mutex
+	leases map[int64]*WatchLease
+
+	// now returns the current time. Tests override it.
+	now func() time.Time
+}
+
+// NewWatchLeases creates an empty lease table.
+func NewWatchLeases() *WatchLeases {
+	return &WatchLeases{
+		leases: make(map[int64]*WatchLease),
+		now:    time.Now,
+	}
+}
+
+// Acquire grants a watcher the lease on an agent's inbox for the TTL, or
+// DefaultWatchLeaseTTL if it isn't positive. The lease is granted if nobody
+// holds it, the holder's lease expired, the holder is the same watcher
+// re-arming, or takeover is set. Otherwise ErrWatcherArmed is returned. A
+// lease taken from another watcher is marked lost so it stands down.
+func (w *WatchLeases) Acquire(agentID int64, holder string,
+	ttl time.Duration, takeover bool) (*WatchLease, error) {
+
+	if ttl <= 0 {
+		ttl = DefaultWatchLeaseTTL
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	if cur, ok := w.leases[agentID]; ok {
+		live := now.Before(cur.ExpiresAt)
+		if live && cur.Holder != holder && !takeover {
+			return nil, fmt.Errorf("%w: held by %s until %s",
+				ErrWatcherArmed, cur.Holder,
+				cur.ExpiresAt.UTC().Format(time.RFC3339))
+		}
+		close(cur.lost)
+	}
+
+	lease := &WatchLease{
+		AgentID:   agentID,
+		Holder:    holder,
+		ExpiresAt: now.Add(ttl),
+		ttl:       ttl,
+		lost:      make(chan struct{}),
+	}
+	w.leases[agentID] = lease
+
+	return lease, nil
+}
+
+// Renew extends a lease by its TTL. It returns ErrWatchLeaseLost if the lease
+// was taken over, released or had already expired.
+func (w *WatchLeases) Renew(lease *WatchLease) (time.Time, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.now()
+	cur, ok := w.leases[lease.AgentID]
+	if !ok || cur != lease || !now.Before(cur.ExpiresAt) {
+		return time.Time{}, ErrWatchLeaseLost
+	}
+	cur.ExpiresAt = now.Add(cur.ttl)
+
+	return cur.ExpiresAt, nil
+}
+
+// Release gives up a lease, unless another watcher has since taken it.
+func (w *WatchLeases) Release(lease *WatchLease) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.leases[lease.AgentID] == lease {
+		delete(w.leases, lease.AgentID)
+		close(lease.lost)
+	}
+}
+
+// Holder returns the watcher holding the live lease on an agent's inbox and
+// when it expires, or false if none does.
+func (w *WatchLeases) Holder(agentID int64) (string, time.Time, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cur, ok := w.leases[agentID]
+	if !ok || !w.now().Before(cur.ExpiresAt) {
+		return "", time.Time{}, false
+	}
+
+	return cur.Holder, cur.ExpiresAt, true
+}
@@ -0,0 +1,121 @@
+package mail
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestWatchFilterMatches tests that a message must match one value of every
+// non-empty filter field.
+func TestWatchFilterMatches(t *testing.T) {
+	t.Parallel()
+
+	msg := InboxMessage{
+		ID:       1,
+		ThreadID: "thread-1",
+		TopicID:  7,
+		SenderID: 3,
+		Priority: PriorityUrgent,
+	}
+
+	require.True(t, WatchFilter{}.Matches(msg))
+	require.True(t, WatchFilter{
+		Priorities: []Priority{PriorityNormal, PriorityUrgent},
+		SenderIDs:  []int64{3, 4},
+		TopicIDs:   []int64{7},
+		ThreadIDs:  []string{"thread-1"},
+	}.Matches(msg))
+
+	require.False(t, WatchFilter{
+		Priorities: []Priority{PriorityLow},
+	}.Matches(msg))
+	require.False(t, WatchFilter{SenderIDs: []int64{4}}.Matches(msg))
+	require.False(t, WatchFilter{TopicIDs: []int64{8}}.Matches(msg))
+	require.False(t, WatchFilter{
+		SenderIDs: []int64{3},
+		ThreadIDs: []string{"thread-2"},
+	}.Matches(msg))
+}
+
+// TestWatchLeases tests that only one watcher holds an agent's lease at a
+// time, that it can be re-armed by its holder or taken over, and that it
+// lapses unless renewed.
+func TestWatchLeases(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	leases := NewWatchLeases()
+	leases.now = func() time.Time { return now }
+
+	requireLost := func(lease *WatchLease) {
+		t.Helper()
+
+		select {
+		case <-lease.Lost():
+		default:
+			t.Fatalf("lease of %s not lost", lease.Holder)
+		}
+	}
+
+	first, err := leases.Acquire(1, "host-a:1", time.Minute, false)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(time.Minute), first.ExpiresAt)
+
+	holder, expiresAt, ok := leases.Holder(1)
+	require.True(t, ok)
+	require.Equal(t, "host-a:1", holder)
+	require.Equal(t, first.ExpiresAt, expiresAt)
+
+	// Another watcher is refused, but other agents' leases are separate.
+	_, err = leases.Acquire(1, "host-b:2", time.Minute, false)
+	require.ErrorIs(t, err, ErrWatcherArmed)
+	require.ErrorContains(t, err, "host-a:1")
+
+	other, err := leases.Acquire(2, "host-b:2", 0, false)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(DefaultWatchLeaseTTL), other.ExpiresAt)
+
+	// Renewing pushes the expiry out by the TTL.
+	now = now.Add(30 * time.Second)
+	expiresAt, err = leases.Renew(first)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(time.Minute), expiresAt)
+
+	// The holder re-arming replaces its own lease.
+	rearmed, err := leases.Acquire(1, "host-a:1", time.Minute, false)
+	require.NoError(t, err)
+	requireLost(first)
+
+	_, err = leases.Renew(first)
+	require.ErrorIs(t, err, ErrWatchLeaseLost)
+
+	// Takeover stands the holder down.
+	taken, err := leases.Acquire(1, "host-b:2", time.Minute, true)
+	require.NoError(t, err)
+	requireLost(rearmed)
+
+	// Releasing a lease that was taken over leaves the new one.
+	leases.Release(rearmed)
+	holder, _, ok = leases.Holder(1)
+	require.True(t, ok)
+	require.Equal(t, "host-b:2", holder)
+
+	// An expired lease can't be renewed, and anyone may arm.
+	now = now.Add(2 * time.Minute)
+	_, _, ok = leases.Holder(1)
+	require.False(t, ok)
+
+	_, err = leases.Renew(taken)
+	require.ErrorIs(t, err, ErrWatchLeaseLost)
+
+	_, err = leases.Acquire(1, "host-c:3", time.Minute, false)
+	require.NoError(t, err)
+	requireLost(taken)
+
+	leases.Release(other)
+	_, _, ok = leases.Holder(2)
+	require.False(t, ok)
+	requireLost(other)
+}
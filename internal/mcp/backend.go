@@ -76,10 +76,11 @@ type Backend interface {
 		agentID int64, topicName string,
 	) error
 
-	// SearchMessages performs full-text search across messages for an agent.
+	// SearchMessages runs a search query over an agent's messages,
+	// returning a page of ranked hits.
 	SearchMessages(ctx context.Context,
-		query string, agentID int64, limit int,
-	) ([]store.Message, error)
+		req mail.SearchRequest,
+	) (mail.SearchResponse, error)
 
 	// RegisterAgent creates a new agent with the given name.
 	RegisterAgent(ctx context.Context,
@@ -106,4 +107,144 @@ type Backend interface {
 	ReadThread(ctx context.Context,
 		threadID string,
 	) ([]mail.InboxMessage, error)
+
+	// CreateRecipientGroup creates a named recipient group.
+	CreateRecipientGroup(ctx context.Context,
+		name, description string, createdBy int64, members []string,
+	) (mail.RecipientGroup, error)
+
+	// GetRecipientGroup returns a recipient group and its members.
+	GetRecipientGroup(ctx context.Context,
+		name string,
+	) (mail.RecipientGroup, error)
+
+	// ListRecipientGroups returns all recipient groups.
+	ListRecipientGroups(ctx context.Context) ([]store.RecipientGroup, error)
+
+	// DeleteRecipientGroup deletes a recipient group.
+	DeleteRecipientGroup(ctx context.Context, name string) error
+
+	// AddRecipientGroupMembers adds agents to a recipient group.
+	AddRecipientGroupMembers(ctx context.Context,
+		name string, members []string,
+	) (mail.RecipientGroup, error)
+
+	// RemoveRecipientGroupMembers removes agents from a recipient group.
+	RemoveRecipientGroupMembers(ctx context.Context,
+		name string, members []string,
+	) (mail.RecipientGroup, error)
+
+	// ListDeadLetters lists the recipients a sender's messages could not
+	// be delivered to, newest first.
+	ListDeadLetters(ctx context.Context,
+		senderID int64, limit int,
+	) ([]store.DeadLetter, error)
+
+	// ListScheduledMessages lists a sender's scheduled messages that have
+	// not been sent or canceled, soonest first.
+	ListScheduledMessages(ctx context.Context,
+		senderID int64, limit int,
+	) ([]store.ScheduledMessage, error)
+
+	// CancelScheduledMessage cancels a scheduled message before it is
+	// sent.
+	CancelScheduledMessage(ctx context.Context, senderID, id int64) error
+
+	// AskQuestion sends a structured question.
+	AskQuestion(ctx context.Context,
+		req mail.AskQuestionRequest,
+	) (store.Question, error)
+
+	// GetQuestion returns a question and, once resolved, its answer.
+	GetQuestion(ctx context.Context,
+		questionID string,
+	) (store.Question, error)
+
+	// AnswerQuestion answers a question by replying in its thread.
+	AnswerQuestion(ctx context.Context, questionID string,
+		agentID int64, answer string,
+	) (store.Question, error)
+
+	// EditMessage edits a sent message, returning the revision number
+	// of the edited version.
+	EditMessage(ctx context.Context, req mail.EditMessageRequest) (int, error)
+
+	// RecallMessage recalls a sent message that no recipient has read
+	// yet.
+	RecallMessage(ctx context.Context, senderID, messageID int64) error
+
+	// CreateInboxRule adds a rule after the agent's existing inbox rules.
+	CreateInboxRule(ctx context.Context,
+		req mail.CreateInboxRuleRequest,
+	) (store.InboxRule, error)
+
+	// ListInboxRules lists an agent's inbox rules in the order they are
+	// applied.
+	ListInboxRules(ctx context.Context,
+		agentID int64,
+	) ([]store.InboxRule, error)
+
+	// DeleteInboxRule deletes one of an agent's inbox rules.
+	DeleteInboxRule(ctx context.Context, agentID int64, name string) error
+
+	// SetInboxRuleEnabled enables or disables one of an agent's inbox
+	// rules.
+	SetInboxRuleEnabled(ctx context.Context, agentID int64, name string,
+		enabled bool,
+	) (store.InboxRule, error)
+
+	// TestInboxRule reports which of an agent's recently received
+	// messages an inbox rule would match, without changing them.
+	TestInboxRule(ctx context.Context,
+		req mail.TestInboxRuleRequest,
+	) (mail.TestInboxRuleResponse, error)
+
+	// ListLabels lists an agent's labels with their message counts.
+	ListLabels(ctx context.Context, agentID int64) ([]store.Label, error)
+
+	// LabelMessage applies and removes labels on an agent's copy of a
+	// message, returning the labels it carries afterwards.
+	LabelMessage(ctx context.Context,
+		req mail.LabelMessageRequest,
+	) ([]string, error)
+
+	// SaveSearch saves a search query under a name for an agent. Run it
+	// with SearchMessages.
+	SaveSearch(ctx context.Context, agentID int64, name,
+		query string,
+	) (store.SavedSearch, error)
+
+	// ListSavedSearches lists an agent's saved searches by name.
+	ListSavedSearches(ctx context.Context,
+		agentID int64,
+	) ([]store.SavedSearch, error)
+
+	// SetAgentCapabilities replaces the capabilities and concurrency
+	// limit an agent declares, returning them as stored.
+	SetAgentCapabilities(ctx context.Context, agentID int64,
+		caps mail.AgentCapabilities,
+	) (mail.AgentCapabilities, error)
+
+	// FindCapableAgents returns the agents declaring a capability, such
+	// as "go-review" or "language:go", or every agent if it's empty.
+	FindCapableAgents(ctx context.Context,
+		capability string,
+	) ([]CapableAgent, error)
+
+	// ListCapabilityRoutes lists the agents the router picked for mail
+	// and tasks addressed to a capability, newest first.
+	ListCapabilityRoutes(ctx context.Context, capability string,
+		limit int,
+	) ([]mail.CapabilityRoute, error)
+}
+
+// CapableAgent is an agent with its status, unread mail and declared
+// capabilities, as the cap: router sees it.
+type CapableAgent struct {
+	ID           int64
+	Name         string
+	ProjectKey   string
+	Status       string
+	UnreadCount  int64
+	Capabilities mail.AgentCapabilities
 }
@@ -322,11 +322,11 @@ func (b *DirectBackend) DeleteSubscription(ctx context.Context,
 	return b.storage.DeleteSubscription(ctx, agentID, topic.ID)
 }
 
-// SearchMessages performs full-text search across messages for an agent.
+// SearchMessages runs a search query over an agent's messages.
 func (b *DirectBackend) SearchMessages(ctx context.Context,
-	query string, agentID int64, limit int,
-) ([]store.Message, error) {
-	return b.storage.SearchMessagesForAgent(ctx, query, agentID, limit)
+	req mail.SearchRequest,
+) (mail.SearchResponse, error) {
+	return b.mailSvc.Search(ctx, req)
 }
 
 // RegisterAgent creates a new agent with the given name.
@@ -402,5 +402,237 @@ func (b *DirectBackend) ReadThread(ctx context.Context,
 	return result, nil
 }
 
+// CreateRecipientGroup creates a named recipient group via the mail service.
+func (b *DirectBackend) CreateRecipientGroup(ctx context.Context,
+	name, description string, createdBy int64, members []string,
+) (mail.RecipientGroup, error) {
+	return b.mailSvc.CreateRecipientGroup(
+		ctx, name, description, createdBy, members,
+	)
+}
+
+// GetRecipientGroup returns a recipient group via the mail service.
+func (b *DirectBackend) GetRecipientGroup(ctx context.Context,
+	name string,
+) (mail.RecipientGroup, error) {
+	return b.mailSvc.GetRecipientGroup(ctx, name)
+}
+
+// ListRecipientGroups returns all recipient groups via the mail service.
+func (b *DirectBackend) ListRecipientGroups(
+	ctx context.Context,
+) ([]store.RecipientGroup, error) {
+	return b.mailSvc.ListRecipientGroups(ctx)
+}
+
+// DeleteRecipientGroup deletes a recipient group via the mail service.
+func (b *DirectBackend) DeleteRecipientGroup(ctx context.Context,
+	name string,
+) error {
+	return b.mailSvc.DeleteRecipientGroup(ctx, name)
+}
+
+// AddRecipientGroupMembers adds agents to a recipient group via the mail
+// service.
+func (b *DirectBackend) AddRecipientGroupMembers(ctx context.Context,
+	name string, members []string,
+) (mail.RecipientGroup, error) {
+	return b.mailSvc.AddRecipientGroupMembers(ctx, name, members)
+}
+
+// RemoveRecipientGroupMembers removes agents from a recipient group via the
+// mail service.
+func (b *DirectBackend) RemoveRecipientGroupMembers(ctx context.Context,
+	name string, members []string,
+) (mail.RecipientGroup, error) {
+	return b.mailSvc.RemoveRecipientGroupMembers(ctx, name, members)
+}
+
+// ListDeadLetters lists a sender's dead letters via the mail service.
+func (b *DirectBackend) ListDeadLetters(ctx context.Context,
+	senderID int64, limit int,
+) ([]store.DeadLetter, error) {
+	return b.mailSvc.ListDeadLetters(ctx, senderID, limit)
+}
+
+// ListScheduledMessages lists a sender's scheduled messages via the mail
+// service.
+func (b *DirectBackend) ListScheduledMessages(ctx context.Context,
+	senderID int64, limit int,
+) ([]store.ScheduledMessage, error) {
+	return b.mailSvc.ListScheduledMessages(ctx, senderID, limit)
+}
+
+// CancelScheduledMessage cancels a scheduled message via the mail service.
+func (b *DirectBackend) CancelScheduledMessage(ctx context.Context,
+	senderID, id int64,
+) error {
+	return b.mailSvc.CancelScheduledMessage(ctx, senderID, id)
+}
+
+// AskQuestion sends a structured question via the mail service.
+func (b *DirectBackend) AskQuestion(ctx context.Context,
+	req mail.AskQuestionRequest,
+) (store.Question, error) {
+	return b.mailSvc.AskQuestion(ctx, req)
+}
+
+// GetQuestion returns a question via the mail service.
+func (b *DirectBackend) GetQuestion(ctx context.Context,
+	questionID string,
+) (store.Question, error) {
+	return b.mailSvc.GetQuestion(ctx, questionID)
+}
+
+// AnswerQuestion answers a question via the mail service.
+func (b *DirectBackend) AnswerQuestion(ctx context.Context, questionID string,
+	agentID int64, answer string,
+) (store.Question, error) {
+	return b.mailSvc.AnswerQuestion(ctx, questionID, agentID, answer)
+}
+
 // Compile-time interface check.
 var _ Backend = (*DirectBackend)(nil)
+
+// EditMessage edits a sent message via the mail service.
+func (b *DirectBackend) EditMessage(ctx context.Context,
+	req mail.EditMessageRequest,
+) (int, error) {
+	resp, err := b.mailSvc.EditMessage(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.Revision, nil
+}
+
+// RecallMessage recalls a sent message via the mail service.
+func (b *DirectBackend) RecallMessage(ctx context.Context, senderID,
+	messageID int64,
+) error {
+	return b.mailSvc.RecallMessage(ctx, senderID, messageID)
+}
+
+// CreateInboxRule creates an inbox rule via the mail service.
+func (b *DirectBackend) CreateInboxRule(ctx context.Context,
+	req mail.CreateInboxRuleRequest,
+) (store.InboxRule, error) {
+	return b.mailSvc.CreateInboxRule(ctx, req)
+}
+
+// ListInboxRules lists an agent's inbox rules via the mail service.
+func (b *DirectBackend) ListInboxRules(ctx context.Context,
+	agentID int64,
+) ([]store.InboxRule, error) {
+	return b.mailSvc.ListInboxRules(ctx, agentID)
+}
+
+// DeleteInboxRule deletes an inbox rule via the mail service.
+func (b *DirectBackend) DeleteInboxRule(ctx context.Context, agentID int64,
+	name string,
+) error {
+	return b.mailSvc.DeleteInboxRule(ctx, agentID, name)
+}
+
+// SetInboxRuleEnabled enables or disables an inbox rule via the mail
+// service.
+func (b *DirectBackend) SetInboxRuleEnabled(ctx context.Context,
+	agentID int64, name string, enabled bool,
+) (store.InboxRule, error) {
+	return b.mailSvc.SetInboxRuleEnabled(ctx, agentID, name, enabled)
+}
+
+// TestInboxRule dry-runs an inbox rule via the mail service.
+func (b *DirectBackend) TestInboxRule(ctx context.Context,
+	req mail.TestInboxRuleRequest,
+) (mail.TestInboxRuleResponse, error) {
+	return b.mailSvc.TestInboxRule(ctx, req)
+}
+
+// ListLabels lists an agent's labels with their message counts.
+func (b *DirectBackend) ListLabels(ctx context.Context,
+	agentID int64,
+) ([]store.Label, error) {
+	return b.mailSvc.ListLabels(ctx, agentID)
+}
+
+// LabelMessage applies and removes labels on an agent's copy of a message.
+func (b *DirectBackend) LabelMessage(ctx context.Context,
+	req mail.LabelMessageRequest,
+) ([]string, error) {
+	return b.mailSvc.LabelMessage(ctx, req)
+}
+
+// SaveSearch saves a search query under a name via the mail service.
+func (b *DirectBackend) SaveSearch(ctx context.Context, agentID int64, name,
+	query string,
+) (store.SavedSearch, error) {
+	return b.mailSvc.SaveSearch(ctx, agentID, name, query)
+}
+
+// ListSavedSearches lists an agent's saved searches via the mail service.
+func (b *DirectBackend) ListSavedSearches(ctx context.Context,
+	agentID int64,
+) ([]store.SavedSearch, error) {
+	return b.mailSvc.ListSavedSearches(ctx, agentID)
+}
+
+// SetAgentCapabilities replaces an agent's capabilities via the mail
+// service.
+func (b *DirectBackend) SetAgentCapabilities(ctx context.Context,
+	agentID int64, caps mail.AgentCapabilities,
+) (mail.AgentCapabilities, error) {
+	return b.mailSvc.SetAgentCapabilities(ctx, agentID, caps)
+}
+
+// FindCapableAgents returns the agents declaring a capability, with their
+// status computed from their heartbeats.
+func (b *DirectBackend) FindCapableAgents(ctx context.Context,
+	capability string,
+) ([]CapableAgent, error) {
+	var kind, name string
+	if capability != "" {
+		var err error
+		kind, name, err = mail.ParseCapability(capability)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	heartbeatMgr := agent.NewHeartbeatManager(b.registry, nil)
+	discovered, _, err := heartbeatMgr.DiscoverAgents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	allCaps, err := b.mailSvc.ListAgentCapabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var agents []CapableAgent
+	for _, d := range discovered {
+		caps := allCaps[d.Agent.ID]
+		if name != "" && !caps.Has(kind, name) {
+			continue
+		}
+
+		agents = append(agents, CapableAgent{
+			ID:           d.Agent.ID,
+			Name:         d.Agent.Name,
+			ProjectKey:   d.Agent.ProjectKey.String,
+			Status:       string(d.Status),
+			UnreadCount:  d.UnreadCount,
+			Capabilities: caps,
+		})
+	}
+
+	return agents, nil
+}
+
+// ListCapabilityRoutes lists capability routes via the mail service.
+func (b *DirectBackend) ListCapabilityRoutes(ctx context.Context,
+	capability string, limit int,
+) ([]mail.CapabilityRoute, error) {
+	return b.mailSvc.ListCapabilityRoutes(ctx, capability, limit)
+}
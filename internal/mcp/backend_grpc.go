@@ -3,6 +3,7 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc"
@@ -17,17 +18,19 @@ import (
 // to a running substrated daemon. This is used by the `substrate mcp serve`
 // command to expose an MCP server that delegates to the daemon.
 type GRPCBackend struct {
-	conn        *grpc.ClientConn
-	mailClient  subtraterpc.MailClient
-	agentClient subtraterpc.AgentClient
+	conn            *grpc.ClientConn
+	mailClient      subtraterpc.MailClient
+	agentClient     subtraterpc.AgentClient
+	inboxRuleClient subtraterpc.InboxRuleServiceClient
 }
 
 // NewGRPCBackend creates a new GRPCBackend from an established connection.
 func NewGRPCBackend(conn *grpc.ClientConn) *GRPCBackend {
 	return &GRPCBackend{
-		conn:        conn,
-		mailClient:  subtraterpc.NewMailClient(conn),
-		agentClient: subtraterpc.NewAgentClient(conn),
+		conn:            conn,
+		mailClient:      subtraterpc.NewMailClient(conn),
+		agentClient:     subtraterpc.NewAgentClient(conn),
+		inboxRuleClient: subtraterpc.NewInboxRuleServiceClient(conn),
 	}
 }
 
@@ -35,7 +38,7 @@ func NewGRPCBackend(conn *grpc.ClientConn) *GRPCBackend {
 func (b *GRPCBackend) SendMail(ctx context.Context,
 	req mail.SendMailRequest,
 ) (mail.SendMailResponse, error) {
-	resp, err := b.mailClient.SendMail(ctx, &subtraterpc.SendMailRequest{
+	grpcReq := &subtraterpc.SendMailRequest{
 		SenderId:       req.SenderID,
 		RecipientNames: req.RecipientNames,
 		TopicName:      req.TopicName,
@@ -44,14 +47,42 @@ func (b *GRPCBackend) SendMail(ctx context.Context,
 		Body:           req.Body,
 		Priority:       priorityToProto(req.Priority),
 		IdempotencyKey: req.IdempotencyKey,
-	})
+		DeliveryMode:   deliveryModeToProto(req.DeliveryMode),
+		Metadata:       req.Metadata,
+	}
+	if req.SendAt != nil {
+		grpcReq.SendAt = timestamppb.New(*req.SendAt)
+	}
+
+	resp, err := b.mailClient.SendMail(ctx, grpcReq)
 	if err != nil {
 		return mail.SendMailResponse{}, err
 	}
 
+	expansions := make([]mail.RecipientExpansion, len(resp.Expansions))
+	for i, e := range resp.Expansions {
+		expansions[i] = mail.RecipientExpansion{
+			Address:    e.Address,
+			AgentNames: e.AgentNames,
+		}
+	}
+
+	deliveries := make([]mail.RecipientDelivery, len(resp.Deliveries))
+	for i, d := range resp.Deliveries {
+		deliveries[i] = mail.RecipientDelivery{
+			Recipient: d.Recipient,
+			AgentID:   d.AgentId,
+			Status:    protoToRecipientStatus(d.Status),
+			Reason:    d.Reason,
+		}
+	}
+
 	return mail.SendMailResponse{
-		MessageID: resp.MessageId,
-		ThreadID:  resp.ThreadId,
+		MessageID:   resp.MessageId,
+		ThreadID:    resp.ThreadId,
+		Expansions:  expansions,
+		Deliveries:  deliveries,
+		ScheduledID: resp.ScheduledId,
 	}, nil
 }
 
@@ -65,6 +96,7 @@ func (b *GRPCBackend) FetchInbox(ctx context.Context,
 			Limit:      int32(req.Limit),
 			UnreadOnly: req.UnreadOnly,
 			Offset:     int32(req.Offset),
+			Label:      req.Label,
 		},
 	)
 	if err != nil {
@@ -193,6 +225,7 @@ func (b *GRPCBackend) Publish(ctx context.Context,
 			Subject:   req.Subject,
 			Body:      req.Body,
 			Priority:  priorityToProto(req.Priority),
+			Metadata:  req.Metadata,
 		},
 	)
 	if err != nil {
@@ -295,39 +328,26 @@ func (b *GRPCBackend) DeleteSubscription(ctx context.Context,
 	return err
 }
 
-// SearchMessages searches messages via the gRPC daemon.
+// SearchMessages runs a search query via the gRPC daemon.
 func (b *GRPCBackend) SearchMessages(ctx context.Context,
-	query string, agentID int64, limit int,
-) ([]store.Message, error) {
+	req mail.SearchRequest,
+) (mail.SearchResponse, error) {
 	resp, err := b.mailClient.Search(ctx, &subtraterpc.SearchRequest{
-		AgentId: agentID,
-		Query:   query,
-		Limit:   int32(limit),
+		AgentId:     req.AgentID,
+		Query:       req.Query,
+		TopicId:     req.TopicID,
+		Label:       req.Label,
+		Limit:       req.Limit,
+		Cursor:      req.Cursor,
+		IncludeBody: req.IncludeBody,
+		SavedSearch: req.SavedSearch,
+		MessageIds:  req.MessageIDs,
 	})
 	if err != nil {
-		return nil, err
+		return mail.SearchResponse{}, err
 	}
 
-	messages := make([]store.Message, len(resp.Results))
-	for i, m := range resp.Results {
-		messages[i] = store.Message{
-			ID:       m.Id,
-			ThreadID: m.ThreadId,
-			TopicID:  m.TopicId,
-			SenderID: m.SenderId,
-			Subject:  m.Subject,
-			Body:     m.Body,
-			Priority: protoToPriorityString(m.Priority),
-			CreatedAt: func() time.Time {
-				if m.CreatedAt != nil {
-					return m.CreatedAt.AsTime()
-				}
-				return time.Time{}
-			}(),
-		}
-	}
-
-	return messages, nil
+	return protoToSearchResponse(resp), nil
 }
 
 // RegisterAgent creates a new agent via the gRPC daemon.
@@ -459,6 +479,683 @@ func (b *GRPCBackend) ReadThread(ctx context.Context,
 	return protoToInboxMessages(resp.Messages), nil
 }
 
+// CreateRecipientGroup creates a named recipient group via the gRPC daemon.
+func (b *GRPCBackend) CreateRecipientGroup(ctx context.Context,
+	name, description string, createdBy int64, members []string,
+) (mail.RecipientGroup, error) {
+	resp, err := b.mailClient.CreateRecipientGroup(
+		ctx, &subtraterpc.CreateRecipientGroupRequest{
+			Name:        name,
+			Description: description,
+			CreatedBy:   createdBy,
+			Members:     members,
+		},
+	)
+	if err != nil {
+		return mail.RecipientGroup{}, err
+	}
+
+	return protoToRecipientGroup(resp.Group), nil
+}
+
+// GetRecipientGroup returns a recipient group via the gRPC daemon.
+func (b *GRPCBackend) GetRecipientGroup(ctx context.Context,
+	name string,
+) (mail.RecipientGroup, error) {
+	resp, err := b.mailClient.GetRecipientGroup(
+		ctx, &subtraterpc.GetRecipientGroupRequest{Name: name},
+	)
+	if err != nil {
+		return mail.RecipientGroup{}, err
+	}
+
+	return protoToRecipientGroup(resp.Group), nil
+}
+
+// ListRecipientGroups returns all recipient groups via the gRPC daemon.
+func (b *GRPCBackend) ListRecipientGroups(
+	ctx context.Context,
+) ([]store.RecipientGroup, error) {
+	resp, err := b.mailClient.ListRecipientGroups(
+		ctx, &subtraterpc.ListRecipientGroupsRequest{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]store.RecipientGroup, len(resp.Groups))
+	for i, g := range resp.Groups {
+		groups[i] = protoToRecipientGroup(g).RecipientGroup
+	}
+
+	return groups, nil
+}
+
+// DeleteRecipientGroup deletes a recipient group via the gRPC daemon.
+func (b *GRPCBackend) DeleteRecipientGroup(ctx context.Context,
+	name string,
+) error {
+	_, err := b.mailClient.DeleteRecipientGroup(
+		ctx, &subtraterpc.DeleteRecipientGroupRequest{Name: name},
+	)
+
+	return err
+}
+
+// AddRecipientGroupMembers adds agents to a recipient group via the gRPC
+// daemon.
+func (b *GRPCBackend) AddRecipientGroupMembers(ctx context.Context,
+	name string, members []string,
+) (mail.RecipientGroup, error) {
+	resp, err := b.mailClient.AddRecipientGroupMembers(
+		ctx, &subtraterpc.RecipientGroupMembersRequest{
+			Name:    name,
+			Members: members,
+		},
+	)
+	if err != nil {
+		return mail.RecipientGroup{}, err
+	}
+
+	return protoToRecipientGroup(resp.Group), nil
+}
+
+// RemoveRecipientGroupMembers removes agents from a recipient group via the
+// gRPC daemon.
+func (b *GRPCBackend) RemoveRecipientGroupMembers(ctx context.Context,
+	name string, members []string,
+) (mail.RecipientGroup, error) {
+	resp, err := b.mailClient.RemoveRecipientGroupMembers(
+		ctx, &subtraterpc.RecipientGroupMembersRequest{
+			Name:    name,
+			Members: members,
+		},
+	)
+	if err != nil {
+		return mail.RecipientGroup{}, err
+	}
+
+	return protoToRecipientGroup(resp.Group), nil
+}
+
+// ListDeadLetters lists a sender's dead letters via the gRPC daemon.
+func (b *GRPCBackend) ListDeadLetters(ctx context.Context,
+	senderID int64, limit int,
+) ([]store.DeadLetter, error) {
+	resp, err := b.mailClient.ListDeadLetters(
+		ctx, &subtraterpc.ListDeadLettersRequest{
+			AgentId: senderID,
+			Limit:   int32(limit),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	letters := make([]store.DeadLetter, len(resp.DeadLetters))
+	for i, l := range resp.DeadLetters {
+		letters[i] = store.DeadLetter{
+			ID:        l.Id,
+			MessageID: l.MessageId,
+			ThreadID:  l.ThreadId,
+			Subject:   l.Subject,
+			SenderID:  senderID,
+			Recipient: l.Recipient,
+			Status:    string(protoToRecipientStatus(l.Status)),
+			Reason:    l.Reason,
+			CreatedAt: l.CreatedAt.AsTime(),
+		}
+	}
+
+	return letters, nil
+}
+
+// ListScheduledMessages lists a sender's scheduled messages via the gRPC
+// daemon.
+func (b *GRPCBackend) ListScheduledMessages(ctx context.Context,
+	senderID int64, limit int,
+) ([]store.ScheduledMessage, error) {
+	resp, err := b.mailClient.ListScheduledMessages(
+		ctx, &subtraterpc.ListScheduledMessagesRequest{
+			AgentId: senderID,
+			Limit:   int32(limit),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]store.ScheduledMessage, len(resp.Messages))
+	for i, m := range resp.Messages {
+		msgs[i] = store.ScheduledMessage{
+			ID:             m.Id,
+			SenderID:       senderID,
+			RecipientNames: m.RecipientNames,
+			TopicName:      m.TopicName,
+			ThreadID:       m.ThreadId,
+			Subject:        m.Subject,
+			Body:           m.Body,
+			Priority:       protoToPriorityString(m.Priority),
+			SendAt:         m.SendAt.AsTime(),
+			State:          m.State,
+			LastError:      m.LastError,
+			CreatedAt:      m.CreatedAt.AsTime(),
+		}
+	}
+
+	return msgs, nil
+}
+
+// CancelScheduledMessage cancels a scheduled message via the gRPC daemon.
+func (b *GRPCBackend) CancelScheduledMessage(ctx context.Context,
+	senderID, id int64,
+) error {
+	_, err := b.mailClient.CancelScheduledMessage(
+		ctx, &subtraterpc.CancelScheduledMessageRequest{
+			AgentId: senderID,
+			Id:      id,
+		},
+	)
+	return err
+}
+
+// AskQuestion sends a structured question via the gRPC daemon.
+func (b *GRPCBackend) AskQuestion(ctx context.Context,
+	req mail.AskQuestionRequest,
+) (store.Question, error) {
+	resp, err := b.mailClient.AskQuestion(
+		ctx, &subtraterpc.AskQuestionRequest{
+			AgentId:        req.SenderID,
+			RecipientNames: req.RecipientNames,
+			Question:       req.Question,
+			Details:        req.Details,
+			Options:        req.Options,
+			DefaultAnswer:  req.DefaultAnswer,
+			TimeoutSeconds: int64(req.Timeout / time.Second),
+			Fallback:       req.Fallback,
+			Priority:       priorityToProto(req.Priority),
+		},
+	)
+	if err != nil {
+		return store.Question{}, err
+	}
+
+	return protoToQuestion(resp.Question), nil
+}
+
+// GetQuestion returns a question via the gRPC daemon.
+func (b *GRPCBackend) GetQuestion(ctx context.Context,
+	questionID string,
+) (store.Question, error) {
+	resp, err := b.mailClient.GetQuestion(
+		ctx, &subtraterpc.GetQuestionRequest{QuestionId: questionID},
+	)
+	if err != nil {
+		return store.Question{}, err
+	}
+
+	return protoToQuestion(resp.Question), nil
+}
+
+// AnswerQuestion answers a question via the gRPC daemon.
+func (b *GRPCBackend) AnswerQuestion(ctx context.Context, questionID string,
+	agentID int64, answer string,
+) (store.Question, error) {
+	resp, err := b.mailClient.AnswerQuestion(
+		ctx, &subtraterpc.AnswerQuestionRequest{
+			QuestionId: questionID,
+			AgentId:    agentID,
+			Answer:     answer,
+		},
+	)
+	if err != nil {
+		return store.Question{}, err
+	}
+
+	return protoToQuestion(resp.Question), nil
+}
+
+// EditMessage edits a sent message via the gRPC daemon.
+func (b *GRPCBackend) EditMessage(ctx context.Context,
+	req mail.EditMessageRequest,
+) (int, error) {
+	resp, err := b.mailClient.EditMessage(
+		ctx, &subtraterpc.EditMessageRequest{
+			AgentId:   req.SenderID,
+			MessageId: req.MessageID,
+			Subject:   req.Subject,
+			Body:      req.Body,
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(resp.Revision), nil
+}
+
+// RecallMessage recalls a sent message via the gRPC daemon.
+func (b *GRPCBackend) RecallMessage(ctx context.Context, senderID,
+	messageID int64,
+) error {
+	_, err := b.mailClient.RecallMessage(
+		ctx, &subtraterpc.RecallMessageRequest{
+			AgentId:   senderID,
+			MessageId: messageID,
+		},
+	)
+	return err
+}
+
+// CreateInboxRule creates an inbox rule via the gRPC daemon.
+func (b *GRPCBackend) CreateInboxRule(ctx context.Context,
+	req mail.CreateInboxRuleRequest,
+) (store.InboxRule, error) {
+	resp, err := b.inboxRuleClient.CreateInboxRule(
+		ctx, &subtraterpc.CreateInboxRuleRequest{
+			AgentId:        req.AgentID,
+			Name:           req.Name,
+			StopProcessing: req.StopProcessing,
+			Conditions: inboxRuleConditionsToProto(
+				req.Conditions,
+			),
+			Actions: &subtraterpc.InboxRuleActions{
+				Archive:   req.Actions.Archive,
+				Star:      req.Actions.Star,
+				Snooze:    req.Actions.Snooze,
+				Category:  string(req.Actions.Category),
+				ForwardTo: req.Actions.ForwardTo,
+				Label:     req.Actions.Label,
+			},
+		},
+	)
+	if err != nil {
+		return store.InboxRule{}, err
+	}
+
+	return protoToInboxRule(resp), nil
+}
+
+// ListInboxRules lists an agent's inbox rules via the gRPC daemon.
+func (b *GRPCBackend) ListInboxRules(ctx context.Context,
+	agentID int64,
+) ([]store.InboxRule, error) {
+	resp, err := b.inboxRuleClient.ListInboxRules(
+		ctx, &subtraterpc.ListInboxRulesRequest{AgentId: agentID},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]store.InboxRule, len(resp.Rules))
+	for i, rule := range resp.Rules {
+		rules[i] = protoToInboxRule(rule)
+	}
+
+	return rules, nil
+}
+
+// DeleteInboxRule deletes an inbox rule via the gRPC daemon.
+func (b *GRPCBackend) DeleteInboxRule(ctx context.Context, agentID int64,
+	name string,
+) error {
+	_, err := b.inboxRuleClient.DeleteInboxRule(
+		ctx, &subtraterpc.DeleteInboxRuleRequest{
+			AgentId: agentID,
+			Name:    name,
+		},
+	)
+	return err
+}
+
+// SetInboxRuleEnabled enables or disables an inbox rule via the gRPC
+// daemon.
+func (b *GRPCBackend) SetInboxRuleEnabled(ctx context.Context,
+	agentID int64, name string, enabled bool,
+) (store.InboxRule, error) {
+	resp, err := b.inboxRuleClient.SetInboxRuleEnabled(
+		ctx, &subtraterpc.SetInboxRuleEnabledRequest{
+			AgentId: agentID,
+			Name:    name,
+			Enabled: enabled,
+		},
+	)
+	if err != nil {
+		return store.InboxRule{}, err
+	}
+
+	return protoToInboxRule(resp), nil
+}
+
+// TestInboxRule dry-runs an inbox rule via the gRPC daemon.
+func (b *GRPCBackend) TestInboxRule(ctx context.Context,
+	req mail.TestInboxRuleRequest,
+) (mail.TestInboxRuleResponse, error) {
+	resp, err := b.inboxRuleClient.TestInboxRule(
+		ctx, &subtraterpc.TestInboxRuleRequest{
+			AgentId:    req.AgentID,
+			Name:       req.Name,
+			Conditions: inboxRuleConditionsToProto(req.Conditions),
+			Limit:      int32(req.Limit),
+		},
+	)
+	if err != nil {
+		return mail.TestInboxRuleResponse{}, err
+	}
+
+	return mail.TestInboxRuleResponse{
+		Scanned: int(resp.Scanned),
+		Matches: protoToInboxMessages(resp.Matches),
+	}, nil
+}
+
+// ListLabels lists an agent's labels via the gRPC daemon.
+func (b *GRPCBackend) ListLabels(ctx context.Context,
+	agentID int64,
+) ([]store.Label, error) {
+	resp, err := b.mailClient.ListLabels(
+		ctx, &subtraterpc.ListLabelsRequest{AgentId: agentID},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]store.Label, len(resp.Labels))
+	for i, label := range resp.Labels {
+		labels[i] = store.Label{
+			ID:           label.Id,
+			AgentID:      label.AgentId,
+			Name:         label.Name,
+			MessageCount: label.MessageCount,
+			UnreadCount:  label.UnreadCount,
+			CreatedAt:    label.CreatedAt.AsTime(),
+		}
+	}
+
+	return labels, nil
+}
+
+// LabelMessage applies and removes labels on a message via the gRPC daemon.
+func (b *GRPCBackend) LabelMessage(ctx context.Context,
+	req mail.LabelMessageRequest,
+) ([]string, error) {
+	resp, err := b.mailClient.LabelMessage(
+		ctx, &subtraterpc.LabelMessageRequest{
+			AgentId:   req.AgentID,
+			MessageId: req.MessageID,
+			Add:       req.Add,
+			Remove:    req.Remove,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Labels, nil
+}
+
+// SaveSearch saves a search query under a name via the gRPC daemon.
+func (b *GRPCBackend) SaveSearch(ctx context.Context, agentID int64, name,
+	query string,
+) (store.SavedSearch, error) {
+	resp, err := b.mailClient.SaveSearch(
+		ctx, &subtraterpc.SaveSearchRequest{
+			AgentId: agentID,
+			Name:    name,
+			Query:   query,
+		},
+	)
+	if err != nil {
+		return store.SavedSearch{}, err
+	}
+
+	return protoToSavedSearch(resp), nil
+}
+
+// ListSavedSearches lists an agent's saved searches via the gRPC daemon.
+func (b *GRPCBackend) ListSavedSearches(ctx context.Context,
+	agentID int64,
+) ([]store.SavedSearch, error) {
+	resp, err := b.mailClient.ListSavedSearches(
+		ctx, &subtraterpc.ListSavedSearchesRequest{AgentId: agentID},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	searches := make([]store.SavedSearch, len(resp.Searches))
+	for i, saved := range resp.Searches {
+		searches[i] = protoToSavedSearch(saved)
+	}
+
+	return searches, nil
+}
+
+// protoToSavedSearch converts a proto saved search to the store type.
+func protoToSavedSearch(saved *subtraterpc.SavedSearchProto) store.SavedSearch {
+	return store.SavedSearch{
+		ID:        saved.Id,
+		AgentID:   saved.AgentId,
+		Name:      saved.Name,
+		Query:     saved.Query,
+		CreatedAt: saved.CreatedAt.AsTime(),
+		UpdatedAt: saved.UpdatedAt.AsTime(),
+	}
+}
+
+// SetAgentCapabilities replaces an agent's capabilities via the gRPC
+// daemon.
+func (b *GRPCBackend) SetAgentCapabilities(ctx context.Context,
+	agentID int64, caps mail.AgentCapabilities,
+) (mail.AgentCapabilities, error) {
+	resp, err := b.agentClient.UpdateAgent(
+		ctx, &subtraterpc.UpdateAgentRequest{
+			Id: agentID,
+			Capabilities: &subtraterpc.AgentCapabilities{
+				Languages:      caps.Languages,
+				Repos:          caps.Repos,
+				Tools:          caps.Tools,
+				Skills:         caps.Skills,
+				MaxConcurrency: caps.MaxConcurrency,
+			},
+		},
+	)
+	if err != nil {
+		return mail.AgentCapabilities{}, err
+	}
+
+	return protoToCapabilities(resp.Agent.Capabilities), nil
+}
+
+// FindCapableAgents returns the agents declaring a capability via the gRPC
+// daemon's agent discovery.
+func (b *GRPCBackend) FindCapableAgents(ctx context.Context,
+	capability string,
+) ([]CapableAgent, error) {
+	resp, err := b.agentClient.DiscoverAgents(
+		ctx, &subtraterpc.DiscoverAgentsRequest{
+			CapabilityFilter: capability,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	agents := make([]CapableAgent, len(resp.Agents))
+	for i, a := range resp.Agents {
+		agents[i] = CapableAgent{
+			ID:         a.Id,
+			Name:       a.Name,
+			ProjectKey: a.ProjectKey,
+			Status: strings.ToLower(strings.TrimPrefix(
+				a.Status.String(), "AGENT_STATUS_",
+			)),
+			UnreadCount:  a.UnreadCount,
+			Capabilities: protoToCapabilities(a.Capabilities),
+		}
+	}
+
+	return agents, nil
+}
+
+// ListCapabilityRoutes lists capability routes via the gRPC daemon.
+func (b *GRPCBackend) ListCapabilityRoutes(ctx context.Context,
+	capability string, limit int,
+) ([]mail.CapabilityRoute, error) {
+	resp, err := b.agentClient.ListCapabilityRoutes(
+		ctx, &subtraterpc.ListCapabilityRoutesRequest{
+			Capability: capability,
+			Limit:      int32(limit),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := make([]mail.CapabilityRoute, len(resp.Routes))
+	for i, r := range resp.Routes {
+		routes[i] = mail.CapabilityRoute{
+			Capability:      r.Capability,
+			AgentID:         r.AgentId,
+			AgentName:       r.AgentName,
+			MessageID:       r.MessageId,
+			TaskID:          r.TaskId,
+			Candidates:      r.Candidates,
+			UnreadCount:     r.UnreadCount,
+			InProgressCount: r.InProgressCount,
+			CreatedAt:       r.CreatedAt.AsTime(),
+		}
+	}
+
+	return routes, nil
+}
+
+// protoToCapabilities converts proto capabilities, treating nil as none.
+func protoToCapabilities(
+	caps *subtraterpc.AgentCapabilities,
+) mail.AgentCapabilities {
+	if caps == nil {
+		return mail.AgentCapabilities{}
+	}
+
+	return mail.AgentCapabilities{
+		Languages:      caps.Languages,
+		Repos:          caps.Repos,
+		Tools:          caps.Tools,
+		Skills:         caps.Skills,
+		MaxConcurrency: caps.MaxConcurrency,
+	}
+}
+
+// inboxRuleConditionsToProto converts inbox rule conditions to proto.
+func inboxRuleConditionsToProto(
+	cond store.InboxRuleConditions,
+) *subtraterpc.InboxRuleConditions {
+	return &subtraterpc.InboxRuleConditions{
+		SenderName:     cond.SenderName,
+		SenderPrefix:   cond.SenderPrefix,
+		SubjectPattern: cond.SubjectPattern,
+		TopicName:      cond.TopicName,
+		Priority:       cond.Priority,
+		MetadataKey:    cond.MetadataKey,
+		MetadataValue:  cond.MetadataValue,
+	}
+}
+
+// protoToInboxRule converts a proto inbox rule to a store InboxRule.
+func protoToInboxRule(rule *subtraterpc.InboxRuleProto) store.InboxRule {
+	result := store.InboxRule{
+		ID:             rule.Id,
+		AgentID:        rule.AgentId,
+		Name:           rule.Name,
+		Enabled:        rule.Enabled,
+		StopProcessing: rule.StopProcessing,
+		MatchCount:     rule.MatchCount,
+		CreatedAt:      rule.CreatedAt.AsTime(),
+		UpdatedAt:      rule.UpdatedAt.AsTime(),
+	}
+	if c := rule.Conditions; c != nil {
+		result.InboxRuleConditions = store.InboxRuleConditions{
+			SenderName:     c.SenderName,
+			SenderPrefix:   c.SenderPrefix,
+			SubjectPattern: c.SubjectPattern,
+			TopicName:      c.TopicName,
+			Priority:       c.Priority,
+			MetadataKey:    c.MetadataKey,
+			MetadataValue:  c.MetadataValue,
+		}
+	}
+	if a := rule.Actions; a != nil {
+		result.InboxRuleActions = store.InboxRuleActions{
+			Archive:   a.Archive,
+			Star:      a.Star,
+			Snooze:    a.Snooze,
+			Category:  store.InboxCategory(a.Category),
+			ForwardTo: a.ForwardTo,
+			Label:     a.Label,
+		}
+	}
+	if rule.LastMatchedAt != nil {
+		lastMatched := rule.LastMatchedAt.AsTime()
+		result.LastMatchedAt = &lastMatched
+	}
+
+	return result
+}
+
+// protoToQuestion converts a proto Question to a store Question.
+func protoToQuestion(q *subtraterpc.Question) store.Question {
+	question := store.Question{
+		QuestionID:      q.QuestionId,
+		MessageID:       q.MessageId,
+		ThreadID:        q.ThreadId,
+		AskerID:         q.AskerId,
+		Question:        q.Question,
+		Options:         q.Options,
+		DefaultAnswer:   q.DefaultAnswer,
+		Timeout:         time.Duration(q.TimeoutSeconds) * time.Second,
+		Fallback:        q.Fallback,
+		State:           q.State,
+		Answer:          q.Answer,
+		AnsweredBy:      q.AnsweredBy,
+		AnswerMessageID: q.AnswerMessageId,
+		CreatedAt:       q.CreatedAt.AsTime(),
+	}
+	if q.TimeoutAt != nil {
+		timeoutAt := q.TimeoutAt.AsTime()
+		question.TimeoutAt = &timeoutAt
+	}
+	if q.EscalatedAt != nil {
+		escalatedAt := q.EscalatedAt.AsTime()
+		question.EscalatedAt = &escalatedAt
+	}
+	if q.AnsweredAt != nil {
+		answeredAt := q.AnsweredAt.AsTime()
+		question.AnsweredAt = &answeredAt
+	}
+
+	return question
+}
+
+// protoToRecipientGroup converts a proto RecipientGroup to a mail
+// RecipientGroup.
+func protoToRecipientGroup(g *subtraterpc.RecipientGroup) mail.RecipientGroup {
+	group := mail.RecipientGroup{
+		RecipientGroup: store.RecipientGroup{
+			ID:          g.Id,
+			Name:        g.Name,
+			Description: g.Description,
+			MemberCount: g.MemberCount,
+		},
+		Members: g.Members,
+	}
+	if g.CreatedAt != nil {
+		group.CreatedAt = g.CreatedAt.AsTime()
+	}
+
+	return group
+}
+
 // protoToStoreTopic converts a proto Topic to a store.Topic.
 func protoToStoreTopic(t *subtraterpc.Topic) store.Topic {
 	topic := store.Topic{
@@ -487,6 +1184,29 @@ func protoToInboxMessages(
 	return result
 }
 
+// protoToSearchResponse converts a proto search response, pairing each
+// result with its hit.
+func protoToSearchResponse(
+	resp *subtraterpc.SearchResponse,
+) mail.SearchResponse {
+	out := mail.SearchResponse{
+		Hits:       make([]mail.SearchHit, len(resp.Results)),
+		Total:      resp.Total,
+		NextCursor: resp.NextCursor,
+	}
+	for i, m := range resp.Results {
+		out.Hits[i].InboxMessage = protoToInboxMessage(m)
+		if i < len(resp.Hits) {
+			out.Hits[i].Snippet = resp.Hits[i].Snippet
+			out.Hits[i].SubjectHighlight =
+				resp.Hits[i].SubjectHighlight
+			out.Hits[i].Rank = resp.Hits[i].Rank
+		}
+	}
+
+	return out
+}
+
 // protoToInboxMessage converts a single proto message to mail.InboxMessage.
 func protoToInboxMessage(m *subtraterpc.InboxMessage) mail.InboxMessage {
 	msg := mail.InboxMessage{
@@ -499,6 +1219,7 @@ func protoToInboxMessage(m *subtraterpc.InboxMessage) mail.InboxMessage {
 		Body:       m.Body,
 		Priority:   protoToPriority(m.Priority),
 		State:      protoToState(m.State),
+		Labels:     m.Labels,
 	}
 
 	if m.CreatedAt != nil {
@@ -597,5 +1318,32 @@ func protoToState(s subtraterpc.MessageState) string {
 	}
 }
 
+// deliveryModeToProto converts a mail delivery mode to its proto enum.
+func deliveryModeToProto(m mail.DeliveryMode) subtraterpc.DeliveryMode {
+	if m == mail.DeliveryModePartial {
+		return subtraterpc.DeliveryMode_DELIVERY_MODE_PARTIAL
+	}
+
+	return subtraterpc.DeliveryMode_DELIVERY_MODE_ATOMIC
+}
+
+// protoToRecipientStatus converts a proto recipient status to its mail form.
+func protoToRecipientStatus(
+	s subtraterpc.RecipientStatus,
+) mail.RecipientStatus {
+	switch s {
+	case subtraterpc.RecipientStatus_RECIPIENT_STATUS_DELIVERED:
+		return mail.RecipientDelivered
+	case subtraterpc.RecipientStatus_RECIPIENT_STATUS_QUEUED:
+		return mail.RecipientQueued
+	case subtraterpc.RecipientStatus_RECIPIENT_STATUS_UNKNOWN:
+		return mail.RecipientUnknown
+	case subtraterpc.RecipientStatus_RECIPIENT_STATUS_REJECTED:
+		return mail.RecipientRejected
+	default:
+		return ""
+	}
+}
+
 // Compile-time interface check.
 var _ Backend = (*GRPCBackend)(nil)
@@ -8,6 +8,7 @@ import (
 	"github.com/roasbeef/subtrate/internal/build"
 	"github.com/roasbeef/subtrate/internal/db"
 	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/ratelimit"
 	"github.com/roasbeef/subtrate/internal/store"
 )
 
@@ -37,9 +38,16 @@ func NewServer(dbStore *db.Store) *Server {
 func NewServerWithConfig(cfg Config) *Server {
 	storage := store.FromDB(cfg.Store.DB())
 
+	// Tools use the database directly rather than the daemon's RPCs, so
+	// the mail service holds agents to their rate limits itself.
+	mailSvc := mail.NewService(mail.ServiceConfig{
+		Store:       storage,
+		RateLimiter: ratelimit.NewLimiter(storage),
+	})
+
 	backend := NewDirectBackend(DirectBackendConfig{
 		Storage:  storage,
-		MailSvc:  mail.NewServiceWithStore(storage),
+		MailSvc:  mailSvc,
 		MailRef:  cfg.MailActorRef,
 		Registry: agent.NewRegistry(cfg.Store),
 	})
@@ -80,8 +88,9 @@ func (s *Server) MCPServer() *mcp.Server {
 func (s *Server) registerTools() {
 	// Core mail tools.
 	mcp.AddTool(s.server, &mcp.Tool{
-		Name:        "send_mail",
-		Description: "Send a message to one or more agents",
+		Name: "send_mail",
+		Description: "Send a message to one or more agents, @groups or " +
+			"aliases, now or at a later time",
 	}, s.handleSendMail)
 
 	mcp.AddTool(s.server, &mcp.Tool{
@@ -148,10 +157,24 @@ func (s *Server) registerTools() {
 
 	// Query tools.
 	mcp.AddTool(s.server, &mcp.Tool{
-		Name:        "search",
-		Description: "Search messages using full-text search",
+		Name: "search",
+		Description: "Search messages with full-text terms and " +
+			"filters such as from:, is:unread, after: and " +
+			"has:diff. Returns ranked snippets, the total " +
+			"hit count and a cursor for the next page",
 	}, s.handleSearch)
 
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name: "save_search",
+		Description: "Save a search query under a name, to run later " +
+			"with the search tool's saved_search",
+	}, s.handleSaveSearch)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "list_saved_searches",
+		Description: "List an agent's saved searches by name",
+	}, s.handleListSavedSearches)
+
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "get_status",
 		Description: "Get mail status summary for an agent",
@@ -184,6 +207,26 @@ func (s *Server) registerTools() {
 		Description: "Look up an agent by name instead of ID",
 	}, s.handleGetAgentByName)
 
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name: "set_capabilities",
+		Description: "Declare an agent's languages, repos, tools, " +
+			"skills and max concurrency, so mail and tasks " +
+			"addressed to cap:<name> can be routed to it",
+	}, s.handleSetCapabilities)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name: "find_capable_agents",
+		Description: "Find agents declaring a capability such as " +
+			"go-review or language:go, with their status and " +
+			"unread mail",
+	}, s.handleFindCapableAgents)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name: "list_capability_routes",
+		Description: "List which agents mail and tasks addressed to " +
+			"cap:<name> were routed to, newest first",
+	}, s.handleListCapabilityRoutes)
+
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "heartbeat",
 		Description: "Send a liveness heartbeat for an agent",
@@ -194,4 +237,121 @@ func (s *Server) registerTools() {
 		Name:        "read_thread",
 		Description: "Read all messages in a conversation thread",
 	}, s.handleReadThread)
+
+	// Recipient group tools.
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "list_recipient_groups",
+		Description: "List named recipient groups",
+	}, s.handleListRecipientGroups)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_recipient_group",
+		Description: "Get a recipient group and its members",
+	}, s.handleGetRecipientGroup)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "create_recipient_group",
+		Description: "Create a recipient group addressable as @name",
+	}, s.handleCreateRecipientGroup)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "delete_recipient_group",
+		Description: "Delete a recipient group",
+	}, s.handleDeleteRecipientGroup)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "add_recipient_group_members",
+		Description: "Add agents to a recipient group",
+	}, s.handleAddRecipientGroupMembers)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "remove_recipient_group_members",
+		Description: "Remove agents from a recipient group",
+	}, s.handleRemoveRecipientGroupMembers)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name: "list_dead_letters",
+		Description: "List recipients an agent's messages could not " +
+			"be delivered to",
+	}, s.handleListDeadLetters)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name: "list_scheduled_messages",
+		Description: "List an agent's messages that are scheduled " +
+			"to be sent later",
+	}, s.handleListScheduledMessages)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "cancel_scheduled_message",
+		Description: "Cancel a scheduled message before it is sent",
+	}, s.handleCancelScheduledMessage)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name: "ask_question",
+		Description: "Ask agents or the user a question with " +
+			"optional suggested answers, a default and a timeout",
+	}, s.handleAskQuestion)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name: "get_question",
+		Description: "Check whether a question has been answered and " +
+			"get its answer",
+	}, s.handleGetQuestion)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "answer_question",
+		Description: "Answer a question asked through mail",
+	}, s.handleAnswerQuestion)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name: "edit_message",
+		Description: "Edit the subject or body of a message you sent, " +
+			"keeping the old version in its revision history",
+	}, s.handleEditMessage)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name: "recall_message",
+		Description: "Recall a message you sent that no recipient has " +
+			"read yet",
+	}, s.handleRecallMessage)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name: "create_inbox_rule",
+		Description: "Add a rule that archives, stars, snoozes, " +
+			"categorizes or forwards matching incoming messages",
+	}, s.handleCreateInboxRule)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name: "list_inbox_rules",
+		Description: "List an agent's inbox rules in the order they " +
+			"run",
+	}, s.handleListInboxRules)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "delete_inbox_rule",
+		Description: "Delete one of an agent's inbox rules",
+	}, s.handleDeleteInboxRule)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "set_inbox_rule_enabled",
+		Description: "Enable or disable one of an agent's inbox rules",
+	}, s.handleSetInboxRuleEnabled)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name: "test_inbox_rule",
+		Description: "Show which recently received messages an inbox " +
+			"rule would match, without changing them",
+	}, s.handleTestInboxRule)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name: "list_labels",
+		Description: "List an agent's labels with how many messages " +
+			"each carries",
+	}, s.handleListLabels)
+
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name: "label_message",
+		Description: "Add labels to or remove labels from a received " +
+			"message, creating new labels as needed",
+	}, s.handleLabelMessage)
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/roasbeef/subtrate/internal/mail"
+	"github.com/roasbeef/subtrate/internal/store"
 )
 
 // requireAgentID validates that agent_id is non-zero. MCP clients that
@@ -28,8 +29,9 @@ type SendMailArgs struct {
 	// AgentID is the sending agent's ID.
 	AgentID int64 `json:"agent_id" jsonschema:"ID of the sending agent"`
 
-	// Recipients is a list of recipient agent names.
-	Recipients []string `json:"recipients" jsonschema:"List of recipient agent names"`
+	// Recipients is a list of recipient agent names, @group names or
+	// dynamic aliases.
+	Recipients []string `json:"recipients" jsonschema:"List of recipient agent names, @group names, or aliases such as @project:<name>, @branch:<name>, @online and @reviewers"`
 
 	// Subject is the message subject line.
 	Subject string `json:"subject" jsonschema:"Message subject line"`
@@ -42,12 +44,44 @@ type SendMailArgs struct {
 
 	// ThreadID is an optional thread ID for threading messages.
 	ThreadID string `json:"thread_id,omitempty" jsonschema:"Optional thread ID for threading related messages"`
+
+	// Partial delivers to every reachable recipient instead of failing
+	// the whole send.
+	Partial bool `json:"partial,omitempty" jsonschema:"Deliver to every reachable recipient and dead-letter the rest instead of failing the whole send"`
+
+	// SendAt is an optional RFC3339 time to send the message at.
+	SendAt string `json:"send_at,omitempty" jsonschema:"Optional RFC3339 time to send the message at instead of now"`
+
+	// SendIn is an optional delay before the message is sent.
+	SendIn string `json:"send_in,omitempty" jsonschema:"Optional delay before the message is sent, e.g. 2h or 30m"`
+
+	// Metadata is optional key/value metadata that recipients' inbox
+	// rules can match on.
+	Metadata map[string]string `json:"metadata,omitempty" jsonschema:"Optional key/value metadata that recipients' inbox rules can match on"`
 }
 
 // SendMailResult is the result of the send_mail tool.
 type SendMailResult struct {
-	MessageID int64  `json:"message_id"`
-	ThreadID  string `json:"thread_id"`
+	MessageID   int64                      `json:"message_id,omitempty"`
+	ThreadID    string                     `json:"thread_id"`
+	Expansions  []RecipientExpansionResult `json:"expansions,omitempty"`
+	Deliveries  []RecipientDeliveryResult  `json:"deliveries,omitempty"`
+	ScheduledID int64                      `json:"scheduled_id,omitempty"`
+	SendAt      string                     `json:"send_at,omitempty"`
+}
+
+// RecipientDeliveryResult is the delivery outcome for one recipient.
+type RecipientDeliveryResult struct {
+	Recipient string `json:"recipient"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// RecipientExpansionResult records the agents a group or alias recipient
+// expanded to.
+type RecipientExpansionResult struct {
+	Address    string   `json:"address"`
+	AgentNames []string `json:"agent_names"`
 }
 
 // handleSendMail sends a message to one or more agent recipients.
@@ -63,29 +97,106 @@ func (s *Server) handleSendMail(ctx context.Context,
 		priority = mail.PriorityNormal
 	}
 
-	resp, err := s.backend.SendMail(ctx, mail.SendMailRequest{
+	sendReq := mail.SendMailRequest{
 		SenderID:       args.AgentID,
 		RecipientNames: args.Recipients,
 		Subject:        args.Subject,
 		Body:           args.Body,
 		Priority:       priority,
 		ThreadID:       args.ThreadID,
-	})
+		Metadata:       args.Metadata,
+	}
+	if args.Partial {
+		sendReq.DeliveryMode = mail.DeliveryModePartial
+	}
+
+	sendAt, err := parseSendAt(args.SendAt, args.SendIn)
 	if err != nil {
 		return nil, SendMailResult{}, err
 	}
+	sendReq.SendAt = sendAt
+
+	resp, err := s.backend.SendMail(ctx, sendReq)
+	if err != nil {
+		return nil, SendMailResult{}, err
+	}
+
+	if resp.ScheduledID != 0 {
+		return nil, SendMailResult{
+			ThreadID:    resp.ThreadID,
+			ScheduledID: resp.ScheduledID,
+			SendAt:      sendAt.Format(time.RFC3339),
+		}, nil
+	}
+
+	expansions := make(
+		[]RecipientExpansionResult, 0, len(resp.Expansions),
+	)
+	for _, e := range resp.Expansions {
+		expansions = append(expansions, RecipientExpansionResult{
+			Address:    e.Address,
+			AgentNames: e.AgentNames,
+		})
+	}
+
+	deliveries := make(
+		[]RecipientDeliveryResult, 0, len(resp.Deliveries),
+	)
+	for _, d := range resp.Deliveries {
+		deliveries = append(deliveries, RecipientDeliveryResult{
+			Recipient: d.Recipient,
+			Status:    string(d.Status),
+			Reason:    d.Reason,
+		})
+	}
 
 	return nil, SendMailResult{
-		MessageID: resp.MessageID,
-		ThreadID:  resp.ThreadID,
+		MessageID:  resp.MessageID,
+		ThreadID:   resp.ThreadID,
+		Expansions: expansions,
+		Deliveries: deliveries,
 	}, nil
 }
 
+// parseSendAt resolves the send_at and send_in arguments of send_mail into
+// the time to send at, or nil to send now.
+func parseSendAt(sendAt, sendIn string) (*time.Time, error) {
+	switch {
+	case sendAt != "" && sendIn != "":
+		return nil, fmt.Errorf("only one of send_at and send_in may " +
+			"be given")
+
+	case sendAt != "":
+		t, err := time.Parse(time.RFC3339, sendAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid send_at: %w", err)
+		}
+
+		return &t, nil
+
+	case sendIn != "":
+		d, err := time.ParseDuration(sendIn)
+		if err != nil {
+			return nil, fmt.Errorf("invalid send_in: %w", err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("send_in must be positive")
+		}
+		t := time.Now().Add(d)
+
+		return &t, nil
+
+	default:
+		return nil, nil
+	}
+}
+
 // FetchInboxArgs are the arguments for the fetch_inbox tool.
 type FetchInboxArgs struct {
-	AgentID    int64 `json:"agent_id" jsonschema:"ID of the agent to fetch inbox for"`
-	Limit      int   `json:"limit,omitempty" jsonschema:"Maximum number of messages to return,default=50"`
-	UnreadOnly bool  `json:"unread_only,omitempty" jsonschema:"Only return unread messages"`
+	AgentID    int64  `json:"agent_id" jsonschema:"ID of the agent to fetch inbox for"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"Maximum number of messages to return,default=50"`
+	UnreadOnly bool   `json:"unread_only,omitempty" jsonschema:"Only return unread messages"`
+	Label      string `json:"label,omitempty" jsonschema:"Only return messages filed under this label"`
 }
 
 // FetchInboxResult is the result of the fetch_inbox tool.
@@ -95,15 +206,16 @@ type FetchInboxResult struct {
 
 // InboxMessageResult is a message in the inbox.
 type InboxMessageResult struct {
-	ID         int64  `json:"id"`
-	ThreadID   string `json:"thread_id"`
-	SenderID   int64  `json:"sender_id"`
-	SenderName string `json:"sender_name,omitempty"`
-	Subject    string `json:"subject"`
-	Body       string `json:"body,omitempty"`
-	Priority   string `json:"priority"`
-	State      string `json:"state"`
-	CreatedAt  string `json:"created_at"`
+	ID         int64    `json:"id"`
+	ThreadID   string   `json:"thread_id"`
+	SenderID   int64    `json:"sender_id"`
+	SenderName string   `json:"sender_name,omitempty"`
+	Subject    string   `json:"subject"`
+	Body       string   `json:"body,omitempty"`
+	Priority   string   `json:"priority"`
+	State      string   `json:"state"`
+	Labels     []string `json:"labels,omitempty"`
+	CreatedAt  string   `json:"created_at"`
 }
 
 // handleFetchInbox retrieves inbox messages for an agent with pagination.
@@ -122,7 +234,8 @@ func (s *Server) handleFetchInbox(ctx context.Context,
 	resp, err := s.backend.FetchInbox(ctx, mail.FetchInboxRequest{
 		AgentID:    args.AgentID,
 		Limit:      limit,
-		UnreadOnly: args.UnreadOnly,
+		UnreadOnly: args.UnreadOnly && args.Label == "",
+		Label:      args.Label,
 	})
 	if err != nil {
 		return nil, FetchInboxResult{}, err
@@ -138,6 +251,7 @@ func (s *Server) handleFetchInbox(ctx context.Context,
 			Subject:    m.Subject,
 			Priority:   string(m.Priority),
 			State:      m.State,
+			Labels:     m.Labels,
 			CreatedAt:  m.CreatedAt.Format(time.RFC3339),
 		})
 	}
@@ -161,6 +275,7 @@ type ReadMessageResult struct {
 	Priority  string `json:"priority"`
 	State     string `json:"state"`
 	CreatedAt string `json:"created_at"`
+	EditedAt  string `json:"edited_at,omitempty"`
 }
 
 // handleReadMessage reads a specific message and marks it as read.
@@ -181,7 +296,7 @@ func (s *Server) handleReadMessage(ctx context.Context,
 	}
 
 	m := resp.Message
-	return nil, ReadMessageResult{
+	result := ReadMessageResult{
 		ID:        m.ID,
 		ThreadID:  m.ThreadID,
 		SenderID:  m.SenderID,
@@ -190,7 +305,12 @@ func (s *Server) handleReadMessage(ctx context.Context,
 		Priority:  string(m.Priority),
 		State:     m.State,
 		CreatedAt: m.CreatedAt.Format(time.RFC3339),
-	}, nil
+	}
+	if m.EditedAt != nil {
+		result.EditedAt = m.EditedAt.Format(time.RFC3339)
+	}
+
+	return nil, result, nil
 }
 
 // AckMessageArgs are the arguments for the ack_message tool.
@@ -467,6 +587,8 @@ type PublishArgs struct {
 	Subject   string `json:"subject" jsonschema:"Message subject line"`
 	Body      string `json:"body" jsonschema:"Message body in markdown format"`
 	Priority  string `json:"priority,omitempty" jsonschema:"Priority: urgent, normal, or low,default=normal"`
+
+	Metadata map[string]string `json:"metadata,omitempty" jsonschema:"Optional key/value metadata that subscribers' inbox rules can match on"`
 }
 
 // PublishResult is the result of the publish tool.
@@ -494,6 +616,7 @@ func (s *Server) handlePublish(ctx context.Context,
 		Subject:   args.Subject,
 		Body:      args.Body,
 		Priority:  priority,
+		Metadata:  args.Metadata,
 	})
 	if err != nil {
 		return nil, PublishResult{}, err
@@ -507,17 +630,31 @@ func (s *Server) handlePublish(ctx context.Context,
 
 // SearchArgs are the arguments for the search tool.
 type SearchArgs struct {
-	AgentID int64  `json:"agent_id" jsonschema:"ID of the agent to search for"`
-	Query   string `json:"query" jsonschema:"Search query string"`
-	Limit   int    `json:"limit,omitempty" jsonschema:"Maximum number of results,default=20"`
+	AgentID     int64  `json:"agent_id" jsonschema:"ID of the agent to search for"`
+	Query       string `json:"query,omitempty" jsonschema:"Search query: words, \"phrases\", OR, -excluded, prefix*, and from:, to:, topic:, thread:, label:, priority:, state:, is:unread, is:starred, before:, after:, has:diff, has:attachment filters"`
+	Label       string `json:"label,omitempty" jsonschema:"Only search messages filed under this label"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"Maximum number of results,default=20"`
+	Cursor      string `json:"cursor,omitempty" jsonschema:"next_cursor of the previous page of results"`
+	SavedSearch string `json:"saved_search,omitempty" jsonschema:"Name of a saved search to run instead, narrowed by query if one is given"`
+}
+
+// SearchHitResult is a message matched by the search tool. It carries a
+// snippet around the matched terms rather than the whole body.
+type SearchHitResult struct {
+	InboxMessageResult
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank,omitempty"`
 }
 
 // SearchResult is the result of the search tool.
 type SearchResult struct {
-	Results []InboxMessageResult `json:"results"`
+	Results    []SearchHitResult `json:"results"`
+	Total      int64             `json:"total"`
+	NextCursor string            `json:"next_cursor,omitempty"`
 }
 
-// handleSearch performs full-text search across messages for an agent.
+// handleSearch performs full-text search across messages for an agent,
+// returning a page of ranked snippets.
 func (s *Server) handleSearch(ctx context.Context,
 	req *mcp.CallToolRequest, args SearchArgs,
 ) (*mcp.CallToolResult, SearchResult, error) {
@@ -530,28 +667,114 @@ func (s *Server) handleSearch(ctx context.Context,
 		limit = 20
 	}
 
-	messages, err := s.backend.SearchMessages(
-		ctx, args.Query, args.AgentID, limit,
-	)
+	resp, err := s.backend.SearchMessages(ctx, mail.SearchRequest{
+		AgentID:     args.AgentID,
+		Query:       args.Query,
+		Label:       args.Label,
+		Limit:       int32(limit),
+		Cursor:      args.Cursor,
+		SavedSearch: args.SavedSearch,
+	})
 	if err != nil {
 		return nil, SearchResult{},
 			fmt.Errorf("search failed: %w", err)
 	}
 
-	results := make([]InboxMessageResult, 0, len(messages))
-	for _, m := range messages {
-		results = append(results, InboxMessageResult{
-			ID:        m.ID,
-			ThreadID:  m.ThreadID,
-			SenderID:  m.SenderID,
-			Subject:   m.Subject,
-			Priority:  m.Priority,
-			State:     "",
-			CreatedAt: m.CreatedAt.Format(time.RFC3339),
+	results := make([]SearchHitResult, 0, len(resp.Hits))
+	for _, h := range resp.Hits {
+		results = append(results, SearchHitResult{
+			InboxMessageResult: InboxMessageResult{
+				ID:         h.ID,
+				ThreadID:   h.ThreadID,
+				SenderID:   h.SenderID,
+				SenderName: h.SenderName,
+				Subject:    h.Subject,
+				Priority:   string(h.Priority),
+				State:      h.State,
+				Labels:     h.Labels,
+				CreatedAt:  h.CreatedAt.Format(time.RFC3339),
+			},
+			Snippet: h.Snippet,
+			Rank:    h.Rank,
 		})
 	}
 
-	return nil, SearchResult{Results: results}, nil
+	return nil, SearchResult{
+		Results:    results,
+		Total:      resp.Total,
+		NextCursor: resp.NextCursor,
+	}, nil
+}
+
+// SaveSearchArgs are the arguments for the save_search tool.
+type SaveSearchArgs struct {
+	AgentID int64  `json:"agent_id" jsonschema:"ID of the agent saving the search"`
+	Name    string `json:"name" jsonschema:"Name to save the search under: lowercase letters, digits, '.', '_', '/' and '-'"`
+	Query   string `json:"query" jsonschema:"Search query, in the language of the search tool"`
+}
+
+// SavedSearchResult is a search query saved under a name.
+type SavedSearchResult struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// handleSaveSearch saves a search query under a name, replacing the query
+// of one already saved under it.
+func (s *Server) handleSaveSearch(ctx context.Context,
+	req *mcp.CallToolRequest, args SaveSearchArgs,
+) (*mcp.CallToolResult, SavedSearchResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, SavedSearchResult{}, err
+	}
+
+	saved, err := s.backend.SaveSearch(
+		ctx, args.AgentID, args.Name, args.Query,
+	)
+	if err != nil {
+		return nil, SavedSearchResult{},
+			fmt.Errorf("failed to save search: %w", err)
+	}
+
+	return nil, SavedSearchResult{
+		Name:  saved.Name,
+		Query: saved.Query,
+	}, nil
+}
+
+// ListSavedSearchesArgs are the arguments for the list_saved_searches tool.
+type ListSavedSearchesArgs struct {
+	AgentID int64 `json:"agent_id" jsonschema:"ID of the agent whose saved searches to list"`
+}
+
+// ListSavedSearchesResult is the result of the list_saved_searches tool.
+type ListSavedSearchesResult struct {
+	Searches []SavedSearchResult `json:"searches"`
+}
+
+// handleListSavedSearches lists an agent's saved searches by name.
+func (s *Server) handleListSavedSearches(ctx context.Context,
+	req *mcp.CallToolRequest, args ListSavedSearchesArgs,
+) (*mcp.CallToolResult, ListSavedSearchesResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, ListSavedSearchesResult{}, err
+	}
+
+	searches, err := s.backend.ListSavedSearches(ctx, args.AgentID)
+	if err != nil {
+		return nil, ListSavedSearchesResult{},
+			fmt.Errorf("failed to list saved searches: %w", err)
+	}
+
+	results := make([]SavedSearchResult, 0, len(searches))
+	for _, saved := range searches {
+		results = append(results, SavedSearchResult{
+			Name:  saved.Name,
+			Query: saved.Query,
+		})
+	}
+
+	return nil, ListSavedSearchesResult{Searches: results}, nil
 }
 
 // GetStatusArgs are the arguments for the get_status tool.
@@ -772,6 +995,165 @@ func (s *Server) handleGetAgentByName(ctx context.Context,
 	}, nil
 }
 
+// SetCapabilitiesArgs are the arguments for the set_capabilities tool.
+type SetCapabilitiesArgs struct {
+	AgentID        int64    `json:"agent_id" jsonschema:"ID of the agent declaring the capabilities"`
+	Languages      []string `json:"languages,omitempty" jsonschema:"Programming languages the agent works in, such as go"`
+	Repos          []string `json:"repos,omitempty" jsonschema:"Repositories the agent knows, such as roasbeef/substrate"`
+	Tools          []string `json:"tools,omitempty" jsonschema:"Tools the agent has, such as MCP servers"`
+	Skills         []string `json:"skills,omitempty" jsonschema:"Anything else the agent can do, such as can-review-security"`
+	MaxConcurrency int64    `json:"max_concurrency,omitempty" jsonschema:"Most tasks the agent takes on at once, 0 for no limit"`
+}
+
+// CapabilitiesResult is the set of capabilities an agent declares.
+type CapabilitiesResult struct {
+	Languages      []string `json:"languages,omitempty"`
+	Repos          []string `json:"repos,omitempty"`
+	Tools          []string `json:"tools,omitempty"`
+	Skills         []string `json:"skills,omitempty"`
+	MaxConcurrency int64    `json:"max_concurrency,omitempty"`
+}
+
+// capabilitiesResult converts an agent's capabilities to their tool form.
+func capabilitiesResult(caps mail.AgentCapabilities) CapabilitiesResult {
+	return CapabilitiesResult{
+		Languages:      caps.Languages,
+		Repos:          caps.Repos,
+		Tools:          caps.Tools,
+		Skills:         caps.Skills,
+		MaxConcurrency: caps.MaxConcurrency,
+	}
+}
+
+// handleSetCapabilities replaces the capabilities an agent declares.
+func (s *Server) handleSetCapabilities(ctx context.Context,
+	req *mcp.CallToolRequest, args SetCapabilitiesArgs,
+) (*mcp.CallToolResult, CapabilitiesResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, CapabilitiesResult{}, err
+	}
+
+	caps, err := s.backend.SetAgentCapabilities(
+		ctx, args.AgentID, mail.AgentCapabilities{
+			Languages:      args.Languages,
+			Repos:          args.Repos,
+			Tools:          args.Tools,
+			Skills:         args.Skills,
+			MaxConcurrency: args.MaxConcurrency,
+		},
+	)
+	if err != nil {
+		return nil, CapabilitiesResult{},
+			fmt.Errorf("failed to set capabilities: %w", err)
+	}
+
+	return nil, capabilitiesResult(caps), nil
+}
+
+// FindCapableAgentsArgs are the arguments for the find_capable_agents
+// tool.
+type FindCapableAgentsArgs struct {
+	Capability string `json:"capability,omitempty" jsonschema:"Capability to match, such as go-review or language:go; empty lists every agent"`
+}
+
+// CapableAgentInfo is an agent found by its capabilities.
+type CapableAgentInfo struct {
+	ID           int64              `json:"id"`
+	Name         string             `json:"name"`
+	ProjectKey   string             `json:"project_key,omitempty"`
+	Status       string             `json:"status"`
+	UnreadCount  int64              `json:"unread_count"`
+	Capabilities CapabilitiesResult `json:"capabilities"`
+}
+
+// FindCapableAgentsResult is the result of the find_capable_agents tool.
+type FindCapableAgentsResult struct {
+	Agents []CapableAgentInfo `json:"agents"`
+}
+
+// handleFindCapableAgents finds the agents declaring a capability.
+func (s *Server) handleFindCapableAgents(ctx context.Context,
+	req *mcp.CallToolRequest, args FindCapableAgentsArgs,
+) (*mcp.CallToolResult, FindCapableAgentsResult, error) {
+	agents, err := s.backend.FindCapableAgents(ctx, args.Capability)
+	if err != nil {
+		return nil, FindCapableAgentsResult{},
+			fmt.Errorf("failed to find agents: %w", err)
+	}
+
+	results := make([]CapableAgentInfo, 0, len(agents))
+	for _, a := range agents {
+		results = append(results, CapableAgentInfo{
+			ID:           a.ID,
+			Name:         a.Name,
+			ProjectKey:   a.ProjectKey,
+			Status:       a.Status,
+			UnreadCount:  a.UnreadCount,
+			Capabilities: capabilitiesResult(a.Capabilities),
+		})
+	}
+
+	return nil, FindCapableAgentsResult{Agents: results}, nil
+}
+
+// ListCapabilityRoutesArgs are the arguments for the
+// list_capability_routes tool.
+type ListCapabilityRoutesArgs struct {
+	Capability string `json:"capability,omitempty" jsonschema:"Only list routes for this capability"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"Maximum number of routes to return (default 50)"`
+}
+
+// CapabilityRouteInfo records which agent a capability address was routed
+// to.
+type CapabilityRouteInfo struct {
+	Capability      string `json:"capability"`
+	AgentID         int64  `json:"agent_id"`
+	AgentName       string `json:"agent_name"`
+	MessageID       int64  `json:"message_id,omitempty"`
+	TaskID          int64  `json:"task_id,omitempty"`
+	Candidates      int64  `json:"candidates"`
+	UnreadCount     int64  `json:"unread_count"`
+	InProgressCount int64  `json:"in_progress_count"`
+	CreatedAt       string `json:"created_at"`
+}
+
+// ListCapabilityRoutesResult is the result of the list_capability_routes
+// tool.
+type ListCapabilityRoutesResult struct {
+	Routes []CapabilityRouteInfo `json:"routes"`
+}
+
+// handleListCapabilityRoutes lists which agents capability addresses were
+// routed to.
+func (s *Server) handleListCapabilityRoutes(ctx context.Context,
+	req *mcp.CallToolRequest, args ListCapabilityRoutesArgs,
+) (*mcp.CallToolResult, ListCapabilityRoutesResult, error) {
+	routes, err := s.backend.ListCapabilityRoutes(
+		ctx, args.Capability, args.Limit,
+	)
+	if err != nil {
+		return nil, ListCapabilityRoutesResult{},
+			fmt.Errorf("failed to list routes: %w", err)
+	}
+
+	results := make([]CapabilityRouteInfo, 0, len(routes))
+	for _, r := range routes {
+		results = append(results, CapabilityRouteInfo{
+			Capability:      r.Capability,
+			AgentID:         r.AgentID,
+			AgentName:       r.AgentName,
+			MessageID:       r.MessageID,
+			TaskID:          r.TaskID,
+			Candidates:      r.Candidates,
+			UnreadCount:     r.UnreadCount,
+			InProgressCount: r.InProgressCount,
+			CreatedAt:       r.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return nil, ListCapabilityRoutesResult{Routes: results}, nil
+}
+
 // HeartbeatArgs are the arguments for the heartbeat tool.
 type HeartbeatArgs struct {
 	AgentID int64 `json:"agent_id" jsonschema:"ID of the agent sending the heartbeat"`
@@ -836,3 +1218,856 @@ func (s *Server) handleReadThread(ctx context.Context,
 
 	return nil, ReadThreadResult{Messages: result}, nil
 }
+
+// RecipientGroupResult is a recipient group in tool results.
+type RecipientGroupResult struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	MemberCount int64    `json:"member_count"`
+	Members     []string `json:"members,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+// recipientGroupResult converts a mail recipient group to a tool result.
+func recipientGroupResult(g mail.RecipientGroup) RecipientGroupResult {
+	return RecipientGroupResult{
+		ID:          g.ID,
+		Name:        g.Name,
+		Description: g.Description,
+		MemberCount: g.MemberCount,
+		Members:     g.Members,
+		CreatedAt:   g.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ListRecipientGroupsArgs are the arguments for the list_recipient_groups
+// tool.
+type ListRecipientGroupsArgs struct{}
+
+// ListRecipientGroupsResult is the result of the list_recipient_groups tool.
+type ListRecipientGroupsResult struct {
+	Groups []RecipientGroupResult `json:"groups"`
+}
+
+// handleListRecipientGroups lists all recipient groups.
+func (s *Server) handleListRecipientGroups(ctx context.Context,
+	req *mcp.CallToolRequest, args ListRecipientGroupsArgs,
+) (*mcp.CallToolResult, ListRecipientGroupsResult, error) {
+	groups, err := s.backend.ListRecipientGroups(ctx)
+	if err != nil {
+		return nil, ListRecipientGroupsResult{},
+			fmt.Errorf("failed to list groups: %w", err)
+	}
+
+	result := make([]RecipientGroupResult, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, recipientGroupResult(
+			mail.RecipientGroup{RecipientGroup: g},
+		))
+	}
+
+	return nil, ListRecipientGroupsResult{Groups: result}, nil
+}
+
+// GetRecipientGroupArgs are the arguments for the get_recipient_group tool.
+type GetRecipientGroupArgs struct {
+	Name string `json:"name" jsonschema:"Name of the group, with or without the leading @"`
+}
+
+// handleGetRecipientGroup returns a recipient group and its members.
+func (s *Server) handleGetRecipientGroup(ctx context.Context,
+	req *mcp.CallToolRequest, args GetRecipientGroupArgs,
+) (*mcp.CallToolResult, RecipientGroupResult, error) {
+	group, err := s.backend.GetRecipientGroup(ctx, args.Name)
+	if err != nil {
+		return nil, RecipientGroupResult{},
+			fmt.Errorf("failed to get group: %w", err)
+	}
+
+	return nil, recipientGroupResult(group), nil
+}
+
+// CreateRecipientGroupArgs are the arguments for the create_recipient_group
+// tool.
+type CreateRecipientGroupArgs struct {
+	AgentID     int64    `json:"agent_id,omitempty" jsonschema:"ID of the agent creating the group"`
+	Name        string   `json:"name" jsonschema:"Name of the group (lowercase letters, digits, dots, dashes and underscores)"`
+	Description string   `json:"description,omitempty" jsonschema:"Description of the group"`
+	Members     []string `json:"members,omitempty" jsonschema:"Agent names to add to the group"`
+}
+
+// handleCreateRecipientGroup creates a recipient group.
+func (s *Server) handleCreateRecipientGroup(ctx context.Context,
+	req *mcp.CallToolRequest, args CreateRecipientGroupArgs,
+) (*mcp.CallToolResult, RecipientGroupResult, error) {
+	group, err := s.backend.CreateRecipientGroup(
+		ctx, args.Name, args.Description, args.AgentID, args.Members,
+	)
+	if err != nil {
+		return nil, RecipientGroupResult{},
+			fmt.Errorf("failed to create group: %w", err)
+	}
+
+	return nil, recipientGroupResult(group), nil
+}
+
+// DeleteRecipientGroupArgs are the arguments for the delete_recipient_group
+// tool.
+type DeleteRecipientGroupArgs struct {
+	Name string `json:"name" jsonschema:"Name of the group to delete"`
+}
+
+// DeleteRecipientGroupResult is the result of the delete_recipient_group
+// tool.
+type DeleteRecipientGroupResult struct {
+	Success bool `json:"success"`
+}
+
+// handleDeleteRecipientGroup deletes a recipient group.
+func (s *Server) handleDeleteRecipientGroup(ctx context.Context,
+	req *mcp.CallToolRequest, args DeleteRecipientGroupArgs,
+) (*mcp.CallToolResult, DeleteRecipientGroupResult, error) {
+	err := s.backend.DeleteRecipientGroup(ctx, args.Name)
+	if err != nil {
+		return nil, DeleteRecipientGroupResult{},
+			fmt.Errorf("failed to delete group: %w", err)
+	}
+
+	return nil, DeleteRecipientGroupResult{Success: true}, nil
+}
+
+// RecipientGroupMembersArgs are the arguments for the
+// add_recipient_group_members and remove_recipient_group_members tools.
+type RecipientGroupMembersArgs struct {
+	Name    string   `json:"name" jsonschema:"Name of the group"`
+	Members []string `json:"members" jsonschema:"Agent names to add or remove"`
+}
+
+// handleAddRecipientGroupMembers adds agents to a recipient group.
+func (s *Server) handleAddRecipientGroupMembers(ctx context.Context,
+	req *mcp.CallToolRequest, args RecipientGroupMembersArgs,
+) (*mcp.CallToolResult, RecipientGroupResult, error) {
+	group, err := s.backend.AddRecipientGroupMembers(
+		ctx, args.Name, args.Members,
+	)
+	if err != nil {
+		return nil, RecipientGroupResult{},
+			fmt.Errorf("failed to add members: %w", err)
+	}
+
+	return nil, recipientGroupResult(group), nil
+}
+
+// handleRemoveRecipientGroupMembers removes agents from a recipient group.
+func (s *Server) handleRemoveRecipientGroupMembers(ctx context.Context,
+	req *mcp.CallToolRequest, args RecipientGroupMembersArgs,
+) (*mcp.CallToolResult, RecipientGroupResult, error) {
+	group, err := s.backend.RemoveRecipientGroupMembers(
+		ctx, args.Name, args.Members,
+	)
+	if err != nil {
+		return nil, RecipientGroupResult{},
+			fmt.Errorf("failed to remove members: %w", err)
+	}
+
+	return nil, recipientGroupResult(group), nil
+}
+
+// ListDeadLettersArgs are the arguments for the list_dead_letters tool.
+type ListDeadLettersArgs struct {
+	AgentID int64 `json:"agent_id" jsonschema:"ID of the sending agent whose dead letters to list"`
+	Limit   int   `json:"limit,omitempty" jsonschema:"Maximum number of dead letters to return,default=50"`
+}
+
+// DeadLetterResult is an undeliverable recipient of a message.
+type DeadLetterResult struct {
+	ID        int64  `json:"id"`
+	MessageID int64  `json:"message_id"`
+	ThreadID  string `json:"thread_id"`
+	Subject   string `json:"subject"`
+	Recipient string `json:"recipient"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ListDeadLettersResult is the result of the list_dead_letters tool.
+type ListDeadLettersResult struct {
+	DeadLetters []DeadLetterResult `json:"dead_letters"`
+}
+
+// handleListDeadLetters lists the recipients an agent's messages could not
+// be delivered to.
+func (s *Server) handleListDeadLetters(ctx context.Context,
+	req *mcp.CallToolRequest, args ListDeadLettersArgs,
+) (*mcp.CallToolResult, ListDeadLettersResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, ListDeadLettersResult{}, err
+	}
+
+	letters, err := s.backend.ListDeadLetters(ctx, args.AgentID, args.Limit)
+	if err != nil {
+		return nil, ListDeadLettersResult{},
+			fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	result := make([]DeadLetterResult, 0, len(letters))
+	for _, l := range letters {
+		result = append(result, DeadLetterResult{
+			ID:        l.ID,
+			MessageID: l.MessageID,
+			ThreadID:  l.ThreadID,
+			Subject:   l.Subject,
+			Recipient: l.Recipient,
+			Status:    l.Status,
+			Reason:    l.Reason,
+			CreatedAt: l.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return nil, ListDeadLettersResult{DeadLetters: result}, nil
+}
+
+// ListScheduledMessagesArgs are the arguments for the
+// list_scheduled_messages tool.
+type ListScheduledMessagesArgs struct {
+	AgentID int64 `json:"agent_id" jsonschema:"ID of the sending agent whose scheduled messages to list"`
+	Limit   int   `json:"limit,omitempty" jsonschema:"Maximum number of scheduled messages to return,default=50"`
+}
+
+// ScheduledMessageResult is a message scheduled to be sent later.
+type ScheduledMessageResult struct {
+	ID         int64    `json:"id"`
+	Recipients []string `json:"recipients,omitempty"`
+	TopicName  string   `json:"topic_name,omitempty"`
+	ThreadID   string   `json:"thread_id"`
+	Subject    string   `json:"subject"`
+	SendAt     string   `json:"send_at"`
+	State      string   `json:"state"`
+	LastError  string   `json:"last_error,omitempty"`
+}
+
+// ListScheduledMessagesResult is the result of the list_scheduled_messages
+// tool.
+type ListScheduledMessagesResult struct {
+	Messages []ScheduledMessageResult `json:"messages"`
+}
+
+// handleListScheduledMessages lists an agent's scheduled messages that have
+// not been sent or canceled.
+func (s *Server) handleListScheduledMessages(ctx context.Context,
+	req *mcp.CallToolRequest, args ListScheduledMessagesArgs,
+) (*mcp.CallToolResult, ListScheduledMessagesResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, ListScheduledMessagesResult{}, err
+	}
+
+	msgs, err := s.backend.ListScheduledMessages(
+		ctx, args.AgentID, args.Limit,
+	)
+	if err != nil {
+		return nil, ListScheduledMessagesResult{},
+			fmt.Errorf("failed to list scheduled messages: %w", err)
+	}
+
+	result := make([]ScheduledMessageResult, 0, len(msgs))
+	for _, m := range msgs {
+		result = append(result, ScheduledMessageResult{
+			ID:         m.ID,
+			Recipients: m.RecipientNames,
+			TopicName:  m.TopicName,
+			ThreadID:   m.ThreadID,
+			Subject:    m.Subject,
+			SendAt:     m.SendAt.Format(time.RFC3339),
+			State:      m.State,
+			LastError:  m.LastError,
+		})
+	}
+
+	return nil, ListScheduledMessagesResult{Messages: result}, nil
+}
+
+// CancelScheduledMessageArgs are the arguments for the
+// cancel_scheduled_message tool.
+type CancelScheduledMessageArgs struct {
+	AgentID int64 `json:"agent_id" jsonschema:"ID of the agent that scheduled the message"`
+	ID      int64 `json:"id" jsonschema:"ID of the scheduled message to cancel"`
+}
+
+// CancelScheduledMessageResult is the result of the
+// cancel_scheduled_message tool.
+type CancelScheduledMessageResult struct {
+	Success bool `json:"success"`
+}
+
+// handleCancelScheduledMessage cancels a scheduled message before it is
+// sent.
+func (s *Server) handleCancelScheduledMessage(ctx context.Context,
+	req *mcp.CallToolRequest, args CancelScheduledMessageArgs,
+) (*mcp.CallToolResult, CancelScheduledMessageResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, CancelScheduledMessageResult{}, err
+	}
+
+	err := s.backend.CancelScheduledMessage(ctx, args.AgentID, args.ID)
+	if err != nil {
+		return nil, CancelScheduledMessageResult{},
+			fmt.Errorf("failed to cancel scheduled message: %w",
+				err)
+	}
+
+	return nil, CancelScheduledMessageResult{Success: true}, nil
+}
+
+// AskQuestionArgs are the arguments for the ask_question tool.
+type AskQuestionArgs struct {
+	AgentID       int64    `json:"agent_id" jsonschema:"ID of the agent asking the question"`
+	Recipients    []string `json:"recipients" jsonschema:"Names of the agents, groups or aliases to ask"`
+	Question      string   `json:"question" jsonschema:"The question, used as the message subject"`
+	Details       string   `json:"details,omitempty" jsonschema:"Optional markdown context shown above the options"`
+	Options       []string `json:"options,omitempty" jsonschema:"Suggested answers; recipients may still answer with free text"`
+	DefaultAnswer string   `json:"default_answer,omitempty" jsonschema:"Answer used if nobody answers in time; must be one of the options, if any"`
+	Timeout       string   `json:"timeout,omitempty" jsonschema:"How long to wait for an answer before escalating or defaulting, e.g. 30m"`
+	Fallback      string   `json:"fallback,omitempty" jsonschema:"Agent the question is escalated to if nobody answers within the timeout"`
+	Priority      string   `json:"priority,omitempty" jsonschema:"Priority: urgent, normal or low,default=normal"`
+}
+
+// QuestionResult is a structured question and, once resolved, its answer.
+type QuestionResult struct {
+	QuestionID    string   `json:"question_id"`
+	ThreadID      string   `json:"thread_id"`
+	Question      string   `json:"question"`
+	Options       []string `json:"options,omitempty"`
+	DefaultAnswer string   `json:"default_answer,omitempty"`
+	State         string   `json:"state"`
+	Answer        string   `json:"answer,omitempty"`
+	OptionIndex   int      `json:"option_index,omitempty"`
+	AnsweredBy    int64    `json:"answered_by,omitempty"`
+	Escalated     bool     `json:"escalated,omitempty"`
+	TimeoutAt     string   `json:"timeout_at,omitempty"`
+}
+
+// handleAskQuestion sends a structured question.
+func (s *Server) handleAskQuestion(ctx context.Context,
+	req *mcp.CallToolRequest, args AskQuestionArgs,
+) (*mcp.CallToolResult, QuestionResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, QuestionResult{}, err
+	}
+
+	var timeout time.Duration
+	if args.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(args.Timeout)
+		if err != nil {
+			return nil, QuestionResult{},
+				fmt.Errorf("invalid timeout: %w", err)
+		}
+	}
+
+	question, err := s.backend.AskQuestion(ctx, mail.AskQuestionRequest{
+		SenderID:       args.AgentID,
+		RecipientNames: args.Recipients,
+		Question:       args.Question,
+		Details:        args.Details,
+		Options:        args.Options,
+		DefaultAnswer:  args.DefaultAnswer,
+		Timeout:        timeout,
+		Fallback:       args.Fallback,
+		Priority:       mail.Priority(args.Priority),
+	})
+	if err != nil {
+		return nil, QuestionResult{},
+			fmt.Errorf("failed to ask question: %w", err)
+	}
+
+	return nil, questionResult(question), nil
+}
+
+// GetQuestionArgs are the arguments for the get_question tool.
+type GetQuestionArgs struct {
+	QuestionID string `json:"question_id" jsonschema:"ID of the question"`
+}
+
+// handleGetQuestion returns a question and, once resolved, its answer.
+func (s *Server) handleGetQuestion(ctx context.Context,
+	req *mcp.CallToolRequest, args GetQuestionArgs,
+) (*mcp.CallToolResult, QuestionResult, error) {
+	question, err := s.backend.GetQuestion(ctx, args.QuestionID)
+	if err != nil {
+		return nil, QuestionResult{},
+			fmt.Errorf("failed to get question: %w", err)
+	}
+
+	return nil, questionResult(question), nil
+}
+
+// AnswerQuestionArgs are the arguments for the answer_question tool.
+type AnswerQuestionArgs struct {
+	AgentID    int64  `json:"agent_id" jsonschema:"ID of the agent answering"`
+	QuestionID string `json:"question_id" jsonschema:"ID of the question to answer"`
+	Answer     string `json:"answer" jsonschema:"An option's number or text, or a free-form answer"`
+}
+
+// handleAnswerQuestion answers a question by replying in its thread.
+func (s *Server) handleAnswerQuestion(ctx context.Context,
+	req *mcp.CallToolRequest, args AnswerQuestionArgs,
+) (*mcp.CallToolResult, QuestionResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, QuestionResult{}, err
+	}
+
+	question, err := s.backend.AnswerQuestion(
+		ctx, args.QuestionID, args.AgentID, args.Answer,
+	)
+	if err != nil {
+		return nil, QuestionResult{},
+			fmt.Errorf("failed to answer question: %w", err)
+	}
+
+	return nil, questionResult(question), nil
+}
+
+// questionResult converts a question to its tool result.
+func questionResult(question store.Question) QuestionResult {
+	result := QuestionResult{
+		QuestionID:    question.QuestionID,
+		ThreadID:      question.ThreadID,
+		Question:      question.Question,
+		Options:       question.Options,
+		DefaultAnswer: question.DefaultAnswer,
+		State:         question.State,
+		Answer:        question.Answer,
+		OptionIndex:   mail.QuestionOptionIndex(question),
+		AnsweredBy:    question.AnsweredBy,
+		Escalated:     question.EscalatedAt != nil,
+	}
+	if question.TimeoutAt != nil {
+		result.TimeoutAt = question.TimeoutAt.Format(time.RFC3339)
+	}
+
+	return result
+}
+
+// EditMessageArgs are the arguments for the edit_message tool.
+type EditMessageArgs struct {
+	AgentID   int64  `json:"agent_id" jsonschema:"ID of the agent that sent the message"`
+	MessageID int64  `json:"message_id" jsonschema:"ID of the message to edit"`
+	Subject   string `json:"subject,omitempty" jsonschema:"New subject; the current one is kept if empty"`
+	Body      string `json:"body,omitempty" jsonschema:"New body in markdown; the current one is kept if empty"`
+}
+
+// EditMessageResult is the result of the edit_message tool.
+type EditMessageResult struct {
+	MessageID int64 `json:"message_id"`
+	Revision  int   `json:"revision"`
+}
+
+// handleEditMessage edits a message the agent sent.
+func (s *Server) handleEditMessage(ctx context.Context,
+	req *mcp.CallToolRequest, args EditMessageArgs,
+) (*mcp.CallToolResult, EditMessageResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, EditMessageResult{}, err
+	}
+
+	revision, err := s.backend.EditMessage(ctx, mail.EditMessageRequest{
+		SenderID:  args.AgentID,
+		MessageID: args.MessageID,
+		Subject:   args.Subject,
+		Body:      args.Body,
+	})
+	if err != nil {
+		return nil, EditMessageResult{},
+			fmt.Errorf("failed to edit message: %w", err)
+	}
+
+	return nil, EditMessageResult{
+		MessageID: args.MessageID,
+		Revision:  revision,
+	}, nil
+}
+
+// RecallMessageArgs are the arguments for the recall_message tool.
+type RecallMessageArgs struct {
+	AgentID   int64 `json:"agent_id" jsonschema:"ID of the agent that sent the message"`
+	MessageID int64 `json:"message_id" jsonschema:"ID of the message to recall"`
+}
+
+// RecallMessageResult is the result of the recall_message tool.
+type RecallMessageResult struct {
+	Success bool `json:"success"`
+}
+
+// handleRecallMessage recalls a message the agent sent before anyone reads
+// it.
+func (s *Server) handleRecallMessage(ctx context.Context,
+	req *mcp.CallToolRequest, args RecallMessageArgs,
+) (*mcp.CallToolResult, RecallMessageResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, RecallMessageResult{}, err
+	}
+
+	err := s.backend.RecallMessage(ctx, args.AgentID, args.MessageID)
+	if err != nil {
+		return nil, RecallMessageResult{},
+			fmt.Errorf("failed to recall message: %w", err)
+	}
+
+	return nil, RecallMessageResult{Success: true}, nil
+}
+
+// InboxRuleArgs are the conditions of an inbox rule, shared by the
+// create_inbox_rule and test_inbox_rule tools.
+type InboxRuleArgs struct {
+	SenderName     string `json:"sender_name,omitempty" jsonschema:"Match messages from this agent"`
+	SenderPrefix   string `json:"sender_prefix,omitempty" jsonschema:"Match senders whose name starts with this prefix, e.g. reviewer-"`
+	SubjectPattern string `json:"subject_pattern,omitempty" jsonschema:"Match subjects against this regular expression"`
+	TopicName      string `json:"topic_name,omitempty" jsonschema:"Match messages sent to this topic"`
+	Priority       string `json:"priority,omitempty" jsonschema:"Match this priority: urgent, normal, or low"`
+	MetadataKey    string `json:"metadata_key,omitempty" jsonschema:"Match messages with this metadata key"`
+	MetadataValue  string `json:"metadata_value,omitempty" jsonschema:"Match this value of metadata_key; any value if empty"`
+}
+
+// conditions converts the arguments to inbox rule conditions.
+func (a InboxRuleArgs) conditions() store.InboxRuleConditions {
+	return store.InboxRuleConditions{
+		SenderName:     a.SenderName,
+		SenderPrefix:   a.SenderPrefix,
+		SubjectPattern: a.SubjectPattern,
+		TopicName:      a.TopicName,
+		Priority:       a.Priority,
+		MetadataKey:    a.MetadataKey,
+		MetadataValue:  a.MetadataValue,
+	}
+}
+
+// CreateInboxRuleArgs are the arguments for the create_inbox_rule tool.
+type CreateInboxRuleArgs struct {
+	AgentID int64  `json:"agent_id" jsonschema:"ID of the agent whose inbox the rule files"`
+	Name    string `json:"name" jsonschema:"Rule name: lowercase letters, digits and dashes"`
+
+	InboxRuleArgs
+
+	Archive        bool   `json:"archive,omitempty" jsonschema:"Archive matching messages"`
+	Star           bool   `json:"star,omitempty" jsonschema:"Star matching messages"`
+	Snooze         string `json:"snooze,omitempty" jsonschema:"Snooze matching messages for a duration such as 2h, or until a time of day such as 09:00"`
+	Category       string `json:"category,omitempty" jsonschema:"File matching messages under primary or notifications"`
+	ForwardTo      string `json:"forward_to,omitempty" jsonschema:"Also deliver matching messages to this agent"`
+	Label          string `json:"label,omitempty" jsonschema:"Label matching messages, creating the label if needed"`
+	StopProcessing bool   `json:"stop_processing,omitempty" jsonschema:"Skip the agent's later rules when this rule matches"`
+}
+
+// InboxRuleResult is an inbox rule.
+type InboxRuleResult struct {
+	Name           string `json:"name"`
+	Enabled        bool   `json:"enabled"`
+	StopProcessing bool   `json:"stop_processing,omitempty"`
+
+	SenderName     string `json:"sender_name,omitempty"`
+	SenderPrefix   string `json:"sender_prefix,omitempty"`
+	SubjectPattern string `json:"subject_pattern,omitempty"`
+	TopicName      string `json:"topic_name,omitempty"`
+	Priority       string `json:"priority,omitempty"`
+	MetadataKey    string `json:"metadata_key,omitempty"`
+	MetadataValue  string `json:"metadata_value,omitempty"`
+
+	Archive   bool   `json:"archive,omitempty"`
+	Star      bool   `json:"star,omitempty"`
+	Snooze    string `json:"snooze,omitempty"`
+	Category  string `json:"category,omitempty"`
+	ForwardTo string `json:"forward_to,omitempty"`
+	Label     string `json:"label,omitempty"`
+
+	MatchCount    int64  `json:"match_count"`
+	LastMatchedAt string `json:"last_matched_at,omitempty"`
+}
+
+// handleCreateInboxRule adds an inbox rule after the agent's existing ones.
+func (s *Server) handleCreateInboxRule(ctx context.Context,
+	req *mcp.CallToolRequest, args CreateInboxRuleArgs,
+) (*mcp.CallToolResult, InboxRuleResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, InboxRuleResult{}, err
+	}
+
+	rule, err := s.backend.CreateInboxRule(ctx, mail.CreateInboxRuleRequest{
+		AgentID:        args.AgentID,
+		Name:           args.Name,
+		StopProcessing: args.StopProcessing,
+		Conditions:     args.conditions(),
+		Actions: store.InboxRuleActions{
+			Archive:   args.Archive,
+			Star:      args.Star,
+			Snooze:    args.Snooze,
+			Category:  store.InboxCategory(args.Category),
+			ForwardTo: args.ForwardTo,
+			Label:     args.Label,
+		},
+	})
+	if err != nil {
+		return nil, InboxRuleResult{},
+			fmt.Errorf("failed to create inbox rule: %w", err)
+	}
+
+	return nil, inboxRuleResult(rule), nil
+}
+
+// ListInboxRulesArgs are the arguments for the list_inbox_rules tool.
+type ListInboxRulesArgs struct {
+	AgentID int64 `json:"agent_id" jsonschema:"ID of the agent whose rules to list"`
+}
+
+// ListInboxRulesResult is the result of the list_inbox_rules tool.
+type ListInboxRulesResult struct {
+	Rules []InboxRuleResult `json:"rules"`
+}
+
+// handleListInboxRules lists an agent's inbox rules in the order they run.
+func (s *Server) handleListInboxRules(ctx context.Context,
+	req *mcp.CallToolRequest, args ListInboxRulesArgs,
+) (*mcp.CallToolResult, ListInboxRulesResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, ListInboxRulesResult{}, err
+	}
+
+	rules, err := s.backend.ListInboxRules(ctx, args.AgentID)
+	if err != nil {
+		return nil, ListInboxRulesResult{},
+			fmt.Errorf("failed to list inbox rules: %w", err)
+	}
+
+	result := make([]InboxRuleResult, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, inboxRuleResult(rule))
+	}
+
+	return nil, ListInboxRulesResult{Rules: result}, nil
+}
+
+// InboxRuleNameArgs are the arguments for the tools that act on a named
+// inbox rule.
+type InboxRuleNameArgs struct {
+	AgentID int64  `json:"agent_id" jsonschema:"ID of the agent that owns the rule"`
+	Name    string `json:"name" jsonschema:"Name of the rule"`
+}
+
+// DeleteInboxRuleResult is the result of the delete_inbox_rule tool.
+type DeleteInboxRuleResult struct {
+	Success bool `json:"success"`
+}
+
+// handleDeleteInboxRule deletes one of an agent's inbox rules.
+func (s *Server) handleDeleteInboxRule(ctx context.Context,
+	req *mcp.CallToolRequest, args InboxRuleNameArgs,
+) (*mcp.CallToolResult, DeleteInboxRuleResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, DeleteInboxRuleResult{}, err
+	}
+
+	err := s.backend.DeleteInboxRule(ctx, args.AgentID, args.Name)
+	if err != nil {
+		return nil, DeleteInboxRuleResult{},
+			fmt.Errorf("failed to delete inbox rule: %w", err)
+	}
+
+	return nil, DeleteInboxRuleResult{Success: true}, nil
+}
+
+// SetInboxRuleEnabledArgs are the arguments for the set_inbox_rule_enabled
+// tool.
+type SetInboxRuleEnabledArgs struct {
+	AgentID int64  `json:"agent_id" jsonschema:"ID of the agent that owns the rule"`
+	Name    string `json:"name" jsonschema:"Name of the rule"`
+	Enabled bool   `json:"enabled" jsonschema:"Whether the rule should run"`
+}
+
+// handleSetInboxRuleEnabled enables or disables one of an agent's inbox
+// rules.
+func (s *Server) handleSetInboxRuleEnabled(ctx context.Context,
+	req *mcp.CallToolRequest, args SetInboxRuleEnabledArgs,
+) (*mcp.CallToolResult, InboxRuleResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, InboxRuleResult{}, err
+	}
+
+	rule, err := s.backend.SetInboxRuleEnabled(
+		ctx, args.AgentID, args.Name, args.Enabled,
+	)
+	if err != nil {
+		return nil, InboxRuleResult{},
+			fmt.Errorf("failed to update inbox rule: %w", err)
+	}
+
+	return nil, inboxRuleResult(rule), nil
+}
+
+// TestInboxRuleArgs are the arguments for the test_inbox_rule tool.
+type TestInboxRuleArgs struct {
+	AgentID int64  `json:"agent_id" jsonschema:"ID of the agent whose messages to try"`
+	Name    string `json:"name,omitempty" jsonschema:"Name of a saved rule to try; if empty the given conditions are tried"`
+	Limit   int    `json:"limit,omitempty" jsonschema:"Number of recently received messages to try,default=100"`
+
+	InboxRuleArgs
+}
+
+// TestInboxRuleResult is the result of the test_inbox_rule tool.
+type TestInboxRuleResult struct {
+	Scanned int                  `json:"scanned"`
+	Matches []InboxMessageResult `json:"matches"`
+}
+
+// handleTestInboxRule reports which of the agent's recently received
+// messages a rule would match, without changing them.
+func (s *Server) handleTestInboxRule(ctx context.Context,
+	req *mcp.CallToolRequest, args TestInboxRuleArgs,
+) (*mcp.CallToolResult, TestInboxRuleResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, TestInboxRuleResult{}, err
+	}
+
+	resp, err := s.backend.TestInboxRule(ctx, mail.TestInboxRuleRequest{
+		AgentID:    args.AgentID,
+		Name:       args.Name,
+		Conditions: args.conditions(),
+		Limit:      args.Limit,
+	})
+	if err != nil {
+		return nil, TestInboxRuleResult{},
+			fmt.Errorf("failed to test inbox rule: %w", err)
+	}
+
+	matches := make([]InboxMessageResult, 0, len(resp.Matches))
+	for _, m := range resp.Matches {
+		matches = append(matches, InboxMessageResult{
+			ID:         m.ID,
+			ThreadID:   m.ThreadID,
+			SenderID:   m.SenderID,
+			SenderName: m.SenderName,
+			Subject:    m.Subject,
+			Priority:   string(m.Priority),
+			State:      m.State,
+			CreatedAt:  m.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return nil, TestInboxRuleResult{
+		Scanned: resp.Scanned,
+		Matches: matches,
+	}, nil
+}
+
+// ListLabelsArgs are the arguments for the list_labels tool.
+type ListLabelsArgs struct {
+	AgentID int64 `json:"agent_id" jsonschema:"ID of the agent whose labels to list"`
+}
+
+// LabelResult is a label with its message counts.
+type LabelResult struct {
+	Name         string `json:"name"`
+	MessageCount int64  `json:"message_count"`
+	UnreadCount  int64  `json:"unread_count"`
+}
+
+// ListLabelsResult is the result of the list_labels tool.
+type ListLabelsResult struct {
+	Labels []LabelResult `json:"labels"`
+}
+
+// handleListLabels lists an agent's labels with their message counts.
+func (s *Server) handleListLabels(ctx context.Context,
+	req *mcp.CallToolRequest, args ListLabelsArgs,
+) (*mcp.CallToolResult, ListLabelsResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, ListLabelsResult{}, err
+	}
+
+	labels, err := s.backend.ListLabels(ctx, args.AgentID)
+	if err != nil {
+		return nil, ListLabelsResult{},
+			fmt.Errorf("failed to list labels: %w", err)
+	}
+
+	results := make([]LabelResult, 0, len(labels))
+	for _, label := range labels {
+		results = append(results, LabelResult{
+			Name:         label.Name,
+			MessageCount: label.MessageCount,
+			UnreadCount:  label.UnreadCount,
+		})
+	}
+
+	return nil, ListLabelsResult{Labels: results}, nil
+}
+
+// LabelMessageArgs are the arguments for the label_message tool.
+type LabelMessageArgs struct {
+	AgentID   int64    `json:"agent_id" jsonschema:"ID of the agent that received the message"`
+	MessageID int64    `json:"message_id" jsonschema:"ID of the message to label"`
+	Add       []string `json:"add,omitempty" jsonschema:"Labels to apply: lowercase letters, digits, '.', '_', '/' and '-'"`
+	Remove    []string `json:"remove,omitempty" jsonschema:"Labels to take off"`
+}
+
+// LabelMessageResult is the result of the label_message tool.
+type LabelMessageResult struct {
+	MessageID int64    `json:"message_id"`
+	Labels    []string `json:"labels"`
+}
+
+// handleLabelMessage applies and removes labels on an agent's copy of a
+// message.
+func (s *Server) handleLabelMessage(ctx context.Context,
+	req *mcp.CallToolRequest, args LabelMessageArgs,
+) (*mcp.CallToolResult, LabelMessageResult, error) {
+	if err := requireAgentID(args.AgentID); err != nil {
+		return nil, LabelMessageResult{}, err
+	}
+
+	labels, err := s.backend.LabelMessage(ctx, mail.LabelMessageRequest{
+		AgentID:   args.AgentID,
+		MessageID: args.MessageID,
+		Add:       args.Add,
+		Remove:    args.Remove,
+	})
+	if err != nil {
+		return nil, LabelMessageResult{},
+			fmt.Errorf("failed to label message: %w", err)
+	}
+	if labels == nil {
+		labels = []string{}
+	}
+
+	return nil, LabelMessageResult{
+		MessageID: args.MessageID,
+		Labels:    labels,
+	}, nil
+}
+
+// inboxRuleResult converts an inbox rule to its tool result.
+func inboxRuleResult(rule store.InboxRule) InboxRuleResult {
+	result := InboxRuleResult{
+		Name:           rule.Name,
+		Enabled:        rule.Enabled,
+		StopProcessing: rule.StopProcessing,
+		SenderName:     rule.SenderName,
+		SenderPrefix:   rule.SenderPrefix,
+		SubjectPattern: rule.SubjectPattern,
+		TopicName:      rule.TopicName,
+		Priority:       rule.Priority,
+		MetadataKey:    rule.MetadataKey,
+		MetadataValue:  rule.MetadataValue,
+		Archive:        rule.Archive,
+		Star:           rule.Star,
+		Snooze:         rule.Snooze,
+		Category:       string(rule.Category),
+		ForwardTo:      rule.ForwardTo,
+		Label:          rule.Label,
+		MatchCount:     rule.MatchCount,
+	}
+	if rule.LastMatchedAt != nil {
+		result.LastMatchedAt = rule.LastMatchedAt.Format(time.RFC3339)
+	}
+
+	return result
+}
@@ -19,6 +19,10 @@ type SendPayload struct {
 	ThreadID       string     `json:"thread_id,omitempty"`
 	DeadlineAt     *time.Time `json:"deadline_at,omitempty"`
 	Attachments    string     `json:"attachments,omitempty"`
+	Partial        bool       `json:"partial,omitempty"`
+	SendAt         *time.Time `json:"send_at,omitempty"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // PublishPayload stores the data for a queued publish operation.
@@ -44,6 +48,27 @@ type StatusUpdatePayload struct {
 	Body           string   `json:"body"`
 }
 
+// ReplyPayload stores the data for a queued reply. Only the replied-to
+// message is recorded: its thread, subject and recipients are looked up at
+// drain time, since the message can't be read while offline.
+type ReplyPayload struct {
+	SenderName string `json:"sender_name"`
+	MessageID  int64  `json:"message_id"`
+	Body       string `json:"body"`
+	Priority   string `json:"priority"`
+	SenderOnly bool   `json:"sender_only,omitempty"`
+	Quote      bool   `json:"quote,omitempty"`
+}
+
+// ThreadStatePayload stores the data for a queued thread archive, unread or
+// delete. State is the mail state every message of the thread the agent
+// received moves to: archived, unread or trash.
+type ThreadStatePayload struct {
+	AgentName string `json:"agent_name"`
+	ThreadID  string `json:"thread_id"`
+	State     string `json:"state"`
+}
+
 // MarshalPayload serializes a payload struct to JSON for queue storage.
 func MarshalPayload(payload any) (string, error) {
 	data, err := json.Marshal(payload)
@@ -88,6 +113,21 @@ func UnmarshalPayload(
 		}
 		return &p, nil
 
+	case OpReply:
+		var p ReplyPayload
+		if err := json.Unmarshal([]byte(jsonStr), &p); err != nil {
+			return nil, fmt.Errorf("unmarshal reply: %w", err)
+		}
+		return &p, nil
+
+	case OpThreadState:
+		var p ThreadStatePayload
+		if err := json.Unmarshal([]byte(jsonStr), &p); err != nil {
+			return nil, fmt.Errorf("unmarshal thread state: %w",
+				err)
+		}
+		return &p, nil
+
 	default:
 		return nil, fmt.Errorf("unknown operation type: %s", opType)
 	}
@@ -367,6 +367,68 @@ func TestQueueStore_PayloadPreservation(t *testing.T) {
 	require.Equal(t, payload, listed[0].PayloadJSON)
 }
 
+// TestQueueStore_ReplyPayload verifies that a queued reply round-trips
+// through the queue with its payload intact.
+func TestQueueStore_ReplyPayload(t *testing.T) {
+	store := newTestQueueStore(t)
+	ctx := context.Background()
+
+	payload := ReplyPayload{
+		SenderName: "agent-1",
+		MessageID:  42,
+		Body:       "Sounds good.",
+		Priority:   "normal",
+		Quote:      true,
+	}
+	payloadJSON, err := MarshalPayload(payload)
+	require.NoError(t, err)
+
+	op := makeOp(OpReply)
+	op.PayloadJSON = payloadJSON
+	require.NoError(t, store.Enqueue(ctx, op))
+
+	drained, err := store.Drain(ctx)
+	require.NoError(t, err)
+	require.Len(t, drained, 1)
+	require.Equal(t, OpReply, drained[0].OperationType)
+
+	decoded, err := UnmarshalPayload(
+		drained[0].OperationType, drained[0].PayloadJSON,
+	)
+	require.NoError(t, err)
+	require.Equal(t, &payload, decoded)
+}
+
+// TestQueueStore_ThreadStatePayload verifies that a queued thread archive
+// round-trips through the queue with its payload intact.
+func TestQueueStore_ThreadStatePayload(t *testing.T) {
+	store := newTestQueueStore(t)
+	ctx := context.Background()
+
+	payload := ThreadStatePayload{
+		AgentName: "agent-1",
+		ThreadID:  "thread-1",
+		State:     "archived",
+	}
+	payloadJSON, err := MarshalPayload(payload)
+	require.NoError(t, err)
+
+	op := makeOp(OpThreadState)
+	op.PayloadJSON = payloadJSON
+	require.NoError(t, store.Enqueue(ctx, op))
+
+	drained, err := store.Drain(ctx)
+	require.NoError(t, err)
+	require.Len(t, drained, 1)
+	require.Equal(t, OpThreadState, drained[0].OperationType)
+
+	decoded, err := UnmarshalPayload(
+		drained[0].OperationType, drained[0].PayloadJSON,
+	)
+	require.NoError(t, err)
+	require.Equal(t, &payload, decoded)
+}
+
 // TestQueueStore_OpenQueueStore verifies the full OpenQueueStore path.
 func TestQueueStore_OpenQueueStore(t *testing.T) {
 	projectRoot := t.TempDir()
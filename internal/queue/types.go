@@ -23,6 +23,13 @@ const (
 
 	// OpStatusUpdate represents a status update operation.
 	OpStatusUpdate OperationType = "status_update"
+
+	// OpReply represents a reply to a message.
+	OpReply OperationType = "reply"
+
+	// OpThreadState represents archiving, marking unread or deleting the
+	// messages of a thread.
+	OpThreadState OperationType = "thread_state"
 )
 
 // PendingOperation is the domain type for a queued operation awaiting
@@ -0,0 +1,497 @@
+// Package ratelimit holds agents to the rate limits and storage quotas an
+// administrator sets for them. Rate limits are token buckets per agent and
+// class of RPC, kept in the database so that the daemon and clients using
+// the database directly draw from the same buckets. The storage quota caps
+// how many bytes of message body an agent may send each UTC day.
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/store"
+)
+
+// Class is a class of RPCs that share a rate limit, or the storage quota.
+type Class string
+
+const (
+	// ClassSend covers sending mail, replying and asking questions.
+	ClassSend Class = "send"
+
+	// ClassPublish covers publishing to topics.
+	ClassPublish Class = "publish"
+
+	// ClassSearch covers full-text, semantic and similar-message search.
+	ClassSearch Class = "search"
+
+	// ClassReview covers creating code and plan reviews.
+	ClassReview Class = "review"
+
+	// ClassBodyBytes is the storage quota: the bytes of message body an
+	// agent may send each UTC day.
+	ClassBodyBytes Class = "body_bytes"
+)
+
+// RateClasses are the classes limited by token buckets.
+var RateClasses = []Class{
+	ClassSend, ClassPublish, ClassSearch, ClassReview,
+}
+
+// quotaInterval is the period a storage quota is counted over.
+const quotaInterval = 24 * time.Hour
+
+var (
+	// ErrRateLimited is returned when an agent has no tokens left for a
+	// class of RPCs.
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// ErrQuotaExceeded is returned when a message would take an agent
+	// past its daily storage quota.
+	ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+	// ErrInvalidLimit is returned for a malformed class, rate or size.
+	ErrInvalidLimit = errors.New("invalid limit")
+)
+
+// LimitError is returned when a call is refused by a rate limit or quota.
+// It unwraps to ErrRateLimited or ErrQuotaExceeded.
+type LimitError struct {
+	// Class is the class whose limit refused the call.
+	Class Class
+
+	// RetryAfter is how long until the call would be allowed: until the
+	// next token for a rate limit, or until the next UTC day for a
+	// quota.
+	RetryAfter time.Duration
+
+	err error
+}
+
+// Error returns the error with the class and when to retry.
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%v for %s: retry after %v", e.err, e.Class,
+		e.RetryAfter)
+}
+
+// Unwrap returns ErrRateLimited or ErrQuotaExceeded.
+func (e *LimitError) Unwrap() error {
+	return e.err
+}
+
+// RetryAfter returns how long to wait before retrying a call refused with
+// err, if it was refused by a limit.
+func RetryAfter(err error) (time.Duration, bool) {
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		return 0, false
+	}
+
+	return limitErr.RetryAfter, true
+}
+
+// Usage is an agent's standing against the limit on one class.
+type Usage struct {
+	Class Class
+
+	// Limit is the limit that applies, or nil if the class is unlimited.
+	Limit *store.AgentLimit
+
+	// Tokens is how many calls the agent may make right now. It is only
+	// meaningful for a limited rate class.
+	Tokens float64
+
+	// Throttled is how many of the agent's calls were refused, and
+	// LastThrottledAt when the last one was.
+	Throttled       int64
+	LastThrottledAt *time.Time
+
+	// UsedBytes is how many bytes of message body the agent sent today,
+	// for ClassBodyBytes.
+	UsedBytes int64
+}
+
+// Limiter enforces rate limits against the token buckets in a store.
+type Limiter struct {
+	store store.Storage
+
+	// now returns the current time; tests replace it.
+	now func() time.Time
+}
+
+// NewLimiter creates a limiter backed by the given store.
+func NewLimiter(st store.Storage) *Limiter {
+	return &Limiter{
+		store: st,
+		now:   time.Now,
+	}
+}
+
+// Allow takes a token from an agent's bucket for a class, returning a
+// *LimitError if there is none. Classes without a limit always allow.
+func (l *Limiter) Allow(ctx context.Context, agentID int64,
+	class Class,
+) error {
+	if agentID == 0 {
+		return nil
+	}
+
+	// Most agents are unlimited, so check for a limit before paying for
+	// a write transaction.
+	_, err := l.store.GetAgentLimit(ctx, agentID, string(class))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get limit: %w", err)
+	}
+
+	// The refused call is still recorded, so the error is only returned
+	// once the transaction has committed.
+	var limitErr *LimitError
+	err = l.store.WithTx(ctx, func(ctx context.Context,
+		txStore store.Storage,
+	) error {
+		// Reset the outcome in case the transaction is retried.
+		limitErr = nil
+
+		limit, err := txStore.GetAgentLimit(
+			ctx, agentID, string(class),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("get limit: %w", err)
+		}
+
+		bucket, err := l.refill(ctx, txStore, agentID, limit)
+		if err != nil {
+			return err
+		}
+
+		if bucket.Tokens >= 1 {
+			bucket.Tokens--
+		} else {
+			now := bucket.UpdatedAt
+			bucket.ThrottledCount++
+			bucket.LastThrottledAt = &now
+
+			limitErr = &LimitError{
+				Class:      class,
+				RetryAfter: retryAfter(limit, bucket.Tokens),
+				err:        ErrRateLimited,
+			}
+		}
+
+		err = txStore.UpsertRateLimitBucket(ctx, bucket)
+		if err != nil {
+			return fmt.Errorf("update bucket: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if limitErr != nil {
+		return limitErr
+	}
+
+	return nil
+}
+
+// refill returns an agent's bucket for a limit, which may be the default,
+// topped up with the tokens earned since it was last updated. A new bucket
+// starts full.
+func (l *Limiter) refill(ctx context.Context, st store.LimitStore,
+	agentID int64, limit store.AgentLimit,
+) (store.RateLimitBucket, error) {
+	now := l.now()
+
+	bucket, err := st.GetRateLimitBucket(ctx, agentID, limit.Class)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return store.RateLimitBucket{
+			AgentID:   agentID,
+			Class:     limit.Class,
+			Tokens:    float64(limit.Burst),
+			UpdatedAt: now,
+		}, nil
+
+	case err != nil:
+		return store.RateLimitBucket{}, fmt.Errorf("get bucket: %w",
+			err)
+	}
+
+	if elapsed := now.Sub(bucket.UpdatedAt); elapsed > 0 {
+		bucket.Tokens += elapsed.Seconds() * rate(limit)
+	}
+	bucket.Tokens = math.Min(bucket.Tokens, float64(limit.Burst))
+	bucket.UpdatedAt = now
+
+	return bucket, nil
+}
+
+// rate returns how many tokens a limit earns a second.
+func rate(limit store.AgentLimit) float64 {
+	return float64(limit.Amount) / limit.Interval.Seconds()
+}
+
+// retryAfter returns how long a bucket holding tokens takes to earn a whole
+// token, rounded up to the second.
+func retryAfter(limit store.AgentLimit, tokens float64) time.Duration {
+	wait := (1 - tokens) / rate(limit)
+	return time.Duration(math.Ceil(wait)) * time.Second
+}
+
+// CheckQuota returns a *LimitError if sending a message body of size bytes
+// would take an agent past its daily storage quota. It reads the quota and
+// the day's usage from st, so a caller sending in a transaction should pass
+// its transaction's store.
+func CheckQuota(ctx context.Context, st store.LimitStore, agentID int64,
+	size int64, now time.Time,
+) error {
+	if agentID == 0 {
+		return nil
+	}
+
+	limit, err := st.GetAgentLimit(ctx, agentID, string(ClassBodyBytes))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get quota: %w", err)
+	}
+
+	dayStart := startOfDay(now)
+	used, err := st.SumSenderBodyBytes(ctx, agentID, dayStart)
+	if err != nil {
+		return fmt.Errorf("sum body bytes: %w", err)
+	}
+
+	if used+size <= limit.Amount {
+		return nil
+	}
+
+	return &LimitError{
+		Class:      ClassBodyBytes,
+		RetryAfter: dayStart.Add(quotaInterval).Sub(now),
+		err:        ErrQuotaExceeded,
+	}
+}
+
+// startOfDay returns the start of the UTC day t falls on.
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(quotaInterval)
+}
+
+// Usage returns an agent's standing against the limit on every class,
+// without taking any tokens.
+func (l *Limiter) Usage(ctx context.Context, agentID int64) ([]Usage,
+	error,
+) {
+	buckets, err := l.store.ListRateLimitBuckets(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("list buckets: %w", err)
+	}
+	throttled := make(map[string]store.RateLimitBucket, len(buckets))
+	for _, bucket := range buckets {
+		throttled[bucket.Class] = bucket
+	}
+
+	classes := append(append([]Class{}, RateClasses...), ClassBodyBytes)
+	usage := make([]Usage, 0, len(classes))
+	for _, class := range classes {
+		u := Usage{Class: class}
+		if bucket, ok := throttled[string(class)]; ok {
+			u.Throttled = bucket.ThrottledCount
+			u.LastThrottledAt = bucket.LastThrottledAt
+		}
+
+		limit, err := l.store.GetAgentLimit(ctx, agentID, string(class))
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+
+		case err != nil:
+			return nil, fmt.Errorf("get limit: %w", err)
+
+		default:
+			u.Limit = &limit
+		}
+
+		if class == ClassBodyBytes {
+			u.UsedBytes, err = l.store.SumSenderBodyBytes(
+				ctx, agentID, startOfDay(l.now()),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("sum body bytes: %w",
+					err)
+			}
+		} else if u.Limit != nil {
+			bucket, err := l.refill(ctx, l.store, agentID, limit)
+			if err != nil {
+				return nil, err
+			}
+			u.Tokens = bucket.Tokens
+		}
+
+		usage = append(usage, u)
+	}
+
+	return usage, nil
+}
+
+// Set sets an agent's limit for a class, or the default for every agent if
+// agentID is 0. For a rate class, amount calls are allowed every interval
+// with up to burst at once; a burst of 0 allows amount at once. For
+// ClassBodyBytes, amount is the bytes allowed a day and interval and burst
+// are ignored.
+func (l *Limiter) Set(ctx context.Context, agentID int64, class Class,
+	amount int64, interval time.Duration, burst int64,
+) (store.AgentLimit, error) {
+	if err := ValidateClass(class); err != nil {
+		return store.AgentLimit{}, err
+	}
+
+	switch {
+	case class == ClassBodyBytes:
+		interval, burst = quotaInterval, amount
+
+	case interval < time.Second || interval%time.Second != 0:
+		return store.AgentLimit{}, fmt.Errorf("%w: interval %v isn't "+
+			"a whole number of seconds", ErrInvalidLimit, interval)
+
+	case burst < 0:
+		return store.AgentLimit{}, fmt.Errorf("%w: negative burst",
+			ErrInvalidLimit)
+
+	case burst == 0:
+		burst = amount
+	}
+	if amount <= 0 {
+		return store.AgentLimit{}, fmt.Errorf("%w: amount must be "+
+			"positive", ErrInvalidLimit)
+	}
+
+	return l.store.SetAgentLimit(ctx, store.SetAgentLimitParams{
+		AgentID:  agentID,
+		Class:    string(class),
+		Amount:   amount,
+		Interval: interval,
+		Burst:    burst,
+	})
+}
+
+// Clear removes an agent's limit for a class, or the default if agentID is
+// 0. It returns false if none was set.
+func (l *Limiter) Clear(ctx context.Context, agentID int64,
+	class Class,
+) (bool, error) {
+	if err := ValidateClass(class); err != nil {
+		return false, err
+	}
+
+	return l.store.DeleteAgentLimit(ctx, agentID, string(class))
+}
+
+// List lists the default limits followed by the agents' own, or only those
+// that apply to an agent if agentID is non-zero.
+func (l *Limiter) List(ctx context.Context,
+	agentID int64,
+) ([]store.AgentLimit, error) {
+	return l.store.ListAgentLimits(ctx, agentID)
+}
+
+// ValidateClass returns an error unless class is a known class.
+func ValidateClass(class Class) error {
+	if class == ClassBodyBytes {
+		return nil
+	}
+	for _, c := range RateClasses {
+		if class == c {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: unknown class %q", ErrInvalidLimit, class)
+}
+
+// ParseRate parses a rate of the form "<amount>/<interval>", such as
+// "30/1m", "5/s" or "1000/1d". The interval is a Go duration, a bare unit
+// meaning one of it, or a number of days ending in d.
+func ParseRate(s string) (int64, time.Duration, error) {
+	amountStr, intervalStr, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: rate %q isn't of the form "+
+			"amount/interval", ErrInvalidLimit, s)
+	}
+
+	amount, err := strconv.ParseInt(amountStr, 10, 64)
+	if err != nil || amount <= 0 {
+		return 0, 0, fmt.Errorf("%w: invalid amount in rate %q",
+			ErrInvalidLimit, s)
+	}
+
+	if intervalStr != "" && (intervalStr[0] < '0' || intervalStr[0] > '9') {
+		intervalStr = "1" + intervalStr
+	}
+
+	var interval time.Duration
+	if days, ok := strings.CutSuffix(intervalStr, "d"); ok {
+		n, err := strconv.ParseInt(days, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%w: invalid interval in rate "+
+				"%q", ErrInvalidLimit, s)
+		}
+		interval = time.Duration(n) * quotaInterval
+	} else {
+		interval, err = time.ParseDuration(intervalStr)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%w: invalid interval in rate "+
+				"%q", ErrInvalidLimit, s)
+		}
+	}
+
+	return amount, interval, nil
+}
+
+// byteUnits are the size suffixes ParseBytes accepts, longest first.
+var byteUnits = []struct {
+	suffix string
+	scale  int64
+}{
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30},
+	{"KB", 1000}, {"MB", 1000 * 1000}, {"GB", 1000 * 1000 * 1000},
+	{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30},
+	{"B", 1},
+}
+
+// ParseBytes parses a size such as "500000", "64KiB" or "10MB". KB, MB and
+// GB are powers of 1000, while KiB, MiB and GiB and the bare K, M and G are
+// powers of 1024.
+func ParseBytes(size string) (int64, error) {
+	s := strings.TrimSpace(size)
+
+	scale := int64(1)
+	for _, unit := range byteUnits {
+		if trimmed, ok := strings.CutSuffix(s, unit.suffix); ok {
+			s, scale = strings.TrimSpace(trimmed), unit.scale
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 || n > math.MaxInt64/scale {
+		return 0, fmt.Errorf("%w: invalid size %q", ErrInvalidLimit,
+			size)
+	}
+
+	return n * scale, nil
+}
@@ -0,0 +1,192 @@
+package ratelimit
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db"
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore creates a temporary test database with migrations applied.
+func testStore(t *testing.T) store.Storage {
+	t.Helper()
+
+	dir := t.TempDir()
+	sqlDB, err := db.OpenSQLite(filepath.Join(dir, "test.db"))
+	require.NoError(t, err)
+
+	err = db.RunMigrations(sqlDB, "../db/migrations")
+	require.NoError(t, err)
+
+	storage := store.FromDB(sqlDB)
+	t.Cleanup(func() {
+		storage.Close()
+	})
+
+	return storage
+}
+
+// TestAllow tests that calls draw from a per-agent bucket that refills at
+// the limit's rate, that an agent's own limit overrides the default, and
+// that refused calls are counted.
+func TestAllow(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage := testStore(t)
+	limiter := NewLimiter(storage)
+
+	now := time.Unix(1_700_000_000, 0)
+	limiter.now = func() time.Time { return now }
+
+	alice, err := storage.CreateAgent(ctx, store.CreateAgentParams{
+		Name: "Alice",
+	})
+	require.NoError(t, err)
+	bob, err := storage.CreateAgent(ctx, store.CreateAgentParams{
+		Name: "Bob",
+	})
+	require.NoError(t, err)
+
+	// Without a limit, calls are always allowed.
+	for i := 0; i < 5; i++ {
+		require.NoError(t, limiter.Allow(ctx, alice.ID, ClassSend))
+	}
+
+	// Everyone may send 2 a minute, and Bob 10.
+	_, err = limiter.Set(ctx, 0, ClassSend, 2, time.Minute, 0)
+	require.NoError(t, err)
+	_, err = limiter.Set(ctx, bob.ID, ClassSend, 10, time.Minute, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, limiter.Allow(ctx, alice.ID, ClassSend))
+	require.NoError(t, limiter.Allow(ctx, alice.ID, ClassSend))
+
+	err = limiter.Allow(ctx, alice.ID, ClassSend)
+	require.ErrorIs(t, err, ErrRateLimited)
+	retry, ok := RetryAfter(err)
+	require.True(t, ok)
+	require.Equal(t, 30*time.Second, retry)
+
+	// Other classes and agents have their own buckets.
+	require.NoError(t, limiter.Allow(ctx, alice.ID, ClassSearch))
+	for i := 0; i < 3; i++ {
+		require.NoError(t, limiter.Allow(ctx, bob.ID, ClassSend))
+	}
+
+	// A token is earned back after half a minute.
+	now = now.Add(30 * time.Second)
+	require.NoError(t, limiter.Allow(ctx, alice.ID, ClassSend))
+	require.ErrorIs(t, limiter.Allow(ctx, alice.ID, ClassSend),
+		ErrRateLimited)
+
+	usage, err := limiter.Usage(ctx, alice.ID)
+	require.NoError(t, err)
+	require.Len(t, usage, len(RateClasses)+1)
+	require.Equal(t, ClassSend, usage[0].Class)
+	require.EqualValues(t, 2, usage[0].Limit.Amount)
+	require.EqualValues(t, 2, usage[0].Throttled)
+	require.Equal(t, now, *usage[0].LastThrottledAt)
+	require.Nil(t, usage[2].Limit)
+
+	// Clearing the default lifts Alice's limit but not Bob's.
+	cleared, err := limiter.Clear(ctx, 0, ClassSend)
+	require.NoError(t, err)
+	require.True(t, cleared)
+	require.NoError(t, limiter.Allow(ctx, alice.ID, ClassSend))
+
+	limits, err := limiter.List(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, limits, 1)
+	require.Equal(t, "Bob", limits[0].AgentName)
+}
+
+// TestCheckQuota tests that an agent's daily storage quota counts the
+// bodies it sent since the start of the UTC day.
+func TestCheckQuota(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage := testStore(t)
+	limiter := NewLimiter(storage)
+
+	alice, err := storage.CreateAgent(ctx, store.CreateAgentParams{
+		Name: "Alice",
+	})
+	require.NoError(t, err)
+	topic, err := storage.CreateTopic(ctx, store.CreateTopicParams{
+		Name:      "agent/Alice/inbox",
+		TopicType: "direct",
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, CheckQuota(ctx, storage, alice.ID, 1<<20, now))
+
+	_, err = limiter.Set(ctx, alice.ID, ClassBodyBytes, 100, 0, 0)
+	require.NoError(t, err)
+
+	_, err = storage.CreateMessage(ctx, store.CreateMessageParams{
+		ThreadID: "thread-1",
+		TopicID:  topic.ID,
+		SenderID: alice.ID,
+		Subject:  "hi",
+		Body:     strings.Repeat("x", 60),
+		Priority: "normal",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, CheckQuota(ctx, storage, alice.ID, 40, now))
+
+	err = CheckQuota(ctx, storage, alice.ID, 41, now)
+	require.ErrorIs(t, err, ErrQuotaExceeded)
+	retry, ok := RetryAfter(err)
+	require.True(t, ok)
+	reset := startOfDay(now).Add(24 * time.Hour)
+	require.True(t, reset.Equal(now.Add(retry)))
+
+	usage, err := limiter.Usage(ctx, alice.ID)
+	require.NoError(t, err)
+	quota := usage[len(usage)-1]
+	require.Equal(t, ClassBodyBytes, quota.Class)
+	require.EqualValues(t, 60, quota.UsedBytes)
+	require.EqualValues(t, 100, quota.Limit.Amount)
+}
+
+// TestParse tests parsing rates and sizes as the CLI accepts them.
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	rates := map[string]time.Duration{
+		"30/1m": time.Minute, "5/s": time.Second,
+		"1000/1d": 24 * time.Hour, "10/90s": 90 * time.Second,
+	}
+	for in, interval := range rates {
+		_, got, err := ParseRate(in)
+		require.NoError(t, err, in)
+		require.Equal(t, interval, got, in)
+	}
+	for _, in := range []string{"", "30", "0/1m", "x/1m", "5/soon"} {
+		_, _, err := ParseRate(in)
+		require.ErrorIs(t, err, ErrInvalidLimit, in)
+	}
+
+	sizes := map[string]int64{
+		"500": 500, "64KiB": 64 << 10, "10MB": 10_000_000,
+		"2M": 2 << 20,
+	}
+	for in, want := range sizes {
+		got, err := ParseBytes(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "MB", "-1", "1.5GB"} {
+		_, err := ParseBytes(in)
+		require.ErrorIs(t, err, ErrInvalidLimit, in)
+	}
+}
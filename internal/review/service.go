@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/lightningnetwork/lnd/fn/v2"
 	"github.com/roasbeef/subtrate/internal/baselib/actor"
+	"github.com/roasbeef/subtrate/internal/ratelimit"
 	"github.com/roasbeef/subtrate/internal/store"
 )
 
@@ -35,6 +36,9 @@ type ServiceConfig struct {
 	// ActorSystem is used to register reviewer sub-actors for lifecycle
 	// management and graceful shutdown.
 	ActorSystem *actor.ActorSystem
+
+	// RateLimiter, if set, holds requesters to their review rate limit.
+	RateLimiter *ratelimit.Limiter
 }
 
 // Service handles review orchestration as an actor. It creates DB records,
@@ -52,6 +56,29 @@ type Service struct {
 	// Active review FSMs, keyed by review ID. Protected by mu.
 	mu            sync.RWMutex
 	activeReviews map[string]*ReviewFSM
+
+	// notifier, if set, is told of review state changes. Protected by
+	// mu.
+	notifier StateChangeNotifier
+
+	// rateLimiter, if set, holds requesters to their review rate limit.
+	rateLimiter *ratelimit.Limiter
+}
+
+// StateChangeNotifier is told of review state changes to enable real-time
+// notifications (e.g., WebSocket broadcasts).
+type StateChangeNotifier interface {
+	// OnReviewStateChange is called after a review moves from one state
+	// to another.
+	OnReviewStateChange(reviewID, oldState, newState string)
+}
+
+// SetStateNotifier sets the notifier told of review state changes.
+func (s *Service) SetStateNotifier(n StateChangeNotifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.notifier = n
 }
 
 // NewService creates a new review service with the given configuration.
@@ -71,7 +98,18 @@ func NewService(cfg ServiceConfig) *Service {
 			cfg.ActorSystem, cfg.Store, cfg.SpawnConfig,
 		),
 		activeReviews: make(map[string]*ReviewFSM),
+		rateLimiter:   cfg.RateLimiter,
+	}
+}
+
+// allow takes a token from an agent's review rate limit, if the service has
+// a rate limiter.
+func (s *Service) allow(ctx context.Context, agentID int64) error {
+	if s.rateLimiter == nil {
+		return nil
 	}
+
+	return s.rateLimiter.Allow(ctx, agentID, ratelimit.ClassReview)
 }
 
 // Receive implements actor.ActorBehavior by dispatching to type-specific
@@ -81,6 +119,12 @@ func (s *Service) Receive(ctx context.Context,
 ) fn.Result[ReviewResponse] {
 	switch m := msg.(type) {
 	case CreateReviewMsg:
+		if err := s.allow(ctx, m.RequesterID); err != nil {
+			return fn.Ok[ReviewResponse](
+				CreateReviewResp{Error: err},
+			)
+		}
+
 		resp := s.handleCreateReview(ctx, m)
 		return fn.Ok[ReviewResponse](resp)
 
@@ -558,8 +602,15 @@ func (s *Service) processOutbox(ctx context.Context,
 			}
 
 		case NotifyReviewStateChange:
-			// TODO(review): Send via notification hub for
-			// WebSocket broadcast.
+			s.mu.RLock()
+			notifier := s.notifier
+			s.mu.RUnlock()
+
+			if notifier != nil {
+				notifier.OnReviewStateChange(
+					e.ReviewID, e.OldState, e.NewState,
+				)
+			}
 
 		case SpawnReviewerAgent:
 			s.spawnReviewer(ctx, e)
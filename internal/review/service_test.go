@@ -9,6 +9,7 @@ import (
 
 	"github.com/roasbeef/subtrate/internal/baselib/actor"
 	"github.com/roasbeef/subtrate/internal/db"
+	"github.com/roasbeef/subtrate/internal/ratelimit"
 	"github.com/roasbeef/subtrate/internal/store"
 	"github.com/stretchr/testify/require"
 )
@@ -186,6 +187,65 @@ func TestService_CreateReview_Defaults(t *testing.T) {
 	require.Equal(t, "full", getResp.ReviewType)
 }
 
+// TestService_CreateReview_RateLimited tests that the service charges each
+// review it creates to the requester's review rate limit.
+func TestService_CreateReview_RateLimited(t *testing.T) {
+	t.Parallel()
+
+	as := actor.NewActorSystem()
+	storage, cleanup := testDB(t)
+	defer cleanup()
+
+	limiter := ratelimit.NewLimiter(storage)
+	svc := NewService(ServiceConfig{
+		Store:       storage,
+		ActorSystem: as,
+		RateLimiter: limiter,
+	})
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(
+			context.Background(), 5*time.Second,
+		)
+		defer cancel()
+		as.Shutdown(shutdownCtx)
+	}()
+
+	ctx := context.Background()
+	requester := createTestAgent(t, storage, "Dev")
+	_, err := limiter.Set(
+		ctx, requester.ID, ratelimit.ClassReview, 1, time.Hour, 0,
+	)
+	require.NoError(t, err)
+
+	create := func() CreateReviewResp {
+		result := svc.Receive(ctx, CreateReviewMsg{
+			RequesterID: requester.ID,
+			Branch:      "fix/bug",
+			CommitSHA:   "deadbeef",
+			RepoPath:    "/tmp/repo",
+		})
+		val, err := result.Unpack()
+		require.NoError(t, err)
+
+		return val.(CreateReviewResp)
+	}
+
+	resp := create()
+	require.NoError(t, resp.Error)
+	require.NotEmpty(t, resp.ReviewID)
+
+	// The second review is over the limit, and isn't created.
+	resp = create()
+	require.ErrorIs(t, resp.Error, ratelimit.ErrRateLimited)
+	require.Empty(t, resp.ReviewID)
+
+	reviews, err := storage.ListReviewsByRequester(
+		ctx, requester.ID, 10,
+	)
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+}
+
 // TestService_GetReview tests retrieving review details after creation.
 func TestService_GetReview(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,89 @@
+package search
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Kind is a type of entity a search can return.
+type Kind string
+
+const (
+	// KindMessage is a mail message, matched on its subject and body.
+	KindMessage Kind = "message"
+
+	// KindReviewIssue is a code review issue, matched on its title and
+	// description.
+	KindReviewIssue Kind = "review_issue"
+
+	// KindPlanReview is a plan review, matched on the plan's title and
+	// summary.
+	KindPlanReview Kind = "plan_review"
+
+	// KindTask is an agent task, matched on its subject and description.
+	KindTask Kind = "task"
+
+	// KindPlanAnnotation is an annotation on a plan, matched on its note
+	// and the plan text it's on.
+	KindPlanAnnotation Kind = "plan_annotation"
+
+	// KindDiffAnnotation is an annotation on a diff, matched on its note
+	// and file path.
+	KindDiffAnnotation Kind = "diff_annotation"
+)
+
+// Kinds are every kind of entity, in the order ties between them are
+// broken.
+var Kinds = []Kind{
+	KindMessage, KindReviewIssue, KindPlanReview, KindTask,
+	KindPlanAnnotation, KindDiffAnnotation,
+}
+
+// ParseKinds parses a comma-separated list of kinds. "annotation" stands
+// for both annotation kinds, and "all" or an empty list for every kind, in
+// which case nil is returned. Unknown kinds fail with an error wrapping
+// ErrInvalidQuery.
+func ParseKinds(s string) ([]Kind, error) {
+	var kinds []Kind
+	add := func(k Kind) {
+		if !slices.Contains(kinds, k) {
+			kinds = append(kinds, k)
+		}
+	}
+
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case name == "":
+			continue
+
+		case name == "all":
+			return nil, nil
+
+		case name == "annotation":
+			add(KindPlanAnnotation)
+			add(KindDiffAnnotation)
+
+		case slices.Contains(Kinds, Kind(name)):
+			add(Kind(name))
+
+		default:
+			return nil, fmt.Errorf("%w: unknown kind %q, expected "+
+				"all, annotation or one of %s", ErrInvalidQuery,
+				name, kindList())
+		}
+	}
+
+	return kinds, nil
+}
+
+// kindList returns the kinds as a comma-separated list.
+func kindList() string {
+	names := make([]string, len(Kinds))
+	for i, k := range Kinds {
+		names[i] = string(k)
+	}
+
+	return strings.Join(names, ", ")
+}
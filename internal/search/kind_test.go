@@ -0,0 +1,26 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseKinds tests that kind lists expand aliases and reject unknown
+// kinds.
+func TestParseKinds(t *testing.T) {
+	t.Parallel()
+
+	kinds, err := ParseKinds("all")
+	require.NoError(t, err)
+	require.Nil(t, kinds)
+
+	kinds, err = ParseKinds(" Task, annotation,task ")
+	require.NoError(t, err)
+	require.Equal(t, []Kind{
+		KindTask, KindPlanAnnotation, KindDiffAnnotation,
+	}, kinds)
+
+	_, err = ParseKinds("message,widget")
+	require.ErrorIs(t, err, ErrInvalidQuery)
+}
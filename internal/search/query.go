@@ -0,0 +1,543 @@
+// Package search parses the query language shared by every message search
+// front end: the CLI search command, the gRPC and MCP search calls and the
+// web UI's search bar.
+package search
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidQuery is returned for search queries that can't be parsed or
+// that name agents, topics or values that don't exist.
+var ErrInvalidQuery = errors.New("invalid search query")
+
+// DiffMarker is the line that marks a message body as carrying a diff. The
+// web UI renders such bodies with its diff viewer, and has:diff finds them.
+const DiffMarker = "<!-- substrate:diff -->"
+
+// Priorities are the values priority: accepts.
+var Priorities = []string{"urgent", "normal", "low"}
+
+// States are the values state: and is: accept.
+var States = []string{
+	"unread", "read", "starred", "snoozed", "archived", "trash",
+}
+
+// Query is a parsed search query. Empty fields don't filter.
+type Query struct {
+	// Match is the FTS5 expression the free-text terms compile to, or
+	// empty if the query has none that must match.
+	Match string
+
+	// Exclude is the FTS5 expression matching any of the terms excluded
+	// with "-" or NOT, or empty if none were.
+	Exclude string
+
+	// Text is the query's terms that aren't excluded, as plain text for
+	// matching by meaning rather than by words.
+	Text string
+
+	// From is the name of the agent that sent the message.
+	From string
+
+	// To is the name of an agent the message was sent to.
+	To string
+
+	// Topic is the name of the topic the message was sent on.
+	Topic string
+
+	// Thread is the ID of the thread the message is part of.
+	Thread string
+
+	// Label is the name of a label the searching agent filed the message
+	// under.
+	Label string
+
+	// Priority is the message's priority.
+	Priority string
+
+	// State is the message's state for the searching agent, or for any
+	// of its recipients in a global search.
+	State string
+
+	// After keeps the messages sent at or after the time.
+	After *time.Time
+
+	// Before keeps the messages sent before the time.
+	Before *time.Time
+
+	// HasDiff keeps the messages carrying a diff.
+	HasDiff bool
+
+	// HasAttachment keeps the messages with attachments.
+	HasAttachment bool
+}
+
+// IsEmpty returns true if the query neither has terms nor filters.
+func (q Query) IsEmpty() bool {
+	return q.Match == "" && q.Exclude == "" && q.From == "" &&
+		q.To == "" && q.Topic == "" && q.Thread == "" &&
+		q.Label == "" && q.Priority == "" && q.State == "" &&
+		q.After == nil && q.Before == nil && !q.HasDiff &&
+		!q.HasAttachment
+}
+
+// QuoteFilter renders an operator and its value as query text, quoting the
+// value if needed so that names with spaces or quotes survive parsing.
+func QuoteFilter(key, value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\r\n\"") {
+		return key + ":" + value
+	}
+
+	return key + `:"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
+
+// tokenKind identifies a lexed query token.
+type tokenKind int
+
+const (
+	tokenTerm tokenKind = iota
+	tokenFilter
+	tokenOr
+	tokenNot
+)
+
+// token is a lexed piece of a query.
+type token struct {
+	kind tokenKind
+
+	// col is the 1-based column the token starts at, for errors.
+	col int
+
+	// text is a term's text or a filter's value.
+	text string
+
+	// key is a filter's lowercased operator.
+	key string
+
+	// negated records a leading "-".
+	negated bool
+
+	// prefix records a trailing "*" on a term.
+	prefix bool
+}
+
+// Parse parses a search query. Free-text words and "quoted phrases" must all
+// match, OR between terms matches either of them, a trailing "*" matches
+// words by prefix, and "-" or NOT excludes a term. The operators filter the
+// results:
+//
+//	from:<agent>  to:<agent>  topic:<name>  thread:<id>  label:<name>
+//	priority:urgent|normal|low
+//	state:<state>  is:<state>  (unread, read, starred, snoozed, archived,
+//	                            trash)
+//	before:<time>  after:<time>  (2026-01-02, RFC 3339, or an age: 12h,
+//	                              7d, 2w)
+//	has:diff  has:attachment
+//
+// Operator values with spaces may be quoted, as in from:"Code Reviewer".
+// Any other word with a colon, like "fix:" or a URL, is searched for as text.
+// The terms compile to an FTS5 expression that is always valid, so malformed
+// queries fail here with an error wrapping ErrInvalidQuery rather than in
+// SQLite. Relative times are taken back from now.
+func Parse(input string, now time.Time) (Query, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return Query{}, err
+	}
+
+	var (
+		q        Query
+		clauses  [][]string
+		excluded []string
+		words    []string
+
+		// orCol and notCol are the columns of an OR or NOT still
+		// waiting for its term, or zero.
+		orCol, notCol int
+
+		// lastTerm records whether the previous token was a term that
+		// an OR can join onto.
+		lastTerm bool
+	)
+	for _, tok := range tokens {
+		switch tok.kind {
+		case tokenOr:
+			if !lastTerm || orCol != 0 {
+				return Query{}, invalidf("OR at column %d "+
+					"must join two search terms", tok.col)
+			}
+			orCol = tok.col
+
+		case tokenNot:
+			if notCol != 0 || orCol != 0 {
+				return Query{}, invalidf("NOT at column %d "+
+					"must be followed by a search term",
+					tok.col)
+			}
+			notCol = tok.col
+			lastTerm = false
+
+		case tokenTerm:
+			term := ftsTerm(tok)
+			if tok.negated || notCol != 0 {
+				if orCol != 0 {
+					return Query{}, invalidf("OR at "+
+						"column %d can't join an "+
+						"excluded term", orCol)
+				}
+				excluded = append(excluded, term)
+				notCol, lastTerm = 0, false
+
+				continue
+			}
+
+			words = append(words, tok.text)
+			if orCol != 0 {
+				last := len(clauses) - 1
+				clauses[last] = append(clauses[last], term)
+				orCol = 0
+			} else {
+				clauses = append(clauses, []string{term})
+			}
+			lastTerm = true
+
+		case tokenFilter:
+			if tok.negated || notCol != 0 || orCol != 0 {
+				return Query{}, invalidf("%s: at column %d "+
+					"can't be excluded or joined with OR",
+					tok.key, tok.col)
+			}
+			if err := q.apply(tok, now); err != nil {
+				return Query{}, err
+			}
+			lastTerm = false
+		}
+	}
+
+	switch {
+	case orCol != 0:
+		return Query{}, invalidf("OR at column %d must join two "+
+			"search terms", orCol)
+
+	case notCol != 0:
+		return Query{}, invalidf("NOT at column %d must be followed "+
+			"by a search term", notCol)
+	}
+
+	if q.After != nil && q.Before != nil && !q.After.Before(*q.Before) {
+		return Query{}, invalidf("after: must be earlier than before:")
+	}
+
+	matches := make([]string, len(clauses))
+	for i, alternatives := range clauses {
+		matches[i] = alternatives[0]
+		if len(alternatives) > 1 {
+			matches[i] = "(" +
+				strings.Join(alternatives, " OR ") + ")"
+		}
+	}
+	q.Match = strings.Join(matches, " AND ")
+	q.Exclude = strings.Join(excluded, " OR ")
+	q.Text = strings.Join(words, " ")
+
+	return q, nil
+}
+
+// apply applies a filter token to the query.
+func (q *Query) apply(tok token, now time.Time) error {
+	value := tok.text
+	switch tok.key {
+	case "from":
+		return setOnce(&q.From, "from", value)
+
+	case "to":
+		return setOnce(&q.To, "to", value)
+
+	case "topic":
+		return setOnce(&q.Topic, "topic", value)
+
+	case "thread":
+		return setOnce(&q.Thread, "thread", value)
+
+	case "label":
+		return setOnce(&q.Label, "label", value)
+
+	case "priority":
+		value = strings.ToLower(value)
+		if !slices.Contains(Priorities, value) {
+			return invalidf("unknown priority %q, want one of %s",
+				tok.text, strings.Join(Priorities, ", "))
+		}
+
+		return setOnce(&q.Priority, "priority", value)
+
+	case "state", "is":
+		value = strings.ToLower(value)
+		if !slices.Contains(States, value) {
+			return invalidf("unknown state %q in %s:, want one "+
+				"of %s", tok.text, tok.key,
+				strings.Join(States, ", "))
+		}
+
+		return setOnce(&q.State, "state", value)
+
+	case "has":
+		switch strings.ToLower(value) {
+		case "diff":
+			q.HasDiff = true
+
+		case "attachment", "attachments":
+			q.HasAttachment = true
+
+		default:
+			return invalidf("unknown has:%s, want has:diff or "+
+				"has:attachment", value)
+		}
+
+		return nil
+
+	case "before", "after":
+		t, err := parseTime(value, now)
+		if err != nil {
+			return invalidf("bad %s: time %q, want a date like "+
+				"2026-01-02, an RFC 3339 time or an age "+
+				"like 7d", tok.key, value)
+		}
+
+		bound := &q.After
+		if tok.key == "before" {
+			bound = &q.Before
+		}
+		if *bound != nil && !(*bound).Equal(t) {
+			return invalidf("conflicting %s: filters", tok.key)
+		}
+		*bound = &t
+
+		return nil
+	}
+
+	return invalidf("unknown operator %q", tok.key+":")
+}
+
+// operators are the filter keys Parse understands.
+var operators = []string{
+	"from", "to", "topic", "thread", "label", "priority", "state", "is",
+	"has", "before", "after",
+}
+
+// lex splits a query into tokens.
+func lex(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		if isSpace(input[i]) {
+			i++
+			continue
+		}
+
+		tok := token{kind: tokenTerm, col: i + 1}
+		if input[i] == '-' && i+1 < len(input) && !isSpace(input[i+1]) {
+			tok.negated = true
+			i++
+		}
+
+		// A quoted phrase, optionally matched by prefix.
+		if input[i] == '"' {
+			text, end, err := quoted(input, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			if i < len(input) && input[i] == '*' {
+				tok.prefix = true
+				i++
+			}
+			if i < len(input) && !isSpace(input[i]) {
+				return nil, invalidf("expected a space "+
+					"after the phrase at column %d",
+					tok.col)
+			}
+			if strings.TrimSpace(text) == "" {
+				return nil, invalidf("empty phrase at "+
+					"column %d", tok.col)
+			}
+			tok.text = text
+			tokens = append(tokens, tok)
+
+			continue
+		}
+
+		start := i
+		for i < len(input) && !isSpace(input[i]) {
+			// An operator whose value is quoted.
+			quotedValue := input[i] == ':' && i+1 < len(input) &&
+				input[i+1] == '"'
+			if quotedValue && isOperator(input[start:i]) {
+				i++
+				break
+			}
+			i++
+		}
+		word := input[start:i]
+
+		key, value, isFilter := strings.Cut(word, ":")
+		if isFilter && isOperator(key) {
+			tok.kind = tokenFilter
+			tok.key = strings.ToLower(key)
+
+			if value == "" && i < len(input) && input[i] == '"' {
+				var (
+					end int
+					err error
+				)
+				value, end, err = quoted(input, i)
+				if err != nil {
+					return nil, err
+				}
+				i = end
+			}
+			if strings.TrimSpace(value) == "" {
+				return nil, invalidf("%s: at column %d "+
+					"needs a value", tok.key, tok.col)
+			}
+			tok.text = value
+			tokens = append(tokens, tok)
+
+			continue
+		}
+
+		if !tok.negated {
+			switch word {
+			case "OR":
+				tok.kind = tokenOr
+				tokens = append(tokens, tok)
+				continue
+
+			case "NOT":
+				tok.kind = tokenNot
+				tokens = append(tokens, tok)
+				continue
+
+			// Terms are ANDed anyway.
+			case "AND":
+				continue
+			}
+		}
+
+		if strings.HasSuffix(word, "*") {
+			tok.prefix = true
+			word = strings.TrimSuffix(word, "*")
+		}
+		if word == "" {
+			return nil, invalidf("empty search term at column %d",
+				tok.col)
+		}
+		tok.text = word
+		tokens = append(tokens, tok)
+	}
+
+	return tokens, nil
+}
+
+// quoted reads the quoted string starting at the quote at input[start],
+// returning its unescaped text and the index just past the closing quote.
+// A doubled quote inside it stands for a literal one.
+func quoted(input string, start int) (string, int, error) {
+	var sb strings.Builder
+	for i := start + 1; i < len(input); i++ {
+		if input[i] != '"' {
+			sb.WriteByte(input[i])
+			continue
+		}
+		if i+1 < len(input) && input[i+1] == '"' {
+			sb.WriteByte('"')
+			i++
+
+			continue
+		}
+
+		return sb.String(), i + 1, nil
+	}
+
+	return "", 0, invalidf("unterminated quote at column %d", start+1)
+}
+
+// ftsTerm renders a term as an FTS5 string, which FTS5 tokenizes like the
+// indexed text, so no character in it can break the expression.
+func ftsTerm(tok token) string {
+	term := `"` + strings.ReplaceAll(tok.text, `"`, `""`) + `"`
+	if tok.prefix {
+		term += "*"
+	}
+
+	return term
+}
+
+// parseTime parses a before: or after: value: a date, taken as midnight
+// UTC, an RFC 3339 time, or an age in hours, days or weeks before now.
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if len(value) < 2 {
+		return time.Time{}, fmt.Errorf("bad time %q", value)
+	}
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n < 0 {
+		return time.Time{}, fmt.Errorf("bad time %q", value)
+	}
+
+	var unit time.Duration
+	switch value[len(value)-1] {
+	case 'h':
+		unit = time.Hour
+
+	case 'd':
+		unit = 24 * time.Hour
+
+	case 'w':
+		unit = 7 * 24 * time.Hour
+
+	default:
+		return time.Time{}, fmt.Errorf("bad time %q", value)
+	}
+
+	return now.Add(-time.Duration(n) * unit), nil
+}
+
+// setOnce sets a single-valued filter, rejecting a second, different value
+// for it.
+func setOnce(field *string, name, value string) error {
+	if *field != "" && *field != value {
+		return invalidf("conflicting %s filters %q and %q", name,
+			*field, value)
+	}
+	*field = value
+
+	return nil
+}
+
+// isOperator returns true if s names one of the operators, in any case.
+func isOperator(s string) bool {
+	return slices.Contains(operators, strings.ToLower(s))
+}
+
+// isSpace returns true for the ASCII whitespace that separates tokens.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// invalidf formats an error wrapping ErrInvalidQuery.
+func invalidf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidQuery, fmt.Sprintf(format,
+		args...))
+}
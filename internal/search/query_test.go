@@ -0,0 +1,169 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestParse tests that queries compile to the expected FTS5 expressions and
+// filters.
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	at := func(s string) *time.Time {
+		tm, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return &tm
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  Query
+	}{
+		{
+			name:  "words and phrases",
+			input: `deploy "hot fix" migrat*`,
+			want: Query{
+				Match: `"deploy" AND "hot fix" AND "migrat"*`,
+				Text:  "deploy hot fix migrat",
+			},
+		},
+		{
+			name: "or and exclusions",
+			input: `deploy OR release AND rollout -staging ` +
+				`NOT "dry run"`,
+			want: Query{
+				Match: `("deploy" OR "release") AND ` +
+					`"rollout"`,
+				Exclude: `"staging" OR "dry run"`,
+				Text:    "deploy release rollout",
+			},
+		},
+		{
+			// FTS5 syntax in terms is searched for literally.
+			name:  "fts syntax is quoted",
+			input: `NEAR(a b) C++ 10:30 say"hi`,
+			want: Query{
+				Match: `"NEAR(a" AND "b)" AND "C++" AND ` +
+					`"10:30" AND "say""hi"`,
+				Text: `NEAR(a b) C++ 10:30 say"hi`,
+			},
+		},
+		{
+			// Only the known operators filter, so other words
+			// with a colon are searched for.
+			name:  "unknown operators are text",
+			input: `fix: crash https://example.com/a?b=c Re:"hi"`,
+			want: Query{
+				Match: `"fix:" AND "crash" AND ` +
+					`"https://example.com/a?b=c" AND ` +
+					`"Re:""hi"""`,
+				Text: `fix: crash https://example.com/a?b=c ` +
+					`Re:"hi"`,
+			},
+		},
+		{
+			name: "filters",
+			input: `from:Alice to:"Code Reviewer" ` +
+				`topic:builds thread:t-1 label:ops ` +
+				`priority:URGENT is:unread has:diff ` +
+				`has:attachment bug`,
+			want: Query{
+				Match:         `"bug"`,
+				Text:          "bug",
+				From:          "Alice",
+				To:            "Code Reviewer",
+				Topic:         "builds",
+				Thread:        "t-1",
+				Label:         "ops",
+				Priority:      "urgent",
+				State:         "unread",
+				HasDiff:       true,
+				HasAttachment: true,
+			},
+		},
+		{
+			name:  "dates",
+			input: `after:2026-10-01 before:2026-10-15T08:00:00Z`,
+			want: Query{
+				After:  at("2026-10-01T00:00:00Z"),
+				Before: at("2026-10-15T08:00:00Z"),
+			},
+		},
+		{
+			name:  "relative dates",
+			input: `after:2w before:36h state:starred`,
+			want: Query{
+				After:  at("2026-10-02T12:00:00Z"),
+				Before: at("2026-10-15T00:00:00Z"),
+				State:  "starred",
+			},
+		},
+		{
+			name:  "repeated filters agree",
+			input: `is:unread state:unread from:bob from:bob`,
+			want:  Query{State: "unread", From: "bob"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := Parse(tc.input, now)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, q)
+		})
+	}
+}
+
+// TestParseErrors tests that malformed queries are rejected with errors that
+// point at the problem.
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`"hot fix`, "unterminated quote at column 1"},
+		{`from:"Code Reviewer`, "unterminated quote at column 6"},
+		{`OR deploy`, "OR at column 1 must join two search terms"},
+		{`deploy OR`, "OR at column 8 must join two search terms"},
+		{`deploy OR OR release`, "OR at column 11"},
+		{`deploy OR -release`, "can't join an excluded term"},
+		{`deploy NOT`, "NOT at column 8 must be followed"},
+		{`-from:alice`, "from: at column 1 can't be excluded"},
+		{`deploy OR from:alice`, "can't be excluded or joined"},
+		{`from:`, "from: at column 1 needs a value"},
+		{`"hot"fix`, "expected a space after the phrase"},
+		{`""`, "empty phrase"},
+		{`*`, "empty search term"},
+		{`priority:high`, `unknown priority "high"`},
+		{`is:pinned`, `unknown state "pinned" in is:`},
+		{`has:link`, "unknown has:link"},
+		{`before:yesterday`, "bad before: time"},
+		{`after:2026-10-02 before:2026-10-01`, "must be earlier"},
+		{`is:unread is:read`, "conflicting state filters"},
+	}
+	for _, tc := range tests {
+		_, err := Parse(tc.input, now)
+		require.ErrorIs(t, err, ErrInvalidQuery, tc.input)
+		require.ErrorContains(t, err, tc.want, tc.input)
+	}
+}
+
+// TestQuoteFilter tests that quoted filters parse back to their values.
+func TestQuoteFilter(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"builds", "release train", `say "hi"`} {
+		q, err := Parse(QuoteFilter("topic", name), time.Now())
+		require.NoError(t, err)
+		require.Equal(t, name, q.Topic)
+	}
+}
@@ -0,0 +1,114 @@
+package search
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultSubjectWeight is how much more a term matched in a message's
+	// subject counts towards its BM25 rank than one matched in its body.
+	DefaultSubjectWeight = 3.0
+
+	// HighlightStart and HighlightEnd wrap the matched terms in snippets
+	// and highlighted subjects. They render as bold in markdown.
+	HighlightStart = "**"
+	HighlightEnd   = "**"
+
+	// SnippetEllipsis marks the text a snippet cut off.
+	SnippetEllipsis = "…"
+
+	// SnippetTokens is the number of tokens a snippet spans around the
+	// matched terms.
+	SnippetTokens = 16
+
+	// ExcerptRunes caps the excerpts shown for results that didn't match
+	// any terms, such as those of filter-only queries.
+	ExcerptRunes = 120
+)
+
+// Cursor is the position of the last result of a page of search results.
+// The next page starts after it.
+type Cursor struct {
+	// Ranked is true for the results of a query with terms, which are
+	// ordered by rank, and false for those of a filter-only query, which
+	// are ordered newest first.
+	Ranked bool
+
+	// Rank is the BM25 rank of the last result, lower being better.
+	Rank float64
+
+	// CreatedAt is the Unix time the last result was sent at.
+	CreatedAt int64
+
+	// ID is the ID of the last result, which breaks ties.
+	ID int64
+}
+
+// Encode returns the cursor as an opaque, URL-safe string.
+func (c Cursor) Encode() string {
+	var raw string
+	if c.Ranked {
+		raw = "r/" + strconv.FormatFloat(c.Rank, 'g', -1, 64)
+	} else {
+		raw = "t/" + strconv.FormatInt(c.CreatedAt, 10)
+	}
+	raw += "/" + strconv.FormatInt(c.ID, 10)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseCursor decodes a cursor returned by Encode. Malformed cursors fail
+// with an error wrapping ErrInvalidQuery.
+func ParseCursor(s string) (Cursor, error) {
+	bad := fmt.Errorf("%w: malformed cursor %q", ErrInvalidQuery, s)
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, bad
+	}
+	parts := strings.Split(string(raw), "/")
+	if len(parts) != 3 {
+		return Cursor{}, bad
+	}
+
+	var c Cursor
+	c.ID, err = strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return Cursor{}, bad
+	}
+
+	switch parts[0] {
+	case "r":
+		c.Ranked = true
+		c.Rank, err = strconv.ParseFloat(parts[1], 64)
+
+	case "t":
+		c.CreatedAt, err = strconv.ParseInt(parts[1], 10, 64)
+
+	default:
+		return Cursor{}, bad
+	}
+	if err != nil {
+		return Cursor{}, bad
+	}
+
+	return c, nil
+}
+
+// Excerpt returns the start of text with its whitespace collapsed, cut to
+// at most limit runes.
+func Excerpt(text string, limit int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+
+	runes := []rune(text)
+	cut := strings.TrimRight(string(runes[:limit]), " ")
+
+	return cut + SnippetEllipsis
+}
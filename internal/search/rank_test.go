@@ -0,0 +1,36 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCursor tests that cursors survive encoding and that malformed ones are
+// rejected.
+func TestCursor(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []Cursor{
+		{Ranked: true, Rank: -1.8451612903225808e-06, ID: 4},
+		{Ranked: true, Rank: 0, ID: 1},
+		{CreatedAt: 1792167042, ID: 2},
+	} {
+		got, err := ParseCursor(c.Encode())
+		require.NoError(t, err)
+		require.Equal(t, c, got)
+	}
+
+	for _, s := range []string{"", "zzz", "eC8xLzI", "ci8xLzI vMw"} {
+		_, err := ParseCursor(s)
+		require.ErrorIs(t, err, ErrInvalidQuery, s)
+	}
+}
+
+// TestExcerpt tests that excerpts collapse whitespace and cut long text.
+func TestExcerpt(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "a b c", Excerpt(" a\n\tb  c ", 10))
+	require.Equal(t, "héllo"+SnippetEllipsis, Excerpt("héllo world", 6))
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/roasbeef/subtrate/internal/db/sqlc"
+	"github.com/roasbeef/subtrate/internal/embed"
+	"github.com/roasbeef/subtrate/internal/search"
 )
 
 // MessageStore handles message persistence operations.
@@ -52,6 +54,19 @@ type MessageStore interface {
 		ctx context.Context, agentID int64, limit, offset int,
 	) ([]InboxMessage, error)
 
+	// GetOverdueMessages retrieves unacked inbox messages whose deadline
+	// is at or before now, most overdue first. An agentID of 0 returns
+	// overdue messages across all agents.
+	GetOverdueMessages(
+		ctx context.Context, agentID int64, now time.Time,
+		limit, offset int,
+	) ([]InboxMessage, error)
+
+	// UpdateMessagePriority changes the priority of a message.
+	UpdateMessagePriority(
+		ctx context.Context, messageID int64, priority string,
+	) error
+
 	// GetArchivedMessages retrieves archived messages for an agent.
 	GetArchivedMessages(
 		ctx context.Context, agentID int64, limit int,
@@ -83,6 +98,17 @@ type MessageStore interface {
 		ctx context.Context, messageID, agentID int64,
 	) (MessageRecipient, error)
 
+	// PersistRecipientState writes the complete recipient state for a
+	// message, including all timestamp columns. This is used to apply
+	// transitions emitted by the thread state machine.
+	PersistRecipientState(
+		ctx context.Context, params PersistRecipientStateParams,
+	) error
+
+	// ListSnoozedRecipients lists all snoozed recipients that have a
+	// pending wake time, ordered by the wake time.
+	ListSnoozedRecipients(ctx context.Context) ([]SnoozedRecipient, error)
+
 	// CountUnreadByAgent counts unread messages for an agent.
 	CountUnreadByAgent(ctx context.Context, agentID int64) (int64, error)
 
@@ -124,6 +150,19 @@ type MessageStore interface {
 		ctx context.Context, query string, limit int,
 	) ([]InboxMessage, error)
 
+	// SearchMessagesWithFilters runs a full-text search narrowed by the
+	// filters of a parsed search query, best matches first, or newest
+	// first if there are no terms to rank by.
+	SearchMessagesWithFilters(
+		ctx context.Context, params MessageSearchParams,
+	) ([]MessageSearchHit, error)
+
+	// CountMessagesWithFilters returns the number of messages a filtered
+	// search matches in all, ignoring its cursor and limit.
+	CountMessagesWithFilters(
+		ctx context.Context, params MessageSearchParams,
+	) (int64, error)
+
 	// GetMessagesByTopic retrieves all messages for a topic.
 	GetMessagesByTopic(ctx context.Context, topicID int64) ([]Message, error)
 
@@ -215,6 +254,12 @@ type TopicStore interface {
 	// ListTopicsByType lists topics of a specific type.
 	ListTopicsByType(ctx context.Context, topicType string) ([]Topic, error)
 
+	// UpdateTopicRetention sets how long a topic's messages are kept, in
+	// seconds. Zero keeps them forever.
+	UpdateTopicRetention(
+		ctx context.Context, topicID, retentionSeconds int64,
+	) error
+
 	// CreateSubscription subscribes an agent to a topic.
 	CreateSubscription(
 		ctx context.Context, agentID, topicID int64,
@@ -252,6 +297,715 @@ type ActivityStore interface {
 	DeleteOldActivities(ctx context.Context, olderThan time.Time) error
 }
 
+// EscalationStore handles persistence of deadline escalation progress.
+type EscalationStore interface {
+	// ListOverdueRecipients lists recipients of unacked messages whose
+	// next escalation level is due as of now, most overdue first.
+	// levelDelays are how long past the deadline each level is due, so
+	// a recipient at level n is listed once its deadline plus
+	// levelDelays[n] has passed.
+	ListOverdueRecipients(ctx context.Context, now time.Time,
+		levelDelays []time.Duration, limit int,
+	) ([]OverdueRecipient, error)
+
+	// GetMessageEscalation retrieves the escalation progress for a
+	// recipient. Returns sql.ErrNoRows if it was never escalated.
+	GetMessageEscalation(
+		ctx context.Context, messageID, agentID int64,
+	) (MessageEscalation, error)
+
+	// UpsertMessageEscalation records the escalation level reached for a
+	// recipient.
+	UpsertMessageEscalation(
+		ctx context.Context, params UpsertMessageEscalationParams,
+	) error
+}
+
+// QueueDeliveryStore handles the competing-consumer lifecycle of messages
+// published to queue topics.
+type QueueDeliveryStore interface {
+	// CreateQueueDelivery registers a newly published queue message as
+	// pending.
+	CreateQueueDelivery(ctx context.Context, messageID, topicID int64) error
+
+	// GetQueueDelivery retrieves the delivery state of a queue message.
+	GetQueueDelivery(
+		ctx context.Context, messageID int64,
+	) (QueueDelivery, error)
+
+	// ClaimNextQueueDelivery leases the oldest claimable message on a
+	// queue topic to an agent. Returns sql.ErrNoRows if there is nothing
+	// to claim.
+	ClaimNextQueueDelivery(
+		ctx context.Context, params ClaimQueueDeliveryParams,
+	) (QueueDelivery, error)
+
+	// AckQueueDelivery completes the agent's lease on a message. It
+	// reports false if the agent doesn't hold the lease.
+	AckQueueDelivery(
+		ctx context.Context, messageID, agentID int64,
+	) (bool, error)
+
+	// ReleaseQueueDelivery hands the agent's lease on a message back to
+	// the queue. It reports false if the agent doesn't hold the lease.
+	ReleaseQueueDelivery(
+		ctx context.Context, messageID, agentID int64, reason string,
+	) (bool, error)
+
+	// ExtendQueueLease pushes out the expiry of the agent's lease on a
+	// message. It reports false if the agent doesn't hold the lease.
+	ExtendQueueLease(
+		ctx context.Context, messageID, agentID int64,
+		expiresAt time.Time,
+	) (bool, error)
+
+	// ListExpiredQueueLeases lists leases that expired as of now without
+	// being acked.
+	ListExpiredQueueLeases(
+		ctx context.Context, now time.Time,
+	) ([]QueueDelivery, error)
+
+	// ReleaseExpiredQueueLeases returns expired leases with delivery
+	// attempts left to the pending state.
+	ReleaseExpiredQueueLeases(
+		ctx context.Context, now time.Time, maxDeliveries int,
+	) (int64, error)
+
+	// ListExhaustedQueueDeliveries lists unacked messages that have used
+	// up their delivery attempts.
+	ListExhaustedQueueDeliveries(
+		ctx context.Context, now time.Time, maxDeliveries int,
+	) ([]QueueDelivery, error)
+
+	// MarkQueueDeliveryDeadLettered marks a message as moved to its
+	// dead-letter topic.
+	MarkQueueDeliveryDeadLettered(ctx context.Context, messageID int64) error
+}
+
+// RetentionStore handles enforcement of per-topic message retention.
+type RetentionStore interface {
+	// SummarizeExpiredMessages summarizes, per topic, the messages that
+	// have outlived their topic's retention window as of now, and how
+	// many of them may be deleted.
+	SummarizeExpiredMessages(
+		ctx context.Context, now time.Time,
+	) ([]ExpiredTopicSummary, error)
+
+	// DeleteExpiredMessages deletes up to limit expired, unprotected
+	// messages along with their recipient rows and search index
+	// entries. The number of deleted messages is returned.
+	DeleteExpiredMessages(
+		ctx context.Context, now time.Time, limit int,
+	) (int64, error)
+
+	// CountStaleConsumerOffsets counts consumer offsets whose agent is no
+	// longer subscribed to the topic.
+	CountStaleConsumerOffsets(ctx context.Context) (int64, error)
+
+	// DeleteStaleConsumerOffsets deletes consumer offsets whose agent is
+	// no longer subscribed to the topic.
+	DeleteStaleConsumerOffsets(ctx context.Context) (int64, error)
+
+	// OptimizeSearchIndex merges the full-text search index after bulk
+	// deletes.
+	OptimizeSearchIndex(ctx context.Context) error
+
+	// SetMessageLegalHold places or lifts a legal hold on a message. It
+	// returns the number of messages updated.
+	SetMessageLegalHold(
+		ctx context.Context, messageID int64, hold bool,
+	) (int64, error)
+
+	// SetThreadLegalHold places or lifts a legal hold on every message in
+	// a thread. It returns the number of messages updated.
+	SetThreadLegalHold(
+		ctx context.Context, threadID string, hold bool,
+	) (int64, error)
+}
+
+// RecipientGroupStore handles named recipient groups and the agent lookups
+// behind dynamic recipient aliases.
+type RecipientGroupStore interface {
+	// CreateRecipientGroup creates a new, empty recipient group.
+	CreateRecipientGroup(
+		ctx context.Context, params CreateRecipientGroupParams,
+	) (RecipientGroup, error)
+
+	// GetRecipientGroupByName retrieves a recipient group by its name.
+	GetRecipientGroupByName(
+		ctx context.Context, name string,
+	) (RecipientGroup, error)
+
+	// ListRecipientGroups lists all recipient groups with their member
+	// counts.
+	ListRecipientGroups(ctx context.Context) ([]RecipientGroup, error)
+
+	// DeleteRecipientGroup deletes a recipient group and its
+	// memberships. It returns false if the group didn't exist.
+	DeleteRecipientGroup(ctx context.Context, id int64) (bool, error)
+
+	// AddRecipientGroupMember adds an agent to a group. Adding an
+	// existing member is a no-op.
+	AddRecipientGroupMember(
+		ctx context.Context, groupID, agentID int64,
+	) error
+
+	// RemoveRecipientGroupMember removes an agent from a group. It
+	// returns false if the agent wasn't a member.
+	RemoveRecipientGroupMember(
+		ctx context.Context, groupID, agentID int64,
+	) (bool, error)
+
+	// ListRecipientGroupMembers lists the agents in a group by name.
+	ListRecipientGroupMembers(
+		ctx context.Context, groupID int64,
+	) ([]Agent, error)
+
+	// ListAgentsMatchingProject lists agents whose project key equals
+	// the given key or is a path ending in it.
+	ListAgentsMatchingProject(
+		ctx context.Context, project string,
+	) ([]Agent, error)
+
+	// ListAgentsByBranch lists agents working on the given git branch.
+	ListAgentsByBranch(ctx context.Context, branch string) ([]Agent, error)
+
+	// ListAgentsActiveSince lists agents whose last heartbeat is at or
+	// after the given time.
+	ListAgentsActiveSince(
+		ctx context.Context, since time.Time,
+	) ([]Agent, error)
+
+	// ListAgentsByNamePrefix lists agents whose name starts with the
+	// given prefix.
+	ListAgentsByNamePrefix(
+		ctx context.Context, prefix string,
+	) ([]Agent, error)
+
+	// CreateRecipientExpansion records that a group or alias in a
+	// message's recipient list resolved to the given agent.
+	CreateRecipientExpansion(
+		ctx context.Context, messageID int64, address string,
+		agentID int64,
+	) error
+
+	// ListRecipientExpansions lists the recorded expansions of a
+	// message's recipient groups and aliases.
+	ListRecipientExpansions(
+		ctx context.Context, messageID int64,
+	) ([]RecipientExpansion, error)
+}
+
+// DeadLetterStore handles the dead-letter mailbox, which records the
+// addressees of a message that could not be delivered to.
+type DeadLetterStore interface {
+	// CreateDeadLetter records an undeliverable addressee of a message.
+	CreateDeadLetter(ctx context.Context, params CreateDeadLetterParams) error
+
+	// ListDeadLetters lists a sender's dead letters, newest first.
+	ListDeadLetters(
+		ctx context.Context, senderID int64, limit int,
+	) ([]DeadLetter, error)
+
+	// DeleteDeadLetter deletes one of a sender's dead letters. It returns
+	// false if the sender has no dead letter with that ID.
+	DeleteDeadLetter(ctx context.Context, id, senderID int64) (bool, error)
+}
+
+// ScheduledMessageStore handles messages that are queued to be sent at a
+// later time.
+type ScheduledMessageStore interface {
+	// CreateScheduledMessage queues a message to be sent at its send
+	// time.
+	CreateScheduledMessage(
+		ctx context.Context, params CreateScheduledMessageParams,
+	) (ScheduledMessage, error)
+
+	// GetScheduledMessageByIdempotencyKey retrieves a scheduled message
+	// by the idempotency key it will be sent with.
+	GetScheduledMessageByIdempotencyKey(
+		ctx context.Context, key string,
+	) (ScheduledMessage, error)
+
+	// ListScheduledMessages lists a sender's pending and failed scheduled
+	// messages, soonest first.
+	ListScheduledMessages(
+		ctx context.Context, senderID int64, limit int,
+	) ([]ScheduledMessage, error)
+
+	// ListDueScheduledMessages lists pending scheduled messages whose
+	// send time is at or before now, oldest first.
+	ListDueScheduledMessages(
+		ctx context.Context, now time.Time, limit int,
+	) ([]ScheduledMessage, error)
+
+	// CancelScheduledMessage cancels one of a sender's unsent scheduled
+	// messages. It returns false if the sender has no unsent scheduled
+	// message with that ID.
+	CancelScheduledMessage(
+		ctx context.Context, id, senderID int64,
+	) (bool, error)
+
+	// MarkScheduledMessageSent records the message a scheduled message
+	// was delivered as.
+	MarkScheduledMessageSent(ctx context.Context, id, messageID int64) error
+
+	// MarkScheduledMessageFailed records why a pending scheduled message
+	// could not be delivered.
+	MarkScheduledMessageFailed(
+		ctx context.Context, id int64, lastError string,
+	) error
+
+	// DeferScheduledMessage pushes a pending scheduled message's send
+	// time back to sendAt, recording why it couldn't be sent yet.
+	DeferScheduledMessage(
+		ctx context.Context, id int64, sendAt time.Time,
+		lastError string,
+	) error
+}
+
+// ScheduleStore handles recurring messages sent on a cron schedule.
+type ScheduleStore interface {
+	// CreateSchedule creates a recurring schedule.
+	CreateSchedule(
+		ctx context.Context, params CreateScheduleParams,
+	) (Schedule, error)
+
+	// GetScheduleByName retrieves one of an agent's schedules by name.
+	GetScheduleByName(
+		ctx context.Context, senderID int64, name string,
+	) (Schedule, error)
+
+	// ListSchedules lists an agent's schedules by name.
+	ListSchedules(ctx context.Context, senderID int64) ([]Schedule, error)
+
+	// ListDueSchedules lists schedules that are not paused and whose next
+	// run is at or before now, oldest first.
+	ListDueSchedules(
+		ctx context.Context, now time.Time, limit int,
+	) ([]Schedule, error)
+
+	// DeleteSchedule deletes one of an agent's schedules. It returns
+	// false if the agent has no schedule with that name.
+	DeleteSchedule(
+		ctx context.Context, senderID int64, name string,
+	) (bool, error)
+
+	// SetSchedulePaused pauses or resumes one of an agent's schedules and
+	// sets its next run. It returns false if the agent has no schedule
+	// with that name.
+	SetSchedulePaused(
+		ctx context.Context, senderID int64, name string, paused bool,
+		nextRunAt time.Time,
+	) (bool, error)
+
+	// RecordScheduleRun records a run of a schedule and moves it on to
+	// its next occurrence.
+	RecordScheduleRun(
+		ctx context.Context, params RecordScheduleRunParams,
+	) error
+
+	// AdvanceSchedule moves a schedule on to its next occurrence without
+	// recording a run.
+	AdvanceSchedule(
+		ctx context.Context, id int64, nextRunAt time.Time,
+	) error
+}
+
+// QuestionStore handles structured questions and their answers.
+type QuestionStore interface {
+	// CreateQuestion records a question asked by a message.
+	CreateQuestion(
+		ctx context.Context, params CreateQuestionParams,
+	) (Question, error)
+
+	// GetQuestion retrieves a question by its public ID.
+	GetQuestion(ctx context.Context, questionID string) (Question, error)
+
+	// ListPendingQuestionsByThread lists the questions in a thread that
+	// are still waiting for an answer.
+	ListPendingQuestionsByThread(
+		ctx context.Context, threadID string,
+	) ([]Question, error)
+
+	// ListTimedOutQuestions lists pending questions whose current
+	// timeout is at or before now, oldest first.
+	ListTimedOutQuestions(
+		ctx context.Context, now time.Time, limit int,
+	) ([]Question, error)
+
+	// ResolveQuestion records the answer to a pending question. It
+	// returns false if the question was no longer pending.
+	ResolveQuestion(
+		ctx context.Context, params ResolveQuestionParams,
+	) (bool, error)
+
+	// EscalateQuestion records that a question was escalated to its
+	// fallback recipient and sets its next timeout.
+	EscalateQuestion(
+		ctx context.Context, id int64, timeoutAt time.Time,
+	) error
+}
+
+// MessageRevisionStore handles message edits, their revision history and
+// recalls.
+type MessageRevisionStore interface {
+	// CreateMessageRevision records a version of a message that an edit
+	// is replacing.
+	CreateMessageRevision(
+		ctx context.Context, params CreateMessageRevisionParams,
+	) (MessageRevision, error)
+
+	// ListMessageRevisions lists the replaced versions of a message,
+	// oldest first.
+	ListMessageRevisions(
+		ctx context.Context, messageID int64,
+	) ([]MessageRevision, error)
+
+	// ListThreadMessageRevisions lists the replaced versions of every
+	// message in a thread, grouped by message and oldest first.
+	ListThreadMessageRevisions(
+		ctx context.Context, threadID string,
+	) ([]MessageRevision, error)
+
+	// UpdateMessageContent replaces the subject and body of a message
+	// with an edited version. It returns false if the message doesn't
+	// exist or is under a legal hold.
+	UpdateMessageContent(
+		ctx context.Context, messageID int64, subject, body string,
+		editedAt time.Time,
+	) (bool, error)
+
+	// RecallMessage deletes one of a sender's messages that no recipient
+	// has read or otherwise acted on yet. It returns false if the message
+	// isn't the sender's, has been read, is under a legal hold or backs a
+	// plan review.
+	RecallMessage(
+		ctx context.Context, messageID, senderID int64,
+	) (bool, error)
+}
+
+// InboxRuleStore handles per-agent inbox rules and the recipient state they
+// set.
+type InboxRuleStore interface {
+	// CreateInboxRule creates an inbox rule.
+	CreateInboxRule(
+		ctx context.Context, params CreateInboxRuleParams,
+	) (InboxRule, error)
+
+	// GetInboxRuleByName retrieves one of an agent's inbox rules by name.
+	GetInboxRuleByName(
+		ctx context.Context, agentID int64, name string,
+	) (InboxRule, error)
+
+	// ListInboxRules lists an agent's inbox rules in the order they are
+	// applied.
+	ListInboxRules(ctx context.Context, agentID int64) ([]InboxRule, error)
+
+	// DeleteInboxRule deletes one of an agent's inbox rules. It returns
+	// false if the agent has no rule with that name.
+	DeleteInboxRule(
+		ctx context.Context, agentID int64, name string,
+	) (bool, error)
+
+	// SetInboxRuleEnabled enables or disables one of an agent's inbox
+	// rules. It returns false if the agent has no rule with that name.
+	SetInboxRuleEnabled(
+		ctx context.Context, agentID int64, name string, enabled bool,
+	) (bool, error)
+
+	// RecordInboxRuleMatch counts a message an inbox rule matched.
+	RecordInboxRuleMatch(
+		ctx context.Context, id int64, matchedAt time.Time,
+	) error
+
+	// SetRecipientCategory files a message under an inbox category for
+	// one of its recipients.
+	SetRecipientCategory(
+		ctx context.Context, messageID, agentID int64,
+		category InboxCategory,
+	) error
+
+	// ListReceivedMessages lists an agent's most recently received
+	// messages in any state but trash, newest first.
+	ListReceivedMessages(
+		ctx context.Context, agentID int64, limit int,
+	) ([]InboxMessage, error)
+}
+
+// LabelStore handles the labels agents file their messages under.
+type LabelStore interface {
+	// CreateLabel creates a label for an agent.
+	CreateLabel(
+		ctx context.Context, agentID int64, name string,
+	) (Label, error)
+
+	// GetLabelByName retrieves one of an agent's labels by name.
+	GetLabelByName(
+		ctx context.Context, agentID int64, name string,
+	) (Label, error)
+
+	// ListLabels lists an agent's labels by name, with their message
+	// counts.
+	ListLabels(ctx context.Context, agentID int64) ([]Label, error)
+
+	// DeleteLabel deletes one of an agent's labels, removing it from the
+	// messages it was applied to. It returns false if the agent has no
+	// label with that name.
+	DeleteLabel(
+		ctx context.Context, agentID int64, name string,
+	) (bool, error)
+
+	// AddMessageLabel applies a label to a recipient's copy of a message.
+	// Applying a label the message already carries is a no-op.
+	AddMessageLabel(
+		ctx context.Context, messageID, agentID, labelID int64,
+	) error
+
+	// RemoveMessageLabel removes a label from a recipient's copy of a
+	// message. It returns false if the message didn't carry the label.
+	RemoveMessageLabel(
+		ctx context.Context, messageID, agentID, labelID int64,
+	) (bool, error)
+
+	// ListMessageLabels returns the names of the labels an agent applied
+	// to each of the given messages, keyed by message ID. Messages
+	// without labels are left out.
+	ListMessageLabels(
+		ctx context.Context, agentID int64, messageIDs []int64,
+	) (map[int64][]string, error)
+
+	// GetInboxMessagesByLabel retrieves the messages an agent filed under
+	// a label, in any state but trash, newest first.
+	GetInboxMessagesByLabel(
+		ctx context.Context, agentID int64, label string,
+		limit, offset int,
+	) ([]InboxMessage, error)
+}
+
+// CredentialStore handles the credentials clients authenticate with. Only
+// the hash of each token is stored.
+type CredentialStore interface {
+	// CreateCredential stores a new credential.
+	CreateCredential(
+		ctx context.Context, params CreateCredentialParams,
+	) (Credential, error)
+
+	// GetCredentialByHash retrieves a credential by the hash of its
+	// token. It returns sql.ErrNoRows if there is none.
+	GetCredentialByHash(
+		ctx context.Context, tokenHash string,
+	) (Credential, error)
+
+	// ListCredentials lists credentials, newest first. A non-zero
+	// agentID lists only that agent's, and revoked credentials are only
+	// included if asked for.
+	ListCredentials(
+		ctx context.Context, agentID int64, includeRevoked bool,
+	) ([]Credential, error)
+
+	// RevokeCredential revokes a credential. It returns false if there
+	// is no such credential or it was already revoked.
+	RevokeCredential(
+		ctx context.Context, id int64, revokedAt time.Time,
+	) (bool, error)
+
+	// TouchCredential records when a credential was last used.
+	TouchCredential(
+		ctx context.Context, id int64, usedAt time.Time,
+	) error
+}
+
+// LimitStore handles the rate limits and quotas agents are held to and the
+// token buckets the rate limits draw from.
+type LimitStore interface {
+	// GetAgentLimit retrieves the limit that applies to an agent for a
+	// class: the agent's own if it has one, else the default. It returns
+	// sql.ErrNoRows if neither is set.
+	GetAgentLimit(
+		ctx context.Context, agentID int64, class string,
+	) (AgentLimit, error)
+
+	// SetAgentLimit sets an agent's limit for a class, or the default if
+	// AgentID is 0, replacing any already set.
+	SetAgentLimit(
+		ctx context.Context, params SetAgentLimitParams,
+	) (AgentLimit, error)
+
+	// DeleteAgentLimit deletes an agent's limit for a class, or the
+	// default if agentID is 0. It returns false if none was set.
+	DeleteAgentLimit(
+		ctx context.Context, agentID int64, class string,
+	) (bool, error)
+
+	// ListAgentLimits lists the default limits followed by the agents'
+	// own. A non-zero agentID lists only the limits that apply to it.
+	ListAgentLimits(
+		ctx context.Context, agentID int64,
+	) ([]AgentLimit, error)
+
+	// GetRateLimitBucket retrieves an agent's token bucket for a class.
+	// It returns sql.ErrNoRows if the agent hasn't drawn from it yet.
+	GetRateLimitBucket(
+		ctx context.Context, agentID int64, class string,
+	) (RateLimitBucket, error)
+
+	// UpsertRateLimitBucket stores an agent's token bucket for a class.
+	UpsertRateLimitBucket(
+		ctx context.Context, bucket RateLimitBucket,
+	) error
+
+	// ListRateLimitBuckets lists an agent's token buckets.
+	ListRateLimitBuckets(
+		ctx context.Context, agentID int64,
+	) ([]RateLimitBucket, error)
+
+	// SumSenderBodyBytes returns the total size in bytes of the message
+	// bodies an agent sent since a time.
+	SumSenderBodyBytes(
+		ctx context.Context, senderID int64, since time.Time,
+	) (int64, error)
+}
+
+// CapabilityStore handles the capabilities agents declare and the routes
+// the router took for mail and tasks addressed to a capability.
+type CapabilityStore interface {
+	// SetAgentCapabilities replaces an agent's capabilities.
+	SetAgentCapabilities(
+		ctx context.Context, agentID int64, caps []AgentCapability,
+	) error
+
+	// ListAgentCapabilities lists an agent's capabilities by kind and
+	// name.
+	ListAgentCapabilities(
+		ctx context.Context, agentID int64,
+	) ([]AgentCapability, error)
+
+	// ListAllAgentCapabilities lists the capabilities of every agent,
+	// keyed by agent ID.
+	ListAllAgentCapabilities(
+		ctx context.Context,
+	) (map[int64][]AgentCapability, error)
+
+	// UpdateAgentMaxConcurrency sets the most tasks an agent takes on at
+	// once, or 0 for no limit.
+	UpdateAgentMaxConcurrency(
+		ctx context.Context, agentID, maxConcurrency int64,
+	) error
+
+	// ListCapabilityCandidates lists the agents with a capability, of any
+	// kind if kind is empty, along with their current load.
+	ListCapabilityCandidates(
+		ctx context.Context, kind, name string,
+	) ([]CapabilityCandidate, error)
+
+	// CreateCapabilityRoute records the agent the router picked for a
+	// message or task addressed to a capability.
+	CreateCapabilityRoute(
+		ctx context.Context, params CreateCapabilityRouteParams,
+	) (CapabilityRoute, error)
+
+	// ListCapabilityRoutes lists the newest routes, of every capability
+	// if capability is empty.
+	ListCapabilityRoutes(
+		ctx context.Context, capability string, limit int,
+	) ([]CapabilityRoute, error)
+
+	// GetLatestTaskRoute retrieves the latest route taken for a task.
+	GetLatestTaskRoute(
+		ctx context.Context, taskID int64,
+	) (CapabilityRoute, error)
+}
+
+// SavedSearchStore handles the search queries agents saved by name.
+type SavedSearchStore interface {
+	// UpsertSavedSearch saves a search for an agent under a name,
+	// replacing the query of one it already saved under that name.
+	UpsertSavedSearch(
+		ctx context.Context, agentID int64, name, query string,
+	) (SavedSearch, error)
+
+	// GetSavedSearch retrieves one of an agent's saved searches by name.
+	GetSavedSearch(
+		ctx context.Context, agentID int64, name string,
+	) (SavedSearch, error)
+
+	// ListSavedSearches lists an agent's saved searches by name.
+	ListSavedSearches(
+		ctx context.Context, agentID int64,
+	) ([]SavedSearch, error)
+
+	// DeleteSavedSearch deletes one of an agent's saved searches. It
+	// returns false if the agent has no search saved under that name.
+	DeleteSavedSearch(
+		ctx context.Context, agentID int64, name string,
+	) (bool, error)
+}
+
+// ThreadStore handles per-agent thread preferences and moving messages
+// between threads.
+type ThreadStore interface {
+	// GetThreadPreference retrieves an agent's preferences for a thread.
+	// It returns sql.ErrNoRows if the agent set none.
+	GetThreadPreference(
+		ctx context.Context, threadID string, agentID int64,
+	) (ThreadPreference, error)
+
+	// SetThreadPreference creates or replaces an agent's preferences for
+	// a thread.
+	SetThreadPreference(
+		ctx context.Context, pref ThreadPreference,
+	) (ThreadPreference, error)
+
+	// DeleteThreadPreference clears an agent's preferences for a thread.
+	// It returns false if the agent set none.
+	DeleteThreadPreference(
+		ctx context.Context, threadID string, agentID int64,
+	) (bool, error)
+
+	// ListThreadPreferences lists the threads an agent muted or follows,
+	// most recently changed first.
+	ListThreadPreferences(
+		ctx context.Context, agentID int64,
+	) ([]ThreadPreference, error)
+
+	// ListThreadPreferencesByThread lists every agent's preferences for a
+	// thread.
+	ListThreadPreferencesByThread(
+		ctx context.Context, threadID string,
+	) ([]ThreadPreference, error)
+
+	// CopyThreadPreferences gives a thread the preferences agents set on
+	// another, keeping any they already set on it.
+	CopyThreadPreferences(
+		ctx context.Context, fromThreadID, toThreadID string,
+	) error
+
+	// DeleteThreadPreferences clears every agent's preferences for a
+	// thread.
+	DeleteThreadPreferences(ctx context.Context, threadID string) error
+
+	// CountThreadMessages counts the messages in a thread.
+	CountThreadMessages(ctx context.Context, threadID string) (int64, error)
+
+	// CountThreadReviews counts the code reviews and plan reviews that
+	// track a thread.
+	CountThreadReviews(ctx context.Context, threadID string) (int64, error)
+
+	// MoveThreadMessages moves messages, and the questions they ask, from
+	// one thread to another, returning how many messages moved. Callers
+	// should run it in a transaction.
+	MoveThreadMessages(
+		ctx context.Context, params MoveThreadMessagesParams,
+	) (int64, error)
+
+	// MoveScheduledThreadMessages points the messages still waiting to be
+	// sent into a thread at another.
+	MoveScheduledThreadMessages(
+		ctx context.Context, fromThreadID, toThreadID string,
+	) error
+}
+
 // SummaryStore handles agent summary persistence.
 type SummaryStore interface {
 	// CreateSummary persists a new agent activity summary.
@@ -622,18 +1376,79 @@ type AnnotationStore interface {
 	) error
 }
 
+// SearchStore provides full-text search across every indexed entity type.
+type SearchStore interface {
+	// SearchAll runs a full-text search over the kinds of entities the
+	// params select, best matches first.
+	SearchAll(
+		ctx context.Context, params SearchAllParams,
+	) ([]SearchAllHit, error)
+
+	// CountSearchAll returns the number of entities a search matches in
+	// all, ignoring its limit.
+	CountSearchAll(
+		ctx context.Context, params SearchAllParams,
+	) (int64, error)
+}
+
+// EmbeddingStore handles the vector embeddings semantic search compares
+// messages by.
+type EmbeddingStore interface {
+	// UpsertMessageEmbedding stores a message's embedding under a model,
+	// replacing any it had.
+	UpsertMessageEmbedding(
+		ctx context.Context, params UpsertMessageEmbeddingParams,
+	) error
+
+	// GetMessageEmbedding retrieves a message's embedding under a model.
+	GetMessageEmbedding(
+		ctx context.Context, messageID int64, model string,
+	) (MessageEmbedding, error)
+
+	// ListMessagesToEmbed lists up to limit of the oldest messages
+	// without an embedding under a model.
+	ListMessagesToEmbed(
+		ctx context.Context, model string, limit int,
+	) ([]Message, error)
+
+	// ListMessageEmbeddings lists the embeddings under a model of the
+	// messages a search's filters keep. The search's Match, Cursor and
+	// Limit are ignored.
+	ListMessageEmbeddings(
+		ctx context.Context, model string, params MessageSearchParams,
+	) ([]MessageEmbedding, error)
+}
+
 // Storage combines all store interfaces for unified access.
 type Storage interface {
 	MessageStore
 	AgentStore
 	TopicStore
 	ActivityStore
+	EscalationStore
+	QueueDeliveryStore
+	RetentionStore
+	RecipientGroupStore
+	DeadLetterStore
+	ScheduledMessageStore
+	ScheduleStore
+	QuestionStore
+	MessageRevisionStore
+	InboxRuleStore
+	LabelStore
+	SavedSearchStore
+	CapabilityStore
+	CredentialStore
+	LimitStore
+	ThreadStore
 	SummaryStore
 	SessionStore
 	TaskStore
 	ReviewStore
 	PlanReviewStore
 	AnnotationStore
+	SearchStore
+	EmbeddingStore
 
 	// WithTx executes a function within a write database transaction.
 	WithTx(ctx context.Context, fn func(ctx context.Context, s Storage) error) error
@@ -663,6 +1478,14 @@ type Message struct {
 	CreatedAt       time.Time
 	DeletedBySender bool
 	IdempotencyKey  string
+
+	// Metadata is the message's JSON-encoded metadata object, or empty
+	// if it has none.
+	Metadata string
+
+	// EditedAt is when the sender last edited the message, or nil if it
+	// was never edited.
+	EditedAt *time.Time
 }
 
 // MessageRecipient represents a message recipient's state.
@@ -673,6 +1496,10 @@ type MessageRecipient struct {
 	SnoozedUntil *time.Time
 	ReadAt       *time.Time
 	AckedAt      *time.Time
+
+	// Category is the inbox category an inbox rule filed the message
+	// under, or InboxCategoryAll if it is derived from the subject.
+	Category InboxCategory
 }
 
 // MessageRecipientWithAgent extends MessageRecipient with agent name.
@@ -681,6 +1508,13 @@ type MessageRecipientWithAgent struct {
 	AgentName string
 }
 
+// SnoozedRecipient extends MessageRecipient with the message's thread ID so
+// the thread state machine can be rebuilt without a second lookup.
+type SnoozedRecipient struct {
+	MessageRecipient
+	ThreadID string
+}
+
 // InboxMessage represents a message in an agent's inbox with metadata.
 type InboxMessage struct {
 	Message
@@ -693,6 +1527,185 @@ type InboxMessage struct {
 	AckedAt          *time.Time
 }
 
+// MessageSearchParams filters a message search. Zero-valued fields don't
+// filter.
+type MessageSearchParams struct {
+	// AgentID restricts the search to the messages the agent sent or
+	// received, with State and Label applying to its copies. Zero
+	// searches every message.
+	AgentID int64
+
+	// Match is an FTS5 expression the message's subject or body must
+	// match.
+	Match string
+
+	// Exclude is an FTS5 expression the message must not match.
+	Exclude string
+
+	// SenderID is the agent that sent the message.
+	SenderID int64
+
+	// RecipientID is an agent the message was sent to.
+	RecipientID int64
+
+	// TopicID is the topic the message was sent on.
+	TopicID int64
+
+	// ThreadID is the thread the message is part of.
+	ThreadID string
+
+	// Priority is the message's priority.
+	Priority string
+
+	// State is the searching agent's state for the message, or any
+	// recipient's in a global search.
+	State string
+
+	// Label is a label the searching agent filed the message under. It
+	// needs AgentID.
+	Label string
+
+	// After keeps the messages created at or after the time.
+	After *time.Time
+
+	// Before keeps the messages created before the time.
+	Before *time.Time
+
+	// BodyContains is text the message's body must contain verbatim.
+	BodyContains string
+
+	// HasAttachments keeps the messages with attachments.
+	HasAttachments bool
+
+	// MessageIDs, if set, keeps only the messages with these IDs.
+	MessageIDs []int64
+
+	// SubjectWeight is how much more a term matched in the subject
+	// counts towards a message's rank than one matched in its body.
+	// Defaults to search.DefaultSubjectWeight.
+	SubjectWeight float64
+
+	// IncludeBody returns the messages' bodies. Without it only their
+	// snippets are.
+	IncludeBody bool
+
+	// Cursor resumes the search after the last result of a previous
+	// page.
+	Cursor *search.Cursor
+
+	// Limit caps the number of messages returned.
+	Limit int
+}
+
+// MessageSearchHit is a message matched by a search.
+type MessageSearchHit struct {
+	InboxMessage
+
+	// Snippet is the excerpt of the body around the matched terms, or
+	// the start of the body if the search had none.
+	Snippet string
+
+	// SubjectHighlight is the subject with its matched terms
+	// highlighted.
+	SubjectHighlight string
+
+	// Rank is the message's BM25 rank, lower being better, or zero if
+	// the search had no terms.
+	Rank float64
+}
+
+// SearchAllParams selects and filters a search across entity types.
+type SearchAllParams struct {
+	// AgentID restricts the messages searched to the ones the agent sent
+	// or received. Zero searches every message. The other kinds aren't
+	// private to an agent.
+	AgentID int64
+
+	// Match is an FTS5 expression the entity's text must match.
+	Match string
+
+	// Exclude is an FTS5 expression the entity must not match.
+	Exclude string
+
+	// Kinds are the kinds of entities to search, or every kind if
+	// empty.
+	Kinds []search.Kind
+
+	// After keeps the entities created at or after the time.
+	After *time.Time
+
+	// Before keeps the entities created before the time.
+	Before *time.Time
+
+	// TitleWeight is how much more a term matched in an entity's title
+	// counts towards its rank than one matched in its text. Defaults to
+	// search.DefaultSubjectWeight.
+	TitleWeight float64
+
+	// Limit caps the number of hits returned.
+	Limit int
+}
+
+// SearchAllHit is an entity matched by a search across entity types.
+type SearchAllHit struct {
+	// Kind is the type of entity matched.
+	Kind search.Kind
+
+	// ID is the entity's row ID: a message, review issue, plan review,
+	// task or annotation ID.
+	ID int64
+
+	// Ref identifies what the entity belongs to: the review ID of an
+	// issue, the plan review ID of a plan review or plan annotation, the
+	// task list ID of a task, or the message ID of a diff annotation. It
+	// is empty for messages.
+	Ref string
+
+	// ThreadID is the message thread the entity is discussed in, if
+	// any.
+	ThreadID string
+
+	// Title is the entity's title, with matched terms highlighted where
+	// the title is indexed.
+	Title string
+
+	// Snippet is the excerpt of the entity's text around the matched
+	// terms.
+	Snippet string
+
+	// Rank is the entity's BM25 rank, lower being better.
+	Rank float64
+
+	// CreatedAt is when the entity was created.
+	CreatedAt time.Time
+}
+
+// UpsertMessageEmbeddingParams contains parameters for storing a message's
+// embedding.
+type UpsertMessageEmbeddingParams struct {
+	MessageID int64
+	Model     string
+	Vector    embed.Vector
+}
+
+// MessageEmbedding is a message's vector embedding under a model.
+type MessageEmbedding struct {
+	MessageID int64
+	Model     string
+	Vector    embed.Vector
+	CreatedAt time.Time
+}
+
+// Cursor returns the cursor the page after the hit starts at.
+func (h MessageSearchHit) Cursor(ranked bool) search.Cursor {
+	return search.Cursor{
+		Ranked:    ranked,
+		Rank:      h.Rank,
+		CreatedAt: h.CreatedAt.Unix(),
+		ID:        h.ID,
+	}
+}
+
 // InboxCategory enumerates the server-side inbox partitions used by the
 // web UI tabs. The empty value means no category filter.
 type InboxCategory string
@@ -721,6 +1734,9 @@ type InboxCategoryCounts struct {
 	Notifications int64
 	Unread        int64
 	Starred       int64
+
+	// Labels holds the counts of each of the agent's labels, by name.
+	Labels []Label
 }
 
 // Agent represents an agent in the system.
@@ -735,6 +1751,10 @@ type Agent struct {
 	Purpose          string
 	WorkingDir       string
 	Hostname         string
+
+	// MaxConcurrency is the most tasks the agent takes on at once, or 0
+	// for no limit.
+	MaxConcurrency int64
 }
 
 // DiscoveredAgent extends Agent with discovery-specific metadata like unread
@@ -839,6 +1859,20 @@ type CreateMessageParams struct {
 	DeadlineAt     *time.Time
 	Attachments    string
 	IdempotencyKey string
+
+	// Metadata is a JSON-encoded metadata object, or empty for none.
+	Metadata string
+}
+
+// PersistRecipientStateParams contains the full recipient state to write for
+// a message. Nil timestamps are stored as NULL.
+type PersistRecipientStateParams struct {
+	MessageID    int64
+	AgentID      int64
+	State        string
+	ReadAt       *time.Time
+	AckedAt      *time.Time
+	SnoozedUntil *time.Time
 }
 
 // CreateAgentParams contains parameters for creating an agent.
@@ -863,6 +1897,554 @@ type CreateActivityParams struct {
 	Metadata     string
 }
 
+// OverdueRecipient is a recipient of an unacked message whose deadline has
+// passed, along with the escalation level reached so far.
+type OverdueRecipient struct {
+	MessageID       int64
+	AgentID         int64
+	State           string
+	ThreadID        string
+	SenderID        int64
+	Subject         string
+	Priority        string
+	DeadlineAt      time.Time
+	EscalationLevel int
+}
+
+// MessageEscalation records how far an overdue recipient has been escalated.
+type MessageEscalation struct {
+	MessageID       int64
+	AgentID         int64
+	Level           int
+	LastEscalatedAt time.Time
+}
+
+// UpsertMessageEscalationParams contains parameters for recording an
+// escalation level.
+type UpsertMessageEscalationParams struct {
+	MessageID int64
+	AgentID   int64
+	Level     int
+}
+
+// QueueDelivery is the delivery state of a message on a queue topic.
+type QueueDelivery struct {
+	MessageID      int64
+	TopicID        int64
+	TopicName      string
+	State          string
+	ClaimedBy      int64
+	LeaseExpiresAt *time.Time
+	DeliveryCount  int
+	LastError      string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// ClaimQueueDeliveryParams contains parameters for claiming a queue message.
+type ClaimQueueDeliveryParams struct {
+	TopicID        int64
+	AgentID        int64
+	LeaseExpiresAt time.Time
+	MaxDeliveries  int
+}
+
+// ExpiredTopicSummary describes the messages of a topic that have outlived
+// its retention window.
+type ExpiredTopicSummary struct {
+	TopicID          int64
+	TopicName        string
+	RetentionSeconds int64
+
+	// ExpiredCount is the number of messages past the retention window,
+	// including protected ones.
+	ExpiredCount int64
+
+	// DeletableCount is the number of expired messages that aren't
+	// protected and may be deleted.
+	DeletableCount int64
+
+	// RecipientCount is the number of recipient rows belonging to the
+	// deletable messages.
+	RecipientCount int64
+}
+
+// RecipientGroup is a named set of agents addressable as "@<name>".
+type RecipientGroup struct {
+	ID          int64
+	Name        string
+	Description string
+	CreatedBy   *int64
+	CreatedAt   time.Time
+
+	// MemberCount is only populated by ListRecipientGroups.
+	MemberCount int64
+}
+
+// CreateRecipientGroupParams contains parameters for creating a recipient
+// group.
+type CreateRecipientGroupParams struct {
+	Name        string
+	Description string
+	CreatedBy   *int64
+}
+
+// RecipientExpansion records one agent that a recipient group or dynamic
+// alias resolved to when a message was sent.
+type RecipientExpansion struct {
+	Address   string
+	AgentID   int64
+	AgentName string
+}
+
+// DeadLetter is an addressee of a message that could not be delivered to.
+type DeadLetter struct {
+	ID        int64
+	MessageID int64
+	ThreadID  string
+	Subject   string
+	SenderID  int64
+	Recipient string
+	Status    string
+	Reason    string
+	CreatedAt time.Time
+}
+
+// CreateDeadLetterParams contains parameters for recording a dead letter.
+type CreateDeadLetterParams struct {
+	MessageID int64
+	SenderID  int64
+	Recipient string
+	Status    string
+	Reason    string
+}
+
+// ScheduledMessage is a message queued to be sent at a later time.
+type ScheduledMessage struct {
+	ID             int64
+	SenderID       int64
+	RecipientNames []string
+	TopicName      string
+	ThreadID       string
+	Subject        string
+	Body           string
+	Priority       string
+	DeadlineAt     *time.Time
+	Attachments    string
+	Metadata       string
+	DeliveryMode   string
+	IdempotencyKey string
+	SendAt         time.Time
+	State          string
+	MessageID      int64
+	LastError      string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// CreateScheduledMessageParams contains parameters for scheduling a message.
+type CreateScheduledMessageParams struct {
+	SenderID       int64
+	RecipientNames []string
+	TopicName      string
+	ThreadID       string
+	Subject        string
+	Body           string
+	Priority       string
+	DeadlineAt     *time.Time
+	Attachments    string
+	Metadata       string
+	DeliveryMode   string
+	IdempotencyKey string
+	SendAt         time.Time
+}
+
+// Schedule is a recurring message sent on a cron schedule.
+type Schedule struct {
+	ID             int64
+	Name           string
+	SenderID       int64
+	RecipientNames []string
+	TopicName      string
+	Subject        string
+	Body           string
+	Priority       string
+	CronExpr       string
+	Timezone       string
+	CatchUp        string
+	Paused         bool
+	NextRunAt      time.Time
+	LastRunAt      *time.Time
+	LastMessageID  int64
+	LastError      string
+	RunCount       int64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// CreateScheduleParams contains parameters for creating a schedule.
+type CreateScheduleParams struct {
+	Name           string
+	SenderID       int64
+	RecipientNames []string
+	TopicName      string
+	Subject        string
+	Body           string
+	Priority       string
+	CronExpr       string
+	Timezone       string
+	CatchUp        string
+	NextRunAt      time.Time
+}
+
+// RecordScheduleRunParams contains parameters for recording a schedule run.
+type RecordScheduleRunParams struct {
+	ID        int64
+	RunAt     time.Time
+	NextRunAt time.Time
+
+	// MessageID is the message sent by the run, or zero if it failed.
+	MessageID int64
+	LastError string
+}
+
+// Question is a structured question asked through mail.
+type Question struct {
+	ID              int64
+	QuestionID      string
+	MessageID       int64
+	ThreadID        string
+	AskerID         int64
+	Question        string
+	Options         []string
+	DefaultAnswer   string
+	Timeout         time.Duration
+	TimeoutAt       *time.Time
+	Fallback        string
+	EscalatedAt     *time.Time
+	State           string
+	Answer          string
+	AnsweredBy      int64
+	AnswerMessageID int64
+	AnsweredAt      *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// CreateQuestionParams contains parameters for recording a question.
+type CreateQuestionParams struct {
+	QuestionID    string
+	MessageID     int64
+	ThreadID      string
+	AskerID       int64
+	Question      string
+	Options       []string
+	DefaultAnswer string
+
+	// Timeout is how long each stage of the question waits for an
+	// answer, or zero if the question never times out.
+	Timeout  time.Duration
+	Fallback string
+}
+
+// ResolveQuestionParams contains parameters for resolving a question.
+type ResolveQuestionParams struct {
+	ID     int64
+	State  string
+	Answer string
+
+	// AnsweredBy and AnswerMessageID identify the reply that answered
+	// the question, and are zero if it timed out.
+	AnsweredBy      int64
+	AnswerMessageID int64
+}
+
+// MessageRevision is a version of a message that was replaced by an edit.
+type MessageRevision struct {
+	ID        int64
+	MessageID int64
+
+	// Revision numbers a message's versions from 1, the message as it
+	// was originally sent.
+	Revision int
+	Subject  string
+	Body     string
+
+	// CreatedAt is when this version was sent or edited in, and
+	// ReplacedAt when it was edited out.
+	CreatedAt  time.Time
+	ReplacedAt time.Time
+}
+
+// CreateMessageRevisionParams contains parameters for recording a message
+// revision.
+type CreateMessageRevisionParams struct {
+	MessageID  int64
+	Revision   int
+	Subject    string
+	Body       string
+	CreatedAt  time.Time
+	ReplacedAt time.Time
+}
+
+// InboxRuleConditions are the conditions of an inbox rule. A rule matches a
+// message when every condition that is set matches it.
+type InboxRuleConditions struct {
+	// SenderName matches the exact name of the sender.
+	SenderName string
+
+	// SenderPrefix matches senders whose name starts with it.
+	SenderPrefix string
+
+	// SubjectPattern is a regular expression matched against the
+	// subject.
+	SubjectPattern string
+
+	// TopicName matches messages published to the topic.
+	TopicName string
+
+	// Priority matches messages of the priority.
+	Priority string
+
+	// MetadataKey matches messages whose metadata has the key, with the
+	// value MetadataValue if that is set too.
+	MetadataKey   string
+	MetadataValue string
+}
+
+// InboxRuleActions are the actions an inbox rule applies to the messages it
+// matches.
+type InboxRuleActions struct {
+	// Archive archives the message.
+	Archive bool
+
+	// Star stars the message.
+	Star bool
+
+	// Snooze snoozes the message for a duration, such as "2h", or until
+	// the next occurrence of a time of day, such as "09:00".
+	Snooze string
+
+	// Category files the message under an inbox category.
+	Category InboxCategory
+
+	// ForwardTo names an agent the message is also delivered to.
+	ForwardTo string
+
+	// Label files the message under one of the agent's labels, creating
+	// the label if needed.
+	Label string
+}
+
+// InboxRule is a per-agent rule applied to messages as they are delivered.
+type InboxRule struct {
+	ID             int64
+	AgentID        int64
+	Name           string
+	Enabled        bool
+	StopProcessing bool
+
+	InboxRuleConditions
+	InboxRuleActions
+
+	MatchCount    int64
+	LastMatchedAt *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// CreateInboxRuleParams contains parameters for creating an inbox rule.
+type CreateInboxRuleParams struct {
+	AgentID        int64
+	Name           string
+	StopProcessing bool
+
+	InboxRuleConditions
+	InboxRuleActions
+}
+
+// Label is a user-defined inbox category an agent files messages under. A
+// message can carry several labels.
+type Label struct {
+	ID        int64
+	AgentID   int64
+	Name      string
+	CreatedAt time.Time
+
+	// MessageCount is how many of the messages carrying the label are in
+	// the agent's inbox, and UnreadCount how many of those are unread.
+	MessageCount int64
+	UnreadCount  int64
+}
+
+// Capability kinds an agent declares capabilities under.
+const (
+	// CapabilityLanguage is a programming language the agent works in.
+	CapabilityLanguage = "language"
+
+	// CapabilityRepo is a repository the agent knows.
+	CapabilityRepo = "repo"
+
+	// CapabilityTool is a tool the agent has, such as an MCP server.
+	CapabilityTool = "tool"
+
+	// CapabilitySkill is anything else the agent can do, such as
+	// can-review-security.
+	CapabilitySkill = "skill"
+)
+
+// AgentCapability is a capability an agent declared.
+type AgentCapability struct {
+	Kind string
+	Name string
+}
+
+// CapabilityCandidate is an agent with a capability, along with the load
+// the router weighs it by.
+type CapabilityCandidate struct {
+	Agent
+	UnreadCount     int64
+	InProgressCount int64
+}
+
+// CapabilityRoute is the agent the router picked for a message or task
+// addressed to a capability.
+type CapabilityRoute struct {
+	ID              int64
+	Capability      string
+	AgentID         int64
+	AgentName       string
+	MessageID       *int64
+	TaskID          *int64
+	Candidates      int64
+	UnreadCount     int64
+	InProgressCount int64
+	CreatedAt       time.Time
+}
+
+// CreateCapabilityRouteParams contains parameters for recording a
+// capability route. Exactly one of MessageID and TaskID is set.
+type CreateCapabilityRouteParams struct {
+	Capability      string
+	AgentID         int64
+	MessageID       *int64
+	TaskID          *int64
+	Candidates      int64
+	UnreadCount     int64
+	InProgressCount int64
+}
+
+// Credential is a token a client authenticates with. An agent credential
+// acts as its agent, while an admin credential, with no agent, may act as
+// any.
+type Credential struct {
+	ID        int64
+	AgentID   int64
+	AgentName string
+	Name      string
+	TokenHash string
+
+	// AllowedMethods are the RPCs the credential may call, by method
+	// name such as SendMail. Empty allows every RPC.
+	AllowedMethods []string
+
+	ExpiresAt  *time.Time
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// IsAdmin reports whether the credential is an admin credential.
+func (c Credential) IsAdmin() bool {
+	return c.AgentID == 0
+}
+
+// CreateCredentialParams contains parameters for storing a credential. An
+// AgentID of 0 creates an admin credential.
+type CreateCredentialParams struct {
+	AgentID        int64
+	Name           string
+	TokenHash      string
+	AllowedMethods []string
+	ExpiresAt      *time.Time
+}
+
+// AgentLimit is a rate limit or quota an agent is held to for a class. An
+// AgentID of 0 is the default for every agent without its own.
+type AgentLimit struct {
+	ID        int64
+	AgentID   int64
+	AgentName string
+	Class     string
+
+	// Amount is how many calls are allowed every Interval, or for a
+	// quota, how many bytes.
+	Amount   int64
+	Interval time.Duration
+
+	// Burst is how many calls may be made at once.
+	Burst int64
+
+	UpdatedAt time.Time
+}
+
+// SetAgentLimitParams contains parameters for setting a limit. An AgentID
+// of 0 sets the default.
+type SetAgentLimitParams struct {
+	AgentID  int64
+	Class    string
+	Amount   int64
+	Interval time.Duration
+	Burst    int64
+}
+
+// RateLimitBucket is the token bucket an agent draws from for a class of
+// RPCs.
+type RateLimitBucket struct {
+	AgentID int64
+	Class   string
+
+	// Tokens is how many calls the agent may make as of UpdatedAt.
+	Tokens    float64
+	UpdatedAt time.Time
+
+	// ThrottledCount is how many calls were refused for lack of tokens.
+	ThrottledCount  int64
+	LastThrottledAt *time.Time
+}
+
+// SavedSearch is a search query an agent saved under a name.
+type SavedSearch struct {
+	ID        int64
+	AgentID   int64
+	Name      string
+	Query     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ThreadPreference is an agent's preferences for a thread. A muted thread's
+// new replies skip the agent's unread state, and a followed thread's replies
+// reach the agent even when it isn't addressed.
+type ThreadPreference struct {
+	ThreadID  string
+	AgentID   int64
+	Muted     bool
+	Following bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// MoveThreadMessagesParams describes messages moving between threads.
+type MoveThreadMessagesParams struct {
+	FromThreadID string
+	ToThreadID   string
+
+	// MinMessageID, if set, moves only the messages from this one on,
+	// leaving the earlier ones behind. Zero moves the whole thread.
+	MinMessageID int64
+}
+
 // CreateSessionIdentityParams contains parameters for creating a session
 // identity.
 type CreateSessionIdentityParams struct {
@@ -941,6 +2523,8 @@ func MessageFromSqlc(m sqlc.Message) Message {
 	if m.IdempotencyKey.Valid {
 		msg.IdempotencyKey = m.IdempotencyKey.String
 	}
+	msg.Metadata = m.Metadata.String
+	msg.EditedAt = nullInt64ToTime(m.EditedAt)
 	return msg
 }
 
@@ -957,6 +2541,7 @@ func AgentFromSqlc(a sqlc.Agent) Agent {
 		Purpose:          a.Purpose.String,
 		WorkingDir:       a.WorkingDir.String,
 		Hostname:         a.Hostname.String,
+		MaxConcurrency:   a.MaxConcurrency,
 	}
 }
 
@@ -1034,6 +2619,7 @@ func MessageRecipientFromSqlc(r sqlc.MessageRecipient) MessageRecipient {
 		MessageID: r.MessageID,
 		AgentID:   r.AgentID,
 		State:     r.State,
+		Category:  InboxCategory(r.Category),
 	}
 	if r.SnoozedUntil.Valid {
 		t := time.Unix(r.SnoozedUntil.Int64, 0)
@@ -37,6 +37,63 @@ type MockStore struct {
 	planAnnotations map[string]PlanAnnotation // Keyed by annotation_id.
 	diffAnnotations map[string]DiffAnnotation // Keyed by annotation_id.
 
+	// Escalation data stores.
+	escalations map[escalationKey]MessageEscalation
+
+	// Queue delivery data stores.
+	queueDeliveries map[int64]QueueDelivery // Keyed by message ID.
+
+	// Retention data stores.
+	legalHolds map[int64]bool // Message IDs under a legal hold.
+
+	// Recipient group data stores.
+	recipientGroups     map[int64]RecipientGroup
+	groupMembers        map[int64]map[int64]bool       // [groupID][agentID]
+	recipientExpansions map[int64][]RecipientExpansion // Keyed by message ID.
+
+	// Dead letter data stores.
+	deadLetters map[int64]DeadLetter
+
+	// Scheduled message data stores.
+	scheduledMessages map[int64]ScheduledMessage
+
+	// Schedule data stores.
+	schedules map[int64]Schedule
+
+	// Question data stores.
+	questions map[int64]Question
+
+	// Message revision data stores, keyed by message ID.
+	messageRevisions map[int64][]MessageRevision
+
+	// Inbox rule data stores.
+	inboxRules map[int64]InboxRule
+
+	// Label data stores. messageLabels holds the IDs of the labels on a
+	// recipient's copy of a message.
+	labels        map[int64]Label
+	messageLabels map[messageLabelKey]map[int64]bool
+
+	// Saved search data stores.
+	savedSearches map[int64]SavedSearch
+
+	// Capability data stores.
+	capabilities     map[int64][]AgentCapability // Keyed by agent ID.
+	capabilityRoutes map[int64]CapabilityRoute
+
+	// Credential data stores.
+	credentials map[int64]Credential
+
+	// Limit data stores.
+	agentLimits      map[int64]AgentLimit
+	rateLimitBuckets map[bucketKey]RateLimitBucket
+
+	// Thread preference data stores.
+	threadPrefs map[threadPrefKey]ThreadPreference
+
+	// Embedding data stores, keyed by message ID and then model.
+	embeddings map[int64]map[string]MessageEmbedding
+
 	// Counters for auto-incrementing IDs.
 	nextMessageID        int64
 	nextAgentID          int64
@@ -47,6 +104,18 @@ type MockStore struct {
 	nextSummaryID        int64
 	nextPlanAnnotationID int64
 	nextDiffAnnotationID int64
+	nextGroupID          int64
+	nextDeadLetterID     int64
+	nextScheduledID      int64
+	nextScheduleID       int64
+	nextQuestionID       int64
+	nextRevisionID       int64
+	nextInboxRuleID      int64
+	nextLabelID          int64
+	nextSavedSearchID    int64
+	nextRouteID          int64
+	nextCredentialID     int64
+	nextAgentLimitID     int64
 }
 
 // NewMockStore creates a new in-memory mock store.
@@ -68,6 +137,28 @@ func NewMockStore() *MockStore {
 		tasksByKey:           make(map[string]int64),
 		planAnnotations:      make(map[string]PlanAnnotation),
 		diffAnnotations:      make(map[string]DiffAnnotation),
+		escalations:          make(map[escalationKey]MessageEscalation),
+		queueDeliveries:      make(map[int64]QueueDelivery),
+		legalHolds:           make(map[int64]bool),
+		recipientGroups:      make(map[int64]RecipientGroup),
+		groupMembers:         make(map[int64]map[int64]bool),
+		recipientExpansions:  make(map[int64][]RecipientExpansion),
+		deadLetters:          make(map[int64]DeadLetter),
+		scheduledMessages:    make(map[int64]ScheduledMessage),
+		schedules:            make(map[int64]Schedule),
+		questions:            make(map[int64]Question),
+		messageRevisions:     make(map[int64][]MessageRevision),
+		inboxRules:           make(map[int64]InboxRule),
+		labels:               make(map[int64]Label),
+		messageLabels:        make(map[messageLabelKey]map[int64]bool),
+		savedSearches:        make(map[int64]SavedSearch),
+		capabilities:         make(map[int64][]AgentCapability),
+		capabilityRoutes:     make(map[int64]CapabilityRoute),
+		credentials:          make(map[int64]Credential),
+		agentLimits:          make(map[int64]AgentLimit),
+		rateLimitBuckets:     make(map[bucketKey]RateLimitBucket),
+		threadPrefs:          make(map[threadPrefKey]ThreadPreference),
+		embeddings:           make(map[int64]map[string]MessageEmbedding),
 		nextMessageID:        1,
 		nextAgentID:          1,
 		nextTopicID:          1,
@@ -77,6 +168,18 @@ func NewMockStore() *MockStore {
 		nextSummaryID:        1,
 		nextPlanAnnotationID: 1,
 		nextDiffAnnotationID: 1,
+		nextGroupID:          1,
+		nextDeadLetterID:     1,
+		nextScheduledID:      1,
+		nextScheduleID:       1,
+		nextQuestionID:       1,
+		nextRevisionID:       1,
+		nextInboxRuleID:      1,
+		nextLabelID:          1,
+		nextSavedSearchID:    1,
+		nextRouteID:          1,
+		nextCredentialID:     1,
+		nextAgentLimitID:     1,
 	}
 }
 
@@ -162,6 +265,7 @@ func (m *MockStore) CreateMessage(
 		CreatedAt:       time.Now(),
 		DeletedBySender: false,
 		IdempotencyKey:  params.IdempotencyKey,
+		Metadata:        params.Metadata,
 	}
 
 	m.nextMessageID++
@@ -297,10 +401,10 @@ func (m *MockStore) GetInboxMessagesByCategory(
 			continue
 		}
 		msg := m.messages[msgID]
-		sender := m.agents[msg.SenderID]
-		if !categoryMatches(sender.Name, msg.Subject, category) {
+		if messageCategory(msg.Subject, recip.Category) != category {
 			continue
 		}
+		sender := m.agents[msg.SenderID]
 		result = append(result, InboxMessage{
 			Message:    msg,
 			SenderName: sender.Name,
@@ -334,7 +438,8 @@ func (m *MockStore) CountInboxCategories(
 			continue
 		}
 		msg := m.messages[msgID]
-		isNotif := isNotificationSubject(msg.Subject)
+		isNotif := messageCategory(msg.Subject, recip.Category) ==
+			InboxCategoryNotifications
 		if isNotif {
 			counts.Notifications++
 		} else {
@@ -347,6 +452,7 @@ func (m *MockStore) CountInboxCategories(
 			counts.Starred++
 		}
 	}
+	counts.Labels = m.listLabels(agentID)
 	return counts, nil
 }
 
@@ -361,20 +467,20 @@ func isNotificationSubject(subject string) bool {
 	return false
 }
 
-// categoryMatches reports whether a message belongs to the given
-// inbox category. The Primary/Notifications classification matches
-// SqlcStore's SQL filters exactly.
-func categoryMatches(
-	_ string, subject string, category InboxCategory,
-) bool {
-	isNotif := isNotificationSubject(subject)
-	switch category {
-	case InboxCategoryPrimary:
-		return !isNotif
-	case InboxCategoryNotifications:
-		return isNotif
+// messageCategory returns the inbox category a message belongs to for a
+// recipient: the one an inbox rule filed it under if any, and otherwise the
+// one its subject implies. The classification matches SqlcStore's SQL
+// filters exactly.
+func messageCategory(subject string,
+	ruleCategory InboxCategory,
+) InboxCategory {
+	switch {
+	case ruleCategory != InboxCategoryAll:
+		return ruleCategory
+	case isNotificationSubject(subject):
+		return InboxCategoryNotifications
 	default:
-		return true
+		return InboxCategoryPrimary
 	}
 }
 
@@ -555,6 +661,125 @@ func (m *MockStore) GetMessageRecipient(
 	return recip, nil
 }
 
+func (m *MockStore) PersistRecipientState(
+	ctx context.Context, params PersistRecipientStateParams,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	recipients, ok := m.messageRecipients[params.MessageID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	recip, ok := recipients[params.AgentID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	recip.State = params.State
+	recip.ReadAt = params.ReadAt
+	recip.AckedAt = params.AckedAt
+	recip.SnoozedUntil = params.SnoozedUntil
+	recipients[params.AgentID] = recip
+
+	return nil
+}
+
+func (m *MockStore) ListSnoozedRecipients(
+	ctx context.Context,
+) ([]SnoozedRecipient, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []SnoozedRecipient
+	for msgID, recipients := range m.messageRecipients {
+		for _, recip := range recipients {
+			if recip.State != "snoozed" || recip.SnoozedUntil == nil {
+				continue
+			}
+			result = append(result, SnoozedRecipient{
+				MessageRecipient: recip,
+				ThreadID:         m.messages[msgID].ThreadID,
+			})
+		}
+	}
+
+	slices.SortFunc(result, func(a, b SnoozedRecipient) int {
+		return a.SnoozedUntil.Compare(*b.SnoozedUntil)
+	})
+
+	return result, nil
+}
+
+// GetOverdueMessages returns unacked inbox messages whose deadline has
+// passed, most overdue first.
+func (m *MockStore) GetOverdueMessages(
+	ctx context.Context, agentID int64, now time.Time, limit, offset int,
+) ([]InboxMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []InboxMessage
+	for msgID, recipients := range m.messageRecipients {
+		msg := m.messages[msgID]
+		if msg.DeadlineAt == nil || msg.DeadlineAt.After(now) {
+			continue
+		}
+
+		for recipID, recip := range recipients {
+			if agentID != 0 && recipID != agentID {
+				continue
+			}
+			if recip.AckedAt != nil || recip.State == "archived" ||
+				recip.State == "trash" {
+
+				continue
+			}
+
+			sender := m.agents[msg.SenderID]
+			result = append(result, InboxMessage{
+				Message:      msg,
+				SenderName:   sender.Name,
+				State:        recip.State,
+				SnoozedUntil: recip.SnoozedUntil,
+				ReadAt:       recip.ReadAt,
+				AckedAt:      recip.AckedAt,
+			})
+		}
+	}
+
+	slices.SortFunc(result, func(a, b InboxMessage) int {
+		return a.DeadlineAt.Compare(*b.DeadlineAt)
+	})
+
+	if offset >= len(result) {
+		return nil, nil
+	}
+	result = result[offset:]
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+// UpdateMessagePriority changes the priority of a message.
+func (m *MockStore) UpdateMessagePriority(
+	ctx context.Context, messageID int64, priority string,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	msg, ok := m.messages[messageID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	msg.Priority = priority
+	m.messages[messageID] = msg
+
+	return nil
+}
+
 func (m *MockStore) CountUnreadByAgent(
 	ctx context.Context, agentID int64,
 ) (int64, error) {
@@ -1145,6 +1370,22 @@ func (m *MockStore) DeleteAgent(ctx context.Context, id int64) error {
 		delete(m.agentsBySession, agent.CurrentSessionID)
 	}
 	delete(m.agents, id)
+	delete(m.capabilities, id)
+	for credID, cred := range m.credentials {
+		if cred.AgentID == id {
+			delete(m.credentials, credID)
+		}
+	}
+	for limitID, limit := range m.agentLimits {
+		if limit.AgentID == id {
+			delete(m.agentLimits, limitID)
+		}
+	}
+	for key := range m.rateLimitBuckets {
+		if key.agentID == id {
+			delete(m.rateLimitBuckets, key)
+		}
+	}
 
 	return nil
 }
@@ -1200,6 +1441,22 @@ func (m *MockStore) GetTopicByName(
 	return m.topics[id], nil
 }
 
+func (m *MockStore) UpdateTopicRetention(
+	ctx context.Context, topicID, retentionSeconds int64,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	topic, ok := m.topics[topicID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	topic.RetentionSeconds = retentionSeconds
+	m.topics[topicID] = topic
+
+	return nil
+}
+
 func (m *MockStore) GetOrCreateAgentInboxTopic(
 	ctx context.Context, agentName string,
 ) (Topic, error) {
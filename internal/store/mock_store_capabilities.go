@@ -0,0 +1,229 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// CapabilityStore mock implementation
+// =============================================================================
+
+// SetAgentCapabilities replaces an agent's capabilities.
+func (m *MockStore) SetAgentCapabilities(ctx context.Context, agentID int64,
+	caps []AgentCapability,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.agents[agentID]; !ok {
+		return fmt.Errorf("FOREIGN KEY constraint failed: agent %d: %w",
+			agentID, sql.ErrNoRows)
+	}
+
+	seen := make(map[AgentCapability]bool, len(caps))
+	var kept []AgentCapability
+	for _, c := range caps {
+		if !seen[c] {
+			seen[c] = true
+			kept = append(kept, c)
+		}
+	}
+	sortCapabilities(kept)
+
+	if len(kept) == 0 {
+		delete(m.capabilities, agentID)
+		return nil
+	}
+	m.capabilities[agentID] = kept
+
+	return nil
+}
+
+// ListAgentCapabilities lists an agent's capabilities by kind and name.
+func (m *MockStore) ListAgentCapabilities(ctx context.Context,
+	agentID int64,
+) ([]AgentCapability, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return append([]AgentCapability(nil), m.capabilities[agentID]...), nil
+}
+
+// ListAllAgentCapabilities lists the capabilities of every agent.
+func (m *MockStore) ListAllAgentCapabilities(
+	ctx context.Context,
+) (map[int64][]AgentCapability, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	caps := make(map[int64][]AgentCapability, len(m.capabilities))
+	for agentID, agentCaps := range m.capabilities {
+		caps[agentID] = append([]AgentCapability(nil), agentCaps...)
+	}
+
+	return caps, nil
+}
+
+// UpdateAgentMaxConcurrency sets the most tasks an agent takes on at once.
+func (m *MockStore) UpdateAgentMaxConcurrency(ctx context.Context,
+	agentID, maxConcurrency int64,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	agent, ok := m.agents[agentID]
+	if !ok {
+		return nil
+	}
+	agent.MaxConcurrency = maxConcurrency
+	m.agents[agentID] = agent
+
+	return nil
+}
+
+// ListCapabilityCandidates lists the agents with a capability.
+func (m *MockStore) ListCapabilityCandidates(ctx context.Context,
+	kind, name string,
+) ([]CapabilityCandidate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var candidates []CapabilityCandidate
+	for agentID, caps := range m.capabilities {
+		if !hasCapability(caps, kind, name) {
+			continue
+		}
+
+		agent := m.agents[agentID]
+		candidate := CapabilityCandidate{Agent: agent}
+		for _, recipients := range m.messageRecipients {
+			r, ok := recipients[agentID]
+			if ok && r.State == "unread" {
+				candidate.UnreadCount++
+			}
+		}
+		for _, task := range m.tasks {
+			if task.Status != "in_progress" {
+				continue
+			}
+			if task.Owner == agent.Name ||
+				(task.Owner == "" && task.AgentID == agentID) {
+
+				candidate.InProgressCount++
+			}
+		}
+
+		candidates = append(candidates, candidate)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].ID < candidates[j].ID
+	})
+
+	return candidates, nil
+}
+
+// CreateCapabilityRoute records the agent picked for a capability.
+func (m *MockStore) CreateCapabilityRoute(ctx context.Context,
+	params CreateCapabilityRouteParams,
+) (CapabilityRoute, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	agent, ok := m.agents[params.AgentID]
+	if !ok {
+		return CapabilityRoute{}, fmt.Errorf("FOREIGN KEY constraint "+
+			"failed: agent %d: %w", params.AgentID, sql.ErrNoRows)
+	}
+
+	route := CapabilityRoute{
+		ID:              m.nextRouteID,
+		Capability:      params.Capability,
+		AgentID:         params.AgentID,
+		AgentName:       agent.Name,
+		MessageID:       params.MessageID,
+		TaskID:          params.TaskID,
+		Candidates:      params.Candidates,
+		UnreadCount:     params.UnreadCount,
+		InProgressCount: params.InProgressCount,
+		CreatedAt:       time.Unix(time.Now().Unix(), 0),
+	}
+	m.capabilityRoutes[route.ID] = route
+	m.nextRouteID++
+
+	return route, nil
+}
+
+// ListCapabilityRoutes lists the newest capability routes.
+func (m *MockStore) ListCapabilityRoutes(ctx context.Context,
+	capability string, limit int,
+) ([]CapabilityRoute, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var routes []CapabilityRoute
+	for _, route := range m.capabilityRoutes {
+		if capability == "" || route.Capability == capability {
+			routes = append(routes, route)
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].ID > routes[j].ID
+	})
+	if len(routes) > limit {
+		routes = routes[:limit]
+	}
+
+	return routes, nil
+}
+
+// GetLatestTaskRoute retrieves the latest route taken for a task.
+func (m *MockStore) GetLatestTaskRoute(ctx context.Context,
+	taskID int64,
+) (CapabilityRoute, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var (
+		latest CapabilityRoute
+		found  bool
+	)
+	for _, route := range m.capabilityRoutes {
+		if route.TaskID == nil || *route.TaskID != taskID {
+			continue
+		}
+		if !found || route.ID > latest.ID {
+			latest, found = route, true
+		}
+	}
+	if !found {
+		return CapabilityRoute{}, sql.ErrNoRows
+	}
+
+	return latest, nil
+}
+
+// hasCapability reports whether caps holds the named capability, of the
+// given kind unless kind is empty.
+func hasCapability(caps []AgentCapability, kind, name string) bool {
+	for _, c := range caps {
+		if c.Name == name && (kind == "" || c.Kind == kind) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortCapabilities sorts capabilities by kind and then name.
+func sortCapabilities(caps []AgentCapability) {
+	sort.Slice(caps, func(i, j int) bool {
+		if caps[i].Kind != caps[j].Kind {
+			return caps[i].Kind < caps[j].Kind
+		}
+		return caps[i].Name < caps[j].Name
+	})
+}
@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// CredentialStore mock implementation
+// =============================================================================
+
+// CreateCredential stores a new credential.
+func (m *MockStore) CreateCredential(ctx context.Context,
+	params CreateCredentialParams,
+) (Credential, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var agentName string
+	if params.AgentID != 0 {
+		agent, ok := m.agents[params.AgentID]
+		if !ok {
+			return Credential{}, fmt.Errorf("FOREIGN KEY "+
+				"constraint failed: agent %d: %w",
+				params.AgentID, sql.ErrNoRows)
+		}
+		agentName = agent.Name
+	}
+
+	for _, cred := range m.credentials {
+		if cred.TokenHash == params.TokenHash {
+			return Credential{}, fmt.Errorf("UNIQUE constraint " +
+				"failed: credentials.token_hash")
+		}
+	}
+
+	cred := Credential{
+		ID:             m.nextCredentialID,
+		AgentID:        params.AgentID,
+		AgentName:      agentName,
+		Name:           params.Name,
+		TokenHash:      params.TokenHash,
+		AllowedMethods: params.AllowedMethods,
+		ExpiresAt:      params.ExpiresAt,
+		CreatedAt:      time.Unix(time.Now().Unix(), 0),
+	}
+	m.credentials[cred.ID] = cred
+	m.nextCredentialID++
+
+	return cred, nil
+}
+
+// GetCredentialByHash retrieves a credential by the hash of its token.
+func (m *MockStore) GetCredentialByHash(ctx context.Context,
+	tokenHash string,
+) (Credential, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, cred := range m.credentials {
+		if cred.TokenHash == tokenHash {
+			return cred, nil
+		}
+	}
+
+	return Credential{}, sql.ErrNoRows
+}
+
+// ListCredentials lists credentials, newest first.
+func (m *MockStore) ListCredentials(ctx context.Context, agentID int64,
+	includeRevoked bool,
+) ([]Credential, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var creds []Credential
+	for _, cred := range m.credentials {
+		if agentID != 0 && cred.AgentID != agentID {
+			continue
+		}
+		if !includeRevoked && cred.RevokedAt != nil {
+			continue
+		}
+		creds = append(creds, cred)
+	}
+	sort.Slice(creds, func(i, j int) bool {
+		return creds[i].ID > creds[j].ID
+	})
+
+	return creds, nil
+}
+
+// RevokeCredential revokes a credential.
+func (m *MockStore) RevokeCredential(ctx context.Context, id int64,
+	revokedAt time.Time,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cred, ok := m.credentials[id]
+	if !ok || cred.RevokedAt != nil {
+		return false, nil
+	}
+
+	at := time.Unix(revokedAt.Unix(), 0)
+	cred.RevokedAt = &at
+	m.credentials[id] = cred
+
+	return true, nil
+}
+
+// TouchCredential records when a credential was last used.
+func (m *MockStore) TouchCredential(ctx context.Context, id int64,
+	usedAt time.Time,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cred, ok := m.credentials[id]
+	if !ok {
+		return nil
+	}
+
+	at := time.Unix(usedAt.Unix(), 0)
+	cred.LastUsedAt = &at
+	m.credentials[id] = cred
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// DeadLetterStore mock implementation
+// =============================================================================
+
+// CreateDeadLetter records an undeliverable addressee of a message.
+func (m *MockStore) CreateDeadLetter(ctx context.Context,
+	params CreateDeadLetterParams,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.messages[params.MessageID]; !ok {
+		return fmt.Errorf("FOREIGN KEY constraint failed: message %d: %w",
+			params.MessageID, sql.ErrNoRows)
+	}
+
+	m.deadLetters[m.nextDeadLetterID] = DeadLetter{
+		ID:        m.nextDeadLetterID,
+		MessageID: params.MessageID,
+		SenderID:  params.SenderID,
+		Recipient: params.Recipient,
+		Status:    params.Status,
+		Reason:    params.Reason,
+		CreatedAt: time.Now(),
+	}
+	m.nextDeadLetterID++
+
+	return nil
+}
+
+// ListDeadLetters lists a sender's dead letters, newest first.
+func (m *MockStore) ListDeadLetters(ctx context.Context, senderID int64,
+	limit int,
+) ([]DeadLetter, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var letters []DeadLetter
+	for _, letter := range m.deadLetters {
+		if letter.SenderID != senderID {
+			continue
+		}
+
+		msg := m.messages[letter.MessageID]
+		letter.ThreadID = msg.ThreadID
+		letter.Subject = msg.Subject
+		letters = append(letters, letter)
+	}
+
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i].ID > letters[j].ID
+	})
+	if limit > 0 && len(letters) > limit {
+		letters = letters[:limit]
+	}
+
+	return letters, nil
+}
+
+// DeleteDeadLetter deletes one of a sender's dead letters.
+func (m *MockStore) DeleteDeadLetter(ctx context.Context, id,
+	senderID int64,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	letter, ok := m.deadLetters[id]
+	if !ok || letter.SenderID != senderID {
+		return false, nil
+	}
+	delete(m.deadLetters, id)
+
+	return true, nil
+}
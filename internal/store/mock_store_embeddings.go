@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// EmbeddingStore mock implementation
+// =============================================================================
+
+// UpsertMessageEmbedding stores a message's embedding under a model.
+func (m *MockStore) UpsertMessageEmbedding(ctx context.Context,
+	params UpsertMessageEmbeddingParams,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.messages[params.MessageID]; !ok {
+		return sql.ErrNoRows
+	}
+
+	if m.embeddings[params.MessageID] == nil {
+		m.embeddings[params.MessageID] = make(
+			map[string]MessageEmbedding,
+		)
+	}
+	m.embeddings[params.MessageID][params.Model] = MessageEmbedding{
+		MessageID: params.MessageID,
+		Model:     params.Model,
+		Vector:    params.Vector,
+		CreatedAt: time.Unix(time.Now().Unix(), 0),
+	}
+
+	return nil
+}
+
+// GetMessageEmbedding retrieves a message's embedding under a model.
+func (m *MockStore) GetMessageEmbedding(ctx context.Context, messageID int64,
+	model string,
+) (MessageEmbedding, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	e, ok := m.embeddings[messageID][model]
+	if !ok {
+		return MessageEmbedding{}, sql.ErrNoRows
+	}
+
+	return e, nil
+}
+
+// ListMessagesToEmbed lists the oldest messages without an embedding under a
+// model.
+func (m *MockStore) ListMessagesToEmbed(ctx context.Context, model string,
+	limit int,
+) ([]Message, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var messages []Message
+	for id, msg := range m.messages {
+		if _, ok := m.embeddings[id][model]; !ok {
+			messages = append(messages, msg)
+		}
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].ID < messages[j].ID
+	})
+	if len(messages) > limit {
+		messages = messages[:limit]
+	}
+
+	return messages, nil
+}
+
+// ListMessageEmbeddings lists the embeddings under a model of the messages a
+// search's filters keep.
+func (m *MockStore) ListMessageEmbeddings(ctx context.Context, model string,
+	params MessageSearchParams,
+) ([]MessageEmbedding, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	params.Match = ""
+
+	var embeddings []MessageEmbedding
+	for _, msg := range m.mockSearch(params) {
+		if e, ok := m.embeddings[msg.ID][model]; ok {
+			embeddings = append(embeddings, e)
+		}
+	}
+
+	return embeddings, nil
+}
@@ -0,0 +1,116 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+)
+
+// escalationKey identifies a recipient's escalation progress.
+type escalationKey struct {
+	messageID int64
+	agentID   int64
+}
+
+// =============================================================================
+// Escalation mock implementation
+// =============================================================================
+
+// ListOverdueRecipients lists recipients of unacked messages whose next
+// escalation level is due.
+func (m *MockStore) ListOverdueRecipients(ctx context.Context, now time.Time,
+	levelDelays []time.Duration, limit int,
+) ([]OverdueRecipient, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []OverdueRecipient
+	for msgID, recipients := range m.messageRecipients {
+		msg := m.messages[msgID]
+		if msg.DeadlineAt == nil || msg.DeadlineAt.After(now) {
+			continue
+		}
+
+		for agentID, recip := range recipients {
+			if recip.AckedAt != nil || recip.State == "archived" ||
+				recip.State == "trash" {
+
+				continue
+			}
+
+			key := escalationKey{messageID: msgID, agentID: agentID}
+			level := m.escalations[key].Level
+			if level >= len(levelDelays) {
+				continue
+			}
+
+			// Whole seconds, as the database stores deadlines.
+			due := msg.DeadlineAt.Add(
+				levelDelays[level].Truncate(time.Second),
+			)
+			if due.Unix() > now.Unix() {
+				continue
+			}
+
+			result = append(result, OverdueRecipient{
+				MessageID:       msgID,
+				AgentID:         agentID,
+				State:           recip.State,
+				ThreadID:        msg.ThreadID,
+				SenderID:        msg.SenderID,
+				Subject:         msg.Subject,
+				Priority:        msg.Priority,
+				DeadlineAt:      *msg.DeadlineAt,
+				EscalationLevel: level,
+			})
+		}
+	}
+
+	slices.SortFunc(result, func(a, b OverdueRecipient) int {
+		return a.DeadlineAt.Compare(b.DeadlineAt)
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+// GetMessageEscalation retrieves the escalation progress for a recipient.
+func (m *MockStore) GetMessageEscalation(ctx context.Context,
+	messageID, agentID int64,
+) (MessageEscalation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key := escalationKey{messageID: messageID, agentID: agentID}
+	esc, ok := m.escalations[key]
+	if !ok {
+		return MessageEscalation{}, sql.ErrNoRows
+	}
+
+	return esc, nil
+}
+
+// UpsertMessageEscalation records the escalation level reached for a
+// recipient.
+func (m *MockStore) UpsertMessageEscalation(ctx context.Context,
+	params UpsertMessageEscalationParams,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := escalationKey{
+		messageID: params.MessageID,
+		agentID:   params.AgentID,
+	}
+	m.escalations[key] = MessageEscalation{
+		MessageID:       params.MessageID,
+		AgentID:         params.AgentID,
+		Level:           params.Level,
+		LastEscalatedAt: time.Now(),
+	}
+
+	return nil
+}
@@ -0,0 +1,257 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// RecipientGroupStore mock implementation
+// =============================================================================
+
+// CreateRecipientGroup creates a new, empty recipient group.
+func (m *MockStore) CreateRecipientGroup(ctx context.Context,
+	params CreateRecipientGroupParams,
+) (RecipientGroup, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, g := range m.recipientGroups {
+		if g.Name == params.Name {
+			return RecipientGroup{}, fmt.Errorf("UNIQUE constraint "+
+				"failed: recipient_groups.name: %s", params.Name)
+		}
+	}
+
+	group := RecipientGroup{
+		ID:          m.nextGroupID,
+		Name:        params.Name,
+		Description: params.Description,
+		CreatedBy:   params.CreatedBy,
+		CreatedAt:   time.Now(),
+	}
+	m.nextGroupID++
+	m.recipientGroups[group.ID] = group
+
+	return group, nil
+}
+
+// GetRecipientGroupByName retrieves a recipient group by its name.
+func (m *MockStore) GetRecipientGroupByName(ctx context.Context,
+	name string,
+) (RecipientGroup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, g := range m.recipientGroups {
+		if g.Name == name {
+			return g, nil
+		}
+	}
+
+	return RecipientGroup{}, sql.ErrNoRows
+}
+
+// ListRecipientGroups lists all recipient groups with their member counts.
+func (m *MockStore) ListRecipientGroups(
+	ctx context.Context,
+) ([]RecipientGroup, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	groups := make([]RecipientGroup, 0, len(m.recipientGroups))
+	for _, g := range m.recipientGroups {
+		g.MemberCount = int64(len(m.groupMembers[g.ID]))
+		groups = append(groups, g)
+	}
+	slices.SortFunc(groups, func(a, b RecipientGroup) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return groups, nil
+}
+
+// DeleteRecipientGroup deletes a recipient group and its memberships.
+func (m *MockStore) DeleteRecipientGroup(ctx context.Context,
+	id int64,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.recipientGroups[id]; !ok {
+		return false, nil
+	}
+	delete(m.recipientGroups, id)
+	delete(m.groupMembers, id)
+
+	return true, nil
+}
+
+// AddRecipientGroupMember adds an agent to a group.
+func (m *MockStore) AddRecipientGroupMember(ctx context.Context, groupID,
+	agentID int64,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.recipientGroups[groupID]; !ok {
+		return fmt.Errorf("FOREIGN KEY constraint failed")
+	}
+	if _, ok := m.agents[agentID]; !ok {
+		return fmt.Errorf("FOREIGN KEY constraint failed")
+	}
+
+	if m.groupMembers[groupID] == nil {
+		m.groupMembers[groupID] = make(map[int64]bool)
+	}
+	m.groupMembers[groupID][agentID] = true
+
+	return nil
+}
+
+// RemoveRecipientGroupMember removes an agent from a group.
+func (m *MockStore) RemoveRecipientGroupMember(ctx context.Context, groupID,
+	agentID int64,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.groupMembers[groupID][agentID] {
+		return false, nil
+	}
+	delete(m.groupMembers[groupID], agentID)
+
+	return true, nil
+}
+
+// ListRecipientGroupMembers lists the agents in a group by name.
+func (m *MockStore) ListRecipientGroupMembers(ctx context.Context,
+	groupID int64,
+) ([]Agent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.filterAgents(func(a Agent) bool {
+		return m.groupMembers[groupID][a.ID]
+	}), nil
+}
+
+// ListAgentsMatchingProject lists agents whose project key equals the given
+// key or is a path ending in it.
+func (m *MockStore) ListAgentsMatchingProject(ctx context.Context,
+	project string,
+) ([]Agent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.filterAgents(func(a Agent) bool {
+		return a.ProjectKey == project ||
+			strings.HasSuffix(a.ProjectKey, "/"+project)
+	}), nil
+}
+
+// ListAgentsByBranch lists agents working on the given git branch.
+func (m *MockStore) ListAgentsByBranch(ctx context.Context,
+	branch string,
+) ([]Agent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.filterAgents(func(a Agent) bool {
+		return a.GitBranch == branch
+	}), nil
+}
+
+// ListAgentsActiveSince lists agents whose last heartbeat is at or after the
+// given time.
+func (m *MockStore) ListAgentsActiveSince(ctx context.Context,
+	since time.Time,
+) ([]Agent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.filterAgents(func(a Agent) bool {
+		return a.LastActiveAt.Unix() >= since.Unix()
+	}), nil
+}
+
+// ListAgentsByNamePrefix lists agents whose name starts with the given
+// prefix.
+func (m *MockStore) ListAgentsByNamePrefix(ctx context.Context,
+	prefix string,
+) ([]Agent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.filterAgents(func(a Agent) bool {
+		return strings.HasPrefix(a.Name, prefix)
+	}), nil
+}
+
+// CreateRecipientExpansion records that a group or alias in a message's
+// recipient list resolved to the given agent.
+func (m *MockStore) CreateRecipientExpansion(ctx context.Context,
+	messageID int64, address string, agentID int64,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	agent, ok := m.agents[agentID]
+	if !ok {
+		return fmt.Errorf("FOREIGN KEY constraint failed")
+	}
+
+	for _, e := range m.recipientExpansions[messageID] {
+		if e.Address == address && e.AgentID == agentID {
+			return nil
+		}
+	}
+	m.recipientExpansions[messageID] = append(
+		m.recipientExpansions[messageID], RecipientExpansion{
+			Address:   address,
+			AgentID:   agentID,
+			AgentName: agent.Name,
+		},
+	)
+
+	return nil
+}
+
+// ListRecipientExpansions lists the recorded expansions of a message's
+// recipient groups and aliases.
+func (m *MockStore) ListRecipientExpansions(ctx context.Context,
+	messageID int64,
+) ([]RecipientExpansion, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	expansions := slices.Clone(m.recipientExpansions[messageID])
+	slices.SortFunc(expansions, func(a, b RecipientExpansion) int {
+		if c := strings.Compare(a.Address, b.Address); c != 0 {
+			return c
+		}
+		return strings.Compare(a.AgentName, b.AgentName)
+	})
+
+	return expansions, nil
+}
+
+// filterAgents returns the agents matching the predicate sorted by name.
+// Callers must hold the lock.
+func (m *MockStore) filterAgents(match func(Agent) bool) []Agent {
+	var agents []Agent
+	for _, a := range m.agents {
+		if match(a) {
+			agents = append(agents, a)
+		}
+	}
+	slices.SortFunc(agents, func(a, b Agent) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return agents
+}
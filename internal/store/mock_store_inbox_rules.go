@@ -0,0 +1,209 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// InboxRuleStore mock implementation
+// =============================================================================
+
+// CreateInboxRule creates an inbox rule.
+func (m *MockStore) CreateInboxRule(ctx context.Context,
+	params CreateInboxRuleParams,
+) (InboxRule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.agents[params.AgentID]; !ok {
+		return InboxRule{}, fmt.Errorf("FOREIGN KEY constraint "+
+			"failed: agent %d: %w", params.AgentID, sql.ErrNoRows)
+	}
+	if _, ok := m.inboxRuleIDByName(params.AgentID, params.Name); ok {
+		return InboxRule{}, errors.New("UNIQUE constraint failed: " +
+			"inbox_rules.agent_id, inbox_rules.name")
+	}
+
+	now := time.Unix(time.Now().Unix(), 0)
+	rule := InboxRule{
+		ID:                  m.nextInboxRuleID,
+		AgentID:             params.AgentID,
+		Name:                params.Name,
+		Enabled:             true,
+		StopProcessing:      params.StopProcessing,
+		InboxRuleConditions: params.InboxRuleConditions,
+		InboxRuleActions:    params.InboxRuleActions,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	m.inboxRules[rule.ID] = rule
+	m.nextInboxRuleID++
+
+	return rule, nil
+}
+
+// GetInboxRuleByName retrieves one of an agent's inbox rules by name.
+func (m *MockStore) GetInboxRuleByName(ctx context.Context, agentID int64,
+	name string,
+) (InboxRule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	id, ok := m.inboxRuleIDByName(agentID, name)
+	if !ok {
+		return InboxRule{}, sql.ErrNoRows
+	}
+
+	return m.inboxRules[id], nil
+}
+
+// ListInboxRules lists an agent's inbox rules in the order they are applied.
+func (m *MockStore) ListInboxRules(ctx context.Context,
+	agentID int64,
+) ([]InboxRule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var rules []InboxRule
+	for _, rule := range m.inboxRules {
+		if rule.AgentID == agentID {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+
+	return rules, nil
+}
+
+// DeleteInboxRule deletes one of an agent's inbox rules.
+func (m *MockStore) DeleteInboxRule(ctx context.Context, agentID int64,
+	name string,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id, ok := m.inboxRuleIDByName(agentID, name)
+	if !ok {
+		return false, nil
+	}
+	delete(m.inboxRules, id)
+
+	return true, nil
+}
+
+// SetInboxRuleEnabled enables or disables one of an agent's inbox rules.
+func (m *MockStore) SetInboxRuleEnabled(ctx context.Context, agentID int64,
+	name string, enabled bool,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id, ok := m.inboxRuleIDByName(agentID, name)
+	if !ok {
+		return false, nil
+	}
+
+	rule := m.inboxRules[id]
+	rule.Enabled = enabled
+	rule.UpdatedAt = time.Unix(time.Now().Unix(), 0)
+	m.inboxRules[id] = rule
+
+	return true, nil
+}
+
+// RecordInboxRuleMatch counts a message an inbox rule matched.
+func (m *MockStore) RecordInboxRuleMatch(ctx context.Context, id int64,
+	matchedAt time.Time,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rule, ok := m.inboxRules[id]
+	if !ok {
+		return nil
+	}
+
+	matched := time.Unix(matchedAt.Unix(), 0)
+	rule.MatchCount++
+	rule.LastMatchedAt = &matched
+	m.inboxRules[id] = rule
+
+	return nil
+}
+
+// SetRecipientCategory files a message under an inbox category for one of
+// its recipients.
+func (m *MockStore) SetRecipientCategory(ctx context.Context, messageID,
+	agentID int64, category InboxCategory,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	recip, ok := m.messageRecipients[messageID][agentID]
+	if !ok {
+		return nil
+	}
+	recip.Category = category
+	m.messageRecipients[messageID][agentID] = recip
+
+	return nil
+}
+
+// ListReceivedMessages lists an agent's most recently received messages in
+// any state but trash, newest first.
+func (m *MockStore) ListReceivedMessages(ctx context.Context, agentID int64,
+	limit int,
+) ([]InboxMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []InboxMessage
+	for msgID, recipients := range m.messageRecipients {
+		recip, ok := recipients[agentID]
+		if !ok || recip.State == "trash" {
+			continue
+		}
+
+		msg := m.messages[msgID]
+		result = append(result, InboxMessage{
+			Message:      msg,
+			SenderName:   m.agents[msg.SenderID].Name,
+			State:        recip.State,
+			SnoozedUntil: recip.SnoozedUntil,
+			ReadAt:       recip.ReadAt,
+			AckedAt:      recip.AckedAt,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID > result[j].ID
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+// inboxRuleIDByName finds one of an agent's inbox rules by name. The caller
+// must hold the lock.
+func (m *MockStore) inboxRuleIDByName(agentID int64,
+	name string,
+) (int64, bool) {
+	for id, rule := range m.inboxRules {
+		if rule.AgentID == agentID && rule.Name == name {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
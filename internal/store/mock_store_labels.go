@@ -0,0 +1,260 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// messageLabelKey identifies a recipient's copy of a message.
+type messageLabelKey struct {
+	messageID int64
+	agentID   int64
+}
+
+// =============================================================================
+// LabelStore mock implementation
+// =============================================================================
+
+// CreateLabel creates a label for an agent.
+func (m *MockStore) CreateLabel(ctx context.Context, agentID int64,
+	name string,
+) (Label, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.agents[agentID]; !ok {
+		return Label{}, fmt.Errorf("FOREIGN KEY constraint failed: "+
+			"agent %d: %w", agentID, sql.ErrNoRows)
+	}
+	if _, ok := m.labelIDByName(agentID, name); ok {
+		return Label{}, errors.New("UNIQUE constraint failed: " +
+			"labels.agent_id, labels.name")
+	}
+
+	label := Label{
+		ID:        m.nextLabelID,
+		AgentID:   agentID,
+		Name:      name,
+		CreatedAt: time.Unix(time.Now().Unix(), 0),
+	}
+	m.labels[label.ID] = label
+	m.nextLabelID++
+
+	return label, nil
+}
+
+// GetLabelByName retrieves one of an agent's labels by name.
+func (m *MockStore) GetLabelByName(ctx context.Context, agentID int64,
+	name string,
+) (Label, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	id, ok := m.labelIDByName(agentID, name)
+	if !ok {
+		return Label{}, sql.ErrNoRows
+	}
+
+	return m.labels[id], nil
+}
+
+// ListLabels lists an agent's labels by name, with their message counts.
+func (m *MockStore) ListLabels(ctx context.Context,
+	agentID int64,
+) ([]Label, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.listLabels(agentID), nil
+}
+
+// DeleteLabel deletes one of an agent's labels.
+func (m *MockStore) DeleteLabel(ctx context.Context, agentID int64,
+	name string,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id, ok := m.labelIDByName(agentID, name)
+	if !ok {
+		return false, nil
+	}
+	delete(m.labels, id)
+	for _, labelIDs := range m.messageLabels {
+		delete(labelIDs, id)
+	}
+
+	return true, nil
+}
+
+// AddMessageLabel applies a label to a recipient's copy of a message.
+func (m *MockStore) AddMessageLabel(ctx context.Context, messageID, agentID,
+	labelID int64,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.messageRecipients[messageID][agentID]; !ok {
+		return fmt.Errorf("FOREIGN KEY constraint failed: message %d "+
+			"recipient %d: %w", messageID, agentID, sql.ErrNoRows)
+	}
+	if _, ok := m.labels[labelID]; !ok {
+		return fmt.Errorf("FOREIGN KEY constraint failed: label %d: %w",
+			labelID, sql.ErrNoRows)
+	}
+
+	key := messageLabelKey{messageID: messageID, agentID: agentID}
+	if m.messageLabels[key] == nil {
+		m.messageLabels[key] = make(map[int64]bool)
+	}
+	m.messageLabels[key][labelID] = true
+
+	return nil
+}
+
+// RemoveMessageLabel removes a label from a recipient's copy of a message.
+func (m *MockStore) RemoveMessageLabel(ctx context.Context, messageID,
+	agentID, labelID int64,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := messageLabelKey{messageID: messageID, agentID: agentID}
+	if !m.messageLabels[key][labelID] {
+		return false, nil
+	}
+	delete(m.messageLabels[key], labelID)
+
+	return true, nil
+}
+
+// ListMessageLabels returns the names of the labels an agent applied to each
+// of the given messages.
+func (m *MockStore) ListMessageLabels(ctx context.Context, agentID int64,
+	messageIDs []int64,
+) (map[int64][]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	labels := make(map[int64][]string)
+	for _, msgID := range messageIDs {
+		names := m.messageLabelNames(msgID, agentID)
+		if len(names) > 0 {
+			labels[msgID] = names
+		}
+	}
+
+	return labels, nil
+}
+
+// GetInboxMessagesByLabel retrieves the messages an agent filed under a
+// label.
+func (m *MockStore) GetInboxMessagesByLabel(ctx context.Context,
+	agentID int64, label string, limit, offset int,
+) ([]InboxMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	labelID, ok := m.labelIDByName(agentID, label)
+	if !ok {
+		return nil, nil
+	}
+
+	var result []InboxMessage
+	for key, labelIDs := range m.messageLabels {
+		if key.agentID != agentID || !labelIDs[labelID] {
+			continue
+		}
+		recip, ok := m.messageRecipients[key.messageID][agentID]
+		if !ok || recip.State == "trash" {
+			continue
+		}
+
+		msg := m.messages[key.messageID]
+		result = append(result, InboxMessage{
+			Message:      msg,
+			SenderName:   m.agents[msg.SenderID].Name,
+			State:        recip.State,
+			SnoozedUntil: recip.SnoozedUntil,
+			ReadAt:       recip.ReadAt,
+			AckedAt:      recip.AckedAt,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID > result[j].ID
+	})
+
+	if offset >= len(result) {
+		return nil, nil
+	}
+	result = result[offset:]
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+// listLabels lists an agent's labels by name with their message counts. The
+// caller must hold the lock.
+func (m *MockStore) listLabels(agentID int64) []Label {
+	var labels []Label
+	for _, label := range m.labels {
+		if label.AgentID != agentID {
+			continue
+		}
+
+		for key, labelIDs := range m.messageLabels {
+			if key.agentID != agentID || !labelIDs[label.ID] {
+				continue
+			}
+
+			recip := m.messageRecipients[key.messageID][agentID]
+			if recip.State != "archived" && recip.State != "trash" {
+				label.MessageCount++
+			}
+			if recip.State == "unread" {
+				label.UnreadCount++
+			}
+		}
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].Name < labels[j].Name
+	})
+
+	return labels
+}
+
+// messageLabelNames returns the sorted names of the labels on a recipient's
+// copy of a message. The caller must hold the lock.
+func (m *MockStore) messageLabelNames(messageID, agentID int64) []string {
+	key := messageLabelKey{messageID: messageID, agentID: agentID}
+
+	var names []string
+	for labelID := range m.messageLabels[key] {
+		names = append(names, m.labels[labelID].Name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// labelIDByName finds one of an agent's labels by name. The caller must hold
+// the lock.
+func (m *MockStore) labelIDByName(agentID int64, name string) (int64, bool) {
+	for id, label := range m.labels {
+		if label.AgentID == agentID && label.Name == name {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
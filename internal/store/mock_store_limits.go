@@ -0,0 +1,208 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+)
+
+// bucketKey identifies an agent's token bucket for a class.
+type bucketKey struct {
+	agentID int64
+	class   string
+}
+
+// =============================================================================
+// LimitStore mock implementation
+// =============================================================================
+
+// GetAgentLimit retrieves the limit that applies to an agent for a class.
+func (m *MockStore) GetAgentLimit(ctx context.Context, agentID int64,
+	class string,
+) (AgentLimit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var (
+		fallback AgentLimit
+		found    bool
+	)
+	for _, limit := range m.agentLimits {
+		if limit.Class != class {
+			continue
+		}
+
+		switch {
+		case agentID != 0 && limit.AgentID == agentID:
+			return limit, nil
+
+		case limit.AgentID == 0:
+			fallback, found = limit, true
+		}
+	}
+	if !found {
+		return AgentLimit{}, sql.ErrNoRows
+	}
+
+	return fallback, nil
+}
+
+// SetAgentLimit sets an agent's limit for a class, or the default.
+func (m *MockStore) SetAgentLimit(ctx context.Context,
+	params SetAgentLimitParams,
+) (AgentLimit, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var agentName string
+	if params.AgentID != 0 {
+		agent, ok := m.agents[params.AgentID]
+		if !ok {
+			return AgentLimit{}, sql.ErrNoRows
+		}
+		agentName = agent.Name
+	}
+
+	for id, limit := range m.agentLimits {
+		if limit.AgentID == params.AgentID &&
+			limit.Class == params.Class {
+
+			delete(m.agentLimits, id)
+		}
+	}
+
+	limit := AgentLimit{
+		ID:        m.nextAgentLimitID,
+		AgentID:   params.AgentID,
+		AgentName: agentName,
+		Class:     params.Class,
+		Amount:    params.Amount,
+		Interval:  params.Interval,
+		Burst:     params.Burst,
+		UpdatedAt: time.Unix(time.Now().Unix(), 0),
+	}
+	m.agentLimits[limit.ID] = limit
+	m.nextAgentLimitID++
+
+	return limit, nil
+}
+
+// DeleteAgentLimit deletes an agent's limit for a class, or the default.
+func (m *MockStore) DeleteAgentLimit(ctx context.Context, agentID int64,
+	class string,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, limit := range m.agentLimits {
+		if limit.AgentID == agentID && limit.Class == class {
+			delete(m.agentLimits, id)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ListAgentLimits lists the default limits followed by the agents' own.
+func (m *MockStore) ListAgentLimits(ctx context.Context,
+	agentID int64,
+) ([]AgentLimit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var limits []AgentLimit
+	for _, limit := range m.agentLimits {
+		if agentID != 0 && limit.AgentID != 0 &&
+			limit.AgentID != agentID {
+
+			continue
+		}
+		limits = append(limits, limit)
+	}
+
+	sort.Slice(limits, func(i, j int) bool {
+		a, b := limits[i], limits[j]
+		if (a.AgentID == 0) != (b.AgentID == 0) {
+			return a.AgentID == 0
+		}
+		if a.AgentName != b.AgentName {
+			return a.AgentName < b.AgentName
+		}
+		return a.Class < b.Class
+	})
+
+	return limits, nil
+}
+
+// GetRateLimitBucket retrieves an agent's token bucket for a class.
+func (m *MockStore) GetRateLimitBucket(ctx context.Context, agentID int64,
+	class string,
+) (RateLimitBucket, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bucket, ok := m.rateLimitBuckets[bucketKey{agentID, class}]
+	if !ok {
+		return RateLimitBucket{}, sql.ErrNoRows
+	}
+
+	return bucket, nil
+}
+
+// UpsertRateLimitBucket stores an agent's token bucket for a class.
+func (m *MockStore) UpsertRateLimitBucket(ctx context.Context,
+	bucket RateLimitBucket,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.agents[bucket.AgentID]; !ok {
+		return sql.ErrNoRows
+	}
+	m.rateLimitBuckets[bucketKey{bucket.AgentID, bucket.Class}] = bucket
+
+	return nil
+}
+
+// ListRateLimitBuckets lists an agent's token buckets.
+func (m *MockStore) ListRateLimitBuckets(ctx context.Context,
+	agentID int64,
+) ([]RateLimitBucket, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var buckets []RateLimitBucket
+	for key, bucket := range m.rateLimitBuckets {
+		if key.agentID == agentID {
+			buckets = append(buckets, bucket)
+		}
+	}
+
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Class < buckets[j].Class
+	})
+
+	return buckets, nil
+}
+
+// SumSenderBodyBytes returns the size of the bodies an agent sent since a
+// time.
+func (m *MockStore) SumSenderBodyBytes(ctx context.Context, senderID int64,
+	since time.Time,
+) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var total int64
+	for _, msg := range m.messages {
+		if msg.SenderID == senderID &&
+			!msg.CreatedAt.Before(since.Truncate(time.Second)) {
+
+			total += int64(len(msg.Body))
+		}
+	}
+
+	return total, nil
+}
@@ -0,0 +1,184 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// QuestionStore mock implementation
+// =============================================================================
+
+// CreateQuestion records a question asked by a message.
+func (m *MockStore) CreateQuestion(ctx context.Context,
+	params CreateQuestionParams,
+) (Question, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.messages[params.MessageID]; !ok {
+		return Question{}, fmt.Errorf("FOREIGN KEY constraint failed: "+
+			"message %d: %w", params.MessageID, sql.ErrNoRows)
+	}
+	if _, ok := m.agents[params.AskerID]; !ok {
+		return Question{}, fmt.Errorf("FOREIGN KEY constraint failed: "+
+			"agent %d: %w", params.AskerID, sql.ErrNoRows)
+	}
+	for _, question := range m.questions {
+		if question.QuestionID == params.QuestionID {
+			return Question{}, errors.New("UNIQUE constraint " +
+				"failed: questions.question_id")
+		}
+	}
+
+	now := time.Now()
+	question := Question{
+		ID:            m.nextQuestionID,
+		QuestionID:    params.QuestionID,
+		MessageID:     params.MessageID,
+		ThreadID:      params.ThreadID,
+		AskerID:       params.AskerID,
+		Question:      params.Question,
+		Options:       slices.Clone(params.Options),
+		DefaultAnswer: params.DefaultAnswer,
+		Timeout:       params.Timeout.Truncate(time.Second),
+		Fallback:      params.Fallback,
+		State:         "pending",
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if question.Options == nil {
+		question.Options = []string{}
+	}
+	if params.Timeout > 0 {
+		timeoutAt := time.Unix(now.Add(params.Timeout).Unix(), 0)
+		question.TimeoutAt = &timeoutAt
+	}
+	m.questions[question.ID] = question
+	m.nextQuestionID++
+
+	return question, nil
+}
+
+// GetQuestion retrieves a question by its public ID.
+func (m *MockStore) GetQuestion(ctx context.Context,
+	questionID string,
+) (Question, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, question := range m.questions {
+		if question.QuestionID == questionID {
+			return question, nil
+		}
+	}
+
+	return Question{}, sql.ErrNoRows
+}
+
+// ListPendingQuestionsByThread lists the questions in a thread that are still
+// waiting for an answer.
+func (m *MockStore) ListPendingQuestionsByThread(ctx context.Context,
+	threadID string,
+) ([]Question, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var questions []Question
+	for _, question := range m.questions {
+		if question.ThreadID == threadID &&
+			question.State == "pending" {
+
+			questions = append(questions, question)
+		}
+	}
+
+	sort.Slice(questions, func(i, j int) bool {
+		return questions[i].ID < questions[j].ID
+	})
+
+	return questions, nil
+}
+
+// ListTimedOutQuestions lists pending questions whose current timeout is at
+// or before now, oldest first.
+func (m *MockStore) ListTimedOutQuestions(ctx context.Context, now time.Time,
+	limit int,
+) ([]Question, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var questions []Question
+	for _, question := range m.questions {
+		if question.State == "pending" && question.TimeoutAt != nil &&
+			question.TimeoutAt.Unix() <= now.Unix() {
+
+			questions = append(questions, question)
+		}
+	}
+
+	sort.Slice(questions, func(i, j int) bool {
+		a, b := questions[i], questions[j]
+		if !a.TimeoutAt.Equal(*b.TimeoutAt) {
+			return a.TimeoutAt.Before(*b.TimeoutAt)
+		}
+		return a.ID < b.ID
+	})
+	if limit > 0 && len(questions) > limit {
+		questions = questions[:limit]
+	}
+
+	return questions, nil
+}
+
+// ResolveQuestion records the answer to a pending question.
+func (m *MockStore) ResolveQuestion(ctx context.Context,
+	params ResolveQuestionParams,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	question, ok := m.questions[params.ID]
+	if !ok || question.State != "pending" {
+		return false, nil
+	}
+
+	now := time.Now()
+	question.State = params.State
+	question.Answer = params.Answer
+	question.AnsweredBy = params.AnsweredBy
+	question.AnswerMessageID = params.AnswerMessageID
+	question.AnsweredAt = &now
+	question.UpdatedAt = now
+	m.questions[params.ID] = question
+
+	return true, nil
+}
+
+// EscalateQuestion records that a question was escalated to its fallback
+// recipient and sets its next timeout.
+func (m *MockStore) EscalateQuestion(ctx context.Context, id int64,
+	timeoutAt time.Time,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	question, ok := m.questions[id]
+	if !ok {
+		return nil
+	}
+
+	now := time.Now()
+	nextTimeout := time.Unix(timeoutAt.Unix(), 0)
+	question.EscalatedAt = &now
+	question.TimeoutAt = &nextTimeout
+	question.UpdatedAt = now
+	m.questions[id] = question
+
+	return nil
+}
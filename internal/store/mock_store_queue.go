@@ -0,0 +1,253 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+)
+
+// =============================================================================
+// Queue delivery mock implementation
+// =============================================================================
+
+// CreateQueueDelivery registers a newly published queue message as pending.
+func (m *MockStore) CreateQueueDelivery(ctx context.Context,
+	messageID, topicID int64,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.queueDeliveries[messageID] = QueueDelivery{
+		MessageID: messageID,
+		TopicID:   topicID,
+		State:     "pending",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	return nil
+}
+
+// GetQueueDelivery retrieves the delivery state of a queue message.
+func (m *MockStore) GetQueueDelivery(ctx context.Context,
+	messageID int64,
+) (QueueDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	d, ok := m.queueDeliveries[messageID]
+	if !ok {
+		return QueueDelivery{}, sql.ErrNoRows
+	}
+
+	return d, nil
+}
+
+// claimable reports whether a delivery can be leased as of now.
+func (d QueueDelivery) claimable(now time.Time, maxDeliveries int) bool {
+	if d.DeliveryCount >= maxDeliveries {
+		return false
+	}
+
+	switch d.State {
+	case "pending":
+		return true
+
+	case "leased":
+		return d.LeaseExpiresAt != nil && !d.LeaseExpiresAt.After(now)
+
+	default:
+		return false
+	}
+}
+
+// sortedQueueDeliveries returns the deliveries matching the predicate in
+// publish order.
+func (m *MockStore) sortedQueueDeliveries(
+	match func(QueueDelivery) bool,
+) []QueueDelivery {
+	var result []QueueDelivery
+	for _, d := range m.queueDeliveries {
+		if match(d) {
+			result = append(result, d)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b QueueDelivery) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return int(a.MessageID - b.MessageID)
+	})
+
+	return result
+}
+
+// ClaimNextQueueDelivery leases the oldest claimable message on a queue
+// topic to an agent.
+func (m *MockStore) ClaimNextQueueDelivery(ctx context.Context,
+	params ClaimQueueDeliveryParams,
+) (QueueDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	candidates := m.sortedQueueDeliveries(func(d QueueDelivery) bool {
+		return d.TopicID == params.TopicID &&
+			d.claimable(now, params.MaxDeliveries)
+	})
+	if len(candidates) == 0 {
+		return QueueDelivery{}, sql.ErrNoRows
+	}
+
+	d := candidates[0]
+	expiresAt := params.LeaseExpiresAt
+	d.State = "leased"
+	d.ClaimedBy = params.AgentID
+	d.LeaseExpiresAt = &expiresAt
+	d.DeliveryCount++
+	d.UpdatedAt = now
+	m.queueDeliveries[d.MessageID] = d
+
+	return d, nil
+}
+
+// updateLease applies fn to a message's delivery if the agent holds its
+// lease, reporting whether it did.
+func (m *MockStore) updateLease(messageID, agentID int64,
+	fn func(d *QueueDelivery),
+) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d, ok := m.queueDeliveries[messageID]
+	if !ok || d.State != "leased" || d.ClaimedBy != agentID {
+		return false
+	}
+
+	fn(&d)
+	d.UpdatedAt = time.Now()
+	m.queueDeliveries[messageID] = d
+
+	return true
+}
+
+// AckQueueDelivery completes the agent's lease on a message.
+func (m *MockStore) AckQueueDelivery(ctx context.Context,
+	messageID, agentID int64,
+) (bool, error) {
+	return m.updateLease(messageID, agentID, func(d *QueueDelivery) {
+		d.State = "acked"
+		d.LeaseExpiresAt = nil
+	}), nil
+}
+
+// ReleaseQueueDelivery hands the agent's lease on a message back to the
+// queue.
+func (m *MockStore) ReleaseQueueDelivery(ctx context.Context,
+	messageID, agentID int64, reason string,
+) (bool, error) {
+	return m.updateLease(messageID, agentID, func(d *QueueDelivery) {
+		d.State = "pending"
+		d.ClaimedBy = 0
+		d.LeaseExpiresAt = nil
+		d.LastError = reason
+	}), nil
+}
+
+// ExtendQueueLease pushes out the expiry of the agent's lease on a message.
+func (m *MockStore) ExtendQueueLease(ctx context.Context,
+	messageID, agentID int64, expiresAt time.Time,
+) (bool, error) {
+	return m.updateLease(messageID, agentID, func(d *QueueDelivery) {
+		d.LeaseExpiresAt = &expiresAt
+	}), nil
+}
+
+// ListExpiredQueueLeases lists leases that expired as of now without being
+// acked.
+func (m *MockStore) ListExpiredQueueLeases(ctx context.Context,
+	now time.Time,
+) ([]QueueDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.sortedQueueDeliveries(func(d QueueDelivery) bool {
+		return d.State == "leased" && d.LeaseExpiresAt != nil &&
+			!d.LeaseExpiresAt.After(now)
+	}), nil
+}
+
+// ReleaseExpiredQueueLeases returns expired leases with delivery attempts
+// left to the pending state.
+func (m *MockStore) ReleaseExpiredQueueLeases(ctx context.Context,
+	now time.Time, maxDeliveries int,
+) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var released int64
+	for id, d := range m.queueDeliveries {
+		if d.State != "leased" || !d.claimable(now, maxDeliveries) {
+			continue
+		}
+
+		d.State = "pending"
+		d.ClaimedBy = 0
+		d.LeaseExpiresAt = nil
+		d.LastError = "lease expired"
+		d.UpdatedAt = now
+		m.queueDeliveries[id] = d
+		released++
+	}
+
+	return released, nil
+}
+
+// ListExhaustedQueueDeliveries lists unacked messages that have used up
+// their delivery attempts.
+func (m *MockStore) ListExhaustedQueueDeliveries(ctx context.Context,
+	now time.Time, maxDeliveries int,
+) ([]QueueDelivery, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := m.sortedQueueDeliveries(func(d QueueDelivery) bool {
+		if d.DeliveryCount < maxDeliveries {
+			return false
+		}
+
+		// An exhausted delivery is one that would be claimable if it
+		// had attempts left.
+		return d.claimable(now, d.DeliveryCount+1)
+	})
+	for i := range result {
+		result[i].TopicName = m.topics[result[i].TopicID].Name
+	}
+
+	return result, nil
+}
+
+// MarkQueueDeliveryDeadLettered marks a message as moved to its dead-letter
+// topic.
+func (m *MockStore) MarkQueueDeliveryDeadLettered(ctx context.Context,
+	messageID int64,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	d, ok := m.queueDeliveries[messageID]
+	if !ok {
+		return nil
+	}
+
+	d.State = "dead_lettered"
+	d.ClaimedBy = 0
+	d.LeaseExpiresAt = nil
+	d.UpdatedAt = time.Now()
+	m.queueDeliveries[messageID] = d
+
+	return nil
+}
@@ -0,0 +1,264 @@
+package store
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// Retention mock implementation
+// =============================================================================
+
+// expiredMessages returns the messages past their topic's retention window
+// in ID order. Callers must hold the lock.
+func (m *MockStore) expiredMessages(now time.Time) []Message {
+	var expired []Message
+	for _, msg := range m.messages {
+		topic, ok := m.topics[msg.TopicID]
+		if !ok || topic.RetentionSeconds <= 0 {
+			continue
+		}
+
+		if msg.CreatedAt.Unix()+topic.RetentionSeconds >= now.Unix() {
+			continue
+		}
+		expired = append(expired, msg)
+	}
+
+	slices.SortFunc(expired, func(a, b Message) int {
+		return int(a.ID - b.ID)
+	})
+
+	return expired
+}
+
+// retentionProtected reports whether a message is exempt from retention.
+// Callers must hold the lock.
+func (m *MockStore) retentionProtected(msg Message) bool {
+	if m.legalHolds[msg.ID] {
+		return true
+	}
+
+	for _, r := range m.messageRecipients[msg.ID] {
+		switch r.State {
+		case "starred", "unread", "snoozed":
+			return true
+		}
+		if msg.DeadlineAt != nil && r.AckedAt == nil {
+			return true
+		}
+	}
+
+	for _, q := range m.questions {
+		if q.ThreadID == msg.ThreadID && q.State == "pending" {
+			return true
+		}
+	}
+
+	for _, r := range getReviewData(m).reviews {
+		if r.ThreadID != msg.ThreadID {
+			continue
+		}
+		switch r.State {
+		case "approved", "rejected", "cancelled":
+		default:
+			return true
+		}
+	}
+
+	for _, pr := range getPlanReviewData(m).planReviews {
+		if pr.MessageID != nil && *pr.MessageID == msg.ID {
+			return true
+		}
+		if pr.ThreadID != msg.ThreadID {
+			continue
+		}
+		if pr.State == "pending" || pr.State == "changes_requested" {
+			return true
+		}
+	}
+
+	if d, ok := m.queueDeliveries[msg.ID]; ok {
+		if d.State == "pending" || d.State == "leased" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SummarizeExpiredMessages summarizes, per topic, the messages that have
+// outlived their topic's retention window.
+func (m *MockStore) SummarizeExpiredMessages(ctx context.Context,
+	now time.Time,
+) ([]ExpiredTopicSummary, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	byTopic := make(map[int64]*ExpiredTopicSummary)
+	for _, msg := range m.expiredMessages(now) {
+		summary, ok := byTopic[msg.TopicID]
+		if !ok {
+			topic := m.topics[msg.TopicID]
+			summary = &ExpiredTopicSummary{
+				TopicID:          topic.ID,
+				TopicName:        topic.Name,
+				RetentionSeconds: topic.RetentionSeconds,
+			}
+			byTopic[msg.TopicID] = summary
+		}
+
+		summary.ExpiredCount++
+		if m.retentionProtected(msg) {
+			continue
+		}
+		summary.DeletableCount++
+		summary.RecipientCount += int64(len(m.messageRecipients[msg.ID]))
+	}
+
+	summaries := make([]ExpiredTopicSummary, 0, len(byTopic))
+	for _, summary := range byTopic {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].TopicName < summaries[j].TopicName
+	})
+
+	return summaries, nil
+}
+
+// DeleteExpiredMessages deletes a batch of expired, unprotected messages.
+func (m *MockStore) DeleteExpiredMessages(ctx context.Context,
+	now time.Time, limit int,
+) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deleted int64
+	for _, msg := range m.expiredMessages(now) {
+		if deleted >= int64(limit) {
+			break
+		}
+		if m.retentionProtected(msg) {
+			continue
+		}
+
+		m.deleteMessageLocked(msg.ID)
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// deleteMessageLocked deletes a message along with everything that
+// references it, mirroring the foreign keys of the SQL schema. The caller
+// must hold the write lock.
+func (m *MockStore) deleteMessageLocked(messageID int64) {
+	delete(m.messages, messageID)
+	delete(m.messageRecipients, messageID)
+	delete(m.queueDeliveries, messageID)
+	delete(m.legalHolds, messageID)
+	delete(m.recipientExpansions, messageID)
+	delete(m.embeddings, messageID)
+	for id, letter := range m.deadLetters {
+		if letter.MessageID == messageID {
+			delete(m.deadLetters, id)
+		}
+	}
+	for id, sched := range m.scheduledMessages {
+		if sched.MessageID == messageID {
+			sched.MessageID = 0
+			m.scheduledMessages[id] = sched
+		}
+	}
+	for id, sched := range m.schedules {
+		if sched.LastMessageID == messageID {
+			sched.LastMessageID = 0
+			m.schedules[id] = sched
+		}
+	}
+	for id, question := range m.questions {
+		switch {
+		case question.MessageID == messageID:
+			delete(m.questions, id)
+
+		case question.AnswerMessageID == messageID:
+			question.AnswerMessageID = 0
+			m.questions[id] = question
+		}
+	}
+	for key := range m.escalations {
+		if key.messageID == messageID {
+			delete(m.escalations, key)
+		}
+	}
+	delete(m.messageRevisions, messageID)
+}
+
+// CountStaleConsumerOffsets always returns zero as the mock doesn't track
+// consumer offsets.
+func (m *MockStore) CountStaleConsumerOffsets(ctx context.Context) (int64,
+	error) {
+
+	return 0, nil
+}
+
+// DeleteStaleConsumerOffsets is a no-op as the mock doesn't track consumer
+// offsets.
+func (m *MockStore) DeleteStaleConsumerOffsets(ctx context.Context) (int64,
+	error) {
+
+	return 0, nil
+}
+
+// OptimizeSearchIndex is a no-op for the mock.
+func (m *MockStore) OptimizeSearchIndex(ctx context.Context) error {
+	return nil
+}
+
+// SetMessageLegalHold places or lifts a legal hold on a message.
+func (m *MockStore) SetMessageLegalHold(ctx context.Context, messageID int64,
+	hold bool,
+) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.messages[messageID]; !ok {
+		return 0, nil
+	}
+	m.setLegalHold(messageID, hold)
+
+	return 1, nil
+}
+
+// SetThreadLegalHold places or lifts a legal hold on every message in a
+// thread.
+func (m *MockStore) SetThreadLegalHold(ctx context.Context, threadID string,
+	hold bool,
+) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var updated int64
+	for _, msg := range m.messages {
+		if msg.ThreadID != threadID {
+			continue
+		}
+		m.setLegalHold(msg.ID, hold)
+		updated++
+	}
+
+	return updated, nil
+}
+
+// setLegalHold records the legal hold flag of a message. Callers must hold
+// the lock.
+func (m *MockStore) setLegalHold(messageID int64, hold bool) {
+	if hold {
+		m.legalHolds[messageID] = true
+		return
+	}
+	delete(m.legalHolds, messageID)
+}
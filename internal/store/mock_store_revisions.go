@@ -0,0 +1,146 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// MessageRevisionStore mock implementation
+// =============================================================================
+
+// CreateMessageRevision records a version of a message that an edit is
+// replacing.
+func (m *MockStore) CreateMessageRevision(ctx context.Context,
+	params CreateMessageRevisionParams,
+) (MessageRevision, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.messages[params.MessageID]; !ok {
+		return MessageRevision{}, fmt.Errorf("FOREIGN KEY constraint "+
+			"failed: message %d: %w", params.MessageID,
+			sql.ErrNoRows)
+	}
+	for _, rev := range m.messageRevisions[params.MessageID] {
+		if rev.Revision == params.Revision {
+			return MessageRevision{}, errors.New("UNIQUE constraint " +
+				"failed: message_revisions.message_id, " +
+				"message_revisions.revision")
+		}
+	}
+
+	rev := MessageRevision{
+		ID:         m.nextRevisionID,
+		MessageID:  params.MessageID,
+		Revision:   params.Revision,
+		Subject:    params.Subject,
+		Body:       params.Body,
+		CreatedAt:  time.Unix(params.CreatedAt.Unix(), 0),
+		ReplacedAt: time.Unix(params.ReplacedAt.Unix(), 0),
+	}
+	m.messageRevisions[params.MessageID] = append(
+		m.messageRevisions[params.MessageID], rev,
+	)
+	m.nextRevisionID++
+
+	return rev, nil
+}
+
+// ListMessageRevisions lists the replaced versions of a message, oldest
+// first.
+func (m *MockStore) ListMessageRevisions(ctx context.Context,
+	messageID int64,
+) ([]MessageRevision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	revisions := slices.Clone(m.messageRevisions[messageID])
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+
+	return revisions, nil
+}
+
+// ListThreadMessageRevisions lists the replaced versions of every message in
+// a thread.
+func (m *MockStore) ListThreadMessageRevisions(ctx context.Context,
+	threadID string,
+) ([]MessageRevision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var revisions []MessageRevision
+	for messageID, revs := range m.messageRevisions {
+		if m.messages[messageID].ThreadID == threadID {
+			revisions = append(revisions, revs...)
+		}
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		a, b := revisions[i], revisions[j]
+		if a.MessageID != b.MessageID {
+			return a.MessageID < b.MessageID
+		}
+		return a.Revision < b.Revision
+	})
+
+	return revisions, nil
+}
+
+// UpdateMessageContent replaces the subject and body of a message with an
+// edited version.
+func (m *MockStore) UpdateMessageContent(ctx context.Context, messageID int64,
+	subject, body string, editedAt time.Time,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	msg, ok := m.messages[messageID]
+	if !ok || m.legalHolds[messageID] {
+		return false, nil
+	}
+
+	edited := time.Unix(editedAt.Unix(), 0)
+	msg.Subject = subject
+	msg.Body = body
+	msg.EditedAt = &edited
+	m.messages[messageID] = msg
+	delete(m.embeddings, messageID)
+
+	return true, nil
+}
+
+// RecallMessage deletes one of a sender's messages that no recipient has read
+// yet.
+func (m *MockStore) RecallMessage(ctx context.Context,
+	messageID, senderID int64,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	msg, ok := m.messages[messageID]
+	if !ok || msg.SenderID != senderID || m.legalHolds[messageID] {
+		return false, nil
+	}
+	for _, r := range m.messageRecipients[messageID] {
+		if r.State != "unread" || r.ReadAt != nil {
+			return false, nil
+		}
+	}
+	for _, pr := range getPlanReviewData(m).planReviews {
+		if pr.MessageID != nil && *pr.MessageID == messageID {
+			return false, nil
+		}
+	}
+
+	m.deleteMessageLocked(messageID)
+
+	return true, nil
+}
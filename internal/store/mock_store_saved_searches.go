@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// SavedSearchStore mock implementation
+// =============================================================================
+
+// UpsertSavedSearch saves a search for an agent under a name.
+func (m *MockStore) UpsertSavedSearch(ctx context.Context, agentID int64,
+	name, query string,
+) (SavedSearch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.agents[agentID]; !ok {
+		return SavedSearch{}, fmt.Errorf("FOREIGN KEY constraint "+
+			"failed: agent %d: %w", agentID, sql.ErrNoRows)
+	}
+
+	now := time.Unix(time.Now().Unix(), 0)
+	if saved, ok := m.savedSearchByName(agentID, name); ok {
+		saved.Query = query
+		saved.UpdatedAt = now
+		m.savedSearches[saved.ID] = saved
+
+		return saved, nil
+	}
+
+	saved := SavedSearch{
+		ID:        m.nextSavedSearchID,
+		AgentID:   agentID,
+		Name:      name,
+		Query:     query,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	m.savedSearches[saved.ID] = saved
+	m.nextSavedSearchID++
+
+	return saved, nil
+}
+
+// GetSavedSearch retrieves one of an agent's saved searches by name.
+func (m *MockStore) GetSavedSearch(ctx context.Context, agentID int64,
+	name string,
+) (SavedSearch, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	saved, ok := m.savedSearchByName(agentID, name)
+	if !ok {
+		return SavedSearch{}, sql.ErrNoRows
+	}
+
+	return saved, nil
+}
+
+// ListSavedSearches lists an agent's saved searches by name.
+func (m *MockStore) ListSavedSearches(ctx context.Context,
+	agentID int64,
+) ([]SavedSearch, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var searches []SavedSearch
+	for _, saved := range m.savedSearches {
+		if saved.AgentID == agentID {
+			searches = append(searches, saved)
+		}
+	}
+	sort.Slice(searches, func(i, j int) bool {
+		return searches[i].Name < searches[j].Name
+	})
+
+	return searches, nil
+}
+
+// DeleteSavedSearch deletes one of an agent's saved searches.
+func (m *MockStore) DeleteSavedSearch(ctx context.Context, agentID int64,
+	name string,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	saved, ok := m.savedSearchByName(agentID, name)
+	if !ok {
+		return false, nil
+	}
+	delete(m.savedSearches, saved.ID)
+
+	return true, nil
+}
+
+// savedSearchByName finds one of an agent's saved searches by name. The
+// caller must hold the lock.
+func (m *MockStore) savedSearchByName(agentID int64,
+	name string,
+) (SavedSearch, bool) {
+	for _, saved := range m.savedSearches {
+		if saved.AgentID == agentID && saved.Name == name {
+			return saved, true
+		}
+	}
+
+	return SavedSearch{}, false
+}
@@ -0,0 +1,217 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// ScheduledMessageStore mock implementation
+// =============================================================================
+
+// CreateScheduledMessage queues a message to be sent at its send time.
+func (m *MockStore) CreateScheduledMessage(ctx context.Context,
+	params CreateScheduledMessageParams,
+) (ScheduledMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.agents[params.SenderID]; !ok {
+		return ScheduledMessage{}, fmt.Errorf("FOREIGN KEY constraint "+
+			"failed: agent %d: %w", params.SenderID, sql.ErrNoRows)
+	}
+	for _, sched := range m.scheduledMessages {
+		if sched.IdempotencyKey == params.IdempotencyKey {
+			return ScheduledMessage{}, errors.New("UNIQUE " +
+				"constraint failed: " +
+				"scheduled_messages.idempotency_key")
+		}
+	}
+
+	now := time.Now()
+	sched := ScheduledMessage{
+		ID:             m.nextScheduledID,
+		SenderID:       params.SenderID,
+		RecipientNames: slices.Clone(params.RecipientNames),
+		TopicName:      params.TopicName,
+		ThreadID:       params.ThreadID,
+		Subject:        params.Subject,
+		Body:           params.Body,
+		Priority:       params.Priority,
+		DeadlineAt:     params.DeadlineAt,
+		Attachments:    params.Attachments,
+		Metadata:       params.Metadata,
+		DeliveryMode:   params.DeliveryMode,
+		IdempotencyKey: params.IdempotencyKey,
+		SendAt:         time.Unix(params.SendAt.Unix(), 0),
+		State:          "pending",
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	m.scheduledMessages[sched.ID] = sched
+	m.nextScheduledID++
+
+	return sched, nil
+}
+
+// GetScheduledMessageByIdempotencyKey retrieves a scheduled message by the
+// idempotency key it will be sent with.
+func (m *MockStore) GetScheduledMessageByIdempotencyKey(ctx context.Context,
+	key string,
+) (ScheduledMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, sched := range m.scheduledMessages {
+		if sched.IdempotencyKey == key {
+			return sched, nil
+		}
+	}
+
+	return ScheduledMessage{}, sql.ErrNoRows
+}
+
+// ListScheduledMessages lists a sender's pending and failed scheduled
+// messages, soonest first.
+func (m *MockStore) ListScheduledMessages(ctx context.Context,
+	senderID int64, limit int,
+) ([]ScheduledMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.filterScheduled(limit, func(sched ScheduledMessage) bool {
+		return sched.SenderID == senderID &&
+			(sched.State == "pending" || sched.State == "failed")
+	}), nil
+}
+
+// ListDueScheduledMessages lists pending scheduled messages whose send time
+// is at or before now, oldest first.
+func (m *MockStore) ListDueScheduledMessages(ctx context.Context,
+	now time.Time, limit int,
+) ([]ScheduledMessage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.filterScheduled(limit, func(sched ScheduledMessage) bool {
+		return sched.State == "pending" &&
+			sched.SendAt.Unix() <= now.Unix()
+	}), nil
+}
+
+// CancelScheduledMessage cancels one of a sender's unsent scheduled
+// messages.
+func (m *MockStore) CancelScheduledMessage(ctx context.Context, id,
+	senderID int64,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sched, ok := m.scheduledMessages[id]
+	if !ok || sched.SenderID != senderID {
+		return false, nil
+	}
+	if sched.State != "pending" && sched.State != "failed" {
+		return false, nil
+	}
+
+	sched.State = "canceled"
+	sched.UpdatedAt = time.Now()
+	m.scheduledMessages[id] = sched
+
+	return true, nil
+}
+
+// MarkScheduledMessageSent records the message a scheduled message was
+// delivered as.
+func (m *MockStore) MarkScheduledMessageSent(ctx context.Context, id,
+	messageID int64,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sched, ok := m.scheduledMessages[id]
+	if !ok {
+		return nil
+	}
+
+	sched.State = "sent"
+	sched.MessageID = messageID
+	sched.LastError = ""
+	sched.UpdatedAt = time.Now()
+	m.scheduledMessages[id] = sched
+
+	return nil
+}
+
+// MarkScheduledMessageFailed records why a pending scheduled message could
+// not be delivered.
+func (m *MockStore) MarkScheduledMessageFailed(ctx context.Context, id int64,
+	lastError string,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sched, ok := m.scheduledMessages[id]
+	if !ok || sched.State != "pending" {
+		return nil
+	}
+
+	sched.State = "failed"
+	sched.LastError = lastError
+	sched.UpdatedAt = time.Now()
+	m.scheduledMessages[id] = sched
+
+	return nil
+}
+
+// DeferScheduledMessage pushes a pending scheduled message's send time back,
+// recording why it couldn't be sent yet.
+func (m *MockStore) DeferScheduledMessage(ctx context.Context, id int64,
+	sendAt time.Time, lastError string,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sched, ok := m.scheduledMessages[id]
+	if !ok || sched.State != "pending" {
+		return nil
+	}
+
+	sched.SendAt = sendAt
+	sched.LastError = lastError
+	sched.UpdatedAt = time.Now()
+	m.scheduledMessages[id] = sched
+
+	return nil
+}
+
+// filterScheduled returns the scheduled messages matching keep, ordered by
+// send time. Callers must hold the mutex.
+func (m *MockStore) filterScheduled(limit int,
+	keep func(ScheduledMessage) bool,
+) []ScheduledMessage {
+	var msgs []ScheduledMessage
+	for _, sched := range m.scheduledMessages {
+		if keep(sched) {
+			msgs = append(msgs, sched)
+		}
+	}
+
+	sort.Slice(msgs, func(i, j int) bool {
+		if !msgs[i].SendAt.Equal(msgs[j].SendAt) {
+			return msgs[i].SendAt.Before(msgs[j].SendAt)
+		}
+		return msgs[i].ID < msgs[j].ID
+	})
+	if limit > 0 && len(msgs) > limit {
+		msgs = msgs[:limit]
+	}
+
+	return msgs
+}
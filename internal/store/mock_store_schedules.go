@@ -0,0 +1,220 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// ScheduleStore mock implementation
+// =============================================================================
+
+// CreateSchedule creates a recurring schedule.
+func (m *MockStore) CreateSchedule(ctx context.Context,
+	params CreateScheduleParams,
+) (Schedule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.agents[params.SenderID]; !ok {
+		return Schedule{}, fmt.Errorf("FOREIGN KEY constraint failed: "+
+			"agent %d: %w", params.SenderID, sql.ErrNoRows)
+	}
+	for _, sched := range m.schedules {
+		if sched.SenderID == params.SenderID &&
+			sched.Name == params.Name {
+
+			return Schedule{}, errors.New("UNIQUE constraint " +
+				"failed: schedules.sender_id, schedules.name")
+		}
+	}
+
+	now := time.Now()
+	sched := Schedule{
+		ID:             m.nextScheduleID,
+		Name:           params.Name,
+		SenderID:       params.SenderID,
+		RecipientNames: slices.Clone(params.RecipientNames),
+		TopicName:      params.TopicName,
+		Subject:        params.Subject,
+		Body:           params.Body,
+		Priority:       params.Priority,
+		CronExpr:       params.CronExpr,
+		Timezone:       params.Timezone,
+		CatchUp:        params.CatchUp,
+		NextRunAt:      time.Unix(params.NextRunAt.Unix(), 0),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	m.schedules[sched.ID] = sched
+	m.nextScheduleID++
+
+	return sched, nil
+}
+
+// GetScheduleByName retrieves one of an agent's schedules by name.
+func (m *MockStore) GetScheduleByName(ctx context.Context, senderID int64,
+	name string,
+) (Schedule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	id, ok := m.scheduleIDByName(senderID, name)
+	if !ok {
+		return Schedule{}, sql.ErrNoRows
+	}
+
+	return m.schedules[id], nil
+}
+
+// ListSchedules lists an agent's schedules by name.
+func (m *MockStore) ListSchedules(ctx context.Context,
+	senderID int64,
+) ([]Schedule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var schedules []Schedule
+	for _, sched := range m.schedules {
+		if sched.SenderID == senderID {
+			schedules = append(schedules, sched)
+		}
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].Name < schedules[j].Name
+	})
+
+	return schedules, nil
+}
+
+// ListDueSchedules lists schedules that are not paused and whose next run is
+// at or before now, oldest first.
+func (m *MockStore) ListDueSchedules(ctx context.Context, now time.Time,
+	limit int,
+) ([]Schedule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var schedules []Schedule
+	for _, sched := range m.schedules {
+		if !sched.Paused && sched.NextRunAt.Unix() <= now.Unix() {
+			schedules = append(schedules, sched)
+		}
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		a, b := schedules[i], schedules[j]
+		if !a.NextRunAt.Equal(b.NextRunAt) {
+			return a.NextRunAt.Before(b.NextRunAt)
+		}
+		return a.ID < b.ID
+	})
+	if limit > 0 && len(schedules) > limit {
+		schedules = schedules[:limit]
+	}
+
+	return schedules, nil
+}
+
+// DeleteSchedule deletes one of an agent's schedules.
+func (m *MockStore) DeleteSchedule(ctx context.Context, senderID int64,
+	name string,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id, ok := m.scheduleIDByName(senderID, name)
+	if !ok {
+		return false, nil
+	}
+	delete(m.schedules, id)
+
+	return true, nil
+}
+
+// SetSchedulePaused pauses or resumes one of an agent's schedules and sets
+// its next run.
+func (m *MockStore) SetSchedulePaused(ctx context.Context, senderID int64,
+	name string, paused bool, nextRunAt time.Time,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id, ok := m.scheduleIDByName(senderID, name)
+	if !ok {
+		return false, nil
+	}
+
+	sched := m.schedules[id]
+	sched.Paused = paused
+	sched.NextRunAt = time.Unix(nextRunAt.Unix(), 0)
+	sched.UpdatedAt = time.Now()
+	m.schedules[id] = sched
+
+	return true, nil
+}
+
+// RecordScheduleRun records a run of a schedule and moves it on to its next
+// occurrence.
+func (m *MockStore) RecordScheduleRun(ctx context.Context,
+	params RecordScheduleRunParams,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sched, ok := m.schedules[params.ID]
+	if !ok {
+		return nil
+	}
+
+	runAt := time.Unix(params.RunAt.Unix(), 0)
+	sched.NextRunAt = time.Unix(params.NextRunAt.Unix(), 0)
+	sched.LastRunAt = &runAt
+	sched.LastMessageID = params.MessageID
+	sched.LastError = params.LastError
+	sched.RunCount++
+	sched.UpdatedAt = time.Now()
+	m.schedules[params.ID] = sched
+
+	return nil
+}
+
+// AdvanceSchedule moves a schedule on to its next occurrence without
+// recording a run.
+func (m *MockStore) AdvanceSchedule(ctx context.Context, id int64,
+	nextRunAt time.Time,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sched, ok := m.schedules[id]
+	if !ok {
+		return nil
+	}
+
+	sched.NextRunAt = time.Unix(nextRunAt.Unix(), 0)
+	sched.UpdatedAt = time.Now()
+	m.schedules[id] = sched
+
+	return nil
+}
+
+// scheduleIDByName finds one of an agent's schedules by name. Callers must
+// hold the mutex.
+func (m *MockStore) scheduleIDByName(senderID int64,
+	name string,
+) (int64, bool) {
+	for id, sched := range m.schedules {
+		if sched.SenderID == senderID && sched.Name == name {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
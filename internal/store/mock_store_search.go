@@ -0,0 +1,284 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/roasbeef/subtrate/internal/search"
+)
+
+// mockQuotedTerm matches the quoted strings of an FTS5 expression.
+var mockQuotedTerm = regexp.MustCompile(`"((?:[^"]|"")*)"`)
+
+// SearchMessagesWithFilters approximates a filtered full-text search: every
+// quoted string in Match must appear in the subject or body, and none of the
+// ones in Exclude may. Results are newest first and all rank equally.
+func (m *MockStore) SearchMessagesWithFilters(ctx context.Context,
+	p MessageSearchParams,
+) ([]MessageSearchHit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var hits []MessageSearchHit
+	for _, result := range m.mockSearch(p) {
+		if p.Cursor != nil && result.ID >= p.Cursor.ID {
+			continue
+		}
+
+		hit := MessageSearchHit{
+			InboxMessage: result,
+			Snippet: search.Excerpt(
+				result.Body, search.ExcerptRunes,
+			),
+			SubjectHighlight: result.Subject,
+		}
+		if !p.IncludeBody {
+			hit.Body = ""
+		}
+		hits = append(hits, hit)
+	}
+	if p.Limit > 0 && len(hits) > p.Limit {
+		hits = hits[:p.Limit]
+	}
+
+	return hits, nil
+}
+
+// CountMessagesWithFilters returns the number of messages the approximate
+// search matches.
+func (m *MockStore) CountMessagesWithFilters(ctx context.Context,
+	p MessageSearchParams,
+) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return int64(len(m.mockSearch(p))), nil
+}
+
+// mockSearch returns every message a search matches, newest first. The
+// caller must hold the read lock.
+func (m *MockStore) mockSearch(p MessageSearchParams) []InboxMessage {
+	var results []InboxMessage
+	for _, msg := range m.messages {
+		text := msg.Subject + "\n" + msg.Body
+
+		result := InboxMessage{Message: msg}
+		if sender, ok := m.agents[msg.SenderID]; ok {
+			result.SenderName = sender.Name
+		}
+
+		recipients := m.messageRecipients[msg.ID]
+		if p.AgentID != 0 {
+			recipient, ok := recipients[p.AgentID]
+			sent := msg.SenderID == p.AgentID &&
+				!msg.DeletedBySender
+			if !ok && !sent {
+				continue
+			}
+			result.State = recipient.State
+		}
+
+		switch {
+		case !mockTermsMatch(p.Match, text, true):
+			continue
+
+		case p.Exclude != "" && mockTermsMatch(p.Exclude, text, false):
+			continue
+
+		case p.SenderID != 0 && msg.SenderID != p.SenderID:
+			continue
+
+		case p.TopicID != 0 && msg.TopicID != p.TopicID:
+			continue
+
+		case p.ThreadID != "" && msg.ThreadID != p.ThreadID:
+			continue
+
+		case p.Priority != "" && msg.Priority != p.Priority:
+			continue
+
+		case p.After != nil && msg.CreatedAt.Before(*p.After):
+			continue
+
+		case p.Before != nil && !msg.CreatedAt.Before(*p.Before):
+			continue
+
+		case !strings.Contains(msg.Body, p.BodyContains):
+			continue
+
+		case p.HasAttachments && (msg.Attachments == "" ||
+			msg.Attachments == "[]" || msg.Attachments == "null"):
+
+			continue
+		}
+
+		if len(p.MessageIDs) > 0 &&
+			!slices.Contains(p.MessageIDs, msg.ID) {
+			continue
+		}
+		if p.RecipientID != 0 {
+			if _, ok := recipients[p.RecipientID]; !ok {
+				continue
+			}
+		}
+		if p.State != "" && !m.mockStateMatches(p, recipients) {
+			continue
+		}
+		if p.Label != "" {
+			labelID, ok := m.labelIDByName(p.AgentID, p.Label)
+			key := messageLabelKey{msg.ID, p.AgentID}
+			if !ok || !m.messageLabels[key][labelID] {
+				continue
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ID > results[j].ID
+	})
+
+	return results
+}
+
+// mockStateMatches returns true if the searching agent's copy of a message,
+// or any copy in a global search, is in the wanted state.
+func (m *MockStore) mockStateMatches(p MessageSearchParams,
+	recipients map[int64]MessageRecipient,
+) bool {
+	if p.AgentID != 0 {
+		recipient, ok := recipients[p.AgentID]
+		return ok && recipient.State == p.State
+	}
+
+	for _, recipient := range recipients {
+		if recipient.State == p.State {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mockTermsMatch checks the quoted strings of an FTS5 expression against
+// text, case-insensitively: all of them must appear, or any of them if all
+// is false. An empty expression matches when all is true.
+func mockTermsMatch(expr, text string, all bool) bool {
+	text = strings.ToLower(text)
+	for _, m := range mockQuotedTerm.FindAllStringSubmatch(expr, -1) {
+		term := strings.ToLower(strings.ReplaceAll(m[1], `""`, `"`))
+		if strings.Contains(text, term) != all {
+			return !all
+		}
+	}
+
+	return all
+}
+
+// SearchAll approximates a search across entity types over the messages,
+// tasks and annotations the mock holds, matching terms as
+// SearchMessagesWithFilters does. Hits all rank equally, newest first.
+func (m *MockStore) SearchAll(ctx context.Context,
+	p SearchAllParams,
+) ([]SearchAllHit, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	hits := m.mockSearchAll(p)
+	if p.Limit > 0 && len(hits) > p.Limit {
+		hits = hits[:p.Limit]
+	}
+
+	return hits, nil
+}
+
+// CountSearchAll returns the number of entities the approximate search
+// matches.
+func (m *MockStore) CountSearchAll(ctx context.Context,
+	p SearchAllParams,
+) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return int64(len(m.mockSearchAll(p))), nil
+}
+
+// mockSearchAll returns every entity a search matches, newest first. The
+// caller must hold the read lock.
+func (m *MockStore) mockSearchAll(p SearchAllParams) []SearchAllHit {
+	var hits []SearchAllHit
+	add := func(kind search.Kind, hit SearchAllHit, title, text string) {
+		if len(p.Kinds) > 0 && !slices.Contains(p.Kinds, kind) {
+			return
+		}
+
+		all := title + "\n" + text
+		switch {
+		case !mockTermsMatch(p.Match, all, true):
+			return
+
+		case p.Exclude != "" && mockTermsMatch(p.Exclude, all, false):
+			return
+
+		case p.After != nil && hit.CreatedAt.Before(*p.After):
+			return
+
+		case p.Before != nil && !hit.CreatedAt.Before(*p.Before):
+			return
+		}
+
+		hit.Kind = kind
+		hit.Snippet = search.Excerpt(text, search.ExcerptRunes)
+		hits = append(hits, hit)
+	}
+
+	for _, msg := range m.mockSearch(MessageSearchParams{
+		AgentID: p.AgentID,
+	}) {
+		add(search.KindMessage, SearchAllHit{
+			ID:        msg.ID,
+			ThreadID:  msg.ThreadID,
+			Title:     msg.Subject,
+			CreatedAt: msg.CreatedAt,
+		}, msg.Subject, msg.Body)
+	}
+	for _, task := range m.tasks {
+		if task.Status == "deleted" {
+			continue
+		}
+		add(search.KindTask, SearchAllHit{
+			ID:        task.ID,
+			Ref:       task.ListID,
+			Title:     task.Subject,
+			CreatedAt: task.CreatedAt,
+		}, task.Subject, task.Description)
+	}
+	for _, a := range m.planAnnotations {
+		add(search.KindPlanAnnotation, SearchAllHit{
+			ID:        a.ID,
+			Ref:       a.PlanReviewID,
+			CreatedAt: a.CreatedAt,
+		}, a.OriginalText, a.Text)
+	}
+	for _, a := range m.diffAnnotations {
+		title := fmt.Sprintf("%s:%d", a.FilePath, a.LineStart)
+		add(search.KindDiffAnnotation, SearchAllHit{
+			ID:        a.ID,
+			Ref:       strconv.FormatInt(a.MessageID, 10),
+			Title:     title,
+			CreatedAt: a.CreatedAt,
+		}, a.FilePath, a.Text)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].CreatedAt.After(hits[j].CreatedAt)
+	})
+
+	return hits
+}
@@ -0,0 +1,254 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+)
+
+// threadPrefKey identifies an agent's preferences for a thread.
+type threadPrefKey struct {
+	threadID string
+	agentID  int64
+}
+
+// =============================================================================
+// ThreadStore mock implementation
+// =============================================================================
+
+// GetThreadPreference retrieves an agent's preferences for a thread.
+func (m *MockStore) GetThreadPreference(ctx context.Context, threadID string,
+	agentID int64,
+) (ThreadPreference, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	pref, ok := m.threadPrefs[threadPrefKey{threadID, agentID}]
+	if !ok {
+		return ThreadPreference{}, sql.ErrNoRows
+	}
+
+	return pref, nil
+}
+
+// SetThreadPreference creates or replaces an agent's preferences for a
+// thread.
+func (m *MockStore) SetThreadPreference(ctx context.Context,
+	pref ThreadPreference,
+) (ThreadPreference, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Unix(time.Now().Unix(), 0)
+	key := threadPrefKey{pref.ThreadID, pref.AgentID}
+	pref.CreatedAt = now
+	if existing, ok := m.threadPrefs[key]; ok {
+		pref.CreatedAt = existing.CreatedAt
+	}
+	pref.UpdatedAt = now
+	m.threadPrefs[key] = pref
+
+	return pref, nil
+}
+
+// DeleteThreadPreference clears an agent's preferences for a thread.
+func (m *MockStore) DeleteThreadPreference(ctx context.Context,
+	threadID string, agentID int64,
+) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := threadPrefKey{threadID, agentID}
+	if _, ok := m.threadPrefs[key]; !ok {
+		return false, nil
+	}
+	delete(m.threadPrefs, key)
+
+	return true, nil
+}
+
+// ListThreadPreferences lists the threads an agent muted or follows, most
+// recently changed first.
+func (m *MockStore) ListThreadPreferences(ctx context.Context,
+	agentID int64,
+) ([]ThreadPreference, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var prefs []ThreadPreference
+	for key, pref := range m.threadPrefs {
+		if key.agentID == agentID {
+			prefs = append(prefs, pref)
+		}
+	}
+	sort.Slice(prefs, func(i, j int) bool {
+		if !prefs[i].UpdatedAt.Equal(prefs[j].UpdatedAt) {
+			return prefs[i].UpdatedAt.After(prefs[j].UpdatedAt)
+		}
+		return prefs[i].ThreadID < prefs[j].ThreadID
+	})
+
+	return prefs, nil
+}
+
+// ListThreadPreferencesByThread lists every agent's preferences for a thread.
+func (m *MockStore) ListThreadPreferencesByThread(ctx context.Context,
+	threadID string,
+) ([]ThreadPreference, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.threadPreferences(threadID), nil
+}
+
+// CopyThreadPreferences gives a thread the preferences agents set on another,
+// keeping any they already set on it.
+func (m *MockStore) CopyThreadPreferences(ctx context.Context,
+	fromThreadID, toThreadID string,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Unix(time.Now().Unix(), 0)
+	for _, pref := range m.threadPreferences(fromThreadID) {
+		key := threadPrefKey{toThreadID, pref.AgentID}
+		if _, ok := m.threadPrefs[key]; ok {
+			continue
+		}
+
+		pref.ThreadID = toThreadID
+		pref.CreatedAt = now
+		pref.UpdatedAt = now
+		m.threadPrefs[key] = pref
+	}
+
+	return nil
+}
+
+// DeleteThreadPreferences clears every agent's preferences for a thread.
+func (m *MockStore) DeleteThreadPreferences(ctx context.Context,
+	threadID string,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key := range m.threadPrefs {
+		if key.threadID == threadID {
+			delete(m.threadPrefs, key)
+		}
+	}
+
+	return nil
+}
+
+// CountThreadMessages counts the messages in a thread.
+func (m *MockStore) CountThreadMessages(ctx context.Context,
+	threadID string,
+) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var count int64
+	for _, msg := range m.messages {
+		if msg.ThreadID == threadID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// CountThreadReviews counts the code reviews and plan reviews that track a
+// thread.
+func (m *MockStore) CountThreadReviews(ctx context.Context,
+	threadID string,
+) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var count int64
+	for _, review := range getReviewData(m).reviews {
+		if review.ThreadID == threadID {
+			count++
+		}
+	}
+	for _, review := range getPlanReviewData(m).planReviews {
+		if review.ThreadID == threadID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// MoveThreadMessages moves messages, and the questions they ask, from one
+// thread to another.
+func (m *MockStore) MoveThreadMessages(ctx context.Context,
+	params MoveThreadMessagesParams,
+) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var moved int64
+	for id, msg := range m.messages {
+		if msg.ThreadID != params.FromThreadID ||
+			id < params.MinMessageID {
+
+			continue
+		}
+
+		msg.ThreadID = params.ToThreadID
+		m.messages[id] = msg
+		moved++
+	}
+
+	for id, q := range m.questions {
+		if q.ThreadID != params.FromThreadID ||
+			q.MessageID < params.MinMessageID {
+
+			continue
+		}
+
+		q.ThreadID = params.ToThreadID
+		m.questions[id] = q
+	}
+
+	return moved, nil
+}
+
+// MoveScheduledThreadMessages points the messages still waiting to be sent
+// into a thread at another.
+func (m *MockStore) MoveScheduledThreadMessages(ctx context.Context,
+	fromThreadID, toThreadID string,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, sm := range m.scheduledMessages {
+		if sm.ThreadID != fromThreadID || sm.State != "pending" {
+			continue
+		}
+
+		sm.ThreadID = toThreadID
+		m.scheduledMessages[id] = sm
+	}
+
+	return nil
+}
+
+// threadPreferences lists every agent's preferences for a thread by agent.
+// The caller must hold the lock.
+func (m *MockStore) threadPreferences(threadID string) []ThreadPreference {
+	var prefs []ThreadPreference
+	for key, pref := range m.threadPrefs {
+		if key.threadID == threadID {
+			prefs = append(prefs, pref)
+		}
+	}
+	sort.Slice(prefs, func(i, j int) bool {
+		return prefs[i].AgentID < prefs[j].AgentID
+	})
+
+	return prefs
+}
@@ -0,0 +1,188 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/roasbeef/subtrate/internal/search"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSearchAll verifies that review issues, plan reviews, tasks and
+// annotations are indexed as they're written, and that a search merges
+// their hits by rank.
+func TestSearchAll(t *testing.T) {
+	store, cleanup := testTaskDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	agentID := createTestAgent(t, store, "searcher")
+
+	topic, err := store.CreateTopic(ctx, CreateTopicParams{
+		Name:      "search-all",
+		TopicType: "broadcast",
+	})
+	require.NoError(t, err)
+	msg, err := store.CreateMessage(ctx, CreateMessageParams{
+		ThreadID:  "thread-msg",
+		TopicID:   topic.ID,
+		LogOffset: 1,
+		SenderID:  agentID,
+		Subject:   "Standup",
+		Body:      "We chased the HTLC timeout bug all day.",
+		Priority:  "normal",
+	})
+	require.NoError(t, err)
+
+	_, err = store.CreateReview(ctx, CreateReviewParams{
+		ReviewID:    "rev-1",
+		ThreadID:    "thread-rev",
+		RequesterID: agentID,
+		Branch:      "fix-htlc",
+		BaseBranch:  "main",
+		CommitSHA:   "abc123",
+		RepoPath:    "/tmp/repo",
+		ReviewType:  "full",
+		Priority:    "normal",
+	})
+	require.NoError(t, err)
+	issue, err := store.CreateReviewIssue(ctx, CreateReviewIssueParams{
+		ReviewID:     "rev-1",
+		IterationNum: 1,
+		IssueType:    "bug",
+		Severity:     "high",
+		FilePath:     "htlcswitch/link.go",
+		LineStart:    10,
+		Title:        "HTLC timeout bug",
+		Description:  "The timeout height is off by one.",
+	})
+	require.NoError(t, err)
+
+	plan, err := store.CreatePlanReview(ctx, CreatePlanReviewParams{
+		PlanReviewID: "plan-1",
+		ThreadID:     "thread-plan",
+		RequesterID:  agentID,
+		ReviewerName: "User",
+		PlanPath:     "/tmp/plan.md",
+		PlanTitle:    "Rework invoice expiry",
+		PlanSummary:  "Expire invoices on a timer.",
+		SessionID:    "session-1",
+	})
+	require.NoError(t, err)
+	ann, err := store.CreatePlanAnnotation(ctx, CreatePlanAnnotationParams{
+		PlanReviewID:   plan.PlanReviewID,
+		AnnotationID:   "ann-1",
+		BlockID:        "block-0",
+		AnnotationType: "COMMENT",
+		Text:           "Mind the HTLC timeout here.",
+		OriginalText:   "Expire invoices",
+	})
+	require.NoError(t, err)
+
+	diffAnn, err := store.CreateDiffAnnotation(
+		ctx, CreateDiffAnnotationParams{
+			AnnotationID:   "diff-1",
+			MessageID:      msg.ID,
+			AnnotationType: "concern",
+			Scope:          "line",
+			FilePath:       "htlc/timeout_resolver.go",
+			LineStart:      42,
+			LineEnd:        42,
+			Side:           "new",
+			Text:           "Is this timeout checked?",
+		},
+	)
+	require.NoError(t, err)
+
+	createTestTaskList(t, store, "list-1", agentID)
+	task, err := store.CreateTask(ctx, CreateTaskParams{
+		AgentID:      agentID,
+		ListID:       "list-1",
+		ClaudeTaskID: "1",
+		Subject:      "Fix the HTLC timeout bug",
+		Status:       "pending",
+	})
+	require.NoError(t, err)
+
+	hits, err := store.SearchAll(ctx, SearchAllParams{
+		Match: `"htlc" AND "timeout"`,
+		Limit: 10,
+	})
+	require.NoError(t, err)
+
+	byKind := make(map[search.Kind]SearchAllHit)
+	for _, hit := range hits {
+		byKind[hit.Kind] = hit
+	}
+	require.Len(t, byKind, 5)
+
+	require.Equal(t, msg.ID, byKind[search.KindMessage].ID)
+	require.Equal(t, "We chased the **HTLC** **timeout** bug all day.",
+		byKind[search.KindMessage].Snippet)
+
+	require.Equal(t, issue.ID, byKind[search.KindReviewIssue].ID)
+	require.Equal(t, "rev-1", byKind[search.KindReviewIssue].Ref)
+	require.Equal(t, "thread-rev", byKind[search.KindReviewIssue].ThreadID)
+	require.Equal(t, "**HTLC** **timeout** bug",
+		byKind[search.KindReviewIssue].Title)
+
+	require.Equal(t, ann.ID, byKind[search.KindPlanAnnotation].ID)
+	require.Equal(t, "plan-1", byKind[search.KindPlanAnnotation].Ref)
+	require.Equal(t, "Rework invoice expiry",
+		byKind[search.KindPlanAnnotation].Title)
+
+	require.Equal(t, diffAnn.ID, byKind[search.KindDiffAnnotation].ID)
+	require.Equal(t, "thread-msg",
+		byKind[search.KindDiffAnnotation].ThreadID)
+
+	require.Equal(t, task.ID, byKind[search.KindTask].ID)
+	require.Equal(t, "list-1", byKind[search.KindTask].Ref)
+
+	for i := 1; i < len(hits); i++ {
+		require.LessOrEqual(t, hits[i-1].Rank, hits[i].Rank)
+	}
+
+	count, err := store.CountSearchAll(ctx, SearchAllParams{
+		Match: `"htlc" AND "timeout"`,
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 5, count)
+
+	// Kinds narrow the search.
+	hits, err = store.SearchAll(ctx, SearchAllParams{
+		Match: `"expiry"`,
+		Kinds: []search.Kind{search.KindPlanReview},
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	require.Equal(t, plan.ID, hits[0].ID)
+	require.Equal(t, "Rework invoice **expiry**", hits[0].Title)
+
+	// Edits are reindexed, and deleted entities drop out.
+	_, err = store.UpsertTask(ctx, UpsertTaskParams{
+		AgentID:      agentID,
+		ListID:       "list-1",
+		ClaudeTaskID: "1",
+		Subject:      "Fix the channel reestablish bug",
+		Status:       "pending",
+	})
+	require.NoError(t, err)
+	taskOnly := SearchAllParams{
+		Match: `"htlc" AND "timeout"`,
+		Kinds: []search.Kind{search.KindTask},
+	}
+	count, err = store.CountSearchAll(ctx, taskOnly)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	taskOnly.Match = `"reestablish"`
+	count, err = store.CountSearchAll(ctx, taskOnly)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, count)
+
+	require.NoError(t, store.DeleteTask(ctx, task.ID))
+	count, err = store.CountSearchAll(ctx, taskOnly)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
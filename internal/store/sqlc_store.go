@@ -68,6 +68,18 @@ type QueryStore interface {
 	GetMessageRecipient(
 		ctx context.Context, arg sqlc.GetMessageRecipientParams,
 	) (sqlc.MessageRecipient, error)
+	PersistRecipientState(
+		ctx context.Context, arg sqlc.PersistRecipientStateParams,
+	) error
+	ListSnoozedRecipients(
+		ctx context.Context,
+	) ([]sqlc.ListSnoozedRecipientsRow, error)
+	GetOverdueMessages(
+		ctx context.Context, arg sqlc.GetOverdueMessagesParams,
+	) ([]sqlc.GetOverdueMessagesRow, error)
+	UpdateMessagePriority(
+		ctx context.Context, arg sqlc.UpdateMessagePriorityParams,
+	) error
 	CountUnreadByAgent(ctx context.Context, agentID int64) (int64, error)
 	CountUnreadUrgentByAgent(
 		ctx context.Context, agentID int64,
@@ -133,6 +145,9 @@ type QueryStore interface {
 		ctx context.Context, arg sqlc.GetOrCreateTopicParams,
 	) (sqlc.Topic, error)
 	ListTopics(ctx context.Context) ([]sqlc.Topic, error)
+	UpdateTopicRetention(
+		ctx context.Context, arg sqlc.UpdateTopicRetentionParams,
+	) error
 	ListTopicsWithMessageCount(
 		ctx context.Context,
 	) ([]sqlc.ListTopicsWithMessageCountRow, error)
@@ -300,6 +315,384 @@ type QueryStore interface {
 		ctx context.Context, planReviewID string,
 	) error
 
+	// Escalation operations.
+	ListOverdueRecipients(
+		ctx context.Context, arg sqlc.ListOverdueRecipientsParams,
+	) ([]sqlc.ListOverdueRecipientsRow, error)
+	GetMessageEscalation(
+		ctx context.Context, arg sqlc.GetMessageEscalationParams,
+	) (sqlc.MessageEscalation, error)
+	UpsertMessageEscalation(
+		ctx context.Context, arg sqlc.UpsertMessageEscalationParams,
+	) error
+
+	// Queue delivery operations.
+	CreateQueueDelivery(
+		ctx context.Context, arg sqlc.CreateQueueDeliveryParams,
+	) error
+	GetQueueDelivery(
+		ctx context.Context, messageID int64,
+	) (sqlc.QueueDelivery, error)
+	ClaimNextQueueDelivery(
+		ctx context.Context, arg sqlc.ClaimNextQueueDeliveryParams,
+	) (sqlc.QueueDelivery, error)
+	AckQueueDelivery(
+		ctx context.Context, arg sqlc.AckQueueDeliveryParams,
+	) (int64, error)
+	ReleaseQueueDelivery(
+		ctx context.Context, arg sqlc.ReleaseQueueDeliveryParams,
+	) (int64, error)
+	ExtendQueueLease(
+		ctx context.Context, arg sqlc.ExtendQueueLeaseParams,
+	) (int64, error)
+	ListExpiredQueueLeases(
+		ctx context.Context, now sql.NullInt64,
+	) ([]sqlc.QueueDelivery, error)
+	ReleaseExpiredQueueLeases(
+		ctx context.Context, arg sqlc.ReleaseExpiredQueueLeasesParams,
+	) (int64, error)
+	ListExhaustedQueueDeliveries(
+		ctx context.Context, arg sqlc.ListExhaustedQueueDeliveriesParams,
+	) ([]sqlc.ListExhaustedQueueDeliveriesRow, error)
+	MarkQueueDeliveryDeadLettered(
+		ctx context.Context, arg sqlc.MarkQueueDeliveryDeadLetteredParams,
+	) error
+
+	// Retention operations.
+	SummarizeExpiredMessages(
+		ctx context.Context, now int64,
+	) ([]sqlc.SummarizeExpiredMessagesRow, error)
+	DeleteExpiredMessages(
+		ctx context.Context, arg sqlc.DeleteExpiredMessagesParams,
+	) (int64, error)
+	CountStaleConsumerOffsets(ctx context.Context) (int64, error)
+	DeleteStaleConsumerOffsets(ctx context.Context) (int64, error)
+	OptimizeMessagesFTS(ctx context.Context) error
+	SetMessageLegalHold(
+		ctx context.Context, arg sqlc.SetMessageLegalHoldParams,
+	) (int64, error)
+	SetThreadLegalHold(
+		ctx context.Context, arg sqlc.SetThreadLegalHoldParams,
+	) (int64, error)
+
+	// Recipient group operations.
+	CreateRecipientGroup(
+		ctx context.Context, arg sqlc.CreateRecipientGroupParams,
+	) (sqlc.RecipientGroup, error)
+	GetRecipientGroupByName(
+		ctx context.Context, name string,
+	) (sqlc.RecipientGroup, error)
+	ListRecipientGroups(
+		ctx context.Context,
+	) ([]sqlc.ListRecipientGroupsRow, error)
+	DeleteRecipientGroup(ctx context.Context, id int64) (int64, error)
+	AddRecipientGroupMember(
+		ctx context.Context, arg sqlc.AddRecipientGroupMemberParams,
+	) error
+	RemoveRecipientGroupMember(
+		ctx context.Context, arg sqlc.RemoveRecipientGroupMemberParams,
+	) (int64, error)
+	ListRecipientGroupMembers(
+		ctx context.Context, groupID int64,
+	) ([]sqlc.Agent, error)
+	ListAgentsMatchingProject(
+		ctx context.Context, project sql.NullString,
+	) ([]sqlc.Agent, error)
+	ListAgentsByBranch(
+		ctx context.Context, gitBranch sql.NullString,
+	) ([]sqlc.Agent, error)
+	ListAgentsActiveSince(
+		ctx context.Context, lastActiveAt int64,
+	) ([]sqlc.Agent, error)
+	ListAgentsByNamePattern(
+		ctx context.Context, pattern string,
+	) ([]sqlc.Agent, error)
+	CreateRecipientExpansion(
+		ctx context.Context, arg sqlc.CreateRecipientExpansionParams,
+	) error
+	ListRecipientExpansions(
+		ctx context.Context, messageID int64,
+	) ([]sqlc.ListRecipientExpansionsRow, error)
+
+	// Dead letter operations.
+	CreateDeadLetter(
+		ctx context.Context, arg sqlc.CreateDeadLetterParams,
+	) error
+	ListDeadLetters(
+		ctx context.Context, arg sqlc.ListDeadLettersParams,
+	) ([]sqlc.ListDeadLettersRow, error)
+	DeleteDeadLetter(
+		ctx context.Context, arg sqlc.DeleteDeadLetterParams,
+	) (int64, error)
+
+	// Scheduled message operations.
+	CreateScheduledMessage(
+		ctx context.Context, arg sqlc.CreateScheduledMessageParams,
+	) (sqlc.ScheduledMessage, error)
+	GetScheduledMessageByIdempotencyKey(
+		ctx context.Context, idempotencyKey string,
+	) (sqlc.ScheduledMessage, error)
+	ListScheduledMessages(
+		ctx context.Context, arg sqlc.ListScheduledMessagesParams,
+	) ([]sqlc.ScheduledMessage, error)
+	ListDueScheduledMessages(
+		ctx context.Context, arg sqlc.ListDueScheduledMessagesParams,
+	) ([]sqlc.ScheduledMessage, error)
+	CancelScheduledMessage(
+		ctx context.Context, arg sqlc.CancelScheduledMessageParams,
+	) (int64, error)
+	MarkScheduledMessageSent(
+		ctx context.Context, arg sqlc.MarkScheduledMessageSentParams,
+	) error
+	MarkScheduledMessageFailed(
+		ctx context.Context, arg sqlc.MarkScheduledMessageFailedParams,
+	) error
+	DeferScheduledMessage(
+		ctx context.Context, arg sqlc.DeferScheduledMessageParams,
+	) error
+
+	// Schedule operations.
+	CreateSchedule(
+		ctx context.Context, arg sqlc.CreateScheduleParams,
+	) (sqlc.Schedule, error)
+	GetScheduleByName(
+		ctx context.Context, arg sqlc.GetScheduleByNameParams,
+	) (sqlc.Schedule, error)
+	ListSchedules(
+		ctx context.Context, senderID int64,
+	) ([]sqlc.Schedule, error)
+	ListDueSchedules(
+		ctx context.Context, arg sqlc.ListDueSchedulesParams,
+	) ([]sqlc.Schedule, error)
+	DeleteSchedule(
+		ctx context.Context, arg sqlc.DeleteScheduleParams,
+	) (int64, error)
+	SetSchedulePaused(
+		ctx context.Context, arg sqlc.SetSchedulePausedParams,
+	) (int64, error)
+	RecordScheduleRun(
+		ctx context.Context, arg sqlc.RecordScheduleRunParams,
+	) error
+	AdvanceSchedule(
+		ctx context.Context, arg sqlc.AdvanceScheduleParams,
+	) error
+
+	// Question operations.
+	CreateQuestion(
+		ctx context.Context, arg sqlc.CreateQuestionParams,
+	) (sqlc.Question, error)
+	GetQuestion(
+		ctx context.Context, questionID string,
+	) (sqlc.Question, error)
+	ListPendingQuestionsByThread(
+		ctx context.Context, threadID string,
+	) ([]sqlc.Question, error)
+	ListTimedOutQuestions(
+		ctx context.Context, arg sqlc.ListTimedOutQuestionsParams,
+	) ([]sqlc.Question, error)
+	ResolveQuestion(
+		ctx context.Context, arg sqlc.ResolveQuestionParams,
+	) (int64, error)
+	EscalateQuestion(
+		ctx context.Context, arg sqlc.EscalateQuestionParams,
+	) error
+
+	// Message revision operations.
+	CreateMessageRevision(
+		ctx context.Context, arg sqlc.CreateMessageRevisionParams,
+	) (sqlc.MessageRevision, error)
+	ListMessageRevisions(
+		ctx context.Context, messageID int64,
+	) ([]sqlc.MessageRevision, error)
+	ListThreadMessageRevisions(
+		ctx context.Context, threadID string,
+	) ([]sqlc.MessageRevision, error)
+	UpdateMessageContent(
+		ctx context.Context, arg sqlc.UpdateMessageContentParams,
+	) (int64, error)
+	RecallMessage(
+		ctx context.Context, arg sqlc.RecallMessageParams,
+	) (int64, error)
+
+	// Inbox rule operations.
+	CreateInboxRule(
+		ctx context.Context, arg sqlc.CreateInboxRuleParams,
+	) (sqlc.InboxRule, error)
+	GetInboxRuleByName(
+		ctx context.Context, arg sqlc.GetInboxRuleByNameParams,
+	) (sqlc.InboxRule, error)
+	ListInboxRules(
+		ctx context.Context, agentID int64,
+	) ([]sqlc.InboxRule, error)
+	DeleteInboxRule(
+		ctx context.Context, arg sqlc.DeleteInboxRuleParams,
+	) (int64, error)
+	SetInboxRuleEnabled(
+		ctx context.Context, arg sqlc.SetInboxRuleEnabledParams,
+	) (int64, error)
+	RecordInboxRuleMatch(
+		ctx context.Context, arg sqlc.RecordInboxRuleMatchParams,
+	) error
+	SetRecipientCategory(
+		ctx context.Context, arg sqlc.SetRecipientCategoryParams,
+	) error
+	ListReceivedMessages(
+		ctx context.Context, arg sqlc.ListReceivedMessagesParams,
+	) ([]sqlc.ListReceivedMessagesRow, error)
+
+	// Label operations.
+	CreateLabel(
+		ctx context.Context, arg sqlc.CreateLabelParams,
+	) (sqlc.Label, error)
+	GetLabelByName(
+		ctx context.Context, arg sqlc.GetLabelByNameParams,
+	) (sqlc.Label, error)
+	ListLabels(
+		ctx context.Context, agentID int64,
+	) ([]sqlc.ListLabelsRow, error)
+	DeleteLabel(
+		ctx context.Context, arg sqlc.DeleteLabelParams,
+	) (int64, error)
+	AddMessageLabel(
+		ctx context.Context, arg sqlc.AddMessageLabelParams,
+	) error
+	RemoveMessageLabel(
+		ctx context.Context, arg sqlc.RemoveMessageLabelParams,
+	) (int64, error)
+	ListMessageLabels(
+		ctx context.Context, arg sqlc.ListMessageLabelsParams,
+	) ([]sqlc.ListMessageLabelsRow, error)
+	GetInboxMessagesByLabel(
+		ctx context.Context, arg sqlc.GetInboxMessagesByLabelParams,
+	) ([]sqlc.GetInboxMessagesByLabelRow, error)
+
+	// Embedding operations.
+	UpsertMessageEmbedding(
+		ctx context.Context, arg sqlc.UpsertMessageEmbeddingParams,
+	) error
+	GetMessageEmbedding(
+		ctx context.Context, arg sqlc.GetMessageEmbeddingParams,
+	) (sqlc.MessageEmbedding, error)
+	ListMessagesToEmbed(
+		ctx context.Context, arg sqlc.ListMessagesToEmbedParams,
+	) ([]sqlc.Message, error)
+
+	// Saved search operations.
+	UpsertSavedSearch(
+		ctx context.Context, arg sqlc.UpsertSavedSearchParams,
+	) (sqlc.SavedSearch, error)
+	GetSavedSearch(
+		ctx context.Context, arg sqlc.GetSavedSearchParams,
+	) (sqlc.SavedSearch, error)
+	ListSavedSearches(
+		ctx context.Context, agentID int64,
+	) ([]sqlc.SavedSearch, error)
+	DeleteSavedSearch(
+		ctx context.Context, arg sqlc.DeleteSavedSearchParams,
+	) (int64, error)
+
+	// Credential operations.
+	CreateCredential(
+		ctx context.Context, arg sqlc.CreateCredentialParams,
+	) (sqlc.Credential, error)
+	GetCredentialByHash(
+		ctx context.Context, tokenHash string,
+	) (sqlc.GetCredentialByHashRow, error)
+	ListCredentials(
+		ctx context.Context, arg sqlc.ListCredentialsParams,
+	) ([]sqlc.ListCredentialsRow, error)
+	RevokeCredential(
+		ctx context.Context, arg sqlc.RevokeCredentialParams,
+	) (int64, error)
+	TouchCredential(
+		ctx context.Context, arg sqlc.TouchCredentialParams,
+	) error
+
+	// Limit operations.
+	GetEffectiveAgentLimit(
+		ctx context.Context, arg sqlc.GetEffectiveAgentLimitParams,
+	) (sqlc.AgentLimit, error)
+	CreateAgentLimit(
+		ctx context.Context, arg sqlc.CreateAgentLimitParams,
+	) (sqlc.AgentLimit, error)
+	DeleteAgentLimit(
+		ctx context.Context, arg sqlc.DeleteAgentLimitParams,
+	) (int64, error)
+	ListAgentLimits(
+		ctx context.Context, agentID int64,
+	) ([]sqlc.ListAgentLimitsRow, error)
+	GetRateLimitBucket(
+		ctx context.Context, arg sqlc.GetRateLimitBucketParams,
+	) (sqlc.RateLimitBucket, error)
+	UpsertRateLimitBucket(
+		ctx context.Context, arg sqlc.UpsertRateLimitBucketParams,
+	) error
+	ListRateLimitBuckets(
+		ctx context.Context, agentID int64,
+	) ([]sqlc.RateLimitBucket, error)
+	SumSenderBodyBytes(
+		ctx context.Context, arg sqlc.SumSenderBodyBytesParams,
+	) (int64, error)
+
+	// Capability operations.
+	CreateAgentCapability(
+		ctx context.Context, arg sqlc.CreateAgentCapabilityParams,
+	) error
+	DeleteAgentCapabilities(ctx context.Context, agentID int64) error
+	ListAgentCapabilities(
+		ctx context.Context, agentID int64,
+	) ([]sqlc.AgentCapability, error)
+	ListAllAgentCapabilities(
+		ctx context.Context,
+	) ([]sqlc.AgentCapability, error)
+	UpdateAgentMaxConcurrency(
+		ctx context.Context, arg sqlc.UpdateAgentMaxConcurrencyParams,
+	) error
+	ListCapabilityCandidates(
+		ctx context.Context, arg sqlc.ListCapabilityCandidatesParams,
+	) ([]sqlc.ListCapabilityCandidatesRow, error)
+	CreateCapabilityRoute(
+		ctx context.Context, arg sqlc.CreateCapabilityRouteParams,
+	) (sqlc.CapabilityRoute, error)
+	ListCapabilityRoutes(
+		ctx context.Context, arg sqlc.ListCapabilityRoutesParams,
+	) ([]sqlc.ListCapabilityRoutesRow, error)
+	GetLatestTaskRoute(
+		ctx context.Context, taskID sql.NullInt64,
+	) (sqlc.GetLatestTaskRouteRow, error)
+
+	// Thread operations.
+	UpsertThreadPreference(
+		ctx context.Context, arg sqlc.UpsertThreadPreferenceParams,
+	) (sqlc.Thread, error)
+	GetThreadPreference(
+		ctx context.Context, arg sqlc.GetThreadPreferenceParams,
+	) (sqlc.Thread, error)
+	DeleteThreadPreference(
+		ctx context.Context, arg sqlc.DeleteThreadPreferenceParams,
+	) (int64, error)
+	ListThreadPreferences(
+		ctx context.Context, agentID int64,
+	) ([]sqlc.Thread, error)
+	ListThreadPreferencesByThread(
+		ctx context.Context, threadID string,
+	) ([]sqlc.Thread, error)
+	CopyThreadPreferences(
+		ctx context.Context, arg sqlc.CopyThreadPreferencesParams,
+	) error
+	DeleteThreadPreferences(ctx context.Context, threadID string) error
+	CountThreadMessages(ctx context.Context, threadID string) (int64, error)
+	CountThreadReviews(ctx context.Context, threadID string) (int64, error)
+	MoveThreadMessages(
+		ctx context.Context, arg sqlc.MoveThreadMessagesParams,
+	) (int64, error)
+	MoveThreadQuestions(
+		ctx context.Context, arg sqlc.MoveThreadQuestionsParams,
+	) error
+	MoveScheduledThreadMessages(
+		ctx context.Context, arg sqlc.MoveScheduledThreadMessagesParams,
+	) error
+
 	// Plan annotation operations.
 	CreatePlanAnnotation(
 		ctx context.Context, arg sqlc.CreatePlanAnnotationParams,
@@ -493,6 +886,7 @@ func (s *SqlcStore) CreateMessage(ctx context.Context,
 		Priority:       params.Priority,
 		DeadlineAt:     ToSqlcNullInt64(params.DeadlineAt),
 		Attachments:    ToSqlcNullString(params.Attachments),
+		Metadata:       ToSqlcNullString(params.Metadata),
 		CreatedAt:      time.Now().Unix(),
 		IdempotencyKey: ToSqlcNullString(params.IdempotencyKey),
 	})
@@ -551,6 +945,7 @@ func (s *SqlcStore) GetMessagesByThreadWithSender(ctx context.Context,
 				Attachments:     row.Attachments.String,
 				CreatedAt:       time.Unix(row.CreatedAt, 0),
 				DeletedBySender: row.DeletedBySender != 0,
+				EditedAt:        nullInt64ToTime(row.EditedAt),
 			},
 			SenderName:       row.SenderName.String,
 			SenderProjectKey: row.SenderProjectKey.String,
@@ -637,11 +1032,16 @@ func (s *SqlcStore) CountInboxCategories(ctx context.Context,
 	if err != nil {
 		return InboxCategoryCounts{}, err
 	}
+	labels, err := listLabels(ctx, s.db, agentID)
+	if err != nil {
+		return InboxCategoryCounts{}, err
+	}
 	return InboxCategoryCounts{
 		Primary:       row.PrimaryCount,
 		Notifications: row.NotificationsCount,
 		Unread:        row.UnreadCount,
 		Starred:       row.StarredCount,
+		Labels:        labels,
 	}, nil
 }
 
@@ -732,6 +1132,78 @@ func (s *SqlcStore) GetMessageRecipient(ctx context.Context, messageID,
 	return MessageRecipientFromSqlc(row), nil
 }
 
+// PersistRecipientState writes the complete recipient state for a message.
+func (s *SqlcStore) PersistRecipientState(ctx context.Context,
+	params PersistRecipientStateParams,
+) error {
+	return s.db.PersistRecipientState(ctx, sqlc.PersistRecipientStateParams{
+		State:        params.State,
+		ReadAt:       ToSqlcNullInt64(params.ReadAt),
+		AckedAt:      ToSqlcNullInt64(params.AckedAt),
+		SnoozedUntil: ToSqlcNullInt64(params.SnoozedUntil),
+		MessageID:    params.MessageID,
+		AgentID:      params.AgentID,
+	})
+}
+
+// ListSnoozedRecipients lists all snoozed recipients with a pending wake
+// time.
+func (s *SqlcStore) ListSnoozedRecipients(
+	ctx context.Context,
+) ([]SnoozedRecipient, error) {
+	rows, err := s.db.ListSnoozedRecipients(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SnoozedRecipient, len(rows))
+	for i, row := range rows {
+		result[i] = SnoozedRecipient{
+			MessageRecipient: MessageRecipient{
+				MessageID:    row.MessageID,
+				AgentID:      row.AgentID,
+				State:        "snoozed",
+				SnoozedUntil: nullInt64ToTime(row.SnoozedUntil),
+				ReadAt:       nullInt64ToTime(row.ReadAt),
+				AckedAt:      nullInt64ToTime(row.AckedAt),
+			},
+			ThreadID: row.ThreadID,
+		}
+	}
+	return result, nil
+}
+
+// GetOverdueMessages retrieves unacked inbox messages whose deadline has
+// passed, most overdue first.
+func (s *SqlcStore) GetOverdueMessages(ctx context.Context, agentID int64,
+	now time.Time, limit, offset int,
+) ([]InboxMessage, error) {
+	rows, err := s.db.GetOverdueMessages(
+		ctx, sqlc.GetOverdueMessagesParams{
+			AgentID: agentID,
+			Now:     ToSqlcNullInt64(&now),
+			Limit:   int64(limit),
+			Offset:  int64(offset),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return convertOverdueRows(rows), nil
+}
+
+// UpdateMessagePriority changes the priority of a message.
+func (s *SqlcStore) UpdateMessagePriority(ctx context.Context, messageID int64,
+	priority string,
+) error {
+	return s.db.UpdateMessagePriority(
+		ctx, sqlc.UpdateMessagePriorityParams{
+			Priority: priority,
+			ID:       messageID,
+		},
+	)
+}
+
 // CountUnreadByAgent counts unread messages for an agent.
 func (s *SqlcStore) CountUnreadByAgent(ctx context.Context,
 	agentID int64,
@@ -811,6 +1283,12 @@ func (s *SqlcStore) SearchMessagesForAgent(ctx context.Context, query string,
 	}
 	defer rows.Close()
 
+	return scanSearchRows(rows)
+}
+
+// scanSearchRows scans the messages returned by a raw full-text search
+// query.
+func scanSearchRows(rows *sql.Rows) ([]Message, error) {
 	var messages []Message
 	for rows.Next() {
 		var msg Message
@@ -1292,6 +1770,21 @@ func (s *SqlcStore) GetTopicByName(ctx context.Context,
 	return TopicFromSqlc(topic), nil
 }
 
+// UpdateTopicRetention sets how long a topic's messages are kept.
+func (s *SqlcStore) UpdateTopicRetention(ctx context.Context, topicID,
+	retentionSeconds int64,
+) error {
+	return s.db.UpdateTopicRetention(
+		ctx, sqlc.UpdateTopicRetentionParams{
+			RetentionSeconds: sql.NullInt64{
+				Int64: retentionSeconds,
+				Valid: retentionSeconds > 0,
+			},
+			ID: topicID,
+		},
+	)
+}
+
 // GetOrCreateAgentInboxTopic gets or creates an agent's inbox topic.
 func (s *SqlcStore) GetOrCreateAgentInboxTopic(ctx context.Context,
 	agentName string,
@@ -1614,6 +2107,7 @@ func (s *txSqlcStore) CreateMessage(ctx context.Context,
 		Priority:       params.Priority,
 		DeadlineAt:     ToSqlcNullInt64(params.DeadlineAt),
 		Attachments:    ToSqlcNullString(params.Attachments),
+		Metadata:       ToSqlcNullString(params.Metadata),
 		CreatedAt:      time.Now().Unix(),
 		IdempotencyKey: ToSqlcNullString(params.IdempotencyKey),
 	})
@@ -1674,6 +2168,7 @@ func (s *txSqlcStore) GetMessagesByThreadWithSender(ctx context.Context,
 				Attachments:     row.Attachments.String,
 				CreatedAt:       time.Unix(row.CreatedAt, 0),
 				DeletedBySender: row.DeletedBySender != 0,
+				EditedAt:        nullInt64ToTime(row.EditedAt),
 			},
 			SenderName:       row.SenderName.String,
 			SenderProjectKey: row.SenderProjectKey.String,
@@ -1759,11 +2254,16 @@ func (s *txSqlcStore) CountInboxCategories(ctx context.Context,
 	if err != nil {
 		return InboxCategoryCounts{}, err
 	}
+	labels, err := listLabels(ctx, s.queries, agentID)
+	if err != nil {
+		return InboxCategoryCounts{}, err
+	}
 	return InboxCategoryCounts{
 		Primary:       row.PrimaryCount,
 		Notifications: row.NotificationsCount,
 		Unread:        row.UnreadCount,
 		Starred:       row.StarredCount,
+		Labels:        labels,
 	}, nil
 }
 
@@ -1854,6 +2354,78 @@ func (s *txSqlcStore) GetMessageRecipient(ctx context.Context, messageID,
 	return MessageRecipientFromSqlc(row), nil
 }
 
+// PersistRecipientState writes the complete recipient state for a message.
+func (s *txSqlcStore) PersistRecipientState(ctx context.Context,
+	params PersistRecipientStateParams,
+) error {
+	return s.queries.PersistRecipientState(ctx, sqlc.PersistRecipientStateParams{
+		State:        params.State,
+		ReadAt:       ToSqlcNullInt64(params.ReadAt),
+		AckedAt:      ToSqlcNullInt64(params.AckedAt),
+		SnoozedUntil: ToSqlcNullInt64(params.SnoozedUntil),
+		MessageID:    params.MessageID,
+		AgentID:      params.AgentID,
+	})
+}
+
+// ListSnoozedRecipients lists all snoozed recipients with a pending wake
+// time.
+func (s *txSqlcStore) ListSnoozedRecipients(
+	ctx context.Context,
+) ([]SnoozedRecipient, error) {
+	rows, err := s.queries.ListSnoozedRecipients(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SnoozedRecipient, len(rows))
+	for i, row := range rows {
+		result[i] = SnoozedRecipient{
+			MessageRecipient: MessageRecipient{
+				MessageID:    row.MessageID,
+				AgentID:      row.AgentID,
+				State:        "snoozed",
+				SnoozedUntil: nullInt64ToTime(row.SnoozedUntil),
+				ReadAt:       nullInt64ToTime(row.ReadAt),
+				AckedAt:      nullInt64ToTime(row.AckedAt),
+			},
+			ThreadID: row.ThreadID,
+		}
+	}
+	return result, nil
+}
+
+// GetOverdueMessages retrieves unacked inbox messages whose deadline has
+// passed, most overdue first.
+func (s *txSqlcStore) GetOverdueMessages(ctx context.Context, agentID int64,
+	now time.Time, limit, offset int,
+) ([]InboxMessage, error) {
+	rows, err := s.queries.GetOverdueMessages(
+		ctx, sqlc.GetOverdueMessagesParams{
+			AgentID: agentID,
+			Now:     ToSqlcNullInt64(&now),
+			Limit:   int64(limit),
+			Offset:  int64(offset),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return convertOverdueRows(rows), nil
+}
+
+// UpdateMessagePriority changes the priority of a message.
+func (s *txSqlcStore) UpdateMessagePriority(ctx context.Context, messageID int64,
+	priority string,
+) error {
+	return s.queries.UpdateMessagePriority(
+		ctx, sqlc.UpdateMessagePriorityParams{
+			Priority: priority,
+			ID:       messageID,
+		},
+	)
+}
+
 // CountUnreadByAgent counts unread messages for an agent.
 func (s *txSqlcStore) CountUnreadByAgent(ctx context.Context,
 	agentID int64,
@@ -1933,37 +2505,7 @@ func (s *txSqlcStore) SearchMessagesForAgent(ctx context.Context, query string,
 	}
 	defer rows.Close()
 
-	var messages []Message
-	for rows.Next() {
-		var msg Message
-		var deadlineAt sql.NullInt64
-		var attachments sql.NullString
-		var createdAt int64
-
-		err := rows.Scan(
-			&msg.ID, &msg.ThreadID, &msg.TopicID, &msg.LogOffset,
-			&msg.SenderID, &msg.Subject, &msg.Body, &msg.Priority,
-			&deadlineAt, &attachments, &createdAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan search result: %w", err)
-		}
-
-		if deadlineAt.Valid {
-			t := time.Unix(deadlineAt.Int64, 0)
-			msg.DeadlineAt = &t
-		}
-		msg.Attachments = attachments.String
-		msg.CreatedAt = time.Unix(createdAt, 0)
-
-		messages = append(messages, msg)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating search results: %w", err)
-	}
-
-	return messages, nil
+	return scanSearchRows(rows)
 }
 
 // CreateAgent creates a new agent in the database.
@@ -2124,6 +2666,21 @@ func (s *txSqlcStore) GetTopicByName(ctx context.Context,
 	return TopicFromSqlc(topic), nil
 }
 
+// UpdateTopicRetention sets how long a topic's messages are kept.
+func (s *txSqlcStore) UpdateTopicRetention(ctx context.Context, topicID,
+	retentionSeconds int64,
+) error {
+	return s.queries.UpdateTopicRetention(
+		ctx, sqlc.UpdateTopicRetentionParams{
+			RetentionSeconds: sql.NullInt64{
+				Int64: retentionSeconds,
+				Valid: retentionSeconds > 0,
+			},
+			ID: topicID,
+		},
+	)
+}
+
 // GetOrCreateAgentInboxTopic gets or creates an agent's inbox topic.
 func (s *txSqlcStore) GetOrCreateAgentInboxTopic(ctx context.Context,
 	agentName string,
@@ -2713,6 +3270,7 @@ func convertInboxRows(rows []sqlc.GetInboxMessagesRow) []InboxMessage {
 				Body:      row.BodyMd,
 				Priority:  row.Priority,
 				CreatedAt: time.Unix(row.CreatedAt, 0),
+				Metadata:  row.Metadata.String,
 			},
 			SenderName:       row.SenderName.String,
 			SenderProjectKey: row.SenderProjectKey.String,
@@ -2769,6 +3327,15 @@ func convertInboxNotificationsRows(
 	return convertInboxRows(cast)
 }
 
+// convertOverdueRows mirrors convertInboxPrimaryRows for the overdue view.
+func convertOverdueRows(rows []sqlc.GetOverdueMessagesRow) []InboxMessage {
+	cast := make([]sqlc.GetInboxMessagesRow, len(rows))
+	for i, r := range rows {
+		cast[i] = sqlc.GetInboxMessagesRow(r)
+	}
+	return convertInboxRows(cast)
+}
+
 // convertUnreadRows converts sqlc unread rows to domain InboxMessage.
 func convertUnreadRows(rows []sqlc.GetUnreadMessagesRow) []InboxMessage {
 	messages := make([]InboxMessage, len(rows))
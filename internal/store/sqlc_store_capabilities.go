@@ -0,0 +1,355 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// CapabilityStore implementation for SqlcStore
+// =============================================================================
+
+// SetAgentCapabilities replaces an agent's capabilities.
+func (s *SqlcStore) SetAgentCapabilities(ctx context.Context, agentID int64,
+	caps []AgentCapability,
+) error {
+	return setAgentCapabilities(ctx, s.db, agentID, caps)
+}
+
+// ListAgentCapabilities lists an agent's capabilities by kind and name.
+func (s *SqlcStore) ListAgentCapabilities(ctx context.Context,
+	agentID int64,
+) ([]AgentCapability, error) {
+	return listAgentCapabilities(ctx, s.db, agentID)
+}
+
+// ListAllAgentCapabilities lists the capabilities of every agent.
+func (s *SqlcStore) ListAllAgentCapabilities(
+	ctx context.Context,
+) (map[int64][]AgentCapability, error) {
+	return listAllAgentCapabilities(ctx, s.db)
+}
+
+// UpdateAgentMaxConcurrency sets the most tasks an agent takes on at once.
+func (s *SqlcStore) UpdateAgentMaxConcurrency(ctx context.Context,
+	agentID, maxConcurrency int64,
+) error {
+	return s.db.UpdateAgentMaxConcurrency(
+		ctx, sqlc.UpdateAgentMaxConcurrencyParams{
+			MaxConcurrency: maxConcurrency,
+			ID:             agentID,
+		},
+	)
+}
+
+// ListCapabilityCandidates lists the agents with a capability.
+func (s *SqlcStore) ListCapabilityCandidates(ctx context.Context,
+	kind, name string,
+) ([]CapabilityCandidate, error) {
+	return listCapabilityCandidates(ctx, s.db, kind, name)
+}
+
+// CreateCapabilityRoute records the agent picked for a capability.
+func (s *SqlcStore) CreateCapabilityRoute(ctx context.Context,
+	params CreateCapabilityRouteParams,
+) (CapabilityRoute, error) {
+	return createCapabilityRoute(ctx, s.db, params)
+}
+
+// ListCapabilityRoutes lists the newest capability routes.
+func (s *SqlcStore) ListCapabilityRoutes(ctx context.Context,
+	capability string, limit int,
+) ([]CapabilityRoute, error) {
+	return listCapabilityRoutes(ctx, s.db, capability, limit)
+}
+
+// GetLatestTaskRoute retrieves the latest route taken for a task.
+func (s *SqlcStore) GetLatestTaskRoute(ctx context.Context,
+	taskID int64,
+) (CapabilityRoute, error) {
+	return getLatestTaskRoute(ctx, s.db, taskID)
+}
+
+// =============================================================================
+// CapabilityStore implementation for txSqlcStore
+// =============================================================================
+
+// SetAgentCapabilities replaces an agent's capabilities.
+func (s *txSqlcStore) SetAgentCapabilities(ctx context.Context,
+	agentID int64, caps []AgentCapability,
+) error {
+	return setAgentCapabilities(ctx, s.queries, agentID, caps)
+}
+
+// ListAgentCapabilities lists an agent's capabilities by kind and name.
+func (s *txSqlcStore) ListAgentCapabilities(ctx context.Context,
+	agentID int64,
+) ([]AgentCapability, error) {
+	return listAgentCapabilities(ctx, s.queries, agentID)
+}
+
+// ListAllAgentCapabilities lists the capabilities of every agent.
+func (s *txSqlcStore) ListAllAgentCapabilities(
+	ctx context.Context,
+) (map[int64][]AgentCapability, error) {
+	return listAllAgentCapabilities(ctx, s.queries)
+}
+
+// UpdateAgentMaxConcurrency sets the most tasks an agent takes on at once.
+func (s *txSqlcStore) UpdateAgentMaxConcurrency(ctx context.Context,
+	agentID, maxConcurrency int64,
+) error {
+	return s.queries.UpdateAgentMaxConcurrency(
+		ctx, sqlc.UpdateAgentMaxConcurrencyParams{
+			MaxConcurrency: maxConcurrency,
+			ID:             agentID,
+		},
+	)
+}
+
+// ListCapabilityCandidates lists the agents with a capability.
+func (s *txSqlcStore) ListCapabilityCandidates(ctx context.Context,
+	kind, name string,
+) ([]CapabilityCandidate, error) {
+	return listCapabilityCandidates(ctx, s.queries, kind, name)
+}
+
+// CreateCapabilityRoute records the agent picked for a capability.
+func (s *txSqlcStore) CreateCapabilityRoute(ctx context.Context,
+	params CreateCapabilityRouteParams,
+) (CapabilityRoute, error) {
+	return createCapabilityRoute(ctx, s.queries, params)
+}
+
+// ListCapabilityRoutes lists the newest capability routes.
+func (s *txSqlcStore) ListCapabilityRoutes(ctx context.Context,
+	capability string, limit int,
+) ([]CapabilityRoute, error) {
+	return listCapabilityRoutes(ctx, s.queries, capability, limit)
+}
+
+// GetLatestTaskRoute retrieves the latest route taken for a task.
+func (s *txSqlcStore) GetLatestTaskRoute(ctx context.Context,
+	taskID int64,
+) (CapabilityRoute, error) {
+	return getLatestTaskRoute(ctx, s.queries, taskID)
+}
+
+// =============================================================================
+// Helper functions
+// =============================================================================
+
+// setAgentCapabilities deletes an agent's capabilities and inserts the new
+// set. Callers outside a transaction may see the agent with none in between.
+func setAgentCapabilities(ctx context.Context, q QueryStore, agentID int64,
+	caps []AgentCapability,
+) error {
+	if err := q.DeleteAgentCapabilities(ctx, agentID); err != nil {
+		return err
+	}
+
+	for _, c := range caps {
+		err := q.CreateAgentCapability(
+			ctx, sqlc.CreateAgentCapabilityParams{
+				AgentID: agentID,
+				Kind:    c.Kind,
+				Name:    c.Name,
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listAgentCapabilities lists an agent's capabilities.
+func listAgentCapabilities(ctx context.Context, q QueryStore,
+	agentID int64,
+) ([]AgentCapability, error) {
+	rows, err := q.ListAgentCapabilities(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	caps := make([]AgentCapability, len(rows))
+	for i, row := range rows {
+		caps[i] = AgentCapability{Kind: row.Kind, Name: row.Name}
+	}
+
+	return caps, nil
+}
+
+// listAllAgentCapabilities lists every agent's capabilities by agent ID.
+func listAllAgentCapabilities(ctx context.Context,
+	q QueryStore,
+) (map[int64][]AgentCapability, error) {
+	rows, err := q.ListAllAgentCapabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	caps := make(map[int64][]AgentCapability)
+	for _, row := range rows {
+		caps[row.AgentID] = append(caps[row.AgentID], AgentCapability{
+			Kind: row.Kind,
+			Name: row.Name,
+		})
+	}
+
+	return caps, nil
+}
+
+// listCapabilityCandidates lists the agents with a capability and their
+// load.
+func listCapabilityCandidates(ctx context.Context, q QueryStore,
+	kind, name string,
+) ([]CapabilityCandidate, error) {
+	rows, err := q.ListCapabilityCandidates(
+		ctx, sqlc.ListCapabilityCandidatesParams{
+			Name: name,
+			Kind: kind,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]CapabilityCandidate, len(rows))
+	for i, r := range rows {
+		candidates[i] = CapabilityCandidate{
+			Agent: Agent{
+				ID:               r.ID,
+				Name:             r.Name,
+				ProjectKey:       r.ProjectKey.String,
+				GitBranch:        r.GitBranch.String,
+				CurrentSessionID: r.CurrentSessionID.String,
+				CreatedAt:        time.Unix(r.CreatedAt, 0),
+				LastActiveAt:     time.Unix(r.LastActiveAt, 0),
+				Purpose:          r.Purpose.String,
+				WorkingDir:       r.WorkingDir.String,
+				Hostname:         r.Hostname.String,
+				MaxConcurrency:   r.MaxConcurrency,
+			},
+			UnreadCount:     r.UnreadCount,
+			InProgressCount: r.InProgressCount,
+		}
+	}
+
+	return candidates, nil
+}
+
+// createCapabilityRoute inserts a capability route.
+func createCapabilityRoute(ctx context.Context, q QueryStore,
+	params CreateCapabilityRouteParams,
+) (CapabilityRoute, error) {
+	row, err := q.CreateCapabilityRoute(
+		ctx, sqlc.CreateCapabilityRouteParams{
+			Capability:      params.Capability,
+			AgentID:         params.AgentID,
+			MessageID:       toNullInt64(params.MessageID),
+			TaskID:          toNullInt64(params.TaskID),
+			Candidates:      params.Candidates,
+			UnreadCount:     params.UnreadCount,
+			InProgressCount: params.InProgressCount,
+			CreatedAt:       time.Now().Unix(),
+		},
+	)
+	if err != nil {
+		return CapabilityRoute{}, err
+	}
+
+	agent, err := q.GetAgent(ctx, row.AgentID)
+	if err != nil {
+		return CapabilityRoute{}, err
+	}
+
+	route := capabilityRouteFromSqlc(sqlc.GetLatestTaskRouteRow{
+		ID:              row.ID,
+		Capability:      row.Capability,
+		AgentID:         row.AgentID,
+		MessageID:       row.MessageID,
+		TaskID:          row.TaskID,
+		Candidates:      row.Candidates,
+		UnreadCount:     row.UnreadCount,
+		InProgressCount: row.InProgressCount,
+		CreatedAt:       row.CreatedAt,
+		AgentName:       agent.Name,
+	})
+
+	return route, nil
+}
+
+// listCapabilityRoutes lists the newest capability routes.
+func listCapabilityRoutes(ctx context.Context, q QueryStore,
+	capability string, limit int,
+) ([]CapabilityRoute, error) {
+	rows, err := q.ListCapabilityRoutes(
+		ctx, sqlc.ListCapabilityRoutesParams{
+			Capability: capability,
+			MaxResults: int64(limit),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := make([]CapabilityRoute, len(rows))
+	for i, row := range rows {
+		routes[i] = capabilityRouteFromSqlc(
+			sqlc.GetLatestTaskRouteRow(row),
+		)
+	}
+
+	return routes, nil
+}
+
+// getLatestTaskRoute fetches the latest route taken for a task.
+func getLatestTaskRoute(ctx context.Context, q QueryStore,
+	taskID int64,
+) (CapabilityRoute, error) {
+	row, err := q.GetLatestTaskRoute(
+		ctx, sql.NullInt64{Int64: taskID, Valid: true},
+	)
+	if err != nil {
+		return CapabilityRoute{}, err
+	}
+
+	return capabilityRouteFromSqlc(row), nil
+}
+
+// capabilityRouteFromSqlc converts a sqlc capability route row joined with
+// its agent's name.
+func capabilityRouteFromSqlc(row sqlc.GetLatestTaskRouteRow) CapabilityRoute {
+	route := CapabilityRoute{
+		ID:              row.ID,
+		Capability:      row.Capability,
+		AgentID:         row.AgentID,
+		AgentName:       row.AgentName,
+		Candidates:      row.Candidates,
+		UnreadCount:     row.UnreadCount,
+		InProgressCount: row.InProgressCount,
+		CreatedAt:       time.Unix(row.CreatedAt, 0),
+	}
+	if row.MessageID.Valid {
+		route.MessageID = &row.MessageID.Int64
+	}
+	if row.TaskID.Valid {
+		route.TaskID = &row.TaskID.Int64
+	}
+
+	return route
+}
+
+// toNullInt64 converts an optional ID to a sql.NullInt64.
+func toNullInt64(v *int64) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: *v, Valid: true}
+}
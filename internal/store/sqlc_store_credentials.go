@@ -0,0 +1,193 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// CredentialStore implementation for SqlcStore
+// =============================================================================
+
+// CreateCredential stores a new credential.
+func (s *SqlcStore) CreateCredential(ctx context.Context,
+	params CreateCredentialParams,
+) (Credential, error) {
+	return createCredential(ctx, s.db, params)
+}
+
+// GetCredentialByHash retrieves a credential by the hash of its token.
+func (s *SqlcStore) GetCredentialByHash(ctx context.Context,
+	tokenHash string,
+) (Credential, error) {
+	return getCredentialByHash(ctx, s.db, tokenHash)
+}
+
+// ListCredentials lists credentials, newest first.
+func (s *SqlcStore) ListCredentials(ctx context.Context, agentID int64,
+	includeRevoked bool,
+) ([]Credential, error) {
+	return listCredentials(ctx, s.db, agentID, includeRevoked)
+}
+
+// RevokeCredential revokes a credential.
+func (s *SqlcStore) RevokeCredential(ctx context.Context, id int64,
+	revokedAt time.Time,
+) (bool, error) {
+	return revokeCredential(ctx, s.db, id, revokedAt)
+}
+
+// TouchCredential records when a credential was last used.
+func (s *SqlcStore) TouchCredential(ctx context.Context, id int64,
+	usedAt time.Time,
+) error {
+	return touchCredential(ctx, s.db, id, usedAt)
+}
+
+// =============================================================================
+// CredentialStore implementation for txSqlcStore
+// =============================================================================
+
+// CreateCredential stores a new credential.
+func (s *txSqlcStore) CreateCredential(ctx context.Context,
+	params CreateCredentialParams,
+) (Credential, error) {
+	return createCredential(ctx, s.queries, params)
+}
+
+// GetCredentialByHash retrieves a credential by the hash of its token.
+func (s *txSqlcStore) GetCredentialByHash(ctx context.Context,
+	tokenHash string,
+) (Credential, error) {
+	return getCredentialByHash(ctx, s.queries, tokenHash)
+}
+
+// ListCredentials lists credentials, newest first.
+func (s *txSqlcStore) ListCredentials(ctx context.Context, agentID int64,
+	includeRevoked bool,
+) ([]Credential, error) {
+	return listCredentials(ctx, s.queries, agentID, includeRevoked)
+}
+
+// RevokeCredential revokes a credential.
+func (s *txSqlcStore) RevokeCredential(ctx context.Context, id int64,
+	revokedAt time.Time,
+) (bool, error) {
+	return revokeCredential(ctx, s.queries, id, revokedAt)
+}
+
+// TouchCredential records when a credential was last used.
+func (s *txSqlcStore) TouchCredential(ctx context.Context, id int64,
+	usedAt time.Time,
+) error {
+	return touchCredential(ctx, s.queries, id, usedAt)
+}
+
+// =============================================================================
+// Helper functions
+// =============================================================================
+
+// createCredential inserts a credential and returns it as stored.
+func createCredential(ctx context.Context, q QueryStore,
+	params CreateCredentialParams,
+) (Credential, error) {
+	var agentID sql.NullInt64
+	if params.AgentID != 0 {
+		agentID = sql.NullInt64{Int64: params.AgentID, Valid: true}
+	}
+
+	row, err := q.CreateCredential(ctx, sqlc.CreateCredentialParams{
+		AgentID:        agentID,
+		Name:           params.Name,
+		TokenHash:      params.TokenHash,
+		AllowedMethods: strings.Join(params.AllowedMethods, ","),
+		ExpiresAt:      ToSqlcNullInt64(params.ExpiresAt),
+		CreatedAt:      time.Now().Unix(),
+	})
+	if err != nil {
+		return Credential{}, err
+	}
+
+	// Read it back for the agent's name.
+	return getCredentialByHash(ctx, q, row.TokenHash)
+}
+
+// getCredentialByHash fetches a credential by the hash of its token.
+func getCredentialByHash(ctx context.Context, q QueryStore,
+	tokenHash string,
+) (Credential, error) {
+	row, err := q.GetCredentialByHash(ctx, tokenHash)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	return credentialFromSqlc(sqlc.ListCredentialsRow(row)), nil
+}
+
+// listCredentials lists credentials, newest first.
+func listCredentials(ctx context.Context, q QueryStore, agentID int64,
+	includeRevoked bool,
+) ([]Credential, error) {
+	params := sqlc.ListCredentialsParams{AgentID: agentID}
+	if includeRevoked {
+		params.IncludeRevoked = 1
+	}
+
+	rows, err := q.ListCredentials(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := make([]Credential, len(rows))
+	for i, row := range rows {
+		creds[i] = credentialFromSqlc(row)
+	}
+
+	return creds, nil
+}
+
+// revokeCredential revokes a credential that isn't revoked yet.
+func revokeCredential(ctx context.Context, q QueryStore, id int64,
+	revokedAt time.Time,
+) (bool, error) {
+	n, err := q.RevokeCredential(ctx, sqlc.RevokeCredentialParams{
+		RevokedAt: sql.NullInt64{Int64: revokedAt.Unix(), Valid: true},
+		ID:        id,
+	})
+	return n > 0, err
+}
+
+// touchCredential records when a credential was last used.
+func touchCredential(ctx context.Context, q QueryStore, id int64,
+	usedAt time.Time,
+) error {
+	return q.TouchCredential(ctx, sqlc.TouchCredentialParams{
+		LastUsedAt: sql.NullInt64{Int64: usedAt.Unix(), Valid: true},
+		ID:         id,
+	})
+}
+
+// credentialFromSqlc converts a sqlc credential row.
+func credentialFromSqlc(row sqlc.ListCredentialsRow) Credential {
+	var methods []string
+	if row.AllowedMethods != "" {
+		methods = strings.Split(row.AllowedMethods, ",")
+	}
+
+	return Credential{
+		ID:             row.ID,
+		AgentID:        row.AgentID.Int64,
+		AgentName:      row.AgentName,
+		Name:           row.Name,
+		TokenHash:      row.TokenHash,
+		AllowedMethods: methods,
+		ExpiresAt:      nullInt64ToTime(row.ExpiresAt),
+		CreatedAt:      time.Unix(row.CreatedAt, 0),
+		LastUsedAt:     nullInt64ToTime(row.LastUsedAt),
+		RevokedAt:      nullInt64ToTime(row.RevokedAt),
+	}
+}
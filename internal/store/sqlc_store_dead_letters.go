@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// DeadLetterStore implementation for SqlcStore
+// =============================================================================
+
+// CreateDeadLetter records an undeliverable addressee of a message.
+func (s *SqlcStore) CreateDeadLetter(ctx context.Context,
+	params CreateDeadLetterParams,
+) error {
+	return s.db.CreateDeadLetter(ctx, createDeadLetterParams(params))
+}
+
+// ListDeadLetters lists a sender's dead letters, newest first.
+func (s *SqlcStore) ListDeadLetters(ctx context.Context, senderID int64,
+	limit int,
+) ([]DeadLetter, error) {
+	rows, err := s.db.ListDeadLetters(ctx, sqlc.ListDeadLettersParams{
+		SenderID: senderID,
+		Limit:    int64(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deadLettersFromSqlc(rows), nil
+}
+
+// DeleteDeadLetter deletes one of a sender's dead letters.
+func (s *SqlcStore) DeleteDeadLetter(ctx context.Context, id,
+	senderID int64,
+) (bool, error) {
+	n, err := s.db.DeleteDeadLetter(ctx, sqlc.DeleteDeadLetterParams{
+		ID:       id,
+		SenderID: senderID,
+	})
+	return n > 0, err
+}
+
+// =============================================================================
+// DeadLetterStore implementation for txSqlcStore
+// =============================================================================
+
+// CreateDeadLetter records an undeliverable addressee of a message.
+func (s *txSqlcStore) CreateDeadLetter(ctx context.Context,
+	params CreateDeadLetterParams,
+) error {
+	return s.queries.CreateDeadLetter(ctx, createDeadLetterParams(params))
+}
+
+// ListDeadLetters lists a sender's dead letters, newest first.
+func (s *txSqlcStore) ListDeadLetters(ctx context.Context, senderID int64,
+	limit int,
+) ([]DeadLetter, error) {
+	rows, err := s.queries.ListDeadLetters(
+		ctx, sqlc.ListDeadLettersParams{
+			SenderID: senderID,
+			Limit:    int64(limit),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return deadLettersFromSqlc(rows), nil
+}
+
+// DeleteDeadLetter deletes one of a sender's dead letters.
+func (s *txSqlcStore) DeleteDeadLetter(ctx context.Context, id,
+	senderID int64,
+) (bool, error) {
+	n, err := s.queries.DeleteDeadLetter(
+		ctx, sqlc.DeleteDeadLetterParams{
+			ID:       id,
+			SenderID: senderID,
+		},
+	)
+	return n > 0, err
+}
+
+// =============================================================================
+// Conversion helpers
+// =============================================================================
+
+// createDeadLetterParams converts dead letter params to their sqlc form,
+// stamping the creation time.
+func createDeadLetterParams(
+	params CreateDeadLetterParams,
+) sqlc.CreateDeadLetterParams {
+	return sqlc.CreateDeadLetterParams{
+		MessageID: params.MessageID,
+		SenderID:  params.SenderID,
+		Recipient: params.Recipient,
+		Status:    params.Status,
+		Reason:    params.Reason,
+		CreatedAt: time.Now().Unix(),
+	}
+}
+
+// deadLettersFromSqlc converts sqlc dead letter rows to domain dead letters.
+func deadLettersFromSqlc(rows []sqlc.ListDeadLettersRow) []DeadLetter {
+	letters := make([]DeadLetter, len(rows))
+	for i, r := range rows {
+		letters[i] = DeadLetter{
+			ID:        r.ID,
+			MessageID: r.MessageID,
+			ThreadID:  r.ThreadID,
+			Subject:   r.Subject,
+			SenderID:  r.SenderID,
+			Recipient: r.Recipient,
+			Status:    r.Status,
+			Reason:    r.Reason,
+			CreatedAt: time.Unix(r.CreatedAt, 0),
+		}
+	}
+
+	return letters
+}
@@ -0,0 +1,192 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+	"github.com/roasbeef/subtrate/internal/embed"
+)
+
+// =============================================================================
+// EmbeddingStore implementation for SqlcStore
+// =============================================================================
+
+// UpsertMessageEmbedding stores a message's embedding under a model.
+func (s *SqlcStore) UpsertMessageEmbedding(ctx context.Context,
+	params UpsertMessageEmbeddingParams,
+) error {
+	return upsertMessageEmbedding(ctx, s.db, params)
+}
+
+// GetMessageEmbedding retrieves a message's embedding under a model.
+func (s *SqlcStore) GetMessageEmbedding(ctx context.Context, messageID int64,
+	model string,
+) (MessageEmbedding, error) {
+	return getMessageEmbedding(ctx, s.db, messageID, model)
+}
+
+// ListMessagesToEmbed lists the oldest messages without an embedding under a
+// model.
+func (s *SqlcStore) ListMessagesToEmbed(ctx context.Context, model string,
+	limit int,
+) ([]Message, error) {
+	return listMessagesToEmbed(ctx, s.db, model, limit)
+}
+
+// ListMessageEmbeddings lists the embeddings under a model of the messages a
+// search's filters keep.
+func (s *SqlcStore) ListMessageEmbeddings(ctx context.Context, model string,
+	params MessageSearchParams,
+) ([]MessageEmbedding, error) {
+	return listMessageEmbeddings(ctx, s.sqlDB, model, params)
+}
+
+// =============================================================================
+// EmbeddingStore implementation for txSqlcStore
+// =============================================================================
+
+// UpsertMessageEmbedding stores a message's embedding under a model.
+func (s *txSqlcStore) UpsertMessageEmbedding(ctx context.Context,
+	params UpsertMessageEmbeddingParams,
+) error {
+	return upsertMessageEmbedding(ctx, s.queries, params)
+}
+
+// GetMessageEmbedding retrieves a message's embedding under a model.
+func (s *txSqlcStore) GetMessageEmbedding(ctx context.Context,
+	messageID int64, model string,
+) (MessageEmbedding, error) {
+	return getMessageEmbedding(ctx, s.queries, messageID, model)
+}
+
+// ListMessagesToEmbed lists the oldest messages without an embedding under a
+// model.
+func (s *txSqlcStore) ListMessagesToEmbed(ctx context.Context, model string,
+	limit int,
+) ([]Message, error) {
+	return listMessagesToEmbed(ctx, s.queries, model, limit)
+}
+
+// ListMessageEmbeddings lists the embeddings under a model of the messages a
+// search's filters keep.
+func (s *txSqlcStore) ListMessageEmbeddings(ctx context.Context,
+	model string, params MessageSearchParams,
+) ([]MessageEmbedding, error) {
+	return listMessageEmbeddings(ctx, s.sqlDB, model, params)
+}
+
+// =============================================================================
+// Helper functions
+// =============================================================================
+
+// upsertMessageEmbedding stores a message's embedding.
+func upsertMessageEmbedding(ctx context.Context, q QueryStore,
+	params UpsertMessageEmbeddingParams,
+) error {
+	return q.UpsertMessageEmbedding(ctx, sqlc.UpsertMessageEmbeddingParams{
+		MessageID:  params.MessageID,
+		Model:      params.Model,
+		Dimensions: int64(len(params.Vector)),
+		Embedding:  embed.Encode(params.Vector),
+		CreatedAt:  time.Now().Unix(),
+	})
+}
+
+// getMessageEmbedding retrieves and decodes a message's embedding.
+func getMessageEmbedding(ctx context.Context, q QueryStore, messageID int64,
+	model string,
+) (MessageEmbedding, error) {
+	row, err := q.GetMessageEmbedding(ctx, sqlc.GetMessageEmbeddingParams{
+		MessageID: messageID,
+		Model:     model,
+	})
+	if err != nil {
+		return MessageEmbedding{}, err
+	}
+
+	vector, err := embed.Decode(row.Embedding)
+	if err != nil {
+		return MessageEmbedding{}, err
+	}
+
+	return MessageEmbedding{
+		MessageID: row.MessageID,
+		Model:     row.Model,
+		Vector:    vector,
+		CreatedAt: time.Unix(row.CreatedAt, 0),
+	}, nil
+}
+
+// listMessagesToEmbed lists the messages still to be embedded under a model.
+func listMessagesToEmbed(ctx context.Context, q QueryStore, model string,
+	limit int,
+) ([]Message, error) {
+	rows, err := q.ListMessagesToEmbed(ctx, sqlc.ListMessagesToEmbedParams{
+		Model: model,
+		Limit: int64(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]Message, len(rows))
+	for i, row := range rows {
+		messages[i] = MessageFromSqlc(row)
+	}
+
+	return messages, nil
+}
+
+// listMessageEmbeddings builds and runs the query behind
+// ListMessageEmbeddings, reusing the search filters' clauses.
+func listMessageEmbeddings(ctx context.Context, db *sql.DB, model string,
+	p MessageSearchParams,
+) ([]MessageEmbedding, error) {
+	p.Match = ""
+	from, args := messageSearchFrom(p)
+
+	query := `
+		SELECT e.message_id, e.embedding, e.created_at
+		FROM message_embeddings e
+		WHERE e.model = ? AND e.message_id IN (
+			SELECT m.id ` + from + `
+		)`
+
+	args = append([]any{model}, args...)
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	var embeddings []MessageEmbedding
+	for rows.Next() {
+		var (
+			e         MessageEmbedding
+			blob      []byte
+			createdAt int64
+		)
+		err := rows.Scan(&e.MessageID, &blob, &createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan embedding: %w",
+				err)
+		}
+
+		e.Vector, err = embed.Decode(blob)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", e.MessageID,
+				err)
+		}
+		e.Model = model
+		e.CreatedAt = time.Unix(createdAt, 0)
+		embeddings = append(embeddings, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating embeddings: %w", err)
+	}
+
+	return embeddings, nil
+}
@@ -0,0 +1,169 @@
+package store
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// EscalationStore implementation for SqlcStore
+// =============================================================================
+
+// ListOverdueRecipients lists recipients of unacked messages whose next
+// escalation level is due.
+func (s *SqlcStore) ListOverdueRecipients(ctx context.Context, now time.Time,
+	levelDelays []time.Duration, limit int,
+) ([]OverdueRecipient, error) {
+	rows, err := s.db.ListOverdueRecipients(
+		ctx, sqlc.ListOverdueRecipientsParams{
+			Now:         ToSqlcNullInt64(&now),
+			LevelDelays: levelDelaysJSON(levelDelays),
+			MaxResults:  int64(limit),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return overdueRecipientsFromSqlc(rows), nil
+}
+
+// GetMessageEscalation retrieves the escalation progress for a recipient.
+func (s *SqlcStore) GetMessageEscalation(ctx context.Context,
+	messageID, agentID int64,
+) (MessageEscalation, error) {
+	row, err := s.db.GetMessageEscalation(
+		ctx, sqlc.GetMessageEscalationParams{
+			MessageID: messageID,
+			AgentID:   agentID,
+		},
+	)
+	if err != nil {
+		return MessageEscalation{}, err
+	}
+
+	return MessageEscalationFromSqlc(row), nil
+}
+
+// UpsertMessageEscalation records the escalation level reached for a
+// recipient.
+func (s *SqlcStore) UpsertMessageEscalation(ctx context.Context,
+	params UpsertMessageEscalationParams,
+) error {
+	return s.db.UpsertMessageEscalation(
+		ctx, sqlc.UpsertMessageEscalationParams{
+			MessageID:       params.MessageID,
+			AgentID:         params.AgentID,
+			Level:           int64(params.Level),
+			LastEscalatedAt: time.Now().Unix(),
+		},
+	)
+}
+
+// levelDelaysJSON encodes escalation level delays as the JSON array of whole
+// seconds ListOverdueRecipients expects.
+func levelDelaysJSON(levelDelays []time.Duration) string {
+	seconds := make([]string, len(levelDelays))
+	for i, delay := range levelDelays {
+		seconds[i] = strconv.FormatInt(int64(delay/time.Second), 10)
+	}
+
+	return "[" + strings.Join(seconds, ",") + "]"
+}
+
+// =============================================================================
+// EscalationStore implementation for txSqlcStore
+// =============================================================================
+
+// ListOverdueRecipients lists recipients of unacked messages whose next
+// escalation level is due.
+func (s *txSqlcStore) ListOverdueRecipients(ctx context.Context, now time.Time,
+	levelDelays []time.Duration, limit int,
+) ([]OverdueRecipient, error) {
+	rows, err := s.queries.ListOverdueRecipients(
+		ctx, sqlc.ListOverdueRecipientsParams{
+			Now:         ToSqlcNullInt64(&now),
+			LevelDelays: levelDelaysJSON(levelDelays),
+			MaxResults:  int64(limit),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return overdueRecipientsFromSqlc(rows), nil
+}
+
+// GetMessageEscalation retrieves the escalation progress for a recipient.
+func (s *txSqlcStore) GetMessageEscalation(ctx context.Context,
+	messageID, agentID int64,
+) (MessageEscalation, error) {
+	row, err := s.queries.GetMessageEscalation(
+		ctx, sqlc.GetMessageEscalationParams{
+			MessageID: messageID,
+			AgentID:   agentID,
+		},
+	)
+	if err != nil {
+		return MessageEscalation{}, err
+	}
+
+	return MessageEscalationFromSqlc(row), nil
+}
+
+// UpsertMessageEscalation records the escalation level reached for a
+// recipient.
+func (s *txSqlcStore) UpsertMessageEscalation(ctx context.Context,
+	params UpsertMessageEscalationParams,
+) error {
+	return s.queries.UpsertMessageEscalation(
+		ctx, sqlc.UpsertMessageEscalationParams{
+			MessageID:       params.MessageID,
+			AgentID:         params.AgentID,
+			Level:           int64(params.Level),
+			LastEscalatedAt: time.Now().Unix(),
+		},
+	)
+}
+
+// =============================================================================
+// Conversion helpers
+// =============================================================================
+
+// MessageEscalationFromSqlc converts a sqlc.MessageEscalation to a
+// MessageEscalation.
+func MessageEscalationFromSqlc(e sqlc.MessageEscalation) MessageEscalation {
+	return MessageEscalation{
+		MessageID:       e.MessageID,
+		AgentID:         e.AgentID,
+		Level:           int(e.Level),
+		LastEscalatedAt: time.Unix(e.LastEscalatedAt, 0),
+	}
+}
+
+// overdueRecipientsFromSqlc converts overdue recipient rows to their domain
+// type.
+func overdueRecipientsFromSqlc(
+	rows []sqlc.ListOverdueRecipientsRow,
+) []OverdueRecipient {
+	result := make([]OverdueRecipient, len(rows))
+	for i, row := range rows {
+		result[i] = OverdueRecipient{
+			MessageID:       row.MessageID,
+			AgentID:         row.AgentID,
+			State:           row.State,
+			ThreadID:        row.ThreadID,
+			SenderID:        row.SenderID,
+			Subject:         row.Subject,
+			Priority:        row.Priority,
+			DeadlineAt:      time.Unix(row.DeadlineAt.Int64, 0),
+			EscalationLevel: int(row.EscalationLevel),
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,404 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// RecipientGroupStore implementation for SqlcStore
+// =============================================================================
+
+// CreateRecipientGroup creates a new, empty recipient group.
+func (s *SqlcStore) CreateRecipientGroup(ctx context.Context,
+	params CreateRecipientGroupParams,
+) (RecipientGroup, error) {
+	var createdBy sql.NullInt64
+	if params.CreatedBy != nil {
+		createdBy = sql.NullInt64{Int64: *params.CreatedBy, Valid: true}
+	}
+
+	group, err := s.db.CreateRecipientGroup(
+		ctx, sqlc.CreateRecipientGroupParams{
+			Name:        params.Name,
+			Description: params.Description,
+			CreatedBy:   createdBy,
+			CreatedAt:   time.Now().Unix(),
+		},
+	)
+	if err != nil {
+		return RecipientGroup{}, err
+	}
+
+	return RecipientGroupFromSqlc(group), nil
+}
+
+// GetRecipientGroupByName retrieves a recipient group by its name.
+func (s *SqlcStore) GetRecipientGroupByName(ctx context.Context,
+	name string,
+) (RecipientGroup, error) {
+	group, err := s.db.GetRecipientGroupByName(ctx, name)
+	if err != nil {
+		return RecipientGroup{}, err
+	}
+
+	return RecipientGroupFromSqlc(group), nil
+}
+
+// ListRecipientGroups lists all recipient groups with their member counts.
+func (s *SqlcStore) ListRecipientGroups(
+	ctx context.Context,
+) ([]RecipientGroup, error) {
+	rows, err := s.db.ListRecipientGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return recipientGroupsFromSqlc(rows), nil
+}
+
+// DeleteRecipientGroup deletes a recipient group and its memberships.
+func (s *SqlcStore) DeleteRecipientGroup(ctx context.Context,
+	id int64,
+) (bool, error) {
+	n, err := s.db.DeleteRecipientGroup(ctx, id)
+	return n > 0, err
+}
+
+// AddRecipientGroupMember adds an agent to a group.
+func (s *SqlcStore) AddRecipientGroupMember(ctx context.Context, groupID,
+	agentID int64,
+) error {
+	return s.db.AddRecipientGroupMember(
+		ctx, sqlc.AddRecipientGroupMemberParams{
+			GroupID: groupID,
+			AgentID: agentID,
+			AddedAt: time.Now().Unix(),
+		},
+	)
+}
+
+// RemoveRecipientGroupMember removes an agent from a group.
+func (s *SqlcStore) RemoveRecipientGroupMember(ctx context.Context, groupID,
+	agentID int64,
+) (bool, error) {
+	n, err := s.db.RemoveRecipientGroupMember(
+		ctx, sqlc.RemoveRecipientGroupMemberParams{
+			GroupID: groupID,
+			AgentID: agentID,
+		},
+	)
+	return n > 0, err
+}
+
+// ListRecipientGroupMembers lists the agents in a group by name.
+func (s *SqlcStore) ListRecipientGroupMembers(ctx context.Context,
+	groupID int64,
+) ([]Agent, error) {
+	return agentsFromSqlc(s.db.ListRecipientGroupMembers(ctx, groupID))
+}
+
+// ListAgentsMatchingProject lists agents whose project key equals the given
+// key or is a path ending in it.
+func (s *SqlcStore) ListAgentsMatchingProject(ctx context.Context,
+	project string,
+) ([]Agent, error) {
+	return agentsFromSqlc(s.db.ListAgentsMatchingProject(
+		ctx, ToSqlcNullString(project),
+	))
+}
+
+// ListAgentsByBranch lists agents working on the given git branch.
+func (s *SqlcStore) ListAgentsByBranch(ctx context.Context,
+	branch string,
+) ([]Agent, error) {
+	return agentsFromSqlc(s.db.ListAgentsByBranch(
+		ctx, ToSqlcNullString(branch),
+	))
+}
+
+// ListAgentsActiveSince lists agents whose last heartbeat is at or after the
+// given time.
+func (s *SqlcStore) ListAgentsActiveSince(ctx context.Context,
+	since time.Time,
+) ([]Agent, error) {
+	return agentsFromSqlc(s.db.ListAgentsActiveSince(ctx, since.Unix()))
+}
+
+// ListAgentsByNamePrefix lists agents whose name starts with the given
+// prefix.
+func (s *SqlcStore) ListAgentsByNamePrefix(ctx context.Context,
+	prefix string,
+) ([]Agent, error) {
+	return agentsFromSqlc(s.db.ListAgentsByNamePattern(
+		ctx, likePrefixPattern(prefix),
+	))
+}
+
+// CreateRecipientExpansion records that a group or alias in a message's
+// recipient list resolved to the given agent.
+func (s *SqlcStore) CreateRecipientExpansion(ctx context.Context,
+	messageID int64, address string, agentID int64,
+) error {
+	return s.db.CreateRecipientExpansion(
+		ctx, sqlc.CreateRecipientExpansionParams{
+			MessageID: messageID,
+			Address:   address,
+			AgentID:   agentID,
+		},
+	)
+}
+
+// ListRecipientExpansions lists the recorded expansions of a message's
+// recipient groups and aliases.
+func (s *SqlcStore) ListRecipientExpansions(ctx context.Context,
+	messageID int64,
+) ([]RecipientExpansion, error) {
+	rows, err := s.db.ListRecipientExpansions(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	expansions := make([]RecipientExpansion, len(rows))
+	for i, row := range rows {
+		expansions[i] = RecipientExpansion{
+			Address:   row.Address,
+			AgentID:   row.AgentID,
+			AgentName: row.AgentName,
+		}
+	}
+
+	return expansions, nil
+}
+
+// =============================================================================
+// RecipientGroupStore implementation for txSqlcStore
+// =============================================================================
+
+// CreateRecipientGroup creates a new, empty recipient group.
+func (s *txSqlcStore) CreateRecipientGroup(ctx context.Context,
+	params CreateRecipientGroupParams,
+) (RecipientGroup, error) {
+	var createdBy sql.NullInt64
+	if params.CreatedBy != nil {
+		createdBy = sql.NullInt64{Int64: *params.CreatedBy, Valid: true}
+	}
+
+	group, err := s.queries.CreateRecipientGroup(
+		ctx, sqlc.CreateRecipientGroupParams{
+			Name:        params.Name,
+			Description: params.Description,
+			CreatedBy:   createdBy,
+			CreatedAt:   time.Now().Unix(),
+		},
+	)
+	if err != nil {
+		return RecipientGroup{}, err
+	}
+
+	return RecipientGroupFromSqlc(group), nil
+}
+
+// GetRecipientGroupByName retrieves a recipient group by its name.
+func (s *txSqlcStore) GetRecipientGroupByName(ctx context.Context,
+	name string,
+) (RecipientGroup, error) {
+	group, err := s.queries.GetRecipientGroupByName(ctx, name)
+	if err != nil {
+		return RecipientGroup{}, err
+	}
+
+	return RecipientGroupFromSqlc(group), nil
+}
+
+// ListRecipientGroups lists all recipient groups with their member counts.
+func (s *txSqlcStore) ListRecipientGroups(
+	ctx context.Context,
+) ([]RecipientGroup, error) {
+	rows, err := s.queries.ListRecipientGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return recipientGroupsFromSqlc(rows), nil
+}
+
+// DeleteRecipientGroup deletes a recipient group and its memberships.
+func (s *txSqlcStore) DeleteRecipientGroup(ctx context.Context,
+	id int64,
+) (bool, error) {
+	n, err := s.queries.DeleteRecipientGroup(ctx, id)
+	return n > 0, err
+}
+
+// AddRecipientGroupMember adds an agent to a group.
+func (s *txSqlcStore) AddRecipientGroupMember(ctx context.Context, groupID,
+	agentID int64,
+) error {
+	return s.queries.AddRecipientGroupMember(
+		ctx, sqlc.AddRecipientGroupMemberParams{
+			GroupID: groupID,
+			AgentID: agentID,
+			AddedAt: time.Now().Unix(),
+		},
+	)
+}
+
+// RemoveRecipientGroupMember removes an agent from a group.
+func (s *txSqlcStore) RemoveRecipientGroupMember(ctx context.Context, groupID,
+	agentID int64,
+) (bool, error) {
+	n, err := s.queries.RemoveRecipientGroupMember(
+		ctx, sqlc.RemoveRecipientGroupMemberParams{
+			GroupID: groupID,
+			AgentID: agentID,
+		},
+	)
+	return n > 0, err
+}
+
+// ListRecipientGroupMembers lists the agents in a group by name.
+func (s *txSqlcStore) ListRecipientGroupMembers(ctx context.Context,
+	groupID int64,
+) ([]Agent, error) {
+	return agentsFromSqlc(s.queries.ListRecipientGroupMembers(ctx, groupID))
+}
+
+// ListAgentsMatchingProject lists agents whose project key equals the given
+// key or is a path ending in it.
+func (s *txSqlcStore) ListAgentsMatchingProject(ctx context.Context,
+	project string,
+) ([]Agent, error) {
+	return agentsFromSqlc(s.queries.ListAgentsMatchingProject(
+		ctx, ToSqlcNullString(project),
+	))
+}
+
+// ListAgentsByBranch lists agents working on the given git branch.
+func (s *txSqlcStore) ListAgentsByBranch(ctx context.Context,
+	branch string,
+) ([]Agent, error) {
+	return agentsFromSqlc(s.queries.ListAgentsByBranch(
+		ctx, ToSqlcNullString(branch),
+	))
+}
+
+// ListAgentsActiveSince lists agents whose last heartbeat is at or after the
+// given time.
+func (s *txSqlcStore) ListAgentsActiveSince(ctx context.Context,
+	since time.Time,
+) ([]Agent, error) {
+	return agentsFromSqlc(s.queries.ListAgentsActiveSince(ctx, since.Unix()))
+}
+
+// ListAgentsByNamePrefix lists agents whose name starts with the given
+// prefix.
+func (s *txSqlcStore) ListAgentsByNamePrefix(ctx context.Context,
+	prefix string,
+) ([]Agent, error) {
+	return agentsFromSqlc(s.queries.ListAgentsByNamePattern(
+		ctx, likePrefixPattern(prefix),
+	))
+}
+
+// CreateRecipientExpansion records that a group or alias in a message's
+// recipient list resolved to the given agent.
+func (s *txSqlcStore) CreateRecipientExpansion(ctx context.Context,
+	messageID int64, address string, agentID int64,
+) error {
+	return s.queries.CreateRecipientExpansion(
+		ctx, sqlc.CreateRecipientExpansionParams{
+			MessageID: messageID,
+			Address:   address,
+			AgentID:   agentID,
+		},
+	)
+}
+
+// ListRecipientExpansions lists the recorded expansions of a message's
+// recipient groups and aliases.
+func (s *txSqlcStore) ListRecipientExpansions(ctx context.Context,
+	messageID int64,
+) ([]RecipientExpansion, error) {
+	rows, err := s.queries.ListRecipientExpansions(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	expansions := make([]RecipientExpansion, len(rows))
+	for i, row := range rows {
+		expansions[i] = RecipientExpansion{
+			Address:   row.Address,
+			AgentID:   row.AgentID,
+			AgentName: row.AgentName,
+		}
+	}
+
+	return expansions, nil
+}
+
+// =============================================================================
+// Helper functions
+// =============================================================================
+
+// RecipientGroupFromSqlc converts a sqlc.RecipientGroup to a RecipientGroup.
+func RecipientGroupFromSqlc(g sqlc.RecipientGroup) RecipientGroup {
+	group := RecipientGroup{
+		ID:          g.ID,
+		Name:        g.Name,
+		Description: g.Description,
+		CreatedAt:   time.Unix(g.CreatedAt, 0),
+	}
+	if g.CreatedBy.Valid {
+		createdBy := g.CreatedBy.Int64
+		group.CreatedBy = &createdBy
+	}
+
+	return group
+}
+
+// recipientGroupsFromSqlc converts ListRecipientGroups rows to
+// RecipientGroups.
+func recipientGroupsFromSqlc(
+	rows []sqlc.ListRecipientGroupsRow,
+) []RecipientGroup {
+
+	groups := make([]RecipientGroup, len(rows))
+	for i, row := range rows {
+		groups[i] = RecipientGroupFromSqlc(sqlc.RecipientGroup{
+			ID:          row.ID,
+			Name:        row.Name,
+			Description: row.Description,
+			CreatedBy:   row.CreatedBy,
+			CreatedAt:   row.CreatedAt,
+		})
+		groups[i].MemberCount = row.MemberCount
+	}
+
+	return groups
+}
+
+// agentsFromSqlc converts the result of an agent list query to Agents.
+func agentsFromSqlc(rows []sqlc.Agent, err error) ([]Agent, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	agents := make([]Agent, len(rows))
+	for i, row := range rows {
+		agents[i] = AgentFromSqlc(row)
+	}
+
+	return agents, nil
+}
+
+// likePrefixPattern returns a LIKE pattern matching strings that start with
+// prefix, escaping any wildcards in it.
+func likePrefixPattern(prefix string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return escaper.Replace(prefix) + "%"
+}
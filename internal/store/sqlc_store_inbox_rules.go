@@ -0,0 +1,305 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// InboxRuleStore implementation for SqlcStore
+// =============================================================================
+
+// CreateInboxRule creates an inbox rule.
+func (s *SqlcStore) CreateInboxRule(ctx context.Context,
+	params CreateInboxRuleParams,
+) (InboxRule, error) {
+	return createInboxRule(ctx, s.db, params)
+}
+
+// GetInboxRuleByName retrieves one of an agent's inbox rules by name.
+func (s *SqlcStore) GetInboxRuleByName(ctx context.Context, agentID int64,
+	name string,
+) (InboxRule, error) {
+	return getInboxRuleByName(ctx, s.db, agentID, name)
+}
+
+// ListInboxRules lists an agent's inbox rules in the order they are applied.
+func (s *SqlcStore) ListInboxRules(ctx context.Context,
+	agentID int64,
+) ([]InboxRule, error) {
+	return listInboxRules(ctx, s.db, agentID)
+}
+
+// DeleteInboxRule deletes one of an agent's inbox rules.
+func (s *SqlcStore) DeleteInboxRule(ctx context.Context, agentID int64,
+	name string,
+) (bool, error) {
+	n, err := s.db.DeleteInboxRule(ctx, sqlc.DeleteInboxRuleParams{
+		AgentID: agentID,
+		Name:    name,
+	})
+	return n > 0, err
+}
+
+// SetInboxRuleEnabled enables or disables one of an agent's inbox rules.
+func (s *SqlcStore) SetInboxRuleEnabled(ctx context.Context, agentID int64,
+	name string, enabled bool,
+) (bool, error) {
+	return setInboxRuleEnabled(ctx, s.db, agentID, name, enabled)
+}
+
+// RecordInboxRuleMatch counts a message an inbox rule matched.
+func (s *SqlcStore) RecordInboxRuleMatch(ctx context.Context, id int64,
+	matchedAt time.Time,
+) error {
+	return s.db.RecordInboxRuleMatch(ctx, recordInboxRuleMatchParams(
+		id, matchedAt,
+	))
+}
+
+// SetRecipientCategory files a message under an inbox category for one of
+// its recipients.
+func (s *SqlcStore) SetRecipientCategory(ctx context.Context, messageID,
+	agentID int64, category InboxCategory,
+) error {
+	return s.db.SetRecipientCategory(ctx, sqlc.SetRecipientCategoryParams{
+		Category:  string(category),
+		MessageID: messageID,
+		AgentID:   agentID,
+	})
+}
+
+// ListReceivedMessages lists an agent's most recently received messages in
+// any state but trash, newest first.
+func (s *SqlcStore) ListReceivedMessages(ctx context.Context, agentID int64,
+	limit int,
+) ([]InboxMessage, error) {
+	return listReceivedMessages(ctx, s.db, agentID, limit)
+}
+
+// =============================================================================
+// InboxRuleStore implementation for txSqlcStore
+// =============================================================================
+
+// CreateInboxRule creates an inbox rule.
+func (s *txSqlcStore) CreateInboxRule(ctx context.Context,
+	params CreateInboxRuleParams,
+) (InboxRule, error) {
+	return createInboxRule(ctx, s.queries, params)
+}
+
+// GetInboxRuleByName retrieves one of an agent's inbox rules by name.
+func (s *txSqlcStore) GetInboxRuleByName(ctx context.Context, agentID int64,
+	name string,
+) (InboxRule, error) {
+	return getInboxRuleByName(ctx, s.queries, agentID, name)
+}
+
+// ListInboxRules lists an agent's inbox rules in the order they are applied.
+func (s *txSqlcStore) ListInboxRules(ctx context.Context,
+	agentID int64,
+) ([]InboxRule, error) {
+	return listInboxRules(ctx, s.queries, agentID)
+}
+
+// DeleteInboxRule deletes one of an agent's inbox rules.
+func (s *txSqlcStore) DeleteInboxRule(ctx context.Context, agentID int64,
+	name string,
+) (bool, error) {
+	n, err := s.queries.DeleteInboxRule(ctx, sqlc.DeleteInboxRuleParams{
+		AgentID: agentID,
+		Name:    name,
+	})
+	return n > 0, err
+}
+
+// SetInboxRuleEnabled enables or disables one of an agent's inbox rules.
+func (s *txSqlcStore) SetInboxRuleEnabled(ctx context.Context, agentID int64,
+	name string, enabled bool,
+) (bool, error) {
+	return setInboxRuleEnabled(ctx, s.queries, agentID, name, enabled)
+}
+
+// RecordInboxRuleMatch counts a message an inbox rule matched.
+func (s *txSqlcStore) RecordInboxRuleMatch(ctx context.Context, id int64,
+	matchedAt time.Time,
+) error {
+	return s.queries.RecordInboxRuleMatch(ctx, recordInboxRuleMatchParams(
+		id, matchedAt,
+	))
+}
+
+// SetRecipientCategory files a message under an inbox category for one of
+// its recipients.
+func (s *txSqlcStore) SetRecipientCategory(ctx context.Context, messageID,
+	agentID int64, category InboxCategory,
+) error {
+	return s.queries.SetRecipientCategory(
+		ctx, sqlc.SetRecipientCategoryParams{
+			Category:  string(category),
+			MessageID: messageID,
+			AgentID:   agentID,
+		},
+	)
+}
+
+// ListReceivedMessages lists an agent's most recently received messages in
+// any state but trash, newest first.
+func (s *txSqlcStore) ListReceivedMessages(ctx context.Context,
+	agentID int64, limit int,
+) ([]InboxMessage, error) {
+	return listReceivedMessages(ctx, s.queries, agentID, limit)
+}
+
+// =============================================================================
+// Helper functions
+// =============================================================================
+
+// createInboxRule inserts an inbox rule. New rules are enabled.
+func createInboxRule(ctx context.Context, q QueryStore,
+	params CreateInboxRuleParams,
+) (InboxRule, error) {
+	now := time.Now().Unix()
+	row, err := q.CreateInboxRule(ctx, sqlc.CreateInboxRuleParams{
+		AgentID:        params.AgentID,
+		Name:           params.Name,
+		Enabled:        1,
+		StopProcessing: boolToInt64(params.StopProcessing),
+		SenderName:     params.SenderName,
+		SenderPrefix:   params.SenderPrefix,
+		SubjectPattern: params.SubjectPattern,
+		TopicName:      params.TopicName,
+		Priority:       params.Priority,
+		MetadataKey:    params.MetadataKey,
+		MetadataValue:  params.MetadataValue,
+		Archive:        boolToInt64(params.Archive),
+		Star:           boolToInt64(params.Star),
+		Snooze:         params.Snooze,
+		Category:       string(params.Category),
+		ForwardTo:      params.ForwardTo,
+		Label:          params.Label,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	if err != nil {
+		return InboxRule{}, err
+	}
+
+	return inboxRuleFromSqlc(row), nil
+}
+
+// getInboxRuleByName fetches one of an agent's inbox rules by name.
+func getInboxRuleByName(ctx context.Context, q QueryStore, agentID int64,
+	name string,
+) (InboxRule, error) {
+	row, err := q.GetInboxRuleByName(ctx, sqlc.GetInboxRuleByNameParams{
+		AgentID: agentID,
+		Name:    name,
+	})
+	if err != nil {
+		return InboxRule{}, err
+	}
+
+	return inboxRuleFromSqlc(row), nil
+}
+
+// listInboxRules lists an agent's inbox rules in the order they are applied.
+func listInboxRules(ctx context.Context, q QueryStore,
+	agentID int64,
+) ([]InboxRule, error) {
+	rows, err := q.ListInboxRules(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]InboxRule, len(rows))
+	for i, row := range rows {
+		rules[i] = inboxRuleFromSqlc(row)
+	}
+
+	return rules, nil
+}
+
+// setInboxRuleEnabled updates the enabled flag of an inbox rule.
+func setInboxRuleEnabled(ctx context.Context, q QueryStore, agentID int64,
+	name string, enabled bool,
+) (bool, error) {
+	n, err := q.SetInboxRuleEnabled(ctx, sqlc.SetInboxRuleEnabledParams{
+		Enabled:   boolToInt64(enabled),
+		UpdatedAt: time.Now().Unix(),
+		AgentID:   agentID,
+		Name:      name,
+	})
+	return n > 0, err
+}
+
+// recordInboxRuleMatchParams builds the parameters to count a rule match.
+func recordInboxRuleMatchParams(id int64,
+	matchedAt time.Time,
+) sqlc.RecordInboxRuleMatchParams {
+	return sqlc.RecordInboxRuleMatchParams{
+		LastMatchedAt: sql.NullInt64{
+			Int64: matchedAt.Unix(),
+			Valid: true,
+		},
+		ID: id,
+	}
+}
+
+// listReceivedMessages lists an agent's most recently received messages. The
+// rows share GetInboxMessagesRow's fields, so they convert the same way.
+func listReceivedMessages(ctx context.Context, q QueryStore, agentID int64,
+	limit int,
+) ([]InboxMessage, error) {
+	rows, err := q.ListReceivedMessages(
+		ctx, sqlc.ListReceivedMessagesParams{
+			AgentID: agentID,
+			Limit:   int64(limit),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cast := make([]sqlc.GetInboxMessagesRow, len(rows))
+	for i, r := range rows {
+		cast[i] = sqlc.GetInboxMessagesRow(r)
+	}
+
+	return convertInboxRows(cast), nil
+}
+
+// inboxRuleFromSqlc converts a sqlc inbox rule to the domain type.
+func inboxRuleFromSqlc(row sqlc.InboxRule) InboxRule {
+	return InboxRule{
+		ID:             row.ID,
+		AgentID:        row.AgentID,
+		Name:           row.Name,
+		Enabled:        row.Enabled != 0,
+		StopProcessing: row.StopProcessing != 0,
+		InboxRuleConditions: InboxRuleConditions{
+			SenderName:     row.SenderName,
+			SenderPrefix:   row.SenderPrefix,
+			SubjectPattern: row.SubjectPattern,
+			TopicName:      row.TopicName,
+			Priority:       row.Priority,
+			MetadataKey:    row.MetadataKey,
+			MetadataValue:  row.MetadataValue,
+		},
+		InboxRuleActions: InboxRuleActions{
+			Archive:   row.Archive != 0,
+			Star:      row.Star != 0,
+			Snooze:    row.Snooze,
+			Category:  InboxCategory(row.Category),
+			ForwardTo: row.ForwardTo,
+			Label:     row.Label,
+		},
+		MatchCount:    row.MatchCount,
+		LastMatchedAt: nullInt64ToTime(row.LastMatchedAt),
+		CreatedAt:     time.Unix(row.CreatedAt, 0),
+		UpdatedAt:     time.Unix(row.UpdatedAt, 0),
+	}
+}
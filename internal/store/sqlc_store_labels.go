@@ -0,0 +1,288 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// LabelStore implementation for SqlcStore
+// =============================================================================
+
+// CreateLabel creates a label for an agent.
+func (s *SqlcStore) CreateLabel(ctx context.Context, agentID int64,
+	name string,
+) (Label, error) {
+	return createLabel(ctx, s.db, agentID, name)
+}
+
+// GetLabelByName retrieves one of an agent's labels by name.
+func (s *SqlcStore) GetLabelByName(ctx context.Context, agentID int64,
+	name string,
+) (Label, error) {
+	return getLabelByName(ctx, s.db, agentID, name)
+}
+
+// ListLabels lists an agent's labels by name, with their message counts.
+func (s *SqlcStore) ListLabels(ctx context.Context,
+	agentID int64,
+) ([]Label, error) {
+	return listLabels(ctx, s.db, agentID)
+}
+
+// DeleteLabel deletes one of an agent's labels.
+func (s *SqlcStore) DeleteLabel(ctx context.Context, agentID int64,
+	name string,
+) (bool, error) {
+	n, err := s.db.DeleteLabel(ctx, sqlc.DeleteLabelParams{
+		AgentID: agentID,
+		Name:    name,
+	})
+	return n > 0, err
+}
+
+// AddMessageLabel applies a label to a recipient's copy of a message.
+func (s *SqlcStore) AddMessageLabel(ctx context.Context, messageID, agentID,
+	labelID int64,
+) error {
+	return s.db.AddMessageLabel(ctx, addMessageLabelParams(
+		messageID, agentID, labelID,
+	))
+}
+
+// RemoveMessageLabel removes a label from a recipient's copy of a message.
+func (s *SqlcStore) RemoveMessageLabel(ctx context.Context, messageID,
+	agentID, labelID int64,
+) (bool, error) {
+	n, err := s.db.RemoveMessageLabel(ctx, sqlc.RemoveMessageLabelParams{
+		MessageID: messageID,
+		AgentID:   agentID,
+		LabelID:   labelID,
+	})
+	return n > 0, err
+}
+
+// ListMessageLabels returns the names of the labels an agent applied to each
+// of the given messages.
+func (s *SqlcStore) ListMessageLabels(ctx context.Context, agentID int64,
+	messageIDs []int64,
+) (map[int64][]string, error) {
+	return listMessageLabels(ctx, s.db, agentID, messageIDs)
+}
+
+// GetInboxMessagesByLabel retrieves the messages an agent filed under a
+// label.
+func (s *SqlcStore) GetInboxMessagesByLabel(ctx context.Context,
+	agentID int64, label string, limit, offset int,
+) ([]InboxMessage, error) {
+	return getInboxMessagesByLabel(ctx, s.db, agentID, label, limit, offset)
+}
+
+// =============================================================================
+// LabelStore implementation for txSqlcStore
+// =============================================================================
+
+// CreateLabel creates a label for an agent.
+func (s *txSqlcStore) CreateLabel(ctx context.Context, agentID int64,
+	name string,
+) (Label, error) {
+	return createLabel(ctx, s.queries, agentID, name)
+}
+
+// GetLabelByName retrieves one of an agent's labels by name.
+func (s *txSqlcStore) GetLabelByName(ctx context.Context, agentID int64,
+	name string,
+) (Label, error) {
+	return getLabelByName(ctx, s.queries, agentID, name)
+}
+
+// ListLabels lists an agent's labels by name, with their message counts.
+func (s *txSqlcStore) ListLabels(ctx context.Context,
+	agentID int64,
+) ([]Label, error) {
+	return listLabels(ctx, s.queries, agentID)
+}
+
+// DeleteLabel deletes one of an agent's labels.
+func (s *txSqlcStore) DeleteLabel(ctx context.Context, agentID int64,
+	name string,
+) (bool, error) {
+	n, err := s.queries.DeleteLabel(ctx, sqlc.DeleteLabelParams{
+		AgentID: agentID,
+		Name:    name,
+	})
+	return n > 0, err
+}
+
+// AddMessageLabel applies a label to a recipient's copy of a message.
+func (s *txSqlcStore) AddMessageLabel(ctx context.Context, messageID,
+	agentID, labelID int64,
+) error {
+	return s.queries.AddMessageLabel(ctx, addMessageLabelParams(
+		messageID, agentID, labelID,
+	))
+}
+
+// RemoveMessageLabel removes a label from a recipient's copy of a message.
+func (s *txSqlcStore) RemoveMessageLabel(ctx context.Context, messageID,
+	agentID, labelID int64,
+) (bool, error) {
+	n, err := s.queries.RemoveMessageLabel(
+		ctx, sqlc.RemoveMessageLabelParams{
+			MessageID: messageID,
+			AgentID:   agentID,
+			LabelID:   labelID,
+		},
+	)
+	return n > 0, err
+}
+
+// ListMessageLabels returns the names of the labels an agent applied to each
+// of the given messages.
+func (s *txSqlcStore) ListMessageLabels(ctx context.Context, agentID int64,
+	messageIDs []int64,
+) (map[int64][]string, error) {
+	return listMessageLabels(ctx, s.queries, agentID, messageIDs)
+}
+
+// GetInboxMessagesByLabel retrieves the messages an agent filed under a
+// label.
+func (s *txSqlcStore) GetInboxMessagesByLabel(ctx context.Context,
+	agentID int64, label string, limit, offset int,
+) ([]InboxMessage, error) {
+	return getInboxMessagesByLabel(
+		ctx, s.queries, agentID, label, limit, offset,
+	)
+}
+
+// =============================================================================
+// Helper functions
+// =============================================================================
+
+// createLabel inserts a label.
+func createLabel(ctx context.Context, q QueryStore, agentID int64,
+	name string,
+) (Label, error) {
+	row, err := q.CreateLabel(ctx, sqlc.CreateLabelParams{
+		AgentID:   agentID,
+		Name:      name,
+		CreatedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		return Label{}, err
+	}
+
+	return labelFromSqlc(row), nil
+}
+
+// getLabelByName fetches one of an agent's labels by name.
+func getLabelByName(ctx context.Context, q QueryStore, agentID int64,
+	name string,
+) (Label, error) {
+	row, err := q.GetLabelByName(ctx, sqlc.GetLabelByNameParams{
+		AgentID: agentID,
+		Name:    name,
+	})
+	if err != nil {
+		return Label{}, err
+	}
+
+	return labelFromSqlc(row), nil
+}
+
+// listLabels lists an agent's labels with their message counts.
+func listLabels(ctx context.Context, q QueryStore,
+	agentID int64,
+) ([]Label, error) {
+	rows, err := q.ListLabels(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]Label, len(rows))
+	for i, row := range rows {
+		labels[i] = Label{
+			ID:           row.ID,
+			AgentID:      row.AgentID,
+			Name:         row.Name,
+			CreatedAt:    time.Unix(row.CreatedAt, 0),
+			MessageCount: row.MessageCount,
+			UnreadCount:  row.UnreadCount,
+		}
+	}
+
+	return labels, nil
+}
+
+// addMessageLabelParams builds the parameters to label a message.
+func addMessageLabelParams(messageID, agentID,
+	labelID int64,
+) sqlc.AddMessageLabelParams {
+	return sqlc.AddMessageLabelParams{
+		MessageID: messageID,
+		AgentID:   agentID,
+		LabelID:   labelID,
+		CreatedAt: time.Now().Unix(),
+	}
+}
+
+// listMessageLabels groups the labels of a batch of messages by message.
+func listMessageLabels(ctx context.Context, q QueryStore, agentID int64,
+	messageIDs []int64,
+) (map[int64][]string, error) {
+	labels := make(map[int64][]string)
+	if len(messageIDs) == 0 {
+		return labels, nil
+	}
+
+	rows, err := q.ListMessageLabels(ctx, sqlc.ListMessageLabelsParams{
+		AgentID:    agentID,
+		MessageIds: messageIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		labels[row.MessageID] = append(labels[row.MessageID], row.Name)
+	}
+
+	return labels, nil
+}
+
+// getInboxMessagesByLabel lists the messages an agent filed under a label.
+// The rows share GetInboxMessagesRow's fields, so they convert the same way.
+func getInboxMessagesByLabel(ctx context.Context, q QueryStore,
+	agentID int64, label string, limit, offset int,
+) ([]InboxMessage, error) {
+	rows, err := q.GetInboxMessagesByLabel(
+		ctx, sqlc.GetInboxMessagesByLabelParams{
+			AgentID: agentID,
+			Name:    label,
+			Limit:   int64(limit),
+			Offset:  int64(offset),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cast := make([]sqlc.GetInboxMessagesRow, len(rows))
+	for i, r := range rows {
+		cast[i] = sqlc.GetInboxMessagesRow(r)
+	}
+
+	return convertInboxRows(cast), nil
+}
+
+// labelFromSqlc converts a sqlc label to the domain type.
+func labelFromSqlc(row sqlc.Label) Label {
+	return Label{
+		ID:        row.ID,
+		AgentID:   row.AgentID,
+		Name:      row.Name,
+		CreatedAt: time.Unix(row.CreatedAt, 0),
+	}
+}
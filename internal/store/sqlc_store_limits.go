@@ -0,0 +1,321 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// LimitStore implementation for SqlcStore
+// =============================================================================
+
+// GetAgentLimit retrieves the limit that applies to an agent for a class.
+func (s *SqlcStore) GetAgentLimit(ctx context.Context, agentID int64,
+	class string,
+) (AgentLimit, error) {
+	return getAgentLimit(ctx, s.db, agentID, class)
+}
+
+// SetAgentLimit sets an agent's limit for a class, or the default.
+func (s *SqlcStore) SetAgentLimit(ctx context.Context,
+	params SetAgentLimitParams,
+) (AgentLimit, error) {
+	var limit AgentLimit
+	err := s.WithTx(ctx, func(ctx context.Context, txStore Storage) error {
+		var err error
+		limit, err = txStore.SetAgentLimit(ctx, params)
+		return err
+	})
+
+	return limit, err
+}
+
+// DeleteAgentLimit deletes an agent's limit for a class, or the default.
+func (s *SqlcStore) DeleteAgentLimit(ctx context.Context, agentID int64,
+	class string,
+) (bool, error) {
+	return deleteAgentLimit(ctx, s.db, agentID, class)
+}
+
+// ListAgentLimits lists the default limits followed by the agents' own.
+func (s *SqlcStore) ListAgentLimits(ctx context.Context,
+	agentID int64,
+) ([]AgentLimit, error) {
+	return listAgentLimits(ctx, s.db, agentID)
+}
+
+// GetRateLimitBucket retrieves an agent's token bucket for a class.
+func (s *SqlcStore) GetRateLimitBucket(ctx context.Context, agentID int64,
+	class string,
+) (RateLimitBucket, error) {
+	return getRateLimitBucket(ctx, s.db, agentID, class)
+}
+
+// UpsertRateLimitBucket stores an agent's token bucket for a class.
+func (s *SqlcStore) UpsertRateLimitBucket(ctx context.Context,
+	bucket RateLimitBucket,
+) error {
+	return upsertRateLimitBucket(ctx, s.db, bucket)
+}
+
+// ListRateLimitBuckets lists an agent's token buckets.
+func (s *SqlcStore) ListRateLimitBuckets(ctx context.Context,
+	agentID int64,
+) ([]RateLimitBucket, error) {
+	return listRateLimitBuckets(ctx, s.db, agentID)
+}
+
+// SumSenderBodyBytes returns the size of the bodies an agent sent since a
+// time.
+func (s *SqlcStore) SumSenderBodyBytes(ctx context.Context, senderID int64,
+	since time.Time,
+) (int64, error) {
+	return sumSenderBodyBytes(ctx, s.db, senderID, since)
+}
+
+// =============================================================================
+// LimitStore implementation for txSqlcStore
+// =============================================================================
+
+// GetAgentLimit retrieves the limit that applies to an agent for a class.
+func (s *txSqlcStore) GetAgentLimit(ctx context.Context, agentID int64,
+	class string,
+) (AgentLimit, error) {
+	return getAgentLimit(ctx, s.queries, agentID, class)
+}
+
+// SetAgentLimit sets an agent's limit for a class, or the default.
+func (s *txSqlcStore) SetAgentLimit(ctx context.Context,
+	params SetAgentLimitParams,
+) (AgentLimit, error) {
+	return setAgentLimit(ctx, s.queries, params)
+}
+
+// DeleteAgentLimit deletes an agent's limit for a class, or the default.
+func (s *txSqlcStore) DeleteAgentLimit(ctx context.Context, agentID int64,
+	class string,
+) (bool, error) {
+	return deleteAgentLimit(ctx, s.queries, agentID, class)
+}
+
+// ListAgentLimits lists the default limits followed by the agents' own.
+func (s *txSqlcStore) ListAgentLimits(ctx context.Context,
+	agentID int64,
+) ([]AgentLimit, error) {
+	return listAgentLimits(ctx, s.queries, agentID)
+}
+
+// GetRateLimitBucket retrieves an agent's token bucket for a class.
+func (s *txSqlcStore) GetRateLimitBucket(ctx context.Context, agentID int64,
+	class string,
+) (RateLimitBucket, error) {
+	return getRateLimitBucket(ctx, s.queries, agentID, class)
+}
+
+// UpsertRateLimitBucket stores an agent's token bucket for a class.
+func (s *txSqlcStore) UpsertRateLimitBucket(ctx context.Context,
+	bucket RateLimitBucket,
+) error {
+	return upsertRateLimitBucket(ctx, s.queries, bucket)
+}
+
+// ListRateLimitBuckets lists an agent's token buckets.
+func (s *txSqlcStore) ListRateLimitBuckets(ctx context.Context,
+	agentID int64,
+) ([]RateLimitBucket, error) {
+	return listRateLimitBuckets(ctx, s.queries, agentID)
+}
+
+// SumSenderBodyBytes returns the size of the bodies an agent sent since a
+// time.
+func (s *txSqlcStore) SumSenderBodyBytes(ctx context.Context,
+	senderID int64, since time.Time,
+) (int64, error) {
+	return sumSenderBodyBytes(ctx, s.queries, senderID, since)
+}
+
+// =============================================================================
+// Helper functions
+// =============================================================================
+
+// limitAgentID converts an agent ID to the nullable column limits use, in
+// which NULL is the default.
+func limitAgentID(agentID int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: agentID, Valid: agentID != 0}
+}
+
+// getAgentLimit fetches the agent's own limit for a class, falling back to
+// the default.
+func getAgentLimit(ctx context.Context, q QueryStore, agentID int64,
+	class string,
+) (AgentLimit, error) {
+	row, err := q.GetEffectiveAgentLimit(
+		ctx, sqlc.GetEffectiveAgentLimitParams{
+			Class:   class,
+			AgentID: limitAgentID(agentID),
+		},
+	)
+	if err != nil {
+		return AgentLimit{}, err
+	}
+
+	return agentLimitFromSqlc(sqlc.ListAgentLimitsRow{
+		ID:              row.ID,
+		AgentID:         row.AgentID,
+		Class:           row.Class,
+		Amount:          row.Amount,
+		IntervalSeconds: row.IntervalSeconds,
+		Burst:           row.Burst,
+		UpdatedAt:       row.UpdatedAt,
+	}), nil
+}
+
+// setAgentLimit replaces an agent's limit for a class. It must run in a
+// transaction so the limit is never briefly missing.
+func setAgentLimit(ctx context.Context, q QueryStore,
+	params SetAgentLimitParams,
+) (AgentLimit, error) {
+	_, err := q.DeleteAgentLimit(ctx, sqlc.DeleteAgentLimitParams{
+		AgentID: limitAgentID(params.AgentID),
+		Class:   params.Class,
+	})
+	if err != nil {
+		return AgentLimit{}, err
+	}
+
+	row, err := q.CreateAgentLimit(ctx, sqlc.CreateAgentLimitParams{
+		AgentID:         limitAgentID(params.AgentID),
+		Class:           params.Class,
+		Amount:          params.Amount,
+		IntervalSeconds: int64(params.Interval / time.Second),
+		Burst:           params.Burst,
+		UpdatedAt:       time.Now().Unix(),
+	})
+	if err != nil {
+		return AgentLimit{}, err
+	}
+
+	return agentLimitFromSqlc(sqlc.ListAgentLimitsRow{
+		ID:              row.ID,
+		AgentID:         row.AgentID,
+		Class:           row.Class,
+		Amount:          row.Amount,
+		IntervalSeconds: row.IntervalSeconds,
+		Burst:           row.Burst,
+		UpdatedAt:       row.UpdatedAt,
+	}), nil
+}
+
+// deleteAgentLimit deletes an agent's limit for a class.
+func deleteAgentLimit(ctx context.Context, q QueryStore, agentID int64,
+	class string,
+) (bool, error) {
+	n, err := q.DeleteAgentLimit(ctx, sqlc.DeleteAgentLimitParams{
+		AgentID: limitAgentID(agentID),
+		Class:   class,
+	})
+	return n > 0, err
+}
+
+// listAgentLimits lists limits, defaults first.
+func listAgentLimits(ctx context.Context, q QueryStore,
+	agentID int64,
+) ([]AgentLimit, error) {
+	rows, err := q.ListAgentLimits(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make([]AgentLimit, len(rows))
+	for i, row := range rows {
+		limits[i] = agentLimitFromSqlc(row)
+	}
+
+	return limits, nil
+}
+
+// getRateLimitBucket fetches an agent's token bucket for a class.
+func getRateLimitBucket(ctx context.Context, q QueryStore, agentID int64,
+	class string,
+) (RateLimitBucket, error) {
+	row, err := q.GetRateLimitBucket(ctx, sqlc.GetRateLimitBucketParams{
+		AgentID: agentID,
+		Class:   class,
+	})
+	if err != nil {
+		return RateLimitBucket{}, err
+	}
+
+	return rateLimitBucketFromSqlc(row), nil
+}
+
+// upsertRateLimitBucket stores an agent's token bucket for a class.
+func upsertRateLimitBucket(ctx context.Context, q QueryStore,
+	bucket RateLimitBucket,
+) error {
+	return q.UpsertRateLimitBucket(ctx, sqlc.UpsertRateLimitBucketParams{
+		AgentID:         bucket.AgentID,
+		Class:           bucket.Class,
+		Tokens:          bucket.Tokens,
+		UpdatedAtMs:     bucket.UpdatedAt.UnixMilli(),
+		ThrottledCount:  bucket.ThrottledCount,
+		LastThrottledAt: ToSqlcNullInt64(bucket.LastThrottledAt),
+	})
+}
+
+// listRateLimitBuckets lists an agent's token buckets.
+func listRateLimitBuckets(ctx context.Context, q QueryStore,
+	agentID int64,
+) ([]RateLimitBucket, error) {
+	rows, err := q.ListRateLimitBuckets(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]RateLimitBucket, len(rows))
+	for i, row := range rows {
+		buckets[i] = rateLimitBucketFromSqlc(row)
+	}
+
+	return buckets, nil
+}
+
+// sumSenderBodyBytes sums the size of the bodies an agent sent since a
+// time.
+func sumSenderBodyBytes(ctx context.Context, q QueryStore, senderID int64,
+	since time.Time,
+) (int64, error) {
+	return q.SumSenderBodyBytes(ctx, sqlc.SumSenderBodyBytesParams{
+		SenderID:  senderID,
+		CreatedAt: since.Unix(),
+	})
+}
+
+// agentLimitFromSqlc converts a sqlc limit row.
+func agentLimitFromSqlc(row sqlc.ListAgentLimitsRow) AgentLimit {
+	return AgentLimit{
+		ID:        row.ID,
+		AgentID:   row.AgentID.Int64,
+		AgentName: row.AgentName,
+		Class:     row.Class,
+		Amount:    row.Amount,
+		Interval:  time.Duration(row.IntervalSeconds) * time.Second,
+		Burst:     row.Burst,
+		UpdatedAt: time.Unix(row.UpdatedAt, 0),
+	}
+}
+
+// rateLimitBucketFromSqlc converts a sqlc token bucket row.
+func rateLimitBucketFromSqlc(row sqlc.RateLimitBucket) RateLimitBucket {
+	return RateLimitBucket{
+		AgentID:         row.AgentID,
+		Class:           row.Class,
+		Tokens:          row.Tokens,
+		UpdatedAt:       time.UnixMilli(row.UpdatedAtMs),
+		ThrottledCount:  row.ThrottledCount,
+		LastThrottledAt: nullInt64ToTime(row.LastThrottledAt),
+	}
+}
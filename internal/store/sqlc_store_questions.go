@@ -0,0 +1,291 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// QuestionStore implementation for SqlcStore
+// =============================================================================
+
+// CreateQuestion records a question asked by a message.
+func (s *SqlcStore) CreateQuestion(ctx context.Context,
+	params CreateQuestionParams,
+) (Question, error) {
+	return createQuestion(ctx, s.db, params)
+}
+
+// GetQuestion retrieves a question by its public ID.
+func (s *SqlcStore) GetQuestion(ctx context.Context,
+	questionID string,
+) (Question, error) {
+	row, err := s.db.GetQuestion(ctx, questionID)
+	if err != nil {
+		return Question{}, err
+	}
+
+	return questionFromSqlc(row)
+}
+
+// ListPendingQuestionsByThread lists the questions in a thread that are still
+// waiting for an answer.
+func (s *SqlcStore) ListPendingQuestionsByThread(ctx context.Context,
+	threadID string,
+) ([]Question, error) {
+	rows, err := s.db.ListPendingQuestionsByThread(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return questionsFromSqlc(rows)
+}
+
+// ListTimedOutQuestions lists pending questions whose current timeout is at
+// or before now, oldest first.
+func (s *SqlcStore) ListTimedOutQuestions(ctx context.Context, now time.Time,
+	limit int,
+) ([]Question, error) {
+	return listTimedOutQuestions(ctx, s.db, now, limit)
+}
+
+// ResolveQuestion records the answer to a pending question.
+func (s *SqlcStore) ResolveQuestion(ctx context.Context,
+	params ResolveQuestionParams,
+) (bool, error) {
+	n, err := s.db.ResolveQuestion(ctx, resolveQuestionParams(params))
+	return n > 0, err
+}
+
+// EscalateQuestion records that a question was escalated to its fallback
+// recipient and sets its next timeout.
+func (s *SqlcStore) EscalateQuestion(ctx context.Context, id int64,
+	timeoutAt time.Time,
+) error {
+	return s.db.EscalateQuestion(ctx, escalateQuestionParams(id, timeoutAt))
+}
+
+// =============================================================================
+// QuestionStore implementation for txSqlcStore
+// =============================================================================
+
+// CreateQuestion records a question asked by a message.
+func (s *txSqlcStore) CreateQuestion(ctx context.Context,
+	params CreateQuestionParams,
+) (Question, error) {
+	return createQuestion(ctx, s.queries, params)
+}
+
+// GetQuestion retrieves a question by its public ID.
+func (s *txSqlcStore) GetQuestion(ctx context.Context,
+	questionID string,
+) (Question, error) {
+	row, err := s.queries.GetQuestion(ctx, questionID)
+	if err != nil {
+		return Question{}, err
+	}
+
+	return questionFromSqlc(row)
+}
+
+// ListPendingQuestionsByThread lists the questions in a thread that are still
+// waiting for an answer.
+func (s *txSqlcStore) ListPendingQuestionsByThread(ctx context.Context,
+	threadID string,
+) ([]Question, error) {
+	rows, err := s.queries.ListPendingQuestionsByThread(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return questionsFromSqlc(rows)
+}
+
+// ListTimedOutQuestions lists pending questions whose current timeout is at
+// or before now, oldest first.
+func (s *txSqlcStore) ListTimedOutQuestions(ctx context.Context,
+	now time.Time, limit int,
+) ([]Question, error) {
+	return listTimedOutQuestions(ctx, s.queries, now, limit)
+}
+
+// ResolveQuestion records the answer to a pending question.
+func (s *txSqlcStore) ResolveQuestion(ctx context.Context,
+	params ResolveQuestionParams,
+) (bool, error) {
+	n, err := s.queries.ResolveQuestion(ctx, resolveQuestionParams(params))
+	return n > 0, err
+}
+
+// EscalateQuestion records that a question was escalated to its fallback
+// recipient and sets its next timeout.
+func (s *txSqlcStore) EscalateQuestion(ctx context.Context, id int64,
+	timeoutAt time.Time,
+) error {
+	return s.queries.EscalateQuestion(
+		ctx, escalateQuestionParams(id, timeoutAt),
+	)
+}
+
+// =============================================================================
+// Helper functions
+// =============================================================================
+
+// createQuestion records a question, starting its first timeout if it has
+// one.
+func createQuestion(ctx context.Context, q QueryStore,
+	params CreateQuestionParams,
+) (Question, error) {
+	options := params.Options
+	if options == nil {
+		options = []string{}
+	}
+	encoded, err := json.Marshal(options)
+	if err != nil {
+		return Question{}, fmt.Errorf("failed to encode options: %w",
+			err)
+	}
+
+	now := time.Now()
+	var timeoutAt sql.NullInt64
+	if params.Timeout > 0 {
+		timeoutAt = sql.NullInt64{
+			Int64: now.Add(params.Timeout).Unix(),
+			Valid: true,
+		}
+	}
+
+	row, err := q.CreateQuestion(ctx, sqlc.CreateQuestionParams{
+		QuestionID:     params.QuestionID,
+		MessageID:      params.MessageID,
+		ThreadID:       params.ThreadID,
+		AskerID:        params.AskerID,
+		Question:       params.Question,
+		Options:        string(encoded),
+		DefaultAnswer:  params.DefaultAnswer,
+		TimeoutSeconds: int64(params.Timeout / time.Second),
+		TimeoutAt:      timeoutAt,
+		Fallback:       params.Fallback,
+		CreatedAt:      now.Unix(),
+		UpdatedAt:      now.Unix(),
+	})
+	if err != nil {
+		return Question{}, err
+	}
+
+	return questionFromSqlc(row)
+}
+
+// listTimedOutQuestions lists pending questions that have timed out.
+func listTimedOutQuestions(ctx context.Context, q QueryStore, now time.Time,
+	limit int,
+) ([]Question, error) {
+	rows, err := q.ListTimedOutQuestions(
+		ctx, sqlc.ListTimedOutQuestionsParams{
+			TimeoutAt: sql.NullInt64{
+				Int64: now.Unix(),
+				Valid: true,
+			},
+			Limit: int64(limit),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return questionsFromSqlc(rows)
+}
+
+// resolveQuestionParams converts question resolution parameters to their
+// sqlc form.
+func resolveQuestionParams(
+	params ResolveQuestionParams,
+) sqlc.ResolveQuestionParams {
+	now := time.Now().Unix()
+	return sqlc.ResolveQuestionParams{
+		State:  params.State,
+		Answer: params.Answer,
+		AnsweredBy: sql.NullInt64{
+			Int64: params.AnsweredBy,
+			Valid: params.AnsweredBy != 0,
+		},
+		AnswerMessageID: sql.NullInt64{
+			Int64: params.AnswerMessageID,
+			Valid: params.AnswerMessageID != 0,
+		},
+		AnsweredAt: sql.NullInt64{Int64: now, Valid: true},
+		UpdatedAt:  now,
+		ID:         params.ID,
+	}
+}
+
+// escalateQuestionParams builds the sqlc parameters for escalating a
+// question.
+func escalateQuestionParams(id int64,
+	timeoutAt time.Time,
+) sqlc.EscalateQuestionParams {
+	now := time.Now().Unix()
+	return sqlc.EscalateQuestionParams{
+		EscalatedAt: sql.NullInt64{Int64: now, Valid: true},
+		TimeoutAt: sql.NullInt64{
+			Int64: timeoutAt.Unix(),
+			Valid: true,
+		},
+		UpdatedAt: now,
+		ID:        id,
+	}
+}
+
+// questionFromSqlc converts a sqlc question to the domain type, decoding its
+// options.
+func questionFromSqlc(row sqlc.Question) (Question, error) {
+	var options []string
+	err := json.Unmarshal([]byte(row.Options), &options)
+	if err != nil {
+		return Question{}, fmt.Errorf("invalid options for question "+
+			"%s: %w", row.QuestionID, err)
+	}
+
+	timeout := time.Duration(row.TimeoutSeconds) * time.Second
+
+	return Question{
+		ID:              row.ID,
+		QuestionID:      row.QuestionID,
+		MessageID:       row.MessageID,
+		ThreadID:        row.ThreadID,
+		AskerID:         row.AskerID,
+		Question:        row.Question,
+		Options:         options,
+		DefaultAnswer:   row.DefaultAnswer,
+		Timeout:         timeout,
+		TimeoutAt:       nullInt64ToTime(row.TimeoutAt),
+		Fallback:        row.Fallback,
+		EscalatedAt:     nullInt64ToTime(row.EscalatedAt),
+		State:           row.State,
+		Answer:          row.Answer,
+		AnsweredBy:      row.AnsweredBy.Int64,
+		AnswerMessageID: row.AnswerMessageID.Int64,
+		AnsweredAt:      nullInt64ToTime(row.AnsweredAt),
+		CreatedAt:       time.Unix(row.CreatedAt, 0),
+		UpdatedAt:       time.Unix(row.UpdatedAt, 0),
+	}, nil
+}
+
+// questionsFromSqlc converts sqlc questions to domain questions.
+func questionsFromSqlc(rows []sqlc.Question) ([]Question, error) {
+	questions := make([]Question, len(rows))
+	for i, row := range rows {
+		question, err := questionFromSqlc(row)
+		if err != nil {
+			return nil, err
+		}
+		questions[i] = question
+	}
+
+	return questions, nil
+}
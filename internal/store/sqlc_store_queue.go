@@ -0,0 +1,392 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// QueueDeliveryStore implementation for SqlcStore
+// =============================================================================
+
+// CreateQueueDelivery registers a newly published queue message as pending.
+func (s *SqlcStore) CreateQueueDelivery(ctx context.Context,
+	messageID, topicID int64,
+) error {
+	now := time.Now().Unix()
+
+	return s.db.CreateQueueDelivery(ctx, sqlc.CreateQueueDeliveryParams{
+		MessageID: messageID,
+		TopicID:   topicID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+}
+
+// GetQueueDelivery retrieves the delivery state of a queue message.
+func (s *SqlcStore) GetQueueDelivery(ctx context.Context,
+	messageID int64,
+) (QueueDelivery, error) {
+	row, err := s.db.GetQueueDelivery(ctx, messageID)
+	if err != nil {
+		return QueueDelivery{}, err
+	}
+
+	return QueueDeliveryFromSqlc(row), nil
+}
+
+// ClaimNextQueueDelivery leases the oldest claimable message on a queue
+// topic to an agent.
+func (s *SqlcStore) ClaimNextQueueDelivery(ctx context.Context,
+	params ClaimQueueDeliveryParams,
+) (QueueDelivery, error) {
+	row, err := s.db.ClaimNextQueueDelivery(
+		ctx, sqlc.ClaimNextQueueDeliveryParams{
+			AgentID: sql.NullInt64{
+				Int64: params.AgentID, Valid: true,
+			},
+			LeaseExpiresAt: ToSqlcNullInt64(&params.LeaseExpiresAt),
+			Now:            time.Now().Unix(),
+			TopicID:        params.TopicID,
+			MaxDeliveries:  int64(params.MaxDeliveries),
+		},
+	)
+	if err != nil {
+		return QueueDelivery{}, err
+	}
+
+	return QueueDeliveryFromSqlc(row), nil
+}
+
+// AckQueueDelivery completes the agent's lease on a message.
+func (s *SqlcStore) AckQueueDelivery(ctx context.Context,
+	messageID, agentID int64,
+) (bool, error) {
+	rows, err := s.db.AckQueueDelivery(ctx, sqlc.AckQueueDeliveryParams{
+		UpdatedAt: time.Now().Unix(),
+		MessageID: messageID,
+		ClaimedBy: sql.NullInt64{Int64: agentID, Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// ReleaseQueueDelivery hands the agent's lease on a message back to the
+// queue.
+func (s *SqlcStore) ReleaseQueueDelivery(ctx context.Context,
+	messageID, agentID int64, reason string,
+) (bool, error) {
+	rows, err := s.db.ReleaseQueueDelivery(
+		ctx, sqlc.ReleaseQueueDeliveryParams{
+			LastError: ToSqlcNullString(reason),
+			UpdatedAt: time.Now().Unix(),
+			MessageID: messageID,
+			ClaimedBy: sql.NullInt64{Int64: agentID, Valid: true},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// ExtendQueueLease pushes out the expiry of the agent's lease on a message.
+func (s *SqlcStore) ExtendQueueLease(ctx context.Context,
+	messageID, agentID int64, expiresAt time.Time,
+) (bool, error) {
+	rows, err := s.db.ExtendQueueLease(ctx, sqlc.ExtendQueueLeaseParams{
+		LeaseExpiresAt: ToSqlcNullInt64(&expiresAt),
+		UpdatedAt:      time.Now().Unix(),
+		MessageID:      messageID,
+		ClaimedBy:      sql.NullInt64{Int64: agentID, Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// ListExpiredQueueLeases lists leases that expired as of now without being
+// acked.
+func (s *SqlcStore) ListExpiredQueueLeases(ctx context.Context,
+	now time.Time,
+) ([]QueueDelivery, error) {
+	rows, err := s.db.ListExpiredQueueLeases(
+		ctx, ToSqlcNullInt64(&now),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]QueueDelivery, len(rows))
+	for i, row := range rows {
+		result[i] = QueueDeliveryFromSqlc(row)
+	}
+
+	return result, nil
+}
+
+// ReleaseExpiredQueueLeases returns expired leases with delivery attempts
+// left to the pending state.
+func (s *SqlcStore) ReleaseExpiredQueueLeases(ctx context.Context,
+	now time.Time, maxDeliveries int,
+) (int64, error) {
+	return s.db.ReleaseExpiredQueueLeases(
+		ctx, sqlc.ReleaseExpiredQueueLeasesParams{
+			Now:           now.Unix(),
+			MaxDeliveries: int64(maxDeliveries),
+		},
+	)
+}
+
+// ListExhaustedQueueDeliveries lists unacked messages that have used up
+// their delivery attempts.
+func (s *SqlcStore) ListExhaustedQueueDeliveries(ctx context.Context,
+	now time.Time, maxDeliveries int,
+) ([]QueueDelivery, error) {
+	rows, err := s.db.ListExhaustedQueueDeliveries(
+		ctx, sqlc.ListExhaustedQueueDeliveriesParams{
+			MaxDeliveries: int64(maxDeliveries),
+			Now:           ToSqlcNullInt64(&now),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return exhaustedQueueDeliveriesFromSqlc(rows), nil
+}
+
+// MarkQueueDeliveryDeadLettered marks a message as moved to its dead-letter
+// topic.
+func (s *SqlcStore) MarkQueueDeliveryDeadLettered(ctx context.Context,
+	messageID int64,
+) error {
+	return s.db.MarkQueueDeliveryDeadLettered(
+		ctx, sqlc.MarkQueueDeliveryDeadLetteredParams{
+			UpdatedAt: time.Now().Unix(),
+			MessageID: messageID,
+		},
+	)
+}
+
+// =============================================================================
+// QueueDeliveryStore implementation for txSqlcStore
+// =============================================================================
+
+// CreateQueueDelivery registers a newly published queue message as pending.
+func (s *txSqlcStore) CreateQueueDelivery(ctx context.Context,
+	messageID, topicID int64,
+) error {
+	now := time.Now().Unix()
+
+	return s.queries.CreateQueueDelivery(ctx, sqlc.CreateQueueDeliveryParams{
+		MessageID: messageID,
+		TopicID:   topicID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+}
+
+// GetQueueDelivery retrieves the delivery state of a queue message.
+func (s *txSqlcStore) GetQueueDelivery(ctx context.Context,
+	messageID int64,
+) (QueueDelivery, error) {
+	row, err := s.queries.GetQueueDelivery(ctx, messageID)
+	if err != nil {
+		return QueueDelivery{}, err
+	}
+
+	return QueueDeliveryFromSqlc(row), nil
+}
+
+// ClaimNextQueueDelivery leases the oldest claimable message on a queue
+// topic to an agent.
+func (s *txSqlcStore) ClaimNextQueueDelivery(ctx context.Context,
+	params ClaimQueueDeliveryParams,
+) (QueueDelivery, error) {
+	row, err := s.queries.ClaimNextQueueDelivery(
+		ctx, sqlc.ClaimNextQueueDeliveryParams{
+			AgentID: sql.NullInt64{
+				Int64: params.AgentID, Valid: true,
+			},
+			LeaseExpiresAt: ToSqlcNullInt64(&params.LeaseExpiresAt),
+			Now:            time.Now().Unix(),
+			TopicID:        params.TopicID,
+			MaxDeliveries:  int64(params.MaxDeliveries),
+		},
+	)
+	if err != nil {
+		return QueueDelivery{}, err
+	}
+
+	return QueueDeliveryFromSqlc(row), nil
+}
+
+// AckQueueDelivery completes the agent's lease on a message.
+func (s *txSqlcStore) AckQueueDelivery(ctx context.Context,
+	messageID, agentID int64,
+) (bool, error) {
+	rows, err := s.queries.AckQueueDelivery(ctx, sqlc.AckQueueDeliveryParams{
+		UpdatedAt: time.Now().Unix(),
+		MessageID: messageID,
+		ClaimedBy: sql.NullInt64{Int64: agentID, Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// ReleaseQueueDelivery hands the agent's lease on a message back to the
+// queue.
+func (s *txSqlcStore) ReleaseQueueDelivery(ctx context.Context,
+	messageID, agentID int64, reason string,
+) (bool, error) {
+	rows, err := s.queries.ReleaseQueueDelivery(
+		ctx, sqlc.ReleaseQueueDeliveryParams{
+			LastError: ToSqlcNullString(reason),
+			UpdatedAt: time.Now().Unix(),
+			MessageID: messageID,
+			ClaimedBy: sql.NullInt64{Int64: agentID, Valid: true},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// ExtendQueueLease pushes out the expiry of the agent's lease on a message.
+func (s *txSqlcStore) ExtendQueueLease(ctx context.Context,
+	messageID, agentID int64, expiresAt time.Time,
+) (bool, error) {
+	rows, err := s.queries.ExtendQueueLease(ctx, sqlc.ExtendQueueLeaseParams{
+		LeaseExpiresAt: ToSqlcNullInt64(&expiresAt),
+		UpdatedAt:      time.Now().Unix(),
+		MessageID:      messageID,
+		ClaimedBy:      sql.NullInt64{Int64: agentID, Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// ListExpiredQueueLeases lists leases that expired as of now without being
+// acked.
+func (s *txSqlcStore) ListExpiredQueueLeases(ctx context.Context,
+	now time.Time,
+) ([]QueueDelivery, error) {
+	rows, err := s.queries.ListExpiredQueueLeases(
+		ctx, ToSqlcNullInt64(&now),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]QueueDelivery, len(rows))
+	for i, row := range rows {
+		result[i] = QueueDeliveryFromSqlc(row)
+	}
+
+	return result, nil
+}
+
+// ReleaseExpiredQueueLeases returns expired leases with delivery attempts
+// left to the pending state.
+func (s *txSqlcStore) ReleaseExpiredQueueLeases(ctx context.Context,
+	now time.Time, maxDeliveries int,
+) (int64, error) {
+	return s.queries.ReleaseExpiredQueueLeases(
+		ctx, sqlc.ReleaseExpiredQueueLeasesParams{
+			Now:           now.Unix(),
+			MaxDeliveries: int64(maxDeliveries),
+		},
+	)
+}
+
+// ListExhaustedQueueDeliveries lists unacked messages that have used up
+// their delivery attempts.
+func (s *txSqlcStore) ListExhaustedQueueDeliveries(ctx context.Context,
+	now time.Time, maxDeliveries int,
+) ([]QueueDelivery, error) {
+	rows, err := s.queries.ListExhaustedQueueDeliveries(
+		ctx, sqlc.ListExhaustedQueueDeliveriesParams{
+			MaxDeliveries: int64(maxDeliveries),
+			Now:           ToSqlcNullInt64(&now),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return exhaustedQueueDeliveriesFromSqlc(rows), nil
+}
+
+// MarkQueueDeliveryDeadLettered marks a message as moved to its dead-letter
+// topic.
+func (s *txSqlcStore) MarkQueueDeliveryDeadLettered(ctx context.Context,
+	messageID int64,
+) error {
+	return s.queries.MarkQueueDeliveryDeadLettered(
+		ctx, sqlc.MarkQueueDeliveryDeadLetteredParams{
+			UpdatedAt: time.Now().Unix(),
+			MessageID: messageID,
+		},
+	)
+}
+
+// =============================================================================
+// Conversion helpers
+// =============================================================================
+
+// QueueDeliveryFromSqlc converts a sqlc.QueueDelivery to a QueueDelivery.
+func QueueDeliveryFromSqlc(d sqlc.QueueDelivery) QueueDelivery {
+	return QueueDelivery{
+		MessageID:      d.MessageID,
+		TopicID:        d.TopicID,
+		State:          d.State,
+		ClaimedBy:      d.ClaimedBy.Int64,
+		LeaseExpiresAt: nullInt64ToTime(d.LeaseExpiresAt),
+		DeliveryCount:  int(d.DeliveryCount),
+		LastError:      d.LastError.String,
+		CreatedAt:      time.Unix(d.CreatedAt, 0),
+		UpdatedAt:      time.Unix(d.UpdatedAt, 0),
+	}
+}
+
+// exhaustedQueueDeliveriesFromSqlc converts exhausted delivery rows to their
+// domain type.
+func exhaustedQueueDeliveriesFromSqlc(
+	rows []sqlc.ListExhaustedQueueDeliveriesRow,
+) []QueueDelivery {
+	result := make([]QueueDelivery, len(rows))
+	for i, row := range rows {
+		result[i] = QueueDeliveryFromSqlc(sqlc.QueueDelivery{
+			MessageID:      row.MessageID,
+			TopicID:        row.TopicID,
+			State:          row.State,
+			ClaimedBy:      row.ClaimedBy,
+			LeaseExpiresAt: row.LeaseExpiresAt,
+			DeliveryCount:  row.DeliveryCount,
+			LastError:      row.LastError,
+			CreatedAt:      row.CreatedAt,
+			UpdatedAt:      row.UpdatedAt,
+		})
+		result[i].TopicName = row.TopicName
+	}
+
+	return result
+}
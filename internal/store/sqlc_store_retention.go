@@ -0,0 +1,178 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// RetentionStore implementation for SqlcStore
+// =============================================================================
+
+// SummarizeExpiredMessages summarizes, per topic, the messages that have
+// outlived their topic's retention window.
+func (s *SqlcStore) SummarizeExpiredMessages(ctx context.Context,
+	now time.Time,
+) ([]ExpiredTopicSummary, error) {
+	rows, err := s.db.SummarizeExpiredMessages(ctx, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	return expiredTopicSummariesFromSqlc(rows), nil
+}
+
+// DeleteExpiredMessages deletes a batch of expired, unprotected messages.
+func (s *SqlcStore) DeleteExpiredMessages(ctx context.Context,
+	now time.Time, limit int,
+) (int64, error) {
+	return s.db.DeleteExpiredMessages(ctx, sqlc.DeleteExpiredMessagesParams{
+		Now:        now.Unix(),
+		MaxResults: int64(limit),
+	})
+}
+
+// CountStaleConsumerOffsets counts consumer offsets of unsubscribed agents.
+func (s *SqlcStore) CountStaleConsumerOffsets(ctx context.Context) (int64,
+	error) {
+
+	return s.db.CountStaleConsumerOffsets(ctx)
+}
+
+// DeleteStaleConsumerOffsets deletes consumer offsets of unsubscribed
+// agents.
+func (s *SqlcStore) DeleteStaleConsumerOffsets(ctx context.Context) (int64,
+	error) {
+
+	return s.db.DeleteStaleConsumerOffsets(ctx)
+}
+
+// OptimizeSearchIndex merges the full-text search index.
+func (s *SqlcStore) OptimizeSearchIndex(ctx context.Context) error {
+	return s.db.OptimizeMessagesFTS(ctx)
+}
+
+// SetMessageLegalHold places or lifts a legal hold on a message.
+func (s *SqlcStore) SetMessageLegalHold(ctx context.Context, messageID int64,
+	hold bool,
+) (int64, error) {
+	return s.db.SetMessageLegalHold(ctx, sqlc.SetMessageLegalHoldParams{
+		LegalHold: boolToInt64(hold),
+		ID:        messageID,
+	})
+}
+
+// SetThreadLegalHold places or lifts a legal hold on every message in a
+// thread.
+func (s *SqlcStore) SetThreadLegalHold(ctx context.Context, threadID string,
+	hold bool,
+) (int64, error) {
+	return s.db.SetThreadLegalHold(ctx, sqlc.SetThreadLegalHoldParams{
+		LegalHold: boolToInt64(hold),
+		ThreadID:  threadID,
+	})
+}
+
+// =============================================================================
+// RetentionStore implementation for txSqlcStore
+// =============================================================================
+
+// SummarizeExpiredMessages summarizes, per topic, the messages that have
+// outlived their topic's retention window.
+func (s *txSqlcStore) SummarizeExpiredMessages(ctx context.Context,
+	now time.Time,
+) ([]ExpiredTopicSummary, error) {
+	rows, err := s.queries.SummarizeExpiredMessages(ctx, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+
+	return expiredTopicSummariesFromSqlc(rows), nil
+}
+
+// DeleteExpiredMessages deletes a batch of expired, unprotected messages.
+func (s *txSqlcStore) DeleteExpiredMessages(ctx context.Context,
+	now time.Time, limit int,
+) (int64, error) {
+	return s.queries.DeleteExpiredMessages(
+		ctx, sqlc.DeleteExpiredMessagesParams{
+			Now:        now.Unix(),
+			MaxResults: int64(limit),
+		},
+	)
+}
+
+// CountStaleConsumerOffsets counts consumer offsets of unsubscribed agents.
+func (s *txSqlcStore) CountStaleConsumerOffsets(ctx context.Context) (int64,
+	error) {
+
+	return s.queries.CountStaleConsumerOffsets(ctx)
+}
+
+// DeleteStaleConsumerOffsets deletes consumer offsets of unsubscribed
+// agents.
+func (s *txSqlcStore) DeleteStaleConsumerOffsets(ctx context.Context) (int64,
+	error) {
+
+	return s.queries.DeleteStaleConsumerOffsets(ctx)
+}
+
+// OptimizeSearchIndex merges the full-text search index.
+func (s *txSqlcStore) OptimizeSearchIndex(ctx context.Context) error {
+	return s.queries.OptimizeMessagesFTS(ctx)
+}
+
+// SetMessageLegalHold places or lifts a legal hold on a message.
+func (s *txSqlcStore) SetMessageLegalHold(ctx context.Context,
+	messageID int64, hold bool,
+) (int64, error) {
+	return s.queries.SetMessageLegalHold(
+		ctx, sqlc.SetMessageLegalHoldParams{
+			LegalHold: boolToInt64(hold),
+			ID:        messageID,
+		},
+	)
+}
+
+// SetThreadLegalHold places or lifts a legal hold on every message in a
+// thread.
+func (s *txSqlcStore) SetThreadLegalHold(ctx context.Context,
+	threadID string, hold bool,
+) (int64, error) {
+	return s.queries.SetThreadLegalHold(
+		ctx, sqlc.SetThreadLegalHoldParams{
+			LegalHold: boolToInt64(hold),
+			ThreadID:  threadID,
+		},
+	)
+}
+
+// boolToInt64 converts a bool to the 0/1 integer used for SQLite flags.
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// expiredTopicSummariesFromSqlc converts expired message summary rows.
+func expiredTopicSummariesFromSqlc(
+	rows []sqlc.SummarizeExpiredMessagesRow,
+) []ExpiredTopicSummary {
+	summaries := make([]ExpiredTopicSummary, len(rows))
+	for i, r := range rows {
+		summaries[i] = ExpiredTopicSummary{
+			TopicID:          r.TopicID,
+			TopicName:        r.TopicName,
+			RetentionSeconds: r.RetentionSeconds,
+			ExpiredCount:     r.ExpiredCount,
+			DeletableCount:   r.DeletableCount,
+			RecipientCount:   r.RecipientCount,
+		}
+	}
+
+	return summaries
+}
@@ -0,0 +1,199 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// MessageRevisionStore implementation for SqlcStore
+// =============================================================================
+
+// CreateMessageRevision records a version of a message that an edit is
+// replacing.
+func (s *SqlcStore) CreateMessageRevision(ctx context.Context,
+	params CreateMessageRevisionParams,
+) (MessageRevision, error) {
+	return createMessageRevision(ctx, s.db, params)
+}
+
+// ListMessageRevisions lists the replaced versions of a message, oldest
+// first.
+func (s *SqlcStore) ListMessageRevisions(ctx context.Context,
+	messageID int64,
+) ([]MessageRevision, error) {
+	rows, err := s.db.ListMessageRevisions(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return messageRevisionsFromSqlc(rows), nil
+}
+
+// ListThreadMessageRevisions lists the replaced versions of every message in
+// a thread.
+func (s *SqlcStore) ListThreadMessageRevisions(ctx context.Context,
+	threadID string,
+) ([]MessageRevision, error) {
+	rows, err := s.db.ListThreadMessageRevisions(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return messageRevisionsFromSqlc(rows), nil
+}
+
+// UpdateMessageContent replaces the subject and body of a message with an
+// edited version.
+func (s *SqlcStore) UpdateMessageContent(ctx context.Context, messageID int64,
+	subject, body string, editedAt time.Time,
+) (bool, error) {
+	n, err := s.db.UpdateMessageContent(ctx, updateMessageContentParams(
+		messageID, subject, body, editedAt,
+	))
+	return n > 0, err
+}
+
+// RecallMessage deletes one of a sender's messages that no recipient has read
+// yet.
+func (s *SqlcStore) RecallMessage(ctx context.Context,
+	messageID, senderID int64,
+) (bool, error) {
+	n, err := s.db.RecallMessage(ctx, sqlc.RecallMessageParams{
+		ID:       messageID,
+		SenderID: senderID,
+	})
+	return n > 0, err
+}
+
+// =============================================================================
+// MessageRevisionStore implementation for txSqlcStore
+// =============================================================================
+
+// CreateMessageRevision records a version of a message that an edit is
+// replacing.
+func (s *txSqlcStore) CreateMessageRevision(ctx context.Context,
+	params CreateMessageRevisionParams,
+) (MessageRevision, error) {
+	return createMessageRevision(ctx, s.queries, params)
+}
+
+// ListMessageRevisions lists the replaced versions of a message, oldest
+// first.
+func (s *txSqlcStore) ListMessageRevisions(ctx context.Context,
+	messageID int64,
+) ([]MessageRevision, error) {
+	rows, err := s.queries.ListMessageRevisions(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	return messageRevisionsFromSqlc(rows), nil
+}
+
+// ListThreadMessageRevisions lists the replaced versions of every message in
+// a thread.
+func (s *txSqlcStore) ListThreadMessageRevisions(ctx context.Context,
+	threadID string,
+) ([]MessageRevision, error) {
+	rows, err := s.queries.ListThreadMessageRevisions(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return messageRevisionsFromSqlc(rows), nil
+}
+
+// UpdateMessageContent replaces the subject and body of a message with an
+// edited version.
+func (s *txSqlcStore) UpdateMessageContent(ctx context.Context,
+	messageID int64, subject, body string, editedAt time.Time,
+) (bool, error) {
+	n, err := s.queries.UpdateMessageContent(
+		ctx, updateMessageContentParams(
+			messageID, subject, body, editedAt,
+		),
+	)
+	return n > 0, err
+}
+
+// RecallMessage deletes one of a sender's messages that no recipient has read
+// yet.
+func (s *txSqlcStore) RecallMessage(ctx context.Context,
+	messageID, senderID int64,
+) (bool, error) {
+	n, err := s.queries.RecallMessage(ctx, sqlc.RecallMessageParams{
+		ID:       messageID,
+		SenderID: senderID,
+	})
+	return n > 0, err
+}
+
+// =============================================================================
+// Helper functions
+// =============================================================================
+
+// createMessageRevision records a replaced version of a message.
+func createMessageRevision(ctx context.Context, q QueryStore,
+	params CreateMessageRevisionParams,
+) (MessageRevision, error) {
+	row, err := q.CreateMessageRevision(
+		ctx, sqlc.CreateMessageRevisionParams{
+			MessageID:  params.MessageID,
+			Revision:   int64(params.Revision),
+			Subject:    params.Subject,
+			BodyMd:     params.Body,
+			CreatedAt:  params.CreatedAt.Unix(),
+			ReplacedAt: params.ReplacedAt.Unix(),
+		},
+	)
+	if err != nil {
+		return MessageRevision{}, err
+	}
+
+	return messageRevisionFromSqlc(row), nil
+}
+
+// updateMessageContentParams builds the sqlc parameters for editing a
+// message.
+func updateMessageContentParams(messageID int64, subject, body string,
+	editedAt time.Time,
+) sqlc.UpdateMessageContentParams {
+	return sqlc.UpdateMessageContentParams{
+		Subject: subject,
+		BodyMd:  body,
+		EditedAt: sql.NullInt64{
+			Int64: editedAt.Unix(),
+			Valid: true,
+		},
+		ID: messageID,
+	}
+}
+
+// messageRevisionFromSqlc converts a sqlc message revision to the domain
+// type.
+func messageRevisionFromSqlc(row sqlc.MessageRevision) MessageRevision {
+	return MessageRevision{
+		ID:         row.ID,
+		MessageID:  row.MessageID,
+		Revision:   int(row.Revision),
+		Subject:    row.Subject,
+		Body:       row.BodyMd,
+		CreatedAt:  time.Unix(row.CreatedAt, 0),
+		ReplacedAt: time.Unix(row.ReplacedAt, 0),
+	}
+}
+
+// messageRevisionsFromSqlc converts sqlc message revisions to domain
+// revisions.
+func messageRevisionsFromSqlc(rows []sqlc.MessageRevision) []MessageRevision {
+	revisions := make([]MessageRevision, len(rows))
+	for i, row := range rows {
+		revisions[i] = messageRevisionFromSqlc(row)
+	}
+
+	return revisions
+}
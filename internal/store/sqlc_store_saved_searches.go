@@ -0,0 +1,149 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// SavedSearchStore implementation for SqlcStore
+// =============================================================================
+
+// UpsertSavedSearch saves a search for an agent under a name.
+func (s *SqlcStore) UpsertSavedSearch(ctx context.Context, agentID int64,
+	name, query string,
+) (SavedSearch, error) {
+	return upsertSavedSearch(ctx, s.db, agentID, name, query)
+}
+
+// GetSavedSearch retrieves one of an agent's saved searches by name.
+func (s *SqlcStore) GetSavedSearch(ctx context.Context, agentID int64,
+	name string,
+) (SavedSearch, error) {
+	return getSavedSearch(ctx, s.db, agentID, name)
+}
+
+// ListSavedSearches lists an agent's saved searches by name.
+func (s *SqlcStore) ListSavedSearches(ctx context.Context,
+	agentID int64,
+) ([]SavedSearch, error) {
+	return listSavedSearches(ctx, s.db, agentID)
+}
+
+// DeleteSavedSearch deletes one of an agent's saved searches.
+func (s *SqlcStore) DeleteSavedSearch(ctx context.Context, agentID int64,
+	name string,
+) (bool, error) {
+	n, err := s.db.DeleteSavedSearch(ctx, sqlc.DeleteSavedSearchParams{
+		AgentID: agentID,
+		Name:    name,
+	})
+	return n > 0, err
+}
+
+// =============================================================================
+// SavedSearchStore implementation for txSqlcStore
+// =============================================================================
+
+// UpsertSavedSearch saves a search for an agent under a name.
+func (s *txSqlcStore) UpsertSavedSearch(ctx context.Context, agentID int64,
+	name, query string,
+) (SavedSearch, error) {
+	return upsertSavedSearch(ctx, s.queries, agentID, name, query)
+}
+
+// GetSavedSearch retrieves one of an agent's saved searches by name.
+func (s *txSqlcStore) GetSavedSearch(ctx context.Context, agentID int64,
+	name string,
+) (SavedSearch, error) {
+	return getSavedSearch(ctx, s.queries, agentID, name)
+}
+
+// ListSavedSearches lists an agent's saved searches by name.
+func (s *txSqlcStore) ListSavedSearches(ctx context.Context,
+	agentID int64,
+) ([]SavedSearch, error) {
+	return listSavedSearches(ctx, s.queries, agentID)
+}
+
+// DeleteSavedSearch deletes one of an agent's saved searches.
+func (s *txSqlcStore) DeleteSavedSearch(ctx context.Context, agentID int64,
+	name string,
+) (bool, error) {
+	n, err := s.queries.DeleteSavedSearch(
+		ctx, sqlc.DeleteSavedSearchParams{
+			AgentID: agentID,
+			Name:    name,
+		},
+	)
+	return n > 0, err
+}
+
+// =============================================================================
+// Helper functions
+// =============================================================================
+
+// upsertSavedSearch inserts or replaces a saved search.
+func upsertSavedSearch(ctx context.Context, q QueryStore, agentID int64,
+	name, query string,
+) (SavedSearch, error) {
+	now := time.Now().Unix()
+	row, err := q.UpsertSavedSearch(ctx, sqlc.UpsertSavedSearchParams{
+		AgentID:   agentID,
+		Name:      name,
+		Query:     query,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return SavedSearch{}, err
+	}
+
+	return savedSearchFromSqlc(row), nil
+}
+
+// getSavedSearch fetches one of an agent's saved searches by name.
+func getSavedSearch(ctx context.Context, q QueryStore, agentID int64,
+	name string,
+) (SavedSearch, error) {
+	row, err := q.GetSavedSearch(ctx, sqlc.GetSavedSearchParams{
+		AgentID: agentID,
+		Name:    name,
+	})
+	if err != nil {
+		return SavedSearch{}, err
+	}
+
+	return savedSearchFromSqlc(row), nil
+}
+
+// listSavedSearches lists an agent's saved searches.
+func listSavedSearches(ctx context.Context, q QueryStore,
+	agentID int64,
+) ([]SavedSearch, error) {
+	rows, err := q.ListSavedSearches(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	searches := make([]SavedSearch, len(rows))
+	for i, row := range rows {
+		searches[i] = savedSearchFromSqlc(row)
+	}
+
+	return searches, nil
+}
+
+// savedSearchFromSqlc converts a sqlc saved search row.
+func savedSearchFromSqlc(row sqlc.SavedSearch) SavedSearch {
+	return SavedSearch{
+		ID:        row.ID,
+		AgentID:   row.AgentID,
+		Name:      row.Name,
+		Query:     row.Query,
+		CreatedAt: time.Unix(row.CreatedAt, 0),
+		UpdatedAt: time.Unix(row.UpdatedAt, 0),
+	}
+}
@@ -0,0 +1,345 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// ScheduledMessageStore implementation for SqlcStore
+// =============================================================================
+
+// CreateScheduledMessage queues a message to be sent at its send time.
+func (s *SqlcStore) CreateScheduledMessage(ctx context.Context,
+	params CreateScheduledMessageParams,
+) (ScheduledMessage, error) {
+	return createScheduledMessage(ctx, s.db, params)
+}
+
+// GetScheduledMessageByIdempotencyKey retrieves a scheduled message by the
+// idempotency key it will be sent with.
+func (s *SqlcStore) GetScheduledMessageByIdempotencyKey(ctx context.Context,
+	key string,
+) (ScheduledMessage, error) {
+	row, err := s.db.GetScheduledMessageByIdempotencyKey(ctx, key)
+	if err != nil {
+		return ScheduledMessage{}, err
+	}
+
+	return scheduledMessageFromSqlc(row)
+}
+
+// ListScheduledMessages lists a sender's pending and failed scheduled
+// messages, soonest first.
+func (s *SqlcStore) ListScheduledMessages(ctx context.Context,
+	senderID int64, limit int,
+) ([]ScheduledMessage, error) {
+	rows, err := s.db.ListScheduledMessages(
+		ctx, sqlc.ListScheduledMessagesParams{
+			SenderID: senderID,
+			Limit:    int64(limit),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduledMessagesFromSqlc(rows)
+}
+
+// ListDueScheduledMessages lists pending scheduled messages whose send time
+// is at or before now, oldest first.
+func (s *SqlcStore) ListDueScheduledMessages(ctx context.Context,
+	now time.Time, limit int,
+) ([]ScheduledMessage, error) {
+	rows, err := s.db.ListDueScheduledMessages(
+		ctx, sqlc.ListDueScheduledMessagesParams{
+			SendAt: now.Unix(),
+			Limit:  int64(limit),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduledMessagesFromSqlc(rows)
+}
+
+// CancelScheduledMessage cancels one of a sender's unsent scheduled
+// messages.
+func (s *SqlcStore) CancelScheduledMessage(ctx context.Context, id,
+	senderID int64,
+) (bool, error) {
+	n, err := s.db.CancelScheduledMessage(
+		ctx, sqlc.CancelScheduledMessageParams{
+			UpdatedAt: time.Now().Unix(),
+			ID:        id,
+			SenderID:  senderID,
+		},
+	)
+	return n > 0, err
+}
+
+// MarkScheduledMessageSent records the message a scheduled message was
+// delivered as.
+func (s *SqlcStore) MarkScheduledMessageSent(ctx context.Context, id,
+	messageID int64,
+) error {
+	return s.db.MarkScheduledMessageSent(
+		ctx, sqlc.MarkScheduledMessageSentParams{
+			MessageID: sql.NullInt64{Int64: messageID, Valid: true},
+			UpdatedAt: time.Now().Unix(),
+			ID:        id,
+		},
+	)
+}
+
+// MarkScheduledMessageFailed records why a pending scheduled message could
+// not be delivered.
+func (s *SqlcStore) MarkScheduledMessageFailed(ctx context.Context, id int64,
+	lastError string,
+) error {
+	return s.db.MarkScheduledMessageFailed(
+		ctx, sqlc.MarkScheduledMessageFailedParams{
+			LastError: lastError,
+			UpdatedAt: time.Now().Unix(),
+			ID:        id,
+		},
+	)
+}
+
+// DeferScheduledMessage pushes a pending scheduled message's send time back,
+// recording why it couldn't be sent yet.
+func (s *SqlcStore) DeferScheduledMessage(ctx context.Context, id int64,
+	sendAt time.Time, lastError string,
+) error {
+	return s.db.DeferScheduledMessage(
+		ctx, sqlc.DeferScheduledMessageParams{
+			SendAt:    sendAt.Unix(),
+			LastError: lastError,
+			UpdatedAt: time.Now().Unix(),
+			ID:        id,
+		},
+	)
+}
+
+// =============================================================================
+// ScheduledMessageStore implementation for txSqlcStore
+// =============================================================================
+
+// CreateScheduledMessage queues a message to be sent at its send time.
+func (s *txSqlcStore) CreateScheduledMessage(ctx context.Context,
+	params CreateScheduledMessageParams,
+) (ScheduledMessage, error) {
+	return createScheduledMessage(ctx, s.queries, params)
+}
+
+// GetScheduledMessageByIdempotencyKey retrieves a scheduled message by the
+// idempotency key it will be sent with.
+func (s *txSqlcStore) GetScheduledMessageByIdempotencyKey(
+	ctx context.Context, key string,
+) (ScheduledMessage, error) {
+	row, err := s.queries.GetScheduledMessageByIdempotencyKey(ctx, key)
+	if err != nil {
+		return ScheduledMessage{}, err
+	}
+
+	return scheduledMessageFromSqlc(row)
+}
+
+// ListScheduledMessages lists a sender's pending and failed scheduled
+// messages, soonest first.
+func (s *txSqlcStore) ListScheduledMessages(ctx context.Context,
+	senderID int64, limit int,
+) ([]ScheduledMessage, error) {
+	rows, err := s.queries.ListScheduledMessages(
+		ctx, sqlc.ListScheduledMessagesParams{
+			SenderID: senderID,
+			Limit:    int64(limit),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduledMessagesFromSqlc(rows)
+}
+
+// ListDueScheduledMessages lists pending scheduled messages whose send time
+// is at or before now, oldest first.
+func (s *txSqlcStore) ListDueScheduledMessages(ctx context.Context,
+	now time.Time, limit int,
+) ([]ScheduledMessage, error) {
+	rows, err := s.queries.ListDueScheduledMessages(
+		ctx, sqlc.ListDueScheduledMessagesParams{
+			SendAt: now.Unix(),
+			Limit:  int64(limit),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduledMessagesFromSqlc(rows)
+}
+
+// CancelScheduledMessage cancels one of a sender's unsent scheduled
+// messages.
+func (s *txSqlcStore) CancelScheduledMessage(ctx context.Context, id,
+	senderID int64,
+) (bool, error) {
+	n, err := s.queries.CancelScheduledMessage(
+		ctx, sqlc.CancelScheduledMessageParams{
+			UpdatedAt: time.Now().Unix(),
+			ID:        id,
+			SenderID:  senderID,
+		},
+	)
+	return n > 0, err
+}
+
+// MarkScheduledMessageSent records the message a scheduled message was
+// delivered as.
+func (s *txSqlcStore) MarkScheduledMessageSent(ctx context.Context, id,
+	messageID int64,
+) error {
+	return s.queries.MarkScheduledMessageSent(
+		ctx, sqlc.MarkScheduledMessageSentParams{
+			MessageID: sql.NullInt64{Int64: messageID, Valid: true},
+			UpdatedAt: time.Now().Unix(),
+			ID:        id,
+		},
+	)
+}
+
+// MarkScheduledMessageFailed records why a pending scheduled message could
+// not be delivered.
+func (s *txSqlcStore) MarkScheduledMessageFailed(ctx context.Context,
+	id int64, lastError string,
+) error {
+	return s.queries.MarkScheduledMessageFailed(
+		ctx, sqlc.MarkScheduledMessageFailedParams{
+			LastError: lastError,
+			UpdatedAt: time.Now().Unix(),
+			ID:        id,
+		},
+	)
+}
+
+// DeferScheduledMessage pushes a pending scheduled message's send time back,
+// recording why it couldn't be sent yet.
+func (s *txSqlcStore) DeferScheduledMessage(ctx context.Context,
+	id int64, sendAt time.Time, lastError string,
+) error {
+	return s.queries.DeferScheduledMessage(
+		ctx, sqlc.DeferScheduledMessageParams{
+			SendAt:    sendAt.Unix(),
+			LastError: lastError,
+			UpdatedAt: time.Now().Unix(),
+			ID:        id,
+		},
+	)
+}
+
+// =============================================================================
+// Conversion helpers
+// =============================================================================
+
+// createScheduledMessage encodes the recipient list and stores a scheduled
+// message.
+func createScheduledMessage(ctx context.Context, q QueryStore,
+	params CreateScheduledMessageParams,
+) (ScheduledMessage, error) {
+	recipients := params.RecipientNames
+	if recipients == nil {
+		recipients = []string{}
+	}
+	encoded, err := json.Marshal(recipients)
+	if err != nil {
+		return ScheduledMessage{}, fmt.Errorf("failed to encode "+
+			"recipients: %w", err)
+	}
+
+	now := time.Now().Unix()
+	row, err := q.CreateScheduledMessage(
+		ctx, sqlc.CreateScheduledMessageParams{
+			SenderID:       params.SenderID,
+			Recipients:     string(encoded),
+			TopicName:      params.TopicName,
+			ThreadID:       params.ThreadID,
+			Subject:        params.Subject,
+			Body:           params.Body,
+			Priority:       params.Priority,
+			DeadlineAt:     ToSqlcNullInt64(params.DeadlineAt),
+			Attachments:    params.Attachments,
+			Metadata:       params.Metadata,
+			DeliveryMode:   params.DeliveryMode,
+			IdempotencyKey: params.IdempotencyKey,
+			SendAt:         params.SendAt.Unix(),
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		},
+	)
+	if err != nil {
+		return ScheduledMessage{}, err
+	}
+
+	return scheduledMessageFromSqlc(row)
+}
+
+// scheduledMessageFromSqlc converts a sqlc scheduled message to the domain
+// type, decoding its recipient list.
+func scheduledMessageFromSqlc(
+	row sqlc.ScheduledMessage,
+) (ScheduledMessage, error) {
+	var recipients []string
+	err := json.Unmarshal([]byte(row.Recipients), &recipients)
+	if err != nil {
+		return ScheduledMessage{}, fmt.Errorf("invalid recipients for "+
+			"scheduled message %d: %w", row.ID, err)
+	}
+
+	return ScheduledMessage{
+		ID:             row.ID,
+		SenderID:       row.SenderID,
+		RecipientNames: recipients,
+		TopicName:      row.TopicName,
+		ThreadID:       row.ThreadID,
+		Subject:        row.Subject,
+		Body:           row.Body,
+		Priority:       row.Priority,
+		DeadlineAt:     nullInt64ToTime(row.DeadlineAt),
+		Attachments:    row.Attachments,
+		Metadata:       row.Metadata,
+		DeliveryMode:   row.DeliveryMode,
+		IdempotencyKey: row.IdempotencyKey,
+		SendAt:         time.Unix(row.SendAt, 0),
+		State:          row.State,
+		MessageID:      row.MessageID.Int64,
+		LastError:      row.LastError,
+		CreatedAt:      time.Unix(row.CreatedAt, 0),
+		UpdatedAt:      time.Unix(row.UpdatedAt, 0),
+	}, nil
+}
+
+// scheduledMessagesFromSqlc converts sqlc scheduled messages to domain
+// scheduled messages.
+func scheduledMessagesFromSqlc(
+	rows []sqlc.ScheduledMessage,
+) ([]ScheduledMessage, error) {
+	msgs := make([]ScheduledMessage, len(rows))
+	for i, row := range rows {
+		msg, err := scheduledMessageFromSqlc(row)
+		if err != nil {
+			return nil, err
+		}
+		msgs[i] = msg
+	}
+
+	return msgs, nil
+}
@@ -0,0 +1,333 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// ScheduleStore implementation for SqlcStore
+// =============================================================================
+
+// CreateSchedule creates a recurring schedule.
+func (s *SqlcStore) CreateSchedule(ctx context.Context,
+	params CreateScheduleParams,
+) (Schedule, error) {
+	return createSchedule(ctx, s.db, params)
+}
+
+// GetScheduleByName retrieves one of an agent's schedules by name.
+func (s *SqlcStore) GetScheduleByName(ctx context.Context, senderID int64,
+	name string,
+) (Schedule, error) {
+	row, err := s.db.GetScheduleByName(ctx, sqlc.GetScheduleByNameParams{
+		SenderID: senderID,
+		Name:     name,
+	})
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	return scheduleFromSqlc(row)
+}
+
+// ListSchedules lists an agent's schedules by name.
+func (s *SqlcStore) ListSchedules(ctx context.Context,
+	senderID int64,
+) ([]Schedule, error) {
+	rows, err := s.db.ListSchedules(ctx, senderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return schedulesFromSqlc(rows)
+}
+
+// ListDueSchedules lists schedules that are not paused and whose next run is
+// at or before now, oldest first.
+func (s *SqlcStore) ListDueSchedules(ctx context.Context, now time.Time,
+	limit int,
+) ([]Schedule, error) {
+	rows, err := s.db.ListDueSchedules(ctx, sqlc.ListDueSchedulesParams{
+		NextRunAt: now.Unix(),
+		Limit:     int64(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schedulesFromSqlc(rows)
+}
+
+// DeleteSchedule deletes one of an agent's schedules.
+func (s *SqlcStore) DeleteSchedule(ctx context.Context, senderID int64,
+	name string,
+) (bool, error) {
+	n, err := s.db.DeleteSchedule(ctx, sqlc.DeleteScheduleParams{
+		SenderID: senderID,
+		Name:     name,
+	})
+	return n > 0, err
+}
+
+// SetSchedulePaused pauses or resumes one of an agent's schedules and sets
+// its next run.
+func (s *SqlcStore) SetSchedulePaused(ctx context.Context, senderID int64,
+	name string, paused bool, nextRunAt time.Time,
+) (bool, error) {
+	return setSchedulePaused(ctx, s.db, senderID, name, paused, nextRunAt)
+}
+
+// RecordScheduleRun records a run of a schedule and moves it on to its next
+// occurrence.
+func (s *SqlcStore) RecordScheduleRun(ctx context.Context,
+	params RecordScheduleRunParams,
+) error {
+	return s.db.RecordScheduleRun(ctx, recordScheduleRunParams(params))
+}
+
+// AdvanceSchedule moves a schedule on to its next occurrence without
+// recording a run.
+func (s *SqlcStore) AdvanceSchedule(ctx context.Context, id int64,
+	nextRunAt time.Time,
+) error {
+	return s.db.AdvanceSchedule(ctx, sqlc.AdvanceScheduleParams{
+		NextRunAt: nextRunAt.Unix(),
+		UpdatedAt: time.Now().Unix(),
+		ID:        id,
+	})
+}
+
+// =============================================================================
+// ScheduleStore implementation for txSqlcStore
+// =============================================================================
+
+// CreateSchedule creates a recurring schedule.
+func (s *txSqlcStore) CreateSchedule(ctx context.Context,
+	params CreateScheduleParams,
+) (Schedule, error) {
+	return createSchedule(ctx, s.queries, params)
+}
+
+// GetScheduleByName retrieves one of an agent's schedules by name.
+func (s *txSqlcStore) GetScheduleByName(ctx context.Context, senderID int64,
+	name string,
+) (Schedule, error) {
+	row, err := s.queries.GetScheduleByName(
+		ctx, sqlc.GetScheduleByNameParams{
+			SenderID: senderID,
+			Name:     name,
+		},
+	)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	return scheduleFromSqlc(row)
+}
+
+// ListSchedules lists an agent's schedules by name.
+func (s *txSqlcStore) ListSchedules(ctx context.Context,
+	senderID int64,
+) ([]Schedule, error) {
+	rows, err := s.queries.ListSchedules(ctx, senderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return schedulesFromSqlc(rows)
+}
+
+// ListDueSchedules lists schedules that are not paused and whose next run is
+// at or before now, oldest first.
+func (s *txSqlcStore) ListDueSchedules(ctx context.Context, now time.Time,
+	limit int,
+) ([]Schedule, error) {
+	rows, err := s.queries.ListDueSchedules(
+		ctx, sqlc.ListDueSchedulesParams{
+			NextRunAt: now.Unix(),
+			Limit:     int64(limit),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return schedulesFromSqlc(rows)
+}
+
+// DeleteSchedule deletes one of an agent's schedules.
+func (s *txSqlcStore) DeleteSchedule(ctx context.Context, senderID int64,
+	name string,
+) (bool, error) {
+	n, err := s.queries.DeleteSchedule(ctx, sqlc.DeleteScheduleParams{
+		SenderID: senderID,
+		Name:     name,
+	})
+	return n > 0, err
+}
+
+// SetSchedulePaused pauses or resumes one of an agent's schedules and sets
+// its next run.
+func (s *txSqlcStore) SetSchedulePaused(ctx context.Context, senderID int64,
+	name string, paused bool, nextRunAt time.Time,
+) (bool, error) {
+	return setSchedulePaused(
+		ctx, s.queries, senderID, name, paused, nextRunAt,
+	)
+}
+
+// RecordScheduleRun records a run of a schedule and moves it on to its next
+// occurrence.
+func (s *txSqlcStore) RecordScheduleRun(ctx context.Context,
+	params RecordScheduleRunParams,
+) error {
+	return s.queries.RecordScheduleRun(
+		ctx, recordScheduleRunParams(params),
+	)
+}
+
+// AdvanceSchedule moves a schedule on to its next occurrence without
+// recording a run.
+func (s *txSqlcStore) AdvanceSchedule(ctx context.Context, id int64,
+	nextRunAt time.Time,
+) error {
+	return s.queries.AdvanceSchedule(ctx, sqlc.AdvanceScheduleParams{
+		NextRunAt: nextRunAt.Unix(),
+		UpdatedAt: time.Now().Unix(),
+		ID:        id,
+	})
+}
+
+// =============================================================================
+// Conversion helpers
+// =============================================================================
+
+// createSchedule encodes the recipient list and stores a schedule.
+func createSchedule(ctx context.Context, q QueryStore,
+	params CreateScheduleParams,
+) (Schedule, error) {
+	recipients := params.RecipientNames
+	if recipients == nil {
+		recipients = []string{}
+	}
+	encoded, err := json.Marshal(recipients)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("failed to encode recipients: %w",
+			err)
+	}
+
+	now := time.Now().Unix()
+	row, err := q.CreateSchedule(ctx, sqlc.CreateScheduleParams{
+		Name:       params.Name,
+		SenderID:   params.SenderID,
+		Recipients: string(encoded),
+		TopicName:  params.TopicName,
+		Subject:    params.Subject,
+		Body:       params.Body,
+		Priority:   params.Priority,
+		CronExpr:   params.CronExpr,
+		Timezone:   params.Timezone,
+		CatchUp:    params.CatchUp,
+		NextRunAt:  params.NextRunAt.Unix(),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	return scheduleFromSqlc(row)
+}
+
+// setSchedulePaused updates the paused flag and next run of a schedule.
+func setSchedulePaused(ctx context.Context, q QueryStore, senderID int64,
+	name string, paused bool, nextRunAt time.Time,
+) (bool, error) {
+	var pausedInt int64
+	if paused {
+		pausedInt = 1
+	}
+
+	n, err := q.SetSchedulePaused(ctx, sqlc.SetSchedulePausedParams{
+		Paused:    pausedInt,
+		NextRunAt: nextRunAt.Unix(),
+		UpdatedAt: time.Now().Unix(),
+		SenderID:  senderID,
+		Name:      name,
+	})
+	return n > 0, err
+}
+
+// recordScheduleRunParams converts schedule run parameters to their sqlc
+// form.
+func recordScheduleRunParams(
+	params RecordScheduleRunParams,
+) sqlc.RecordScheduleRunParams {
+	return sqlc.RecordScheduleRunParams{
+		NextRunAt: params.NextRunAt.Unix(),
+		LastRunAt: sql.NullInt64{
+			Int64: params.RunAt.Unix(),
+			Valid: true,
+		},
+		LastMessageID: sql.NullInt64{
+			Int64: params.MessageID,
+			Valid: params.MessageID != 0,
+		},
+		LastError: params.LastError,
+		UpdatedAt: time.Now().Unix(),
+		ID:        params.ID,
+	}
+}
+
+// scheduleFromSqlc converts a sqlc schedule to the domain type, decoding its
+// recipient list.
+func scheduleFromSqlc(row sqlc.Schedule) (Schedule, error) {
+	var recipients []string
+	err := json.Unmarshal([]byte(row.Recipients), &recipients)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid recipients for "+
+			"schedule %d: %w", row.ID, err)
+	}
+
+	return Schedule{
+		ID:             row.ID,
+		Name:           row.Name,
+		SenderID:       row.SenderID,
+		RecipientNames: recipients,
+		TopicName:      row.TopicName,
+		Subject:        row.Subject,
+		Body:           row.Body,
+		Priority:       row.Priority,
+		CronExpr:       row.CronExpr,
+		Timezone:       row.Timezone,
+		CatchUp:        row.CatchUp,
+		Paused:         row.Paused != 0,
+		NextRunAt:      time.Unix(row.NextRunAt, 0),
+		LastRunAt:      nullInt64ToTime(row.LastRunAt),
+		LastMessageID:  row.LastMessageID.Int64,
+		LastError:      row.LastError,
+		RunCount:       row.RunCount,
+		CreatedAt:      time.Unix(row.CreatedAt, 0),
+		UpdatedAt:      time.Unix(row.UpdatedAt, 0),
+	}, nil
+}
+
+// schedulesFromSqlc converts sqlc schedules to domain schedules.
+func schedulesFromSqlc(rows []sqlc.Schedule) ([]Schedule, error) {
+	schedules := make([]Schedule, len(rows))
+	for i, row := range rows {
+		sched, err := scheduleFromSqlc(row)
+		if err != nil {
+			return nil, err
+		}
+		schedules[i] = sched
+	}
+
+	return schedules, nil
+}
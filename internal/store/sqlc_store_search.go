@@ -0,0 +1,301 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/search"
+)
+
+// =============================================================================
+// Filtered search for SqlcStore and txSqlcStore
+// =============================================================================
+
+// SearchMessagesWithFilters runs a full-text search narrowed by the filters
+// of a parsed search query.
+func (s *SqlcStore) SearchMessagesWithFilters(ctx context.Context,
+	params MessageSearchParams,
+) ([]MessageSearchHit, error) {
+	return searchMessagesWithFilters(ctx, s.sqlDB, params)
+}
+
+// CountMessagesWithFilters returns the number of messages a filtered search
+// matches.
+func (s *SqlcStore) CountMessagesWithFilters(ctx context.Context,
+	params MessageSearchParams,
+) (int64, error) {
+	return countMessagesWithFilters(ctx, s.sqlDB, params)
+}
+
+// SearchMessagesWithFilters runs a full-text search narrowed by the filters
+// of a parsed search query.
+func (s *txSqlcStore) SearchMessagesWithFilters(ctx context.Context,
+	params MessageSearchParams,
+) ([]MessageSearchHit, error) {
+	return searchMessagesWithFilters(ctx, s.sqlDB, params)
+}
+
+// CountMessagesWithFilters returns the number of messages a filtered search
+// matches.
+func (s *txSqlcStore) CountMessagesWithFilters(ctx context.Context,
+	params MessageSearchParams,
+) (int64, error) {
+	return countMessagesWithFilters(ctx, s.sqlDB, params)
+}
+
+// =============================================================================
+// Helper functions
+// =============================================================================
+
+// messageSearchFrom builds the FROM and WHERE clauses shared by the search
+// and count queries, joining each message's sender. Only the filters that
+// are set add clauses, and every value is bound as a parameter.
+func messageSearchFrom(p MessageSearchParams) (string, []any) {
+	var (
+		joins, where []string
+		joinArgs     []any
+		whereArgs    []any
+	)
+	filter := func(clause string, args ...any) {
+		where = append(where, clause)
+		whereArgs = append(whereArgs, args...)
+	}
+
+	// An agent sees the messages it received, with its own state for
+	// them, and the ones it sent and didn't delete.
+	if p.AgentID != 0 {
+		joins = append(joins, "LEFT JOIN message_recipients mr "+
+			"ON mr.message_id = m.id AND mr.agent_id = ?")
+		joinArgs = append(joinArgs, p.AgentID)
+		filter("(mr.agent_id IS NOT NULL OR (m.sender_id = ? "+
+			"AND m.deleted_by_sender = 0))", p.AgentID)
+	}
+
+	if p.Match != "" {
+		joins = append(joins,
+			"JOIN messages_fts ON messages_fts.rowid = m.id")
+		filter("messages_fts MATCH ?", p.Match)
+	}
+	if p.Exclude != "" {
+		filter("m.id NOT IN (SELECT rowid FROM messages_fts "+
+			"WHERE messages_fts MATCH ?)", p.Exclude)
+	}
+
+	if p.SenderID != 0 {
+		filter("m.sender_id = ?", p.SenderID)
+	}
+	if p.RecipientID != 0 {
+		filter("EXISTS (SELECT 1 FROM message_recipients r "+
+			"WHERE r.message_id = m.id AND r.agent_id = ?)",
+			p.RecipientID)
+	}
+	if p.TopicID != 0 {
+		filter("m.topic_id = ?", p.TopicID)
+	}
+	if p.ThreadID != "" {
+		filter("m.thread_id = ?", p.ThreadID)
+	}
+	if p.Priority != "" {
+		filter("m.priority = ?", p.Priority)
+	}
+
+	switch {
+	case p.State != "" && p.AgentID != 0:
+		filter("mr.state = ?", p.State)
+
+	case p.State != "":
+		filter("EXISTS (SELECT 1 FROM message_recipients r "+
+			"WHERE r.message_id = m.id AND r.state = ?)", p.State)
+	}
+
+	if p.Label != "" {
+		filter("EXISTS (SELECT 1 FROM message_labels ml "+
+			"JOIN labels l ON l.id = ml.label_id "+
+			"WHERE ml.message_id = m.id AND ml.agent_id = ? "+
+			"AND l.name = ?)", p.AgentID, p.Label)
+	}
+	if p.After != nil {
+		filter("m.created_at >= ?", p.After.Unix())
+	}
+	if p.Before != nil {
+		filter("m.created_at < ?", p.Before.Unix())
+	}
+	if p.BodyContains != "" {
+		filter("instr(m.body_md, ?) > 0", p.BodyContains)
+	}
+	if p.HasAttachments {
+		filter("COALESCE(m.attachments, '') NOT IN ('', '[]', 'null')")
+	}
+	if len(p.MessageIDs) > 0 {
+		ids := make([]any, len(p.MessageIDs))
+		for i, id := range p.MessageIDs {
+			ids[i] = id
+		}
+		filter("m.id IN (?"+strings.Repeat(", ?", len(ids)-1)+")",
+			ids...)
+	}
+
+	from := "FROM messages m\n\t\tLEFT JOIN agents a " +
+		"ON a.id = m.sender_id\n\t\t" + strings.Join(joins, "\n\t\t")
+	if len(where) > 0 {
+		from += "\n\t\tWHERE " + strings.Join(where, "\n\t\t  AND ")
+	}
+
+	return from, append(joinArgs, whereArgs...)
+}
+
+// countMessagesWithFilters runs the count query behind
+// CountMessagesWithFilters.
+func countMessagesWithFilters(ctx context.Context, db *sql.DB,
+	p MessageSearchParams,
+) (int64, error) {
+	from, args := messageSearchFrom(p)
+
+	var count int64
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) "+from, args...).
+		Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	return count, nil
+}
+
+// searchMessagesWithFilters builds and runs the raw FTS5 query behind
+// SearchMessagesWithFilters. Queries with terms are ordered by their BM25
+// rank and get snippets around the matched terms; the others are ordered
+// newest first and get the start of the body.
+func searchMessagesWithFilters(ctx context.Context, db *sql.DB,
+	p MessageSearchParams,
+) ([]MessageSearchHit, error) {
+	from, fromArgs := messageSearchFrom(p)
+
+	var selectArgs []any
+	bodyCol := "''"
+	if p.IncludeBody {
+		bodyCol = "m.body_md"
+	}
+
+	// The rank, snippet and highlighted subject columns.
+	rankCols := fmt.Sprintf("0.0 AS score, substr(m.body_md, 1, %d), "+
+		"m.subject", 4*search.ExcerptRunes)
+	ranked := p.Match != ""
+	if ranked {
+		weight := p.SubjectWeight
+		if weight <= 0 {
+			weight = search.DefaultSubjectWeight
+		}
+		rankCols = "bm25(messages_fts, ?, 1.0) AS score, " +
+			"snippet(messages_fts, 1, ?, ?, ?, ?), " +
+			"highlight(messages_fts, 0, ?, ?)"
+		selectArgs = append(selectArgs, weight,
+			search.HighlightStart, search.HighlightEnd,
+			search.SnippetEllipsis, search.SnippetTokens,
+			search.HighlightStart, search.HighlightEnd)
+	}
+
+	recipientCols := "'', NULL, NULL, NULL"
+	if p.AgentID != 0 {
+		recipientCols = "COALESCE(mr.state, ''), mr.snoozed_until, " +
+			"mr.read_at, mr.acked_at"
+	}
+
+	// The outer query pages through the ranked rows, ties going to the
+	// newest message.
+	order := "created_at DESC, id DESC"
+	if ranked {
+		order = "score, id DESC"
+	}
+
+	var (
+		after     string
+		afterArgs []any
+	)
+	switch c := p.Cursor; {
+	case c != nil && ranked:
+		after = "\n\t\tWHERE score > ? OR (score = ? AND id < ?)"
+		afterArgs = []any{c.Rank, c.Rank, c.ID}
+
+	case c != nil:
+		after = "\n\t\tWHERE created_at < ? OR " +
+			"(created_at = ? AND id < ?)"
+		afterArgs = []any{c.CreatedAt, c.CreatedAt, c.ID}
+	}
+
+	query := `
+		SELECT * FROM (
+		SELECT m.id AS id, m.thread_id, m.topic_id, m.log_offset,
+		       m.sender_id, m.subject, ` + bodyCol + `, m.priority,
+		       m.deadline_at, m.attachments,
+		       m.created_at AS created_at, m.edited_at,
+		       COALESCE(a.name, ''), COALESCE(a.project_key, ''),
+		       COALESCE(a.git_branch, ''), ` + recipientCols + `,
+		       ` + rankCols + `
+		` + from + `
+		)` + after + `
+		ORDER BY ` + order + `
+		LIMIT ?`
+
+	args := append(selectArgs, fromArgs...)
+	args = append(args, afterArgs...)
+	args = append(args, p.Limit)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search messages: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []MessageSearchHit
+	for rows.Next() {
+		var (
+			hit                MessageSearchHit
+			deadlineAt, editAt sql.NullInt64
+			snoozedUntil       sql.NullInt64
+			readAt, ackedAt    sql.NullInt64
+			attachments        sql.NullString
+			createdAt          int64
+		)
+		err := rows.Scan(
+			&hit.ID, &hit.ThreadID, &hit.TopicID, &hit.LogOffset,
+			&hit.SenderID, &hit.Subject, &hit.Body, &hit.Priority,
+			&deadlineAt, &attachments, &createdAt, &editAt,
+			&hit.SenderName, &hit.SenderProjectKey,
+			&hit.SenderGitBranch, &hit.State, &snoozedUntil,
+			&readAt, &ackedAt, &hit.Rank, &hit.Snippet,
+			&hit.SubjectHighlight,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search "+
+				"result: %w", err)
+		}
+
+		hit.DeadlineAt = nullInt64ToTime(deadlineAt)
+		hit.Attachments = attachments.String
+		hit.CreatedAt = time.Unix(createdAt, 0)
+		hit.EditedAt = nullInt64ToTime(editAt)
+		hit.SnoozedUntil = nullInt64ToTime(snoozedUntil)
+		hit.ReadAt = nullInt64ToTime(readAt)
+		hit.AckedAt = nullInt64ToTime(ackedAt)
+		if ranked {
+			hit.Snippet = strings.Join(
+				strings.Fields(hit.Snippet), " ",
+			)
+		} else {
+			hit.Snippet = search.Excerpt(
+				hit.Snippet, search.ExcerptRunes,
+			)
+		}
+
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating search results: %w",
+			err)
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,319 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/search"
+)
+
+// =============================================================================
+// Search across entity types for SqlcStore and txSqlcStore
+// =============================================================================
+
+// SearchAll runs a full-text search over the kinds of entities the params
+// select.
+func (s *SqlcStore) SearchAll(ctx context.Context,
+	params SearchAllParams,
+) ([]SearchAllHit, error) {
+	return searchAll(ctx, s.sqlDB, params)
+}
+
+// CountSearchAll returns the number of entities a search matches.
+func (s *SqlcStore) CountSearchAll(ctx context.Context,
+	params SearchAllParams,
+) (int64, error) {
+	return countSearchAll(ctx, s.sqlDB, params)
+}
+
+// SearchAll runs a full-text search over the kinds of entities the params
+// select.
+func (s *txSqlcStore) SearchAll(ctx context.Context,
+	params SearchAllParams,
+) ([]SearchAllHit, error) {
+	return searchAll(ctx, s.sqlDB, params)
+}
+
+// CountSearchAll returns the number of entities a search matches.
+func (s *txSqlcStore) CountSearchAll(ctx context.Context,
+	params SearchAllParams,
+) (int64, error) {
+	return countSearchAll(ctx, s.sqlDB, params)
+}
+
+// =============================================================================
+// Helper functions
+// =============================================================================
+
+// searchSource describes how one kind of entity is searched. The entity's
+// table is aliased t.
+type searchSource struct {
+	kind search.Kind
+
+	// fts and table are the FTS5 index and the table it indexes.
+	fts, table string
+
+	// joins are the extra joins the columns need.
+	joins string
+
+	// ref, threadID and createdAt are the expressions of the hit's
+	// columns.
+	ref, threadID, createdAt string
+
+	// title is the title expression for entities whose first indexed
+	// column isn't a title, and empty for those whose is, which get it
+	// highlighted and weighted.
+	title string
+
+	// snippetCol is the indexed column snippets are cut from, or -1 to
+	// let FTS5 pick the best matching one.
+	snippetCol int
+
+	// where is an extra condition on the entities searched.
+	where string
+}
+
+// searchSources are the searchable kinds of entities, in the order of
+// search.Kinds.
+var searchSources = []searchSource{{
+	kind:       search.KindMessage,
+	fts:        "messages_fts",
+	table:      "messages",
+	ref:        "''",
+	threadID:   "t.thread_id",
+	createdAt:  "t.created_at",
+	snippetCol: 1,
+}, {
+	kind:       search.KindReviewIssue,
+	fts:        "review_issues_fts",
+	table:      "review_issues",
+	joins:      "LEFT JOIN reviews r ON r.review_id = t.review_id",
+	ref:        "t.review_id",
+	threadID:   "COALESCE(r.thread_id, '')",
+	createdAt:  "t.created_at",
+	snippetCol: 1,
+}, {
+	kind:       search.KindPlanReview,
+	fts:        "plan_reviews_fts",
+	table:      "plan_reviews",
+	ref:        "t.plan_review_id",
+	threadID:   "t.thread_id",
+	createdAt:  "t.created_at",
+	snippetCol: 1,
+}, {
+	kind:       search.KindTask,
+	fts:        "agent_tasks_fts",
+	table:      "agent_tasks",
+	ref:        "t.list_id",
+	threadID:   "''",
+	createdAt:  "t.created_at",
+	snippetCol: 1,
+	where:      "t.status != 'deleted'",
+}, {
+	kind:  search.KindPlanAnnotation,
+	fts:   "plan_annotations_fts",
+	table: "plan_annotations",
+	joins: "LEFT JOIN plan_reviews pr " +
+		"ON pr.plan_review_id = t.plan_review_id",
+	ref:        "t.plan_review_id",
+	threadID:   "COALESCE(pr.thread_id, '')",
+	createdAt:  "t.created_at",
+	title:      "COALESCE(pr.plan_title, '')",
+	snippetCol: -1,
+}, {
+	kind:       search.KindDiffAnnotation,
+	fts:        "diff_annotations_fts",
+	table:      "diff_annotations",
+	joins:      "LEFT JOIN messages dm ON dm.id = t.message_id",
+	ref:        "CAST(t.message_id AS TEXT)",
+	threadID:   "COALESCE(dm.thread_id, '')",
+	createdAt:  "t.created_at",
+	title:      "t.file_path || ':' || t.line_start",
+	snippetCol: 0,
+}}
+
+// from builds the FROM and WHERE clauses that find the source's entities
+// matching a search. Messages are narrowed to an agent's if the params
+// name one.
+func (src searchSource) from(p SearchAllParams) (string, []any) {
+	var (
+		where     []string
+		joinArgs  []any
+		whereArgs []any
+	)
+	filter := func(clause string, args ...any) {
+		where = append(where, clause)
+		whereArgs = append(whereArgs, args...)
+	}
+
+	joins := src.joins
+	if src.kind == search.KindMessage && p.AgentID != 0 {
+		joins = "LEFT JOIN message_recipients mr " +
+			"ON mr.message_id = t.id AND mr.agent_id = ?"
+		joinArgs = append(joinArgs, p.AgentID)
+		filter("(mr.agent_id IS NOT NULL OR (t.sender_id = ? "+
+			"AND t.deleted_by_sender = 0))", p.AgentID)
+	}
+
+	filter(src.fts+" MATCH ?", p.Match)
+	if p.Exclude != "" {
+		filter(fmt.Sprintf("t.id NOT IN (SELECT rowid FROM %s "+
+			"WHERE %s MATCH ?)", src.fts, src.fts), p.Exclude)
+	}
+	if p.After != nil {
+		filter(src.createdAt+" >= ?", p.After.Unix())
+	}
+	if p.Before != nil {
+		filter(src.createdAt+" < ?", p.Before.Unix())
+	}
+	if src.where != "" {
+		filter(src.where)
+	}
+
+	from := fmt.Sprintf("FROM %s\n\t\tJOIN %s t ON t.id = %s.rowid",
+		src.fts, src.table, src.fts)
+	if joins != "" {
+		from += "\n\t\t" + joins
+	}
+	from += "\n\t\tWHERE " + strings.Join(where, "\n\t\t  AND ")
+
+	return from, append(joinArgs, whereArgs...)
+}
+
+// selectHits builds the query selecting the source's hits.
+func (src searchSource) selectHits(p SearchAllParams) (string, []any) {
+	weight := p.TitleWeight
+	if weight <= 0 {
+		weight = search.DefaultSubjectWeight
+	}
+
+	var args []any
+	title, rank := src.title, "bm25("+src.fts+")"
+	if title == "" {
+		title = "COALESCE(highlight(" + src.fts + ", 0, ?, ?), '')"
+		rank = "bm25(" + src.fts + ", ?, 1.0)"
+		args = append(args, search.HighlightStart, search.HighlightEnd)
+	}
+	args = append(args, search.HighlightStart, search.HighlightEnd,
+		search.SnippetEllipsis, search.SnippetTokens)
+	if src.title == "" {
+		args = append(args, weight)
+	}
+
+	from, fromArgs := src.from(p)
+	query := fmt.Sprintf(`
+		SELECT '%s' AS kind, t.id AS id, %s, %s, %s,
+		       COALESCE(snippet(%s, %d, ?, ?, ?, ?), ''), %s AS score,
+		       %s AS created_at
+		%s`, src.kind, src.ref, src.threadID, title, src.fts,
+		src.snippetCol, rank, src.createdAt, from)
+
+	return query, append(args, fromArgs...)
+}
+
+// searchSourcesFor returns the sources of the kinds the params select.
+func searchSourcesFor(p SearchAllParams) []searchSource {
+	if len(p.Kinds) == 0 {
+		return searchSources
+	}
+
+	var sources []searchSource
+	for _, src := range searchSources {
+		if slices.Contains(p.Kinds, src.kind) {
+			sources = append(sources, src)
+		}
+	}
+
+	return sources
+}
+
+// countSearchAll runs the count query behind CountSearchAll.
+func countSearchAll(ctx context.Context, db *sql.DB,
+	p SearchAllParams,
+) (int64, error) {
+	var (
+		counts []string
+		args   []any
+	)
+	for _, src := range searchSourcesFor(p) {
+		from, fromArgs := src.from(p)
+		counts = append(counts, "(SELECT COUNT(*) "+from+")")
+		args = append(args, fromArgs...)
+	}
+	if len(counts) == 0 {
+		return 0, nil
+	}
+
+	var count int64
+	query := "SELECT " + strings.Join(counts, "\n\t\t+ ")
+	err := db.QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	return count, nil
+}
+
+// searchAll builds and runs the query behind SearchAll: the hits of every
+// selected kind, merged by rank.
+func searchAll(ctx context.Context, db *sql.DB,
+	p SearchAllParams,
+) ([]SearchAllHit, error) {
+	var (
+		branches []string
+		args     []any
+	)
+	for _, src := range searchSourcesFor(p) {
+		branch, branchArgs := src.selectHits(p)
+		branches = append(branches, branch)
+		args = append(args, branchArgs...)
+	}
+	if len(branches) == 0 {
+		return nil, nil
+	}
+
+	query := "SELECT * FROM (" + strings.Join(branches,
+		"\n\t\tUNION ALL") + `
+		)
+		ORDER BY score, created_at DESC, id DESC
+		LIMIT ?`
+	args = append(args, p.Limit)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []SearchAllHit
+	for rows.Next() {
+		var (
+			hit       SearchAllHit
+			kind      string
+			createdAt int64
+		)
+		err := rows.Scan(
+			&kind, &hit.ID, &hit.Ref, &hit.ThreadID, &hit.Title,
+			&hit.Snippet, &hit.Rank, &createdAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search "+
+				"result: %w", err)
+		}
+
+		hit.Kind = search.Kind(kind)
+		hit.Snippet = strings.Join(strings.Fields(hit.Snippet), " ")
+		hit.CreatedAt = time.Unix(createdAt, 0)
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating search results: %w",
+			err)
+	}
+
+	return hits, nil
+}
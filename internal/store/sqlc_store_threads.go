@@ -0,0 +1,325 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/roasbeef/subtrate/internal/db/sqlc"
+)
+
+// =============================================================================
+// ThreadStore implementation for SqlcStore
+// =============================================================================
+
+// GetThreadPreference retrieves an agent's preferences for a thread.
+func (s *SqlcStore) GetThreadPreference(ctx context.Context, threadID string,
+	agentID int64,
+) (ThreadPreference, error) {
+	return getThreadPreference(ctx, s.db, threadID, agentID)
+}
+
+// SetThreadPreference creates or replaces an agent's preferences for a
+// thread.
+func (s *SqlcStore) SetThreadPreference(ctx context.Context,
+	pref ThreadPreference,
+) (ThreadPreference, error) {
+	return setThreadPreference(ctx, s.db, pref)
+}
+
+// DeleteThreadPreference clears an agent's preferences for a thread.
+func (s *SqlcStore) DeleteThreadPreference(ctx context.Context,
+	threadID string, agentID int64,
+) (bool, error) {
+	return deleteThreadPreference(ctx, s.db, threadID, agentID)
+}
+
+// ListThreadPreferences lists the threads an agent muted or follows.
+func (s *SqlcStore) ListThreadPreferences(ctx context.Context,
+	agentID int64,
+) ([]ThreadPreference, error) {
+	rows, err := s.db.ListThreadPreferences(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return threadPreferencesFromSqlc(rows), nil
+}
+
+// ListThreadPreferencesByThread lists every agent's preferences for a thread.
+func (s *SqlcStore) ListThreadPreferencesByThread(ctx context.Context,
+	threadID string,
+) ([]ThreadPreference, error) {
+	rows, err := s.db.ListThreadPreferencesByThread(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return threadPreferencesFromSqlc(rows), nil
+}
+
+// CopyThreadPreferences gives a thread the preferences agents set on another.
+func (s *SqlcStore) CopyThreadPreferences(ctx context.Context,
+	fromThreadID, toThreadID string,
+) error {
+	return s.db.CopyThreadPreferences(
+		ctx, copyThreadPreferencesParams(fromThreadID, toThreadID),
+	)
+}
+
+// DeleteThreadPreferences clears every agent's preferences for a thread.
+func (s *SqlcStore) DeleteThreadPreferences(ctx context.Context,
+	threadID string,
+) error {
+	return s.db.DeleteThreadPreferences(ctx, threadID)
+}
+
+// CountThreadMessages counts the messages in a thread.
+func (s *SqlcStore) CountThreadMessages(ctx context.Context,
+	threadID string,
+) (int64, error) {
+	return s.db.CountThreadMessages(ctx, threadID)
+}
+
+// CountThreadReviews counts the code reviews and plan reviews that track a
+// thread.
+func (s *SqlcStore) CountThreadReviews(ctx context.Context,
+	threadID string,
+) (int64, error) {
+	return s.db.CountThreadReviews(ctx, threadID)
+}
+
+// MoveThreadMessages moves messages, and the questions they ask, from one
+// thread to another.
+func (s *SqlcStore) MoveThreadMessages(ctx context.Context,
+	params MoveThreadMessagesParams,
+) (int64, error) {
+	return moveThreadMessages(ctx, s.db, params)
+}
+
+// MoveScheduledThreadMessages points the messages still waiting to be sent
+// into a thread at another.
+func (s *SqlcStore) MoveScheduledThreadMessages(ctx context.Context,
+	fromThreadID, toThreadID string,
+) error {
+	return s.db.MoveScheduledThreadMessages(
+		ctx, sqlc.MoveScheduledThreadMessagesParams{
+			FromThreadID: fromThreadID,
+			ToThreadID:   toThreadID,
+		},
+	)
+}
+
+// =============================================================================
+// ThreadStore implementation for txSqlcStore
+// =============================================================================
+
+// GetThreadPreference retrieves an agent's preferences for a thread.
+func (s *txSqlcStore) GetThreadPreference(ctx context.Context,
+	threadID string, agentID int64,
+) (ThreadPreference, error) {
+	return getThreadPreference(ctx, s.queries, threadID, agentID)
+}
+
+// SetThreadPreference creates or replaces an agent's preferences for a
+// thread.
+func (s *txSqlcStore) SetThreadPreference(ctx context.Context,
+	pref ThreadPreference,
+) (ThreadPreference, error) {
+	return setThreadPreference(ctx, s.queries, pref)
+}
+
+// DeleteThreadPreference clears an agent's preferences for a thread.
+func (s *txSqlcStore) DeleteThreadPreference(ctx context.Context,
+	threadID string, agentID int64,
+) (bool, error) {
+	return deleteThreadPreference(ctx, s.queries, threadID, agentID)
+}
+
+// ListThreadPreferences lists the threads an agent muted or follows.
+func (s *txSqlcStore) ListThreadPreferences(ctx context.Context,
+	agentID int64,
+) ([]ThreadPreference, error) {
+	rows, err := s.queries.ListThreadPreferences(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return threadPreferencesFromSqlc(rows), nil
+}
+
+// ListThreadPreferencesByThread lists every agent's preferences for a thread.
+func (s *txSqlcStore) ListThreadPreferencesByThread(ctx context.Context,
+	threadID string,
+) ([]ThreadPreference, error) {
+	rows, err := s.queries.ListThreadPreferencesByThread(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	return threadPreferencesFromSqlc(rows), nil
+}
+
+// CopyThreadPreferences gives a thread the preferences agents set on another.
+func (s *txSqlcStore) CopyThreadPreferences(ctx context.Context,
+	fromThreadID, toThreadID string,
+) error {
+	return s.queries.CopyThreadPreferences(
+		ctx, copyThreadPreferencesParams(fromThreadID, toThreadID),
+	)
+}
+
+// DeleteThreadPreferences clears every agent's preferences for a thread.
+func (s *txSqlcStore) DeleteThreadPreferences(ctx context.Context,
+	threadID string,
+) error {
+	return s.queries.DeleteThreadPreferences(ctx, threadID)
+}
+
+// CountThreadMessages counts the messages in a thread.
+func (s *txSqlcStore) CountThreadMessages(ctx context.Context,
+	threadID string,
+) (int64, error) {
+	return s.queries.CountThreadMessages(ctx, threadID)
+}
+
+// CountThreadReviews counts the code reviews and plan reviews that track a
+// thread.
+func (s *txSqlcStore) CountThreadReviews(ctx context.Context,
+	threadID string,
+) (int64, error) {
+	return s.queries.CountThreadReviews(ctx, threadID)
+}
+
+// MoveThreadMessages moves messages, and the questions they ask, from one
+// thread to another.
+func (s *txSqlcStore) MoveThreadMessages(ctx context.Context,
+	params MoveThreadMessagesParams,
+) (int64, error) {
+	return moveThreadMessages(ctx, s.queries, params)
+}
+
+// MoveScheduledThreadMessages points the messages still waiting to be sent
+// into a thread at another.
+func (s *txSqlcStore) MoveScheduledThreadMessages(ctx context.Context,
+	fromThreadID, toThreadID string,
+) error {
+	return s.queries.MoveScheduledThreadMessages(
+		ctx, sqlc.MoveScheduledThreadMessagesParams{
+			FromThreadID: fromThreadID,
+			ToThreadID:   toThreadID,
+		},
+	)
+}
+
+// =============================================================================
+// Helper functions
+// =============================================================================
+
+// getThreadPreference fetches an agent's preferences for a thread.
+func getThreadPreference(ctx context.Context, q QueryStore, threadID string,
+	agentID int64,
+) (ThreadPreference, error) {
+	row, err := q.GetThreadPreference(ctx, sqlc.GetThreadPreferenceParams{
+		ThreadID: threadID,
+		AgentID:  agentID,
+	})
+	if err != nil {
+		return ThreadPreference{}, err
+	}
+
+	return threadPreferenceFromSqlc(row), nil
+}
+
+// setThreadPreference upserts an agent's preferences for a thread.
+func setThreadPreference(ctx context.Context, q QueryStore,
+	pref ThreadPreference,
+) (ThreadPreference, error) {
+	now := time.Now().Unix()
+	row, err := q.UpsertThreadPreference(
+		ctx, sqlc.UpsertThreadPreferenceParams{
+			ThreadID:  pref.ThreadID,
+			AgentID:   pref.AgentID,
+			Muted:     boolToInt64(pref.Muted),
+			Following: boolToInt64(pref.Following),
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	)
+	if err != nil {
+		return ThreadPreference{}, err
+	}
+
+	return threadPreferenceFromSqlc(row), nil
+}
+
+// deleteThreadPreference deletes an agent's preferences for a thread.
+func deleteThreadPreference(ctx context.Context, q QueryStore,
+	threadID string, agentID int64,
+) (bool, error) {
+	n, err := q.DeleteThreadPreference(
+		ctx, sqlc.DeleteThreadPreferenceParams{
+			ThreadID: threadID,
+			AgentID:  agentID,
+		},
+	)
+	return n > 0, err
+}
+
+// copyThreadPreferencesParams builds the parameters to copy a thread's
+// preferences to another.
+func copyThreadPreferencesParams(fromThreadID,
+	toThreadID string,
+) sqlc.CopyThreadPreferencesParams {
+	return sqlc.CopyThreadPreferencesParams{
+		FromThreadID: fromThreadID,
+		ToThreadID:   toThreadID,
+		Now:          time.Now().Unix(),
+	}
+}
+
+// moveThreadMessages moves messages and their questions between threads.
+func moveThreadMessages(ctx context.Context, q QueryStore,
+	params MoveThreadMessagesParams,
+) (int64, error) {
+	moved, err := q.MoveThreadMessages(ctx, sqlc.MoveThreadMessagesParams{
+		FromThreadID: params.FromThreadID,
+		ToThreadID:   params.ToThreadID,
+		MinMessageID: params.MinMessageID,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	err = q.MoveThreadQuestions(ctx, sqlc.MoveThreadQuestionsParams{
+		FromThreadID: params.FromThreadID,
+		ToThreadID:   params.ToThreadID,
+		MinMessageID: params.MinMessageID,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return moved, nil
+}
+
+// threadPreferencesFromSqlc converts sqlc thread rows to the domain type.
+func threadPreferencesFromSqlc(rows []sqlc.Thread) []ThreadPreference {
+	prefs := make([]ThreadPreference, len(rows))
+	for i, row := range rows {
+		prefs[i] = threadPreferenceFromSqlc(row)
+	}
+
+	return prefs
+}
+
+// threadPreferenceFromSqlc converts a sqlc thread row to the domain type.
+func threadPreferenceFromSqlc(row sqlc.Thread) ThreadPreference {
+	return ThreadPreference{
+		ThreadID:  row.ThreadID,
+		AgentID:   row.AgentID,
+		Muted:     row.Muted != 0,
+		Following: row.Following != 0,
+		CreatedAt: time.Unix(row.CreatedAt, 0),
+		UpdatedAt: time.Unix(row.UpdatedAt, 0),
+	}
+}
@@ -0,0 +1,305 @@
+// Package tlscert generates, loads and pins the TLS certificates the daemon
+// serves gRPC and the web UI with. Like lnd's tls.cert, the daemon can make
+// its own self-signed certificate on first start; clients then trust it by
+// pinning its SHA-256 fingerprint rather than through a CA.
+package tlscert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// CertFilename is the name of the certificate in a TLS directory.
+	CertFilename = "tls.cert"
+
+	// KeyFilename is the name of the private key in a TLS directory.
+	KeyFilename = "tls.key"
+
+	// DefaultValidity is how long a generated certificate is valid for.
+	DefaultValidity = 14 * 30 * 24 * time.Hour
+
+	// certOrganization is the organization generated certificates are
+	// issued to.
+	certOrganization = "substrate autogenerated cert"
+)
+
+var (
+	// ErrFingerprintMismatch is returned when a server presents a
+	// certificate other than the pinned one.
+	ErrFingerprintMismatch = errors.New("certificate fingerprint " +
+		"mismatch")
+
+	// ErrInvalidFingerprint is returned for a fingerprint that isn't a
+	// hex SHA-256 hash.
+	ErrInvalidFingerprint = errors.New("invalid certificate fingerprint")
+)
+
+// DefaultDir returns where the daemon keeps its certificate by default,
+// ~/.subtrate/tls.
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".subtrate", "tls"), nil
+}
+
+// Ensure loads the certificate and key at the given paths, generating a new
+// self-signed pair first if neither exists or the certificate has expired.
+// The certificate covers localhost, the loopback addresses, the machine's
+// hostname and any extra hosts, which may be DNS names or IP addresses.
+func Ensure(certPath, keyPath string,
+	extraHosts []string,
+) (tls.Certificate, error) {
+	_, certErr := os.Stat(certPath)
+	_, keyErr := os.Stat(keyPath)
+
+	switch {
+	case os.IsNotExist(certErr) && os.IsNotExist(keyErr):
+		err := Generate(certPath, keyPath, extraHosts, DefaultValidity)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+
+	case os.IsNotExist(certErr) || os.IsNotExist(keyErr):
+		return tls.Certificate{}, fmt.Errorf("only one of %s and %s "+
+			"exists; remove it to generate a new pair", certPath,
+			keyPath)
+	}
+
+	cert, err := Load(certPath, keyPath)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if time.Now().Before(cert.Leaf.NotAfter) {
+		return cert, nil
+	}
+
+	// The certificate has expired, so replace it. Clients that pinned
+	// the old one have to pin the new one.
+	err = Generate(certPath, keyPath, extraHosts, DefaultValidity)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return Load(certPath, keyPath)
+}
+
+// Load loads a PEM certificate and key pair, such as one provided by the
+// user rather than generated.
+func Load(certPath, keyPath string) (tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("load TLS key pair: %w",
+			err)
+	}
+
+	cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("parse certificate: %w",
+			err)
+	}
+
+	return cert, nil
+}
+
+// Generate writes a new self-signed ECDSA certificate and key to the given
+// paths, replacing any that exist. The key is only readable by the owner.
+func Generate(certPath, keyPath string, extraHosts []string,
+	validity time.Duration,
+) error {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("generate key: %w", err)
+	}
+
+	serialLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serial, err := rand.Int(rand.Reader, serialLimit)
+	if err != nil {
+		return fmt.Errorf("generate serial number: %w", err)
+	}
+
+	dnsNames, ips := certHosts(extraHosts)
+
+	// Backdate the certificate a little so that clocks running slightly
+	// behind don't reject it.
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{certOrganization},
+			CommonName:   dnsNames[0],
+		},
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(validity),
+		KeyUsage: x509.KeyUsageDigitalSignature |
+			x509.KeyUsageCertSign,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth,
+		},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              dnsNames,
+		IPAddresses:           ips,
+	}
+
+	der, err := x509.CreateCertificate(
+		rand.Reader, &template, &template, &priv.PublicKey, priv,
+	)
+	if err != nil {
+		return fmt.Errorf("create certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return fmt.Errorf("marshal key: %w", err)
+	}
+
+	for _, dir := range []string{
+		filepath.Dir(certPath), filepath.Dir(keyPath),
+	} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("create TLS dir: %w", err)
+		}
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{
+		Type: "CERTIFICATE", Bytes: der,
+	})
+	if err := os.WriteFile(certPath, certPEM, 0644); err != nil {
+		return fmt.Errorf("write certificate: %w", err)
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type: "PRIVATE KEY", Bytes: keyDER,
+	})
+	if err := os.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		return fmt.Errorf("write key: %w", err)
+	}
+
+	return nil
+}
+
+// certHosts returns the DNS names and IP addresses a generated certificate
+// covers, starting with localhost.
+func certHosts(extraHosts []string) ([]string, []net.IP) {
+	dnsNames := []string{"localhost"}
+	ips := []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+
+	if hostname, err := os.Hostname(); err == nil &&
+		hostname != "localhost" {
+
+		dnsNames = append(dnsNames, hostname)
+	}
+
+	for _, host := range extraHosts {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+
+		if ip := net.ParseIP(host); ip != nil {
+			ips = append(ips, ip)
+		} else {
+			dnsNames = append(dnsNames, host)
+		}
+	}
+
+	return dnsNames, ips
+}
+
+// Fingerprint returns the hex SHA-256 fingerprint of a DER certificate.
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
+
+// FingerprintFile returns the fingerprint of the first certificate in a PEM
+// file.
+func FingerprintFile(certPath string) (string, error) {
+	data, err := os.ReadFile(certPath)
+	if err != nil {
+		return "", err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return "", fmt.Errorf("no certificate in %s", certPath)
+	}
+
+	return Fingerprint(block.Bytes), nil
+}
+
+// ParseFingerprint normalizes a fingerprint given as hex, optionally with
+// colons between bytes and a "sha256:" prefix, as openssl prints them.
+func ParseFingerprint(s string) (string, error) {
+	fp := strings.ToLower(strings.TrimSpace(s))
+	fp = strings.TrimPrefix(fp, "sha256:")
+	fp = strings.ReplaceAll(fp, ":", "")
+
+	raw, err := hex.DecodeString(fp)
+	if err != nil || len(raw) != sha256.Size {
+		return "", fmt.Errorf("%w: %q", ErrInvalidFingerprint, s)
+	}
+
+	return fp, nil
+}
+
+// ServerConfig returns the TLS config a listener serves a certificate with.
+func ServerConfig(cert tls.Certificate) *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+}
+
+// PinnedConfig returns a client TLS config that only accepts a server whose
+// certificate has the given fingerprint. The pin replaces CA and hostname
+// verification, so a self-signed certificate is trusted wherever the daemon
+// is reached from.
+func PinnedConfig(fingerprint string) (*tls.Config, error) {
+	pinned, err := ParseFingerprint(fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+
+		// Verification is done by the pin below instead.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte,
+			_ [][]*x509.Certificate,
+		) error {
+			if len(rawCerts) == 0 {
+				return ErrFingerprintMismatch
+			}
+
+			got := Fingerprint(rawCerts[0])
+			if got != pinned {
+				return fmt.Errorf("%w: server presented "+
+					"%s, pinned %s",
+					ErrFingerprintMismatch, got, pinned)
+			}
+
+			return nil
+		},
+	}, nil
+}
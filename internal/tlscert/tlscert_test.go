@@ -0,0 +1,109 @@
+package tlscert
+
+import (
+	"crypto/tls"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestEnsure tests that a certificate is generated on first use, reused
+// afterwards, replaced once expired, and that a lone cert or key is refused.
+func TestEnsure(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "tls", CertFilename)
+	keyPath := filepath.Join(dir, "tls", KeyFilename)
+
+	cert, err := Ensure(certPath, keyPath, []string{"10.0.0.7", "box.lan"})
+	require.NoError(t, err)
+	require.Contains(t, cert.Leaf.DNSNames, "localhost")
+	require.Contains(t, cert.Leaf.DNSNames, "box.lan")
+	require.True(t, cert.Leaf.IPAddresses[2].Equal(net.ParseIP("10.0.0.7")))
+
+	info, err := os.Stat(keyPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	fingerprint, err := FingerprintFile(certPath)
+	require.NoError(t, err)
+	require.Equal(t, Fingerprint(cert.Certificate[0]), fingerprint)
+
+	// The certificate is reused on the next start.
+	again, err := Ensure(certPath, keyPath, nil)
+	require.NoError(t, err)
+	require.Equal(t, cert.Certificate[0], again.Certificate[0])
+
+	// An expired certificate is replaced.
+	err = Generate(certPath, keyPath, nil, -time.Minute)
+	require.NoError(t, err)
+	renewed, err := Ensure(certPath, keyPath, nil)
+	require.NoError(t, err)
+	require.True(t, renewed.Leaf.NotAfter.After(time.Now()))
+
+	// A key without its certificate isn't silently replaced.
+	require.NoError(t, os.Remove(certPath))
+	_, err = Ensure(certPath, keyPath, nil)
+	require.ErrorContains(t, err, "only one of")
+}
+
+// TestParseFingerprint tests that fingerprints are normalized from the
+// forms openssl and our own logs print.
+func TestParseFingerprint(t *testing.T) {
+	t.Parallel()
+
+	hex := strings.Repeat("ab", 32)
+	colons := strings.TrimSuffix(strings.Repeat("AB:", 32), ":")
+
+	for _, in := range []string{hex, "sha256:" + hex, colons} {
+		fp, err := ParseFingerprint(in)
+		require.NoError(t, err, in)
+		require.Equal(t, hex, fp)
+	}
+
+	for _, in := range []string{"", "abcd", strings.Repeat("zz", 32)} {
+		_, err := ParseFingerprint(in)
+		require.ErrorIs(t, err, ErrInvalidFingerprint, in)
+	}
+}
+
+// TestPinnedConfig tests that a pinned client only completes a handshake
+// with the server presenting the pinned certificate.
+func TestPinnedConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cert, err := Ensure(
+		filepath.Join(dir, CertFilename),
+		filepath.Join(dir, KeyFilename), nil,
+	)
+	require.NoError(t, err)
+
+	handshake := func(fingerprint string) error {
+		clientCfg, err := PinnedConfig(fingerprint)
+		require.NoError(t, err)
+
+		clientConn, serverConn := net.Pipe()
+		defer clientConn.Close()
+		defer serverConn.Close()
+
+		server := tls.Server(serverConn, ServerConfig(cert))
+		go func() {
+			_ = server.Handshake()
+			server.Close()
+		}()
+
+		return tls.Client(clientConn, clientCfg).Handshake()
+	}
+
+	require.NoError(t, handshake(Fingerprint(cert.Certificate[0])))
+
+	err = handshake(strings.Repeat("00", 32))
+	require.ErrorIs(t, err, ErrFingerprintMismatch)
+}
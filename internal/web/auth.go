@@ -0,0 +1,124 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/roasbeef/subtrate/internal/auth"
+)
+
+// authCookieName is the cookie the web UI's credential is kept in once a
+// browser has logged in with a ?token= link.
+const authCookieName = "substrate_token"
+
+// authMiddleware requires a credential on the REST API and the WebSocket.
+// Clients present it as a bearer Authorization header, the login cookie, or
+// a token query parameter. A page load carrying ?token= trades the token for
+// the cookie and redirects to the same page without it, so the token
+// doesn't linger in the address bar. The static frontend needs no
+// credential.
+func (s *Server) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		protected := r.URL.Path == "/ws" ||
+			strings.HasPrefix(r.URL.Path, "/api/")
+
+		if !protected {
+			if query.Get("token") != "" {
+				s.login(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, err := requestToken(r)
+		if err != nil {
+			writeAuthError(w, err)
+			return
+		}
+
+		cred, err := s.auth.Authenticate(r.Context(), token)
+		if err != nil {
+			writeAuthError(w, err)
+			return
+		}
+
+		// Hand the credential to the handlers, and make sure the
+		// gateway forwards it to the gRPC server as a bearer token
+		// whichever way it arrived.
+		r = r.Clone(auth.NewContext(r.Context(), cred))
+		r.Header.Set("Authorization", "Bearer "+token)
+		if query.Has("token") {
+			query.Del("token")
+			r.URL.RawQuery = query.Encode()
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// login checks the token in a page load's query and, if it's valid, stores
+// it in the login cookie and redirects to the page without it.
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	token := query.Get("token")
+
+	if _, err := s.auth.Authenticate(r.Context(), token); err != nil {
+		writeAuthError(w, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     authCookieName,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	query.Del("token")
+	target := *r.URL
+	target.RawQuery = query.Encode()
+	http.Redirect(w, r, target.String(), http.StatusSeeOther)
+}
+
+// requestToken returns the token a request carries, looking at the
+// Authorization header, then the login cookie, then the token query
+// parameter.
+func requestToken(r *http.Request) (string, error) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		return auth.ParseBearer(header)
+	}
+
+	if cookie, err := r.Cookie(authCookieName); err == nil {
+		return cookie.Value, nil
+	}
+
+	if token := r.URL.Query().Get("token"); token != "" {
+		return token, nil
+	}
+
+	return "", auth.ErrMissingCredential
+}
+
+// writeAuthError writes the response for a request whose credential was
+// refused.
+func writeAuthError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, auth.ErrMissingCredential),
+		errors.Is(err, auth.ErrInvalidCredential),
+		errors.Is(err, auth.ErrCredentialExpired),
+		errors.Is(err, auth.ErrCredentialRevoked):
+
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+
+	default:
+		http.Error(w, "failed to authenticate: "+err.Error(),
+			http.StatusInternalServerError)
+	}
+}
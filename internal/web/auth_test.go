@@ -0,0 +1,87 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/roasbeef/subtrate/internal/auth"
+	"github.com/roasbeef/subtrate/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAuthMiddleware tests that the API needs a credential given as a
+// header, cookie or query parameter, that login links set the cookie, and
+// that the frontend is served without one.
+func TestAuthMiddleware(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	svc := auth.NewService(store.NewMockStore())
+	token, _, err := svc.Mint(ctx, auth.MintRequest{Name: "web"})
+	require.NoError(t, err)
+
+	var seen *http.Request
+	s := &Server{auth: svc}
+	handler := s.authMiddleware(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			seen = r
+		},
+	))
+
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		seen = nil
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+
+		return rec
+	}
+
+	// The frontend needs no credential, but the API does.
+	rec := serve(httptest.NewRequest(http.MethodGet, "/inbox", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = serve(httptest.NewRequest(http.MethodGet, "/api/v1/agents", nil))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.Nil(t, seen)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/agents", nil)
+	req.Header.Set("Authorization", "Bearer sbt_bogus")
+	rec = serve(req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// A bearer header is passed on with the credential.
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/agents", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec = serve(req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	cred, ok := auth.FromContext(seen.Context())
+	require.True(t, ok)
+	require.True(t, cred.IsAdmin())
+
+	// A login link sets the cookie and redirects without the token.
+	rec = serve(httptest.NewRequest(
+		http.MethodGet, "/inbox?token="+token+"&tab=unread", nil,
+	))
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+	require.Equal(t, "/inbox?tab=unread", rec.Header().Get("Location"))
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	require.Equal(t, token, cookies[0].Value)
+	require.True(t, cookies[0].HttpOnly)
+
+	// The cookie and query parameter are turned into a bearer header for
+	// the gateway, and the token is dropped from the query.
+	req = httptest.NewRequest(http.MethodGet, "/ws", nil)
+	req.AddCookie(cookies[0])
+	rec = serve(req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "Bearer "+token, seen.Header.Get("Authorization"))
+
+	rec = serve(httptest.NewRequest(
+		http.MethodGet, "/api/v1/agents?token="+token+"&limit=5", nil,
+	))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "limit=5", seen.URL.RawQuery)
+	require.Equal(t, "Bearer "+token, seen.Header.Get("Authorization"))
+}
@@ -3,6 +3,7 @@ package web
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,11 +13,13 @@ import (
 	"github.com/roasbeef/subtrate/internal/activity"
 	"github.com/roasbeef/subtrate/internal/agent"
 	subtraterpc "github.com/roasbeef/subtrate/internal/api/grpc"
+	"github.com/roasbeef/subtrate/internal/auth"
 	"github.com/roasbeef/subtrate/internal/mail"
 	"github.com/roasbeef/subtrate/internal/mailclient"
 	"github.com/roasbeef/subtrate/internal/store"
 	"github.com/roasbeef/subtrate/internal/summary"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -32,13 +35,18 @@ type Server struct {
 	actClient    *mailclient.ActivityClient // Shared activity client (required).
 	notifHubRef  NotificationHubRef         // Notification hub reference (optional).
 	summarySvc   *summary.Service           // Summary service (optional).
+	auth         *auth.Service              // Credential checks (optional).
 	hub          *Hub                       // WebSocket hub for real-time updates.
 	notifBridge  *HubNotificationBridge     // Bridge for actor notifications to WebSocket.
 	mux          *http.ServeMux
 	gatewayMux   *runtime.ServeMux // grpc-gateway REST proxy mux (optional).
 	srv          *http.Server
 	addr         string
-	grpcEndpoint string // gRPC endpoint for gateway proxy.
+	tlsConfig    *tls.Config // Serves HTTPS when set (optional).
+	grpcEndpoint string      // gRPC endpoint for gateway proxy.
+
+	// grpcCreds are the gateway's dial credentials (optional).
+	grpcCreds credentials.TransportCredentials
 }
 
 // Config holds configuration for the web server.
@@ -63,6 +71,18 @@ type Config struct {
 	// When provided, enables REST-to-gRPC proxy via grpc-gateway.
 	// Example: "localhost:10009"
 	GRPCEndpoint string
+
+	// GRPCCredentials are the transport credentials the gateway dials the
+	// gRPC endpoint with (optional). Defaults to plaintext.
+	GRPCCredentials credentials.TransportCredentials
+
+	// TLSConfig serves the web UI and API over HTTPS when set (optional).
+	TLSConfig *tls.Config
+
+	// Auth is the credential service (optional). When provided, the REST
+	// API and WebSocket require a credential, which the gateway passes
+	// on to the gRPC server.
+	Auth *auth.Service
 }
 
 // DefaultConfig returns the default server configuration.
@@ -95,9 +115,12 @@ func NewServer(cfg *Config, st store.Storage,
 		actClient:    mailclient.NewActivityClient(cfg.ActivityRef),
 		notifHubRef:  cfg.NotificationHubRef,
 		summarySvc:   cfg.SummarySvc,
+		auth:         cfg.Auth,
 		mux:          http.NewServeMux(),
 		addr:         cfg.Addr,
+		tlsConfig:    cfg.TLSConfig,
 		grpcEndpoint: cfg.GRPCEndpoint,
+		grpcCreds:    cfg.GRPCCredentials,
 	}
 
 	// API v1 routes are now served by grpc-gateway REST proxy.
@@ -138,14 +161,26 @@ func NewServer(cfg *Config, st store.Storage,
 
 // Start starts the HTTP server.
 func (s *Server) Start() error {
+	var handler http.Handler = s.mux
+	if s.auth != nil {
+		handler = s.authMiddleware(handler)
+	}
+
 	s.srv = &http.Server{
 		Addr:         s.addr,
-		Handler:      s.mux,
+		Handler:      handler,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
 
+	if s.tlsConfig != nil {
+		s.srv.TLSConfig = s.tlsConfig.Clone()
+
+		log.Printf("Starting web server on %s (TLS)", s.addr)
+		return s.srv.ListenAndServeTLS("", "")
+	}
+
 	log.Printf("Starting web server on %s", s.addr)
 	return s.srv.ListenAndServe()
 }
@@ -173,6 +208,17 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// gatewayHeaderMatcher maps gRPC response headers onto HTTP ones. The retry
+// delay of a rate limited call becomes a standard Retry-After header, and
+// everything else keeps the gateway's Grpc-Metadata- prefix.
+func gatewayHeaderMatcher(key string) (string, bool) {
+	if key == "retry-after" {
+		return "Retry-After", true
+	}
+
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
 // registerGateway sets up the grpc-gateway REST proxy to forward requests to the
 // gRPC server. This allows REST clients to access gRPC services via HTTP/JSON.
 func (s *Server) registerGateway(ctx context.Context) error {
@@ -182,14 +228,19 @@ func (s *Server) registerGateway(ctx context.Context) error {
 			EmitDefaults: true,
 			OrigName:     true,
 		}),
+		runtime.WithOutgoingHeaderMatcher(gatewayHeaderMatcher),
 	)
 
 	// gRPC dial options for connecting to the gRPC server. The default
 	// max receive size (4MB) is too small for inboxes with large diff
 	// attachments, so we raise the gateway client limit to 100MB.
 	const maxGatewayRecvSize = 100 * 1024 * 1024
+	creds := s.grpcCreds
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
 	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTransportCredentials(creds),
 		grpc.WithDefaultCallOptions(
 			grpc.MaxCallRecvMsgSize(maxGatewayRecvSize),
 		),
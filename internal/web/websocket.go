@@ -4,41 +4,75 @@ package web
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/roasbeef/subtrate/internal/auth"
 )
 
 // WebSocket message types for real-time updates.
 const (
-	WSMsgTypeUnreadCount = "unread_count"
-	WSMsgTypeNewMessage  = "new_message"
-	WSMsgTypeAgentUpdate = "agent_update"
-	WSMsgTypeActivity    = "activity"
-	WSMsgTypeTaskUpdate  = "task_update"
-	WSMsgTypeSummary     = "summary_updated"
-	WSMsgTypePong        = "pong"
-	WSMsgTypeConnected   = "connected"
-	WSMsgTypeError       = "error"
+	WSMsgTypeUnreadCount  = "unread_count"
+	WSMsgTypeNewMessage   = "new_message"
+	WSMsgTypeEdited       = "message_edited"
+	WSMsgTypeStateChange  = "message_state"
+	WSMsgTypeAgentUpdate  = "agent_update"
+	WSMsgTypeActivity     = "activity"
+	WSMsgTypeTaskUpdate   = "task_update"
+	WSMsgTypeReviewUpdate = "review_update"
+	WSMsgTypeSummary      = "summary_updated"
+	WSMsgTypePong         = "pong"
+	WSMsgTypeConnected    = "connected"
+	WSMsgTypeSubscribed   = "subscribed"
+	WSMsgTypeUnsubscribed = "unsubscribed"
+	WSMsgTypeResumed      = "resumed"
+	WSMsgTypeResync       = "resync_required"
+	WSMsgTypeError        = "error"
 )
 
-// WSMessage represents a WebSocket message sent to clients.
+// WebSocket protocol versions, picked by a client with the v query parameter.
+// Legacy clients are subscribed to their agent's inbox and every shared
+// channel. Version 2 clients subscribe to the channels they want and can
+// resume from the sequence of the last event they saw.
+const (
+	WSProtocolLegacy  = 1
+	WSProtocolVersion = 2
+)
+
+// refreshDelay is how long the hub waits after an event before refreshing
+// the activity feed and agent statuses, so that the writes behind a burst of
+// events land first and the burst costs one refresh.
+const refreshDelay = 500 * time.Millisecond
+
+// statusRefreshInterval is how often agent statuses are refreshed without
+// an event asking for it, so that agents going idle or offline as time
+// passes are published too.
+const statusRefreshInterval = 30 * time.Second
+
+// maxReplayEvents caps the events replayed to a resuming client, so the
+// replay fits in its send buffer. Clients further behind are told to resync.
+const maxReplayEvents = sendBufferSize / 2
+
+// WSMessage represents a WebSocket message sent to clients. Events published
+// on a channel carry the channel and their sequence in the hub's journal.
 type WSMessage struct {
 	Type    string `json:"type"`
+	Seq     uint64 `json:"seq,omitempty"`
+	Channel string `json:"channel,omitempty"`
 	Payload any    `json:"payload,omitempty"`
 }
 
-// Hub maintains the set of active WebSocket clients and broadcasts messages.
+// Hub maintains the set of active WebSocket clients and publishes events to
+// the ones subscribed to their channel.
 type Hub struct {
-	// Registered clients keyed by agent ID.
-	clients map[int64]map[*WSClient]struct{}
-
-	// All clients regardless of agent ID.
-	allClients map[*WSClient]struct{}
+	// All connected clients.
+	clients map[*WSClient]struct{}
 
 	// Register requests from clients.
 	register chan *WSClient
@@ -46,16 +80,26 @@ type Hub struct {
 	// Unregister requests from clients.
 	unregister chan *WSClient
 
-	// Broadcast messages to specific agent's clients.
-	broadcast chan *agentBroadcast
+	// journal keeps recent events for clients resuming after a
+	// disconnect.
+	journal *eventJournal
+
+	// refresh asks for the activity feed and agent statuses to be
+	// refreshed.
+	refresh chan struct{}
 
-	// Broadcast messages to all clients.
-	broadcastAll chan *WSMessage
+	// lastActivityID and agentsDigest are the newest activity and the
+	// agent statuses last published, so refreshes only publish changes.
+	// Only the refresh loop touches them.
+	lastActivityID int64
+	agentsDigest   string
 
 	// Server reference for data fetching.
 	server *Server
 
-	// Mutex for thread-safe access.
+	// Mutex for thread-safe access. Publishing holds it while journaling
+	// and fanning out an event so that replays to resuming clients can't
+	// interleave with live events.
 	mu sync.RWMutex
 
 	// Context for shutdown.
@@ -63,130 +107,135 @@ type Hub struct {
 	cancel context.CancelFunc
 }
 
-// agentBroadcast holds a message targeted at a specific agent.
-type agentBroadcast struct {
-	agentID int64
-	message *WSMessage
-}
-
 // NewHub creates a new WebSocket hub.
 func NewHub(server *Server) *Hub {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Hub{
-		clients:      make(map[int64]map[*WSClient]struct{}),
-		allClients:   make(map[*WSClient]struct{}),
-		register:     make(chan *WSClient),
-		unregister:   make(chan *WSClient),
-		broadcast:    make(chan *agentBroadcast, 256),
-		broadcastAll: make(chan *WSMessage, 256),
-		server:       server,
-		ctx:          ctx,
-		cancel:       cancel,
+		clients:    make(map[*WSClient]struct{}),
+		register:   make(chan *WSClient),
+		unregister: make(chan *WSClient),
+		journal:    newEventJournal(DefaultJournalSize),
+		refresh:    make(chan struct{}, 1),
+		server:     server,
+		ctx:        ctx,
+		cancel:     cancel,
 	}
 }
 
 // Run starts the hub's main loop.
 func (h *Hub) Run() {
-	// Start background ticker for periodic updates.
-	go h.runPeriodicUpdates()
+	go h.runRefresher()
 
 	for {
 		select {
 		case <-h.ctx.Done():
 			// Clean up all clients on shutdown.
 			h.mu.Lock()
-			for client := range h.allClients {
+			for client := range h.clients {
 				client.Close()
 			}
 			h.mu.Unlock()
 			return
 
 		case client := <-h.register:
-			agentID := client.AgentID()
 			h.mu.Lock()
-			h.allClients[client] = struct{}{}
-			// Track all clients including agentID=0 (global inbox viewers).
-			// This enables the notification bridge to subscribe to agent 0
-			// notifications for real-time updates in the default inbox view.
-			if h.clients[agentID] == nil {
-				h.clients[agentID] = make(map[*WSClient]struct{})
-			}
-			h.clients[agentID][client] = struct{}{}
+			h.clients[client] = struct{}{}
+			total := len(h.clients)
 			h.mu.Unlock()
-			log.Printf("WebSocket: Client registered (agent_id=%d, total=%d)",
-				agentID, len(h.allClients))
+			log.Printf("WebSocket: Client registered "+
+				"(agent_id=%d, v=%d, total=%d)",
+				client.AgentID(), client.Version(), total)
 
 		case client := <-h.unregister:
-			agentID := client.AgentID()
 			h.mu.Lock()
-			if _, ok := h.allClients[client]; ok {
-				delete(h.allClients, client)
-				// Remove from agent-specific map (including agentID=0).
-				delete(h.clients[agentID], client)
-				if len(h.clients[agentID]) == 0 {
-					delete(h.clients, agentID)
-				}
+			if _, ok := h.clients[client]; ok {
+				delete(h.clients, client)
 				client.Close()
 			}
+			total := len(h.clients)
 			h.mu.Unlock()
-			log.Printf("WebSocket: Client unregistered (agent_id=%d, total=%d)",
-				agentID, len(h.allClients))
-
-		case msg := <-h.broadcast:
-			h.mu.RLock()
-			if clients, ok := h.clients[msg.agentID]; ok {
-				for client := range clients {
-					client.Send(msg.message)
-				}
-			}
-			h.mu.RUnlock()
+			log.Printf("WebSocket: Client unregistered "+
+				"(agent_id=%d, total=%d)", client.AgentID(), total)
+		}
+	}
+}
 
-		case msg := <-h.broadcastAll:
-			h.mu.RLock()
-			for client := range h.allClients {
-				client.Send(msg)
-			}
-			h.mu.RUnlock()
+// Publish journals an event on a channel and sends it to the clients
+// subscribed to the channel.
+func (h *Hub) Publish(channel string, msg WSMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	event := h.journal.append(channel, msg)
+	for client := range h.clients {
+		if client.Subscribed(channel) {
+			client.Send(event)
 		}
 	}
 }
 
-// runPeriodicUpdates sends periodic updates to all connected clients.
-func (h *Hub) runPeriodicUpdates() {
-	agentTicker := time.NewTicker(15 * time.Second)
-	activityTicker := time.NewTicker(10 * time.Second)
-	inboxTicker := time.NewTicker(5 * time.Second)
+// Refresh asks the hub to refresh the activity feed and agent statuses,
+// publishing them if they changed. Requests made while one is pending are
+// coalesced into it.
+func (h *Hub) Refresh() {
+	select {
+	case h.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// runRefresher refreshes the activity feed and agent statuses when asked to
+// by the notifications behind the other channels' events, as those are what
+// record activities and make agents active. Agent statuses are also
+// refreshed every statusRefreshInterval, since agents go idle and offline
+// without any event.
+func (h *Hub) runRefresher() {
+	if h.server == nil {
+		return
+	}
+
+	// Start from what's there now, so that the first refresh only
+	// publishes what changed since the hub started.
+	h.refreshActivity(false)
+	h.refreshAgentStatus(false)
 
-	defer agentTicker.Stop()
-	defer activityTicker.Stop()
-	defer inboxTicker.Stop()
+	ticker := time.NewTicker(statusRefreshInterval)
+	defer ticker.Stop()
 
 	for {
 		select {
 		case <-h.ctx.Done():
 			return
-		case <-agentTicker.C:
-			h.broadcastAgentStatus()
-		case <-activityTicker.C:
-			h.broadcastActivity()
-		case <-inboxTicker.C:
-			h.broadcastUnreadCounts()
+
+		case <-ticker.C:
+			h.refreshAgentStatus(true)
+			continue
+
+		case <-h.refresh:
 		}
-	}
-}
 
-// broadcastAgentStatus sends agent status updates to all clients.
-func (h *Hub) broadcastAgentStatus() {
-	if h.server == nil {
-		return
+		select {
+		case <-h.ctx.Done():
+			return
+
+		case <-time.After(refreshDelay):
+		}
+
+		h.refreshActivity(true)
+		h.refreshAgentStatus(true)
 	}
+}
 
-	ctx := context.Background()
+// refreshAgentStatus publishes agent statuses if they changed since they
+// were last published.
+func (h *Hub) refreshAgentStatus(publish bool) {
+	ctx := h.ctx
 	agents, err := h.server.heartbeatMgr.ListAgentsWithStatus(ctx)
 	if err != nil {
 		return
 	}
 
+	var digest strings.Builder
 	agentList := make([]map[string]any, 0, len(agents))
 	for _, aws := range agents {
 		agentList = append(agentList, map[string]any{
@@ -196,11 +245,20 @@ func (h *Hub) broadcastAgentStatus() {
 			"last_active_at":          aws.LastActive.UTC().Format(time.RFC3339),
 			"seconds_since_heartbeat": int(time.Since(aws.LastActive).Seconds()),
 		})
+		fmt.Fprintf(&digest, "%d:%s;", aws.Agent.ID, aws.Status)
+	}
+
+	if digest.String() == h.agentsDigest {
+		return
+	}
+	h.agentsDigest = digest.String()
+	if !publish {
+		return
 	}
 
 	counts, _ := h.server.heartbeatMgr.GetStatusCounts(ctx)
 
-	h.BroadcastToAll(&WSMessage{
+	h.Publish(ChannelAgents, WSMessage{
 		Type: WSMsgTypeAgentUpdate,
 		Payload: map[string]any{
 			"agents": agentList,
@@ -209,21 +267,17 @@ func (h *Hub) broadcastAgentStatus() {
 	})
 }
 
-// broadcastActivity sends activity updates to all clients.
-func (h *Hub) broadcastActivity() {
-	if h.server == nil {
-		return
-	}
-
-	ctx := context.Background()
-
+// refreshActivity publishes the recent activity feed if an activity was
+// recorded since it was last published.
+func (h *Hub) refreshActivity(publish bool) {
 	// Fetch activities via actor system.
-	activities, err := h.server.listRecentActivities(ctx, 10)
+	activities, err := h.server.listRecentActivities(h.ctx, 10)
 	if err != nil {
 		log.Printf("WebSocket: Failed to fetch activities: %v", err)
 		return
 	}
 
+	var newest int64
 	activityList := make([]map[string]any, 0, len(activities))
 	for _, a := range activities {
 		activityList = append(activityList, map[string]any{
@@ -234,44 +288,44 @@ func (h *Hub) broadcastActivity() {
 			"description": a.Description,
 			"created_at":  a.CreatedAt.UTC().Format(time.RFC3339),
 		})
+		newest = max(newest, a.ID)
 	}
 
-	h.BroadcastToAll(&WSMessage{
+	if newest <= h.lastActivityID {
+		return
+	}
+	h.lastActivityID = newest
+	if !publish {
+		return
+	}
+
+	h.Publish(ChannelActivity, WSMessage{
 		Type:    WSMsgTypeActivity,
 		Payload: activityList,
 	})
 }
 
-// broadcastUnreadCounts sends unread counts to each connected agent.
-func (h *Hub) broadcastUnreadCounts() {
+// publishUnreadCount publishes an agent's unread counts on its inbox channel.
+func (h *Hub) publishUnreadCount(agentID int64) {
 	if h.server == nil {
 		return
 	}
 
-	h.mu.RLock()
-	agentIDs := make([]int64, 0, len(h.clients))
-	for agentID := range h.clients {
-		agentIDs = append(agentIDs, agentID)
+	// Fetch agent status via actor system.
+	resp, err := h.server.getAgentStatus(h.ctx, agentID)
+	if err != nil {
+		log.Printf("WebSocket: Failed to get status for agent %d: %v",
+			agentID, err)
+		return
 	}
-	h.mu.RUnlock()
 
-	ctx := context.Background()
-	for _, agentID := range agentIDs {
-		// Fetch agent status via actor system.
-		resp, err := h.server.getAgentStatus(ctx, agentID)
-		if err != nil {
-			log.Printf("WebSocket: Failed to get status for agent %d: %v", agentID, err)
-			continue
-		}
-
-		h.BroadcastToAgent(agentID, &WSMessage{
-			Type: WSMsgTypeUnreadCount,
-			Payload: map[string]any{
-				"count":        resp.Status.UnreadCount,
-				"urgent_count": resp.Status.UrgentCount,
-			},
-		})
-	}
+	h.Publish(InboxChannel(agentID), WSMessage{
+		Type: WSMsgTypeUnreadCount,
+		Payload: map[string]any{
+			"count":        resp.Status.UnreadCount,
+			"urgent_count": resp.Status.UrgentCount,
+		},
+	})
 }
 
 // Stop shuts down the hub.
@@ -279,24 +333,6 @@ func (h *Hub) Stop() {
 	h.cancel()
 }
 
-// BroadcastToAgent sends a message to all clients for a specific agent.
-func (h *Hub) BroadcastToAgent(agentID int64, msg *WSMessage) {
-	select {
-	case h.broadcast <- &agentBroadcast{agentID: agentID, message: msg}:
-	default:
-		log.Printf("WebSocket: Broadcast buffer full, dropping message for agent %d", agentID)
-	}
-}
-
-// BroadcastToAll sends a message to all connected clients.
-func (h *Hub) BroadcastToAll(msg *WSMessage) {
-	select {
-	case h.broadcastAll <- msg:
-	default:
-		log.Printf("WebSocket: Broadcast buffer full, dropping message")
-	}
-}
-
 // HubTaskNotifier adapts the WebSocket Hub to the gRPC TaskChangeNotifier
 // interface, forwarding task mutation events as WebSocket broadcasts.
 type HubTaskNotifier struct {
@@ -315,7 +351,7 @@ func (n *HubTaskNotifier) OnTaskChange(action string, payload map[string]any) {
 	n.hub.BroadcastTaskUpdate(action, payload)
 }
 
-// BroadcastTaskUpdate notifies all clients of a task change.
+// BroadcastTaskUpdate publishes a task change on the tasks channel.
 // The action string describes what happened (e.g., "upsert", "status", "owner",
 // "sync", "delete") and the payload carries relevant IDs for cache invalidation.
 func (h *Hub) BroadcastTaskUpdate(action string, payload map[string]any) {
@@ -324,14 +360,44 @@ func (h *Hub) BroadcastTaskUpdate(action string, payload map[string]any) {
 	}
 	payload["action"] = action
 
-	h.BroadcastToAll(&WSMessage{
+	h.Publish(ChannelTasks, WSMessage{
 		Type:    WSMsgTypeTaskUpdate,
 		Payload: payload,
 	})
+	h.Refresh()
+}
+
+// HubReviewNotifier adapts the WebSocket Hub to the review service's
+// StateChangeNotifier interface, forwarding review state changes as
+// WebSocket broadcasts.
+type HubReviewNotifier struct {
+	hub *Hub
+}
+
+// NewHubReviewNotifier creates a new notifier that broadcasts review state
+// changes via the WebSocket hub.
+func NewHubReviewNotifier(hub *Hub) *HubReviewNotifier {
+	return &HubReviewNotifier{hub: hub}
+}
+
+// OnReviewStateChange implements the review StateChangeNotifier interface by
+// publishing the change on the reviews channel.
+func (n *HubReviewNotifier) OnReviewStateChange(reviewID, oldState,
+	newState string) {
+
+	n.hub.Publish(ChannelReviews, WSMessage{
+		Type: WSMsgTypeReviewUpdate,
+		Payload: map[string]any{
+			"review_id": reviewID,
+			"old_state": oldState,
+			"new_state": newState,
+		},
+	})
+	n.hub.Refresh()
 }
 
-// BroadcastSummaryUpdate notifies all clients that an agent's activity
-// summary has been refreshed.
+// BroadcastSummaryUpdate notifies clients on the agents channel that an
+// agent's activity summary has been refreshed.
 func (h *Hub) BroadcastSummaryUpdate(
 	agentID int64, payload map[string]any,
 ) {
@@ -340,32 +406,17 @@ func (h *Hub) BroadcastSummaryUpdate(
 	}
 	payload["agent_id"] = agentID
 
-	h.BroadcastToAll(&WSMessage{
+	h.Publish(ChannelAgents, WSMessage{
 		Type:    WSMsgTypeSummary,
 		Payload: payload,
 	})
 }
 
-// BroadcastNewMessage notifies clients of a new message.
-// This broadcasts to both the specific recipient and to "global" viewers (agent_id=0).
-func (h *Hub) BroadcastNewMessage(recipientID int64, msg map[string]any) {
-	wsMsg := &WSMessage{
-		Type:    WSMsgTypeNewMessage,
-		Payload: msg,
-	}
-
-	// Broadcast to the specific recipient.
-	h.BroadcastToAgent(recipientID, wsMsg)
-
-	// Also broadcast to "global" viewers (agent_id=0) so they see new messages.
-	h.BroadcastToAgent(0, wsMsg)
-}
-
 // ClientCount returns the number of connected clients.
 func (h *Hub) ClientCount() int {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
-	return len(h.allClients)
+	return len(h.clients)
 }
 
 // upgrader specifies parameters for upgrading an HTTP connection to WebSocket.
@@ -408,6 +459,34 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// An agent credential only follows its own agent.
+	cred, ok := auth.FromContext(r.Context())
+	boundAgentID := int64(0)
+	if ok && !cred.IsAdmin() {
+		if agentID != 0 && agentID != cred.AgentID {
+			http.Error(w, "Credential can't follow agent "+
+				agentIDStr, http.StatusForbidden)
+			return
+		}
+		agentID = cred.AgentID
+		boundAgentID = cred.AgentID
+	}
+
+	// Parse the protocol version, defaulting to the legacy one that
+	// clients predating versioning speak.
+	version := WSProtocolLegacy
+	switch v := r.URL.Query().Get("v"); v {
+	case "", "1":
+
+	case "2":
+		version = WSProtocolVersion
+
+	default:
+		http.Error(w, "Unsupported protocol version "+v,
+			http.StatusBadRequest)
+		return
+	}
+
 	// Upgrade HTTP connection to WebSocket.
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -416,16 +495,20 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create new client.
-	client := NewWSClient(s.hub, conn, agentID)
+	client := NewWSClient(s.hub, conn, agentID, version)
+	client.boundAgentID = boundAgentID
 
 	// Register client with hub.
 	s.hub.register <- client
 
-	// Send connection confirmation.
+	// Send connection confirmation, with the sequence of the newest
+	// event so clients know where a later resume would start from.
 	client.Send(&WSMessage{
 		Type: WSMsgTypeConnected,
 		Payload: map[string]any{
 			"agent_id": agentID,
+			"protocol": version,
+			"seq":      s.hub.journal.last(),
 			"time":     time.Now().UTC().Format(time.RFC3339),
 		},
 	})
@@ -435,6 +518,15 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	go client.readPump()
 }
 
+// subscribeRequest is the data of a subscribe message. Legacy clients switch
+// the agent they follow with agent_id. Version 2 clients list channels and
+// may ask for the events after resume_from on them to be replayed first.
+type subscribeRequest struct {
+	AgentID    int64    `json:"agent_id"`
+	Channels   []string `json:"channels"`
+	ResumeFrom *uint64  `json:"resume_from"`
+}
+
 // handleIncomingMessage processes messages received from WebSocket clients.
 func (h *Hub) handleIncomingMessage(client *WSClient, messageType int, data []byte) {
 	if messageType != websocket.TextMessage {
@@ -447,12 +539,7 @@ func (h *Hub) handleIncomingMessage(client *WSClient, messageType int, data []by
 	}
 
 	if err := json.Unmarshal(data, &msg); err != nil {
-		client.Send(&WSMessage{
-			Type: WSMsgTypeError,
-			Payload: map[string]any{
-				"message": "Invalid message format",
-			},
-		})
+		client.Send(wsError("Invalid message format"))
 		return
 	}
 
@@ -467,40 +554,155 @@ func (h *Hub) handleIncomingMessage(client *WSClient, messageType int, data []by
 		})
 
 	case "subscribe":
-		// Handle subscription requests (agent_id change).
-		var sub struct {
-			AgentID int64 `json:"agent_id"`
+		var sub subscribeRequest
+		if err := json.Unmarshal(msg.Data, &sub); err != nil {
+			client.Send(wsError("Invalid subscribe request"))
+			return
 		}
-		if err := json.Unmarshal(msg.Data, &sub); err == nil && sub.AgentID > 0 {
-			oldAgentID := client.AgentID()
-			h.mu.Lock()
-			// Remove from old agent.
-			if oldAgentID > 0 {
-				delete(h.clients[oldAgentID], client)
+
+		if client.Version() < WSProtocolVersion {
+			// Handle subscription requests (agent_id change).
+			if sub.AgentID <= 0 {
+				return
 			}
-			// Add to new agent.
-			client.SetAgentID(sub.AgentID)
-			if h.clients[sub.AgentID] == nil {
-				h.clients[sub.AgentID] = make(map[*WSClient]struct{})
+			if !client.mayFollow(sub.AgentID) {
+				client.Send(wsError("Credential can't follow " +
+					"that agent"))
+				return
 			}
-			h.clients[sub.AgentID][client] = struct{}{}
-			h.mu.Unlock()
+			client.SetAgentID(sub.AgentID)
 
 			client.Send(&WSMessage{
-				Type: "subscribed",
+				Type: WSMsgTypeSubscribed,
 				Payload: map[string]any{
 					"agent_id": sub.AgentID,
 				},
 			})
+			return
+		}
+
+		// Version 2 clients following an agent the legacy way get
+		// its inbox channel.
+		if len(sub.Channels) == 0 && sub.AgentID > 0 {
+			sub.Channels = []string{InboxChannel(sub.AgentID)}
+		}
+		h.subscribe(client, sub.Channels, sub.ResumeFrom)
+
+	case "unsubscribe":
+		var unsub struct {
+			Channels []string `json:"channels"`
+		}
+		err := json.Unmarshal(msg.Data, &unsub)
+		if err != nil || client.Version() < WSProtocolVersion {
+			client.Send(wsError("Invalid unsubscribe request"))
+			return
+		}
+
+		client.Unsubscribe(unsub.Channels)
+		client.Send(&WSMessage{
+			Type: WSMsgTypeUnsubscribed,
+			Payload: map[string]any{
+				"channels": client.Channels(),
+			},
+		})
+
+	case "resume":
+		var resume struct {
+			Seq uint64 `json:"seq"`
 		}
+		err := json.Unmarshal(msg.Data, &resume)
+		if err != nil || client.Version() < WSProtocolVersion {
+			client.Send(wsError("Invalid resume request"))
+			return
+		}
+
+		h.mu.Lock()
+		h.replay(client, resume.Seq)
+		h.mu.Unlock()
 
 	default:
 		// Unknown message type.
+		client.Send(wsError("Unknown message type: " + msg.Type))
+	}
+}
+
+// subscribe adds channels to a version 2 client's subscriptions, first
+// replaying the events it missed on them if it's resuming. Holding the hub's
+// lock throughout means no live event can slip in between the replay and the
+// subscription.
+func (h *Hub) subscribe(client *WSClient, channels []string,
+	resumeFrom *uint64) {
+
+	if len(channels) == 0 {
+		client.Send(wsError("No channels to subscribe to"))
+		return
+	}
+	for _, channel := range channels {
+		if err := validateChannel(channel); err != nil {
+			client.Send(wsError(err.Error()))
+			return
+		}
+
+		if !client.maySubscribe(channel) {
+			client.Send(wsError("Credential can't subscribe to " +
+				channel))
+			return
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	client.Subscribe(channels)
+	if resumeFrom != nil {
+		h.replay(client, *resumeFrom)
+	}
+
+	client.Send(&WSMessage{
+		Type: WSMsgTypeSubscribed,
+		Payload: map[string]any{
+			"channels": client.Channels(),
+			"seq":      h.journal.last(),
+		},
+	})
+}
+
+// replay sends a client the journaled events after a sequence on the
+// channels it's subscribed to, followed by a resumed message. If the journal
+// no longer holds all of them, or there are too many to replay, the client is
+// told to resync from the REST API instead. The caller must hold the hub's
+// lock.
+func (h *Hub) replay(client *WSClient, seq uint64) {
+	events, ok := h.journal.since(seq, client.Subscribed)
+	if !ok || len(events) > maxReplayEvents {
 		client.Send(&WSMessage{
-			Type: WSMsgTypeError,
+			Type: WSMsgTypeResync,
 			Payload: map[string]any{
-				"message": "Unknown message type: " + msg.Type,
+				"seq": h.journal.last(),
 			},
 		})
+		return
+	}
+
+	for _, event := range events {
+		client.Send(event)
+	}
+	client.Send(&WSMessage{
+		Type: WSMsgTypeResumed,
+		Payload: map[string]any{
+			"from":     seq,
+			"seq":      h.journal.last(),
+			"replayed": len(events),
+		},
+	})
+}
+
+// wsError builds an error message for a client.
+func wsError(message string) *WSMessage {
+	return &WSMessage{
+		Type: WSMsgTypeError,
+		Payload: map[string]any{
+			"message": message,
+		},
 	}
 }
@@ -3,7 +3,6 @@ package web
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"time"
 
@@ -13,162 +12,157 @@ import (
 // NotificationHubRef is the interface for interacting with the notification hub.
 // This abstracts the actor reference to allow for testing with mocks.
 type NotificationHubRef interface {
-	// Subscribe registers for notifications for a specific agent.
-	Subscribe(ctx context.Context, agentID int64, subscriberID string, ch chan<- mail.InboxMessage) error
+	// SubscribeFirehose registers for every notification the hub
+	// delivers.
+	SubscribeFirehose(ctx context.Context, subscriberID string,
+		ch chan<- mail.HubNotification) error
 
-	// Unsubscribe removes a notification subscription.
-	Unsubscribe(ctx context.Context, agentID int64, subscriberID string) error
+	// UnsubscribeFirehose removes a firehose subscription.
+	UnsubscribeFirehose(ctx context.Context, subscriberID string) error
 }
 
-// HubNotificationBridge connects the WebSocket hub to the actor notification system.
-// It subscribes to notifications for connected agents and forwards them to WebSocket clients.
+// bridgeSubscriberID is the bridge's notification hub subscriber ID.
+const bridgeSubscriberID = "ws-hub"
+
+// HubNotificationBridge connects the WebSocket hub to the actor notification
+// system. It receives every notification the notification hub delivers, so
+// events are journaled whether or not the agent they're for has a client
+// connected, and publishes them on the inbox and topic channels.
 type HubNotificationBridge struct {
-	hub           *Hub
-	notifHub      NotificationHubRef
-	deliveryChan  chan mail.InboxMessage
-	subscriptions map[int64]bool // Track which agents we're subscribed to.
-	ctx           context.Context
-	cancel        context.CancelFunc
+	hub          *Hub
+	notifHub     NotificationHubRef
+	deliveryChan chan mail.HubNotification
+	ctx          context.Context
+	cancel       context.CancelFunc
 }
 
 // NewHubNotificationBridge creates a new bridge between the WebSocket hub and notifications.
 func NewHubNotificationBridge(hub *Hub, notifHub NotificationHubRef) *HubNotificationBridge {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &HubNotificationBridge{
-		hub:           hub,
-		notifHub:      notifHub,
-		deliveryChan:  make(chan mail.InboxMessage, 256),
-		subscriptions: make(map[int64]bool),
-		ctx:           ctx,
-		cancel:        cancel,
+		hub:          hub,
+		notifHub:     notifHub,
+		deliveryChan: make(chan mail.HubNotification, 256),
+		ctx:          ctx,
+		cancel:       cancel,
 	}
 }
 
-// Start begins the notification bridge, listening for notifications and forwarding them.
+// Start subscribes to the notification hub and begins forwarding
+// notifications.
 func (b *HubNotificationBridge) Start() {
+	if b.notifHub != nil {
+		ctx, cancel := context.WithTimeout(b.ctx, 5*time.Second)
+		err := b.notifHub.SubscribeFirehose(
+			ctx, bridgeSubscriberID, b.deliveryChan,
+		)
+		cancel()
+		if err != nil {
+			log.Printf("WebSocket: Failed to subscribe to "+
+				"notifications: %v", err)
+		}
+	}
+
 	go b.runNotificationLoop()
-	go b.runSubscriptionManager()
 }
 
-// Stop shuts down the notification bridge.
+// Stop unsubscribes from the notification hub and shuts down the bridge.
 func (b *HubNotificationBridge) Stop() {
 	b.cancel()
-}
 
-// runNotificationLoop receives notifications and forwards them to WebSocket clients.
-func (b *HubNotificationBridge) runNotificationLoop() {
-	for {
-		select {
-		case <-b.ctx.Done():
-			return
-		case msg := <-b.deliveryChan:
-			b.forwardNotification(msg)
-		}
+	if b.notifHub == nil {
+		return
 	}
-}
 
-// forwardNotification converts a mail notification to a WebSocket message and broadcasts it.
-func (b *HubNotificationBridge) forwardNotification(msg mail.InboxMessage) {
-	wsMsg := &WSMessage{
-		Type: WSMsgTypeNewMessage,
-		Payload: map[string]any{
-			"id":                 msg.ID,
-			"sender_id":          msg.SenderID,
-			"sender_name":        msg.SenderName,
-			"sender_project_key": msg.SenderProjectKey,
-			"sender_git_branch":  msg.SenderGitBranch,
-			"subject":            msg.Subject,
-			"body":               msg.Body,
-			"priority":           string(msg.Priority),
-			"created_at":         msg.CreatedAt.UTC().Format(time.RFC3339),
-			"thread_id":          msg.ThreadID,
-			"state":              msg.State,
-		},
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	// Broadcast to all recipients. In a real implementation, we'd track which
-	// agent received which message, but for simplicity we broadcast based on
-	// sender for now (the mail actor handles recipient filtering).
-	// The SenderID is who sent it, we need to broadcast to recipients.
-	// For now, broadcast to all connected clients and let them filter.
-	b.hub.BroadcastToAll(wsMsg)
+	_ = b.notifHub.UnsubscribeFirehose(ctx, bridgeSubscriberID)
 }
 
-// runSubscriptionManager periodically updates subscriptions based on connected clients.
-func (b *HubNotificationBridge) runSubscriptionManager() {
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
-
+// runNotificationLoop receives notifications and forwards them to WebSocket clients.
+func (b *HubNotificationBridge) runNotificationLoop() {
 	for {
 		select {
 		case <-b.ctx.Done():
-			// Unsubscribe from all on shutdown.
-			b.unsubscribeAll()
 			return
-		case <-ticker.C:
-			b.syncSubscriptions()
+		case notif := <-b.deliveryChan:
+			b.forwardNotification(notif)
 		}
 	}
 }
 
-// syncSubscriptions updates subscriptions to match connected clients.
-func (b *HubNotificationBridge) syncSubscriptions() {
-	if b.notifHub == nil {
+// forwardNotification publishes a notification on the inbox channel of each
+// agent it was for, along with their new unread counts, and on its topic's
+// channel if it was published to one.
+func (b *HubNotificationBridge) forwardNotification(
+	notif mail.HubNotification) {
+
+	if notif.StateChange != nil {
+		b.forwardStateChange(*notif.StateChange)
 		return
 	}
 
-	b.hub.mu.RLock()
-	connectedAgents := make(map[int64]bool)
-	for agentID := range b.hub.clients {
-		connectedAgents[agentID] = true
+	msg := notif.Message
+	payload := map[string]any{
+		"id":                 msg.ID,
+		"sender_id":          msg.SenderID,
+		"sender_name":        msg.SenderName,
+		"sender_project_key": msg.SenderProjectKey,
+		"sender_git_branch":  msg.SenderGitBranch,
+		"subject":            msg.Subject,
+		"body":               msg.Body,
+		"priority":           string(msg.Priority),
+		"created_at":         msg.CreatedAt.UTC().Format(time.RFC3339),
+		"thread_id":          msg.ThreadID,
+		"state":              msg.State,
+	}
+	if notif.TopicName != "" {
+		payload["topic_id"] = notif.TopicID
+		payload["topic_name"] = notif.TopicName
 	}
-	b.hub.mu.RUnlock()
 
-	ctx, cancel := context.WithTimeout(b.ctx, 5*time.Second)
-	defer cancel()
+	// Edits to a message the clients already have are sent as their own
+	// type so they update it in place rather than adding a new one.
+	msgType := WSMsgTypeNewMessage
+	if msg.EditedAt != nil {
+		msgType = WSMsgTypeEdited
+		payload["edited_at"] = msg.EditedAt.UTC().Format(time.RFC3339)
+	}
+	wsMsg := WSMessage{Type: msgType, Payload: payload}
 
-	// Subscribe to new agents.
-	for agentID := range connectedAgents {
-		if !b.subscriptions[agentID] {
-			subscriberID := fmt.Sprintf("ws-hub-%d", agentID)
-			err := b.notifHub.Subscribe(ctx, agentID, subscriberID, b.deliveryChan)
-			if err != nil {
-				log.Printf("WebSocket: Failed to subscribe for agent %d: %v", agentID, err)
-				continue
-			}
-			b.subscriptions[agentID] = true
-			log.Printf("WebSocket: Subscribed to notifications for agent %d", agentID)
-		}
+	if notif.TopicName != "" {
+		b.hub.Publish(TopicChannel(notif.TopicName), wsMsg)
 	}
+	for _, agentID := range notif.AgentIDs {
+		b.hub.Publish(InboxChannel(agentID), wsMsg)
 
-	// Unsubscribe from disconnected agents.
-	for agentID := range b.subscriptions {
-		if !connectedAgents[agentID] {
-			subscriberID := fmt.Sprintf("ws-hub-%d", agentID)
-			err := b.notifHub.Unsubscribe(ctx, agentID, subscriberID)
-			if err != nil {
-				log.Printf("WebSocket: Failed to unsubscribe for agent %d: %v", agentID, err)
-			}
-			delete(b.subscriptions, agentID)
-			log.Printf("WebSocket: Unsubscribed from notifications for agent %d", agentID)
+		// Agent 0 is the global feed, which has no unread count.
+		if agentID != 0 {
+			b.hub.publishUnreadCount(agentID)
 		}
 	}
-}
 
-// unsubscribeAll removes all subscriptions during shutdown.
-func (b *HubNotificationBridge) unsubscribeAll() {
-	if b.notifHub == nil {
-		return
-	}
+	// Mail records activities and makes its sender active.
+	b.hub.Refresh()
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// forwardStateChange publishes a change to the state of an agent's copy of
+// a message on the agent's inbox channel, along with its new unread counts,
+// so that reading, acking or archiving mail elsewhere updates open views.
+func (b *HubNotificationBridge) forwardStateChange(
+	change mail.NotifyStateChange) {
 
-	for agentID := range b.subscriptions {
-		subscriberID := fmt.Sprintf("ws-hub-%d", agentID)
-		_ = b.notifHub.Unsubscribe(ctx, agentID, subscriberID)
-	}
-	b.subscriptions = make(map[int64]bool)
+	b.hub.Publish(InboxChannel(change.AgentID), WSMessage{
+		Type: WSMsgTypeStateChange,
+		Payload: map[string]any{
+			"id":        change.MessageID,
+			"thread_id": change.ThreadID,
+			"old_state": change.OldState,
+			"new_state": change.NewState,
+		},
+	})
+	b.hub.publishUnreadCount(change.AgentID)
 }
 
 // ActorNotificationHubRef wraps an actor reference to implement NotificationHubRef.
@@ -181,12 +175,11 @@ func NewActorNotificationHubRef(ref mail.NotificationActorRef) *ActorNotificatio
 	return &ActorNotificationHubRef{ref: ref}
 }
 
-// Subscribe implements NotificationHubRef.
-func (a *ActorNotificationHubRef) Subscribe(
-	ctx context.Context, agentID int64, subscriberID string, ch chan<- mail.InboxMessage,
-) error {
-	resp := a.ref.Ask(ctx, mail.SubscribeAgentMsg{
-		AgentID:      agentID,
+// SubscribeFirehose implements NotificationHubRef.
+func (a *ActorNotificationHubRef) SubscribeFirehose(ctx context.Context,
+	subscriberID string, ch chan<- mail.HubNotification) error {
+
+	resp := a.ref.Ask(ctx, mail.SubscribeFirehoseMsg{
 		SubscriberID: subscriberID,
 		DeliveryChan: ch,
 	})
@@ -196,12 +189,11 @@ func (a *ActorNotificationHubRef) Subscribe(
 	return err
 }
 
-// Unsubscribe implements NotificationHubRef.
-func (a *ActorNotificationHubRef) Unsubscribe(
-	ctx context.Context, agentID int64, subscriberID string,
-) error {
-	resp := a.ref.Ask(ctx, mail.UnsubscribeAgentMsg{
-		AgentID:      agentID,
+// UnsubscribeFirehose implements NotificationHubRef.
+func (a *ActorNotificationHubRef) UnsubscribeFirehose(ctx context.Context,
+	subscriberID string) error {
+
+	resp := a.ref.Ask(ctx, mail.UnsubscribeFirehoseMsg{
 		SubscriberID: subscriberID,
 	})
 
@@ -14,233 +14,276 @@ import (
 // mockNotificationHubRef implements NotificationHubRef for testing.
 type mockNotificationHubRef struct {
 	mu               sync.Mutex
-	subscriptions    map[int64]map[string]chan<- mail.InboxMessage
+	subscriptions    map[string]chan<- mail.HubNotification
 	subscribeCalls   int
 	unsubscribeCalls int
 }
 
 func newMockNotificationHubRef() *mockNotificationHubRef {
 	return &mockNotificationHubRef{
-		subscriptions: make(map[int64]map[string]chan<- mail.InboxMessage),
+		subscriptions: make(map[string]chan<- mail.HubNotification),
 	}
 }
 
-func (m *mockNotificationHubRef) Subscribe(
-	ctx context.Context, agentID int64, subscriberID string, ch chan<- mail.InboxMessage,
-) error {
+func (m *mockNotificationHubRef) SubscribeFirehose(ctx context.Context,
+	subscriberID string, ch chan<- mail.HubNotification) error {
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.subscribeCalls++
-	if m.subscriptions[agentID] == nil {
-		m.subscriptions[agentID] = make(map[string]chan<- mail.InboxMessage)
-	}
-	m.subscriptions[agentID][subscriberID] = ch
+	m.subscriptions[subscriberID] = ch
 	return nil
 }
 
-func (m *mockNotificationHubRef) Unsubscribe(
-	ctx context.Context, agentID int64, subscriberID string,
-) error {
+func (m *mockNotificationHubRef) UnsubscribeFirehose(ctx context.Context,
+	subscriberID string) error {
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.unsubscribeCalls++
-	if subs, ok := m.subscriptions[agentID]; ok {
-		delete(subs, subscriberID)
-		if len(subs) == 0 {
-			delete(m.subscriptions, agentID)
-		}
-	}
+	delete(m.subscriptions, subscriberID)
 	return nil
 }
 
-func (m *mockNotificationHubRef) subscriberCount(agentID int64) int {
+func (m *mockNotificationHubRef) subscriberCount() int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	return len(m.subscriptions[agentID])
+	return len(m.subscriptions)
 }
 
-// TestHubNotificationBridge_ForwardNotification tests that notifications are forwarded to WebSocket.
-func TestHubNotificationBridge_ForwardNotification(t *testing.T) {
-	t.Parallel()
+// newTestClient adds a client without a connection to a hub that isn't
+// running, so tests can read what the hub sends it.
+func newTestClient(hub *Hub, agentID int64, version int) *WSClient {
+	client := NewWSClient(hub, nil, agentID, version)
 
-	// Create a hub without a server (nil is OK for this test).
-	hub := NewHub(nil)
-	go hub.Run()
-	defer hub.Stop()
+	hub.mu.Lock()
+	hub.clients[client] = struct{}{}
+	hub.mu.Unlock()
 
-	mockRef := newMockNotificationHubRef()
-	bridge := NewHubNotificationBridge(hub, mockRef)
-	bridge.Start()
-	defer bridge.Stop()
+	return client
+}
 
-	// Simulate receiving a notification.
-	testMsg := mail.InboxMessage{
-		ID:         100,
-		SenderID:   1,
-		SenderName: "TestSender",
-		Subject:    "Test Subject",
-		Body:       "Test Body",
-		Priority:   mail.PriorityNormal,
-		CreatedAt:  time.Now(),
-		ThreadID:   "thread-1",
-		State:      "unread",
-	}
+// recvWS returns the next message the hub sent a test client.
+func recvWS(t *testing.T, client *WSClient) *WSMessage {
+	t.Helper()
 
-	// Send notification to bridge's delivery channel.
-	bridge.deliveryChan <- testMsg
+	select {
+	case msg := <-client.send:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("no WebSocket message received")
+		return nil
+	}
+}
 
-	// Give time for processing.
-	time.Sleep(50 * time.Millisecond)
+// requireNoWS checks that the hub sent a test client nothing more.
+func requireNoWS(t *testing.T, client *WSClient) {
+	t.Helper()
 
-	// The notification should have been forwarded (broadcasted to all).
-	// Since we don't have connected clients, we just verify no panic occurred.
+	select {
+	case msg := <-client.send:
+		t.Fatalf("unexpected WebSocket message: %+v", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
 }
 
-// TestHubNotificationBridge_SubscriptionSync tests subscription management.
-func TestHubNotificationBridge_SubscriptionSync(t *testing.T) {
+// TestHubNotificationBridge_ForwardNotification tests that notifications are
+// published on the inbox channels of the agents they were for and on their
+// topic's channel.
+func TestHubNotificationBridge_ForwardNotification(t *testing.T) {
 	t.Parallel()
 
+	// Create a hub without a server (nil is OK for this test).
 	hub := NewHub(nil)
-	go hub.Run()
-	defer hub.Stop()
+	bridge := NewHubNotificationBridge(hub, newMockNotificationHubRef())
 
-	mockRef := newMockNotificationHubRef()
-	bridge := NewHubNotificationBridge(hub, mockRef)
+	v2 := newTestClient(hub, 0, WSProtocolVersion)
+	v2.Subscribe([]string{InboxChannel(5), TopicChannel("builds")})
+	legacy := newTestClient(hub, 5, WSProtocolLegacy)
+	other := newTestClient(hub, 6, WSProtocolLegacy)
 
-	// Manually add a client to the hub to simulate a connection.
-	hub.mu.Lock()
-	hub.clients[42] = make(map[*WSClient]struct{})
-	hub.clients[42][&WSClient{}] = struct{}{}
-	hub.mu.Unlock()
-
-	// Sync subscriptions.
-	bridge.syncSubscriptions()
-
-	// Verify subscription was created.
-	require.Equal(t, 1, mockRef.subscribeCalls)
-	require.Equal(t, 1, mockRef.subscriberCount(42))
-	require.True(t, bridge.subscriptions[42])
-
-	// Remove client and sync again.
-	hub.mu.Lock()
-	delete(hub.clients, 42)
-	hub.mu.Unlock()
-
-	bridge.syncSubscriptions()
+	bridge.forwardNotification(mail.HubNotification{
+		AgentIDs:  []int64{5},
+		TopicID:   3,
+		TopicName: "builds",
+		Message: mail.InboxMessage{
+			ID:         100,
+			SenderID:   1,
+			SenderName: "TestSender",
+			Subject:    "Test Subject",
+			Priority:   mail.PriorityNormal,
+			CreatedAt:  time.Now(),
+			State:      "unread",
+		},
+	})
 
-	// Verify unsubscription was called.
-	require.Equal(t, 1, mockRef.unsubscribeCalls)
-	require.Equal(t, 0, mockRef.subscriberCount(42))
-	require.False(t, bridge.subscriptions[42])
+	// The version 2 client gets the message on both channels, in order.
+	msg := recvWS(t, v2)
+	require.Equal(t, WSMsgTypeNewMessage, msg.Type)
+	require.Equal(t, TopicChannel("builds"), msg.Channel)
+	require.Equal(t, uint64(1), msg.Seq)
+	payload := msg.Payload.(map[string]any)
+	require.Equal(t, int64(100), payload["id"])
+	require.Equal(t, "builds", payload["topic_name"])
+
+	msg = recvWS(t, v2)
+	require.Equal(t, InboxChannel(5), msg.Channel)
+	require.Equal(t, uint64(2), msg.Seq)
+	requireNoWS(t, v2)
+
+	// The legacy client following agent 5 only gets its inbox event, and
+	// the one following agent 6 nothing.
+	msg = recvWS(t, legacy)
+	require.Equal(t, InboxChannel(5), msg.Channel)
+	requireNoWS(t, legacy)
+	requireNoWS(t, other)
+
+	// Edits are sent as their own type.
+	edited := time.Now()
+	bridge.forwardNotification(mail.HubNotification{
+		AgentIDs: []int64{5},
+		Message:  mail.InboxMessage{ID: 100, EditedAt: &edited},
+	})
+	msg = recvWS(t, legacy)
+	require.Equal(t, WSMsgTypeEdited, msg.Type)
+	require.Equal(t, uint64(3), msg.Seq)
 }
 
-// TestHubNotificationBridge_MultipleAgents tests handling multiple agents.
+// TestHubNotificationBridge_MultipleAgents tests that a notification for
+// several agents is published once on each of their inbox channels, and not
+// to clients following other agents.
 func TestHubNotificationBridge_MultipleAgents(t *testing.T) {
 	t.Parallel()
 
 	hub := NewHub(nil)
-	go hub.Run()
-	defer hub.Stop()
-
-	mockRef := newMockNotificationHubRef()
-	bridge := NewHubNotificationBridge(hub, mockRef)
+	bridge := NewHubNotificationBridge(hub, newMockNotificationHubRef())
 
-	// Add multiple clients.
-	hub.mu.Lock()
-	hub.clients[1] = make(map[*WSClient]struct{})
-	hub.clients[1][&WSClient{}] = struct{}{}
-	hub.clients[2] = make(map[*WSClient]struct{})
-	hub.clients[2][&WSClient{}] = struct{}{}
-	hub.clients[3] = make(map[*WSClient]struct{})
-	hub.clients[3][&WSClient{}] = struct{}{}
-	hub.mu.Unlock()
+	clients := make(map[int64]*WSClient)
+	for _, agentID := range []int64{1, 2, 3, 4} {
+		clients[agentID] = newTestClient(
+			hub, agentID, WSProtocolLegacy,
+		)
+	}
 
-	// Sync subscriptions.
-	bridge.syncSubscriptions()
+	bridge.forwardNotification(mail.HubNotification{
+		AgentIDs:  []int64{1, 2, 3},
+		TopicID:   7,
+		TopicName: "team",
+		Message:   mail.InboxMessage{ID: 42, Subject: "Standup"},
+	})
 
-	// Verify all subscriptions created.
-	require.Equal(t, 3, mockRef.subscribeCalls)
-	require.Equal(t, 1, mockRef.subscriberCount(1))
-	require.Equal(t, 1, mockRef.subscriberCount(2))
-	require.Equal(t, 1, mockRef.subscriberCount(3))
+	for _, agentID := range []int64{1, 2, 3} {
+		msg := recvWS(t, clients[agentID])
+		require.Equal(t, WSMsgTypeNewMessage, msg.Type)
+		require.Equal(t, InboxChannel(agentID), msg.Channel)
+		require.Equal(t, int64(42), msg.Payload.(map[string]any)["id"])
+		requireNoWS(t, clients[agentID])
+	}
+	requireNoWS(t, clients[4])
+}
 
-	// Partially disconnect.
-	hub.mu.Lock()
-	delete(hub.clients, 2)
-	hub.mu.Unlock()
+// TestHubNotificationBridge_StateChange tests that a change to the state of
+// an agent's copy of a message is published on that agent's inbox channel.
+func TestHubNotificationBridge_StateChange(t *testing.T) {
+	t.Parallel()
 
-	bridge.syncSubscriptions()
+	hub := NewHub(nil)
+	bridge := NewHubNotificationBridge(hub, newMockNotificationHubRef())
+
+	reader := newTestClient(hub, 5, WSProtocolLegacy)
+	other := newTestClient(hub, 6, WSProtocolLegacy)
+
+	bridge.forwardNotification(mail.HubNotification{
+		AgentIDs: []int64{5},
+		StateChange: &mail.NotifyStateChange{
+			AgentID:   5,
+			MessageID: 100,
+			ThreadID:  "thread-1",
+			OldState:  "unread",
+			NewState:  "read",
+		},
+	})
 
-	// Should have one unsubscribe call.
-	require.Equal(t, 1, mockRef.unsubscribeCalls)
-	require.Equal(t, 0, mockRef.subscriberCount(2))
+	msg := recvWS(t, reader)
+	require.Equal(t, WSMsgTypeStateChange, msg.Type)
+	require.Equal(t, InboxChannel(5), msg.Channel)
+	payload := msg.Payload.(map[string]any)
+	require.Equal(t, int64(100), payload["id"])
+	require.Equal(t, "read", payload["new_state"])
+	requireNoWS(t, reader)
+	requireNoWS(t, other)
 }
 
-// TestHubNotificationBridge_UnsubscribeAll tests cleanup on shutdown.
-func TestHubNotificationBridge_UnsubscribeAll(t *testing.T) {
+// TestHubNotificationBridge_StartStop tests that the bridge subscribes to the
+// notification hub's firehose when started and unsubscribes when stopped.
+func TestHubNotificationBridge_StartStop(t *testing.T) {
 	t.Parallel()
 
 	hub := NewHub(nil)
-	go hub.Run()
-	defer hub.Stop()
-
 	mockRef := newMockNotificationHubRef()
 	bridge := NewHubNotificationBridge(hub, mockRef)
 
-	// Add clients and sync.
-	hub.mu.Lock()
-	hub.clients[10] = make(map[*WSClient]struct{})
-	hub.clients[20] = make(map[*WSClient]struct{})
-	hub.mu.Unlock()
-
-	bridge.syncSubscriptions()
-	require.Equal(t, 2, mockRef.subscribeCalls)
-
-	// Unsubscribe all.
-	bridge.unsubscribeAll()
+	bridge.Start()
+	require.Equal(t, 1, mockRef.subscribeCalls)
+	require.Equal(t, 1, mockRef.subscriberCount())
 
-	// Verify unsubscriptions.
-	require.Equal(t, 2, mockRef.unsubscribeCalls)
-	require.Equal(t, 0, len(bridge.subscriptions))
+	bridge.Stop()
+	require.Equal(t, 1, mockRef.unsubscribeCalls)
+	require.Equal(t, 0, mockRef.subscriberCount())
 }
 
-// TestActorNotificationHubRef_Integration tests the actor-based notification hub ref.
+// TestActorNotificationHubRef_Integration tests subscribing to and
+// unsubscribing from the firehose of a real notification hub actor.
 func TestActorNotificationHubRef_Integration(t *testing.T) {
 	t.Parallel()
 
-	// Create an actor system.
 	system := actor.NewActorSystem()
 	defer system.Shutdown(context.Background())
 
-	// Create and spawn the notification hub.
 	notifHub := mail.NewNotificationHub()
-	hubRef := mail.NotificationHubKey.Spawn(system, "test-notif-hub", notifHub)
-
-	// Wrap in ActorNotificationHubRef.
+	hubRef := mail.NotificationHubKey.Spawn(
+		system, "test-notif-hub", notifHub,
+	)
 	actorRef := NewActorNotificationHubRef(hubRef)
 
-	// Create delivery channel.
-	deliveryChan := make(chan mail.InboxMessage, 10)
-
-	// Subscribe.
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	err := actorRef.Subscribe(ctx, 1, "test-sub", deliveryChan)
+	deliveryChan := make(chan mail.HubNotification, 10)
+	err := actorRef.SubscribeFirehose(ctx, "test-sub", deliveryChan)
 	require.NoError(t, err)
 
-	// Verify subscription via direct hub check.
-	require.Equal(t, 1, notifHub.SubscriberCount(1))
+	// State changes only reach firehose subscribers.
+	change := mail.NotifyStateChange{
+		AgentID:   1,
+		MessageID: 7,
+		OldState:  "unread",
+		NewState:  "archived",
+	}
+	hubRef.Ask(ctx, mail.NotifyStateChangeMsg{Change: change}).Await(ctx)
 
-	// Unsubscribe.
-	err = actorRef.Unsubscribe(ctx, 1, "test-sub")
+	select {
+	case notif := <-deliveryChan:
+		require.Equal(t, []int64{1}, notif.AgentIDs)
+		require.Equal(t, &change, notif.StateChange)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("state change was not delivered")
+	}
+
+	// Once unsubscribed, nothing more is delivered.
+	err = actorRef.UnsubscribeFirehose(ctx, "test-sub")
 	require.NoError(t, err)
 
-	// Verify unsubscription.
-	require.Equal(t, 0, notifHub.SubscriberCount(1))
+	hubRef.Ask(ctx, mail.NotifyStateChangeMsg{Change: change}).Await(ctx)
+	select {
+	case notif := <-deliveryChan:
+		t.Fatalf("unexpected notification: %+v", notif)
+	case <-time.After(50 * time.Millisecond):
+	}
 }
 
-// TestActorNotificationHubRef_NotificationDelivery tests end-to-end notification delivery.
+// TestActorNotificationHubRef_NotificationDelivery tests end-to-end
+// notification delivery through the actor-based notification hub ref.
 func TestActorNotificationHubRef_NotificationDelivery(t *testing.T) {
 	t.Parallel()
 
@@ -256,46 +299,36 @@ func TestActorNotificationHubRef_NotificationDelivery(t *testing.T) {
 	actorRef := NewActorNotificationHubRef(hubRef)
 
 	// Create delivery channel.
-	deliveryChan := make(chan mail.InboxMessage, 10)
+	deliveryChan := make(chan mail.HubNotification, 10)
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
 	// Subscribe.
-	err := actorRef.Subscribe(ctx, 1, "test-sub", deliveryChan)
+	err := actorRef.SubscribeFirehose(ctx, "test-sub", deliveryChan)
 	require.NoError(t, err)
 
 	// Send a notification directly via the hub ref.
-	testMsg := mail.InboxMessage{
-		ID:      500,
-		Subject: "Integration Test",
-		Body:    "Test body content",
-	}
-
-	notifyResp := hubRef.Ask(ctx, mail.NotifyAgentMsg{
+	hubRef.Ask(ctx, mail.NotifyAgentMsg{
 		AgentID: 1,
-		Message: testMsg,
-	})
-
-	result := notifyResp.Await(ctx)
-	resp, err := result.Unpack()
-	require.NoError(t, err)
-
-	notifyResult, ok := resp.(mail.NotifyAgentResponse)
-	require.True(t, ok)
-	require.Equal(t, 1, notifyResult.DeliveredCount)
+		Message: mail.InboxMessage{
+			ID:      500,
+			Subject: "Integration Test",
+		},
+	}).Await(ctx)
 
 	// Verify message was delivered to channel.
 	select {
-	case msg := <-deliveryChan:
-		require.Equal(t, int64(500), msg.ID)
-		require.Equal(t, "Integration Test", msg.Subject)
+	case notif := <-deliveryChan:
+		require.Equal(t, []int64{1}, notif.AgentIDs)
+		require.Equal(t, int64(500), notif.Message.ID)
+		require.Equal(t, "Integration Test", notif.Message.Subject)
 	case <-time.After(100 * time.Millisecond):
 		t.Fatal("notification was not delivered")
 	}
 
 	// Cleanup.
-	err = actorRef.Unsubscribe(ctx, 1, "test-sub")
+	err = actorRef.UnsubscribeFirehose(ctx, "test-sub")
 	require.NoError(t, err)
 }
 
@@ -309,8 +342,6 @@ func TestHubNotificationBridge_FullIntegration(t *testing.T) {
 
 	// Create the WebSocket hub.
 	hub := NewHub(nil)
-	go hub.Run()
-	defer hub.Stop()
 
 	// Create and spawn the notification hub actor.
 	notifHub := mail.NewNotificationHub()
@@ -322,20 +353,11 @@ func TestHubNotificationBridge_FullIntegration(t *testing.T) {
 	bridge.Start()
 	defer bridge.Stop()
 
-	// Simulate a client connecting.
-	hub.mu.Lock()
-	hub.clients[99] = make(map[*WSClient]struct{})
-	hub.clients[99][&WSClient{}] = struct{}{}
-	hub.mu.Unlock()
-
-	// Force subscription sync.
-	bridge.syncSubscriptions()
-
-	// Verify subscription was established.
-	require.True(t, bridge.subscriptions[99])
-	require.Equal(t, 1, notifHub.SubscriberCount(99))
+	client := newTestClient(hub, 0, WSProtocolVersion)
+	client.Subscribe([]string{InboxChannel(99)})
 
-	// Send a notification to agent 99.
+	// Send a notification to agent 99, which nothing subscribed to at the
+	// notification hub.
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
@@ -347,11 +369,10 @@ func TestHubNotificationBridge_FullIntegration(t *testing.T) {
 		},
 	}).Await(ctx)
 
-	// Give time for notification to propagate through bridge.
-	time.Sleep(100 * time.Millisecond)
-
-	// The message should have been forwarded by the bridge.
-	// Since we don't have a real WebSocket client, we verify the bridge processed it.
+	msg := recvWS(t, client)
+	require.Equal(t, WSMsgTypeNewMessage, msg.Type)
+	require.Equal(t, InboxChannel(99), msg.Channel)
+	require.Equal(t, int64(999), msg.Payload.(map[string]any)["id"])
 }
 
 // TestHubNotificationBridge_NilNotificationHub tests graceful handling of nil hub.
@@ -359,20 +380,11 @@ func TestHubNotificationBridge_NilNotificationHub(t *testing.T) {
 	t.Parallel()
 
 	hub := NewHub(nil)
-	go hub.Run()
-	defer hub.Stop()
 
 	// Create bridge with nil notification hub.
 	bridge := NewHubNotificationBridge(hub, nil)
 
-	// Add a client.
-	hub.mu.Lock()
-	hub.clients[1] = make(map[*WSClient]struct{})
-	hub.mu.Unlock()
-
-	// Sync should not panic with nil hub.
-	bridge.syncSubscriptions()
-
-	// Unsubscribe all should not panic.
-	bridge.unsubscribeAll()
+	// Starting and stopping should not panic with nil hub.
+	bridge.Start()
+	bridge.Stop()
 }
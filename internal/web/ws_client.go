@@ -4,6 +4,8 @@ package web
 import (
 	"encoding/json"
 	"log"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,7 +25,8 @@ const (
 	// Maximum message size allowed from peer.
 	maxMessageSize = 4096
 
-	// Size of the client send buffer.
+	// Size of the client send buffer. A v2 client's last slot is kept for
+	// the resync_required message sent when the rest fill up.
 	sendBufferSize = 256
 )
 
@@ -38,9 +41,23 @@ type WSClient struct {
 	// Agent ID associated with this connection.
 	agentID int64
 
+	// boundAgentID is the agent an agent credential ties the client to,
+	// or zero if it may follow any agent.
+	boundAgentID int64
+
+	// version is the protocol version the client speaks.
+	version int
+
+	// channels are the channels a v2 client subscribed to.
+	channels map[string]struct{}
+
 	// Buffered channel of outbound messages.
 	send chan *WSMessage
 
+	// overflowed is set once a v2 client's send buffer filled up and it
+	// was told to resync. Messages are dropped until that is written.
+	overflowed bool
+
 	// Mutex for thread-safe connection operations.
 	mu sync.Mutex
 
@@ -48,16 +65,85 @@ type WSClient struct {
 	closed bool
 }
 
-// NewWSClient creates a new WebSocket client.
-func NewWSClient(hub *Hub, conn *websocket.Conn, agentID int64) *WSClient {
+// NewWSClient creates a new WebSocket client speaking the given protocol
+// version.
+func NewWSClient(hub *Hub, conn *websocket.Conn, agentID int64,
+	version int) *WSClient {
+
 	return &WSClient{
-		hub:     hub,
-		conn:    conn,
-		agentID: agentID,
-		send:    make(chan *WSMessage, sendBufferSize),
+		hub:      hub,
+		conn:     conn,
+		agentID:  agentID,
+		version:  version,
+		channels: make(map[string]struct{}),
+		send:     make(chan *WSMessage, sendBufferSize),
+	}
+}
+
+// Version returns the protocol version the client speaks.
+func (c *WSClient) Version() int {
+	return c.version
+}
+
+// Subscribed reports whether the client receives events published on a
+// channel (thread-safe). Legacy clients receive everything but the inbox and
+// topic channels, of which they only get their own agent's inbox, and the
+// channels their credential doesn't allow.
+func (c *WSClient) Subscribed(channel string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.version < WSProtocolVersion {
+		switch {
+		case strings.HasPrefix(channel, inboxChannelPrefix):
+			return channel == InboxChannel(c.agentID)
+
+		case strings.HasPrefix(channel, topicChannelPrefix):
+			return false
+		}
+
+		return c.maySubscribe(channel)
+	}
+
+	_, ok := c.channels[channel]
+	return ok
+}
+
+// Subscribe adds channels to the client's subscriptions (thread-safe).
+func (c *WSClient) Subscribe(channels []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, channel := range channels {
+		c.channels[channel] = struct{}{}
+	}
+}
+
+// Unsubscribe removes channels from the client's subscriptions
+// (thread-safe).
+func (c *WSClient) Unsubscribe(channels []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, channel := range channels {
+		delete(c.channels, channel)
 	}
 }
 
+// Channels returns the client's subscriptions, sorted (thread-safe).
+func (c *WSClient) Channels() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	channels := make([]string, 0, len(c.channels))
+	for channel := range c.channels {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+
+	return channels
+}
+
 // AgentID returns the agent ID associated with this client (thread-safe).
 func (c *WSClient) AgentID() int64 {
 	c.mu.Lock()
@@ -72,23 +158,74 @@ func (c *WSClient) SetAgentID(id int64) {
 	c.agentID = id
 }
 
-// Send queues a message to be sent to the client.
+// mayFollow reports whether the client may follow an agent's inbox.
+func (c *WSClient) mayFollow(agentID int64) bool {
+	return c.boundAgentID == 0 || c.boundAgentID == agentID
+}
+
+// maySubscribe reports whether the client may receive a channel's events.
+// A client tied to an agent only gets that agent's inbox, which carries the
+// topic mail delivered to it, and the activity and agents channels, whose
+// contents any agent can list over the API anyway. The global feed, topics,
+// reviews and tasks carry other agents' mail and work.
+func (c *WSClient) maySubscribe(channel string) bool {
+	if c.boundAgentID == 0 {
+		return true
+	}
+	if agentID, ok := inboxChannelAgent(channel); ok {
+		return c.mayFollow(agentID)
+	}
+
+	return channel == ChannelActivity || channel == ChannelAgents
+}
+
+// Send queues a message to be sent to the client. If a v2 client's buffer is
+// full, the message is dropped and the client is told to resync instead, so
+// it never skips events without knowing. Nothing more is queued until the
+// resync message is written.
 func (c *WSClient) Send(msg *WSMessage) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.closed {
+	if c.closed || c.overflowed {
 		return
 	}
 
-	select {
-	case c.send <- msg:
-	default:
+	free := cap(c.send) - len(c.send)
+	if c.version >= WSProtocolVersion {
+		free--
+	}
+	if free > 0 {
+		c.send <- msg
+		return
+	}
+
+	if c.version < WSProtocolVersion {
 		// Buffer full, drop message.
 		log.Printf("WebSocket: Send buffer full for agent %d, dropping message", c.agentID)
+		return
+	}
+
+	log.Printf("WebSocket: Send buffer full for agent %d, asking client "+
+		"to resync", c.agentID)
+
+	c.overflowed = true
+	c.send <- &WSMessage{
+		Type: WSMsgTypeResync,
+		Payload: map[string]any{
+			"seq": c.hub.journal.last(),
+		},
 	}
 }
 
+// resynced lets messages be queued again once the resync message sent on an
+// overflow was written (thread-safe).
+func (c *WSClient) resynced() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.overflowed = false
+}
+
 // Close closes the client connection.
 func (c *WSClient) Close() {
 	c.mu.Lock()
@@ -150,6 +287,10 @@ func (c *WSClient) writePump() {
 				return
 			}
 
+			if msg.Type == WSMsgTypeResync {
+				c.resynced()
+			}
+
 			// Marshal and send the message.
 			data, err := json.Marshal(msg)
 			if err != nil {
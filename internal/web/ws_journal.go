@@ -0,0 +1,170 @@
+package web
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// WebSocket channels clients of the v2 protocol subscribe to. Inbox and
+// topic channels are named per agent and per topic with InboxChannel and
+// TopicChannel.
+const (
+	// ChannelReviews carries review state changes.
+	ChannelReviews = "reviews"
+
+	// ChannelTasks carries task list mutations.
+	ChannelTasks = "tasks"
+
+	// ChannelActivity carries the recent activity feed.
+	ChannelActivity = "activity"
+
+	// ChannelAgents carries agent status and summary updates.
+	ChannelAgents = "agents"
+
+	// inboxChannelPrefix and topicChannelPrefix prefix the names of the
+	// per-agent and per-topic channels.
+	inboxChannelPrefix = "inbox:"
+	topicChannelPrefix = "topic:"
+)
+
+// DefaultJournalSize is the number of events the hub keeps for clients
+// resuming after a disconnect.
+const DefaultJournalSize = 1024
+
+// InboxChannel returns the channel carrying an agent's inbox events. Agent 0
+// is the global feed every message also appears in.
+func InboxChannel(agentID int64) string {
+	return inboxChannelPrefix + strconv.FormatInt(agentID, 10)
+}
+
+// TopicChannel returns the channel carrying the messages published to a
+// topic.
+func TopicChannel(name string) string {
+	return topicChannelPrefix + name
+}
+
+// validateChannel checks that a client asked for a channel that exists.
+func validateChannel(channel string) error {
+	switch {
+	case channel == ChannelReviews, channel == ChannelTasks,
+		channel == ChannelActivity, channel == ChannelAgents:
+
+		return nil
+
+	case strings.HasPrefix(channel, inboxChannelPrefix):
+		id, err := strconv.ParseInt(
+			strings.TrimPrefix(channel, inboxChannelPrefix), 10, 64,
+		)
+		if err != nil || id < 0 {
+			return fmt.Errorf("invalid inbox channel %q", channel)
+		}
+
+		return nil
+
+	case strings.HasPrefix(channel, topicChannelPrefix) &&
+		len(channel) > len(topicChannelPrefix):
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown channel %q", channel)
+}
+
+// inboxChannelAgent returns the agent whose inbox a channel carries, if it
+// is an inbox channel.
+func inboxChannelAgent(channel string) (int64, bool) {
+	if !strings.HasPrefix(channel, inboxChannelPrefix) {
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(
+		strings.TrimPrefix(channel, inboxChannelPrefix), 10, 64,
+	)
+	if err != nil {
+		return 0, false
+	}
+
+	return id, true
+}
+
+// eventJournal is a bounded, in-memory log of the events the hub published,
+// numbered by a sequence that increases by one per event. Clients that
+// reconnect replay what they missed from it, so long as it hasn't been
+// overwritten yet.
+type eventJournal struct {
+	mu sync.Mutex
+
+	// events is a ring buffer holding the newest events, the event with
+	// sequence n at index (n-1) % len(events).
+	events []*WSMessage
+
+	// lastSeq is the sequence of the newest event, or 0 if there are
+	// none yet.
+	lastSeq uint64
+}
+
+// newEventJournal creates a journal keeping the given number of events.
+func newEventJournal(size int) *eventJournal {
+	if size <= 0 {
+		size = DefaultJournalSize
+	}
+
+	return &eventJournal{events: make([]*WSMessage, size)}
+}
+
+// append records a message as published on a channel, returning a copy
+// stamped with its sequence and channel.
+func (j *eventJournal) append(channel string, msg WSMessage) *WSMessage {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.lastSeq++
+	msg.Seq = j.lastSeq
+	msg.Channel = channel
+
+	event := &msg
+	j.events[(j.lastSeq-1)%uint64(len(j.events))] = event
+
+	return event
+}
+
+// last returns the sequence of the newest event.
+func (j *eventJournal) last() uint64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.lastSeq
+}
+
+// since returns the events after the given sequence on the channels match
+// keeps, oldest first. It returns false if some of those events were already
+// overwritten, or the sequence is from before a restart, in which case the
+// client can't catch up from the journal.
+func (j *eventJournal) since(seq uint64,
+	match func(channel string) bool,
+) ([]*WSMessage, bool) {
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	size := uint64(len(j.events))
+	switch {
+	case seq > j.lastSeq:
+		return nil, false
+
+	case j.lastSeq > size && seq < j.lastSeq-size:
+		return nil, false
+	}
+
+	var events []*WSMessage
+	for n := seq + 1; n <= j.lastSeq; n++ {
+		event := j.events[(n-1)%size]
+		if match(event.Channel) {
+			events = append(events, event)
+		}
+	}
+
+	return events, true
+}
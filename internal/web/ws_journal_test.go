@@ -0,0 +1,221 @@
+package web
+
+import (
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEventJournal tests that the journal numbers events, replays the ones
+// after a sequence on matching channels, and reports gaps once events were
+// overwritten.
+func TestEventJournal(t *testing.T) {
+	t.Parallel()
+
+	j := newEventJournal(3)
+	require.Zero(t, j.last())
+
+	all := func(string) bool { return true }
+	events, ok := j.since(0, all)
+	require.True(t, ok)
+	require.Empty(t, events)
+
+	for _, channel := range []string{"a", "b", "a"} {
+		j.append(channel, WSMessage{Type: "x"})
+	}
+	require.Equal(t, uint64(3), j.last())
+
+	events, ok = j.since(0, func(c string) bool { return c == "a" })
+	require.True(t, ok)
+	require.Len(t, events, 2)
+	require.Equal(t, uint64(1), events[0].Seq)
+	require.Equal(t, uint64(3), events[1].Seq)
+
+	// A sequence from the future, as after a restart, can't be resumed.
+	_, ok = j.since(4, all)
+	require.False(t, ok)
+
+	// The fourth event overwrites the first, so only clients that saw it
+	// can still resume.
+	j.append("b", WSMessage{Type: "x"})
+	_, ok = j.since(0, all)
+	require.False(t, ok)
+
+	events, ok = j.since(1, all)
+	require.True(t, ok)
+	require.Len(t, events, 3)
+	require.Equal(t, uint64(2), events[0].Seq)
+	require.Equal(t, "b", events[2].Channel)
+}
+
+// TestValidateChannel tests which channels clients may subscribe to.
+func TestValidateChannel(t *testing.T) {
+	t.Parallel()
+
+	for _, channel := range []string{
+		"reviews", "tasks", "activity", "agents", "inbox:0",
+		"inbox:12", "topic:builds",
+	} {
+		require.NoError(t, validateChannel(channel), channel)
+	}
+
+	for _, channel := range []string{
+		"", "inbox:", "inbox:x", "inbox:-1", "topic:", "mail",
+	} {
+		require.Error(t, validateChannel(channel), channel)
+	}
+}
+
+// TestHubResume tests that a client subscribing with resume_from is replayed
+// the events it missed on its channels before going live, and is told to
+// resync once the journal no longer holds them.
+func TestHubResume(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(nil)
+	hub.journal = newEventJournal(4)
+
+	hub.Publish(InboxChannel(1), WSMessage{Type: WSMsgTypeNewMessage})
+	hub.Publish(InboxChannel(2), WSMessage{Type: WSMsgTypeNewMessage})
+	hub.Publish(ChannelReviews, WSMessage{Type: WSMsgTypeReviewUpdate})
+
+	client := newTestClient(hub, 0, WSProtocolVersion)
+	hub.handleIncomingMessage(client, websocket.TextMessage, []byte(
+		`{"type": "subscribe", "data": {"channels": ["inbox:1",
+		"reviews"], "resume_from": 0}}`,
+	))
+
+	msg := recvWS(t, client)
+	require.Equal(t, uint64(1), msg.Seq)
+	msg = recvWS(t, client)
+	require.Equal(t, uint64(3), msg.Seq)
+	require.Equal(t, ChannelReviews, msg.Channel)
+
+	msg = recvWS(t, client)
+	require.Equal(t, WSMsgTypeResumed, msg.Type)
+	require.Equal(t, 2, msg.Payload.(map[string]any)["replayed"])
+
+	msg = recvWS(t, client)
+	require.Equal(t, WSMsgTypeSubscribed, msg.Type)
+	require.Equal(t, []string{"inbox:1", "reviews"},
+		msg.Payload.(map[string]any)["channels"])
+	require.Equal(t, uint64(3), msg.Payload.(map[string]any)["seq"])
+
+	// Live events follow.
+	hub.Publish(InboxChannel(1), WSMessage{Type: WSMsgTypeUnreadCount})
+	msg = recvWS(t, client)
+	require.Equal(t, uint64(4), msg.Seq)
+	requireNoWS(t, client)
+
+	// Resuming from an event the journal has since overwritten needs a
+	// resync.
+	hub.Publish(ChannelTasks, WSMessage{Type: WSMsgTypeTaskUpdate})
+	hub.handleIncomingMessage(client, websocket.TextMessage, []byte(
+		`{"type": "resume", "data": {"seq": 0}}`,
+	))
+	msg = recvWS(t, client)
+	require.Equal(t, WSMsgTypeResync, msg.Type)
+	require.Equal(t, uint64(5), msg.Payload.(map[string]any)["seq"])
+
+	// Unsubscribing stops the channel's events.
+	hub.handleIncomingMessage(client, websocket.TextMessage, []byte(
+		`{"type": "unsubscribe", "data": {"channels": ["inbox:1"]}}`,
+	))
+	msg = recvWS(t, client)
+	require.Equal(t, WSMsgTypeUnsubscribed, msg.Type)
+	hub.Publish(InboxChannel(1), WSMessage{Type: WSMsgTypeNewMessage})
+	requireNoWS(t, client)
+
+	// Unknown channels are rejected.
+	hub.handleIncomingMessage(client, websocket.TextMessage, []byte(
+		`{"type": "subscribe", "data": {"channels": ["mail"]}}`,
+	))
+	msg = recvWS(t, client)
+	require.Equal(t, WSMsgTypeError, msg.Type)
+	require.Equal(t, []string{"reviews"}, client.Channels())
+}
+
+// TestClientSendOverflow tests that a v2 client whose send buffer fills up is
+// told to resync rather than silently missing events, while a legacy client
+// just has them dropped.
+func TestClientSendOverflow(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(nil)
+	client := newTestClient(hub, 0, WSProtocolVersion)
+	client.Subscribe([]string{ChannelTasks})
+	legacy := newTestClient(hub, 0, WSProtocolLegacy)
+
+	total := sendBufferSize + 10
+	for i := 0; i < total; i++ {
+		hub.Publish(ChannelTasks, WSMessage{Type: WSMsgTypeTaskUpdate})
+	}
+
+	// The v2 client gets every event that fit, then resync_required in
+	// the slot kept for it, carrying the sequence it overflowed at.
+	for seq := uint64(1); seq < sendBufferSize; seq++ {
+		msg := recvWS(t, client)
+		require.Equal(t, seq, msg.Seq)
+	}
+	msg := recvWS(t, client)
+	require.Equal(t, WSMsgTypeResync, msg.Type)
+	require.Equal(t, uint64(sendBufferSize),
+		msg.Payload.(map[string]any)["seq"])
+	requireNoWS(t, client)
+
+	// Nothing is queued until the resync message is written.
+	hub.Publish(ChannelTasks, WSMessage{Type: WSMsgTypeTaskUpdate})
+	requireNoWS(t, client)
+
+	client.resynced()
+	hub.Publish(ChannelTasks, WSMessage{Type: WSMsgTypeTaskUpdate})
+	msg = recvWS(t, client)
+	require.Equal(t, uint64(total+2), msg.Seq)
+
+	// The legacy client can't resume, so it fills its whole buffer.
+	for seq := uint64(1); seq <= sendBufferSize; seq++ {
+		msg := recvWS(t, legacy)
+		require.Equal(t, seq, msg.Seq)
+	}
+	requireNoWS(t, legacy)
+}
+
+// TestHubAgentCredentialChannels tests that a client tied to an agent by its
+// credential only gets that agent's inbox and the public channels.
+func TestHubAgentCredentialChannels(t *testing.T) {
+	t.Parallel()
+
+	hub := NewHub(nil)
+
+	client := newTestClient(hub, 0, WSProtocolVersion)
+	client.boundAgentID = 7
+	for _, channel := range []string{
+		"inbox:0", "inbox:8", "topic:builds", "reviews", "tasks",
+	} {
+		hub.handleIncomingMessage(client, websocket.TextMessage, []byte(
+			`{"type": "subscribe", "data": {"channels": ["`+
+				channel+`"]}}`,
+		))
+		msg := recvWS(t, client)
+		require.Equal(t, WSMsgTypeError, msg.Type, channel)
+	}
+
+	hub.handleIncomingMessage(client, websocket.TextMessage, []byte(
+		`{"type": "subscribe", "data": {"channels": ["inbox:7",
+		"activity", "agents"]}}`,
+	))
+	msg := recvWS(t, client)
+	require.Equal(t, WSMsgTypeSubscribed, msg.Type)
+
+	// Legacy clients tied to an agent don't get the reviews and tasks
+	// they'd otherwise receive without subscribing.
+	legacy := newTestClient(hub, 7, 1)
+	legacy.boundAgentID = 7
+	hub.Publish(ChannelReviews, WSMessage{Type: WSMsgTypeReviewUpdate})
+	hub.Publish(ChannelTasks, WSMessage{Type: WSMsgTypeTaskUpdate})
+	hub.Publish(ChannelActivity, WSMessage{Type: WSMsgTypeActivity})
+	msg = recvWS(t, legacy)
+	require.Equal(t, WSMsgTypeActivity, msg.Type)
+	requireNoWS(t, legacy)
+}
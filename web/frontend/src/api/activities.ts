@@ -30,7 +30,7 @@ function normalizeActivityType(type?: string): ActivityType {
   const normalized = type.toLowerCase().replace('activity_type_', '');
   const validTypes: ActivityType[] = [
     'message_sent', 'message_read', 'session_started',
-    'session_completed', 'agent_registered', 'heartbeat'
+    'session_completed', 'agent_registered', 'heartbeat', 'schedule_run'
   ];
   return validTypes.includes(normalized as ActivityType) ? normalized as ActivityType : 'heartbeat';
 }
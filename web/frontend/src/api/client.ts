@@ -73,10 +73,13 @@ async function request<T>(
 
   // Check for error responses.
   if (!response.ok) {
-    const errorData = data as APIError;
+    // The grpc-gateway reports errors as a top-level message.
+    const errorData = data as APIError & { message?: string };
     throw new ApiError(
       errorData.error?.code ?? 'unknown_error',
-      errorData.error?.message ?? 'An unknown error occurred',
+      errorData.error?.message ??
+        errorData.message ??
+        'An unknown error occurred',
       response.status,
       errorData.error?.details,
     );
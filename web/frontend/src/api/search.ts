@@ -35,7 +35,7 @@ interface GatewayInboxMessage {
   sender_project_key?: string;
   sender_git_branch?: string;
   subject: string;
-  body: string;
+  body?: string;
   priority?: string;
   state?: string;
   created_at?: string;
@@ -45,8 +45,24 @@ interface GatewayInboxMessage {
   acknowledged_at?: string | null;
 }
 
+interface GatewaySearchHit {
+  message_id: string;
+  snippet?: string;
+  subject_highlight?: string;
+  rank?: number;
+}
+
 interface GatewaySearchResponse {
   results?: GatewayInboxMessage[];
+  hits?: GatewaySearchHit[];
+  total?: string;
+  next_cursor?: string;
+}
+
+// Strip the **bold** markers the server wraps matched terms in, since the
+// results page highlights the query terms itself.
+function stripHighlights(text: string): string {
+  return text.replace(/\*\*/g, '');
 }
 
 interface GatewayAutocompleteRecipient {
@@ -61,12 +77,16 @@ interface GatewayAutocompleteResponse {
   recipients?: GatewayAutocompleteRecipient[];
 }
 
-// Parse gateway search result (InboxMessage) to SearchResult format.
-function parseSearchResult(msg: GatewayInboxMessage): SearchResult {
-  // Extract a snippet from the body (first 150 chars).
-  const snippet = msg.body.length > 150
-    ? msg.body.substring(0, 150) + '...'
-    : msg.body;
+// Parse gateway search result (InboxMessage) to SearchResult format, using
+// the server's snippet when the hit carries one.
+function parseSearchResult(msg: GatewayInboxMessage, hit?: GatewaySearchHit): SearchResult {
+  // Otherwise extract a snippet from the body (first 150 chars).
+  const body = msg.body ?? '';
+  const snippet = hit?.snippet !== undefined
+    ? stripHighlights(hit.snippet)
+    : body.length > 150
+      ? body.substring(0, 150) + '...'
+      : body;
 
   const result: SearchResult = {
     type: 'message',
@@ -110,11 +130,14 @@ export async function search(
 ): Promise<APIResponse<SearchResult[]>> {
   const encoded = encodeURIComponent(query);
   const response = await get<GatewaySearchResponse>(`/search?query=${encoded}`, signal);
-  const results = (response.results ?? []).map(parseSearchResult);
+  const hits = response.hits ?? [];
+  const results = (response.results ?? []).map((msg, i) =>
+    parseSearchResult(msg, hits[i]),
+  );
   return {
     data: results,
     meta: {
-      total: results.length,
+      total: response.total !== undefined ? toNumber(response.total) : results.length,
       page: 1,
       page_size: results.length,
     },
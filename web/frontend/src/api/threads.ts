@@ -2,7 +2,12 @@
 // Uses grpc-gateway REST API directly.
 
 import { get, post } from './client.js';
-import type { Message, ThreadWithMessages, MessagePriority } from '@/types/api.js';
+import type {
+  Message,
+  MessagePriority,
+  MessageRevision,
+  ThreadWithMessages,
+} from '@/types/api.js';
 
 // Helper to convert proto int64 (string) to number.
 function toNumber(value: string | number | undefined): number {
@@ -34,7 +39,39 @@ interface GatewayReadThreadResponse {
     priority?: string;
     created_at?: string;
     recipient_names?: string[];
+    edited_at?: string;
   }>;
+  revisions?: Array<{
+    message_id: string;
+    revision?: number;
+    subject?: string;
+    body?: string;
+    created_at?: string;
+    replaced_at?: string;
+  }>;
+}
+
+// Group the replaced versions of edited messages by message ID.
+function parseRevisions(
+  revisions: GatewayReadThreadResponse['revisions'],
+): Map<number, MessageRevision[]> {
+  const byMessage = new Map<number, MessageRevision[]>();
+  for (const rev of revisions ?? []) {
+    const parsed: MessageRevision = {
+      message_id: toNumber(rev.message_id),
+      revision: rev.revision ?? 0,
+      subject: rev.subject ?? '',
+      body: rev.body ?? '',
+      created_at: rev.created_at ?? '',
+    };
+    if (rev.replaced_at !== undefined) {
+      parsed.replaced_at = rev.replaced_at;
+    }
+    const existing = byMessage.get(parsed.message_id) ?? [];
+    existing.push(parsed);
+    byMessage.set(parsed.message_id, existing);
+  }
+  return byMessage;
 }
 
 // Parse gateway messages to internal format.
@@ -61,6 +98,9 @@ function parseMessages(messages: GatewayReadThreadResponse['messages']): Message
     if (msg.recipient_names && msg.recipient_names.length > 0) {
       result.recipient_names = msg.recipient_names;
     }
+    if (msg.edited_at !== undefined) {
+      result.edited_at = msg.edited_at;
+    }
     return result;
   });
 }
@@ -72,6 +112,13 @@ export async function fetchThread(
 ): Promise<ThreadWithMessages> {
   const response = await get<GatewayReadThreadResponse>(`/threads/${id}`, signal);
   const messages = parseMessages(response.messages);
+  const revisions = parseRevisions(response.revisions);
+  for (const msg of messages) {
+    const msgRevisions = revisions.get(msg.id);
+    if (msgRevisions) {
+      msg.revisions = msgRevisions;
+    }
+  }
 
   // Build thread info from messages
   const firstMsg = messages[0];
@@ -5,6 +5,8 @@ export type WSMessageType =
   | 'connected'
   | 'unread_count'
   | 'new_message'
+  | 'message_edited'
+  | 'message_state'
   | 'agent_update'
   | 'activity'
   | 'task_update'
@@ -100,6 +100,20 @@ function UserPlusIcon({ className }: { className?: string }) {
   );
 }
 
+// Clock icon.
+function ClockIcon({ className }: { className?: string }) {
+  return (
+    <svg className={className} fill="none" viewBox="0 0 24 24" stroke="currentColor">
+      <path
+        strokeLinecap="round"
+        strokeLinejoin="round"
+        strokeWidth={2}
+        d="M12 8v4l3 3m6-3a9 9 0 11-18 0 9 9 0 0118 0z"
+      />
+    </svg>
+  );
+}
+
 // Heartbeat icon.
 function HeartbeatIcon({ className }: { className?: string }) {
   return (
@@ -151,6 +165,12 @@ const activityTypeConfigs: Record<ActivityType, ActivityTypeConfig> = {
     iconColor: 'text-red-500',
     label: 'Heartbeat',
   },
+  schedule_run: {
+    icon: <ClockIcon className="h-4 w-4" />,
+    bgColor: 'bg-indigo-100',
+    iconColor: 'text-indigo-600',
+    label: 'Scheduled message',
+  },
 };
 
 // Format relative time.
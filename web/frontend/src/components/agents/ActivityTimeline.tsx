@@ -72,6 +72,7 @@ function getActivityIcon(
       return 'session';
     case 'message_sent':
     case 'message_read':
+    case 'schedule_run':
       return 'message';
     case 'agent_registered':
       return 'agent';
@@ -95,6 +96,8 @@ function formatActivityTitle(type: string): string {
       return 'Message read';
     case 'agent_registered':
       return 'Agent registered';
+    case 'schedule_run':
+      return 'Scheduled message';
     default:
       return type.replace(/_/g, ' ');
   }
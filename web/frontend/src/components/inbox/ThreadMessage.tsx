@@ -123,6 +123,10 @@ export function ThreadMessage({
   const [diffExpanded, setDiffExpanded] = useState(false);
   const [reviewMode, setReviewMode] = useState(false);
 
+  // Track whether the replaced versions of an edited message are shown.
+  const [historyExpanded, setHistoryExpanded] = useState(false);
+  const revisions = message.revisions ?? [];
+
   // Diff annotation support, scoped to this message's ID.
   const {
     diffAnnotations: allDiffAnnotations,
@@ -243,6 +247,14 @@ export function ThreadMessage({
             </div>
             <span className="text-sm text-gray-500">
               {formatMessageDate(message.created_at)}
+              {message.edited_at ? (
+                <span
+                  className="ml-1 text-gray-400"
+                  title={`Edited ${formatMessageDate(message.edited_at)}`}
+                >
+                  (edited)
+                </span>
+              ) : null}
             </span>
             {/* Recipient (To) field. */}
             {message.recipient_names && message.recipient_names.length > 0 ? (
@@ -278,6 +290,44 @@ export function ThreadMessage({
         dangerouslySetInnerHTML={{ __html: renderedBody }}
       />
 
+      {/* Versions of the message that edits replaced. */}
+      {revisions.length > 0 ? (
+        <div className="mt-3 border-t border-gray-100 pt-2">
+          <button
+            type="button"
+            onClick={() => setHistoryExpanded(!historyExpanded)}
+            className="text-xs text-gray-500 hover:text-gray-700"
+          >
+            {historyExpanded
+              ? 'Hide edit history'
+              : `Show edit history (${revisions.length})`}
+          </button>
+          {historyExpanded ? (
+            <ol className="mt-2 space-y-2">
+              {revisions.map((rev) => (
+                <li
+                  key={rev.revision}
+                  className="rounded border border-gray-100 bg-gray-50 p-2 text-sm"
+                >
+                  <div className="text-xs text-gray-400">
+                    Revision {rev.revision}
+                    {rev.replaced_at
+                      ? `, replaced ${formatMessageDate(rev.replaced_at)}`
+                      : null}
+                  </div>
+                  <div className="font-medium text-gray-700">
+                    {rev.subject}
+                  </div>
+                  <div className="whitespace-pre-wrap text-gray-600">
+                    {rev.body}
+                  </div>
+                </li>
+              ))}
+            </ol>
+          ) : null}
+        </div>
+      ) : null}
+
       {/* Embedded diff section (from substrate send-diff). */}
       {patch ? (
         <div className="mt-4 border-t border-gray-100 pt-3">
@@ -277,7 +277,8 @@ export function SearchBar({
   const [selectedIndex, setSelectedIndex] = useState(0);
 
   // Use enriched search for results with routes.
-  const { enrichedResults, isSearching, debouncedQuery } = useEnrichedSearch(searchQuery);
+  const { enrichedResults, isSearching, debouncedQuery, error } =
+    useEnrichedSearch(searchQuery);
 
   // Reset selection when results change.
   useEffect(() => {
@@ -427,6 +428,10 @@ export function SearchBar({
                     <p className="mt-2 text-sm text-gray-500">
                       Type to search messages, threads, agents, and topics
                     </p>
+                    <p className="mt-1 text-xs text-gray-400">
+                      Narrow results with from:, to:, topic:, is:unread,
+                      is:starred, after:7d, has:diff, and more
+                    </p>
                     <p className="mt-1 text-xs text-gray-400">
                       Press <kbd className="rounded bg-gray-100 px-1">↑</kbd>{' '}
                       <kbd className="rounded bg-gray-100 px-1">↓</kbd> to
@@ -445,6 +450,11 @@ export function SearchBar({
                   <div className="px-4 py-8 text-center text-sm text-gray-500">
                     Searching...
                   </div>
+                ) : error && enrichedResults.length === 0 ? (
+                  // Invalid query, such as an unterminated quote.
+                  <div className="px-4 py-8 text-center">
+                    <p className="text-sm text-red-600">{error.message}</p>
+                  </div>
                 ) : enrichedResults.length === 0 && debouncedQuery.trim().length >= 2 ? (
                   // No results.
                   <div className="px-4 py-8 text-center">
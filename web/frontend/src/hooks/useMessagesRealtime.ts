@@ -3,12 +3,15 @@
 import { useCallback, useEffect, useRef } from 'react';
 import { useQueryClient } from '@tanstack/react-query';
 import {
+  useEditedMessages,
   useNewMessages,
   useUnreadCount,
   useWebSocketConnection,
+  type EditedMessagePayload,
   type NewMessagePayload,
 } from '@/hooks/useWebSocket.js';
 import { messageKeys } from '@/hooks/useMessages.js';
+import { threadKeys } from '@/hooks/useThreads.js';
 import type { MessageWithRecipients, APIResponse } from '@/types/api.js';
 
 // Type alias for message list response.
@@ -103,8 +106,46 @@ export function useMessagesRealtime(): RealtimeMessageState {
     [queryClient],
   );
 
-  // Subscribe to new message events.
+  // Handle an edit to a message already in the cache.
+  const handleEditedMessage = useCallback(
+    (payload: EditedMessagePayload) => {
+      queryClient.setQueriesData<MessageListResponse>(
+        { queryKey: messageKeys.lists() },
+        (oldData) => {
+          if (!oldData) return oldData;
+
+          return {
+            ...oldData,
+            data: oldData.data.map((m) =>
+              m.id === payload.id
+                ? {
+                    ...m,
+                    subject: payload.subject,
+                    body: payload.body,
+                    edited_at: payload.edited_at,
+                  }
+                : m,
+            ),
+          };
+        },
+      );
+
+      // Refetch the thread so its edit history is current.
+      if (payload.thread_id) {
+        void queryClient.invalidateQueries({
+          queryKey: threadKeys.detail(payload.thread_id),
+        });
+      }
+      void queryClient.invalidateQueries({
+        queryKey: messageKeys.detail(payload.id),
+      });
+    },
+    [queryClient],
+  );
+
+  // Subscribe to new and edited message events.
   useNewMessages(handleNewMessage);
+  useEditedMessages(handleEditedMessage);
 
   // Clean up processed IDs on unmount.
   useEffect(() => {
@@ -242,6 +242,50 @@ export function useNewMessages(handler: (payload: NewMessagePayload) => void): v
   }, [client, handler]);
 }
 
+// Edited message payload type. Carries the message as edited.
+export interface EditedMessagePayload extends NewMessagePayload {
+  edited_at: string;
+}
+
+// Hook to subscribe to edits of messages already delivered.
+export function useEditedMessages(
+  handler: (payload: EditedMessagePayload) => void,
+): void {
+  const client = useWebSocketClient();
+
+  useEffect(() => {
+    const unsubscribe = client.on<EditedMessagePayload>(
+      'message_edited',
+      handler,
+    );
+    return unsubscribe;
+  }, [client, handler]);
+}
+
+// Message state payload type. Sent when the agent's copy of a message is
+// read, acked, archived or otherwise changes state.
+export interface MessageStatePayload {
+  id: number;
+  thread_id: string;
+  old_state: string;
+  new_state: string;
+}
+
+// Hook to subscribe to message state changes.
+export function useMessageStateChanges(
+  handler: (payload: MessageStatePayload) => void,
+): void {
+  const client = useWebSocketClient();
+
+  useEffect(() => {
+    const unsubscribe = client.on<MessageStatePayload>(
+      'message_state',
+      handler,
+    );
+    return unsubscribe;
+  }, [client, handler]);
+}
+
 // Summary update payload type.
 export interface SummaryUpdatePayload {
   agent_id: number;
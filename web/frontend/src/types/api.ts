@@ -79,6 +79,18 @@ export interface Message {
   created_at: string;
   thread_id?: string;
   recipient_names?: string[];
+  edited_at?: string;
+  revisions?: MessageRevision[];
+}
+
+// A version of a message that an edit replaced.
+export interface MessageRevision {
+  message_id: number;
+  revision: number;
+  subject: string;
+  body: string;
+  created_at: string;
+  replaced_at?: string;
 }
 
 export type MessagePriority = 'low' | 'normal' | 'high' | 'urgent';
@@ -155,7 +167,8 @@ export type ActivityType =
   | 'session_started'
   | 'session_completed'
   | 'agent_registered'
-  | 'heartbeat';
+  | 'heartbeat'
+  | 'schedule_run';
 
 // Agent summary types.
 export interface AgentSummary {